@@ -0,0 +1,239 @@
+// Command anonymize scrubs personal data from a restored production snapshot
+// so it can back a staging or demo environment. Row counts, foreign keys,
+// timestamps, provinces/districts and post/engagement volumes are left as they
+// are; what identifies a person is rewritten in place:
+//
+//   - Emails become anon-<hash>@example.invalid. The hash is of the original
+//     address, so the same address maps to the same alias in every table.
+//   - Phone and WhatsApp numbers keep their country prefix and length; the
+//     remaining digits are derived from a hash.
+//   - First/last names are drawn from a fixed pool, keyed on the user id.
+//   - Precise locations (profiles, posts, comments, businesses, broadcasts)
+//     are moved a random distance up to -jitter metres in a random direction.
+//   - Chat messages, help chat, feedback, report notes and notification text
+//     are replaced with filler of the same length. LOCATION messages lose
+//     their content entirely.
+//   - Every password becomes -password, so testers can sign in as anyone;
+//     OAuth-only accounts stay password-less.
+//   - Sessions, tokens, MFA secrets, invites, recoveries, IP bans and app
+//     logs are deleted; IP addresses elsewhere are cleared.
+//
+// Everything runs in one transaction: the snapshot is either fully scrubbed
+// or untouched. Uploaded media (avatars, attachments) is not touched — point
+// staging at its own bucket.
+//
+// The command refuses to run when ENV=production and requires -confirm to
+// name the database it is about to rewrite.
+//
+// Examples:
+//
+//	go run cmd/anonymize/main.go -confirm hamsaya_staging
+//	go run cmd/anonymize/main.go -confirm hamsaya_demo -jitter 1000 -password 'Demo123!'
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hamsaya/backend/config"
+	"github.com/hamsaya/backend/pkg/database"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// step is one scrubbing statement, reported by name with its row count.
+type step struct {
+	name string
+	sql  string
+	args []any
+}
+
+// helpersSQL defines session-local functions the steps share. pg_temp
+// functions vanish with the connection, so nothing is left in the snapshot.
+const helpersSQL = `
+CREATE FUNCTION pg_temp.anon_email(src text) RETURNS text AS $$
+	SELECT CASE WHEN src IS NULL OR src = '' THEN src
+	            ELSE 'anon-' || left(md5(src), 16) || '@example.invalid' END
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE FUNCTION pg_temp.anon_phone(src text) RETURNS text AS $$
+	SELECT CASE WHEN src IS NULL OR length(src) <= 3 THEN src
+	            ELSE left(src, 3) || left(translate(md5(src) || md5(src || 'x'), 'abcdef', '012345'), length(src) - 3) END
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE FUNCTION pg_temp.anon_text(src text) RETURNS text AS $$
+	SELECT CASE WHEN src IS NULL OR src = '' THEN src
+	            ELSE left(repeat('Lorem ipsum dolor sit amet, consectetur adipiscing elit. ', length(src) / 56 + 1), length(src)) END
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE FUNCTION pg_temp.pick(seed text, pool text[]) RETURNS text AS $$
+	SELECT pool[1 + abs(hashtext(seed)::bigint) % array_length(pool, 1)]
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE FUNCTION pg_temp.jitter(g geography, max_m double precision) RETURNS geography AS $$
+	SELECT CASE WHEN g IS NULL THEN NULL
+	            ELSE ST_Project(g, random() * max_m, radians(random() * 360)) END
+$$ LANGUAGE sql VOLATILE;
+`
+
+// firstNames and lastNames are the pools replacement names are drawn from.
+var (
+	firstNames = []string{
+		"Ahmad", "Mohammad", "Ali", "Hamid", "Karim", "Farid", "Nasir", "Omid", "Sami", "Zahir",
+		"Fatima", "Maryam", "Zainab", "Laila", "Nadia", "Parisa", "Roya", "Sara", "Shabnam", "Yasmin",
+	}
+	lastNames = []string{
+		"Ahmadi", "Hakimi", "Karimi", "Nazari", "Rahimi", "Sadat", "Safi", "Stanikzai", "Wardak", "Yousufzai",
+		"Amiri", "Hashimi", "Jalali", "Mohammadi", "Noori", "Popal", "Qaderi", "Rasuli", "Sultani", "Zaheer",
+	}
+)
+
+// steps lists the rewrites in order. passwordHash replaces every stored
+// password; jitter is the radius in metres locations are moved within.
+func steps(passwordHash string, jitter float64) []step {
+	return []step{
+		// Credentials and anything that could replay a real session.
+		{"sessions and tokens", `TRUNCATE user_sessions, token_blacklist, password_reset_tokens, email_verifications,
+			calendar_feed_tokens, device_credentials, admin_invites, account_recoveries`, nil},
+		{"mfa", `TRUNCATE mfa_challenges, mfa_backup_codes, mfa_factors`, nil},
+		{"ip bans", `TRUNCATE ip_bans`, nil},
+		{"app logs", `TRUNCATE app_logs`, nil},
+
+		// People.
+		{"users", `UPDATE users SET
+			email = pg_temp.anon_email(email),
+			phone = pg_temp.anon_phone(phone),
+			password_hash = CASE WHEN password_hash IS NULL THEN NULL ELSE $1 END,
+			oauth_provider_id = CASE WHEN oauth_provider_id IS NULL THEN NULL ELSE md5(oauth_provider_id) END,
+			mfa_enabled = false`, []any{passwordHash}},
+		{"profiles", `UPDATE profiles SET
+			first_name = CASE WHEN first_name IS NULL THEN NULL ELSE pg_temp.pick(id::text || 'first', $2) END,
+			last_name = CASE WHEN last_name IS NULL THEN NULL ELSE pg_temp.pick(id::text || 'last', $3) END,
+			about = pg_temp.anon_text(about),
+			website = CASE WHEN website IS NULL OR website = '' THEN website ELSE 'https://example.com' END,
+			dob = dob + (abs(hashtext(id::text || 'dob')::bigint) % 365 - 182),
+			location = pg_temp.jitter(location, $1)`, []any{jitter, firstNames, lastNames}},
+		{"email suppressions", `UPDATE email_suppressions SET email = pg_temp.anon_email(email)`, nil},
+		{"sent emails", `UPDATE sent_emails SET to_email = pg_temp.anon_email(to_email)`, nil},
+		{"device bans", `UPDATE device_bans SET device_id = md5(device_id)`, nil},
+		{"audit log ips", `UPDATE audit_logs SET ip_address = NULL WHERE ip_address IS NOT NULL`, nil},
+		{"deletion request ips", `UPDATE account_deletion_requests SET user_ip = NULL WHERE user_ip IS NOT NULL`, nil},
+
+		// Businesses and listings.
+		{"business profiles", `UPDATE business_profiles SET
+			email = pg_temp.anon_email(email),
+			phone_number = pg_temp.anon_phone(phone_number),
+			website = CASE WHEN website IS NULL OR website = '' THEN website ELSE 'https://example.com' END,
+			address = pg_temp.anon_text(address),
+			address_location = pg_temp.jitter(address_location, $1)`, []any{jitter}},
+		{"business locations", `UPDATE business_locations SET
+			phone_number = pg_temp.anon_phone(phone_number),
+			address = pg_temp.anon_text(address),
+			location = pg_temp.jitter(location, $1)`, []any{jitter}},
+		{"ads", `UPDATE ads SET
+			phone_number = pg_temp.anon_phone(phone_number),
+			whatsapp_number = pg_temp.anon_phone(whatsapp_number)
+			WHERE phone_number IS NOT NULL OR whatsapp_number IS NOT NULL`, nil},
+		{"business bookings", `UPDATE business_bookings SET
+			note = pg_temp.anon_text(note),
+			response_note = pg_temp.anon_text(response_note)`, nil},
+
+		// Locations attached to content.
+		{"post locations", `UPDATE posts SET
+			address_location = pg_temp.jitter(address_location, $1),
+			user_location = pg_temp.jitter(user_location, $1)
+			WHERE address_location IS NOT NULL OR user_location IS NOT NULL`, []any{jitter}},
+		{"comment locations", `UPDATE post_comments SET location = pg_temp.jitter(location, $1) WHERE location IS NOT NULL`, []any{jitter}},
+		{"broadcast centres", `UPDATE post_broadcasts b SET latitude = ST_Y(p.g::geometry), longitude = ST_X(p.g::geometry)
+			FROM (SELECT id, pg_temp.jitter(ST_SetSRID(ST_MakePoint(longitude, latitude), 4326)::geography, $1) AS g
+			      FROM post_broadcasts WHERE latitude IS NOT NULL AND longitude IS NOT NULL) p
+			WHERE b.id = p.id`, []any{jitter}},
+
+		// Private text.
+		{"chat messages", `UPDATE messages SET
+			content = CASE WHEN message_type = 'LOCATION' THEN NULL ELSE pg_temp.anon_text(content) END
+			WHERE content IS NOT NULL`, nil},
+		{"reported messages", `UPDATE message_reports SET message_content = pg_temp.anon_text(message_content)`, nil},
+		{"help chat", `UPDATE help_chat_messages SET content = pg_temp.anon_text(content), device_info = NULL`, nil},
+		{"feedback", `UPDATE user_feedback SET message = pg_temp.anon_text(message), device_info = NULL, admin_notes = pg_temp.anon_text(admin_notes)`, nil},
+		{"user reports", `UPDATE user_reports SET description = pg_temp.anon_text(description)`, nil},
+		{"notifications", `UPDATE notifications SET message = pg_temp.anon_text(message) WHERE message IS NOT NULL`, nil},
+	}
+}
+
+func main() {
+	var (
+		confirm  = flag.String("confirm", "", "name of the database to anonymize; must match DB_NAME")
+		jitter   = flag.Float64("jitter", 500, "maximum distance in metres precise locations are moved")
+		password = flag.String("password", "Demo123!", "password set on every account that has one")
+	)
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+	if cfg.Server.Env == "production" {
+		fmt.Fprintln(os.Stderr, "Refusing to anonymize with ENV=production: restore the snapshot into a staging database first.")
+		os.Exit(1)
+	}
+	if *confirm != cfg.Database.Name {
+		fmt.Fprintf(os.Stderr, "This rewrites every user in database %q on %s. Re-run with -confirm %s to proceed.\n",
+			cfg.Database.Name, cfg.Database.Host, cfg.Database.Name)
+		os.Exit(1)
+	}
+	if *jitter <= 0 {
+		fmt.Fprintln(os.Stderr, "-jitter must be positive")
+		os.Exit(1)
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(*password), bcrypt.DefaultCost)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to hash password: %v\n", err)
+		os.Exit(1)
+	}
+
+	db, err := database.New(&cfg.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	start := time.Now()
+	if err := run(ctx, db, string(passwordHash), *jitter); err != nil {
+		fmt.Fprintf(os.Stderr, "Anonymization failed, nothing was changed: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Database %q anonymized in %s.\n", cfg.Database.Name, time.Since(start).Round(time.Second))
+}
+
+// run applies every step in a single transaction.
+func run(ctx context.Context, db *database.DB, passwordHash string, jitter float64) error {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(context.Background()) }()
+
+	if _, err := tx.Exec(ctx, helpersSQL); err != nil {
+		return fmt.Errorf("failed to create helper functions: %w", err)
+	}
+
+	for _, s := range steps(passwordHash, jitter) {
+		tag, err := tx.Exec(ctx, s.sql, s.args...)
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.name, err)
+		}
+		fmt.Printf("  %-22s %s\n", s.name, tag.String())
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
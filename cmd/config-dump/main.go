@@ -0,0 +1,35 @@
+// Command config-dump prints the effective configuration (environment plus
+// .env plus built-in defaults) with secrets redacted, then lists any
+// validation problems. Exits non-zero when the configuration would be
+// rejected at server startup, so it doubles as a pre-deploy check.
+// Run: go run ./cmd/config-dump
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/hamsaya/backend/config"
+)
+
+func main() {
+	cfg, err := config.LoadUnvalidated()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	out, err := json.MarshalIndent(cfg.Dump(), "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to encode configuration: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(string(out))
+
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Fprintln(os.Stderr, "Configuration OK")
+}
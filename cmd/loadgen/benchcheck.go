@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// benchResult is one benchmark's cost per operation.
+type benchResult struct {
+	NsPerOp     float64 `json:"ns_per_op"`
+	AllocsPerOp float64 `json:"allocs_per_op"`
+}
+
+func runBenchCheck(args []string) error {
+	fs := flag.NewFlagSet("benchcheck", flag.ExitOnError)
+	baselinePath := fs.String("baseline", "tests/load/bench_baseline.json", "baseline file")
+	nsTolerance := fs.Float64("ns-tolerance", 0.5, "allowed ns/op growth as a fraction; timings vary with the machine")
+	allocsTolerance := fs.Float64("allocs-tolerance", 0.1, "allowed allocs/op growth as a fraction")
+	update := fs.Bool("update", false, "write the measured results as the new baseline instead of checking")
+	_ = fs.Parse(args)
+
+	measured, err := parseBenchOutput(os.Stdin)
+	if err != nil {
+		return err
+	}
+	if len(measured) == 0 {
+		return fmt.Errorf("no benchmark results on stdin; pipe `go test -bench . -benchmem` into this command")
+	}
+
+	if *update {
+		data, err := json.MarshalIndent(measured, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*baselinePath, append(data, '\n'), 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("Baseline %s updated with %d benchmarks.\n", *baselinePath, len(measured))
+		return nil
+	}
+
+	data, err := os.ReadFile(*baselinePath)
+	if err != nil {
+		return fmt.Errorf("failed to read baseline: %w", err)
+	}
+	var baseline map[string]benchResult
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return fmt.Errorf("failed to parse baseline: %w", err)
+	}
+
+	regressions := compareBench(baseline, measured, *nsTolerance, *allocsTolerance)
+	if len(regressions) > 0 {
+		for _, r := range regressions {
+			fmt.Println("REGRESSION", r)
+		}
+		return fmt.Errorf("%d benchmark regressions against %s", len(regressions), *baselinePath)
+	}
+	fmt.Printf("%d benchmarks within %s.\n", len(measured), *baselinePath)
+	return nil
+}
+
+// parseBenchOutput reads `go test -bench -benchmem` output. The -N GOMAXPROCS
+// suffix is dropped from names so results compare across machines.
+func parseBenchOutput(r io.Reader) (map[string]benchResult, error) {
+	results := map[string]benchResult{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+		name := fields[0]
+		if i := strings.LastIndex(name, "-"); i > 0 {
+			if _, err := strconv.Atoi(name[i+1:]); err == nil {
+				name = name[:i]
+			}
+		}
+		var res benchResult
+		for i := 2; i+1 < len(fields); i += 2 {
+			v, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				continue
+			}
+			switch fields[i+1] {
+			case "ns/op":
+				res.NsPerOp = v
+			case "allocs/op":
+				res.AllocsPerOp = v
+			}
+		}
+		results[name] = res
+	}
+	return results, scanner.Err()
+}
+
+// compareBench lists every measured benchmark that is slower or allocates
+// more than its baseline allows, and every baseline entry that was not run.
+func compareBench(baseline, measured map[string]benchResult, nsTolerance, allocsTolerance float64) []string {
+	var out []string
+	for name, want := range baseline {
+		got, ok := measured[name]
+		if !ok {
+			out = append(out, fmt.Sprintf("%s: in the baseline but not run", name))
+			continue
+		}
+		if limit := want.NsPerOp * (1 + nsTolerance); want.NsPerOp > 0 && got.NsPerOp > limit {
+			out = append(out, fmt.Sprintf("%s: %.0f ns/op, baseline %.0f (limit %.0f)", name, got.NsPerOp, want.NsPerOp, limit))
+		}
+		if limit := want.AllocsPerOp * (1 + allocsTolerance); want.AllocsPerOp > 0 && got.AllocsPerOp > limit {
+			out = append(out, fmt.Sprintf("%s: %.0f allocs/op, baseline %.0f (limit %.0f)", name, got.AllocsPerOp, want.AllocsPerOp, limit))
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,83 @@
+// Command loadgen prepares and judges performance runs against the hot
+// endpoints (feed, search, chat send).
+//
+//	loadgen seed        bulk-insert realistic fixtures (default 100k users, 1M posts)
+//	loadgen targets     mint sessions for seeded users and write vegeta targets
+//	                    or a k6 fixtures file for tests/load/hot_paths.js
+//	loadgen benchcheck  compare `go test -bench` output with the committed
+//	                    baseline and exit non-zero on a regression
+//	loadgen clean       delete everything seed created
+//
+// Seeded users are recognisable by their loadgen-<n>@loadgen.invalid email,
+// so seed is idempotent and clean never touches real accounts. Like the k6
+// profiles, seed and targets are for a dedicated environment, never
+// production; both refuse to run with ENV=production.
+//
+// Examples:
+//
+//	go run ./cmd/loadgen seed -users 100000 -posts 1000000 -conversations 20000
+//	go run ./cmd/loadgen targets -sessions 200 -n 50000 > targets.json
+//	vegeta attack -format=json -targets=targets.json -rate=200 -duration=2m | vegeta report
+//	go test ./internal/services -run '^$' -bench . -benchmem | go run ./cmd/loadgen benchcheck
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/hamsaya/backend/config"
+	"github.com/hamsaya/backend/pkg/database"
+)
+
+const usage = `Usage: loadgen <command> [flags]
+
+Commands:
+  seed        bulk-insert load-test fixtures
+  targets     mint sessions and write vegeta targets or k6 fixtures
+  benchcheck  compare go benchmark output with the baseline
+  clean       delete seeded fixtures
+
+Run "loadgen <command> -h" for the flags of a command.`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch cmd, args := os.Args[1], os.Args[2:]; cmd {
+	case "seed":
+		err = runSeed(args)
+	case "targets":
+		err = runTargets(args)
+	case "benchcheck":
+		err = runBenchCheck(args)
+	case "clean":
+		err = runClean(args)
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "loadgen %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+// connect loads the configuration and opens the database, refusing to
+// touch a production environment.
+func connect() (*config.Config, *database.DB, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if cfg.Server.Env == "production" {
+		return nil, nil, fmt.Errorf("refusing to run with ENV=production")
+	}
+	db, err := database.New(&cfg.Database)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return cfg, db, nil
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/hamsaya/backend/pkg/database"
+)
+
+// seedEmailPattern matches every account seed creates.
+const seedEmailPattern = "loadgen-%@loadgen.invalid"
+
+// seedCitiesSQL is the city table profiles are placed in, with Kabul
+// weighted to half the users the way real sign-ups are.
+const seedCitiesSQL = `
+	cities(idx, province, lat, lng) AS (VALUES
+		(0, 'Kabul', 34.5553, 69.2075),
+		(1, 'Herat', 34.3482, 62.1997),
+		(2, 'Balkh', 36.7090, 67.1109),
+		(3, 'Kandahar', 31.6289, 65.7372),
+		(4, 'Nangarhar', 34.4265, 70.4515)
+	)`
+
+const seedUsersSQL = `
+	INSERT INTO users (email, email_verified, created_at, updated_at)
+	SELECT 'loadgen-' || g || '@loadgen.invalid', true, at, at
+	FROM (SELECT g, NOW() - random() * INTERVAL '365 days' AS at FROM generate_series($1::int, $2::int) g) s
+	ON CONFLICT (email) DO NOTHING`
+
+// seedProfilesSQL gives every seeded user without a profile a name and a
+// home within 15 km of their city centre.
+const seedProfilesSQL = `
+	WITH` + seedCitiesSQL + `,
+	seeded AS (
+		SELECT u.id, (ARRAY[0,0,0,0,0,1,1,2,3,4])[1 + abs(hashtext(u.id::text)::bigint) % 10] AS city
+		FROM users u
+		LEFT JOIN profiles p ON p.id = u.id
+		WHERE u.email LIKE '` + seedEmailPattern + `' AND p.id IS NULL
+	)
+	INSERT INTO profiles (id, first_name, last_name, location, country, province, is_complete)
+	SELECT s.id,
+	       (ARRAY['Ahmad','Ali','Farid','Hamid','Karim','Fatima','Laila','Maryam','Nadia','Sara'])[1 + floor(random() * 10)::int],
+	       (ARRAY['Ahmadi','Hakimi','Karimi','Nazari','Rahimi','Safi','Noori','Popal','Sultani','Wardak'])[1 + floor(random() * 10)::int],
+	       ST_Project(ST_SetSRID(ST_MakePoint(c.lng, c.lat), 4326)::geography, random() * 15000, radians(random() * 360)),
+	       'Afghanistan', c.province, true
+	FROM seeded s
+	JOIN cities c ON c.idx = s.city`
+
+// seedPostsSQL inserts $1 posts by random seeded authors, placed near the
+// author's home and spread over the last 90 days. The mix (65% FEED, 25%
+// SELL, 10% EVENT) and the vocabulary follow production.
+const seedPostsSQL = `
+	WITH pool AS (
+		SELECT array_agg(id) AS ids FROM users WHERE email LIKE '` + seedEmailPattern + `'
+	),
+	picks AS (
+		SELECT pool.ids[1 + floor(random() * array_length(pool.ids, 1))::int] AS user_id,
+		       random() AS r,
+		       NOW() - random() * INTERVAL '90 days' AS at
+		FROM pool, generate_series(1, $1::int)
+	)
+	INSERT INTO posts (user_id, type, title, description, visibility, status, price, currency,
+		start_date, start_time, address_location, country, province, created_at, updated_at, bumped_at)
+	SELECT p.user_id,
+	       CASE WHEN p.r < 0.65 THEN 'FEED' WHEN p.r < 0.90 THEN 'SELL' ELSE 'EVENT' END,
+	       (ARRAY['Used bicycle','Apartment for rent','Lost cat','Community cleanup','Fresh bread',
+	              'Laptop for sale','Wedding hall','English classes','Water outage','Football match'])[1 + floor(random() * 10)::int],
+	       'Neighbours in ' || pr.province || ': ' ||
+	       (ARRAY['anyone know a good plumber nearby?','selling in good condition, price negotiable.',
+	              'join us this Friday after prayers.','the road near the bazaar is closed today.',
+	              'looking for recommendations for a tailor.'])[1 + floor(random() * 5)::int],
+	       'PUBLIC', true,
+	       CASE WHEN p.r >= 0.65 AND p.r < 0.90 THEN round((random() * 50000)::numeric, 0) END,
+	       CASE WHEN p.r >= 0.65 AND p.r < 0.90 THEN 'AFN' END,
+	       CASE WHEN p.r >= 0.90 THEN (NOW() + random() * INTERVAL '30 days')::date END,
+	       CASE WHEN p.r >= 0.90 THEN TIME '18:00' END,
+	       ST_Project(pr.location, random() * 2000, radians(random() * 360)),
+	       'Afghanistan', pr.province, p.at, p.at, p.at
+	FROM picks p
+	JOIN profiles pr ON pr.id = p.user_id`
+
+// seedConversationsSQL opens $1 conversations between random seeded users
+// and fills each with $2 alternating messages; the seq trigger numbers them.
+const seedConversationsSQL = `
+	WITH pool AS (
+		SELECT array_agg(id) AS ids FROM users WHERE email LIKE '` + seedEmailPattern + `'
+	),
+	pairs AS (
+		SELECT DISTINCT LEAST(a, b) AS p1, GREATEST(a, b) AS p2
+		FROM (
+			SELECT pool.ids[1 + floor(random() * array_length(pool.ids, 1))::int] AS a,
+			       pool.ids[1 + floor(random() * array_length(pool.ids, 1))::int] AS b
+			FROM pool, generate_series(1, $1::int)
+		) x
+		WHERE a <> b
+	),
+	convs AS (
+		INSERT INTO conversations (participant1_id, participant2_id, last_message_at)
+		SELECT p1, p2, NOW() FROM pairs
+		ON CONFLICT (participant1_id, participant2_id) DO NOTHING
+		RETURNING id, participant1_id, participant2_id
+	)
+	INSERT INTO messages (conversation_id, sender_id, content, message_type, created_at)
+	SELECT c.id,
+	       CASE WHEN n % 2 = 0 THEN c.participant1_id ELSE c.participant2_id END,
+	       (ARRAY['Salaam, is this still available?','Yes, it is.','What is your best price?',
+	              'Can I see it tomorrow?','Thank you!'])[1 + n % 5],
+	       'TEXT',
+	       NOW() - ($2::int - n) * INTERVAL '3 minutes'
+	FROM convs c, generate_series(1, $2::int) n`
+
+func runSeed(args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ExitOnError)
+	users := fs.Int("users", 100000, "number of seeded users to have in total")
+	posts := fs.Int("posts", 1000000, "number of posts to add")
+	conversations := fs.Int("conversations", 20000, "number of conversations to add")
+	messages := fs.Int("messages", 20, "messages per added conversation")
+	batch := fs.Int("batch", 50000, "rows per insert statement")
+	_ = fs.Parse(args)
+
+	_, db, err := connect()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	start := time.Now()
+	for from := 1; from <= *users; from += *batch {
+		to := min(from+*batch-1, *users)
+		if _, err := db.Pool.Exec(ctx, seedUsersSQL, from, to); err != nil {
+			return fmt.Errorf("users %d-%d: %w", from, to, err)
+		}
+		fmt.Printf("users     %d/%d\n", to, *users)
+	}
+	tag, err := db.Pool.Exec(ctx, seedProfilesSQL)
+	if err != nil {
+		return fmt.Errorf("profiles: %w", err)
+	}
+	fmt.Printf("profiles  %d added\n", tag.RowsAffected())
+
+	if err := inBatches(*posts, *batch, func(n int) error {
+		_, err := db.Pool.Exec(ctx, seedPostsSQL, n)
+		return err
+	}, "posts"); err != nil {
+		return err
+	}
+
+	// Conversations are small rows but carry -messages each; keep the
+	// statement near -batch messages.
+	convBatch := max(*batch/max(*messages, 1), 1)
+	if err := inBatches(*conversations, convBatch, func(n int) error {
+		_, err := db.Pool.Exec(ctx, seedConversationsSQL, n, *messages)
+		return err
+	}, "conversations"); err != nil {
+		return err
+	}
+
+	if _, err := db.Pool.Exec(ctx, `ANALYZE users; ANALYZE profiles; ANALYZE posts; ANALYZE conversations; ANALYZE messages`); err != nil {
+		return fmt.Errorf("analyze: %w", err)
+	}
+	fmt.Printf("Seeded in %s.\n", time.Since(start).Round(time.Second))
+	return nil
+}
+
+// inBatches calls insert with batch-sized counts until total rows are done.
+func inBatches(total, batch int, insert func(n int) error, what string) error {
+	for done := 0; done < total; {
+		n := min(batch, total-done)
+		if err := insert(n); err != nil {
+			return fmt.Errorf("%s after %d: %w", what, done, err)
+		}
+		done += n
+		fmt.Printf("%-9s %d/%d\n", what, done, total)
+	}
+	return nil
+}
+
+func runClean(args []string) error {
+	fs := flag.NewFlagSet("clean", flag.ExitOnError)
+	_ = fs.Parse(args)
+
+	_, db, err := connect()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return cleanSeed(context.Background(), db)
+}
+
+// cleanSeed removes seeded users and their posts. Posts only SET NULL their
+// author on user delete, so they go first; everything else cascades.
+func cleanSeed(ctx context.Context, db *database.DB) error {
+	seeded := `SELECT id FROM users WHERE email LIKE '` + seedEmailPattern + `'`
+	tag, err := db.Pool.Exec(ctx, `DELETE FROM posts WHERE user_id IN (`+seeded+`)`)
+	if err != nil {
+		return fmt.Errorf("posts: %w", err)
+	}
+	fmt.Printf("posts     %d deleted\n", tag.RowsAffected())
+	tag, err = db.Pool.Exec(ctx, `DELETE FROM users WHERE id IN (`+seeded+`)`)
+	if err != nil {
+		return fmt.Errorf("users: %w", err)
+	}
+	fmt.Printf("users     %d deleted\n", tag.RowsAffected())
+	return nil
+}
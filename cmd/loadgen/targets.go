@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/services"
+)
+
+// searchTerms are the queries search targets use; they all match words the
+// seeded posts contain, so every request exercises ranking and enrichment.
+var searchTerms = []string{"bicycle", "apartment", "kabul", "herat", "plumber", "laptop", "classes", "bazaar"}
+
+// fixtureUser is a seeded user with a live session.
+type fixtureUser struct {
+	ID    string `json:"id"`
+	Token string `json:"token"`
+}
+
+// vegetaTarget is one line of vegeta's JSON target format.
+type vegetaTarget struct {
+	Method string              `json:"method"`
+	URL    string              `json:"url"`
+	Header map[string][]string `json:"header"`
+	Body   []byte              `json:"body,omitempty"`
+}
+
+func runTargets(args []string) error {
+	fs := flag.NewFlagSet("targets", flag.ExitOnError)
+	apiURL := fs.String("api", "http://localhost:8080", "base URL of the backend under test")
+	sessions := fs.Int("sessions", 200, "seeded users to mint sessions for")
+	n := fs.Int("n", 50000, "number of vegeta targets to write")
+	mix := fs.String("mix", "feed=60,search=30,chat=10", "relative weight of each endpoint")
+	ttl := fs.Duration("ttl", 2*time.Hour, "access-token lifetime; must outlast the run")
+	format := fs.String("format", "vegeta", "vegeta (JSON targets) or k6 (fixtures for tests/load/hot_paths.js)")
+	out := fs.String("out", "-", "output file, - for stdout")
+	_ = fs.Parse(args)
+
+	weights, err := parseMix(*mix)
+	if err != nil {
+		return err
+	}
+	if *format != "vegeta" && *format != "k6" {
+		return fmt.Errorf("unknown -format %q", *format)
+	}
+
+	cfg, db, err := connect()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	rows, err := db.Pool.Query(ctx,
+		`SELECT id, email FROM users WHERE email LIKE '`+seedEmailPattern+`' ORDER BY random() LIMIT $1`, *sessions)
+	if err != nil {
+		return fmt.Errorf("failed to load seeded users: %w", err)
+	}
+	type seeded struct{ id, email string }
+	var picked []seeded
+	for rows.Next() {
+		var s seeded
+		if err := rows.Scan(&s.id, &s.email); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to load seeded users: %w", err)
+		}
+		picked = append(picked, s)
+	}
+	rows.Close()
+	if len(picked) < 2 {
+		return fmt.Errorf("found %d seeded users; run loadgen seed first", len(picked))
+	}
+
+	// Sessions are created the way a login creates them, so the auth
+	// middleware's session check runs for real during the test.
+	jwtService := services.NewJWTService(&cfg.JWT)
+	userRepo := repositories.NewUserRepository(db)
+	users := make([]fixtureUser, 0, len(picked))
+	for _, s := range picked {
+		sessionID := uuid.New().String()
+		pair, err := jwtService.GenerateTokenPairWithTTL(s.id, s.email, 1, sessionID, *ttl)
+		if err != nil {
+			return fmt.Errorf("failed to sign token: %w", err)
+		}
+		now := time.Now()
+		if err := userRepo.CreateSession(ctx, &models.UserSession{
+			ID:               sessionID,
+			UserID:           s.id,
+			RefreshToken:     pair.RefreshToken,
+			RefreshTokenHash: jwtService.HashToken(pair.RefreshToken),
+			AccessTokenHash:  jwtService.HashToken(pair.AccessToken),
+			ExpiresAt:        now.Add(*ttl),
+			CreatedAt:        now,
+			UpdatedAt:        now,
+			AAL:              1,
+		}); err != nil {
+			return fmt.Errorf("failed to create session: %w", err)
+		}
+		users = append(users, fixtureUser{ID: s.id, Token: pair.AccessToken})
+	}
+
+	w := io.Writer(os.Stdout)
+	if *out != "-" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if *format == "k6" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(map[string]any{"users": users, "search_terms": searchTerms})
+	}
+
+	enc := json.NewEncoder(w)
+	base := strings.TrimRight(*apiURL, "/")
+	for i := 0; i < *n; i++ {
+		if err := enc.Encode(newTarget(base, weights, users)); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %d targets for %d sessions (valid for %s).\n", *n, len(users), *ttl)
+	return nil
+}
+
+// newTarget draws one request from the mix, as a random fixture user.
+func newTarget(base string, weights map[string]int, users []fixtureUser) vegetaTarget {
+	user := users[rand.IntN(len(users))]
+	header := map[string][]string{"Authorization": {"Bearer " + user.Token}}
+
+	switch pickWeighted(weights) {
+	case "search":
+		q := url.Values{"query": {searchTerms[rand.IntN(len(searchTerms))]}, "type": {"posts"}, "limit": {"20"}}
+		return vegetaTarget{Method: http.MethodGet, URL: base + "/api/v1/search?" + q.Encode(), Header: header}
+	case "chat":
+		recipient := users[rand.IntN(len(users))]
+		for recipient.ID == user.ID {
+			recipient = users[rand.IntN(len(users))]
+		}
+		content := "Salaam, is this still available?"
+		body, _ := json.Marshal(models.SendMessageRequest{
+			RecipientID: recipient.ID,
+			MessageType: models.MessageTypeText,
+			Content:     &content,
+		})
+		header["Content-Type"] = []string{"application/json"}
+		return vegetaTarget{Method: http.MethodPost, URL: base + "/api/v1/chat/messages", Header: header, Body: body}
+	default:
+		return vegetaTarget{Method: http.MethodGet, URL: base + "/api/v1/posts/feed?limit=20", Header: header}
+	}
+}
+
+// parseMix reads "feed=60,search=30,chat=10".
+func parseMix(s string) (map[string]int, error) {
+	weights := map[string]int{}
+	for _, part := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid -mix entry %q", part)
+		}
+		switch name {
+		case "feed", "search", "chat":
+		default:
+			return nil, fmt.Errorf("unknown -mix endpoint %q (want feed, search or chat)", name)
+		}
+		w, err := strconv.Atoi(value)
+		if err != nil || w < 0 {
+			return nil, fmt.Errorf("invalid -mix weight %q", part)
+		}
+		weights[name] = w
+	}
+	if weights["feed"]+weights["search"]+weights["chat"] == 0 {
+		return nil, fmt.Errorf("-mix weights add up to zero")
+	}
+	return weights, nil
+}
+
+func pickWeighted(weights map[string]int) string {
+	total := weights["feed"] + weights["search"] + weights["chat"]
+	r := rand.IntN(total)
+	for _, name := range []string{"feed", "search", "chat"} {
+		if r < weights[name] {
+			return name
+		}
+		r -= weights[name]
+	}
+	return "feed"
+}
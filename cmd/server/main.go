@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	_ "github.com/hamsaya/backend/docs" // Import swagger docs
 	"github.com/hamsaya/backend/internal/handlers"
 	"github.com/hamsaya/backend/internal/middleware"
+	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/repositories"
 	"github.com/hamsaya/backend/internal/services"
 	"github.com/hamsaya/backend/internal/utils"
@@ -23,9 +25,14 @@ import (
 	"github.com/hamsaya/backend/pkg/cache"
 	pkgcrypto "github.com/hamsaya/backend/pkg/crypto"
 	"github.com/hamsaya/backend/pkg/database"
+	"github.com/hamsaya/backend/pkg/events"
+	"github.com/hamsaya/backend/pkg/geocoding"
+	"github.com/hamsaya/backend/pkg/lifecycle"
 	"github.com/hamsaya/backend/pkg/notification"
+	"github.com/hamsaya/backend/pkg/nsfw"
 	"github.com/hamsaya/backend/pkg/observability"
 	"github.com/hamsaya/backend/pkg/redislock"
+	"github.com/hamsaya/backend/pkg/runtimeconfig"
 	"github.com/hamsaya/backend/pkg/secrets"
 	"github.com/hamsaya/backend/pkg/transcode"
 	"github.com/hamsaya/backend/pkg/websocket"
@@ -87,6 +94,7 @@ func main() {
 		os.Exit(1)
 	}
 	defer utils.Sync()
+	utils.SetLegacyListEnvelope(cfg.Server.LegacyListEnvelope)
 
 	logger := utils.GetBaseLogger()
 	sugaredLogger := utils.GetLogger()
@@ -95,6 +103,13 @@ func main() {
 	// fanout, comment notifications, post fanout) can dispatch fire-and-forget
 	// work that is awaited on graceful shutdown.
 	bgtasks.Init(logger)
+
+	// Every long-lived component — HTTP listener, WebSocket hub, workers,
+	// periodic jobs — runs under one lifecycle so SIGINT / SIGTERM stops
+	// them in order with bounded drains (see the end of main).
+	signalCtx, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+	lc := lifecycle.New(signalCtx, logger)
 	sugaredLogger.Info("Starting Hamsaya Backend API...")
 	sugaredLogger.Infow("Secrets backend", "source", secretsLabel)
 	sugaredLogger.Infow("Configuration loaded",
@@ -160,16 +175,18 @@ func main() {
 
 	// Connect to database
 	sugaredLogger.Info("Connecting to database...")
-	// Chain two pgx tracers: SlowQueryTracer logs anything over 200ms,
-	// and NewPGXTracer emits db_queries_total / db_query_duration_seconds
+	// Chain pgx tracers: SlowQueryTracer logs anything over 200ms,
+	// NewPGXTracer emits db_queries_total / db_query_duration_seconds
 	// to the OTel meter so Grafana panels show per-table query rates +
-	// latency without manual instrumentation in every repo.
+	// latency without manual instrumentation in every repo, and
+	// QueryCounterTracer feeds the per-request db_queries access-log field.
 	db, err := database.NewWithTracer(&cfg.Database, database.NewMultiTracer(
 		&database.SlowQueryTracer{
 			Logger:    logger.Named("pgx"),
 			Threshold: 200 * time.Millisecond,
 		},
 		observability.NewPGXTracer(),
+		database.QueryCounterTracer{},
 	))
 	if err != nil {
 		sugaredLogger.Fatalw("Failed to connect to database", "error", err)
@@ -177,6 +194,32 @@ func main() {
 	defer db.Close()
 	sugaredLogger.Info("Database connected successfully")
 
+	// Optionally bring the schema up to date before anything queries it.
+	// Instances deploying at once serialise on an advisory lock inside Up.
+	if cfg.Database.AutoMigrate {
+		sugaredLogger.Info("Running database migrations (DB_AUTO_MIGRATE=true)...")
+		migrateCtx, migrateCancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		err := database.NewMigrator(db, "./migrations").Up(migrateCtx)
+		migrateCancel()
+		if err != nil {
+			sugaredLogger.Fatalw("Failed to run migrations", "error", err)
+		}
+	}
+
+	// Fail fast when the database lacks the extensions or types the schema
+	// is built on, rather than at the first query that needs them.
+	verifyCtx, verifyCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	err = database.VerifySchema(verifyCtx, db)
+	verifyCancel()
+	if err != nil {
+		sugaredLogger.Fatalw("Database is not ready for this build", "error", err)
+	}
+	verifyCtx, verifyCancel = context.WithTimeout(context.Background(), 10*time.Second)
+	if pending, err := database.NewMigrator(db, "./migrations").Pending(verifyCtx); err == nil && pending > 0 {
+		sugaredLogger.Warnw("Database has pending migrations — run ./migrate up or set DB_AUTO_MIGRATE=true", "pending", pending)
+	}
+	verifyCancel()
+
 	// Mirror warn+ log entries to the app_logs table so the admin /logs page
 	// can surface them. The sink runs in a background goroutine bounded by a
 	// 256-entry channel; oversize bursts evict oldest rather than block.
@@ -205,7 +248,14 @@ func main() {
 	// Initialize WebSocket hub
 	sugaredLogger.Info("Initializing WebSocket hub...")
 	wsHub := websocket.NewHub(logger)
-	go wsHub.Run()
+	lc.Go("websocket-hub", func(context.Context) error {
+		wsHub.Run()
+		return nil
+	})
+	lc.OnStop("websocket-hub", 5*time.Second, func(context.Context) error {
+		wsHub.Shutdown()
+		return nil
+	})
 	sugaredLogger.Info("WebSocket hub started")
 
 	// Cross-instance fanout via Redis pub/sub. Enabled when WS_FANOUT=true
@@ -217,7 +267,10 @@ func main() {
 		fanout.Start()
 		wsHub.AttachFanout(fanout)
 		sugaredLogger.Infow("WebSocket pub/sub fanout enabled", "process_id", hostname)
-		defer fanout.Stop()
+		lc.OnStop("websocket-fanout", 5*time.Second, func(context.Context) error {
+			fanout.Stop()
+			return nil
+		})
 	}
 
 	// Initialize Firebase Cloud Messaging (optional - only if credentials are provided)
@@ -283,10 +336,33 @@ func main() {
 	relationshipsRepo := repositories.NewRelationshipsRepository(db)
 	postRepo := repositories.NewPostRepository(db)
 	commentRepo := repositories.NewCommentRepository(db)
+	privacyRepo := repositories.NewPrivacyRepository(db)
 	pollRepo := repositories.NewPollRepository(db)
 	eventRepo := repositories.NewEventRepository(db)
 	businessRepo := repositories.NewBusinessRepository(db)
 	businessReviewRepo := repositories.NewBusinessReviewRepository(db)
+	endorsementRepo := repositories.NewEndorsementRepository(db)
+	inviteRepo := repositories.NewInviteRepository(db)
+	stickerRepo := repositories.NewStickerRepository(db)
+	mediaScanRepo := repositories.NewMediaScanRepository(db)
+	profileMediaRepo := repositories.NewProfileMediaRepository(db)
+	accountRecoveryRepo := repositories.NewAccountRecoveryRepository(db)
+	oauthRevocationRepo := repositories.NewOAuthRevocationRepository(db)
+	businessProductRepo := repositories.NewBusinessProductRepository(db)
+	quickReplyRepo := repositories.NewBusinessQuickReplyRepository(db)
+	responseStatsRepo := repositories.NewBusinessResponseStatsRepository(db)
+	featuredBusinessRepo := repositories.NewFeaturedBusinessRepository(db)
+	branchRepo := repositories.NewBusinessBranchRepository(db)
+	linkPreviewRepo := repositories.NewLinkPreviewRepository(db)
+	lifecycleTemplateRepo := repositories.NewLifecycleTemplateRepository(db)
+	businessBookingRepo := repositories.NewBusinessBookingRepository(db)
+	uploadSessionRepo := repositories.NewUploadSessionRepository(db)
+	helpPledgeRepo := repositories.NewHelpPledgeRepository(db)
+	eventHostRepo := repositories.NewEventHostRepository(db)
+	calendarFeedRepo := repositories.NewCalendarFeedRepository(db)
+	locationRepo := repositories.NewLocationRepository(db)
+	bookmarkCollectionRepo := repositories.NewBookmarkCollectionRepository(db)
+	groupRepo := repositories.NewGroupRepository(db)
 	businessVerificationRepo := repositories.NewBusinessVerificationRepository(db)
 	categoryRepo := repositories.NewCategoryRepository(db)
 	conversationRepo := repositories.NewConversationRepository(db)
@@ -302,56 +378,201 @@ func main() {
 	dailyLimitRepo := repositories.NewDailyLimitRepository(db)
 	monetizationRepo := repositories.NewMonetizationRepository(db)
 	appLogRepo := repositories.NewAppLogRepository(db)
+	emailDeliveryRepo := repositories.NewEmailDeliveryRepository(db)
+	tokenRevocationRepo := repositories.NewTokenRevocationRepository(db)
+	syncTombstoneRepo := repositories.NewSyncTombstoneRepository(db)
+	postBroadcastRepo := repositories.NewPostBroadcastRepository(db)
+	regionalTrendingRepo := repositories.NewRegionalTrendingRepository(db)
+	storageQuotaRepo := repositories.NewStorageQuotaRepository(db)
+	storageReconcileRepo := repositories.NewStorageReconcileRepository(db)
+	mutedTermRepo := repositories.NewMutedTermRepository(db)
+	profileViewRepo := repositories.NewProfileViewRepository(db)
+	businessImportRepo := repositories.NewBusinessImportRepository(db)
+	businessClaimRepo := repositories.NewBusinessClaimRepository(db)
 
 	// Initialize services
 	sugaredLogger.Info("Initializing services...")
 	jwtService := services.NewJWTService(&cfg.JWT)
 	passwordService := services.NewPasswordService()
-	emailService := services.NewEmailService(&cfg.Email, logger)
-	tokenStorage := services.NewTokenStorageService(redisClient, logger)
+	emailService := services.NewEmailService(&cfg.Email, logger).
+		WithDeliveryTracking(emailDeliveryRepo)
+	emailDeliveryService := services.NewEmailDeliveryService(emailDeliveryRepo, cfg.Email.ResendWebhookSecret, logger)
+	tokenStorage := services.NewTokenStorageService(redisClient, logger).
+		WithRevocationStore(tokenRevocationRepo)
+	// Refill Redis with revocations it may have lost in a restart, so
+	// logged-out access tokens don't come back.
+	if restored, err := tokenStorage.RestoreRevocations(context.Background(), cfg.JWT.AccessTokenDuration); err != nil {
+		sugaredLogger.Warnw("Failed to restore token revocations", "error", err)
+	} else if restored > 0 {
+		sugaredLogger.Infow("Token revocations restored", "count", restored)
+	}
 	mfaService := services.NewMFAService(mfaRepo, userRepo, passwordService, logger)
-	oauthService := services.NewOAuthService(cfg, userRepo, logger)
+	oauthService := services.NewOAuthService(cfg, userRepo, logger).
+		WithRevocations(oauthRevocationRepo)
 	storageService := services.NewStorageService(cfg, logger)
+	uploadSessionService := services.NewUploadSessionService(uploadSessionRepo, storageService, logger)
 
 	// Async WebP transcode pool. Opt-in via TRANSCODE_ASYNC=true so the
 	// existing synchronous-encode upload path keeps working until handlers
 	// are migrated to enqueue jobs. Pool runs only when storage is real.
+	var transcodeQueue *transcode.Queue
 	if os.Getenv("TRANSCODE_ASYNC") == "true" && storageService.Client() != nil {
-		transcodeQueue := transcode.NewQueue(redisClient, "")
+		transcodeQueue = transcode.NewQueue(redisClient, "")
 		transcodePool := transcode.NewPool(transcodeQueue, storageService.Client(), logger, 4)
-		transcodeCtx, transcodeCancel := context.WithCancel(context.Background())
-		go transcodePool.Run(transcodeCtx)
-		defer transcodeCancel()
+		lc.Go("transcode", func(ctx context.Context) error {
+			transcodePool.Run(ctx)
+			return nil
+		})
 		sugaredLogger.Info("Transcode pool started (4 workers)")
 	}
-	profileService := services.NewProfileService(userRepo, postRepo, commentRepo, relationshipsRepo, logger)
+	// Async image moderation. Queued uploads are sent to the NSFW
+	// classifier by the media-scan job below; flagged ones top the admin
+	// review queue. Off unless a classifier URL is configured.
+	var mediaScanner *services.MediaScanner
+	if cfg.Moderation.NSFWScannerURL != "" && storageService.Client() != nil {
+		classifier := nsfw.New(cfg.Moderation.NSFWScannerURL, cfg.Moderation.NSFWBlockThreshold)
+		mediaScanner = services.NewMediaScanner(mediaScanRepo, classifier, "nudenet", storageService.Client(), cfg.Moderation.BlurFlaggedMedia, logger)
+		sugaredLogger.Infow("Media scanner enabled", "blur_flagged", cfg.Moderation.BlurFlaggedMedia)
+	}
+	// Reverse geocoder for posts/profiles that only send coordinates. Results
+	// are cached per ~100 m cell for a month; addresses barely move.
+	reverseGeocoder, err := geocoding.New(cfg.Geocoding.Provider, cfg.Geocoding.BaseURL)
+	if err != nil {
+		logger.Warn("Unknown geocoding provider, falling back to Nominatim", zap.Error(err))
+		reverseGeocoder = geocoding.NewNominatim(cfg.Geocoding.BaseURL)
+	}
+	cachedGeocoder := geocoding.NewCached(reverseGeocoder, cache.New(redisClient, "geocode", logger), 30*24*time.Hour)
+	profilePrivacy := services.NewProfilePrivacy(privacyRepo, relationshipsRepo, logger)
+	// New avatars and covers wait in the profile media queue when holding
+	// is on; the scanner approves the ones that score safe.
+	profileMediaService := services.NewProfileMediaService(profileMediaRepo, cfg.Moderation.HoldProfileMedia, logger)
+	profileViewService := services.NewProfileViewService(redisClient, profileViewRepo, businessRepo, logger)
+	profileService := services.NewProfileService(userRepo, postRepo, commentRepo, relationshipsRepo, logger).
+		WithGeocoder(cachedGeocoder).
+		WithPrivacy(profilePrivacy).
+		WithEndorsements(endorsementRepo).
+		WithInvites(inviteRepo).
+		WithMediaReview(profileMediaService).
+		WithProfileViews(profileViewService)
+	mutedTermService := services.NewMutedTermService(mutedTermRepo, postRepo, logger).
+		WithCache(cache.New(redisClient, "muted_terms", logger))
 	notificationService := services.NewNotificationService(notificationRepo, notificationSettingsRepo, userRepo, fcmClient, redisClient, wsHub, logger).
 		WithCache(cache.New(redisClient, "notifications", logger)).
-		WithAPNs(apnsClient)
+		WithAPNs(apnsClient).
+		WithTombstones(syncTombstoneRepo).
+		WithMutedTerms(mutedTermService)
 	relationshipsService := services.NewRelationshipsService(relationshipsRepo, userRepo, notificationService, logger)
+	responsivenessService := services.NewBusinessResponsivenessService(responseStatsRepo, businessRepo, logger)
 	businessService := services.NewBusinessService(businessRepo, userRepo, notificationService, logger).
-		WithCache(cache.New(redisClient, "businesses", logger))
+		WithCache(cache.New(redisClient, "businesses", logger)).
+		WithGalleryLimit(cfg.Business.MaxGalleryImages).
+		WithMediaReview(profileMediaService).
+		WithResponsiveness(responsivenessService).
+		WithProfileViews(profileViewService)
+	profileMediaService.WithTargets(profileService, businessService)
+	mediaScanner.WithProfileMedia(profileMediaService)
 	businessReviewService := services.NewBusinessReviewService(businessReviewRepo, businessRepo, userRepo, notificationService, logger)
+	endorsementService := services.NewEndorsementService(endorsementRepo, userRepo, relationshipsRepo, notificationService, logger)
+	inviteService := services.NewInviteService(inviteRepo, cfg.Share.InviteURL, logger)
+	businessProductService := services.NewBusinessProductService(businessProductRepo, businessRepo, logger)
+	quickReplyService := services.NewBusinessQuickReplyService(quickReplyRepo, businessRepo, logger)
+	branchService := services.NewBusinessBranchService(branchRepo, businessRepo, logger)
+	linkPreviewService := services.NewLinkPreviewService(linkPreviewRepo, logger)
+	businessBookingService := services.NewBusinessBookingService(businessBookingRepo, businessRepo, userRepo, notificationService, logger)
 	businessVerificationService := services.NewBusinessVerificationService(businessVerificationRepo, businessRepo, notificationService, logger).
 		WithBusinessCache(cache.New(redisClient, "businesses", logger))
+	businessClaimService := services.NewBusinessClaimService(businessClaimRepo, businessRepo, userRepo, notificationService, logger).
+		WithBusinessCache(cache.New(redisClient, "businesses", logger))
 	categoryService := services.NewCategoryService(categoryRepo, logger).
 		WithCache(cache.New(redisClient, "categories", logger))
+	locationService := services.NewLocationService(locationRepo, logger).
+		WithCache(cache.New(redisClient, "locations", logger))
 	fanoutService := services.NewFanoutService(fanoutRepo, logger)
 	dailyLimitService := services.NewDailyLimitService(dailyLimitRepo, db, redisClient, logger)
 	monetizationService := services.NewMonetizationService(monetizationRepo, storageService, logger)
 	automodService := services.NewAutomodService(db, logger)
-	postService := services.NewPostService(postRepo, pollRepo, userRepo, businessRepo, relationshipsRepo, categoryRepo, eventRepo, notificationService, fanoutService, fanoutRepo, dailyLimitService, automodService, cfg.Storage.BucketName, logger)
-	commentService := services.NewCommentService(commentRepo, postRepo, userRepo, businessRepo, notificationService, logger)
-	pollService := services.NewPollService(pollRepo, postRepo, userRepo, notificationService, logger)
+	creationThrottle := services.NewCreationThrottle(redisClient, services.CreationLimitsFromConfig(cfg.RateLimit), logger)
+	contentLimits := services.ContentLimitsFromConfig(cfg.Content)
+	bookmarkCollectionService := services.NewBookmarkCollectionService(bookmarkCollectionRepo, logger)
+	helpPledgeService := services.NewHelpPledgeService(helpPledgeRepo, postRepo, userRepo, notificationService, logger)
+	eventHostService := services.NewEventHostService(eventHostRepo, postRepo, userRepo, businessRepo, notificationService, logger)
+	// Domain events: services publish, subscribers are registered below.
+	eventBus := events.New(logger)
+	postService := services.NewPostService(postRepo, pollRepo, userRepo, businessRepo, relationshipsRepo, categoryRepo, eventRepo, notificationService, fanoutService, fanoutRepo, dailyLimitService, automodService, cfg.Storage.BucketName, logger).
+		WithCreationThrottle(creationThrottle).
+		WithContentLimits(contentLimits).
+		WithProducts(businessProductService).
+		WithBranches(branchService).
+		WithLinkPreviews(linkPreviewService).
+		WithGroups(groupRepo).
+		WithPledges(helpPledgeService).
+		WithEventHosts(eventHostService).
+		WithPrivacy(profilePrivacy).
+		WithGeocoder(cachedGeocoder).
+		WithBookmarkCollections(bookmarkCollectionService).
+		WithShareLinks(cfg.Share.LinkBaseURL, cfg.Share.PostURL).
+		WithMediaScanner(mediaScanner).
+		WithViewCounter(services.NewPostViewCounter(redisClient, postRepo, logger)).
+		WithUploadSessions(uploadSessionService).
+		WithMutedTerms(mutedTermService).
+		WithEvents(eventBus)
+	businessService.WithEvents(eventBus).WithFeaturedPosts(postService)
+	groupService := services.NewGroupService(groupRepo, postRepo, postService, logger)
+	commentService := services.NewCommentService(commentRepo, postRepo, userRepo, businessRepo, notificationService, logger).
+		WithCreationThrottle(creationThrottle).
+		WithContentLimits(contentLimits)
+	pollService := services.NewPollService(pollRepo, postRepo, userRepo, notificationService, logger).
+		WithVoteLockAfter(cfg.Poll.VoteLockAfter).
+		WithContentLimits(contentLimits)
 	eventService := services.NewEventService(eventRepo, postRepo, userRepo, notificationService, logger)
-	authService := services.NewAuthService(userRepo, adminRepo, passwordService, jwtService, emailService, tokenStorage, mfaService, cfg, logger)
+	calendarService := services.NewCalendarService(calendarFeedRepo, postRepo, postService.Authorizer(), cfg.Share.PostURL, logger)
+	loginGuard := services.NewLoginGuard(redisClient, logger)
+	authService := services.NewAuthService(userRepo, adminRepo, passwordService, jwtService, emailService, tokenStorage, mfaService, cfg, logger).
+		WithLoginGuard(loginGuard).
+		WithInvites(inviteService).
+		WithAccountRecovery(accountRecoveryRepo).
+		WithOAuthRevocations(oauthRevocationRepo)
 	authService.SetNotificationService(notificationService)
-	chatService := services.NewChatService(conversationRepo, messageRepo, userRepo, businessRepo, relationshipsRepo, notificationService, wsHub, logger)
+	unreadCounters := services.NewUnreadCounters(redisClient, messageRepo, logger)
+	stickerService := services.NewStickerService(stickerRepo, logger)
+	chatService := services.NewChatService(conversationRepo, messageRepo, userRepo, businessRepo, relationshipsRepo, notificationService, wsHub, logger).
+		WithCreationThrottle(creationThrottle).
+		WithProducts(businessProductService).
+		WithPosts(postRepo).
+		WithUnreadCounters(unreadCounters).
+		WithStickers(stickerService).
+		WithAttachmentStorage(storageService).
+		WithTombstones(syncTombstoneRepo).
+		WithResponsiveness(responsivenessService).
+		WithEvents(eventBus)
 	searchService := services.NewSearchService(searchRepo, postRepo, userRepo, businessRepo, categoryRepo, relationshipsRepo, logger).
-		WithCache(cache.New(redisClient, "discover", logger))
-	reportService := services.NewReportService(reportRepo, postRepo, userRepo, validator)
+		WithCache(cache.New(redisClient, "discover", logger)).
+		WithAuthorizer(postService.Authorizer())
+	reportService := services.NewReportService(reportRepo, postRepo, userRepo, validator).
+		WithEvents(eventBus).
+		WithChat(conversationRepo, messageRepo)
+	postService.SubscribeNotifications(eventBus)
+	chatService.SubscribeNotifications(eventBus)
+	quickReplyService.SubscribeAutoReplies(eventBus, chatService)
+	unreadCounters.SubscribeMessages(eventBus)
+	reportService.SubscribeModeration(eventBus)
+	searchService.SubscribeHashtags(eventBus)
+	services.SubscribeAnalytics(eventBus)
+	mediaScanner.SubscribeBusinessMedia(eventBus)
+	// "New posts" hints for GET /posts/stream, shared across instances
+	// over Redis pub/sub.
+	feedStream := services.NewFeedStream(redisClient, logger)
+	feedStream.SubscribePosts(eventBus)
+	linkPreviewService.SubscribePosts(eventBus)
+	feedStream.Start()
+	lc.OnStop("feed-stream", 5*time.Second, func(context.Context) error {
+		feedStream.Stop()
+		return nil
+	})
 	feedbackService := services.NewFeedbackService(feedbackRepo, validator)
-	adminService := services.NewAdminService(adminRepo, db, fcmClient, notificationService, logger)
+	adminService := services.NewAdminService(adminRepo, db, fcmClient, notificationService, logger).
+		WithEmailDelivery(emailDeliveryService).
+		WithLoginGuard(loginGuard)
 	helpChatService := services.NewHelpChatService(helpChatRepo, logger)
 	helpChatService.SetNotificationService(notificationService)
 	// Proactive re-engagement jobs (event reminders, dormant win-back, sell
@@ -362,10 +583,43 @@ func main() {
 
 	// Initialize middleware
 	sugaredLogger.Info("Initializing middleware...")
-	authMiddleware := middleware.NewAuthMiddleware(jwtService, userRepo, tokenStorage, logger)
+	authMiddleware := middleware.NewAuthMiddleware(jwtService, userRepo, tokenStorage, logger).
+		WithActingBusiness(businessRepo).
+		WithAdminMFA(cfg.Admin.RequireMFA)
+	adminIPAllowlist := middleware.AdminIPAllowlist(cfg.Admin.IPAllowlist, logger)
+	wsHub.AttachAuthenticator(authMiddleware.AuthenticateSocketToken)
 	// verifiedAuth requires email verification; use for create/update/delete (post, comment, follow, etc.)
 	verifiedAuth := authMiddleware.RequireVerifiedEmail()
-	rateLimiter := middleware.NewRateLimiter(redisClient, logger)
+	// Hot-reloadable runtime settings (rate limits, feature flags, service
+	// mode switches) live in a Redis hash; each instance re-reads it every 15s.
+	runtimeSettings := runtimeconfig.New(redisClient, logger)
+	rateLimiter := middleware.NewRateLimiter(redisClient, logger).WithRuntimeSettings(runtimeSettings)
+	creationThrottle.WithRuntimeSettings(runtimeSettings)
+	serviceMode := middleware.NewServiceMode(runtimeSettings)
+	// Directory imports: businesses whose owner has no account yet are
+	// held by the importing admin until the owner claims them.
+	businessImportService := services.NewBusinessImportService(businessImportRepo, userRepo, businessService, emailService, cfg.Email.EmailVerifyBaseURL, logger)
+	postBroadcastService := services.NewPostBroadcastService(postBroadcastRepo, postRepo, notificationService, logger).
+		WithAccessControl(postService.Access()).
+		WithRuntimeSettings(runtimeSettings)
+	regionalTrendingService := services.NewRegionalTrendingService(regionalTrendingRepo, notificationService, logger).
+		WithRuntimeSettings(runtimeSettings)
+	storageService.WithQuota(storageQuotaRepo, runtimeSettings)
+	postService.WithExpiryPolicy(services.NewPostExpiryPolicy(runtimeSettings)).
+		WithBookmarkCounter(services.NewPostBookmarkCounter(postRepo, notificationService, runtimeSettings, logger))
+	notificationService.WithCommentThrottle(runtimeSettings)
+	// Welcome, first-post nudge and re-engagement copy is admin-edited;
+	// their timing is a runtime setting.
+	lifecycleMessages := services.NewLifecycleMessageService(lifecycleTemplateRepo, runtimeSettings, logger)
+	authService.WithLifecycleMessages(lifecycleMessages)
+	engagementService.WithLifecycleMessages(lifecycleMessages)
+	// Orphaned-media cleanup only makes sense against real storage.
+	var storageReconcileService *services.StorageReconcileService
+	if client := storageService.Client(); client != nil {
+		storageReconcileService = services.NewStorageReconcileService(storageReconcileRepo, client, logger).
+			WithQuota(storageQuotaRepo).
+			WithRuntimeSettings(runtimeSettings)
+	}
 	// IP-keyed cap for the unauthenticated read surface — makes catalog
 	// scraping impractical while leaving real browsing untouched.
 	publicReadRL := rateLimiter.LimitByType("public-read")
@@ -391,26 +645,37 @@ func main() {
 
 	// Global middleware.
 	router.Use(gin.Recovery())
-	router.Use(middleware.Logger(sugaredLogger))
+	router.Use(middleware.AccessLogger(sugaredLogger, middleware.AccessLogConfig{
+		SampleRate: cfg.Server.AccessLogSampleRate,
+		SkipPaths:  cfg.Server.AccessLogSkipPaths,
+	}))
 	router.Use(middleware.CORS(cfg.CORS))
 	router.Use(middleware.RequestID())
-	router.Use(middleware.SecurityHeaders())
+	router.Use(middleware.ErrorHandler())
+	router.Use(middleware.SecurityHeaders(cfg.Security))
 	router.Use(middleware.BodyLimit(middleware.DefaultMaxBodyBytes))
 	router.Use(middleware.Timeout(middleware.DefaultRequestTimeout))
 	router.Use(banMiddleware.Enforce())
+	router.Use(serviceMode.Enforce())
 
-	// gzip JSON responses (excludes uploads, websocket, metrics)
+	// gzip JSON responses (excludes uploads, websocket, metrics). Bodies under
+	// 1 KB go out as-is; compressing them costs more than it saves.
 	router.Use(gzip.Gzip(
 		gzip.DefaultCompression,
+		gzip.WithMinLength(1024),
 		gzip.WithExcludedPaths([]string{
 			"/api/v1/posts/upload-image",
 			"/api/v1/users/me/avatar",
 			"/api/v1/users/me/cover",
-			"/api/v1/businesses/", // covers /:id/avatar /cover /attachments
 			"/api/v1/chat/ws",
+			"/api/v1/posts/stream",
 			"/metrics",
 			"/health",
 		}),
+		// Only the business upload routes; business detail and lists compress.
+		gzip.WithExcludedPathsRegexs([]string{
+			`^/api/v1/businesses/[^/]+/(avatar|cover|attachments)$`,
+		}),
 	))
 
 	// OpenTelemetry: in-flight count, request duration/count metrics, and tracing
@@ -423,11 +688,85 @@ func main() {
 
 	// Initialize handlers
 	sugaredLogger.Info("Initializing handlers...")
-	healthHandler := handlers.NewHealthHandler(db, redisClient)
+	healthHandler := handlers.NewHealthHandler(db, redisClient).
+		WithCriticalComponents(cfg.Monitoring.HealthCriticalComponents).
+		WithCheck("fcm", func(ctx context.Context) (string, error) {
+			if fcmClient == nil {
+				return "", errors.New("not configured; push notifications disabled")
+			}
+			return "configured", nil
+		}).
+		WithCheck("storage", func(ctx context.Context) (string, error) {
+			client := storageService.Client()
+			if client == nil {
+				return "", errors.New("not configured")
+			}
+			stat := client.Stat(ctx)
+			if !stat.Reachable {
+				if stat.Error != "" {
+					return "", errors.New(stat.Error)
+				}
+				return "", fmt.Errorf("bucket %q not found", stat.Bucket)
+			}
+			return stat.Bucket, nil
+		}).
+		WithCheck("migrations", func(ctx context.Context) (string, error) {
+			pending, err := database.NewMigrator(db, "./migrations").Pending(ctx)
+			if err != nil {
+				return "", err
+			}
+			if pending > 0 {
+				return "", fmt.Errorf("%d pending migrations", pending)
+			}
+			return "up to date", nil
+		})
+	if transcodeQueue != nil {
+		healthHandler.WithCheck("transcode_queue", func(ctx context.Context) (string, error) {
+			depth, err := transcodeQueue.PendingCount(ctx)
+			if err != nil {
+				return "", err
+			}
+			// Four workers drain a few jobs per second; a backlog this deep
+			// means the pool is stuck or badly under-provisioned.
+			if depth > 1000 {
+				return "", fmt.Errorf("queue depth %d exceeds 1000", depth)
+			}
+			return fmt.Sprintf("queue depth %d", depth), nil
+		})
+	}
 	authHandler := handlers.NewAuthHandler(authService, validator, logger)
 	adminCookieCfg := utils.NewCookieConfig(cfg.Server.Env, cfg.Server.AdminCookieDomain)
 	featureFlagRepo := repositories.NewFeatureFlagRepository(db)
-	systemHandler := handlers.NewSystemHandler(db, redisClient, featureFlagRepo, wsHub, storageService.Client(), logger)
+	if flags, err := featureFlagRepo.List(context.Background()); err != nil {
+		sugaredLogger.Warnw("Failed to load feature flags for runtime settings", "error", err)
+	} else {
+		for _, f := range flags {
+			runtimeSettings.Register(runtimeconfig.Setting{
+				Key:         "flag." + f.Key,
+				Kind:        runtimeconfig.KindBool,
+				Default:     strconv.FormatBool(f.Enabled),
+				Description: f.Description,
+			})
+		}
+	}
+	if err := runtimeSettings.Refresh(context.Background()); err != nil {
+		sugaredLogger.Warnw("Initial runtime settings load failed, using defaults", "error", err)
+	}
+	lc.Go("runtime-settings", func(ctx context.Context) error {
+		runtimeSettings.Run(ctx, 15*time.Second)
+		return nil
+	})
+	// Home timelines are mirrored into Redis; the redis_home_timeline flag
+	// decides whether reads are served from there or from user_feeds.
+	homeTimeline := services.NewRedisTimeline(redisClient, fanoutRepo, func() bool {
+		return runtimeSettings.Bool("flag."+services.FlagRedisHomeTimeline, false)
+	}, logger)
+	fanoutService.WithFeedProvider(homeTimeline)
+	postService.WithFeedProvider(homeTimeline)
+	systemHandler := handlers.NewSystemHandler(db, redisClient, featureFlagRepo, wsHub, storageService.Client(), logger).
+		WithRuntimeSettings(runtimeSettings).
+		WithServiceMode(serviceMode).
+		WithStorageReconcile(storageReconcileService)
 	storageHandler := handlers.NewStorageHandler(storageService.Client(), logger)
 	backupService, err := services.NewBackupService(db, cfg, logger)
 	if err != nil {
@@ -437,26 +776,51 @@ func main() {
 
 	deletionRequestService := services.NewDeletionRequestService(db, adminService, logger)
 	deletionRequestHandler := handlers.NewDeletionRequestHandler(deletionRequestService, adminService, logger)
+	accountRecoveryHandler := handlers.NewAccountRecoveryHandler(authService, adminService, validator, logger)
 
 	automodHandler := handlers.NewAutomodHandler(automodService, adminService, logger)
 
-	mediaModerationService := services.NewMediaModerationService(db, logger)
-	mediaModerationHandler := handlers.NewMediaModerationHandler(mediaModerationService, adminService, logger)
+	mediaModerationService := services.NewMediaModerationService(db, logger).
+		WithBusinesses(businessService)
+	mediaModerationHandler := handlers.NewMediaModerationHandler(mediaModerationService, adminService, logger).
+		WithProfileMedia(profileMediaService)
 	customRoleRepo := repositories.NewCustomRoleRepository(db)
-	adminAuthHandler := handlers.NewAdminAuthHandler(authService, customRoleRepo, validator, logger, adminCookieCfg, cfg.JWT)
+	adminAuthHandler := handlers.NewAdminAuthHandler(authService, customRoleRepo, validator, logger, adminCookieCfg, cfg.Admin)
 	customRoleHandler := handlers.NewCustomRoleHandler(customRoleRepo, logger)
+	searchService.WithCustomRoles(customRoleRepo)
+	featuredBusinessService := services.NewFeaturedBusinessService(featuredBusinessRepo, businessRepo, businessService, logger)
+	searchService.WithFeaturedBusinesses(featuredBusinessService)
 	mfaHandler := handlers.NewMFAHandler(mfaService, validator, logger)
 	oauthHandler := handlers.NewOAuthHandler(authService, oauthService, validator, logger)
 	profileHandler := handlers.NewProfileHandler(profileService, storageService, deletionRequestService, validator, logger)
 	relationshipsHandler := handlers.NewRelationshipsHandler(relationshipsService, logger)
-	postHandler := handlers.NewPostHandler(postService, storageService, validator, logger)
+	postHandler := handlers.NewPostHandler(postService, storageService, validator, logger).
+		WithUploadSessions(uploadSessionService)
 	commentHandler := handlers.NewCommentHandler(commentService, validator, logger)
 	pollHandler := handlers.NewPollHandler(pollService, validator, logger)
 	eventHandler := handlers.NewEventHandler(eventService, validator, logger)
 	businessHandler := handlers.NewBusinessHandler(businessService, storageService, validator, logger)
 	businessReviewHandler := handlers.NewBusinessReviewHandler(businessReviewService, userRepo, validator, logger)
+	endorsementHandler := handlers.NewEndorsementHandler(endorsementService, validator, logger)
+	inviteHandler := handlers.NewInviteHandler(inviteService, logger)
+	stickerHandler := handlers.NewStickerHandler(stickerService, validator, logger)
+	businessProductHandler := handlers.NewBusinessProductHandler(businessProductService, storageService, validator, logger)
+	quickReplyHandler := handlers.NewBusinessQuickReplyHandler(quickReplyService, validator, logger)
+	mutedTermHandler := handlers.NewMutedTermHandler(mutedTermService, validator, logger)
+	branchHandler := handlers.NewBusinessBranchHandler(branchService, validator, logger)
+	businessBookingHandler := handlers.NewBusinessBookingHandler(businessBookingService, validator, logger)
+	helpPledgeHandler := handlers.NewHelpPledgeHandler(helpPledgeService, validator, logger)
+	eventHostHandler := handlers.NewEventHostHandler(eventHostService, validator, logger)
+	calendarHandler := handlers.NewCalendarHandler(calendarService, logger)
+	groupHandler := handlers.NewGroupHandler(groupService, validator, logger)
 	businessVerificationHandler := handlers.NewBusinessVerificationHandler(businessVerificationService, storageService, adminService, validator, logger)
+	postBroadcastHandler := handlers.NewPostBroadcastHandler(postBroadcastService, adminService, validator, logger)
+	businessImportHandler := handlers.NewBusinessImportHandler(businessImportService, adminService, validator, logger)
+	businessClaimHandler := handlers.NewBusinessClaimHandler(businessClaimService, storageService, adminService, validator, logger)
+	trendingHandler := handlers.NewTrendingHandler(regionalTrendingService, logger)
 	categoryHandler := handlers.NewCategoryHandler(categoryService, validator, logger)
+	locationHandler := handlers.NewLocationHandler(locationService, validator, logger)
+	bookmarkCollectionHandler := handlers.NewBookmarkCollectionHandler(bookmarkCollectionService, validator, logger)
 	chatHandler := handlers.NewChatHandler(chatService, wsHub, validator, logger, cfg)
 	notificationHandler := handlers.NewNotificationHandler(notificationService, validator, logger)
 	searchHandler := handlers.NewSearchHandler(searchService, validator, logger)
@@ -466,8 +830,17 @@ func main() {
 	helpChatHandler := handlers.NewHelpChatHandler(helpChatService, validator, logger)
 	dailyLimitHandler := handlers.NewDailyLimitHandler(dailyLimitService, userRepo, validator, logger)
 	monetizationHandler := handlers.NewMonetizationHandler(monetizationService, storageService, validator, logger, redisClient)
+	featuredBusinessHandler := handlers.NewFeaturedBusinessHandler(featuredBusinessService, adminService, validator, logger, redisClient)
 	appLogHandler := handlers.NewAppLogHandler(appLogRepo, logger)
 	appVersionHandler := handlers.NewAppVersionHandler(cfg.AppVersion)
+	contentLimitsHandler := handlers.NewContentLimitsHandler(contentLimits)
+	feedStreamHandler := handlers.NewFeedStreamHandler(feedStream)
+	lifecycleTemplateHandler := handlers.NewLifecycleTemplateHandler(lifecycleMessages, adminService, validator, logger)
+	emailWebhookHandler := handlers.NewEmailWebhookHandler(emailDeliveryService, logger)
+
+	// External post share short links (public; counts the click, then
+	// redirects to the post's landing page).
+	router.GET("/s/:code", publicReadRL, postHandler.OpenShareLink)
 
 	// Health check routes (no versioning)
 	router.GET("/health", healthHandler.Health)
@@ -518,6 +891,15 @@ func main() {
 		c.Data(http.StatusOK, "image/jpeg", services.EmailIconBytes())
 	})
 
+	// Public keys for verifying access tokens (RS256 keyring entries only;
+	// empty while tokens are HMAC-signed). Not wrapped in the API envelope:
+	// JWKS consumers expect the bare RFC 7517 document. Short cache so a
+	// staged rotation key reaches verifiers well before it signs anything.
+	router.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, jwtService.JWKS())
+	})
+
 	// API v1 routes
 	v1 := router.Group("/api/v1")
 	{
@@ -533,11 +915,38 @@ func main() {
 		// Platform-aware "Open Hamsaya" redirect for emails (iOS→App Store,
 		// Android→Play, else→website). Public, no auth.
 		v1.GET("/app/open", appVersionHandler.OpenApp)
+		// Post/comment limits for the client's counters — public, no auth.
+		v1.GET("/config/limits", middleware.ETag(), contentLimitsHandler.GetLimits)
+
+		// Email provider delivery callbacks (bounce/complaint/delivered).
+		// No bearer auth — authenticated by the provider's signature headers.
+		v1.POST("/webhooks/email/resend", rateLimiter.LimitByType("webhook"), emailWebhookHandler.ResendWebhook)
+		v1.POST("/webhooks/oauth/apple", rateLimiter.LimitByType("webhook"), oauthHandler.AppleNotification)
+		v1.POST("/webhooks/oauth/google", rateLimiter.LimitByType("webhook"), oauthHandler.GoogleSecurityEvent)
 
 		// Explicit /users/me/* routes first so they always match (avoid 404 from param route)
 		v1.GET("/users/me/posts", authMiddleware.RequireAuth(), postHandler.GetMyPosts)
+		v1.GET("/users/me/listings", authMiddleware.RequireAuth(), postHandler.GetMyListings)
 		v1.GET("/users/me/bookmarks", authMiddleware.RequireAuth(), postHandler.GetMyBookmarks)
+		v1.PUT("/users/me/bookmarks/:post_id/collection", authMiddleware.RequireAuth(), bookmarkCollectionHandler.AssignBookmark)
+		v1.GET("/users/me/bookmark-collections", authMiddleware.RequireAuth(), bookmarkCollectionHandler.ListCollections)
+		v1.POST("/users/me/bookmark-collections", authMiddleware.RequireAuth(), bookmarkCollectionHandler.CreateCollection)
+		v1.PUT("/users/me/bookmark-collections/:collection_id", authMiddleware.RequireAuth(), bookmarkCollectionHandler.RenameCollection)
+		v1.DELETE("/users/me/bookmark-collections/:collection_id", authMiddleware.RequireAuth(), bookmarkCollectionHandler.DeleteCollection)
 		v1.GET("/users/me/events", authMiddleware.RequireAuth(), postHandler.GetMyEvents)
+		v1.GET("/users/me/event-host-invitations", authMiddleware.RequireAuth(), eventHostHandler.ListInvitations)
+		// Token in the query instead of a bearer header: calendar apps subscribe to the URL.
+		v1.GET("/users/me/events.ics", publicReadRL, calendarHandler.EventsFeed)
+		v1.POST("/users/me/calendar-feed", authMiddleware.RequireAuth(), calendarHandler.EnableEventsFeed)
+		v1.DELETE("/users/me/calendar-feed", authMiddleware.RequireAuth(), calendarHandler.DisableEventsFeed)
+		// Email change: code to the new address, revert link to the old one
+		v1.POST("/users/me/email/change", authMiddleware.RequireAuth(), rateLimiter.LimitStrict(), authHandler.RequestEmailChange)
+		v1.POST("/users/me/email/confirm", authMiddleware.RequireAuth(), rateLimiter.LimitPasswordReset(), authHandler.ConfirmEmailChange)
+		// Trusted contact recovery: the contact gets the code, support completes it
+		v1.GET("/users/me/trusted-contact", authMiddleware.RequireAuth(), authHandler.GetTrustedContact)
+		v1.PUT("/users/me/trusted-contact", authMiddleware.RequireAuth(), rateLimiter.LimitStrict(), authHandler.SetTrustedContact)
+		v1.DELETE("/users/me/trusted-contact", authMiddleware.RequireAuth(), authHandler.RemoveTrustedContact)
+		v1.DELETE("/users/me/recovery", authMiddleware.RequireAuth(), authHandler.CancelAccountRecovery)
 
 		// Public auth routes (with rate limiting)
 		auth := v1.Group("/auth")
@@ -554,6 +963,8 @@ func main() {
 			auth.POST("/forgot-password", rateLimiter.LimitStrict(), authHandler.ForgotPassword)
 			auth.POST("/verify-reset-code", rateLimiter.LimitPasswordReset(), authHandler.VerifyResetCode)
 			auth.POST("/reset-password", rateLimiter.LimitPasswordReset(), authHandler.ResetPassword)
+			auth.POST("/email/revert", rateLimiter.LimitPasswordReset(), authHandler.RevertEmailChange)
+			auth.POST("/recovery/trusted-contact", rateLimiter.LimitStrict(), authHandler.StartAccountRecovery)
 
 			// MFA verification
 			auth.POST("/mfa/verify", rateLimiter.LimitAuth(), authHandler.VerifyMFA)
@@ -567,12 +978,13 @@ func main() {
 			// JSON-token endpoints above; mobile clients keep using /login.
 			// Rate limiting intentionally disabled on /admin/login and the
 			// MFA/refresh helpers per operator request. Brute-force defence
-			// for the admin panel must come from external means (IP allow-
-			// list at the reverse proxy, MFA on every admin, account lockout
-			// after N failed attempts in auth_service).
-			auth.POST("/admin/login", adminAuthHandler.AdminLogin)
-			auth.POST("/admin/refresh", adminAuthHandler.AdminRefresh)
-			auth.POST("/admin/mfa/verify", adminAuthHandler.AdminMFAVerify)
+			// for the admin panel comes from ADMIN_IP_ALLOWLIST (or an
+			// allowlist at the reverse proxy), required MFA on every admin
+			// (ADMIN_REQUIRE_MFA) and account lockout after N failed
+			// attempts in auth_service.
+			auth.POST("/admin/login", adminIPAllowlist, adminAuthHandler.AdminLogin)
+			auth.POST("/admin/refresh", adminIPAllowlist, adminAuthHandler.AdminRefresh)
+			auth.POST("/admin/mfa/verify", adminIPAllowlist, adminAuthHandler.AdminMFAVerify)
 			auth.POST("/admin/logout", authMiddleware.RequireAuth(), middleware.CSRF(), adminAuthHandler.AdminLogout)
 
 			// Protected auth routes (require authentication)
@@ -591,7 +1003,7 @@ func main() {
 		// Profile routes
 		users := v1.Group("/users")
 		{
-			// /me/posts, /me/bookmarks, /me/events are registered above on v1
+			// /me/posts, /me/bookmarks, /me/bookmark-collections, /me/events are registered above on v1
 
 			// Protected routes (require authentication)
 			users.GET("/me", authMiddleware.RequireAuth(), profileHandler.GetMyProfile)
@@ -601,8 +1013,21 @@ func main() {
 			users.DELETE("/me/avatar", verifiedAuth, profileHandler.DeleteAvatar)
 			users.POST("/me/cover", verifiedAuth, profileHandler.UploadCover)
 			users.DELETE("/me/cover", verifiedAuth, profileHandler.DeleteCover)
+			users.GET("/me/storage", authMiddleware.RequireAuth(), profileHandler.GetStorageUsage)
+			users.DELETE("/me/storage/:object_id", verifiedAuth, profileHandler.DeleteStorageObject)
 			// GDPR Article 20: per-user data export. 1 / 24h. Requires verified email so unverified accounts can't exfiltrate data.
 			users.GET("/me/export", verifiedAuth, rateLimiter.LimitDataExport(), profileHandler.ExportData)
+			users.GET("/me/privacy", authMiddleware.RequireAuth(), profileHandler.GetPrivacySettings)
+			users.GET("/me/profile-views", authMiddleware.RequireAuth(), profileHandler.GetMyProfileViews)
+			users.PUT("/me/privacy", verifiedAuth, profileHandler.UpdatePrivacySettings)
+			users.GET("/me/content-languages", authMiddleware.RequireAuth(), profileHandler.GetContentLanguages)
+			users.GET("/me/invite", authMiddleware.RequireAuth(), inviteHandler.GetMyInvite)
+			users.PUT("/me/content-languages", authMiddleware.RequireAuth(), profileHandler.UpdateContentLanguages)
+			users.PUT("/me/handle", verifiedAuth, profileHandler.UpdateHandle)
+			users.GET("/me/muted-terms", authMiddleware.RequireAuth(), mutedTermHandler.ListMutedTerms)
+			users.POST("/me/muted-terms", authMiddleware.RequireAuth(), mutedTermHandler.AddMutedTerm)
+			users.DELETE("/me/muted-terms/:term_id", authMiddleware.RequireAuth(), mutedTermHandler.DeleteMutedTerm)
+			users.GET("/by-handle/:handle", authMiddleware.OptionalAuth(), publicReadRL, profileHandler.GetProfileByHandle)
 
 			// Require auth for user profile and relationship views
 			users.GET("/:user_id", authMiddleware.OptionalAuth(), publicReadRL, profileHandler.GetUserProfile)
@@ -618,10 +1043,20 @@ func main() {
 			users.POST("/:user_id/block", verifiedAuth, relationshipsHandler.BlockUser)
 			users.DELETE("/:user_id/block", verifiedAuth, relationshipsHandler.UnblockUser)
 			users.GET("/blocked", authMiddleware.RequireAuth(), relationshipsHandler.GetBlockedUsers)
+			users.GET("/blocked/export", authMiddleware.RequireAuth(), relationshipsHandler.ExportBlockList)
+			users.POST("/blocked/bulk", verifiedAuth, relationshipsHandler.BulkBlockUsers)
 			users.GET("/:user_id/relationship", authMiddleware.RequireAuth(), relationshipsHandler.GetRelationshipStatus)
 
 			// User reporting (require authentication + rate limiting)
 			users.POST("/:user_id/report", verifiedAuth, rateLimiter.LimitReports(), reportHandler.ReportUser)
+
+			// Endorsements — public list shows approved ones; owners approve
+			// or reject from their own profile (GET /me/endorsements?status=PENDING).
+			users.GET("/:user_id/endorsements", authMiddleware.OptionalAuth(), publicReadRL, endorsementHandler.ListEndorsements)
+			users.POST("/:user_id/endorsements", verifiedAuth, endorsementHandler.Endorse)
+			users.PUT("/me/endorsements/:endorsement_id", verifiedAuth, endorsementHandler.DecideEndorsement)
+			users.DELETE("/:user_id/endorsements/:endorsement_id", verifiedAuth, endorsementHandler.DeleteEndorsement)
+			users.POST("/:user_id/endorsements/:endorsement_id/report", verifiedAuth, rateLimiter.LimitReports(), endorsementHandler.ReportEndorsement)
 		}
 
 		// Post routes
@@ -630,11 +1065,14 @@ func main() {
 			// Feed + detail reads are public (guest browsing); engagement fields
 			// (liked_by_me etc.) are only populated when a token is present.
 			posts.GET("", authMiddleware.OptionalAuth(), publicReadRL, postHandler.GetFeed)
-			// /posts/feed must be registered before /:post_id to avoid the param route catching it
+			// /posts/feed and /posts/stream must be registered before /:post_id to avoid the param route catching them
+			posts.GET("/stream", authMiddleware.OptionalAuth(), feedStreamHandler.Stream)
 			posts.GET("/feed", authMiddleware.RequireAuth(), postHandler.GetPersonalizedFeed)
 			// Daily limit usage — must come before /:post_id for the same reason.
 			posts.GET("/daily-limits", authMiddleware.RequireAuth(), dailyLimitHandler.GetMyDailyLimits)
 			posts.GET("/:post_id", authMiddleware.OptionalAuth(), publicReadRL, postHandler.GetPost)
+			// Author-only "view as" preview of the post's visibility.
+			posts.GET("/:post_id/preview", authMiddleware.RequireAuth(), postHandler.PreviewPost)
 			// Users who liked a post (for the "liked by" sheet).
 			posts.GET("/:post_id/likes", authMiddleware.RequireAuth(), postHandler.GetPostLikes)
 			// Record a unique post view (feeds the total-views count).
@@ -643,7 +1081,9 @@ func main() {
 			// Protected routes (require verified email)
 			posts.POST("", verifiedAuth, rateLimiter.LimitPostsCreate(), postHandler.CreatePost)
 			posts.POST("/upload-image", verifiedAuth, rateLimiter.LimitPostsCreate(), postHandler.UploadPostImage)
+			posts.POST("/upload-sessions", verifiedAuth, postHandler.CreateUploadSession)
 			posts.PUT("/:post_id", verifiedAuth, postHandler.UpdatePost)
+			posts.PUT("/:post_id/attachments/order", verifiedAuth, postHandler.ReorderAttachments)
 			posts.DELETE("/:post_id", verifiedAuth, postHandler.DeletePost)
 
 			// Post interactions (require verified email)
@@ -652,8 +1092,16 @@ func main() {
 			posts.POST("/:post_id/bookmark", verifiedAuth, postHandler.BookmarkPost)
 			posts.DELETE("/:post_id/bookmark", verifiedAuth, postHandler.UnbookmarkPost)
 			posts.POST("/:post_id/share", verifiedAuth, postHandler.SharePost)
+			posts.POST("/:post_id/share/chat", verifiedAuth, chatHandler.SharePostToChats)
+			posts.POST("/:post_id/share/external", verifiedAuth, postHandler.ShareExternally)
 			posts.POST("/:post_id/resell", verifiedAuth, postHandler.ResellPost)
+			posts.POST("/:post_id/renew", verifiedAuth, postHandler.RenewPost)
+			posts.POST("/:post_id/relist", verifiedAuth, postHandler.RelistPost)
 			posts.POST("/:post_id/report", verifiedAuth, rateLimiter.LimitReports(), reportHandler.ReportPost)
+			// Geofenced broadcast of EVENT/ALERT posts (author; large ones
+			// wait for admin approval)
+			posts.POST("/:post_id/broadcast", verifiedAuth, postBroadcastHandler.RequestBroadcast)
+			posts.GET("/:post_id/broadcast", authMiddleware.RequireAuth(), postBroadcastHandler.GetBroadcast)
 
 			// Comment routes
 			posts.GET("/:post_id/comments", authMiddleware.OptionalAuth(), publicReadRL, commentHandler.GetPostComments)
@@ -662,6 +1110,13 @@ func main() {
 			// Poll routes
 			posts.GET("/:post_id/polls", authMiddleware.OptionalAuth(), publicReadRL, pollHandler.GetPostPoll)
 			posts.POST("/:post_id/polls", verifiedAuth, pollHandler.CreatePoll)
+
+			// Help-request pledge routes
+			posts.GET("/:post_id/pledges", authMiddleware.OptionalAuth(), publicReadRL, helpPledgeHandler.ListPledges)
+			posts.POST("/:post_id/pledges", verifiedAuth, helpPledgeHandler.Pledge)
+			posts.DELETE("/:post_id/pledges/me", verifiedAuth, helpPledgeHandler.WithdrawPledge)
+			posts.POST("/:post_id/pledges/:pledge_id/confirm", verifiedAuth, helpPledgeHandler.ConfirmPledge)
+			posts.POST("/:post_id/fulfill", verifiedAuth, helpPledgeHandler.MarkFulfilled)
 		}
 
 		// Comment routes
@@ -673,6 +1128,8 @@ func main() {
 			comments.GET("/:comment_id/replies", authMiddleware.RequireAuth(), commentHandler.GetCommentReplies)
 			comments.POST("/:comment_id/like", verifiedAuth, commentHandler.LikeComment)
 			comments.DELETE("/:comment_id/like", verifiedAuth, commentHandler.UnlikeComment)
+			comments.POST("/:comment_id/pin", verifiedAuth, commentHandler.PinComment)
+			comments.DELETE("/:comment_id/pin", verifiedAuth, commentHandler.UnpinComment)
 			comments.POST("/:comment_id/report", verifiedAuth, rateLimiter.LimitReports(), reportHandler.ReportComment)
 		}
 
@@ -681,6 +1138,7 @@ func main() {
 		{
 			polls.GET("/:poll_id", authMiddleware.RequireAuth(), pollHandler.GetPoll)
 			polls.POST("/:poll_id/vote", verifiedAuth, pollHandler.VotePoll)
+			polls.PUT("/:poll_id/vote", verifiedAuth, pollHandler.ChangeVote)
 			polls.DELETE("/:poll_id/vote", verifiedAuth, pollHandler.DeleteVote)
 		}
 
@@ -692,6 +1150,13 @@ func main() {
 			events.DELETE("/:post_id/interest", verifiedAuth, eventHandler.RemoveEventInterest)
 			events.GET("/:post_id/interested", authMiddleware.RequireAuth(), eventHandler.GetInterestedUsers)
 			events.GET("/:post_id/going", authMiddleware.RequireAuth(), eventHandler.GetGoingUsers)
+			events.GET("/:post_id/ics", authMiddleware.OptionalAuth(), publicReadRL, calendarHandler.EventICS)
+			events.GET("/:post_id/hosts", authMiddleware.OptionalAuth(), eventHostHandler.ListHosts)
+			events.POST("/:post_id/hosts", verifiedAuth, eventHostHandler.InviteHost)
+			events.POST("/:post_id/hosts/:host_id/accept", verifiedAuth, eventHostHandler.AcceptHostInvite)
+			events.POST("/:post_id/hosts/:host_id/decline", verifiedAuth, eventHostHandler.DeclineHostInvite)
+			events.DELETE("/:post_id/hosts/:host_id", verifiedAuth, eventHostHandler.RemoveHost)
+			events.POST("/:post_id/message", verifiedAuth, eventHostHandler.MessageAttendees)
 		}
 
 		// Business routes
@@ -699,28 +1164,49 @@ func main() {
 		{
 			// Static and more specific routes first (before /:business_id)
 			businesses.GET("/search", authMiddleware.OptionalAuth(), publicReadRL, businessHandler.ListBusinesses)
-			businesses.GET("/categories", authMiddleware.OptionalAuth(), businessHandler.GetCategories)
+			businesses.GET("/search/facets", authMiddleware.OptionalAuth(), publicReadRL, businessHandler.GetSearchFacets)
+			businesses.GET("/categories", authMiddleware.OptionalAuth(), middleware.ETag(), businessHandler.GetCategories)
+			businesses.GET("/bookings/me", authMiddleware.RequireAuth(), businessBookingHandler.ListMyBookings)
+			// Featured placements: trackers are public and deduped per
+			// client, like ads.
+			businesses.GET("/featured", authMiddleware.OptionalAuth(), publicReadRL, featuredBusinessHandler.GetFeatured)
+			businesses.POST("/featured/:placement_id/impression", rateLimiter.LimitByType("ad-tracking"), featuredBusinessHandler.RecordImpression)
+			businesses.POST("/featured/:placement_id/click", rateLimiter.LimitByType("ad-tracking"), featuredBusinessHandler.RecordClick)
+			businesses.GET("/:business_id/featured-placements", authMiddleware.RequireAuth(), featuredBusinessHandler.ListBusinessPlacements)
 			businesses.GET("/:business_id/hours", businessHandler.GetBusinessHours)
 			businesses.GET("/:business_id/attachments", authMiddleware.OptionalAuth(), publicReadRL, businessHandler.GetGallery)
 			businesses.GET("/:business_id/insights", authMiddleware.RequireAuth(), businessHandler.GetBusinessInsights)
+			businesses.GET("/:business_id/conversations", authMiddleware.RequireAuth(), chatHandler.GetBusinessConversations)
 
 			// Business verification (owner submits documents; requires verified email)
 			businesses.POST("/:business_id/verification", verifiedAuth, businessVerificationHandler.SubmitVerification)
 			businesses.GET("/:business_id/verification", authMiddleware.RequireAuth(), businessVerificationHandler.GetVerificationStatus)
 
-			businesses.GET("/:business_id", authMiddleware.OptionalAuth(), publicReadRL, businessHandler.GetBusiness)
+			// Claims on businesses nobody owns yet (proof upload; admins review)
+			businesses.POST("/:business_id/claim-requests", verifiedAuth, businessClaimHandler.SubmitClaim)
+			businesses.GET("/:business_id/claim-requests/me", authMiddleware.RequireAuth(), businessClaimHandler.GetMyClaim)
+
+			businesses.GET("/:business_id", authMiddleware.OptionalAuth(), publicReadRL, middleware.ETag(), businessHandler.GetBusiness)
 
 			// Protected routes (require verified email)
 			businesses.GET("", authMiddleware.RequireAuth(), businessHandler.GetMyBusinesses)
 			businesses.POST("", verifiedAuth, businessHandler.CreateBusiness)
+			businesses.POST("/claim", verifiedAuth, businessImportHandler.ClaimBusiness)
 			businesses.PUT("/:business_id", verifiedAuth, businessHandler.UpdateBusiness)
 			businesses.DELETE("/:business_id", verifiedAuth, businessHandler.DeleteBusiness)
 
 			// Business media (require verified email)
 			businesses.POST("/:business_id/avatar", verifiedAuth, businessHandler.UploadAvatar)
 			businesses.POST("/:business_id/cover", verifiedAuth, businessHandler.UploadCover)
+			businesses.PUT("/:business_id/cover/from-gallery", verifiedAuth, businessHandler.SetCoverFromGallery)
 			businesses.POST("/:business_id/attachments", verifiedAuth, businessHandler.AddGalleryImage)
+			businesses.PUT("/:business_id/attachments/order", verifiedAuth, businessHandler.ReorderGallery)
+			businesses.PATCH("/:business_id/attachments/:attachment_id", verifiedAuth, businessHandler.UpdateGalleryCaption)
 			businesses.DELETE("/:business_id/attachments/:attachment_id", verifiedAuth, businessHandler.DeleteGalleryImage)
+			businesses.GET("/:business_id/featured-posts", authMiddleware.OptionalAuth(), publicReadRL, businessHandler.GetFeaturedPosts)
+			businesses.POST("/:business_id/featured-posts", verifiedAuth, businessHandler.FeaturePost)
+			businesses.PUT("/:business_id/featured-posts/order", verifiedAuth, businessHandler.ReorderFeaturedPosts)
+			businesses.DELETE("/:business_id/featured-posts/:post_id", verifiedAuth, businessHandler.UnfeaturePost)
 
 			// Business hours (POST requires verified email)
 			businesses.POST("/:business_id/hours", verifiedAuth, businessHandler.SetBusinessHours)
@@ -729,6 +1215,13 @@ func main() {
 			businesses.POST("/:business_id/follow", verifiedAuth, businessHandler.FollowBusiness)
 			businesses.DELETE("/:business_id/follow", verifiedAuth, businessHandler.UnfollowBusiness)
 
+			// Owner follower list, province breakdown and fan-out setting
+			businesses.GET("/:business_id/followers", authMiddleware.RequireAuth(), businessHandler.ListFollowers)
+			businesses.GET("/:business_id/viewers", authMiddleware.RequireAuth(), businessHandler.ListViewers)
+			businesses.GET("/:business_id/followers/stats", authMiddleware.RequireAuth(), businessHandler.GetFollowerStats)
+			businesses.GET("/:business_id/followers/notifications", authMiddleware.RequireAuth(), businessHandler.GetFollowerNotificationSettings)
+			businesses.PUT("/:business_id/followers/notifications", verifiedAuth, businessHandler.UpdateFollowerNotificationSettings)
+
 			// Business reporting (require verified email + rate limiting)
 			businesses.POST("/:business_id/report", verifiedAuth, rateLimiter.LimitReports(), reportHandler.ReportBusiness)
 
@@ -740,15 +1233,68 @@ func main() {
 			businesses.POST("/:business_id/reviews", verifiedAuth, businessReviewHandler.SubmitReview)
 			businesses.PUT("/:business_id/reviews/:review_id", verifiedAuth, businessReviewHandler.UpdateReview)
 			businesses.DELETE("/:business_id/reviews/:review_id", verifiedAuth, businessReviewHandler.DeleteReview)
+
+			// Product catalog — public reads, owner-only writes.
+			businesses.GET("/:business_id/products", authMiddleware.OptionalAuth(), publicReadRL, businessProductHandler.ListProducts)
+			businesses.GET("/:business_id/products/:product_id", authMiddleware.OptionalAuth(), publicReadRL, businessProductHandler.GetProduct)
+			businesses.POST("/:business_id/products/photos", verifiedAuth, businessProductHandler.UploadProductPhoto)
+			businesses.POST("/:business_id/products", verifiedAuth, businessProductHandler.CreateProduct)
+			businesses.PUT("/:business_id/products/:product_id", verifiedAuth, businessProductHandler.UpdateProduct)
+			businesses.DELETE("/:business_id/products/:product_id", verifiedAuth, businessProductHandler.DeleteProduct)
+
+			// Branches — public list, owner-only writes.
+			businesses.GET("/:business_id/branches", authMiddleware.OptionalAuth(), publicReadRL, branchHandler.ListBranches)
+			businesses.POST("/:business_id/branches", verifiedAuth, branchHandler.CreateBranch)
+			businesses.PUT("/:business_id/branches/:branch_id", verifiedAuth, branchHandler.UpdateBranch)
+			businesses.DELETE("/:business_id/branches/:branch_id", verifiedAuth, branchHandler.DeleteBranch)
+
+			// Chat quick replies and the away message (owner only)
+			businesses.GET("/:business_id/quick-replies", authMiddleware.RequireAuth(), quickReplyHandler.ListQuickReplies)
+			businesses.POST("/:business_id/quick-replies", verifiedAuth, quickReplyHandler.CreateQuickReply)
+			businesses.PUT("/:business_id/quick-replies/:reply_id", verifiedAuth, quickReplyHandler.UpdateQuickReply)
+			businesses.DELETE("/:business_id/quick-replies/:reply_id", verifiedAuth, quickReplyHandler.DeleteQuickReply)
+			businesses.GET("/:business_id/auto-reply", authMiddleware.RequireAuth(), quickReplyHandler.GetAutoReply)
+			businesses.PUT("/:business_id/auto-reply", verifiedAuth, quickReplyHandler.SetAutoReply)
+
+			// Booking requests — customers request, owners accept/decline and
+			// read the calendar.
+			businesses.POST("/:business_id/bookings", verifiedAuth, businessBookingHandler.RequestBooking)
+			businesses.GET("/:business_id/bookings", authMiddleware.RequireAuth(), businessBookingHandler.ListBookings)
+			businesses.POST("/:business_id/bookings/:booking_id/accept", verifiedAuth, businessBookingHandler.AcceptBooking)
+			businesses.POST("/:business_id/bookings/:booking_id/decline", verifiedAuth, businessBookingHandler.DeclineBooking)
+			businesses.POST("/:business_id/bookings/:booking_id/cancel", verifiedAuth, businessBookingHandler.CancelBooking)
+		}
+
+		// Group routes — neighborhood and interest communities. Reads of
+		// public groups are open; private groups are checked in the service.
+		groups := v1.Group("/groups")
+		{
+			groups.GET("", authMiddleware.OptionalAuth(), publicReadRL, groupHandler.ListGroups)
+			groups.POST("", verifiedAuth, groupHandler.CreateGroup)
+			groups.GET("/me", authMiddleware.RequireAuth(), groupHandler.ListMyGroups)
+			groups.GET("/:group_id", authMiddleware.OptionalAuth(), publicReadRL, groupHandler.GetGroup)
+			groups.PUT("/:group_id", verifiedAuth, groupHandler.UpdateGroup)
+			groups.DELETE("/:group_id", verifiedAuth, groupHandler.DeleteGroup)
+			groups.POST("/:group_id/join", verifiedAuth, groupHandler.JoinGroup)
+			groups.POST("/:group_id/leave", authMiddleware.RequireAuth(), groupHandler.LeaveGroup)
+			groups.GET("/:group_id/members", authMiddleware.OptionalAuth(), publicReadRL, groupHandler.ListMembers)
+			groups.POST("/:group_id/members/:user_id/approve", verifiedAuth, groupHandler.ApproveMember)
+			groups.PUT("/:group_id/members/:user_id/role", verifiedAuth, groupHandler.UpdateMemberRole)
+			groups.DELETE("/:group_id/members/:user_id", verifiedAuth, groupHandler.RemoveMember)
+			groups.GET("/:group_id/posts", authMiddleware.OptionalAuth(), publicReadRL, groupHandler.GetGroupFeed)
+			groups.DELETE("/:group_id/posts/:post_id", verifiedAuth, groupHandler.RemoveGroupPost)
 		}
 
 		// Category routes (marketplace categories)
 		categories := v1.Group("/categories")
 		{
-			categories.GET("", authMiddleware.OptionalAuth(), categoryHandler.ListCategories)
+			categories.GET("", authMiddleware.OptionalAuth(), middleware.ETag(), categoryHandler.ListCategories)
 			categories.GET("/:category_id", authMiddleware.RequireAuth(), categoryHandler.GetCategory)
 		}
 
+		// Location reference data (province / district / neighborhood pickers)
+		v1.GET("/locations", publicReadRL, middleware.ETag(), locationHandler.ListLocations)
+
 		// Chat routes — WS uses plain auth (only needs to receive frames, no
 		// email verification required). Send/write endpoints use verifiedAuth.
 		chat := v1.Group("/chat")
@@ -760,11 +1306,15 @@ func main() {
 			// HTTP endpoints — write operations still require verified email
 			chat.POST("/messages", verifiedAuth, rateLimiter.LimitChatSend(), chatHandler.SendMessage)
 			chat.GET("/conversations", authMiddleware.RequireAuth(), chatHandler.GetConversations)
+			chat.GET("/badge", authMiddleware.RequireAuth(), chatHandler.GetUnreadBadge)
+			chat.GET("/stickers", authMiddleware.RequireAuth(), stickerHandler.GetCatalog)
 			chat.GET("/conversations/:conversation_id/messages", authMiddleware.RequireAuth(), chatHandler.GetMessages)
 			chat.POST("/conversations/:conversation_id/read", authMiddleware.RequireAuth(), chatHandler.MarkConversationAsRead)
+			chat.PUT("/conversations/:conversation_id/disappearing", verifiedAuth, chatHandler.SetDisappearingMessages)
 			chat.PUT("/messages/:message_id", verifiedAuth, chatHandler.EditMessage)
 			chat.DELETE("/messages/:message_id", verifiedAuth, chatHandler.DeleteMessage)
 			chat.POST("/messages/:message_id/delete-for-me", verifiedAuth, chatHandler.DeleteMessageForMe)
+			chat.POST("/messages/:message_id/report", verifiedAuth, rateLimiter.LimitReports(), reportHandler.ReportMessage)
 			chat.POST("/messages/:message_id/react", verifiedAuth, chatHandler.ReactToMessage)
 			chat.DELETE("/messages/:message_id/react", verifiedAuth, chatHandler.UnreactToMessage)
 		}
@@ -777,6 +1327,7 @@ func main() {
 			notifications.GET("/unread-count", authMiddleware.RequireAuth(), notificationHandler.GetUnreadCount)
 			notifications.POST("/:notification_id/read", verifiedAuth, notificationHandler.MarkAsRead)
 			notifications.POST("/read-all", verifiedAuth, notificationHandler.MarkAllAsRead)
+			notifications.DELETE("", verifiedAuth, notificationHandler.ClearNotifications)
 			notifications.DELETE("/:notification_id", verifiedAuth, notificationHandler.DeleteNotification)
 
 			// Notification settings (read: auth; update: verified email)
@@ -790,6 +1341,10 @@ func main() {
 			// APNs token registration (iOS direct-to-Apple path; works where Google is blocked)
 			notifications.POST("/apns-token", authMiddleware.RequireAuth(), notificationHandler.RegisterAPNsToken)
 			notifications.DELETE("/apns-token", authMiddleware.RequireAuth(), notificationHandler.UnregisterAPNsToken)
+
+			// Registered push devices (list + revoke)
+			notifications.GET("/devices", authMiddleware.RequireAuth(), notificationHandler.ListDevices)
+			notifications.DELETE("/devices/:device_id", authMiddleware.RequireAuth(), notificationHandler.RevokeDevice)
 		}
 
 		// Search and discovery routes — public reads for guest browsing, but
@@ -800,7 +1355,11 @@ func main() {
 		v1.GET("/search/posts", authMiddleware.OptionalAuth(), searchRL, searchHandler.SearchPosts)
 		v1.GET("/search/users", authMiddleware.RequireAuth(), searchRL, searchHandler.SearchUsers)
 		v1.GET("/search/businesses", authMiddleware.OptionalAuth(), searchRL, searchHandler.SearchBusinesses)
+		v1.GET("/search/suggest", authMiddleware.OptionalAuth(), rateLimiter.LimitByType("search-suggest"), searchHandler.Suggest)
+		v1.GET("/search/recent", authMiddleware.RequireAuth(), searchHandler.ListRecentSearches)
+		v1.DELETE("/search/recent", authMiddleware.RequireAuth(), searchHandler.ClearRecentSearches)
 		v1.GET("/discover", authMiddleware.OptionalAuth(), searchRL, searchHandler.Discover)
+		v1.GET("/discover/trending", authMiddleware.OptionalAuth(), searchRL, trendingHandler.RegionalTrending)
 
 		// Feedback routes (require verified email to submit)
 		feedback := v1.Group("/feedback")
@@ -827,7 +1386,7 @@ func main() {
 		superOnly := authMiddleware.RequireSuperAdmin()
 
 		admin := v1.Group("/admin")
-		admin.Use(authMiddleware.RequireAdmin())
+		admin.Use(adminIPAllowlist, authMiddleware.RequireAdmin())
 		{
 			// Dashboard & Analytics — admin-tier (mods don't see analytics).
 			admin.GET("/stats", adminOnly, adminHandler.GetDashboardStats)
@@ -838,6 +1397,12 @@ func main() {
 			admin.GET("/analytics/businesses", adminOnly, adminHandler.GetBusinessAnalytics)
 			admin.GET("/revenue", adminOnly, adminHandler.GetRevenueSummary)
 			admin.GET("/top-content", adminHandler.GetTopContent)
+			admin.GET("/referrals/top", adminOnly, inviteHandler.AdminTopReferrers)
+			admin.GET("/sticker-packs", adminOnly, stickerHandler.AdminListPacks)
+			admin.POST("/sticker-packs", adminOnly, stickerHandler.AdminCreatePack)
+			admin.PUT("/sticker-packs/:pack_id", adminOnly, stickerHandler.AdminSetPackActive)
+			admin.DELETE("/sticker-packs/:pack_id", adminOnly, stickerHandler.AdminDeletePack)
+			admin.GET("/search", searchHandler.AdminSearch)
 
 			// User Management — read for all admins; suspend/unsuspend admin-only;
 			// delete admin-only; role change super_admin-only.
@@ -852,6 +1417,9 @@ func main() {
 			admin.POST("/users/:user_id/logout-all", adminOnly, adminHandler.ForceLogoutUser)
 			admin.GET("/users/:user_id/sessions", adminOnly, adminHandler.UserSessionsList)
 			admin.POST("/users/:user_id/shadowban", adminOnly, adminHandler.SetUserShadowban)
+			admin.DELETE("/users/:user_id/email-suppression", adminOnly, adminHandler.ClearEmailSuppression)
+			admin.DELETE("/users/:user_id/login-lock", adminOnly, adminHandler.ClearLoginLock)
+			admin.GET("/security/locked-accounts", adminOnly, adminHandler.ListLockedAccounts)
 			admin.PATCH("/users/:user_id/verification", adminOnly, adminHandler.SetUserVerification)
 			admin.GET("/rate-limit-overrides", adminOnly, adminHandler.RateLimitOverridesList)
 			admin.PUT("/users/:user_id/rate-limit", adminOnly, adminHandler.SetRateLimitOverride)
@@ -880,7 +1448,13 @@ func main() {
 			admin.GET("/reports/users/:report_id", adminHandler.GetUserReport)
 			admin.GET("/reports/businesses", adminHandler.ListBusinessReports)
 			admin.GET("/reports/businesses/:report_id", adminHandler.GetBusinessReport)
+			admin.GET("/reports/messages", adminHandler.ListMessageReports)
+			admin.GET("/reports/messages/:report_id", adminHandler.GetMessageReport)
 			admin.PUT("/reports/:report_type/:report_id/status", adminHandler.UpdateReportStatus)
+			admin.GET("/reports/grouped/:report_type", adminHandler.ListReportGroups)
+			admin.PUT("/reports/grouped/:report_type/:target_id/status", adminHandler.ResolveReportGroup)
+			admin.GET("/endorsements/reported", endorsementHandler.AdminListReported)
+			admin.DELETE("/endorsements/:endorsement_id", endorsementHandler.AdminDeleteEndorsement)
 
 			// Feedback — list for all admins; resolve admin-only.
 			admin.GET("/feedback", adminHandler.ListFeedback)
@@ -888,28 +1462,55 @@ func main() {
 
 			// Business Management — read+approve for all admins; create+delete admin-only.
 			admin.POST("/businesses", adminOnly, businessHandler.CreateBusinessForOwner)
+			admin.POST("/businesses/import", adminOnly, businessImportHandler.ImportBusinesses)
+			admin.GET("/businesses/imports", adminOnly, businessImportHandler.ListImports)
+			admin.GET("/businesses/imports/:job_id", adminOnly, businessImportHandler.GetImport)
 			admin.GET("/businesses", adminHandler.ListAllBusinesses)
 			admin.GET("/businesses/:business_id", adminHandler.GetBusinessDetail)
 			admin.PUT("/businesses/:business_id/status", adminHandler.UpdateBusinessStatus)
+			admin.PUT("/businesses/:business_id/categories", adminHandler.SetBusinessCategories)
 			admin.DELETE("/businesses/:business_id", adminOnly, adminHandler.DeleteBusiness)
+			admin.GET("/businesses/:business_id/ownership-history", adminOnly, businessClaimHandler.GetOwnershipHistory)
 
 			// Business verification review queue — admin-only (grants a
 			// public trust mark; moderators don't review these).
 			admin.GET("/business-verifications", adminOnly, businessVerificationHandler.ListVerifications)
 			admin.PATCH("/business-verifications/:request_id", adminOnly, businessVerificationHandler.ReviewVerification)
 
+			// Business claim review queue — admin-only (approval transfers
+			// ownership of the business).
+			admin.GET("/business-claims", adminOnly, businessClaimHandler.ListClaims)
+			admin.PATCH("/business-claims/:claim_id", adminOnly, businessClaimHandler.ReviewClaim)
+
+			// Post broadcasts — admin-only (pushes reach strangers by
+			// location): the approval queue plus direct admin broadcasts.
+			admin.POST("/posts/:post_id/broadcast", adminOnly, postBroadcastHandler.AdminRequestBroadcast)
+			admin.GET("/broadcasts", adminOnly, postBroadcastHandler.ListBroadcasts)
+			admin.PATCH("/broadcasts/:broadcast_id", adminOnly, postBroadcastHandler.ReviewBroadcast)
+
 			// Categories — admin-only (platform config).
 			admin.GET("/categories", adminOnly, categoryHandler.GetAllCategories)
 			admin.POST("/categories", adminOnly, categoryHandler.CreateCategory)
 			admin.PUT("/categories/:category_id", adminOnly, categoryHandler.UpdateCategory)
 			admin.DELETE("/categories/:category_id", adminOnly, categoryHandler.DeleteCategory)
 
+			// Location reference data
+			admin.GET("/locations", adminOnly, locationHandler.AdminListLocations)
+			admin.POST("/locations", adminOnly, locationHandler.CreateLocation)
+			admin.PUT("/locations/:location_id", adminOnly, locationHandler.UpdateLocation)
+			admin.DELETE("/locations/:location_id", adminOnly, locationHandler.DeleteLocation)
+
 			// Push Notifications — broadcast admin-only; targeted super_admin-only
 			// (named-user push has higher abuse potential than mass broadcast).
 			admin.POST("/notifications/broadcast", adminOnly, adminHandler.BroadcastNotification)
 			admin.POST("/notifications/send", superOnly, adminHandler.SendTargetedNotification)
 			admin.GET("/notifications/history", adminOnly, adminHandler.ListBroadcastHistory)
 
+			// Lifecycle messages — welcome, first-post nudge, re-engagement.
+			admin.GET("/lifecycle-templates", adminOnly, lifecycleTemplateHandler.List)
+			admin.PUT("/lifecycle-templates/:kind", adminOnly, lifecycleTemplateHandler.Update)
+			admin.DELETE("/lifecycle-templates/:kind", adminOnly, lifecycleTemplateHandler.Reset)
+
 			// Audit Logs — admin-and-above. Mods don't audit other admins.
 			admin.GET("/audit-logs", adminOnly, adminHandler.ListAuditLogs)
 
@@ -968,6 +1569,11 @@ func main() {
 			admin.GET("/boosts", adminOnly, monetizationHandler.ListBoosts)
 			admin.PUT("/boosts/:boost_id/cancel", adminOnly, monetizationHandler.CancelBoost)
 
+			admin.GET("/featured-businesses", adminOnly, featuredBusinessHandler.AdminList)
+			admin.POST("/featured-businesses", adminOnly, featuredBusinessHandler.AdminCreate)
+			admin.PUT("/featured-businesses/:placement_id", adminOnly, featuredBusinessHandler.AdminUpdate)
+			admin.DELETE("/featured-businesses/:placement_id", adminOnly, featuredBusinessHandler.AdminDelete)
+
 			// /admin/system/* — super_admin exclusive platform telemetry +
 			// feature-flag controls. RequireSuperAdmin replaces (not stacks
 			// with) the group middleware here, but Gin runs both — moderator
@@ -980,7 +1586,13 @@ func main() {
 			admin.POST("/system/sessions/:session_id/revoke", superOnly, systemHandler.SessionRevoke)
 			admin.GET("/system/flags", superOnly, systemHandler.FlagsList)
 			admin.PUT("/system/flags/:key", superOnly, systemHandler.FlagsToggle)
+			admin.GET("/system/settings", superOnly, systemHandler.SettingsList)
+			admin.PUT("/system/settings/:key", superOnly, systemHandler.SettingsUpdate)
+			admin.DELETE("/system/settings/:key", superOnly, systemHandler.SettingsReset)
 			admin.GET("/system/denylist-stats", superOnly, systemHandler.DenylistStats)
+			admin.GET("/system/storage-reconcile", superOnly, systemHandler.StorageReconcile)
+			admin.GET("/system/mode", superOnly, systemHandler.ModeGet)
+			admin.PUT("/system/mode", superOnly, systemHandler.ModeUpdate)
 
 			// Database backups (super_admin only — read history, trigger
 			// ad-hoc, presigned download). Restore is intentionally NOT a
@@ -996,12 +1608,21 @@ func main() {
 			admin.GET("/media-moderation", adminOnly, mediaModerationHandler.List)
 			admin.POST("/media-moderation/:attachment_id/approve", adminOnly, mediaModerationHandler.Approve)
 			admin.POST("/media-moderation/:attachment_id/reject", adminOnly, mediaModerationHandler.Reject)
+			admin.GET("/media-moderation/businesses", adminOnly, mediaModerationHandler.ListBusiness)
+			admin.POST("/media-moderation/businesses/:media_id/approve", adminOnly, mediaModerationHandler.ApproveBusiness)
+			admin.POST("/media-moderation/businesses/:media_id/reject", adminOnly, mediaModerationHandler.RejectBusiness)
+			admin.GET("/media-moderation/profiles", adminOnly, mediaModerationHandler.ListProfiles)
+			admin.POST("/media-moderation/profiles/:review_id/approve", adminOnly, mediaModerationHandler.ApproveProfile)
+			admin.POST("/media-moderation/profiles/:review_id/reject", adminOnly, mediaModerationHandler.RejectProfile)
 
 			// GDPR / DSAR account deletion request queue.
 			admin.GET("/deletion-requests", adminOnly, deletionRequestHandler.List)
 			admin.POST("/deletion-requests", adminOnly, deletionRequestHandler.Create)
 			admin.POST("/deletion-requests/:id/approve", adminOnly, deletionRequestHandler.Approve)
 			admin.POST("/deletion-requests/:id/reject", adminOnly, deletionRequestHandler.Reject)
+			admin.GET("/account-recoveries", adminOnly, accountRecoveryHandler.List)
+			admin.POST("/account-recoveries/:id/complete", adminOnly, accountRecoveryHandler.Complete)
+			admin.POST("/account-recoveries/:id/reject", adminOnly, accountRecoveryHandler.Reject)
 
 			admin.GET("/system/backups", superOnly, backupHandler.List)
 			admin.POST("/system/backups/run", superOnly, backupHandler.Run)
@@ -1061,62 +1682,42 @@ func main() {
 	// Boost expiry sweeper — flips ACTIVE boosts past their end_at to
 	// EXPIRED every 15 minutes so the admin panel and public queries stay
 	// accurate without relying on lazy filtering alone.
-	go func() {
-		ticker := time.NewTicker(15 * time.Minute)
-		defer ticker.Stop()
-		consecutiveFailures := 0
-		for range ticker.C {
-			// Per-tick timeout so a hung query never blocks subsequent runs.
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-			res, err := db.Pool.Exec(ctx, `
-				UPDATE boosts SET status = 'EXPIRED'
-				WHERE status = 'ACTIVE' AND expires_at < NOW()
-			`)
-			cancel()
-			if err != nil {
-				consecutiveFailures++
-				if consecutiveFailures >= 3 {
-					sugaredLogger.Errorw("boost expiry sweep failing repeatedly",
-						"failures", consecutiveFailures, "error", err)
-				} else {
-					sugaredLogger.Warnw("boost expiry sweep failed", "error", err)
-				}
-				continue
-			}
-			consecutiveFailures = 0
-			if res.RowsAffected() > 0 {
-				sugaredLogger.Infow("boost expiry sweep", "expired", res.RowsAffected())
+	boostSweepFailures := 0
+	lc.Every("boost-expiry", 15*time.Minute, false, func(ctx context.Context) {
+		// Per-tick timeout so a hung query never blocks subsequent runs.
+		ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		res, err := db.Pool.Exec(ctx, `
+			UPDATE boosts SET status = 'EXPIRED'
+			WHERE status = 'ACTIVE' AND expires_at < NOW()
+		`)
+		cancel()
+		if err != nil {
+			boostSweepFailures++
+			if boostSweepFailures >= 3 {
+				sugaredLogger.Errorw("boost expiry sweep failing repeatedly",
+					"failures", boostSweepFailures, "error", err)
+			} else {
+				sugaredLogger.Warnw("boost expiry sweep failed", "error", err)
 			}
+			return
 		}
-	}()
-
-	// Start server in a goroutine
-	go func() {
-		sugaredLogger.Infow("Starting HTTP server", "address", addr)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			sugaredLogger.Fatalw("Failed to start server", "error", err)
+		boostSweepFailures = 0
+		if res.RowsAffected() > 0 {
+			sugaredLogger.Infow("boost expiry sweep", "expired", res.RowsAffected())
 		}
-	}()
-
-	sugaredLogger.Infow("Server started successfully",
-		"address", addr,
-		"env", cfg.Server.Env,
-	)
-
-	// Wait for interrupt signal to gracefully shut down the server
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	})
 
 	// runIfLeader executes fn only when this instance holds the named Redis lock.
 	// Lock TTL is shorter than the job interval, so a crashed leader's lock expires
-	// before the next tick and another instance can take over.
-	runIfLeader := func(jobName, lockKey string, lockTTL time.Duration, fn func(context.Context) error) {
-		runCtx, cancel := context.WithTimeout(context.Background(), lockTTL)
+	// before the next tick and another instance can take over. A run in progress
+	// at shutdown sees its context cancelled.
+	runIfLeader := func(ctx context.Context, jobName, lockKey string, lockTTL time.Duration, fn func(context.Context) error) {
+		runCtx, cancel := context.WithTimeout(ctx, lockTTL)
 		defer cancel()
 		lock, err := redislock.Acquire(runCtx, redisClient, lockKey, lockTTL)
 		if err != nil {
-			if errors.Is(err, redislock.ErrNotAcquired) {
-				return // another instance is running the job
+			if errors.Is(err, redislock.ErrNotAcquired) || ctx.Err() != nil {
+				return // another instance is running the job, or shutting down
 			}
 			sugaredLogger.Warnw("Background job lock error", "job", jobName, "error", err)
 			return
@@ -1131,97 +1732,144 @@ func main() {
 		}
 	}
 
+	// leaderJob schedules fn every interval under the lifecycle, leader-elected
+	// via the lock:job:<name> Redis lock. runNow also runs it at startup.
+	leaderJob := func(jobName string, interval, lockTTL time.Duration, runNow bool, fn func(context.Context) error) {
+		lc.Every(jobName, interval, runNow, func(ctx context.Context) {
+			runIfLeader(ctx, jobName, "lock:job:"+jobName, lockTTL, fn)
+		})
+	}
+
 	// Background job: expire unsold SELL posts and notify owners (runs every hour).
 	// Leader-elected via Redis lock so only one instance executes per tick.
-	go func() {
-		ticker := time.NewTicker(1 * time.Hour)
-		defer ticker.Stop()
-
-		expireSellPosts := func(ctx context.Context) error {
-			count, err := postService.ProcessExpiredSellPosts(ctx)
-			if err != nil {
-				return err
-			}
-			if count > 0 {
-				sugaredLogger.Infow("Sell expiry job completed", "expired_count", count)
-			}
-			return nil
+	leaderJob("sell-expiry", 1*time.Hour, 30*time.Minute, true, func(ctx context.Context) error {
+		count, err := postService.ProcessExpiredSellPosts(ctx)
+		if err != nil {
+			return err
 		}
+		if count > 0 {
+			sugaredLogger.Infow("Sell expiry job completed", "expired_count", count)
+		}
+		return nil
+	})
 
-		runIfLeader("sell-expiry", "lock:job:sell-expiry", 30*time.Minute, expireSellPosts)
-
-		for {
-			select {
-			case <-ticker.C:
-				runIfLeader("sell-expiry", "lock:job:sell-expiry", 30*time.Minute, expireSellPosts)
-			case <-quit:
-				return
-			}
+	// Background job: archive EVENT and ALERT posts past their expiry (runs
+	// every 15 minutes, leader-elected). Expiry times are set at creation
+	// from the admin-tunable post_expiry.* settings.
+	leaderJob("post-archive", 15*time.Minute, 10*time.Minute, true, func(ctx context.Context) error {
+		count, err := postService.ArchiveExpiredPosts(ctx)
+		if err != nil {
+			return err
 		}
-	}()
+		if count > 0 {
+			sugaredLogger.Infow("Post archive job completed", "archived_count", count)
+		}
+		return nil
+	})
 
 	// Background job: proactive re-engagement pushes (event reminders, dormant
 	// win-back, sell expiring-soon). Runs hourly, leader-elected so only one
 	// instance sends per tick. Idempotent + deduped against the notifications
 	// table; quiet hours + per-user frequency cap apply in the push path.
-	go func() {
-		ticker := time.NewTicker(1 * time.Hour)
-		defer ticker.Stop()
-
-		runIfLeader("engagement", "lock:job:engagement", 30*time.Minute, engagementService.RunHourly)
-
-		for {
-			select {
-			case <-ticker.C:
-				runIfLeader("engagement", "lock:job:engagement", 30*time.Minute, engagementService.RunHourly)
-			case <-quit:
-				return
-			}
+	leaderJob("engagement", 1*time.Hour, 30*time.Minute, true, engagementService.RunHourly)
+
+	// Background job: reconcile the Redis unread counters against the
+	// messages table (runs hourly, leader-elected). Fixes drift from
+	// deletes and from messages sent while a user's counters were seeding.
+	leaderJob("unread-reconcile", 1*time.Hour, 30*time.Minute, false, unreadCounters.ReconcileAll)
+
+	// Background job: run queued images through the NSFW classifier (runs
+	// every minute, leader-elected). Only when a classifier is configured.
+	if mediaScanner != nil {
+		leaderJob("media-scan", 1*time.Minute, 10*time.Minute, false, mediaScanner.ProcessPending)
+	}
+
+	// Background job: delete post uploads no post claimed within a day
+	// (runs hourly, leader-elected).
+	leaderJob("upload-cleanup", 1*time.Hour, 30*time.Minute, false, uploadSessionService.CleanupStale)
+
+	// Background job: drop durable revocations of access tokens that have
+	// expired anyway (runs hourly, leader-elected).
+	leaderJob("token-blacklist-cleanup", 1*time.Hour, 30*time.Minute, false, func(ctx context.Context) error {
+		count, err := tokenStorage.PurgeExpiredRevocations(ctx)
+		if err != nil {
+			return err
 		}
-	}()
+		if count > 0 {
+			sugaredLogger.Infow("Token blacklist cleanup completed", "deleted_count", count)
+		}
+		return nil
+	})
 
-	// Background job: purge expired and revoked sessions (runs every 24 hours).
-	go func() {
-		ticker := time.NewTicker(24 * time.Hour)
-		defer ticker.Stop()
+	// Background job: hard-delete disappearing messages past their expiry
+	// and their attachments (runs every 5 minutes, leader-elected).
+	leaderJob("disappearing-messages", 5*time.Minute, 4*time.Minute, false, chatService.PurgeExpiredMessages)
 
-		purgeSessions := func(ctx context.Context) error {
-			count, err := userRepo.DeleteExpiredSessions(ctx)
-			if err != nil {
-				return err
-			}
-			if count > 0 {
-				sugaredLogger.Infow("Session cleanup completed", "deleted_count", count)
-			}
-			return nil
+	// Background job: purge expired and revoked sessions (runs every 24 hours).
+	leaderJob("session-cleanup", 24*time.Hour, 12*time.Hour, true, func(ctx context.Context) error {
+		count, err := userRepo.DeleteExpiredSessions(ctx)
+		if err != nil {
+			return err
 		}
+		if count > 0 {
+			sugaredLogger.Infow("Session cleanup completed", "deleted_count", count)
+		}
+		return nil
+	})
 
-		runIfLeader("session-cleanup", "lock:job:session-cleanup", 12*time.Hour, purgeSessions)
+	// Background job: send queued post broadcasts in batches (runs every
+	// minute, leader-elected). Each run is budgeted and resumes from the
+	// saved cursor, so large broadcasts spread over several runs.
+	leaderJob("post-broadcasts", 1*time.Minute, 10*time.Minute, false, postBroadcastService.ProcessQueued)
+
+	// Background job: send the batched COMMENT notifications of busy posts
+	// whose digest interval has passed (runs every minute, leader-elected).
+	leaderJob("comment-digests", 1*time.Minute, 5*time.Minute, false, notificationService.FlushCommentDigests)
+
+	// Background job: work through queued business CSV imports a few
+	// hundred rows at a time (runs every minute, leader-elected).
+	leaderJob("business-imports", 1*time.Minute, 10*time.Minute, false, businessImportService.ProcessQueued)
+
+	// Background job: recompute the province / district trending rollup and,
+	// on the digest weekday, send the opt-in weekly digest (runs hourly,
+	// leader-elected). Also runs at startup so the endpoint has data.
+	leaderJob("regional-trending", 1*time.Hour, 30*time.Minute, true, regionalTrendingService.RunRollup)
+
+	// Background job: recompute each business's median chat response time
+	// over the last 30 days (runs every 24 hours, leader-elected). Also runs
+	// at startup so business pages have data.
+	leaderJob("business-response-stats", 24*time.Hour, 1*time.Hour, true, responsivenessService.RunRollup)
+
+	// Background job: drop business viewer rows older than 90 days (runs
+	// every 24 hours, leader-elected).
+	leaderJob("business-viewer-retention", 24*time.Hour, 1*time.Hour, false, profileViewService.PruneBusinessViewers)
+
+	// Background job: delete stored media no row references any more once
+	// past the grace period, and record what was reclaimed (runs every 24
+	// hours, leader-elected). Only when real storage is configured.
+	if storageReconcileService != nil {
+		leaderJob("storage-reconcile", 24*time.Hour, 3*time.Hour, false, storageReconcileService.Run)
+	}
 
-		for {
-			select {
-			case <-ticker.C:
-				runIfLeader("session-cleanup", "lock:job:session-cleanup", 12*time.Hour, purgeSessions)
-			case <-quit:
-				return
-			}
+	// Background job: drop delta-sync tombstones past the sync window (runs
+	// every 24 hours, leader-elected).
+	leaderJob("sync-tombstone-cleanup", 24*time.Hour, 1*time.Hour, false, func(ctx context.Context) error {
+		count, err := syncTombstoneRepo.PurgeOlderThan(ctx, time.Now().Add(-models.DeltaSyncWindow))
+		if err != nil {
+			return err
 		}
-	}()
+		if count > 0 {
+			sugaredLogger.Infow("Sync tombstone cleanup completed", "deleted_count", count)
+		}
+		return nil
+	})
 
 	// Background job: encrypted database backup + GFS retention prune
 	// (runs every 24 hours, leader-elected). Uses pg_dump piped through
 	// gpg before anything lands on disk; artifacts go to a local volume
 	// AND a separate MinIO bucket. Restore is operator-only.
-	go func() {
-		if !cfg.Backup.Enabled || cfg.Backup.Passphrase == "" {
-			sugaredLogger.Warn("Backup job not started — set BACKUP_ENABLED=true and BACKUP_PASSPHRASE")
-			return
-		}
-
-		ticker := time.NewTicker(24 * time.Hour)
-		defer ticker.Stop()
-
-		runBackup := func(ctx context.Context) error {
+	if cfg.Backup.Enabled && cfg.Backup.Passphrase != "" {
+		leaderJob("db-backup", 24*time.Hour, 1*time.Hour, true, func(ctx context.Context) error {
 			if _, err := backupService.Run(ctx, "cron", nil); err != nil {
 				return err
 			}
@@ -1229,94 +1877,104 @@ func main() {
 				return fmt.Errorf("prune: %w", err)
 			}
 			return nil
-		}
-
-		runIfLeader("db-backup", "lock:job:db-backup", 1*time.Hour, runBackup)
+		})
+	} else {
+		sugaredLogger.Warn("Backup job not started — set BACKUP_ENABLED=true and BACKUP_PASSPHRASE")
+	}
 
-		for {
-			select {
-			case <-ticker.C:
-				runIfLeader("db-backup", "lock:job:db-backup", 1*time.Hour, runBackup)
-			case <-quit:
-				return
+	// Background job: notification inbox retention (runs every 24 hours).
+	// Drops notifications older than NOTIFICATION_RETENTION_DAYS for every
+	// user; 0 keeps them forever.
+	if cfg.Notification.RetentionDays > 0 {
+		leaderJob("notification-retention", 24*time.Hour, 1*time.Hour, true, func(ctx context.Context) error {
+			count, err := notificationService.PurgeExpired(ctx, cfg.Notification.RetentionDays)
+			if err != nil {
+				return err
 			}
-		}
-	}()
+			if count > 0 {
+				sugaredLogger.Infow("Notification retention completed", "deleted_count", count, "retention_days", cfg.Notification.RetentionDays)
+			}
+			return nil
+		})
+	}
 
 	// Background job: app_logs retention by severity (runs every 24 hours).
 	// Industry-standard tiered retention — fatal class kept longer for
 	// post-mortems, info/debug churned aggressively. Audit logs are NOT
 	// touched; they live in audit_logs and are retained indefinitely for
 	// compliance.
-	go func() {
-		ticker := time.NewTicker(24 * time.Hour)
-		defer ticker.Stop()
-
-		// Each row: SQL level filter + max age in days. Keep rules
-		// data-driven so future policy tweaks are a single-line edit.
-		type retentionRule struct {
-			levels  []string
-			maxDays int
+	//
+	// Each row: SQL level filter + max age in days. Keep rules
+	// data-driven so future policy tweaks are a single-line edit.
+	type retentionRule struct {
+		levels  []string
+		maxDays int
+	}
+	appLogRules := []retentionRule{
+		{[]string{"fatal", "panic", "dpanic"}, 90},
+		{[]string{"error"}, 30},
+		{[]string{"warn"}, 14},
+		{[]string{"info", "debug"}, 3},
+	}
+	leaderJob("app-logs-retention", 24*time.Hour, 1*time.Hour, true, func(ctx context.Context) error {
+		var totalDeleted int64
+		for _, r := range appLogRules {
+			tag, err := db.Pool.Exec(ctx,
+				`DELETE FROM app_logs WHERE level = ANY($1) AND created_at < NOW() - make_interval(days => $2)`,
+				r.levels,
+				r.maxDays,
+			)
+			if err != nil {
+				return fmt.Errorf("purge %v: %w", r.levels, err)
+			}
+			totalDeleted += tag.RowsAffected()
 		}
-		rules := []retentionRule{
-			{[]string{"fatal", "panic", "dpanic"}, 90},
-			{[]string{"error"}, 30},
-			{[]string{"warn"}, 14},
-			{[]string{"info", "debug"}, 3},
+		if totalDeleted > 0 {
+			sugaredLogger.Infow("app_logs retention completed", "deleted_count", totalDeleted)
 		}
+		return nil
+	})
 
-		purgeAppLogs := func(ctx context.Context) error {
-			var totalDeleted int64
-			for _, r := range rules {
-				tag, err := db.Pool.Exec(ctx,
-					`DELETE FROM app_logs WHERE level = ANY($1) AND created_at < NOW() - make_interval(days => $2)`,
-					r.levels,
-					r.maxDays,
-				)
-				if err != nil {
-					return fmt.Errorf("purge %v: %w", r.levels, err)
-				}
-				totalDeleted += tag.RowsAffected()
-			}
-			if totalDeleted > 0 {
-				sugaredLogger.Infow("app_logs retention completed", "deleted_count", totalDeleted)
-			}
-			return nil
+	// Drain background tasks (notification fanout, post fanout, etc.) after
+	// the HTTP server stops — so requests still finishing can submit — and
+	// before the WebSocket hub closes, so in-flight notifications are
+	// flushed rather than dropped.
+	lc.OnStop("bgtasks", 10*time.Second, func(context.Context) error {
+		if !bgtasks.Shutdown(10 * time.Second) {
+			return errors.New("drain timed out — some tasks may not have completed")
 		}
+		return nil
+	})
 
-		runIfLeader("app-logs-retention", "lock:job:app-logs-retention", 1*time.Hour, purgeAppLogs)
-
-		for {
-			select {
-			case <-ticker.C:
-				runIfLeader("app-logs-retention", "lock:job:app-logs-retention", 1*time.Hour, purgeAppLogs)
-			case <-quit:
-				return
-			}
+	// The HTTP server is registered last so it is the first thing stopped:
+	// no new requests, in-flight ones get 10 s to finish.
+	lc.Go("http", func(context.Context) error {
+		sugaredLogger.Infow("Starting HTTP server", "address", addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("failed to start server: %w", err)
 		}
-	}()
-
-	<-quit
-
-	sugaredLogger.Info("Shutting down server...")
+		return nil
+	})
+	lc.OnStop("http", 10*time.Second, srv.Shutdown)
 
-	// Shut down WebSocket hub (close all client connections)
-	sugaredLogger.Info("Shutting down WebSocket hub...")
-	wsHub.Shutdown()
+	sugaredLogger.Infow("Server started successfully",
+		"address", addr,
+		"env", cfg.Server.Env,
+	)
 
-	// Drain background tasks (notification fanout, post fanout, etc.) up to
-	// 10 s so in-flight work either completes or is cleanly cancelled.
-	sugaredLogger.Info("Draining background tasks...")
-	if !bgtasks.Shutdown(10 * time.Second) {
-		sugaredLogger.Warn("bgtasks drain timed out — some tasks may not have completed")
-	}
+	// Wait for SIGINT / SIGTERM, or for a component to fail.
+	<-lc.Done()
 
-	// Graceful shutdown with timeout
-	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	sugaredLogger.Info("Shutting down server...")
 
-	if err := srv.Shutdown(ctx); err != nil {
-		sugaredLogger.Fatalw("Server forced to shutdown", "error", err)
+	// Stop steps run in reverse registration order: HTTP server, bgtasks
+	// drain, WebSocket fanout, WebSocket hub. Workers and periodic jobs
+	// then get 15 s to return before the deferred database, Redis and
+	// telemetry shutdowns run. An unclean shutdown is logged rather than
+	// fatal: os.Exit would skip those deferred closes and the log flush.
+	if err := lc.Shutdown(15 * time.Second); err != nil {
+		sugaredLogger.Errorw("Server did not shut down cleanly", "error", err)
+		return
 	}
 
 	sugaredLogger.Info("Server exited successfully")
@@ -1,7 +1,6 @@
 package config
 
 import (
-	"fmt"
 	"math"
 	"strings"
 	"time"
@@ -51,9 +50,17 @@ type Config struct {
 	APNs       APNsConfig
 	AppVersion AppVersionConfig
 	Geocoding  GeocodingConfig
+	Share      ShareConfig
+	Moderation ModerationConfig
+	Notification NotificationConfig
+	Poll         PollConfig
+	Business     BusinessConfig
+	Content      ContentConfig
 	RateLimit RateLimitConfig
 	Email     EmailConfig
 	CORS      CORSConfig
+	Security  SecurityConfig
+	Admin     AdminConfig
 	Monitoring MonitoringConfig
 	Crypto    CryptoConfig
 	Backup    BackupConfig
@@ -97,6 +104,17 @@ type ServerConfig struct {
 	// can't be spoofed by an arbitrary XFF header. Defaults to private docker
 	// ranges; override with TRUSTED_PROXIES (comma-separated).
 	TrustedProxies []string
+	// AccessLogSampleRate is the fraction (0..1) of successful requests
+	// written to the access log; errors and slow requests are always
+	// logged. Defaults to 1 (log everything) when unset.
+	AccessLogSampleRate float64
+	// AccessLogSkipPaths are paths (e.g. probe endpoints) whose successful
+	// requests are never access-logged.
+	AccessLogSkipPaths []string
+	// LegacyListEnvelope keeps each list endpoint's old data shape while
+	// clients migrate to the {data, meta} list envelope (LEGACY_LIST_ENVELOPE,
+	// default true). A client can opt in early with X-Envelope: 2.
+	LegacyListEnvelope bool
 }
 
 // DatabaseConfig holds database configuration
@@ -120,6 +138,11 @@ type DatabaseConfig struct {
 	ReplicaPort     string
 	ReplicaUser     string
 	ReplicaPassword string
+
+	// AutoMigrate applies pending migrations when the server starts
+	// (DB_AUTO_MIGRATE). Instances starting together take turns on a
+	// Postgres advisory lock, so it is safe with several replicas.
+	AutoMigrate bool
 }
 
 // RedisConfig holds Redis configuration
@@ -143,6 +166,10 @@ type JWTConfig struct {
 	// DeviceCredentialDuration sets the TTL for /auth/device/login secrets.
 	// 0 means non-expiring (until explicit revoke).
 	DeviceCredentialDuration time.Duration
+	// Keys is the signing keyring for key rotation (JWT_KEYS or
+	// JWT_KEYS_FILE). Empty means tokens are HS256-signed with Secret and
+	// carry no key ID, as before keyrings existed.
+	Keys []JWTKey
 }
 
 // OAuthConfig holds OAuth provider configurations
@@ -222,7 +249,69 @@ type APNsConfig struct {
 // GeocodingConfig holds geocoding service configuration
 type GeocodingConfig struct {
 	APIKey   string
-	Provider string
+	Provider string // nominatim (default) or none
+	BaseURL  string // self-hosted Nominatim /reverse endpoint; empty = public instance
+}
+
+// ShareConfig holds the URLs used by external post share links.
+type ShareConfig struct {
+	LinkBaseURL string // SHARE_LINK_BASE_URL — short link prefix, e.g. https://hamsaya.af/s/
+	PostURL     string // SHARE_POST_URL — landing page the short link redirects to, post id appended
+	InviteURL   string // SHARE_INVITE_URL — invite landing page, invite code appended
+}
+
+// ModerationConfig configures background image moderation.
+type ModerationConfig struct {
+	NSFWScannerURL     string  // NSFW_SCANNER_URL — NudeNet sidecar root; empty disables image scanning
+	NSFWBlockThreshold float64 // NSFW_BLOCK_THRESHOLD — detection score that flags an image (default 0.6)
+	BlurFlaggedMedia   bool    // NSFW_BLUR_FLAGGED — serve blurred copies of flagged images until reviewed
+	HoldProfileMedia   bool    // MODERATION_HOLD_PROFILE_MEDIA — keep the old avatar/cover until a new one is approved
+}
+
+// NotificationConfig holds in-app notification inbox settings.
+type NotificationConfig struct {
+	RetentionDays int // NOTIFICATION_RETENTION_DAYS — older notifications are pruned daily (default 90; 0 keeps them forever)
+}
+
+// PollConfig holds poll voting settings.
+type PollConfig struct {
+	VoteLockAfter time.Duration // POLL_VOTE_LOCK_AFTER — votes can be changed or withdrawn for this long after being cast (0 = never locks)
+}
+
+// BusinessConfig holds business profile settings.
+type BusinessConfig struct {
+	MaxGalleryImages int // BUSINESS_GALLERY_MAX_IMAGES — gallery images per business (default 10)
+}
+
+// ContentConfig holds post and comment content limits. Post applies to
+// every post type and PostTypes overrides it per type, read from keys
+// naming the type (e.g. CONTENT_SELL_DESCRIPTION_MAX_LENGTH). Zero keeps
+// the built-in default.
+type ContentConfig struct {
+	Post                  ContentPostLimits            // CONTENT_POST_*
+	PostTypes             map[string]ContentPostLimits // CONTENT_<TYPE>_*, keyed by post type
+	PollMaxOptions        int                          // CONTENT_POLL_MAX_OPTIONS
+	PollOptionMaxLength   int                          // CONTENT_POLL_OPTION_MAX_LENGTH
+	CommentMaxLength      int                          // CONTENT_COMMENT_MAX_LENGTH
+	CommentMaxAttachments int                          // CONTENT_COMMENT_MAX_ATTACHMENTS
+}
+
+// ContentPostLimits are the limits of one post type.
+type ContentPostLimits struct {
+	TitleMaxLength       int // *_TITLE_MAX_LENGTH (at most 255, the column size)
+	DescriptionMaxLength int // *_DESCRIPTION_MAX_LENGTH
+	MaxAttachments       int // *_MAX_ATTACHMENTS
+}
+
+// contentPostTypes are the post types that accept CONTENT_<TYPE>_* overrides.
+var contentPostTypes = []string{"FEED", "EVENT", "SELL", "PULL", "LOST_FOUND", "ALERT", "HELP"}
+
+func loadContentPostLimits(prefix string) ContentPostLimits {
+	return ContentPostLimits{
+		TitleMaxLength:       viper.GetInt(prefix + "_TITLE_MAX_LENGTH"),
+		DescriptionMaxLength: viper.GetInt(prefix + "_DESCRIPTION_MAX_LENGTH"),
+		MaxAttachments:       viper.GetInt(prefix + "_MAX_ATTACHMENTS"),
+	}
 }
 
 // RateLimitConfig holds rate limiting configuration
@@ -230,6 +319,12 @@ type RateLimitConfig struct {
 	RequestsPerHour int
 	AuthAttempts    int
 	AuthWindow      time.Duration
+
+	// Per-user content creation limits, enforced in services on top of the
+	// IP-level middleware limits. Zero keeps the built-in default.
+	CreatePostsPerHour      int // CREATE_LIMIT_POSTS_PER_HOUR
+	CreateCommentsPerMinute int // CREATE_LIMIT_COMMENTS_PER_MINUTE
+	CreateMessagesPer10s    int // CREATE_LIMIT_MESSAGES_PER_10S
 }
 
 // EmailConfig holds email configuration (SMTP and/or Resend)
@@ -240,6 +335,9 @@ type EmailConfig struct {
 	Password          string
 	From              string
 	ResendAPIKey      string // When set, send via Resend API instead of SMTP
+	// ResendWebhookSecret is the Svix signing secret ("whsec_...") for the
+	// Resend delivery webhook. Empty rejects every webhook call.
+	ResendWebhookSecret string
 	EmailVerifyBaseURL string // Base URL for verification link (e.g. https://hamsaya.com or app deep link)
 	// AppLink is the smart deep link used in re-engagement emails (e.g. the
 	// AppsFlyer OneLink https://hamsaya.onelink.me/XXXX): opens the app if
@@ -254,12 +352,47 @@ type EmailConfig struct {
 
 // CORSConfig holds CORS configuration
 type CORSConfig struct {
+	// AllowedOrigins are exact origins or wildcard-subdomain patterns such as
+	// https://*.hamsaya.app. CORS_ALLOWED_ORIGINS_<ENV> (e.g.
+	// CORS_ALLOWED_ORIGINS_PRODUCTION) replaces CORS_ALLOWED_ORIGINS for that
+	// environment, so one .env can describe every deployment.
 	AllowedOrigins   []string
 	AllowedMethods   []string
 	AllowedHeaders   []string
 	AllowCredentials bool
 }
 
+// SecurityConfig holds the security response headers. Empty values keep the
+// middleware defaults.
+type SecurityConfig struct {
+	ContentSecurityPolicy string // SECURITY_CSP, for API responses
+	// DashboardCSP applies instead under DashboardPaths, for the admin web
+	// dashboard when it is served from this origin. Env: SECURITY_DASHBOARD_CSP,
+	// SECURITY_DASHBOARD_PATHS (comma-separated path prefixes).
+	DashboardCSP   string
+	DashboardPaths []string
+	ReferrerPolicy string // SECURITY_REFERRER_POLICY
+	HSTSMaxAge     int    // seconds; SECURITY_HSTS_MAX_AGE
+	HSTSPreload    bool   // SECURITY_HSTS_PRELOAD, default true
+}
+
+// AdminConfig hardens the admin dashboard.
+type AdminConfig struct {
+	// RequireMFA refuses admin-dashboard logins of accounts without MFA and
+	// admin-route requests with tokens below AAL2. ADMIN_REQUIRE_MFA,
+	// default true.
+	RequireMFA bool
+	// IPAllowlist limits /admin and /auth/admin to these addresses or CIDR
+	// ranges (ADMIN_IP_ALLOWLIST, comma-separated). Empty allows any.
+	IPAllowlist []string
+	// AccessTokenDuration is the access-token lifetime of dashboard
+	// sessions. ADMIN_ACCESS_TOKEN_DURATION, default 10m.
+	AccessTokenDuration time.Duration
+	// SessionDuration is how long a dashboard login lasts; refreshing does
+	// not extend it. ADMIN_SESSION_DURATION, default 12h.
+	SessionDuration time.Duration
+}
+
 // MonitoringConfig holds monitoring and observability configuration
 type MonitoringConfig struct {
 	SentryDSN            string
@@ -267,10 +400,28 @@ type MonitoringConfig struct {
 	ObservabilityEnabled bool
 	OTLPEndpoint         string
 	TraceSamplingRate    float64
+	// HealthCriticalComponents lists readiness components whose failure
+	// returns 503. Anything else only marks /health/ready as degraded.
+	HealthCriticalComponents []string
 }
 
-// Load loads configuration from environment variables
+// Load loads configuration from environment variables and validates it.
+// A *ValidationError lists every problem at once.
 func Load() (*Config, error) {
+	cfg, err := LoadUnvalidated()
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// LoadUnvalidated reads the configuration and applies defaults without
+// validating it. Used by the config-dump command so an operator can inspect
+// an incomplete configuration.
+func LoadUnvalidated() (*Config, error) {
 	viper.SetConfigFile(".env")
 	viper.AutomaticEnv()
 
@@ -279,12 +430,13 @@ func Load() (*Config, error) {
 
 	cfg := &Config{
 		Server: ServerConfig{
-			Port:              viper.GetString("SERVER_PORT"),
-			Host:              viper.GetString("SERVER_HOST"),
-			Env:               viper.GetString("ENV"),
-			LogLevel:          viper.GetString("LOG_LEVEL"),
-			AdminCookieDomain: viper.GetString("ADMIN_COOKIE_DOMAIN"),
-			TrustedProxies:    parseTrustedProxies(viper.GetString("TRUSTED_PROXIES")),
+			Port:               viper.GetString("SERVER_PORT"),
+			Host:               viper.GetString("SERVER_HOST"),
+			Env:                viper.GetString("ENV"),
+			LogLevel:           viper.GetString("LOG_LEVEL"),
+			AdminCookieDomain:  viper.GetString("ADMIN_COOKIE_DOMAIN"),
+			TrustedProxies:     parseTrustedProxies(viper.GetString("TRUSTED_PROXIES")),
+			AccessLogSkipPaths: parseStringSlice(viper.GetString("ACCESS_LOG_SKIP_PATHS")),
 		},
 		Database: DatabaseConfig{
 			Host:            viper.GetString("DB_HOST"),
@@ -301,6 +453,7 @@ func Load() (*Config, error) {
 			ReplicaPort:     viper.GetString("DB_REPLICA_PORT"),
 			ReplicaUser:     viper.GetString("DB_REPLICA_USER"),
 			ReplicaPassword: viper.GetString("DB_REPLICA_PASSWORD"),
+			AutoMigrate:     viper.GetBool("DB_AUTO_MIGRATE"),
 		},
 		Redis: RedisConfig{
 			Host:     viper.GetString("REDIS_HOST"),
@@ -367,11 +520,27 @@ func Load() (*Config, error) {
 		Geocoding: GeocodingConfig{
 			APIKey:   viper.GetString("GEOCODING_API_KEY"),
 			Provider: viper.GetString("GEOCODING_PROVIDER"),
+			BaseURL:  viper.GetString("GEOCODING_BASE_URL"),
+		},
+		Share: ShareConfig{
+			LinkBaseURL: viper.GetString("SHARE_LINK_BASE_URL"),
+			PostURL:     viper.GetString("SHARE_POST_URL"),
+			InviteURL:   viper.GetString("SHARE_INVITE_URL"),
+		},
+		Moderation: ModerationConfig{
+			NSFWScannerURL:     viper.GetString("NSFW_SCANNER_URL"),
+			NSFWBlockThreshold: viper.GetFloat64("NSFW_BLOCK_THRESHOLD"),
+			BlurFlaggedMedia:   viper.GetBool("NSFW_BLUR_FLAGGED"),
+			HoldProfileMedia:   viper.GetBool("MODERATION_HOLD_PROFILE_MEDIA"),
 		},
 		RateLimit: RateLimitConfig{
 			RequestsPerHour: viper.GetInt("RATE_LIMIT_REQUESTS_PER_HOUR"),
 			AuthAttempts:    viper.GetInt("RATE_LIMIT_AUTH_ATTEMPTS"),
 			AuthWindow:      viper.GetDuration("RATE_LIMIT_AUTH_WINDOW"),
+
+			CreatePostsPerHour:      viper.GetInt("CREATE_LIMIT_POSTS_PER_HOUR"),
+			CreateCommentsPerMinute: viper.GetInt("CREATE_LIMIT_COMMENTS_PER_MINUTE"),
+			CreateMessagesPer10s:    viper.GetInt("CREATE_LIMIT_MESSAGES_PER_10S"),
 		},
 		Email: EmailConfig{
 			SMTPHost:           viper.GetString("SMTP_HOST"),
@@ -380,6 +549,7 @@ func Load() (*Config, error) {
 			Password:           viper.GetString("SMTP_PASSWORD"),
 			From:               viper.GetString("EMAIL_FROM"),
 			ResendAPIKey:       viper.GetString("RESEND_API_KEY"),
+			ResendWebhookSecret: viper.GetString("RESEND_WEBHOOK_SECRET"),
 			EmailVerifyBaseURL: viper.GetString("EMAIL_VERIFY_BASE_URL"),
 			AppLink:            viper.GetString("APP_DEEP_LINK_URL"),
 			StoreURLIOS:        viper.GetString("APP_STORE_URL_IOS"),
@@ -391,12 +561,27 @@ func Load() (*Config, error) {
 			AllowedHeaders:   parseStringSlice(viper.GetString("CORS_ALLOWED_HEADERS")),
 			AllowCredentials: viper.GetBool("CORS_ALLOW_CREDENTIALS"),
 		},
+		Security: SecurityConfig{
+			ContentSecurityPolicy: viper.GetString("SECURITY_CSP"),
+			DashboardCSP:          viper.GetString("SECURITY_DASHBOARD_CSP"),
+			DashboardPaths:        parseStringSlice(viper.GetString("SECURITY_DASHBOARD_PATHS")),
+			ReferrerPolicy:        viper.GetString("SECURITY_REFERRER_POLICY"),
+			HSTSMaxAge:            viper.GetInt("SECURITY_HSTS_MAX_AGE"),
+			HSTSPreload:           true,
+		},
+		Admin: AdminConfig{
+			RequireMFA:          true,
+			IPAllowlist:         parseStringSlice(viper.GetString("ADMIN_IP_ALLOWLIST")),
+			AccessTokenDuration: durationOrDefault("ADMIN_ACCESS_TOKEN_DURATION", 10*time.Minute),
+			SessionDuration:     durationOrDefault("ADMIN_SESSION_DURATION", 12*time.Hour),
+		},
 		Monitoring: MonitoringConfig{
-			SentryDSN:            viper.GetString("SENTRY_DSN"),
-			PrometheusEnabled:    viper.GetBool("PROMETHEUS_ENABLED"),
-			ObservabilityEnabled: viper.GetBool("OBSERVABILITY_ENABLED"),
-			OTLPEndpoint:         viper.GetString("OTLP_ENDPOINT"),
-			TraceSamplingRate:    viper.GetFloat64("TRACE_SAMPLING_RATE"),
+			SentryDSN:                viper.GetString("SENTRY_DSN"),
+			PrometheusEnabled:        viper.GetBool("PROMETHEUS_ENABLED"),
+			ObservabilityEnabled:     viper.GetBool("OBSERVABILITY_ENABLED"),
+			OTLPEndpoint:             viper.GetString("OTLP_ENDPOINT"),
+			TraceSamplingRate:        viper.GetFloat64("TRACE_SAMPLING_RATE"),
+			HealthCriticalComponents: parseStringSlice(viper.GetString("HEALTH_CRITICAL_COMPONENTS")),
 		},
 		Crypto: CryptoConfig{
 			MFASecretKey: viper.GetString("MFA_SECRET_ENCRYPTION_KEY"),
@@ -409,6 +594,55 @@ func Load() (*Config, error) {
 		},
 	}
 
+	if viper.IsSet("SECURITY_HSTS_PRELOAD") {
+		cfg.Security.HSTSPreload = viper.GetBool("SECURITY_HSTS_PRELOAD")
+	}
+	if viper.IsSet("ADMIN_REQUIRE_MFA") {
+		cfg.Admin.RequireMFA = viper.GetBool("ADMIN_REQUIRE_MFA")
+	}
+
+	if envOrigins := parseStringSlice(viper.GetString("CORS_ALLOWED_ORIGINS_" + strings.ToUpper(cfg.Server.Env))); cfg.Server.Env != "" && len(envOrigins) > 0 {
+		cfg.CORS.AllowedOrigins = envOrigins
+	}
+
+	cfg.Notification.RetentionDays = 90
+	if viper.IsSet("NOTIFICATION_RETENTION_DAYS") {
+		cfg.Notification.RetentionDays = viper.GetInt("NOTIFICATION_RETENTION_DAYS")
+	}
+
+	if d := viper.GetDuration("POLL_VOTE_LOCK_AFTER"); d > 0 {
+		cfg.Poll.VoteLockAfter = d
+	}
+
+	cfg.Business.MaxGalleryImages = 10
+	if viper.IsSet("BUSINESS_GALLERY_MAX_IMAGES") {
+		cfg.Business.MaxGalleryImages = viper.GetInt("BUSINESS_GALLERY_MAX_IMAGES")
+	}
+
+	cfg.Content = ContentConfig{
+		Post:                  loadContentPostLimits("CONTENT_POST"),
+		PostTypes:             make(map[string]ContentPostLimits),
+		PollMaxOptions:        viper.GetInt("CONTENT_POLL_MAX_OPTIONS"),
+		PollOptionMaxLength:   viper.GetInt("CONTENT_POLL_OPTION_MAX_LENGTH"),
+		CommentMaxLength:      viper.GetInt("CONTENT_COMMENT_MAX_LENGTH"),
+		CommentMaxAttachments: viper.GetInt("CONTENT_COMMENT_MAX_ATTACHMENTS"),
+	}
+	for _, t := range contentPostTypes {
+		if limits := loadContentPostLimits("CONTENT_" + t); limits != (ContentPostLimits{}) {
+			cfg.Content.PostTypes[t] = limits
+		}
+	}
+
+	cfg.Server.LegacyListEnvelope = true
+	if viper.IsSet("LEGACY_LIST_ENVELOPE") {
+		cfg.Server.LegacyListEnvelope = viper.GetBool("LEGACY_LIST_ENVELOPE")
+	}
+
+	cfg.Server.AccessLogSampleRate = 1
+	if viper.IsSet("ACCESS_LOG_SAMPLE_RATE") {
+		cfg.Server.AccessLogSampleRate = viper.GetFloat64("ACCESS_LOG_SAMPLE_RATE")
+	}
+
 	// Default observability settings
 	if cfg.Monitoring.TraceSamplingRate == 0 {
 		// Default to 10% sampling in production, 100% in development
@@ -451,36 +685,11 @@ func Load() (*Config, error) {
 		cfg.Database.MaxConnIdleTime = 30 * time.Minute
 	}
 
-	// Reject weak or default JWT secrets at startup to prevent accidental insecure deployments.
-	const defaultJWTSecret = "your-super-secret-jwt-key-change-this-in-production"
-	if cfg.JWT.Secret == "" || cfg.JWT.Secret == defaultJWTSecret || len(cfg.JWT.Secret) < 32 {
-		return nil, fmt.Errorf(
-			"JWT_SECRET must be set to a strong, unique secret of at least 32 characters " +
-				"(current value is empty, the default placeholder, or too short)")
-	}
-
-	// Require MFA encryption key: non-empty and a valid 32-byte hex string (64 hex chars).
-	// pkg/crypto.NewSecretCipher enforces the same shape; validating here fails fast at boot
-	// instead of at first MFA operation.
-	if cfg.Crypto.MFASecretKey == "" {
-		return nil, fmt.Errorf(
-			"MFA_SECRET_ENCRYPTION_KEY must be set (32-byte hex, 64 characters) — " +
-				"generate with: openssl rand -hex 32")
-	}
-	if len(cfg.Crypto.MFASecretKey) != 64 {
-		return nil, fmt.Errorf(
-			"MFA_SECRET_ENCRYPTION_KEY must be 64 hex characters (32 bytes); got %d characters",
-			len(cfg.Crypto.MFASecretKey))
-	}
-
-	// Reject default MinIO dev credential for object storage to prevent accidental
-	// deployment with well-known keys.
-	const defaultStorageSecretKey = "minioadmin"
-	if cfg.Storage.SecretKey == "" || cfg.Storage.SecretKey == defaultStorageSecretKey {
-		return nil, fmt.Errorf(
-			"STORAGE_SECRET_KEY must be set to a non-default value " +
-				"(current value is empty or the well-known MinIO default 'minioadmin')")
+	jwtKeys, err := loadJWTKeys(viper.GetString("JWT_KEYS"), viper.GetString("JWT_KEYS_FILE"))
+	if err != nil {
+		return nil, err
 	}
+	cfg.JWT.Keys = jwtKeys
 
 	// Default CORS in development so admin panel (e.g. localhost:3001) works without .env
 	if cfg.Server.Env == "development" {
@@ -494,7 +703,7 @@ func Load() (*Config, error) {
 			cfg.CORS.AllowedMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"}
 		}
 		if len(cfg.CORS.AllowedHeaders) == 0 {
-			cfg.CORS.AllowedHeaders = []string{"Content-Type", "Authorization", "Accept", "Origin", "User-Agent", "X-CSRF-Token", "X-Device-Info"}
+			cfg.CORS.AllowedHeaders = []string{"Content-Type", "Authorization", "Accept", "Origin", "User-Agent", "X-CSRF-Token", "X-Device-Info", "X-Acting-Business"}
 		}
 		if !cfg.CORS.AllowCredentials {
 			// Admin SPA depends on credentialed cross-origin requests when
@@ -504,19 +713,6 @@ func Load() (*Config, error) {
 		}
 	}
 
-	// Reject the unsafe combination of credentialed CORS with a wildcard
-	// origin: browsers ignore the response, but the misconfiguration tends to
-	// hide a real bug (someone meant to allowlist explicit origins).
-	if cfg.CORS.AllowCredentials {
-		for _, o := range cfg.CORS.AllowedOrigins {
-			if strings.TrimSpace(o) == "*" {
-				return nil, fmt.Errorf(
-					"CORS_ALLOWED_ORIGINS cannot contain '*' when CORS_ALLOW_CREDENTIALS=true; " +
-						"list explicit origins (e.g. https://admin.hamsaya.af)")
-			}
-		}
-	}
-
 	return cfg, nil
 }
 
@@ -3,6 +3,7 @@ package config
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -103,3 +104,134 @@ func TestRedisConfig_GetAddr(t *testing.T) {
 	addr := cfg.GetAddr()
 	assert.Equal(t, "localhost:6379", addr)
 }
+
+func TestLoad_ListsEveryProblem(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("JWT_SECRET", "short")
+	t.Setenv("STORAGE_SECRET_KEY", "")
+	t.Setenv("DB_PORT", "not-a-port")
+
+	_, err := Load()
+	require.Error(t, err)
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Len(t, verr.Problems, 3)
+	assert.Contains(t, err.Error(), "JWT_SECRET")
+	assert.Contains(t, err.Error(), "STORAGE_SECRET_KEY")
+	assert.Contains(t, err.Error(), "DB_PORT")
+}
+
+func TestLoad_RejectsPartialFirebase(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("FIREBASE_PROJECT_ID", "hamsaya")
+
+	_, err := Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "FIREBASE_PRIVATE_KEY")
+}
+
+func TestConfig_DumpRedactsSecrets(t *testing.T) {
+	setValidEnv(t)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	dump := cfg.Dump()
+
+	jwt := dump["JWT"].(map[string]interface{})
+	assert.Equal(t, "<set>", jwt["Secret"])
+	assert.Equal(t, "15m0s", jwt["AccessTokenDuration"])
+	storage := dump["Storage"].(map[string]interface{})
+	assert.Equal(t, "<set>", storage["SecretKey"])
+	crypto := dump["Crypto"].(map[string]interface{})
+	assert.Equal(t, "<set>", crypto["MFASecretKey"])
+	db := dump["Database"].(map[string]interface{})
+	assert.Equal(t, "localhost", db["Host"])
+	assert.Equal(t, "<unset>", db["Password"])
+}
+
+func TestLoad_PerEnvironmentCORSOrigins(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("ENV", "staging")
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://hamsaya.af")
+	t.Setenv("CORS_ALLOWED_ORIGINS_STAGING", "https://*.staging.hamsaya.af, https://staging.hamsaya.af")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://*.staging.hamsaya.af", "https://staging.hamsaya.af"}, cfg.CORS.AllowedOrigins)
+	assert.True(t, cfg.Security.HSTSPreload)
+}
+
+func TestLoad_RejectsMisplacedCORSWildcard(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://admin.*.hamsaya.af")
+
+	_, err := Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "first subdomain label")
+}
+
+func TestLoad_AdminHardening(t *testing.T) {
+	setValidEnv(t)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.Admin.RequireMFA)
+	assert.Empty(t, cfg.Admin.IPAllowlist)
+	assert.Equal(t, 10*time.Minute, cfg.Admin.AccessTokenDuration)
+	assert.Equal(t, 12*time.Hour, cfg.Admin.SessionDuration)
+
+	t.Setenv("ADMIN_REQUIRE_MFA", "false")
+	t.Setenv("ADMIN_IP_ALLOWLIST", "203.0.113.7, 10.20.0.0/16")
+	cfg, err = Load()
+	require.NoError(t, err)
+	assert.False(t, cfg.Admin.RequireMFA)
+	assert.Equal(t, []string{"203.0.113.7", "10.20.0.0/16"}, cfg.Admin.IPAllowlist)
+
+	t.Setenv("ADMIN_IP_ALLOWLIST", "office")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ADMIN_IP_ALLOWLIST")
+}
+
+func TestLoad_ContentLimits(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("CONTENT_POST_DESCRIPTION_MAX_LENGTH", "3000")
+	t.Setenv("CONTENT_SELL_MAX_ATTACHMENTS", "8")
+	t.Setenv("CONTENT_COMMENT_MAX_LENGTH", "500")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 3000, cfg.Content.Post.DescriptionMaxLength)
+	assert.Equal(t, map[string]ContentPostLimits{"SELL": {MaxAttachments: 8}}, cfg.Content.PostTypes)
+	assert.Equal(t, 500, cfg.Content.CommentMaxLength)
+
+	t.Setenv("CONTENT_EVENT_TITLE_MAX_LENGTH", "300")
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "CONTENT_EVENT_TITLE_MAX_LENGTH")
+}
+
+func TestLoad_JWTKeyring(t *testing.T) {
+	setValidEnv(t)
+	t.Setenv("JWT_KEYS", `[
+		{"kid":"2026-09","alg":"HS256","secret":"previous-secret-at-least-32-characters-long","active_from":"2026-09-01T00:00:00Z"},
+		{"kid":"2026-10","alg":"HS256","secret":"current-secret-at-least-32-characters-long!","active_from":"2026-10-01T00:00:00Z"}
+	]`)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.Len(t, cfg.JWT.Keys, 2)
+	assert.Equal(t, "2026-10", cfg.JWT.Keys[1].ID)
+
+	keys := cfg.Dump()["JWT"].(map[string]interface{})["Keys"].([]map[string]interface{})
+	assert.Equal(t, "<set>", keys[0]["Secret"])
+	assert.Equal(t, "2026-09", keys[0]["ID"])
+
+	t.Setenv("JWT_KEYS", `[{"kid":"a","alg":"HS256","secret":"short"},{"kid":"a","alg":"RS256","private_key":"nope"},{"alg":"ES256"}]`)
+	_, err = Load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `key "a" needs a secret`)
+	assert.Contains(t, err.Error(), `duplicate kid "a"`)
+	assert.Contains(t, err.Error(), "not PEM encoded")
+	assert.Contains(t, err.Error(), "entry 2 has no kid")
+}
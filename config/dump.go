@@ -0,0 +1,61 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// sensitiveFieldMarkers flag struct fields whose values must never be
+// printed. Matching is on the Go field name, case-insensitive.
+var sensitiveFieldMarkers = []string{"secret", "password", "privatekey", "passphrase", "apikey", "keyp8", "dsn", "accesskey"}
+
+func isSensitiveField(name string) bool {
+	lower := strings.ToLower(name)
+	for _, m := range sensitiveFieldMarkers {
+		if strings.Contains(lower, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// Dump returns the effective configuration (after defaults) as nested maps
+// keyed by section and field name. Secrets are replaced with "<set>" or
+// "<unset>" so the output is safe to paste into a ticket.
+func (c *Config) Dump() map[string]interface{} {
+	return dumpStruct(reflect.ValueOf(*c))
+}
+
+func dumpStruct(v reflect.Value) map[string]interface{} {
+	out := make(map[string]interface{}, v.NumField())
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		switch {
+		case fv.Kind() == reflect.Struct && field.Type.PkgPath() == t.PkgPath():
+			out[field.Name] = dumpStruct(fv)
+		case fv.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.Struct && field.Type.Elem().PkgPath() == t.PkgPath():
+			items := make([]map[string]interface{}, fv.Len())
+			for j := range items {
+				items[j] = dumpStruct(fv.Index(j))
+			}
+			out[field.Name] = items
+		case isSensitiveField(field.Name):
+			if fv.IsZero() {
+				out[field.Name] = "<unset>"
+			} else {
+				out[field.Name] = "<set>"
+			}
+		case field.Type == reflect.TypeOf(time.Duration(0)):
+			out[field.Name] = fv.Interface().(time.Duration).String()
+		default:
+			out[field.Name] = fv.Interface()
+		}
+	}
+	return out
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// JWT signing algorithms a keyring entry may use.
+const (
+	JWTAlgHS256 = "HS256"
+	JWTAlgRS256 = "RS256"
+)
+
+// JWTKey is one entry of the JWT signing keyring (JWT_KEYS, or the file
+// named by JWT_KEYS_FILE; a JSON array). The newest key whose ActiveFrom
+// has passed signs new tokens, so a rotation is scheduled by adding the next
+// key with a future ActiveFrom. The key it replaces keeps validating tokens
+// until it is dropped from the keyring.
+type JWTKey struct {
+	ID         string    `json:"kid"`
+	Algorithm  string    `json:"alg"`                   // HS256 or RS256
+	Secret     string    `json:"secret,omitempty"`      // HS256 shared secret
+	PrivateKey string    `json:"private_key,omitempty"` // RS256 PEM: raw, \n-escaped or base64
+	ActiveFrom time.Time `json:"active_from"`
+}
+
+// RSAPrivateKey parses the key's PEM (PKCS#1 or PKCS#8).
+func (k JWTKey) RSAPrivateKey() (*rsa.PrivateKey, error) {
+	value := k.PrivateKey
+	if !strings.Contains(value, "-----BEGIN") {
+		if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value)); err == nil {
+			value = string(decoded)
+		}
+	}
+	block, _ := pem.Decode([]byte(strings.ReplaceAll(value, `\n`, "\n")))
+	if block == nil {
+		return nil, fmt.Errorf("private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// loadJWTKeys reads the keyring from the inline JSON or, when that is empty,
+// from the file. Neither set means the single JWT_SECRET key.
+func loadJWTKeys(inline, path string) ([]JWTKey, error) {
+	raw := strings.TrimSpace(inline)
+	if raw == "" && path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read JWT_KEYS_FILE: %w", err)
+		}
+		raw = strings.TrimSpace(string(data))
+	}
+	if raw == "" {
+		return nil, nil
+	}
+	var keys []JWTKey
+	if err := json.Unmarshal([]byte(raw), &keys); err != nil {
+		return nil, fmt.Errorf("parse JWT keyring: %w", err)
+	}
+	return keys, nil
+}
+
+// validateJWTKeys reports keyring problems through add.
+func validateJWTKeys(keys []JWTKey, add func(format string, args ...interface{})) {
+	seen := make(map[string]bool, len(keys))
+	for i, k := range keys {
+		if k.ID == "" {
+			add("JWT_KEYS entry %d has no kid", i)
+			continue
+		}
+		if seen[k.ID] {
+			add("JWT_KEYS has duplicate kid %q", k.ID)
+		}
+		seen[k.ID] = true
+		switch k.Algorithm {
+		case JWTAlgHS256:
+			if len(k.Secret) < 32 || k.Secret == defaultJWTSecret {
+				add("JWT_KEYS key %q needs a secret of at least 32 characters", k.ID)
+			}
+		case JWTAlgRS256:
+			if _, err := k.RSAPrivateKey(); err != nil {
+				add("JWT_KEYS key %q: %v", k.ID, err)
+			}
+		default:
+			add("JWT_KEYS key %q has unsupported alg %q (use HS256 or RS256)", k.ID, k.Algorithm)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package config
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// ValidationError lists every problem found in a loaded configuration so an
+// operator can fix the .env in one pass instead of one restart per mistake.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid configuration (%d problem", len(e.Problems))
+	if len(e.Problems) != 1 {
+		b.WriteString("s")
+	}
+	b.WriteString("):")
+	for _, p := range e.Problems {
+		b.WriteString("\n  - ")
+		b.WriteString(p)
+	}
+	return b.String()
+}
+
+const (
+	defaultJWTSecret        = "your-super-secret-jwt-key-change-this-in-production"
+	defaultStorageSecretKey = "minioadmin"
+)
+
+// Validate checks the configuration for values that would only fail later
+// at runtime. It returns a *ValidationError listing every problem, or nil.
+func (c *Config) Validate() error {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	requirePort := func(key, value string) {
+		if value == "" {
+			return
+		}
+		if n, err := strconv.Atoi(value); err != nil || n < 1 || n > 65535 {
+			add("%s must be a port number between 1 and 65535 (got %q)", key, value)
+		}
+	}
+
+	// Connectivity: without these the process dies at the first query.
+	if c.Database.Host == "" {
+		add("DB_HOST must be set")
+	}
+	if c.Database.Name == "" {
+		add("DB_NAME must be set")
+	}
+	if c.Redis.Host == "" {
+		add("REDIS_HOST must be set")
+	}
+	requirePort("SERVER_PORT", c.Server.Port)
+	requirePort("DB_PORT", c.Database.Port)
+	requirePort("REDIS_PORT", c.Redis.Port)
+	requirePort("SMTP_PORT", c.Email.SMTPPort)
+	if c.Database.MinConns > c.Database.MaxConns {
+		add("DB_MIN_CONNS (%d) cannot exceed DB_MAX_CONNS (%d)", c.Database.MinConns, c.Database.MaxConns)
+	}
+	if c.Database.MaxConns < 0 || c.Database.MinConns < 0 {
+		add("DB_MAX_CONNS and DB_MIN_CONNS must not be negative")
+	}
+
+	// Reject weak or default JWT secrets at startup to prevent accidental insecure deployments.
+	// With a keyring configured, JWT_SECRET is unused and each key is checked instead.
+	if len(c.JWT.Keys) > 0 {
+		validateJWTKeys(c.JWT.Keys, add)
+	} else if c.JWT.Secret == "" || c.JWT.Secret == defaultJWTSecret || len(c.JWT.Secret) < 32 {
+		add("JWT_SECRET must be set to a strong, unique secret of at least 32 characters " +
+			"(current value is empty, the default placeholder, or too short)")
+	}
+
+	// Require MFA encryption key: non-empty and a valid 32-byte hex string (64 hex chars).
+	// pkg/crypto.NewSecretCipher enforces the same shape; validating here fails fast at boot
+	// instead of at first MFA operation.
+	switch {
+	case c.Crypto.MFASecretKey == "":
+		add("MFA_SECRET_ENCRYPTION_KEY must be set (32-byte hex, 64 characters) — " +
+			"generate with: openssl rand -hex 32")
+	case len(c.Crypto.MFASecretKey) != 64:
+		add("MFA_SECRET_ENCRYPTION_KEY must be 64 hex characters (32 bytes); got %d characters",
+			len(c.Crypto.MFASecretKey))
+	}
+
+	// Reject default MinIO dev credential for object storage to prevent accidental
+	// deployment with well-known keys.
+	if c.Storage.SecretKey == "" || c.Storage.SecretKey == defaultStorageSecretKey {
+		add("STORAGE_SECRET_KEY must be set to a non-default value " +
+			"(current value is empty or the well-known MinIO default 'minioadmin')")
+	}
+
+	// Reject the unsafe combination of credentialed CORS with a wildcard
+	// origin: browsers ignore the response, but the misconfiguration tends to
+	// hide a real bug (someone meant to allowlist explicit origins).
+	if c.CORS.AllowCredentials {
+		for _, o := range c.CORS.AllowedOrigins {
+			if strings.TrimSpace(o) == "*" {
+				add("CORS_ALLOWED_ORIGINS cannot contain '*' when CORS_ALLOW_CREDENTIALS=true; " +
+					"list explicit origins (e.g. https://admin.hamsaya.af)")
+				break
+			}
+		}
+	}
+
+	// "*" is only understood alone or as a leading subdomain label
+	// (https://*.example.com); anywhere else it would silently match nothing.
+	for _, o := range c.CORS.AllowedOrigins {
+		o = strings.TrimSpace(o)
+		if o == "*" || !strings.Contains(o, "*") {
+			continue
+		}
+		if _, rest, ok := strings.Cut(o, "://*."); !ok || strings.Contains(rest, "*") {
+			add("CORS origin %q: a wildcard must be the first subdomain label (e.g. https://*.hamsaya.af)", o)
+		}
+	}
+
+	if c.Security.HSTSMaxAge < 0 {
+		add("SECURITY_HSTS_MAX_AGE must not be negative")
+	}
+
+	for _, entry := range c.Admin.IPAllowlist {
+		if !validIPOrCIDR(entry) {
+			add("ADMIN_IP_ALLOWLIST entry %q is not an IP address or CIDR range", entry)
+		}
+	}
+
+	if c.RateLimit.CreatePostsPerHour < 0 || c.RateLimit.CreateCommentsPerMinute < 0 || c.RateLimit.CreateMessagesPer10s < 0 {
+		add("CREATE_LIMIT_* values must not be negative")
+	}
+
+	if c.Notification.RetentionDays < 0 {
+		add("NOTIFICATION_RETENTION_DAYS must not be negative (0 keeps notifications forever)")
+	}
+
+	if n := c.Business.MaxGalleryImages; n < 1 || n > 100 {
+		add("BUSINESS_GALLERY_MAX_IMAGES must be between 1 and 100 (got %d)", n)
+	}
+
+	checkContentPost := func(prefix string, limits ContentPostLimits) {
+		if limits.TitleMaxLength > 255 {
+			add("%s_TITLE_MAX_LENGTH must be at most 255, the column size (got %d)", prefix, limits.TitleMaxLength)
+		}
+		if limits.TitleMaxLength < 0 || limits.DescriptionMaxLength < 0 || limits.MaxAttachments < 0 {
+			add("%s_* values must not be negative", prefix)
+		}
+	}
+	checkContentPost("CONTENT_POST", c.Content.Post)
+	for _, t := range contentPostTypes {
+		if limits, ok := c.Content.PostTypes[t]; ok {
+			checkContentPost("CONTENT_"+t, limits)
+		}
+	}
+	if c.Content.PollMaxOptions < 0 || c.Content.PollOptionMaxLength < 0 ||
+		c.Content.CommentMaxLength < 0 || c.Content.CommentMaxAttachments < 0 {
+		add("CONTENT_POLL_* and CONTENT_COMMENT_* values must not be negative")
+	}
+	if c.Content.PollMaxOptions == 1 {
+		add("CONTENT_POLL_MAX_OPTIONS must allow at least 2 options")
+	}
+
+	if r := c.Server.AccessLogSampleRate; r < 0 || r > 1 {
+		add("ACCESS_LOG_SAMPLE_RATE must be between 0 and 1 (got %g)", r)
+	}
+	if r := c.Monitoring.TraceSamplingRate; r < 0 || r > 1 {
+		add("TRACE_SAMPLING_RATE must be between 0 and 1 (got %g)", r)
+	}
+
+	// Half-configured Firebase silently disables push; a credentials file
+	// alone is enough, otherwise all three inline fields are needed.
+	fb := c.Firebase
+	if fb.CredentialsPath == "" && (fb.ProjectID != "" || fb.PrivateKey != "" || fb.ClientEmail != "") &&
+		(fb.ProjectID == "" || fb.PrivateKey == "" || fb.ClientEmail == "") {
+		add("FIREBASE_PROJECT_ID, FIREBASE_PRIVATE_KEY and FIREBASE_CLIENT_EMAIL must be set together " +
+			"(or set FIREBASE_CREDENTIALS_PATH)")
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: problems}
+}
+
+// validIPOrCIDR reports whether s is a single IP address or a CIDR range.
+func validIPOrCIDR(s string) bool {
+	if _, err := netip.ParsePrefix(s); err == nil {
+		return true
+	}
+	_, err := netip.ParseAddr(s)
+	return err == nil
+}
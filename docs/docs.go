@@ -6026,6 +6026,47 @@ const docTemplate = `{
                 }
             }
         },
+        "models.BusinessCardResponse": {
+            "type": "object",
+            "properties": {
+                "address_location": {
+                    "type": "string"
+                },
+                "avatar": {
+                    "$ref": "#/definitions/models.Photo"
+                },
+                "avatar_color": {
+                    "type": "string"
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BusinessCategory"
+                    }
+                },
+                "district": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_verified": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "neighborhood": {
+                    "type": "string"
+                },
+                "province": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
         "models.BusinessCategory": {
             "type": "object",
             "properties": {
@@ -6089,6 +6130,113 @@ const docTemplate = `{
                 }
             }
         },
+        "models.BusinessImportJob": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "created_count": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "failed_count": {
+                    "type": "integer"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "invited_count": {
+                    "type": "integer"
+                },
+                "processed_rows": {
+                    "type": "integer"
+                },
+                "row_errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BusinessImportRowError"
+                    }
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total_rows": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BusinessImportPreview": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BusinessImportRowError"
+                    }
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BusinessImportRow"
+                    }
+                },
+                "total_rows": {
+                    "type": "integer"
+                },
+                "valid_rows": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BusinessImportRow": {
+            "type": "object",
+            "properties": {
+                "business": {
+                    "$ref": "#/definitions/models.CreateBusinessRequest"
+                },
+                "owner_email": {
+                    "type": "string"
+                },
+                "owner_name": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BusinessImportRowError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "integer"
+                }
+            }
+        },
         "models.BusinessInfo": {
             "type": "object",
             "properties": {
@@ -6243,6 +6391,9 @@ const docTemplate = `{
                 "province": {
                     "type": "string"
                 },
+                "responsiveness": {
+                    "$ref": "#/definitions/models.BusinessResponsiveness"
+                },
                 "show_location": {
                     "type": "boolean"
                 },
@@ -6266,6 +6417,20 @@ const docTemplate = `{
                 }
             }
         },
+        "models.BusinessResponsiveness": {
+            "type": "object",
+            "properties": {
+                "is_open": {
+                    "type": "boolean"
+                },
+                "may_be_delayed": {
+                    "type": "boolean"
+                },
+                "median_response_seconds": {
+                    "type": "integer"
+                }
+            }
+        },
         "models.BusinessSearchResult": {
             "type": "object",
             "properties": {
@@ -6350,6 +6515,17 @@ const docTemplate = `{
                 }
             }
         },
+        "models.ClaimBusinessRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
         "models.CommentAttachmentResponse": {
             "type": "object",
             "properties": {
@@ -6449,6 +6625,29 @@ const docTemplate = `{
                 }
             }
         },
+        "models.ContentLimits": {
+            "type": "object",
+            "properties": {
+                "comment_max_attachments": {
+                    "type": "integer"
+                },
+                "comment_max_length": {
+                    "type": "integer"
+                },
+                "poll_max_options": {
+                    "type": "integer"
+                },
+                "poll_option_max_length": {
+                    "type": "integer"
+                },
+                "posts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.PostTypeLimits"
+                    }
+                }
+            }
+        },
         "models.CreateBusinessReportRequest": {
             "type": "object",
             "required": [
@@ -6587,11 +6786,40 @@ const docTemplate = `{
                 },
                 "text": {
                     "type": "string",
-                    "maxLength": 1000,
                     "minLength": 1
                 }
             }
         },
+        "models.CreateFeaturedBusinessRequest": {
+            "type": "object",
+            "required": [
+                "business_id",
+                "ends_at",
+                "province",
+                "starts_at"
+            ],
+            "properties": {
+                "business_id": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 0
+                },
+                "province": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "starts_at": {
+                    "type": "string"
+                }
+            }
+        },
         "models.CreateFeedbackRequest": {
             "type": "object",
             "required": [
@@ -6643,7 +6871,6 @@ const docTemplate = `{
             "properties": {
                 "options": {
                     "type": "array",
-                    "maxItems": 10,
                     "minItems": 2,
                     "items": {
                         "type": "string"
@@ -6784,6 +7011,12 @@ const docTemplate = `{
                         "$ref": "#/definitions/models.DiscoverBusiness"
                     }
                 },
+                "featured": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FeaturedBusinessCard"
+                    }
+                },
                 "posts": {
                     "type": "array",
                     "items": {
@@ -6872,6 +7105,61 @@ const docTemplate = `{
                 "EventStateEnded"
             ]
         },
+        "models.FeaturedBusiness": {
+            "type": "object",
+            "properties": {
+                "business_id": {
+                    "type": "string"
+                },
+                "business_name": {
+                    "type": "string"
+                },
+                "clicks": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "impressions": {
+                    "type": "integer"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "province": {
+                    "type": "string"
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FeaturedBusinessCard": {
+            "type": "object",
+            "properties": {
+                "business": {
+                    "$ref": "#/definitions/models.BusinessCardResponse"
+                },
+                "placement_id": {
+                    "type": "string"
+                }
+            }
+        },
         "models.FeedbackRating": {
             "type": "integer",
             "enum": [
@@ -7133,6 +7421,63 @@ const docTemplate = `{
                 }
             }
         },
+        "models.LifecycleTemplate": {
+            "type": "object",
+            "properties": {
+                "customized": {
+                    "description": "Customized is false while the built-in copy is in use.",
+                    "type": "boolean"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "kind": {
+                    "description": "WELCOME, FIRST_POST_NUDGE or WINBACK",
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "placeholders": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LinkPreview": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "fetched_at": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "site_name": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
         "models.Location": {
             "type": "object",
             "properties": {
@@ -7354,7 +7699,6 @@ const docTemplate = `{
             "properties": {
                 "options": {
                     "type": "array",
-                    "maxItems": 10,
                     "minItems": 2,
                     "items": {
                         "type": "string"
@@ -7520,6 +7864,14 @@ const docTemplate = `{
                 "liked_by_me": {
                     "type": "boolean"
                 },
+                "link_preview": {
+                    "description": "Preview of the first link in the title or description, when any",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.LinkPreview"
+                        }
+                    ]
+                },
                 "location": {
                     "description": "Location",
                     "allOf": [
@@ -7555,6 +7907,10 @@ const docTemplate = `{
                 "title": {
                     "type": "string"
                 },
+                "total_bookmarks": {
+                    "description": "TotalBookmarks is how many users saved the post; owner only, like\nViewCount.",
+                    "type": "integer"
+                },
                 "total_comments": {
                     "description": "Engagement",
                     "type": "integer"
@@ -7599,6 +7955,20 @@ const docTemplate = `{
                 "PostTypePull"
             ]
         },
+        "models.PostTypeLimits": {
+            "type": "object",
+            "properties": {
+                "description_max_length": {
+                    "type": "integer"
+                },
+                "max_attachments": {
+                    "type": "integer"
+                },
+                "title_max_length": {
+                    "type": "integer"
+                }
+            }
+        },
         "models.PostVisibility": {
             "type": "string",
             "enum": [
@@ -7966,11 +8336,52 @@ const docTemplate = `{
                 },
                 "text": {
                     "type": "string",
-                    "maxLength": 1000,
                     "minLength": 1
                 }
             }
         },
+        "models.UpdateFeaturedBusinessRequest": {
+            "type": "object",
+            "properties": {
+                "ends_at": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 0
+                },
+                "province": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "starts_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UpdateLifecycleTemplateRequest": {
+            "type": "object",
+            "required": [
+                "message",
+                "title"
+            ],
+            "properties": {
+                "enabled": {
+                    "description": "Enabled defaults to true; false stops the message for everyone.",
+                    "type": "boolean"
+                },
+                "message": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
         "models.UpdatePostRequest": {
             "type": "object"
         },
@@ -8234,6 +8645,48 @@ const docTemplate = `{
                 }
             }
         },
+        "utils.ListMeta": {
+            "type": "object",
+            "properties": {
+                "counts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "utils.ListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "error": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/utils.ListMeta"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
         "utils.Response": {
             "type": "object",
             "properties": {
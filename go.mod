@@ -39,6 +39,7 @@ require (
 	golang.org/x/crypto v0.51.0
 	golang.org/x/image v0.43.0
 	golang.org/x/net v0.55.0
+	golang.org/x/sync v0.21.0
 	google.golang.org/api v0.231.0
 )
 
@@ -155,7 +156,6 @@ require (
 	golang.org/x/exp v0.0.0-20240110193028-0dcbfd608b1e // indirect
 	golang.org/x/mod v0.36.0 // indirect
 	golang.org/x/oauth2 v0.35.0 // indirect
-	golang.org/x/sync v0.21.0 // indirect
 	golang.org/x/sys v0.45.0 // indirect
 	golang.org/x/text v0.38.0 // indirect
 	golang.org/x/time v0.11.0 // indirect
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/services"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// AccountRecoveryHandler exposes the support queue for trusted contact
+// account recoveries. Every decision is written to the audit log.
+type AccountRecoveryHandler struct {
+	authService  *services.AuthService
+	adminService *services.AdminService
+	validator    *utils.Validator
+	logger       *zap.Logger
+}
+
+func NewAccountRecoveryHandler(authService *services.AuthService, adminService *services.AdminService, validator *utils.Validator, logger *zap.Logger) *AccountRecoveryHandler {
+	return &AccountRecoveryHandler{authService: authService, adminService: adminService, validator: validator, logger: logger}
+}
+
+func (h *AccountRecoveryHandler) sendErr(c *gin.Context, err error) {
+	middleware.RespondWithError(c, err)
+}
+
+// List godoc
+// @Router /admin/account-recoveries [get]
+func (h *AccountRecoveryHandler) List(c *gin.Context) {
+	rows, err := h.authService.ListAccountRecoveries(c.Request.Context(), c.Query("status"), 100)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "ok", gin.H{"recoveries": rows})
+}
+
+// Complete godoc
+// @Router /admin/account-recoveries/{id}/complete [post]
+func (h *AccountRecoveryHandler) Complete(c *gin.Context) {
+	id := c.Param("id")
+	var req models.CompleteAccountRecoveryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+	adminID, _ := middleware.GetUserID(c)
+
+	recovery, err := h.authService.CompleteAccountRecovery(c.Request.Context(), id, adminID, &req)
+	// Wrong codes are audited too: they are how a social engineering
+	// attempt on support shows up.
+	details := map[string]interface{}{"notes": req.Notes, "new_email": req.NewEmail, "succeeded": err == nil}
+	_ = h.adminService.LogAuditAction(c.Request.Context(), adminID, "complete_account_recovery", "account_recovery", id, details, c.ClientIP())
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Account recovered + password reset code sent to the new address", recovery)
+}
+
+// Reject godoc
+// @Router /admin/account-recoveries/{id}/reject [post]
+func (h *AccountRecoveryHandler) Reject(c *gin.Context) {
+	id := c.Param("id")
+	var body reviewBody
+	_ = c.ShouldBindJSON(&body)
+	adminID, _ := middleware.GetUserID(c)
+	if err := h.authService.RejectAccountRecovery(c.Request.Context(), id, adminID, body.Notes); err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	_ = h.adminService.LogAuditAction(c.Request.Context(), adminID, "reject_account_recovery", "account_recovery", id,
+		map[string]interface{}{"notes": body.Notes}, c.ClientIP())
+	utils.SendSuccess(c, http.StatusOK, "Rejected", nil)
+}
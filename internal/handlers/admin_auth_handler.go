@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -22,7 +23,7 @@ type AdminAuthHandler struct {
 	validator      *utils.Validator
 	logger         *zap.Logger
 	cookieCfg      utils.CookieConfig
-	jwtCfg         config.JWTConfig
+	adminCfg       config.AdminConfig
 }
 
 func NewAdminAuthHandler(
@@ -31,7 +32,7 @@ func NewAdminAuthHandler(
 	validator *utils.Validator,
 	logger *zap.Logger,
 	cookieCfg utils.CookieConfig,
-	jwtCfg config.JWTConfig,
+	adminCfg config.AdminConfig,
 ) *AdminAuthHandler {
 	return &AdminAuthHandler{
 		authService:    authService,
@@ -39,7 +40,7 @@ func NewAdminAuthHandler(
 		validator:      validator,
 		logger:         logger,
 		cookieCfg:      cookieCfg,
-		jwtCfg:         jwtCfg,
+		adminCfg:       adminCfg,
 	}
 }
 
@@ -47,8 +48,11 @@ func NewAdminAuthHandler(
 // plus a non-HttpOnly CSRF cookie. The response body omits the token pair —
 // the SPA never sees raw tokens, mitigating XSS exfiltration.
 //
-// Role check: only admin/moderator may use this endpoint. Anything else gets
-// the same generic 401 to avoid role-enumeration via login.
+// Role check: only admin/moderator may use this endpoint. Unknown accounts
+// and wrong passwords get the same generic 401 to avoid enumeration; a right
+// password on a non-admin account, or (with ADMIN_REQUIRE_MFA) an admin
+// account without MFA, gets 403. Dashboard sessions use the shorter
+// ADMIN_ACCESS_TOKEN_DURATION / ADMIN_SESSION_DURATION lifetimes.
 func (h *AdminAuthHandler) AdminLogin(c *gin.Context) {
 	var req models.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -69,11 +73,9 @@ func (h *AdminAuthHandler) AdminLogin(c *gin.Context) {
 		req.DeviceInfo = &deviceInfo
 	}
 
-	resp, err := h.authService.Login(c.Request.Context(), &req)
+	resp, err := h.authService.AdminLogin(c.Request.Context(), &req)
 	if err != nil {
-		// Reuse upstream handler's error mapping by leaning on the same
-		// service error types — wrap caller-facing 401 here for clarity.
-		utils.SendError(c, http.StatusUnauthorized, "Invalid credentials", utils.ErrUnauthorized)
+		sendAdminAuthError(c, err, "Invalid credentials")
 		return
 	}
 
@@ -106,8 +108,8 @@ func (h *AdminAuthHandler) AdminLogin(c *gin.Context) {
 		resp.Tokens.AccessToken,
 		resp.Tokens.RefreshToken,
 		csrf,
-		h.jwtCfg.AccessTokenDuration,
-		h.jwtCfg.RefreshTokenDuration,
+		h.adminCfg.AccessTokenDuration,
+		h.adminCfg.SessionDuration,
 	)
 
 	// Strip raw tokens from the response — admin SPA never reads them.
@@ -126,6 +128,23 @@ func (h *AdminAuthHandler) AdminLogin(c *gin.Context) {
 	})
 }
 
+// sendAdminAuthError answers a failed admin login or MFA step. A 403 (not
+// an admin, or MFA not enabled) is only returned once the password was
+// right, so it is passed through for the SPA to explain; anything else
+// becomes the generic 401.
+func sendAdminAuthError(c *gin.Context, err error, message string) {
+	var appErr *utils.AppError
+	if errors.As(err, &appErr) && appErr.Code == http.StatusForbidden {
+		cause := appErr.Err
+		if cause == nil {
+			cause = utils.ErrForbidden
+		}
+		utils.SendError(c, http.StatusForbidden, appErr.Message, cause)
+		return
+	}
+	utils.SendError(c, http.StatusUnauthorized, message, utils.ErrUnauthorized)
+}
+
 // resolveCustomPerms fetches the custom role for userID and returns its
 // permission list. Returns nil on any error or when no role is assigned.
 func (h *AdminAuthHandler) resolveCustomPerms(c *gin.Context, userID string) []string {
@@ -154,9 +173,9 @@ func (h *AdminAuthHandler) AdminMFAVerify(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.authService.VerifyMFA(c.Request.Context(), &req)
+	resp, err := h.authService.AdminVerifyMFA(c.Request.Context(), &req)
 	if err != nil {
-		utils.SendError(c, http.StatusUnauthorized, "MFA verification failed", utils.ErrUnauthorized)
+		sendAdminAuthError(c, err, "MFA verification failed")
 		return
 	}
 
@@ -182,8 +201,8 @@ func (h *AdminAuthHandler) AdminMFAVerify(c *gin.Context) {
 		resp.Tokens.AccessToken,
 		resp.Tokens.RefreshToken,
 		csrf,
-		h.jwtCfg.AccessTokenDuration,
-		h.jwtCfg.RefreshTokenDuration,
+		h.adminCfg.AccessTokenDuration,
+		h.adminCfg.SessionDuration,
 	)
 
 	resp.Tokens = nil
@@ -226,7 +245,7 @@ func (h *AdminAuthHandler) AdminRefresh(c *gin.Context) {
 		return
 	}
 
-	pair, err := h.authService.RefreshToken(c.Request.Context(), &models.RefreshTokenRequest{
+	pair, err := h.authService.AdminRefreshToken(c.Request.Context(), &models.RefreshTokenRequest{
 		RefreshToken: cookie.Value,
 	})
 	if err != nil {
@@ -248,8 +267,8 @@ func (h *AdminAuthHandler) AdminRefresh(c *gin.Context) {
 		pair.AccessToken,
 		pair.RefreshToken,
 		csrf,
-		h.jwtCfg.AccessTokenDuration,
-		h.jwtCfg.RefreshTokenDuration,
+		h.adminCfg.AccessTokenDuration,
+		h.adminCfg.SessionDuration,
 	)
 
 	utils.SendSuccess(c, http.StatusOK, "Token refreshed", nil)
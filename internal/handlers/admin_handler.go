@@ -480,6 +480,14 @@ func (h *AdminHandler) SetUserVerification(c *gin.Context) {
 }
 
 // SetUserShadowban toggles shadowban on a user. Audit-logged.
+// @Summary Shadowban or restore a user
+// @Description A shadowbanned user keeps posting and sees their own content as normal, but their posts are hidden from other users' feeds, search and discover, and their actions no longer notify anyone.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param user_id path string true "User ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
 // @Router /admin/users/{user_id}/shadowban [post]
 func (h *AdminHandler) SetUserShadowban(c *gin.Context) {
 	userID := c.Param("user_id")
@@ -493,7 +501,7 @@ func (h *AdminHandler) SetUserShadowban(c *gin.Context) {
 		return
 	}
 	if err := h.adminService.SetShadowban(c.Request.Context(), userID, body.Enabled, adminID, body.Reason); err != nil {
-		utils.SendError(c, http.StatusInternalServerError, "Failed", err)
+		h.handleError(c, err)
 		return
 	}
 	action := "shadowban_user"
@@ -530,6 +538,62 @@ func (h *AdminHandler) ForceLogoutUser(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusOK, "All sessions revoked", nil)
 }
 
+// ListLockedAccounts godoc
+// @Summary List accounts locked by failed logins
+// @Description Users locked after repeated wrong passwords, and accounts the login guard is currently delaying or asking for a CAPTCHA
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=services.LockedAccounts}
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /admin/security/locked-accounts [get]
+func (h *AdminHandler) ListLockedAccounts(c *gin.Context) {
+	accounts, err := h.adminService.ListLockedAccounts(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Locked accounts retrieved", accounts)
+}
+
+// ClearLoginLock lets a user locked out by failed logins sign in again.
+// Admin suspensions are not lifted. Audit-logged.
+// @Router /admin/users/{user_id}/login-lock [delete]
+func (h *AdminHandler) ClearLoginLock(c *gin.Context) {
+	userID := c.Param("user_id")
+	adminID, _ := middleware.GetUserID(c)
+	email, err := h.adminService.ClearLoginLock(c.Request.Context(), userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	if err := h.adminService.LogAuditAction(c.Request.Context(), adminID, "clear_login_lock", "user", userID,
+		map[string]interface{}{"email": email}, c.ClientIP()); err != nil {
+		h.logger.Warn("audit log failed", zap.Error(err))
+	}
+	utils.SendSuccess(c, http.StatusOK, "Login lock cleared", nil)
+}
+
+// ClearEmailSuppression re-enables email delivery for a user whose address
+// was suppressed after a bounce or complaint. Audit-logged.
+// @Router /admin/users/{user_id}/email-suppression [delete]
+func (h *AdminHandler) ClearEmailSuppression(c *gin.Context) {
+	userID := c.Param("user_id")
+	adminID, _ := middleware.GetUserID(c)
+	email, err := h.adminService.ClearUserEmailSuppression(c.Request.Context(), userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	if err := h.adminService.LogAuditAction(c.Request.Context(), adminID, "clear_email_suppression", "user", userID,
+		map[string]interface{}{"email": email}, c.ClientIP()); err != nil {
+		h.logger.Warn("audit log failed", zap.Error(err))
+	}
+	utils.SendSuccess(c, http.StatusOK, "Email suppression cleared", nil)
+}
+
 // @Router /admin/users/{user_id} [delete]
 // ForceDisableUserMFA admin-resets a user's MFA. Used to unlock users who
 // lost their authenticator. No password required (admin authority is enough).
@@ -872,12 +936,12 @@ func (h *AdminHandler) ListPostReports(c *gin.Context) {
 
 // GetPostReport godoc
 // @Summary Get post report by ID
-// @Description Get a single post report by ID
+// @Description Get a single post report by ID with the reported content, its author, the author's prior violations, other reports on the same item and the moderation actions available
 // @Tags admin
 // @Produce json
 // @Security BearerAuth
 // @Param report_id path string true "Report ID"
-// @Success 200 {object} utils.Response{data=models.AdminPostReportResponse}
+// @Success 200 {object} utils.Response{data=models.AdminPostReportDetail}
 // @Failure 404 {object} utils.Response
 // @Router /admin/reports/posts/{report_id} [get]
 func (h *AdminHandler) GetPostReport(c *gin.Context) {
@@ -929,12 +993,12 @@ func (h *AdminHandler) ListCommentReports(c *gin.Context) {
 
 // GetCommentReport godoc
 // @Summary Get comment report by ID
-// @Description Get a single comment report by ID
+// @Description Get a single comment report by ID with the reported content, its author, the author's prior violations, other reports on the same item and the moderation actions available
 // @Tags admin
 // @Produce json
 // @Security BearerAuth
 // @Param report_id path string true "Report ID"
-// @Success 200 {object} utils.Response{data=models.AdminCommentReportResponse}
+// @Success 200 {object} utils.Response{data=models.AdminCommentReportDetail}
 // @Failure 404 {object} utils.Response
 // @Router /admin/reports/comments/{report_id} [get]
 func (h *AdminHandler) GetCommentReport(c *gin.Context) {
@@ -982,12 +1046,12 @@ func (h *AdminHandler) ListUserReports(c *gin.Context) {
 
 // GetUserReport godoc
 // @Summary Get user report by ID
-// @Description Get a single user report by ID
+// @Description Get a single user report by ID with the reported content, its author, the author's prior violations, other reports on the same item and the moderation actions available
 // @Tags admin
 // @Produce json
 // @Security BearerAuth
 // @Param report_id path string true "Report ID"
-// @Success 200 {object} utils.Response{data=models.AdminUserReportResponse}
+// @Success 200 {object} utils.Response{data=models.AdminUserReportDetail}
 // @Failure 404 {object} utils.Response
 // @Router /admin/reports/users/{report_id} [get]
 func (h *AdminHandler) GetUserReport(c *gin.Context) {
@@ -1035,12 +1099,12 @@ func (h *AdminHandler) ListBusinessReports(c *gin.Context) {
 
 // GetBusinessReport godoc
 // @Summary Get business report by ID
-// @Description Get a single business report by ID
+// @Description Get a single business report by ID with the reported content, its author, the author's prior violations, other reports on the same item and the moderation actions available
 // @Tags admin
 // @Produce json
 // @Security BearerAuth
 // @Param report_id path string true "Report ID"
-// @Success 200 {object} utils.Response{data=models.AdminBusinessReportResponse}
+// @Success 200 {object} utils.Response{data=models.AdminBusinessReportDetail}
 // @Failure 404 {object} utils.Response
 // @Router /admin/reports/businesses/{report_id} [get]
 func (h *AdminHandler) GetBusinessReport(c *gin.Context) {
@@ -1057,6 +1121,61 @@ func (h *AdminHandler) GetBusinessReport(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusOK, "Business report retrieved successfully", report)
 }
 
+// ListMessageReports godoc
+// @Summary List chat message reports
+// @Description List chat message reports with the reported message snapshot
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter by status (PENDING, REVIEWING, RESOLVED, REJECTED)"
+// @Param message_id query string false "Filter by message"
+// @Param user_id query string false "Filter by message sender"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} utils.Response{data=models.PaginatedResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /admin/reports/messages [get]
+func (h *AdminHandler) ListMessageReports(c *gin.Context) {
+	var filter models.AdminReportFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.SendBadRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	result, err := h.adminService.ListMessageReports(c.Request.Context(), &filter)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Message reports retrieved successfully", result)
+}
+
+// GetMessageReport godoc
+// @Summary Get chat message report by ID
+// @Description Get a single chat message report by ID with the reported content, its author, the author's prior violations, other reports on the same item and the moderation actions available
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param report_id path string true "Report ID"
+// @Success 200 {object} utils.Response{data=models.AdminMessageReportDetail}
+// @Failure 404 {object} utils.Response
+// @Router /admin/reports/messages/{report_id} [get]
+func (h *AdminHandler) GetMessageReport(c *gin.Context) {
+	reportID := c.Param("report_id")
+	if _, err := uuid.Parse(reportID); err != nil {
+		utils.SendBadRequest(c, "Invalid report ID format", err)
+		return
+	}
+	report, err := h.adminService.GetMessageReport(c.Request.Context(), reportID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Message report retrieved successfully", report)
+}
+
 // UpdateReportStatus godoc
 // @Summary Update report status
 // @Description Update a report's status
@@ -1064,7 +1183,7 @@ func (h *AdminHandler) GetBusinessReport(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param report_type path string true "Report type (posts, comments, users, businesses)"
+// @Param report_type path string true "Report type (posts, comments, users, businesses, messages)"
 // @Param report_id path string true "Report ID"
 // @Param request body models.UpdateReportStatusRequest true "Status update"
 // @Success 200 {object} utils.Response
@@ -1092,6 +1211,73 @@ func (h *AdminHandler) UpdateReportStatus(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusOK, "Report status updated successfully", nil)
 }
 
+// ListReportGroups godoc
+// @Summary List reports grouped by item
+// @Description One row per reported item with report and reporter counts, open count and a reasons breakdown, most open reports first
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param report_type path string true "Report type (posts, comments, users, businesses, messages)"
+// @Param status query string false "Only count reports with this status"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} utils.Response{data=models.PaginatedResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /admin/reports/grouped/{report_type} [get]
+func (h *AdminHandler) ListReportGroups(c *gin.Context) {
+	var filter models.AdminReportFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.SendBadRequest(c, "Invalid query parameters", err)
+		return
+	}
+
+	result, err := h.adminService.ListReportGroups(c.Request.Context(), c.Param("report_type"), &filter)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Reports retrieved successfully", result)
+}
+
+// ResolveReportGroup godoc
+// @Summary Resolve all reports on an item
+// @Description Closes every open report on the item with one status
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param report_type path string true "Report type (posts, comments, users, businesses, messages)"
+// @Param target_id path string true "Reported item ID"
+// @Param request body models.AdminResolveReportGroupRequest true "Status"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /admin/reports/grouped/{report_type}/{target_id}/status [put]
+func (h *AdminHandler) ResolveReportGroup(c *gin.Context) {
+	targetID := c.Param("target_id")
+	if _, err := uuid.Parse(targetID); err != nil {
+		utils.SendBadRequest(c, "Invalid target ID format", err)
+		return
+	}
+	adminID, _ := middleware.GetUserID(c)
+
+	var req models.AdminResolveReportGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendBadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	closed, err := h.adminService.ResolveReportGroup(c.Request.Context(), c.Param("report_type"), targetID, req.Status, adminID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Reports resolved successfully", gin.H{"closed": closed})
+}
+
 // ListFeedback godoc
 // @Summary List user feedback
 // @Description List all user feedback with pagination and optional type filter
@@ -1207,6 +1393,37 @@ func (h *AdminHandler) UpdateBusinessStatus(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusOK, "Business status updated successfully", nil)
 }
 
+// SetBusinessCategories godoc
+// @Summary Recategorize a business
+// @Description Replace the business's categories; an empty list clears them
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param business_id path string true "Business ID"
+// @Param request body models.SetBusinessCategoriesRequest true "Categories"
+// @Success 200 {object} utils.Response{data=[]string}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/businesses/{business_id}/categories [put]
+func (h *AdminHandler) SetBusinessCategories(c *gin.Context) {
+	businessID := c.Param("business_id")
+	adminID, _ := middleware.GetUserID(c)
+
+	var req models.SetBusinessCategoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendBadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	categories, err := h.adminService.SetBusinessCategories(c.Request.Context(), businessID, req.CategoryIDs, adminID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Business categories updated successfully", categories)
+}
+
 // DeleteBusiness godoc
 // @Summary Delete a business
 // @Description Soft delete a business
@@ -1304,27 +1521,30 @@ func (h *AdminHandler) SendTargetedNotification(c *gin.Context) {
 }
 
 func (h *AdminHandler) handleError(c *gin.Context, err error) {
-	if appErr, ok := err.(*utils.AppError); ok {
-		utils.SendError(c, appErr.Code, appErr.Message, appErr.Err)
-		return
-	}
-	h.logger.Error("Unhandled error in admin handler", zap.Error(err))
-	utils.SendInternalServerError(c, "An error occurred", err)
+	middleware.RespondWithError(c, err)
 }
 
 // ListAuditLogs returns paginated audit log entries
+// @Success      200 {object} utils.ListResponse{data=[]models.AuditLog}
+// @Router       /admin/audit-logs [get]
 func (h *AdminHandler) ListAuditLogs(c *gin.Context) {
 	var filter models.AuditLogFilter
 	if err := c.ShouldBindQuery(&filter); err != nil {
 		utils.SendBadRequest(c, "Invalid query parameters", err)
 		return
 	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.Limit <= 0 || filter.Limit > 200 {
+		filter.Limit = 50
+	}
 	items, total, err := h.adminService.ListAuditLogs(c.Request.Context(), &filter)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
-	utils.SendSuccess(c, http.StatusOK, "Audit logs retrieved", gin.H{
+	utils.SendList(c, "Audit logs retrieved", items, utils.PageMeta(filter.Page, filter.Limit, total), gin.H{
 		"logs":        items,
 		"total_count": total,
 		"page":        filter.Page,
@@ -1415,13 +1635,15 @@ func (h *AdminHandler) CreateIPBan(c *gin.Context) {
 }
 
 // ListIPBans returns all IP bans
+// @Success      200 {object} utils.ListResponse{data=[]models.IPBan}
+// @Router       /admin/bans/ip [get]
 func (h *AdminHandler) ListIPBans(c *gin.Context) {
 	page := 1
 	limit := 50
 	if v, err := strconv.Atoi(c.Query("page")); err == nil && v > 0 {
 		page = v
 	}
-	if v, err := strconv.Atoi(c.Query("limit")); err == nil && v > 0 {
+	if v, err := strconv.Atoi(c.Query("limit")); err == nil && v > 0 && v <= 100 {
 		limit = v
 	}
 	items, total, err := h.adminService.ListIPBans(c.Request.Context(), page, limit)
@@ -1429,7 +1651,7 @@ func (h *AdminHandler) ListIPBans(c *gin.Context) {
 		h.handleError(c, err)
 		return
 	}
-	utils.SendSuccess(c, http.StatusOK, "IP bans retrieved", gin.H{"bans": items, "total_count": total})
+	utils.SendList(c, "IP bans retrieved", items, utils.PageMeta(page, limit, total), gin.H{"bans": items, "total_count": total})
 }
 
 // DeleteIPBan removes an IP ban
@@ -1459,13 +1681,15 @@ func (h *AdminHandler) CreateDeviceBan(c *gin.Context) {
 }
 
 // ListDeviceBans returns all device bans
+// @Success      200 {object} utils.ListResponse{data=[]models.DeviceBan}
+// @Router       /admin/bans/devices [get]
 func (h *AdminHandler) ListDeviceBans(c *gin.Context) {
 	page := 1
 	limit := 50
 	if v, err := strconv.Atoi(c.Query("page")); err == nil && v > 0 {
 		page = v
 	}
-	if v, err := strconv.Atoi(c.Query("limit")); err == nil && v > 0 {
+	if v, err := strconv.Atoi(c.Query("limit")); err == nil && v > 0 && v <= 100 {
 		limit = v
 	}
 	items, total, err := h.adminService.ListDeviceBans(c.Request.Context(), page, limit)
@@ -1473,7 +1697,7 @@ func (h *AdminHandler) ListDeviceBans(c *gin.Context) {
 		h.handleError(c, err)
 		return
 	}
-	utils.SendSuccess(c, http.StatusOK, "Device bans retrieved", gin.H{"bans": items, "total_count": total})
+	utils.SendList(c, "Device bans retrieved", items, utils.PageMeta(page, limit, total), gin.H{"bans": items, "total_count": total})
 }
 
 // DeleteDeviceBan removes a device ban
@@ -1574,6 +1798,8 @@ func (h *AdminHandler) GetInboxCounts(c *gin.Context) {
 
 // ListBroadcastHistory returns past admin broadcast notifications grouped by
 // (title, message, sent_at minute). Each row shows recipient count.
+// @Success      200 {object} utils.ListResponse{data=[]models.BroadcastHistoryItem}
+// @Router       /admin/notifications/history [get]
 func (h *AdminHandler) ListBroadcastHistory(c *gin.Context) {
 	limit := 50
 	if v, err := strconv.Atoi(c.Query("limit")); err == nil && v > 0 && v <= 200 {
@@ -1584,7 +1810,7 @@ func (h *AdminHandler) ListBroadcastHistory(c *gin.Context) {
 		h.handleError(c, err)
 		return
 	}
-	utils.SendSuccess(c, http.StatusOK, "Broadcast history retrieved", gin.H{
+	utils.SendList(c, "Broadcast history retrieved", items, utils.ListMeta{Limit: limit}, gin.H{
 		"items": items,
 	})
 }
@@ -24,6 +24,8 @@ func NewAppLogHandler(repo repositories.AppLogRepository, logger *zap.Logger) *A
 
 // List returns paginated log entries with optional level / request_id /
 // free-text filters. Empty `level` returns all (warn+ in practice).
+// @Success      200 {object} utils.ListResponse{data=[]repositories.AppLogEntry}
+// @Router       /admin/logs [get]
 func (h *AppLogHandler) List(c *gin.Context) {
 	level := strings.ToLower(strings.TrimSpace(c.Query("level")))
 	switch level {
@@ -34,6 +36,9 @@ func (h *AppLogHandler) List(c *gin.Context) {
 
 	page := atoiOr(c.Query("page"), 1)
 	limit := atoiOr(c.Query("limit"), 50)
+	if limit > 200 {
+		limit = 50
+	}
 
 	filter := repositories.AppLogFilter{
 		Level:     level,
@@ -59,7 +64,7 @@ func (h *AppLogHandler) List(c *gin.Context) {
 		totalPages = (total + filter.Limit - 1) / filter.Limit
 	}
 
-	utils.SendSuccess(c, http.StatusOK, "Logs retrieved", gin.H{
+	utils.SendList(c, "Logs retrieved", entries, utils.PageMeta(filter.Page, filter.Limit, int64(total)), gin.H{
 		"items":       entries,
 		"total_count": total,
 		"page":        filter.Page,
@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"net/http"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/hamsaya/backend/internal/middleware"
@@ -635,6 +634,250 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusOK, "Password changed successfully", nil)
 }
 
+// RequestEmailChange godoc
+// @Summary Change email address
+// @Description Sends a confirmation code to the new address and a notice with a revert link to the current one. The email only changes after ConfirmEmailChange.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.ChangeEmailRequest true "New email and current password"
+// @Success 200 {object} utils.Response{data=models.EmailChangeResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /users/me/email/change [post]
+func (h *AuthHandler) RequestEmailChange(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	var req models.ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	resp, err := h.authService.RequestEmailChange(c.Request.Context(), userID.(string), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Confirmation code sent to the new email address", resp)
+}
+
+// ConfirmEmailChange godoc
+// @Summary Confirm email change
+// @Description Completes an email change with the code sent to the new address. Every session is revoked; the client must sign in again.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.ConfirmEmailChangeRequest true "Confirmation code"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /users/me/email/confirm [post]
+func (h *AuthHandler) ConfirmEmailChange(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	var req models.ConfirmEmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	if err := h.authService.ConfirmEmailChange(c.Request.Context(), userID.(string), &req); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Email changed successfully; please sign in again", nil)
+}
+
+// RevertEmailChange godoc
+// @Summary Revert email change
+// @Description Uses the token from the notice sent to the old address to cancel a pending email change or restore the old address. Every session is revoked.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.RevertEmailChangeRequest true "Revert token"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /auth/email/revert [post]
+func (h *AuthHandler) RevertEmailChange(c *gin.Context) {
+	var req models.RevertEmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	if err := h.authService.RevertEmailChange(c.Request.Context(), &req); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Email change reverted", nil)
+}
+
+// GetTrustedContact godoc
+// @Summary Get trusted contact
+// @Description Returns the user who receives account recovery codes for the caller, or null when none is set.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=models.TrustedContactResponse}
+// @Router /users/me/trusted-contact [get]
+func (h *AuthHandler) GetTrustedContact(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	contact, err := h.authService.GetTrustedContact(c.Request.Context(), userID.(string))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Trusted contact retrieved", contact)
+}
+
+// SetTrustedContact godoc
+// @Summary Set trusted contact
+// @Description Names another user as the one who receives a recovery code if the caller loses access to their email. The contact is notified.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.SetTrustedContactRequest true "Contact and current password"
+// @Success 200 {object} utils.Response{data=models.TrustedContactResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /users/me/trusted-contact [put]
+func (h *AuthHandler) SetTrustedContact(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	var req models.SetTrustedContactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	contact, err := h.authService.SetTrustedContact(c.Request.Context(), userID.(string), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Trusted contact set", contact)
+}
+
+// RemoveTrustedContact godoc
+// @Summary Remove trusted contact
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Router /users/me/trusted-contact [delete]
+func (h *AuthHandler) RemoveTrustedContact(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	if err := h.authService.RemoveTrustedContact(c.Request.Context(), userID.(string)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Trusted contact removed", nil)
+}
+
+// StartAccountRecovery godoc
+// @Summary Start account recovery
+// @Description For users who lost access to their email: sends a one-time code to the account's trusted contact in the app. The user gets the code from the contact and gives it to support, who completes the recovery. The response is the same whether or not the account exists.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.StartAccountRecoveryRequest true "Account email"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /auth/recovery/trusted-contact [post]
+func (h *AuthHandler) StartAccountRecovery(c *gin.Context) {
+	var req models.StartAccountRecoveryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	if err := h.authService.StartAccountRecovery(c.Request.Context(), &req, c.ClientIP()); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "If this account has a trusted contact, they have been sent a recovery code. Get the code from them and contact support.", nil)
+}
+
+// CancelAccountRecovery godoc
+// @Summary Cancel account recovery
+// @Description Cancels a recovery started for the caller's account, e.g. one the caller didn't start.
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /users/me/recovery [delete]
+func (h *AuthHandler) CancelAccountRecovery(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	if err := h.authService.CancelAccountRecovery(c.Request.Context(), userID.(string)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Account recovery cancelled", nil)
+}
+
 // GetActiveSessions godoc
 // @Summary Get active sessions
 // @Description Get all active sessions for the authenticated user
@@ -663,25 +906,7 @@ func (h *AuthHandler) GetActiveSessions(c *gin.Context) {
 
 // handleError handles service errors and sends appropriate HTTP responses
 func (h *AuthHandler) handleError(c *gin.Context, err error) {
-	// Check if it's an AppError
-	if appErr, ok := err.(*utils.AppError); ok {
-		utils.SendError(c, appErr.Code, appErr.Message, appErr.Err)
-		return
-	}
-
-	// Check for specific error types
-	errMsg := err.Error()
-	switch {
-	case strings.Contains(errMsg, "not found"):
-		utils.SendError(c, http.StatusNotFound, errMsg, err)
-	case strings.Contains(errMsg, "already exists"):
-		utils.SendError(c, http.StatusConflict, errMsg, err)
-	case strings.Contains(errMsg, "unauthorized") || strings.Contains(errMsg, "invalid"):
-		utils.SendError(c, http.StatusUnauthorized, errMsg, err)
-	default:
-		h.logger.Error("Unhandled error in auth handler", zap.Error(err))
-		utils.SendError(c, http.StatusInternalServerError, "An error occurred", err)
-	}
+	middleware.RespondWithError(c, err)
 }
 
 // AcceptAdminInvite godoc
@@ -726,6 +951,7 @@ func (h *AuthHandler) RegisterRoutes(router *gin.RouterGroup, authMiddleware ...
 		auth.POST("/forgot-password", h.ForgotPassword)
 		auth.POST("/verify-reset-code", h.VerifyResetCode)
 		auth.POST("/reset-password", h.ResetPassword)
+		auth.POST("/email/revert", h.RevertEmailChange)
 		auth.POST("/mfa/verify", h.VerifyMFA)
 		auth.POST("/mfa/verify-backup-code", h.VerifyMFAWithBackupCode)
 
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
 	"github.com/hamsaya/backend/internal/services"
 	"github.com/hamsaya/backend/internal/utils"
 	"go.uber.org/zap"
@@ -74,7 +75,7 @@ func (h *BackupHandler) Download(c *gin.Context) {
 	}
 	stream, err := h.svc.OpenDownload(c.Request.Context(), id)
 	if err != nil {
-		utils.SendError(c, http.StatusNotFound, err.Error(), utils.ErrNotFound)
+		middleware.RespondWithError(c, err)
 		return
 	}
 	defer func() { _ = stream.Reader.Close() }()
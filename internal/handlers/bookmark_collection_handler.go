@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/services"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// BookmarkCollectionHandler exposes the caller's bookmark folders under
+// /api/v1/users/me/bookmark-collections. Filtering bookmarks by folder is
+// GET /users/me/bookmarks?collection_id=... on the post handler.
+type BookmarkCollectionHandler struct {
+	service   *services.BookmarkCollectionService
+	validator *utils.Validator
+	logger    *zap.Logger
+}
+
+// NewBookmarkCollectionHandler wires the handler.
+func NewBookmarkCollectionHandler(
+	service *services.BookmarkCollectionService,
+	validator *utils.Validator,
+	logger *zap.Logger,
+) *BookmarkCollectionHandler {
+	return &BookmarkCollectionHandler{
+		service:   service,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (h *BookmarkCollectionHandler) sendErr(c *gin.Context, err error) {
+	middleware.RespondWithError(c, err)
+}
+
+func (h *BookmarkCollectionHandler) currentUser(c *gin.Context) (string, bool) {
+	v, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return "", false
+	}
+	return v.(string), true
+}
+
+// ListCollections returns the caller's collections with bookmark counts.
+// @Tags         bookmark-collections
+// @Security     BearerAuth
+// @Success      200 {object} utils.Response{data=models.BookmarkCollectionsResponse}
+// @Router       /users/me/bookmark-collections [get]
+func (h *BookmarkCollectionHandler) ListCollections(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	out, err := h.service.List(c.Request.Context(), userID)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Collections retrieved successfully", out)
+}
+
+// CreateCollection adds a named collection.
+// @Tags         bookmark-collections
+// @Security     BearerAuth
+// @Param        request body models.BookmarkCollectionRequest true "Collection"
+// @Success      201 {object} utils.Response{data=models.BookmarkCollection}
+// @Failure      409 {object} utils.Response
+// @Router       /users/me/bookmark-collections [post]
+func (h *BookmarkCollectionHandler) CreateCollection(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.BookmarkCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	collection, err := h.service.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusCreated, "Collection created successfully", collection)
+}
+
+// RenameCollection changes a collection's name.
+// @Tags         bookmark-collections
+// @Security     BearerAuth
+// @Param        collection_id path string true "Collection id"
+// @Param        request body models.BookmarkCollectionRequest true "New name"
+// @Success      200 {object} utils.Response{data=models.BookmarkCollection}
+// @Failure      409 {object} utils.Response
+// @Router       /users/me/bookmark-collections/{collection_id} [put]
+func (h *BookmarkCollectionHandler) RenameCollection(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.BookmarkCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	collection, err := h.service.Rename(c.Request.Context(), c.Param("collection_id"), userID, &req)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Collection renamed successfully", collection)
+}
+
+// DeleteCollection removes a collection. Its bookmarks are kept, unfiled.
+// @Tags         bookmark-collections
+// @Security     BearerAuth
+// @Param        collection_id path string true "Collection id"
+// @Success      200 {object} utils.Response
+// @Router       /users/me/bookmark-collections/{collection_id} [delete]
+func (h *BookmarkCollectionHandler) DeleteCollection(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Delete(c.Request.Context(), c.Param("collection_id"), userID); err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Collection deleted successfully", nil)
+}
+
+// AssignBookmark moves a bookmarked post into a collection; a null
+// collection_id takes it out of its collection.
+// @Tags         bookmark-collections
+// @Security     BearerAuth
+// @Param        post_id path string true "Bookmarked post id"
+// @Param        request body models.AssignBookmarkRequest true "Target collection"
+// @Success      200 {object} utils.Response
+// @Failure      404 {object} utils.Response
+// @Router       /users/me/bookmarks/{post_id}/collection [put]
+func (h *BookmarkCollectionHandler) AssignBookmark(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.AssignBookmarkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	if err := h.service.AssignBookmark(c.Request.Context(), userID, c.Param("post_id"), req.CollectionID); err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Bookmark moved successfully", nil)
+}
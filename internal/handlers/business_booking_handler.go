@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/services"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// BusinessBookingHandler exposes the booking request endpoints under
+// /api/v1/businesses/:business_id/bookings.
+type BusinessBookingHandler struct {
+	service   *services.BusinessBookingService
+	validator *utils.Validator
+	logger    *zap.Logger
+}
+
+// NewBusinessBookingHandler wires the handler.
+func NewBusinessBookingHandler(
+	service *services.BusinessBookingService,
+	validator *utils.Validator,
+	logger *zap.Logger,
+) *BusinessBookingHandler {
+	return &BusinessBookingHandler{
+		service:   service,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (h *BusinessBookingHandler) sendErr(c *gin.Context, err error) {
+	middleware.RespondWithError(c, err)
+}
+
+func (h *BusinessBookingHandler) currentUser(c *gin.Context) (string, bool) {
+	v, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return "", false
+	}
+	return v.(string), true
+}
+
+// RequestBooking submits a booking request to a business.
+// @Tags         business-bookings
+// @Security     BearerAuth
+// @Param        business_id path string true "Business profile id"
+// @Param        request body models.CreateBookingRequest true "Booking"
+// @Success      201 {object} utils.Response{data=models.BusinessBooking}
+// @Router       /businesses/{business_id}/bookings [post]
+func (h *BusinessBookingHandler) RequestBooking(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	booking, err := h.service.Request(c.Request.Context(), c.Param("business_id"), userID, &req)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusCreated, "Booking requested", booking)
+}
+
+// respond handles accept/decline, which share an optional note body.
+func (h *BusinessBookingHandler) respond(c *gin.Context, accept bool) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.RespondBookingRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+			return
+		}
+		if err := h.validator.Validate(&req); err != nil {
+			utils.SendError(c, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+	}
+
+	respond, msg := h.service.Accept, "Booking accepted"
+	if !accept {
+		respond, msg = h.service.Decline, "Booking declined"
+	}
+	booking, err := respond(c.Request.Context(), c.Param("business_id"), c.Param("booking_id"), userID, &req)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, msg, booking)
+}
+
+// AcceptBooking confirms a pending booking (owner only).
+// @Tags         business-bookings
+// @Security     BearerAuth
+// @Param        business_id path string true "Business profile id"
+// @Param        booking_id path string true "Booking id"
+// @Param        request body models.RespondBookingRequest false "Optional note to the customer"
+// @Success      200 {object} utils.Response{data=models.BusinessBooking}
+// @Failure      409 {object} utils.Response
+// @Router       /businesses/{business_id}/bookings/{booking_id}/accept [post]
+func (h *BusinessBookingHandler) AcceptBooking(c *gin.Context) {
+	h.respond(c, true)
+}
+
+// DeclineBooking rejects a pending booking (owner only).
+// @Tags         business-bookings
+// @Security     BearerAuth
+// @Param        business_id path string true "Business profile id"
+// @Param        booking_id path string true "Booking id"
+// @Param        request body models.RespondBookingRequest false "Optional note to the customer"
+// @Success      200 {object} utils.Response{data=models.BusinessBooking}
+// @Failure      409 {object} utils.Response
+// @Router       /businesses/{business_id}/bookings/{booking_id}/decline [post]
+func (h *BusinessBookingHandler) DeclineBooking(c *gin.Context) {
+	h.respond(c, false)
+}
+
+// CancelBooking withdraws the caller's pending or accepted booking.
+// @Tags         business-bookings
+// @Security     BearerAuth
+// @Param        business_id path string true "Business profile id"
+// @Param        booking_id path string true "Booking id"
+// @Success      200 {object} utils.Response{data=models.BusinessBooking}
+// @Failure      409 {object} utils.Response
+// @Router       /businesses/{business_id}/bookings/{booking_id}/cancel [post]
+func (h *BusinessBookingHandler) CancelBooking(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	booking, err := h.service.Cancel(c.Request.Context(), c.Param("business_id"), c.Param("booking_id"), userID)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Booking cancelled", booking)
+}
+
+// ListBookings returns the owner's booking calendar grouped by day.
+// @Tags         business-bookings
+// @Security     BearerAuth
+// @Param        business_id path string true "Business profile id"
+// @Param        status query string false "Comma-separated statuses (PENDING,ACCEPTED,DECLINED,CANCELLED)"
+// @Param        from query string false "Range start, YYYY-MM-DD or RFC3339 (default today)"
+// @Param        to query string false "Range end, YYYY-MM-DD inclusive or RFC3339 (default from + 31 days, max 92 days)"
+// @Success      200 {object} utils.Response
+// @Router       /businesses/{business_id}/bookings [get]
+func (h *BusinessBookingHandler) ListBookings(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	statuses, err := services.ParseBookingStatuses(c.Query("status"))
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	from, err := services.ParseBookingDate(c.Query("from"), false)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	to, err := services.ParseBookingDate(c.Query("to"), true)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+
+	filter := &models.BookingFilter{Statuses: statuses, From: from, To: to}
+	days, total, err := h.service.Calendar(c.Request.Context(), c.Param("business_id"), userID, filter)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Bookings", gin.H{
+		"days":  days,
+		"total": total,
+		"from":  filter.From,
+		"to":    filter.To,
+	})
+}
+
+// ListMyBookings returns the caller's own booking requests.
+// @Tags         business-bookings
+// @Security     BearerAuth
+// @Param        limit query int false "Page size (default 20, max 100)"
+// @Param        offset query int false "Offset (default 0)"
+// @Success      200 {object} utils.ListResponse
+// @Router       /businesses/bookings/me [get]
+func (h *BusinessBookingHandler) ListMyBookings(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	limit := 20
+	offset := 0
+	if v := c.Query("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 100 {
+			limit = n
+		}
+	}
+	if v := c.Query("offset"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			offset = n
+		}
+	}
+
+	bookings, total, err := h.service.ListMine(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendList(c, "Bookings", bookings, utils.OffsetMeta(limit, offset, total), gin.H{
+		"items":  bookings,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/services"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// BusinessBranchHandler exposes a business's branches under
+// /api/v1/businesses/:business_id/branches. Listing is public; editing is
+// owner-only.
+type BusinessBranchHandler struct {
+	service   *services.BusinessBranchService
+	validator *utils.Validator
+	logger    *zap.Logger
+}
+
+// NewBusinessBranchHandler wires the handler.
+func NewBusinessBranchHandler(
+	service *services.BusinessBranchService,
+	validator *utils.Validator,
+	logger *zap.Logger,
+) *BusinessBranchHandler {
+	return &BusinessBranchHandler{
+		service:   service,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (h *BusinessBranchHandler) sendErr(c *gin.Context, err error) {
+	middleware.RespondWithError(c, err)
+}
+
+func (h *BusinessBranchHandler) currentUser(c *gin.Context) (string, bool) {
+	v, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return "", false
+	}
+	return v.(string), true
+}
+
+// ListBranches returns the business's branches in display order.
+// @Tags         businesses
+// @Param        business_id path string true "Business profile id"
+// @Success      200 {object} utils.Response{data=[]models.BusinessBranch}
+// @Router       /businesses/{business_id}/branches [get]
+func (h *BusinessBranchHandler) ListBranches(c *gin.Context) {
+	branches, err := h.service.List(c.Request.Context(), c.Param("business_id"))
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Branches", branches)
+}
+
+// CreateBranch adds a branch (owner only).
+// @Tags         businesses
+// @Security     BearerAuth
+// @Param        business_id path string true "Business profile id"
+// @Param        request body models.CreateBusinessBranchRequest true "Branch"
+// @Success      201 {object} utils.Response{data=models.BusinessBranch}
+// @Router       /businesses/{business_id}/branches [post]
+func (h *BusinessBranchHandler) CreateBranch(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateBusinessBranchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	branch, err := h.service.Create(c.Request.Context(), c.Param("business_id"), userID, &req)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusCreated, "Branch created", branch)
+}
+
+// UpdateBranch edits a branch (owner only).
+// @Tags         businesses
+// @Security     BearerAuth
+// @Param        business_id path string true "Business profile id"
+// @Param        branch_id path string true "Branch id"
+// @Param        request body models.UpdateBusinessBranchRequest true "Update"
+// @Success      200 {object} utils.Response{data=models.BusinessBranch}
+// @Router       /businesses/{business_id}/branches/{branch_id} [put]
+func (h *BusinessBranchHandler) UpdateBranch(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateBusinessBranchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	branch, err := h.service.Update(c.Request.Context(), c.Param("business_id"), c.Param("branch_id"), userID, &req)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Branch updated", branch)
+}
+
+// DeleteBranch removes a branch (owner only). Posts from it stay up.
+// @Tags         businesses
+// @Security     BearerAuth
+// @Param        business_id path string true "Business profile id"
+// @Param        branch_id path string true "Branch id"
+// @Success      200 {object} utils.Response
+// @Router       /businesses/{business_id}/branches/{branch_id} [delete]
+func (h *BusinessBranchHandler) DeleteBranch(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Delete(c.Request.Context(), c.Param("business_id"), c.Param("branch_id"), userID); err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Branch deleted", nil)
+}
@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/services"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// maxClaimProofImages caps proof images per claim request.
+const maxClaimProofImages = 5
+
+// BusinessClaimHandler exposes the member claim endpoints, the admin review
+// queue and a business's ownership history.
+type BusinessClaimHandler struct {
+	claimService   *services.BusinessClaimService
+	storageService *services.StorageService
+	adminService   *services.AdminService
+	validator      *utils.Validator
+	logger         *zap.Logger
+}
+
+// NewBusinessClaimHandler constructs the handler. adminService is used for
+// audit-logging review decisions (may be nil in tests).
+func NewBusinessClaimHandler(
+	claimService *services.BusinessClaimService,
+	storageService *services.StorageService,
+	adminService *services.AdminService,
+	validator *utils.Validator,
+	logger *zap.Logger,
+) *BusinessClaimHandler {
+	return &BusinessClaimHandler{
+		claimService:   claimService,
+		storageService: storageService,
+		adminService:   adminService,
+		validator:      validator,
+		logger:         logger,
+	}
+}
+
+// SubmitClaim godoc
+// @Summary Claim a business that has no owner yet
+// @Description Multipart: 1-5 proof images such as a photo of the business license (field "proof") + optional note
+// @Tags businesses
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param business_id path string true "Business ID"
+// @Param proof formData file true "Proof images (repeatable, max 5)"
+// @Param note formData string false "Note for the reviewer"
+// @Success 201 {object} utils.Response{data=models.BusinessClaimRequest}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /businesses/{business_id}/claim-requests [post]
+func (h *BusinessClaimHandler) SubmitClaim(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid multipart form", err)
+		return
+	}
+	files := form.File["proof"]
+	if len(files) == 0 {
+		utils.SendError(c, http.StatusBadRequest, "At least one proof image is required", nil)
+		return
+	}
+	if len(files) > maxClaimProofImages {
+		utils.SendError(c, http.StatusBadRequest, "Too many proof images (max 5)", nil)
+		return
+	}
+
+	proof := make([]models.Photo, 0, len(files))
+	for _, header := range files {
+		if !utils.EnforceUploadSize(c, header.Size, utils.MaxImageUploadBytes) {
+			return
+		}
+		file, err := header.Open()
+		if err != nil {
+			utils.SendError(c, http.StatusBadRequest, "Failed to read proof image", err)
+			return
+		}
+		photo, err := h.storageService.UploadImage(c.Request.Context(), userID.(string), file, header, services.ImageTypeVerification)
+		_ = file.Close()
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		proof = append(proof, *photo)
+	}
+
+	var note *string
+	if v := c.PostForm("note"); v != "" {
+		note = &v
+	}
+
+	claim, err := h.claimService.Submit(c.Request.Context(), c.Param("business_id"), userID.(string), note, proof)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusCreated, "Claim submitted", claim)
+}
+
+// GetMyClaim godoc
+// @Summary Get my latest claim on a business
+// @Tags businesses
+// @Produce json
+// @Security BearerAuth
+// @Param business_id path string true "Business ID"
+// @Success 200 {object} utils.Response{data=models.BusinessClaimRequest}
+// @Failure 401 {object} utils.Response
+// @Router /businesses/{business_id}/claim-requests/me [get]
+func (h *BusinessClaimHandler) GetMyClaim(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	claim, err := h.claimService.MyClaim(c.Request.Context(), c.Param("business_id"), userID.(string))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Claim retrieved", claim)
+}
+
+// ListClaims godoc
+// @Summary List business claim requests (admin)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter: PENDING | APPROVED | REJECTED"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} utils.ListResponse{data=[]models.BusinessClaimListItem}
+// @Failure 401 {object} utils.Response
+// @Router /admin/business-claims [get]
+func (h *BusinessClaimHandler) ListClaims(c *gin.Context) {
+	limit, offset := pageParams(c)
+	var status *string
+	if v := c.Query("status"); v == models.BusinessClaimStatusPending ||
+		v == models.BusinessClaimStatusApproved || v == models.BusinessClaimStatusRejected {
+		status = &v
+	}
+
+	items, total, err := h.claimService.List(c.Request.Context(), status, limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendList(c, "Business claims retrieved", items, utils.OffsetMeta(limit, offset, total), gin.H{
+		"items":  items,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// ReviewClaim godoc
+// @Summary Approve or reject a business claim (admin)
+// @Description Approval transfers the business to the claimant and declines the other pending claims on it
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param claim_id path string true "Claim ID"
+// @Param request body models.ReviewBusinessClaimRequest true "action: approve | reject (+ reason)"
+// @Success 200 {object} utils.Response{data=models.BusinessClaimRequest}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /admin/business-claims/{claim_id} [patch]
+func (h *BusinessClaimHandler) ReviewClaim(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	var req models.ReviewBusinessClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	result, err := h.claimService.Review(
+		c.Request.Context(), c.Param("claim_id"), adminID.(string), req.Action, req.Reason,
+	)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Audit trail — approval hands a business to another account.
+	if h.adminService != nil {
+		details := map[string]interface{}{
+			"action":      req.Action,
+			"business_id": result.BusinessID,
+			"claimant_id": result.UserID,
+		}
+		if req.Reason != nil && *req.Reason != "" {
+			details["reason"] = *req.Reason
+		}
+		_ = h.adminService.LogAuditAction(
+			c.Request.Context(), adminID.(string),
+			"review_business_claim", "business_claim", result.ID,
+			details, c.ClientIP(),
+		)
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Claim reviewed", result)
+}
+
+// GetOwnershipHistory godoc
+// @Summary Get a business's ownership history (admin)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param business_id path string true "Business ID"
+// @Success 200 {object} utils.Response{data=[]models.BusinessOwnershipChange}
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/businesses/{business_id}/ownership-history [get]
+func (h *BusinessClaimHandler) GetOwnershipHistory(c *gin.Context) {
+	changes, err := h.claimService.OwnershipHistory(c.Request.Context(), c.Param("business_id"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Ownership history retrieved", changes)
+}
+
+func (h *BusinessClaimHandler) handleError(c *gin.Context, err error) {
+	middleware.RespondWithError(c, err)
+}
@@ -4,8 +4,12 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hamsaya/backend/internal/middleware"
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/services"
 	"github.com/hamsaya/backend/internal/utils"
@@ -264,11 +268,15 @@ func (h *BusinessHandler) CreateBusinessForOwner(c *gin.Context) {
 // @Tags businesses
 // @Produce json
 // @Param business_id path string true "Business ID"
+// @Param gallery_page query int false "Gallery page (default 1)"
+// @Param gallery_limit query int false "Gallery images per page (default 12, max 50)"
 // @Success 200 {object} utils.Response{data=models.BusinessResponse}
 // @Failure 404 {object} utils.Response
 // @Router /businesses/{business_id} [get]
 func (h *BusinessHandler) GetBusiness(c *gin.Context) {
 	businessID := c.Param("business_id")
+	galleryPage, _ := strconv.Atoi(c.DefaultQuery("gallery_page", "1"))
+	galleryLimit, _ := strconv.Atoi(c.DefaultQuery("gallery_limit", strconv.Itoa(services.DefaultGalleryPageSize)))
 
 	// Get viewer ID (may be nil for unauthenticated requests)
 	var viewerID *string
@@ -278,12 +286,15 @@ func (h *BusinessHandler) GetBusiness(c *gin.Context) {
 	}
 
 	// Get business
-	business, err := h.businessService.GetBusiness(c.Request.Context(), businessID, viewerID)
+	business, err := h.businessService.GetBusinessWithGalleryPage(c.Request.Context(), businessID, viewerID, galleryPage, galleryLimit)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
+	// The body depends on the viewer (follow state etc.), so only the
+	// client may store it, and it must revalidate via If-None-Match.
+	c.Header("Cache-Control", "private, no-cache")
 	utils.SendSuccess(c, http.StatusOK, "Business retrieved successfully", business)
 }
 
@@ -539,6 +550,7 @@ func (h *BusinessHandler) SetBusinessHours(c *gin.Context) {
 // @Security BearerAuth
 // @Param business_id path string true "Business ID"
 // @Param file formData file true "Avatar image file (JPEG/PNG/WebP, max 10MB)"
+// @Param alt_text formData string false "Image description for screen readers"
 // @Success 200 {object} utils.Response
 // @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
@@ -565,23 +577,28 @@ func (h *BusinessHandler) UploadAvatar(c *gin.Context) {
 	if !utils.EnforceUploadSize(c, header.Size, utils.MaxImageUploadBytes) {
 		return
 	}
+	altText, ok := formAltText(c)
+	if !ok {
+		return
+	}
 
 	// Upload and process the image via storage service
-	photo, err := h.storageService.UploadImage(c.Request.Context(), file, header, services.ImageTypeAvatar)
+	photo, err := h.storageService.UploadImage(c.Request.Context(), userID.(string), file, header, services.ImageTypeAvatar)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
+	photo.AltText = altText
 
 	// Save the photo URL to the business profile
-	if err := h.businessService.UploadAvatar(c.Request.Context(), businessID, userID.(string), photo.URL); err != nil {
+	if err := h.businessService.UploadAvatar(c.Request.Context(), businessID, userID.(string), photo.URL, altText); err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	utils.SendSuccess(c, http.StatusOK, "Avatar uploaded successfully", &models.UploadImageResponse{
-		Photo: photo,
-	})
+	resp := models.NewUploadImageResponse(photo)
+	resp.Pending = h.businessService.MediaHeldForReview()
+	utils.SendSuccess(c, http.StatusOK, "Avatar uploaded successfully", resp)
 }
 
 // UploadCover godoc
@@ -593,6 +610,7 @@ func (h *BusinessHandler) UploadAvatar(c *gin.Context) {
 // @Security BearerAuth
 // @Param business_id path string true "Business ID"
 // @Param file formData file true "Cover image file (JPEG/PNG/WebP, max 10MB)"
+// @Param alt_text formData string false "Image description for screen readers"
 // @Success 200 {object} utils.Response
 // @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
@@ -619,28 +637,33 @@ func (h *BusinessHandler) UploadCover(c *gin.Context) {
 	if !utils.EnforceUploadSize(c, header.Size, utils.MaxImageUploadBytes) {
 		return
 	}
+	altText, ok := formAltText(c)
+	if !ok {
+		return
+	}
 
 	// Upload and process the image via storage service
-	photo, err := h.storageService.UploadImage(c.Request.Context(), file, header, services.ImageTypeCover)
+	photo, err := h.storageService.UploadImage(c.Request.Context(), userID.(string), file, header, services.ImageTypeCover)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
+	photo.AltText = altText
 
 	// Save the photo URL to the business profile
-	if err := h.businessService.UploadCover(c.Request.Context(), businessID, userID.(string), photo.URL); err != nil {
+	if err := h.businessService.UploadCover(c.Request.Context(), businessID, userID.(string), photo.URL, altText); err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	utils.SendSuccess(c, http.StatusOK, "Cover uploaded successfully", &models.UploadImageResponse{
-		Photo: photo,
-	})
+	resp := models.NewUploadImageResponse(photo)
+	resp.Pending = h.businessService.MediaHeldForReview()
+	utils.SendSuccess(c, http.StatusOK, "Cover uploaded successfully", resp)
 }
 
 // GetGallery godoc
 // @Summary Get business gallery
-// @Description Get all gallery images for a business (id + photo per item)
+// @Description Get all gallery images for a business in gallery order
 // @Tags businesses
 // @Produce json
 // @Param business_id path string true "Business ID"
@@ -661,15 +684,143 @@ func (h *BusinessHandler) GetGallery(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusOK, "Gallery retrieved successfully", gallery)
 }
 
+// GetFeaturedPosts godoc
+// @Summary Get featured posts
+// @Description Get the posts a business pinned to its profile, in the owner's order
+// @Tags businesses
+// @Produce json
+// @Param business_id path string true "Business ID"
+// @Success 200 {object} utils.Response{data=[]models.PostResponse}
+// @Failure 404 {object} utils.Response
+// @Router /businesses/{business_id}/featured-posts [get]
+func (h *BusinessHandler) GetFeaturedPosts(c *gin.Context) {
+	var viewerID *string
+	if id, exists := c.Get("user_id"); exists {
+		idStr := id.(string)
+		viewerID = &idStr
+	}
+
+	posts, err := h.businessService.GetFeaturedPosts(c.Request.Context(), c.Param("business_id"), viewerID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Featured posts retrieved successfully", posts)
+}
+
+// FeaturePost godoc
+// @Summary Feature a post
+// @Description Pin one of the business's posts to its profile (up to 6), after the ones already featured
+// @Tags businesses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param business_id path string true "Business ID"
+// @Param request body models.FeaturePostRequest true "Post to feature"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /businesses/{business_id}/featured-posts [post]
+func (h *BusinessHandler) FeaturePost(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	var req models.FeaturePostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	if err := h.businessService.FeaturePost(c.Request.Context(), c.Param("business_id"), userID.(string), req.PostID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Post featured successfully", nil)
+}
+
+// UnfeaturePost godoc
+// @Summary Unfeature a post
+// @Description Remove a post from the business's featured section
+// @Tags businesses
+// @Produce json
+// @Security BearerAuth
+// @Param business_id path string true "Business ID"
+// @Param post_id path string true "Post ID"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /businesses/{business_id}/featured-posts/{post_id} [delete]
+func (h *BusinessHandler) UnfeaturePost(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	if err := h.businessService.UnfeaturePost(c.Request.Context(), c.Param("business_id"), userID.(string), c.Param("post_id")); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Post unfeatured successfully", nil)
+}
+
+// ReorderFeaturedPosts godoc
+// @Summary Reorder featured posts
+// @Description Set the order of the featured posts; post_ids must list every featured post once
+// @Tags businesses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param business_id path string true "Business ID"
+// @Param request body models.ReorderFeaturedPostsRequest true "Featured post ids in the new order"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /businesses/{business_id}/featured-posts/order [put]
+func (h *BusinessHandler) ReorderFeaturedPosts(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	var req models.ReorderFeaturedPostsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	if err := h.businessService.ReorderFeaturedPosts(c.Request.Context(), c.Param("business_id"), userID.(string), req.PostIDs); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Featured posts reordered successfully", nil)
+}
+
 // AddGalleryImage godoc
 // @Summary Add gallery image
-// @Description Add an image to business gallery (multipart file upload)
+// @Description Add an image to the end of the business gallery (multipart file upload), up to the configured gallery limit
 // @Tags businesses
 // @Accept multipart/form-data
 // @Produce json
 // @Security BearerAuth
 // @Param business_id path string true "Business ID"
 // @Param file formData file true "Gallery image file (JPEG/PNG/WebP, max 10MB)"
+// @Param alt_text formData string false "Image description for screen readers"
+// @Param caption formData string false "Caption shown under the image (max 300 characters)"
 // @Success 200 {object} utils.Response
 // @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
@@ -696,23 +847,31 @@ func (h *BusinessHandler) AddGalleryImage(c *gin.Context) {
 	if !utils.EnforceUploadSize(c, header.Size, utils.MaxImageUploadBytes) {
 		return
 	}
+	altText, ok := formAltText(c)
+	if !ok {
+		return
+	}
+	caption := c.PostForm("caption")
+	if utf8.RuneCountInString(caption) > 300 {
+		utils.SendError(c, http.StatusBadRequest, "caption must be at most 300 characters", utils.ErrBadRequest)
+		return
+	}
 
 	// Upload and process the image via storage service
-	photo, err := h.storageService.UploadImage(c.Request.Context(), file, header, services.ImageTypePost)
+	photo, err := h.storageService.UploadImage(c.Request.Context(), userID.(string), file, header, services.ImageTypePost)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
+	photo.AltText = altText
 
 	// Save the photo URL to the business gallery
-	if err := h.businessService.AddGalleryImage(c.Request.Context(), businessID, userID.(string), photo.URL); err != nil {
+	if err := h.businessService.AddGalleryImage(c.Request.Context(), businessID, userID.(string), photo.URL, altText, caption); err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	utils.SendSuccess(c, http.StatusOK, "Gallery image added successfully", &models.UploadImageResponse{
-		Photo: photo,
-	})
+	utils.SendSuccess(c, http.StatusOK, "Gallery image added successfully", models.NewUploadImageResponse(photo))
 }
 
 // DeleteGalleryImage godoc
@@ -747,6 +906,123 @@ func (h *BusinessHandler) DeleteGalleryImage(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusOK, "Gallery image deleted successfully", nil)
 }
 
+// ReorderGallery godoc
+// @Summary Reorder gallery images
+// @Description Set the order of the business gallery; attachment_ids must list every gallery image once
+// @Tags businesses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param business_id path string true "Business ID"
+// @Param request body models.ReorderGalleryRequest true "Gallery image ids in the new order"
+// @Success 200 {object} utils.Response{data=[]models.GalleryItem}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /businesses/{business_id}/attachments/order [put]
+func (h *BusinessHandler) ReorderGallery(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	var req models.ReorderGalleryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	gallery, err := h.businessService.ReorderGallery(c.Request.Context(), c.Param("business_id"), userID.(string), req.AttachmentIDs)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Gallery reordered successfully", gallery)
+}
+
+// UpdateGalleryCaption godoc
+// @Summary Update gallery image caption
+// @Description Set the caption of a gallery image; an empty caption clears it
+// @Tags businesses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param business_id path string true "Business ID"
+// @Param attachment_id path string true "Attachment ID"
+// @Param request body models.UpdateGalleryCaptionRequest true "New caption"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /businesses/{business_id}/attachments/{attachment_id} [patch]
+func (h *BusinessHandler) UpdateGalleryCaption(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	var req models.UpdateGalleryCaptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	if err := h.businessService.UpdateGalleryCaption(c.Request.Context(), c.Param("business_id"), userID.(string), c.Param("attachment_id"), req.Caption); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Caption updated successfully", nil)
+}
+
+// SetCoverFromGallery godoc
+// @Summary Set cover from gallery
+// @Description Make one of the business's gallery images its cover; the image stays in the gallery
+// @Tags businesses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param business_id path string true "Business ID"
+// @Param request body models.SetCoverFromGalleryRequest true "Gallery image to use"
+// @Success 200 {object} utils.Response{data=models.UploadImageResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /businesses/{business_id}/cover/from-gallery [put]
+func (h *BusinessHandler) SetCoverFromGallery(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	var req models.SetCoverFromGalleryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	photo, err := h.businessService.SetCoverFromGallery(c.Request.Context(), c.Param("business_id"), userID.(string), req.AttachmentID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Cover updated successfully", models.NewUploadImageResponse(photo))
+}
+
 // FollowBusiness godoc
 // @Summary Follow a business
 // @Description Follow a business profile
@@ -806,6 +1082,174 @@ func (h *BusinessHandler) UnfollowBusiness(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusOK, "Business unfollowed successfully", nil)
 }
 
+// ListFollowers godoc
+// @Summary List business followers (owner only)
+// @Description Followers of the business with their name, avatar and location, newest first
+// @Tags businesses
+// @Produce json
+// @Security BearerAuth
+// @Param business_id path string true "Business ID"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Offset (default 0)"
+// @Success 200 {object} utils.ListResponse
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /businesses/{business_id}/followers [get]
+func (h *BusinessHandler) ListFollowers(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	limit, offset := pageParams(c)
+	followers, total, err := h.businessService.ListFollowers(
+		c.Request.Context(), c.Param("business_id"), userID.(string), limit, offset,
+	)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendList(c, "Followers retrieved successfully", followers, utils.OffsetMeta(limit, offset, total), gin.H{
+		"items":  followers,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// ListViewers godoc
+// @Summary List who viewed the business (owner only)
+// @Description Signed-in users who viewed the business profile in the last days (default and max 90), most recent first. Viewers whose privacy settings don't share the visit are left out and counted in hidden.
+// @Tags businesses
+// @Produce json
+// @Security BearerAuth
+// @Param business_id path string true "Business ID"
+// @Param days query int false "Window in days (default 90, max 90)"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Offset (default 0)"
+// @Success 200 {object} utils.Response{data=models.BusinessViewersResponse}
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /businesses/{business_id}/viewers [get]
+func (h *BusinessHandler) ListViewers(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	days := 0
+	if daysStr := c.Query("days"); daysStr != "" {
+		if d, err := strconv.Atoi(daysStr); err == nil {
+			days = d
+		}
+	}
+	limit, offset := pageParams(c)
+	viewers, err := h.businessService.ListViewers(
+		c.Request.Context(), c.Param("business_id"), userID.(string), days, limit, offset,
+	)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Viewers retrieved successfully", viewers)
+}
+
+// GetFollowerStats godoc
+// @Summary Follower counts by province (owner only)
+// @Tags businesses
+// @Produce json
+// @Security BearerAuth
+// @Param business_id path string true "Business ID"
+// @Success 200 {object} utils.Response{data=models.BusinessFollowerStats}
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /businesses/{business_id}/followers/stats [get]
+func (h *BusinessHandler) GetFollowerStats(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	stats, err := h.businessService.GetFollowerStats(c.Request.Context(), c.Param("business_id"), userID.(string))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Follower stats retrieved successfully", stats)
+}
+
+// GetFollowerNotificationSettings godoc
+// @Summary Get follower notification settings (owner only)
+// @Tags businesses
+// @Produce json
+// @Security BearerAuth
+// @Param business_id path string true "Business ID"
+// @Success 200 {object} utils.Response{data=models.BusinessFollowerNotificationSettings}
+// @Failure 403 {object} utils.Response
+// @Router /businesses/{business_id}/followers/notifications [get]
+func (h *BusinessHandler) GetFollowerNotificationSettings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	settings, err := h.businessService.GetFollowerNotificationSettings(c.Request.Context(), c.Param("business_id"), userID.(string))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Notification settings retrieved successfully", settings)
+}
+
+// UpdateFollowerNotificationSettings godoc
+// @Summary Update follower notification settings (owner only)
+// @Description Turn follower notifications for updates and polls on or off. Events always notify followers.
+// @Tags businesses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param business_id path string true "Business ID"
+// @Param request body models.UpdateFollowerNotificationSettingsRequest true "Settings"
+// @Success 200 {object} utils.Response{data=models.BusinessFollowerNotificationSettings}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /businesses/{business_id}/followers/notifications [put]
+func (h *BusinessHandler) UpdateFollowerNotificationSettings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	var req models.UpdateFollowerNotificationSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	settings, err := h.businessService.UpdateFollowerNotificationSettings(
+		c.Request.Context(), c.Param("business_id"), userID.(string), &req,
+	)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Notification settings updated successfully", settings)
+}
+
 // ListBusinesses godoc
 // @Summary List businesses
 // @Description List business profiles with filters
@@ -813,6 +1257,7 @@ func (h *BusinessHandler) UnfollowBusiness(c *gin.Context) {
 // @Produce json
 // @Param user_id query string false "Filter by user ID"
 // @Param category_id query string false "Filter by category ID"
+// @Param category_ids query string false "Comma-separated category IDs; matches any"
 // @Param province query string false "Filter by province"
 // @Param search query string false "Search by name or description"
 // @Param latitude query number false "Latitude for nearby search"
@@ -821,6 +1266,7 @@ func (h *BusinessHandler) UnfollowBusiness(c *gin.Context) {
 // @Param limit query int false "Limit" default(20)
 // @Param offset query int false "Offset" default(0)
 // @Success 200 {object} utils.Response{data=[]models.BusinessResponse}
+// @Failure 400 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /businesses/search [get]
 func (h *BusinessHandler) ListBusinesses(c *gin.Context) {
@@ -831,7 +1277,57 @@ func (h *BusinessHandler) ListBusinesses(c *gin.Context) {
 		viewerID = &idStr
 	}
 
-	// Parse query parameters
+	filter, ok := parseBusinessListFilter(c)
+	if !ok {
+		return
+	}
+
+	// List businesses
+	businesses, err := h.businessService.ListBusinesses(c.Request.Context(), filter, viewerID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	// Search/directory cards only render a slim subset — trimming here keeps
+	// the payload small (full profiles shipped hours/contact/counters too).
+	cards := make([]*models.BusinessCardResponse, 0, len(businesses))
+	for _, b := range businesses {
+		cards = append(cards, models.NewBusinessCardResponse(b))
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Businesses retrieved successfully", cards)
+}
+
+// GetSearchFacets godoc
+// @Summary Business category counts
+// @Description Count the businesses /businesses/search would return per category, for filter chips. Takes the same filters; category filters are ignored so every chip keeps its count.
+// @Tags businesses
+// @Produce json
+// @Param province query string false "Filter by province"
+// @Param search query string false "Search by name or description"
+// @Param latitude query number false "Latitude for nearby search"
+// @Param longitude query number false "Longitude for nearby search"
+// @Param radius_km query number false "Radius in kilometers for nearby search"
+// @Success 200 {object} utils.Response{data=models.BusinessSearchFacets}
+// @Failure 400 {object} utils.Response
+// @Router /businesses/search/facets [get]
+func (h *BusinessHandler) GetSearchFacets(c *gin.Context) {
+	filter, ok := parseBusinessListFilter(c)
+	if !ok {
+		return
+	}
+	facets, err := h.businessService.SearchFacets(c.Request.Context(), filter)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Business facets retrieved successfully", facets)
+}
+
+// parseBusinessListFilter reads the /businesses/search query parameters.
+// It writes a 400 and returns false on a malformed category id.
+func parseBusinessListFilter(c *gin.Context) (*models.BusinessListFilter, bool) {
 	filter := &models.BusinessListFilter{
 		Limit:  20,
 		Offset: 0,
@@ -841,9 +1337,22 @@ func (h *BusinessHandler) ListBusinesses(c *gin.Context) {
 		filter.UserID = &userID
 	}
 
-	if categoryID := c.Query("category_id"); categoryID != "" {
-		filter.CategoryID = &categoryID
+	// category_id (single, older clients) and category_ids (comma list or
+	// repeated) combine; a business in any of them matches.
+	var categoryIDs []string
+	for _, raw := range append(c.QueryArray("category_ids"), c.Query("category_id")) {
+		for _, id := range strings.Split(raw, ",") {
+			if id = strings.TrimSpace(id); id == "" {
+				continue
+			}
+			if _, err := uuid.Parse(id); err != nil {
+				utils.SendError(c, http.StatusBadRequest, "Invalid category id", err)
+				return nil, false
+			}
+			categoryIDs = append(categoryIDs, id)
+		}
 	}
+	filter.CategoryIDs = categoryIDs
 
 	if province := c.Query("province"); province != "" {
 		filter.Province = &province
@@ -887,22 +1396,7 @@ func (h *BusinessHandler) ListBusinesses(c *gin.Context) {
 			filter.Offset = page * filter.Limit
 		}
 	}
-
-	// List businesses
-	businesses, err := h.businessService.ListBusinesses(c.Request.Context(), filter, viewerID)
-	if err != nil {
-		h.handleError(c, err)
-		return
-	}
-
-	// Search/directory cards only render a slim subset — trimming here keeps
-	// the payload small (full profiles shipped hours/contact/counters too).
-	cards := make([]*models.BusinessCardResponse, 0, len(businesses))
-	for _, b := range businesses {
-		cards = append(cards, models.NewBusinessCardResponse(b))
-	}
-
-	utils.SendSuccess(c, http.StatusOK, "Businesses retrieved successfully", cards)
+	return filter, true
 }
 
 // GetCategories godoc
@@ -927,18 +1421,14 @@ func (h *BusinessHandler) GetCategories(c *gin.Context) {
 	if categories == nil {
 		categories = []*models.BusinessCategory{}
 	}
+
+	// Reference data: clients may keep it and revalidate with the ETag the
+	// route middleware adds.
+	c.Header("Cache-Control", "public, max-age=3600, s-maxage=3600")
 	utils.SendSuccess(c, http.StatusOK, "Categories retrieved successfully", categories)
 }
 
 // handleError handles service errors and sends appropriate HTTP responses
 func (h *BusinessHandler) handleError(c *gin.Context, err error) {
-	// Check if it's an AppError
-	if appErr, ok := err.(*utils.AppError); ok {
-		utils.SendError(c, appErr.Code, appErr.Message, appErr.Err)
-		return
-	}
-
-	// Default to internal server error
-	h.logger.Error("Unhandled error in business handler", zap.Error(err))
-	utils.SendError(c, http.StatusInternalServerError, "An error occurred", err)
+	middleware.RespondWithError(c, err)
 }
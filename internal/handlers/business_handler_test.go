@@ -38,6 +38,7 @@ func newBusinessRouter(
 	r.PUT("/api/v1/businesses/:business_id", authed, h.UpdateBusiness)
 	r.DELETE("/api/v1/businesses/:business_id", authed, h.DeleteBusiness)
 	r.GET("/api/v1/users/my/businesses", authed, h.GetMyBusinesses)
+	r.GET("/api/v1/businesses/search/facets", h.GetSearchFacets)
 
 	r.POST("/api/v1/noauth/businesses", h.CreateBusiness)
 	return r
@@ -185,7 +186,8 @@ func TestBusinessHandler_DeleteBusiness(t *testing.T) {
 		req, _ := http.NewRequest(http.MethodDelete, "/api/v1/businesses/"+bizTestBizID, nil)
 		r.ServeHTTP(w, req)
 
-		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		// Authenticated but not the owner: forbidden, not unauthenticated.
+		assert.Equal(t, http.StatusForbidden, w.Code)
 	})
 
 	t.Run("success", func(t *testing.T) {
@@ -220,3 +222,32 @@ func TestBusinessHandler_GetMyBusinesses(t *testing.T) {
 		assert.Less(t, w.Code, 500)
 	})
 }
+
+// --- GetSearchFacets ---
+
+func TestBusinessHandler_GetSearchFacets(t *testing.T) {
+	t.Run("invalid category id", func(t *testing.T) {
+		r := newBusinessRouter(t, &mocks.MockBusinessRepository{}, &mocks.MockUserRepository{})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/businesses/search/facets?category_ids=bakery", nil)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("passes filters through", func(t *testing.T) {
+		bizRepo := &mocks.MockBusinessRepository{}
+		catA, catB := "6f1c3c8e-0d55-4a4b-9f0e-8c3f3d1b2a01", "6f1c3c8e-0d55-4a4b-9f0e-8c3f3d1b2a02"
+		bizRepo.On("CountByCategory", mock.Anything, mock.MatchedBy(func(f *models.BusinessListFilter) bool {
+			return len(f.CategoryIDs) == 2 && f.CategoryIDs[0] == catB && f.CategoryIDs[1] == catA &&
+				f.Province != nil && *f.Province == "Herat"
+		})).Return([]*models.FacetCount{{Value: catA, Label: "Bakery", Count: 4}}, nil)
+		r := newBusinessRouter(t, bizRepo, &mocks.MockUserRepository{})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet,
+			fmt.Sprintf("/api/v1/businesses/search/facets?category_ids=%s&category_id=%s&province=Herat", catB, catA), nil)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"label":"Bakery"`)
+		bizRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/services"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// BusinessImportHandler exposes admin CSV imports of business directories
+// and the claim link their owners are emailed.
+type BusinessImportHandler struct {
+	importService *services.BusinessImportService
+	adminService  *services.AdminService
+	validator     *utils.Validator
+	logger        *zap.Logger
+}
+
+// NewBusinessImportHandler constructs the handler. adminService is used for
+// audit-logging imports (may be nil in tests).
+func NewBusinessImportHandler(
+	importService *services.BusinessImportService,
+	adminService *services.AdminService,
+	validator *utils.Validator,
+	logger *zap.Logger,
+) *BusinessImportHandler {
+	return &BusinessImportHandler{
+		importService: importService,
+		adminService:  adminService,
+		validator:     validator,
+		logger:        logger,
+	}
+}
+
+// ImportBusinesses godoc
+// @Summary Import businesses from a CSV file (admin)
+// @Description Header row names the columns: name and owner_email are required; owner_name, license_no, description, address, phone_number, email, website, country, province, district, neighborhood, latitude, longitude and categories (names separated by ";") are optional. With dry_run=true the file is only validated and row-level errors are returned. Otherwise valid rows are imported in the background; poll the returned job for progress. Owners with an account get the business; the others are emailed a link to claim it.
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "CSV file (max 5 MB, 5000 rows)"
+// @Param dry_run query bool false "Validate only"
+// @Success 200 {object} utils.Response{data=models.BusinessImportPreview}
+// @Success 202 {object} utils.Response{data=models.BusinessImportJob}
+// @Failure 400 {object} utils.Response
+// @Failure 413 {object} utils.Response
+// @Router /admin/businesses/import [post]
+func (h *BusinessImportHandler) ImportBusinesses(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "No file uploaded", err)
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	if !utils.EnforceUploadSize(c, header.Size, utils.MaxCSVUploadBytes) {
+		return
+	}
+	raw, err := io.ReadAll(io.LimitReader(file, utils.MaxCSVUploadBytes+1))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Failed to read the file", err)
+		return
+	}
+	if !utils.EnforceUploadSize(c, int64(len(raw)), utils.MaxCSVUploadBytes) {
+		return
+	}
+	if !utf8.Valid(raw) {
+		utils.SendError(c, http.StatusBadRequest, "The file must be UTF-8 encoded CSV", utils.ErrBadRequest)
+		return
+	}
+
+	if dryRun, _ := strconv.ParseBool(c.Query("dry_run")); dryRun {
+		preview, err := h.importService.Preview(c.Request.Context(), string(raw))
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		utils.SendSuccess(c, http.StatusOK, "Import checked", preview)
+		return
+	}
+
+	job, err := h.importService.StartImport(c.Request.Context(), adminID.(string), header.Filename, string(raw))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	if h.adminService != nil {
+		_ = h.adminService.LogAuditAction(
+			c.Request.Context(), adminID.(string),
+			"import_businesses", "business_import", job.ID,
+			map[string]interface{}{"file_name": job.FileName, "rows": job.TotalRows},
+			c.ClientIP(),
+		)
+	}
+
+	utils.SendSuccess(c, http.StatusAccepted, "Import queued", job)
+}
+
+// ListImports godoc
+// @Summary List business imports (admin)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "QUEUED | RUNNING | COMPLETED | FAILED"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Offset (default 0)"
+// @Success 200 {object} utils.ListResponse
+// @Router /admin/businesses/imports [get]
+func (h *BusinessImportHandler) ListImports(c *gin.Context) {
+	limit, offset := pageParams(c)
+	var status *string
+	switch v := c.Query("status"); v {
+	case models.BusinessImportStatusQueued, models.BusinessImportStatusRunning,
+		models.BusinessImportStatusCompleted, models.BusinessImportStatusFailed:
+		status = &v
+	}
+
+	jobs, total, err := h.importService.ListJobs(c.Request.Context(), status, limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendList(c, "Imports retrieved", jobs, utils.OffsetMeta(limit, offset, total), gin.H{
+		"items":  jobs,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetImport godoc
+// @Summary Get a business import's progress and row errors (admin)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param job_id path string true "Import job ID"
+// @Success 200 {object} utils.Response{data=models.BusinessImportJob}
+// @Failure 404 {object} utils.Response
+// @Router /admin/businesses/imports/{job_id} [get]
+func (h *BusinessImportHandler) GetImport(c *gin.Context) {
+	job, err := h.importService.GetJob(c.Request.Context(), c.Param("job_id"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Import retrieved", job)
+}
+
+// ClaimBusiness godoc
+// @Summary Claim an imported business
+// @Description Takes over a business imported from a directory, using the token from the invite email. The caller's verified email must be the one the invite was sent to.
+// @Tags businesses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.ClaimBusinessRequest true "Invite token"
+// @Success 200 {object} utils.Response{data=models.BusinessResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /businesses/claim [post]
+func (h *BusinessImportHandler) ClaimBusiness(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	var req models.ClaimBusinessRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	business, err := h.importService.ClaimBusiness(c.Request.Context(), userID.(string), req.Token)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Business claimed successfully", business)
+}
+
+func (h *BusinessImportHandler) handleError(c *gin.Context, err error) {
+	middleware.RespondWithError(c, err)
+}
@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/services"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// BusinessProductHandler exposes the catalog endpoints under
+// /api/v1/businesses/:business_id/products.
+type BusinessProductHandler struct {
+	service        *services.BusinessProductService
+	storageService *services.StorageService
+	validator      *utils.Validator
+	logger         *zap.Logger
+}
+
+// NewBusinessProductHandler wires the handler.
+func NewBusinessProductHandler(
+	service *services.BusinessProductService,
+	storageService *services.StorageService,
+	validator *utils.Validator,
+	logger *zap.Logger,
+) *BusinessProductHandler {
+	return &BusinessProductHandler{
+		service:        service,
+		storageService: storageService,
+		validator:      validator,
+		logger:         logger,
+	}
+}
+
+func (h *BusinessProductHandler) sendErr(c *gin.Context, err error) {
+	middleware.RespondWithError(c, err)
+}
+
+func (h *BusinessProductHandler) currentUser(c *gin.Context) (string, bool) {
+	v, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return "", false
+	}
+	return v.(string), true
+}
+
+// CreateProduct adds a product to the business catalog (owner only).
+// @Tags         business-products
+// @Security     BearerAuth
+// @Param        business_id path string true "Business profile id"
+// @Param        request body models.CreateBusinessProductRequest true "Product"
+// @Success      201 {object} utils.Response{data=models.BusinessProduct}
+// @Router       /businesses/{business_id}/products [post]
+func (h *BusinessProductHandler) CreateProduct(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	businessID := c.Param("business_id")
+
+	var req models.CreateBusinessProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	product, err := h.service.Create(c.Request.Context(), businessID, userID, &req)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusCreated, "Product created", product)
+}
+
+// UpdateProduct edits a catalog product (owner only).
+// @Tags         business-products
+// @Security     BearerAuth
+// @Param        business_id path string true "Business profile id"
+// @Param        product_id path string true "Product id"
+// @Param        request body models.UpdateBusinessProductRequest true "Update"
+// @Success      200 {object} utils.Response{data=models.BusinessProduct}
+// @Router       /businesses/{business_id}/products/{product_id} [put]
+func (h *BusinessProductHandler) UpdateProduct(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	businessID := c.Param("business_id")
+	productID := c.Param("product_id")
+
+	var req models.UpdateBusinessProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	product, err := h.service.Update(c.Request.Context(), businessID, productID, userID, &req)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Product updated", product)
+}
+
+// DeleteProduct removes a product from the catalog (owner only).
+// @Tags         business-products
+// @Security     BearerAuth
+// @Param        business_id path string true "Business profile id"
+// @Param        product_id path string true "Product id"
+// @Success      200 {object} utils.Response
+// @Router       /businesses/{business_id}/products/{product_id} [delete]
+func (h *BusinessProductHandler) DeleteProduct(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Delete(c.Request.Context(), c.Param("business_id"), c.Param("product_id"), userID); err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Product deleted", nil)
+}
+
+// GetProduct returns a single catalog product (public).
+// @Tags         business-products
+// @Param        business_id path string true "Business profile id"
+// @Param        product_id path string true "Product id"
+// @Success      200 {object} utils.Response{data=models.BusinessProduct}
+// @Router       /businesses/{business_id}/products/{product_id} [get]
+func (h *BusinessProductHandler) GetProduct(c *gin.Context) {
+	product, err := h.service.Get(c.Request.Context(), c.Param("business_id"), c.Param("product_id"))
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Product", product)
+}
+
+// ListProducts returns the business catalog (public). The owner also sees
+// discontinued products.
+// @Tags         business-products
+// @Param        business_id path string true "Business profile id"
+// @Param        limit query int false "Page size (default 20, max 100)"
+// @Param        offset query int false "Offset (default 0)"
+// @Success      200 {object} utils.ListResponse
+// @Router       /businesses/{business_id}/products [get]
+func (h *BusinessProductHandler) ListProducts(c *gin.Context) {
+	businessID := c.Param("business_id")
+
+	limit := 20
+	offset := 0
+	if v := c.Query("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 100 {
+			limit = n
+		}
+	}
+	if v := c.Query("offset"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			offset = n
+		}
+	}
+
+	viewerID := c.GetString("user_id")
+	products, total, err := h.service.List(c.Request.Context(), businessID, viewerID, limit, offset)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendList(c, "Products", products, utils.OffsetMeta(limit, offset, total), gin.H{
+		"items":  products,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// UploadProductPhoto uploads one product image and returns its metadata for
+// use in the create/update body.
+// @Tags         business-products
+// @Accept       multipart/form-data
+// @Security     BearerAuth
+// @Param        business_id path string true "Business profile id"
+// @Param        file formData file true "Product image (JPEG/PNG/WebP, max 10MB)"
+// @Param        alt_text formData string false "Image description for screen readers"
+// @Success      200 {object} utils.Response{data=models.UploadImageResponse}
+// @Router       /businesses/{business_id}/products/photos [post]
+func (h *BusinessProductHandler) UploadProductPhoto(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "No file uploaded", err)
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	if !utils.EnforceUploadSize(c, header.Size, utils.MaxImageUploadBytes) {
+		return
+	}
+	altText, ok := formAltText(c)
+	if !ok {
+		return
+	}
+
+	photo, err := h.storageService.UploadImage(c.Request.Context(), userID, file, header, services.ImageTypePost)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	photo.AltText = altText
+	utils.SendSuccess(c, http.StatusOK, "Photo uploaded", models.NewUploadImageResponse(photo))
+}
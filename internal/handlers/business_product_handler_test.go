@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/services"
+	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+const (
+	productTestUserID = "product-user-001"
+	productTestBizID  = "product-biz-001"
+)
+
+func newProductRouter(
+	t *testing.T,
+	productRepo *mocks.MockBusinessProductRepository,
+	bizRepo *mocks.MockBusinessRepository,
+) *gin.Engine {
+	t.Helper()
+	svc := services.NewBusinessProductService(productRepo, bizRepo, zap.NewNop())
+	h := NewBusinessProductHandler(svc, nil, testutil.CreateTestValidator(), zap.NewNop())
+
+	r := gin.New()
+	authedGroup := r.Group("/api/v1")
+	authedGroup.Use(authContextMiddleware(productTestUserID, "product-sess-001"))
+	authedGroup.POST("/businesses/:business_id/products", h.CreateProduct)
+
+	r.GET("/api/v1/businesses/:business_id/products", h.ListProducts)
+	r.POST("/api/v1/noauth/businesses/:business_id/products", h.CreateProduct)
+	return r
+}
+
+func TestBusinessProductHandler_CreateProduct(t *testing.T) {
+	t.Run("unauthenticated", func(t *testing.T) {
+		r := newProductRouter(t, &mocks.MockBusinessProductRepository{}, &mocks.MockBusinessRepository{})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost,
+			"/api/v1/noauth/businesses/"+productTestBizID+"/products",
+			strings.NewReader(`{"name":"Rice"}`))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("invalid availability", func(t *testing.T) {
+		r := newProductRouter(t, &mocks.MockBusinessProductRepository{}, &mocks.MockBusinessRepository{})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost,
+			"/api/v1/businesses/"+productTestBizID+"/products",
+			strings.NewReader(`{"name":"Rice","availability":"SOMETIMES"}`))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("non-owner forbidden", func(t *testing.T) {
+		bizRepo := &mocks.MockBusinessRepository{}
+		bizRepo.On("GetByID", mock.Anything, productTestBizID).
+			Return(&models.BusinessProfile{ID: productTestBizID, UserID: "someone-else"}, nil)
+		r := newProductRouter(t, &mocks.MockBusinessProductRepository{}, bizRepo)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost,
+			"/api/v1/businesses/"+productTestBizID+"/products",
+			strings.NewReader(`{"name":"Rice","price":120}`))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("owner creates product", func(t *testing.T) {
+		bizRepo := &mocks.MockBusinessRepository{}
+		bizRepo.On("GetByID", mock.Anything, productTestBizID).
+			Return(&models.BusinessProfile{ID: productTestBizID, UserID: productTestUserID}, nil)
+		productRepo := &mocks.MockBusinessProductRepository{}
+		productRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.BusinessProduct")).Return(nil)
+		r := newProductRouter(t, productRepo, bizRepo)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost,
+			"/api/v1/businesses/"+productTestBizID+"/products",
+			strings.NewReader(`{"name":"Rice","price":120,"currency":"AFN"}`))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"availability":"IN_STOCK"`)
+		productRepo.AssertExpectations(t)
+	})
+}
+
+func TestBusinessProductHandler_ListProducts(t *testing.T) {
+	productRepo := &mocks.MockBusinessProductRepository{}
+	productRepo.On("ListByBusiness", mock.Anything, productTestBizID, false, 20, 0).
+		Return([]*models.BusinessProduct{{ID: "prod-1", Name: "Rice", Photos: []models.Photo{}}}, 1, nil)
+	r := newProductRouter(t, productRepo, &mocks.MockBusinessRepository{})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/businesses/"+productTestBizID+"/products?limit=500", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"total":1`)
+	productRepo.AssertExpectations(t)
+}
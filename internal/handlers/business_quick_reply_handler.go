@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/services"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// BusinessQuickReplyHandler exposes the owner-only saved replies under
+// /api/v1/businesses/:business_id/quick-replies and the away message under
+// /api/v1/businesses/:business_id/auto-reply.
+type BusinessQuickReplyHandler struct {
+	service   *services.BusinessQuickReplyService
+	validator *utils.Validator
+	logger    *zap.Logger
+}
+
+// NewBusinessQuickReplyHandler wires the handler.
+func NewBusinessQuickReplyHandler(
+	service *services.BusinessQuickReplyService,
+	validator *utils.Validator,
+	logger *zap.Logger,
+) *BusinessQuickReplyHandler {
+	return &BusinessQuickReplyHandler{
+		service:   service,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (h *BusinessQuickReplyHandler) sendErr(c *gin.Context, err error) {
+	middleware.RespondWithError(c, err)
+}
+
+func (h *BusinessQuickReplyHandler) currentUser(c *gin.Context) (string, bool) {
+	v, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return "", false
+	}
+	return v.(string), true
+}
+
+// ListQuickReplies returns the business's saved replies for the chat
+// composer (owner only).
+// @Tags         business-chat
+// @Security     BearerAuth
+// @Param        business_id path string true "Business profile id"
+// @Success      200 {object} utils.Response{data=[]models.BusinessQuickReply}
+// @Router       /businesses/{business_id}/quick-replies [get]
+func (h *BusinessQuickReplyHandler) ListQuickReplies(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	replies, err := h.service.List(c.Request.Context(), c.Param("business_id"), userID)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Quick replies", replies)
+}
+
+// CreateQuickReply saves a quick reply (owner only).
+// @Tags         business-chat
+// @Security     BearerAuth
+// @Param        business_id path string true "Business profile id"
+// @Param        request body models.CreateQuickReplyRequest true "Quick reply"
+// @Success      201 {object} utils.Response{data=models.BusinessQuickReply}
+// @Router       /businesses/{business_id}/quick-replies [post]
+func (h *BusinessQuickReplyHandler) CreateQuickReply(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateQuickReplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	reply, err := h.service.Create(c.Request.Context(), c.Param("business_id"), userID, &req)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusCreated, "Quick reply created", reply)
+}
+
+// UpdateQuickReply edits a quick reply (owner only).
+// @Tags         business-chat
+// @Security     BearerAuth
+// @Param        business_id path string true "Business profile id"
+// @Param        reply_id path string true "Quick reply id"
+// @Param        request body models.UpdateQuickReplyRequest true "Update"
+// @Success      200 {object} utils.Response{data=models.BusinessQuickReply}
+// @Router       /businesses/{business_id}/quick-replies/{reply_id} [put]
+func (h *BusinessQuickReplyHandler) UpdateQuickReply(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateQuickReplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	reply, err := h.service.Update(c.Request.Context(), c.Param("business_id"), c.Param("reply_id"), userID, &req)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Quick reply updated", reply)
+}
+
+// DeleteQuickReply removes a quick reply (owner only).
+// @Tags         business-chat
+// @Security     BearerAuth
+// @Param        business_id path string true "Business profile id"
+// @Param        reply_id path string true "Quick reply id"
+// @Success      200 {object} utils.Response
+// @Router       /businesses/{business_id}/quick-replies/{reply_id} [delete]
+func (h *BusinessQuickReplyHandler) DeleteQuickReply(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Delete(c.Request.Context(), c.Param("business_id"), c.Param("reply_id"), userID); err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Quick reply deleted", nil)
+}
+
+// GetAutoReply returns the away message settings (owner only).
+// @Tags         business-chat
+// @Security     BearerAuth
+// @Param        business_id path string true "Business profile id"
+// @Success      200 {object} utils.Response{data=models.BusinessAutoReply}
+// @Router       /businesses/{business_id}/auto-reply [get]
+func (h *BusinessQuickReplyHandler) GetAutoReply(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	reply, err := h.service.GetAutoReply(c.Request.Context(), c.Param("business_id"), userID)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Auto-reply", reply)
+}
+
+// SetAutoReply turns the away message, sent to customers who write while
+// the business is closed, on or off (owner only).
+// @Tags         business-chat
+// @Security     BearerAuth
+// @Param        business_id path string true "Business profile id"
+// @Param        request body models.SetAutoReplyRequest true "Auto-reply"
+// @Success      200 {object} utils.Response{data=models.BusinessAutoReply}
+// @Router       /businesses/{business_id}/auto-reply [put]
+func (h *BusinessQuickReplyHandler) SetAutoReply(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.SetAutoReplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	reply, err := h.service.SetAutoReply(c.Request.Context(), c.Param("business_id"), userID, &req)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Auto-reply saved", reply)
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/repositories"
 	"github.com/hamsaya/backend/internal/services"
@@ -37,12 +38,7 @@ func NewBusinessReviewHandler(
 }
 
 func (h *BusinessReviewHandler) sendErr(c *gin.Context, err error) {
-	if appErr, ok := err.(*utils.AppError); ok {
-		utils.SendError(c, appErr.Code, appErr.Message, appErr.Err)
-		return
-	}
-	h.logger.Error("Unhandled error in business review handler", zap.Error(err))
-	utils.SendError(c, http.StatusInternalServerError, "An error occurred", err)
+	middleware.RespondWithError(c, err)
 }
 
 func (h *BusinessReviewHandler) currentUser(c *gin.Context) (string, bool) {
@@ -169,7 +165,7 @@ func (h *BusinessReviewHandler) DeleteReview(c *gin.Context) {
 // @Param        business_id path string true "Business profile id"
 // @Param        limit query int false "Page size (default 20, max 100)"
 // @Param        offset query int false "Offset (default 0)"
-// @Success      200 {object} utils.Response
+// @Success      200 {object} utils.ListResponse
 // @Router       /businesses/{business_id}/reviews [get]
 func (h *BusinessReviewHandler) ListReviews(c *gin.Context) {
 	businessID := c.Param("business_id")
@@ -198,7 +194,7 @@ func (h *BusinessReviewHandler) ListReviews(c *gin.Context) {
 		h.sendErr(c, err)
 		return
 	}
-	utils.SendSuccess(c, http.StatusOK, "Reviews", gin.H{
+	utils.SendList(c, "Reviews", reviews, utils.OffsetMeta(limit, offset, total), gin.H{
 		"items":  reviews,
 		"total":  total,
 		"limit":  limit,
@@ -248,7 +244,7 @@ func (h *BusinessReviewHandler) GetStats(c *gin.Context) {
 // @Param        business_id path string true "Business profile id"
 // @Param        limit query int false "Page size (default 20, max 100)"
 // @Param        offset query int false "Offset (default 0)"
-// @Success      200 {object} utils.Response
+// @Success      200 {object} utils.ListResponse
 // @Router       /admin/businesses/{business_id}/reviews [get]
 func (h *BusinessReviewHandler) AdminListReviews(c *gin.Context) {
 	businessID := c.Param("business_id")
@@ -276,7 +272,7 @@ func (h *BusinessReviewHandler) AdminListReviews(c *gin.Context) {
 		return
 	}
 	stats, _ := h.service.Stats(c.Request.Context(), businessID)
-	utils.SendSuccess(c, http.StatusOK, "Reviews", gin.H{
+	utils.SendList(c, "Reviews", reviews, utils.OffsetMeta(limit, offset, total), gin.H{
 		"items":  reviews,
 		"total":  total,
 		"limit":  limit,
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/services"
 	"github.com/hamsaya/backend/internal/utils"
@@ -91,7 +92,7 @@ func (h *BusinessVerificationHandler) SubmitVerification(c *gin.Context) {
 			utils.SendError(c, http.StatusBadRequest, "Failed to read document", err)
 			return
 		}
-		photo, err := h.storageService.UploadImage(c.Request.Context(), file, header, services.ImageTypeVerification)
+		photo, err := h.storageService.UploadImage(c.Request.Context(), userID.(string), file, header, services.ImageTypeVerification)
 		_ = file.Close()
 		if err != nil {
 			h.handleError(c, err)
@@ -151,7 +152,7 @@ func (h *BusinessVerificationHandler) GetVerificationStatus(c *gin.Context) {
 // @Param status query string false "Filter: PENDING | APPROVED | REJECTED"
 // @Param limit query int false "Limit" default(20)
 // @Param offset query int false "Offset" default(0)
-// @Success 200 {object} utils.Response{data=[]models.BusinessVerificationListItem}
+// @Success 200 {object} utils.ListResponse{data=[]models.BusinessVerificationListItem}
 // @Failure 401 {object} utils.Response
 // @Router /admin/business-verifications [get]
 func (h *BusinessVerificationHandler) ListVerifications(c *gin.Context) {
@@ -175,7 +176,7 @@ func (h *BusinessVerificationHandler) ListVerifications(c *gin.Context) {
 		return
 	}
 
-	utils.SendSuccess(c, http.StatusOK, "Verification requests retrieved", map[string]interface{}{
+	utils.SendList(c, "Verification requests retrieved", items, utils.OffsetMeta(limit, offset, total), map[string]interface{}{
 		"items":  items,
 		"total":  total,
 		"limit":  limit,
@@ -241,10 +242,5 @@ func (h *BusinessVerificationHandler) ReviewVerification(c *gin.Context) {
 }
 
 func (h *BusinessVerificationHandler) handleError(c *gin.Context, err error) {
-	if appErr, ok := err.(*utils.AppError); ok {
-		utils.SendError(c, appErr.Code, appErr.Message, appErr.Err)
-		return
-	}
-	h.logger.Error("Unhandled error in business verification handler", zap.Error(err))
-	utils.SendError(c, http.StatusInternalServerError, "An error occurred", err)
+	middleware.RespondWithError(c, err)
 }
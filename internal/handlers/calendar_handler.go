@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/services"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/hamsaya/backend/pkg/ical"
+	"go.uber.org/zap"
+)
+
+// calendarFeedPath is where the personal feed is served, relative to the
+// API host.
+const calendarFeedPath = "/api/v1/users/me/events.ics"
+
+// CalendarHandler serves iCalendar exports of events.
+type CalendarHandler struct {
+	service *services.CalendarService
+	logger  *zap.Logger
+}
+
+// NewCalendarHandler wires the handler.
+func NewCalendarHandler(service *services.CalendarService, logger *zap.Logger) *CalendarHandler {
+	return &CalendarHandler{service: service, logger: logger}
+}
+
+func (h *CalendarHandler) sendErr(c *gin.Context, err error) {
+	middleware.RespondWithError(c, err)
+}
+
+// EventICS downloads one event as an .ics file.
+// @Tags         events
+// @Produce      text/calendar
+// @Param        post_id path string true "EVENT post id"
+// @Success      200 {string} string "iCalendar file"
+// @Failure      400 {object} utils.Response
+// @Failure      404 {object} utils.Response
+// @Router       /events/{post_id}/ics [get]
+func (h *CalendarHandler) EventICS(c *gin.Context) {
+	var viewerID *string
+	if id, exists := c.Get("user_id"); exists {
+		idStr := id.(string)
+		viewerID = &idStr
+	}
+	postID := c.Param("post_id")
+	body, err := h.service.EventICS(c.Request.Context(), postID, viewerID)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="event-`+postID+`.ics"`)
+	c.Data(http.StatusOK, ical.ContentType, body)
+}
+
+// EventsFeed serves the caller's subscription feed: every event they're
+// going to. Authenticated by the token query parameter, since calendar apps
+// can't send an Authorization header.
+// @Tags         events
+// @Produce      text/calendar
+// @Param        token query string true "Feed token"
+// @Success      200 {string} string "iCalendar feed"
+// @Failure      404 {object} utils.Response
+// @Router       /users/me/events.ics [get]
+func (h *CalendarHandler) EventsFeed(c *gin.Context) {
+	body, err := h.service.FeedICS(c.Request.Context(), c.Query("token"))
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, ical.ContentType, body)
+}
+
+// EnableEventsFeed creates the caller's feed token, replacing any earlier
+// one, and returns the URL to subscribe to.
+// @Tags         events
+// @Security     BearerAuth
+// @Success      200 {object} utils.Response{data=models.CalendarFeedResponse}
+// @Router       /users/me/calendar-feed [post]
+func (h *CalendarHandler) EnableEventsFeed(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+	token, err := h.service.EnableFeed(c.Request.Context(), userID.(string))
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	feedURL := scheme + "://" + c.Request.Host + calendarFeedPath + "?token=" + token
+	utils.SendSuccess(c, http.StatusOK, "Calendar feed enabled", &models.CalendarFeedResponse{
+		URL:       feedURL,
+		WebcalURL: "webcal://" + strings.TrimPrefix(strings.TrimPrefix(feedURL, "https://"), "http://"),
+		Token:     token,
+	})
+}
+
+// DisableEventsFeed revokes the caller's feed token.
+// @Tags         events
+// @Security     BearerAuth
+// @Success      200 {object} utils.Response
+// @Router       /users/me/calendar-feed [delete]
+func (h *CalendarHandler) DisableEventsFeed(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+	if err := h.service.DisableFeed(c.Request.Context(), userID.(string)); err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Calendar feed disabled", nil)
+}
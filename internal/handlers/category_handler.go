@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/services"
 	"github.com/hamsaya/backend/internal/utils"
@@ -206,13 +207,5 @@ func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 
 // handleError handles service errors and sends appropriate HTTP responses
 func (h *CategoryHandler) handleError(c *gin.Context, err error) {
-	// Check if it's an AppError
-	if appErr, ok := err.(*utils.AppError); ok {
-		utils.SendError(c, appErr.Code, appErr.Message, appErr.Err)
-		return
-	}
-
-	// Default to internal server error
-	h.logger.Error("Unhandled error in category handler", zap.Error(err))
-	utils.SendError(c, http.StatusInternalServerError, "An error occurred", err)
+	middleware.RespondWithError(c, err)
 }
@@ -5,10 +5,12 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 	"github.com/hamsaya/backend/config"
+	"github.com/hamsaya/backend/internal/middleware"
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/services"
 	"github.com/hamsaya/backend/internal/utils"
@@ -140,13 +142,17 @@ func (h *ChatHandler) HandleWebSocket(c *gin.Context) {
 		return
 	}
 
-	// Create client
+	// Create client. The token expiry is enforced on the open connection;
+	// clients refresh it with the auth frame.
 	client := &ws.Client{
 		ID:   userID.(string),
 		Conn: conn,
 		Hub:  h.wsHub,
 		Send: make(chan []byte, 256),
 	}
+	if exp := c.GetInt64("token_exp"); exp > 0 {
+		client.TokenExpiry = time.Unix(exp, 0)
+	}
 
 	// Register client with hub
 	h.wsHub.Register(client)
@@ -182,6 +188,10 @@ func (h *ChatHandler) SendMessage(c *gin.Context) {
 		return
 	}
 
+	if !middleware.ActingAs(c, &req.BusinessID) {
+		return
+	}
+
 	// Send message
 	message, err := h.chatService.SendMessage(c.Request.Context(), userID.(string), &req)
 	if err != nil {
@@ -192,7 +202,38 @@ func (h *ChatHandler) SendMessage(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusCreated, "Message sent successfully", message)
 }
 
-// GetConversations handles GET /api/v1/chat/conversations
+// SharePostToChats handles POST /api/v1/posts/:post_id/share/chat. The post
+// goes to each recipient as a POST message; per-recipient failures are
+// reported in the response instead of failing the request.
+func (h *ChatHandler) SharePostToChats(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	var req models.ShareToChatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	result, err := h.chatService.SharePost(c.Request.Context(), userID.(string), c.Param("post_id"), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Post shared to chat", result)
+}
+
+// GetConversations handles GET /api/v1/chat/conversations. With since
+// (RFC 3339) it returns a delta page: conversations changed after it plus
+// the IDs deleted since.
 func (h *ChatHandler) GetConversations(c *gin.Context) {
 	// Get authenticated user ID
 	userID, exists := c.Get("user_id")
@@ -201,9 +242,66 @@ func (h *ChatHandler) GetConversations(c *gin.Context) {
 		return
 	}
 
-	// Parse pagination
-	limit := 20
-	offset := 0
+	limit, offset := conversationPage(c)
+
+	// Optional business scope: when provided, return only conversations
+	// addressed to that business (caller must own it; service will verify).
+	// Acting as a business opens its inbox.
+	var businessID *string
+	if biz := c.Query("business_id"); biz != "" {
+		businessID = &biz
+	}
+	if !middleware.ActingAs(c, &businessID) {
+		return
+	}
+
+	since, ok := deltaSince(c)
+	if !ok {
+		return
+	}
+	if since != nil {
+		page, err := h.chatService.GetConversationChanges(c.Request.Context(), userID.(string), limit, businessID, *since)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		utils.SendSuccess(c, http.StatusOK, "Conversation changes retrieved successfully", page)
+		return
+	}
+
+	// Get conversations
+	conversations, err := h.chatService.GetConversations(c.Request.Context(), userID.(string), limit, offset, businessID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Conversations retrieved successfully", conversations)
+}
+
+// GetBusinessConversations handles GET /api/v1/businesses/:business_id/conversations,
+// the business inbox: chats customers started with the business. Owner only.
+func (h *ChatHandler) GetBusinessConversations(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	limit, offset := conversationPage(c)
+	businessID := c.Param("business_id")
+	conversations, err := h.chatService.GetConversations(c.Request.Context(), userID.(string), limit, offset, &businessID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Conversations retrieved successfully", conversations)
+}
+
+// conversationPage parses limit (default 20, max 100) and offset.
+func conversationPage(c *gin.Context) (limit, offset int) {
+	limit = 20
 	if limitStr := c.Query("limit"); limitStr != "" {
 		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
 			limit = l
@@ -214,25 +312,32 @@ func (h *ChatHandler) GetConversations(c *gin.Context) {
 			offset = o
 		}
 	}
+	return limit, offset
+}
 
-	// Optional business scope: when provided, return only conversations
-	// addressed to that business (caller must own it; service will verify).
-	var businessID *string
-	if biz := c.Query("business_id"); biz != "" {
-		businessID = &biz
+// GetUnreadBadge handles GET /api/v1/chat/badge
+func (h *ChatHandler) GetUnreadBadge(c *gin.Context) {
+	// Get authenticated user ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
 	}
 
-	// Get conversations
-	conversations, err := h.chatService.GetConversations(c.Request.Context(), userID.(string), limit, offset, businessID)
+	badge, err := h.chatService.GetUnreadBadge(c.Request.Context(), userID.(string))
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	utils.SendSuccess(c, http.StatusOK, "Conversations retrieved successfully", conversations)
+	utils.SendSuccess(c, http.StatusOK, "Unread badge retrieved successfully", badge)
 }
 
 // GetMessages handles GET /api/v1/chat/conversations/:conversation_id/messages
+//
+// With ?after_seq=N it returns the messages after seq N, oldest first, with
+// the cursor for the next call (reconnect sync). With ?before_seq=N it
+// returns older history, newest first. Otherwise limit/offset paging applies.
 func (h *ChatHandler) GetMessages(c *gin.Context) {
 	// Get authenticated user ID
 	userID, exists := c.Get("user_id")
@@ -261,8 +366,32 @@ func (h *ChatHandler) GetMessages(c *gin.Context) {
 		}
 	}
 
-	// Get messages
-	messages, err := h.chatService.GetMessages(c.Request.Context(), userID.(string), conversationID, limit, offset)
+	afterSeq, ok := parseSeq(c, "after_seq")
+	if !ok {
+		return
+	}
+	beforeSeq, ok := parseSeq(c, "before_seq")
+	if !ok {
+		return
+	}
+
+	if afterSeq != nil {
+		page, err := h.chatService.SyncMessages(c.Request.Context(), userID.(string), conversationID, *afterSeq, limit)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		utils.SendSuccess(c, http.StatusOK, "Messages retrieved successfully", page)
+		return
+	}
+
+	var messages []*models.MessageResponse
+	var err error
+	if beforeSeq != nil {
+		messages, err = h.chatService.GetMessagesBefore(c.Request.Context(), userID.(string), conversationID, *beforeSeq, limit)
+	} else {
+		messages, err = h.chatService.GetMessages(c.Request.Context(), userID.(string), conversationID, limit, offset)
+	}
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -271,6 +400,21 @@ func (h *ChatHandler) GetMessages(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusOK, "Messages retrieved successfully", messages)
 }
 
+// parseSeq reads a message seq query parameter; nil when absent. ok is
+// false when it isn't a non-negative integer, and a 400 has been sent.
+func parseSeq(c *gin.Context, name string) (*int64, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, true
+	}
+	seq, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || seq < 0 {
+		utils.SendError(c, http.StatusBadRequest, name+" must be a non-negative integer", utils.ErrBadRequest)
+		return nil, false
+	}
+	return &seq, true
+}
+
 // MarkConversationAsRead handles POST /api/v1/chat/conversations/:conversation_id/read
 func (h *ChatHandler) MarkConversationAsRead(c *gin.Context) {
 	// Get authenticated user ID
@@ -295,6 +439,41 @@ func (h *ChatHandler) MarkConversationAsRead(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusOK, "Conversation marked as read", nil)
 }
 
+// SetDisappearingMessages handles PUT /api/v1/chat/conversations/:conversation_id/disappearing
+// Turns disappearing messages on (24h, 7d) or off for the conversation.
+// Returns the SYSTEM message recording the change, or null when unchanged.
+func (h *ChatHandler) SetDisappearingMessages(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	conversationID := c.Param("conversation_id")
+	if conversationID == "" {
+		utils.SendError(c, http.StatusBadRequest, "Conversation ID is required", utils.ErrBadRequest)
+		return
+	}
+
+	var req models.SetDisappearingMessagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	message, err := h.chatService.SetDisappearingMessages(c.Request.Context(), userID.(string), conversationID, req.TTL)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Disappearing messages updated", message)
+}
+
 // DeleteMessage handles DELETE /api/v1/chat/messages/:message_id
 func (h *ChatHandler) DeleteMessage(c *gin.Context) {
 	// Get authenticated user ID
@@ -412,7 +591,8 @@ func (h *ChatHandler) reactToMessage(c *gin.Context, add bool) {
 		return
 	}
 
-	if err := h.chatService.ReactToMessage(c.Request.Context(), userID.(string), messageID, req.Emoji, add); err != nil {
+	reactions, err := h.chatService.ReactToMessage(c.Request.Context(), userID.(string), messageID, req.Emoji, add)
+	if err != nil {
 		h.handleError(c, err)
 		return
 	}
@@ -421,18 +601,10 @@ func (h *ChatHandler) reactToMessage(c *gin.Context, add bool) {
 	if !add {
 		msg = "Reaction removed"
 	}
-	utils.SendSuccess(c, http.StatusOK, msg, nil)
+	utils.SendSuccess(c, http.StatusOK, msg, gin.H{"reactions": reactions})
 }
 
 // handleError handles service errors and sends appropriate HTTP responses
 func (h *ChatHandler) handleError(c *gin.Context, err error) {
-	// Check if it's an AppError
-	if appErr, ok := err.(*utils.AppError); ok {
-		utils.SendError(c, appErr.Code, appErr.Message, appErr.Err)
-		return
-	}
-
-	// Default to internal server error
-	h.logger.Error("Unhandled error in chat handler", zap.Error(err))
-	utils.SendError(c, http.StatusInternalServerError, "An error occurred", err)
+	middleware.RespondWithError(c, err)
 }
@@ -171,6 +171,34 @@ func TestChatHandler_GetMessages(t *testing.T) {
 
 		assert.Less(t, w.Code, 500)
 	})
+
+	t.Run("after_seq returns a sync page", func(t *testing.T) {
+		convRepo := &mocks.MockConversationRepository{}
+		msgRepo := &mocks.MockMessageRepository{}
+		convRepo.On("IsParticipant", mock.Anything, chatTestConvID, chatTestUserID).Return(true, nil)
+		msgRepo.On("List", mock.Anything, mock.MatchedBy(func(f *models.GetMessagesFilter) bool {
+			return f.AfterSeq != nil && *f.AfterSeq == 5
+		})).Return([]*models.Message{}, nil)
+		r := newChatRouter(t, convRepo, msgRepo)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/chat/conversations/"+chatTestConvID+"/messages?after_seq=5", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"next_seq":5`)
+		msgRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid after_seq", func(t *testing.T) {
+		r := newChatRouter(t, &mocks.MockConversationRepository{}, &mocks.MockMessageRepository{})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/chat/conversations/"+chatTestConvID+"/messages?after_seq=-1", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
 }
 
 // --- MarkConversationAsRead ---
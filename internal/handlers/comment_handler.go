@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/services"
 	"github.com/hamsaya/backend/internal/utils"
@@ -68,6 +69,10 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 		return
 	}
 
+	if !middleware.ActingAs(c, &req.BusinessID) {
+		return
+	}
+
 	// Create comment
 	comment, err := h.commentService.CreateComment(c.Request.Context(), postID, userID.(string), &req)
 	if err != nil {
@@ -86,7 +91,9 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 // @Param post_id path string true "Post ID"
 // @Param limit query int false "Limit" default(20)
 // @Param offset query int false "Offset" default(0)
+// @Param sort query string false "newest, oldest or most_liked; defaults by post type"
 // @Success 200 {object} utils.Response{data=[]models.CommentResponse}
+// @Failure 400 {object} utils.Response
 // @Failure 404 {object} utils.Response
 // @Router /posts/{post_id}/comments [get]
 func (h *CommentHandler) GetPostComments(c *gin.Context) {
@@ -119,7 +126,8 @@ func (h *CommentHandler) GetPostComments(c *gin.Context) {
 	}
 
 	// Get comments
-	comments, err := h.commentService.GetPostComments(c.Request.Context(), postID, limit, offset, viewerID)
+	sort := models.CommentSort(c.Query("sort"))
+	comments, err := h.commentService.GetPostComments(c.Request.Context(), postID, sort, limit, offset, viewerID)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -284,6 +292,67 @@ func (h *CommentHandler) DeleteComment(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusOK, "Comment deleted successfully", nil)
 }
 
+// PinComment godoc
+// @Summary Pin a comment
+// @Description Pin a top-level comment to the top of the post's thread. Only the post owner may pin; pinning replaces any existing pin.
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param comment_id path string true "Comment ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /comments/{comment_id}/pin [post]
+func (h *CommentHandler) PinComment(c *gin.Context) {
+	// Get authenticated user ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	commentID := c.Param("comment_id")
+
+	if err := h.commentService.PinComment(c.Request.Context(), commentID, userID.(string)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Comment pinned successfully", nil)
+}
+
+// UnpinComment godoc
+// @Summary Unpin a comment
+// @Description Remove the pin from a comment. Only the post owner may unpin.
+// @Tags comments
+// @Produce json
+// @Security BearerAuth
+// @Param comment_id path string true "Comment ID"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /comments/{comment_id}/pin [delete]
+func (h *CommentHandler) UnpinComment(c *gin.Context) {
+	// Get authenticated user ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	commentID := c.Param("comment_id")
+
+	if err := h.commentService.UnpinComment(c.Request.Context(), commentID, userID.(string)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Comment unpinned successfully", nil)
+}
+
 // LikeComment godoc
 // @Summary Like a comment
 // @Description Like a comment
@@ -345,13 +414,5 @@ func (h *CommentHandler) UnlikeComment(c *gin.Context) {
 
 // handleError handles service errors and sends appropriate HTTP responses
 func (h *CommentHandler) handleError(c *gin.Context, err error) {
-	// Check if it's an AppError
-	if appErr, ok := err.(*utils.AppError); ok {
-		utils.SendError(c, appErr.Code, appErr.Message, appErr.Err)
-		return
-	}
-
-	// Default to internal server error
-	h.logger.Error("Unhandled error in comment handler", zap.Error(err))
-	utils.SendError(c, http.StatusInternalServerError, "An error occurred", err)
+	middleware.RespondWithError(c, err)
 }
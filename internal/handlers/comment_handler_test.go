@@ -137,7 +137,7 @@ func TestCommentHandler_GetPostComments(t *testing.T) {
 		postRepo := &mocks.MockPostRepository{}
 		post := testutil.CreateTestPost(commentTestPostID, "other-user", models.PostTypeFeed)
 		postRepo.On("GetByID", mock.Anything, commentTestPostID).Return(post, nil)
-		commentRepo.On("GetByPostID", mock.Anything, commentTestPostID, 20, 0).
+		commentRepo.On("GetByPostID", mock.Anything, commentTestPostID, models.CommentSortNewest, 20, 0).
 			Return([]*models.PostComment{}, nil)
 		r := newCommentRouter(t, commentRepo, postRepo, &mocks.MockUserRepository{})
 
@@ -154,7 +154,7 @@ func TestCommentHandler_GetPostComments(t *testing.T) {
 		postRepo := &mocks.MockPostRepository{}
 		post := testutil.CreateTestPost(commentTestPostID, "other-user", models.PostTypeFeed)
 		postRepo.On("GetByID", mock.Anything, commentTestPostID).Return(post, nil)
-		commentRepo.On("GetByPostID", mock.Anything, commentTestPostID, 20, 0).
+		commentRepo.On("GetByPostID", mock.Anything, commentTestPostID, models.CommentSortNewest, 20, 0).
 			Return(nil, fmt.Errorf("db error"))
 		r := newCommentRouter(t, commentRepo, postRepo, &mocks.MockUserRepository{})
 
@@ -201,9 +201,11 @@ func TestCommentHandler_DeleteComment(t *testing.T) {
 
 	t.Run("not owner", func(t *testing.T) {
 		commentRepo := &mocks.MockCommentRepository{}
-		comment := &models.PostComment{ID: commentTestCommentID, UserID: "other-user"}
+		comment := &models.PostComment{ID: commentTestCommentID, PostID: "post-1", UserID: "other-user"}
 		commentRepo.On("GetByID", mock.Anything, commentTestCommentID).Return(comment, nil)
-		r := newCommentRouter(t, commentRepo, &mocks.MockPostRepository{}, &mocks.MockUserRepository{})
+		postRepo := &mocks.MockPostRepository{}
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(testutil.CreateTestPost("post-1", "other-user", models.PostTypeFeed), nil)
+		r := newCommentRouter(t, commentRepo, postRepo, &mocks.MockUserRepository{})
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest(http.MethodDelete, "/api/v1/comments/"+commentTestCommentID, nil)
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/utils"
+)
+
+// ContentLimitsHandler serves the post and comment limits the API enforces,
+// so the mobile client's counters and pickers match the server.
+type ContentLimitsHandler struct {
+	limits models.ContentLimits
+}
+
+// NewContentLimitsHandler builds the handler from the configured limits.
+func NewContentLimitsHandler(limits models.ContentLimits) *ContentLimitsHandler {
+	return &ContentLimitsHandler{limits: limits}
+}
+
+// GetLimits godoc
+// @Summary Get content limits
+// @Description Per-post-type title/description lengths and attachment counts, poll and comment limits. Public.
+// @Tags config
+// @Produce json
+// @Success 200 {object} utils.Response{data=models.ContentLimits}
+// @Router /config/limits [get]
+func (h *ContentLimitsHandler) GetLimits(c *gin.Context) {
+	utils.SendSuccess(c, http.StatusOK, "Content limits", h.limits)
+}
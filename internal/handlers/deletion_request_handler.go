@@ -71,7 +71,7 @@ func (h *DeletionRequestHandler) Approve(c *gin.Context) {
 	adminID, _ := middleware.GetUserID(c)
 
 	if err := h.svc.Approve(c.Request.Context(), id, adminID, body.Notes); err != nil {
-		utils.SendError(c, http.StatusBadRequest, err.Error(), err)
+		middleware.RespondWithError(c, err)
 		return
 	}
 	_ = h.adminService.LogAuditAction(c.Request.Context(), adminID, "approve_deletion_request",
@@ -88,7 +88,7 @@ func (h *DeletionRequestHandler) Reject(c *gin.Context) {
 	adminID, _ := middleware.GetUserID(c)
 
 	if err := h.svc.Reject(c.Request.Context(), id, adminID, body.Notes); err != nil {
-		utils.SendError(c, http.StatusBadRequest, err.Error(), err)
+		middleware.RespondWithError(c, err)
 		return
 	}
 	_ = h.adminService.LogAuditAction(c.Request.Context(), adminID, "reject_deletion_request",
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/utils"
+)
+
+// deltaSince reads the delta-sync timestamp from ?since (or its alias
+// ?updated_after) as RFC 3339. since is nil when neither is set; ok is false
+// after a 400 has been sent for a malformed value.
+func deltaSince(c *gin.Context) (since *time.Time, ok bool) {
+	raw := c.Query("since")
+	if raw == "" {
+		raw = c.Query("updated_after")
+	}
+	if raw == "" {
+		return nil, true
+	}
+	t, err := time.Parse(time.RFC3339Nano, raw)
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid since timestamp; use RFC 3339", utils.ErrBadRequest)
+		return nil, false
+	}
+	return &t, true
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
+	"github.com/hamsaya/backend/internal/services"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// maxEmailWebhookBytes caps the webhook body. Resend event payloads are a
+// few KB; anything larger is not a legitimate callback.
+const maxEmailWebhookBytes = 64 << 10
+
+// EmailWebhookHandler receives email provider delivery callbacks.
+type EmailWebhookHandler struct {
+	deliveryService *services.EmailDeliveryService
+	logger          *zap.Logger
+}
+
+// NewEmailWebhookHandler creates a new email webhook handler
+func NewEmailWebhookHandler(deliveryService *services.EmailDeliveryService, logger *zap.Logger) *EmailWebhookHandler {
+	return &EmailWebhookHandler{
+		deliveryService: deliveryService,
+		logger:          logger,
+	}
+}
+
+// ResendWebhook godoc
+// @Summary Resend delivery webhook
+// @Description Receives bounce, complaint and delivery events from Resend. Authenticated by the Svix signature headers, not a bearer token.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /webhooks/email/resend [post]
+func (h *EmailWebhookHandler) ResendWebhook(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxEmailWebhookBytes))
+	if err != nil {
+		utils.SendBadRequest(c, "Failed to read request body", err)
+		return
+	}
+
+	if err := h.deliveryService.VerifyResendSignature(
+		c.GetHeader("svix-id"),
+		c.GetHeader("svix-timestamp"),
+		c.GetHeader("svix-signature"),
+		body,
+	); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	if err := h.deliveryService.HandleResendEvent(c.Request.Context(), body); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Event processed", nil)
+}
+
+// handleError handles service errors and sends appropriate HTTP responses
+func (h *EmailWebhookHandler) handleError(c *gin.Context, err error) {
+	middleware.RespondWithError(c, err)
+}
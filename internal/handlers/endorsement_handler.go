@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/services"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// EndorsementHandler exposes the endorsement endpoints under
+// /api/v1/users/:user_id/endorsements.
+type EndorsementHandler struct {
+	service   *services.EndorsementService
+	validator *utils.Validator
+	logger    *zap.Logger
+}
+
+// NewEndorsementHandler wires the handler.
+func NewEndorsementHandler(
+	service *services.EndorsementService,
+	validator *utils.Validator,
+	logger *zap.Logger,
+) *EndorsementHandler {
+	return &EndorsementHandler{
+		service:   service,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (h *EndorsementHandler) sendErr(c *gin.Context, err error) {
+	middleware.RespondWithError(c, err)
+}
+
+func (h *EndorsementHandler) currentUser(c *gin.Context) (string, bool) {
+	v, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return "", false
+	}
+	return v.(string), true
+}
+
+func endorsementPage(c *gin.Context) (limit, offset int) {
+	limit = 20
+	if v := c.Query("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			limit = n
+		}
+	}
+	if v := c.Query("offset"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			offset = n
+		}
+	}
+	return limit, offset
+}
+
+// Endorse creates or replaces the caller's endorsement of a user. It stays
+// hidden until the user approves it.
+// @Tags         endorsements
+// @Security     BearerAuth
+// @Param        user_id path string true "User id"
+// @Param        request body models.CreateEndorsementRequest true "Endorsement"
+// @Success      201 {object} utils.Response{data=models.Endorsement}
+// @Router       /users/{user_id}/endorsements [post]
+func (h *EndorsementHandler) Endorse(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateEndorsementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	endorsement, err := h.service.Endorse(c.Request.Context(), c.Param("user_id"), userID, &req)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusCreated, "Endorsement submitted for approval", endorsement)
+}
+
+// ListEndorsements returns a user's approved endorsements. On their own
+// profile users see every status and can filter with ?status=PENDING.
+// @Tags         endorsements
+// @Param        user_id path string true "User id"
+// @Param        status query string false "PENDING, APPROVED or REJECTED (own profile only)"
+// @Param        limit query int false "Page size (default 20, max 100)"
+// @Param        offset query int false "Offset (default 0)"
+// @Success      200 {object} utils.ListResponse
+// @Router       /users/{user_id}/endorsements [get]
+func (h *EndorsementHandler) ListEndorsements(c *gin.Context) {
+	var viewerID *string
+	if v, exists := c.Get("user_id"); exists {
+		id := v.(string)
+		viewerID = &id
+	}
+	subjectID := c.Param("user_id")
+	if subjectID == "me" {
+		if viewerID == nil {
+			utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+			return
+		}
+		subjectID = *viewerID
+	}
+	limit, offset := endorsementPage(c)
+
+	endorsements, total, err := h.service.List(c.Request.Context(), subjectID, viewerID, c.Query("status"), limit, offset)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendList(c, "Endorsements", endorsements, utils.OffsetMeta(limit, offset, total), gin.H{
+		"items":  endorsements,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// DecideEndorsement approves or rejects an endorsement on the caller's
+// profile.
+// @Tags         endorsements
+// @Security     BearerAuth
+// @Param        endorsement_id path string true "Endorsement id"
+// @Param        request body models.DecideEndorsementRequest true "Decision"
+// @Success      200 {object} utils.Response{data=models.Endorsement}
+// @Router       /users/me/endorsements/{endorsement_id} [put]
+func (h *EndorsementHandler) DecideEndorsement(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.DecideEndorsementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	endorsement, err := h.service.Decide(c.Request.Context(), c.Param("endorsement_id"), userID, &req)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Endorsement updated", endorsement)
+}
+
+// DeleteEndorsement removes an endorsement. The author and the endorsed
+// user may remove it.
+// @Tags         endorsements
+// @Security     BearerAuth
+// @Param        user_id path string true "User id"
+// @Param        endorsement_id path string true "Endorsement id"
+// @Success      200 {object} utils.Response
+// @Router       /users/{user_id}/endorsements/{endorsement_id} [delete]
+func (h *EndorsementHandler) DeleteEndorsement(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Delete(c.Request.Context(), c.Param("endorsement_id"), userID); err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Endorsement deleted", nil)
+}
+
+// ReportEndorsement reports an endorsement to the moderators.
+// @Tags         endorsements
+// @Security     BearerAuth
+// @Param        user_id path string true "User id"
+// @Param        endorsement_id path string true "Endorsement id"
+// @Param        request body models.ReportEndorsementRequest true "Report"
+// @Success      201 {object} utils.Response
+// @Router       /users/{user_id}/endorsements/{endorsement_id}/report [post]
+func (h *EndorsementHandler) ReportEndorsement(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.ReportEndorsementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	if err := h.service.Report(c.Request.Context(), c.Param("endorsement_id"), userID, &req); err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusCreated, "Endorsement reported", nil)
+}
+
+// AdminListReported returns reported endorsements, most reported first.
+// @Tags         admin
+// @Security     BearerAuth
+// @Param        limit query int false "Page size (default 20, max 100)"
+// @Param        offset query int false "Offset (default 0)"
+// @Success      200 {object} utils.ListResponse
+// @Router       /admin/endorsements/reported [get]
+func (h *EndorsementHandler) AdminListReported(c *gin.Context) {
+	limit, offset := endorsementPage(c)
+	reported, total, err := h.service.ListReported(c.Request.Context(), limit, offset)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendList(c, "Reported endorsements", reported, utils.OffsetMeta(limit, offset, total), gin.H{
+		"items":  reported,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// AdminDeleteEndorsement removes any endorsement.
+// @Tags         admin
+// @Security     BearerAuth
+// @Param        endorsement_id path string true "Endorsement id"
+// @Success      200 {object} utils.Response
+// @Router       /admin/endorsements/{endorsement_id} [delete]
+func (h *EndorsementHandler) AdminDeleteEndorsement(c *gin.Context) {
+	if err := h.service.AdminDelete(c.Request.Context(), c.Param("endorsement_id")); err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Endorsement deleted", nil)
+}
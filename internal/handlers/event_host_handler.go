@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/services"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// EventHostHandler exposes event co-hosting under
+// /api/v1/events/:post_id/hosts and the attendee message endpoint.
+type EventHostHandler struct {
+	service   *services.EventHostService
+	validator *utils.Validator
+	logger    *zap.Logger
+}
+
+// NewEventHostHandler wires the handler.
+func NewEventHostHandler(
+	service *services.EventHostService,
+	validator *utils.Validator,
+	logger *zap.Logger,
+) *EventHostHandler {
+	return &EventHostHandler{
+		service:   service,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (h *EventHostHandler) sendErr(c *gin.Context, err error) {
+	middleware.RespondWithError(c, err)
+}
+
+func (h *EventHostHandler) currentUser(c *gin.Context) (string, bool) {
+	v, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return "", false
+	}
+	return v.(string), true
+}
+
+// ListHosts lists the event's author and co-hosts. Hosts also see pending
+// invitations.
+// @Tags         events
+// @Param        post_id path string true "EVENT post id"
+// @Success      200 {object} utils.Response{data=[]models.EventHost}
+// @Router       /events/{post_id}/hosts [get]
+func (h *EventHostHandler) ListHosts(c *gin.Context) {
+	var viewerID *string
+	if id, exists := c.Get("user_id"); exists {
+		idStr := id.(string)
+		viewerID = &idStr
+	}
+	hosts, err := h.service.List(c.Request.Context(), c.Param("post_id"), viewerID)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Event hosts", hosts)
+}
+
+// InviteHost invites a user or a business to co-host the event (author only).
+// @Tags         events
+// @Security     BearerAuth
+// @Param        post_id path string true "EVENT post id"
+// @Param        request body models.InviteEventHostRequest true "Invitee"
+// @Success      201 {object} utils.Response{data=models.EventHost}
+// @Failure      403 {object} utils.Response
+// @Failure      409 {object} utils.Response
+// @Router       /events/{post_id}/hosts [post]
+func (h *EventHostHandler) InviteHost(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.InviteEventHostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	host, err := h.service.Invite(c.Request.Context(), c.Param("post_id"), userID, &req)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusCreated, "Co-host invited", host)
+}
+
+// AcceptHostInvite accepts an invitation to co-host the event.
+// @Tags         events
+// @Security     BearerAuth
+// @Param        post_id path string true "EVENT post id"
+// @Param        host_id path string true "Invitation id"
+// @Success      200 {object} utils.Response{data=models.EventHost}
+// @Failure      409 {object} utils.Response
+// @Router       /events/{post_id}/hosts/{host_id}/accept [post]
+func (h *EventHostHandler) AcceptHostInvite(c *gin.Context) {
+	h.respond(c, true)
+}
+
+// DeclineHostInvite declines an invitation to co-host the event.
+// @Tags         events
+// @Security     BearerAuth
+// @Param        post_id path string true "EVENT post id"
+// @Param        host_id path string true "Invitation id"
+// @Success      200 {object} utils.Response{data=models.EventHost}
+// @Failure      409 {object} utils.Response
+// @Router       /events/{post_id}/hosts/{host_id}/decline [post]
+func (h *EventHostHandler) DeclineHostInvite(c *gin.Context) {
+	h.respond(c, false)
+}
+
+func (h *EventHostHandler) respond(c *gin.Context, accept bool) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	host, err := h.service.Respond(c.Request.Context(), c.Param("post_id"), c.Param("host_id"), userID, accept)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	msg := "Invitation declined"
+	if accept {
+		msg = "Invitation accepted"
+	}
+	utils.SendSuccess(c, http.StatusOK, msg, host)
+}
+
+// RemoveHost removes a co-host. The author removes anyone; co-hosts can step
+// down.
+// @Tags         events
+// @Security     BearerAuth
+// @Param        post_id path string true "EVENT post id"
+// @Param        host_id path string true "Invitation id"
+// @Success      200 {object} utils.Response
+// @Failure      403 {object} utils.Response
+// @Router       /events/{post_id}/hosts/{host_id} [delete]
+func (h *EventHostHandler) RemoveHost(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Remove(c.Request.Context(), c.Param("post_id"), c.Param("host_id"), userID); err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Co-host removed", nil)
+}
+
+// MessageAttendees notifies the event's attendees with a message from one of
+// its hosts.
+// @Tags         events
+// @Security     BearerAuth
+// @Param        post_id path string true "EVENT post id"
+// @Param        request body models.MessageEventAttendeesRequest true "Message"
+// @Success      200 {object} utils.Response{data=models.MessageEventAttendeesResponse}
+// @Failure      403 {object} utils.Response
+// @Router       /events/{post_id}/message [post]
+func (h *EventHostHandler) MessageAttendees(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.MessageEventAttendeesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	resp, err := h.service.MessageAttendees(c.Request.Context(), c.Param("post_id"), userID, &req)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Attendees messaged", resp)
+}
+
+// ListInvitations lists the co-host invitations waiting on the caller,
+// including those sent to businesses they own.
+// @Tags         events
+// @Security     BearerAuth
+// @Success      200 {object} utils.Response{data=[]models.EventHostInvitation}
+// @Router       /users/me/event-host-invitations [get]
+func (h *EventHostHandler) ListInvitations(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	invitations, err := h.service.ListInvitations(c.Request.Context(), userID)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Event host invitations", invitations)
+}
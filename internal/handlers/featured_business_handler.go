@@ -0,0 +1,320 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/services"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Featured placement trackers dedupe per (placement, IP) like ads do.
+const (
+	featuredImpressionDedupeTTL = 30 * time.Minute
+	featuredClickDedupeTTL      = 1 * time.Hour
+)
+
+// FeaturedBusinessHandler exposes the public featured businesses list and
+// its trackers, the owner's placement report and the admin placement
+// management.
+type FeaturedBusinessHandler struct {
+	featuredService *services.FeaturedBusinessService
+	adminService    *services.AdminService
+	validator       *utils.Validator
+	logger          *zap.Logger
+	redis           *redis.Client
+}
+
+// NewFeaturedBusinessHandler constructs the handler. adminService is used
+// for audit-logging placement changes and redisClient for tracker dedupe;
+// either may be nil.
+func NewFeaturedBusinessHandler(
+	featuredService *services.FeaturedBusinessService,
+	adminService *services.AdminService,
+	validator *utils.Validator,
+	logger *zap.Logger,
+	redisClient *redis.Client,
+) *FeaturedBusinessHandler {
+	return &FeaturedBusinessHandler{
+		featuredService: featuredService,
+		adminService:    adminService,
+		validator:       validator,
+		logger:          logger,
+		redis:           redisClient,
+	}
+}
+
+// GetFeatured godoc
+// @Summary Featured businesses in a province
+// @Description Live featured placements for the province, in placement order. Report impressions and clicks against placement_id.
+// @Tags businesses
+// @Produce json
+// @Param province query string true "Province"
+// @Param limit query int false "Limit (default and max 10)"
+// @Success 200 {object} utils.Response{data=[]models.FeaturedBusinessCard}
+// @Failure 400 {object} utils.Response
+// @Router /businesses/featured [get]
+func (h *FeaturedBusinessHandler) GetFeatured(c *gin.Context) {
+	province := strings.TrimSpace(c.Query("province"))
+	if province == "" {
+		utils.SendError(c, http.StatusBadRequest, "province is required", utils.ErrBadRequest)
+		return
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	var viewerID *string
+	if id, exists := c.Get("user_id"); exists {
+		s := id.(string)
+		viewerID = &s
+	}
+
+	cards := h.featuredService.Featured(c.Request.Context(), province, viewerID, limit)
+	utils.SendSuccess(c, http.StatusOK, "Featured businesses retrieved", cards)
+}
+
+// RecordImpression godoc
+// @Summary Record a featured business impression
+// @Description Public, fire-and-forget; repeats from the same client within 30 minutes count once.
+// @Tags businesses
+// @Produce json
+// @Param placement_id path string true "Placement ID"
+// @Success 200 {object} utils.Response
+// @Router /businesses/featured/{placement_id}/impression [post]
+func (h *FeaturedBusinessHandler) RecordImpression(c *gin.Context) {
+	id := c.Param("placement_id")
+	if firstTrackingEvent(c, h.redis, h.logger, fmt.Sprintf("featured-dedupe:imp:%s:%s", id, c.ClientIP()), featuredImpressionDedupeTTL) {
+		if err := h.featuredService.RecordImpression(c.Request.Context(), id); err != nil {
+			h.logger.Warn("featured impression", zap.Error(err))
+		}
+	}
+	utils.SendSuccess(c, http.StatusOK, "ok", nil)
+}
+
+// RecordClick godoc
+// @Summary Record a featured business click
+// @Description Public, called before opening the business; repeats from the same client within an hour count once.
+// @Tags businesses
+// @Produce json
+// @Param placement_id path string true "Placement ID"
+// @Success 200 {object} utils.Response
+// @Router /businesses/featured/{placement_id}/click [post]
+func (h *FeaturedBusinessHandler) RecordClick(c *gin.Context) {
+	id := c.Param("placement_id")
+	if firstTrackingEvent(c, h.redis, h.logger, fmt.Sprintf("featured-dedupe:click:%s:%s", id, c.ClientIP()), featuredClickDedupeTTL) {
+		if err := h.featuredService.RecordClick(c.Request.Context(), id); err != nil {
+			h.logger.Warn("featured click", zap.Error(err))
+		}
+	}
+	utils.SendSuccess(c, http.StatusOK, "ok", nil)
+}
+
+// ListBusinessPlacements godoc
+// @Summary List a business's featured placements (owner only)
+// @Description Every placement of the business with its impressions and clicks.
+// @Tags businesses
+// @Produce json
+// @Security BearerAuth
+// @Param business_id path string true "Business ID"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} utils.ListResponse{data=[]models.FeaturedBusiness}
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /businesses/{business_id}/featured-placements [get]
+func (h *FeaturedBusinessHandler) ListBusinessPlacements(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+	limit, offset := featuredPage(c)
+
+	items, total, err := h.featuredService.ListForBusiness(c.Request.Context(), c.Param("business_id"), userID.(string), limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	utils.SendList(c, "Featured placements retrieved", items, utils.OffsetMeta(limit, offset, total), map[string]interface{}{
+		"items":  items,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// AdminList godoc
+// @Summary List featured business placements (admin)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param province query string false "Province"
+// @Param business_id query string false "Business ID"
+// @Param status query string false "scheduled | live | ended"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} utils.ListResponse{data=[]models.FeaturedBusiness}
+// @Failure 401 {object} utils.Response
+// @Router /admin/featured-businesses [get]
+func (h *FeaturedBusinessHandler) AdminList(c *gin.Context) {
+	limit, offset := featuredPage(c)
+	filter := models.FeaturedBusinessFilter{
+		Province:   c.Query("province"),
+		BusinessID: c.Query("business_id"),
+	}
+	switch v := c.Query("status"); v {
+	case models.FeaturedStatusScheduled, models.FeaturedStatusLive, models.FeaturedStatusEnded:
+		filter.Status = v
+	}
+
+	items, total, err := h.featuredService.List(c.Request.Context(), filter, limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	utils.SendList(c, "Featured placements retrieved", items, utils.OffsetMeta(limit, offset, total), map[string]interface{}{
+		"items":  items,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// AdminCreate godoc
+// @Summary Feature a business in a province (admin)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateFeaturedBusinessRequest true "Business, province, position and date window"
+// @Success 201 {object} utils.Response{data=models.FeaturedBusiness}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/featured-businesses [post]
+func (h *FeaturedBusinessHandler) AdminCreate(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+	var req models.CreateFeaturedBusinessRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	placement, err := h.featuredService.Create(c.Request.Context(), adminID.(string), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	h.audit(c, adminID.(string), "create_featured_business", placement)
+	utils.SendSuccess(c, http.StatusCreated, "Featured placement created", placement)
+}
+
+// AdminUpdate godoc
+// @Summary Edit a featured business placement (admin)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param placement_id path string true "Placement ID"
+// @Param request body models.UpdateFeaturedBusinessRequest true "Fields to change"
+// @Success 200 {object} utils.Response{data=models.FeaturedBusiness}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/featured-businesses/{placement_id} [put]
+func (h *FeaturedBusinessHandler) AdminUpdate(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+	var req models.UpdateFeaturedBusinessRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	placement, err := h.featuredService.Update(c.Request.Context(), c.Param("placement_id"), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	h.audit(c, adminID.(string), "update_featured_business", placement)
+	utils.SendSuccess(c, http.StatusOK, "Featured placement updated", placement)
+}
+
+// AdminDelete godoc
+// @Summary Remove a featured business placement (admin)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param placement_id path string true "Placement ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/featured-businesses/{placement_id} [delete]
+func (h *FeaturedBusinessHandler) AdminDelete(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+	id := c.Param("placement_id")
+	if err := h.featuredService.Delete(c.Request.Context(), id); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	if h.adminService != nil {
+		_ = h.adminService.LogAuditAction(c.Request.Context(), adminID.(string),
+			"delete_featured_business", "featured_business", id, nil, c.ClientIP())
+	}
+	utils.SendSuccess(c, http.StatusOK, "Featured placement deleted", nil)
+}
+
+// audit records a placement change; featuring is paid promotion, so every
+// change is traceable to an admin.
+func (h *FeaturedBusinessHandler) audit(c *gin.Context, adminID, action string, placement *models.FeaturedBusiness) {
+	if h.adminService == nil {
+		return
+	}
+	_ = h.adminService.LogAuditAction(c.Request.Context(), adminID, action, "featured_business", placement.ID,
+		map[string]interface{}{
+			"business_id": placement.BusinessID,
+			"province":    placement.Province,
+			"starts_at":   placement.StartsAt,
+			"ends_at":     placement.EndsAt,
+		}, c.ClientIP())
+}
+
+// featuredPage reads limit (default 20, max 100) and offset.
+func featuredPage(c *gin.Context) (limit, offset int) {
+	limit = 20
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+	if o, err := strconv.Atoi(c.Query("offset")); err == nil && o >= 0 {
+		offset = o
+	}
+	return limit, offset
+}
+
+func (h *FeaturedBusinessHandler) handleError(c *gin.Context, err error) {
+	middleware.RespondWithError(c, err)
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/services"
+)
+
+const (
+	// feedStreamHeartbeat keeps proxies from closing an idle stream.
+	feedStreamHeartbeat = 25 * time.Second
+	// feedStreamWriteWait bounds a single write; the server's WriteTimeout
+	// would otherwise end the stream after 15 seconds.
+	feedStreamWriteWait = 10 * time.Second
+)
+
+// FeedStreamHandler serves GET /posts/stream.
+type FeedStreamHandler struct {
+	stream *services.FeedStream
+}
+
+// NewFeedStreamHandler creates the handler.
+func NewFeedStreamHandler(stream *services.FeedStream) *FeedStreamHandler {
+	return &FeedStreamHandler{stream: stream}
+}
+
+// Stream godoc
+// @Summary Stream "new posts available" hints
+// @Description Server-Sent Events. Takes the scope parameters of GET /posts and sends a "new_posts" event with data {"count", "newest_id"} whenever posts in that scope are created; count is cumulative since the stream opened. Reconnect after refreshing the feed to reset it. Hints are approximate.
+// @Tags posts
+// @Produce text/event-stream
+// @Param type query string false "Post type"
+// @Param business_id query string false "Business ID"
+// @Param only_business query bool false "Only business posts"
+// @Param category_id query string false "Category ID"
+// @Param province query string false "Province"
+// @Param latitude query number false "Latitude"
+// @Param longitude query number false "Longitude"
+// @Param radius_km query number false "Radius in km"
+// @Success 200 {string} string "event stream"
+// @Router /posts/stream [get]
+func (h *FeedStreamHandler) Stream(c *gin.Context) {
+	viewerID := c.GetString("user_id")
+	sub := h.stream.Subscribe(feedStreamFilter(c), viewerID)
+	defer sub.Close()
+
+	rc := http.NewResponseController(c.Writer)
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	write := func(frame string) bool {
+		_ = rc.SetWriteDeadline(time.Now().Add(feedStreamWriteWait))
+		if _, err := fmt.Fprint(c.Writer, frame); err != nil {
+			return false
+		}
+		c.Writer.Flush()
+		return true
+	}
+	if !write(": connected\n\n") {
+		return
+	}
+
+	heartbeat := time.NewTicker(feedStreamHeartbeat)
+	defer heartbeat.Stop()
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			if !write(": ping\n\n") {
+				return
+			}
+		case <-sub.Updates():
+			data, _ := json.Marshal(sub.Latest())
+			if !write("event: new_posts\ndata: " + string(data) + "\n\n") {
+				return
+			}
+		}
+	}
+}
+
+// feedStreamFilter reads the scope parameters GetFeed accepts.
+func feedStreamFilter(c *gin.Context) *models.FeedFilter {
+	filter := &models.FeedFilter{}
+	if postType := c.Query("type"); postType != "" {
+		pt := models.PostType(postType)
+		filter.Type = &pt
+	}
+	if businessID := c.Query("business_id"); businessID != "" {
+		filter.BusinessID = &businessID
+	}
+	filter.OnlyBusiness = c.Query("only_business") == "true"
+	if categoryID := c.Query("category_id"); categoryID != "" {
+		filter.CategoryID = &categoryID
+	}
+	if province := c.Query("province"); province != "" {
+		filter.Province = &province
+	}
+	if lat, err := strconv.ParseFloat(c.Query("latitude"), 64); err == nil {
+		filter.Latitude = &lat
+	}
+	if lng, err := strconv.ParseFloat(c.Query("longitude"), 64); err == nil {
+		filter.Longitude = &lng
+	}
+	if radius, err := strconv.ParseFloat(c.Query("radius_km"), 64); err == nil {
+		filter.RadiusKm = &radius
+	}
+	// Same as the home feed: business feeds show every listing.
+	filter.HideUnpromotedSell = filter.BusinessID == nil
+	return filter
+}
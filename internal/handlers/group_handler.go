@@ -0,0 +1,418 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/services"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// GroupHandler exposes the groups endpoints under /api/v1/groups.
+type GroupHandler struct {
+	service   *services.GroupService
+	validator *utils.Validator
+	logger    *zap.Logger
+}
+
+// NewGroupHandler wires the handler.
+func NewGroupHandler(
+	service *services.GroupService,
+	validator *utils.Validator,
+	logger *zap.Logger,
+) *GroupHandler {
+	return &GroupHandler{
+		service:   service,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (h *GroupHandler) sendErr(c *gin.Context, err error) {
+	middleware.RespondWithError(c, err)
+}
+
+func (h *GroupHandler) currentUser(c *gin.Context) (string, bool) {
+	v, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return "", false
+	}
+	return v.(string), true
+}
+
+func optionalViewer(c *gin.Context) *string {
+	if id, exists := c.Get("user_id"); exists {
+		idStr := id.(string)
+		return &idStr
+	}
+	return nil
+}
+
+func pageParams(c *gin.Context) (int, int) {
+	limit := 20
+	offset := 0
+	if v := c.Query("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 100 {
+			limit = n
+		}
+	}
+	if v := c.Query("offset"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			offset = n
+		}
+	}
+	return limit, offset
+}
+
+// CreateGroup creates a group with the caller as admin.
+// @Tags         groups
+// @Security     BearerAuth
+// @Param        request body models.CreateGroupRequest true "Group"
+// @Success      201 {object} utils.Response{data=models.GroupResponse}
+// @Router       /groups [post]
+func (h *GroupHandler) CreateGroup(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	group, err := h.service.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusCreated, "Group created", group)
+}
+
+// GetGroup returns one group with the viewer's membership.
+// @Tags         groups
+// @Param        group_id path string true "Group id"
+// @Success      200 {object} utils.Response{data=models.GroupResponse}
+// @Router       /groups/{group_id} [get]
+func (h *GroupHandler) GetGroup(c *gin.Context) {
+	group, err := h.service.Get(c.Request.Context(), c.Param("group_id"), optionalViewer(c))
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Group", group)
+}
+
+// UpdateGroup edits a group (admins only).
+// @Tags         groups
+// @Security     BearerAuth
+// @Param        group_id path string true "Group id"
+// @Param        request body models.UpdateGroupRequest true "Changes"
+// @Success      200 {object} utils.Response{data=models.GroupResponse}
+// @Router       /groups/{group_id} [put]
+func (h *GroupHandler) UpdateGroup(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	group, err := h.service.Update(c.Request.Context(), c.Param("group_id"), userID, &req)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Group updated", group)
+}
+
+// DeleteGroup deletes a group (admins only).
+// @Tags         groups
+// @Security     BearerAuth
+// @Param        group_id path string true "Group id"
+// @Success      200 {object} utils.Response
+// @Router       /groups/{group_id} [delete]
+func (h *GroupHandler) DeleteGroup(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Delete(c.Request.Context(), c.Param("group_id"), userID); err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Group deleted", nil)
+}
+
+// ListGroups is the group directory.
+// @Tags         groups
+// @Param        search query string false "Name or description contains"
+// @Param        kind query string false "NEIGHBORHOOD or INTEREST"
+// @Param        province query string false "Province"
+// @Param        district query string false "District"
+// @Param        limit query int false "Page size (default 20, max 100)"
+// @Param        offset query int false "Offset (default 0)"
+// @Success      200 {object} utils.ListResponse
+// @Router       /groups [get]
+func (h *GroupHandler) ListGroups(c *gin.Context) {
+	limit, offset := pageParams(c)
+	filter := &models.GroupListFilter{Limit: limit, Offset: offset}
+	if v := strings.TrimSpace(c.Query("search")); v != "" {
+		filter.Search = &v
+	}
+	if v := strings.ToUpper(strings.TrimSpace(c.Query("kind"))); v != "" {
+		kind := models.GroupKind(v)
+		if kind != models.GroupKindNeighborhood && kind != models.GroupKindInterest {
+			utils.SendError(c, http.StatusBadRequest, "kind must be NEIGHBORHOOD or INTEREST", nil)
+			return
+		}
+		filter.Kind = &kind
+	}
+	if v := c.Query("province"); v != "" {
+		filter.Province = &v
+	}
+	if v := c.Query("district"); v != "" {
+		filter.District = &v
+	}
+
+	groups, total, err := h.service.List(c.Request.Context(), filter, optionalViewer(c))
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendList(c, "Groups", groups, utils.OffsetMeta(limit, offset, total), gin.H{
+		"items":  groups,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// ListMyGroups returns the groups the caller belongs to.
+// @Tags         groups
+// @Security     BearerAuth
+// @Param        limit query int false "Page size (default 20, max 100)"
+// @Param        offset query int false "Offset (default 0)"
+// @Success      200 {object} utils.ListResponse
+// @Router       /groups/me [get]
+func (h *GroupHandler) ListMyGroups(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	limit, offset := pageParams(c)
+	groups, total, err := h.service.ListMine(c.Request.Context(), userID, limit, offset)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendList(c, "Groups", groups, utils.OffsetMeta(limit, offset, total), gin.H{
+		"items":  groups,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// JoinGroup joins a public group or requests to join a private one.
+// @Tags         groups
+// @Security     BearerAuth
+// @Param        group_id path string true "Group id"
+// @Success      200 {object} utils.Response{data=models.GroupMember}
+// @Router       /groups/{group_id}/join [post]
+func (h *GroupHandler) JoinGroup(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	member, err := h.service.Join(c.Request.Context(), c.Param("group_id"), userID)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	msg := "Joined group"
+	if member.Status == models.GroupMemberPending {
+		msg = "Join request sent"
+	}
+	utils.SendSuccess(c, http.StatusOK, msg, member)
+}
+
+// LeaveGroup leaves a group or withdraws a pending join request.
+// @Tags         groups
+// @Security     BearerAuth
+// @Param        group_id path string true "Group id"
+// @Success      200 {object} utils.Response
+// @Router       /groups/{group_id}/leave [post]
+func (h *GroupHandler) LeaveGroup(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Leave(c.Request.Context(), c.Param("group_id"), userID); err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Left group", nil)
+}
+
+// ListMembers lists members, or pending join requests for moderators.
+// @Tags         groups
+// @Param        group_id path string true "Group id"
+// @Param        status query string false "ACTIVE (default) or PENDING"
+// @Param        limit query int false "Page size (default 20, max 100)"
+// @Param        offset query int false "Offset (default 0)"
+// @Success      200 {object} utils.ListResponse
+// @Router       /groups/{group_id}/members [get]
+func (h *GroupHandler) ListMembers(c *gin.Context) {
+	status := models.GroupMemberActive
+	if v := strings.ToUpper(c.Query("status")); v != "" {
+		status = models.GroupMemberStatus(v)
+		if status != models.GroupMemberActive && status != models.GroupMemberPending {
+			utils.SendError(c, http.StatusBadRequest, "status must be ACTIVE or PENDING", nil)
+			return
+		}
+	}
+	limit, offset := pageParams(c)
+
+	members, total, err := h.service.ListMembers(c.Request.Context(), c.Param("group_id"), optionalViewer(c), status, limit, offset)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendList(c, "Group members", members, utils.OffsetMeta(limit, offset, total), gin.H{
+		"items":  members,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// ApproveMember accepts a pending join request (admins and moderators).
+// @Tags         groups
+// @Security     BearerAuth
+// @Param        group_id path string true "Group id"
+// @Param        user_id path string true "Requesting user id"
+// @Success      200 {object} utils.Response
+// @Router       /groups/{group_id}/members/{user_id}/approve [post]
+func (h *GroupHandler) ApproveMember(c *gin.Context) {
+	actorID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	if err := h.service.ApproveRequest(c.Request.Context(), c.Param("group_id"), actorID, c.Param("user_id")); err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Join request approved", nil)
+}
+
+// RemoveMember removes a member or rejects a join request.
+// @Tags         groups
+// @Security     BearerAuth
+// @Param        group_id path string true "Group id"
+// @Param        user_id path string true "Member user id"
+// @Success      200 {object} utils.Response
+// @Router       /groups/{group_id}/members/{user_id} [delete]
+func (h *GroupHandler) RemoveMember(c *gin.Context) {
+	actorID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	if err := h.service.RemoveMember(c.Request.Context(), c.Param("group_id"), actorID, c.Param("user_id")); err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Member removed", nil)
+}
+
+// UpdateMemberRole changes a member's role (admins only).
+// @Tags         groups
+// @Security     BearerAuth
+// @Param        group_id path string true "Group id"
+// @Param        user_id path string true "Member user id"
+// @Param        request body models.UpdateGroupMemberRoleRequest true "Role"
+// @Success      200 {object} utils.Response
+// @Router       /groups/{group_id}/members/{user_id}/role [put]
+func (h *GroupHandler) UpdateMemberRole(c *gin.Context) {
+	actorID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateGroupMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	if err := h.service.SetRole(c.Request.Context(), c.Param("group_id"), actorID, c.Param("user_id"), req.Role); err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Member role updated", nil)
+}
+
+// GetGroupFeed returns the group's posts, newest first. To post into a
+// group, create a post with group_id set.
+// @Tags         groups
+// @Param        group_id path string true "Group id"
+// @Param        limit query int false "Page size (default 20, max 100)"
+// @Param        offset query int false "Offset (default 0)"
+// @Success      200 {object} utils.ListResponse
+// @Router       /groups/{group_id}/posts [get]
+func (h *GroupHandler) GetGroupFeed(c *gin.Context) {
+	limit, offset := pageParams(c)
+	posts, total, err := h.service.Feed(c.Request.Context(), c.Param("group_id"), optionalViewer(c), limit, offset)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendList(c, "Group posts", posts, utils.OffsetMeta(limit, offset, int(total)), gin.H{
+		"items":  posts,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// RemoveGroupPost takes a post down from the group (admins and moderators).
+// @Tags         groups
+// @Security     BearerAuth
+// @Param        group_id path string true "Group id"
+// @Param        post_id path string true "Post id"
+// @Success      200 {object} utils.Response
+// @Router       /groups/{group_id}/posts/{post_id} [delete]
+func (h *GroupHandler) RemoveGroupPost(c *gin.Context) {
+	actorID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	if err := h.service.RemovePost(c.Request.Context(), c.Param("group_id"), c.Param("post_id"), actorID); err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Post removed", nil)
+}
@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"runtime"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -20,25 +23,94 @@ var (
 	startTime = time.Now()
 )
 
+// HealthCheckFunc probes one dependency for the readiness endpoint. The
+// returned detail (e.g. "queue depth 12") is reported alongside the status;
+// a non-nil error marks the component unhealthy.
+type HealthCheckFunc func(ctx context.Context) (string, error)
+
+type healthCheck struct {
+	name  string
+	check HealthCheckFunc
+}
+
+// defaultCriticalComponents fail readiness outright. Every other registered
+// check only degrades the response so a flaky optional dependency (push,
+// object storage) doesn't pull the pod out of the load balancer.
+var defaultCriticalComponents = []string{"database", "redis"}
+
 // HealthHandler handles health check endpoints
 type HealthHandler struct {
-	db    *database.DB
-	redis *redis.Client
+	db       *database.DB
+	redis    *redis.Client
+	checks   []healthCheck
+	critical map[string]bool
 }
 
-// NewHealthHandler creates a new health handler
+// NewHealthHandler creates a new health handler. Database and Redis checks
+// are always registered; further components are added with WithCheck.
 func NewHealthHandler(db *database.DB, redis *redis.Client) *HealthHandler {
-	return &HealthHandler{
+	h := &HealthHandler{
 		db:    db,
 		redis: redis,
 	}
+	h.WithCriticalComponents(defaultCriticalComponents)
+	h.WithCheck("database", func(ctx context.Context) (string, error) {
+		if h.db == nil {
+			return "", errors.New("not configured")
+		}
+		return "", h.db.Health(ctx)
+	})
+	h.WithCheck("redis", func(ctx context.Context) (string, error) {
+		if h.redis == nil {
+			return "", errors.New("not configured")
+		}
+		return "", h.redis.Ping(ctx).Err()
+	})
+	return h
+}
+
+// WithCheck registers an additional readiness component. Registering a name
+// twice replaces the earlier check.
+func (h *HealthHandler) WithCheck(name string, check HealthCheckFunc) *HealthHandler {
+	for i := range h.checks {
+		if h.checks[i].name == name {
+			h.checks[i].check = check
+			return h
+		}
+	}
+	h.checks = append(h.checks, healthCheck{name: name, check: check})
+	return h
+}
+
+// WithCriticalComponents replaces the set of components whose failure makes
+// the service unready (503). An empty list keeps the current set.
+func (h *HealthHandler) WithCriticalComponents(names []string) *HealthHandler {
+	if len(names) == 0 {
+		return h
+	}
+	h.critical = make(map[string]bool, len(names))
+	for _, n := range names {
+		h.critical[n] = true
+	}
+	return h
 }
 
 // HealthResponse represents health check response
 type HealthResponse struct {
-	Status    string            `json:"status"`
-	Timestamp time.Time         `json:"timestamp"`
-	Services  map[string]string `json:"services"`
+	Status     string                     `json:"status"`
+	Timestamp  time.Time                  `json:"timestamp"`
+	Services   map[string]string          `json:"services"`
+	Components map[string]ComponentHealth `json:"components,omitempty"`
+	Warnings   []string                   `json:"warnings,omitempty"`
+}
+
+// ComponentHealth is the per-dependency result of a readiness probe.
+type ComponentHealth struct {
+	Status    string `json:"status"`
+	Critical  bool   `json:"critical"`
+	Detail    string `json:"detail,omitempty"`
+	Error     string `json:"error,omitempty"`
+	LatencyMS int64  `json:"latency_ms"`
 }
 
 // Health handles the basic health check
@@ -70,7 +142,7 @@ func (h *HealthHandler) Live(c *gin.Context) {
 
 // Ready handles the readiness probe
 // @Summary Readiness probe
-// @Description Check if the application is ready to serve traffic
+// @Description Check every registered dependency. Returns 200 "ready" when all are healthy, 200 "degraded" with warnings when only optional components fail, and 503 when a critical component fails.
 // @Tags health
 // @Produce json
 // @Success 200 {object} HealthResponse
@@ -80,48 +152,87 @@ func (h *HealthHandler) Ready(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
 	defer cancel()
 
-	services := make(map[string]string)
-	allHealthy := true
-
-	// Check database
-	if err := h.db.Health(ctx); err != nil {
-		services["database"] = "unhealthy: " + err.Error()
-		allHealthy = false
-	} else {
-		services["database"] = "healthy"
-	}
+	components := h.runChecks(ctx)
 
-	// Check Redis
-	if err := h.redis.Ping(ctx).Err(); err != nil {
-		services["redis"] = "unhealthy: " + err.Error()
-		allHealthy = false
-	} else {
-		services["redis"] = "healthy"
+	services := make(map[string]string, len(components))
+	warnings := []string{}
+	criticalDown := false
+	for name, comp := range components {
+		if comp.Status == "healthy" {
+			services[name] = "healthy"
+			continue
+		}
+		services[name] = "unhealthy: " + comp.Error
+		if comp.Critical {
+			criticalDown = true
+		} else {
+			warnings = append(warnings, name+": "+comp.Error)
+		}
 	}
+	sort.Strings(warnings)
 
 	status := "ready"
 	httpStatus := http.StatusOK
 	message := "Service ready"
 
-	if !allHealthy {
-		status = "degraded"
+	switch {
+	case criticalDown:
+		status = "unavailable"
 		httpStatus = http.StatusServiceUnavailable
+		message = "Service unavailable"
+	case len(warnings) > 0:
+		status = "degraded"
 		message = "Service degraded"
 	}
 
 	response := HealthResponse{
-		Status:    status,
-		Timestamp: time.Now(),
-		Services:  services,
+		Status:     status,
+		Timestamp:  time.Now(),
+		Services:   services,
+		Components: components,
+		Warnings:   warnings,
 	}
 
 	c.JSON(httpStatus, gin.H{
-		"success": allHealthy,
+		"success": !criticalDown,
 		"message": message,
 		"data":    response,
 	})
 }
 
+// runChecks probes every registered component concurrently so one slow
+// dependency costs the probe its own latency, not the sum of all of them.
+func (h *HealthHandler) runChecks(ctx context.Context) map[string]ComponentHealth {
+	results := make(map[string]ComponentHealth, len(h.checks))
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, hc := range h.checks {
+		wg.Add(1)
+		go func(hc healthCheck) {
+			defer wg.Done()
+			start := time.Now()
+			detail, err := hc.check(ctx)
+			comp := ComponentHealth{
+				Status:    "healthy",
+				Critical:  h.critical[hc.name],
+				Detail:    detail,
+				LatencyMS: time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				comp.Status = "unhealthy"
+				comp.Error = err.Error()
+			}
+			mu.Lock()
+			results[hc.name] = comp
+			mu.Unlock()
+		}(hc)
+	}
+	wg.Wait()
+	return results
+}
+
 // DBStats returns database connection pool statistics
 // @Summary Database statistics
 // @Description Get database connection pool statistics
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -93,3 +95,89 @@ func TestHealthHandler_Metrics(t *testing.T) {
 	assert.Contains(t, memory, "alloc_mb")
 	assert.Contains(t, memory, "heap_alloc_mb")
 }
+
+func healthyCheck(detail string) HealthCheckFunc {
+	return func(ctx context.Context) (string, error) { return detail, nil }
+}
+
+func failingCheck(msg string) HealthCheckFunc {
+	return func(ctx context.Context) (string, error) { return "", errors.New(msg) }
+}
+
+// readyHandler stubs the built-in database/redis checks so Ready can run
+// without live dependencies.
+func readyHandler() *HealthHandler {
+	return NewHealthHandler(nil, nil).
+		WithCheck("database", healthyCheck("")).
+		WithCheck("redis", healthyCheck(""))
+}
+
+func TestHealthHandler_Ready_AllHealthy(t *testing.T) {
+	h := readyHandler().WithCheck("storage", healthyCheck("media"))
+	r := gin.New()
+	r.GET("/health/ready", h.Ready)
+
+	w := doGet(r, "/health/ready")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := parseBody(t, w)
+	assert.True(t, body["success"].(bool))
+	data := body["data"].(map[string]interface{})
+	assert.Equal(t, "ready", data["status"])
+	assert.NotContains(t, data, "warnings")
+
+	components := data["components"].(map[string]interface{})
+	assert.Len(t, components, 3)
+	storage := components["storage"].(map[string]interface{})
+	assert.Equal(t, "healthy", storage["status"])
+	assert.Equal(t, "media", storage["detail"])
+	assert.False(t, storage["critical"].(bool))
+	assert.True(t, components["database"].(map[string]interface{})["critical"].(bool))
+}
+
+func TestHealthHandler_Ready_OptionalFailureDegrades(t *testing.T) {
+	h := readyHandler().WithCheck("fcm", failingCheck("not configured"))
+	r := gin.New()
+	r.GET("/health/ready", h.Ready)
+
+	w := doGet(r, "/health/ready")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	body := parseBody(t, w)
+	assert.True(t, body["success"].(bool))
+	data := body["data"].(map[string]interface{})
+	assert.Equal(t, "degraded", data["status"])
+	assert.Equal(t, []interface{}{"fcm: not configured"}, data["warnings"])
+	services := data["services"].(map[string]interface{})
+	assert.Equal(t, "unhealthy: not configured", services["fcm"])
+}
+
+func TestHealthHandler_Ready_CriticalFailureUnavailable(t *testing.T) {
+	h := readyHandler().WithCheck("redis", failingCheck("connection refused"))
+	r := gin.New()
+	r.GET("/health/ready", h.Ready)
+
+	w := doGet(r, "/health/ready")
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	body := parseBody(t, w)
+	assert.False(t, body["success"].(bool))
+	data := body["data"].(map[string]interface{})
+	assert.Equal(t, "unavailable", data["status"])
+}
+
+func TestHealthHandler_Ready_ConfigurableCritical(t *testing.T) {
+	h := readyHandler().
+		WithCriticalComponents([]string{"database", "storage"}).
+		WithCheck("storage", failingCheck("bucket missing")).
+		WithCheck("redis", failingCheck("timeout"))
+	r := gin.New()
+	r.GET("/health/ready", h.Ready)
+
+	w := doGet(r, "/health/ready")
+
+	// storage is now critical; redis was demoted to optional.
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	data := parseBody(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"redis: timeout"}, data["warnings"])
+}
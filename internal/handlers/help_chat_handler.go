@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/services"
 	"github.com/hamsaya/backend/internal/utils"
@@ -61,7 +62,7 @@ func (h *HelpChatHandler) SendMessage(c *gin.Context) {
 // @Security BearerAuth
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(50)
-// @Success 200 {object} utils.Response{data=[]models.HelpChatMessage}
+// @Success 200 {object} utils.ListResponse{data=[]models.HelpChatMessage}
 // @Router /help-chat/messages [get]
 func (h *HelpChatHandler) GetMessages(c *gin.Context) {
 	userID := c.GetString("user_id")
@@ -72,7 +73,7 @@ func (h *HelpChatHandler) GetMessages(c *gin.Context) {
 		h.handleError(c, err)
 		return
 	}
-	utils.SendSuccess(c, http.StatusOK, "Messages retrieved", gin.H{
+	utils.SendList(c, "Messages retrieved", msgs, utils.PageMeta(page, limit, total), gin.H{
 		"messages": msgs,
 		"total":    total,
 	})
@@ -86,7 +87,7 @@ func (h *HelpChatHandler) GetMessages(c *gin.Context) {
 // @Security BearerAuth
 // @Param page query int false "Page" default(1)
 // @Param limit query int false "Per page" default(50)
-// @Success 200 {object} utils.Response
+// @Success 200 {object} utils.ListResponse
 // @Router /admin/help-chat [get]
 func (h *HelpChatHandler) AdminGetThreads(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
@@ -96,7 +97,7 @@ func (h *HelpChatHandler) AdminGetThreads(c *gin.Context) {
 		h.handleError(c, err)
 		return
 	}
-	utils.SendSuccess(c, http.StatusOK, "Threads retrieved", gin.H{
+	utils.SendList(c, "Threads retrieved", threads, utils.PageMeta(page, limit, total), gin.H{
 		"threads": threads,
 		"total":   total,
 	})
@@ -110,7 +111,7 @@ func (h *HelpChatHandler) AdminGetThreads(c *gin.Context) {
 // @Param user_id path string true "User ID"
 // @Param page query int false "Page" default(1)
 // @Param limit query int false "Per page" default(50)
-// @Success 200 {object} utils.Response{data=[]models.HelpChatMessage}
+// @Success 200 {object} utils.ListResponse{data=[]models.HelpChatMessage}
 // @Router /admin/help-chat/{user_id} [get]
 func (h *HelpChatHandler) AdminGetUserThread(c *gin.Context) {
 	userID := c.Param("user_id")
@@ -121,7 +122,7 @@ func (h *HelpChatHandler) AdminGetUserThread(c *gin.Context) {
 		h.handleError(c, err)
 		return
 	}
-	utils.SendSuccess(c, http.StatusOK, "Messages retrieved", gin.H{
+	utils.SendList(c, "Messages retrieved", msgs, utils.PageMeta(page, limit, total), gin.H{
 		"messages": msgs,
 		"total":    total,
 	})
@@ -158,10 +159,5 @@ func (h *HelpChatHandler) AdminReply(c *gin.Context) {
 }
 
 func (h *HelpChatHandler) handleError(c *gin.Context, err error) {
-	if appErr, ok := err.(*utils.AppError); ok {
-		utils.SendError(c, appErr.Code, appErr.Message, appErr.Err)
-		return
-	}
-	h.logger.Error("Unhandled error in help chat handler", zap.Error(err))
-	utils.SendError(c, http.StatusInternalServerError, "An error occurred", err)
+	middleware.RespondWithError(c, err)
 }
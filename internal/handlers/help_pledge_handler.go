@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/services"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// HelpPledgeHandler exposes the pledge endpoints on HELP posts under
+// /api/v1/posts/:post_id/pledges.
+type HelpPledgeHandler struct {
+	service   *services.HelpPledgeService
+	validator *utils.Validator
+	logger    *zap.Logger
+}
+
+// NewHelpPledgeHandler wires the handler.
+func NewHelpPledgeHandler(
+	service *services.HelpPledgeService,
+	validator *utils.Validator,
+	logger *zap.Logger,
+) *HelpPledgeHandler {
+	return &HelpPledgeHandler{
+		service:   service,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (h *HelpPledgeHandler) sendErr(c *gin.Context, err error) {
+	middleware.RespondWithError(c, err)
+}
+
+func (h *HelpPledgeHandler) currentUser(c *gin.Context) (string, bool) {
+	v, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return "", false
+	}
+	return v.(string), true
+}
+
+// Pledge offers items or time on a help request. Pledging again updates the
+// caller's open pledge.
+// @Tags         help-pledges
+// @Security     BearerAuth
+// @Param        post_id path string true "HELP post id"
+// @Param        request body models.CreatePledgeRequest true "Pledge"
+// @Success      201 {object} utils.Response{data=models.HelpPledge}
+// @Failure      409 {object} utils.Response
+// @Router       /posts/{post_id}/pledges [post]
+func (h *HelpPledgeHandler) Pledge(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreatePledgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	pledge, err := h.service.Pledge(c.Request.Context(), c.Param("post_id"), userID, &req)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusCreated, "Pledge recorded", pledge)
+}
+
+// WithdrawPledge takes back the caller's open pledge.
+// @Tags         help-pledges
+// @Security     BearerAuth
+// @Param        post_id path string true "HELP post id"
+// @Success      200 {object} utils.Response
+// @Router       /posts/{post_id}/pledges/me [delete]
+func (h *HelpPledgeHandler) WithdrawPledge(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Withdraw(c.Request.Context(), c.Param("post_id"), userID); err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Pledge withdrawn", nil)
+}
+
+// ListPledges lists the open and confirmed pledges on a help request.
+// @Tags         help-pledges
+// @Param        post_id path string true "HELP post id"
+// @Param        limit query int false "Page size (default 20, max 100)"
+// @Param        offset query int false "Offset (default 0)"
+// @Success      200 {object} utils.ListResponse
+// @Router       /posts/{post_id}/pledges [get]
+func (h *HelpPledgeHandler) ListPledges(c *gin.Context) {
+	limit, offset := pageParams(c)
+	pledges, total, err := h.service.List(c.Request.Context(), c.Param("post_id"), limit, offset)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendList(c, "Pledges", pledges, utils.OffsetMeta(limit, offset, total), gin.H{
+		"items":  pledges,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// ConfirmPledge marks a pledge as delivered (post author only).
+// @Tags         help-pledges
+// @Security     BearerAuth
+// @Param        post_id path string true "HELP post id"
+// @Param        pledge_id path string true "Pledge id"
+// @Success      200 {object} utils.Response{data=models.HelpPledge}
+// @Failure      409 {object} utils.Response
+// @Router       /posts/{post_id}/pledges/{pledge_id}/confirm [post]
+func (h *HelpPledgeHandler) ConfirmPledge(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	pledge, err := h.service.ConfirmPledge(c.Request.Context(), c.Param("post_id"), c.Param("pledge_id"), userID)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Pledge confirmed", pledge)
+}
+
+// MarkFulfilled closes a help request and notifies its pledgers (post
+// author only).
+// @Tags         help-pledges
+// @Security     BearerAuth
+// @Param        post_id path string true "HELP post id"
+// @Success      200 {object} utils.Response{data=models.HelpSummary}
+// @Failure      409 {object} utils.Response
+// @Router       /posts/{post_id}/fulfill [post]
+func (h *HelpPledgeHandler) MarkFulfilled(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	summary, err := h.service.MarkFulfilled(c.Request.Context(), c.Param("post_id"), userID)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Help request fulfilled", summary)
+}
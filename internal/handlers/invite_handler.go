@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
+	"github.com/hamsaya/backend/internal/services"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// InviteHandler exposes the caller's invite link and the admin referral
+// report.
+type InviteHandler struct {
+	service *services.InviteService
+	logger  *zap.Logger
+}
+
+// NewInviteHandler wires the handler.
+func NewInviteHandler(service *services.InviteService, logger *zap.Logger) *InviteHandler {
+	return &InviteHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *InviteHandler) sendErr(c *gin.Context, err error) {
+	middleware.RespondWithError(c, err)
+}
+
+// GetMyInvite returns the caller's invite code and link, creating the code
+// on first use, with how many people signed up through it.
+// @Tags         users
+// @Security     BearerAuth
+// @Success      200 {object} utils.Response{data=models.InviteLink}
+// @Router       /users/me/invite [get]
+func (h *InviteHandler) GetMyInvite(c *gin.Context) {
+	v, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	invite, err := h.service.GetMyInvite(c.Request.Context(), v.(string))
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Invite link", invite)
+}
+
+// AdminTopReferrers returns the users whose invites brought in the most
+// signups.
+// @Tags         admin
+// @Security     BearerAuth
+// @Param        days query int false "Only count signups from the last N days (default all time)"
+// @Param        limit query int false "Rows (default 20, max 100)"
+// @Success      200 {object} utils.Response{data=[]models.TopReferrer}
+// @Router       /admin/referrals/top [get]
+func (h *InviteHandler) AdminTopReferrers(c *gin.Context) {
+	days, _ := strconv.Atoi(c.Query("days"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	if days < 0 {
+		utils.SendError(c, http.StatusBadRequest, "days must not be negative", nil)
+		return
+	}
+
+	top, err := h.service.TopReferrers(c.Request.Context(), days, limit)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Top referrers", top)
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/services"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// LifecycleTemplateHandler lets admins edit the copy of lifecycle messages
+// (welcome, first-post nudge, re-engagement) under
+// /api/v1/admin/lifecycle-templates. Their timing is a runtime setting
+// (lifecycle.*) under /admin/system/settings. Every change is audited.
+type LifecycleTemplateHandler struct {
+	service      *services.LifecycleMessageService
+	adminService *services.AdminService
+	validator    *utils.Validator
+	logger       *zap.Logger
+}
+
+// NewLifecycleTemplateHandler wires the handler.
+func NewLifecycleTemplateHandler(service *services.LifecycleMessageService, adminService *services.AdminService, validator *utils.Validator, logger *zap.Logger) *LifecycleTemplateHandler {
+	return &LifecycleTemplateHandler{
+		service:      service,
+		adminService: adminService,
+		validator:    validator,
+		logger:       logger,
+	}
+}
+
+func lifecycleKind(c *gin.Context) models.NotificationType {
+	return models.NotificationType(strings.ToUpper(c.Param("kind")))
+}
+
+// List returns the effective copy of every lifecycle message.
+// @Tags         admin
+// @Security     BearerAuth
+// @Success      200 {object} utils.Response{data=[]models.LifecycleTemplate}
+// @Router       /admin/lifecycle-templates [get]
+func (h *LifecycleTemplateHandler) List(c *gin.Context) {
+	templates, err := h.service.List(c.Request.Context())
+	if err != nil {
+		middleware.RespondWithError(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Lifecycle templates", templates)
+}
+
+// Update replaces a lifecycle message's copy, or switches it off.
+// @Tags         admin
+// @Security     BearerAuth
+// @Param        kind path string true "WELCOME, FIRST_POST_NUDGE or WINBACK"
+// @Param        request body models.UpdateLifecycleTemplateRequest true "Copy"
+// @Success      200 {object} utils.Response{data=models.LifecycleTemplate}
+// @Failure      400 {object} utils.Response
+// @Failure      404 {object} utils.Response
+// @Router       /admin/lifecycle-templates/{kind} [put]
+func (h *LifecycleTemplateHandler) Update(c *gin.Context) {
+	var req models.UpdateLifecycleTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+	adminID, _ := middleware.GetUserID(c)
+	kind := lifecycleKind(c)
+
+	template, err := h.service.Update(c.Request.Context(), kind, adminID, &req)
+	if err != nil {
+		middleware.RespondWithError(c, err)
+		return
+	}
+	_ = h.adminService.LogAuditAction(c.Request.Context(), adminID, "update_lifecycle_template", "lifecycle_template", string(kind),
+		map[string]interface{}{"title": template.Title, "message": template.Message, "enabled": template.Enabled}, c.ClientIP())
+	utils.SendSuccess(c, http.StatusOK, "Lifecycle template updated", template)
+}
+
+// Reset restores a lifecycle message's built-in copy.
+// @Tags         admin
+// @Security     BearerAuth
+// @Param        kind path string true "WELCOME, FIRST_POST_NUDGE or WINBACK"
+// @Success      200 {object} utils.Response{data=models.LifecycleTemplate}
+// @Failure      404 {object} utils.Response
+// @Router       /admin/lifecycle-templates/{kind} [delete]
+func (h *LifecycleTemplateHandler) Reset(c *gin.Context) {
+	adminID, _ := middleware.GetUserID(c)
+	kind := lifecycleKind(c)
+
+	template, err := h.service.Reset(c.Request.Context(), kind)
+	if err != nil {
+		middleware.RespondWithError(c, err)
+		return
+	}
+	_ = h.adminService.LogAuditAction(c.Request.Context(), adminID, "reset_lifecycle_template", "lifecycle_template", string(kind), nil, c.ClientIP())
+	utils.SendSuccess(c, http.StatusOK, "Lifecycle template reset", template)
+}
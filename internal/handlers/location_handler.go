@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/services"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// LocationHandler serves the province / district / neighborhood reference
+// list: public pickers under /locations, admin CRUD under /admin/locations.
+type LocationHandler struct {
+	service   *services.LocationService
+	validator *utils.Validator
+	logger    *zap.Logger
+}
+
+// NewLocationHandler wires the handler.
+func NewLocationHandler(service *services.LocationService, validator *utils.Validator, logger *zap.Logger) *LocationHandler {
+	return &LocationHandler{
+		service:   service,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (h *LocationHandler) sendErr(c *gin.Context, err error) {
+	middleware.RespondWithError(c, err)
+}
+
+// ListLocations returns picker options: provinces by default, or the
+// children of parent_id. Labels follow ?locale=en|dari|pashto or
+// Accept-Language; send back the value field in province/district.
+// @Tags         locations
+// @Param        parent_id query string false "Province id (districts) or district id (neighborhoods)"
+// @Param        locale query string false "en, dari or pashto"
+// @Success      200 {object} utils.Response{data=[]models.LocationOption}
+// @Router       /locations [get]
+func (h *LocationHandler) ListLocations(c *gin.Context) {
+	options, err := h.service.Options(c.Request.Context(), c.Query("parent_id"), categoryLocale(c))
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+
+	// Reference data changes rarely; same caching as categories.
+	c.Header("Cache-Control", "public, max-age=3600, s-maxage=3600")
+	c.Header("Vary", "Accept-Language")
+
+	utils.SendSuccess(c, http.StatusOK, "Locations retrieved successfully", options)
+}
+
+// AdminListLocations returns provinces or the children of parent_id,
+// including inactive entries.
+// @Tags         admin-locations
+// @Security     BearerAuth
+// @Param        parent_id query string false "Parent location id"
+// @Success      200 {object} utils.Response{data=[]models.LocationRef}
+// @Router       /admin/locations [get]
+func (h *LocationHandler) AdminListLocations(c *gin.Context) {
+	locs, err := h.service.List(c.Request.Context(), c.Query("parent_id"))
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Locations retrieved successfully", locs)
+}
+
+// CreateLocation adds a province, district or neighborhood.
+// @Tags         admin-locations
+// @Security     BearerAuth
+// @Param        request body models.CreateLocationRequest true "Location"
+// @Success      201 {object} utils.Response{data=models.LocationRef}
+// @Failure      409 {object} utils.Response
+// @Router       /admin/locations [post]
+func (h *LocationHandler) CreateLocation(c *gin.Context) {
+	var req models.CreateLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	loc, err := h.service.Create(c.Request.Context(), &req)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusCreated, "Location created successfully", loc)
+}
+
+// UpdateLocation renames, reorders or (de)activates a location. Renames are
+// applied to existing profiles, businesses, posts and groups.
+// @Tags         admin-locations
+// @Security     BearerAuth
+// @Param        location_id path string true "Location id"
+// @Param        request body models.UpdateLocationRequest true "Changes"
+// @Success      200 {object} utils.Response{data=models.LocationRef}
+// @Router       /admin/locations/{location_id} [put]
+func (h *LocationHandler) UpdateLocation(c *gin.Context) {
+	var req models.UpdateLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	loc, err := h.service.Update(c.Request.Context(), c.Param("location_id"), &req)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Location updated successfully", loc)
+}
+
+// DeleteLocation removes a location and its children.
+// @Tags         admin-locations
+// @Security     BearerAuth
+// @Param        location_id path string true "Location id"
+// @Success      200 {object} utils.Response
+// @Router       /admin/locations/{location_id} [delete]
+func (h *LocationHandler) DeleteLocation(c *gin.Context) {
+	if err := h.service.Delete(c.Request.Context(), c.Param("location_id")); err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Location deleted successfully", nil)
+}
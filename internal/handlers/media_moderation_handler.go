@@ -10,10 +10,14 @@ import (
 	"go.uber.org/zap"
 )
 
+// mediaModerationPageSize is how many queue rows each list returns.
+const mediaModerationPageSize = 100
+
 // MediaModerationHandler exposes the admin media-review queue.
 type MediaModerationHandler struct {
 	svc          *services.MediaModerationService
 	adminService *services.AdminService
+	profiles     *services.ProfileMediaService
 	logger       *zap.Logger
 }
 
@@ -22,16 +26,17 @@ func NewMediaModerationHandler(svc *services.MediaModerationService, adminServic
 }
 
 // List godoc
+// @Success 200 {object} utils.ListResponse
 // @Router /admin/media-moderation [get]
 func (h *MediaModerationHandler) List(c *gin.Context) {
 	status := c.Query("status")
-	rows, err := h.svc.List(c.Request.Context(), status, 100)
+	rows, err := h.svc.List(c.Request.Context(), status, c.Query("flagged") == "true", mediaModerationPageSize)
 	if err != nil {
 		utils.SendError(c, http.StatusInternalServerError, "Query failed", err)
 		return
 	}
 	counts, _ := h.svc.Counts(c.Request.Context())
-	utils.SendSuccess(c, http.StatusOK, "ok", gin.H{"items": rows, "counts": counts})
+	utils.SendList(c, "ok", rows, utils.ListMeta{Limit: mediaModerationPageSize, Counts: counts}, gin.H{"items": rows, "counts": counts})
 }
 
 type reviewMediaBody struct {
@@ -46,7 +51,7 @@ func (h *MediaModerationHandler) Approve(c *gin.Context) {
 	_ = c.ShouldBindJSON(&body)
 	adminID, _ := middleware.GetUserID(c)
 	if err := h.svc.Approve(c.Request.Context(), id, adminID, body.Notes); err != nil {
-		utils.SendError(c, http.StatusBadRequest, err.Error(), err)
+		h.sendErr(c, err)
 		return
 	}
 	_ = h.adminService.LogAuditAction(c.Request.Context(), adminID, "approve_media", "attachment", id,
@@ -62,10 +67,112 @@ func (h *MediaModerationHandler) Reject(c *gin.Context) {
 	_ = c.ShouldBindJSON(&body)
 	adminID, _ := middleware.GetUserID(c)
 	if err := h.svc.Reject(c.Request.Context(), id, adminID, body.Notes); err != nil {
-		utils.SendError(c, http.StatusBadRequest, err.Error(), err)
+		h.sendErr(c, err)
 		return
 	}
 	_ = h.adminService.LogAuditAction(c.Request.Context(), adminID, "reject_media", "attachment", id,
 		map[string]interface{}{"notes": body.Notes}, c.ClientIP())
 	utils.SendSuccess(c, http.StatusOK, "Rejected + attachment deleted", nil)
 }
+
+// ListBusiness godoc
+// @Success 200 {object} utils.ListResponse
+// @Router /admin/media-moderation/businesses [get]
+func (h *MediaModerationHandler) ListBusiness(c *gin.Context) {
+	status := c.Query("status")
+	rows, err := h.svc.ListBusiness(c.Request.Context(), status, c.Query("flagged") == "true", mediaModerationPageSize)
+	if err != nil {
+		utils.SendError(c, http.StatusInternalServerError, "Query failed", err)
+		return
+	}
+	counts, _ := h.svc.BusinessCounts(c.Request.Context())
+	utils.SendList(c, "ok", rows, utils.ListMeta{Limit: mediaModerationPageSize, Counts: counts}, gin.H{"items": rows, "counts": counts})
+}
+
+// ApproveBusiness godoc
+// @Router /admin/media-moderation/businesses/{media_id}/approve [post]
+func (h *MediaModerationHandler) ApproveBusiness(c *gin.Context) {
+	id := c.Param("media_id")
+	var body reviewMediaBody
+	_ = c.ShouldBindJSON(&body)
+	adminID, _ := middleware.GetUserID(c)
+	if err := h.svc.ApproveBusiness(c.Request.Context(), id, adminID, body.Notes); err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	_ = h.adminService.LogAuditAction(c.Request.Context(), adminID, "approve_media", "business_media", id,
+		map[string]interface{}{"notes": body.Notes}, c.ClientIP())
+	utils.SendSuccess(c, http.StatusOK, "Approved", nil)
+}
+
+// RejectBusiness godoc
+// @Router /admin/media-moderation/businesses/{media_id}/reject [post]
+func (h *MediaModerationHandler) RejectBusiness(c *gin.Context) {
+	id := c.Param("media_id")
+	var body reviewMediaBody
+	_ = c.ShouldBindJSON(&body)
+	adminID, _ := middleware.GetUserID(c)
+	if err := h.svc.RejectBusiness(c.Request.Context(), id, adminID, body.Notes); err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	_ = h.adminService.LogAuditAction(c.Request.Context(), adminID, "reject_media", "business_media", id,
+		map[string]interface{}{"notes": body.Notes}, c.ClientIP())
+	utils.SendSuccess(c, http.StatusOK, "Rejected + image removed from business", nil)
+}
+
+// WithProfileMedia enables the held avatar and cover queue.
+func (h *MediaModerationHandler) WithProfileMedia(profiles *services.ProfileMediaService) *MediaModerationHandler {
+	h.profiles = profiles
+	return h
+}
+
+// sendErr maps service errors onto their status codes.
+func (h *MediaModerationHandler) sendErr(c *gin.Context, err error) {
+	middleware.RespondWithError(c, err)
+}
+
+// ListProfiles godoc
+// @Success 200 {object} utils.ListResponse
+// @Router /admin/media-moderation/profiles [get]
+func (h *MediaModerationHandler) ListProfiles(c *gin.Context) {
+	rows, err := h.profiles.List(c.Request.Context(), c.Query("status"), c.Query("flagged") == "true", mediaModerationPageSize)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	counts, _ := h.profiles.Counts(c.Request.Context())
+	utils.SendList(c, "ok", rows, utils.ListMeta{Limit: mediaModerationPageSize, Counts: counts}, gin.H{"items": rows, "counts": counts})
+}
+
+// ApproveProfile godoc
+// @Router /admin/media-moderation/profiles/{review_id}/approve [post]
+func (h *MediaModerationHandler) ApproveProfile(c *gin.Context) {
+	id := c.Param("review_id")
+	var body reviewMediaBody
+	_ = c.ShouldBindJSON(&body)
+	adminID, _ := middleware.GetUserID(c)
+	if err := h.profiles.Approve(c.Request.Context(), id, adminID, body.Notes); err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	_ = h.adminService.LogAuditAction(c.Request.Context(), adminID, "approve_media", "profile_media", id,
+		map[string]interface{}{"notes": body.Notes}, c.ClientIP())
+	utils.SendSuccess(c, http.StatusOK, "Approved + image applied", nil)
+}
+
+// RejectProfile godoc
+// @Router /admin/media-moderation/profiles/{review_id}/reject [post]
+func (h *MediaModerationHandler) RejectProfile(c *gin.Context) {
+	id := c.Param("review_id")
+	var body reviewMediaBody
+	_ = c.ShouldBindJSON(&body)
+	adminID, _ := middleware.GetUserID(c)
+	if err := h.profiles.Reject(c.Request.Context(), id, adminID, body.Notes); err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	_ = h.adminService.LogAuditAction(c.Request.Context(), adminID, "reject_media", "profile_media", id,
+		map[string]interface{}{"notes": body.Notes}, c.ClientIP())
+	utils.SendSuccess(c, http.StatusOK, "Rejected", nil)
+}
@@ -56,9 +56,13 @@ func NewMonetizationHandler(
 // ListActiveAdsPublic returns currently-live ads. No auth required so the
 // mobile feed can fetch even before the user signs in.
 //
+// @Success 200 {object} utils.ListResponse{data=[]models.Ad}
 // @Router /ads/active [get]
 func (h *MonetizationHandler) ListActiveAdsPublic(c *gin.Context) {
 	limit := atoiOr(c.Query("limit"), 10)
+	if limit > 50 {
+		limit = 10
+	}
 	// User context for targeting. Mobile passes its own province + locale so
 	// the server can match against ads.target_provinces / target_languages.
 	// Empty values disable targeting on that dimension.
@@ -73,7 +77,7 @@ func (h *MonetizationHandler) ListActiveAdsPublic(c *gin.Context) {
 	if ads == nil {
 		ads = []*models.Ad{}
 	}
-	utils.SendSuccess(c, http.StatusOK, "Active ads", gin.H{"items": ads})
+	utils.SendList(c, "Active ads", ads, utils.ListMeta{Limit: limit}, gin.H{"items": ads})
 }
 
 // RecordAdImpression — public, fire-and-forget impression tracker called by
@@ -115,13 +119,19 @@ func (h *MonetizationHandler) RecordAdClick(c *gin.Context) {
 // Redis errors — the rate-limit middleware already throttles raw request
 // volume, so a Redis outage shouldn't block legitimate ad analytics.
 func (h *MonetizationHandler) shouldRecordAdEvent(c *gin.Context, kind, adID string, ttl time.Duration) bool {
-	if h.redis == nil {
+	return firstTrackingEvent(c, h.redis, h.logger, fmt.Sprintf("ad-dedupe:%s:%s:%s", kind, adID, c.ClientIP()), ttl)
+}
+
+// firstTrackingEvent claims key for ttl and reports whether this request
+// was first. Shared by the public impression / click trackers; fails open
+// like shouldRecordAdEvent.
+func firstTrackingEvent(c *gin.Context, rdb *redis.Client, logger *zap.Logger, key string, ttl time.Duration) bool {
+	if rdb == nil {
 		return true
 	}
-	key := fmt.Sprintf("ad-dedupe:%s:%s:%s", kind, adID, c.ClientIP())
-	ok, err := h.redis.SetNX(c.Request.Context(), key, "1", ttl).Result()
+	ok, err := rdb.SetNX(c.Request.Context(), key, "1", ttl).Result()
 	if err != nil {
-		h.logger.Warn("ad dedupe SETNX failed", zap.Error(err))
+		logger.Warn("tracking dedupe SETNX failed", zap.String("key", key), zap.Error(err))
 		return true
 	}
 	return ok
@@ -159,7 +169,7 @@ func (h *MonetizationHandler) CreateAd(c *gin.Context) {
 		if !utils.EnforceUploadSize(c, header.Size, utils.MaxImageUploadBytes) {
 			return
 		}
-		photo, uErr := h.storage.UploadImage(c.Request.Context(), file, header, services.ImageTypeAd)
+		photo, uErr := h.storage.UploadImage(c.Request.Context(), "", file, header, services.ImageTypeAd)
 		if uErr != nil {
 			h.logger.Error("ad image upload", zap.Error(uErr))
 			utils.SendError(c, http.StatusInternalServerError, "Failed to upload image", uErr)
@@ -212,7 +222,7 @@ func (h *MonetizationHandler) UpdateAd(c *gin.Context) {
 		if !utils.EnforceUploadSize(c, header.Size, utils.MaxImageUploadBytes) {
 			return
 		}
-		photo, uErr := h.storage.UploadImage(c.Request.Context(), file, header, services.ImageTypeAd)
+		photo, uErr := h.storage.UploadImage(c.Request.Context(), "", file, header, services.ImageTypeAd)
 		if uErr != nil {
 			h.logger.Error("ad image upload", zap.Error(uErr))
 			utils.SendError(c, http.StatusInternalServerError, "Failed to upload image", uErr)
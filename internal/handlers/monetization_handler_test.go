@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"mime/multipart"
 	"net/http"
@@ -12,12 +13,14 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
 	"github.com/hamsaya/backend/internal/mocks"
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/services"
 	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/hamsaya/backend/internal/utils"
 )
 
 const (
@@ -109,6 +112,22 @@ func TestMonetizationHandler_ListActiveAdsPublic(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Contains(t, w.Body.String(), `"items":[]`)
 	})
+
+	t.Run("list envelope on request", func(t *testing.T) {
+		repo := &mocks.MockMonetizationRepository{}
+		repo.On("ListActiveAds", mock.Anything, 10, "", "").Return([]*models.Ad{{ID: "a1"}}, nil)
+		r := newMonetizationRouter(t, repo)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/ads/active?limit=500", nil)
+		req.Header.Set(utils.EnvelopeHeader, "2")
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var body utils.ListResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Len(t, body.Data, 1)
+		assert.Equal(t, 10, body.Meta.Limit)
+	})
 }
 
 func TestMonetizationHandler_RecordImpressionAndClick(t *testing.T) {
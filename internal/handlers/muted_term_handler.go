@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/services"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// MutedTermHandler exposes the caller's muted keywords and hashtags under
+// /api/v1/users/me/muted-terms.
+type MutedTermHandler struct {
+	service   *services.MutedTermService
+	validator *utils.Validator
+	logger    *zap.Logger
+}
+
+// NewMutedTermHandler wires the handler.
+func NewMutedTermHandler(service *services.MutedTermService, validator *utils.Validator, logger *zap.Logger) *MutedTermHandler {
+	return &MutedTermHandler{
+		service:   service,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (h *MutedTermHandler) sendErr(c *gin.Context, err error) {
+	middleware.RespondWithError(c, err)
+}
+
+func (h *MutedTermHandler) currentUser(c *gin.Context) (string, bool) {
+	v, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return "", false
+	}
+	return v.(string), true
+}
+
+// ListMutedTerms returns the caller's muted keywords and hashtags.
+// @Tags         profile
+// @Security     BearerAuth
+// @Success      200 {object} utils.Response{data=[]models.MutedTerm}
+// @Router       /users/me/muted-terms [get]
+func (h *MutedTermHandler) ListMutedTerms(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	terms, err := h.service.List(c.Request.Context(), userID)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Muted terms", terms)
+}
+
+// AddMutedTerm mutes a keyword, or a hashtag when it starts with #. Posts
+// containing it leave the caller's feeds and stop notifying them.
+// @Tags         profile
+// @Security     BearerAuth
+// @Param        request body models.AddMutedTermRequest true "Term"
+// @Success      201 {object} utils.Response{data=models.MutedTerm}
+// @Failure      409 {object} utils.Response
+// @Router       /users/me/muted-terms [post]
+func (h *MutedTermHandler) AddMutedTerm(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req models.AddMutedTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	term, err := h.service.Add(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusCreated, "Term muted", term)
+}
+
+// DeleteMutedTerm unmutes a term.
+// @Tags         profile
+// @Security     BearerAuth
+// @Param        term_id path string true "Muted term id"
+// @Success      200 {object} utils.Response
+// @Failure      404 {object} utils.Response
+// @Router       /users/me/muted-terms/{term_id} [delete]
+func (h *MutedTermHandler) DeleteMutedTerm(c *gin.Context) {
+	userID, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Delete(c.Request.Context(), userID, c.Param("term_id")); err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Term unmuted", nil)
+}
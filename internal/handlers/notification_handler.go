@@ -3,8 +3,11 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/services"
 	"github.com/hamsaya/backend/internal/utils"
@@ -32,6 +35,12 @@ func NewNotificationHandler(
 }
 
 // GetNotifications handles GET /api/v1/notifications
+// Optional query: type (comma-separated, e.g. LIKE,COMMENT), unread_only,
+// business_id. Pagination is by limit with offset/page, or by cursor: pass
+// cursor (empty for the first page) to page on created_at instead; the
+// response then carries meta.sorts.next_cursor while more remain. With
+// since (RFC 3339) it returns a delta page instead: notifications changed
+// after it plus the IDs deleted since.
 func (h *NotificationHandler) GetNotifications(c *gin.Context) {
 	// Get authenticated user ID
 	userID, exists := c.Get("user_id")
@@ -41,40 +50,88 @@ func (h *NotificationHandler) GetNotifications(c *gin.Context) {
 	}
 
 	// Parse query parameters
-	unreadOnly := c.Query("unread_only") == "true"
-	var businessID *string
+	filter := &models.GetNotificationsFilter{
+		UserID:     userID.(string),
+		UnreadOnly: c.Query("unread_only") == "true",
+		Limit:      20,
+	}
 	if b := c.Query("business_id"); b != "" {
-		businessID = &b
+		filter.BusinessID = &b
+	}
+	for _, t := range strings.Split(c.Query("type"), ",") {
+		if t = strings.ToUpper(strings.TrimSpace(t)); t != "" {
+			filter.Types = append(filter.Types, models.NotificationType(t))
+		}
 	}
 
-	limit := 20
-	offset := 0
 	if limitStr := c.Query("limit"); limitStr != "" {
 		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
-			limit = l
+			filter.Limit = l
 		}
 	}
-	if offsetStr := c.Query("offset"); offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
-			offset = o
+
+	cursorStr, cursorMode := c.GetQuery("cursor")
+	if cursorMode {
+		if cursorStr != "" {
+			t, err := time.Parse(time.RFC3339Nano, cursorStr)
+			if err != nil {
+				utils.SendError(c, http.StatusBadRequest, "Invalid cursor", utils.ErrBadRequest)
+				return
+			}
+			filter.Cursor = &t
 		}
-	}
-	if offset == 0 && c.Query("offset") == "" {
-		if pageStr := c.Query("page"); pageStr != "" {
-			if p, err := strconv.Atoi(pageStr); err == nil && p >= 0 {
-				offset = p * limit
+	} else {
+		if offsetStr := c.Query("offset"); offsetStr != "" {
+			if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+				filter.Offset = o
+			}
+		}
+		if filter.Offset == 0 && c.Query("offset") == "" {
+			if pageStr := c.Query("page"); pageStr != "" {
+				if p, err := strconv.Atoi(pageStr); err == nil && p >= 0 {
+					filter.Offset = p * filter.Limit
+				}
 			}
 		}
 	}
 
+	since, ok := deltaSince(c)
+	if !ok {
+		return
+	}
+	if since != nil {
+		page, err := h.notificationService.GetNotificationChanges(c.Request.Context(), filter, *since)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		utils.SendSuccess(c, http.StatusOK, "Notification changes retrieved successfully", page)
+		return
+	}
+
 	// Get notifications
-	notifications, err := h.notificationService.GetNotifications(c.Request.Context(), userID.(string), unreadOnly, limit, offset, businessID)
+	notifications, err := h.notificationService.GetNotifications(c.Request.Context(), filter)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	utils.SendSuccess(c, http.StatusOK, "Notifications retrieved successfully", notifications)
+	if !cursorMode {
+		utils.SendSuccess(c, http.StatusOK, "Notifications retrieved successfully", notifications)
+		return
+	}
+
+	var filters map[string]interface{}
+	if len(filter.Types) > 0 {
+		filters = map[string]interface{}{"type": filter.Types}
+	}
+	sorts := map[string]interface{}{"sort_by": "recent"}
+	var nextCursor string
+	if len(notifications) == filter.Limit {
+		nextCursor = notifications[len(notifications)-1].CreatedAt.UTC().Format(time.RFC3339Nano)
+		sorts["next_cursor"] = nextCursor
+	}
+	utils.SendCursorPage(c, notifications, filter.Limit, nextCursor, filters, sorts)
 }
 
 // GetUnreadCount handles GET /api/v1/notifications/unread-count
@@ -170,6 +227,36 @@ func (h *NotificationHandler) DeleteNotification(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusOK, "Notification deleted successfully", nil)
 }
 
+// ClearNotifications handles DELETE /api/v1/notifications
+// Deletes all of the user's notifications, or only read ones with
+// ?read_only=true. Optional business_id scopes the clear like the list.
+func (h *NotificationHandler) ClearNotifications(c *gin.Context) {
+	// Get authenticated user ID
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	filter := &models.ClearNotificationsFilter{
+		UserID:   userID.(string),
+		ReadOnly: c.Query("read_only") == "true",
+	}
+	if b := c.Query("business_id"); b != "" {
+		filter.BusinessID = &b
+	}
+
+	deleted, err := h.notificationService.ClearNotifications(c.Request.Context(), filter)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Notifications cleared", gin.H{
+		"deleted_count": deleted,
+	})
+}
+
 // GetNotificationSettings handles GET /api/v1/notifications/settings
 func (h *NotificationHandler) GetNotificationSettings(c *gin.Context) {
 	// Get authenticated user ID
@@ -244,7 +331,7 @@ func (h *NotificationHandler) RegisterFCMToken(c *gin.Context) {
 	}
 
 	// Register FCM token
-	if err := h.notificationService.RegisterFCMToken(c.Request.Context(), userID.(string), req.Token); err != nil {
+	if err := h.notificationService.RegisterFCMToken(c.Request.Context(), userID.(string), req.Token, req.DeviceName); err != nil {
 		h.handleError(c, err)
 		return
 	}
@@ -301,7 +388,7 @@ func (h *NotificationHandler) RegisterAPNsToken(c *gin.Context) {
 		return
 	}
 
-	if err := h.notificationService.RegisterAPNsToken(c.Request.Context(), userID.(string), req.Token); err != nil {
+	if err := h.notificationService.RegisterAPNsToken(c.Request.Context(), userID.(string), req.Token, req.DeviceName); err != nil {
 		h.handleError(c, err)
 		return
 	}
@@ -328,15 +415,45 @@ func (h *NotificationHandler) UnregisterAPNsToken(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusOK, "APNs token unregistered successfully", nil)
 }
 
-// handleError handles service errors and sends appropriate HTTP responses
-func (h *NotificationHandler) handleError(c *gin.Context, err error) {
-	// Check if it's an AppError
-	if appErr, ok := err.(*utils.AppError); ok {
-		utils.SendError(c, appErr.Code, appErr.Message, appErr.Err)
+// ListDevices handles GET /api/v1/notifications/devices
+//
+// Lists the devices registered for push, most recently active first, with
+// their delivery state. Tokens are never returned; ids are for revocation.
+func (h *NotificationHandler) ListDevices(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	devices, err := h.notificationService.ListPushDevices(c.Request.Context(), userID.(string))
+	if err != nil {
+		h.handleError(c, err)
 		return
 	}
 
-	// Default to internal server error
-	h.logger.Error("Unhandled error in notification handler", zap.Error(err))
-	utils.SendError(c, http.StatusInternalServerError, "An error occurred", err)
+	utils.SendSuccess(c, http.StatusOK, "Devices retrieved successfully", devices)
+}
+
+// RevokeDevice handles DELETE /api/v1/notifications/devices/:device_id
+//
+// The device stops receiving pushes until the app registers it again.
+func (h *NotificationHandler) RevokeDevice(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	if err := h.notificationService.RevokePushDevice(c.Request.Context(), userID.(string), c.Param("device_id")); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Device revoked successfully", nil)
+}
+
+// handleError handles service errors and sends appropriate HTTP responses
+func (h *NotificationHandler) handleError(c *gin.Context, err error) {
+	middleware.RespondWithError(c, err)
 }
@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/alicebob/miniredis/v2"
 	"github.com/gin-gonic/gin"
@@ -16,6 +17,7 @@ import (
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
@@ -49,11 +51,14 @@ func newNotificationRouter(
 	r.GET("/api/v1/notifications/unread-count", authed, h.GetUnreadCount)
 	r.POST("/api/v1/notifications/:notification_id/read", authed, h.MarkAsRead)
 	r.POST("/api/v1/notifications/read-all", authed, h.MarkAllAsRead)
+	r.DELETE("/api/v1/notifications", authed, h.ClearNotifications)
 	r.DELETE("/api/v1/notifications/:notification_id", authed, h.DeleteNotification)
 	r.GET("/api/v1/notifications/settings", authed, h.GetNotificationSettings)
 	r.PUT("/api/v1/notifications/settings", authed, h.UpdateNotificationSetting)
 	r.POST("/api/v1/notifications/fcm-token", authed, h.RegisterFCMToken)
 	r.DELETE("/api/v1/notifications/fcm-token", authed, h.UnregisterFCMToken)
+	r.GET("/api/v1/notifications/devices", authed, h.ListDevices)
+	r.DELETE("/api/v1/notifications/devices/:device_id", authed, h.RevokeDevice)
 
 	r.GET("/api/v1/noauth/notifications", h.GetNotifications)
 	return r
@@ -98,6 +103,70 @@ func TestNotificationHandler_GetNotifications(t *testing.T) {
 	})
 }
 
+func TestNotificationHandler_GetNotifications_CursorAndTypes(t *testing.T) {
+	t.Run("first page returns next cursor", func(t *testing.T) {
+		notifRepo := &mocks.MockNotificationRepository{}
+		created := time.Date(2026, 10, 1, 8, 30, 0, 0, time.UTC)
+		notifRepo.On("List", mock.Anything, mock.MatchedBy(func(f *models.GetNotificationsFilter) bool {
+			return f.Cursor == nil && f.Limit == 1 && f.Offset == 0 &&
+				assert.ObjectsAreEqual([]models.NotificationType{"LIKE", "COMMENT"}, f.Types)
+		})).Return([]*models.Notification{{ID: "n-1", UserID: notifTestUserID, Type: "LIKE", CreatedAt: created}}, nil)
+		r := newNotificationRouter(t, notifRepo, &mocks.MockNotificationSettingsRepository{})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/notifications?cursor=&limit=1&offset=5&type=like,%20COMMENT", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"next_cursor":"2026-10-01T08:30:00Z"`)
+		notifRepo.AssertExpectations(t)
+	})
+
+	t.Run("cursor is passed through", func(t *testing.T) {
+		notifRepo := &mocks.MockNotificationRepository{}
+		cursor := time.Date(2026, 10, 1, 8, 30, 0, 0, time.UTC)
+		notifRepo.On("List", mock.Anything, mock.MatchedBy(func(f *models.GetNotificationsFilter) bool {
+			return f.Cursor != nil && f.Cursor.Equal(cursor)
+		})).Return([]*models.Notification{}, nil)
+		r := newNotificationRouter(t, notifRepo, &mocks.MockNotificationSettingsRepository{})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/notifications?cursor=2026-10-01T08:30:00Z", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "next_cursor")
+		notifRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		r := newNotificationRouter(t, &mocks.MockNotificationRepository{}, &mocks.MockNotificationSettingsRepository{})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/api/v1/notifications?cursor=yesterday", nil)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+// --- ClearNotifications ---
+
+func TestNotificationHandler_ClearNotifications(t *testing.T) {
+	notifRepo := &mocks.MockNotificationRepository{}
+	notifRepo.On("DeleteForUser", mock.Anything, &models.ClearNotificationsFilter{UserID: notifTestUserID, ReadOnly: true}).
+		Return(int64(4), nil)
+	r := newNotificationRouter(t, notifRepo, &mocks.MockNotificationSettingsRepository{})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/api/v1/notifications?read_only=true", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"deleted_count":4`)
+	notifRepo.AssertExpectations(t)
+}
+
 // --- GetUnreadCount ---
 
 func TestNotificationHandler_GetUnreadCount(t *testing.T) {
@@ -287,3 +356,33 @@ func TestNotificationHandler_UnregisterFCMToken(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 	})
 }
+
+// --- Devices ---
+
+func TestNotificationHandler_Devices(t *testing.T) {
+	t.Run("registered token is listed", func(t *testing.T) {
+		r := newNotificationRouter(t, &mocks.MockNotificationRepository{}, &mocks.MockNotificationSettingsRepository{})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/notifications/fcm-token",
+			strings.NewReader(`{"token":"fcm-token-0123456789","device_name":"Pixel 8"}`))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest(http.MethodGet, "/api/v1/notifications/devices", nil)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"device_name":"Pixel 8"`)
+		assert.NotContains(t, w.Body.String(), "fcm-token-0123456789")
+	})
+
+	t.Run("revoke unknown device", func(t *testing.T) {
+		r := newNotificationRouter(t, &mocks.MockNotificationRepository{}, &mocks.MockNotificationSettingsRepository{})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodDelete, "/api/v1/notifications/devices/unknown", nil)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -1,16 +1,22 @@
 package handlers
 
 import (
+	"io"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/services"
 	"github.com/hamsaya/backend/internal/utils"
 	"go.uber.org/zap"
 )
 
+// maxSecurityEventBytes caps provider notification bodies. Apple and Google
+// send a single signed JWT of a few KB.
+const maxSecurityEventBytes = 64 << 10
+
 // OAuthHandler handles OAuth authentication endpoints
 type OAuthHandler struct {
 	authService  *services.AuthService
@@ -59,6 +65,12 @@ type AppleOAuthRequest struct {
 	DeviceInfo *string `json:"device_info,omitempty"`
 }
 
+// AppleNotificationRequest is the body of a Sign in with Apple
+// server-to-server notification.
+type AppleNotificationRequest struct {
+	Payload string `json:"payload" validate:"required"`
+}
+
 // GoogleOAuth godoc
 // @Summary Google OAuth authentication
 // @Description Authenticate or register a user using Google OAuth
@@ -358,15 +370,75 @@ func (h *OAuthHandler) AppleOAuth(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusOK, message, response)
 }
 
-// handleError handles service errors and sends appropriate HTTP responses
-func (h *OAuthHandler) handleError(c *gin.Context, err error) {
-	// Check if it's an AppError
-	if appErr, ok := err.(*utils.AppError); ok {
-		utils.SendError(c, appErr.Code, appErr.Message, appErr.Err)
+// AppleNotification godoc
+// @Summary Sign in with Apple server-to-server notifications
+// @Description Receives consent revocation, account deletion and email relay events from Apple. Authenticated by Apple's signature on the payload, not a bearer token.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param request body AppleNotificationRequest true "Apple notification"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /webhooks/oauth/apple [post]
+func (h *OAuthHandler) AppleNotification(c *gin.Context) {
+	var req AppleNotificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	event, err := h.oauthService.VerifyAppleNotification(c.Request.Context(), req.Payload)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	if err := h.authService.HandleOAuthProviderEvent(c.Request.Context(), event); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Notification processed", nil)
+}
+
+// GoogleSecurityEvent godoc
+// @Summary Google Cross-Account Protection events
+// @Description Receives security event tokens (application/secevent+jwt) from Google's RISC service. Authenticated by Google's signature on the token, not a bearer token.
+// @Tags webhooks
+// @Accept plain
+// @Success 202
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /webhooks/oauth/google [post]
+func (h *OAuthHandler) GoogleSecurityEvent(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxSecurityEventBytes))
+	if err != nil {
+		utils.SendBadRequest(c, "Failed to read request body", err)
 		return
 	}
 
-	// Default to internal server error
-	h.logger.Error("Unhandled error in OAuth handler", zap.Error(err))
-	utils.SendError(c, http.StatusInternalServerError, "An error occurred", err)
+	events, err := h.oauthService.VerifyGoogleSecurityEvent(c.Request.Context(), strings.TrimSpace(string(body)))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	for _, event := range events {
+		if err := h.authService.HandleOAuthProviderEvent(c.Request.Context(), event); err != nil {
+			h.handleError(c, err)
+			return
+		}
+	}
+
+	// RISC transmitters expect 202 Accepted with an empty body.
+	c.Status(http.StatusAccepted)
+}
+
+// handleError handles service errors and sends appropriate HTTP responses
+func (h *OAuthHandler) handleError(c *gin.Context, err error) {
+	middleware.RespondWithError(c, err)
 }
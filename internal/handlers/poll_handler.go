@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/services"
 	"github.com/hamsaya/backend/internal/utils"
@@ -182,6 +183,46 @@ func (h *PollHandler) VotePoll(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusOK, "Vote recorded successfully", poll)
 }
 
+// ChangeVote godoc
+// @Summary Change vote
+// @Description Move the user's existing vote to another option
+// @Tags polls
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param poll_id path string true "Poll ID"
+// @Param request body models.VotePollRequest true "New option"
+// @Success 200 {object} utils.Response{data=models.PollResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response "Poll closed or vote locked"
+// @Router /polls/{poll_id}/vote [put]
+func (h *PollHandler) ChangeVote(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	var req models.VotePollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	poll, err := h.pollService.ChangeVote(c.Request.Context(), c.Param("poll_id"), userID.(string), req.PollOptionID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Vote changed successfully", poll)
+}
+
 // DeleteVote godoc
 // @Summary Delete vote
 // @Description Remove user's vote from a poll
@@ -214,13 +255,5 @@ func (h *PollHandler) DeleteVote(c *gin.Context) {
 
 // handleError handles service errors and sends appropriate HTTP responses
 func (h *PollHandler) handleError(c *gin.Context, err error) {
-	// Check if it's an AppError
-	if appErr, ok := err.(*utils.AppError); ok {
-		utils.SendError(c, appErr.Code, appErr.Message, appErr.Err)
-		return
-	}
-
-	// Default to internal server error
-	h.logger.Error("Unhandled error in poll handler", zap.Error(err))
-	utils.SendError(c, http.StatusInternalServerError, "An error occurred", err)
+	middleware.RespondWithError(c, err)
 }
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/services"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// PostBroadcastHandler exposes geofenced broadcasts of EVENT and ALERT posts
+// to their authors, and the admin approval queue.
+type PostBroadcastHandler struct {
+	broadcastService *services.PostBroadcastService
+	adminService     *services.AdminService
+	validator        *utils.Validator
+	logger           *zap.Logger
+}
+
+// NewPostBroadcastHandler constructs the handler. adminService is used for
+// audit-logging admin broadcasts and reviews (may be nil in tests).
+func NewPostBroadcastHandler(
+	broadcastService *services.PostBroadcastService,
+	adminService *services.AdminService,
+	validator *utils.Validator,
+	logger *zap.Logger,
+) *PostBroadcastHandler {
+	return &PostBroadcastHandler{
+		broadcastService: broadcastService,
+		adminService:     adminService,
+		validator:        validator,
+		logger:           logger,
+	}
+}
+
+// RequestBroadcast godoc
+// @Summary Broadcast an EVENT or ALERT post to everyone within a radius (author only)
+// @Description Large broadcasts wait for admin approval (status PENDING_APPROVAL); others are queued and sent in the background.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param request body models.CreatePostBroadcastRequest true "Broadcast radius"
+// @Success 201 {object} utils.Response{data=models.PostBroadcast}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /posts/{post_id}/broadcast [post]
+func (h *PostBroadcastHandler) RequestBroadcast(c *gin.Context) {
+	h.request(c, false)
+}
+
+// AdminRequestBroadcast godoc
+// @Summary Broadcast any EVENT or ALERT post within a radius (admin)
+// @Description Admin broadcasts skip the approval queue.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param request body models.CreatePostBroadcastRequest true "Broadcast radius"
+// @Success 201 {object} utils.Response{data=models.PostBroadcast}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/posts/{post_id}/broadcast [post]
+func (h *PostBroadcastHandler) AdminRequestBroadcast(c *gin.Context) {
+	h.request(c, true)
+}
+
+func (h *PostBroadcastHandler) request(c *gin.Context, asAdmin bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	var req models.CreatePostBroadcastRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	broadcast, err := h.broadcastService.Request(c.Request.Context(), c.Param("post_id"), userID.(string), asAdmin, req.RadiusKm)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	if asAdmin && h.adminService != nil {
+		_ = h.adminService.LogAuditAction(
+			c.Request.Context(), userID.(string),
+			"broadcast_post", "post", broadcast.PostID,
+			map[string]interface{}{
+				"broadcast_id":         broadcast.ID,
+				"radius_km":            broadcast.RadiusKm,
+				"estimated_recipients": broadcast.EstimatedRecipients,
+			}, c.ClientIP(),
+		)
+	}
+
+	utils.SendSuccess(c, http.StatusCreated, "Broadcast requested", broadcast)
+}
+
+// GetBroadcast godoc
+// @Summary Get the latest broadcast of a post (author only)
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Success 200 {object} utils.Response{data=models.PostBroadcast}
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /posts/{post_id}/broadcast [get]
+func (h *PostBroadcastHandler) GetBroadcast(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	broadcast, err := h.broadcastService.Status(c.Request.Context(), c.Param("post_id"), userID.(string))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Broadcast retrieved", broadcast)
+}
+
+// ListBroadcasts godoc
+// @Summary List post broadcasts (admin)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter: PENDING_APPROVAL | QUEUED | SENDING | SENT | REJECTED"
+// @Param limit query int false "Limit" default(20)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} utils.ListResponse{data=[]models.PostBroadcastListItem}
+// @Failure 401 {object} utils.Response
+// @Router /admin/broadcasts [get]
+func (h *PostBroadcastHandler) ListBroadcasts(c *gin.Context) {
+	limit := 20
+	offset := 0
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+	if o, err := strconv.Atoi(c.Query("offset")); err == nil && o >= 0 {
+		offset = o
+	}
+	var status *string
+	switch v := c.Query("status"); v {
+	case models.BroadcastStatusPendingApproval, models.BroadcastStatusQueued,
+		models.BroadcastStatusSending, models.BroadcastStatusSent, models.BroadcastStatusRejected:
+		status = &v
+	}
+
+	items, total, err := h.broadcastService.List(c.Request.Context(), status, limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendList(c, "Broadcasts retrieved", items, utils.OffsetMeta(limit, offset, total), map[string]interface{}{
+		"items":  items,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// ReviewBroadcast godoc
+// @Summary Approve or reject a broadcast waiting for approval (admin)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param broadcast_id path string true "Broadcast ID"
+// @Param request body models.ReviewPostBroadcastRequest true "action: approve | reject (+ reason)"
+// @Success 200 {object} utils.Response{data=models.PostBroadcast}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/broadcasts/{broadcast_id} [patch]
+func (h *PostBroadcastHandler) ReviewBroadcast(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	var req models.ReviewPostBroadcastRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Validation failed", err)
+		return
+	}
+
+	result, err := h.broadcastService.Review(c.Request.Context(), c.Param("broadcast_id"), adminID.(string), req.Action, req.Reason)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	if h.adminService != nil {
+		details := map[string]interface{}{
+			"action":  req.Action,
+			"post_id": result.PostID,
+		}
+		if req.Reason != nil && *req.Reason != "" {
+			details["reason"] = *req.Reason
+		}
+		_ = h.adminService.LogAuditAction(
+			c.Request.Context(), adminID.(string),
+			"review_post_broadcast", "post_broadcast", result.ID,
+			details, c.ClientIP(),
+		)
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Broadcast reviewed", result)
+}
+
+func (h *PostBroadcastHandler) handleError(c *gin.Context, err error) {
+	middleware.RespondWithError(c, err)
+}
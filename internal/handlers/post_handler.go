@@ -10,9 +10,11 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/services"
 	"github.com/hamsaya/backend/internal/utils"
+	"github.com/hamsaya/backend/pkg/langdetect"
 	"go.uber.org/zap"
 )
 
@@ -30,10 +32,26 @@ func parseFloat64(v interface{}) (float64, bool) {
 	}
 }
 
+// parseLanguages reads ?lang=fa,ps. Absent returns nil so the viewer's
+// content languages apply; "all" (or only unknown codes) returns an empty
+// list, which turns the filter off.
+func parseLanguages(c *gin.Context) []string {
+	raw := c.Query("lang")
+	if raw == "" {
+		return nil
+	}
+	languages := langdetect.ParseList(raw)
+	if languages == nil {
+		return []string{}
+	}
+	return languages
+}
+
 // PostHandler handles post-related endpoints
 type PostHandler struct {
 	postService    *services.PostService
 	storageService *services.StorageService
+	uploadSessions *services.UploadSessionService
 	validator      *utils.Validator
 	logger         *zap.Logger
 }
@@ -53,6 +71,12 @@ func NewPostHandler(
 	}
 }
 
+// WithUploadSessions enables upload sessions for pre-uploaded post images.
+func (h *PostHandler) WithUploadSessions(u *services.UploadSessionService) *PostHandler {
+	h.uploadSessions = u
+	return h
+}
+
 // CreatePost godoc
 // @Summary Create a post
 // @Description Create a new post (FEED, EVENT, SELL, or PULL)
@@ -132,6 +156,10 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 		return
 	}
 
+	if !middleware.ActingAs(c, &req.BusinessID) {
+		return
+	}
+
 	// Create post
 	post, err := h.postService.CreatePost(c.Request.Context(), userID.(string), &req)
 	if err != nil {
@@ -172,6 +200,41 @@ func (h *PostHandler) GetPost(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusOK, "Post retrieved successfully", post)
 }
 
+// PreviewPost godoc
+// @Summary Preview a post as an audience
+// @Description Shows the author what a signed-out viewer, a follower or a blocked user would see. 404 means that audience can't see the post.
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param as query string true "Audience" Enums(public, follower, blocked)
+// @Success 200 {object} utils.Response{data=models.PostResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /posts/{post_id}/preview [get]
+func (h *PostHandler) PreviewPost(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	audience, ok := services.ParsePreviewAudience(c.Query("as"))
+	if !ok {
+		utils.SendError(c, http.StatusBadRequest, "as must be public, follower or blocked", utils.ErrValidation)
+		return
+	}
+
+	post, err := h.postService.PreviewPost(c.Request.Context(), c.Param("post_id"), userID.(string), audience)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Post preview retrieved successfully", post)
+}
+
 // UpdatePost godoc
 // @Summary Update a post
 // @Description Update a post
@@ -185,6 +248,7 @@ func (h *PostHandler) GetPost(c *gin.Context) {
 // @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
 // @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response{data=models.PostResponse} "Edited elsewhere since version; data is the current post"
 // @Router /posts/{post_id} [put]
 func (h *PostHandler) UpdatePost(c *gin.Context) {
 	// Get authenticated user ID
@@ -254,6 +318,46 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusOK, "Post updated successfully", post)
 }
 
+// ReorderAttachments godoc
+// @Summary Reorder post attachments
+// @Description Set the order of a post's attachments; the first becomes the cover. Every attachment must be listed once.
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param request body models.ReorderAttachmentsRequest true "Attachment IDs in order"
+// @Success 200 {object} utils.Response{data=[]models.AttachmentResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /posts/{post_id}/attachments/order [put]
+func (h *PostHandler) ReorderAttachments(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	var req models.ReorderAttachmentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	attachments, err := h.postService.ReorderAttachments(c.Request.Context(), c.Param("post_id"), userID.(string), req.AttachmentIDs)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Attachments reordered", attachments)
+}
+
 // DeletePost godoc
 // @Summary Delete a post
 // @Description Delete a post (soft delete)
@@ -314,6 +418,66 @@ func (h *PostHandler) ResellPost(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusOK, "Post relisted successfully", post)
 }
 
+// RenewPost godoc
+// @Summary Renew a sell post
+// @Description Resets an active sell post's expiry to 30 days from now and bumps it to the top of the recent feed. Allowed once every 72 hours per post.
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Router /posts/{post_id}/renew [post]
+func (h *PostHandler) RenewPost(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	post, err := h.postService.RenewPost(c.Request.Context(), c.Param("post_id"), userID.(string))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Post renewed successfully", post)
+}
+
+// RelistPost godoc
+// @Summary Relist a sold or expired sell post
+// @Description Copies a sold or expired sell post, with its attachments, into a new listing. Reported listings can't be relisted; relisting the same post again returns the earlier copy.
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Success 201 {object} utils.Response{data=models.PostResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Router /posts/{post_id}/relist [post]
+func (h *PostHandler) RelistPost(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	post, err := h.postService.RelistPost(c.Request.Context(), c.Param("post_id"), userID.(string))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendCreated(c, "Post relisted successfully", post)
+}
+
 // LikePost godoc
 // @Summary Like a post
 // @Description Like a post
@@ -374,12 +538,12 @@ func (h *PostHandler) GetPostLikes(c *gin.Context) {
 
 // RecordPostView godoc
 // @Summary Record a post view
-// @Description Records that the authenticated user viewed the post (unique per user)
+// @Description Records that the authenticated user viewed the post. Counted once per user per day; returns the updated view count and unique viewers
 // @Tags posts
 // @Produce json
 // @Security BearerAuth
 // @Param post_id path string true "Post ID"
-// @Success 200 {object} utils.Response
+// @Success 200 {object} utils.Response{data=models.PostViewCounts}
 // @Router /posts/{post_id}/view [post]
 func (h *PostHandler) RecordPostView(c *gin.Context) {
 	viewerID := ""
@@ -387,11 +551,12 @@ func (h *PostHandler) RecordPostView(c *gin.Context) {
 		viewerID, _ = v.(string)
 	}
 	postID := c.Param("post_id")
-	if err := h.postService.RecordPostView(c.Request.Context(), postID, viewerID); err != nil {
+	counts, err := h.postService.RecordPostView(c.Request.Context(), postID, viewerID)
+	if err != nil {
 		h.handleError(c, err)
 		return
 	}
-	utils.SendSuccess(c, http.StatusOK, "View recorded", nil)
+	utils.SendSuccess(c, http.StatusOK, "View recorded", counts)
 }
 
 // UnlikePost godoc
@@ -532,19 +697,82 @@ func (h *PostHandler) SharePost(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusOK, "Post shared successfully", post)
 }
 
+// ShareExternally godoc
+// @Summary Get a share link for a post
+// @Description Returns a tracked short link for sharing the post outside the app and counts the share
+// @Tags posts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param post_id path string true "Post ID"
+// @Param request body models.ExternalShareRequest false "Share channel"
+// @Success 200 {object} utils.Response{data=models.ExternalShareResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /posts/{post_id}/share/external [post]
+func (h *PostHandler) ShareExternally(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	// Body is optional; no channel means "other".
+	var req models.ExternalShareRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+			return
+		}
+		if err := h.validator.Validate(&req); err != nil {
+			utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+			return
+		}
+	}
+
+	share, err := h.postService.CreateExternalShare(c.Request.Context(), userID.(string), c.Param("post_id"), req.Channel)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Share link created", share)
+}
+
+// OpenShareLink godoc
+// @Summary Open a share link
+// @Description Counts the click and redirects to the shared post's landing page
+// @Tags posts
+// @Param code path string true "Short link code"
+// @Success 302
+// @Failure 404 {object} utils.Response
+// @Router /s/{code} [get]
+func (h *PostHandler) OpenShareLink(c *gin.Context) {
+	target, err := h.postService.ResolveShareLink(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	c.Redirect(http.StatusFound, target)
+}
+
 // GetFeed godoc
 // @Summary Get feed
 // @Description Get posts feed with filters
 // @Tags posts
 // @Produce json
-// @Param type query string false "Post type (FEED, EVENT, SELL, PULL)"
+// @Param type query string false "Post type (FEED, EVENT, SELL, PULL, LOST_FOUND, ALERT)"
 // @Param user_id query string false "Filter by user ID"
 // @Param category_id query string false "Filter by category ID (for SELL posts)"
 // @Param province query string false "Filter by province"
+// @Param lost_found_kind query string false "LOST or FOUND (for LOST_FOUND posts)"
+// @Param min_urgency query string false "LOW, MEDIUM, HIGH or CRITICAL; returns that urgency and above (for ALERT posts)"
+// @Param lang query string false "Comma-separated languages (fa, ps, ar, en), or all; defaults to the viewer's content languages"
 // @Param sort_by query string false "Sort by (recent, trending, nearby)" default(recent)
 // @Param limit query int false "Limit" default(20)
 // @Param offset query int false "Offset" default(0)
-// @Success 200 {object} utils.Response{data=[]models.PostResponse}
+// @Param since query string false "RFC 3339; delta sync: posts changed after this plus removed IDs (alias updated_after)"
+// @Success 200 {object} utils.ListResponse{data=[]models.PostResponse}
 // @Failure 500 {object} utils.Response
 // @Router /posts [get]
 func (h *PostHandler) GetFeed(c *gin.Context) {
@@ -555,6 +783,11 @@ func (h *PostHandler) GetFeed(c *gin.Context) {
 		viewerID = &idStr
 	}
 
+	since, ok := deltaSince(c)
+	if !ok {
+		return
+	}
+
 	// Parse query parameters
 	filter := &models.FeedFilter{
 		SortBy: "recent",
@@ -587,6 +820,16 @@ func (h *PostHandler) GetFeed(c *gin.Context) {
 		filter.Province = &province
 	}
 
+	if kind := models.LostFoundKind(strings.ToUpper(c.Query("lost_found_kind"))); kind == models.LostFoundLost || kind == models.LostFoundFound {
+		filter.LostFoundKind = &kind
+	}
+
+	if urgency := models.AlertUrgency(strings.ToUpper(c.Query("min_urgency"))); len(urgency.AtLeast()) > 0 {
+		filter.MinUrgency = &urgency
+	}
+
+	filter.Languages = parseLanguages(c)
+
 	if sortBy := c.Query("sort_by"); sortBy != "" {
 		filter.SortBy = sortBy
 	}
@@ -645,7 +888,7 @@ func (h *PostHandler) GetFeed(c *gin.Context) {
 	}
 
 	// Cursor-based pagination.
-	// - recent/nearby: cursor is a RFC3339Nano timestamp (keyset on created_at)
+	// - recent/nearby: cursor is a RFC3339Nano timestamp (keyset on bumped_at)
 	// - trending:      cursor is a plain integer string representing the next OFFSET,
 	//                  because trending results are sorted by a computed score, not a
 	//                  stable column, so timestamp keyset pagination cannot be used.
@@ -667,6 +910,17 @@ func (h *PostHandler) GetFeed(c *gin.Context) {
 	// posts) so their SELL listings show on their profile.
 	filter.HideUnpromotedSell = filter.BusinessID == nil && filter.UserID == nil
 
+	// Delta sync replaces paging: changes since the client's last sync.
+	if since != nil {
+		page, err := h.postService.GetFeedChanges(c.Request.Context(), filter, viewerID, *since)
+		if err != nil {
+			h.handleError(c, err)
+			return
+		}
+		utils.SendSuccess(c, http.StatusOK, "Feed changes retrieved successfully", page)
+		return
+	}
+
 	// Get feed
 	posts, totalCount, err := h.postService.GetFeed(c.Request.Context(), filter, viewerID)
 	if err != nil {
@@ -688,6 +942,15 @@ func (h *PostHandler) GetFeed(c *gin.Context) {
 	if filter.Province != nil {
 		filters["province"] = *filter.Province
 	}
+	if filter.LostFoundKind != nil {
+		filters["lost_found_kind"] = string(*filter.LostFoundKind)
+	}
+	if filter.MinUrgency != nil {
+		filters["min_urgency"] = string(*filter.MinUrgency)
+	}
+	if len(filter.Languages) > 0 {
+		filters["lang"] = strings.Join(filter.Languages, ",")
+	}
 
 	// Build sorts map for response
 	sorts := map[string]interface{}{
@@ -696,12 +959,12 @@ func (h *PostHandler) GetFeed(c *gin.Context) {
 
 	// Emit next_cursor for all sort modes.
 	// Trending uses an integer offset cursor (score-ranked, no stable keyset column).
-	// Recent/nearby use a RFC3339Nano timestamp cursor (keyset on created_at).
+	// Recent/nearby use a RFC3339Nano timestamp cursor (keyset on bumped_at).
 	if len(posts) > 0 && len(posts) == filter.Limit {
 		if filter.SortBy == "trending" {
 			sorts["next_cursor"] = strconv.Itoa(filter.Offset + filter.Limit)
 		} else {
-			sorts["next_cursor"] = posts[len(posts)-1].CreatedAt.UTC().Format(time.RFC3339Nano)
+			sorts["next_cursor"] = posts[len(posts)-1].FeedTime().UTC().Format(time.RFC3339Nano)
 		}
 	}
 
@@ -717,7 +980,7 @@ func (h *PostHandler) GetFeed(c *gin.Context) {
 // @Security BearerAuth
 // @Param cursor query string false "Cursor from previous response (RFC3339Nano)"
 // @Param limit query int false "Number of posts to return" default(20)
-// @Success 200 {object} utils.Response{data=[]models.PostResponse}
+// @Success 200 {object} utils.ListResponse{data=[]models.PostResponse}
 // @Failure 401 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /posts/feed [get]
@@ -754,10 +1017,12 @@ func (h *PostHandler) GetPersonalizedFeed(c *gin.Context) {
 	}
 
 	sorts := map[string]interface{}{"sort_by": "recent"}
+	var cursor string
 	if nextCursor != nil {
-		sorts["next_cursor"] = nextCursor.UTC().Format(time.RFC3339Nano)
+		cursor = nextCursor.UTC().Format(time.RFC3339Nano)
+		sorts["next_cursor"] = cursor
 	}
-	utils.SendPaginatedWithFilters(c, posts, 1, limit, 0, nil, sorts)
+	utils.SendCursorPage(c, posts, limit, cursor, nil, sorts)
 }
 
 // @Summary Get authenticated user's posts
@@ -767,7 +1032,7 @@ func (h *PostHandler) GetPersonalizedFeed(c *gin.Context) {
 // @Security BearerAuth
 // @Param limit query int false "Limit" default(20)
 // @Param offset query int false "Offset" default(0)
-// @Success 200 {object} utils.Response{data=[]models.PostResponse}
+// @Success 200 {object} utils.ListResponse{data=[]models.PostResponse}
 // @Failure 401 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /users/me/posts [get]
@@ -841,12 +1106,64 @@ func (h *PostHandler) GetMyPosts(c *gin.Context) {
 	utils.SendPaginated(c, posts, page, limit, totalCount)
 }
 
+// GetMyListings godoc
+// @Summary Get my sell listings
+// @Description Get the authenticated user's sell listings in one state: active, sold (the sold-item archive) or expired
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Param state query string false "active, sold or expired" default(active)
+// @Param limit query int false "Limit" default(20)
+// @Param page query int false "Page" default(1)
+// @Success 200 {object} utils.ListResponse{data=[]models.PostResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /users/me/listings [get]
+func (h *PostHandler) GetMyListings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	state := models.ListingState(c.DefaultQuery("state", string(models.ListingStateActive)))
+	switch state {
+	case models.ListingStateActive, models.ListingStateSold, models.ListingStateExpired:
+	default:
+		utils.SendError(c, http.StatusBadRequest, "state must be active, sold or expired", utils.ErrValidation)
+		return
+	}
+
+	limit := 20
+	page := 1
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+	if pageStr := c.Query("page"); pageStr != "" {
+		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+			page = p
+		}
+	}
+
+	posts, total, err := h.postService.GetMyListings(c.Request.Context(), userID.(string), state, limit, (page-1)*limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendPaginated(c, posts, page, limit, total)
+}
+
 // GetMyBookmarks godoc
 // @Summary Get bookmarked posts
-// @Description Get all bookmarked posts for the authenticated user
+// @Description Get all bookmarked posts for the authenticated user, optionally narrowed to one collection
 // @Tags posts
 // @Produce json
 // @Security BearerAuth
+// @Param collection_id query string false "Collection id, or none for bookmarks not in any collection"
 // @Param limit query int false "Limit" default(20)
 // @Param offset query int false "Offset" default(0)
 // @Success 200 {object} utils.Response{data=[]models.PostResponse}
@@ -876,7 +1193,7 @@ func (h *PostHandler) GetMyBookmarks(c *gin.Context) {
 	}
 
 	// Get bookmarks
-	posts, err := h.postService.GetUserBookmarks(c.Request.Context(), userID.(string), limit, offset)
+	posts, err := h.postService.GetUserBookmarks(c.Request.Context(), userID.(string), c.Query("collection_id"), limit, offset)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -964,6 +1281,8 @@ func (h *PostHandler) GetMyEvents(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param file formData file true "Image file to upload"
+// @Param session_id formData string false "Upload session to add the image to"
+// @Param alt_text formData string false "Image description for screen readers"
 // @Success 200 {object} utils.Response{data=models.UploadImageResponse}
 // @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
@@ -995,33 +1314,82 @@ func (h *PostHandler) UploadPostImage(c *gin.Context) {
 	if !utils.EnforceUploadSize(c, header.Size, maxBytes) {
 		return
 	}
+	altText, ok := formAltText(c)
+	if !ok {
+		return
+	}
+
+	// Uploads into a session are claimed by the post that uses them; the
+	// rest are deleted after a day.
+	sessionID := c.PostForm("session_id")
+	if sessionID != "" {
+		if err := h.uploadSessions.CheckOpen(c.Request.Context(), sessionID, userID.(string)); err != nil {
+			h.handleError(c, err)
+			return
+		}
+	}
 
 	// Upload image or video to storage (images 10MB, videos 50MB)
-	photo, err := h.storageService.UploadPostAttachment(c.Request.Context(), file, header)
+	photo, err := h.storageService.UploadPostAttachment(c.Request.Context(), userID.(string), file, header)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
+	photo.AltText = altText
+
+	if sessionID != "" {
+		if err := h.uploadSessions.RecordUpload(c.Request.Context(), sessionID, photo); err != nil {
+			h.handleError(c, err)
+			return
+		}
+	}
 
 	h.logger.Info("Post attachment uploaded successfully",
 		zap.String("user_id", userID.(string)),
 		zap.String("url", photo.URL),
 	)
 
-	utils.SendSuccess(c, http.StatusOK, "Image uploaded successfully", &models.UploadImageResponse{
-		Photo: photo,
-	})
+	utils.SendSuccess(c, http.StatusOK, "Image uploaded successfully", models.NewUploadImageResponse(photo))
 }
 
-// handleError handles service errors and sends appropriate HTTP responses
-func (h *PostHandler) handleError(c *gin.Context, err error) {
-	// Check if it's an AppError
-	if appErr, ok := err.(*utils.AppError); ok {
-		utils.SendError(c, appErr.Code, appErr.Message, appErr.Err)
+// formAltText reads the optional alt_text field of an image upload. It
+// sends 400 and returns false when the text is too long.
+func formAltText(c *gin.Context) (string, bool) {
+	altText, err := models.NormalizeAltText(c.PostForm("alt_text"))
+	if err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrBadRequest)
+		return "", false
+	}
+	return altText, true
+}
+
+// CreateUploadSession godoc
+// @Summary Open an upload session
+// @Description Open a session to upload post images into before creating the post. Pass its id as session_id when uploading and as upload_session_id on create; images not claimed by a post within 24 hours are deleted.
+// @Tags posts
+// @Produce json
+// @Security BearerAuth
+// @Success 201 {object} utils.Response{data=models.UploadSessionResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /posts/upload-sessions [post]
+func (h *PostHandler) CreateUploadSession(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	session, err := h.uploadSessions.Create(c.Request.Context(), userID.(string))
+	if err != nil {
+		h.handleError(c, err)
 		return
 	}
 
-	// Default to internal server error
-	h.logger.Error("Unhandled error in post handler", zap.Error(err))
-	utils.SendError(c, http.StatusInternalServerError, "An error occurred", err)
+	utils.SendCreated(c, "Upload session created", session)
+}
+
+// handleError handles service errors and sends appropriate HTTP responses
+func (h *PostHandler) handleError(c *gin.Context, err error) {
+	middleware.RespondWithError(c, err)
 }
@@ -2,10 +2,12 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/services"
 	"github.com/hamsaya/backend/internal/utils"
@@ -97,6 +99,73 @@ func (h *ProfileHandler) GetUserProfile(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusOK, "Profile retrieved successfully", profile)
 }
 
+// GetProfileByHandle godoc
+// @Summary Get user profile by handle
+// @Description Get a user's profile by their @handle (the @ is optional)
+// @Tags profile
+// @Produce json
+// @Security BearerAuth
+// @Param handle path string true "Handle"
+// @Success 200 {object} utils.Response{data=models.FullProfileResponse}
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /users/by-handle/{handle} [get]
+func (h *ProfileHandler) GetProfileByHandle(c *gin.Context) {
+	var viewerID *string
+	if id, exists := c.Get("user_id"); exists {
+		idStr := id.(string)
+		viewerID = &idStr
+	}
+
+	profile, err := h.profileService.GetProfileByHandle(c.Request.Context(), c.Param("handle"), viewerID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Profile retrieved successfully", profile)
+}
+
+// UpdateHandle godoc
+// @Summary Set handle
+// @Description Claim a unique @handle: 3-30 lowercase letters, digits or underscores, starting with a letter. It can be changed again after 30 days.
+// @Tags profile
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.UpdateHandleRequest true "Handle"
+// @Success 200 {object} utils.Response{data=models.FullProfileResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Router /users/me/handle [put]
+func (h *ProfileHandler) UpdateHandle(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	var req models.UpdateHandleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	profile, err := h.profileService.SetHandle(c.Request.Context(), userID.(string), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Handle updated successfully", profile)
+}
+
 // ExportData godoc
 // @Summary Export user data (GDPR Article 20)
 // @Description Returns a JSON dump of the authenticated user's owned data:
@@ -140,6 +209,7 @@ func (h *ProfileHandler) ExportData(c *gin.Context) {
 // @Success 200 {object} utils.Response{data=models.FullProfileResponse}
 // @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
+// @Failure 409 {object} utils.Response{data=models.FullProfileResponse} "Edited elsewhere since version; data is the current profile"
 // @Failure 500 {object} utils.Response
 // @Router /users/me [put]
 func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
@@ -194,6 +264,170 @@ func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusOK, "Profile updated successfully", profile)
 }
 
+// GetMyProfileViews godoc
+// @Summary Get views of my profile
+// @Description Daily counts of signed-in views of the authenticated user's profile, once per viewer per day. Viewers are never listed.
+// @Tags profile
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "Window in days (default 28, max 365)"
+// @Success 200 {object} utils.Response{data=models.ProfileViewStats}
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /users/me/profile-views [get]
+func (h *ProfileHandler) GetMyProfileViews(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	days := 28
+	if daysStr := c.Query("days"); daysStr != "" {
+		if d, err := strconv.Atoi(daysStr); err == nil {
+			days = d
+		}
+	}
+
+	stats, err := h.profileService.GetMyProfileViews(c.Request.Context(), userID.(string), days)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Profile views retrieved successfully", stats)
+}
+
+// GetPrivacySettings godoc
+// @Summary Get privacy settings
+// @Description Get who can see the authenticated user's location, email and phone
+// @Tags profile
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=models.PrivacySettings}
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /users/me/privacy [get]
+func (h *ProfileHandler) GetPrivacySettings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	settings, err := h.profileService.GetPrivacySettings(c.Request.Context(), userID.(string))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Privacy settings retrieved successfully", settings)
+}
+
+// UpdatePrivacySettings godoc
+// @Summary Update privacy settings
+// @Description Set who can see the authenticated user's location, email and phone: EVERYONE, FOLLOWERS or NOBODY
+// @Tags profile
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.UpdatePrivacySettingsRequest true "Privacy settings"
+// @Success 200 {object} utils.Response{data=models.PrivacySettings}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /users/me/privacy [put]
+func (h *ProfileHandler) UpdatePrivacySettings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	var req models.UpdatePrivacySettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	settings, err := h.profileService.UpdatePrivacySettings(c.Request.Context(), userID.(string), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Privacy settings updated successfully", settings)
+}
+
+// GetContentLanguages godoc
+// @Summary Get content languages
+// @Description Get the languages the authenticated user reads. Feed and search show posts in these languages unless ?lang is given.
+// @Tags profile
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=models.ContentLanguages}
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /users/me/content-languages [get]
+func (h *ProfileHandler) GetContentLanguages(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	languages, err := h.profileService.GetContentLanguages(c.Request.Context(), userID.(string))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Content languages retrieved successfully", languages)
+}
+
+// UpdateContentLanguages godoc
+// @Summary Update content languages
+// @Description Set the languages the authenticated user reads (fa, ps, ar, en). An empty list shows every language.
+// @Tags profile
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.ContentLanguages true "Content languages"
+// @Success 200 {object} utils.Response{data=models.ContentLanguages}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /users/me/content-languages [put]
+func (h *ProfileHandler) UpdateContentLanguages(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	var req models.ContentLanguages
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	languages, err := h.profileService.UpdateContentLanguages(c.Request.Context(), userID.(string), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Content languages updated successfully", languages)
+}
+
 // UploadAvatar godoc
 // @Summary Upload avatar
 // @Description Upload a new avatar image for the authenticated user
@@ -202,6 +436,7 @@ func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param file formData file true "Avatar image file (JPEG/PNG, max 10MB)"
+// @Param alt_text formData string false "Image description for screen readers"
 // @Success 200 {object} utils.Response{data=models.UploadImageResponse}
 // @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
@@ -226,13 +461,18 @@ func (h *ProfileHandler) UploadAvatar(c *gin.Context) {
 	if !utils.EnforceUploadSize(c, header.Size, utils.MaxImageUploadBytes) {
 		return
 	}
+	altText, ok := formAltText(c)
+	if !ok {
+		return
+	}
 
 	// Upload image
-	photo, err := h.storageService.UploadImage(c.Request.Context(), file, header, services.ImageTypeAvatar)
+	photo, err := h.storageService.UploadImage(c.Request.Context(), userID.(string), file, header, services.ImageTypeAvatar)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
+	photo.AltText = altText
 
 	// Update profile avatar
 	if err := h.profileService.UpdateAvatar(c.Request.Context(), userID.(string), photo); err != nil {
@@ -240,9 +480,9 @@ func (h *ProfileHandler) UploadAvatar(c *gin.Context) {
 		return
 	}
 
-	utils.SendSuccess(c, http.StatusOK, "Avatar uploaded successfully", &models.UploadImageResponse{
-		Photo: photo,
-	})
+	resp := models.NewUploadImageResponse(photo)
+	resp.Pending = h.profileService.MediaHeldForReview()
+	utils.SendSuccess(c, http.StatusOK, "Avatar uploaded successfully", resp)
 }
 
 // DeleteAvatar godoc
@@ -280,6 +520,7 @@ func (h *ProfileHandler) DeleteAvatar(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param file formData file true "Cover image file (JPEG/PNG, max 10MB)"
+// @Param alt_text formData string false "Image description for screen readers"
 // @Success 200 {object} utils.Response{data=models.UploadImageResponse}
 // @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
@@ -304,13 +545,18 @@ func (h *ProfileHandler) UploadCover(c *gin.Context) {
 	if !utils.EnforceUploadSize(c, header.Size, utils.MaxImageUploadBytes) {
 		return
 	}
+	altText, ok := formAltText(c)
+	if !ok {
+		return
+	}
 
 	// Upload image
-	photo, err := h.storageService.UploadImage(c.Request.Context(), file, header, services.ImageTypeCover)
+	photo, err := h.storageService.UploadImage(c.Request.Context(), userID.(string), file, header, services.ImageTypeCover)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
+	photo.AltText = altText
 
 	// Update profile cover
 	if err := h.profileService.UpdateCover(c.Request.Context(), userID.(string), photo); err != nil {
@@ -318,9 +564,9 @@ func (h *ProfileHandler) UploadCover(c *gin.Context) {
 		return
 	}
 
-	utils.SendSuccess(c, http.StatusOK, "Cover photo uploaded successfully", &models.UploadImageResponse{
-		Photo: photo,
-	})
+	resp := models.NewUploadImageResponse(photo)
+	resp.Pending = h.profileService.MediaHeldForReview()
+	utils.SendSuccess(c, http.StatusOK, "Cover photo uploaded successfully", resp)
 }
 
 // DeleteCover godoc
@@ -350,6 +596,71 @@ func (h *ProfileHandler) DeleteCover(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusOK, "Cover photo deleted successfully", nil)
 }
 
+// GetStorageUsage godoc
+// @Summary Get storage usage
+// @Description Returns the authenticated user's upload total against their storage quota and a page of their uploads, so old ones can be deleted to free space
+// @Tags profile
+// @Produce json
+// @Security BearerAuth
+// @Param sort query string false "oldest (default), largest or newest"
+// @Param limit query int false "Uploads per page (default 20, max 100)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} utils.Response{data=models.StorageUsageResponse}
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /users/me/storage [get]
+func (h *ProfileHandler) GetStorageUsage(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	offset, _ := strconv.Atoi(c.Query("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	usage, err := h.storageService.GetStorageUsage(c.Request.Context(), userID.(string), c.Query("sort"), limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Storage usage retrieved", usage)
+}
+
+// DeleteStorageObject godoc
+// @Summary Delete an upload
+// @Description Deletes one of the authenticated user's uploads to free storage. Uploads still shown on a post, comment or profile are refused with 409.
+// @Tags profile
+// @Produce json
+// @Security BearerAuth
+// @Param object_id path string true "Upload ID"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /users/me/storage/{object_id} [delete]
+func (h *ProfileHandler) DeleteStorageObject(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	if err := h.storageService.DeleteStorageObject(c.Request.Context(), userID.(string), c.Param("object_id")); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Upload deleted", nil)
+}
+
 // DeleteAccount godoc
 // @Summary Deactivate (soft delete) account
 // @Description Soft-deletes the authenticated user's account and revokes all sessions
@@ -396,13 +707,5 @@ func (h *ProfileHandler) DeleteAccount(c *gin.Context) {
 
 // handleError handles service errors and sends appropriate HTTP responses
 func (h *ProfileHandler) handleError(c *gin.Context, err error) {
-	// Check if it's an AppError
-	if appErr, ok := err.(*utils.AppError); ok {
-		utils.SendError(c, appErr.Code, appErr.Message, appErr.Err)
-		return
-	}
-
-	// Default to internal server error
-	h.logger.Error("Unhandled error in profile handler", zap.Error(err))
-	utils.SendError(c, http.StatusInternalServerError, "An error occurred", err)
+	middleware.RespondWithError(c, err)
 }
@@ -5,6 +5,8 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
+	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/services"
 	"github.com/hamsaya/backend/internal/utils"
 	"go.uber.org/zap"
@@ -335,6 +337,65 @@ func (h *RelationshipsHandler) GetBlockedUsers(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusOK, "Blocked users retrieved successfully", blockedUsers)
 }
 
+// BulkBlockUsers godoc
+// @Summary Block many users
+// @Description Block up to 500 users at once, e.g. from an imported community blocklist. Unknown accounts and users already blocked are skipped.
+// @Tags relationships
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.BulkBlockRequest true "User IDs to block"
+// @Success 200 {object} utils.Response{data=models.BulkBlockResponse}
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /users/blocked/bulk [post]
+func (h *RelationshipsHandler) BulkBlockUsers(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	var req models.BulkBlockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+
+	result, err := h.relationshipsService.BulkBlockUsers(c.Request.Context(), userID.(string), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Users blocked successfully", result)
+}
+
+// ExportBlockList godoc
+// @Summary Export block list
+// @Description Export every blocked user ID, in the shape the bulk block endpoint accepts
+// @Tags relationships
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=models.BlockListExport}
+// @Failure 401 {object} utils.Response
+// @Router /users/blocked/export [get]
+func (h *RelationshipsHandler) ExportBlockList(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.SendError(c, http.StatusUnauthorized, "User not authenticated", utils.ErrUnauthorized)
+		return
+	}
+
+	export, err := h.relationshipsService.ExportBlockList(c.Request.Context(), userID.(string))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Block list exported successfully", export)
+}
+
 // GetRelationshipStatus godoc
 // @Summary Get relationship status
 // @Description Get the relationship status between authenticated user and another user
@@ -369,13 +430,5 @@ func (h *RelationshipsHandler) GetRelationshipStatus(c *gin.Context) {
 
 // handleError handles service errors and sends appropriate HTTP responses
 func (h *RelationshipsHandler) handleError(c *gin.Context, err error) {
-	// Check if it's an AppError
-	if appErr, ok := err.(*utils.AppError); ok {
-		utils.SendError(c, appErr.Code, appErr.Message, appErr.Err)
-		return
-	}
-
-	// Default to internal server error
-	h.logger.Error("Unhandled error in relationships handler", zap.Error(err))
-	utils.SendError(c, http.StatusInternalServerError, "An error occurred", err)
+	middleware.RespondWithError(c, err)
 }
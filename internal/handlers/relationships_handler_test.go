@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -41,6 +42,8 @@ func newRelationshipsRouter(
 	r.GET("/api/v1/users/:user_id/followers", authed, h.GetFollowers)
 	r.GET("/api/v1/users/:user_id/following", authed, h.GetFollowing)
 	r.GET("/api/v1/users/blocked", authed, h.GetBlockedUsers)
+	r.GET("/api/v1/users/blocked/export", authed, h.ExportBlockList)
+	r.POST("/api/v1/users/blocked/bulk", authed, h.BulkBlockUsers)
 	r.GET("/api/v1/users/:user_id/relationship", authed, h.GetRelationshipStatus)
 
 	// Unauthed routes for context-missing tests
@@ -217,6 +220,7 @@ func TestRelationshipsHandler_BlockUser(t *testing.T) {
 		// BlockUser also removes any follow relationships
 		relRepo.On("UnfollowUser", mock.Anything, relTestUserID, relTestTargetID).Return(nil).Maybe()
 		relRepo.On("UnfollowUser", mock.Anything, relTestTargetID, relTestUserID).Return(nil).Maybe()
+		relRepo.On("CountBlockedUsers", mock.Anything, relTestUserID).Return(0, nil)
 		relRepo.On("BlockUser", mock.Anything, relTestUserID, relTestTargetID).Return(nil)
 		r := newRelationshipsRouter(t, relRepo, userRepo)
 
@@ -277,3 +281,53 @@ func TestRelationshipsHandler_GetBlockedUsers(t *testing.T) {
 		assert.Less(t, w.Code, 500)
 	})
 }
+
+// --- Bulk block / export ---
+
+func TestRelationshipsHandler_BulkBlockUsers(t *testing.T) {
+	t.Run("rejects non-UUIDs", func(t *testing.T) {
+		r := newRelationshipsRouter(t, &mocks.MockRelationshipsRepository{}, &mocks.MockUserRepository{})
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/users/blocked/bulk", strings.NewReader(`{"user_ids":["nope"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		a, b := "9f1c3a52-6d7e-4f40-8d2b-1a2b3c4d5e6f", "0b6f0f4e-2f0a-4c7b-9a8e-7d6c5b4a3f2e"
+		relRepo := &mocks.MockRelationshipsRepository{}
+		relRepo.On("BlockUsers", mock.Anything, relTestUserID, []string{a, b}, models.MaxBlockedUsers).
+			Return([]string{a}, nil)
+		r := newRelationshipsRouter(t, relRepo, &mocks.MockUserRepository{})
+
+		w := httptest.NewRecorder()
+		body := fmt.Sprintf(`{"user_ids":["%s","%s","%s"]}`, a, b, a)
+		req, _ := http.NewRequest(http.MethodPost, "/api/v1/users/blocked/bulk", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		data := parseBody(t, w)["data"].(map[string]interface{})
+		assert.Equal(t, float64(1), data["blocked"])
+		assert.Equal(t, float64(1), data["skipped"])
+	})
+}
+
+func TestRelationshipsHandler_ExportBlockList(t *testing.T) {
+	relRepo := &mocks.MockRelationshipsRepository{}
+	relRepo.On("GetBlockedUsers", mock.Anything, relTestUserID, models.MaxBlockedUsers, 0).
+		Return([]*models.UserBlock{{BlockerID: relTestUserID, BlockedID: relTestTargetID}}, nil)
+	r := newRelationshipsRouter(t, relRepo, &mocks.MockUserRepository{})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/users/blocked/export", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	data := parseBody(t, w)["data"].(map[string]interface{})
+	assert.Equal(t, []interface{}{relTestTargetID}, data["user_ids"])
+	assert.Equal(t, float64(1), data["count"])
+}
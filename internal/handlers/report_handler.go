@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/services"
 	"github.com/hamsaya/backend/internal/utils"
@@ -24,14 +25,7 @@ func NewReportHandler(reportService *services.ReportService) *ReportHandler {
 
 // handleError handles errors in a consistent way
 func (h *ReportHandler) handleError(c *gin.Context, err error) {
-	// Check if it's an AppError
-	if appErr, ok := err.(*utils.AppError); ok {
-		utils.SendError(c, appErr.Code, appErr.Message, appErr.Err)
-		return
-	}
-
-	// Default to internal server error
-	utils.SendInternalServerError(c, "An error occurred", err)
+	middleware.RespondWithError(c, err)
 }
 
 // ReportPost godoc
@@ -47,6 +41,7 @@ func (h *ReportHandler) handleError(c *gin.Context, err error) {
 // @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
 // @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /posts/{post_id}/report [post]
 func (h *ReportHandler) ReportPost(c *gin.Context) {
@@ -88,6 +83,7 @@ func (h *ReportHandler) ReportPost(c *gin.Context) {
 // @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
 // @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /comments/{comment_id}/report [post]
 func (h *ReportHandler) ReportComment(c *gin.Context) {
@@ -121,6 +117,7 @@ func (h *ReportHandler) ReportComment(c *gin.Context) {
 // @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
 // @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /users/{user_id}/report [post]
 func (h *ReportHandler) ReportUser(c *gin.Context) {
@@ -154,6 +151,7 @@ func (h *ReportHandler) ReportUser(c *gin.Context) {
 // @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
 // @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /businesses/{business_id}/report [post]
 func (h *ReportHandler) ReportBusiness(c *gin.Context) {
@@ -173,3 +171,37 @@ func (h *ReportHandler) ReportBusiness(c *gin.Context) {
 
 	utils.SendCreated(c, "Business reported successfully", nil)
 }
+
+// ReportMessage godoc
+// @Summary Report a chat message
+// @Description Report a message in one of your conversations. The report keeps a copy of the message for moderators.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param message_id path string true "Message ID"
+// @Param request body models.CreateMessageReportRequest true "Report details"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /chat/messages/{message_id}/report [post]
+func (h *ReportHandler) ReportMessage(c *gin.Context) {
+	userID := c.GetString("user_id")
+	messageID := c.Param("message_id")
+
+	var req models.CreateMessageReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendBadRequest(c, "Invalid request body", err)
+		return
+	}
+
+	if err := h.reportService.ReportMessage(c.Request.Context(), userID, messageID, &req); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendCreated(c, "Message reported successfully", nil)
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/services"
 	"github.com/hamsaya/backend/internal/utils"
@@ -45,6 +46,11 @@ func NewSearchHandler(
 // @Param latitude query number false "Latitude for location-based search"
 // @Param longitude query number false "Longitude for location-based search"
 // @Param radius_km query number false "Radius in kilometers for location-based search"
+// @Param post_type query string false "Post type chip" Enums(FEED, EVENT, SELL, PULL, LOST_FOUND, ALERT, HELP)
+// @Param category_id query string false "Category chip"
+// @Param province query string false "Province chip"
+// @Param lang query string false "Comma-separated languages (fa, ps, ar, en), or all; defaults to the user's content languages"
+// @Param facets query bool false "Include per type / category / province counts"
 // @Security BearerAuth
 // @Success 200 {object} utils.Response{data=models.SearchResponse}
 // @Failure 400 {object} utils.Response
@@ -110,6 +116,7 @@ func (h *SearchHandler) Search(c *gin.Context) {
 		Longitude: longitude,
 		RadiusKm:  radiusKm,
 	}
+	parsePostSearchChips(c, req)
 
 	// Validate request
 	if err := h.validator.Validate(req); err != nil {
@@ -146,6 +153,11 @@ func (h *SearchHandler) Search(c *gin.Context) {
 // @Param latitude query number false "Latitude for location-based search"
 // @Param longitude query number false "Longitude for location-based search"
 // @Param radius_km query number false "Radius in kilometers"
+// @Param post_type query string false "Post type chip" Enums(FEED, EVENT, SELL, PULL, LOST_FOUND, ALERT, HELP)
+// @Param category_id query string false "Category chip"
+// @Param province query string false "Province chip"
+// @Param lang query string false "Comma-separated languages (fa, ps, ar, en), or all; defaults to the user's content languages"
+// @Param facets query bool false "Include per type / category / province counts"
 // @Security BearerAuth
 // @Success 200 {object} utils.Response{data=[]models.PostResponse}
 // @Failure 400 {object} utils.Response
@@ -204,6 +216,11 @@ func (h *SearchHandler) SearchPosts(c *gin.Context) {
 		Longitude: longitude,
 		RadiusKm:  radiusKm,
 	}
+	parsePostSearchChips(c, req)
+	if err := h.validator.Validate(req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
 
 	var userID *string
 	if id, exists := c.Get("user_id"); exists {
@@ -217,9 +234,33 @@ func (h *SearchHandler) SearchPosts(c *gin.Context) {
 		return
 	}
 
+	// The flat list stays the default; facets need the wrapping object.
+	if req.IncludeFacets {
+		utils.SendSuccess(c, http.StatusOK, "Posts found", gin.H{
+			"posts":  results.Posts,
+			"facets": results.Facets,
+		})
+		return
+	}
 	utils.SendSuccess(c, http.StatusOK, "Posts found", results.Posts)
 }
 
+// parsePostSearchChips reads the post filter chips, ?lang and ?facets=true.
+func parsePostSearchChips(c *gin.Context, req *models.SearchRequest) {
+	if v := strings.ToUpper(strings.TrimSpace(c.Query("post_type"))); v != "" {
+		pt := models.PostType(v)
+		req.PostType = &pt
+	}
+	if v := strings.TrimSpace(c.Query("category_id")); v != "" {
+		req.CategoryID = &v
+	}
+	if v := strings.TrimSpace(c.Query("province")); v != "" {
+		req.Province = &v
+	}
+	req.Languages = parseLanguages(c)
+	req.IncludeFacets, _ = strconv.ParseBool(c.Query("facets"))
+}
+
 // SearchUsers handles GET /api/v1/search/users
 // @Summary Search users
 // @Description Search for users by name
@@ -379,6 +420,7 @@ func (h *SearchHandler) SearchBusinesses(c *gin.Context) {
 // @Param filter query string false "Filter: all (default), business, event, sell"
 // @Param type query string false "Post type filter: FEED, EVENT, SELL, PULL"
 // @Param limit query int false "Limit results (default 100, max 500)"
+// @Param province query string false "Province for featured businesses (default: the viewer's province)"
 // @Security BearerAuth
 // @Success 200 {object} utils.Response{data=models.DiscoverResponse}
 // @Failure 400 {object} utils.Response
@@ -450,6 +492,7 @@ func (h *SearchHandler) Discover(c *gin.Context) {
 		Filter:    filter,
 		Type:      postType,
 		Limit:     limit,
+		Province:  strings.TrimSpace(c.Query("province")),
 	}
 
 	// Validate request
@@ -482,15 +525,111 @@ func (h *SearchHandler) Discover(c *gin.Context) {
 	utils.SendSuccess(c, http.StatusOK, "Discovery completed successfully", results)
 }
 
-// handleError handles service errors and sends appropriate HTTP responses
-func (h *SearchHandler) handleError(c *gin.Context, err error) {
-	// Check if it's an AppError
-	if appErr, ok := err.(*utils.AppError); ok {
-		utils.SendError(c, appErr.Code, appErr.Message, appErr.Err)
+// Suggest handles GET /api/v1/search/suggest
+// @Summary Search autocomplete
+// @Description Prefix-matched users, businesses, categories and hashtags ranked by popularity, plus the caller's matching recent searches. A leading # returns hashtags only.
+// @Tags Search
+// @Produce json
+// @Param q query string false "What the user has typed so far"
+// @Param limit query int false "Entries per kind (default 5, max 10)"
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=models.SearchSuggestResponse}
+// @Router /search/suggest [get]
+func (h *SearchHandler) Suggest(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	var userID *string
+	if id, exists := c.Get("user_id"); exists {
+		userIDStr := id.(string)
+		userID = &userIDStr
+	}
+
+	results, err := h.searchService.Suggest(c.Request.Context(), userID, c.Query("q"), limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Suggestions retrieved successfully", results)
+}
+
+// ListRecentSearches handles GET /api/v1/search/recent
+// @Summary Recent searches
+// @Tags Search
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=[]models.RecentSearch}
+// @Router /search/recent [get]
+func (h *SearchHandler) ListRecentSearches(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	searches, err := h.searchService.ListRecentSearches(c.Request.Context(), userID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Recent searches retrieved successfully", searches)
+}
+
+// ClearRecentSearches handles DELETE /api/v1/search/recent
+// @Summary Clear recent searches
+// @Tags Search
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response
+// @Router /search/recent [delete]
+func (h *SearchHandler) ClearRecentSearches(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if err := h.searchService.ClearRecentSearches(c.Request.Context(), userID); err != nil {
+		h.handleError(c, err)
 		return
 	}
 
-	// Default to internal server error
-	h.logger.Error("Unhandled error in search handler", zap.Error(err))
-	utils.SendError(c, http.StatusInternalServerError, "An error occurred", err)
+	utils.SendSuccess(c, http.StatusOK, "Recent searches cleared", nil)
+}
+
+// AdminSearch handles GET /api/v1/admin/search
+// @Summary Admin content search
+// @Description Searches posts, comments, users, businesses and reported chat messages in one list, hidden content included, each result badged with its entity type. Restricted to the entities the admin's permissions cover.
+// @Tags Admin
+// @Produce json
+// @Param q query string true "Search query (at least 2 characters)"
+// @Param types query string false "Comma-separated entity types: post, comment, user, business, message"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Offset"
+// @Security BearerAuth
+// @Success 200 {object} utils.ListResponse
+// @Failure 403 {object} utils.Response
+// @Router /admin/search [get]
+func (h *SearchHandler) AdminSearch(c *gin.Context) {
+	admin, _ := middleware.GetAdminUser(c)
+	limit, offset := pageParams(c)
+
+	var types []models.AdminSearchEntity
+	for _, t := range strings.Split(c.Query("types"), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, models.AdminSearchEntity(strings.ToLower(t)))
+		}
+	}
+
+	results, total, searched, err := h.searchService.AdminSearch(c.Request.Context(), admin, c.Query("q"), types, limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendList(c, "Search completed successfully", results, utils.OffsetMeta(limit, offset, total), gin.H{
+		"items":  results,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+		"types":  searched,
+	})
+}
+
+// handleError handles service errors and sends appropriate HTTP responses
+func (h *SearchHandler) handleError(c *gin.Context, err error) {
+	middleware.RespondWithError(c, err)
 }
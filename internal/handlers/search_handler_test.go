@@ -23,10 +23,12 @@ func newSearchRouter(
 	searchRepo *mocks.MockSearchRepository,
 ) *gin.Engine {
 	t.Helper()
+	userRepo := &mocks.MockUserRepository{}
+	userRepo.On("GetContentLanguages", mock.Anything, searchTestUserID).Return([]string{}, nil).Maybe()
 	svc := services.NewSearchService(
 		searchRepo,
 		&mocks.MockPostRepository{},
-		&mocks.MockUserRepository{},
+		userRepo,
 		&mocks.MockBusinessRepository{},
 		&mocks.MockCategoryRepository{},
 		&mocks.MockRelationshipsRepository{},
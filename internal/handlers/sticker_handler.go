@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/services"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// StickerHandler serves the chat sticker catalog and its admin management.
+type StickerHandler struct {
+	service   *services.StickerService
+	validator *utils.Validator
+	logger    *zap.Logger
+}
+
+// NewStickerHandler wires the handler.
+func NewStickerHandler(service *services.StickerService, validator *utils.Validator, logger *zap.Logger) *StickerHandler {
+	return &StickerHandler{
+		service:   service,
+		validator: validator,
+		logger:    logger,
+	}
+}
+
+func (h *StickerHandler) sendErr(c *gin.Context, err error) {
+	middleware.RespondWithError(c, err)
+}
+
+// GetCatalog returns the active sticker packs for the chat sticker picker.
+// @Tags         chat
+// @Security     BearerAuth
+// @Success      200 {object} utils.Response{data=[]models.StickerPack}
+// @Router       /chat/stickers [get]
+func (h *StickerHandler) GetCatalog(c *gin.Context) {
+	packs, err := h.service.Catalog(c.Request.Context())
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Sticker packs", packs)
+}
+
+// AdminListPacks returns every sticker pack, hidden ones included.
+// @Tags         admin
+// @Security     BearerAuth
+// @Success      200 {object} utils.Response{data=[]models.StickerPack}
+// @Router       /admin/sticker-packs [get]
+func (h *StickerHandler) AdminListPacks(c *gin.Context) {
+	packs, err := h.service.ListPacks(c.Request.Context())
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Sticker packs", packs)
+}
+
+// AdminCreatePack adds a sticker pack to the catalog.
+// @Tags         admin
+// @Security     BearerAuth
+// @Param        body body models.CreateStickerPackRequest true "Pack with its stickers"
+// @Success      201 {object} utils.Response{data=models.StickerPack}
+// @Router       /admin/sticker-packs [post]
+func (h *StickerHandler) AdminCreatePack(c *gin.Context) {
+	var req models.CreateStickerPackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	pack, err := h.service.CreatePack(c.Request.Context(), &req)
+	if err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusCreated, "Sticker pack created", pack)
+}
+
+// AdminSetPackActive shows or hides a sticker pack in the catalog.
+// @Tags         admin
+// @Security     BearerAuth
+// @Param        pack_id path string true "Pack ID"
+// @Param        body body models.SetStickerPackActiveRequest true "Visibility"
+// @Success      200 {object} utils.Response
+// @Router       /admin/sticker-packs/{pack_id} [put]
+func (h *StickerHandler) AdminSetPackActive(c *gin.Context) {
+	var req models.SetStickerPackActiveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid request body", utils.ErrInvalidJSON)
+		return
+	}
+	if err := h.validator.Validate(&req); err != nil {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+
+	if err := h.service.SetPackActive(c.Request.Context(), c.Param("pack_id"), *req.IsActive); err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Sticker pack updated", nil)
+}
+
+// AdminDeletePack removes a sticker pack and its stickers.
+// @Tags         admin
+// @Security     BearerAuth
+// @Param        pack_id path string true "Pack ID"
+// @Success      200 {object} utils.Response
+// @Router       /admin/sticker-packs/{pack_id} [delete]
+func (h *StickerHandler) AdminDeletePack(c *gin.Context) {
+	if err := h.service.DeletePack(c.Request.Context(), c.Param("pack_id")); err != nil {
+		h.sendErr(c, err)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "Sticker pack deleted", nil)
+}
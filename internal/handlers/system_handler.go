@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"runtime"
 	"strconv"
@@ -8,9 +9,12 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
 	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/services"
 	"github.com/hamsaya/backend/internal/utils"
 	"github.com/hamsaya/backend/pkg/database"
+	"github.com/hamsaya/backend/pkg/runtimeconfig"
 	"github.com/hamsaya/backend/pkg/storage"
 	"github.com/hamsaya/backend/pkg/websocket"
 	"github.com/redis/go-redis/v9"
@@ -27,6 +31,9 @@ type SystemHandler struct {
 	flagRepo  repositories.FeatureFlagRepository
 	hub       *websocket.Hub
 	storage   *storage.Client
+	settings  *runtimeconfig.Store
+	mode      *middleware.ServiceMode
+	reconcile *services.StorageReconcileService
 	logger    *zap.Logger
 	startedAt time.Time
 }
@@ -50,6 +57,27 @@ func NewSystemHandler(
 	}
 }
 
+// WithRuntimeSettings enables the /system/settings endpoints and mirrors
+// feature flag toggles into the runtime store so every instance sees them
+// on its next refresh.
+func (h *SystemHandler) WithRuntimeSettings(store *runtimeconfig.Store) *SystemHandler {
+	h.settings = store
+	return h
+}
+
+// WithServiceMode enables the /system/mode endpoints. The switches are
+// runtime settings, so WithRuntimeSettings must be wired too.
+func (h *SystemHandler) WithServiceMode(mode *middleware.ServiceMode) *SystemHandler {
+	h.mode = mode
+	return h
+}
+
+// WithStorageReconcile enables the /system/storage-reconcile report.
+func (h *SystemHandler) WithStorageReconcile(svc *services.StorageReconcileService) *SystemHandler {
+	h.reconcile = svc
+	return h
+}
+
 // BuildInfo returns ldflags-injected build metadata + runtime info, surfaced
 // to the /system page so super_admins can confirm what is actually running.
 // @Router /admin/system/build-info [get]
@@ -384,9 +412,171 @@ func (h *SystemHandler) FlagsToggle(c *gin.Context) {
 		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
 		return
 	}
+	if h.settings != nil {
+		if err := h.settings.Set(c.Request.Context(), "flag."+key, strconv.FormatBool(body.Enabled)); err != nil {
+			h.logger.Warn("feature flag runtime mirror failed", zap.String("key", key), zap.Error(err))
+		}
+	}
 	utils.SendSuccess(c, http.StatusOK, "Flag updated", nil)
 }
 
+// SettingsList returns every hot-reloadable runtime setting (rate limits,
+// feature flags) with its default and effective value.
+// @Router /admin/system/settings [get]
+func (h *SystemHandler) SettingsList(c *gin.Context) {
+	if h.settings == nil {
+		utils.SendSuccess(c, http.StatusOK, "ok", gin.H{"available": false})
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "ok", gin.H{
+		"available": true,
+		"settings":  h.settings.List(),
+	})
+}
+
+// SettingsUpdate overrides one runtime setting. Body: {"value": string}.
+// Values are validated against the setting's kind ("60", "5m", "true").
+// "flag.*" keys are written through to feature_flags so the database stays
+// the durable source of truth.
+// @Router /admin/system/settings/{key} [put]
+func (h *SystemHandler) SettingsUpdate(c *gin.Context) {
+	if h.settings == nil {
+		utils.SendError(c, http.StatusServiceUnavailable, "Runtime settings unavailable", utils.ErrInternalServer)
+		return
+	}
+	key := c.Param("key")
+
+	var body struct {
+		Value string `json:"value" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid body", utils.ErrInvalidJSON)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if flagKey, ok := strings.CutPrefix(key, "flag."); ok {
+		enabled, err := strconv.ParseBool(body.Value)
+		if err != nil {
+			utils.SendError(c, http.StatusBadRequest, "Flag value must be true or false", utils.ErrValidation)
+			return
+		}
+		userID, _ := c.Get("user_id")
+		uid, _ := userID.(string)
+		if err := h.flagRepo.Set(ctx, flagKey, enabled, uid); err != nil {
+			utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+			return
+		}
+	}
+
+	if err := h.settings.Set(ctx, key, body.Value); err != nil {
+		h.sendSettingsError(c, key, err)
+		return
+	}
+	h.logger.Info("runtime setting updated", zap.String("key", key), zap.String("value", body.Value))
+	utils.SendSuccess(c, http.StatusOK, "Setting updated", nil)
+}
+
+// SettingsReset drops an override so the setting returns to its default.
+// @Router /admin/system/settings/{key} [delete]
+func (h *SystemHandler) SettingsReset(c *gin.Context) {
+	if h.settings == nil {
+		utils.SendError(c, http.StatusServiceUnavailable, "Runtime settings unavailable", utils.ErrInternalServer)
+		return
+	}
+	key := c.Param("key")
+	if strings.HasPrefix(key, "flag.") {
+		utils.SendError(c, http.StatusBadRequest, "Feature flags cannot be reset; set them explicitly", utils.ErrValidation)
+		return
+	}
+	if err := h.settings.Reset(c.Request.Context(), key); err != nil {
+		h.sendSettingsError(c, key, err)
+		return
+	}
+	h.logger.Info("runtime setting reset", zap.String("key", key))
+	utils.SendSuccess(c, http.StatusOK, "Setting reset", nil)
+}
+
+// ModeGet reports whether maintenance or read-only mode is on.
+// @Router /admin/system/mode [get]
+func (h *SystemHandler) ModeGet(c *gin.Context) {
+	if h.mode == nil {
+		utils.SendSuccess(c, http.StatusOK, "ok", gin.H{"available": false})
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "ok", h.mode.Status())
+}
+
+// ModeUpdate flips the maintenance / read-only switches. Body:
+// {"maintenance": bool, "read_only": bool, "retry_after": "10m"}; omitted
+// fields are left as they are. This instance applies the change at once,
+// the rest of the fleet on its next settings refresh.
+// @Router /admin/system/mode [put]
+func (h *SystemHandler) ModeUpdate(c *gin.Context) {
+	if h.mode == nil || h.settings == nil {
+		utils.SendError(c, http.StatusServiceUnavailable, "Runtime settings unavailable", utils.ErrInternalServer)
+		return
+	}
+
+	var body struct {
+		Maintenance *bool   `json:"maintenance"`
+		ReadOnly    *bool   `json:"read_only"`
+		RetryAfter  *string `json:"retry_after"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		utils.SendError(c, http.StatusBadRequest, "Invalid body", utils.ErrInvalidJSON)
+		return
+	}
+
+	// Retry-After first so clients turned away by the switch get the new
+	// value straight away.
+	updates := make([][2]string, 0, 3)
+	if body.RetryAfter != nil {
+		updates = append(updates, [2]string{middleware.SettingModeRetryAfter, *body.RetryAfter})
+	}
+	if body.Maintenance != nil {
+		updates = append(updates, [2]string{middleware.SettingMaintenanceMode, strconv.FormatBool(*body.Maintenance)})
+	}
+	if body.ReadOnly != nil {
+		updates = append(updates, [2]string{middleware.SettingReadOnlyMode, strconv.FormatBool(*body.ReadOnly)})
+	}
+	if len(updates) == 0 {
+		utils.SendError(c, http.StatusBadRequest, "Nothing to update", utils.ErrValidation)
+		return
+	}
+
+	ctx := c.Request.Context()
+	for _, u := range updates {
+		if err := h.settings.Set(ctx, u[0], u[1]); err != nil {
+			h.sendSettingsError(c, u[0], err)
+			return
+		}
+	}
+
+	userID, _ := c.Get("user_id")
+	uid, _ := userID.(string)
+	status := h.mode.Status()
+	h.logger.Warn("service mode updated",
+		zap.String("admin_id", uid),
+		zap.Bool("maintenance", status.Maintenance),
+		zap.Bool("read_only", status.ReadOnly),
+		zap.Int("retry_after_seconds", status.RetryAfterSeconds))
+	utils.SendSuccess(c, http.StatusOK, "Service mode updated", status)
+}
+
+func (h *SystemHandler) sendSettingsError(c *gin.Context, key string, err error) {
+	if errors.Is(err, runtimeconfig.ErrUnknownSetting) {
+		utils.SendError(c, http.StatusNotFound, "Unknown setting", utils.ErrNotFound)
+		return
+	}
+	if errors.Is(err, runtimeconfig.ErrInvalidValue) {
+		utils.SendError(c, http.StatusBadRequest, err.Error(), utils.ErrValidation)
+		return
+	}
+	h.logger.Error("runtime setting write failed", zap.String("key", key), zap.Error(err))
+	utils.SendError(c, http.StatusInternalServerError, "Failed to update setting", utils.ErrInternalServer)
+}
+
 // DenylistStats reports the size of the JWT access-token denylist (Redis).
 // Useful for spotting runaway logout activity or a leaked token campaign.
 // @Router /admin/system/denylist-stats [get]
@@ -395,7 +585,7 @@ func (h *SystemHandler) DenylistStats(c *gin.Context) {
 		utils.SendSuccess(c, http.StatusOK, "ok", gin.H{"available": false})
 		return
 	}
-	keys, err := h.redis.Keys(c.Request.Context(), "denylist:*").Result()
+	keys, err := h.redis.Keys(c.Request.Context(), "blacklist:token:*").Result()
 	if err != nil {
 		utils.SendError(c, http.StatusInternalServerError, "Redis query failed", utils.ErrInternalServer)
 		return
@@ -405,3 +595,23 @@ func (h *SystemHandler) DenylistStats(c *gin.Context) {
 		"count":     len(keys),
 	})
 }
+
+// StorageReconcile reports the recent runs of the orphaned-storage
+// reconciliation job and the space it has reclaimed in total.
+// @Router /admin/system/storage-reconcile [get]
+func (h *SystemHandler) StorageReconcile(c *gin.Context) {
+	if h.reconcile == nil {
+		utils.SendSuccess(c, http.StatusOK, "ok", gin.H{"available": false})
+		return
+	}
+	report, err := h.reconcile.Report(c.Request.Context(), 30)
+	if err != nil {
+		h.logger.Error("storage reconcile report failed", zap.Error(err))
+		utils.SendError(c, http.StatusInternalServerError, "Query failed", utils.ErrInternalServer)
+		return
+	}
+	utils.SendSuccess(c, http.StatusOK, "ok", gin.H{
+		"available": true,
+		"report":    report,
+	})
+}
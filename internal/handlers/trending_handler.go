@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/middleware"
+	"github.com/hamsaya/backend/internal/services"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// TrendingHandler serves the province / district trending view.
+type TrendingHandler struct {
+	trendingService *services.RegionalTrendingService
+	logger          *zap.Logger
+}
+
+// NewTrendingHandler constructs the handler.
+func NewTrendingHandler(trendingService *services.RegionalTrendingService, logger *zap.Logger) *TrendingHandler {
+	return &TrendingHandler{
+		trendingService: trendingService,
+		logger:          logger,
+	}
+}
+
+// RegionalTrending handles GET /api/v1/discover/trending
+// @Summary Trending in a province or district
+// @Description Top posts, events and new businesses of the last week in a region, from an hourly rollup.
+// @Tags Discovery
+// @Produce json
+// @Param province query string true "Province, e.g. Kabul"
+// @Param district query string false "District within the province"
+// @Param limit query int false "Items per section (default and max 20)"
+// @Success 200 {object} utils.Response{data=models.RegionalTrendingResponse}
+// @Failure 400 {object} utils.Response
+// @Router /discover/trending [get]
+func (h *TrendingHandler) RegionalTrending(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	result, err := h.trendingService.GetTrending(c.Request.Context(), c.Query("province"), c.Query("district"), limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	utils.SendSuccess(c, http.StatusOK, "Trending retrieved", result)
+}
+
+func (h *TrendingHandler) handleError(c *gin.Context, err error) {
+	middleware.RespondWithError(c, err)
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRequireAuth_ActingBusiness(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const (
+		userID    = "user-123"
+		email     = "owner@example.com"
+		sessionID = "session-123"
+		ownBiz    = "6f1c2f0e-8d5b-4c1e-9a55-0f8a2b7e1c01"
+		otherBiz  = "6f1c2f0e-8d5b-4c1e-9a55-0f8a2b7e1c02"
+		missing   = "6f1c2f0e-8d5b-4c1e-9a55-0f8a2b7e1c03"
+	)
+	token := generateTestToken(userID, email, models.AAL1, sessionID)
+
+	tests := []struct {
+		name       string
+		header     string
+		body       string
+		wantStatus int
+		wantBiz    string
+	}{
+		{name: "no header acts as the user", wantStatus: http.StatusOK},
+		{name: "owned business", header: ownBiz, wantStatus: http.StatusOK, wantBiz: ownBiz},
+		{name: "body business_id matching header", header: ownBiz, body: ownBiz, wantStatus: http.StatusOK, wantBiz: ownBiz},
+		{name: "body business_id differing from header", header: ownBiz, body: otherBiz, wantStatus: http.StatusBadRequest},
+		{name: "someone else's business", header: otherBiz, wantStatus: http.StatusForbidden},
+		{name: "unknown business", header: missing, wantStatus: http.StatusNotFound},
+		{name: "malformed header", header: "not-a-uuid", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := new(mocks.MockUserRepository)
+			userRepo.On("GetSessionByID", mock.Anything, sessionID).Return(buildValidSession(sessionID, userID, token), nil)
+			userRepo.On("GetByID", mock.Anything, userID).Return(testutil.CreateTestUser(userID, email), nil)
+			businessRepo := new(mocks.MockBusinessRepository)
+			businessRepo.On("GetByID", mock.Anything, ownBiz).Return(&models.BusinessProfile{ID: ownBiz, UserID: userID}, nil)
+			businessRepo.On("GetByID", mock.Anything, otherBiz).Return(&models.BusinessProfile{ID: otherBiz, UserID: "user-456"}, nil)
+			businessRepo.On("GetByID", mock.Anything, missing).Return(nil, errors.New("not found"))
+
+			m := newTestAuthMiddleware(userRepo).WithActingBusiness(businessRepo)
+			router := gin.New()
+			var got *string
+			router.POST("/test", m.RequireAuth(), func(c *gin.Context) {
+				requested := &tt.body
+				if tt.body == "" {
+					requested = nil
+				}
+				if !ActingAs(c, &requested) {
+					return
+				}
+				got = requested
+				c.JSON(http.StatusOK, gin.H{"ok": true})
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader("{}"))
+			req.Header.Set("Authorization", "Bearer "+token)
+			if tt.header != "" {
+				req.Header.Set(ActingBusinessHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if tt.wantBiz == "" {
+				assert.Nil(t, got)
+			} else if assert.NotNil(t, got) {
+				assert.Equal(t, tt.wantBiz, *got)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// WithAdminMFA makes RequireAdmin, RequireAdminOnly and RequireSuperAdmin
+// refuse tokens below AAL2, so admin routes need an MFA-verified session.
+func (m *AuthMiddleware) WithAdminMFA(required bool) *AuthMiddleware {
+	m.adminRequireMFA = required
+	return m
+}
+
+// requireAdminAAL aborts with 403 when admin MFA is required and the token
+// hasn't passed it. It returns false after aborting.
+func (m *AuthMiddleware) requireAdminAAL(c *gin.Context, claims *models.JWTClaims) bool {
+	if !m.adminRequireMFA || claims.AAL >= models.AAL2 {
+		return true
+	}
+	m.logger.Warn("Admin access without MFA",
+		zap.String("user_id", claims.UserID),
+		zap.Int("aal", claims.AAL),
+	)
+	utils.SendError(c, http.StatusForbidden,
+		"Admin access requires multi-factor authentication",
+		utils.ErrMFARequired)
+	c.Abort()
+	return false
+}
+
+// AdminIPAllowlist only lets requests from the given addresses or CIDR
+// ranges through, by gin's ClientIP (so TRUSTED_PROXIES decides whether
+// X-Forwarded-For counts). An empty list allows everyone. Entries that
+// don't parse are skipped; config validation rejects them at startup.
+func AdminIPAllowlist(entries []string, logger *zap.Logger) gin.HandlerFunc {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		if p, err := netip.ParsePrefix(e); err == nil {
+			prefixes = append(prefixes, p.Masked())
+		} else if a, err := netip.ParseAddr(e); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(a.Unmap(), a.Unmap().BitLen()))
+		}
+	}
+	if len(prefixes) == 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		if addr, err := netip.ParseAddr(c.ClientIP()); err == nil {
+			addr = addr.Unmap()
+			for _, p := range prefixes {
+				if p.Contains(addr) {
+					c.Next()
+					return
+				}
+			}
+		}
+		logger.Warn("Admin request from address not in allowlist",
+			zap.String("ip", c.ClientIP()),
+			zap.String("path", c.Request.URL.Path),
+		)
+		utils.SendError(c, http.StatusForbidden, "Access denied from this network", utils.ErrForbidden)
+		c.Abort()
+	}
+}
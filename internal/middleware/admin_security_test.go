@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+func TestRequireAdmin_AdminMFA(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const (
+		userID    = "user-admin"
+		email     = "admin@example.com"
+		sessionID = "session-admin-mfa"
+	)
+
+	tests := []struct {
+		name       string
+		required   bool
+		aal        int
+		wantStatus int
+	}{
+		{"not required, AAL1", false, models.AAL1, http.StatusOK},
+		{"required, AAL1", true, models.AAL1, http.StatusForbidden},
+		{"required, AAL2", true, models.AAL2, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := generateTestToken(userID, email, tt.aal, sessionID)
+			userRepo := new(mocks.MockUserRepository)
+			userRepo.On("GetSessionByID", mock.Anything, sessionID).Return(buildValidSession(sessionID, userID, token), nil)
+			u := testutil.CreateTestUser(userID, email)
+			u.Role = models.RoleAdmin
+			userRepo.On("GetByID", mock.Anything, userID).Return(u, nil)
+
+			m := newTestAuthMiddleware(userRepo).WithAdminMFA(tt.required)
+			router := gin.New()
+			router.GET("/admin", m.RequireAdmin(), func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"ok": true})
+			})
+			router.GET("/admin-only", m.RequireAdminOnly(), func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"ok": true})
+			})
+
+			assert.Equal(t, tt.wantStatus, performRequest(router, http.MethodGet, "/admin", token).Code)
+			assert.Equal(t, tt.wantStatus, performRequest(router, http.MethodGet, "/admin-only", token).Code)
+		})
+	}
+}
+
+func TestAdminIPAllowlist(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		allowlist  []string
+		remoteAddr string
+		wantStatus int
+	}{
+		{"empty list allows everyone", nil, "198.51.100.9:1234", http.StatusOK},
+		{"listed address", []string{"203.0.113.7"}, "203.0.113.7:1234", http.StatusOK},
+		{"inside a range", []string{"203.0.113.7", "10.20.0.0/16"}, "10.20.5.1:1234", http.StatusOK},
+		{"outside the list", []string{"203.0.113.7", "10.20.0.0/16"}, "10.21.0.1:1234", http.StatusForbidden},
+		{"IPv6 range", []string{"2001:db8::/32"}, "[2001:db8::1]:1234", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/admin", AdminIPAllowlist(tt.allowlist, zap.NewNop()), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			req.RemoteAddr = tt.remoteAddr
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
@@ -19,7 +19,10 @@ type AuthMiddleware struct {
 	jwtService   *services.JWTService
 	userRepo     repositories.UserRepository
 	tokenStorage *services.TokenStorageService
+	businessRepo repositories.BusinessRepository
 	logger       *zap.Logger
+	// adminRequireMFA: see WithAdminMFA.
+	adminRequireMFA bool
 }
 
 // NewAuthMiddleware creates a new auth middleware
@@ -74,6 +77,9 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 		c.Set("aal", claims.AAL)
 		c.Set("jti", claims.JTI)
 		c.Set("token_exp", claims.ExpiresAt)
+		if !m.resolveActingBusiness(c, claims.UserID) {
+			return
+		}
 
 		c.Next()
 	}
@@ -131,6 +137,9 @@ func (m *AuthMiddleware) RequireAAL2() gin.HandlerFunc {
 		c.Set("aal", claims.AAL)
 		c.Set("jti", claims.JTI)
 		c.Set("token_exp", claims.ExpiresAt)
+		if !m.resolveActingBusiness(c, claims.UserID) {
+			return
+		}
 
 		c.Next()
 	}
@@ -176,6 +185,9 @@ func (m *AuthMiddleware) RequireVerifiedEmail() gin.HandlerFunc {
 		c.Set("session_id", claims.SessionID)
 		c.Set("aal", claims.AAL)
 		c.Set("user", user)
+		if !m.resolveActingBusiness(c, claims.UserID) {
+			return
+		}
 
 		c.Next()
 	}
@@ -199,6 +211,9 @@ func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 		c.Set("aal", claims.AAL)
 		c.Set("jti", claims.JTI)
 		c.Set("token_exp", claims.ExpiresAt)
+		if !m.resolveActingBusiness(c, claims.UserID) {
+			return
+		}
 
 		c.Next()
 	}
@@ -232,6 +247,9 @@ func (m *AuthMiddleware) RequireAdmin() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		if !m.requireAdminAAL(c, claims) {
+			return
+		}
 
 		c.Set("user_id", claims.UserID)
 		c.Set("email", claims.Email)
@@ -274,6 +292,9 @@ func (m *AuthMiddleware) RequireSuperAdmin() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		if !m.requireAdminAAL(c, claims) {
+			return
+		}
 
 		c.Set("user_id", claims.UserID)
 		c.Set("email", claims.Email)
@@ -316,6 +337,9 @@ func (m *AuthMiddleware) RequireAdminOnly() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		if !m.requireAdminAAL(c, claims) {
+			return
+		}
 
 		c.Set("user_id", claims.UserID)
 		c.Set("email", claims.Email)
@@ -382,30 +406,55 @@ func (m *AuthMiddleware) extractAndValidateToken(c *gin.Context) (*models.JWTCla
 		return nil, utils.NewUnauthorizedError("Missing token", nil)
 	}
 
+	return m.validateToken(c.Request.Context(), token)
+}
+
+// validateToken checks the signature, the denylist and the session of an
+// access token.
+func (m *AuthMiddleware) validateToken(ctx context.Context, token string) (*models.JWTClaims, error) {
 	// Validate token
 	claims, err := m.jwtService.ValidateAccessToken(token)
 	if err != nil {
 		return nil, err
 	}
 
-	// Access-token denylist: tokens revoked via /auth/logout are stored in Redis
-	// keyed by JTI for the remainder of their natural TTL. Skipped when the
-	// token has no JTI (legacy tokens issued before this feature shipped).
-	if claims.JTI != "" && m.tokenStorage != nil {
-		denied, derr := m.tokenStorage.IsTokenBlacklisted(c.Request.Context(), claims.JTI)
-		if derr == nil && denied {
+	// Access-token revocation: tokens revoked via /auth/logout are stored in
+	// Redis keyed by JTI for the remainder of their natural TTL (skipped for
+	// legacy tokens without a JTI), and /auth/logout-all stores one
+	// per-user cutoff that rejects every token issued up to it.
+	if m.tokenStorage != nil {
+		revoked, rerr := m.tokenStorage.IsAccessTokenRevoked(ctx, claims.JTI, claims.UserID, claims.IssuedAtMillis())
+		if rerr == nil && revoked {
 			return nil, utils.NewUnauthorizedError("Token has been revoked", nil)
 		}
 	}
 
 	// Verify session is still active
-	if err := m.verifySession(c.Request.Context(), claims.SessionID, token); err != nil {
+	if err := m.verifySession(ctx, claims.SessionID, token); err != nil {
 		return nil, err
 	}
 
 	return claims, nil
 }
 
+// AuthenticateSocketToken validates a token sent over an open WebSocket
+// (the auth frame) with the same checks as RequireAuth, and returns the
+// user and the token's expiry. Wired into the hub at boot.
+func (m *AuthMiddleware) AuthenticateSocketToken(ctx context.Context, token string) (string, time.Time, error) {
+	claims, err := m.validateToken(ctx, token)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	user, err := m.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return "", time.Time{}, utils.NewUnauthorizedError("Invalid user", err)
+	}
+	if user.IsLocked() {
+		return "", time.Time{}, utils.NewForbiddenError("Your account has been suspended", nil)
+	}
+	return claims.UserID, time.Unix(claims.ExpiresAt, 0), nil
+}
+
 // verifySession checks if the session is still active and not revoked.
 // It first checks a Redis cache to avoid hitting the database on every request.
 // Cache misses fall through to the database and populate the cache for subsequent requests.
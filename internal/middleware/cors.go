@@ -25,7 +25,7 @@ func CORS(cfg config.CORSConfig) gin.HandlerFunc {
 				wildcard = true
 				continue
 			}
-			if allowedOrigin == origin {
+			if origin != "" && originMatches(allowedOrigin, origin) {
 				allowed = true
 				break
 			}
@@ -54,3 +54,31 @@ func CORS(cfg config.CORSConfig) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// originMatches reports whether origin is allowed by pattern: either the
+// exact origin or a wildcard-subdomain pattern like https://*.hamsaya.app,
+// which matches any subdomain (at any depth) with the same scheme and port
+// but not the bare domain.
+func originMatches(pattern, origin string) bool {
+	if pattern == origin {
+		return true
+	}
+	scheme, suffix, ok := strings.Cut(pattern, "://*.")
+	if !ok {
+		return false
+	}
+	host, ok := strings.CutPrefix(origin, scheme+"://")
+	if !ok {
+		return false
+	}
+	sub, ok := strings.CutSuffix(host, "."+suffix)
+	if !ok || sub == "" {
+		return false
+	}
+	for _, r := range sub {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
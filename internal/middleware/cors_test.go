@@ -121,3 +121,30 @@ func TestCORS_NoOriginHeader(t *testing.T) {
 	// no Origin header — no ACAO header expected
 	assert.Equal(t, http.StatusOK, w.Code)
 }
+
+func TestCORS_WildcardSubdomain(t *testing.T) {
+	cfg := defaultCORSConfig()
+	cfg.AllowedOrigins = []string{"https://*.hamsaya.af"}
+	r := newCORSRouter(cfg)
+
+	for origin, want := range map[string]bool{
+		"https://admin.hamsaya.af":          true,
+		"https://staging.admin.hamsaya.af":  true,
+		"https://hamsaya.af":                false, // bare domain needs its own entry
+		"http://admin.hamsaya.af":           false, // scheme must match
+		"https://admin.hamsaya.af:8443":     false, // so must the port
+		"https://evilhamsaya.af":            false,
+		"https://admin.hamsaya.af.evil.com": false,
+	} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Origin", origin)
+		r.ServeHTTP(w, req)
+
+		if want {
+			assert.Equal(t, origin, w.Header().Get("Access-Control-Allow-Origin"), origin)
+		} else {
+			assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"), origin)
+		}
+	}
+}
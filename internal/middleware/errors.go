@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrorHandler renders the last error a handler attached with c.Error when
+// the handler didn't write a response itself, so a handler can simply
+// `_ = c.Error(err); return`.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		RespondWithError(c, c.Errors.Last().Err)
+	}
+}
+
+// RespondWithError is the single place service and repository errors become
+// HTTP responses:
+//   - *utils.AppError keeps its own status and message;
+//   - repository sentinels map by kind: ErrNotFound (and a bare
+//     pgx.ErrNoRows) to 404, ErrConflict to 409, ErrForbidden to 403, with
+//     the sentinel's message;
+//   - a request that ran out of time is a 504;
+//   - anything else is a 500 with a generic message — the cause is logged,
+//     never shown.
+func RespondWithError(c *gin.Context, err error) {
+	var appErr *utils.AppError
+	if errors.As(err, &appErr) {
+		utils.SendError(c, appErr.Code, appErr.Message, appErr.Err)
+		return
+	}
+
+	switch {
+	case errors.Is(err, repositories.ErrNotFound), errors.Is(err, pgx.ErrNoRows):
+		utils.SendError(c, http.StatusNotFound, sentinelMessage(err, "Not found"), err)
+	case errors.Is(err, repositories.ErrConflict):
+		utils.SendError(c, http.StatusConflict, sentinelMessage(err, "Conflict"), err)
+	case errors.Is(err, repositories.ErrForbidden):
+		utils.SendError(c, http.StatusForbidden, sentinelMessage(err, "Forbidden"), err)
+	case errors.Is(err, context.DeadlineExceeded):
+		utils.SendError(c, http.StatusGatewayTimeout, "The request took too long. Please try again.", err)
+	default:
+		utils.SendError(c, http.StatusInternalServerError, "An error occurred", err)
+	}
+}
+
+// sentinelMessage returns the repository sentinel's message, capitalised
+// for clients, or fallback when err carries none. Wrapping context added
+// with fmt.Errorf is left out.
+func sentinelMessage(err error, fallback string) string {
+	var sentinel *repositories.SentinelError
+	if !errors.As(err, &sentinel) || strings.TrimSpace(sentinel.Message) == "" {
+		return fallback
+	}
+	r, size := utf8.DecodeRuneInString(sentinel.Message)
+	return string(unicode.ToUpper(r)) + sentinel.Message[size:]
+}
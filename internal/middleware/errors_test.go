@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveError(t *testing.T, err error) (int, string) {
+	t.Helper()
+	r := gin.New()
+	r.Use(ErrorHandler())
+	r.GET("/x", func(c *gin.Context) { _ = c.Error(err) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/x", nil))
+	var body struct {
+		Message string `json:"message"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return w.Code, body.Message
+}
+
+func TestErrorHandler_MapsErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name    string
+		err     error
+		code    int
+		message string
+	}{
+		{"app error", utils.NewBadRequestError("Bad input", nil), http.StatusBadRequest, "Bad input"},
+		{"wrapped not found", fmt.Errorf("get booking: %w", repositories.ErrBookingNotFound), http.StatusNotFound, "Booking not found"},
+		{"no rows", fmt.Errorf("load thing: %w", pgx.ErrNoRows), http.StatusNotFound, "Not found"},
+		{"conflict", repositories.ErrEmailTaken, http.StatusConflict, "Email already in use"},
+		{"forbidden", repositories.ErrForbidden, http.StatusForbidden, "Forbidden"},
+		{"timeout", fmt.Errorf("query: %w", context.DeadlineExceeded), http.StatusGatewayTimeout, "The request took too long. Please try again."},
+		{"raw error", errors.New("pq: relation does not exist"), http.StatusInternalServerError, "An error occurred"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, message := serveError(t, tt.err)
+			assert.Equal(t, tt.code, code)
+			assert.Equal(t, tt.message, message)
+		})
+	}
+}
+
+func TestErrorHandler_LeavesWrittenResponses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ErrorHandler())
+	r.GET("/x", func(c *gin.Context) {
+		_ = c.Error(errors.New("logged only"))
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/x", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestSentinelErrorsKeepIdentity(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", repositories.ErrRecoveryNotFound)
+	assert.ErrorIs(t, err, repositories.ErrRecoveryNotFound)
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+	assert.NotErrorIs(t, err, repositories.ErrConflict)
+	assert.Equal(t, "account recovery not found", repositories.ErrRecoveryNotFound.Error())
+}
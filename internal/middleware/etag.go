@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ETag buffers successful GET responses, tags them with a weak validator
+// derived from the body and answers a matching If-None-Match with an empty
+// 304. The handler still runs, so this saves bandwidth rather than work —
+// the point for reference data and profile pages fetched over slow mobile
+// links. The validator is weak because the gzip middleware re-encodes the
+// bytes on the wire.
+//
+// Use it on read endpoints whose responses fit comfortably in memory; it is
+// not meant for streams or downloads.
+func ETag() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		w := &etagWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		if w.status != http.StatusOK || w.body.Len() == 0 {
+			w.flush()
+			return
+		}
+
+		sum := sha256.Sum256(w.body.Bytes())
+		tag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+		c.Header("ETag", tag)
+		if etagMatches(c.GetHeader("If-None-Match"), tag) {
+			c.Writer.Header().Del("Content-Type")
+			c.Writer.Header().Del("Content-Length")
+			c.Writer.WriteHeader(http.StatusNotModified)
+			c.Writer.WriteHeaderNow()
+			return
+		}
+		w.flush()
+	}
+}
+
+// etagMatches reports whether an If-None-Match header lists tag. Weak
+// comparison is used, as RFC 9110 requires for If-None-Match.
+func etagMatches(header, tag string) bool {
+	if header == "" {
+		return false
+	}
+	want := strings.TrimPrefix(tag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// etagWriter holds the status and body until the ETag is known.
+type etagWriter struct {
+	gin.ResponseWriter
+	body   bytes.Buffer
+	status int
+}
+
+func (w *etagWriter) WriteHeader(code int) { w.status = code }
+
+func (w *etagWriter) WriteHeaderNow() {}
+
+func (w *etagWriter) Write(b []byte) (int, error) { return w.body.Write(b) }
+
+func (w *etagWriter) WriteString(s string) (int, error) { return w.body.WriteString(s) }
+
+func (w *etagWriter) Status() int { return w.status }
+
+func (w *etagWriter) Size() int {
+	if w.body.Len() == 0 {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *etagWriter) Written() bool { return w.body.Len() > 0 }
+
+// flush sends the buffered response through unchanged.
+func (w *etagWriter) flush() {
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.body.Len() > 0 {
+		_, _ = w.ResponseWriter.Write(w.body.Bytes())
+	}
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newETagRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/categories", ETag(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"categories": []string{"food", "tools"}})
+	})
+	router.GET("/missing", ETag(), func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"message": "not found"})
+	})
+	return router
+}
+
+func getWithIfNoneMatch(router *gin.Engine, path, ifNoneMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestETag_SetsValidatorAndReturnsNotModified(t *testing.T) {
+	router := newETagRouter()
+
+	first := getWithIfNoneMatch(router, "/categories", "")
+	require.Equal(t, http.StatusOK, first.Code)
+	tag := first.Header().Get("ETag")
+	require.NotEmpty(t, tag)
+	assert.Contains(t, first.Body.String(), "food")
+
+	again := getWithIfNoneMatch(router, "/categories", tag)
+	assert.Equal(t, http.StatusNotModified, again.Code)
+	assert.Empty(t, again.Body.String())
+	assert.Equal(t, tag, again.Header().Get("ETag"))
+
+	// A strong form of the same tag, or a list containing it, also matches.
+	assert.Equal(t, http.StatusNotModified, getWithIfNoneMatch(router, "/categories", `"other", `+tag[2:]).Code)
+}
+
+func TestETag_StaleTagGetsFullResponse(t *testing.T) {
+	router := newETagRouter()
+
+	w := getWithIfNoneMatch(router, "/categories", `W/"stale"`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "tools")
+}
+
+func TestETag_SkipsErrors(t *testing.T) {
+	router := newETagRouter()
+
+	w := getWithIfNoneMatch(router, "/missing", "*")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, w.Header().Get("ETag"))
+	assert.Contains(t, w.Body.String(), "not found")
+}
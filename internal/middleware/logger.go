@@ -1,10 +1,13 @@
 package middleware
 
 import (
+	"math/rand/v2"
+	"net/http"
 	"net/url"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/pkg/database"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
@@ -38,30 +41,91 @@ func redactQuery(raw string) string {
 	return values.Encode()
 }
 
-// Logger returns a gin middleware that logs HTTP requests with trace correlation
+// AccessLogConfig controls access-log volume. Errors (status >= 400) and
+// slow requests are always logged; SampleRate applies to the rest so high
+// traffic doesn't drown the aggregator in 200s.
+type AccessLogConfig struct {
+	// SampleRate is the fraction (0..1) of successful fast requests logged.
+	SampleRate float64
+	// SlowThreshold marks requests that are always logged. Zero means 1s.
+	SlowThreshold time.Duration
+	// SkipPaths are never logged when successful (probe endpoints).
+	SkipPaths []string
+}
+
+// Logger returns a gin middleware that logs every HTTP request with trace
+// correlation.
 func Logger(logger *zap.SugaredLogger) gin.HandlerFunc {
+	return AccessLogger(logger, AccessLogConfig{SampleRate: 1})
+}
+
+// AccessLogger returns a gin middleware that emits one structured entry per
+// request: route template, authenticated user/session, response size and
+// the number of DB queries the request issued. Field names are flat
+// snake_case so the JSON encoder output indexes cleanly in log aggregation.
+func AccessLogger(logger *zap.SugaredLogger, cfg AccessLogConfig) gin.HandlerFunc {
+	slow := cfg.SlowThreshold
+	if slow <= 0 {
+		slow = time.Second
+	}
+	skip := make(map[string]struct{}, len(cfg.SkipPaths))
+	for _, p := range cfg.SkipPaths {
+		skip[p] = struct{}{}
+	}
+
 	return func(c *gin.Context) {
 		start := time.Now()
 		path := c.Request.URL.Path
 		query := redactQuery(c.Request.URL.RawQuery)
 
+		// Count queries issued on behalf of this request (see
+		// database.QueryCounterTracer).
+		c.Request = c.Request.WithContext(database.WithQueryCounter(c.Request.Context()))
+
 		// Process request
 		c.Next()
 
 		// Calculate latency
 		latency := time.Since(start)
+		status := c.Writer.Status()
+
+		if status < http.StatusBadRequest && latency < slow {
+			if _, ok := skip[path]; ok {
+				return
+			}
+			if cfg.SampleRate < 1 && rand.Float64() >= cfg.SampleRate {
+				return
+			}
+		}
+
+		responseBytes := c.Writer.Size()
+		if responseBytes < 0 {
+			responseBytes = 0
+		}
 
 		// Build log fields
 		fields := []interface{}{
 			"method", c.Request.Method,
 			"path", path,
+			"route", c.FullPath(),
 			"query", query,
-			"status", c.Writer.Status(),
+			"status", status,
 			"latency_ms", latency.Milliseconds(),
+			"response_bytes", responseBytes,
+			"db_queries", database.QueryCount(c.Request.Context()),
 			"client_ip", c.ClientIP(),
 			"user_agent", c.Request.UserAgent(),
 			"request_id", c.GetString("request_id"),
 		}
+		if userID := c.GetString("user_id"); userID != "" {
+			fields = append(fields, "user_id", userID)
+		}
+		if sessionID := c.GetString("session_id"); sessionID != "" {
+			fields = append(fields, "session_id", sessionID)
+		}
+		if cfg.SampleRate < 1 {
+			fields = append(fields, "sample_rate", cfg.SampleRate)
+		}
 
 		// Add trace correlation if available (from OpenTelemetry)
 		span := trace.SpanFromContext(c.Request.Context())
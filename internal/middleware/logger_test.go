@@ -7,7 +7,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func newLoggerRouter(t *testing.T) *gin.Engine {
@@ -51,3 +53,55 @@ func TestLogger_WithErrors(t *testing.T) {
 	r.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 }
+
+func newObservedLogger() (*zap.SugaredLogger, *observer.ObservedLogs) {
+	core, logs := observer.New(zap.InfoLevel)
+	return zap.New(core).Sugar(), logs
+}
+
+func TestAccessLogger_UserAndRouteContext(t *testing.T) {
+	logger, logs := newObservedLogger()
+	r := gin.New()
+	r.Use(AccessLogger(logger, AccessLogConfig{SampleRate: 1}))
+	r.GET("/posts/:id", func(c *gin.Context) {
+		c.Set("user_id", "user-1")
+		c.Set("session_id", "sess-1")
+		c.String(http.StatusOK, "hello")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/posts/42", nil))
+
+	require.Equal(t, 1, logs.Len())
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, "/posts/:id", fields["route"])
+	assert.Equal(t, "/posts/42", fields["path"])
+	assert.Equal(t, "user-1", fields["user_id"])
+	assert.Equal(t, "sess-1", fields["session_id"])
+	assert.EqualValues(t, 5, fields["response_bytes"])
+	assert.EqualValues(t, 0, fields["db_queries"])
+}
+
+func TestAccessLogger_SamplingKeepsErrors(t *testing.T) {
+	logger, logs := newObservedLogger()
+	r := gin.New()
+	r.Use(AccessLogger(logger, AccessLogConfig{SampleRate: 0}))
+	r.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/bad", func(c *gin.Context) { c.Status(http.StatusBadRequest) })
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+	assert.Equal(t, 0, logs.Len(), "successful request should be sampled out")
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/bad", nil))
+	assert.Equal(t, 1, logs.Len(), "errors are always logged")
+}
+
+func TestAccessLogger_SkipPaths(t *testing.T) {
+	logger, logs := newObservedLogger()
+	r := gin.New()
+	r.Use(AccessLogger(logger, AccessLogConfig{SampleRate: 1, SkipPaths: []string{"/health/live"}}))
+	r.GET("/health/live", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health/live", nil))
+	assert.Equal(t, 0, logs.Len())
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/hamsaya/backend/internal/utils"
+	"github.com/hamsaya/backend/pkg/runtimeconfig"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
@@ -121,6 +122,14 @@ var DefaultRateLimits = map[string]RateLimitConfig{
 		Window:      time.Minute,
 		KeyPrefix:   "ratelimit:chat-send:",
 	},
+	// webhook: provider callbacks (email delivery events). Signature-checked
+	// downstream; the cap only keeps an unsigned flood from reaching the
+	// HMAC + DB path. 600/min/IP absorbs a provider's retry burst.
+	"webhook": {
+		MaxRequests: 600,
+		Window:      time.Minute,
+		KeyPrefix:   "ratelimit:webhook:",
+	},
 	// storage-stream: public proxy endpoint serving MinIO objects.
 	// 300/min/IP comfortably covers a feed scroll with many images +
 	// videos pulling Range chunks (one IP can request the same object
@@ -138,12 +147,21 @@ var DefaultRateLimits = map[string]RateLimitConfig{
 		Window:      time.Minute,
 		KeyPrefix:   "ratelimit:search:",
 	},
+	// search-suggest: autocomplete fires per (debounced) keystroke; the
+	// queries are indexed prefix lookups, so it gets a separate, larger
+	// budget that doesn't eat into full searches.
+	"search-suggest": {
+		MaxRequests: 180,
+		Window:      time.Minute,
+		KeyPrefix:   "ratelimit:search-suggest:",
+	},
 }
 
 // RateLimiter handles rate limiting using Redis
 type RateLimiter struct {
-	redis  *redis.Client
-	logger *zap.Logger
+	redis    *redis.Client
+	logger   *zap.Logger
+	settings *runtimeconfig.Store
 }
 
 // NewRateLimiter creates a new rate limiter
@@ -154,6 +172,44 @@ func NewRateLimiter(redisClient *redis.Client, logger *zap.Logger) *RateLimiter
 	}
 }
 
+// WithRuntimeSettings makes every named limit in DefaultRateLimits tunable
+// at runtime via "ratelimit.<type>.max_requests" and
+// "ratelimit.<type>.window". Limits are resolved per request so an admin
+// change takes effect on the next refresh without a redeploy.
+func (rl *RateLimiter) WithRuntimeSettings(store *runtimeconfig.Store) *RateLimiter {
+	for name, cfg := range DefaultRateLimits {
+		store.Register(runtimeconfig.Setting{
+			Key:         "ratelimit." + name + ".max_requests",
+			Kind:        runtimeconfig.KindInt,
+			Default:     fmt.Sprintf("%d", cfg.MaxRequests),
+			Description: "Requests allowed per window for the " + name + " limit",
+		})
+		store.Register(runtimeconfig.Setting{
+			Key:         "ratelimit." + name + ".window",
+			Kind:        runtimeconfig.KindDuration,
+			Default:     cfg.Window.String(),
+			Description: "Sliding window for the " + name + " limit",
+		})
+	}
+	rl.settings = store
+	return rl
+}
+
+// resolve returns the effective config for a named limit, applying any
+// runtime override. The key prefix is never overridable.
+func (rl *RateLimiter) resolve(limitType string) RateLimitConfig {
+	config, exists := DefaultRateLimits[limitType]
+	if !exists {
+		limitType = "default"
+		config = DefaultRateLimits["default"]
+	}
+	if rl.settings != nil {
+		config.MaxRequests = rl.settings.Int("ratelimit."+limitType+".max_requests", config.MaxRequests)
+		config.Window = rl.settings.Duration("ratelimit."+limitType+".window", config.Window)
+	}
+	return config
+}
+
 // Limit creates a rate limiting middleware with the specified config
 func (rl *RateLimiter) Limit(config RateLimitConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -208,11 +264,22 @@ func (rl *RateLimiter) Limit(config RateLimitConfig) gin.HandlerFunc {
 
 // LimitByType creates a rate limiting middleware by type name
 func (rl *RateLimiter) LimitByType(limitType string) gin.HandlerFunc {
-	config, exists := DefaultRateLimits[limitType]
-	if !exists {
-		config = DefaultRateLimits["default"]
+	if rl.settings == nil {
+		return rl.Limit(rl.resolve(limitType))
+	}
+	return func(c *gin.Context) {
+		rl.Limit(rl.resolve(limitType))(c)
+	}
+}
+
+// limitByUserType is LimitByType keyed by user ID.
+func (rl *RateLimiter) limitByUserType(limitType string) gin.HandlerFunc {
+	if rl.settings == nil {
+		return rl.LimitByUser(rl.resolve(limitType))
+	}
+	return func(c *gin.Context) {
+		rl.LimitByUser(rl.resolve(limitType))(c)
 	}
-	return rl.Limit(config)
 }
 
 // LimitAuth is a convenience method for auth endpoints
@@ -228,28 +295,24 @@ func (rl *RateLimiter) LimitStrict() gin.HandlerFunc {
 // LimitReports is a convenience method for report endpoints
 // Limits users to 10 reports per 24 hours to prevent spam
 func (rl *RateLimiter) LimitReports() gin.HandlerFunc {
-	config := DefaultRateLimits["reports"]
-	return rl.LimitByUser(config)
+	return rl.limitByUserType("reports")
 }
 
 // LimitPostsCreate caps how many posts a single authenticated user can create
 // per hour. Falls back to per-IP limiting for unauthenticated callers.
 func (rl *RateLimiter) LimitPostsCreate() gin.HandlerFunc {
-	config := DefaultRateLimits["posts-create"]
-	return rl.LimitByUser(config)
+	return rl.limitByUserType("posts-create")
 }
 
 // LimitDataExport gates GET /users/me/export at 1 request / 24h per user.
 func (rl *RateLimiter) LimitDataExport() gin.HandlerFunc {
-	config := DefaultRateLimits["data-export"]
-	return rl.LimitByUser(config)
+	return rl.limitByUserType("data-export")
 }
 
 // LimitChatSend caps chat messages at 30/min/user. Spam guard — leaves
 // plenty of headroom for normal conversation while blocking floods.
 func (rl *RateLimiter) LimitChatSend() gin.HandlerFunc {
-	config := DefaultRateLimits["chat-send"]
-	return rl.LimitByUser(config)
+	return rl.limitByUserType("chat-send")
 }
 
 // checkRateLimit checks if a request is within rate limits using sliding window
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/alicebob/miniredis/v2"
 	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/pkg/runtimeconfig"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
@@ -104,3 +106,29 @@ func TestRateLimit_LimitByUser_FallsBackToIPWhenNoUserID(t *testing.T) {
 	r.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusOK, w.Code)
 }
+
+func TestRateLimit_RuntimeOverride(t *testing.T) {
+	rl, mr := newTestRateLimiter(t)
+	store := runtimeconfig.New(redis.NewClient(&redis.Options{Addr: mr.Addr()}), zap.NewNop())
+	rl.WithRuntimeSettings(store)
+
+	r := gin.New()
+	r.Use(rl.LimitByType("search"))
+	r.GET("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	doReq := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.RemoteAddr = "10.0.0.9:1234"
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, "60", doReq().Header().Get("X-RateLimit-Limit"))
+
+	// Override applies to the already-registered middleware.
+	assert.NoError(t, store.Set(context.Background(), "ratelimit.search.max_requests", "1"))
+	w := doReq()
+	assert.Equal(t, "1", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+}
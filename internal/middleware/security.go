@@ -1,12 +1,41 @@
 package middleware
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/config"
+)
+
+const (
+	// defaultCSP suits JSON API responses: nothing may load and nothing may
+	// frame them.
+	defaultCSP = "default-src 'self'; frame-ancestors 'none'"
+	// defaultDashboardCSP lets the admin dashboard load its own bundle,
+	// inline styles from the UI kit, and images from anywhere (CDN, avatars).
+	defaultDashboardCSP = "default-src 'self'; script-src 'self'; style-src 'self' 'unsafe-inline'; " +
+		"img-src 'self' data: https:; connect-src 'self'; frame-ancestors 'none'; base-uri 'self'; form-action 'self'"
+	defaultReferrerPolicy = "strict-origin-when-cross-origin"
+	defaultHSTSMaxAge     = 63072000 // 2 years
 )
 
 // SecurityHeaders returns middleware that sets essential security headers
 // to protect against common web vulnerabilities (XSS, clickjacking, MIME sniffing, etc.)
-func SecurityHeaders() gin.HandlerFunc {
+// Zero-valued fields in cfg fall back to the defaults above.
+func SecurityHeaders(cfg config.SecurityConfig) gin.HandlerFunc {
+	csp := orDefault(cfg.ContentSecurityPolicy, defaultCSP)
+	dashboardCSP := orDefault(cfg.DashboardCSP, defaultDashboardCSP)
+	referrer := orDefault(cfg.ReferrerPolicy, defaultReferrerPolicy)
+	maxAge := cfg.HSTSMaxAge
+	if maxAge == 0 {
+		maxAge = defaultHSTSMaxAge
+	}
+	hsts := "max-age=" + strconv.Itoa(maxAge) + "; includeSubDomains"
+	if cfg.HSTSPreload {
+		hsts += "; preload"
+	}
+
 	return func(c *gin.Context) {
 		// Prevent MIME type sniffing
 		c.Header("X-Content-Type-Options", "nosniff")
@@ -18,11 +47,15 @@ func SecurityHeaders() gin.HandlerFunc {
 		c.Header("X-XSS-Protection", "1; mode=block")
 
 		// Control referrer information
-		c.Header("Referrer-Policy", "strict-origin-when-cross-origin")
+		c.Header("Referrer-Policy", referrer)
 
-		// Content Security Policy - restrict resource loading
-		// Using 'self' as baseline; adjust based on your frontend needs
-		c.Header("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+		// Content Security Policy - restrict resource loading. The admin
+		// dashboard pages get their own policy since they run scripts.
+		if hasPathPrefix(c.Request.URL.Path, cfg.DashboardPaths) {
+			c.Header("Content-Security-Policy", dashboardCSP)
+		} else {
+			c.Header("Content-Security-Policy", csp)
+		}
 
 		// Prevent DNS prefetching to avoid privacy leaks
 		c.Header("X-DNS-Prefetch-Control", "off")
@@ -35,8 +68,7 @@ func SecurityHeaders() gin.HandlerFunc {
 		// Enable HSTS if behind TLS terminating proxy or direct HTTPS
 		// Check X-Forwarded-Proto for reverse proxy setups
 		if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
-			// max-age=2 years, include subdomains
-			c.Header("Strict-Transport-Security", "max-age=63072000; includeSubDomains; preload")
+			c.Header("Strict-Transport-Security", hsts)
 		}
 
 		// Block cross-domain policies (Adobe Flash/Acrobat)
@@ -48,3 +80,21 @@ func SecurityHeaders() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+func orDefault(v, def string) string {
+	if strings.TrimSpace(v) == "" {
+		return def
+	}
+	return v
+}
+
+// hasPathPrefix reports whether path is one of prefixes or below it.
+func hasPathPrefix(path string, prefixes []string) bool {
+	for _, p := range prefixes {
+		p = strings.TrimSuffix(p, "/")
+		if p != "" && (path == p || strings.HasPrefix(path, p+"/")) {
+			return true
+		}
+	}
+	return false
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/config"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -15,7 +16,7 @@ func init() {
 
 func newSecurityRouter() *gin.Engine {
 	r := gin.New()
-	r.Use(SecurityHeaders())
+	r.Use(SecurityHeaders(config.SecurityConfig{}))
 	r.GET("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
 	return r
 }
@@ -42,7 +43,7 @@ func TestSecurityHeaders_HTTP(t *testing.T) {
 
 func TestSecurityHeaders_HTTPS_ForwardedProto(t *testing.T) {
 	r := gin.New()
-	r.Use(SecurityHeaders())
+	r.Use(SecurityHeaders(config.SecurityConfig{}))
 	r.GET("/test", func(c *gin.Context) { c.Status(http.StatusOK) })
 
 	w := httptest.NewRecorder()
@@ -54,3 +55,35 @@ func TestSecurityHeaders_HTTPS_ForwardedProto(t *testing.T) {
 	assert.Contains(t, hsts, "max-age=63072000")
 	assert.Contains(t, hsts, "includeSubDomains")
 }
+
+func TestSecurityHeaders_Configured(t *testing.T) {
+	r := gin.New()
+	r.Use(SecurityHeaders(config.SecurityConfig{
+		ContentSecurityPolicy: "default-src 'none'",
+		DashboardPaths:        []string{"/dashboard/"},
+		ReferrerPolicy:        "no-referrer",
+		HSTSMaxAge:            3600,
+		HSTSPreload:           false,
+	}))
+	r.GET("/api/v1/posts", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/dashboard/reports", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/dashboardx", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	get := func(path string) http.Header {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("X-Forwarded-Proto", "https")
+		r.ServeHTTP(w, req)
+		return w.Header()
+	}
+
+	api := get("/api/v1/posts")
+	assert.Equal(t, "default-src 'none'", api.Get("Content-Security-Policy"))
+	assert.Equal(t, "no-referrer", api.Get("Referrer-Policy"))
+	assert.Equal(t, "max-age=3600; includeSubDomains", api.Get("Strict-Transport-Security"))
+
+	// Dashboard pages get the dashboard policy (default here), which lets
+	// the app load its scripts; lookalike paths don't.
+	assert.Equal(t, defaultDashboardCSP, get("/dashboard/reports").Get("Content-Security-Policy"))
+	assert.Equal(t, "default-src 'none'", get("/dashboardx").Get("Content-Security-Policy"))
+}
@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/hamsaya/backend/pkg/runtimeconfig"
+)
+
+// Runtime setting keys for the service mode switches. They live in the
+// shared runtime settings hash, so flipping one on any instance reaches the
+// rest of the fleet on their next refresh.
+const (
+	SettingMaintenanceMode = "mode.maintenance"
+	SettingReadOnlyMode    = "mode.read_only"
+	SettingModeRetryAfter  = "mode.retry_after"
+)
+
+const defaultModeRetryAfter = 5 * time.Minute
+
+// serviceModeExemptPrefixes stay reachable in every mode: health probes and
+// metrics for the orchestrator, and the admin dashboard (including its login)
+// so operators can turn the switch back off. Admin routes are still behind
+// their own auth.
+var serviceModeExemptPrefixes = []string{
+	"/health",
+	"/metrics",
+	"/api/v1/admin",
+	"/api/v1/auth/admin",
+}
+
+// readOnlyExemptPaths are the session writes read-only mode still lets
+// through: signing in (and its MFA step), refreshing an access token and
+// signing out. Without them every client is logged out for the duration,
+// although it could otherwise keep reading.
+var readOnlyExemptPaths = map[string]bool{
+	"/api/v1/auth/login":      true,
+	"/api/v1/auth/mfa/verify": true,
+	"/api/v1/auth/refresh":    true,
+	"/api/v1/auth/logout":     true,
+	"/api/v1/auth/logout-all": true,
+}
+
+// ServiceModeStatus is the effective state of the switches.
+type ServiceModeStatus struct {
+	Maintenance       bool `json:"maintenance"`
+	ReadOnly          bool `json:"read_only"`
+	RetryAfterSeconds int  `json:"retry_after_seconds"`
+}
+
+// ServiceMode puts the API into maintenance mode (every non-exempt request
+// gets a 503) or read-only mode (writes get a 503, reads keep working)
+// without a redeploy.
+type ServiceMode struct {
+	settings *runtimeconfig.Store
+}
+
+// NewServiceMode registers the mode settings on store. Both switches default
+// to off.
+func NewServiceMode(store *runtimeconfig.Store) *ServiceMode {
+	store.Register(runtimeconfig.Setting{
+		Key:         SettingMaintenanceMode,
+		Kind:        runtimeconfig.KindBool,
+		Default:     "false",
+		Description: "Reject all non-admin API traffic with 503 (maintenance mode)",
+	})
+	store.Register(runtimeconfig.Setting{
+		Key:         SettingReadOnlyMode,
+		Kind:        runtimeconfig.KindBool,
+		Default:     "false",
+		Description: "Reject non-admin writes with 503 while reads keep working (read-only mode)",
+	})
+	store.Register(runtimeconfig.Setting{
+		Key:         SettingModeRetryAfter,
+		Kind:        runtimeconfig.KindDuration,
+		Default:     defaultModeRetryAfter.String(),
+		Description: "Retry-After sent to clients while maintenance or read-only mode is on",
+	})
+	return &ServiceMode{settings: store}
+}
+
+// Status returns the switches as this instance currently sees them.
+func (m *ServiceMode) Status() ServiceModeStatus {
+	retryAfter := m.settings.Duration(SettingModeRetryAfter, defaultModeRetryAfter)
+	return ServiceModeStatus{
+		Maintenance:       m.settings.Bool(SettingMaintenanceMode, false),
+		ReadOnly:          m.settings.Bool(SettingReadOnlyMode, false),
+		RetryAfterSeconds: int(retryAfter / time.Second),
+	}
+}
+
+// Enforce returns the global middleware. Maintenance wins over read-only
+// when both are on.
+func (m *ServiceMode) Enforce() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		maintenance := m.settings.Bool(SettingMaintenanceMode, false)
+		readOnly := m.settings.Bool(SettingReadOnlyMode, false)
+		if (!maintenance && !readOnly) || serviceModeExempt(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		retryAfter := m.settings.Duration(SettingModeRetryAfter, defaultModeRetryAfter)
+		if maintenance {
+			utils.SendAppError(c, utils.NewMaintenanceError(retryAfter))
+			c.Abort()
+			return
+		}
+		if isWriteMethod(c.Request.Method) && !readOnlyExemptPaths[c.Request.URL.Path] {
+			utils.SendAppError(c, utils.NewReadOnlyError(retryAfter))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func serviceModeExempt(path string) bool {
+	for _, prefix := range serviceModeExemptPrefixes {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func isWriteMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return true
+}
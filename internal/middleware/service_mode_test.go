@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/hamsaya/backend/pkg/runtimeconfig"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestServiceMode(t *testing.T) (*ServiceMode, *runtimeconfig.Store, *gin.Engine) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	mr := miniredis.RunT(t)
+	store := runtimeconfig.New(redis.NewClient(&redis.Options{Addr: mr.Addr()}), zap.NewNop())
+	mode := NewServiceMode(store)
+
+	router := gin.New()
+	router.Use(mode.Enforce())
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/api/v1/posts", ok)
+	router.POST("/api/v1/posts", ok)
+	router.POST("/api/v1/admin/users/1/ban", ok)
+	router.GET("/health/ready", ok)
+	router.POST("/api/v1/auth/login", ok)
+	router.POST("/api/v1/auth/refresh", ok)
+	router.POST("/api/v1/auth/logout", ok)
+	router.POST("/api/v1/auth/register", ok)
+	return mode, store, router
+}
+
+func serve(router *gin.Engine, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w
+}
+
+func TestServiceMode_OffByDefault(t *testing.T) {
+	mode, _, router := newTestServiceMode(t)
+
+	assert.Equal(t, http.StatusOK, serve(router, http.MethodGet, "/api/v1/posts").Code)
+	assert.Equal(t, http.StatusOK, serve(router, http.MethodPost, "/api/v1/posts").Code)
+	assert.Equal(t, ServiceModeStatus{RetryAfterSeconds: 300}, mode.Status())
+}
+
+func TestServiceMode_Maintenance(t *testing.T) {
+	_, store, router := newTestServiceMode(t)
+	ctx := context.Background()
+	require.NoError(t, store.Set(ctx, SettingMaintenanceMode, "true"))
+	require.NoError(t, store.Set(ctx, SettingModeRetryAfter, "90s"))
+
+	w := serve(router, http.MethodGet, "/api/v1/posts")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "90", w.Header().Get("Retry-After"))
+	var body struct {
+		Code string `json:"code"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "maintenance", body.Code)
+
+	assert.Equal(t, http.StatusOK, serve(router, http.MethodPost, "/api/v1/admin/users/1/ban").Code)
+	assert.Equal(t, http.StatusOK, serve(router, http.MethodGet, "/health/ready").Code)
+}
+
+func TestServiceMode_ReadOnly(t *testing.T) {
+	_, store, router := newTestServiceMode(t)
+	require.NoError(t, store.Set(context.Background(), SettingReadOnlyMode, "true"))
+
+	assert.Equal(t, http.StatusOK, serve(router, http.MethodGet, "/api/v1/posts").Code)
+
+	w := serve(router, http.MethodPost, "/api/v1/posts")
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "300", w.Header().Get("Retry-After"))
+	var body struct {
+		Code string `json:"code"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "read_only", body.Code)
+
+	assert.Equal(t, http.StatusOK, serve(router, http.MethodPost, "/api/v1/admin/users/1/ban").Code)
+}
+
+func TestServiceMode_ReadOnlyKeepsSessionsWorking(t *testing.T) {
+	_, store, router := newTestServiceMode(t)
+	ctx := context.Background()
+	require.NoError(t, store.Set(ctx, SettingReadOnlyMode, "true"))
+
+	for _, path := range []string{"/api/v1/auth/login", "/api/v1/auth/refresh", "/api/v1/auth/logout"} {
+		assert.Equal(t, http.StatusOK, serve(router, http.MethodPost, path).Code, path)
+	}
+	assert.Equal(t, http.StatusServiceUnavailable, serve(router, http.MethodPost, "/api/v1/auth/register").Code)
+
+	// Maintenance still closes everything.
+	require.NoError(t, store.Set(ctx, SettingMaintenanceMode, "true"))
+	assert.Equal(t, http.StatusServiceUnavailable, serve(router, http.MethodPost, "/api/v1/auth/refresh").Code)
+}
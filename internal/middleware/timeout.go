@@ -30,8 +30,10 @@ const DefaultRequestTimeout = 25 * time.Second
 // middleware, or by replacing the request context before calling Next.
 func Timeout(d time.Duration) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Skip on WebSocket upgrade — connections are long-lived by design.
-		if strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+		// Skip on WebSocket upgrade and server-sent events — connections
+		// are long-lived by design.
+		if strings.EqualFold(c.GetHeader("Upgrade"), "websocket") ||
+			strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
 			c.Next()
 			return
 		}
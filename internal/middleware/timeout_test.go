@@ -68,6 +68,24 @@ func TestTimeout_SkipsWebSocketUpgrade(t *testing.T) {
 	assert.False(t, deadlineSet, "WebSocket upgrade must not inherit a request deadline")
 }
 
+func TestTimeout_SkipsEventStream(t *testing.T) {
+	r := gin.New()
+	r.Use(Timeout(10 * time.Millisecond))
+	var deadlineSet bool
+	r.GET("/stream", func(c *gin.Context) {
+		_, ok := c.Request.Context().Deadline()
+		deadlineSet = ok
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/stream", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	r.ServeHTTP(w, req)
+
+	assert.False(t, deadlineSet, "server-sent events must not inherit a request deadline")
+}
+
 func TestTimeout_DefaultConstant(t *testing.T) {
 	// 25s documented in timeout.go — pin the contract.
 	assert.Equal(t, 25*time.Second, DefaultRequestTimeout)
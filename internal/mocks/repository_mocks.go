@@ -73,6 +73,11 @@ func (m *MockUserRepository) UpdateLastLogin(ctx context.Context, userID string)
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) IsShadowbanned(ctx context.Context, userID string) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
 func (m *MockUserRepository) SoftDelete(ctx context.Context, userID string) error {
 	args := m.Called(ctx, userID)
 	return args.Error(0)
@@ -120,11 +125,55 @@ func (m *MockUserRepository) GetUserIDsByNeighborhood(ctx context.Context, provi
 	return args.Get(0).([]string), args.Error(1)
 }
 
+func (m *MockUserRepository) GetUserIDsWithinRadius(ctx context.Context, lat, lng, radiusKm float64, excludeUserID string, limit, offset int) ([]string, error) {
+	args := m.Called(ctx, lat, lng, radiusKm, excludeUserID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 func (m *MockUserRepository) UpdateProfile(ctx context.Context, profile *models.Profile) error {
 	args := m.Called(ctx, profile)
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) FillProfileAddress(ctx context.Context, profileID string, lat, lng float64, addr *models.ResolvedAddress) error {
+	args := m.Called(ctx, profileID, lat, lng, addr)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetContentLanguages(ctx context.Context, userID string) ([]string, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockUserRepository) SetContentLanguages(ctx context.Context, userID string, languages []string) error {
+	args := m.Called(ctx, userID, languages)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) SetProfileHandle(ctx context.Context, userID, handle string, changedBefore time.Time) error {
+	args := m.Called(ctx, userID, handle, changedBefore)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetUserIDByHandle(ctx context.Context, handle string) (string, error) {
+	args := m.Called(ctx, handle)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockUserRepository) GetUserIDsByHandles(ctx context.Context, handles []string) (map[string]string, error) {
+	args := m.Called(ctx, handles)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]string), args.Error(1)
+}
+
 func (m *MockUserRepository) CreateUserWithProfile(ctx context.Context, user *models.User, profile *models.Profile) error {
 	args := m.Called(ctx, user, profile)
 	return args.Error(0)
@@ -269,6 +318,11 @@ func (m *MockPostRepository) Delete(ctx context.Context, postID string) error {
 	return args.Error(0)
 }
 
+func (m *MockPostRepository) FillAddress(ctx context.Context, postID string, lat, lng float64, addr *models.ResolvedAddress) error {
+	args := m.Called(ctx, postID, lat, lng, addr)
+	return args.Error(0)
+}
+
 // Stub implementations for full PostRepository interface compliance
 func (m *MockPostRepository) CreateAttachment(ctx context.Context, attachment *models.Attachment) error {
 	args := m.Called(ctx, attachment)
@@ -301,6 +355,21 @@ func (m *MockPostRepository) DeleteAttachmentForPost(ctx context.Context, postID
 	return args.Error(0)
 }
 
+func (m *MockPostRepository) ReorderAttachments(ctx context.Context, postID string, attachmentIDs []string) error {
+	args := m.Called(ctx, postID, attachmentIDs)
+	return args.Error(0)
+}
+
+func (m *MockPostRepository) SetAttachmentCaption(ctx context.Context, postID, attachmentID string, caption *string) error {
+	args := m.Called(ctx, postID, attachmentID, caption)
+	return args.Error(0)
+}
+
+func (m *MockPostRepository) SetAttachmentAltText(ctx context.Context, postID, attachmentID, altText string) error {
+	args := m.Called(ctx, postID, attachmentID, altText)
+	return args.Error(0)
+}
+
 func (m *MockPostRepository) LikePost(ctx context.Context, userID, postID string) error {
 	args := m.Called(ctx, userID, postID)
 	return args.Error(0)
@@ -347,6 +416,19 @@ func (m *MockPostRepository) RecordPostView(ctx context.Context, userID, postID
 	return args.Error(0)
 }
 
+func (m *MockPostRepository) IncrementDailyViews(ctx context.Context, userID, postID string) error {
+	args := m.Called(ctx, userID, postID)
+	return args.Error(0)
+}
+
+func (m *MockPostRepository) GetViewCounts(ctx context.Context, postIDs []string) (map[string]int, error) {
+	args := m.Called(ctx, postIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
 func (m *MockPostRepository) BookmarkPost(ctx context.Context, userID, postID string) error {
 	args := m.Called(ctx, userID, postID)
 	return args.Error(0)
@@ -370,6 +452,19 @@ func (m *MockPostRepository) GetBookmarkerIDs(ctx context.Context, postID string
 	return args.Get(0).([]string), args.Error(1)
 }
 
+func (m *MockPostRepository) GetBookmarkCounts(ctx context.Context, postIDs []string) (map[string]int, error) {
+	args := m.Called(ctx, postIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
+func (m *MockPostRepository) ClaimBookmarkMilestone(ctx context.Context, postID string, thresholds []int) (int, error) {
+	args := m.Called(ctx, postID, thresholds)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockPostRepository) SharePost(ctx context.Context, share *models.PostShare) error {
 	args := m.Called(ctx, share)
 	return args.Error(0)
@@ -416,6 +511,30 @@ func (m *MockPostRepository) GetUserPosts(ctx context.Context, userID string, li
 	return args.Get(0).([]*models.Post), args.Error(1)
 }
 
+func (m *MockPostRepository) RecordExternalShare(ctx context.Context, link *models.PostShareLink) (*models.PostShareLink, int, error) {
+	args := m.Called(ctx, link)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).(*models.PostShareLink), args.Int(1), args.Error(2)
+}
+
+func (m *MockPostRepository) ResolveShareLink(ctx context.Context, code string) (*models.PostShareLink, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PostShareLink), args.Error(1)
+}
+
+func (m *MockPostRepository) GetUserBookmarksInCollection(ctx context.Context, userID, collectionID string, limit, offset int) ([]*models.Post, error) {
+	args := m.Called(ctx, userID, collectionID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Post), args.Error(1)
+}
+
 func (m *MockPostRepository) GetUserBookmarks(ctx context.Context, userID string, limit, offset int) ([]*models.Post, error) {
 	args := m.Called(ctx, userID, limit, offset)
 	if args.Get(0) == nil {
@@ -445,6 +564,14 @@ func (m *MockPostRepository) CountFeed(ctx context.Context, filter *models.FeedF
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockPostRepository) RemovedPostIDs(ctx context.Context, filter *models.FeedFilter, since time.Time, limit int) ([]string, error) {
+	args := m.Called(ctx, filter, since, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 func (m *MockPostRepository) CountPostsByUser(ctx context.Context, userID string) (int, error) {
 	args := m.Called(ctx, userID)
 	return args.Int(0), args.Error(1)
@@ -471,11 +598,39 @@ func (m *MockPostRepository) MarkSellPostsExpired(ctx context.Context, postIDs [
 	return args.Error(0)
 }
 
-func (m *MockPostRepository) ReactivateSellPost(ctx context.Context, postID string) error {
-	args := m.Called(ctx, postID)
+func (m *MockPostRepository) ReactivateSellPost(ctx context.Context, postID string, expiresAt time.Time) error {
+	args := m.Called(ctx, postID, expiresAt)
 	return args.Error(0)
 }
 
+func (m *MockPostRepository) SetExpiry(ctx context.Context, postID string, expiresAt *time.Time) error {
+	args := m.Called(ctx, postID, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockPostRepository) ArchiveExpiredPosts(ctx context.Context, asOf time.Time) (int64, error) {
+	args := m.Called(ctx, asOf)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPostRepository) ListUserListings(ctx context.Context, userID string, state models.ListingState, limit, offset int) ([]*models.Post, int64, error) {
+	args := m.Called(ctx, userID, state, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*models.Post), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockPostRepository) HasActionableReports(ctx context.Context, postID string) (bool, error) {
+	args := m.Called(ctx, postID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPostRepository) RenewSellPost(ctx context.Context, postID string, cooldown time.Duration, expiresAt time.Time) (bool, error) {
+	args := m.Called(ctx, postID, cooldown, expiresAt)
+	return args.Bool(0), args.Error(1)
+}
+
 // MockReportRepository is a mock implementation of ReportRepository
 type MockReportRepository struct {
 	mock.Mock
@@ -564,6 +719,11 @@ func (m *MockReportRepository) CreateBusinessReport(ctx context.Context, report
 	return args.Error(0)
 }
 
+func (m *MockReportRepository) CreateMessageReport(ctx context.Context, report *models.MessageReport) error {
+	args := m.Called(ctx, report)
+	return args.Error(0)
+}
+
 func (m *MockReportRepository) GetBusinessReport(ctx context.Context, id string) (*models.BusinessReport, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -679,6 +839,19 @@ func (m *MockRelationshipsRepository) GetBlockedUsers(ctx context.Context, block
 	return args.Get(0).([]*models.UserBlock), args.Error(1)
 }
 
+func (m *MockRelationshipsRepository) CountBlockedUsers(ctx context.Context, blockerID string) (int, error) {
+	args := m.Called(ctx, blockerID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRelationshipsRepository) BlockUsers(ctx context.Context, blockerID string, blockedIDs []string, maxBlocks int) ([]string, error) {
+	args := m.Called(ctx, blockerID, blockedIDs, maxBlocks)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 func (m *MockRelationshipsRepository) GetRelationshipStatus(ctx context.Context, viewerID, targetUserID string) (*models.RelationshipStatus, error) {
 	args := m.Called(ctx, viewerID, targetUserID)
 	if args.Get(0) == nil {
@@ -687,6 +860,14 @@ func (m *MockRelationshipsRepository) GetRelationshipStatus(ctx context.Context,
 	return args.Get(0).(*models.RelationshipStatus), args.Error(1)
 }
 
+func (m *MockRelationshipsRepository) GetRelationshipStatuses(ctx context.Context, viewerID string, targetUserIDs []string) (map[string]*models.RelationshipStatus, error) {
+	args := m.Called(ctx, viewerID, targetUserIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]*models.RelationshipStatus), args.Error(1)
+}
+
 // MockCommentRepository is a mock implementation of CommentRepository
 type MockCommentRepository struct {
 	mock.Mock
@@ -710,6 +891,16 @@ func (m *MockCommentRepository) GetRootCommentID(ctx context.Context, commentID
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockCommentRepository) PinComment(ctx context.Context, postID, commentID string) error {
+	args := m.Called(ctx, postID, commentID)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) UnpinComment(ctx context.Context, commentID string) error {
+	args := m.Called(ctx, commentID)
+	return args.Error(0)
+}
+
 func (m *MockCommentRepository) Update(ctx context.Context, comment *models.PostComment) error {
 	args := m.Called(ctx, comment)
 	return args.Error(0)
@@ -720,8 +911,8 @@ func (m *MockCommentRepository) Delete(ctx context.Context, commentID string) er
 	return args.Error(0)
 }
 
-func (m *MockCommentRepository) GetByPostID(ctx context.Context, postID string, limit, offset int) ([]*models.PostComment, error) {
-	args := m.Called(ctx, postID, limit, offset)
+func (m *MockCommentRepository) GetByPostID(ctx context.Context, postID string, sort models.CommentSort, limit, offset int) ([]*models.PostComment, error) {
+	args := m.Called(ctx, postID, sort, limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -868,6 +1059,29 @@ func (m *MockBusinessRepository) RemoveCategories(ctx context.Context, businessI
 	return args.Error(0)
 }
 
+func (m *MockBusinessRepository) GetFeaturedPostIDs(ctx context.Context, businessID string) ([]string, error) {
+	args := m.Called(ctx, businessID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockBusinessRepository) AddFeaturedPost(ctx context.Context, businessID, postID string) error {
+	args := m.Called(ctx, businessID, postID)
+	return args.Error(0)
+}
+
+func (m *MockBusinessRepository) RemoveFeaturedPost(ctx context.Context, businessID, postID string) error {
+	args := m.Called(ctx, businessID, postID)
+	return args.Error(0)
+}
+
+func (m *MockBusinessRepository) ReorderFeaturedPosts(ctx context.Context, businessID string, postIDs []string) error {
+	args := m.Called(ctx, businessID, postIDs)
+	return args.Error(0)
+}
+
 func (m *MockBusinessRepository) GetHoursByBusinessID(ctx context.Context, businessID string) ([]*models.BusinessHours, error) {
 	args := m.Called(ctx, businessID)
 	if args.Get(0) == nil {
@@ -899,8 +1113,26 @@ func (m *MockBusinessRepository) GetAttachmentsByBusinessID(ctx context.Context,
 	return args.Get(0).([]*models.BusinessAttachment), args.Error(1)
 }
 
-func (m *MockBusinessRepository) DeleteAttachment(ctx context.Context, attachmentID string) error {
-	args := m.Called(ctx, attachmentID)
+func (m *MockBusinessRepository) GetAttachment(ctx context.Context, businessID, attachmentID string) (*models.BusinessAttachment, error) {
+	args := m.Called(ctx, businessID, attachmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BusinessAttachment), args.Error(1)
+}
+
+func (m *MockBusinessRepository) DeleteAttachment(ctx context.Context, businessID, attachmentID string) error {
+	args := m.Called(ctx, businessID, attachmentID)
+	return args.Error(0)
+}
+
+func (m *MockBusinessRepository) ReorderAttachments(ctx context.Context, businessID string, attachmentIDs []string) error {
+	args := m.Called(ctx, businessID, attachmentIDs)
+	return args.Error(0)
+}
+
+func (m *MockBusinessRepository) SetAttachmentCaption(ctx context.Context, businessID, attachmentID string, caption *string) error {
+	args := m.Called(ctx, businessID, attachmentID, caption)
 	return args.Error(0)
 }
 
@@ -927,6 +1159,14 @@ func (m *MockBusinessRepository) GetFollowers(ctx context.Context, businessID st
 	return args.Get(0).([]string), args.Error(1)
 }
 
+func (m *MockBusinessRepository) CountByCategory(ctx context.Context, filter *models.BusinessListFilter) ([]*models.FacetCount, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.FacetCount), args.Error(1)
+}
+
 func (m *MockBusinessRepository) GetAllCategories(ctx context.Context, search *string) ([]*models.BusinessCategory, error) {
 	args := m.Called(ctx, search)
 	if args.Get(0) == nil {
@@ -993,6 +1233,14 @@ func (m *MockBusinessRepository) GetDailyPostViews(ctx context.Context, business
 	return args.Get(0).([]models.DailyCount), args.Error(1)
 }
 
+func (m *MockBusinessRepository) GetDailyPostImpressions(ctx context.Context, businessID string, days int) ([]models.DailyCount, error) {
+	args := m.Called(ctx, businessID, days)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DailyCount), args.Error(1)
+}
+
 func (m *MockBusinessRepository) GetRatingDistribution(ctx context.Context, businessID string) (map[int]int, error) {
 	args := m.Called(ctx, businessID)
 	if args.Get(0) == nil {
@@ -1006,6 +1254,35 @@ func (m *MockBusinessRepository) GetFollowerCount(ctx context.Context, businessI
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockBusinessRepository) ListFollowers(ctx context.Context, businessID string, limit, offset int) ([]*models.BusinessFollowerResponse, int, error) {
+	args := m.Called(ctx, businessID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.BusinessFollowerResponse), args.Int(1), args.Error(2)
+}
+
+func (m *MockBusinessRepository) GetFollowerProvinceCounts(ctx context.Context, businessID string) ([]*models.ProvinceCount, error) {
+	args := m.Called(ctx, businessID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ProvinceCount), args.Error(1)
+}
+
+func (m *MockBusinessRepository) GetFollowerNotificationSettings(ctx context.Context, businessID string) (*models.BusinessFollowerNotificationSettings, error) {
+	args := m.Called(ctx, businessID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BusinessFollowerNotificationSettings), args.Error(1)
+}
+
+func (m *MockBusinessRepository) SetFollowerNotificationSettings(ctx context.Context, businessID string, settings *models.BusinessFollowerNotificationSettings) error {
+	args := m.Called(ctx, businessID, settings)
+	return args.Error(0)
+}
+
 func (m *MockBusinessRepository) GetOwnerPostCounts(ctx context.Context, businessID, ownerID string) (*models.BusinessOwnerPostCounts, error) {
 	args := m.Called(ctx, businessID, ownerID)
 	if args.Get(0) == nil {
@@ -1125,6 +1402,16 @@ func (m *MockNotificationRepository) Delete(ctx context.Context, notificationID
 	return args.Error(0)
 }
 
+func (m *MockNotificationRepository) DeleteForUser(ctx context.Context, filter *models.ClearNotificationsFilter) (int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepository) DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	args := m.Called(ctx, cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockNotificationRepository) GetUnreadCount(ctx context.Context, userID string, businessID *string) (int, error) {
 	args := m.Called(ctx, userID, businessID)
 	return args.Int(0), args.Error(1)
@@ -1399,6 +1686,11 @@ func (m *MockAdminRepository) GetBusinessHours(ctx context.Context, businessID s
 	return args.Get(0).([]models.AdminBusinessHour), args.Error(1)
 }
 
+func (m *MockAdminRepository) SetBusinessCategories(ctx context.Context, businessID string, categoryIDs []string) error {
+	args := m.Called(ctx, businessID, categoryIDs)
+	return args.Error(0)
+}
+
 func (m *MockAdminRepository) GetBusinessCategories(ctx context.Context, businessID string) ([]string, error) {
 	args := m.Called(ctx, businessID)
 	if args.Get(0) == nil {
@@ -1509,6 +1801,45 @@ func (m *MockAdminRepository) UpdateBusinessReportStatus(ctx context.Context, re
 	return args.Error(0)
 }
 
+func (m *MockAdminRepository) ListMessageReports(ctx context.Context, filter *models.AdminReportFilter) ([]*models.AdminMessageReportResponse, int64, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*models.AdminMessageReportResponse), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockAdminRepository) GetMessageReportByID(ctx context.Context, reportID string) (*models.AdminMessageReportResponse, error) {
+	args := m.Called(ctx, reportID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AdminMessageReportResponse), args.Error(1)
+}
+
+func (m *MockAdminRepository) CountPriorViolations(ctx context.Context, userID, excludeTargetID string) (int64, error) {
+	args := m.Called(ctx, userID, excludeTargetID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAdminRepository) UpdateMessageReportStatus(ctx context.Context, reportID, status string) error {
+	args := m.Called(ctx, reportID, status)
+	return args.Error(0)
+}
+
+func (m *MockAdminRepository) ListReportGroups(ctx context.Context, reportType string, filter *models.AdminReportFilter) ([]*models.AdminReportGroup, int64, error) {
+	args := m.Called(ctx, reportType, filter)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*models.AdminReportGroup), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockAdminRepository) ResolveReportGroup(ctx context.Context, reportType, targetID, status string) (int64, error) {
+	args := m.Called(ctx, reportType, targetID, status)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockAdminRepository) GetAllUserIDs(ctx context.Context) ([]string, error) {
 	args := m.Called(ctx)
 	if args.Get(0) == nil {
@@ -1945,6 +2276,11 @@ func (m *MockConversationRepository) GetOtherParticipantID(ctx context.Context,
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockConversationRepository) SetDisappearingTTL(ctx context.Context, conversationID string, ttlSeconds *int) error {
+	args := m.Called(ctx, conversationID, ttlSeconds)
+	return args.Error(0)
+}
+
 // MockMessageRepository is a mock implementation of MessageRepository
 type MockMessageRepository struct {
 	mock.Mock
@@ -2005,6 +2341,14 @@ func (m *MockMessageRepository) GetUnreadCount(ctx context.Context, conversation
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockMessageRepository) GetUnreadCountsByUser(ctx context.Context, userID string) (map[string]int, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int), args.Error(1)
+}
+
 func (m *MockMessageRepository) GetLastMessage(ctx context.Context, conversationID, viewerID string) (*models.Message, error) {
 	args := m.Called(ctx, conversationID, viewerID)
 	if args.Get(0) == nil {
@@ -2031,6 +2375,14 @@ func (m *MockMessageRepository) GetReactions(ctx context.Context, messageIDs []s
 	return args.Get(0).(map[string][]models.MessageReaction), args.Error(1)
 }
 
+func (m *MockMessageRepository) DeleteExpired(ctx context.Context, limit int) ([]*models.Message, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Message), args.Error(1)
+}
+
 // MockMFARepository is a mock implementation of MFARepository
 type MockMFARepository struct {
 	mock.Mock
@@ -2139,6 +2491,14 @@ func (m *MockFanoutRepository) GetCelebrityPostIDs(ctx context.Context, viewerID
 	return args.Get(0).([]string), args.Error(1)
 }
 
+func (m *MockFanoutRepository) FilterTimelinePostIDs(ctx context.Context, viewerID string, postIDs []string) ([]string, error) {
+	args := m.Called(ctx, viewerID, postIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 // MockSearchRepository is a mock implementation of SearchRepository
 type MockSearchRepository struct {
 	mock.Mock
@@ -2152,7 +2512,23 @@ func (m *MockSearchRepository) SearchPosts(ctx context.Context, filter *models.S
 	return args.Get(0).([]*models.Post), args.Error(1)
 }
 
-func (m *MockSearchRepository) SearchUsers(ctx context.Context, filter *models.SearchFilter) ([]*models.Profile, error) {
+func (m *MockSearchRepository) GetPostSearchFacets(ctx context.Context, filter *models.SearchFilter) (*models.SearchFacets, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SearchFacets), args.Error(1)
+}
+
+func (m *MockSearchRepository) GetPostHighlights(ctx context.Context, postIDs []string, query string) (map[string]*models.SearchHighlight, error) {
+	args := m.Called(ctx, postIDs, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]*models.SearchHighlight), args.Error(1)
+}
+
+func (m *MockSearchRepository) SearchUsers(ctx context.Context, filter *models.SearchFilter) ([]*models.Profile, error) {
 	args := m.Called(ctx, filter)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -2184,6 +2560,135 @@ func (m *MockSearchRepository) GetDiscoverBusinesses(ctx context.Context, lat, l
 	return args.Get(0).([]*models.BusinessProfile), args.Error(1)
 }
 
+func (m *MockSearchRepository) SuggestUsers(ctx context.Context, prefix string, viewerID *string, limit int) ([]*models.SearchSuggestion, error) {
+	args := m.Called(ctx, prefix, viewerID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.SearchSuggestion), args.Error(1)
+}
+
+func (m *MockSearchRepository) SuggestBusinesses(ctx context.Context, prefix string, limit int) ([]*models.SearchSuggestion, error) {
+	args := m.Called(ctx, prefix, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.SearchSuggestion), args.Error(1)
+}
+
+func (m *MockSearchRepository) SuggestCategories(ctx context.Context, prefix string, limit int) ([]*models.SearchSuggestion, error) {
+	args := m.Called(ctx, prefix, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.SearchSuggestion), args.Error(1)
+}
+
+func (m *MockSearchRepository) SuggestHashtags(ctx context.Context, prefix string, limit int) ([]*models.SearchSuggestion, error) {
+	args := m.Called(ctx, prefix, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.SearchSuggestion), args.Error(1)
+}
+
+func (m *MockSearchRepository) IncrementHashtags(ctx context.Context, tags []string) error {
+	return m.Called(ctx, tags).Error(0)
+}
+
+func (m *MockSearchRepository) AddRecentSearch(ctx context.Context, userID, query string) error {
+	return m.Called(ctx, userID, query).Error(0)
+}
+
+func (m *MockSearchRepository) ListRecentSearches(ctx context.Context, userID, prefix string, limit int) ([]*models.RecentSearch, error) {
+	args := m.Called(ctx, userID, prefix, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.RecentSearch), args.Error(1)
+}
+
+func (m *MockSearchRepository) ClearRecentSearches(ctx context.Context, userID string) error {
+	return m.Called(ctx, userID).Error(0)
+}
+
+func (m *MockSearchRepository) AdminSearch(ctx context.Context, filter *models.AdminSearchFilter) ([]*models.AdminSearchResult, int, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.AdminSearchResult), args.Int(1), args.Error(2)
+}
+
+// MockCustomRoleRepository is a mock implementation of CustomRoleRepository.
+type MockCustomRoleRepository struct {
+	mock.Mock
+}
+
+func (m *MockCustomRoleRepository) List(ctx context.Context) ([]*models.CustomRole, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.CustomRole), args.Error(1)
+}
+
+func (m *MockCustomRoleRepository) Get(ctx context.Context, id string) (*models.CustomRole, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CustomRole), args.Error(1)
+}
+
+func (m *MockCustomRoleRepository) GetByName(ctx context.Context, name string) (*models.CustomRole, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CustomRole), args.Error(1)
+}
+
+func (m *MockCustomRoleRepository) Create(ctx context.Context, req *models.CreateCustomRoleRequest, createdBy string) (*models.CustomRole, error) {
+	args := m.Called(ctx, req, createdBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CustomRole), args.Error(1)
+}
+
+func (m *MockCustomRoleRepository) Update(ctx context.Context, id string, req *models.UpdateCustomRoleRequest, updatedBy string) (*models.CustomRole, error) {
+	args := m.Called(ctx, id, req, updatedBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CustomRole), args.Error(1)
+}
+
+func (m *MockCustomRoleRepository) Delete(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockCustomRoleRepository) Assign(ctx context.Context, userID string, customRoleID *string) error {
+	return m.Called(ctx, userID, customRoleID).Error(0)
+}
+
+func (m *MockCustomRoleRepository) ListUsers(ctx context.Context, customRoleID string) ([]*models.CustomRoleUser, error) {
+	args := m.Called(ctx, customRoleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.CustomRoleUser), args.Error(1)
+}
+
+func (m *MockCustomRoleRepository) GetUserCustomRole(ctx context.Context, userID string) (*models.CustomRole, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CustomRole), args.Error(1)
+}
+
 // MockHelpChatRepository is a mock implementation of HelpChatRepository.
 type MockHelpChatRepository struct {
 	mock.Mock
@@ -2277,123 +2782,1628 @@ func (m *MockBusinessReviewRepository) GetStats(ctx context.Context, businessID
 	return args.Get(0).(*models.BusinessReviewStats), args.Error(1)
 }
 
-// MockMonetizationRepository is a mock implementation of MonetizationRepository.
-type MockMonetizationRepository struct {
+// MockBusinessProductRepository is a mock implementation of BusinessProductRepository
+type MockBusinessProductRepository struct {
 	mock.Mock
 }
 
-func (m *MockMonetizationRepository) ListAds(ctx context.Context, status string, page, limit int) ([]*models.Ad, int, error) {
-	args := m.Called(ctx, status, page, limit)
+func (m *MockBusinessProductRepository) Create(ctx context.Context, product *models.BusinessProduct) error {
+	args := m.Called(ctx, product)
+	return args.Error(0)
+}
+
+func (m *MockBusinessProductRepository) GetByID(ctx context.Context, productID string) (*models.BusinessProduct, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BusinessProduct), args.Error(1)
+}
+
+func (m *MockBusinessProductRepository) Update(ctx context.Context, product *models.BusinessProduct) error {
+	args := m.Called(ctx, product)
+	return args.Error(0)
+}
+
+func (m *MockBusinessProductRepository) Delete(ctx context.Context, productID string) error {
+	args := m.Called(ctx, productID)
+	return args.Error(0)
+}
+
+func (m *MockBusinessProductRepository) ListByBusiness(ctx context.Context, businessID string, includeDiscontinued bool, limit, offset int) ([]*models.BusinessProduct, int, error) {
+	args := m.Called(ctx, businessID, includeDiscontinued, limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Int(1), args.Error(2)
 	}
-	return args.Get(0).([]*models.Ad), args.Int(1), args.Error(2)
+	return args.Get(0).([]*models.BusinessProduct), args.Int(1), args.Error(2)
 }
 
-func (m *MockMonetizationRepository) ListActiveAds(ctx context.Context, limit int, province, language string) ([]*models.Ad, error) {
-	args := m.Called(ctx, limit, province, language)
+func (m *MockBusinessProductRepository) GetByPostIDs(ctx context.Context, postIDs []string) (map[string]*models.BusinessProduct, error) {
+	args := m.Called(ctx, postIDs)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]*models.Ad), args.Error(1)
+	return args.Get(0).(map[string]*models.BusinessProduct), args.Error(1)
 }
 
-func (m *MockMonetizationRepository) GetAd(ctx context.Context, id string) (*models.Ad, error) {
-	args := m.Called(ctx, id)
+// MockBusinessBookingRepository is a mock implementation of BusinessBookingRepository
+type MockBusinessBookingRepository struct {
+	mock.Mock
+}
+
+func (m *MockBusinessBookingRepository) Create(ctx context.Context, booking *models.BusinessBooking) error {
+	args := m.Called(ctx, booking)
+	return args.Error(0)
+}
+
+func (m *MockBusinessBookingRepository) GetByID(ctx context.Context, bookingID string) (*models.BusinessBooking, error) {
+	args := m.Called(ctx, bookingID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*models.Ad), args.Error(1)
+	return args.Get(0).(*models.BusinessBooking), args.Error(1)
 }
 
-func (m *MockMonetizationRepository) CreateAd(ctx context.Context, advertiserID, title, body, imageURL, targetURL, phoneNumber, whatsappNumber, status string, startAt, endAt *time.Time, weight int, dailyImpressionCap *int, targetProvinces, targetLanguages []string) (*models.Ad, error) {
-	args := m.Called(ctx, advertiserID, title, body, imageURL, targetURL, phoneNumber, whatsappNumber, status, startAt, endAt, weight, dailyImpressionCap, targetProvinces, targetLanguages)
+func (m *MockBusinessBookingRepository) Transition(ctx context.Context, bookingID string, from []models.BookingStatus, to models.BookingStatus, responseNote *string) (*models.BusinessBooking, error) {
+	args := m.Called(ctx, bookingID, from, to, responseNote)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*models.Ad), args.Error(1)
+	return args.Get(0).(*models.BusinessBooking), args.Error(1)
 }
 
-func (m *MockMonetizationRepository) UpdateAdStatus(ctx context.Context, id, status, reviewedBy string, req *models.AdReviewRequest) (*models.Ad, error) {
-	args := m.Called(ctx, id, status, reviewedBy, req)
+func (m *MockBusinessBookingRepository) ListByBusiness(ctx context.Context, businessID string, filter *models.BookingFilter) ([]*models.BusinessBookingWithCustomer, error) {
+	args := m.Called(ctx, businessID, filter)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*models.Ad), args.Error(1)
+	return args.Get(0).([]*models.BusinessBookingWithCustomer), args.Error(1)
 }
 
-func (m *MockMonetizationRepository) UpdateAd(ctx context.Context, id, title, body, targetURL, phoneNumber, whatsappNumber string, weight int, dailyImpressionCap *int, targetProvinces, targetLanguages []string, startAt, endAt *time.Time, imageURL *string) (*models.Ad, error) {
-	args := m.Called(ctx, id, title, body, targetURL, phoneNumber, whatsappNumber, weight, dailyImpressionCap, targetProvinces, targetLanguages, startAt, endAt, imageURL)
+func (m *MockBusinessBookingRepository) ListByCustomer(ctx context.Context, customerID string, limit, offset int) ([]*models.BusinessBooking, int, error) {
+	args := m.Called(ctx, customerID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.BusinessBooking), args.Int(1), args.Error(2)
+}
+
+// MockGroupRepository is a mock implementation of GroupRepository
+type MockGroupRepository struct {
+	mock.Mock
+}
+
+func (m *MockGroupRepository) Create(ctx context.Context, group *models.Group) error {
+	args := m.Called(ctx, group)
+	return args.Error(0)
+}
+
+func (m *MockGroupRepository) GetByID(ctx context.Context, groupID string) (*models.Group, error) {
+	args := m.Called(ctx, groupID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*models.Ad), args.Error(1)
+	return args.Get(0).(*models.Group), args.Error(1)
 }
 
-func (m *MockMonetizationRepository) DeleteAd(ctx context.Context, id string) error {
-	return m.Called(ctx, id).Error(0)
+func (m *MockGroupRepository) Update(ctx context.Context, group *models.Group) error {
+	args := m.Called(ctx, group)
+	return args.Error(0)
 }
 
-func (m *MockMonetizationRepository) IncrementAdImpression(ctx context.Context, id string) error {
-	return m.Called(ctx, id).Error(0)
+func (m *MockGroupRepository) Delete(ctx context.Context, groupID string) error {
+	args := m.Called(ctx, groupID)
+	return args.Error(0)
 }
 
-func (m *MockMonetizationRepository) IncrementAdClick(ctx context.Context, id string) error {
-	return m.Called(ctx, id).Error(0)
+func (m *MockGroupRepository) List(ctx context.Context, filter *models.GroupListFilter) ([]*models.Group, int, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.Group), args.Int(1), args.Error(2)
 }
 
-func (m *MockMonetizationRepository) ListBalances(ctx context.Context, search string, page, limit int) ([]*models.CreditBalance, int, error) {
-	args := m.Called(ctx, search, page, limit)
+func (m *MockGroupRepository) ListByMember(ctx context.Context, userID string, limit, offset int) ([]*models.Group, int, error) {
+	args := m.Called(ctx, userID, limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Int(1), args.Error(2)
 	}
-	return args.Get(0).([]*models.CreditBalance), args.Int(1), args.Error(2)
+	return args.Get(0).([]*models.Group), args.Int(1), args.Error(2)
 }
 
-func (m *MockMonetizationRepository) GetBalance(ctx context.Context, userID string) (*models.CreditBalance, error) {
-	args := m.Called(ctx, userID)
+func (m *MockGroupRepository) GetMember(ctx context.Context, groupID, userID string) (*models.GroupMember, error) {
+	args := m.Called(ctx, groupID, userID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*models.CreditBalance), args.Error(1)
+	return args.Get(0).(*models.GroupMember), args.Error(1)
 }
 
-func (m *MockMonetizationRepository) ListUserTransactions(ctx context.Context, userID string, limit int) ([]*models.CreditTransaction, error) {
-	args := m.Called(ctx, userID, limit)
+func (m *MockGroupRepository) GetMemberships(ctx context.Context, userID string, groupIDs []string) (map[string]*models.GroupMember, error) {
+	args := m.Called(ctx, userID, groupIDs)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]*models.CreditTransaction), args.Error(1)
+	return args.Get(0).(map[string]*models.GroupMember), args.Error(1)
 }
 
-func (m *MockMonetizationRepository) AdjustCredits(ctx context.Context, userID string, req *models.AdjustCreditsRequest, adminID string) (*models.CreditBalance, error) {
-	args := m.Called(ctx, userID, req, adminID)
+func (m *MockGroupRepository) AddMember(ctx context.Context, member *models.GroupMember) error {
+	args := m.Called(ctx, member)
+	return args.Error(0)
+}
+
+func (m *MockGroupRepository) ApproveMember(ctx context.Context, groupID, userID string) error {
+	args := m.Called(ctx, groupID, userID)
+	return args.Error(0)
+}
+
+func (m *MockGroupRepository) SetMemberRole(ctx context.Context, groupID, userID string, role models.GroupRole) error {
+	args := m.Called(ctx, groupID, userID, role)
+	return args.Error(0)
+}
+
+func (m *MockGroupRepository) RemoveMember(ctx context.Context, groupID, userID string) error {
+	args := m.Called(ctx, groupID, userID)
+	return args.Error(0)
+}
+
+func (m *MockGroupRepository) ListMembers(ctx context.Context, groupID string, status models.GroupMemberStatus, limit, offset int) ([]*models.GroupMemberWithProfile, int, error) {
+	args := m.Called(ctx, groupID, status, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.GroupMemberWithProfile), args.Int(1), args.Error(2)
+}
+
+func (m *MockGroupRepository) CountAdmins(ctx context.Context, groupID string) (int, error) {
+	args := m.Called(ctx, groupID)
+	return args.Int(0), args.Error(1)
+}
+
+// MockHelpPledgeRepository is a mock implementation of HelpPledgeRepository
+type MockHelpPledgeRepository struct {
+	mock.Mock
+}
+
+func (m *MockHelpPledgeRepository) Create(ctx context.Context, pledge *models.HelpPledge) error {
+	args := m.Called(ctx, pledge)
+	return args.Error(0)
+}
+
+func (m *MockHelpPledgeRepository) GetByID(ctx context.Context, pledgeID string) (*models.HelpPledge, error) {
+	args := m.Called(ctx, pledgeID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*models.CreditBalance), args.Error(1)
+	return args.Get(0).(*models.HelpPledge), args.Error(1)
 }
 
-func (m *MockMonetizationRepository) ListBoosts(ctx context.Context, status string, page, limit int) ([]*models.Boost, int, error) {
-	args := m.Called(ctx, status, page, limit)
+func (m *MockHelpPledgeRepository) GetActive(ctx context.Context, postID, userID string) (*models.HelpPledge, error) {
+	args := m.Called(ctx, postID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.HelpPledge), args.Error(1)
+}
+
+func (m *MockHelpPledgeRepository) UpdateDetails(ctx context.Context, pledge *models.HelpPledge) error {
+	args := m.Called(ctx, pledge)
+	return args.Error(0)
+}
+
+func (m *MockHelpPledgeRepository) Transition(ctx context.Context, pledgeID string, from []models.PledgeStatus, to models.PledgeStatus) (*models.HelpPledge, error) {
+	args := m.Called(ctx, pledgeID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.HelpPledge), args.Error(1)
+}
+
+func (m *MockHelpPledgeRepository) ListByPost(ctx context.Context, postID string, limit, offset int) ([]*models.HelpPledgeWithProfile, int, error) {
+	args := m.Called(ctx, postID, limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Int(1), args.Error(2)
 	}
-	return args.Get(0).([]*models.Boost), args.Int(1), args.Error(2)
+	return args.Get(0).([]*models.HelpPledgeWithProfile), args.Int(1), args.Error(2)
 }
 
-func (m *MockMonetizationRepository) GetBoost(ctx context.Context, id string) (*models.Boost, error) {
-	args := m.Called(ctx, id)
+func (m *MockHelpPledgeRepository) ListPledgerIDs(ctx context.Context, postID string) ([]string, error) {
+	args := m.Called(ctx, postID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*models.Boost), args.Error(1)
+	return args.Get(0).([]string), args.Error(1)
 }
 
-func (m *MockMonetizationRepository) CancelBoost(ctx context.Context, id, adminID, reason string) (*models.Boost, error) {
-	args := m.Called(ctx, id, adminID, reason)
+func (m *MockHelpPledgeRepository) GetSummaries(ctx context.Context, postIDs []string, viewerID string) (map[string]*models.HelpSummary, error) {
+	args := m.Called(ctx, postIDs, viewerID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*models.Boost), args.Error(1)
+	return args.Get(0).(map[string]*models.HelpSummary), args.Error(1)
+}
+
+func (m *MockHelpPledgeRepository) MarkFulfilled(ctx context.Context, postID string) (time.Time, error) {
+	args := m.Called(ctx, postID)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+// MockEventHostRepository is a mock implementation of EventHostRepository
+type MockEventHostRepository struct {
+	mock.Mock
+}
+
+func (m *MockEventHostRepository) Invite(ctx context.Context, host *models.EventHost) error {
+	args := m.Called(ctx, host)
+	return args.Error(0)
+}
+
+func (m *MockEventHostRepository) GetByID(ctx context.Context, hostID string) (*models.EventHost, error) {
+	args := m.Called(ctx, hostID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EventHost), args.Error(1)
+}
+
+func (m *MockEventHostRepository) Respond(ctx context.Context, hostID string, status models.EventHostStatus) (*models.EventHost, error) {
+	args := m.Called(ctx, hostID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EventHost), args.Error(1)
+}
+
+func (m *MockEventHostRepository) Delete(ctx context.Context, hostID string) error {
+	args := m.Called(ctx, hostID)
+	return args.Error(0)
+}
+
+func (m *MockEventHostRepository) ListByPostIDs(ctx context.Context, postIDs []string, includePending bool) (map[string][]*models.EventHost, error) {
+	args := m.Called(ctx, postIDs, includePending)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string][]*models.EventHost), args.Error(1)
+}
+
+func (m *MockEventHostRepository) ListPendingForUser(ctx context.Context, userID string) ([]*models.EventHostInvitation, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.EventHostInvitation), args.Error(1)
+}
+
+func (m *MockEventHostRepository) IsCoHost(ctx context.Context, postID, userID string) (bool, error) {
+	args := m.Called(ctx, postID, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEventHostRepository) ListAttendeeIDs(ctx context.Context, postID string, states []models.EventInterestState) ([]string, error) {
+	args := m.Called(ctx, postID, states)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// MockLocationRepository is a mock implementation of LocationRepository
+type MockLocationRepository struct {
+	mock.Mock
+}
+
+func (m *MockLocationRepository) Create(ctx context.Context, loc *models.LocationRef) error {
+	args := m.Called(ctx, loc)
+	return args.Error(0)
+}
+
+func (m *MockLocationRepository) GetByID(ctx context.Context, id string) (*models.LocationRef, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LocationRef), args.Error(1)
+}
+
+func (m *MockLocationRepository) Update(ctx context.Context, loc *models.LocationRef, renamed bool) error {
+	args := m.Called(ctx, loc, renamed)
+	return args.Error(0)
+}
+
+func (m *MockLocationRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockLocationRepository) List(ctx context.Context, filter *models.LocationFilter) ([]*models.LocationRef, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.LocationRef), args.Error(1)
+}
+
+// MockBookmarkCollectionRepository is a mock implementation of BookmarkCollectionRepository
+type MockBookmarkCollectionRepository struct {
+	mock.Mock
+}
+
+func (m *MockBookmarkCollectionRepository) Create(ctx context.Context, collection *models.BookmarkCollection) error {
+	args := m.Called(ctx, collection)
+	return args.Error(0)
+}
+
+func (m *MockBookmarkCollectionRepository) GetByID(ctx context.Context, collectionID, userID string) (*models.BookmarkCollection, error) {
+	args := m.Called(ctx, collectionID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BookmarkCollection), args.Error(1)
+}
+
+func (m *MockBookmarkCollectionRepository) Rename(ctx context.Context, collection *models.BookmarkCollection) error {
+	args := m.Called(ctx, collection)
+	return args.Error(0)
+}
+
+func (m *MockBookmarkCollectionRepository) Delete(ctx context.Context, collectionID, userID string) error {
+	args := m.Called(ctx, collectionID, userID)
+	return args.Error(0)
+}
+
+func (m *MockBookmarkCollectionRepository) List(ctx context.Context, userID string) (*models.BookmarkCollectionsResponse, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BookmarkCollectionsResponse), args.Error(1)
+}
+
+func (m *MockBookmarkCollectionRepository) AssignBookmark(ctx context.Context, userID, postID string, collectionID *string) error {
+	args := m.Called(ctx, userID, postID, collectionID)
+	return args.Error(0)
+}
+
+// MockMonetizationRepository is a mock implementation of MonetizationRepository.
+type MockMonetizationRepository struct {
+	mock.Mock
+}
+
+func (m *MockMonetizationRepository) ListAds(ctx context.Context, status string, page, limit int) ([]*models.Ad, int, error) {
+	args := m.Called(ctx, status, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.Ad), args.Int(1), args.Error(2)
+}
+
+func (m *MockMonetizationRepository) ListActiveAds(ctx context.Context, limit int, province, language string) ([]*models.Ad, error) {
+	args := m.Called(ctx, limit, province, language)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Ad), args.Error(1)
+}
+
+func (m *MockMonetizationRepository) GetAd(ctx context.Context, id string) (*models.Ad, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Ad), args.Error(1)
+}
+
+func (m *MockMonetizationRepository) CreateAd(ctx context.Context, advertiserID, title, body, imageURL, targetURL, phoneNumber, whatsappNumber, status string, startAt, endAt *time.Time, weight int, dailyImpressionCap *int, targetProvinces, targetLanguages []string) (*models.Ad, error) {
+	args := m.Called(ctx, advertiserID, title, body, imageURL, targetURL, phoneNumber, whatsappNumber, status, startAt, endAt, weight, dailyImpressionCap, targetProvinces, targetLanguages)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Ad), args.Error(1)
+}
+
+func (m *MockMonetizationRepository) UpdateAdStatus(ctx context.Context, id, status, reviewedBy string, req *models.AdReviewRequest) (*models.Ad, error) {
+	args := m.Called(ctx, id, status, reviewedBy, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Ad), args.Error(1)
+}
+
+func (m *MockMonetizationRepository) UpdateAd(ctx context.Context, id, title, body, targetURL, phoneNumber, whatsappNumber string, weight int, dailyImpressionCap *int, targetProvinces, targetLanguages []string, startAt, endAt *time.Time, imageURL *string) (*models.Ad, error) {
+	args := m.Called(ctx, id, title, body, targetURL, phoneNumber, whatsappNumber, weight, dailyImpressionCap, targetProvinces, targetLanguages, startAt, endAt, imageURL)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Ad), args.Error(1)
+}
+
+func (m *MockMonetizationRepository) DeleteAd(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockMonetizationRepository) IncrementAdImpression(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockMonetizationRepository) IncrementAdClick(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockMonetizationRepository) ListBalances(ctx context.Context, search string, page, limit int) ([]*models.CreditBalance, int, error) {
+	args := m.Called(ctx, search, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.CreditBalance), args.Int(1), args.Error(2)
+}
+
+func (m *MockMonetizationRepository) GetBalance(ctx context.Context, userID string) (*models.CreditBalance, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CreditBalance), args.Error(1)
+}
+
+func (m *MockMonetizationRepository) ListUserTransactions(ctx context.Context, userID string, limit int) ([]*models.CreditTransaction, error) {
+	args := m.Called(ctx, userID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.CreditTransaction), args.Error(1)
+}
+
+func (m *MockMonetizationRepository) AdjustCredits(ctx context.Context, userID string, req *models.AdjustCreditsRequest, adminID string) (*models.CreditBalance, error) {
+	args := m.Called(ctx, userID, req, adminID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CreditBalance), args.Error(1)
+}
+
+func (m *MockMonetizationRepository) ListBoosts(ctx context.Context, status string, page, limit int) ([]*models.Boost, int, error) {
+	args := m.Called(ctx, status, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.Boost), args.Int(1), args.Error(2)
+}
+
+func (m *MockMonetizationRepository) GetBoost(ctx context.Context, id string) (*models.Boost, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Boost), args.Error(1)
+}
+
+func (m *MockMonetizationRepository) CancelBoost(ctx context.Context, id, adminID, reason string) (*models.Boost, error) {
+	args := m.Called(ctx, id, adminID, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Boost), args.Error(1)
+}
+
+// MockEmailDeliveryRepository is a mock implementation of EmailDeliveryRepository.
+type MockEmailDeliveryRepository struct {
+	mock.Mock
+}
+
+func (m *MockEmailDeliveryRepository) LogSent(ctx context.Context, email *models.SentEmail) (string, error) {
+	args := m.Called(ctx, email)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockEmailDeliveryRepository) UpdateStatusByProviderID(ctx context.Context, provider, providerMessageID string, status models.EmailDeliveryStatus, detail *string) (string, error) {
+	args := m.Called(ctx, provider, providerMessageID, status, detail)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockEmailDeliveryRepository) ListByEmail(ctx context.Context, email string, limit int) ([]models.SentEmail, error) {
+	args := m.Called(ctx, email, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SentEmail), args.Error(1)
+}
+
+func (m *MockEmailDeliveryRepository) GetSuppression(ctx context.Context, email string) (*models.EmailSuppression, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EmailSuppression), args.Error(1)
+}
+
+func (m *MockEmailDeliveryRepository) Suppress(ctx context.Context, email, reason string, detail *string) error {
+	args := m.Called(ctx, email, reason, detail)
+	return args.Error(0)
+}
+
+func (m *MockEmailDeliveryRepository) Unsuppress(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
+// MockPrivacyRepository is a mock implementation of PrivacyRepository.
+type MockPrivacyRepository struct {
+	mock.Mock
+}
+
+func (m *MockPrivacyRepository) Get(ctx context.Context, userID string) (*models.PrivacySettings, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PrivacySettings), args.Error(1)
+}
+
+func (m *MockPrivacyRepository) GetMany(ctx context.Context, userIDs []string) (map[string]*models.PrivacySettings, error) {
+	args := m.Called(ctx, userIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]*models.PrivacySettings), args.Error(1)
+}
+
+func (m *MockPrivacyRepository) Upsert(ctx context.Context, settings *models.PrivacySettings) error {
+	args := m.Called(ctx, settings)
+	return args.Error(0)
+}
+
+// MockEndorsementRepository is a mock implementation of EndorsementRepository.
+type MockEndorsementRepository struct {
+	mock.Mock
+}
+
+func (m *MockEndorsementRepository) Upsert(ctx context.Context, e *models.Endorsement) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
+
+func (m *MockEndorsementRepository) GetByID(ctx context.Context, id string) (*models.Endorsement, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Endorsement), args.Error(1)
+}
+
+func (m *MockEndorsementRepository) ListForSubject(ctx context.Context, subjectID string, status *models.EndorsementStatus, limit, offset int) ([]*models.EndorsementWithAuthor, int, error) {
+	args := m.Called(ctx, subjectID, status, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.EndorsementWithAuthor), args.Int(1), args.Error(2)
+}
+
+func (m *MockEndorsementRepository) CountApproved(ctx context.Context, subjectID string) (int, error) {
+	args := m.Called(ctx, subjectID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockEndorsementRepository) SetStatus(ctx context.Context, id string, status models.EndorsementStatus) error {
+	args := m.Called(ctx, id, status)
+	return args.Error(0)
+}
+
+func (m *MockEndorsementRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockEndorsementRepository) CreateReport(ctx context.Context, report *models.EndorsementReport) error {
+	args := m.Called(ctx, report)
+	return args.Error(0)
+}
+
+func (m *MockEndorsementRepository) ListReported(ctx context.Context, limit, offset int) ([]*models.ReportedEndorsement, int, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.ReportedEndorsement), args.Int(1), args.Error(2)
+}
+
+// MockMediaScanRepository is a mock implementation of MediaScanRepository.
+type MockMediaScanRepository struct {
+	mock.Mock
+}
+
+func (m *MockMediaScanRepository) ListUnscanned(ctx context.Context, limit int) ([]*models.MediaScanJob, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.MediaScanJob), args.Error(1)
+}
+
+func (m *MockMediaScanRepository) RecordScan(ctx context.Context, job *models.MediaScanJob, labels *models.MediaScanLabels, flagged bool, blurredURL *string) error {
+	args := m.Called(ctx, job, labels, flagged, blurredURL)
+	return args.Error(0)
+}
+
+func (m *MockMediaScanRepository) RecordScanFailure(ctx context.Context, job *models.MediaScanJob, reason string, maxAttempts int) error {
+	args := m.Called(ctx, job, reason, maxAttempts)
+	return args.Error(0)
+}
+
+func (m *MockMediaScanRepository) EnqueueBusinessMedia(ctx context.Context, businessID, url string) error {
+	args := m.Called(ctx, businessID, url)
+	return args.Error(0)
+}
+
+func (m *MockMediaScanRepository) FlaggedAttachments(ctx context.Context, attachmentIDs []string) (map[string]*string, error) {
+	args := m.Called(ctx, attachmentIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]*string), args.Error(1)
+}
+
+// MockMutedTermRepository is a mock implementation of MutedTermRepository
+type MockMutedTermRepository struct {
+	mock.Mock
+}
+
+func (m *MockMutedTermRepository) Create(ctx context.Context, term *models.MutedTerm) error {
+	args := m.Called(ctx, term)
+	return args.Error(0)
+}
+
+func (m *MockMutedTermRepository) Delete(ctx context.Context, userID, termID string) error {
+	args := m.Called(ctx, userID, termID)
+	return args.Error(0)
+}
+
+func (m *MockMutedTermRepository) ListByUser(ctx context.Context, userID string) ([]*models.MutedTerm, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.MutedTerm), args.Error(1)
+}
+
+func (m *MockMutedTermRepository) CountByUser(ctx context.Context, userID string) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+// MockBusinessQuickReplyRepository is a mock implementation of BusinessQuickReplyRepository
+type MockBusinessQuickReplyRepository struct {
+	mock.Mock
+}
+
+func (m *MockBusinessQuickReplyRepository) Create(ctx context.Context, reply *models.BusinessQuickReply) error {
+	args := m.Called(ctx, reply)
+	return args.Error(0)
+}
+
+func (m *MockBusinessQuickReplyRepository) GetByID(ctx context.Context, replyID string) (*models.BusinessQuickReply, error) {
+	args := m.Called(ctx, replyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BusinessQuickReply), args.Error(1)
+}
+
+func (m *MockBusinessQuickReplyRepository) Update(ctx context.Context, reply *models.BusinessQuickReply) error {
+	args := m.Called(ctx, reply)
+	return args.Error(0)
+}
+
+func (m *MockBusinessQuickReplyRepository) Delete(ctx context.Context, replyID string) error {
+	args := m.Called(ctx, replyID)
+	return args.Error(0)
+}
+
+func (m *MockBusinessQuickReplyRepository) ListByBusiness(ctx context.Context, businessID string) ([]*models.BusinessQuickReply, error) {
+	args := m.Called(ctx, businessID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.BusinessQuickReply), args.Error(1)
+}
+
+func (m *MockBusinessQuickReplyRepository) CountByBusiness(ctx context.Context, businessID string) (int, error) {
+	args := m.Called(ctx, businessID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockBusinessQuickReplyRepository) GetAutoReply(ctx context.Context, businessID string) (*models.BusinessAutoReply, error) {
+	args := m.Called(ctx, businessID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BusinessAutoReply), args.Error(1)
+}
+
+func (m *MockBusinessQuickReplyRepository) SetAutoReply(ctx context.Context, reply *models.BusinessAutoReply) error {
+	args := m.Called(ctx, reply)
+	return args.Error(0)
+}
+
+func (m *MockBusinessQuickReplyRepository) ClaimAutoReply(ctx context.Context, conversationID string, cooldown time.Duration) (bool, error) {
+	args := m.Called(ctx, conversationID, cooldown)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockBusinessBranchRepository is a mock implementation of BusinessBranchRepository
+type MockBusinessBranchRepository struct {
+	mock.Mock
+}
+
+func (m *MockBusinessBranchRepository) Create(ctx context.Context, branch *models.BusinessBranch) error {
+	args := m.Called(ctx, branch)
+	return args.Error(0)
+}
+
+func (m *MockBusinessBranchRepository) GetByID(ctx context.Context, branchID string) (*models.BusinessBranch, error) {
+	args := m.Called(ctx, branchID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BusinessBranch), args.Error(1)
+}
+
+func (m *MockBusinessBranchRepository) Update(ctx context.Context, branch *models.BusinessBranch) error {
+	args := m.Called(ctx, branch)
+	return args.Error(0)
+}
+
+func (m *MockBusinessBranchRepository) Delete(ctx context.Context, branchID string) error {
+	args := m.Called(ctx, branchID)
+	return args.Error(0)
+}
+
+func (m *MockBusinessBranchRepository) ListByBusiness(ctx context.Context, businessID string) ([]*models.BusinessBranch, error) {
+	args := m.Called(ctx, businessID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.BusinessBranch), args.Error(1)
+}
+
+func (m *MockBusinessBranchRepository) CountByBusiness(ctx context.Context, businessID string) (int, error) {
+	args := m.Called(ctx, businessID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockBusinessBranchRepository) GetByPostIDs(ctx context.Context, postIDs []string) (map[string]*models.BusinessBranch, error) {
+	args := m.Called(ctx, postIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]*models.BusinessBranch), args.Error(1)
+}
+
+// MockUploadSessionRepository is a mock implementation of UploadSessionRepository
+type MockUploadSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockUploadSessionRepository) Create(ctx context.Context, session *models.UploadSession) error {
+	args := m.Called(ctx, session)
+	return args.Error(0)
+}
+
+func (m *MockUploadSessionRepository) GetByID(ctx context.Context, sessionID string) (*models.UploadSession, error) {
+	args := m.Called(ctx, sessionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UploadSession), args.Error(1)
+}
+
+func (m *MockUploadSessionRepository) AddFile(ctx context.Context, file *models.UploadSessionFile) error {
+	args := m.Called(ctx, file)
+	return args.Error(0)
+}
+
+func (m *MockUploadSessionRepository) ListFiles(ctx context.Context, sessionID string) ([]*models.UploadSessionFile, error) {
+	args := m.Called(ctx, sessionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.UploadSessionFile), args.Error(1)
+}
+
+func (m *MockUploadSessionRepository) Claim(ctx context.Context, sessionID, postID string, urls []string) (bool, error) {
+	args := m.Called(ctx, sessionID, postID, urls)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUploadSessionRepository) ListStaleFiles(ctx context.Context, cutoff time.Time, limit int) ([]*models.UploadSessionFile, error) {
+	args := m.Called(ctx, cutoff, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.UploadSessionFile), args.Error(1)
+}
+
+func (m *MockUploadSessionRepository) DeleteFiles(ctx context.Context, fileIDs []string) error {
+	args := m.Called(ctx, fileIDs)
+	return args.Error(0)
+}
+
+func (m *MockUploadSessionRepository) DeleteEmptySessions(ctx context.Context, cutoff time.Time) (int64, error) {
+	args := m.Called(ctx, cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockTokenRevocationRepository is a mock implementation of TokenRevocationRepository
+type MockTokenRevocationRepository struct {
+	mock.Mock
+}
+
+func (m *MockTokenRevocationRepository) AddBlacklisted(ctx context.Context, tokenID string, expiresAt time.Time, reason string) error {
+	args := m.Called(ctx, tokenID, expiresAt, reason)
+	return args.Error(0)
+}
+
+func (m *MockTokenRevocationRepository) ListBlacklisted(ctx context.Context) (map[string]time.Time, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]time.Time), args.Error(1)
+}
+
+func (m *MockTokenRevocationRepository) DeleteExpiredBlacklisted(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTokenRevocationRepository) SetTokensInvalidBefore(ctx context.Context, userID string, at time.Time) error {
+	args := m.Called(ctx, userID, at)
+	return args.Error(0)
+}
+
+func (m *MockTokenRevocationRepository) ListTokensInvalidBefore(ctx context.Context, since time.Time) (map[string]time.Time, error) {
+	args := m.Called(ctx, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]time.Time), args.Error(1)
+}
+
+func (m *MockTokenRevocationRepository) IsRevoked(ctx context.Context, tokenID, userID string, issuedAt time.Time) (bool, error) {
+	args := m.Called(ctx, tokenID, userID, issuedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockInviteRepository is a mock implementation of InviteRepository.
+type MockInviteRepository struct {
+	mock.Mock
+}
+
+func (m *MockInviteRepository) GetCode(ctx context.Context, userID string) (string, error) {
+	args := m.Called(ctx, userID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockInviteRepository) CreateCode(ctx context.Context, userID, code string) (string, error) {
+	args := m.Called(ctx, userID, code)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockInviteRepository) GetUserIDByCode(ctx context.Context, code string) (string, error) {
+	args := m.Called(ctx, code)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockInviteRepository) CreateReferral(ctx context.Context, referral *models.Referral) (bool, error) {
+	args := m.Called(ctx, referral)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockInviteRepository) CountReferrals(ctx context.Context, referrerID string) (int, error) {
+	args := m.Called(ctx, referrerID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockInviteRepository) TopReferrers(ctx context.Context, since *time.Time, limit int) ([]*models.TopReferrer, error) {
+	args := m.Called(ctx, since, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.TopReferrer), args.Error(1)
+}
+
+// MockStickerRepository is a mock implementation of StickerRepository.
+type MockStickerRepository struct {
+	mock.Mock
+}
+
+func (m *MockStickerRepository) ListPacks(ctx context.Context, includeInactive bool) ([]*models.StickerPack, error) {
+	args := m.Called(ctx, includeInactive)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.StickerPack), args.Error(1)
+}
+
+func (m *MockStickerRepository) CreatePack(ctx context.Context, pack *models.StickerPack) error {
+	args := m.Called(ctx, pack)
+	return args.Error(0)
+}
+
+func (m *MockStickerRepository) SetPackActive(ctx context.Context, packID string, active bool) error {
+	args := m.Called(ctx, packID, active)
+	return args.Error(0)
+}
+
+func (m *MockStickerRepository) DeletePack(ctx context.Context, packID string) error {
+	args := m.Called(ctx, packID)
+	return args.Error(0)
+}
+
+func (m *MockStickerRepository) GetSticker(ctx context.Context, stickerID string, activeOnly bool) (*models.Sticker, error) {
+	args := m.Called(ctx, stickerID, activeOnly)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Sticker), args.Error(1)
+}
+
+// MockSyncTombstoneRepository is a mock implementation of SyncTombstoneRepository.
+type MockSyncTombstoneRepository struct {
+	mock.Mock
+}
+
+func (m *MockSyncTombstoneRepository) DeletedSince(ctx context.Context, userID string, entity models.SyncEntity, since time.Time, limit int) ([]string, error) {
+	args := m.Called(ctx, userID, entity, since, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockSyncTombstoneRepository) PurgeOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	args := m.Called(ctx, cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockPostBroadcastRepository is a mock implementation of PostBroadcastRepository.
+type MockPostBroadcastRepository struct {
+	mock.Mock
+}
+
+func (m *MockPostBroadcastRepository) Create(ctx context.Context, b *models.PostBroadcast) error {
+	args := m.Called(ctx, b)
+	return args.Error(0)
+}
+
+func (m *MockPostBroadcastRepository) GetByID(ctx context.Context, id string) (*models.PostBroadcast, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PostBroadcast), args.Error(1)
+}
+
+func (m *MockPostBroadcastRepository) GetLatestByPost(ctx context.Context, postID string) (*models.PostBroadcast, error) {
+	args := m.Called(ctx, postID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PostBroadcast), args.Error(1)
+}
+
+func (m *MockPostBroadcastRepository) List(ctx context.Context, status *string, limit, offset int) ([]*models.PostBroadcastListItem, int, error) {
+	args := m.Called(ctx, status, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.PostBroadcastListItem), args.Int(1), args.Error(2)
+}
+
+func (m *MockPostBroadcastRepository) Review(ctx context.Context, id, reviewerID, status string, reason *string) error {
+	args := m.Called(ctx, id, reviewerID, status, reason)
+	return args.Error(0)
+}
+
+func (m *MockPostBroadcastRepository) NextToSend(ctx context.Context) (*models.PostBroadcast, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PostBroadcast), args.Error(1)
+}
+
+func (m *MockPostBroadcastRepository) RecordProgress(ctx context.Context, id, lastUserID string, sent int) error {
+	args := m.Called(ctx, id, lastUserID, sent)
+	return args.Error(0)
+}
+
+func (m *MockPostBroadcastRepository) MarkSent(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPostBroadcastRepository) CountRecipients(ctx context.Context, lat, lng, radiusKm, excludeRadiusKm float64, excludeUserID string) (int, error) {
+	args := m.Called(ctx, lat, lng, radiusKm, excludeRadiusKm, excludeUserID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockPostBroadcastRepository) RecipientsAfter(ctx context.Context, b *models.PostBroadcast, excludeUserID string, afterUserID *string, limit int) ([]string, error) {
+	args := m.Called(ctx, b, excludeUserID, afterUserID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// MockRegionalTrendingRepository is a mock implementation of RegionalTrendingRepository.
+type MockRegionalTrendingRepository struct {
+	mock.Mock
+}
+
+func (m *MockRegionalTrendingRepository) Rollup(ctx context.Context, since time.Time, perKind int) error {
+	args := m.Called(ctx, since, perKind)
+	return args.Error(0)
+}
+
+func (m *MockRegionalTrendingRepository) GetActivity(ctx context.Context, province, district string) (*models.RegionalActivity, error) {
+	args := m.Called(ctx, province, district)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RegionalActivity), args.Error(1)
+}
+
+func (m *MockRegionalTrendingRepository) ListActivity(ctx context.Context) ([]*models.RegionalActivity, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.RegionalActivity), args.Error(1)
+}
+
+func (m *MockRegionalTrendingRepository) ListPosts(ctx context.Context, province, district, kind string, limit int) ([]*models.TrendingPost, error) {
+	args := m.Called(ctx, province, district, kind, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.TrendingPost), args.Error(1)
+}
+
+func (m *MockRegionalTrendingRepository) ListBusinesses(ctx context.Context, province, district string, limit int) ([]*models.TrendingBusiness, error) {
+	args := m.Called(ctx, province, district, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.TrendingBusiness), args.Error(1)
+}
+
+func (m *MockRegionalTrendingRepository) DigestRecipients(ctx context.Context, week string, afterUserID *string, limit int) ([]*models.DigestRecipient, error) {
+	args := m.Called(ctx, week, afterUserID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.DigestRecipient), args.Error(1)
+}
+
+// MockStorageQuotaRepository is a mock implementation of StorageQuotaRepository.
+type MockStorageQuotaRepository struct {
+	mock.Mock
+}
+
+func (m *MockStorageQuotaRepository) GetUsage(ctx context.Context, userID string) (*models.StorageUsage, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StorageUsage), args.Error(1)
+}
+
+func (m *MockStorageQuotaRepository) Record(ctx context.Context, obj *models.StorageObject) error {
+	args := m.Called(ctx, obj)
+	return args.Error(0)
+}
+
+func (m *MockStorageQuotaRepository) Release(ctx context.Context, url string) error {
+	args := m.Called(ctx, url)
+	return args.Error(0)
+}
+
+func (m *MockStorageQuotaRepository) List(ctx context.Context, filter *models.StorageObjectFilter) ([]*models.StorageObject, int, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.StorageObject), args.Int(1), args.Error(2)
+}
+
+func (m *MockStorageQuotaRepository) Get(ctx context.Context, userID, id string) (*models.StorageObject, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StorageObject), args.Error(1)
+}
+
+// MockStorageReconcileRepository is a mock implementation of StorageReconcileRepository.
+type MockStorageReconcileRepository struct {
+	mock.Mock
+}
+
+func (m *MockStorageReconcileRepository) ReferencedKeys(ctx context.Context, keys []string, deletedBefore time.Time) (map[string]bool, error) {
+	args := m.Called(ctx, keys, deletedBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]bool), args.Error(1)
+}
+
+func (m *MockStorageReconcileRepository) RecordRun(ctx context.Context, run *models.StorageReconcileRun) error {
+	args := m.Called(ctx, run)
+	return args.Error(0)
+}
+
+func (m *MockStorageReconcileRepository) ListRuns(ctx context.Context, limit int) ([]*models.StorageReconcileRun, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.StorageReconcileRun), args.Error(1)
+}
+
+func (m *MockStorageReconcileRepository) Totals(ctx context.Context) (int64, int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Get(1).(int64), args.Error(2)
+}
+
+// MockCalendarFeedRepository is a mock implementation of CalendarFeedRepository
+type MockCalendarFeedRepository struct {
+	mock.Mock
+}
+
+func (m *MockCalendarFeedRepository) SetToken(ctx context.Context, userID, tokenHash string) error {
+	args := m.Called(ctx, userID, tokenHash)
+	return args.Error(0)
+}
+
+func (m *MockCalendarFeedRepository) DeleteToken(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockCalendarFeedRepository) UserIDByToken(ctx context.Context, tokenHash string) (string, error) {
+	args := m.Called(ctx, tokenHash)
+	return args.String(0), args.Error(1)
+}
+
+// MockProfileMediaRepository is a mock implementation of ProfileMediaRepository
+type MockProfileMediaRepository struct {
+	mock.Mock
+}
+
+func (m *MockProfileMediaRepository) Submit(ctx context.Context, review *models.ProfileMediaReview) error {
+	args := m.Called(ctx, review)
+	return args.Error(0)
+}
+
+func (m *MockProfileMediaRepository) CancelPending(ctx context.Context, ownerType, ownerID, kind string) error {
+	args := m.Called(ctx, ownerType, ownerID, kind)
+	return args.Error(0)
+}
+
+func (m *MockProfileMediaRepository) GetByID(ctx context.Context, id string) (*models.ProfileMediaReview, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ProfileMediaReview), args.Error(1)
+}
+
+func (m *MockProfileMediaRepository) List(ctx context.Context, status string, flaggedOnly bool, limit int) ([]*models.ProfileMediaReview, error) {
+	args := m.Called(ctx, status, flaggedOnly, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ProfileMediaReview), args.Error(1)
+}
+
+func (m *MockProfileMediaRepository) Counts(ctx context.Context) (map[string]int64, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]int64), args.Error(1)
+}
+
+func (m *MockProfileMediaRepository) Resolve(ctx context.Context, id, status string, reviewerID *string, notes string) error {
+	args := m.Called(ctx, id, status, reviewerID, notes)
+	return args.Error(0)
+}
+
+// MockAccountRecoveryRepository is a mock implementation of AccountRecoveryRepository
+type MockAccountRecoveryRepository struct {
+	mock.Mock
+}
+
+func (m *MockAccountRecoveryRepository) SetTrustedContact(ctx context.Context, userID, contactID string) error {
+	args := m.Called(ctx, userID, contactID)
+	return args.Error(0)
+}
+
+func (m *MockAccountRecoveryRepository) GetTrustedContact(ctx context.Context, userID string) (string, time.Time, error) {
+	args := m.Called(ctx, userID)
+	return args.String(0), args.Get(1).(time.Time), args.Error(2)
+}
+
+func (m *MockAccountRecoveryRepository) DeleteTrustedContact(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockAccountRecoveryRepository) CreateRecovery(ctx context.Context, recovery *models.AccountRecovery) error {
+	args := m.Called(ctx, recovery)
+	return args.Error(0)
+}
+
+func (m *MockAccountRecoveryRepository) GetRecovery(ctx context.Context, id string) (*models.AccountRecovery, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AccountRecovery), args.Error(1)
+}
+
+func (m *MockAccountRecoveryRepository) ListRecoveries(ctx context.Context, status string, limit int) ([]*models.AccountRecovery, error) {
+	args := m.Called(ctx, status, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.AccountRecovery), args.Error(1)
+}
+
+func (m *MockAccountRecoveryRepository) CancelPendingRecovery(ctx context.Context, userID string) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAccountRecoveryRepository) RecordFailedAttempt(ctx context.Context, id string) (int, error) {
+	args := m.Called(ctx, id)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAccountRecoveryRepository) ResolveRecovery(ctx context.Context, id, status string, reviewerID *string, notes string, oldEmail, newEmail *string) error {
+	args := m.Called(ctx, id, status, reviewerID, notes, oldEmail, newEmail)
+	return args.Error(0)
+}
+
+// MockOAuthRevocationRepository is a mock implementation of OAuthRevocationRepository
+type MockOAuthRevocationRepository struct {
+	mock.Mock
+}
+
+func (m *MockOAuthRevocationRepository) Mark(ctx context.Context, userID, provider, reason string) error {
+	args := m.Called(ctx, userID, provider, reason)
+	return args.Error(0)
+}
+
+func (m *MockOAuthRevocationRepository) Get(ctx context.Context, userID string) (*models.OAuthRevocation, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OAuthRevocation), args.Error(1)
+}
+
+func (m *MockOAuthRevocationRepository) Clear(ctx context.Context, userID string) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockBusinessResponseStatsRepository is a mock implementation of BusinessResponseStatsRepository
+type MockBusinessResponseStatsRepository struct {
+	mock.Mock
+}
+
+func (m *MockBusinessResponseStatsRepository) Get(ctx context.Context, businessID string) (*models.BusinessResponseStats, error) {
+	args := m.Called(ctx, businessID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BusinessResponseStats), args.Error(1)
+}
+
+func (m *MockBusinessResponseStatsRepository) Rollup(ctx context.Context, since time.Time) (int64, error) {
+	args := m.Called(ctx, since)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockFeaturedBusinessRepository is a mock implementation of FeaturedBusinessRepository
+type MockFeaturedBusinessRepository struct {
+	mock.Mock
+}
+
+func (m *MockFeaturedBusinessRepository) Create(ctx context.Context, placement *models.FeaturedBusiness) error {
+	args := m.Called(ctx, placement)
+	return args.Error(0)
+}
+
+func (m *MockFeaturedBusinessRepository) GetByID(ctx context.Context, id string) (*models.FeaturedBusiness, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.FeaturedBusiness), args.Error(1)
+}
+
+func (m *MockFeaturedBusinessRepository) Update(ctx context.Context, placement *models.FeaturedBusiness) error {
+	args := m.Called(ctx, placement)
+	return args.Error(0)
+}
+
+func (m *MockFeaturedBusinessRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockFeaturedBusinessRepository) List(ctx context.Context, filter models.FeaturedBusinessFilter, limit, offset int) ([]*models.FeaturedBusiness, int, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.FeaturedBusiness), args.Int(1), args.Error(2)
+}
+
+func (m *MockFeaturedBusinessRepository) ListLive(ctx context.Context, province string, limit int) ([]*models.FeaturedBusiness, error) {
+	args := m.Called(ctx, province, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.FeaturedBusiness), args.Error(1)
+}
+
+func (m *MockFeaturedBusinessRepository) IncrementImpression(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockFeaturedBusinessRepository) IncrementClick(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockProfileViewRepository is a mock implementation of ProfileViewRepository
+type MockProfileViewRepository struct {
+	mock.Mock
+}
+
+func (m *MockProfileViewRepository) IncrementProfileView(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockProfileViewRepository) GetDailyProfileViews(ctx context.Context, userID string, days int) ([]models.DailyCount, error) {
+	args := m.Called(ctx, userID, days)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DailyCount), args.Error(1)
+}
+
+func (m *MockProfileViewRepository) RecordBusinessViewer(ctx context.Context, businessID, viewerID string) error {
+	args := m.Called(ctx, businessID, viewerID)
+	return args.Error(0)
+}
+
+func (m *MockProfileViewRepository) ListBusinessViewers(ctx context.Context, businessID string, days, limit, offset int) ([]*models.BusinessViewer, int, int, error) {
+	args := m.Called(ctx, businessID, days, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Int(2), args.Error(3)
+	}
+	return args.Get(0).([]*models.BusinessViewer), args.Int(1), args.Int(2), args.Error(3)
+}
+
+func (m *MockProfileViewRepository) GetDailyUniqueViewers(ctx context.Context, businessID string, days int) ([]models.DailyCount, error) {
+	args := m.Called(ctx, businessID, days)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DailyCount), args.Error(1)
+}
+
+func (m *MockProfileViewRepository) DeleteBusinessViewersBefore(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockBusinessImportRepository is a mock implementation of BusinessImportRepository
+type MockBusinessImportRepository struct {
+	mock.Mock
+}
+
+func (m *MockBusinessImportRepository) CreateJob(ctx context.Context, job *models.BusinessImportJob) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *MockBusinessImportRepository) GetJob(ctx context.Context, id string) (*models.BusinessImportJob, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BusinessImportJob), args.Error(1)
+}
+
+func (m *MockBusinessImportRepository) ListJobs(ctx context.Context, status *string, limit, offset int) ([]*models.BusinessImportJob, int, error) {
+	args := m.Called(ctx, status, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.BusinessImportJob), args.Int(1), args.Error(2)
+}
+
+func (m *MockBusinessImportRepository) NextToProcess(ctx context.Context) (*models.BusinessImportJob, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BusinessImportJob), args.Error(1)
+}
+
+func (m *MockBusinessImportRepository) RecordRow(ctx context.Context, id string, created, invited bool, rowErrs []models.BusinessImportRowError) error {
+	args := m.Called(ctx, id, created, invited, rowErrs)
+	return args.Error(0)
+}
+
+func (m *MockBusinessImportRepository) Finish(ctx context.Context, id, status string, errMsg *string) error {
+	args := m.Called(ctx, id, status, errMsg)
+	return args.Error(0)
+}
+
+func (m *MockBusinessImportRepository) ExistingLicenses(ctx context.Context, licenses []string) (map[string]bool, error) {
+	args := m.Called(ctx, licenses)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]bool), args.Error(1)
+}
+
+func (m *MockBusinessImportRepository) CreateInvite(ctx context.Context, invite *models.BusinessOwnerInvite) error {
+	args := m.Called(ctx, invite)
+	return args.Error(0)
+}
+
+func (m *MockBusinessImportRepository) GetInviteByTokenHash(ctx context.Context, tokenHash string) (*models.BusinessOwnerInvite, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BusinessOwnerInvite), args.Error(1)
+}
+
+func (m *MockBusinessImportRepository) ClaimInvite(ctx context.Context, invite *models.BusinessOwnerInvite, userID string) error {
+	args := m.Called(ctx, invite, userID)
+	return args.Error(0)
+}
+
+// MockBusinessClaimRepository is a mock implementation of BusinessClaimRepository
+type MockBusinessClaimRepository struct {
+	mock.Mock
+}
+
+func (m *MockBusinessClaimRepository) Create(ctx context.Context, claim *models.BusinessClaimRequest) error {
+	args := m.Called(ctx, claim)
+	return args.Error(0)
+}
+
+func (m *MockBusinessClaimRepository) GetByID(ctx context.Context, id string) (*models.BusinessClaimRequest, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BusinessClaimRequest), args.Error(1)
+}
+
+func (m *MockBusinessClaimRepository) GetLatestForUser(ctx context.Context, businessID, userID string) (*models.BusinessClaimRequest, error) {
+	args := m.Called(ctx, businessID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BusinessClaimRequest), args.Error(1)
+}
+
+func (m *MockBusinessClaimRepository) List(ctx context.Context, status *string, limit, offset int) ([]*models.BusinessClaimListItem, int, error) {
+	args := m.Called(ctx, status, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*models.BusinessClaimListItem), args.Int(1), args.Error(2)
+}
+
+func (m *MockBusinessClaimRepository) Approve(ctx context.Context, claim *models.BusinessClaimRequest, reviewerID string, reason *string, supersededReason string) ([]*models.BusinessClaimRequest, error) {
+	args := m.Called(ctx, claim, reviewerID, reason, supersededReason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.BusinessClaimRequest), args.Error(1)
+}
+
+func (m *MockBusinessClaimRepository) Reject(ctx context.Context, id, reviewerID string, reason *string) error {
+	args := m.Called(ctx, id, reviewerID, reason)
+	return args.Error(0)
+}
+
+func (m *MockBusinessClaimRepository) ListOwnershipHistory(ctx context.Context, businessID string) ([]*models.BusinessOwnershipChange, error) {
+	args := m.Called(ctx, businessID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.BusinessOwnershipChange), args.Error(1)
+}
+
+// MockLinkPreviewRepository is a mock implementation of LinkPreviewRepository
+type MockLinkPreviewRepository struct {
+	mock.Mock
+}
+
+func (m *MockLinkPreviewRepository) GetByHash(ctx context.Context, urlHash string) (*models.LinkPreview, error) {
+	args := m.Called(ctx, urlHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LinkPreview), args.Error(1)
+}
+
+func (m *MockLinkPreviewRepository) Upsert(ctx context.Context, urlHash string, preview *models.LinkPreview) error {
+	args := m.Called(ctx, urlHash, preview)
+	return args.Error(0)
+}
+
+func (m *MockLinkPreviewRepository) AttachToPost(ctx context.Context, postID, urlHash string) error {
+	args := m.Called(ctx, postID, urlHash)
+	return args.Error(0)
+}
+
+func (m *MockLinkPreviewRepository) DetachFromPost(ctx context.Context, postID string) error {
+	args := m.Called(ctx, postID)
+	return args.Error(0)
+}
+
+func (m *MockLinkPreviewRepository) GetByPostIDs(ctx context.Context, postIDs []string) (map[string]*models.LinkPreview, error) {
+	args := m.Called(ctx, postIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]*models.LinkPreview), args.Error(1)
+}
+
+// MockLifecycleTemplateRepository is a mock implementation of LifecycleTemplateRepository
+type MockLifecycleTemplateRepository struct {
+	mock.Mock
+}
+
+func (m *MockLifecycleTemplateRepository) List(ctx context.Context) ([]*models.LifecycleTemplate, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.LifecycleTemplate), args.Error(1)
+}
+
+func (m *MockLifecycleTemplateRepository) Get(ctx context.Context, kind models.NotificationType) (*models.LifecycleTemplate, error) {
+	args := m.Called(ctx, kind)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LifecycleTemplate), args.Error(1)
+}
+
+func (m *MockLifecycleTemplateRepository) Upsert(ctx context.Context, t *models.LifecycleTemplate) error {
+	args := m.Called(ctx, t)
+	return args.Error(0)
+}
+
+func (m *MockLifecycleTemplateRepository) Delete(ctx context.Context, kind models.NotificationType) error {
+	args := m.Called(ctx, kind)
+	return args.Error(0)
 }
@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// Account recovery statuses.
+const (
+	AccountRecoveryPending   = "pending"
+	AccountRecoveryCompleted = "completed"
+	AccountRecoveryRejected  = "rejected"
+	AccountRecoveryCancelled = "cancelled"
+)
+
+// SetTrustedContactRequest names the user who receives recovery codes for
+// the caller's account.
+type SetTrustedContactRequest struct {
+	ContactUserID string `json:"contact_user_id" validate:"required,uuid"`
+	// Password confirms the change; not needed for accounts without one.
+	Password string `json:"password" validate:"omitempty,max=128"`
+}
+
+// TrustedContactResponse is the caller's trusted contact.
+type TrustedContactResponse struct {
+	UserID   string    `json:"user_id"`
+	FullName string    `json:"full_name"`
+	Handle   *string   `json:"handle,omitempty"`
+	Avatar   *Photo    `json:"avatar,omitempty"`
+	Since    time.Time `json:"since"`
+}
+
+// StartAccountRecoveryRequest starts recovering the account registered
+// with Email through its trusted contact.
+type StartAccountRecoveryRequest struct {
+	Email string `json:"email" validate:"required,email,max=320"`
+}
+
+// CompleteAccountRecoveryRequest is support's completion of a recovery:
+// the code the user got from their trusted contact and the address the
+// account moves to.
+type CompleteAccountRecoveryRequest struct {
+	Code     string `json:"code" validate:"required,max=32"`
+	NewEmail string `json:"new_email" validate:"required,email,max=255"`
+	Notes    string `json:"notes" validate:"required,max=1000"`
+}
+
+// AccountRecovery is a trusted contact recovery request.
+type AccountRecovery struct {
+	ID             string     `json:"id"`
+	UserID         string     `json:"user_id"`
+	UserEmail      string     `json:"user_email"`
+	ContactID      *string    `json:"contact_id,omitempty"`
+	ContactEmail   *string    `json:"contact_email,omitempty"`
+	CodeHash       string     `json:"-"`
+	FailedAttempts int        `json:"failed_attempts"`
+	RequestedIP    *string    `json:"requested_ip,omitempty"`
+	RequestedAt    time.Time  `json:"requested_at"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	Status         string     `json:"status"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+	ReviewedBy     *string    `json:"reviewed_by,omitempty"`
+	ReviewNotes    *string    `json:"review_notes,omitempty"`
+	OldEmail       *string    `json:"old_email,omitempty"`
+	NewEmail       *string    `json:"new_email,omitempty"`
+}
@@ -59,6 +59,9 @@ type EngagementAnalytics struct {
 	TotalLikes       int64            `json:"total_likes"`
 	TotalComments    int64            `json:"total_comments"`
 	TotalShares      int64            `json:"total_shares"`
+	// TotalExternalShares counts short links handed out for sharing outside
+	// the app; TotalShares is in-app reposts only.
+	TotalExternalShares int64 `json:"total_external_shares"`
 }
 
 // AdminProvinceUserCount is one row in the per-province user breakdown
@@ -139,6 +142,11 @@ type AdminUserResponse struct {
 	IsSuspended   bool       `json:"is_suspended"`
 	LockedUntil   *time.Time `json:"locked_until,omitempty"`
 	LastLoginAt   *time.Time `json:"last_login_at,omitempty"`
+	// Shadowbanned users keep posting normally but their content is hidden
+	// from everyone else. ShadowbanReason is only loaded on the detail view.
+	IsShadowbanned  bool       `json:"is_shadowbanned"`
+	ShadowbannedAt  *time.Time `json:"shadowbanned_at,omitempty"`
+	ShadowbanReason *string    `json:"shadowban_reason,omitempty"`
 	CreatedAt      time.Time `json:"created_at"`
 	PostsCount     int64     `json:"posts_count"`
 	FollowersCount int64     `json:"followers_count"`
@@ -157,6 +165,8 @@ type AdminUserDetailResponse struct {
 	BusinessCount  int64                    `json:"business_count"`
 	RecentPosts    []AdminPostResponse      `json:"recent_posts"`
 	Businesses     []AdminBusinessResponse  `json:"businesses"`
+	// EmailDelivery is nil when delivery tracking is disabled.
+	EmailDelivery *AdminEmailDeliveryInfo `json:"email_delivery,omitempty"`
 }
 
 // AdminPostFilter contains filters for listing posts in admin panel
@@ -380,6 +390,7 @@ type AdminReportFilter struct {
 	CommentID  string `form:"comment_id"`  // filter by comment (for comment reports)
 	UserID     string `form:"user_id"`     // filter by reported user (for user reports)
 	BusinessID string `form:"business_id"` // filter by business (for business reports)
+	MessageID  string `form:"message_id"`  // filter by chat message (for message reports)
 	Status     string `form:"status"`
 	SortBy     string `form:"sort_by"`
 	SortDir    string `form:"sort_dir"`
@@ -458,6 +469,114 @@ type AdminBusinessReportResponse struct {
 	CreatedAt          time.Time `json:"created_at"`
 }
 
+// AdminMessageReportResponse is the chat message report data for admin API.
+// MessageContent is the snapshot taken when the report was filed;
+// MessageDeleted tells whether the message is gone from the conversation.
+type AdminMessageReportResponse struct {
+	ID                 string    `json:"id"`
+	MessageID          string    `json:"message_id"`
+	ConversationID     string    `json:"conversation_id"`
+	MessageContent     *string   `json:"message_content,omitempty"`
+	MessageType        string    `json:"message_type"`
+	MessageSentAt      time.Time `json:"message_sent_at"`
+	MessageDeleted     bool      `json:"message_deleted"`
+	SenderID           string    `json:"sender_id"`
+	SenderEmail        string    `json:"sender_email"`
+	ReporterID         string    `json:"reporter_id"`
+	ReporterEmail      string    `json:"reporter_email"`
+	Reason             string    `json:"reason"`
+	AdditionalComments *string   `json:"additional_comments,omitempty"`
+	Status             string    `json:"status"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// AdminReportContext is what a moderator needs next to a report to act on
+// it without opening other screens. Author is the reported content's author
+// (the reported user for user reports, the owner for businesses, the sender
+// for messages); nil when the account no longer exists.
+type AdminReportContext struct {
+	Author *AdminUserResponse `json:"author,omitempty"`
+	// PriorViolations counts the author's other content that had a report
+	// upheld against it.
+	PriorViolations  int64               `json:"prior_violations"`
+	OtherReportCount int64               `json:"other_report_count"` // other reports on the same item, all statuses
+	Actions          []AdminReportAction `json:"actions"`
+}
+
+// AdminReportAction is an admin API call a moderator can make from the report
+// detail. Path is relative to /api/v1. AdminOnly actions are rejected for
+// moderators.
+type AdminReportAction struct {
+	Action    string `json:"action"` // e.g. resolve_report, hide_post, suspend_author
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	AdminOnly bool   `json:"admin_only"`
+}
+
+// AdminPostReportDetail is a post report with the post as it is now.
+type AdminPostReportDetail struct {
+	AdminPostReportResponse
+	Post         *AdminPostDetailResponse   `json:"post,omitempty"`
+	OtherReports []*AdminPostReportResponse `json:"other_reports"`
+	AdminReportContext
+}
+
+// AdminCommentReportDetail is a comment report with the comment as it is now.
+type AdminCommentReportDetail struct {
+	AdminCommentReportResponse
+	Comment      *AdminCommentDetailResponse   `json:"comment,omitempty"`
+	OtherReports []*AdminCommentReportResponse `json:"other_reports"`
+	AdminReportContext
+}
+
+// AdminUserReportDetail is a user report; the reported account is Author.
+type AdminUserReportDetail struct {
+	AdminUserReportResponse
+	OtherReports []*AdminUserReportResponse `json:"other_reports"`
+	AdminReportContext
+}
+
+// AdminBusinessReportDetail is a business report with the business as it is now.
+type AdminBusinessReportDetail struct {
+	AdminBusinessReportResponse
+	Business     *AdminBusinessDetailResponse   `json:"business,omitempty"`
+	OtherReports []*AdminBusinessReportResponse `json:"other_reports"`
+	AdminReportContext
+}
+
+// AdminMessageReportDetail is a message report; the message snapshot is
+// already on the report.
+type AdminMessageReportDetail struct {
+	AdminMessageReportResponse
+	OtherReports []*AdminMessageReportResponse `json:"other_reports"`
+	AdminReportContext
+}
+
+// AdminReportGroup is one reported item in the grouped report listing,
+// with all of its reports collapsed into a single row.
+type AdminReportGroup struct {
+	TargetID        string              `json:"target_id"`
+	TargetLabel     string              `json:"target_label"` // post title, comment or message text, user email or business name
+	TargetOwnerID   *string             `json:"target_owner_id,omitempty"`
+	ReportCount     int                 `json:"report_count"`
+	ReporterCount   int                 `json:"reporter_count"`
+	OpenCount       int                 `json:"open_count"` // reports not yet resolved or rejected
+	Reasons         []ReportReasonCount `json:"reasons"`
+	FirstReportedAt time.Time           `json:"first_reported_at"`
+	LastReportedAt  time.Time           `json:"last_reported_at"`
+}
+
+// ReportReasonCount is how many of an item's reports gave Reason.
+type ReportReasonCount struct {
+	Reason string `json:"reason"`
+	Count  int    `json:"count"`
+}
+
+// AdminResolveReportGroupRequest closes every open report on one item.
+type AdminResolveReportGroupRequest struct {
+	Status string `json:"status" binding:"required,oneof=RESOLVED REJECTED"`
+}
+
 // UpdateUserRoleRequest is the request to update a user's role
 type UpdateUserRoleRequest struct {
 	Role string `json:"role" binding:"required,oneof=user admin moderator"`
@@ -503,6 +622,12 @@ type UpdateBusinessStatusRequest struct {
 	Status string `json:"status" binding:"required,oneof=ACTIVE PENDING SUSPENDED REJECTED"`
 }
 
+// SetBusinessCategoriesRequest replaces a business's categories; an empty
+// list clears them.
+type SetBusinessCategoriesRequest struct {
+	CategoryIDs []string `json:"category_ids" binding:"max=10,dive,uuid"`
+}
+
 // BroadcastNotificationRequest is the request to send a broadcast notification
 //
 // Targeting precedence (most-specific wins):
@@ -531,6 +656,7 @@ type AdminInboxCounts struct {
 	CommentReports  int64      `json:"comment_reports"`
 	UserReports     int64      `json:"user_reports"`
 	BusinessReports int64      `json:"business_reports"`
+	MessageReports  int64      `json:"message_reports"`
 	OpenFeedback    int64      `json:"open_feedback"`
 	UnansweredHelp  int64      `json:"unanswered_help"`
 	Total           int64      `json:"total"`
@@ -18,6 +18,7 @@ type RegisterRequest struct {
 	Latitude   float64  `json:"latitude,omitempty" validate:"omitempty,latitude"`
 	Longitude  float64  `json:"longitude,omitempty" validate:"omitempty,longitude"`
 	DeviceInfo *string  `json:"device_info,omitempty" validate:"omitempty,max=512"`
+	InviteCode *string  `json:"invite_code,omitempty" validate:"omitempty,max=32"` // Referrer's invite code, if any
 	IPAddress  *string  `json:"-"` // Set from request context
 	UserAgent  *string  `json:"-"` // Set from request context
 }
@@ -41,6 +42,7 @@ type UnifiedAuthRequest struct {
 	Latitude   *float64 `json:"latitude,omitempty" validate:"omitempty,latitude"`
 	Longitude  *float64 `json:"longitude,omitempty" validate:"omitempty,longitude"`
 	DeviceInfo *string  `json:"device_info,omitempty" validate:"omitempty,max=512"`
+	InviteCode *string  `json:"invite_code,omitempty" validate:"omitempty,max=32"` // Referrer's invite code, if any
 	IPAddress  *string  `json:"-"` // Set from request context
 	UserAgent  *string  `json:"-"` // Set from request context
 }
@@ -97,6 +99,31 @@ type ChangePasswordRequest struct {
 	NewPassword     string `json:"new_password" validate:"required,min=8,max=128"`
 }
 
+// ChangeEmailRequest starts an email change. Password is required for
+// accounts that have one.
+type ChangeEmailRequest struct {
+	NewEmail string `json:"new_email" validate:"required,email,max=255"`
+	Password string `json:"password" validate:"max=128"`
+}
+
+// ConfirmEmailChangeRequest completes an email change with the code sent to
+// the new address.
+type ConfirmEmailChangeRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// RevertEmailChangeRequest undoes an email change with the token from the
+// notice sent to the old address.
+type RevertEmailChangeRequest struct {
+	Token string `json:"token" validate:"required,max=128"`
+}
+
+// EmailChangeResponse describes a pending email change.
+type EmailChangeResponse struct {
+	NewEmail  string    `json:"new_email"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
 // VerifyEmailRequest represents an email verification request
 type VerifyEmailRequest struct {
 	Token string `json:"token" validate:"required,max=4096"`
@@ -169,15 +196,44 @@ type LoginResponse struct {
 // JWTClaims represents the claims in a JWT token.
 // JTI is the unique token identifier used by the access-token denylist
 // (set on /auth/logout so the access token cannot be replayed before expiry).
+// IssuedAtMs is iat in milliseconds, compared against the logout-all cutoff
+// so a token issued in the same second as the cutoff isn't caught by it.
 type JWTClaims struct {
-	UserID    string `json:"user_id"`
-	Email     string `json:"email"`
-	AAL       int    `json:"aal"` // Authentication Assurance Level (1 or 2)
-	SessionID string `json:"session_id"`
-	JTI       string `json:"jti"`
-	IssuedAt  int64  `json:"iat"`
-	ExpiresAt int64  `json:"exp"`
-	Issuer    string `json:"iss"`
+	UserID     string `json:"user_id"`
+	Email      string `json:"email"`
+	AAL        int    `json:"aal"` // Authentication Assurance Level (1 or 2)
+	SessionID  string `json:"session_id"`
+	JTI        string `json:"jti"`
+	IssuedAt   int64  `json:"iat"`
+	IssuedAtMs int64  `json:"iat_ms,omitempty"`
+	ExpiresAt  int64  `json:"exp"`
+	Issuer     string `json:"iss"`
+}
+
+// IssuedAtMillis returns when the token was issued, in Unix milliseconds.
+// Tokens minted before iat_ms existed only know the second.
+func (c *JWTClaims) IssuedAtMillis() int64 {
+	if c.IssuedAtMs > 0 {
+		return c.IssuedAtMs
+	}
+	return c.IssuedAt * 1000
+}
+
+// JWKS is the JSON Web Key Set served at /.well-known/jwks.json (RFC 7517)
+// so other services can verify access tokens without the signing secret.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK is one RSA public signing key. Modulus and exponent are unpadded
+// base64url, as RFC 7518 requires.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
 }
 
 // AAL (Authentication Assurance Level)
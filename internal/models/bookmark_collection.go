@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// BookmarkCollectionNone filters GET /users/me/bookmarks to bookmarks that
+// aren't filed in any collection.
+const BookmarkCollectionNone = "none"
+
+// BookmarkCollection is a user's named folder of bookmarked posts.
+type BookmarkCollection struct {
+	ID            string    `json:"id"`
+	UserID        string    `json:"user_id"`
+	Name          string    `json:"name"`
+	BookmarkCount int       `json:"bookmark_count"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// BookmarkCollectionsResponse lists a user's collections with per-collection
+// counts, plus the overall and unfiled bookmark totals.
+type BookmarkCollectionsResponse struct {
+	Collections  []*BookmarkCollection `json:"collections"`
+	TotalCount   int                   `json:"total_count"`
+	UnfiledCount int                   `json:"unfiled_count"`
+}
+
+// BookmarkCollectionRequest creates or renames a collection.
+type BookmarkCollectionRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=60"`
+}
+
+// AssignBookmarkRequest moves a bookmark into a collection; a null
+// collection_id unfiles it.
+type AssignBookmarkRequest struct {
+	CollectionID *string `json:"collection_id" validate:"omitempty,uuid"`
+}
@@ -36,6 +36,12 @@ type BusinessProfile struct {
 	CreatedAt       time.Time     `json:"created_at"`
 	UpdatedAt       time.Time     `json:"updated_at"`
 	DeletedAt       *time.Time    `json:"-"`
+
+	// Set only by location-aware search/discover queries: distance from the
+	// search point to the nearer of the main address and the nearest branch,
+	// and that branch when it is the nearer one.
+	DistanceKm    *float64       `json:"-"`
+	NearestBranch *NearestBranch `json:"-"`
 }
 
 // BusinessCategory represents a business category
@@ -70,6 +76,18 @@ type BusinessHours struct {
 type GalleryItem struct {
 	ID    string `json:"id"`
 	Photo Photo  `json:"photo"`
+	// Position is the image's place in the gallery, from 0.
+	Position int     `json:"position"`
+	Caption  *string `json:"caption,omitempty"`
+}
+
+// GalleryPagination describes the gallery page embedded in the business
+// detail response. Same shape as the list endpoints' pagination meta.
+type GalleryPagination struct {
+	CurrentPage  int `json:"currentPage"`
+	ItemsPerPage int `json:"itemsPerPage"`
+	TotalItems   int `json:"totalItems"`
+	TotalPages   int `json:"totalPages"`
 }
 
 // BusinessAttachment represents a business gallery image
@@ -77,6 +95,8 @@ type BusinessAttachment struct {
 	ID                string     `json:"id"`
 	BusinessProfileID string     `json:"business_profile_id"`
 	Photo             Photo      `json:"photo"`
+	Position          int        `json:"position"`
+	Caption           *string    `json:"caption,omitempty"`
 	CreatedAt         time.Time  `json:"created_at"`
 	UpdatedAt         time.Time  `json:"updated_at"`
 	DeletedAt         *time.Time `json:"-"`
@@ -145,6 +165,10 @@ type UpdateBusinessRequest struct {
 	CategoryIDs    []string `json:"category_ids,omitempty" validate:"omitempty,dive,uuid"`
 	// CategoryNames are created if they don't exist, then linked (with category_ids).
 	CategoryNames []string `json:"category_names,omitempty" validate:"omitempty,dive,max=100"`
+	// AvatarAltText / CoverAltText set the alt text of the current images;
+	// an empty string clears it.
+	AvatarAltText *string `json:"avatar_alt_text,omitempty" validate:"omitempty,max=1000"`
+	CoverAltText  *string `json:"cover_alt_text,omitempty" validate:"omitempty,max=1000"`
 }
 
 // BusinessHoursRequest represents operating hours for a day
@@ -160,6 +184,33 @@ type SetBusinessHoursRequest struct {
 	Hours []BusinessHoursRequest `json:"hours" validate:"required,min=1,max=7"`
 }
 
+// FeaturePostRequest pins one of the business's posts to its profile.
+type FeaturePostRequest struct {
+	PostID string `json:"post_id" validate:"required,uuid"`
+}
+
+// ReorderFeaturedPostsRequest lists every featured post id in the new order.
+type ReorderFeaturedPostsRequest struct {
+	PostIDs []string `json:"post_ids" validate:"required,dive,uuid"`
+}
+
+// ReorderGalleryRequest lists every gallery image id in the new order.
+type ReorderGalleryRequest struct {
+	AttachmentIDs []string `json:"attachment_ids" validate:"required,min=1,dive,uuid"`
+}
+
+// UpdateGalleryCaptionRequest sets or clears (empty) a gallery image's
+// caption.
+type UpdateGalleryCaptionRequest struct {
+	Caption string `json:"caption" validate:"max=300"`
+}
+
+// SetCoverFromGalleryRequest makes one of the business's gallery images
+// its cover.
+type SetCoverFromGalleryRequest struct {
+	AttachmentID string `json:"attachment_id" validate:"required,uuid"`
+}
+
 // BusinessResponse represents a business profile in API responses
 type BusinessResponse struct {
 	ID              string                  `json:"id"`
@@ -187,7 +238,10 @@ type BusinessResponse struct {
 	TotalFollow     int                     `json:"total_follow"`
 	Categories      []BusinessCategory      `json:"categories"`
 	Hours           []BusinessHoursResponse `json:"hours,omitempty"`
-	Gallery         []GalleryItem           `json:"gallery,omitempty"`
+	Gallery         []GalleryItem           `json:"gallery,omitempty"`        // one page, in gallery order; detail endpoint only
+	GalleryMeta     *GalleryPagination      `json:"gallery_meta,omitempty"`   // paging of Gallery
+	FeaturedPosts   []*PostResponse         `json:"featured_posts,omitempty"` // pinned posts in the owner's order; detail endpoint only
+	Responsiveness  *BusinessResponsiveness `json:"responsiveness,omitempty"` // open now and usual reply time; detail endpoint only
 	IsFollowing     bool                    `json:"is_following"`
 	IsVerified      bool                    `json:"is_verified"`
 	CreatedAt       time.Time               `json:"created_at"`
@@ -238,15 +292,21 @@ type BusinessHoursResponse struct {
 
 // BusinessListFilter represents filters for listing businesses
 type BusinessListFilter struct {
-	UserID     *string  `json:"user_id,omitempty"`
-	CategoryID *string  `json:"category_id,omitempty"`
-	Province   *string  `json:"province,omitempty"`
-	Search     *string  `json:"search,omitempty"`
-	Latitude   *float64 `json:"latitude,omitempty"`
-	Longitude  *float64 `json:"longitude,omitempty"`
-	RadiusKm   *float64 `json:"radius_km,omitempty"`
-	Limit      int      `json:"limit"`
-	Offset     int      `json:"offset"`
+	UserID      *string  `json:"user_id,omitempty"`
+	CategoryIDs []string `json:"category_ids,omitempty"` // any of these categories
+	Province    *string  `json:"province,omitempty"`
+	Search      *string  `json:"search,omitempty"`
+	Latitude    *float64 `json:"latitude,omitempty"`
+	Longitude   *float64 `json:"longitude,omitempty"`
+	RadiusKm    *float64 `json:"radius_km,omitempty"`
+	Limit       int      `json:"limit"`
+	Offset      int      `json:"offset"`
+}
+
+// BusinessSearchFacets counts /businesses/search results per category,
+// largest first.
+type BusinessSearchFacets struct {
+	Categories []*FacetCount `json:"categories"`
 }
 
 // DailyCount is one point in an insights time-series.
@@ -259,15 +319,18 @@ type DailyCount struct {
 // series (zero-filled, oldest first) plus all-time totals for the header
 // numbers on the insight cards.
 type BusinessInsightsResponse struct {
-	Days       int          `json:"days"`
-	Views      []DailyCount `json:"views"`
-	Followers  []DailyCount `json:"followers"`
-	Reviews    []DailyCount `json:"reviews"`
-	Likes      []DailyCount `json:"likes"`       // likes on the business's posts
-	Comments   []DailyCount `json:"comments"`    // comments on the business's posts
-	PostViews  []DailyCount `json:"post_views"`  // unique post views ("reach")
-	Sold       []DailyCount `json:"sold"`        // owner's SELL listings marked sold
-	EventRSVPs []DailyCount `json:"event_rsvps"` // "going" RSVPs on the business's events
+	Days            int          `json:"days"`
+	Views           []DailyCount `json:"views"`
+	Followers       []DailyCount `json:"followers"`
+	Reviews         []DailyCount `json:"reviews"`
+	Likes           []DailyCount `json:"likes"`            // likes on the business's posts
+	Comments        []DailyCount `json:"comments"`         // comments on the business's posts
+	PostViews       []DailyCount `json:"post_views"`       // unique post views ("reach")
+	PostImpressions []DailyCount `json:"post_impressions"` // post views, once per viewer per post per day
+	Sold            []DailyCount `json:"sold"`             // owner's SELL listings marked sold
+	EventRSVPs      []DailyCount `json:"event_rsvps"`      // "going" RSVPs on the business's events
+	// Distinct signed-in visitors of the business profile per day.
+	UniqueViewers []DailyCount `json:"unique_viewers"`
 	// Visible-review counts keyed by star ("1".."5"), zero-filled.
 	RatingDistribution map[string]int `json:"rating_distribution"`
 	AvgRating          float64        `json:"avg_rating"`
@@ -291,6 +354,50 @@ type BusinessOwnerPostCounts struct {
 	SoldSells      int `json:"sold_sells"`
 }
 
+// BusinessFollowerResponse is one row of the owner-only follower list.
+type BusinessFollowerResponse struct {
+	UserID      string    `json:"user_id"`
+	FirstName   *string   `json:"first_name,omitempty"`
+	LastName    *string   `json:"last_name,omitempty"`
+	FullName    string    `json:"full_name"`
+	Avatar      *Photo    `json:"avatar,omitempty"`
+	AvatarColor *string   `json:"avatar_color,omitempty"`
+	Province    *string   `json:"province,omitempty"`
+	District    *string   `json:"district,omitempty"`
+	FollowedAt  time.Time `json:"followed_at"`
+}
+
+// ProvinceCount is a follower count for one province. Province is empty for
+// followers who haven't set one.
+type ProvinceCount struct {
+	Province string `json:"province"`
+	Count    int    `json:"count"`
+}
+
+// BusinessFollowerStats breaks a business's followers down by province,
+// largest first.
+type BusinessFollowerStats struct {
+	TotalFollowers int              `json:"total_followers"`
+	ByProvince     []*ProvinceCount `json:"by_province"`
+}
+
+// BusinessFollowerNotificationSettings controls the new-post fan-out to
+// followers. Events always notify; updates and polls are low priority.
+type BusinessFollowerNotificationSettings struct {
+	NotifyLowPriorityPosts bool `json:"notify_low_priority_posts"`
+}
+
+// IsLowPriorityBusinessPost reports whether a business post of type t is a
+// routine update the owner can keep out of followers' notifications.
+func IsLowPriorityBusinessPost(t PostType) bool {
+	return t == PostTypeFeed || t == PostTypePull
+}
+
+// UpdateFollowerNotificationSettingsRequest is the owner's PUT body.
+type UpdateFollowerNotificationSettingsRequest struct {
+	NotifyLowPriorityPosts *bool `json:"notify_low_priority_posts" validate:"required"`
+}
+
 // Business verification -------------------------------------------------------
 
 // VerificationStatus values for business_verification_requests.status.
@@ -330,3 +437,20 @@ type ReviewBusinessVerificationRequest struct {
 	Action string  `json:"action" validate:"required,oneof=approve reject"`
 	Reason *string `json:"reason,omitempty" validate:"omitempty,max=1000"`
 }
+
+// BusinessResponseStats is a business's chat response time from the
+// nightly rollup.
+type BusinessResponseStats struct {
+	BusinessID            string    `json:"business_id"`
+	MedianResponseSeconds int       `json:"median_response_seconds"`
+	SampleSize            int       `json:"sample_size"`
+	ComputedAt            time.Time `json:"computed_at"`
+}
+
+// BusinessResponsiveness tells a customer what to expect before writing to
+// a business: whether it is open now and how fast it usually answers.
+type BusinessResponsiveness struct {
+	IsOpen                *bool `json:"is_open,omitempty"`                 // nil when the business never set its hours
+	MedianResponseSeconds *int  `json:"median_response_seconds,omitempty"` // nil until there are enough replies to tell
+	MayBeDelayed          bool  `json:"may_be_delayed"`                    // closed now, or usually slow to answer
+}
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// BookingStatus is the lifecycle state of a booking request.
+type BookingStatus string
+
+const (
+	BookingPending   BookingStatus = "PENDING"
+	BookingAccepted  BookingStatus = "ACCEPTED"
+	BookingDeclined  BookingStatus = "DECLINED"
+	BookingCancelled BookingStatus = "CANCELLED"
+)
+
+// BusinessBooking is a customer's request for a service slot at a business.
+type BusinessBooking struct {
+	ID           string        `json:"id"`
+	BusinessID   string        `json:"business_id"`
+	CustomerID   string        `json:"customer_id"`
+	RequestedAt  time.Time     `json:"requested_at"`
+	Note         *string       `json:"note,omitempty"`
+	Status       BookingStatus `json:"status"`
+	ResponseNote *string       `json:"response_note,omitempty"`
+	RespondedAt  *time.Time    `json:"responded_at,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+// BusinessBookingWithCustomer enriches a booking with the customer's display
+// data for the owner's calendar.
+type BusinessBookingWithCustomer struct {
+	BusinessBooking
+	CustomerFirstName *string `json:"customer_first_name,omitempty"`
+	CustomerLastName  *string `json:"customer_last_name,omitempty"`
+	CustomerAvatar    *Photo  `json:"customer_avatar,omitempty"`
+	CustomerAvatarHex *string `json:"customer_avatar_color,omitempty"`
+}
+
+// BookingDay groups the bookings that fall on one calendar day (Asia/Kabul).
+type BookingDay struct {
+	Date     string                         `json:"date"` // YYYY-MM-DD
+	Bookings []*BusinessBookingWithCustomer `json:"bookings"`
+}
+
+// CreateBookingRequest is the body a customer sends to request a booking.
+type CreateBookingRequest struct {
+	RequestedAt time.Time `json:"requested_at" validate:"required"`
+	Note        *string   `json:"note,omitempty" validate:"omitempty,max=1000"`
+}
+
+// RespondBookingRequest is the optional body an owner sends with an
+// accept/decline, e.g. "Please arrive 10 minutes early".
+type RespondBookingRequest struct {
+	Note *string `json:"note,omitempty" validate:"omitempty,max=1000"`
+}
+
+// BookingFilter narrows the owner's calendar list. Empty Statuses means all.
+type BookingFilter struct {
+	Statuses []BookingStatus
+	From     time.Time
+	To       time.Time
+}
@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// BusinessBranch is one physical location of a business beyond the main
+// address on its profile (table business_locations), e.g. a second shop in
+// another district. Each branch has its own map point, phone and hours.
+type BusinessBranch struct {
+	ID           string                  `json:"id"`
+	BusinessID   string                  `json:"business_id"`
+	Name         string                  `json:"name"`
+	Address      *string                 `json:"address,omitempty"`
+	Latitude     *float64                `json:"latitude,omitempty"`
+	Longitude    *float64                `json:"longitude,omitempty"`
+	Country      *string                 `json:"country,omitempty"`
+	Province     *string                 `json:"province,omitempty"`
+	District     *string                 `json:"district,omitempty"`
+	Neighborhood *string                 `json:"neighborhood,omitempty"`
+	PhoneNumber  *string                 `json:"phone_number,omitempty"`
+	Hours        []BusinessHoursResponse `json:"hours"`
+	Position     int                     `json:"position"`
+	CreatedAt    time.Time               `json:"created_at"`
+	UpdatedAt    time.Time               `json:"updated_at"`
+}
+
+// HasLocation reports whether the branch has a map point.
+func (b *BusinessBranch) HasLocation() bool {
+	return b.Latitude != nil && b.Longitude != nil
+}
+
+// CreateBusinessBranchRequest is the body for adding a branch. Latitude and
+// Longitude go together.
+type CreateBusinessBranchRequest struct {
+	Name         string                 `json:"name" validate:"required,min=1,max=100"`
+	Address      *string                `json:"address,omitempty" validate:"omitempty,max=500"`
+	Latitude     *float64               `json:"latitude,omitempty" validate:"omitempty,latitude"`
+	Longitude    *float64               `json:"longitude,omitempty" validate:"omitempty,longitude"`
+	Country      *string                `json:"country,omitempty" validate:"omitempty,max=100"`
+	Province     *string                `json:"province,omitempty" validate:"omitempty,max=100"`
+	District     *string                `json:"district,omitempty" validate:"omitempty,max=100"`
+	Neighborhood *string                `json:"neighborhood,omitempty" validate:"omitempty,max=100"`
+	PhoneNumber  *string                `json:"phone_number,omitempty" validate:"omitempty,max=20"`
+	Hours        []BusinessHoursRequest `json:"hours,omitempty" validate:"omitempty,max=7,dive"`
+	Position     *int                   `json:"position,omitempty" validate:"omitempty,min=0"`
+}
+
+// UpdateBusinessBranchRequest edits a branch. Nil fields are left as-is;
+// Hours, when sent, replaces the whole week.
+type UpdateBusinessBranchRequest struct {
+	Name         *string                `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+	Address      *string                `json:"address,omitempty" validate:"omitempty,max=500"`
+	Latitude     *float64               `json:"latitude,omitempty" validate:"omitempty,latitude"`
+	Longitude    *float64               `json:"longitude,omitempty" validate:"omitempty,longitude"`
+	Country      *string                `json:"country,omitempty" validate:"omitempty,max=100"`
+	Province     *string                `json:"province,omitempty" validate:"omitempty,max=100"`
+	District     *string                `json:"district,omitempty" validate:"omitempty,max=100"`
+	Neighborhood *string                `json:"neighborhood,omitempty" validate:"omitempty,max=100"`
+	PhoneNumber  *string                `json:"phone_number,omitempty" validate:"omitempty,max=20"`
+	Hours        []BusinessHoursRequest `json:"hours,omitempty" validate:"omitempty,max=7,dive"`
+	Position     *int                   `json:"position,omitempty" validate:"omitempty,min=0"`
+}
+
+// NearestBranch is the branch of a business closest to a search point, set
+// on location-aware search and discover results when it is closer than the
+// business's main address.
+type NearestBranch struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Latitude   float64 `json:"latitude"`
+	Longitude  float64 `json:"longitude"`
+	DistanceKm float64 `json:"distance_km"`
+}
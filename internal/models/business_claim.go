@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// BusinessClaimStatus values for business_claim_requests.status.
+const (
+	BusinessClaimStatusPending  = "PENDING"
+	BusinessClaimStatusApproved = "APPROVED"
+	BusinessClaimStatusRejected = "REJECTED"
+)
+
+// OwnershipChangeSource values for business_ownership_history.source.
+const (
+	OwnershipChangeSourceClaimRequest = "CLAIM_REQUEST" // admin approved a claim
+	OwnershipChangeSourceInvite       = "INVITE"        // owner used an import invite
+)
+
+// BusinessClaimRequest is a user's request to take over a business that has
+// no real owner yet, with proof for the reviewer.
+type BusinessClaimRequest struct {
+	ID              string     `json:"id"`
+	BusinessID      string     `json:"business_id"`
+	UserID          string     `json:"user_id"`
+	Note            *string    `json:"note,omitempty"`
+	Proof           []Photo    `json:"proof"`
+	Status          string     `json:"status"`
+	RejectionReason *string    `json:"rejection_reason,omitempty"`
+	ReviewedBy      *string    `json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// BusinessClaimListItem is the admin queue row: request + business and
+// claimant context for review without extra fetches.
+type BusinessClaimListItem struct {
+	BusinessClaimRequest
+	BusinessName   string  `json:"business_name"`
+	BusinessAvatar *Photo  `json:"business_avatar,omitempty"`
+	ClaimantEmail  string  `json:"claimant_email"`
+	ClaimantName   *string `json:"claimant_name,omitempty"`
+}
+
+// ReviewBusinessClaimRequest is the admin approve/reject payload.
+type ReviewBusinessClaimRequest struct {
+	Action string  `json:"action" validate:"required,oneof=approve reject"`
+	Reason *string `json:"reason,omitempty" validate:"omitempty,max=1000"`
+}
+
+// BusinessOwnershipChange is one entry of a business's ownership history.
+type BusinessOwnershipChange struct {
+	ID                 string    `json:"id"`
+	BusinessID         string    `json:"business_id"`
+	PreviousOwnerID    *string   `json:"previous_owner_id,omitempty"`
+	PreviousOwnerEmail *string   `json:"previous_owner_email,omitempty"`
+	NewOwnerID         *string   `json:"new_owner_id,omitempty"`
+	NewOwnerEmail      *string   `json:"new_owner_email,omitempty"`
+	ChangedBy          *string   `json:"changed_by,omitempty"`
+	Source             string    `json:"source"`
+	ClaimRequestID     *string   `json:"claim_request_id,omitempty"`
+	Reason             *string   `json:"reason,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+}
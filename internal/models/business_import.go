@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+// BusinessImportStatus values for business_import_jobs.status.
+const (
+	BusinessImportStatusQueued    = "QUEUED"
+	BusinessImportStatusRunning   = "RUNNING"
+	BusinessImportStatusCompleted = "COMPLETED"
+	BusinessImportStatusFailed    = "FAILED"
+)
+
+// BusinessImportJob is an admin CSV import of businesses. The worker creates
+// the rows in file order; ProcessedRows is how far it got.
+type BusinessImportJob struct {
+	ID            string                   `json:"id"`
+	CreatedBy     string                   `json:"created_by"`
+	FileName      string                   `json:"file_name"`
+	Status        string                   `json:"status"`
+	TotalRows     int                      `json:"total_rows"`
+	ProcessedRows int                      `json:"processed_rows"`
+	CreatedCount  int                      `json:"created_count"`
+	FailedCount   int                      `json:"failed_count"`
+	InvitedCount  int                      `json:"invited_count"` // owners emailed a claim link
+	RowErrors     []BusinessImportRowError `json:"row_errors"`
+	CSVData       string                   `json:"-"`
+	Error         *string                  `json:"error,omitempty"`
+	StartedAt     *time.Time               `json:"started_at,omitempty"`
+	CompletedAt   *time.Time               `json:"completed_at,omitempty"`
+	CreatedAt     time.Time                `json:"created_at"`
+	UpdatedAt     time.Time                `json:"updated_at"`
+}
+
+// BusinessImportRowError explains why a row of an import was skipped. Row
+// is the line in the spreadsheet, the header being line 1.
+type BusinessImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"` // CSV column, empty for the whole row
+	Message string `json:"message"`
+}
+
+// BusinessImportRow is one parsed, valid row of an import file.
+type BusinessImportRow struct {
+	Row        int                   `json:"row"`
+	OwnerEmail string                `json:"owner_email"`
+	OwnerName  string                `json:"owner_name,omitempty"`
+	Business   CreateBusinessRequest `json:"business"`
+}
+
+// BusinessImportPreview is the dry-run result: what would be imported and
+// which rows would be skipped.
+type BusinessImportPreview struct {
+	TotalRows int                      `json:"total_rows"`
+	ValidRows int                      `json:"valid_rows"`
+	Errors    []BusinessImportRowError `json:"errors"`
+	Rows      []BusinessImportRow      `json:"rows"` // the first valid rows
+}
+
+// BusinessOwnerInvite lets the owner named in an import claim the business
+// once they have an account with that email.
+type BusinessOwnerInvite struct {
+	ID          string     `json:"id"`
+	BusinessID  string     `json:"business_id"`
+	ImportJobID *string    `json:"import_job_id,omitempty"`
+	Email       string     `json:"email"`
+	TokenHash   string     `json:"-"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	ClaimedBy   *string    `json:"claimed_by,omitempty"`
+	ClaimedAt   *time.Time `json:"claimed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// ClaimBusinessRequest claims an imported business with the emailed token.
+type ClaimBusinessRequest struct {
+	Token string `json:"token" validate:"required"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// ProductAvailability is the stock state shown on a catalog product.
+type ProductAvailability string
+
+const (
+	ProductInStock      ProductAvailability = "IN_STOCK"
+	ProductOutOfStock   ProductAvailability = "OUT_OF_STOCK"
+	ProductPreorder     ProductAvailability = "PREORDER"
+	ProductDiscontinued ProductAvailability = "DISCONTINUED"
+)
+
+// BusinessProduct is an item in a business's catalog. Unlike SELL posts,
+// products don't expire or appear in feeds; they are listed on the business
+// profile and can be attached to posts and chat messages.
+type BusinessProduct struct {
+	ID           string              `json:"id"`
+	BusinessID   string              `json:"business_id"`
+	Name         string              `json:"name"`
+	Description  *string             `json:"description,omitempty"`
+	Price        *float64            `json:"price,omitempty"`
+	Currency     *string             `json:"currency,omitempty"`
+	Photos       []Photo             `json:"photos"`
+	Availability ProductAvailability `json:"availability"`
+	Position     int                 `json:"position"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+	DeletedAt    *time.Time          `json:"-"`
+}
+
+// CreateBusinessProductRequest is the body for adding a catalog product.
+// Photos are uploaded first (POST /businesses/:id/products/photos) and passed
+// here by URL/metadata.
+type CreateBusinessProductRequest struct {
+	Name         string              `json:"name" validate:"required,min=1,max=200"`
+	Description  *string             `json:"description,omitempty" validate:"omitempty,max=5000"`
+	Price        *float64            `json:"price,omitempty" validate:"omitempty,min=0"`
+	Currency     *string             `json:"currency,omitempty" validate:"omitempty,len=3"`
+	Photos       []Photo             `json:"photos,omitempty" validate:"omitempty,max=10"`
+	Availability ProductAvailability `json:"availability,omitempty" validate:"omitempty,oneof=IN_STOCK OUT_OF_STOCK PREORDER DISCONTINUED"`
+	Position     *int                `json:"position,omitempty" validate:"omitempty,min=0"`
+}
+
+// UpdateBusinessProductRequest edits a product. Nil fields are left as-is;
+// Photos replaces the whole list when present.
+type UpdateBusinessProductRequest struct {
+	Name         *string              `json:"name,omitempty" validate:"omitempty,min=1,max=200"`
+	Description  *string              `json:"description,omitempty" validate:"omitempty,max=5000"`
+	Price        *float64             `json:"price,omitempty" validate:"omitempty,min=0"`
+	Currency     *string              `json:"currency,omitempty" validate:"omitempty,len=3"`
+	Photos       *[]Photo             `json:"photos,omitempty" validate:"omitempty,max=10"`
+	Availability *ProductAvailability `json:"availability,omitempty" validate:"omitempty,oneof=IN_STOCK OUT_OF_STOCK PREORDER DISCONTINUED"`
+	Position     *int                 `json:"position,omitempty" validate:"omitempty,min=0"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// BusinessQuickReply is a saved response a business owner can drop into a
+// chat from the composer.
+type BusinessQuickReply struct {
+	ID         string    `json:"id"`
+	BusinessID string    `json:"business_id"`
+	Title      string    `json:"title"`
+	Body       string    `json:"body"`
+	Position   int       `json:"position"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// CreateQuickReplyRequest is the body for saving a quick reply.
+type CreateQuickReplyRequest struct {
+	Title    string `json:"title" validate:"required,min=1,max=60"`
+	Body     string `json:"body" validate:"required,min=1,max=5000"`
+	Position *int   `json:"position,omitempty" validate:"omitempty,min=0"`
+}
+
+// UpdateQuickReplyRequest edits a quick reply. Nil fields are left as-is.
+type UpdateQuickReplyRequest struct {
+	Title    *string `json:"title,omitempty" validate:"omitempty,min=1,max=60"`
+	Body     *string `json:"body,omitempty" validate:"omitempty,min=1,max=5000"`
+	Position *int    `json:"position,omitempty" validate:"omitempty,min=0"`
+}
+
+// BusinessAutoReply is the away message sent to customers who write to the
+// business while it is closed according to its business hours.
+type BusinessAutoReply struct {
+	BusinessID string     `json:"business_id"`
+	Enabled    bool       `json:"enabled"`
+	Message    string     `json:"message"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+}
+
+// SetAutoReplyRequest turns the away message on or off. Message is required
+// to enable it.
+type SetAutoReplyRequest struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message" validate:"max=1000"`
+}
@@ -11,6 +11,20 @@ const (
 	MessageTypeFile     MessageType = "FILE"
 	MessageTypeLocation MessageType = "LOCATION"
 	MessageTypeVoice    MessageType = "VOICE"
+	// MessageTypePost carries a shared post (SharedPostID); Content is the
+	// sender's optional note.
+	MessageTypePost MessageType = "POST"
+	// MessageTypeSticker carries a catalog sticker (StickerID) and no text.
+	MessageTypeSticker MessageType = "STICKER"
+	// MessageTypeSystem records a conversation event, such as a change to
+	// disappearing messages. Clients render it centered, not as a bubble.
+	MessageTypeSystem MessageType = "SYSTEM"
+)
+
+// Disappearing-message TTLs a conversation can be set to.
+const (
+	DisappearingTTL24h = 24 * 60 * 60
+	DisappearingTTL7d  = 7 * 24 * 60 * 60
 )
 
 // Conversation represents a chat conversation between two users (optionally
@@ -22,21 +36,46 @@ type Conversation struct {
 	BusinessID     *string    `json:"business_id,omitempty"`
 	LastMessageAt  *time.Time `json:"last_message_at"`
 	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	// DisappearingTTL is how long new messages live, in seconds; nil when
+	// disappearing messages are off.
+	DisappearingTTL *int `json:"disappearing_ttl_seconds,omitempty"`
 }
 
 // Message represents a chat message
 type Message struct {
-	ID               string      `json:"id"`
-	ConversationID   string      `json:"conversation_id"`
-	SenderID         string      `json:"sender_id"`
-	Content          *string     `json:"content"`
-	MessageType      MessageType `json:"message_type"`
-	ProductID        *string     `json:"product_id,omitempty"`
-	ReplyToMessageID *string     `json:"reply_to_message_id,omitempty"`
-	ReadAt           *time.Time  `json:"read_at,omitempty"`
-	CreatedAt        time.Time   `json:"created_at"`
-	EditedAt         *time.Time  `json:"edited_at,omitempty"`
-	DeletedAt        *time.Time  `json:"deleted_at,omitempty"`
+	ID             string      `json:"id"`
+	ConversationID string      `json:"conversation_id"`
+	SenderID       string      `json:"sender_id"`
+	Content        *string     `json:"content"`
+	MessageType    MessageType `json:"message_type"`
+	ProductID      *string     `json:"product_id,omitempty"`
+	// BusinessProductID references a catalog product (business_products),
+	// whereas ProductID references a SELL post.
+	BusinessProductID *string    `json:"business_product_id,omitempty"`
+	ReplyToMessageID  *string    `json:"reply_to_message_id,omitempty"`
+	ReadAt            *time.Time `json:"read_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	EditedAt          *time.Time `json:"edited_at,omitempty"`
+	DeletedAt         *time.Time `json:"deleted_at,omitempty"`
+	SharedPostID      *string    `json:"shared_post_id,omitempty"`
+	StickerID         *string    `json:"sticker_id,omitempty"`
+	ExpiresAt         *time.Time `json:"expires_at,omitempty"`
+	// Seq is the message's position in its conversation, assigned by the
+	// database on insert.
+	Seq int64 `json:"seq"`
+}
+
+// SharedPostPreview is the card rendered for a POST message. Nil on the
+// response when the post has since been deleted.
+type SharedPostPreview struct {
+	ID          string   `json:"id"`
+	Type        PostType `json:"type"`
+	Title       *string  `json:"title,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Cover       *Photo   `json:"cover,omitempty"`
+	Price       *float64 `json:"price,omitempty"`
+	Currency    *string  `json:"currency,omitempty"`
 }
 
 // MessageReplyPreview is the quoted message shown above a reply.
@@ -63,14 +102,21 @@ type ConversationResponse struct {
 	UnreadCount      int                 `json:"unread_count"`
 	LastMessageAt    *time.Time          `json:"last_message_at"`
 	CreatedAt        time.Time           `json:"created_at"`
+	UpdatedAt        time.Time           `json:"updated_at"`
+	// DisappearingTTL is the disappearing-messages TTL in seconds; omitted
+	// when off.
+	DisappearingTTL *int `json:"disappearing_ttl_seconds,omitempty"`
 }
 
 // ConversationBizRef is a brief business reference shown next to a conversation
 // when the chat is scoped to a business.
 type ConversationBizRef struct {
-	ID     string  `json:"id"`
-	Name   string  `json:"name"`
-	Avatar *Photo  `json:"avatar,omitempty"`
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Avatar *Photo `json:"avatar,omitempty"`
+	// Responsiveness is shown to the customer in the chat header; omitted
+	// for the business's own side of the chat.
+	Responsiveness *BusinessResponsiveness `json:"responsiveness,omitempty"`
 }
 
 // MessageResponse is the API response for a message
@@ -81,11 +127,16 @@ type MessageResponse struct {
 	Content        *string              `json:"content"`
 	MessageType    MessageType          `json:"message_type"`
 	ProductID      *string              `json:"product_id,omitempty"`
+	Product        *BusinessProduct     `json:"product,omitempty"`
+	SharedPost     *SharedPostPreview   `json:"shared_post,omitempty"`
+	Sticker        *Sticker             `json:"sticker,omitempty"`
 	ReplyTo        *MessageReplyPreview `json:"reply_to,omitempty"`
 	Reactions      []MessageReaction    `json:"reactions,omitempty"`
 	IsRead         bool                 `json:"is_read"`
 	CreatedAt      time.Time            `json:"created_at"`
 	EditedAt       *time.Time           `json:"edited_at,omitempty"`
+	ExpiresAt      *time.Time           `json:"expires_at,omitempty"`
+	Seq            int64                `json:"seq"`
 }
 
 // MessageInfo is a brief message summary for conversation lists
@@ -97,7 +148,9 @@ type MessageInfo struct {
 	CreatedAt   time.Time   `json:"created_at"`
 }
 
-// UserInfo represents brief user information for chat
+// UserInfo represents brief user information for chat. In a
+// business-scoped chat the business owner is shown as the business:
+// BusinessID is set and the name and avatar are the business's.
 type UserInfo struct {
 	UserID      string  `json:"user_id"`
 	FirstName   string  `json:"first_name"`
@@ -105,16 +158,31 @@ type UserInfo struct {
 	FullName    string  `json:"full_name"`
 	Avatar      *Photo  `json:"avatar,omitempty"`
 	AvatarColor *string `json:"avatar_color,omitempty"`
+	BusinessID  *string `json:"business_id,omitempty"`
 }
 
 // SendMessageRequest represents a request to send a message
 type SendMessageRequest struct {
-	RecipientID      string      `json:"recipient_id" validate:"required,uuid"`
-	Content          *string     `json:"content,omitempty" validate:"omitempty,min=1,max=5000"`
-	MessageType      MessageType `json:"message_type" validate:"required"`
-	ProductID        *string     `json:"product_id,omitempty" validate:"omitempty,uuid"`
-	BusinessID       *string     `json:"business_id,omitempty" validate:"omitempty,uuid"`
-	ReplyToMessageID *string     `json:"reply_to_message_id,omitempty" validate:"omitempty,uuid"`
+	RecipientID string      `json:"recipient_id" validate:"required,uuid"`
+	Content     *string     `json:"content,omitempty" validate:"omitempty,min=1,max=5000"`
+	MessageType MessageType `json:"message_type" validate:"required"`
+	ProductID   *string     `json:"product_id,omitempty" validate:"omitempty,uuid"`
+	// BusinessProductID attaches a catalog product. In a business-scoped
+	// chat it must belong to that business.
+	BusinessProductID *string `json:"business_product_id,omitempty" validate:"omitempty,uuid"`
+	BusinessID        *string `json:"business_id,omitempty" validate:"omitempty,uuid"`
+	ReplyToMessageID  *string `json:"reply_to_message_id,omitempty" validate:"omitempty,uuid"`
+	// StickerID names the catalog sticker of a STICKER message.
+	StickerID *string `json:"sticker_id,omitempty" validate:"omitempty,uuid"`
+	// SharedPostID is set server-side by the share-to-chat flow; clients
+	// can't send POST messages directly.
+	SharedPostID *string `json:"-"`
+}
+
+// SetDisappearingMessagesRequest turns disappearing messages on (24h or 7d)
+// or off for a conversation.
+type SetDisappearingMessagesRequest struct {
+	TTL string `json:"ttl" validate:"required,oneof=off 24h 7d"`
 }
 
 // ReactToMessageRequest toggles an emoji reaction on a message.
@@ -131,8 +199,11 @@ type EditMessageRequest struct {
 type GetConversationsFilter struct {
 	UserID     string
 	BusinessID *string // nil = personal chats only; non-nil = chats scoped to that business
-	Limit      int
-	Offset     int
+	// UpdatedAfter switches to delta sync: conversations changed after
+	// this, oldest change first. Offset is ignored.
+	UpdatedAfter *time.Time
+	Limit        int
+	Offset       int
 }
 
 // GetMessagesFilter represents filters for listing messages.
@@ -141,11 +212,34 @@ type GetConversationsFilter struct {
 type GetMessagesFilter struct {
 	ConversationID string
 	ViewerID       string
-	Limit          int
-	Offset         int
+	// AfterSeq returns messages with a higher seq, oldest first (sync after
+	// reconnect). BeforeSeq returns older history, newest first. Offset is
+	// ignored when either is set.
+	AfterSeq  *int64
+	BeforeSeq *int64
+	Limit     int
+	Offset    int
+}
+
+// MessageSyncPage is the response to ?after_seq: the messages the client
+// missed, oldest first, and the after_seq to send next. Seqs need not be
+// contiguous: deleted messages and ones hidden from this viewer leave gaps.
+type MessageSyncPage struct {
+	Messages []*MessageResponse `json:"messages"`
+	NextSeq  int64              `json:"next_seq"`
+	HasMore  bool               `json:"has_more"`
 }
 
-// WSMessage represents a WebSocket message for real-time communication
+// UnreadBadge is the app icon badge.
+type UnreadBadge struct {
+	Messages      int `json:"messages"`
+	Notifications int `json:"notifications"`
+	Total         int `json:"total"`
+}
+
+// WSMessage represents a WebSocket message for real-time communication.
+// The hub stamps it with the protocol version on the way out; see
+// pkg/websocket/protocol.go.
 type WSMessage struct {
 	Type    string      `json:"type"` // "message", "typing", "read", "error"
 	Payload interface{} `json:"payload"`
@@ -163,6 +257,7 @@ type WSMessagePayload struct {
 	BusinessID     *string     `json:"business_id,omitempty"`
 	Content        *string     `json:"content"`
 	MessageType    MessageType `json:"message_type"`
+	Sticker        *Sticker    `json:"sticker,omitempty"`
 	CreatedAt      time.Time   `json:"created_at"`
 }
 
@@ -195,6 +290,22 @@ type WSReactionPayload struct {
 	UserID         string `json:"user_id"`
 	Emoji          string `json:"emoji"`
 	Added          bool   `json:"added"` // true = reaction added, false = removed
+	// Reactions is the message's aggregated reactions after the change, with
+	// Reacted relative to the recipient, so clients can replace the bubble's
+	// reactions instead of patching counts.
+	Reactions []MessageReaction `json:"reactions"`
+}
+
+// WSDisappearingPayload tells the other participant that disappearing
+// messages were turned on or off, with the SYSTEM message recording it.
+type WSDisappearingPayload struct {
+	ConversationID  string    `json:"conversation_id"`
+	DisappearingTTL *int      `json:"disappearing_ttl_seconds"` // nil = off
+	ChangedBy       string    `json:"changed_by"`
+	MessageID       string    `json:"message_id"`
+	Content         *string   `json:"content"`
+	BusinessID      *string   `json:"business_id,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
 }
 
 // WSTypingPayload represents the payload for typing indicators
@@ -22,6 +22,9 @@ type PostComment struct {
 	UpdatedAt        time.Time `json:"updated_at"`
 	DeletedAt        *time.Time `json:"-"`
 	MentionedUserIDs []string  `json:"-"` // Stored in DB as JSONB; order matches @mentions in text
+	// PinnedAt is set when the post owner pinned this comment. At most one
+	// live comment per post is pinned.
+	PinnedAt *time.Time `json:"pinned_at,omitempty"`
 }
 
 // CommentAttachment represents an attachment on a comment
@@ -44,7 +47,7 @@ type CommentLike struct {
 
 // CreateCommentRequest represents a request to create a comment
 type CreateCommentRequest struct {
-	Text            string   `json:"text" validate:"required,min=1,max=1000"`
+	Text            string   `json:"text" validate:"required,min=1"`
 	ParentCommentID *string  `json:"parent_comment_id,omitempty" validate:"omitempty,uuid"`
 	BusinessID      *string  `json:"business_id,omitempty" validate:"omitempty,uuid"`
 	Latitude        *float64 `json:"latitude,omitempty"`
@@ -53,9 +56,28 @@ type CreateCommentRequest struct {
 	TaggedUserIDs   []string `json:"tagged_user_ids,omitempty"` // User IDs mentioned in the comment; each receives a MENTION notification
 }
 
+// CommentSort orders a post's top-level comments. The pinned comment always
+// comes first.
+type CommentSort string
+
+const (
+	CommentSortNewest    CommentSort = "newest"
+	CommentSortOldest    CommentSort = "oldest"
+	CommentSortMostLiked CommentSort = "most_liked"
+)
+
+// IsValid reports whether s is a known sort mode.
+func (s CommentSort) IsValid() bool {
+	switch s {
+	case CommentSortNewest, CommentSortOldest, CommentSortMostLiked:
+		return true
+	}
+	return false
+}
+
 // UpdateCommentRequest represents a request to update a comment
 type UpdateCommentRequest struct {
-	Text                 string   `json:"text" validate:"required,min=1,max=1000"`
+	Text                 string   `json:"text" validate:"required,min=1"`
 	Attachments          []string `json:"attachments,omitempty"`           // New photo URLs to add
 	DeletedAttachmentIDs []string `json:"deleted_attachment_ids,omitempty"` // Attachment IDs to remove
 }
@@ -69,8 +91,9 @@ type CommentAttachmentResponse struct {
 
 // MentionedUser is a user mentioned in a comment (for tap-to-profile).
 type MentionedUser struct {
-	UserID   string `json:"user_id"`
-	FullName string `json:"full_name"`
+	UserID   string  `json:"user_id"`
+	FullName string  `json:"full_name"`
+	Handle   *string `json:"handle,omitempty"`
 }
 
 // CommentResponse represents a comment in API responses
@@ -88,6 +111,8 @@ type CommentResponse struct {
 	TotalReplies    int                         `json:"total_replies"`
 	LikedByMe       bool                        `json:"liked_by_me"`
 	IsMine          bool                        `json:"is_mine"`
+	IsAuthor        bool                        `json:"is_author"` // Written by the post's author (or as the post's business)
+	IsPinned        bool                        `json:"is_pinned"`
 	Replies         []*CommentResponse          `json:"replies,omitempty"`
 	CreatedAt       time.Time                   `json:"created_at"`
 	UpdatedAt       time.Time                   `json:"updated_at"`
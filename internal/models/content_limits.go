@@ -0,0 +1,57 @@
+package models
+
+// PostTypeLimits caps the content of one post type. Lengths count
+// characters, not bytes.
+type PostTypeLimits struct {
+	TitleMaxLength       int `json:"title_max_length"`
+	DescriptionMaxLength int `json:"description_max_length"`
+	MaxAttachments       int `json:"max_attachments"`
+}
+
+// ContentLimits are the post and comment limits the API enforces. Clients
+// read them from GET /config/limits so their counters match.
+type ContentLimits struct {
+	Posts                 map[PostType]PostTypeLimits `json:"posts"`
+	PollMaxOptions        int                         `json:"poll_max_options"`
+	PollOptionMaxLength   int                         `json:"poll_option_max_length"`
+	CommentMaxLength      int                         `json:"comment_max_length"`
+	CommentMaxAttachments int                         `json:"comment_max_attachments"`
+}
+
+// AllPostTypes lists every post type, in the order clients show them.
+var AllPostTypes = []PostType{
+	PostTypeFeed, PostTypeEvent, PostTypeSell, PostTypePull,
+	PostTypeLostFound, PostTypeAlert, PostTypeHelp,
+}
+
+// DefaultPostTypeLimits apply to post types without their own limits.
+var DefaultPostTypeLimits = PostTypeLimits{
+	TitleMaxLength:       255, // posts.title is VARCHAR(255)
+	DescriptionMaxLength: 5000,
+	MaxAttachments:       20,
+}
+
+// DefaultContentLimits returns the built-in limits, the same for every
+// post type.
+func DefaultContentLimits() ContentLimits {
+	posts := make(map[PostType]PostTypeLimits, len(AllPostTypes))
+	for _, t := range AllPostTypes {
+		posts[t] = DefaultPostTypeLimits
+	}
+	return ContentLimits{
+		Posts:                 posts,
+		PollMaxOptions:        10,
+		PollOptionMaxLength:   100,
+		CommentMaxLength:      1000,
+		CommentMaxAttachments: 10,
+	}
+}
+
+// ForPost returns the limits of a post type, falling back to
+// DefaultPostTypeLimits.
+func (l ContentLimits) ForPost(t PostType) PostTypeLimits {
+	if limits, ok := l.Posts[t]; ok {
+		return limits
+	}
+	return DefaultPostTypeLimits
+}
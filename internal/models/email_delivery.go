@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// EmailDeliveryStatus is the lifecycle state of one outbound email row.
+// "sent" means the provider accepted it; later states arrive via webhook.
+type EmailDeliveryStatus string
+
+const (
+	EmailStatusSent       EmailDeliveryStatus = "sent"
+	EmailStatusFailed     EmailDeliveryStatus = "failed"
+	EmailStatusSuppressed EmailDeliveryStatus = "suppressed"
+	EmailStatusDelivered  EmailDeliveryStatus = "delivered"
+	EmailStatusDelayed    EmailDeliveryStatus = "delayed"
+	EmailStatusBounced    EmailDeliveryStatus = "bounced"
+	EmailStatusComplained EmailDeliveryStatus = "complained"
+)
+
+// Suppression reasons stored on email_suppressions.
+const (
+	EmailSuppressionBounce    = "bounce"
+	EmailSuppressionComplaint = "complaint"
+	EmailSuppressionManual    = "manual"
+)
+
+// SentEmail is one row of the outbound email log.
+type SentEmail struct {
+	ID                string              `json:"id"`
+	ToEmail           string              `json:"to_email"`
+	Subject           string              `json:"subject"`
+	Provider          string              `json:"provider"`
+	ProviderMessageID *string             `json:"provider_message_id,omitempty"`
+	Status            EmailDeliveryStatus `json:"status"`
+	Error             *string             `json:"error,omitempty"`
+	CreatedAt         time.Time           `json:"created_at"`
+	UpdatedAt         time.Time           `json:"updated_at"`
+}
+
+// EmailSuppression marks an address as undeliverable.
+type EmailSuppression struct {
+	Email     string    `json:"email"`
+	Reason    string    `json:"reason"`
+	Detail    *string   `json:"detail,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AdminEmailDeliveryInfo is the support-troubleshooting block on the admin
+// user detail page: whether the address is suppressed and the most recent
+// emails we tried to send to it.
+type AdminEmailDeliveryInfo struct {
+	Suppression  *EmailSuppression `json:"suppression,omitempty"`
+	RecentEmails []SentEmail       `json:"recent_emails"`
+}
@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// EndorsementStatus tracks the profile owner's decision on an endorsement.
+type EndorsementStatus string
+
+const (
+	EndorsementPending  EndorsementStatus = "PENDING"
+	EndorsementApproved EndorsementStatus = "APPROVED"
+	EndorsementRejected EndorsementStatus = "REJECTED"
+)
+
+// Endorsement is a short public recommendation AuthorID left on
+// SubjectID's profile. Only APPROVED endorsements are shown to others.
+type Endorsement struct {
+	ID        string            `json:"id"`
+	SubjectID string            `json:"subject_id"`
+	AuthorID  string            `json:"author_id"`
+	Text      string            `json:"text"`
+	Status    EndorsementStatus `json:"status"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// EndorsementWithAuthor adds the author's display data for lists.
+type EndorsementWithAuthor struct {
+	Endorsement
+	AuthorFirstName *string `json:"author_first_name,omitempty"`
+	AuthorLastName  *string `json:"author_last_name,omitempty"`
+	AuthorAvatar    *Photo  `json:"author_avatar,omitempty"`
+	AuthorAvatarHex *string `json:"author_avatar_color,omitempty"`
+}
+
+// ReportedEndorsement is an endorsement in the admin moderation list.
+type ReportedEndorsement struct {
+	EndorsementWithAuthor
+	ReportCount    int       `json:"report_count"`
+	LastReportedAt time.Time `json:"last_reported_at"`
+}
+
+// EndorsementReport is a user's report of an endorsement.
+type EndorsementReport struct {
+	ID                 string    `json:"id"`
+	UserID             string    `json:"user_id"`
+	EndorsementID      string    `json:"endorsement_id"`
+	Reason             string    `json:"reason"`
+	AdditionalComments *string   `json:"additional_comments,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// CreateEndorsementRequest is the body for endorsing a user. Endorsing the
+// same user again replaces the text.
+type CreateEndorsementRequest struct {
+	Text string `json:"text" validate:"required,min=3,max=500"`
+}
+
+// DecideEndorsementRequest is the profile owner's approval or rejection.
+type DecideEndorsementRequest struct {
+	Status EndorsementStatus `json:"status" validate:"required,oneof=APPROVED REJECTED"`
+}
+
+// ReportEndorsementRequest is the body for reporting an endorsement.
+type ReportEndorsementRequest struct {
+	Reason             string  `json:"reason" validate:"required,max=100"`
+	AdditionalComments *string `json:"additional_comments,omitempty" validate:"omitempty,max=1000"`
+}
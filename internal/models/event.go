@@ -40,3 +40,78 @@ type EventInterestedUser struct {
 	EventState EventInterestState `json:"event_state"`
 	CreatedAt time.Time          `json:"created_at"`
 }
+
+// EventHostStatus is the state of a co-host invitation
+type EventHostStatus string
+
+const (
+	EventHostPending  EventHostStatus = "PENDING"
+	EventHostAccepted EventHostStatus = "ACCEPTED"
+	EventHostDeclined EventHostStatus = "DECLINED"
+)
+
+// EventHostRole tells the event's own author apart from invited co-hosts
+type EventHostRole string
+
+const (
+	EventHostRoleHost   EventHostRole = "host"
+	EventHostRoleCoHost EventHostRole = "co_host"
+)
+
+// EventHost is a host of an event: a user or a business, never both. The
+// author of the post is listed with role "host" and no ID; co-hosts carry the
+// ID of their invitation.
+type EventHost struct {
+	ID          string          `json:"id,omitempty"`
+	PostID      string          `json:"post_id"`
+	Role        EventHostRole   `json:"role"`
+	UserID      *string         `json:"user_id,omitempty"`
+	BusinessID  *string         `json:"business_id,omitempty"`
+	Name        string          `json:"name"`
+	Avatar      *Photo          `json:"avatar,omitempty"`
+	Status      EventHostStatus `json:"status,omitempty"`
+	InvitedBy   string          `json:"invited_by,omitempty"`
+	CreatedAt   *time.Time      `json:"created_at,omitempty"`
+	RespondedAt *time.Time      `json:"responded_at,omitempty"`
+}
+
+// InviteEventHostRequest invites a user or a business to co-host an event
+type InviteEventHostRequest struct {
+	UserID     *string `json:"user_id,omitempty" validate:"omitempty,uuid"`
+	BusinessID *string `json:"business_id,omitempty" validate:"omitempty,uuid"`
+}
+
+// EventHostInvitation is a pending co-host invitation shown to its invitee
+type EventHostInvitation struct {
+	Host      *EventHost `json:"host"`
+	PostTitle *string    `json:"post_title,omitempty"`
+	StartDate *time.Time `json:"start_date,omitempty"`
+}
+
+// EventAudience selects which attendees a host message goes to
+type EventAudience string
+
+const (
+	EventAudienceGoing      EventAudience = "going"
+	EventAudienceInterested EventAudience = "interested"
+	EventAudienceAll        EventAudience = "all"
+)
+
+// MessageEventAttendeesRequest is a message from a host to the event's attendees
+type MessageEventAttendeesRequest struct {
+	Message  string        `json:"message" validate:"required,min=1,max=500"`
+	Audience EventAudience `json:"audience" validate:"omitempty,oneof=going interested all"`
+}
+
+// MessageEventAttendeesResponse reports how many attendees were messaged
+type MessageEventAttendeesResponse struct {
+	Recipients int `json:"recipients"`
+}
+
+// CalendarFeedResponse is the subscription URL of a user's events.ics feed.
+// The token in it is only shown when the feed is (re)created.
+type CalendarFeedResponse struct {
+	URL       string `json:"url"`
+	WebcalURL string `json:"webcal_url"`
+	Token     string `json:"token"`
+}
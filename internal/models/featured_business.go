@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// Featured placement states, derived from the date window.
+const (
+	FeaturedStatusScheduled = "scheduled"
+	FeaturedStatusLive      = "live"
+	FeaturedStatusEnded     = "ended"
+)
+
+// FeaturedBusiness is an admin-managed placement that promotes a business
+// in one province between StartsAt and EndsAt. Lower Position shows first.
+type FeaturedBusiness struct {
+	ID           string    `json:"id"`
+	BusinessID   string    `json:"business_id"`
+	BusinessName string    `json:"business_name,omitempty"`
+	Province     string    `json:"province"`
+	Position     int       `json:"position"`
+	StartsAt     time.Time `json:"starts_at"`
+	EndsAt       time.Time `json:"ends_at"`
+	Status       string    `json:"status"` // scheduled | live | ended
+	Impressions  int64     `json:"impressions"`
+	Clicks       int64     `json:"clicks"`
+	CreatedBy    *string   `json:"created_by,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// FeaturedBusinessCard is a live placement as clients see it. Impressions
+// and clicks are reported against PlacementID.
+type FeaturedBusinessCard struct {
+	PlacementID string                `json:"placement_id"`
+	Business    *BusinessCardResponse `json:"business"`
+}
+
+// FeaturedBusinessFilter narrows the admin list. Empty fields match all.
+type FeaturedBusinessFilter struct {
+	Province   string
+	BusinessID string
+	Status     string // scheduled | live | ended
+}
+
+// CreateFeaturedBusinessRequest is the admin body for a new placement.
+type CreateFeaturedBusinessRequest struct {
+	BusinessID string    `json:"business_id" validate:"required,uuid"`
+	Province   string    `json:"province" validate:"required,min=1,max=100"`
+	Position   int       `json:"position" validate:"omitempty,min=0,max=1000"`
+	StartsAt   time.Time `json:"starts_at" validate:"required"`
+	EndsAt     time.Time `json:"ends_at" validate:"required,gtfield=StartsAt"`
+}
+
+// UpdateFeaturedBusinessRequest edits a placement. Nil fields are left
+// as-is.
+type UpdateFeaturedBusinessRequest struct {
+	Province *string    `json:"province,omitempty" validate:"omitempty,min=1,max=100"`
+	Position *int       `json:"position,omitempty" validate:"omitempty,min=0,max=1000"`
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+}
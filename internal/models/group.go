@@ -0,0 +1,127 @@
+package models
+
+import "time"
+
+// GroupKind distinguishes location-bound groups from topic groups.
+type GroupKind string
+
+const (
+	GroupKindNeighborhood GroupKind = "NEIGHBORHOOD"
+	GroupKindInterest     GroupKind = "INTEREST"
+)
+
+// GroupPrivacy controls who can read a group and how people join.
+// PUBLIC: anyone can read the feed and join instantly.
+// PRIVATE: only members read the feed; joining needs admin/moderator approval.
+type GroupPrivacy string
+
+const (
+	GroupPublic  GroupPrivacy = "PUBLIC"
+	GroupPrivate GroupPrivacy = "PRIVATE"
+)
+
+// GroupRole is a member's permission level within a group.
+type GroupRole string
+
+const (
+	GroupRoleAdmin     GroupRole = "ADMIN"     // edit/delete group, manage roles
+	GroupRoleModerator GroupRole = "MODERATOR" // approve joins, remove members and posts
+	GroupRoleMember    GroupRole = "MEMBER"
+)
+
+// CanModerate reports whether the role may approve joins and remove content.
+func (r GroupRole) CanModerate() bool {
+	return r == GroupRoleAdmin || r == GroupRoleModerator
+}
+
+// GroupMemberStatus is ACTIVE for members, PENDING for join requests.
+type GroupMemberStatus string
+
+const (
+	GroupMemberActive  GroupMemberStatus = "ACTIVE"
+	GroupMemberPending GroupMemberStatus = "PENDING"
+)
+
+// Group is a neighborhood or interest community.
+type Group struct {
+	ID           string       `json:"id"`
+	Name         string       `json:"name"`
+	Description  *string      `json:"description,omitempty"`
+	Kind         GroupKind    `json:"kind"`
+	Privacy      GroupPrivacy `json:"privacy"`
+	Cover        *Photo       `json:"cover,omitempty"`
+	Province     *string      `json:"province,omitempty"`
+	District     *string      `json:"district,omitempty"`
+	Neighborhood *string      `json:"neighborhood,omitempty"`
+	CreatedBy    string       `json:"created_by"`
+	MemberCount  int          `json:"member_count"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+	DeletedAt    *time.Time   `json:"-"`
+}
+
+// GroupMember is one user's membership (or pending request) in a group.
+type GroupMember struct {
+	GroupID   string            `json:"group_id"`
+	UserID    string            `json:"user_id"`
+	Role      GroupRole         `json:"role"`
+	Status    GroupMemberStatus `json:"status"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// GroupMemberWithProfile enriches a membership with display data for the
+// member list and the pending-requests queue.
+type GroupMemberWithProfile struct {
+	GroupMember
+	FirstName   *string `json:"first_name,omitempty"`
+	LastName    *string `json:"last_name,omitempty"`
+	Avatar      *Photo  `json:"avatar,omitempty"`
+	AvatarColor *string `json:"avatar_color,omitempty"`
+}
+
+// GroupResponse is a group plus the viewer's membership, when any.
+type GroupResponse struct {
+	*Group
+	ViewerRole   *GroupRole         `json:"viewer_role,omitempty"`
+	ViewerStatus *GroupMemberStatus `json:"viewer_status,omitempty"`
+}
+
+// CreateGroupRequest is the body for creating a group. The creator becomes
+// its first admin.
+type CreateGroupRequest struct {
+	Name         string       `json:"name" validate:"required,min=3,max=120"`
+	Description  *string      `json:"description,omitempty" validate:"omitempty,max=2000"`
+	Kind         GroupKind    `json:"kind,omitempty" validate:"omitempty,oneof=NEIGHBORHOOD INTEREST"`
+	Privacy      GroupPrivacy `json:"privacy,omitempty" validate:"omitempty,oneof=PUBLIC PRIVATE"`
+	Cover        *Photo       `json:"cover,omitempty"`
+	Province     *string      `json:"province,omitempty" validate:"omitempty,max=100"`
+	District     *string      `json:"district,omitempty" validate:"omitempty,max=100"`
+	Neighborhood *string      `json:"neighborhood,omitempty" validate:"omitempty,max=100"`
+}
+
+// UpdateGroupRequest edits a group (admins only). Nil fields are unchanged.
+type UpdateGroupRequest struct {
+	Name         *string       `json:"name,omitempty" validate:"omitempty,min=3,max=120"`
+	Description  *string       `json:"description,omitempty" validate:"omitempty,max=2000"`
+	Privacy      *GroupPrivacy `json:"privacy,omitempty" validate:"omitempty,oneof=PUBLIC PRIVATE"`
+	Cover        *Photo        `json:"cover,omitempty"`
+	Province     *string       `json:"province,omitempty" validate:"omitempty,max=100"`
+	District     *string       `json:"district,omitempty" validate:"omitempty,max=100"`
+	Neighborhood *string       `json:"neighborhood,omitempty" validate:"omitempty,max=100"`
+}
+
+// UpdateGroupMemberRoleRequest changes a member's role (admins only).
+type UpdateGroupMemberRoleRequest struct {
+	Role GroupRole `json:"role" validate:"required,oneof=ADMIN MODERATOR MEMBER"`
+}
+
+// GroupListFilter narrows the group directory.
+type GroupListFilter struct {
+	Search   *string
+	Kind     *GroupKind
+	Province *string
+	District *string
+	Limit    int
+	Offset   int
+}
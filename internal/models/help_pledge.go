@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// PledgeKind says what a neighbor offers on a HELP post.
+type PledgeKind string
+
+const (
+	PledgeKindItem PledgeKind = "ITEM"
+	PledgeKindTime PledgeKind = "TIME"
+)
+
+// PledgeStatus is the lifecycle state of a pledge.
+// PLEDGED: offered, not yet delivered.
+// FULFILLED: the post owner confirmed it was delivered.
+// WITHDRAWN: the pledger backed out.
+type PledgeStatus string
+
+const (
+	PledgeStatusPledged   PledgeStatus = "PLEDGED"
+	PledgeStatusFulfilled PledgeStatus = "FULFILLED"
+	PledgeStatusWithdrawn PledgeStatus = "WITHDRAWN"
+)
+
+// HelpPledge is one neighbor's offer of items or time on a HELP post.
+type HelpPledge struct {
+	ID          string       `json:"id"`
+	PostID      string       `json:"post_id"`
+	UserID      string       `json:"user_id"`
+	Kind        PledgeKind   `json:"kind"`
+	Note        *string      `json:"note,omitempty"`
+	Quantity    *int         `json:"quantity,omitempty"`
+	Status      PledgeStatus `json:"status"`
+	FulfilledAt *time.Time   `json:"fulfilled_at,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// HelpPledgeWithProfile enriches a pledge with the pledger's display data
+// for the pledge list.
+type HelpPledgeWithProfile struct {
+	HelpPledge
+	FirstName   *string `json:"first_name,omitempty"`
+	LastName    *string `json:"last_name,omitempty"`
+	Avatar      *Photo  `json:"avatar,omitempty"`
+	AvatarColor *string `json:"avatar_color,omitempty"`
+}
+
+// HelpSummary is the pledge state shown on a HELP post.
+type HelpSummary struct {
+	PledgeCount    int         `json:"pledge_count"`
+	FulfilledCount int         `json:"fulfilled_count"`
+	FulfilledAt    *time.Time  `json:"fulfilled_at,omitempty"` // request marked fulfilled by the owner
+	MyPledge       *HelpPledge `json:"my_pledge,omitempty"`
+}
+
+// CreatePledgeRequest is the body for pledging on a HELP post. Pledging
+// again replaces the caller's live pledge.
+type CreatePledgeRequest struct {
+	Kind     PledgeKind `json:"kind" validate:"required,oneof=ITEM TIME"`
+	Note     *string    `json:"note,omitempty" validate:"omitempty,max=1000"`
+	Quantity *int       `json:"quantity,omitempty" validate:"omitempty,min=1,max=10000"`
+}
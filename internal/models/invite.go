@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// InviteLink is the caller's invite code and the link to share it with.
+type InviteLink struct {
+	Code string `json:"code"`
+	URL  string `json:"url"`
+	// InvitesCount counts users who signed up with the code and still have
+	// an account.
+	InvitesCount int `json:"invites_count"`
+}
+
+// Referral records that a new user signed up with another user's invite
+// code.
+type Referral struct {
+	ReferredUserID string    `json:"referred_user_id"`
+	ReferrerID     string    `json:"referrer_id"`
+	Code           string    `json:"code"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// TopReferrer is one row of the admin top-referrers report.
+type TopReferrer struct {
+	UserID         string    `json:"user_id"`
+	Email          string    `json:"email"`
+	FirstName      *string   `json:"first_name,omitempty"`
+	LastName       *string   `json:"last_name,omitempty"`
+	Invites        int       `json:"invites"`
+	LastReferralAt time.Time `json:"last_referral_at"`
+}
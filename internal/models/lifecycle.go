@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// LifecycleTemplate is the copy of a lifecycle message: the welcome
+// notification (WELCOME), the nudge to post or follow someone
+// (FIRST_POST_NUDGE), or the re-engagement push (WINBACK). Title and
+// message may use the placeholders listed in Placeholders, e.g.
+// "{first_name}".
+type LifecycleTemplate struct {
+	Kind         NotificationType `json:"kind"`
+	Title        string           `json:"title"`
+	Message      string           `json:"message"`
+	Enabled      bool             `json:"enabled"`
+	Placeholders []string         `json:"placeholders"`
+	// Customized is false while the built-in copy is in use.
+	Customized bool       `json:"customized"`
+	UpdatedBy  *string    `json:"updated_by,omitempty"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+}
+
+// UpdateLifecycleTemplateRequest replaces a lifecycle message's copy.
+type UpdateLifecycleTemplateRequest struct {
+	Title   string `json:"title" validate:"required,max=255"`
+	Message string `json:"message" validate:"required,max=1000"`
+	// Enabled defaults to true; false stops the message for everyone.
+	Enabled *bool `json:"enabled,omitempty"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// LinkPreview is the card shown for the first URL in a post.
+type LinkPreview struct {
+	URL         string    `json:"url"`
+	Title       *string   `json:"title,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	ImageURL    *string   `json:"image_url,omitempty"`
+	SiteName    *string   `json:"site_name,omitempty"`
+	FetchedAt   time.Time `json:"fetched_at"`
+	// Failed marks a cached fetch that produced nothing to show.
+	Failed bool `json:"-"`
+}
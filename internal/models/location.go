@@ -0,0 +1,102 @@
+package models
+
+import "time"
+
+// LocationLevel is the tier of a reference location.
+type LocationLevel string
+
+const (
+	LocationProvince     LocationLevel = "PROVINCE"
+	LocationDistrict     LocationLevel = "DISTRICT"
+	LocationNeighborhood LocationLevel = "NEIGHBORHOOD"
+)
+
+// ParentLevel returns the level a location of this level must hang under,
+// or "" for provinces.
+func (l LocationLevel) ParentLevel() LocationLevel {
+	switch l {
+	case LocationDistrict:
+		return LocationProvince
+	case LocationNeighborhood:
+		return LocationDistrict
+	}
+	return ""
+}
+
+// LocationRef is a province, district or neighborhood in the admin-managed
+// reference list (name = English; name_dari, name_pashto for i18n).
+type LocationRef struct {
+	ID         string        `json:"id"`
+	ParentID   *string       `json:"parent_id,omitempty"`
+	Level      LocationLevel `json:"level"`
+	Name       string        `json:"name"`
+	NameDari   *string       `json:"name_dari,omitempty"`
+	NamePashto *string       `json:"name_pashto,omitempty"`
+	IsActive   bool          `json:"is_active"`
+	Position   int           `json:"position"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+}
+
+// NameForLocale returns the name for the given locale (en, dari, pashto).
+// Falls back to Name (en) if the translation is missing.
+func (l *LocationRef) NameForLocale(locale string) string {
+	switch locale {
+	case LocaleDari:
+		if l.NameDari != nil && *l.NameDari != "" {
+			return *l.NameDari
+		}
+	case LocalePashto:
+		if l.NamePashto != nil && *l.NamePashto != "" {
+			return *l.NamePashto
+		}
+	}
+	return l.Name
+}
+
+// LocationOption is one entry in a client picker. Value is the canonical
+// English name clients send back in province / district fields; Label is
+// localized.
+type LocationOption struct {
+	ID       string        `json:"id"`
+	ParentID *string       `json:"parent_id,omitempty"`
+	Level    LocationLevel `json:"level"`
+	Value    string        `json:"value"`
+	Label    string        `json:"label"`
+}
+
+// CreateLocationRequest adds a province, district or neighborhood (admin).
+// Districts need a province parent; neighborhoods a district parent.
+type CreateLocationRequest struct {
+	Level      LocationLevel `json:"level" validate:"required,oneof=PROVINCE DISTRICT NEIGHBORHOOD"`
+	ParentID   *string       `json:"parent_id,omitempty" validate:"omitempty,uuid"`
+	Name       string        `json:"name" validate:"required,min=2,max=100"`
+	NameDari   *string       `json:"name_dari,omitempty" validate:"omitempty,max=100"`
+	NamePashto *string       `json:"name_pashto,omitempty" validate:"omitempty,max=100"`
+	Position   *int          `json:"position,omitempty" validate:"omitempty,min=0"`
+}
+
+// UpdateLocationRequest edits a location (admin). Nil fields are unchanged.
+type UpdateLocationRequest struct {
+	Name       *string `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
+	NameDari   *string `json:"name_dari,omitempty" validate:"omitempty,max=100"`
+	NamePashto *string `json:"name_pashto,omitempty" validate:"omitempty,max=100"`
+	IsActive   *bool   `json:"is_active,omitempty"`
+	Position   *int    `json:"position,omitempty" validate:"omitempty,min=0"`
+}
+
+// LocationFilter narrows a location listing. Nil ParentID lists provinces.
+type LocationFilter struct {
+	ParentID        *string
+	IncludeInactive bool
+}
+
+// ResolvedAddress is a reverse-geocoded address used to backfill the
+// country / province / district / neighborhood text on posts and profiles.
+// Empty fields are left as they are.
+type ResolvedAddress struct {
+	Country      string
+	Province     string
+	District     string
+	Neighborhood string
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// MediaScanJob is an image waiting for the classifier: a post attachment
+// from media_moderation_queue, a business image from
+// business_media_moderation_queue or a held avatar/cover from
+// profile_media_reviews. Exactly one of AttachmentID, BusinessMediaID and
+// ProfileMediaID is set.
+type MediaScanJob struct {
+	AttachmentID    string
+	BusinessMediaID string
+	ProfileMediaID  string
+	URL             string
+	MimeType        string
+	Attempts        int
+}
+
+// MediaScanLabels is the classifier output stored in a queue row's
+// auto_labels.
+type MediaScanLabels struct {
+	Classifier string    `json:"classifier"`
+	IsExplicit bool      `json:"is_explicit"`
+	TopClass   string    `json:"top_class,omitempty"`
+	TopScore   float64   `json:"top_score,omitempty"`
+	Skipped    string    `json:"skipped,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	ScannedAt  time.Time `json:"scanned_at"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// MutedTerm is a keyword or hashtag the user doesn't want to see. Posts
+// containing it are left out of their feeds and notifications.
+type MutedTerm struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"-"`
+	Term      string    `json:"term"` // lowercase; "#tag" mutes only the hashtag
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AddMutedTermRequest is the body for muting a keyword or hashtag.
+type AddMutedTermRequest struct {
+	Term string `json:"term" validate:"required,max=100"`
+}
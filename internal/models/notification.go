@@ -17,23 +17,45 @@ const (
 	NotificationTypeEventGoing     NotificationType = "EVENT_GOING"
 	NotificationTypeBusinessFollow NotificationType = "BUSINESS_FOLLOW"
 	NotificationTypeBusinessReview NotificationType = "BUSINESS_REVIEW"
+	NotificationTypeEndorsement    NotificationType = "ENDORSEMENT" // neighbor left a recommendation awaiting approval
 	NotificationTypePostShare      NotificationType = "POST_SHARE"
 	NotificationTypePollVote       NotificationType = "POLL_VOTE"
 	NotificationTypeNewPost        NotificationType = "NEW_POST"
 	NotificationTypeAdmin          NotificationType = "ADMIN"
 	NotificationTypeSellExpired    NotificationType = "SELL_EXPIRED"
+	NotificationTypeSafetyAlert    NotificationType = "SAFETY_ALERT" // ALERT post near the recipient
+	NotificationTypeNearbyEvent    NotificationType = "NEARBY_EVENT" // EVENT post broadcast to the recipient's area
+
+	// Event co-hosting
+	NotificationTypeEventHostInvite   NotificationType = "EVENT_HOST_INVITE"   // invited to co-host an event
+	NotificationTypeEventHostAccepted NotificationType = "EVENT_HOST_ACCEPTED" // invitee → event author
+	NotificationTypeEventHostMessage  NotificationType = "EVENT_HOST_MESSAGE"  // host → attendees
 
 	// Re-engagement (scheduled, proactive)
 	NotificationTypeEventReminder  NotificationType = "EVENT_REMINDER"   // T-24h / T-1h before an RSVP'd event
 	NotificationTypeWinback        NotificationType = "WINBACK"          // dormant-user bring-back
 	NotificationTypeFirstPostNudge NotificationType = "FIRST_POST_NUDGE" // encourage users who never posted
 	NotificationTypeMonthlyReport  NotificationType = "MONTHLY_REPORT"   // business owners' monthly insights summary
+	NotificationTypeWeeklyDigest   NotificationType = "WEEKLY_DIGEST"    // opt-in summary of the week in the user's area
 
 	// Business growth
 	NotificationTypeBusinessMilestone NotificationType = "BUSINESS_MILESTONE" // follower-count milestones (10, 25, 50, …)
 	// Business verification lifecycle
 	NotificationTypeBusinessVerified             NotificationType = "BUSINESS_VERIFIED"              // admin approved — tick granted
 	NotificationTypeBusinessVerificationRejected NotificationType = "BUSINESS_VERIFICATION_REJECTED" // admin rejected w/ reason
+	// Business claims
+	NotificationTypeBusinessClaimApproved NotificationType = "BUSINESS_CLAIM_APPROVED" // claimant now owns the business
+	NotificationTypeBusinessClaimRejected NotificationType = "BUSINESS_CLAIM_REJECTED" // admin rejected w/ reason
+	// Booking requests
+	NotificationTypeBookingRequest   NotificationType = "BOOKING_REQUEST"   // customer → owner
+	NotificationTypeBookingAccepted  NotificationType = "BOOKING_ACCEPTED"  // owner → customer
+	NotificationTypeBookingDeclined  NotificationType = "BOOKING_DECLINED"  // owner → customer
+	NotificationTypeBookingCancelled NotificationType = "BOOKING_CANCELLED" // customer → owner
+	// Help-request pledges
+	NotificationTypeHelpPledge           NotificationType = "HELP_PLEDGE"            // pledger → owner
+	NotificationTypeHelpPledgeConfirmed  NotificationType = "HELP_PLEDGE_CONFIRMED"  // owner → pledger
+	NotificationTypeHelpRequestFulfilled NotificationType = "HELP_REQUEST_FULFILLED" // owner → all pledgers
+	NotificationTypeHelpRequestUpdated   NotificationType = "HELP_REQUEST_UPDATED"   // owner edited → all pledgers
 
 	// Account / security
 	NotificationTypeWelcome            NotificationType = "WELCOME"
@@ -42,10 +64,18 @@ const (
 	NotificationTypeAccountSuspended   NotificationType = "ACCOUNT_SUSPENDED"
 	NotificationTypeAccountUnsuspended NotificationType = "ACCOUNT_UNSUSPENDED"
 
+	NotificationTypeTrustedContactAdded    NotificationType = "TRUSTED_CONTACT_ADDED"    // owner → new trusted contact
+	NotificationTypeAccountRecoveryCode    NotificationType = "ACCOUNT_RECOVERY_CODE"    // recovery code → trusted contact
+	NotificationTypeAccountRecoveryStarted NotificationType = "ACCOUNT_RECOVERY_STARTED" // warning → account owner
+	NotificationTypeOAuthAccessRevoked     NotificationType = "OAUTH_ACCESS_REVOKED"     // Apple/Google revoked the sign-in link
+
 	// Sales / shopping
 	NotificationTypeSellInterested NotificationType = "SELL_INTERESTED" // someone bookmarked your sell
 	NotificationTypeSellSold       NotificationType = "SELL_SOLD"       // seller marked as sold (for bookmarkers)
 	NotificationTypeSellExpiring   NotificationType = "SELL_EXPIRING"   // owner nudge ~48h before a listing expires
+	// NotificationTypeSellBookmarkMilestone tells a seller their listing was
+	// saved 5, 10 or 25 times.
+	NotificationTypeSellBookmarkMilestone NotificationType = "SELL_BOOKMARK_MILESTONE"
 
 	// Moderation
 	NotificationTypePostDeletedByAdmin     NotificationType = "POST_DELETED_BY_ADMIN"
@@ -63,6 +93,13 @@ const (
 	NotificationCategorySales    NotificationCategory = "SALES"
 	NotificationCategoryBusiness NotificationCategory = "BUSINESS"
 	NotificationCategoryAccount  NotificationCategory = "ACCOUNT"
+	// NotificationCategoryDigest is the weekly neighborhood digest. Unlike
+	// the other categories it is opt-in: no row means off.
+	NotificationCategoryDigest NotificationCategory = "DIGEST"
+	// NotificationCategoryTips covers lifecycle messages: the welcome
+	// notification, first-post nudges and re-engagement pushes. Turning it
+	// off stops those messages altogether, not just their push.
+	NotificationCategoryTips NotificationCategory = "TIPS"
 )
 
 // Notification represents a user notification
@@ -75,6 +112,7 @@ type Notification struct {
 	Data      map[string]interface{} `json:"data,omitempty"`
 	Read      bool                   `json:"read"`
 	CreatedAt time.Time              `json:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
 }
 
 // NotificationSetting represents user notification preferences
@@ -96,6 +134,7 @@ type NotificationResponse struct {
 	Data      map[string]interface{} `json:"data,omitempty"`
 	Read      bool                   `json:"read"`
 	CreatedAt time.Time              `json:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
 }
 
 // CreateNotificationRequest represents a request to create a notification
@@ -109,18 +148,29 @@ type CreateNotificationRequest struct {
 
 // UpdateNotificationSettingsRequest represents a request to update notification settings
 type UpdateNotificationSettingsRequest struct {
-	Category NotificationCategory `json:"category" validate:"required,oneof=POSTS MESSAGES EVENTS SALES BUSINESS ACCOUNT"`
+	Category NotificationCategory `json:"category" validate:"required,oneof=POSTS MESSAGES EVENTS SALES BUSINESS ACCOUNT DIGEST TIPS"`
 	PushPref bool                 `json:"push_pref"`
 }
 
 // GetNotificationsFilter represents filters for listing notifications
 type GetNotificationsFilter struct {
 	UserID     string
-	Type       *NotificationType
+	Types      []NotificationType // when set, only these types
 	UnreadOnly bool
-	BusinessID *string // when set, only notifications whose data.business_id matches (e.g. BUSINESS_FOLLOW)
-	Limit      int
-	Offset     int
+	BusinessID *string    // when set, only notifications whose data.business_id matches (e.g. BUSINESS_FOLLOW)
+	Cursor     *time.Time // keyset: only notifications created before this; replaces Offset
+	// UpdatedAfter switches to delta sync: notifications changed after
+	// this, oldest change first. Cursor and Offset are ignored.
+	UpdatedAfter *time.Time
+	Limit        int
+	Offset       int
+}
+
+// ClearNotificationsFilter selects the notifications a bulk clear deletes.
+type ClearNotificationsFilter struct {
+	UserID     string
+	ReadOnly   bool    // only notifications already read
+	BusinessID *string // same scoping as GetNotificationsFilter.BusinessID
 }
 
 // FCMTokenRequest represents a request to register/update FCM token
@@ -129,6 +179,19 @@ type FCMTokenRequest struct {
 	DeviceName *string `json:"device_name,omitempty" validate:"omitempty,max=100"`
 }
 
+// PushDevice is a device registered for push notifications, as listed to
+// its owner. The token itself is never returned; ID identifies the device
+// for revocation.
+type PushDevice struct {
+	ID                   string     `json:"id"`
+	Provider             string     `json:"provider"` // "fcm" or "apns"
+	DeviceName           *string    `json:"device_name,omitempty"`
+	RegisteredAt         *time.Time `json:"registered_at,omitempty"`
+	LastSuccessAt        *time.Time `json:"last_success_at,omitempty"`
+	LastFailureAt        *time.Time `json:"last_failure_at,omitempty"`
+	UnregisteredFailures int        `json:"unregistered_failures"`
+}
+
 // PushNotificationPayload represents the payload for push notifications
 type PushNotificationPayload struct {
 	Title       string                 `json:"title"`
@@ -150,5 +213,6 @@ func (n *Notification) ToNotificationResponse() *NotificationResponse {
 		Data:      n.Data,
 		Read:      n.Read,
 		CreatedAt: n.CreatedAt,
+		UpdatedAt: n.UpdatedAt,
 	}
 }
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// OAuthRevocation marks an OAuth-only account whose provider revoked our
+// access. The user has to sign in with the provider again.
+type OAuthRevocation struct {
+	UserID    string    `json:"user_id"`
+	Provider  string    `json:"provider"`
+	Reason    string    `json:"reason"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
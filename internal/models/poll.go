@@ -2,15 +2,36 @@ package models
 
 import "time"
 
+// PollResultsVisibility controls when voters see a poll's counts.
+type PollResultsVisibility string
+
+const (
+	// PollResultsAlways shows counts to everyone (the default).
+	PollResultsAlways PollResultsVisibility = "always"
+	// PollResultsAfterVote shows counts once the viewer has voted.
+	PollResultsAfterVote PollResultsVisibility = "after_vote"
+	// PollResultsAfterClose shows counts once the poll has closed; requires
+	// closes_at.
+	PollResultsAfterClose PollResultsVisibility = "after_close"
+)
+
 // Poll represents a poll attached to a PULL post
 type Poll struct {
-	ID        string     `json:"id"`
-	PostID    string     `json:"post_id"`
+	ID                string                `json:"id"`
+	PostID            string                `json:"post_id"`
+	ResultsVisibility PollResultsVisibility `json:"results_visibility"`
+	// ClosesAt ends voting; nil keeps the poll open.
+	ClosesAt  *time.Time `json:"closes_at,omitempty"`
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
 	DeletedAt *time.Time `json:"-"`
 }
 
+// IsClosed reports whether voting has ended at now.
+func (p *Poll) IsClosed(now time.Time) bool {
+	return p.ClosesAt != nil && !now.Before(*p.ClosesAt)
+}
+
 // PollOption represents an option in a poll
 type PollOption struct {
 	ID        string     `json:"id"`
@@ -34,7 +55,10 @@ type UserPoll struct {
 
 // CreatePollRequest represents a request to create a poll
 type CreatePollRequest struct {
-	Options []string `json:"options" validate:"required,min=2,max=10,dive,required,min=1,max=100"`
+	Options []string `json:"options" validate:"required,min=2,dive,required,min=1"`
+	// ResultsVisibility defaults to always.
+	ResultsVisibility PollResultsVisibility `json:"results_visibility,omitempty" validate:"omitempty,oneof=always after_vote after_close"`
+	ClosesAt          *time.Time            `json:"closes_at,omitempty"`
 }
 
 // VotePollRequest represents a request to vote on a poll
@@ -44,14 +68,20 @@ type VotePollRequest struct {
 
 // PollResponse represents a poll in API responses
 type PollResponse struct {
-	ID           string               `json:"id"`
-	PostID       string               `json:"post_id"`
-	Options      []*PollOptionResponse `json:"options"`
-	TotalVotes   int                  `json:"total_votes"`
-	UserVote     *string              `json:"user_vote,omitempty"` // Poll option ID that user voted for
-	HasVoted     bool                 `json:"has_voted"`
-	CreatedAt    time.Time            `json:"created_at"`
-	UpdatedAt    time.Time            `json:"updated_at"`
+	ID                string                `json:"id"`
+	PostID            string                `json:"post_id"`
+	Options           []*PollOptionResponse `json:"options"`
+	TotalVotes        int                   `json:"total_votes"`
+	UserVote          *string               `json:"user_vote,omitempty"` // Poll option ID that user voted for
+	HasVoted          bool                  `json:"has_voted"`
+	ResultsVisibility PollResultsVisibility `json:"results_visibility"`
+	// ResultsHidden is set when counts and percentages are withheld from
+	// this viewer until the visibility condition is met.
+	ResultsHidden bool       `json:"results_hidden"`
+	ClosesAt      *time.Time `json:"closes_at,omitempty"`
+	Closed        bool       `json:"closed"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
 }
 
 // PollOptionResponse represents a poll option in API responses
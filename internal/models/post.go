@@ -2,7 +2,10 @@ package models
 
 import (
 	"encoding/json"
+	"errors"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -15,8 +18,81 @@ const (
 	PostTypeEvent PostType = "EVENT"
 	PostTypeSell PostType = "SELL"
 	PostTypePull PostType = "PULL"
+	PostTypeLostFound PostType = "LOST_FOUND"
+	PostTypeAlert PostType = "ALERT"
+	PostTypeHelp PostType = "HELP"
 )
 
+// ListingState is a tab of the seller's own SELL listings
+// (GET /users/me/listings).
+type ListingState string
+
+const (
+	ListingStateActive  ListingState = "active"
+	ListingStateSold    ListingState = "sold"
+	ListingStateExpired ListingState = "expired"
+)
+
+// RelistClientTokenPrefix prefixes the idempotency token of a relisted
+// copy ("relist:{original post id}"), so relisting the same listing twice
+// returns the first copy.
+const RelistClientTokenPrefix = "relist:"
+
+// RelistClientToken is the client token of the post relisting postID.
+func RelistClientToken(postID string) string {
+	return RelistClientTokenPrefix + postID
+}
+
+// LostFoundKind says whether a LOST_FOUND post reports a lost or a found item
+type LostFoundKind string
+
+const (
+	LostFoundLost  LostFoundKind = "LOST"
+	LostFoundFound LostFoundKind = "FOUND"
+)
+
+// AlertUrgency is the urgency level of an ALERT post. It also sets how far
+// the alert push reaches (see AlertRadiusKm).
+type AlertUrgency string
+
+const (
+	AlertUrgencyLow      AlertUrgency = "LOW"
+	AlertUrgencyMedium   AlertUrgency = "MEDIUM"
+	AlertUrgencyHigh     AlertUrgency = "HIGH"
+	AlertUrgencyCritical AlertUrgency = "CRITICAL"
+)
+
+// alertUrgencyOrder lists urgencies from least to most urgent.
+var alertUrgencyOrder = []AlertUrgency{AlertUrgencyLow, AlertUrgencyMedium, AlertUrgencyHigh, AlertUrgencyCritical}
+
+// AtLeast returns this urgency and every more urgent level.
+func (u AlertUrgency) AtLeast() []string {
+	for i, level := range alertUrgencyOrder {
+		if level == u {
+			out := make([]string, 0, len(alertUrgencyOrder)-i)
+			for _, l := range alertUrgencyOrder[i:] {
+				out = append(out, string(l))
+			}
+			return out
+		}
+	}
+	return nil
+}
+
+// AlertRadiusKm returns the push radius for an alert of this urgency.
+func (u AlertUrgency) AlertRadiusKm() float64 {
+	switch u {
+	case AlertUrgencyCritical:
+		return 25
+	case AlertUrgencyHigh:
+		return 10
+	case AlertUrgencyMedium:
+		return 5
+	default:
+		return 2
+	}
+}
+
 // PostVisibility represents the visibility of a post
 type PostVisibility string
 
@@ -25,6 +101,7 @@ const (
 	VisibilityFriends  PostVisibility = "FRIENDS"
 	VisibilityPrivate  PostVisibility = "PRIVATE"
 	VisibilityViewOnly PostVisibility = "VIEW_ONLY" // FEED only: post is view-only (no likes/comments)
+	VisibilityGroup    PostVisibility = "GROUP"     // posted into a group; only shown in that group's feed
 )
 
 // EventState represents the state of an event
@@ -88,10 +165,38 @@ type Post struct {
 	// Client-generated idempotency token (see migration add_post_client_token).
 	ClientToken      *string         `json:"client_token,omitempty"`
 
+	// Catalog product attached at creation (see migration create_business_products).
+	BusinessProductID *string        `json:"business_product_id,omitempty"`
+
+	// Branch of the business the post is from (see migration create_business_locations).
+	BranchID         *string         `json:"branch_id,omitempty"`
+
+	// Group the post was targeted to (see migration create_groups).
+	GroupID          *string         `json:"group_id,omitempty"`
+
+	// Lost & found / alert fields (see migration add_lost_found_alert_posts).
+	// Last-seen and alert coordinates live in AddressLocation.
+	LostFoundKind    *LostFoundKind  `json:"lost_found_kind,omitempty"`
+	ItemDescription  *string         `json:"item_description,omitempty"`
+	LastSeenPlace    *string         `json:"last_seen_place,omitempty"`
+	LastSeenAt       *time.Time      `json:"last_seen_at,omitempty"`
+	Urgency          *AlertUrgency   `json:"urgency,omitempty"`
+
+	// Lang is the detected language of title + description (see
+	// pkg/langdetect); nil when there was too little text.
+	Lang             *string         `json:"lang,omitempty"`
+
 	// Timestamps
 	CreatedAt        time.Time       `json:"created_at"`
 	UpdatedAt        time.Time       `json:"updated_at"`
 	DeletedAt        *time.Time      `json:"-"`
+
+	// BumpedAt orders the recent feed; it equals CreatedAt until a SELL
+	// listing is renewed (see migration add_post_bumped_at).
+	BumpedAt         time.Time       `json:"-"`
+
+	// Version increments on every edit; see UpdatePostRequest.Version.
+	Version          int             `json:"version"`
 }
 
 // Attachment represents an attachment on a post
@@ -99,6 +204,8 @@ type Attachment struct {
 	ID        string     `json:"id"`
 	PostID    string     `json:"post_id"`
 	Photo     Photo      `json:"photo"`
+	Position  int        `json:"position"`
+	Caption   *string    `json:"caption,omitempty"`
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
 	DeletedAt *time.Time `json:"-"`
@@ -109,20 +216,29 @@ type Attachment struct {
 type AttachmentResponse struct {
 	ID    string `json:"id"`
 	Photo Photo  `json:"photo"`
+	// Position is the attachment's place in the post, from 0 (the cover).
+	Position int     `json:"position"`
+	Caption  *string `json:"caption,omitempty"`
+	// Sensitive marks an image flagged by moderation and not yet reviewed;
+	// Photo then points at a blurred copy.
+	Sensitive bool `json:"sensitive,omitempty"`
 }
 
 // PollRequestData represents poll data from mobile app
 type PollRequestData struct {
 	Question string   `json:"question"`
-	Options  []string `json:"options" validate:"required,min=2,max=10,dive,required,min=1,max=100"`
+	Options  []string `json:"options" validate:"required,min=2,dive,required,min=1"`
+	// ResultsVisibility defaults to always.
+	ResultsVisibility PollResultsVisibility `json:"results_visibility,omitempty" validate:"omitempty,oneof=always after_vote after_close"`
+	ClosesAt          *time.Time            `json:"closes_at,omitempty"`
 }
 
 // CreatePostRequest represents a request to create a post
 type CreatePostRequest struct {
 	// Content
 	Title       *string        `json:"title,omitempty" validate:"omitempty,max=255"`
-	Description *string        `json:"description,omitempty" validate:"omitempty,max=5000"`
-	Type        PostType       `json:"type" validate:"required,oneof=FEED EVENT SELL PULL"`
+	Description *string        `json:"description,omitempty"`
+	Type        PostType       `json:"type" validate:"required,oneof=FEED EVENT SELL PULL LOST_FOUND ALERT HELP"`
 	Visibility  PostVisibility `json:"visibility,omitempty" validate:"omitempty,oneof=PUBLIC FRIENDS PRIVATE VIEW_ONLY"`
 
 	// Sell-specific
@@ -141,9 +257,18 @@ type CreatePostRequest struct {
 	EndTime   *time.Time `json:"end_time,omitempty"`
 
 	// Poll-specific (for PULL posts)
-	PollOptions []string          `json:"poll_options,omitempty" validate:"omitempty,min=2,max=10,dive,required,min=1,max=100"`
+	PollOptions []string          `json:"poll_options,omitempty" validate:"omitempty,min=2,dive,required,min=1"`
 	Poll        *PollRequestData  `json:"poll,omitempty"`
 
+	// Lost & found specific; the last-seen coordinates go in latitude/longitude
+	LostFoundKind   *LostFoundKind `json:"lost_found_kind,omitempty" validate:"omitempty,oneof=LOST FOUND"`
+	ItemDescription *string        `json:"item_description,omitempty" validate:"omitempty,max=1000"`
+	LastSeenPlace   *string        `json:"last_seen_place,omitempty" validate:"omitempty,max=255"`
+	LastSeenAt      *time.Time     `json:"last_seen_at,omitempty"`
+
+	// Alert specific; the alert location goes in latitude/longitude
+	Urgency *AlertUrgency `json:"urgency,omitempty" validate:"omitempty,oneof=LOW MEDIUM HIGH CRITICAL"`
+
 	// Location (accept top-level latitude/longitude or nested location object from app)
 	Latitude     *float64             `json:"latitude,omitempty"`
 	Longitude    *float64             `json:"longitude,omitempty"`
@@ -154,7 +279,9 @@ type CreatePostRequest struct {
 	District     *string  `json:"district,omitempty" validate:"omitempty,max=100"`
 	Neighborhood *string  `json:"neighborhood,omitempty" validate:"omitempty,max=100"`
 
-	// Attachments: already uploaded. Accepts []string (URLs only) or []Photo (full metadata).
+	// Attachments: already uploaded, in display order (the first is the
+	// cover). Accepts []string (URLs only) or []Photo (full metadata); a Photo
+	// object may also carry a "caption".
 	// Use json.RawMessage so we can unmarshal flexibly in the service and avoid binding issues.
 	Attachments []json.RawMessage `json:"attachments,omitempty"`
 
@@ -168,6 +295,22 @@ type CreatePostRequest struct {
 	// post job and retries it until acked, so a stable per-job UUID lets the
 	// server dedupe a replayed create into the original post instead of a copy.
 	ClientToken *string `json:"client_token,omitempty" validate:"omitempty,max=64"`
+
+	// UploadSessionID names the upload session the attachments were
+	// uploaded in; creating the post claims them so they aren't cleaned up.
+	UploadSessionID *string `json:"upload_session_id,omitempty" validate:"omitempty,uuid"`
+
+	// BusinessProductID attaches a catalog product. On a business post it
+	// must be one of that business's products.
+	BusinessProductID *string `json:"business_product_id,omitempty" validate:"omitempty,uuid"`
+
+	// BranchID names the branch a business post is from. Posts without a
+	// location of their own take the branch's.
+	BranchID *string `json:"branch_id,omitempty" validate:"omitempty,uuid"`
+
+	// GroupID posts into a group the author is an active member of. Group
+	// posts get visibility GROUP and stay out of the home feed and search.
+	GroupID *string `json:"group_id,omitempty" validate:"omitempty,uuid"`
 }
 
 // CreatePostLocation is the nested location format sent by the app.
@@ -193,10 +336,35 @@ func ParseAttachmentPhoto(data json.RawMessage) (Photo, error) {
 	return p, nil
 }
 
+// MaxAttachmentCaptionLength caps an attachment caption, in characters.
+const MaxAttachmentCaptionLength = 500
+
+// ErrAttachmentCaptionTooLong is returned by ParseAttachmentCaption.
+var ErrAttachmentCaptionTooLong = errors.New("attachment caption must be at most 500 characters")
+
+// ParseAttachmentCaption reads the optional "caption" of an attachment
+// object. Bare URL strings have none; blank captions count as none.
+func ParseAttachmentCaption(data json.RawMessage) (*string, error) {
+	var obj struct {
+		Caption *string `json:"caption"`
+	}
+	if len(data) == 0 || json.Unmarshal(data, &obj) != nil || obj.Caption == nil {
+		return nil, nil
+	}
+	caption := strings.TrimSpace(*obj.Caption)
+	if caption == "" {
+		return nil, nil
+	}
+	if utf8.RuneCountInString(caption) > MaxAttachmentCaptionLength {
+		return nil, ErrAttachmentCaptionTooLong
+	}
+	return &caption, nil
+}
+
 // UpdatePostRequest represents a request to update a post
 type UpdatePostRequest struct {
 	Title       *string        `json:"title,omitempty" validate:"omitempty,max=255"`
-	Description *string        `json:"description,omitempty" validate:"omitempty,max=5000"`
+	Description *string        `json:"description,omitempty"`
 	Visibility  *PostVisibility `json:"visibility,omitempty" validate:"omitempty,oneof=PUBLIC FRIENDS PRIVATE VIEW_ONLY"`
 
 	// Sell-specific
@@ -216,6 +384,13 @@ type UpdatePostRequest struct {
 	EndDate   *time.Time `json:"end_date,omitempty"`
 	EndTime   *time.Time `json:"end_time,omitempty"`
 
+	// Lost & found / alert specific
+	LostFoundKind   *LostFoundKind `json:"lost_found_kind,omitempty" validate:"omitempty,oneof=LOST FOUND"`
+	ItemDescription *string        `json:"item_description,omitempty" validate:"omitempty,max=1000"`
+	LastSeenPlace   *string        `json:"last_seen_place,omitempty" validate:"omitempty,max=255"`
+	LastSeenAt      *time.Time     `json:"last_seen_at,omitempty"`
+	Urgency         *AlertUrgency  `json:"urgency,omitempty" validate:"omitempty,oneof=LOW MEDIUM HIGH CRITICAL"`
+
 	// Location (top-level or nested). When set, address_location is updated so post appears in discover.
 	Latitude   *float64             `json:"latitude,omitempty"`
 	Longitude  *float64             `json:"longitude,omitempty"`
@@ -228,9 +403,23 @@ type UpdatePostRequest struct {
 	// Attachment changes: newly uploaded photo objects / URLs, and IDs of attachments to remove.
 	Attachments        []json.RawMessage `json:"attachments,omitempty"`
 	DeletedAttachments []string          `json:"deleted_attachments,omitempty"`
+	// AttachmentOrder lists the remaining attachment IDs in their new
+	// order; attachments added in the same update follow them in request
+	// order.
+	AttachmentOrder []string `json:"attachment_order,omitempty" validate:"omitempty,max=20,dive,uuid"`
+	// AttachmentCaptions sets captions of existing attachments by ID; an
+	// empty caption clears it.
+	AttachmentCaptions map[string]string `json:"attachment_captions,omitempty" validate:"omitempty,max=20,dive,keys,uuid,endkeys,max=500"`
+	// AttachmentAltTexts sets alt text of existing attachments by ID; an
+	// empty string clears it.
+	AttachmentAltTexts map[string]string `json:"attachment_alt_texts,omitempty" validate:"omitempty,max=20,dive,keys,uuid,endkeys,max=1000"`
 
 	// PULL-specific: updated poll options (replaces existing options when present).
-	PollOptions []string `json:"poll_options,omitempty" validate:"omitempty,min=2,max=10,dive,required,min=1,max=100"`
+	PollOptions []string `json:"poll_options,omitempty" validate:"omitempty,min=2,dive,required,min=1"`
+
+	// Version is the post version the edit was based on. When set and the
+	// post has changed since, the update fails with 409 and the current post.
+	Version *int `json:"version,omitempty" validate:"omitempty,min=1"`
 }
 
 // PostResponse represents a post in API responses
@@ -239,6 +428,7 @@ type PostResponse struct {
 	Type        PostType        `json:"type"`
 	Title       *string         `json:"title,omitempty"`
 	Description *string         `json:"description,omitempty"`
+	Lang        *string         `json:"lang,omitempty"`
 	Visibility  PostVisibility  `json:"visibility"`
 	Status      bool            `json:"status"`
 
@@ -248,10 +438,20 @@ type PostResponse struct {
 	Author     *AuthorInfo   `json:"author,omitempty"`
 	BusinessID *string       `json:"business_id,omitempty"`
 	Business   *BusinessInfo `json:"business_profile,omitempty"`
+	GroupID    *string       `json:"group_id,omitempty"`
 
 	// Attachments (full objects with id so the client can reference them for deletion)
 	Attachments []AttachmentResponse `json:"attachments,omitempty"`
 
+	// Attached catalog product, when any
+	Product *BusinessProduct `json:"product,omitempty"`
+
+	// Business branch the post is from, when any
+	Branch *BusinessBranch `json:"branch,omitempty"`
+
+	// Preview of the first link in the title or description, when any
+	LinkPreview *LinkPreview `json:"link_preview,omitempty"`
+
 	// Sell-specific
 	Currency    *string         `json:"currency,omitempty"`
 	Price       *float64        `json:"price,omitempty"`
@@ -273,6 +473,17 @@ type PostResponse struct {
 	UserEventState  *EventInterestState  `json:"user_event_state,omitempty"`  // current user's interest: interested/going/not_interested
 	InterestedCount *int                 `json:"interested_count,omitempty"`
 	GoingCount      *int                 `json:"going_count,omitempty"`
+	Hosts           []*EventHost         `json:"hosts,omitempty"` // author first, then accepted co-hosts
+
+	// Lost & found / alert specific
+	LostFoundKind   *LostFoundKind `json:"lost_found_kind,omitempty"`
+	ItemDescription *string        `json:"item_description,omitempty"`
+	LastSeenPlace   *string        `json:"last_seen_place,omitempty"`
+	LastSeenAt      *time.Time     `json:"last_seen_at,omitempty"`
+	Urgency         *AlertUrgency  `json:"urgency,omitempty"`
+
+	// Help-request pledges
+	Help *HelpSummary `json:"help,omitempty"`
 
 	// Location
 	Location     *LocationInfo `json:"location,omitempty"`
@@ -284,14 +495,36 @@ type PostResponse struct {
 	LikedByMe      bool `json:"liked_by_me"`
 	BookmarkedByMe bool `json:"bookmarked_by_me"`
 	IsMine         bool `json:"is_mine"`
+	// ViewCount is only sent to the post's owner (or its business's owner).
+	ViewCount *int `json:"view_count,omitempty"`
+	// TotalBookmarks is how many users saved the post; owner only, like
+	// ViewCount.
+	TotalBookmarks *int `json:"total_bookmarks,omitempty"`
 
 	// Original post (for shares)
 	OriginalPost *PostResponse `json:"original_post,omitempty"`
 
+	// Matched terms wrapped in <mark>; search results only.
+	Highlight *SearchHighlight `json:"highlight,omitempty"`
+
 	// Timestamps
 	CreatedAt  time.Time  `json:"created_at"`
 	UpdatedAt  time.Time  `json:"updated_at"`
 	ExpiredAt  *time.Time `json:"expired_at,omitempty"`
+	// RenewedAt is set on SELL listings the seller renewed; the recent feed
+	// pages on it instead of created_at.
+	RenewedAt  *time.Time `json:"renewed_at,omitempty"`
+
+	// Version is sent back on edit so a stale edit gets a 409.
+	Version    int        `json:"version"`
+}
+
+// FeedTime is the timestamp the recent feed is ordered and paged by.
+func (p *PostResponse) FeedTime() time.Time {
+	if p.RenewedAt != nil {
+		return *p.RenewedAt
+	}
+	return p.CreatedAt
 }
 
 // AuthorInfo represents post author information
@@ -378,6 +611,13 @@ type PostLikesResponse struct {
 	Users      []*PostLikerResponse `json:"users"`
 }
 
+// PostViewCounts is returned after recording a view. Views count each
+// user once per day; unique viewers once ever.
+type PostViewCounts struct {
+	ViewCount     int `json:"view_count"`
+	UniqueViewers int `json:"unique_viewers"`
+}
+
 // PostBookmark represents a bookmark on a post
 type PostBookmark struct {
 	ID        string    `json:"id"`
@@ -396,6 +636,68 @@ type PostShare struct {
 	CreatedAt      time.Time  `json:"created_at"`
 }
 
+// ShareChannel is where an external share was sent, for analytics.
+type ShareChannel string
+
+const (
+	ShareChannelWhatsApp ShareChannel = "whatsapp"
+	ShareChannelTelegram ShareChannel = "telegram"
+	ShareChannelFacebook ShareChannel = "facebook"
+	ShareChannelSMS      ShareChannel = "sms"
+	ShareChannelCopyLink ShareChannel = "copy_link"
+	ShareChannelOther    ShareChannel = "other"
+)
+
+// ReorderAttachmentsRequest lists every attachment of a post in its new
+// order; the first becomes the cover.
+type ReorderAttachmentsRequest struct {
+	AttachmentIDs []string `json:"attachment_ids" validate:"required,min=1,max=20,dive,uuid"`
+}
+
+// ShareToChatRequest sends a post into one or more chats as a POST message.
+type ShareToChatRequest struct {
+	RecipientIDs []string `json:"recipient_ids" validate:"required,min=1,max=20,dive,uuid"`
+	Message      *string  `json:"message,omitempty" validate:"omitempty,max=1000"`
+}
+
+// ShareToChatFailure is a recipient the post couldn't be sent to.
+type ShareToChatFailure struct {
+	RecipientID string `json:"recipient_id"`
+	Error       string `json:"error"`
+}
+
+// ShareToChatResponse reports per-recipient results; one blocked or
+// throttled recipient doesn't fail the rest.
+type ShareToChatResponse struct {
+	Sent   []*MessageResponse    `json:"sent"`
+	Failed []*ShareToChatFailure `json:"failed"`
+}
+
+// ExternalShareRequest asks for a short link to share a post outside the app.
+type ExternalShareRequest struct {
+	Channel ShareChannel `json:"channel" validate:"omitempty,oneof=whatsapp telegram facebook sms copy_link other"`
+}
+
+// PostShareLink is a tracked short link for sharing a post externally.
+type PostShareLink struct {
+	Code         string       `json:"code"`
+	PostID       string       `json:"post_id"`
+	UserID       string       `json:"user_id"`
+	Channel      ShareChannel `json:"channel"`
+	ShareCount   int          `json:"share_count"`
+	ClickCount   int          `json:"click_count"`
+	CreatedAt    time.Time    `json:"created_at"`
+	LastSharedAt time.Time    `json:"last_shared_at"`
+}
+
+// ExternalShareResponse is the link to hand to the OS share sheet.
+type ExternalShareResponse struct {
+	URL            string       `json:"url"`
+	Code           string       `json:"code"`
+	Channel        ShareChannel `json:"channel"`
+	ExternalShares int          `json:"external_shares"`
+}
+
 // FeedFilter represents filters for fetching posts
 type FeedFilter struct {
 	Type         *PostType  `json:"type,omitempty"`
@@ -403,6 +705,13 @@ type FeedFilter struct {
 	BusinessID   *string    `json:"business_id,omitempty"`
 	CategoryID   *string    `json:"category_id,omitempty"`
 	Province     *string    `json:"province,omitempty"`
+	GroupID      *string    `json:"group_id,omitempty"` // group feed; nil excludes group posts
+	LostFoundKind *LostFoundKind `json:"lost_found_kind,omitempty"` // LOST_FOUND feed: LOST or FOUND only
+	MinUrgency    *AlertUrgency  `json:"min_urgency,omitempty"`     // ALERT feed: this urgency or higher
+	// Languages keeps posts in these languages plus posts with no detected
+	// language. nil applies the viewer's content languages; an empty
+	// slice means every language.
+	Languages    []string   `json:"languages,omitempty"`
 	SortBy       string     `json:"sort_by"` // recent, trending, nearby
 	Limit        int        `json:"limit"`
 	Offset       int        `json:"offset"`
@@ -421,6 +730,10 @@ type FeedFilter struct {
 	// of the last item from the previous page.
 	Cursor       *time.Time `json:"cursor,omitempty"`
 
+	// UpdatedAfter switches to delta sync: posts changed after this,
+	// oldest change first. SortBy, Cursor and Offset are ignored.
+	UpdatedAfter *time.Time `json:"-"`
+
 	// IncludeInactive bypasses the status = true filter so the post owner
 	// can see their own inactive/expired posts (e.g. the Expired tab).
 	IncludeInactive bool `json:"-"`
@@ -445,4 +758,8 @@ type FeedFilter struct {
 	// have blocked the viewer (bidirectional hide). Empty = no filter (used
 	// by public/anon endpoints).
 	ViewerID string `json:"-"`
+
+	// MutedTerms excludes posts whose title or description contains one of
+	// these lowercase keywords or hashtags as a whole word.
+	MutedTerms []string `json:"-"`
 }
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// BroadcastStatus values for post_broadcasts.status.
+const (
+	BroadcastStatusPendingApproval = "PENDING_APPROVAL"
+	BroadcastStatusQueued          = "QUEUED"
+	BroadcastStatusSending         = "SENDING"
+	BroadcastStatusSent            = "SENT"
+	BroadcastStatusRejected        = "REJECTED"
+)
+
+// IsBroadcastablePostType reports whether posts of type t may be pushed to a
+// radius: events and alerts are the only posts worth interrupting strangers
+// nearby for.
+func IsBroadcastablePostType(t PostType) bool {
+	return t == PostTypeEvent || t == PostTypeAlert
+}
+
+// PostBroadcast is a radius push of an EVENT or ALERT post. The centre is the
+// post's address at request time.
+type PostBroadcast struct {
+	ID          string  `json:"id"`
+	PostID      string  `json:"post_id"`
+	RequestedBy string  `json:"requested_by"`
+	RadiusKm    float64 `json:"radius_km"`
+	// ExcludeRadiusKm is the inner ring that already got the post's
+	// automatic nearby push (the ALERT urgency radius); zero for events.
+	ExcludeRadiusKm     float64    `json:"exclude_radius_km"`
+	Latitude            float64    `json:"latitude"`
+	Longitude           float64    `json:"longitude"`
+	Status              string     `json:"status"`
+	EstimatedRecipients int        `json:"estimated_recipients"`
+	MaxRecipients       int        `json:"max_recipients"`
+	SentCount           int        `json:"sent_count"`
+	CursorUserID        *string    `json:"-"`
+	RejectionReason     *string    `json:"rejection_reason,omitempty"`
+	ReviewedBy          *string    `json:"reviewed_by,omitempty"`
+	ReviewedAt          *time.Time `json:"reviewed_at,omitempty"`
+	CompletedAt         *time.Time `json:"completed_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// PostBroadcastListItem is the admin queue row: broadcast plus enough of the
+// post to review it without another fetch.
+type PostBroadcastListItem struct {
+	PostBroadcast
+	PostType        PostType `json:"post_type"`
+	PostTitle       *string  `json:"post_title,omitempty"`
+	PostDescription *string  `json:"post_description,omitempty"`
+}
+
+// CreatePostBroadcastRequest is the author's (or admin's) broadcast request.
+type CreatePostBroadcastRequest struct {
+	RadiusKm float64 `json:"radius_km" validate:"required,gt=0"`
+}
+
+// ReviewPostBroadcastRequest is the admin approve/reject payload.
+type ReviewPostBroadcastRequest struct {
+	Action string  `json:"action" validate:"required,oneof=approve reject"`
+	Reason *string `json:"reason,omitempty" validate:"omitempty,max=1000"`
+}
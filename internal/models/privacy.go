@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// PrivacyAudience is who may see a profile field besides its owner.
+type PrivacyAudience string
+
+const (
+	AudienceEveryone  PrivacyAudience = "EVERYONE"
+	AudienceFollowers PrivacyAudience = "FOLLOWERS"
+	AudienceNobody    PrivacyAudience = "NOBODY"
+)
+
+// PrivacySettings controls which contact and location fields other users
+// see on a profile and on post authors. Location covers country, province,
+// district and neighborhood. ProfileViewVisibility is which businesses list
+// the user among their viewers: all of them, the ones the user follows, or
+// none.
+type PrivacySettings struct {
+	UserID                string          `json:"-"`
+	LocationVisibility    PrivacyAudience `json:"location_visibility"`
+	EmailVisibility       PrivacyAudience `json:"email_visibility"`
+	PhoneVisibility       PrivacyAudience `json:"phone_visibility"`
+	ProfileViewVisibility PrivacyAudience `json:"profile_view_visibility"`
+	UpdatedAt             time.Time       `json:"updated_at"`
+}
+
+// DefaultPrivacySettings is what users who never changed their settings
+// get: location public, contact info private, business visits shown only
+// to businesses they follow.
+func DefaultPrivacySettings(userID string) *PrivacySettings {
+	return &PrivacySettings{
+		UserID:                userID,
+		LocationVisibility:    AudienceEveryone,
+		EmailVisibility:       AudienceNobody,
+		PhoneVisibility:       AudienceNobody,
+		ProfileViewVisibility: AudienceFollowers,
+	}
+}
+
+// UpdatePrivacySettingsRequest changes the given fields only.
+type UpdatePrivacySettingsRequest struct {
+	LocationVisibility *PrivacyAudience `json:"location_visibility,omitempty" validate:"omitempty,oneof=EVERYONE FOLLOWERS NOBODY"`
+	EmailVisibility    *PrivacyAudience `json:"email_visibility,omitempty" validate:"omitempty,oneof=EVERYONE FOLLOWERS NOBODY"`
+	PhoneVisibility    *PrivacyAudience `json:"phone_visibility,omitempty" validate:"omitempty,oneof=EVERYONE FOLLOWERS NOBODY"`
+	// FOLLOWERS: only businesses the user follows.
+	ProfileViewVisibility *PrivacyAudience `json:"profile_view_visibility,omitempty" validate:"omitempty,oneof=EVERYONE FOLLOWERS NOBODY"`
+}
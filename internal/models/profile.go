@@ -1,6 +1,11 @@
 package models
 
-import "time"
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
 
 // LocationCoordinates represents latitude and longitude
 type LocationCoordinates struct {
@@ -31,6 +36,19 @@ type UpdateProfileRequest struct {
 	Latitude  *float64 `json:"latitude,omitempty" validate:"omitempty,latitude"`
 	Longitude *float64 `json:"longitude,omitempty" validate:"omitempty,longitude"`
 	IsComplete *bool   `json:"is_complete,omitempty"`
+	// AvatarAltText / CoverAltText set the alt text of the current images;
+	// an empty string clears it.
+	AvatarAltText *string `json:"avatar_alt_text,omitempty" validate:"omitempty,max=1000"`
+	CoverAltText  *string `json:"cover_alt_text,omitempty" validate:"omitempty,max=1000"`
+	// Version is the profile version the edit was based on. When set and the
+	// profile has changed since, the update fails with 409 and the current profile.
+	Version *int `json:"version,omitempty" validate:"omitempty,min=1"`
+}
+
+// ContentLanguages is the set of languages a user reads. Feed and search
+// show posts in these languages by default; empty means every language.
+type ContentLanguages struct {
+	Languages []string `json:"languages" validate:"max=4,dive,oneof=fa ps ar en"`
 }
 
 // FullProfileResponse represents complete profile information
@@ -39,6 +57,10 @@ type FullProfileResponse struct {
 	FirstName    *string    `json:"first_name,omitempty"`
 	LastName     *string    `json:"last_name,omitempty"`
 	FullName     string     `json:"full_name"`
+	Handle       *string    `json:"handle,omitempty"`
+	// HandleChangeableAt is when the handle can next be changed; only set
+	// on the user's own profile during the rename cooldown.
+	HandleChangeableAt *time.Time `json:"handle_changeable_at,omitempty"`
 	Avatar       *Photo     `json:"avatar,omitempty"`
 	AvatarColor  *string    `json:"avatar_color,omitempty"`
 	Cover        *Photo     `json:"cover,omitempty"`
@@ -62,6 +84,7 @@ type FullProfileResponse struct {
 	MissingFields     []string `json:"missing_fields,omitempty"`
 	CreatedAt    time.Time  `json:"created_at"`
 	UpdatedAt    time.Time  `json:"updated_at"`
+	Version      int        `json:"version"`
 
 	// User info
 	Email         string    `json:"email"`
@@ -79,6 +102,10 @@ type FullProfileResponse struct {
 	FollowersCount  int `json:"followers_count"`
 	FollowingCount  int `json:"following_count"`
 	PostsCount      int `json:"posts_count"`
+	// EndorsementsCount counts approved endorsements only.
+	EndorsementsCount int `json:"endorsements_count"`
+	// InvitesCount counts users who signed up with this user's invite code.
+	InvitesCount int `json:"invites_count"`
 
 	// Relationship status (relative to authenticated user)
 	// No omitempty so client always receives block status for Block/Unblock UI
@@ -97,6 +124,7 @@ type UserSearchResult struct {
 	FirstName *string `json:"first_name,omitempty"`
 	LastName  *string `json:"last_name,omitempty"`
 	FullName  string  `json:"full_name"`
+	Handle    *string `json:"handle,omitempty"`
 	Avatar    *Photo  `json:"avatar,omitempty"`
 	About     *string `json:"about,omitempty"`
 	Province  *string `json:"province,omitempty"`
@@ -106,6 +134,12 @@ type UserSearchResult struct {
 	IsFollowedBy bool `json:"is_followed_by"`
 }
 
+// UpdateHandleRequest sets the user's @handle. A leading @ and case are
+// ignored.
+type UpdateHandleRequest struct {
+	Handle string `json:"handle" validate:"required,max=31"`
+}
+
 // UploadImageRequest represents an image upload request
 type UploadImageRequest struct {
 	ImageType string `json:"image_type" validate:"required,oneof=avatar cover"`
@@ -114,6 +148,40 @@ type UploadImageRequest struct {
 // UploadImageResponse represents an image upload response
 type UploadImageResponse struct {
 	Photo *Photo `json:"photo"`
+	// Warnings flag things the client should prompt the user to fix, such
+	// as UploadWarningAltTextMissing. The upload itself succeeded.
+	Warnings []string `json:"warnings,omitempty"`
+	// Pending is set when the image is held for review; the old image is
+	// shown until it is approved.
+	Pending bool `json:"pending,omitempty"`
+}
+
+// UploadWarningAltTextMissing is returned for images uploaded without alt text.
+const UploadWarningAltTextMissing = "alt_text_missing"
+
+// NewUploadImageResponse wraps an uploaded photo, warning when it has no
+// alt text.
+func NewUploadImageResponse(photo *Photo) *UploadImageResponse {
+	resp := &UploadImageResponse{Photo: photo}
+	if photo != nil && photo.AltText == "" && !strings.HasPrefix(photo.MimeType, "video/") {
+		resp.Warnings = []string{UploadWarningAltTextMissing}
+	}
+	return resp
+}
+
+// MaxAltTextLength caps a photo's alt text, in characters.
+const MaxAltTextLength = 1000
+
+// ErrAltTextTooLong is returned by NormalizeAltText.
+var ErrAltTextTooLong = errors.New("alt_text must be at most 1000 characters")
+
+// NormalizeAltText trims alt text and checks its length. Blank means none.
+func NormalizeAltText(raw string) (string, error) {
+	alt := strings.TrimSpace(raw)
+	if utf8.RuneCountInString(alt) > MaxAltTextLength {
+		return "", ErrAltTextTooLong
+	}
+	return alt, nil
 }
 
 // DefaultAvatarColorForProfile returns a deterministic color for profileID when DB has no avatar_color (e.g. existing users).
@@ -144,6 +212,7 @@ func ToFullProfileResponse(user *User, profile *Profile) *FullProfileResponse {
 		FirstName:     profile.FirstName,
 		LastName:      profile.LastName,
 		FullName:      profile.FullName(),
+		Handle:        profile.Handle,
 		Avatar:        profile.Avatar,
 		AvatarColor:   avatarColor,
 		Cover:         profile.Cover,
@@ -158,6 +227,7 @@ func ToFullProfileResponse(user *User, profile *Profile) *FullProfileResponse {
 		IsComplete:    profile.IsComplete,
 		CreatedAt:     profile.CreatedAt,
 		UpdatedAt:     profile.UpdatedAt,
+		Version:       profile.Version,
 		Email:            user.Email,
 		Phone:            user.Phone,
 		PhoneCountryCode: user.PhoneCountryCode,
@@ -227,6 +297,7 @@ func ToUserSearchResult(profile *Profile) *UserSearchResult {
 		FirstName: profile.FirstName,
 		LastName:  profile.LastName,
 		FullName:  profile.FullName(),
+		Handle:    profile.Handle,
 		Avatar:    profile.Avatar,
 		About:     profile.About,
 		Province:  profile.Province,
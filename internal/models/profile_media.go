@@ -0,0 +1,38 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Owners and kinds of held profile media.
+const (
+	ProfileMediaOwnerUser     = "user"
+	ProfileMediaOwnerBusiness = "business"
+
+	ProfileMediaAvatar = "avatar"
+	ProfileMediaCover  = "cover"
+)
+
+// ProfileMediaReview is an uploaded avatar or cover held for review. The
+// profile keeps its old image until the review is approved.
+type ProfileMediaReview struct {
+	ID        string `json:"id"`
+	OwnerType string `json:"owner_type"`
+	OwnerID   string `json:"owner_id"`
+	// OwnerName is the user's or business's display name, for the admin
+	// queue.
+	OwnerName   string     `json:"owner_name"`
+	Kind        string     `json:"kind"`
+	Photo       Photo      `json:"photo"`
+	EnqueuedAt  time.Time  `json:"enqueued_at"`
+	Status      string     `json:"status"`
+	ReviewedAt  *time.Time `json:"reviewed_at,omitempty"`
+	ReviewedBy  *string    `json:"reviewed_by,omitempty"`
+	ReviewNotes *string    `json:"review_notes,omitempty"`
+	// Flagged is set when the classifier judged the image unsafe;
+	// AutoLabels holds its output once the image was scanned.
+	Flagged    bool            `json:"flagged"`
+	AutoLabels json.RawMessage `json:"auto_labels,omitempty"`
+	BlurredURL *string         `json:"blurred_url,omitempty"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewUploadImageResponse_Warnings(t *testing.T) {
+	cases := []struct {
+		name  string
+		photo *Photo
+		warn  bool
+	}{
+		{"image without alt text", &Photo{URL: "a.webp", MimeType: "image/webp"}, true},
+		{"image with alt text", &Photo{URL: "a.webp", MimeType: "image/webp", AltText: "A cat"}, false},
+		{"video", &Photo{URL: "a.mp4", MimeType: "video/mp4"}, false},
+	}
+	for _, c := range cases {
+		got := NewUploadImageResponse(c.photo)
+		if (len(got.Warnings) > 0) != c.warn {
+			t.Errorf("%s: warnings = %v, want warning %v", c.name, got.Warnings, c.warn)
+		}
+	}
+}
+
+func TestNormalizeAltText(t *testing.T) {
+	if got, err := NormalizeAltText("  A cat  "); err != nil || got != "A cat" {
+		t.Errorf("NormalizeAltText trimmed = %q, %v", got, err)
+	}
+	if _, err := NormalizeAltText(strings.Repeat("é", MaxAltTextLength)); err != nil {
+		t.Errorf("NormalizeAltText at the limit: %v", err)
+	}
+	if _, err := NormalizeAltText(strings.Repeat("x", MaxAltTextLength+1)); err != ErrAltTextTooLong {
+		t.Errorf("NormalizeAltText over the limit = %v, want ErrAltTextTooLong", err)
+	}
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// ProfileViewStats is the owner's view of who looked at their personal
+// profile: daily counts only, never the viewers.
+type ProfileViewStats struct {
+	Days  int          `json:"days"`
+	Views []DailyCount `json:"views"`
+	Total int          `json:"total"`
+}
+
+// BusinessViewer is a user in a business's viewer list. Only viewers whose
+// privacy settings share the visit with the business are listed.
+type BusinessViewer struct {
+	UserID      string    `json:"user_id"`
+	FirstName   *string   `json:"first_name,omitempty"`
+	LastName    *string   `json:"last_name,omitempty"`
+	FullName    string    `json:"full_name"`
+	Avatar      *Photo    `json:"avatar,omitempty"`
+	AvatarColor *string   `json:"avatar_color,omitempty"`
+	IsFollower  bool      `json:"is_follower"`
+	VisitDays   int       `json:"visit_days"` // days with a visit in the window
+	LastViewed  time.Time `json:"last_viewed_at"`
+}
+
+// BusinessViewersResponse is a page of a business's viewer list. Hidden
+// counts the viewers in the window whose privacy settings keep them off it.
+type BusinessViewersResponse struct {
+	Items  []*BusinessViewer `json:"items"`
+	Total  int               `json:"total"`
+	Hidden int               `json:"hidden"`
+	Days   int               `json:"days"`
+	Limit  int               `json:"limit"`
+	Offset int               `json:"offset"`
+}
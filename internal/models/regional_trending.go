@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// Kinds of ranked items in the regional trending rollup.
+const (
+	TrendingKindPost     = "POST"
+	TrendingKindEvent    = "EVENT"
+	TrendingKindBusiness = "BUSINESS"
+)
+
+// RegionalActivity is a region's activity over the rollup window. District
+// is "" for the province-wide totals.
+type RegionalActivity struct {
+	Province       string    `json:"province"`
+	District       string    `json:"district,omitempty"`
+	NewPosts       int       `json:"new_posts"`
+	UpcomingEvents int       `json:"upcoming_events"`
+	NewBusinesses  int       `json:"new_businesses"`
+	ComputedAt     time.Time `json:"computed_at"`
+}
+
+// TrendingPost is a ranked post or event in a region.
+type TrendingPost struct {
+	ID            string    `json:"id"`
+	Type          PostType  `json:"type"`
+	Title         *string   `json:"title,omitempty"`
+	Description   *string   `json:"description,omitempty"`
+	Province      *string   `json:"province,omitempty"`
+	District      *string   `json:"district,omitempty"`
+	Neighborhood  *string   `json:"neighborhood,omitempty"`
+	TotalLikes    int       `json:"total_likes"`
+	TotalComments int       `json:"total_comments"`
+	TotalShares   int       `json:"total_shares"`
+	GoingCount    int       `json:"going_count,omitempty"`
+	StartDate     *string   `json:"start_date,omitempty"`
+	StartTime     *string   `json:"start_time,omitempty"`
+	Score         float64   `json:"score"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// TrendingBusiness is a business that joined the region during the window.
+type TrendingBusiness struct {
+	ID           string    `json:"id"`
+	UserID       string    `json:"user_id"`
+	Name         string    `json:"name"`
+	Avatar       *Photo    `json:"avatar,omitempty"`
+	Province     *string   `json:"province,omitempty"`
+	District     *string   `json:"district,omitempty"`
+	Neighborhood *string   `json:"neighborhood,omitempty"`
+	TotalFollow  int       `json:"total_follow"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// RegionalTrendingResponse is GET /discover/trending. Activity is nil until
+// the first rollup covering the region has run.
+type RegionalTrendingResponse struct {
+	Province   string              `json:"province"`
+	District   string              `json:"district,omitempty"`
+	Activity   *RegionalActivity   `json:"activity,omitempty"`
+	Posts      []*TrendingPost     `json:"posts"`
+	Events     []*TrendingPost     `json:"events"`
+	Businesses []*TrendingBusiness `json:"businesses"`
+}
+
+// DigestRecipient is a user who opted into the weekly neighborhood digest,
+// with the region their profile is in.
+type DigestRecipient struct {
+	UserID   string
+	Province string
+	District string
+}
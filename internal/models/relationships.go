@@ -64,6 +64,37 @@ type BlockedUserResponse struct {
 	CreatedAt time.Time  `json:"blocked_at"`
 }
 
+// Block list limits.
+const (
+	// MaxBlockedUsers caps how many users one account can block.
+	MaxBlockedUsers = 5000
+	// MaxBulkBlockUsers caps the user IDs in one bulk block request.
+	MaxBulkBlockUsers = 500
+)
+
+// BulkBlockRequest blocks many users at once, e.g. from an imported
+// community blocklist.
+type BulkBlockRequest struct {
+	UserIDs []string `json:"user_ids"`
+}
+
+// BulkBlockResponse reports the outcome of a bulk block.
+type BulkBlockResponse struct {
+	// Blocked counts the users newly blocked.
+	Blocked int `json:"blocked"`
+	// Skipped counts IDs already blocked, unknown or deleted, or the caller's
+	// own.
+	Skipped int `json:"skipped"`
+}
+
+// BlockListExport is the caller's whole block list; user_ids can be posted
+// back to the bulk block endpoint.
+type BlockListExport struct {
+	UserIDs    []string  `json:"user_ids"`
+	Count      int       `json:"count"`
+	ExportedAt time.Time `json:"exported_at"`
+}
+
 // RelationshipStatus represents the relationship status between two users
 type RelationshipStatus struct {
 	IsFollowing  bool `json:"is_following"`
@@ -60,6 +60,24 @@ type BusinessReport struct {
 	UpdatedAt          time.Time    `json:"updated_at"`
 }
 
+// MessageReport represents a report for a chat message. MessageContent,
+// MessageType and SenderID are snapshots taken when the report is filed.
+type MessageReport struct {
+	ID                 string       `json:"id"`
+	UserID             string       `json:"user_id"`
+	MessageID          string       `json:"message_id"`
+	ConversationID     string       `json:"conversation_id"`
+	SenderID           string       `json:"sender_id"`
+	MessageContent     *string      `json:"message_content,omitempty"`
+	MessageType        MessageType  `json:"message_type"`
+	MessageSentAt      time.Time    `json:"message_sent_at"`
+	Reason             string       `json:"reason"`
+	AdditionalComments *string      `json:"additional_comments,omitempty"`
+	ReportStatus       ReportStatus `json:"report_status"`
+	CreatedAt          time.Time    `json:"created_at"`
+	UpdatedAt          time.Time    `json:"updated_at"`
+}
+
 // CreatePostReportRequest represents a request to report a post
 type CreatePostReportRequest struct {
 	Reason             string  `json:"reason" validate:"required,max=100"`
@@ -84,6 +102,12 @@ type CreateBusinessReportRequest struct {
 	AdditionalComments *string `json:"additional_comments,omitempty" validate:"omitempty,max=500"`
 }
 
+// CreateMessageReportRequest represents a request to report a chat message
+type CreateMessageReportRequest struct {
+	Reason             string  `json:"reason" validate:"required,max=100"`
+	AdditionalComments *string `json:"additional_comments,omitempty" validate:"omitempty,max=500"`
+}
+
 // UpdateReportStatusRequest represents a request to update report status
 type UpdateReportStatusRequest struct {
 	Status ReportStatus `json:"status" validate:"required,oneof=PENDING REVIEWING RESOLVED REJECTED"`
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"regexp"
+	"strings"
+	"time"
+)
 
 // SearchType represents the type of search
 type SearchType string
@@ -21,6 +25,17 @@ type SearchRequest struct {
 	Latitude  *float64   `json:"latitude" validate:"omitempty,latitude"`
 	Longitude *float64   `json:"longitude" validate:"omitempty,longitude"`
 	RadiusKm  *float64   `json:"radius_km" validate:"omitempty,min=0,max=1000"`
+
+	// Post filter chips. Facet counts ignore these so every chip keeps
+	// its count while one is selected.
+	PostType   *PostType `json:"post_type" validate:"omitempty,oneof=FEED EVENT SELL PULL LOST_FOUND ALERT HELP"`
+	CategoryID *string   `json:"category_id" validate:"omitempty,uuid"`
+	Province   *string   `json:"province" validate:"omitempty,max=100"`
+	// Languages restricts posts like FeedFilter.Languages: nil applies the
+	// user's content languages, empty means every language.
+	Languages []string `json:"languages"`
+	// IncludeFacets adds post facet counts; clients ask on the first page.
+	IncludeFacets bool `json:"include_facets"`
 }
 
 // SearchResponse represents aggregated search results
@@ -29,6 +44,29 @@ type SearchResponse struct {
 	Users      []*UserSearchResult     `json:"users"`
 	Businesses []*BusinessSearchResult `json:"businesses"`
 	Total      int                     `json:"total"`
+	Facets     *SearchFacets           `json:"facets,omitempty"`
+}
+
+// SearchHighlight holds ts_headline snippets of a matched post.
+type SearchHighlight struct {
+	Title       *string `json:"title,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// FacetCount is one filter chip: the value to filter by, a display label
+// where the value is an id, and how many posts match.
+type FacetCount struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int    `json:"count"`
+}
+
+// SearchFacets counts matching posts per type, category and province,
+// largest first. Only publicly visible posts are counted.
+type SearchFacets struct {
+	Types      []*FacetCount `json:"types"`
+	Categories []*FacetCount `json:"categories"`
+	Provinces  []*FacetCount `json:"provinces"`
 }
 
 // BusinessSearchResult represents a business in search results
@@ -44,6 +82,7 @@ type BusinessSearchResult struct {
 	Categories     []string  `json:"categories,omitempty"`
 	Location       *Location `json:"location,omitempty"`
 	Distance       *float64  `json:"distance,omitempty"` // Distance in km from search point
+	NearestBranch  *NearestBranch `json:"nearest_branch,omitempty"` // Set when a branch is nearer than the main address
 	TotalFollow    int       `json:"total_follow"`
 	TotalViews     int       `json:"total_views"`
 	IsFollowing    bool      `json:"is_following,omitempty"`
@@ -65,8 +104,11 @@ type DiscoverRequest struct {
 	Longitude float64        `json:"longitude" validate:"required,longitude"`
 	RadiusKm  float64        `json:"radius_km" validate:"required,min=0.1,max=100"`
 	Filter    DiscoverFilter `json:"filter" validate:"omitempty,oneof=all business event sell"`
-	Type      *PostType      `json:"type" validate:"omitempty,oneof=FEED EVENT SELL PULL"`
+	Type      *PostType      `json:"type" validate:"omitempty,oneof=FEED EVENT SELL PULL LOST_FOUND ALERT HELP"`
 	Limit     int            `json:"limit" validate:"omitempty,min=1,max=500"`
+	// Province picks the featured businesses; empty falls back to the
+	// viewer's province.
+	Province string `json:"province" validate:"omitempty,max=100"`
 }
 
 // DiscoverResponse represents discovery results
@@ -74,6 +116,9 @@ type DiscoverResponse struct {
 	Posts      []*DiscoverPost     `json:"posts"`
 	Businesses []*DiscoverBusiness `json:"businesses"`
 	Total      int                 `json:"total"`
+	// Featured is the province's featured businesses, in placement order;
+	// not counted in Total.
+	Featured []*FeaturedBusinessCard `json:"featured,omitempty"`
 }
 
 // DiscoverPost represents a post marker on the map
@@ -103,6 +148,7 @@ type DiscoverBusiness struct {
 	Cover       *Photo    `json:"cover,omitempty"`
 	Location    *Location `json:"location"`
 	Distance    float64   `json:"distance"` // Distance in km from search point
+	NearestBranch *NearestBranch `json:"nearest_branch,omitempty"` // Set when the pin is a branch rather than the main address
 	Categories  []string  `json:"categories,omitempty"`
 	TotalFollow int       `json:"total_follow"`
 }
@@ -126,4 +172,152 @@ type SearchFilter struct {
 	Latitude   *float64
 	Longitude  *float64
 	RadiusKm   *float64
+	PostType   *PostType
+	CategoryID *string
+	Province   *string
+	// Languages restricts posts like FeedFilter.Languages; only non-empty
+	// lists filter.
+	Languages []string
+}
+
+// Suggestion kinds returned by search autocomplete.
+const (
+	SuggestionUser     = "user"
+	SuggestionBusiness = "business"
+	SuggestionCategory = "category"
+	SuggestionHashtag  = "hashtag"
+)
+
+// SearchSuggestion is one autocomplete entry. Popularity is what it was
+// ranked by: followers for users and businesses, posts for categories and
+// hashtags.
+type SearchSuggestion struct {
+	Kind        string  `json:"kind"`
+	ID          string  `json:"id,omitempty"`
+	Label       string  `json:"label"`
+	Handle      *string `json:"handle,omitempty"` // users only
+	Avatar      *Photo  `json:"avatar,omitempty"`
+	AvatarColor *string `json:"avatar_color,omitempty"`
+	Popularity  int     `json:"popularity"`
+}
+
+// SearchSuggestResponse groups autocomplete entries by kind. Recent holds
+// the caller's own matching recent searches (empty when signed out).
+type SearchSuggestResponse struct {
+	Recent     []*RecentSearch     `json:"recent"`
+	Users      []*SearchSuggestion `json:"users"`
+	Businesses []*SearchSuggestion `json:"businesses"`
+	Categories []*SearchSuggestion `json:"categories"`
+	Hashtags   []*SearchSuggestion `json:"hashtags"`
+}
+
+// RecentSearch is a query the user ran, stored normalized.
+type RecentSearch struct {
+	Query      string    `json:"query"`
+	SearchedAt time.Time `json:"searched_at"`
+}
+
+// NormalizeSearchQuery trims, lower-cases and collapses whitespace so the
+// same search typed twice is stored once.
+func NormalizeSearchQuery(q string) string {
+	return strings.ToLower(strings.Join(strings.Fields(q), " "))
+}
+
+var hashtagPattern = regexp.MustCompile(`#([\p{L}\p{M}\p{N}_]+)`)
+
+// maxHashtagsPerPost caps how many tags one post contributes, so a post
+// stuffed with tags can't flood suggestions.
+const maxHashtagsPerPost = 10
+
+// ExtractHashtags returns the distinct lower-cased hashtags (without '#')
+// in texts, in order of first appearance.
+func ExtractHashtags(texts ...*string) []string {
+	var tags []string
+	seen := map[string]bool{}
+	for _, t := range texts {
+		if t == nil {
+			continue
+		}
+		for _, m := range hashtagPattern.FindAllStringSubmatch(*t, -1) {
+			tag := strings.ToLower(m[1])
+			if seen[tag] || len([]rune(tag)) > 50 {
+				continue
+			}
+			seen[tag] = true
+			tags = append(tags, tag)
+			if len(tags) == maxHashtagsPerPost {
+				return tags
+			}
+		}
+	}
+	return tags
+}
+
+// AdminSearchEntity is the entity-type badge of an admin search result.
+type AdminSearchEntity string
+
+const (
+	AdminSearchPost     AdminSearchEntity = "post"
+	AdminSearchComment  AdminSearchEntity = "comment"
+	AdminSearchUser     AdminSearchEntity = "user"
+	AdminSearchBusiness AdminSearchEntity = "business"
+	// AdminSearchMessage covers chat messages that were reported; other
+	// messages stay private.
+	AdminSearchMessage AdminSearchEntity = "message"
+)
+
+// AllAdminSearchEntities lists every searchable entity, in badge order.
+var AllAdminSearchEntities = []AdminSearchEntity{
+	AdminSearchPost, AdminSearchComment, AdminSearchUser, AdminSearchBusiness, AdminSearchMessage,
+}
+
+// Admin permission keys (see custom_roles.permissions) that gate each
+// entity in admin search.
+const (
+	PermPostsView      = "POSTS_VIEW"
+	PermCommentsView   = "COMMENTS_VIEW"
+	PermUsersView      = "USERS_VIEW"
+	PermBusinessesView = "BUSINESSES_VIEW"
+	PermReportsView    = "REPORTS_VIEW"
+)
+
+// AdminSearchPermission is the permission needed to search entity.
+func AdminSearchPermission(entity AdminSearchEntity) string {
+	switch entity {
+	case AdminSearchPost:
+		return PermPostsView
+	case AdminSearchComment:
+		return PermCommentsView
+	case AdminSearchUser:
+		return PermUsersView
+	case AdminSearchBusiness:
+		return PermBusinessesView
+	case AdminSearchMessage:
+		return PermReportsView
+	}
+	return ""
+}
+
+// AdminSearchFilter is what the repository searches. Entities is never
+// empty; the service resolves it from the request and the admin's scopes.
+type AdminSearchFilter struct {
+	Query    string
+	Entities []AdminSearchEntity
+	Limit    int
+	Offset   int
+}
+
+// AdminSearchResult is one hit of admin search, whatever the entity.
+// ParentID is the post of a comment or the conversation of a message.
+// Hidden marks content soft-hidden by moderation, a deleted message, or an
+// inactive or locked account.
+type AdminSearchResult struct {
+	Type      AdminSearchEntity `json:"type"`
+	ID        string            `json:"id"`
+	Title     string            `json:"title"`
+	Snippet   *string           `json:"snippet,omitempty"`
+	OwnerID   *string           `json:"owner_id,omitempty"`
+	ParentID  *string           `json:"parent_id,omitempty"`
+	Hidden    bool              `json:"hidden"`
+	CreatedAt time.Time         `json:"created_at"`
 }
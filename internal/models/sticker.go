@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// StickerPack is a named set of stickers in the chat sticker catalog.
+type StickerPack struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Position  int        `json:"position"`
+	IsActive  bool       `json:"is_active"`
+	Stickers  []*Sticker `json:"stickers"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Sticker is one image in a pack. Emoji is the emoji it stands for, used
+// for previews and suggestions.
+type Sticker struct {
+	ID       string  `json:"id"`
+	PackID   string  `json:"pack_id"`
+	ImageURL string  `json:"image_url"`
+	Emoji    *string `json:"emoji,omitempty"`
+	Position int     `json:"position"`
+}
+
+// CreateStickerPackRequest adds a pack with its stickers, in order.
+type CreateStickerPackRequest struct {
+	Name     string                 `json:"name" validate:"required,min=1,max=100"`
+	Position int                    `json:"position" validate:"min=0"`
+	Stickers []CreateStickerRequest `json:"stickers" validate:"required,min=1,max=100,dive"`
+}
+
+// CreateStickerRequest is one sticker of a new pack.
+type CreateStickerRequest struct {
+	ImageURL string  `json:"image_url" validate:"required,url,max=2048"`
+	Emoji    *string `json:"emoji,omitempty" validate:"omitempty,max=16"`
+}
+
+// SetStickerPackActiveRequest shows or hides a pack in the catalog.
+type SetStickerPackActiveRequest struct {
+	IsActive *bool `json:"is_active" validate:"required"`
+}
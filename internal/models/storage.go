@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// StorageObject is one upload counted against a user's storage quota.
+type StorageObject struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"-"`
+	URL       string    `json:"url"`
+	ThumbURL  *string   `json:"thumb_url,omitempty"`
+	MediumURL *string   `json:"medium_url,omitempty"`
+	MimeType  *string   `json:"mime_type,omitempty"`
+	SizeBytes int64     `json:"size_bytes"`
+	Purpose   string    `json:"purpose"`
+	CreatedAt time.Time `json:"created_at"`
+	// InUse is set on reads: the file is still shown on a live post,
+	// comment or profile, so deleting it would leave a broken image.
+	InUse bool `json:"in_use"`
+}
+
+// StorageUsage is a user's running storage total.
+type StorageUsage struct {
+	UsedBytes   int64 `json:"used_bytes"`
+	ObjectCount int   `json:"object_count"`
+}
+
+// StorageObjectFilter pages a user's uploads for the storage screen.
+type StorageObjectFilter struct {
+	UserID string
+	// Sort is "oldest" (default), "largest" or "newest".
+	Sort   string
+	Limit  int
+	Offset int
+}
+
+// StorageUsageResponse is GET /users/me/storage: the total against the
+// quota plus a page of uploads the user can delete to free space.
+type StorageUsageResponse struct {
+	UsedBytes   int64 `json:"used_bytes"`
+	LimitBytes  int64 `json:"limit_bytes"`
+	ObjectCount int   `json:"object_count"`
+	// NearLimit is set once usage passes the warning threshold, so the
+	// app can nudge before uploads start failing.
+	NearLimit bool             `json:"near_limit"`
+	Objects   []*StorageObject `json:"objects"`
+	Total     int              `json:"total"`
+	Limit     int              `json:"limit"`
+	Offset    int              `json:"offset"`
+}
+
+// StorageReconcileRun is one run of the orphaned-storage reconciliation job.
+type StorageReconcileRun struct {
+	ID              string    `json:"id"`
+	StartedAt       time.Time `json:"started_at"`
+	FinishedAt      time.Time `json:"finished_at"`
+	DryRun          bool      `json:"dry_run"`
+	ScannedObjects  int64     `json:"scanned_objects"`
+	ScannedBytes    int64     `json:"scanned_bytes"`
+	OrphanedObjects int64     `json:"orphaned_objects"`
+	OrphanedBytes   int64     `json:"orphaned_bytes"`
+	DeletedObjects  int64     `json:"deleted_objects"`
+	ReclaimedBytes  int64     `json:"reclaimed_bytes"`
+	Error           *string   `json:"error,omitempty"`
+}
+
+// StorageReconcileReport is GET /admin/system/storage-reconcile: recent runs
+// plus what the job has reclaimed in total.
+type StorageReconcileReport struct {
+	Runs                []*StorageReconcileRun `json:"runs"`
+	TotalDeletedObjects int64                  `json:"total_deleted_objects"`
+	TotalReclaimedBytes int64                  `json:"total_reclaimed_bytes"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// SyncEntity names what a sync tombstone refers to.
+type SyncEntity string
+
+const (
+	SyncEntityNotification SyncEntity = "notification"
+	SyncEntityConversation SyncEntity = "conversation"
+)
+
+// DeltaSyncWindow is how far back ?since may reach. Tombstones older than
+// this are purged, so a client that has been away longer gets Reset and
+// refetches from scratch.
+const DeltaSyncWindow = 30 * 24 * time.Hour
+
+// DeltaPage is what a list endpoint returns when called with ?since: the
+// items created or changed after Since (oldest change first), the IDs
+// removed since, and the value to send as since on the next call.
+//
+// HasMore means another call with NextSince will return more changes.
+// Reset means the server can't tell what changed (since is older than the
+// sync window, or too much was removed); the client drops its cache and
+// pages the list normally.
+type DeltaPage struct {
+	Items     interface{} `json:"items"`
+	Deleted   []string    `json:"deleted"`
+	NextSince time.Time   `json:"next_since"`
+	HasMore   bool        `json:"has_more"`
+	Reset     bool        `json:"reset,omitempty"`
+}
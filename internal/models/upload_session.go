@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// UploadSession groups the images a client uploads before creating a post.
+// CreatePost claims it with upload_session_id; uploads left unclaimed are
+// deleted after a day.
+type UploadSession struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"-"`
+	PostID    *string    `json:"post_id,omitempty"`
+	ClaimedAt *time.Time `json:"claimed_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// UploadSessionFile is one image uploaded into a session.
+type UploadSessionFile struct {
+	ID        string
+	SessionID string
+	Photo     Photo
+	ClaimedAt *time.Time
+	CreatedAt time.Time
+}
+
+// UploadSessionResponse is returned when a client opens an upload session.
+type UploadSessionResponse struct {
+	ID        string    `json:"id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -101,6 +101,11 @@ type Profile struct {
 	CreatedAt    time.Time              `json:"created_at"`
 	UpdatedAt    time.Time              `json:"updated_at"`
 	DeletedAt    *time.Time             `json:"-"`
+	// Version increments on every profile edit; see UpdateProfileRequest.Version.
+	Version      int                    `json:"version"`
+	// Handle is the unique @handle, lowercase without the @; nil until set.
+	Handle          *string    `json:"handle,omitempty"`
+	HandleChangedAt *time.Time `json:"-"`
 }
 
 // Photo represents an image with metadata
@@ -113,6 +118,8 @@ type Photo struct {
 	Width     int    `json:"width"`
 	Height    int    `json:"height"`
 	MimeType  string `json:"mime_type"`
+	// AltText describes the image for screen readers.
+	AltText string `json:"alt_text,omitempty"`
 }
 
 // Scan implements the sql.Scanner interface for Photo to handle JSONB from PostgreSQL
@@ -164,6 +171,11 @@ type UserSession struct {
 	RevokedAt           *time.Time `json:"revoked_at,omitempty"`
 	CreatedAt           time.Time  `json:"created_at"`
 	UpdatedAt           time.Time  `json:"updated_at"`
+	// AAL is the assurance level the session was opened at; refreshes keep it.
+	AAL int `json:"aal"`
+	// Admin marks an admin-dashboard session. Those refresh only through
+	// /auth/admin/refresh and never past their original expiry.
+	Admin bool `json:"is_admin"`
 }
 
 // DeviceCredential represents a long-lived device-bound credential. The
@@ -0,0 +1,231 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// AccountRecoveryRepository stores trusted contacts and the recovery
+// requests that go through them.
+type AccountRecoveryRepository interface {
+	// SetTrustedContact names (or replaces) the user's trusted contact.
+	SetTrustedContact(ctx context.Context, userID, contactID string) error
+	// GetTrustedContact returns the user's trusted contact and when it was
+	// set; ErrTrustedContactNotFound when there is none.
+	GetTrustedContact(ctx context.Context, userID string) (string, time.Time, error)
+	// DeleteTrustedContact removes the user's trusted contact, if any.
+	DeleteTrustedContact(ctx context.Context, userID string) error
+
+	// CreateRecovery opens a recovery request, cancelling the user's
+	// expired pending one. ErrRecoveryInProgress when a live one exists.
+	// Sets recovery.ID, RequestedAt and Status.
+	CreateRecovery(ctx context.Context, recovery *models.AccountRecovery) error
+	// GetRecovery returns a request; ErrRecoveryNotFound when there is
+	// none.
+	GetRecovery(ctx context.Context, id string) (*models.AccountRecovery, error)
+	// ListRecoveries returns up to limit requests, optionally by status,
+	// newest first.
+	ListRecoveries(ctx context.Context, status string, limit int) ([]*models.AccountRecovery, error)
+	// CancelPendingRecovery cancels the user's pending request. Reports
+	// whether there was one.
+	CancelPendingRecovery(ctx context.Context, userID string) (bool, error)
+	// RecordFailedAttempt counts a wrong code and returns the new count.
+	RecordFailedAttempt(ctx context.Context, id string) (int, error)
+	// ResolveRecovery moves a pending request to status, recording the
+	// reviewer and, for completed ones, the email change.
+	// ErrRecoveryNotPending when it was already resolved.
+	ResolveRecovery(ctx context.Context, id, status string, reviewerID *string, notes string, oldEmail, newEmail *string) error
+}
+
+type accountRecoveryRepository struct {
+	db *database.DB
+}
+
+// NewAccountRecoveryRepository wires a new account recovery repository.
+func NewAccountRecoveryRepository(db *database.DB) AccountRecoveryRepository {
+	return &accountRecoveryRepository{db: db}
+}
+
+var (
+	// ErrTrustedContactNotFound is returned when the user has no trusted
+	// contact.
+	ErrTrustedContactNotFound = newNotFoundError("trusted contact not found")
+	// ErrRecoveryNotFound is returned for an unknown recovery id.
+	ErrRecoveryNotFound = newNotFoundError("account recovery not found")
+	// ErrRecoveryInProgress is returned when the user already has a live
+	// recovery request.
+	ErrRecoveryInProgress = newConflictError("account recovery already in progress")
+	// ErrRecoveryNotPending is returned when resolving a request that is no
+	// longer pending.
+	ErrRecoveryNotPending = newConflictError("account recovery is not pending")
+)
+
+func (r *accountRecoveryRepository) SetTrustedContact(ctx context.Context, userID, contactID string) error {
+	if _, err := r.db.Pool.Exec(ctx, `
+		INSERT INTO trusted_contacts (user_id, contact_id)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET contact_id = EXCLUDED.contact_id, created_at = NOW()
+	`, userID, contactID); err != nil {
+		return fmt.Errorf("set trusted contact: %w", err)
+	}
+	return nil
+}
+
+func (r *accountRecoveryRepository) GetTrustedContact(ctx context.Context, userID string) (string, time.Time, error) {
+	var contactID string
+	var since time.Time
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT c.contact_id::text, c.created_at
+		FROM trusted_contacts c
+		JOIN users u ON u.id = c.contact_id AND u.deleted_at IS NULL
+		WHERE c.user_id = $1
+	`, userID).Scan(&contactID, &since)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", time.Time{}, ErrTrustedContactNotFound
+	}
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("get trusted contact: %w", err)
+	}
+	return contactID, since, nil
+}
+
+func (r *accountRecoveryRepository) DeleteTrustedContact(ctx context.Context, userID string) error {
+	if _, err := r.db.Pool.Exec(ctx, `DELETE FROM trusted_contacts WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("delete trusted contact: %w", err)
+	}
+	return nil
+}
+
+func (r *accountRecoveryRepository) CreateRecovery(ctx context.Context, recovery *models.AccountRecovery) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE account_recoveries SET status = 'cancelled'
+		WHERE user_id = $1 AND status = 'pending' AND expires_at <= NOW()
+	`, recovery.UserID); err != nil {
+		return fmt.Errorf("expire account recovery: %w", err)
+	}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO account_recoveries (user_id, contact_id, code_hash, requested_ip, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) WHERE status = 'pending' DO NOTHING
+		RETURNING id, requested_at, status
+	`, recovery.UserID, recovery.ContactID, recovery.CodeHash, recovery.RequestedIP, recovery.ExpiresAt).
+		Scan(&recovery.ID, &recovery.RequestedAt, &recovery.Status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrRecoveryInProgress
+	}
+	if err != nil {
+		return fmt.Errorf("create account recovery: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// accountRecoverySelect reads requests with the user's and contact's
+// emails.
+const accountRecoverySelect = `
+	SELECT r.id::text, r.user_id::text, COALESCE(u.email, ''), r.contact_id::text, cu.email,
+	       r.code_hash, r.failed_attempts, r.requested_ip, r.requested_at, r.expires_at,
+	       r.status, r.reviewed_at, r.reviewed_by::text, r.review_notes, r.old_email, r.new_email
+	FROM account_recoveries r
+	LEFT JOIN users u ON u.id = r.user_id
+	LEFT JOIN users cu ON cu.id = r.contact_id
+`
+
+func scanAccountRecovery(row pgx.Row) (*models.AccountRecovery, error) {
+	a := &models.AccountRecovery{}
+	err := row.Scan(
+		&a.ID, &a.UserID, &a.UserEmail, &a.ContactID, &a.ContactEmail,
+		&a.CodeHash, &a.FailedAttempts, &a.RequestedIP, &a.RequestedAt, &a.ExpiresAt,
+		&a.Status, &a.ReviewedAt, &a.ReviewedBy, &a.ReviewNotes, &a.OldEmail, &a.NewEmail,
+	)
+	return a, err
+}
+
+func (r *accountRecoveryRepository) GetRecovery(ctx context.Context, id string) (*models.AccountRecovery, error) {
+	a, err := scanAccountRecovery(r.db.Pool.QueryRow(ctx, accountRecoverySelect+` WHERE r.id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrRecoveryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get account recovery: %w", err)
+	}
+	return a, nil
+}
+
+func (r *accountRecoveryRepository) ListRecoveries(ctx context.Context, status string, limit int) ([]*models.AccountRecovery, error) {
+	q := accountRecoverySelect
+	args := []interface{}{}
+	if status != "" {
+		args = append(args, status)
+		q += ` WHERE r.status = $1`
+	}
+	args = append(args, limit)
+	q += fmt.Sprintf(" ORDER BY r.requested_at DESC LIMIT $%d", len(args))
+
+	rows, err := r.db.Pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list account recoveries: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]*models.AccountRecovery, 0)
+	for rows.Next() {
+		a, err := scanAccountRecovery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan account recovery: %w", err)
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+func (r *accountRecoveryRepository) CancelPendingRecovery(ctx context.Context, userID string) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE account_recoveries SET status = 'cancelled'
+		WHERE user_id = $1 AND status = 'pending'
+	`, userID)
+	if err != nil {
+		return false, fmt.Errorf("cancel account recovery: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *accountRecoveryRepository) RecordFailedAttempt(ctx context.Context, id string) (int, error) {
+	var attempts int
+	err := r.db.Pool.QueryRow(ctx, `
+		UPDATE account_recoveries SET failed_attempts = failed_attempts + 1
+		WHERE id = $1
+		RETURNING failed_attempts
+	`, id).Scan(&attempts)
+	if err != nil {
+		return 0, fmt.Errorf("record account recovery attempt: %w", err)
+	}
+	return attempts, nil
+}
+
+func (r *accountRecoveryRepository) ResolveRecovery(ctx context.Context, id, status string, reviewerID *string, notes string, oldEmail, newEmail *string) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE account_recoveries
+		SET status = $2, reviewed_at = NOW(), reviewed_by = $3, review_notes = NULLIF($4, ''),
+		    old_email = $5, new_email = $6
+		WHERE id = $1 AND status = 'pending'
+	`, id, status, reviewerID, notes, oldEmail, newEmail)
+	if err != nil {
+		return fmt.Errorf("resolve account recovery: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrRecoveryNotPending
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/utils"
 	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 )
 
@@ -62,6 +64,10 @@ type AdminRepository interface {
 	GetBusinessCategories(ctx context.Context, businessID string) ([]string, error)
 	GetBusinessGallery(ctx context.Context, businessID string) ([]models.AttachmentResponse, error)
 	UpdateBusinessStatus(ctx context.Context, businessID, status string) error
+	// SetBusinessCategories replaces the business's categories. Every id
+	// must be an active category, else ErrUnknownBusinessCategory and
+	// nothing changes.
+	SetBusinessCategories(ctx context.Context, businessID string, categoryIDs []string) error
 	DeleteBusiness(ctx context.Context, businessID string) error
 	
 	ListPostReports(ctx context.Context, filter *models.AdminReportFilter) ([]*models.AdminPostReportResponse, int64, error)
@@ -75,7 +81,20 @@ type AdminRepository interface {
 	UpdatePostReportStatus(ctx context.Context, reportID, status string) error
 	UpdateCommentReportStatus(ctx context.Context, reportID, status string) error
 	UpdateUserReportResolved(ctx context.Context, reportID string, resolved bool) error
+	ListMessageReports(ctx context.Context, filter *models.AdminReportFilter) ([]*models.AdminMessageReportResponse, int64, error)
+	GetMessageReportByID(ctx context.Context, reportID string) (*models.AdminMessageReportResponse, error)
 	UpdateBusinessReportStatus(ctx context.Context, reportID, status string) error
+	UpdateMessageReportStatus(ctx context.Context, reportID, status string) error
+	// ListReportGroups lists reported items of reportType (posts, comments,
+	// users, businesses, messages), one row per item, most open reports first.
+	ListReportGroups(ctx context.Context, reportType string, filter *models.AdminReportFilter) ([]*models.AdminReportGroup, int64, error)
+	// ResolveReportGroup closes every open report on targetID with status
+	// and returns how many were closed.
+	ResolveReportGroup(ctx context.Context, reportType, targetID, status string) (int64, error)
+	// CountPriorViolations counts the user's content that moderators upheld
+	// a report against (RESOLVED), across posts, comments, businesses and
+	// messages, leaving out excludeTargetID (the item under review).
+	CountPriorViolations(ctx context.Context, userID, excludeTargetID string) (int64, error)
 	
 	GetAllUserIDs(ctx context.Context) ([]string, error)
 	GetUserIDsByProvince(ctx context.Context, province string) ([]string, error)
@@ -386,9 +405,10 @@ func (r *adminRepository) GetEngagementAnalytics(ctx context.Context, period str
 		SELECT 
 			(SELECT COUNT(*) FROM post_likes) as total_likes,
 			(SELECT COUNT(*) FROM post_comments WHERE deleted_at IS NULL) as total_comments,
-			(SELECT COUNT(*) FROM post_shares) as total_shares
+			(SELECT COUNT(*) FROM post_shares) as total_shares,
+			(SELECT COALESCE(SUM(external_shares), 0) FROM posts) as total_external_shares
 	`
-	err = r.db.Pool.QueryRow(ctx, totalsQuery).Scan(&analytics.TotalLikes, &analytics.TotalComments, &analytics.TotalShares)
+	err = r.db.Pool.QueryRow(ctx, totalsQuery).Scan(&analytics.TotalLikes, &analytics.TotalComments, &analytics.TotalShares, &analytics.TotalExternalShares)
 	if err != nil {
 		return nil, err
 	}
@@ -591,39 +611,32 @@ func (r *adminRepository) GetTopContent(ctx context.Context, period, metric stri
 	return out, nil
 }
 
-func (r *adminRepository) ListUsers(ctx context.Context, filter *models.AdminUserFilter) ([]*models.AdminUserResponse, int64, error) {
-	var conditions []string
-	var args []interface{}
-	argIndex := 1
-	
-	conditions = append(conditions, "u.deleted_at IS NULL")
-	
+// adminUserWhere builds the ListUsers filter (users u, profiles p).
+func adminUserWhere(filter *models.AdminUserFilter) *whereBuilder {
+	b := newWhereBuilder("u.deleted_at IS NULL")
 	if filter.Search != "" {
-		conditions = append(conditions, fmt.Sprintf(`(u.email ILIKE $%d ESCAPE '\' OR p.first_name ILIKE $%d ESCAPE '\' OR p.last_name ILIKE $%d ESCAPE '\')`, argIndex, argIndex, argIndex))
-		args = append(args, "%"+EscapeLike(filter.Search)+"%")
-		argIndex++
+		b.where(`(u.email ILIKE ? ESCAPE '\' OR p.first_name ILIKE ? ESCAPE '\' OR p.last_name ILIKE ? ESCAPE '\')`, "%"+EscapeLike(filter.Search)+"%")
 	}
-	
 	if filter.Role != "" {
-		conditions = append(conditions, fmt.Sprintf("u.role = $%d", argIndex))
-		args = append(args, filter.Role)
-		argIndex++
+		b.where("u.role = ?", filter.Role)
 	}
-	
 	switch filter.Status {
 	case "suspended":
-		conditions = append(conditions, "u.locked_until > NOW()")
+		b.where("u.locked_until > NOW()")
 	case "active":
-		conditions = append(conditions, "(u.locked_until IS NULL OR u.locked_until <= NOW())")
+		b.where("(u.locked_until IS NULL OR u.locked_until <= NOW())")
+	case "shadowbanned":
+		b.where("u.shadowbanned_at IS NOT NULL")
 	}
-	
 	if filter.Province != "" {
-		conditions = append(conditions, fmt.Sprintf("p.province = $%d", argIndex))
-		args = append(args, filter.Province)
-		argIndex++
+		b.where("p.province = ?", filter.Province)
 	}
-	
-	whereClause := strings.Join(conditions, " AND ")
+	return b
+}
+
+func (r *adminRepository) ListUsers(ctx context.Context, filter *models.AdminUserFilter) ([]*models.AdminUserResponse, int64, error) {
+	where := adminUserWhere(filter)
+	whereClause := where.clause()
 	
 	countQuery := fmt.Sprintf(`
 		SELECT COUNT(*)
@@ -633,7 +646,7 @@ func (r *adminRepository) ListUsers(ctx context.Context, filter *models.AdminUse
 	`, whereClause)
 	
 	var totalCount int64
-	err := r.db.Pool.QueryRow(ctx, countQuery, args...).Scan(&totalCount)
+	err := r.db.Pool.QueryRow(ctx, countQuery, where.params()...).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -667,7 +680,7 @@ func (r *adminRepository) ListUsers(ctx context.Context, filter *models.AdminUse
 			u.id, u.email, u.phone, u.email_verified, u.mfa_enabled, u.role,
 			p.first_name, p.last_name, p.avatar, p.cover, p.country, p.province, p.district, p.neighborhood, p.is_complete,
 			u.oauth_provider,
-			u.locked_until, u.last_login_at, u.created_at,
+			u.locked_until, u.last_login_at, u.created_at, u.shadowbanned_at,
 			(SELECT COUNT(*) FROM posts WHERE user_id = u.id AND deleted_at IS NULL) as posts_count,
 			(SELECT COUNT(*) FROM user_follows WHERE following_id = u.id) as followers_count,
 			(SELECT COUNT(*) FROM user_follows WHERE follower_id = u.id) as following_count,
@@ -686,12 +699,10 @@ func (r *adminRepository) ListUsers(ctx context.Context, filter *models.AdminUse
 		LEFT JOIN custom_roles cr ON cr.id = u.custom_role_id
 		WHERE %s
 		ORDER BY %s %s
-		LIMIT $%d OFFSET $%d
-	`, whereClause, sortBy, sortDir, argIndex, argIndex+1)
-	
-	args = append(args, limit, offset)
+		LIMIT %s OFFSET %s
+	`, whereClause, sortBy, sortDir, where.bind(limit), where.bind(offset))
 	
-	rows, err := r.db.Pool.Query(ctx, query, args...)
+	rows, err := r.db.Pool.Query(ctx, query, where.params()...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -706,7 +717,7 @@ func (r *adminRepository) ListUsers(ctx context.Context, filter *models.AdminUse
 			&user.Country, &user.Province, &user.District, &user.Neighborhood,
 			&user.IsComplete,
 			&user.OAuthProvider,
-			&user.LockedUntil, &user.LastLoginAt, &user.CreatedAt,
+			&user.LockedUntil, &user.LastLoginAt, &user.CreatedAt, &user.ShadowbannedAt,
 			&user.PostsCount, &user.FollowersCount, &user.FollowingCount,
 			&user.CustomRoleName, &user.LastPlatform,
 		)
@@ -714,6 +725,7 @@ func (r *adminRepository) ListUsers(ctx context.Context, filter *models.AdminUse
 			return nil, 0, err
 		}
 		user.IsSuspended = user.LockedUntil != nil && user.LockedUntil.After(time.Now())
+		user.IsShadowbanned = user.ShadowbannedAt != nil
 		users = append(users, user)
 	}
 	
@@ -729,6 +741,7 @@ func (r *adminRepository) GetUserByID(ctx context.Context, userID string) (*mode
 			ST_X(p.location::geometry) as longitude,
 			ST_Y(p.location::geometry) as latitude,
 			u.locked_until, u.last_login_at, u.created_at,
+			u.shadowbanned_at, u.shadowban_reason,
 			(SELECT COUNT(*) FROM posts WHERE user_id = u.id AND deleted_at IS NULL) as posts_count,
 			(SELECT COUNT(*) FROM user_follows WHERE following_id = u.id) as followers_count,
 			(SELECT COUNT(*) FROM user_follows WHERE follower_id = u.id) as following_count
@@ -747,6 +760,7 @@ func (r *adminRepository) GetUserByID(ctx context.Context, userID string) (*mode
 		&user.OAuthProvider,
 		&longitude, &latitude,
 		&user.LockedUntil, &user.LastLoginAt, &user.CreatedAt,
+		&user.ShadowbannedAt, &user.ShadowbanReason,
 		&user.PostsCount, &user.FollowersCount, &user.FollowingCount,
 	)
 	if err != nil {
@@ -755,6 +769,7 @@ func (r *adminRepository) GetUserByID(ctx context.Context, userID string) (*mode
 	user.Longitude = longitude
 	user.Latitude = latitude
 	user.IsSuspended = user.LockedUntil != nil && user.LockedUntil.After(time.Now())
+	user.IsShadowbanned = user.ShadowbannedAt != nil
 	return user, nil
 }
 
@@ -890,49 +905,36 @@ func (r *adminRepository) DeleteUser(ctx context.Context, userID string) error {
 	return err
 }
 
-func (r *adminRepository) ListPosts(ctx context.Context, filter *models.AdminPostFilter) ([]*models.AdminPostResponse, int64, error) {
-	var conditions []string
-	var args []interface{}
-	argIndex := 1
-	
-	conditions = append(conditions, "p.deleted_at IS NULL")
-	
+// adminPostWhere builds the ListPosts filter (posts p).
+func adminPostWhere(filter *models.AdminPostFilter) *whereBuilder {
+	b := newWhereBuilder("p.deleted_at IS NULL")
 	if filter.Search != "" {
-		conditions = append(conditions, fmt.Sprintf(`(p.title ILIKE $%d ESCAPE '\' OR p.description ILIKE $%d ESCAPE '\')`, argIndex, argIndex))
-		args = append(args, "%"+EscapeLike(filter.Search)+"%")
-		argIndex++
+		b.where(`(p.title ILIKE ? ESCAPE '\' OR p.description ILIKE ? ESCAPE '\')`, "%"+EscapeLike(filter.Search)+"%")
 	}
-	
 	if filter.Type != "" && filter.Type != "all" {
-		conditions = append(conditions, fmt.Sprintf("p.type = $%d", argIndex))
-		args = append(args, filter.Type)
-		argIndex++
+		b.where("p.type = ?", filter.Type)
 	}
-	
 	if filter.Status != "" {
 		// posts.status is boolean: true = visible, false = hidden
-		statusBool := filter.Status == "ACTIVE" || filter.Status == "true"
-		conditions = append(conditions, fmt.Sprintf("p.status = $%d", argIndex))
-		args = append(args, statusBool)
-		argIndex++
+		b.where("p.status = ?", filter.Status == "ACTIVE" || filter.Status == "true")
 	}
-	
 	if filter.UserID != "" {
-		conditions = append(conditions, fmt.Sprintf("p.user_id = $%d", argIndex))
-		args = append(args, filter.UserID)
-		argIndex++
+		b.where("p.user_id = ?", filter.UserID)
 	}
-	
 	if filter.Reported {
-		conditions = append(conditions, "EXISTS (SELECT 1 FROM post_reports pr WHERE pr.post_id = p.id)")
+		b.where("EXISTS (SELECT 1 FROM post_reports pr WHERE pr.post_id = p.id)")
 	}
-	
-	whereClause := strings.Join(conditions, " AND ")
+	return b
+}
+
+func (r *adminRepository) ListPosts(ctx context.Context, filter *models.AdminPostFilter) ([]*models.AdminPostResponse, int64, error) {
+	where := adminPostWhere(filter)
+	whereClause := where.clause()
 	
 	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM posts p WHERE %s`, whereClause)
 	
 	var totalCount int64
-	err := r.db.Pool.QueryRow(ctx, countQuery, args...).Scan(&totalCount)
+	err := r.db.Pool.QueryRow(ctx, countQuery, where.params()...).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -970,12 +972,10 @@ func (r *adminRepository) ListPosts(ctx context.Context, filter *models.AdminPos
 		LEFT JOIN business_profiles bp ON p.business_id = bp.id
 		WHERE %s
 		ORDER BY %s %s
-		LIMIT $%d OFFSET $%d
-	`, whereClause, sortBy, sortDir, argIndex, argIndex+1)
-	
-	args = append(args, limit, offset)
+		LIMIT %s OFFSET %s
+	`, whereClause, sortBy, sortDir, where.bind(limit), where.bind(offset))
 	
-	rows, err := r.db.Pool.Query(ctx, query, args...)
+	rows, err := r.db.Pool.Query(ctx, query, where.params()...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -1260,46 +1260,34 @@ func (r *adminRepository) GetCommentByID(ctx context.Context, commentID string)
 }
 
 func (r *adminRepository) ListComments(ctx context.Context, filter *models.AdminCommentFilter) ([]*models.AdminCommentResponse, int64, error) {
-	var conditions []string
-	var args []interface{}
-	argIndex := 1
-
-	conditions = append(conditions, "c.deleted_at IS NULL")
+	where := newWhereBuilder("c.deleted_at IS NULL")
 
 	if filter.CommentID != "" {
-		conditions = append(conditions, fmt.Sprintf("c.id = $%d", argIndex))
-		args = append(args, filter.CommentID)
-		argIndex++
+		where.where("c.id = ?", filter.CommentID)
 	}
 
 	if filter.Search != "" {
-		conditions = append(conditions, fmt.Sprintf(`c.text ILIKE $%d ESCAPE '\'`, argIndex))
-		args = append(args, "%"+EscapeLike(filter.Search)+"%")
-		argIndex++
+		where.where(`c.text ILIKE ? ESCAPE '\'`, "%"+EscapeLike(filter.Search)+"%")
 	}
 
 	if filter.PostID != "" {
-		conditions = append(conditions, fmt.Sprintf("c.post_id = $%d", argIndex))
-		args = append(args, filter.PostID)
-		argIndex++
+		where.where("c.post_id = ?", filter.PostID)
 	}
 	
 	if filter.UserID != "" {
-		conditions = append(conditions, fmt.Sprintf("c.user_id = $%d", argIndex))
-		args = append(args, filter.UserID)
-		argIndex++
+		where.where("c.user_id = ?", filter.UserID)
 	}
 	
 	if filter.Reported {
-		conditions = append(conditions, "EXISTS (SELECT 1 FROM comment_reports cr WHERE cr.comment_id = c.id)")
+		where.where("EXISTS (SELECT 1 FROM comment_reports cr WHERE cr.comment_id = c.id)")
 	}
 	
-	whereClause := strings.Join(conditions, " AND ")
+	whereClause := where.clause()
 	
 	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM post_comments c WHERE %s`, whereClause)
 	
 	var totalCount int64
-	err := r.db.Pool.QueryRow(ctx, countQuery, args...).Scan(&totalCount)
+	err := r.db.Pool.QueryRow(ctx, countQuery, where.params()...).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -1328,12 +1316,10 @@ func (r *adminRepository) ListComments(ctx context.Context, filter *models.Admin
 		LEFT JOIN profiles pr ON u.id = pr.id
 		WHERE %s
 		ORDER BY c.created_at DESC
-		LIMIT $%d OFFSET $%d
-	`, whereClause, argIndex, argIndex+1)
-	
-	args = append(args, limit, offset)
+		LIMIT %s OFFSET %s
+	`, whereClause, where.bind(limit), where.bind(offset))
 	
-	rows, err := r.db.Pool.Query(ctx, query, args...)
+	rows, err := r.db.Pool.Query(ctx, query, where.params()...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -1371,7 +1357,7 @@ func (r *adminRepository) UpdateCommentContent(ctx context.Context, commentID, c
 		return err
 	}
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("comment not found")
+		return newNotFoundError("comment not found")
 	}
 	return nil
 }
@@ -1384,7 +1370,7 @@ func (r *adminRepository) RestoreComment(ctx context.Context, commentID string)
 		return err
 	}
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("comment not found or not deleted")
+		return newNotFoundError("comment not found or not deleted")
 	}
 	return nil
 }
@@ -1397,66 +1383,52 @@ func (r *adminRepository) ResolveCommentReportsByCommentID(ctx context.Context,
 }
 
 func (r *adminRepository) ListBusinesses(ctx context.Context, filter *models.AdminBusinessFilter) ([]*models.AdminBusinessResponse, int64, error) {
-	var conditions []string
-	var args []interface{}
-	argIndex := 1
+	where := newWhereBuilder()
 	
 	// Soft-delete handling: status=DELETED → only deleted rows; include_deleted
 	// flag → both live and deleted; otherwise live only.
 	switch {
 	case filter.Status == "DELETED":
-		conditions = append(conditions, "b.deleted_at IS NOT NULL")
+		where.where("b.deleted_at IS NOT NULL")
 	case filter.IncludeDeleted:
 		// no deleted_at filter — show both
 	default:
-		conditions = append(conditions, "b.deleted_at IS NULL")
+		where.where("b.deleted_at IS NULL")
 	}
 
 	if filter.Search != "" {
-		conditions = append(conditions, fmt.Sprintf(`(b.name ILIKE $%d ESCAPE '\' OR b.description ILIKE $%d ESCAPE '\')`, argIndex, argIndex))
-		args = append(args, "%"+EscapeLike(filter.Search)+"%")
-		argIndex++
+		where.where(`(b.name ILIKE ? ESCAPE '\' OR b.description ILIKE ? ESCAPE '\')`, "%"+EscapeLike(filter.Search)+"%")
 	}
 
 	if filter.Status != "" && filter.Status != "DELETED" {
 		// Convert string status to boolean (status column is boolean in DB)
 		// ACTIVE = true, anything else (PENDING, SUSPENDED, REJECTED) = false
 		statusBool := filter.Status == "ACTIVE"
-		conditions = append(conditions, fmt.Sprintf("b.status = $%d", argIndex))
-		args = append(args, statusBool)
-		argIndex++
+		where.where("b.status = ?", statusBool)
 	}
 
 	if filter.Province != "" {
-		conditions = append(conditions, fmt.Sprintf(`b.province ILIKE $%d ESCAPE '\'`, argIndex))
-		args = append(args, "%"+EscapeLike(filter.Province)+"%")
-		argIndex++
+		where.where(`b.province ILIKE ? ESCAPE '\'`, "%"+EscapeLike(filter.Province)+"%")
 	}
 
 	if filter.Category != "" {
-		conditions = append(conditions, fmt.Sprintf(`
+		where.where(`
 			EXISTS (
 				SELECT 1 FROM business_categories bc
 				JOIN categories c ON bc.category_id = c.id
-				WHERE bc.business_id = b.id AND c.name ILIKE $%d ESCAPE '\'
+				WHERE bc.business_id = b.id AND c.name ILIKE ? ESCAPE '\'
 			)
-		`, argIndex))
-		args = append(args, "%"+EscapeLike(filter.Category)+"%")
-		argIndex++
+		`, "%"+EscapeLike(filter.Category)+"%")
 	}
 
-	// Guard against empty conditions: include_deleted with no other filters
-	// removes every WHERE-clause member, which would produce `WHERE ` —
-	// invalid SQL. Pin a tautology so the clause stays well-formed.
-	if len(conditions) == 0 {
-		conditions = append(conditions, "1=1")
-	}
-	whereClause := strings.Join(conditions, " AND ")
+	// include_deleted with no other filters leaves no conditions; clause()
+	// falls back to 1=1 so the WHERE stays well-formed.
+	whereClause := where.clause()
 
 	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM business_profiles b WHERE %s`, whereClause)
 	
 	var totalCount int64
-	err := r.db.Pool.QueryRow(ctx, countQuery, args...).Scan(&totalCount)
+	err := r.db.Pool.QueryRow(ctx, countQuery, where.params()...).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -1489,12 +1461,10 @@ func (r *adminRepository) ListBusinesses(ctx context.Context, filter *models.Adm
 		LEFT JOIN profiles pr ON u.id = pr.id
 		WHERE %s
 		ORDER BY b.created_at DESC
-		LIMIT $%d OFFSET $%d
-	`, whereClause, argIndex, argIndex+1)
-	
-	args = append(args, limit, offset)
+		LIMIT %s OFFSET %s
+	`, whereClause, where.bind(limit), where.bind(offset))
 	
-	rows, err := r.db.Pool.Query(ctx, query, args...)
+	rows, err := r.db.Pool.Query(ctx, query, where.params()...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -1693,10 +1663,10 @@ func (r *adminRepository) GetBusinessCategories(ctx context.Context, businessID
 
 func (r *adminRepository) GetBusinessGallery(ctx context.Context, businessID string) ([]models.AttachmentResponse, error) {
 	query := `
-		SELECT id, photo
+		SELECT id, photo, position, caption
 		FROM business_attachments
 		WHERE business_profile_id = $1 AND deleted_at IS NULL
-		ORDER BY created_at
+		ORDER BY position, created_at DESC
 	`
 
 	rows, err := r.db.Pool.Query(ctx, query, businessID)
@@ -1708,7 +1678,7 @@ func (r *adminRepository) GetBusinessGallery(ctx context.Context, businessID str
 	var gallery []models.AttachmentResponse
 	for rows.Next() {
 		var a models.AttachmentResponse
-		if err := rows.Scan(&a.ID, &a.Photo); err != nil {
+		if err := rows.Scan(&a.ID, &a.Photo, &a.Position, &a.Caption); err != nil {
 			continue
 		}
 		gallery = append(gallery, a)
@@ -1729,69 +1699,79 @@ func (r *adminRepository) UpdateBusinessStatus(ctx context.Context, businessID,
 	return err
 }
 
+// ErrUnknownBusinessCategory is returned by SetBusinessCategories when an
+// id isn't an active business category.
+var ErrUnknownBusinessCategory = errors.New("unknown business category")
+
+func (r *adminRepository) SetBusinessCategories(ctx context.Context, businessID string, categoryIDs []string) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `DELETE FROM business_profile_categories WHERE business_profile_id = $1`, businessID); err != nil {
+		return fmt.Errorf("clear business categories: %w", err)
+	}
+	if len(categoryIDs) > 0 {
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO business_profile_categories (id, business_profile_id, business_category_id, created_at)
+			SELECT uuid_generate_v4(), $1, bc.id, NOW()
+			FROM business_categories bc
+			WHERE bc.id = ANY($2) AND bc.is_active = true
+		`, businessID, categoryIDs)
+		if err != nil {
+			return fmt.Errorf("set business categories: %w", err)
+		}
+		if tag.RowsAffected() != int64(len(categoryIDs)) {
+			return ErrUnknownBusinessCategory
+		}
+	}
+	return tx.Commit(ctx)
+}
+
 func (r *adminRepository) DeleteBusiness(ctx context.Context, businessID string) error {
 	query := `UPDATE business_profiles SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1`
 	_, err := r.db.Pool.Exec(ctx, query, businessID)
 	return err
 }
 
-// applyReportTriageFilters appends date-range + reason-substring SQL
-// fragments to the report-list WHERE clause. Used by all four
+// applyReportTriageFilters adds date-range + reason-substring conditions
+// to the report-list WHERE clause. Used by all four
 // List<Type>Reports paths to keep date/text filters consistent.
-func applyReportTriageFilters(
-	filter *models.AdminReportFilter,
-	conditions []string,
-	args []interface{},
-	argIndex int,
-) ([]string, []interface{}, int) {
+func applyReportTriageFilters(filter *models.AdminReportFilter, where *whereBuilder) {
 	if filter.From != "" {
-		conditions = append(conditions, fmt.Sprintf("r.created_at >= $%d", argIndex))
-		args = append(args, filter.From)
-		argIndex++
+		where.where("r.created_at >= ?", filter.From)
 	}
 	if filter.To != "" {
 		// Inclusive end-of-day so YYYY-MM-DD as `to` matches reports filed
 		// at any time on that day.
-		conditions = append(conditions, fmt.Sprintf("r.created_at < ($%d::date + INTERVAL '1 day')", argIndex))
-		args = append(args, filter.To)
-		argIndex++
+		where.where("r.created_at < (?::date + INTERVAL '1 day')", filter.To)
 	}
 	if filter.Reason != "" {
-		conditions = append(conditions, fmt.Sprintf(`r.reason ILIKE $%d ESCAPE '\'`, argIndex))
-		args = append(args, "%"+EscapeLike(filter.Reason)+"%")
-		argIndex++
+		where.where(`r.reason ILIKE ? ESCAPE '\'`, "%"+EscapeLike(filter.Reason)+"%")
 	}
-	return conditions, args, argIndex
 }
 
 func (r *adminRepository) ListPostReports(ctx context.Context, filter *models.AdminReportFilter) ([]*models.AdminPostReportResponse, int64, error) {
-	var conditions []string
-	var args []interface{}
-	argIndex := 1
+	where := newWhereBuilder()
 
 	if filter.PostID != "" {
-		conditions = append(conditions, fmt.Sprintf("r.post_id = $%d", argIndex))
-		args = append(args, filter.PostID)
-		argIndex++
+		where.where("r.post_id = ?", filter.PostID)
 	}
 
 	if filter.Status != "" {
-		conditions = append(conditions, fmt.Sprintf("r.report_status = $%d", argIndex))
-		args = append(args, filter.Status)
-		argIndex++
+		where.where("r.report_status = ?", filter.Status)
 	}
 
-	conditions, args, argIndex = applyReportTriageFilters(filter, conditions, args, argIndex)
+	applyReportTriageFilters(filter, where)
 
-	whereClause := "1=1"
-	if len(conditions) > 0 {
-		whereClause = strings.Join(conditions, " AND ")
-	}
+	whereClause := where.clause()
 
 	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM post_reports r WHERE %s`, whereClause)
 	
 	var totalCount int64
-	err := r.db.Pool.QueryRow(ctx, countQuery, args...).Scan(&totalCount)
+	err := r.db.Pool.QueryRow(ctx, countQuery, where.params()...).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -1819,12 +1799,10 @@ func (r *adminRepository) ListPostReports(ctx context.Context, filter *models.Ad
 		JOIN users ru ON r.user_id = ru.id
 		WHERE %s
 		ORDER BY r.created_at DESC
-		LIMIT $%d OFFSET $%d
-	`, whereClause, argIndex, argIndex+1)
-	
-	args = append(args, limit, offset)
+		LIMIT %s OFFSET %s
+	`, whereClause, where.bind(limit), where.bind(offset))
 	
-	rows, err := r.db.Pool.Query(ctx, query, args...)
+	rows, err := r.db.Pool.Query(ctx, query, where.params()...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -1887,33 +1865,24 @@ func (r *adminRepository) GetPostReportByID(ctx context.Context, reportID string
 }
 
 func (r *adminRepository) ListCommentReports(ctx context.Context, filter *models.AdminReportFilter) ([]*models.AdminCommentReportResponse, int64, error) {
-	var conditions []string
-	var args []interface{}
-	argIndex := 1
+	where := newWhereBuilder()
 
 	if filter.CommentID != "" {
-		conditions = append(conditions, fmt.Sprintf("r.comment_id = $%d", argIndex))
-		args = append(args, filter.CommentID)
-		argIndex++
+		where.where("r.comment_id = ?", filter.CommentID)
 	}
 
 	if filter.Status != "" {
-		conditions = append(conditions, fmt.Sprintf("r.report_status = $%d", argIndex))
-		args = append(args, filter.Status)
-		argIndex++
+		where.where("r.report_status = ?", filter.Status)
 	}
 
-	conditions, args, argIndex = applyReportTriageFilters(filter, conditions, args, argIndex)
+	applyReportTriageFilters(filter, where)
 
-	whereClause := "1=1"
-	if len(conditions) > 0 {
-		whereClause = strings.Join(conditions, " AND ")
-	}
+	whereClause := where.clause()
 
 	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM comment_reports r WHERE %s`, whereClause)
 	
 	var totalCount int64
-	err := r.db.Pool.QueryRow(ctx, countQuery, args...).Scan(&totalCount)
+	err := r.db.Pool.QueryRow(ctx, countQuery, where.params()...).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -1945,12 +1914,10 @@ func (r *adminRepository) ListCommentReports(ctx context.Context, filter *models
 		LEFT JOIN users ru ON r.user_id = ru.id
 		WHERE %s
 		ORDER BY r.created_at DESC
-		LIMIT $%d OFFSET $%d
-	`, whereClause, argIndex, argIndex+1)
-	
-	args = append(args, limit, offset)
+		LIMIT %s OFFSET %s
+	`, whereClause, where.bind(limit), where.bind(offset))
 	
-	rows, err := r.db.Pool.Query(ctx, query, args...)
+	rows, err := r.db.Pool.Query(ctx, query, where.params()...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -2006,34 +1973,27 @@ func (r *adminRepository) GetCommentReportByID(ctx context.Context, reportID str
 }
 
 func (r *adminRepository) ListUserReports(ctx context.Context, filter *models.AdminReportFilter) ([]*models.AdminUserReportResponse, int64, error) {
-	var conditions []string
-	var args []interface{}
-	argIndex := 1
+	where := newWhereBuilder()
 
 	if filter.UserID != "" {
-		conditions = append(conditions, fmt.Sprintf("r.reported_user = $%d", argIndex))
-		args = append(args, filter.UserID)
-		argIndex++
+		where.where("r.reported_user = ?", filter.UserID)
 	}
 
 	switch filter.Status {
 	case "RESOLVED":
-		conditions = append(conditions, "r.resolved = true")
+		where.where("r.resolved = true")
 	case "PENDING":
-		conditions = append(conditions, "r.resolved = false")
+		where.where("r.resolved = false")
 	}
 
-	conditions, args, argIndex = applyReportTriageFilters(filter, conditions, args, argIndex)
+	applyReportTriageFilters(filter, where)
 
-	whereClause := "1=1"
-	if len(conditions) > 0 {
-		whereClause = strings.Join(conditions, " AND ")
-	}
+	whereClause := where.clause()
 
 	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM user_reports r WHERE %s`, whereClause)
 
 	var totalCount int64
-	err := r.db.Pool.QueryRow(ctx, countQuery, args...).Scan(&totalCount)
+	err := r.db.Pool.QueryRow(ctx, countQuery, where.params()...).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -2063,12 +2023,10 @@ func (r *adminRepository) ListUserReports(ctx context.Context, filter *models.Ad
 		LEFT JOIN users rb ON r.reported_by_id = rb.id
 		WHERE %s
 		ORDER BY r.created_at DESC
-		LIMIT $%d OFFSET $%d
-	`, whereClause, argIndex, argIndex+1)
+		LIMIT %s OFFSET %s
+	`, whereClause, where.bind(limit), where.bind(offset))
 
-	args = append(args, limit, offset)
-	
-	rows, err := r.db.Pool.Query(ctx, query, args...)
+	rows, err := r.db.Pool.Query(ctx, query, where.params()...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -2121,33 +2079,24 @@ func (r *adminRepository) GetUserReportByID(ctx context.Context, reportID string
 }
 
 func (r *adminRepository) ListBusinessReports(ctx context.Context, filter *models.AdminReportFilter) ([]*models.AdminBusinessReportResponse, int64, error) {
-	var conditions []string
-	var args []interface{}
-	argIndex := 1
+	where := newWhereBuilder()
 
 	if filter.BusinessID != "" {
-		conditions = append(conditions, fmt.Sprintf("r.business_id = $%d", argIndex))
-		args = append(args, filter.BusinessID)
-		argIndex++
+		where.where("r.business_id = ?", filter.BusinessID)
 	}
 
 	if filter.Status != "" {
-		conditions = append(conditions, fmt.Sprintf("r.report_status = $%d", argIndex))
-		args = append(args, filter.Status)
-		argIndex++
+		where.where("r.report_status = ?", filter.Status)
 	}
 
-	conditions, args, argIndex = applyReportTriageFilters(filter, conditions, args, argIndex)
+	applyReportTriageFilters(filter, where)
 
-	whereClause := "1=1"
-	if len(conditions) > 0 {
-		whereClause = strings.Join(conditions, " AND ")
-	}
+	whereClause := where.clause()
 
 	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM business_reports r WHERE %s`, whereClause)
 	
 	var totalCount int64
-	err := r.db.Pool.QueryRow(ctx, countQuery, args...).Scan(&totalCount)
+	err := r.db.Pool.QueryRow(ctx, countQuery, where.params()...).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -2178,12 +2127,10 @@ func (r *adminRepository) ListBusinessReports(ctx context.Context, filter *model
 		LEFT JOIN users ru ON r.user_id = ru.id
 		WHERE %s
 		ORDER BY r.created_at DESC
-		LIMIT $%d OFFSET $%d
-	`, whereClause, argIndex, argIndex+1)
+		LIMIT %s OFFSET %s
+	`, whereClause, where.bind(limit), where.bind(offset))
 	
-	args = append(args, limit, offset)
-	
-	rows, err := r.db.Pool.Query(ctx, query, args...)
+	rows, err := r.db.Pool.Query(ctx, query, where.params()...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -2241,6 +2188,123 @@ func (r *adminRepository) GetBusinessReportByID(ctx context.Context, reportID st
 	return report, nil
 }
 
+// messageReportColumns selects an AdminMessageReportResponse. The message
+// counts as deleted once its row is gone or soft-deleted; the snapshot on
+// the report stays either way.
+const messageReportColumns = `
+	r.id, r.message_id::text, r.conversation_id::text,
+	r.message_content, r.message_type, r.message_sent_at,
+	(m.id IS NULL OR m.deleted_at IS NOT NULL),
+	r.sender_id::text, COALESCE(su.email, ''),
+	r.user_id::text, COALESCE(ru.email, ''),
+	r.reason, r.additional_comments, r.report_status, r.created_at
+	FROM message_reports r
+	LEFT JOIN messages m ON m.id = r.message_id
+	LEFT JOIN users su ON su.id = r.sender_id
+	LEFT JOIN users ru ON ru.id = r.user_id`
+
+func scanMessageReport(row pgx.Row) (*models.AdminMessageReportResponse, error) {
+	report := &models.AdminMessageReportResponse{}
+	err := row.Scan(
+		&report.ID, &report.MessageID, &report.ConversationID,
+		&report.MessageContent, &report.MessageType, &report.MessageSentAt,
+		&report.MessageDeleted,
+		&report.SenderID, &report.SenderEmail,
+		&report.ReporterID, &report.ReporterEmail,
+		&report.Reason, &report.AdditionalComments, &report.Status, &report.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (r *adminRepository) ListMessageReports(ctx context.Context, filter *models.AdminReportFilter) ([]*models.AdminMessageReportResponse, int64, error) {
+	where := newWhereBuilder()
+
+	if filter.MessageID != "" {
+		where.where("r.message_id = ?", filter.MessageID)
+	}
+
+	if filter.UserID != "" {
+		where.where("r.sender_id = ?", filter.UserID)
+	}
+
+	if filter.Status != "" {
+		where.where("r.report_status = ?", filter.Status)
+	}
+
+	applyReportTriageFilters(filter, where)
+
+	whereClause := where.clause()
+
+	var totalCount int64
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM message_reports r WHERE %s`, whereClause)
+	if err := r.db.Pool.QueryRow(ctx, countQuery, where.params()...).Scan(&totalCount); err != nil {
+		return nil, 0, err
+	}
+
+	limit := 20
+	if filter.Limit > 0 && filter.Limit <= 100 {
+		limit = filter.Limit
+	}
+	page := 1
+	if filter.Page > 0 {
+		page = filter.Page
+	}
+	offset := (page - 1) * limit
+
+	query := fmt.Sprintf(`SELECT %s
+		WHERE %s
+		ORDER BY r.created_at DESC
+		LIMIT %s OFFSET %s
+	`, messageReportColumns, whereClause, where.bind(limit), where.bind(offset))
+	rows, err := r.db.Pool.Query(ctx, query, where.params()...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	reports := []*models.AdminMessageReportResponse{}
+	for rows.Next() {
+		report, err := scanMessageReport(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, totalCount, rows.Err()
+}
+
+func (r *adminRepository) GetMessageReportByID(ctx context.Context, reportID string) (*models.AdminMessageReportResponse, error) {
+	return scanMessageReport(r.db.Pool.QueryRow(ctx, `SELECT `+messageReportColumns+` WHERE r.id = $1`, reportID))
+}
+
+func (r *adminRepository) CountPriorViolations(ctx context.Context, userID, excludeTargetID string) (int64, error) {
+	// Counted per item, not per report: five upheld reports on one post are
+	// one violation. User reports are left out because resolved=true there
+	// doesn't say whether the report was upheld or dismissed.
+	var count int64
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT
+			(SELECT COUNT(DISTINCT r.post_id) FROM post_reports r JOIN posts t ON t.id = r.post_id
+				WHERE t.user_id = $1 AND r.report_status = 'RESOLVED' AND r.post_id::text <> $2)
+			+ (SELECT COUNT(DISTINCT r.comment_id) FROM comment_reports r JOIN post_comments t ON t.id = r.comment_id
+				WHERE t.user_id = $1 AND r.report_status = 'RESOLVED' AND r.comment_id::text <> $2)
+			+ (SELECT COUNT(DISTINCT r.business_id) FROM business_reports r JOIN business_profiles t ON t.id = r.business_id
+				WHERE t.user_id = $1 AND r.report_status = 'RESOLVED' AND r.business_id::text <> $2)
+			+ (SELECT COUNT(DISTINCT r.message_id) FROM message_reports r
+				WHERE r.sender_id = $1 AND r.report_status = 'RESOLVED' AND r.message_id::text <> $2)
+	`, userID, excludeTargetID).Scan(&count)
+	return count, err
+}
+
+func (r *adminRepository) UpdateMessageReportStatus(ctx context.Context, reportID, status string) error {
+	query := `UPDATE message_reports SET report_status = $1, updated_at = NOW() WHERE id = $2`
+	_, err := r.db.Pool.Exec(ctx, query, status, reportID)
+	return err
+}
+
 func (r *adminRepository) UpdatePostReportStatus(ctx context.Context, reportID, status string) error {
 	query := `UPDATE post_reports SET report_status = $1, updated_at = NOW() WHERE id = $2`
 	_, err := r.db.Pool.Exec(ctx, query, status, reportID)
@@ -2391,6 +2455,7 @@ func (r *adminRepository) GetInboxCounts(ctx context.Context) (*models.AdminInbo
 			(SELECT COUNT(*) FROM comment_reports WHERE report_status = 'PENDING'),
 			(SELECT COUNT(*) FROM user_reports WHERE resolved = false),
 			(SELECT COUNT(*) FROM business_reports WHERE report_status = 'PENDING'),
+			(SELECT COUNT(*) FROM message_reports WHERE report_status = 'PENDING'),
 			(SELECT COUNT(*) FROM user_feedback WHERE status = 'OPEN'),
 			(SELECT COUNT(*) FROM (
 				SELECT DISTINCT ON (user_id) user_id, is_from_user
@@ -2402,6 +2467,7 @@ func (r *adminRepository) GetInboxCounts(ctx context.Context) (*models.AdminInbo
 				(SELECT MAX(created_at) FROM comment_reports  WHERE report_status = 'PENDING'),
 				(SELECT MAX(created_at) FROM user_reports     WHERE resolved = false),
 				(SELECT MAX(created_at) FROM business_reports WHERE report_status = 'PENDING'),
+				(SELECT MAX(created_at) FROM message_reports  WHERE report_status = 'PENDING'),
 				(SELECT MAX(created_at) FROM user_feedback    WHERE status = 'OPEN'),
 				(SELECT MAX(created_at) FROM help_chat_messages WHERE is_from_user = true)
 			)
@@ -2412,13 +2478,15 @@ func (r *adminRepository) GetInboxCounts(ctx context.Context) (*models.AdminInbo
 		&c.CommentReports,
 		&c.UserReports,
 		&c.BusinessReports,
+		&c.MessageReports,
 		&c.OpenFeedback,
 		&c.UnansweredHelp,
 		&c.NewestAt,
 	); err != nil {
 		return nil, err
 	}
-	c.Total = c.PostReports + c.CommentReports + c.UserReports + c.BusinessReports + c.OpenFeedback + c.UnansweredHelp
+	c.Total = c.PostReports + c.CommentReports + c.UserReports + c.BusinessReports + c.MessageReports +
+		c.OpenFeedback + c.UnansweredHelp
 	return c, nil
 }
 
@@ -2433,33 +2501,22 @@ func (r *adminRepository) ListFeedback(ctx context.Context, filter *models.Admin
 	}
 	offset := (page - 1) * limit
 
-	var conditions []string
-	var countArgs []interface{}
-	argIdx := 1
+	where := newWhereBuilder()
 	if filter.Type != "" {
-		conditions = append(conditions, fmt.Sprintf("f.type = $%d", argIdx))
-		countArgs = append(countArgs, filter.Type)
-		argIdx++
+		where.where("f.type = ?", filter.Type)
 	}
 	if filter.Status != "" {
-		conditions = append(conditions, fmt.Sprintf("f.status = $%d", argIdx))
-		countArgs = append(countArgs, filter.Status)
-		argIdx++
-	}
-	whereClause := "1=1"
-	if len(conditions) > 0 {
-		whereClause = strings.Join(conditions, " AND ")
+		where.where("f.status = ?", filter.Status)
 	}
+	whereClause := where.clause()
 
 	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM user_feedback f WHERE %s`, whereClause)
 	var totalCount int64
-	err := r.db.Pool.QueryRow(ctx, countQuery, countArgs...).Scan(&totalCount)
+	err := r.db.Pool.QueryRow(ctx, countQuery, where.params()...).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	args := append(countArgs, limit, offset)
-	argIndex := argIdx
 	query := fmt.Sprintf(`
 		SELECT f.id, f.user_id, COALESCE(u.email, ''), f.rating, f.type, f.message,
 		       f.app_version, f.device_info, COALESCE(f.status, 'OPEN'),
@@ -2468,10 +2525,10 @@ func (r *adminRepository) ListFeedback(ctx context.Context, filter *models.Admin
 		LEFT JOIN users u ON f.user_id = u.id
 		WHERE %s
 		ORDER BY f.created_at DESC
-		LIMIT $%d OFFSET $%d
-	`, whereClause, argIndex, argIndex+1)
+		LIMIT %s OFFSET %s
+	`, whereClause, where.bind(limit), where.bind(offset))
 
-	rows, err := r.db.Pool.Query(ctx, query, args...)
+	rows, err := r.db.Pool.Query(ctx, query, where.params()...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -2643,40 +2700,26 @@ func (r *adminRepository) ListAuditLogs(ctx context.Context, filter *models.Audi
 	}
 	offset := (page - 1) * limit
 
-	var conditions []string
-	var args []interface{}
-	argIdx := 1
+	where := newWhereBuilder()
 
 	if filter.AdminID != "" {
-		conditions = append(conditions, fmt.Sprintf("l.admin_id = $%d", argIdx))
-		args = append(args, filter.AdminID)
-		argIdx++
+		where.where("l.admin_id = ?", filter.AdminID)
 	}
 	if filter.Action != "" {
-		conditions = append(conditions, fmt.Sprintf("l.action = $%d", argIdx))
-		args = append(args, filter.Action)
-		argIdx++
+		where.where("l.action = ?", filter.Action)
 	}
 	if filter.EntityType != "" {
-		conditions = append(conditions, fmt.Sprintf("l.entity_type = $%d", argIdx))
-		args = append(args, filter.EntityType)
-		argIdx++
+		where.where("l.entity_type = ?", filter.EntityType)
 	}
 
-	where := "1=1"
-	if len(conditions) > 0 {
-		where = strings.Join(conditions, " AND ")
-	}
+	whereClause := where.clause()
 
 	var total int64
-	countArgs := make([]interface{}, len(args))
-	copy(countArgs, args)
-	err := r.db.Pool.QueryRow(ctx, fmt.Sprintf(`SELECT COUNT(*) FROM audit_logs l WHERE %s`, where), countArgs...).Scan(&total)
+	err := r.db.Pool.QueryRow(ctx, fmt.Sprintf(`SELECT COUNT(*) FROM audit_logs l WHERE %s`, whereClause), where.params()...).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	args = append(args, limit, offset)
 	query := fmt.Sprintf(`
 		SELECT l.id, l.admin_id, COALESCE(u.email, ''), l.action, l.entity_type,
 		       l.entity_id::text, l.details, l.ip_address, l.created_at
@@ -2684,10 +2727,10 @@ func (r *adminRepository) ListAuditLogs(ctx context.Context, filter *models.Audi
 		LEFT JOIN users u ON l.admin_id = u.id
 		WHERE %s
 		ORDER BY l.created_at DESC
-		LIMIT $%d OFFSET $%d
-	`, where, argIdx, argIdx+1)
+		LIMIT %s OFFSET %s
+	`, whereClause, where.bind(limit), where.bind(offset))
 
-	rows, err := r.db.Pool.Query(ctx, query, args...)
+	rows, err := r.db.Pool.Query(ctx, query, where.params()...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -2913,3 +2956,179 @@ func (r *adminRepository) IsDeviceBanned(ctx context.Context, deviceID string) (
 	`, deviceID).Scan(&exists)
 	return exists, err
 }
+
+// reportGroupTable describes one report table for the grouped listing.
+// open is the SQL condition for an unresolved report (alias r); label and
+// owner are read from the target row (alias t).
+type reportGroupTable struct {
+	table, target, reporter, open string
+	join, label, owner            string
+}
+
+var reportGroupTables = map[string]reportGroupTable{
+	"posts": {
+		table:    "post_reports",
+		target:   "post_id",
+		reporter: "user_id",
+		open:     "r.report_status IN ('PENDING', 'REVIEWING')",
+		join:     "posts",
+		label:    "COALESCE(NULLIF(t.title, ''), LEFT(t.description, 80), '')",
+		owner:    "t.user_id::text",
+	},
+	"comments": {
+		table:    "comment_reports",
+		target:   "comment_id",
+		reporter: "user_id",
+		open:     "r.report_status IN ('PENDING', 'REVIEWING')",
+		join:     "post_comments",
+		label:    "COALESCE(LEFT(t.text, 80), '')",
+		owner:    "t.user_id::text",
+	},
+	"users": {
+		table:    "user_reports",
+		target:   "reported_user",
+		reporter: "reported_by_id",
+		open:     "r.resolved IS NOT TRUE",
+		join:     "users",
+		label:    "COALESCE(t.email, '')",
+		owner:    "NULL::text",
+	},
+	// Message labels come from the report snapshot, which outlives the
+	// message itself.
+	"messages": {
+		table:    "message_reports",
+		target:   "message_id",
+		reporter: "user_id",
+		open:     "r.report_status IN ('PENDING', 'REVIEWING')",
+		join:     "messages",
+		label:    "COALESCE((SELECT LEFT(s.message_content, 80) FROM message_reports s WHERE s.message_id = g.target_id LIMIT 1), '')",
+		owner:    "(SELECT s.sender_id::text FROM message_reports s WHERE s.message_id = g.target_id LIMIT 1)",
+	},
+	"businesses": {
+		table:    "business_reports",
+		target:   "business_id",
+		reporter: "user_id",
+		open:     "r.report_status IN ('PENDING', 'REVIEWING')",
+		join:     "business_profiles",
+		label:    "COALESCE(t.name, '')",
+		owner:    "t.user_id::text",
+	},
+}
+
+func (r *adminRepository) ListReportGroups(ctx context.Context, reportType string, filter *models.AdminReportFilter) ([]*models.AdminReportGroup, int64, error) {
+	spec, ok := reportGroupTables[reportType]
+	if !ok {
+		return nil, 0, fmt.Errorf("unknown report type %q", reportType)
+	}
+
+	where := newWhereBuilder()
+
+	if reportType == "users" {
+		switch filter.Status {
+		case "RESOLVED":
+			where.where("r.resolved = true")
+		case "PENDING":
+			where.where("r.resolved IS NOT TRUE")
+		}
+	} else if filter.Status != "" {
+		where.where("r.report_status = ?", filter.Status)
+	}
+
+	applyReportTriageFilters(filter, where)
+
+	whereClause := where.clause()
+
+	limit := 20
+	if filter.Limit > 0 && filter.Limit <= 100 {
+		limit = filter.Limit
+	}
+	page := 1
+	if filter.Page > 0 {
+		page = filter.Page
+	}
+	offset := (page - 1) * limit
+
+	// The reasons breakdown re-applies the same filters so it adds up to
+	// report_count.
+	query := fmt.Sprintf(`
+		WITH g AS (
+			SELECT r.%[2]s AS target_id,
+				COUNT(*) AS report_count,
+				COUNT(DISTINCT r.%[3]s) AS reporter_count,
+				COUNT(*) FILTER (WHERE %[4]s) AS open_count,
+				MIN(r.created_at) AS first_reported_at,
+				MAX(r.created_at) AS last_reported_at
+			FROM %[1]s r
+			WHERE %[8]s
+			GROUP BY r.%[2]s
+		)
+		SELECT g.target_id::text, %[6]s, %[7]s,
+			g.report_count, g.reporter_count, g.open_count,
+			(SELECT COALESCE(json_agg(json_build_object('reason', x.reason, 'count', x.n) ORDER BY x.n DESC, x.reason), '[]')
+				FROM (
+					SELECT r.reason, COUNT(*) AS n
+					FROM %[1]s r
+					WHERE r.%[2]s = g.target_id AND %[8]s
+					GROUP BY r.reason
+				) x),
+			g.first_reported_at, g.last_reported_at,
+			COUNT(*) OVER() AS total
+		FROM g
+		LEFT JOIN %[5]s t ON t.id = g.target_id
+		ORDER BY g.open_count DESC, g.last_reported_at DESC
+		LIMIT %[9]s OFFSET %[10]s
+	`, spec.table, spec.target, spec.reporter, spec.open, spec.join, spec.label, spec.owner,
+		whereClause, where.bind(limit), where.bind(offset))
+
+	rows, err := r.db.Pool.Query(ctx, query, where.params()...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var total int64
+	groups := []*models.AdminReportGroup{}
+	for rows.Next() {
+		g := &models.AdminReportGroup{}
+		var reasons []byte
+		if err := rows.Scan(
+			&g.TargetID, &g.TargetLabel, &g.TargetOwnerID,
+			&g.ReportCount, &g.ReporterCount, &g.OpenCount,
+			&reasons,
+			&g.FirstReportedAt, &g.LastReportedAt,
+			&total,
+		); err != nil {
+			return nil, 0, err
+		}
+		if err := json.Unmarshal(reasons, &g.Reasons); err != nil {
+			return nil, 0, err
+		}
+		groups = append(groups, g)
+	}
+	return groups, total, rows.Err()
+}
+
+func (r *adminRepository) ResolveReportGroup(ctx context.Context, reportType, targetID, status string) (int64, error) {
+	spec, ok := reportGroupTables[reportType]
+	if !ok {
+		return 0, fmt.Errorf("unknown report type %q", reportType)
+	}
+
+	var query string
+	args := []interface{}{targetID}
+	if reportType == "users" {
+		// user_reports only has a resolved flag; rejecting closes it too.
+		query = fmt.Sprintf(`UPDATE %s r SET resolved = true, updated_at = NOW() WHERE r.%s = $1 AND %s`,
+			spec.table, spec.target, spec.open)
+	} else {
+		query = fmt.Sprintf(`UPDATE %s r SET report_status = $2, updated_at = NOW() WHERE r.%s = $1 AND %s`,
+			spec.table, spec.target, spec.open)
+		args = append(args, status)
+	}
+
+	result, err := r.db.Pool.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
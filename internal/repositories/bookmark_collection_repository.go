@@ -0,0 +1,168 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// BookmarkCollectionRepository handles persistence for a user's named
+// bookmark folders and which folder each bookmark is filed in.
+type BookmarkCollectionRepository interface {
+	// Create inserts a collection. Returns ErrBookmarkCollectionExists when
+	// the user already has one with the same name (case-insensitive).
+	Create(ctx context.Context, collection *models.BookmarkCollection) error
+	// GetByID returns the user's collection with its bookmark count.
+	GetByID(ctx context.Context, collectionID, userID string) (*models.BookmarkCollection, error)
+	Rename(ctx context.Context, collection *models.BookmarkCollection) error
+	// Delete removes the collection; its bookmarks stay, unfiled.
+	Delete(ctx context.Context, collectionID, userID string) error
+	// List returns the user's collections with counts, newest first, plus
+	// the overall and unfiled bookmark totals.
+	List(ctx context.Context, userID string) (*models.BookmarkCollectionsResponse, error)
+	// AssignBookmark files the user's bookmark of postID into collectionID
+	// (nil unfiles it). Returns ErrBookmarkNotFound when the post isn't
+	// bookmarked.
+	AssignBookmark(ctx context.Context, userID, postID string, collectionID *string) error
+}
+
+type bookmarkCollectionRepository struct {
+	db *database.DB
+}
+
+// NewBookmarkCollectionRepository wires a new bookmark collection repository.
+func NewBookmarkCollectionRepository(db *database.DB) BookmarkCollectionRepository {
+	return &bookmarkCollectionRepository{db: db}
+}
+
+var (
+	// ErrBookmarkCollectionNotFound is returned when a collection doesn't
+	// exist or belongs to someone else.
+	ErrBookmarkCollectionNotFound = newNotFoundError("bookmark collection not found")
+	// ErrBookmarkCollectionExists is returned when the name is taken.
+	ErrBookmarkCollectionExists = newConflictError("bookmark collection already exists")
+	// ErrBookmarkNotFound is returned when assigning a post the user hasn't
+	// bookmarked.
+	ErrBookmarkNotFound = newNotFoundError("bookmark not found")
+)
+
+const bookmarkCollectionColumns = `bc.id, bc.user_id, bc.name,
+	(SELECT COUNT(*) FROM post_bookmarks pb
+		JOIN posts p ON p.id = pb.post_id AND p.deleted_at IS NULL
+		WHERE pb.collection_id = bc.id),
+	bc.created_at, bc.updated_at`
+
+func bookmarkCollectionScanDest(c *models.BookmarkCollection) []interface{} {
+	return []interface{}{&c.ID, &c.UserID, &c.Name, &c.BookmarkCount, &c.CreatedAt, &c.UpdatedAt}
+}
+
+func (r *bookmarkCollectionRepository) Create(ctx context.Context, collection *models.BookmarkCollection) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO bookmark_collections (id, user_id, name, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		RETURNING created_at, updated_at
+	`, collection.ID, collection.UserID, collection.Name,
+	).Scan(&collection.CreatedAt, &collection.UpdatedAt)
+	if err != nil && isUniqueViolation(err) {
+		return ErrBookmarkCollectionExists
+	}
+	if err != nil {
+		return fmt.Errorf("create bookmark collection: %w", err)
+	}
+	return nil
+}
+
+func (r *bookmarkCollectionRepository) GetByID(ctx context.Context, collectionID, userID string) (*models.BookmarkCollection, error) {
+	out := &models.BookmarkCollection{}
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT `+bookmarkCollectionColumns+` FROM bookmark_collections bc WHERE bc.id = $1 AND bc.user_id = $2`,
+		collectionID, userID,
+	).Scan(bookmarkCollectionScanDest(out)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrBookmarkCollectionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get bookmark collection: %w", err)
+	}
+	return out, nil
+}
+
+func (r *bookmarkCollectionRepository) Rename(ctx context.Context, collection *models.BookmarkCollection) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		UPDATE bookmark_collections SET name = $3, updated_at = NOW()
+		WHERE id = $1 AND user_id = $2
+		RETURNING updated_at
+	`, collection.ID, collection.UserID, collection.Name).Scan(&collection.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrBookmarkCollectionNotFound
+	}
+	if err != nil && isUniqueViolation(err) {
+		return ErrBookmarkCollectionExists
+	}
+	if err != nil {
+		return fmt.Errorf("rename bookmark collection: %w", err)
+	}
+	return nil
+}
+
+func (r *bookmarkCollectionRepository) Delete(ctx context.Context, collectionID, userID string) error {
+	tag, err := r.db.Pool.Exec(ctx,
+		`DELETE FROM bookmark_collections WHERE id = $1 AND user_id = $2`, collectionID, userID)
+	if err != nil {
+		return fmt.Errorf("delete bookmark collection: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrBookmarkCollectionNotFound
+	}
+	return nil
+}
+
+func (r *bookmarkCollectionRepository) List(ctx context.Context, userID string) (*models.BookmarkCollectionsResponse, error) {
+	rows, err := r.db.Reader().Query(ctx,
+		`SELECT `+bookmarkCollectionColumns+` FROM bookmark_collections bc
+		WHERE bc.user_id = $1 ORDER BY bc.created_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list bookmark collections: %w", err)
+	}
+	defer rows.Close()
+
+	out := &models.BookmarkCollectionsResponse{Collections: make([]*models.BookmarkCollection, 0)}
+	for rows.Next() {
+		c := &models.BookmarkCollection{}
+		if err := rows.Scan(bookmarkCollectionScanDest(c)...); err != nil {
+			return nil, fmt.Errorf("scan bookmark collection: %w", err)
+		}
+		out.Collections = append(out.Collections, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	err = r.db.Reader().QueryRow(ctx, `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE pb.collection_id IS NULL)
+		FROM post_bookmarks pb
+		JOIN posts p ON p.id = pb.post_id AND p.deleted_at IS NULL
+		WHERE pb.user_id = $1
+	`, userID).Scan(&out.TotalCount, &out.UnfiledCount)
+	if err != nil {
+		return nil, fmt.Errorf("count bookmarks: %w", err)
+	}
+	return out, nil
+}
+
+func (r *bookmarkCollectionRepository) AssignBookmark(ctx context.Context, userID, postID string, collectionID *string) error {
+	tag, err := r.db.Pool.Exec(ctx,
+		`UPDATE post_bookmarks SET collection_id = $3 WHERE user_id = $1 AND post_id = $2`,
+		userID, postID, collectionID)
+	if err != nil {
+		return fmt.Errorf("assign bookmark: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrBookmarkNotFound
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// BusinessBookingRepository handles persistence for booking requests.
+type BusinessBookingRepository interface {
+	// Create inserts a PENDING booking; timestamps are filled on the struct.
+	Create(ctx context.Context, booking *models.BusinessBooking) error
+
+	// GetByID returns a single booking.
+	GetByID(ctx context.Context, bookingID string) (*models.BusinessBooking, error)
+
+	// Transition moves a booking from one of the from statuses to to,
+	// recording responseNote when non-nil. Returns ErrBookingConflict when
+	// the booking is no longer in a from status (e.g. the customer cancelled
+	// while the owner was accepting).
+	Transition(ctx context.Context, bookingID string, from []models.BookingStatus, to models.BookingStatus, responseNote *string) (*models.BusinessBooking, error)
+
+	// ListByBusiness returns the business's bookings in [filter.From,
+	// filter.To), ordered by requested time, enriched with customer info.
+	ListByBusiness(ctx context.Context, businessID string, filter *models.BookingFilter) ([]*models.BusinessBookingWithCustomer, error)
+
+	// ListByCustomer returns the customer's bookings, newest first.
+	ListByCustomer(ctx context.Context, customerID string, limit, offset int) ([]*models.BusinessBooking, int, error)
+}
+
+type businessBookingRepository struct {
+	db *database.DB
+}
+
+// NewBusinessBookingRepository wires a new booking repository.
+func NewBusinessBookingRepository(db *database.DB) BusinessBookingRepository {
+	return &businessBookingRepository{db: db}
+}
+
+var (
+	// ErrBookingNotFound is returned when a booking id doesn't exist.
+	ErrBookingNotFound = newNotFoundError("booking not found")
+	// ErrBookingConflict is returned when a transition doesn't apply to the
+	// booking's current status.
+	ErrBookingConflict = newConflictError("booking status changed")
+)
+
+const bookingColumns = `id, business_id, customer_id, requested_at, note, status,
+		response_note, responded_at, created_at, updated_at`
+
+func bookingScanDest(b *models.BusinessBooking) []interface{} {
+	return []interface{}{
+		&b.ID, &b.BusinessID, &b.CustomerID, &b.RequestedAt, &b.Note, &b.Status,
+		&b.ResponseNote, &b.RespondedAt, &b.CreatedAt, &b.UpdatedAt,
+	}
+}
+
+func (r *businessBookingRepository) Create(ctx context.Context, booking *models.BusinessBooking) error {
+	const q = `
+		INSERT INTO business_bookings (id, business_id, customer_id, requested_at, note, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+		RETURNING created_at, updated_at
+	`
+	if err := r.db.Pool.QueryRow(ctx, q,
+		booking.ID,
+		booking.BusinessID,
+		booking.CustomerID,
+		booking.RequestedAt,
+		booking.Note,
+		booking.Status,
+	).Scan(&booking.CreatedAt, &booking.UpdatedAt); err != nil {
+		return fmt.Errorf("create booking: %w", err)
+	}
+	return nil
+}
+
+func (r *businessBookingRepository) GetByID(ctx context.Context, bookingID string) (*models.BusinessBooking, error) {
+	out := &models.BusinessBooking{}
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT `+bookingColumns+` FROM business_bookings WHERE id = $1`, bookingID,
+	).Scan(bookingScanDest(out)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrBookingNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get booking: %w", err)
+	}
+	return out, nil
+}
+
+func (r *businessBookingRepository) Transition(ctx context.Context, bookingID string, from []models.BookingStatus, to models.BookingStatus, responseNote *string) (*models.BusinessBooking, error) {
+	fromStrs := make([]string, len(from))
+	for i, s := range from {
+		fromStrs[i] = string(s)
+	}
+	// responded_at marks the owner's decision; a customer cancel leaves it.
+	q := `
+		UPDATE business_bookings
+		SET status = $1,
+		    response_note = COALESCE($2, response_note),
+		    responded_at = CASE WHEN $1 IN ('ACCEPTED', 'DECLINED') THEN NOW() ELSE responded_at END,
+		    updated_at = NOW()
+		WHERE id = $3 AND status = ANY($4)
+		RETURNING ` + bookingColumns
+	out := &models.BusinessBooking{}
+	err := r.db.Pool.QueryRow(ctx, q, string(to), responseNote, bookingID, fromStrs).Scan(bookingScanDest(out)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrBookingConflict
+	}
+	if err != nil {
+		return nil, fmt.Errorf("update booking status: %w", err)
+	}
+	return out, nil
+}
+
+func (r *businessBookingRepository) ListByBusiness(ctx context.Context, businessID string, filter *models.BookingFilter) ([]*models.BusinessBookingWithCustomer, error) {
+	args := []interface{}{businessID, filter.From, filter.To}
+	statusClause := ""
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, s := range filter.Statuses {
+			statuses[i] = string(s)
+		}
+		args = append(args, statuses)
+		statusClause = "AND b.status = ANY($4)"
+	}
+
+	q := fmt.Sprintf(`
+		SELECT
+			b.id, b.business_id, b.customer_id, b.requested_at, b.note, b.status,
+			b.response_note, b.responded_at, b.created_at, b.updated_at,
+			p.first_name, p.last_name, p.avatar, p.avatar_color
+		FROM business_bookings b
+		LEFT JOIN profiles p ON p.id = b.customer_id
+		WHERE b.business_id = $1 AND b.requested_at >= $2 AND b.requested_at < $3 %s
+		ORDER BY b.requested_at ASC
+		LIMIT 500
+	`, statusClause)
+
+	rows, err := r.db.Pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list bookings: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]*models.BusinessBookingWithCustomer, 0)
+	for rows.Next() {
+		w := &models.BusinessBookingWithCustomer{}
+		dest := append(bookingScanDest(&w.BusinessBooking),
+			&w.CustomerFirstName, &w.CustomerLastName, &w.CustomerAvatar, &w.CustomerAvatarHex)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("scan booking: %w", err)
+		}
+		out = append(out, w)
+	}
+	return out, rows.Err()
+}
+
+func (r *businessBookingRepository) ListByCustomer(ctx context.Context, customerID string, limit, offset int) ([]*models.BusinessBooking, int, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT `+bookingColumns+`
+		FROM business_bookings
+		WHERE customer_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`, customerID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list my bookings: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]*models.BusinessBooking, 0)
+	for rows.Next() {
+		b := &models.BusinessBooking{}
+		if err := rows.Scan(bookingScanDest(b)...); err != nil {
+			return nil, 0, fmt.Errorf("scan booking: %w", err)
+		}
+		out = append(out, b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("list my bookings: %w", err)
+	}
+
+	var total int
+	if err := r.db.Pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM business_bookings WHERE customer_id = $1`, customerID,
+	).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count my bookings: %w", err)
+	}
+	return out, total, nil
+}
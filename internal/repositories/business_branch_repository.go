@@ -0,0 +1,270 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// BusinessBranchRepository stores the branches of a business (table
+// business_locations).
+type BusinessBranchRepository interface {
+	// Create inserts a branch; timestamps are filled on the struct.
+	Create(ctx context.Context, branch *models.BusinessBranch) error
+
+	// GetByID returns a branch.
+	GetByID(ctx context.Context, branchID string) (*models.BusinessBranch, error)
+
+	// Update persists every editable field of the branch.
+	Update(ctx context.Context, branch *models.BusinessBranch) error
+
+	// Delete removes a branch. Posts naming it keep existing without one.
+	Delete(ctx context.Context, branchID string) error
+
+	// ListByBusiness returns every branch of the business by position.
+	ListByBusiness(ctx context.Context, businessID string) ([]*models.BusinessBranch, error)
+
+	// CountByBusiness returns how many branches the business has.
+	CountByBusiness(ctx context.Context, businessID string) (int, error)
+
+	// GetByPostIDs returns the branch named by each post, keyed by post ID.
+	// Posts without a branch are absent from the map.
+	GetByPostIDs(ctx context.Context, postIDs []string) (map[string]*models.BusinessBranch, error)
+}
+
+type businessBranchRepository struct {
+	db *database.DB
+}
+
+// NewBusinessBranchRepository wires a new branch repository.
+func NewBusinessBranchRepository(db *database.DB) BusinessBranchRepository {
+	return &businessBranchRepository{db: db}
+}
+
+// ErrBranchNotFound is returned when a branch id doesn't exist.
+var ErrBranchNotFound = newNotFoundError("branch not found")
+
+const branchColumns = `bl.id, bl.business_id, bl.name, bl.address,
+	ST_Y(bl.location::geometry), ST_X(bl.location::geometry),
+	bl.country, bl.province, bl.district, bl.neighborhood, bl.phone_number,
+	bl.hours, bl.position, bl.created_at, bl.updated_at`
+
+// scanBranch scans branchColumns, optionally preceded by extra leading
+// columns (e.g. the post id in GetByPostIDs).
+func scanBranch(row pgx.Row, lead ...any) (*models.BusinessBranch, error) {
+	b := &models.BusinessBranch{}
+	dest := append(lead,
+		&b.ID, &b.BusinessID, &b.Name, &b.Address,
+		&b.Latitude, &b.Longitude,
+		&b.Country, &b.Province, &b.District, &b.Neighborhood, &b.PhoneNumber,
+		&b.Hours, &b.Position, &b.CreatedAt, &b.UpdatedAt,
+	)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	if b.Hours == nil {
+		b.Hours = []models.BusinessHoursResponse{}
+	}
+	return b, nil
+}
+
+// branchPoint returns the WKT for the branch's point, or nil when it has
+// none, for ST_GeogFromText.
+func branchPoint(b *models.BusinessBranch) *string {
+	if !b.HasLocation() {
+		return nil
+	}
+	wkt := fmt.Sprintf("SRID=4326;POINT(%f %f)", *b.Longitude, *b.Latitude)
+	return &wkt
+}
+
+func (r *businessBranchRepository) Create(ctx context.Context, branch *models.BusinessBranch) error {
+	const q = `
+		INSERT INTO business_locations (
+			id, business_id, name, address, location,
+			country, province, district, neighborhood, phone_number,
+			hours, position, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, ST_GeogFromText($5), $6, $7, $8, $9, $10, $11, $12, NOW(), NOW())
+		RETURNING created_at, updated_at
+	`
+	if err := r.db.Pool.QueryRow(ctx, q,
+		branch.ID, branch.BusinessID, branch.Name, branch.Address, branchPoint(branch),
+		branch.Country, branch.Province, branch.District, branch.Neighborhood, branch.PhoneNumber,
+		branch.Hours, branch.Position,
+	).Scan(&branch.CreatedAt, &branch.UpdatedAt); err != nil {
+		return fmt.Errorf("create branch: %w", err)
+	}
+	return nil
+}
+
+func (r *businessBranchRepository) GetByID(ctx context.Context, branchID string) (*models.BusinessBranch, error) {
+	q := `SELECT ` + branchColumns + ` FROM business_locations bl WHERE bl.id = $1`
+	branch, err := scanBranch(r.db.Pool.QueryRow(ctx, q, branchID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrBranchNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get branch: %w", err)
+	}
+	return branch, nil
+}
+
+func (r *businessBranchRepository) Update(ctx context.Context, branch *models.BusinessBranch) error {
+	const q = `
+		UPDATE business_locations
+		SET name = $1, address = $2, location = ST_GeogFromText($3),
+			country = $4, province = $5, district = $6, neighborhood = $7, phone_number = $8,
+			hours = $9, position = $10, updated_at = NOW()
+		WHERE id = $11
+		RETURNING updated_at
+	`
+	err := r.db.Pool.QueryRow(ctx, q,
+		branch.Name, branch.Address, branchPoint(branch),
+		branch.Country, branch.Province, branch.District, branch.Neighborhood, branch.PhoneNumber,
+		branch.Hours, branch.Position, branch.ID,
+	).Scan(&branch.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrBranchNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("update branch: %w", err)
+	}
+	return nil
+}
+
+func (r *businessBranchRepository) Delete(ctx context.Context, branchID string) error {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM business_locations WHERE id = $1`, branchID)
+	if err != nil {
+		return fmt.Errorf("delete branch: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrBranchNotFound
+	}
+	return nil
+}
+
+func (r *businessBranchRepository) ListByBusiness(ctx context.Context, businessID string) ([]*models.BusinessBranch, error) {
+	q := `SELECT ` + branchColumns + `
+		FROM business_locations bl
+		WHERE bl.business_id = $1
+		ORDER BY bl.position ASC, bl.created_at ASC`
+	rows, err := r.db.Pool.Query(ctx, q, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("list branches: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]*models.BusinessBranch, 0)
+	for rows.Next() {
+		branch, err := scanBranch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan branch: %w", err)
+		}
+		out = append(out, branch)
+	}
+	return out, rows.Err()
+}
+
+func (r *businessBranchRepository) CountByBusiness(ctx context.Context, businessID string) (int, error) {
+	var n int
+	if err := r.db.Pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM business_locations WHERE business_id = $1`, businessID,
+	).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count branches: %w", err)
+	}
+	return n, nil
+}
+
+func (r *businessBranchRepository) GetByPostIDs(ctx context.Context, postIDs []string) (map[string]*models.BusinessBranch, error) {
+	out := make(map[string]*models.BusinessBranch)
+	if len(postIDs) == 0 {
+		return out, nil
+	}
+	q := `SELECT p.id, ` + branchColumns + `
+		FROM posts p
+		JOIN business_locations bl ON bl.id = p.business_location_id
+		WHERE p.id = ANY($1)`
+	rows, err := r.db.Reader().Query(ctx, q, postIDs)
+	if err != nil {
+		return nil, fmt.Errorf("branches for posts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID string
+		branch, err := scanBranch(rows, &postID)
+		if err != nil {
+			return nil, fmt.Errorf("scan branch: %w", err)
+		}
+		out[postID] = branch
+	}
+	return out, rows.Err()
+}
+
+// nearestBranchJoin is a LATERAL join exposing, as nb, the business's branch
+// closest to the point ($lngArg, $latArg): nb.id, nb.name, nb.lat, nb.lng and
+// nb.distance_km. bp must alias business_profiles. Businesses without
+// located branches get a row of NULLs.
+func nearestBranchJoin(lngArg, latArg int) string {
+	origin := fmt.Sprintf("ST_SetSRID(ST_MakePoint($%d, $%d), 4326)::geography", lngArg, latArg)
+	return `
+		LEFT JOIN LATERAL (
+			SELECT bl.id, bl.name,
+				ST_Y(bl.location::geometry) AS lat, ST_X(bl.location::geometry) AS lng,
+				ST_Distance(bl.location, ` + origin + `) / 1000 AS distance_km
+			FROM business_locations bl
+			WHERE bl.business_id = bp.id AND bl.location IS NOT NULL
+			ORDER BY bl.location <-> ` + origin + `
+			LIMIT 1
+		) nb ON true`
+}
+
+// branchWithin is a predicate true when the business bp has a branch within
+// $radiusArg meters of the point ($lngArg, $latArg). It only refers to bp, so
+// the planner can apply it before the nearestBranchJoin lateral.
+func branchWithin(lngArg, latArg, radiusArg int) string {
+	return fmt.Sprintf(`EXISTS (
+				SELECT 1 FROM business_locations bl
+				WHERE bl.business_id = bp.id
+					AND ST_DWithin(bl.location, ST_SetSRID(ST_MakePoint($%d, $%d), 4326)::geography, $%d)
+			)`, lngArg, latArg, radiusArg)
+}
+
+// nearestBranchColumns selects the nb columns of nearestBranchJoin, in the
+// order nearestBranchScan.dest scans them.
+const nearestBranchColumns = `nb.id, nb.name, nb.lat, nb.lng, nb.distance_km`
+
+// nearestBranchScan receives nearestBranchColumns.
+type nearestBranchScan struct {
+	id, name             *string
+	lat, lng, distanceKm *float64
+}
+
+func (s *nearestBranchScan) dest() []any {
+	return []any{&s.id, &s.name, &s.lat, &s.lng, &s.distanceKm}
+}
+
+// apply sets the business's DistanceKm to the nearer of its main address
+// (mainKm; nil when it has none or hides it) and the nearest branch, and
+// records the branch when it is the nearer one.
+func (s *nearestBranchScan) apply(b *models.BusinessProfile, mainKm *float64) {
+	b.DistanceKm = mainKm
+	if s.id == nil || s.distanceKm == nil || s.lat == nil || s.lng == nil {
+		return
+	}
+	if mainKm != nil && *mainKm <= *s.distanceKm {
+		return
+	}
+	b.DistanceKm = s.distanceKm
+	b.NearestBranch = &models.NearestBranch{
+		ID:         *s.id,
+		Name:       *s.name,
+		Latitude:   *s.lat,
+		Longitude:  *s.lng,
+		DistanceKm: *s.distanceKm,
+	}
+}
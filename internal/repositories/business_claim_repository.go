@@ -0,0 +1,272 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrBusinessClaimNotFound is returned when a claim request doesn't exist.
+var ErrBusinessClaimNotFound = newNotFoundError("business claim not found")
+
+// ErrBusinessClaimPending is returned when the user already has an open
+// claim for the business.
+var ErrBusinessClaimPending = newConflictError("business claim already pending")
+
+// ErrBusinessClaimReviewed is returned when a review races another one.
+var ErrBusinessClaimReviewed = newConflictError("business claim already reviewed")
+
+// BusinessClaimRepository persists claim requests for ownerless businesses,
+// their review and the ownership history they produce.
+type BusinessClaimRepository interface {
+	Create(ctx context.Context, claim *models.BusinessClaimRequest) error
+	GetByID(ctx context.Context, id string) (*models.BusinessClaimRequest, error)
+	// GetLatestForUser returns the user's most recent claim on a business,
+	// or ErrBusinessClaimNotFound.
+	GetLatestForUser(ctx context.Context, businessID, userID string) (*models.BusinessClaimRequest, error)
+	// List returns admin queue rows (optionally filtered by status) plus
+	// total, oldest first.
+	List(ctx context.Context, status *string, limit, offset int) ([]*models.BusinessClaimListItem, int, error)
+	// Approve marks the claim APPROVED, hands the business to the claimant,
+	// records the change in the ownership history, voids open import
+	// invites and rejects every other pending claim on the business with
+	// supersededReason. It returns the claims it rejected.
+	Approve(ctx context.Context, claim *models.BusinessClaimRequest, reviewerID string, reason *string, supersededReason string) ([]*models.BusinessClaimRequest, error)
+	// Reject marks a PENDING claim REJECTED.
+	Reject(ctx context.Context, id, reviewerID string, reason *string) error
+	// ListOwnershipHistory returns a business's ownership changes, newest first.
+	ListOwnershipHistory(ctx context.Context, businessID string) ([]*models.BusinessOwnershipChange, error)
+}
+
+type businessClaimRepository struct {
+	db *database.DB
+}
+
+// NewBusinessClaimRepository creates the repository.
+func NewBusinessClaimRepository(db *database.DB) BusinessClaimRepository {
+	return &businessClaimRepository{db: db}
+}
+
+func (r *businessClaimRepository) Create(ctx context.Context, claim *models.BusinessClaimRequest) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO business_claim_requests (id, business_id, user_id, note, proof, status)
+		VALUES ($1, $2, $3, $4, $5, 'PENDING')
+		RETURNING status, created_at, updated_at
+	`, claim.ID, claim.BusinessID, claim.UserID, claim.Note, claim.Proof,
+	).Scan(&claim.Status, &claim.CreatedAt, &claim.UpdatedAt)
+	if err != nil && isUniqueViolation(err) {
+		return ErrBusinessClaimPending
+	}
+	return err
+}
+
+const claimColumns = `
+	id, business_id, user_id, note, proof, status, rejection_reason,
+	reviewed_by, reviewed_at, created_at, updated_at`
+
+func claimScanTargets(c *models.BusinessClaimRequest) []any {
+	return []any{
+		&c.ID, &c.BusinessID, &c.UserID, &c.Note, &c.Proof, &c.Status, &c.RejectionReason,
+		&c.ReviewedBy, &c.ReviewedAt, &c.CreatedAt, &c.UpdatedAt,
+	}
+}
+
+func scanClaim(row pgx.Row) (*models.BusinessClaimRequest, error) {
+	c := &models.BusinessClaimRequest{}
+	err := row.Scan(claimScanTargets(c)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrBusinessClaimNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (r *businessClaimRepository) GetByID(ctx context.Context, id string) (*models.BusinessClaimRequest, error) {
+	return scanClaim(r.db.Pool.QueryRow(ctx, `
+		SELECT`+claimColumns+`
+		FROM business_claim_requests
+		WHERE id = $1
+	`, id))
+}
+
+func (r *businessClaimRepository) GetLatestForUser(ctx context.Context, businessID, userID string) (*models.BusinessClaimRequest, error) {
+	return scanClaim(r.db.Pool.QueryRow(ctx, `
+		SELECT`+claimColumns+`
+		FROM business_claim_requests
+		WHERE business_id = $1 AND user_id = $2
+		ORDER BY created_at DESC
+		LIMIT 1
+	`, businessID, userID))
+}
+
+func (r *businessClaimRepository) List(ctx context.Context, status *string, limit, offset int) ([]*models.BusinessClaimListItem, int, error) {
+	var total int
+	if err := r.db.Pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM business_claim_requests
+		WHERE ($1::text IS NULL OR status = $1)
+	`, status).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT
+			c.id, c.business_id, c.user_id, c.note, c.proof, c.status, c.rejection_reason,
+			c.reviewed_by, c.reviewed_at, c.created_at, c.updated_at,
+			b.name, b.avatar, u.email,
+			NULLIF(TRIM(CONCAT_WS(' ', p.first_name, p.last_name)), '')
+		FROM business_claim_requests c
+		JOIN business_profiles b ON b.id = c.business_id
+		JOIN users u ON u.id = c.user_id
+		LEFT JOIN profiles p ON p.id = c.user_id
+		WHERE ($1::text IS NULL OR c.status = $1)
+		ORDER BY c.created_at ASC
+		LIMIT $2 OFFSET $3
+	`, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]*models.BusinessClaimListItem, 0, limit)
+	for rows.Next() {
+		item := &models.BusinessClaimListItem{}
+		targets := append(claimScanTargets(&item.BusinessClaimRequest),
+			&item.BusinessName, &item.BusinessAvatar, &item.ClaimantEmail, &item.ClaimantName)
+		if err := rows.Scan(targets...); err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+	}
+	return items, total, rows.Err()
+}
+
+func (r *businessClaimRepository) Approve(ctx context.Context, claim *models.BusinessClaimRequest, reviewerID string, reason *string, supersededReason string) ([]*models.BusinessClaimRequest, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE business_claim_requests
+		SET status = 'APPROVED', rejection_reason = NULL, reviewed_by = $2,
+		    reviewed_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = 'PENDING'
+	`, claim.ID, reviewerID)
+	if err != nil {
+		return nil, fmt.Errorf("approve claim: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrBusinessClaimReviewed
+	}
+
+	// History first: it reads the owner being replaced.
+	tag, err = tx.Exec(ctx, `
+		INSERT INTO business_ownership_history
+			(business_id, previous_owner_id, new_owner_id, changed_by, source, claim_request_id, reason)
+		SELECT id, user_id, $2, $3, 'CLAIM_REQUEST', $4, $5
+		FROM business_profiles
+		WHERE id = $1 AND deleted_at IS NULL
+	`, claim.BusinessID, claim.UserID, reviewerID, claim.ID, reason)
+	if err != nil {
+		return nil, fmt.Errorf("record ownership change: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrNotFound
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE business_profiles
+		SET user_id = $2, updated_at = NOW()
+		WHERE id = $1
+	`, claim.BusinessID, claim.UserID); err != nil {
+		return nil, fmt.Errorf("transfer business: %w", err)
+	}
+	// Outstanding import invites would hand the business away again.
+	if _, err := tx.Exec(ctx, `
+		UPDATE business_owner_invites
+		SET expires_at = NOW()
+		WHERE business_id = $1 AND claimed_at IS NULL AND expires_at > NOW()
+	`, claim.BusinessID); err != nil {
+		return nil, fmt.Errorf("expire invites: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, `
+		UPDATE business_claim_requests
+		SET status = 'REJECTED', rejection_reason = $3, reviewed_by = $4,
+		    reviewed_at = NOW(), updated_at = NOW()
+		WHERE business_id = $1 AND id <> $2 AND status = 'PENDING'
+		RETURNING`+claimColumns,
+		claim.BusinessID, claim.ID, supersededReason, reviewerID)
+	if err != nil {
+		return nil, fmt.Errorf("reject other claims: %w", err)
+	}
+	var superseded []*models.BusinessClaimRequest
+	for rows.Next() {
+		c := &models.BusinessClaimRequest{}
+		if err := rows.Scan(claimScanTargets(c)...); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		superseded = append(superseded, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return superseded, nil
+}
+
+func (r *businessClaimRepository) Reject(ctx context.Context, id, reviewerID string, reason *string) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE business_claim_requests
+		SET status = 'REJECTED', rejection_reason = $2, reviewed_by = $3,
+		    reviewed_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = 'PENDING'
+	`, id, reason, reviewerID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrBusinessClaimReviewed
+	}
+	return nil
+}
+
+func (r *businessClaimRepository) ListOwnershipHistory(ctx context.Context, businessID string) ([]*models.BusinessOwnershipChange, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT h.id, h.business_id, h.previous_owner_id, pu.email, h.new_owner_id, nu.email,
+		       h.changed_by, h.source, h.claim_request_id, h.reason, h.created_at
+		FROM business_ownership_history h
+		LEFT JOIN users pu ON pu.id = h.previous_owner_id
+		LEFT JOIN users nu ON nu.id = h.new_owner_id
+		WHERE h.business_id = $1
+		ORDER BY h.created_at DESC
+	`, businessID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []*models.BusinessOwnershipChange{}
+	for rows.Next() {
+		ch := &models.BusinessOwnershipChange{}
+		if err := rows.Scan(
+			&ch.ID, &ch.BusinessID, &ch.PreviousOwnerID, &ch.PreviousOwnerEmail, &ch.NewOwnerID, &ch.NewOwnerEmail,
+			&ch.ChangedBy, &ch.Source, &ch.ClaimRequestID, &ch.Reason, &ch.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		changes = append(changes, ch)
+	}
+	return changes, rows.Err()
+}
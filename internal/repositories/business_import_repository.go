@@ -0,0 +1,282 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrBusinessImportNotFound is returned when an import job doesn't exist.
+var ErrBusinessImportNotFound = newNotFoundError("business import not found")
+
+// ErrBusinessInviteNotFound is returned when no invite matches the token.
+var ErrBusinessInviteNotFound = newNotFoundError("business invite not found")
+
+// ErrBusinessInviteClaimed is returned when the invite was already used.
+var ErrBusinessInviteClaimed = newConflictError("business invite already claimed")
+
+// BusinessImportRepository persists admin CSV imports of businesses and the
+// owner invites they send.
+type BusinessImportRepository interface {
+	CreateJob(ctx context.Context, job *models.BusinessImportJob) error
+	// GetJob returns the job without its file, or ErrBusinessImportNotFound.
+	GetJob(ctx context.Context, id string) (*models.BusinessImportJob, error)
+	// ListJobs returns jobs newest first (optionally filtered by status)
+	// plus total, without their files.
+	ListJobs(ctx context.Context, status *string, limit, offset int) ([]*models.BusinessImportJob, int, error)
+	// NextToProcess returns the oldest QUEUED or RUNNING job with its file,
+	// or nil when there is nothing to import.
+	NextToProcess(ctx context.Context) (*models.BusinessImportJob, error)
+	// RecordRow marks the next row as handled: created and invited bump the
+	// counters; a row with errors counts as failed and its errors are
+	// appended to the job's.
+	RecordRow(ctx context.Context, id string, created, invited bool, rowErrs []models.BusinessImportRowError) error
+	// Finish moves the job to COMPLETED or FAILED and drops its file.
+	Finish(ctx context.Context, id, status string, errMsg *string) error
+
+	// ExistingLicenses returns which of the license numbers (lower-cased)
+	// already belong to a business.
+	ExistingLicenses(ctx context.Context, licenses []string) (map[string]bool, error)
+
+	CreateInvite(ctx context.Context, invite *models.BusinessOwnerInvite) error
+	// GetInviteByTokenHash returns the invite or ErrBusinessInviteNotFound.
+	GetInviteByTokenHash(ctx context.Context, tokenHash string) (*models.BusinessOwnerInvite, error)
+	// ClaimInvite hands the invite's business to userID and records the
+	// change in the ownership history, or returns ErrBusinessInviteClaimed
+	// when someone was first.
+	ClaimInvite(ctx context.Context, invite *models.BusinessOwnerInvite, userID string) error
+}
+
+type businessImportRepository struct {
+	db *database.DB
+}
+
+// NewBusinessImportRepository creates the repository.
+func NewBusinessImportRepository(db *database.DB) BusinessImportRepository {
+	return &businessImportRepository{db: db}
+}
+
+func (r *businessImportRepository) CreateJob(ctx context.Context, job *models.BusinessImportJob) error {
+	return r.db.Pool.QueryRow(ctx, `
+		INSERT INTO business_import_jobs (id, created_by, file_name, status, total_rows, csv_data)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at, updated_at
+	`, job.ID, job.CreatedBy, job.FileName, job.Status, job.TotalRows, job.CSVData,
+	).Scan(&job.CreatedAt, &job.UpdatedAt)
+}
+
+const importJobColumns = `
+	id, created_by, file_name, status, total_rows, processed_rows, created_count,
+	failed_count, invited_count, row_errors, error, started_at, completed_at,
+	created_at, updated_at`
+
+func importJobScanTargets(j *models.BusinessImportJob) []any {
+	return []any{
+		&j.ID, &j.CreatedBy, &j.FileName, &j.Status, &j.TotalRows, &j.ProcessedRows, &j.CreatedCount,
+		&j.FailedCount, &j.InvitedCount, &j.RowErrors, &j.Error, &j.StartedAt, &j.CompletedAt,
+		&j.CreatedAt, &j.UpdatedAt,
+	}
+}
+
+func (r *businessImportRepository) GetJob(ctx context.Context, id string) (*models.BusinessImportJob, error) {
+	job := &models.BusinessImportJob{}
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT`+importJobColumns+`
+		FROM business_import_jobs
+		WHERE id = $1
+	`, id).Scan(importJobScanTargets(job)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrBusinessImportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *businessImportRepository) ListJobs(ctx context.Context, status *string, limit, offset int) ([]*models.BusinessImportJob, int, error) {
+	var total int
+	if err := r.db.Pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM business_import_jobs
+		WHERE ($1::text IS NULL OR status = $1)
+	`, status).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT`+importJobColumns+`
+		FROM business_import_jobs
+		WHERE ($1::text IS NULL OR status = $1)
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	jobs := make([]*models.BusinessImportJob, 0, limit)
+	for rows.Next() {
+		job := &models.BusinessImportJob{}
+		if err := rows.Scan(importJobScanTargets(job)...); err != nil {
+			return nil, 0, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, total, rows.Err()
+}
+
+func (r *businessImportRepository) NextToProcess(ctx context.Context) (*models.BusinessImportJob, error) {
+	job := &models.BusinessImportJob{}
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT`+importJobColumns+`, csv_data
+		FROM business_import_jobs
+		WHERE status IN ('QUEUED', 'RUNNING')
+		ORDER BY created_at ASC
+		LIMIT 1
+	`).Scan(append(importJobScanTargets(job), &job.CSVData)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *businessImportRepository) RecordRow(ctx context.Context, id string, created, invited bool, rowErrs []models.BusinessImportRowError) error {
+	if rowErrs == nil {
+		rowErrs = []models.BusinessImportRowError{}
+	}
+	rowErrors, err := json.Marshal(rowErrs)
+	if err != nil {
+		return err
+	}
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE business_import_jobs
+		SET status = 'RUNNING',
+		    started_at = COALESCE(started_at, NOW()),
+		    processed_rows = processed_rows + 1,
+		    created_count = created_count + CASE WHEN $2 THEN 1 ELSE 0 END,
+		    invited_count = invited_count + CASE WHEN $3 THEN 1 ELSE 0 END,
+		    failed_count = failed_count + CASE WHEN $4 THEN 1 ELSE 0 END,
+		    row_errors = row_errors || $5::jsonb
+		WHERE id = $1 AND status IN ('QUEUED', 'RUNNING')
+	`, id, created, invited, len(rowErrs) > 0, string(rowErrors))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrBusinessImportNotFound
+	}
+	return nil
+}
+
+func (r *businessImportRepository) Finish(ctx context.Context, id, status string, errMsg *string) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE business_import_jobs
+		SET status = $2, error = $3, csv_data = '', completed_at = NOW()
+		WHERE id = $1
+	`, id, status, errMsg)
+	return err
+}
+
+func (r *businessImportRepository) ExistingLicenses(ctx context.Context, licenses []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	if len(licenses) == 0 {
+		return existing, nil
+	}
+	lowered := make([]string, len(licenses))
+	for i, l := range licenses {
+		lowered[i] = strings.ToLower(l)
+	}
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT DISTINCT LOWER(license_no)
+		FROM business_profiles
+		WHERE LOWER(license_no) = ANY($1) AND deleted_at IS NULL
+	`, lowered)
+	if err != nil {
+		return nil, fmt.Errorf("existing licenses: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var license string
+		if err := rows.Scan(&license); err != nil {
+			return nil, err
+		}
+		existing[license] = true
+	}
+	return existing, rows.Err()
+}
+
+func (r *businessImportRepository) CreateInvite(ctx context.Context, invite *models.BusinessOwnerInvite) error {
+	return r.db.Pool.QueryRow(ctx, `
+		INSERT INTO business_owner_invites (id, business_id, import_job_id, email, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at
+	`, invite.ID, invite.BusinessID, invite.ImportJobID, invite.Email, invite.TokenHash, invite.ExpiresAt,
+	).Scan(&invite.CreatedAt)
+}
+
+func (r *businessImportRepository) GetInviteByTokenHash(ctx context.Context, tokenHash string) (*models.BusinessOwnerInvite, error) {
+	invite := &models.BusinessOwnerInvite{}
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT id, business_id, import_job_id, email, token_hash, expires_at,
+		       claimed_by, claimed_at, created_at
+		FROM business_owner_invites
+		WHERE token_hash = $1
+	`, tokenHash).Scan(
+		&invite.ID, &invite.BusinessID, &invite.ImportJobID, &invite.Email, &invite.TokenHash, &invite.ExpiresAt,
+		&invite.ClaimedBy, &invite.ClaimedAt, &invite.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrBusinessInviteNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return invite, nil
+}
+
+func (r *businessImportRepository) ClaimInvite(ctx context.Context, invite *models.BusinessOwnerInvite, userID string) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE business_owner_invites
+		SET claimed_by = $2, claimed_at = NOW()
+		WHERE id = $1 AND claimed_at IS NULL
+	`, invite.ID, userID)
+	if err != nil {
+		return fmt.Errorf("claim invite: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrBusinessInviteClaimed
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO business_ownership_history
+			(business_id, previous_owner_id, new_owner_id, changed_by, source)
+		SELECT id, user_id, $2, $2, 'INVITE'
+		FROM business_profiles
+		WHERE id = $1 AND deleted_at IS NULL
+	`, invite.BusinessID, userID); err != nil {
+		return fmt.Errorf("record ownership change: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE business_profiles
+		SET user_id = $2, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`, invite.BusinessID, userID); err != nil {
+		return fmt.Errorf("transfer business: %w", err)
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,216 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// BusinessProductRepository handles persistence for business catalog products.
+// Deletes are soft so posts and messages that reference a product keep
+// rendering it as unavailable instead of losing the reference.
+type BusinessProductRepository interface {
+	// Create inserts a product; ID, timestamps are filled on the struct.
+	Create(ctx context.Context, product *models.BusinessProduct) error
+
+	// GetByID returns a live (not deleted) product.
+	GetByID(ctx context.Context, productID string) (*models.BusinessProduct, error)
+
+	// Update persists every editable field of product.
+	Update(ctx context.Context, product *models.BusinessProduct) error
+
+	// Delete soft-deletes a product.
+	Delete(ctx context.Context, productID string) error
+
+	// ListByBusiness returns paginated products ordered by position.
+	// includeDiscontinued=false hides DISCONTINUED rows for the public view.
+	ListByBusiness(ctx context.Context, businessID string, includeDiscontinued bool, limit, offset int) ([]*models.BusinessProduct, int, error)
+
+	// GetByPostIDs returns the product attached to each post, keyed by post ID.
+	// Posts without a (live) product are absent from the map.
+	GetByPostIDs(ctx context.Context, postIDs []string) (map[string]*models.BusinessProduct, error)
+}
+
+type businessProductRepository struct {
+	db *database.DB
+}
+
+// NewBusinessProductRepository wires a new product repository.
+func NewBusinessProductRepository(db *database.DB) BusinessProductRepository {
+	return &businessProductRepository{db: db}
+}
+
+// ErrProductNotFound is returned when a product id doesn't exist or was deleted.
+var ErrProductNotFound = newNotFoundError("product not found")
+
+const productColumns = `id, business_id, name, description, price, currency, photos,
+		availability, position, created_at, updated_at`
+
+func scanProduct(row pgx.Row, extra ...interface{}) (*models.BusinessProduct, error) {
+	p := &models.BusinessProduct{}
+	dest := append(extra,
+		&p.ID, &p.BusinessID, &p.Name, &p.Description, &p.Price, &p.Currency, &p.Photos,
+		&p.Availability, &p.Position, &p.CreatedAt, &p.UpdatedAt,
+	)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	if p.Photos == nil {
+		p.Photos = []models.Photo{}
+	}
+	return p, nil
+}
+
+func (r *businessProductRepository) Create(ctx context.Context, product *models.BusinessProduct) error {
+	if product.Photos == nil {
+		product.Photos = []models.Photo{}
+	}
+	const q = `
+		INSERT INTO business_products (id, business_id, name, description, price, currency, photos, availability, position, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())
+		RETURNING created_at, updated_at
+	`
+	if err := r.db.Pool.QueryRow(ctx, q,
+		product.ID,
+		product.BusinessID,
+		product.Name,
+		product.Description,
+		product.Price,
+		product.Currency,
+		product.Photos,
+		product.Availability,
+		product.Position,
+	).Scan(&product.CreatedAt, &product.UpdatedAt); err != nil {
+		return fmt.Errorf("create product: %w", err)
+	}
+	return nil
+}
+
+func (r *businessProductRepository) GetByID(ctx context.Context, productID string) (*models.BusinessProduct, error) {
+	q := `SELECT ` + productColumns + ` FROM business_products WHERE id = $1 AND deleted_at IS NULL`
+	p, err := scanProduct(r.db.Pool.QueryRow(ctx, q, productID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrProductNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get product: %w", err)
+	}
+	return p, nil
+}
+
+func (r *businessProductRepository) Update(ctx context.Context, product *models.BusinessProduct) error {
+	if product.Photos == nil {
+		product.Photos = []models.Photo{}
+	}
+	const q = `
+		UPDATE business_products
+		SET name = $1, description = $2, price = $3, currency = $4, photos = $5,
+		    availability = $6, position = $7, updated_at = NOW()
+		WHERE id = $8 AND deleted_at IS NULL
+		RETURNING updated_at
+	`
+	err := r.db.Pool.QueryRow(ctx, q,
+		product.Name,
+		product.Description,
+		product.Price,
+		product.Currency,
+		product.Photos,
+		product.Availability,
+		product.Position,
+		product.ID,
+	).Scan(&product.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrProductNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("update product: %w", err)
+	}
+	return nil
+}
+
+func (r *businessProductRepository) Delete(ctx context.Context, productID string) error {
+	tag, err := r.db.Pool.Exec(ctx,
+		`UPDATE business_products SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`,
+		productID)
+	if err != nil {
+		return fmt.Errorf("delete product: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrProductNotFound
+	}
+	return nil
+}
+
+func (r *businessProductRepository) ListByBusiness(ctx context.Context, businessID string, includeDiscontinued bool, limit, offset int) ([]*models.BusinessProduct, int, error) {
+	filter := "AND availability <> 'DISCONTINUED'"
+	if includeDiscontinued {
+		filter = ""
+	}
+
+	listQ := fmt.Sprintf(`
+		SELECT %s
+		FROM business_products
+		WHERE business_id = $1 AND deleted_at IS NULL %s
+		ORDER BY position ASC, created_at DESC
+		LIMIT $2 OFFSET $3
+	`, productColumns, filter)
+
+	rows, err := r.db.Pool.Query(ctx, listQ, businessID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list products: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]*models.BusinessProduct, 0)
+	for rows.Next() {
+		p, err := scanProduct(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("scan product: %w", err)
+		}
+		out = append(out, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("list products: %w", err)
+	}
+
+	countQ := fmt.Sprintf(`SELECT COUNT(*) FROM business_products WHERE business_id = $1 AND deleted_at IS NULL %s`, filter)
+	var total int
+	if err := r.db.Pool.QueryRow(ctx, countQ, businessID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count products: %w", err)
+	}
+
+	return out, total, nil
+}
+
+func (r *businessProductRepository) GetByPostIDs(ctx context.Context, postIDs []string) (map[string]*models.BusinessProduct, error) {
+	out := make(map[string]*models.BusinessProduct)
+	if len(postIDs) == 0 {
+		return out, nil
+	}
+	const q = `
+		SELECT p.id, bp.id, bp.business_id, bp.name, bp.description, bp.price, bp.currency, bp.photos,
+		       bp.availability, bp.position, bp.created_at, bp.updated_at
+		FROM posts p
+		JOIN business_products bp ON bp.id = p.business_product_id AND bp.deleted_at IS NULL
+		WHERE p.id = ANY($1)
+	`
+	rows, err := r.db.Reader().Query(ctx, q, postIDs)
+	if err != nil {
+		return nil, fmt.Errorf("products for posts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID string
+		p, err := scanProduct(rows, &postID)
+		if err != nil {
+			return nil, fmt.Errorf("scan product: %w", err)
+		}
+		out[postID] = p
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,192 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// BusinessQuickReplyRepository stores a business's saved chat replies and
+// its away message.
+type BusinessQuickReplyRepository interface {
+	// Create inserts a quick reply; timestamps are filled on the struct.
+	Create(ctx context.Context, reply *models.BusinessQuickReply) error
+
+	// GetByID returns a quick reply.
+	GetByID(ctx context.Context, replyID string) (*models.BusinessQuickReply, error)
+
+	// Update persists title, body and position.
+	Update(ctx context.Context, reply *models.BusinessQuickReply) error
+
+	// Delete removes a quick reply.
+	Delete(ctx context.Context, replyID string) error
+
+	// ListByBusiness returns every quick reply of the business by position.
+	ListByBusiness(ctx context.Context, businessID string) ([]*models.BusinessQuickReply, error)
+
+	// CountByBusiness returns how many quick replies the business has.
+	CountByBusiness(ctx context.Context, businessID string) (int, error)
+
+	// GetAutoReply returns the business's away message; a disabled, empty
+	// one when it never set one.
+	GetAutoReply(ctx context.Context, businessID string) (*models.BusinessAutoReply, error)
+
+	// SetAutoReply creates or replaces the business's away message.
+	SetAutoReply(ctx context.Context, reply *models.BusinessAutoReply) error
+
+	// ClaimAutoReply marks that the away message is being sent in the
+	// conversation. False when it was already sent there within cooldown.
+	ClaimAutoReply(ctx context.Context, conversationID string, cooldown time.Duration) (bool, error)
+}
+
+type businessQuickReplyRepository struct {
+	db *database.DB
+}
+
+// NewBusinessQuickReplyRepository wires a new quick reply repository.
+func NewBusinessQuickReplyRepository(db *database.DB) BusinessQuickReplyRepository {
+	return &businessQuickReplyRepository{db: db}
+}
+
+// ErrQuickReplyNotFound is returned when a quick reply id doesn't exist.
+var ErrQuickReplyNotFound = newNotFoundError("quick reply not found")
+
+const quickReplyColumns = `id, business_id, title, body, position, created_at, updated_at`
+
+func scanQuickReply(row pgx.Row) (*models.BusinessQuickReply, error) {
+	q := &models.BusinessQuickReply{}
+	if err := row.Scan(&q.ID, &q.BusinessID, &q.Title, &q.Body, &q.Position, &q.CreatedAt, &q.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func (r *businessQuickReplyRepository) Create(ctx context.Context, reply *models.BusinessQuickReply) error {
+	const q = `
+		INSERT INTO business_quick_replies (id, business_id, title, body, position, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		RETURNING created_at, updated_at
+	`
+	if err := r.db.Pool.QueryRow(ctx, q,
+		reply.ID, reply.BusinessID, reply.Title, reply.Body, reply.Position,
+	).Scan(&reply.CreatedAt, &reply.UpdatedAt); err != nil {
+		return fmt.Errorf("create quick reply: %w", err)
+	}
+	return nil
+}
+
+func (r *businessQuickReplyRepository) GetByID(ctx context.Context, replyID string) (*models.BusinessQuickReply, error) {
+	q := `SELECT ` + quickReplyColumns + ` FROM business_quick_replies WHERE id = $1`
+	reply, err := scanQuickReply(r.db.Pool.QueryRow(ctx, q, replyID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrQuickReplyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get quick reply: %w", err)
+	}
+	return reply, nil
+}
+
+func (r *businessQuickReplyRepository) Update(ctx context.Context, reply *models.BusinessQuickReply) error {
+	const q = `
+		UPDATE business_quick_replies
+		SET title = $1, body = $2, position = $3, updated_at = NOW()
+		WHERE id = $4
+		RETURNING updated_at
+	`
+	err := r.db.Pool.QueryRow(ctx, q, reply.Title, reply.Body, reply.Position, reply.ID).Scan(&reply.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrQuickReplyNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("update quick reply: %w", err)
+	}
+	return nil
+}
+
+func (r *businessQuickReplyRepository) Delete(ctx context.Context, replyID string) error {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM business_quick_replies WHERE id = $1`, replyID)
+	if err != nil {
+		return fmt.Errorf("delete quick reply: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrQuickReplyNotFound
+	}
+	return nil
+}
+
+func (r *businessQuickReplyRepository) ListByBusiness(ctx context.Context, businessID string) ([]*models.BusinessQuickReply, error) {
+	q := `SELECT ` + quickReplyColumns + `
+		FROM business_quick_replies
+		WHERE business_id = $1
+		ORDER BY position ASC, created_at ASC`
+	rows, err := r.db.Pool.Query(ctx, q, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("list quick replies: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]*models.BusinessQuickReply, 0)
+	for rows.Next() {
+		reply, err := scanQuickReply(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan quick reply: %w", err)
+		}
+		out = append(out, reply)
+	}
+	return out, rows.Err()
+}
+
+func (r *businessQuickReplyRepository) CountByBusiness(ctx context.Context, businessID string) (int, error) {
+	var n int
+	if err := r.db.Pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM business_quick_replies WHERE business_id = $1`, businessID,
+	).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count quick replies: %w", err)
+	}
+	return n, nil
+}
+
+func (r *businessQuickReplyRepository) GetAutoReply(ctx context.Context, businessID string) (*models.BusinessAutoReply, error) {
+	reply := &models.BusinessAutoReply{BusinessID: businessID}
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT enabled, message, updated_at FROM business_auto_replies WHERE business_id = $1`, businessID,
+	).Scan(&reply.Enabled, &reply.Message, &reply.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return reply, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get auto reply: %w", err)
+	}
+	return reply, nil
+}
+
+func (r *businessQuickReplyRepository) SetAutoReply(ctx context.Context, reply *models.BusinessAutoReply) error {
+	const q = `
+		INSERT INTO business_auto_replies (business_id, enabled, message, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (business_id) DO UPDATE
+		SET enabled = EXCLUDED.enabled, message = EXCLUDED.message, updated_at = NOW()
+		RETURNING updated_at
+	`
+	if err := r.db.Pool.QueryRow(ctx, q, reply.BusinessID, reply.Enabled, reply.Message).Scan(&reply.UpdatedAt); err != nil {
+		return fmt.Errorf("set auto reply: %w", err)
+	}
+	return nil
+}
+
+func (r *businessQuickReplyRepository) ClaimAutoReply(ctx context.Context, conversationID string, cooldown time.Duration) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE conversations SET auto_replied_at = NOW()
+		WHERE id = $1 AND (auto_replied_at IS NULL OR auto_replied_at < $2)
+	`, conversationID, time.Now().Add(-cooldown))
+	if err != nil {
+		return false, fmt.Errorf("claim auto reply: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -22,6 +23,9 @@ type BusinessRepository interface {
 	Update(ctx context.Context, business *models.BusinessProfile) error
 	Delete(ctx context.Context, businessID string) error
 	List(ctx context.Context, filter *models.BusinessListFilter) ([]*models.BusinessProfile, error)
+	// CountByCategory counts the businesses List would return per active
+	// category, ignoring filter.CategoryIDs, largest first.
+	CountByCategory(ctx context.Context, filter *models.BusinessListFilter) ([]*models.FacetCount, error)
 
 	// Categories
 	GetCategoriesByBusinessID(ctx context.Context, businessID string) ([]*models.BusinessCategory, error)
@@ -35,9 +39,23 @@ type BusinessRepository interface {
 	DeleteHoursByBusinessID(ctx context.Context, businessID string) error
 
 	// Gallery
+	// AddAttachment appends an image to the end of the gallery and sets its
+	// Position.
 	AddAttachment(ctx context.Context, attachment *models.BusinessAttachment) error
+	// GetAttachmentsByBusinessID returns the gallery in position order.
 	GetAttachmentsByBusinessID(ctx context.Context, businessID string) ([]*models.BusinessAttachment, error)
-	DeleteAttachment(ctx context.Context, attachmentID string) error
+	// GetAttachment returns one gallery image of the business, or
+	// ErrGalleryImageNotFound.
+	GetAttachment(ctx context.Context, businessID, attachmentID string) (*models.BusinessAttachment, error)
+	// DeleteAttachment soft deletes one gallery image of the business, or
+	// returns ErrGalleryImageNotFound.
+	DeleteAttachment(ctx context.Context, businessID, attachmentID string) error
+	// ReorderAttachments sets each gallery image's position to its index in
+	// attachmentIDs.
+	ReorderAttachments(ctx context.Context, businessID string, attachmentIDs []string) error
+	// SetAttachmentCaption sets or clears (nil) a gallery image's caption,
+	// or returns ErrGalleryImageNotFound.
+	SetAttachmentCaption(ctx context.Context, businessID, attachmentID string, caption *string) error
 
 	// Followers
 	Follow(ctx context.Context, businessID, userID string) error
@@ -46,6 +64,14 @@ type BusinessRepository interface {
 	GetFollowers(ctx context.Context, businessID string, limit, offset int) ([]string, error)
 	// GetFollowerCount returns the number of active followers.
 	GetFollowerCount(ctx context.Context, businessID string) (int, error)
+	// ListFollowers returns active followers with their profile, newest
+	// first, plus the total for paging. Deleted accounts are skipped.
+	ListFollowers(ctx context.Context, businessID string, limit, offset int) ([]*models.BusinessFollowerResponse, int, error)
+	// GetFollowerProvinceCounts groups active followers by province, using
+	// the reference-list name when the profile is linked to one.
+	GetFollowerProvinceCounts(ctx context.Context, businessID string) ([]*models.ProvinceCount, error)
+	GetFollowerNotificationSettings(ctx context.Context, businessID string) (*models.BusinessFollowerNotificationSettings, error)
+	SetFollowerNotificationSettings(ctx context.Context, businessID string, settings *models.BusinessFollowerNotificationSettings) error
 
 	// Categories Management
 	GetAllCategories(ctx context.Context, search *string) ([]*models.BusinessCategory, error)
@@ -62,6 +88,7 @@ type BusinessRepository interface {
 	GetDailyPostLikes(ctx context.Context, businessID string, days int) ([]models.DailyCount, error)
 	GetDailyPostComments(ctx context.Context, businessID string, days int) ([]models.DailyCount, error)
 	GetDailyPostViews(ctx context.Context, businessID string, days int) ([]models.DailyCount, error)
+	GetDailyPostImpressions(ctx context.Context, businessID string, days int) ([]models.DailyCount, error)
 	// GetRatingDistribution returns visible-review counts keyed by star (1-5).
 	GetRatingDistribution(ctx context.Context, businessID string) (map[int]int, error)
 	// GetOwnerPostCounts returns dashboard content counts: the business's
@@ -74,8 +101,29 @@ type BusinessRepository interface {
 	// GetEventAttendeeTotal returns distinct users going to any of the
 	// business's events (all-time).
 	GetEventAttendeeTotal(ctx context.Context, businessID string) (int, error)
+
+	// Featured posts
+	// GetFeaturedPostIDs returns the business's featured posts in display
+	// order, skipping deleted ones.
+	GetFeaturedPostIDs(ctx context.Context, businessID string) ([]string, error)
+	// AddFeaturedPost features one of the business's own live posts after
+	// the existing ones. Returns ErrFeaturedPostNotFound when the post isn't
+	// the business's.
+	AddFeaturedPost(ctx context.Context, businessID, postID string) error
+	RemoveFeaturedPost(ctx context.Context, businessID, postID string) error
+	// ReorderFeaturedPosts sets each featured post's position to its index
+	// in postIDs.
+	ReorderFeaturedPosts(ctx context.Context, businessID string, postIDs []string) error
 }
 
+// ErrFeaturedPostNotFound is returned when featuring a post that doesn't
+// belong to the business (or was deleted or hidden).
+var ErrFeaturedPostNotFound = newNotFoundError("post not found for business")
+
+// ErrGalleryImageNotFound is returned when a gallery image doesn't belong
+// to the business (or was deleted).
+var ErrGalleryImageNotFound = newNotFoundError("gallery image not found for business")
+
 type businessRepository struct {
 	db *database.DB
 }
@@ -222,7 +270,7 @@ func (r *businessRepository) GetByID(ctx context.Context, businessID string) (*m
 	)
 
 	if err == pgx.ErrNoRows {
-		return nil, fmt.Errorf("business profile not found")
+		return nil, newNotFoundError("business profile not found")
 	}
 	if err == nil {
 		scanBusinessLocation(lng, lat, business)
@@ -500,7 +548,7 @@ func (r *businessRepository) Delete(ctx context.Context, businessID string) erro
 // List lists business profiles with filters
 func (r *businessRepository) List(ctx context.Context, filter *models.BusinessListFilter) ([]*models.BusinessProfile, error) {
 	query := `
-		SELECT DISTINCT
+		SELECT
 			bp.id, bp.user_id, bp.name, bp.license_no, bp.description, bp.address,
 			bp.phone_number, bp.email, bp.website, bp.avatar, bp.avatar_color, bp.cover, bp.status,
 			bp.additional_info, ST_X(bp.address_location::geometry), ST_Y(bp.address_location::geometry),
@@ -509,60 +557,12 @@ func (r *businessRepository) List(ctx context.Context, filter *models.BusinessLi
 			bp.total_follow, bp.avg_rating, bp.review_count, bp.is_verified, bp.created_at, bp.updated_at
 		FROM business_profiles bp
 	`
+	where := businessListWhere(filter, true)
 
-	var conditions []string
-	var args []interface{}
-	argCount := 1
-
-	conditions = append(conditions, "bp.deleted_at IS NULL")
-	// Only list businesses that are visible to others (status = true)
-	conditions = append(conditions, "bp.status = true")
-
-	if filter.UserID != nil {
-		conditions = append(conditions, fmt.Sprintf("bp.user_id = $%d", argCount))
-		args = append(args, *filter.UserID)
-		argCount++
-	}
-
-	if filter.CategoryID != nil {
-		query += " INNER JOIN business_profile_categories bpc ON bp.id = bpc.business_profile_id"
-		conditions = append(conditions, fmt.Sprintf("bpc.business_category_id = $%d", argCount))
-		args = append(args, *filter.CategoryID)
-		argCount++
-	}
-
-	if filter.Province != nil {
-		conditions = append(conditions, fmt.Sprintf("bp.province = $%d", argCount))
-		args = append(args, *filter.Province)
-		argCount++
-	}
-
-	if filter.Search != nil && *filter.Search != "" {
-		conditions = append(conditions, fmt.Sprintf(`(bp.name ILIKE $%d ESCAPE '\' OR bp.description ILIKE $%d ESCAPE '\')`, argCount, argCount))
-		args = append(args, "%"+EscapeLike(*filter.Search)+"%")
-		argCount++
-	}
-
-	if filter.Latitude != nil && filter.Longitude != nil && filter.RadiusKm != nil {
-		conditions = append(conditions, fmt.Sprintf(`
-			ST_DWithin(
-				bp.address_location,
-				ST_SetSRID(ST_MakePoint($%d, $%d), 4326)::geography,
-				$%d
-			)
-		`, argCount, argCount+1, argCount+2))
-		args = append(args, *filter.Longitude, *filter.Latitude, *filter.RadiusKm*1000) // Convert km to meters
-		argCount += 3
-	}
-
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
-	}
-
-	query += fmt.Sprintf(" ORDER BY bp.created_at DESC LIMIT $%d OFFSET $%d", argCount, argCount+1)
-	args = append(args, filter.Limit, filter.Offset)
+	query += " WHERE " + where.clause()
+	query += fmt.Sprintf(" ORDER BY bp.created_at DESC LIMIT %s OFFSET %s", where.bind(filter.Limit), where.bind(filter.Offset))
 
-	rows, err := r.db.Pool.Query(ctx, query, args...)
+	rows, err := r.db.Pool.Query(ctx, query, where.params()...)
 	if err != nil {
 		return nil, err
 	}
@@ -612,6 +612,73 @@ func (r *businessRepository) List(ctx context.Context, filter *models.BusinessLi
 	return businesses, rows.Err()
 }
 
+// businessListWhere builds the WHERE clause of List. withCategories adds
+// the category filter; CountByCategory leaves it out so every category
+// keeps its count while some are selected.
+func businessListWhere(filter *models.BusinessListFilter, withCategories bool) *whereBuilder {
+	// Only list businesses that are visible to others (status = true)
+	where := newWhereBuilder("bp.deleted_at IS NULL", "bp.status = true")
+
+	if filter.UserID != nil {
+		where.where("bp.user_id = ?", *filter.UserID)
+	}
+
+	if withCategories && len(filter.CategoryIDs) > 0 {
+		where.where(`EXISTS (
+			SELECT 1 FROM business_profile_categories bpc
+			WHERE bpc.business_profile_id = bp.id AND bpc.business_category_id = ANY(?)
+		)`, filter.CategoryIDs)
+	}
+
+	if filter.Province != nil {
+		where.where(provinceFilter("bp.", where.bindIndex(*filter.Province)))
+	}
+
+	if filter.Search != nil && *filter.Search != "" {
+		where.where(`(bp.name ILIKE ? ESCAPE '\' OR bp.description ILIKE ? ESCAPE '\')`, "%"+EscapeLike(*filter.Search)+"%")
+	}
+
+	if filter.Latitude != nil && filter.Longitude != nil && filter.RadiusKm != nil {
+		where.where(`
+			ST_DWithin(
+				bp.address_location,
+				ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography,
+				?
+			)
+		`, *filter.Longitude, *filter.Latitude, *filter.RadiusKm*1000) // Convert km to meters
+	}
+	return where
+}
+
+// CountByCategory counts matching businesses per active category.
+func (r *businessRepository) CountByCategory(ctx context.Context, filter *models.BusinessListFilter) ([]*models.FacetCount, error) {
+	where := businessListWhere(filter, false)
+	query := `
+		SELECT bc.id::text, bc.name, COUNT(*)
+		FROM business_profiles bp
+		JOIN business_profile_categories bpc ON bpc.business_profile_id = bp.id
+		JOIN business_categories bc ON bc.id = bpc.business_category_id AND bc.is_active = true
+		WHERE ` + where.clause() + `
+		GROUP BY bc.id, bc.name
+		ORDER BY COUNT(*) DESC, bc.name
+	`
+	rows, err := r.db.Reader().Query(ctx, query, where.params()...)
+	if err != nil {
+		return nil, fmt.Errorf("count businesses by category: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]*models.FacetCount, 0)
+	for rows.Next() {
+		f := &models.FacetCount{}
+		if err := rows.Scan(&f.Value, &f.Label, &f.Count); err != nil {
+			return nil, fmt.Errorf("scan category count: %w", err)
+		}
+		out = append(out, f)
+	}
+	return out, rows.Err()
+}
+
 // GetCategoriesByBusinessID gets all categories for a business
 func (r *businessRepository) GetCategoriesByBusinessID(ctx context.Context, businessID string) ([]*models.BusinessCategory, error) {
 	query := `
@@ -801,31 +868,33 @@ func (r *businessRepository) DeleteHoursByBusinessID(ctx context.Context, busine
 	return err
 }
 
-// AddAttachment adds a gallery attachment
+// AddAttachment adds a gallery attachment after the business's existing ones
 func (r *businessRepository) AddAttachment(ctx context.Context, attachment *models.BusinessAttachment) error {
 	query := `
-		INSERT INTO business_attachments (id, business_profile_id, photo, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO business_attachments (id, business_profile_id, photo, caption, position, created_at, updated_at)
+		SELECT $1, $2, $3, $4,
+			COALESCE((SELECT MAX(position) + 1 FROM business_attachments WHERE business_profile_id = $2 AND deleted_at IS NULL), 0),
+			$5, $6
+		RETURNING position
 	`
 
-	_, err := r.db.Pool.Exec(ctx, query,
+	return r.db.Pool.QueryRow(ctx, query,
 		attachment.ID,
 		attachment.BusinessProfileID,
 		attachment.Photo,
+		attachment.Caption,
 		attachment.CreatedAt,
 		attachment.UpdatedAt,
-	)
-
-	return err
+	).Scan(&attachment.Position)
 }
 
 // GetAttachmentsByBusinessID gets all gallery attachments for a business
 func (r *businessRepository) GetAttachmentsByBusinessID(ctx context.Context, businessID string) ([]*models.BusinessAttachment, error) {
 	query := `
-		SELECT id, business_profile_id, photo, created_at, updated_at
+		SELECT id, business_profile_id, photo, position, caption, created_at, updated_at
 		FROM business_attachments
 		WHERE business_profile_id = $1 AND deleted_at IS NULL
-		ORDER BY created_at DESC
+		ORDER BY position ASC, created_at DESC
 	`
 
 	rows, err := r.db.Pool.Query(ctx, query, businessID)
@@ -841,6 +910,8 @@ func (r *businessRepository) GetAttachmentsByBusinessID(ctx context.Context, bus
 			&attachment.ID,
 			&attachment.BusinessProfileID,
 			&attachment.Photo,
+			&attachment.Position,
+			&attachment.Caption,
 			&attachment.CreatedAt,
 			&attachment.UpdatedAt,
 		)
@@ -853,16 +924,88 @@ func (r *businessRepository) GetAttachmentsByBusinessID(ctx context.Context, bus
 	return attachments, rows.Err()
 }
 
-// DeleteAttachment soft deletes a gallery attachment
-func (r *businessRepository) DeleteAttachment(ctx context.Context, attachmentID string) error {
+// GetAttachment gets one gallery attachment of a business
+func (r *businessRepository) GetAttachment(ctx context.Context, businessID, attachmentID string) (*models.BusinessAttachment, error) {
+	query := `
+		SELECT id, business_profile_id, photo, position, caption, created_at, updated_at
+		FROM business_attachments
+		WHERE id = $1 AND business_profile_id = $2 AND deleted_at IS NULL
+	`
+
+	attachment := &models.BusinessAttachment{}
+	err := r.db.Pool.QueryRow(ctx, query, attachmentID, businessID).Scan(
+		&attachment.ID,
+		&attachment.BusinessProfileID,
+		&attachment.Photo,
+		&attachment.Position,
+		&attachment.Caption,
+		&attachment.CreatedAt,
+		&attachment.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrGalleryImageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// DeleteAttachment soft deletes a gallery attachment of a business
+func (r *businessRepository) DeleteAttachment(ctx context.Context, businessID, attachmentID string) error {
 	query := `
 		UPDATE business_attachments
-		SET deleted_at = $2
-		WHERE id = $1 AND deleted_at IS NULL
+		SET deleted_at = $3
+		WHERE id = $1 AND business_profile_id = $2 AND deleted_at IS NULL
 	`
 
-	_, err := r.db.Pool.Exec(ctx, query, attachmentID, time.Now())
-	return err
+	tag, err := r.db.Pool.Exec(ctx, query, attachmentID, businessID, time.Now())
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrGalleryImageNotFound
+	}
+	return nil
+}
+
+// ReorderAttachments rewrites the positions of the business's gallery
+// images in one transaction.
+func (r *businessRepository) ReorderAttachments(ctx context.Context, businessID string, attachmentIDs []string) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	for i, attachmentID := range attachmentIDs {
+		if _, err := tx.Exec(ctx,
+			`UPDATE business_attachments SET position = $3, updated_at = NOW()
+			WHERE id = $2 AND business_profile_id = $1 AND deleted_at IS NULL`,
+			businessID, attachmentID, i,
+		); err != nil {
+			return fmt.Errorf("reorder gallery image: %w", err)
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// SetAttachmentCaption updates one gallery attachment's caption
+func (r *businessRepository) SetAttachmentCaption(ctx context.Context, businessID, attachmentID string, caption *string) error {
+	query := `
+		UPDATE business_attachments
+		SET caption = $3, updated_at = NOW()
+		WHERE id = $1 AND business_profile_id = $2 AND deleted_at IS NULL
+	`
+
+	tag, err := r.db.Pool.Exec(ctx, query, attachmentID, businessID, caption)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrGalleryImageNotFound
+	}
+	return nil
 }
 
 // Follow follows a business
@@ -943,6 +1086,96 @@ func (r *businessRepository) GetFollowers(ctx context.Context, businessID string
 	return followerIDs, rows.Err()
 }
 
+// ListFollowers returns active followers with their profile, newest first.
+func (r *businessRepository) ListFollowers(ctx context.Context, businessID string, limit, offset int) ([]*models.BusinessFollowerResponse, int, error) {
+	var total int
+	err := r.db.Reader().QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM business_profile_followers f
+		JOIN users u ON u.id = f.follower_id AND u.deleted_at IS NULL
+		WHERE f.business_id = $1 AND f.is_active = true
+	`, businessID).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count business followers: %w", err)
+	}
+
+	rows, err := r.db.Reader().Query(ctx, `
+		SELECT f.follower_id, p.first_name, p.last_name, p.avatar, p.avatar_color,
+		       p.province, p.district, f.created_at
+		FROM business_profile_followers f
+		JOIN users u ON u.id = f.follower_id AND u.deleted_at IS NULL
+		LEFT JOIN profiles p ON p.id = f.follower_id
+		WHERE f.business_id = $1 AND f.is_active = true
+		ORDER BY f.created_at DESC, f.follower_id
+		LIMIT $2 OFFSET $3
+	`, businessID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list business followers: %w", err)
+	}
+	defer rows.Close()
+
+	followers := make([]*models.BusinessFollowerResponse, 0)
+	for rows.Next() {
+		f := &models.BusinessFollowerResponse{}
+		if err := rows.Scan(&f.UserID, &f.FirstName, &f.LastName, &f.Avatar, &f.AvatarColor,
+			&f.Province, &f.District, &f.FollowedAt); err != nil {
+			return nil, 0, fmt.Errorf("scan business follower: %w", err)
+		}
+		followers = append(followers, f)
+	}
+	return followers, total, rows.Err()
+}
+
+// GetFollowerProvinceCounts groups active followers by province.
+func (r *businessRepository) GetFollowerProvinceCounts(ctx context.Context, businessID string) ([]*models.ProvinceCount, error) {
+	rows, err := r.db.Reader().Query(ctx, `
+		SELECT COALESCE(l.name, NULLIF(TRIM(p.province), ''), '') AS province, COUNT(*) AS n
+		FROM business_profile_followers f
+		JOIN users u ON u.id = f.follower_id AND u.deleted_at IS NULL
+		LEFT JOIN profiles p ON p.id = f.follower_id
+		LEFT JOIN locations l ON l.id = p.province_id
+		WHERE f.business_id = $1 AND f.is_active = true
+		GROUP BY 1
+		ORDER BY n DESC, province ASC
+	`, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("count followers by province: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make([]*models.ProvinceCount, 0)
+	for rows.Next() {
+		c := &models.ProvinceCount{}
+		if err := rows.Scan(&c.Province, &c.Count); err != nil {
+			return nil, fmt.Errorf("scan province count: %w", err)
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+// GetFollowerNotificationSettings reads the owner's new-post fan-out switch.
+func (r *businessRepository) GetFollowerNotificationSettings(ctx context.Context, businessID string) (*models.BusinessFollowerNotificationSettings, error) {
+	settings := &models.BusinessFollowerNotificationSettings{}
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT notify_followers_low_priority FROM business_profiles WHERE id = $1 AND deleted_at IS NULL`,
+		businessID,
+	).Scan(&settings.NotifyLowPriorityPosts)
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// SetFollowerNotificationSettings saves the owner's new-post fan-out switch.
+func (r *businessRepository) SetFollowerNotificationSettings(ctx context.Context, businessID string, settings *models.BusinessFollowerNotificationSettings) error {
+	_, err := r.db.Pool.Exec(ctx,
+		`UPDATE business_profiles SET notify_followers_low_priority = $2, updated_at = NOW() WHERE id = $1`,
+		businessID, settings.NotifyLowPriorityPosts,
+	)
+	return err
+}
+
 // GetAllCategories gets all business categories, optionally filtered by search (name).
 func (r *businessRepository) GetAllCategories(ctx context.Context, search *string) ([]*models.BusinessCategory, error) {
 	query := `
@@ -1096,6 +1329,24 @@ func (r *businessRepository) GetDailyPostViews(ctx context.Context, businessID s
 	)
 }
 
+// GetDailyPostImpressions returns post views per day across the business's
+// posts (zero-filled), each viewer counted once per post per day.
+func (r *businessRepository) GetDailyPostImpressions(ctx context.Context, businessID string, days int) ([]models.DailyCount, error) {
+	return r.queryDailyCounts(ctx,
+		`SELECT d::date, COALESCE(v.cnt, 0)
+		 FROM generate_series(CURRENT_DATE - ($2::int - 1), CURRENT_DATE, '1 day') AS d
+		 LEFT JOIN (
+		   SELECT dv.day, SUM(dv.views) AS cnt
+		   FROM post_daily_views dv
+		   JOIN posts p ON p.id = dv.post_id AND p.business_id = $1 AND p.deleted_at IS NULL
+		   WHERE dv.day >= CURRENT_DATE - ($2::int - 1)
+		   GROUP BY 1
+		 ) v ON v.day = d::date
+		 ORDER BY d`,
+		businessID, days,
+	)
+}
+
 // GetRatingDistribution returns visible-review counts keyed by star (1-5);
 // stars with no reviews are filled with 0.
 func (r *businessRepository) GetRatingDistribution(ctx context.Context, businessID string) (map[int]int, error) {
@@ -1241,3 +1492,84 @@ func (r *businessRepository) GetOrCreateCategoryByName(ctx context.Context, name
 	).Scan(&id)
 	return id, err
 }
+
+// GetFeaturedPostIDs returns the featured post ids in position order.
+func (r *businessRepository) GetFeaturedPostIDs(ctx context.Context, businessID string) ([]string, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT f.post_id
+		FROM business_featured_posts f
+		JOIN posts p ON p.id = f.post_id AND p.deleted_at IS NULL
+		WHERE f.business_id = $1
+		ORDER BY f.position, f.created_at
+	`, businessID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// AddFeaturedPost appends the post to the featured list. Already featured
+// posts are left where they are.
+func (r *businessRepository) AddFeaturedPost(ctx context.Context, businessID, postID string) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+		INSERT INTO business_featured_posts (business_id, post_id, position)
+		SELECT $1, p.id, COALESCE((SELECT MAX(position) + 1 FROM business_featured_posts WHERE business_id = $1), 0)
+		FROM posts p
+		WHERE p.id = $2 AND p.business_id = $1 AND p.deleted_at IS NULL AND p.status = true AND p.group_id IS NULL
+		ON CONFLICT (business_id, post_id) DO NOTHING
+	`, businessID, postID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		var featured bool
+		if err := r.db.Pool.QueryRow(ctx,
+			`SELECT EXISTS (SELECT 1 FROM business_featured_posts WHERE business_id = $1 AND post_id = $2)`,
+			businessID, postID,
+		).Scan(&featured); err != nil {
+			return err
+		}
+		if !featured {
+			return ErrFeaturedPostNotFound
+		}
+	}
+	return nil
+}
+
+// RemoveFeaturedPost unfeatures a post; a post that isn't featured is a no-op.
+func (r *businessRepository) RemoveFeaturedPost(ctx context.Context, businessID, postID string) error {
+	_, err := r.db.Pool.Exec(ctx,
+		`DELETE FROM business_featured_posts WHERE business_id = $1 AND post_id = $2`,
+		businessID, postID,
+	)
+	return err
+}
+
+// ReorderFeaturedPosts rewrites the positions in one transaction.
+func (r *businessRepository) ReorderFeaturedPosts(ctx context.Context, businessID string, postIDs []string) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	for i, postID := range postIDs {
+		if _, err := tx.Exec(ctx,
+			`UPDATE business_featured_posts SET position = $3 WHERE business_id = $1 AND post_id = $2`,
+			businessID, postID, i,
+		); err != nil {
+			return fmt.Errorf("reorder featured post: %w", err)
+		}
+	}
+	return tx.Commit(ctx)
+}
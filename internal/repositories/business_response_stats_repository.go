@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// BusinessResponseStatsRepository stores how quickly each business answers
+// chat, as computed by the nightly rollup.
+type BusinessResponseStatsRepository interface {
+	// Get returns the business's stats; nil when the last rollup had no
+	// replies from it.
+	Get(ctx context.Context, businessID string) (*models.BusinessResponseStats, error)
+
+	// Rollup recomputes every business's median response time from chat
+	// messages sent since since, replacing the previous rollup. Returns how
+	// many businesses have stats.
+	Rollup(ctx context.Context, since time.Time) (int64, error)
+}
+
+type businessResponseStatsRepository struct {
+	db *database.DB
+}
+
+// NewBusinessResponseStatsRepository wires a new response stats repository.
+func NewBusinessResponseStatsRepository(db *database.DB) BusinessResponseStatsRepository {
+	return &businessResponseStatsRepository{db: db}
+}
+
+func (r *businessResponseStatsRepository) Get(ctx context.Context, businessID string) (*models.BusinessResponseStats, error) {
+	s := &models.BusinessResponseStats{}
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT business_id, median_response_seconds, sample_size, computed_at
+		FROM business_response_stats
+		WHERE business_id = $1
+	`, businessID).Scan(&s.BusinessID, &s.MedianResponseSeconds, &s.SampleSize, &s.ComputedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// businessResponseWaits yields one row per business reply with how long the
+// customer had been waiting: consecutive messages from the same side are
+// one turn, and a business turn's wait runs from the start of the customer
+// turn before it. Away messages don't count as answers and are left out, as
+// are system messages.
+const businessResponseWaits = `
+	WITH msgs AS (
+		SELECT c.business_id, m.conversation_id, m.id, m.created_at,
+			m.sender_id = b.user_id AS from_business
+		FROM messages m
+		JOIN conversations c ON c.id = m.conversation_id
+		JOIN business_profiles b ON b.id = c.business_id
+		LEFT JOIN business_auto_replies ar ON ar.business_id = b.id AND ar.message <> ''
+		WHERE c.business_id IS NOT NULL
+		  AND m.created_at >= $1
+		  AND m.message_type <> 'SYSTEM'
+		  AND NOT COALESCE(m.sender_id = b.user_id AND m.content = ar.message, FALSE)
+	),
+	turns AS (
+		SELECT business_id, conversation_id, created_at, from_business
+		FROM (
+			SELECT *, LAG(from_business) OVER (PARTITION BY conversation_id ORDER BY created_at, id) AS prev_from_business
+			FROM msgs
+		) t
+		WHERE prev_from_business IS DISTINCT FROM from_business
+	),
+	waits AS (
+		SELECT business_id, from_business,
+			EXTRACT(EPOCH FROM created_at - LAG(created_at) OVER (PARTITION BY conversation_id ORDER BY created_at)) AS wait_seconds
+		FROM turns
+	)
+`
+
+func (r *businessResponseStatsRepository) Rollup(ctx context.Context, since time.Time) (int64, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `DELETE FROM business_response_stats`); err != nil {
+		return 0, fmt.Errorf("clear response stats: %w", err)
+	}
+	tag, err := tx.Exec(ctx, businessResponseWaits+`
+		INSERT INTO business_response_stats (business_id, median_response_seconds, sample_size, computed_at)
+		SELECT business_id,
+			ROUND(percentile_cont(0.5) WITHIN GROUP (ORDER BY wait_seconds))::INTEGER,
+			COUNT(*),
+			NOW()
+		FROM waits
+		WHERE from_business AND wait_seconds IS NOT NULL
+		GROUP BY business_id
+	`, since)
+	if err != nil {
+		return 0, fmt.Errorf("compute response stats: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -56,7 +56,7 @@ func NewBusinessReviewRepository(db *database.DB) BusinessReviewRepository {
 
 // ErrReviewNotFound is returned when a review id doesn't exist or doesn't
 // belong to the calling user (in non-admin contexts).
-var ErrReviewNotFound = errors.New("review not found")
+var ErrReviewNotFound = newNotFoundError("review not found")
 
 func (r *businessReviewRepository) Upsert(ctx context.Context, review *models.BusinessReview) error {
 	const q = `
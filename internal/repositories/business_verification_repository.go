@@ -10,10 +10,10 @@ import (
 )
 
 // ErrVerificationNotFound is returned when a verification request doesn't exist.
-var ErrVerificationNotFound = errors.New("verification request not found")
+var ErrVerificationNotFound = newNotFoundError("verification request not found")
 
 // ErrVerificationPending is returned when the business already has an open request.
-var ErrVerificationPending = errors.New("verification request already pending")
+var ErrVerificationPending = newConflictError("verification request already pending")
 
 // BusinessVerificationRepository persists owner verification requests and
 // admin review decisions.
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// CalendarFeedRepository stores the secret tokens of personal calendar
+// feeds, by SHA-256.
+type CalendarFeedRepository interface {
+	// SetToken creates or replaces the user's feed token.
+	SetToken(ctx context.Context, userID, tokenHash string) error
+	DeleteToken(ctx context.Context, userID string) error
+	// UserIDByToken resolves a feed token and records its use. Returns
+	// ErrCalendarFeedNotFound for unknown tokens and deleted users.
+	UserIDByToken(ctx context.Context, tokenHash string) (string, error)
+}
+
+type calendarFeedRepository struct {
+	db *database.DB
+}
+
+// NewCalendarFeedRepository creates the repository.
+func NewCalendarFeedRepository(db *database.DB) CalendarFeedRepository {
+	return &calendarFeedRepository{db: db}
+}
+
+// ErrCalendarFeedNotFound is returned when a feed token doesn't exist.
+var ErrCalendarFeedNotFound = newNotFoundError("calendar feed not found")
+
+func (r *calendarFeedRepository) SetToken(ctx context.Context, userID, tokenHash string) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		INSERT INTO calendar_feed_tokens (user_id, token_hash, created_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET token_hash = EXCLUDED.token_hash, created_at = NOW(), last_used_at = NULL
+	`, userID, tokenHash)
+	if err != nil {
+		return fmt.Errorf("set calendar feed token: %w", err)
+	}
+	return nil
+}
+
+func (r *calendarFeedRepository) DeleteToken(ctx context.Context, userID string) error {
+	if _, err := r.db.Pool.Exec(ctx, `DELETE FROM calendar_feed_tokens WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("delete calendar feed token: %w", err)
+	}
+	return nil
+}
+
+func (r *calendarFeedRepository) UserIDByToken(ctx context.Context, tokenHash string) (string, error) {
+	var userID string
+	err := r.db.Pool.QueryRow(ctx, `
+		UPDATE calendar_feed_tokens t SET last_used_at = NOW()
+		FROM users u
+		WHERE t.token_hash = $1 AND u.id = t.user_id AND u.deleted_at IS NULL
+		RETURNING t.user_id
+	`, tokenHash).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrCalendarFeedNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("resolve calendar feed token: %w", err)
+	}
+	return userID, nil
+}
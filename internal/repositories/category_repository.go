@@ -90,7 +90,7 @@ func (r *categoryRepository) GetByID(ctx context.Context, categoryID string) (*m
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("category not found")
+			return nil, newNotFoundError("category not found")
 		}
 		return nil, fmt.Errorf("failed to get category: %w", err)
 	}
@@ -177,7 +177,7 @@ func (r *categoryRepository) Update(ctx context.Context, category *models.SellCa
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("category not found")
+		return newNotFoundError("category not found")
 	}
 
 	return nil
@@ -193,7 +193,7 @@ func (r *categoryRepository) Delete(ctx context.Context, categoryID string) erro
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("category not found")
+		return newNotFoundError("category not found")
 	}
 
 	return nil
@@ -3,7 +3,6 @@ package repositories
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -22,9 +21,13 @@ type CommentRepository interface {
 	GetRootCommentID(ctx context.Context, commentID string) (string, error)
 	Update(ctx context.Context, comment *models.PostComment) error
 	Delete(ctx context.Context, commentID string) error
+	// PinComment makes commentID the post's only pinned comment, replacing
+	// any previous pin. UnpinComment clears it.
+	PinComment(ctx context.Context, postID, commentID string) error
+	UnpinComment(ctx context.Context, commentID string) error
 
 	// Comment queries
-	GetByPostID(ctx context.Context, postID string, limit, offset int) ([]*models.PostComment, error)
+	GetByPostID(ctx context.Context, postID string, sort models.CommentSort, limit, offset int) ([]*models.PostComment, error)
 	GetReplies(ctx context.Context, parentCommentID string, limit, offset int) ([]*models.PostComment, error)
 	CountByPostID(ctx context.Context, postID string) (int, error)
 	GetByUserID(ctx context.Context, userID string, limit, offset int) ([]*models.PostComment, error)
@@ -102,7 +105,8 @@ func (r *commentRepository) GetByID(ctx context.Context, commentID string) (*mod
 			id, post_id, user_id, business_id, parent_comment_id, text,
 			ST_Y(location::geometry)::double precision,
 			ST_X(location::geometry)::double precision,
-			total_likes, total_replies, created_at, updated_at, deleted_at, mentioned_user_ids
+			total_likes, total_replies, created_at, updated_at, deleted_at, mentioned_user_ids,
+			pinned_at
 		FROM post_comments
 		WHERE id = $1 AND deleted_at IS NULL
 	`
@@ -124,10 +128,11 @@ func (r *commentRepository) GetByID(ctx context.Context, commentID string) (*mod
 		&comment.UpdatedAt,
 		&comment.DeletedAt,
 		&mentionedRaw,
+		&comment.PinnedAt,
 	)
 
 	if err == pgx.ErrNoRows {
-		return nil, fmt.Errorf("comment not found")
+		return nil, newNotFoundError("comment not found")
 	}
 	if err != nil {
 		return nil, err
@@ -191,17 +196,69 @@ func (r *commentRepository) Delete(ctx context.Context, commentID string) error
 	return err
 }
 
-// GetByPostID gets comments by post ID (top-level comments only)
-func (r *commentRepository) GetByPostID(ctx context.Context, postID string, limit, offset int) ([]*models.PostComment, error) {
+// PinComment clears any existing pin on the post and pins commentID in one
+// transaction so the one-pin-per-post unique index never trips.
+func (r *commentRepository) PinComment(ctx context.Context, postID, commentID string) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE post_comments SET pinned_at = NULL
+		WHERE post_id = $1 AND pinned_at IS NOT NULL AND id <> $2
+	`, postID, commentID); err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE post_comments SET pinned_at = COALESCE(pinned_at, NOW())
+		WHERE id = $1 AND post_id = $2 AND deleted_at IS NULL
+	`, commentID, postID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return newNotFoundError("comment not found")
+	}
+
+	return tx.Commit(ctx)
+}
+
+// UnpinComment clears the pin on a comment. Idempotent.
+func (r *commentRepository) UnpinComment(ctx context.Context, commentID string) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE post_comments SET pinned_at = NULL WHERE id = $1
+	`, commentID)
+	return err
+}
+
+// commentSortOrder maps a sort mode to its ORDER BY keys; ties break on id
+// so offset pages stay stable. Unknown modes sort newest first.
+var commentSortOrder = map[models.CommentSort]string{
+	models.CommentSortNewest:    "created_at DESC, id DESC",
+	models.CommentSortOldest:    "created_at ASC, id ASC",
+	models.CommentSortMostLiked: "total_likes DESC, created_at DESC, id DESC",
+}
+
+// GetByPostID gets comments by post ID (top-level comments only) in the
+// given order. The pinned comment, if any, always sorts first.
+func (r *commentRepository) GetByPostID(ctx context.Context, postID string, sort models.CommentSort, limit, offset int) ([]*models.PostComment, error) {
+	order, ok := commentSortOrder[sort]
+	if !ok {
+		order = commentSortOrder[models.CommentSortNewest]
+	}
 	query := `
 		SELECT
 			id, post_id, user_id, business_id, parent_comment_id, text,
 			ST_Y(location::geometry)::double precision,
 			ST_X(location::geometry)::double precision,
-			total_likes, total_replies, created_at, updated_at, deleted_at, mentioned_user_ids
+			total_likes, total_replies, created_at, updated_at, deleted_at, mentioned_user_ids,
+			pinned_at
 		FROM post_comments
 		WHERE post_id = $1 AND parent_comment_id IS NULL AND deleted_at IS NULL
-		ORDER BY created_at DESC
+		ORDER BY (pinned_at IS NOT NULL) DESC, ` + order + `
 		LIMIT $2 OFFSET $3
 	`
 
@@ -215,7 +272,8 @@ func (r *commentRepository) GetReplies(ctx context.Context, parentCommentID stri
 			id, post_id, user_id, business_id, parent_comment_id, text,
 			ST_Y(location::geometry)::double precision,
 			ST_X(location::geometry)::double precision,
-			total_likes, total_replies, created_at, updated_at, deleted_at, mentioned_user_ids
+			total_likes, total_replies, created_at, updated_at, deleted_at, mentioned_user_ids,
+			pinned_at
 		FROM post_comments
 		WHERE parent_comment_id = $1 AND deleted_at IS NULL
 		ORDER BY created_at ASC
@@ -234,7 +292,8 @@ func (r *commentRepository) GetByUserID(ctx context.Context, userID string, limi
 			id, post_id, user_id, business_id, parent_comment_id, text,
 			ST_Y(location::geometry)::double precision,
 			ST_X(location::geometry)::double precision,
-			total_likes, total_replies, created_at, updated_at, deleted_at, mentioned_user_ids
+			total_likes, total_replies, created_at, updated_at, deleted_at, mentioned_user_ids,
+			pinned_at
 		FROM post_comments
 		WHERE user_id = $1 AND deleted_at IS NULL
 		ORDER BY created_at DESC
@@ -420,6 +479,7 @@ func (r *commentRepository) queryComments(ctx context.Context, query string, arg
 			&comment.UpdatedAt,
 			&comment.DeletedAt,
 			&mentionedRaw,
+			&comment.PinnedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -3,6 +3,7 @@ package repositories_test
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -115,7 +116,7 @@ func TestCommentRepository_GetByUserID(t *testing.T) {
 			func(dest ...any) error {
 				// id, post_id, user_id, business_id, parent_comment_id, text,
 				// latitude, longitude, total_likes, total_replies,
-				// created_at, updated_at, deleted_at, mentioned_user_ids
+				// created_at, updated_at, deleted_at, mentioned_user_ids, pinned_at
 				*dest[0].(*string) = "c1"
 				*dest[1].(*string) = "p1"
 				*dest[2].(*string) = "user-target"
@@ -160,3 +161,26 @@ func TestCommentRepository_GetByUserID(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestCommentRepository_GetByPostID_Sort(t *testing.T) {
+	cases := map[models.CommentSort]string{
+		models.CommentSortNewest:    "pinned_at IS NOT NULL) DESC, created_at DESC",
+		models.CommentSortOldest:    "pinned_at IS NOT NULL) DESC, created_at ASC",
+		models.CommentSortMostLiked: "pinned_at IS NOT NULL) DESC, total_likes DESC",
+		"bogus":                     "pinned_at IS NOT NULL) DESC, created_at DESC",
+	}
+	for sort, order := range cases {
+		t.Run(string(sort), func(t *testing.T) {
+			pool := new(testutil.MockPool)
+			repo := newCommentRepo(pool)
+
+			pool.On("Query", mock.Anything, mock.MatchedBy(func(sql string) bool {
+				return strings.Contains(sql, order)
+			}), mock.Anything, mock.Anything, mock.Anything).Return(testutil.NewFuncRows(), nil)
+
+			_, err := repo.GetByPostID(context.Background(), "post-1", sort, 20, 0)
+			require.NoError(t, err)
+			pool.AssertExpectations(t)
+		})
+	}
+}
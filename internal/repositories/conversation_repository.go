@@ -23,6 +23,10 @@ type ConversationRepository interface {
 	// Participant checks
 	IsParticipant(ctx context.Context, conversationID, userID string) (bool, error)
 	GetOtherParticipantID(ctx context.Context, conversationID, userID string) (string, error)
+
+	// SetDisappearingTTL sets how long new messages live, in seconds; nil
+	// turns disappearing messages off. Existing messages keep their expiry.
+	SetDisappearingTTL(ctx context.Context, conversationID string, ttlSeconds *int) error
 }
 
 type conversationRepository struct {
@@ -52,7 +56,7 @@ func (r *conversationRepository) GetOrCreate(ctx context.Context, userID1, userI
 	query := `
 		INSERT INTO conversations (participant1_id, participant2_id, business_id, created_at)
 		VALUES ($1, $2, $3, NOW())
-		RETURNING id, participant1_id, participant2_id, business_id, last_message_at, created_at
+		RETURNING id, participant1_id, participant2_id, business_id, last_message_at, created_at, disappearing_ttl_seconds, updated_at
 	`
 
 	conversation := &models.Conversation{}
@@ -63,6 +67,8 @@ func (r *conversationRepository) GetOrCreate(ctx context.Context, userID1, userI
 		&conversation.BusinessID,
 		&conversation.LastMessageAt,
 		&conversation.CreatedAt,
+		&conversation.DisappearingTTL,
+		&conversation.UpdatedAt,
 	)
 
 	if err != nil {
@@ -75,7 +81,7 @@ func (r *conversationRepository) GetOrCreate(ctx context.Context, userID1, userI
 // GetByID retrieves a conversation by ID
 func (r *conversationRepository) GetByID(ctx context.Context, conversationID string) (*models.Conversation, error) {
 	query := `
-		SELECT id, participant1_id, participant2_id, business_id, last_message_at, created_at
+		SELECT id, participant1_id, participant2_id, business_id, last_message_at, created_at, disappearing_ttl_seconds, updated_at
 		FROM conversations
 		WHERE id = $1
 	`
@@ -88,11 +94,13 @@ func (r *conversationRepository) GetByID(ctx context.Context, conversationID str
 		&conversation.BusinessID,
 		&conversation.LastMessageAt,
 		&conversation.CreatedAt,
+		&conversation.DisappearingTTL,
+		&conversation.UpdatedAt,
 	)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("conversation not found")
+			return nil, newNotFoundError("conversation not found")
 		}
 		return nil, fmt.Errorf("failed to get conversation: %w", err)
 	}
@@ -113,14 +121,14 @@ func (r *conversationRepository) GetByParticipants(ctx context.Context, userID1,
 	var args []interface{}
 	if businessID == nil {
 		query = `
-			SELECT id, participant1_id, participant2_id, business_id, last_message_at, created_at
+			SELECT id, participant1_id, participant2_id, business_id, last_message_at, created_at, disappearing_ttl_seconds, updated_at
 			FROM conversations
 			WHERE participant1_id = $1 AND participant2_id = $2 AND business_id IS NULL
 		`
 		args = []interface{}{participant1, participant2}
 	} else {
 		query = `
-			SELECT id, participant1_id, participant2_id, business_id, last_message_at, created_at
+			SELECT id, participant1_id, participant2_id, business_id, last_message_at, created_at, disappearing_ttl_seconds, updated_at
 			FROM conversations
 			WHERE participant1_id = $1 AND participant2_id = $2 AND business_id = $3
 		`
@@ -135,11 +143,13 @@ func (r *conversationRepository) GetByParticipants(ctx context.Context, userID1,
 		&conversation.BusinessID,
 		&conversation.LastMessageAt,
 		&conversation.CreatedAt,
+		&conversation.DisappearingTTL,
+		&conversation.UpdatedAt,
 	)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("conversation not found")
+			return nil, newNotFoundError("conversation not found")
 		}
 		return nil, fmt.Errorf("failed to get conversation: %w", err)
 	}
@@ -159,24 +169,28 @@ func (r *conversationRepository) List(ctx context.Context, filter *models.GetCon
 	var args []interface{}
 	if filter.BusinessID == nil {
 		query = `
-			SELECT c.id, c.participant1_id, c.participant2_id, c.business_id, c.last_message_at, c.created_at
+			SELECT c.id, c.participant1_id, c.participant2_id, c.business_id, c.last_message_at, c.created_at, c.disappearing_ttl_seconds, c.updated_at
 			FROM conversations c
 			LEFT JOIN business_profiles bp ON bp.id = c.business_id
 			WHERE (c.participant1_id = $1 OR c.participant2_id = $1)
-			  AND (c.business_id IS NULL OR bp.user_id <> $1)
-			ORDER BY COALESCE(c.last_message_at, c.created_at) DESC
-			LIMIT $2 OFFSET $3
-		`
-		args = []interface{}{filter.UserID, filter.Limit, filter.Offset}
+			  AND (c.business_id IS NULL OR bp.user_id <> $1)`
+		args = []interface{}{filter.UserID}
 	} else {
 		query = `
-			SELECT id, participant1_id, participant2_id, business_id, last_message_at, created_at
-			FROM conversations
-			WHERE (participant1_id = $1 OR participant2_id = $1) AND business_id = $2
-			ORDER BY COALESCE(last_message_at, created_at) DESC
-			LIMIT $3 OFFSET $4
-		`
-		args = []interface{}{filter.UserID, *filter.BusinessID, filter.Limit, filter.Offset}
+			SELECT c.id, c.participant1_id, c.participant2_id, c.business_id, c.last_message_at, c.created_at, c.disappearing_ttl_seconds, c.updated_at
+			FROM conversations c
+			WHERE (c.participant1_id = $1 OR c.participant2_id = $1) AND c.business_id = $2`
+		args = []interface{}{filter.UserID, *filter.BusinessID}
+	}
+
+	// Delta sync walks changes oldest first; the inbox shows the most
+	// recently active chats first.
+	if filter.UpdatedAfter != nil {
+		query += fmt.Sprintf(" AND c.updated_at > $%d ORDER BY c.updated_at ASC LIMIT $%d", len(args)+1, len(args)+2)
+		args = append(args, *filter.UpdatedAfter, filter.Limit)
+	} else {
+		query += fmt.Sprintf(" ORDER BY COALESCE(c.last_message_at, c.created_at) DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+		args = append(args, filter.Limit, filter.Offset)
 	}
 
 	rows, err := r.db.Pool.Query(ctx, query, args...)
@@ -195,6 +209,8 @@ func (r *conversationRepository) List(ctx context.Context, filter *models.GetCon
 			&conversation.BusinessID,
 			&conversation.LastMessageAt,
 			&conversation.CreatedAt,
+			&conversation.DisappearingTTL,
+			&conversation.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan conversation: %w", err)
@@ -235,7 +251,7 @@ func (r *conversationRepository) Delete(ctx context.Context, conversationID stri
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("conversation not found")
+		return newNotFoundError("conversation not found")
 	}
 
 	return nil
@@ -276,7 +292,7 @@ func (r *conversationRepository) GetOtherParticipantID(ctx context.Context, conv
 	err := r.db.Pool.QueryRow(ctx, query, conversationID, userID).Scan(&otherParticipantID)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return "", fmt.Errorf("conversation not found")
+			return "", newNotFoundError("conversation not found")
 		}
 		return "", fmt.Errorf("failed to get other participant: %w", err)
 	}
@@ -287,3 +303,16 @@ func (r *conversationRepository) GetOtherParticipantID(ctx context.Context, conv
 
 	return *otherParticipantID, nil
 }
+
+// SetDisappearingTTL updates the conversation's disappearing-messages TTL.
+func (r *conversationRepository) SetDisappearingTTL(ctx context.Context, conversationID string, ttlSeconds *int) error {
+	query := `UPDATE conversations SET disappearing_ttl_seconds = $2 WHERE id = $1`
+	tag, err := r.db.Pool.Exec(ctx, query, conversationID, ttlSeconds)
+	if err != nil {
+		return fmt.Errorf("failed to set disappearing ttl: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return newNotFoundError("conversation not found")
+	}
+	return nil
+}
@@ -28,6 +28,7 @@ func makeConversationScanFn(c *models.Conversation) func(dest ...any) error {
 		*dest[3].(**string) = c.BusinessID
 		*dest[4].(**time.Time) = c.LastMessageAt
 		*dest[5].(*time.Time) = c.CreatedAt
+		*dest[6].(**int) = c.DisappearingTTL
 		return nil
 	}
 }
@@ -142,3 +143,19 @@ func TestConversationRepository_GetOtherParticipantID_Success(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "user-b", otherID)
 }
+
+func TestConversationRepository_List_UpdatedAfter(t *testing.T) {
+	pool := new(testutil.MockPool)
+	pages := capture(pool, "Query")
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := newConversationRepo(pool).List(context.Background(), &models.GetConversationsFilter{
+		UserID: "user-a", UpdatedAfter: &since, Limit: 20, Offset: 40,
+	})
+	require.Error(t, err)
+
+	sql := (*pages)[0].sql
+	assert.Contains(t, sql, "AND c.updated_at > $2 ORDER BY c.updated_at ASC LIMIT $3")
+	assert.NotContains(t, sql, "OFFSET")
+	assert.Equal(t, []any{"user-a", since, 20}, (*pages)[0].args)
+}
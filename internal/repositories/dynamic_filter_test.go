@@ -0,0 +1,275 @@
+package repositories_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/testutil"
+)
+
+// The list queries below build their WHERE clause from optional filters.
+// These tests pin the SQL and arguments they produced before the filters
+// moved onto the shared where-clause builder.
+
+type capturedQuery struct {
+	sql  string
+	args []any
+}
+
+// capture records every call of method ("Query" or "QueryRow") on pool.
+// Query calls fail so list methods stop after the first page query;
+// QueryRow calls scan a zero count.
+func capture(pool *testutil.MockPool, method string) *[]capturedQuery {
+	var calls []capturedQuery
+	call := pool.On(method, mock.Anything, mock.Anything, mock.Anything).Run(func(a mock.Arguments) {
+		calls = append(calls, capturedQuery{sql: compactSQL(a.String(1)), args: a.Get(2).([]any)})
+	})
+	if method == "Query" {
+		call.Return(nil, errors.New("stop"))
+	} else {
+		call.Return(testutil.NewMockRow(func(dest ...any) error {
+			*dest[0].(*int64) = 0
+			return nil
+		}))
+	}
+	return &calls
+}
+
+func compactSQL(q string) string {
+	return strings.Join(strings.Fields(q), " ")
+}
+
+func TestAdminRepository_ListUsers_FilterSQL(t *testing.T) {
+	pool := new(testutil.MockPool)
+	repo := newAdminRepo(pool)
+	counts := capture(pool, "QueryRow")
+	pages := capture(pool, "Query")
+
+	_, _, err := repo.ListUsers(context.Background(), &models.AdminUserFilter{
+		Search: "ali_", Role: "admin", Status: "suspended", Province: "Kabul", Page: 2, Limit: 10,
+	})
+	require.Error(t, err)
+
+	where := `u.deleted_at IS NULL AND (u.email ILIKE $1 ESCAPE '\' OR p.first_name ILIKE $1 ESCAPE '\' OR p.last_name ILIKE $1 ESCAPE '\') AND u.role = $2 AND u.locked_until > NOW() AND p.province = $3`
+	filterArgs := []any{`%ali\_%`, "admin", "Kabul"}
+
+	require.Len(t, *counts, 1)
+	assert.Contains(t, (*counts)[0].sql, "WHERE "+where)
+	assert.Equal(t, filterArgs, (*counts)[0].args)
+
+	require.Len(t, *pages, 1)
+	assert.Contains(t, (*pages)[0].sql, "WHERE "+where+" ORDER BY")
+	assert.Contains(t, (*pages)[0].sql, "LIMIT $4 OFFSET $5")
+	assert.Equal(t, append(filterArgs, 10, 10), (*pages)[0].args)
+}
+
+func TestAdminRepository_ListPosts_FilterSQL(t *testing.T) {
+	pool := new(testutil.MockPool)
+	repo := newAdminRepo(pool)
+	counts := capture(pool, "QueryRow")
+	capture(pool, "Query")
+
+	_, _, err := repo.ListPosts(context.Background(), &models.AdminPostFilter{
+		Search: "bike", Type: "SELL", Status: "ACTIVE", UserID: "user-1", Reported: true,
+	})
+	require.Error(t, err)
+
+	require.Len(t, *counts, 1)
+	assert.Equal(t,
+		`SELECT COUNT(*) FROM posts p WHERE p.deleted_at IS NULL AND (p.title ILIKE $1 ESCAPE '\' OR p.description ILIKE $1 ESCAPE '\') AND p.type = $2 AND p.status = $3 AND p.user_id = $4 AND EXISTS (SELECT 1 FROM post_reports pr WHERE pr.post_id = p.id)`,
+		(*counts)[0].sql)
+	assert.Equal(t, []any{"%bike%", "SELL", true, "user-1"}, (*counts)[0].args)
+}
+
+func TestAdminRepository_ListBusinesses_IncludeDeletedOnly(t *testing.T) {
+	pool := new(testutil.MockPool)
+	repo := newAdminRepo(pool)
+	counts := capture(pool, "QueryRow")
+	pages := capture(pool, "Query")
+
+	_, _, err := repo.ListBusinesses(context.Background(), &models.AdminBusinessFilter{IncludeDeleted: true})
+	require.Error(t, err)
+
+	assert.Equal(t, `SELECT COUNT(*) FROM business_profiles b WHERE 1=1`, (*counts)[0].sql)
+	assert.Empty(t, (*counts)[0].args)
+	assert.Contains(t, (*pages)[0].sql, "WHERE 1=1 ORDER BY b.created_at DESC LIMIT $1 OFFSET $2")
+	assert.Equal(t, []any{20, 0}, (*pages)[0].args)
+}
+
+func TestAdminRepository_ListPostReports_TriageFilterSQL(t *testing.T) {
+	pool := new(testutil.MockPool)
+	repo := newAdminRepo(pool)
+	counts := capture(pool, "QueryRow")
+	pages := capture(pool, "Query")
+
+	_, _, err := repo.ListPostReports(context.Background(), &models.AdminReportFilter{
+		Status: "OPEN", From: "2026-01-01", To: "2026-01-31", Reason: "spam",
+	})
+	require.Error(t, err)
+
+	assert.Equal(t,
+		`SELECT COUNT(*) FROM post_reports r WHERE r.report_status = $1 AND r.created_at >= $2 AND r.created_at < ($3::date + INTERVAL '1 day') AND r.reason ILIKE $4 ESCAPE '\'`,
+		(*counts)[0].sql)
+	assert.Equal(t, []any{"OPEN", "2026-01-01", "2026-01-31", "%spam%"}, (*counts)[0].args)
+	assert.Contains(t, (*pages)[0].sql, "LIMIT $5 OFFSET $6")
+	assert.Len(t, (*pages)[0].args, 6)
+}
+
+func TestPostRepository_CountFeed_FilterSQL(t *testing.T) {
+	pool := new(testutil.MockPool)
+	repo := newPostRepo(pool)
+	counts := capture(pool, "QueryRow")
+
+	province := "Kabul"
+	search := "bike"
+	_, err := repo.CountFeed(context.Background(), &models.FeedFilter{
+		ViewerID: "viewer-1",
+		Province: &province,
+		Search:   &search,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, *counts, 1)
+	sql := (*counts)[0].sql
+	assert.True(t, strings.HasPrefix(sql, "SELECT COUNT(*) FROM posts WHERE deleted_at IS NULL AND status = true AND user_id NOT IN ("), sql)
+	assert.Contains(t, sql, "SELECT blocked_id FROM user_blocks WHERE blocker_id = $1 UNION SELECT blocker_id FROM user_blocks WHERE blocked_id = $1")
+	assert.Contains(t, sql, "AND sb.id <> $2 ) AND group_id IS NULL")
+	assert.Contains(t, sql, "(pr.province_id = resolve_location_id('PROVINCE', NULL, $3) OR pr.province = $3)")
+	assert.Contains(t, sql, `(title ILIKE $4 ESCAPE '\' OR description ILIKE $5 ESCAPE '\'`)
+	assert.Contains(t, sql, `sc.name ILIKE $6 ESCAPE '\'`)
+	assert.True(t, strings.HasSuffix(sql, "AND (type != 'SELL' OR sold = false)"), sql)
+	assert.Equal(t, []any{"viewer-1", "viewer-1", "Kabul", "%bike%", "%bike%", "%bike%"}, (*counts)[0].args)
+}
+
+func TestPostRepository_CountFeed_MutedTermsSQL(t *testing.T) {
+	pool := new(testutil.MockPool)
+	counts := capture(pool, "QueryRow")
+
+	_, err := newPostRepo(pool).CountFeed(context.Background(), &models.FeedFilter{
+		MutedTerms: []string{"politics", "#c++"},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, *counts, 1)
+	assert.Contains(t, (*counts)[0].sql, `AND (COALESCE(title, '') || ' ' || COALESCE(description, '')) !~* ALL($1)`)
+	assert.Equal(t, []any{[]string{
+		`(^|[^[:alnum:]_])politics($|[^[:alnum:]_])`,
+		`(^|[^[:alnum:]_])#c\+\+($|[^[:alnum:]_])`,
+	}}, (*counts)[0].args)
+}
+
+func TestPostRepository_GetFeed_PaginationSQL(t *testing.T) {
+	cursor := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	lat, lng, radius := 34.5, 69.2, 5.0
+
+	t.Run("cursor replaces offset", func(t *testing.T) {
+		pool := new(testutil.MockPool)
+		pages := capture(pool, "Query")
+
+		_, err := newPostRepo(pool).GetFeed(context.Background(), &models.FeedFilter{Cursor: &cursor, Limit: 20})
+		require.Error(t, err)
+
+		sql := (*pages)[0].sql
+		assert.Contains(t, sql, "AND (type != 'SELL' OR sold = false) AND bumped_at < $1 ORDER BY bumped_at DESC LIMIT $2")
+		assert.NotContains(t, sql, "OFFSET")
+		assert.Equal(t, []any{cursor, 20}, (*pages)[0].args)
+	})
+
+	t.Run("nearby binds the origin after the radius", func(t *testing.T) {
+		pool := new(testutil.MockPool)
+		pages := capture(pool, "Query")
+
+		_, err := newPostRepo(pool).GetFeed(context.Background(), &models.FeedFilter{
+			Latitude: &lat, Longitude: &lng, RadiusKm: &radius, SortBy: "nearby", Cursor: &cursor, Limit: 20, Offset: 40,
+		})
+		require.Error(t, err)
+
+		sql := (*pages)[0].sql
+		assert.Contains(t, sql, "ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography, $3 )")
+		assert.Contains(t, sql, "ORDER BY ST_Distance( address_location::geography, ST_SetSRID(ST_MakePoint($4, $5), 4326)::geography ) ASC LIMIT $6 OFFSET $7")
+		assert.NotContains(t, sql, "bumped_at <")
+		assert.Equal(t, []any{lng, lat, radius * 1000, lng, lat, 20, 40}, (*pages)[0].args)
+	})
+
+	t.Run("delta sync walks updated_at", func(t *testing.T) {
+		pool := new(testutil.MockPool)
+		pages := capture(pool, "Query")
+
+		_, err := newPostRepo(pool).GetFeed(context.Background(), &models.FeedFilter{
+			SortBy: "trending", UpdatedAfter: &cursor, Cursor: &cursor, Limit: 20, Offset: 40,
+		})
+		require.Error(t, err)
+
+		sql := (*pages)[0].sql
+		assert.Contains(t, sql, "AND updated_at > $1 ORDER BY updated_at ASC LIMIT $2")
+		assert.NotContains(t, sql, "bumped_at <")
+		assert.NotContains(t, sql, "OFFSET")
+		assert.Equal(t, []any{cursor, 20}, (*pages)[0].args)
+	})
+}
+
+func TestPostRepository_RemovedPostIDs_SQL(t *testing.T) {
+	pool := new(testutil.MockPool)
+	pages := capture(pool, "Query")
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	businessID := "biz-1"
+
+	_, err := newPostRepo(pool).RemovedPostIDs(context.Background(), &models.FeedFilter{BusinessID: &businessID}, since, 100)
+	require.Error(t, err)
+
+	sql := (*pages)[0].sql
+	assert.Contains(t, sql, "WHERE (deleted_at IS NOT NULL OR status = false OR (type = 'SELL' AND sold = true)) AND updated_at > $1 AND business_id = $2")
+	assert.Contains(t, sql, "ORDER BY updated_at ASC LIMIT $3")
+	assert.Equal(t, []any{since, businessID, 100}, (*pages)[0].args)
+}
+
+func TestBusinessRepository_List_FilterSQL(t *testing.T) {
+	pool := new(testutil.MockPool)
+	pages := capture(pool, "Query")
+
+	province := "Herat"
+	search := "bakery"
+	lat, lng, radius := 34.3, 62.2, 2.0
+	_, err := newBusinessRepo(pool).List(context.Background(), &models.BusinessListFilter{
+		CategoryIDs: []string{"cat-1", "cat-2"},
+		Province:    &province,
+		Search:      &search,
+		Latitude:    &lat,
+		Longitude:   &lng,
+		RadiusKm:    &radius,
+		Limit:       20,
+		Offset:      0,
+	})
+	require.Error(t, err)
+
+	sql := (*pages)[0].sql
+	assert.Contains(t, sql, "FROM business_profiles bp WHERE bp.deleted_at IS NULL AND bp.status = true AND EXISTS ( SELECT 1 FROM business_profile_categories bpc WHERE bpc.business_profile_id = bp.id AND bpc.business_category_id = ANY($1) ) AND (bp.province_id = resolve_location_id('PROVINCE', NULL, $2) OR bp.province = $2) AND (bp.name ILIKE $3 ESCAPE '\\' OR bp.description ILIKE $3 ESCAPE '\\')")
+	assert.Contains(t, sql, "ST_SetSRID(ST_MakePoint($4, $5), 4326)::geography, $6 ) ORDER BY bp.created_at DESC LIMIT $7 OFFSET $8")
+	assert.Equal(t, []any{[]string{"cat-1", "cat-2"}, "Herat", "%bakery%", lng, lat, radius * 1000, 20, 0}, (*pages)[0].args)
+}
+
+func TestBusinessRepository_CountByCategory_IgnoresCategoryFilter(t *testing.T) {
+	pool := new(testutil.MockPool)
+	calls := capture(pool, "Query")
+
+	province := "Herat"
+	_, err := newBusinessRepo(pool).CountByCategory(context.Background(), &models.BusinessListFilter{
+		CategoryIDs: []string{"cat-1"},
+		Province:    &province,
+		Limit:       20,
+	})
+	require.Error(t, err)
+
+	sql := (*calls)[0].sql
+	assert.Contains(t, sql, "JOIN business_categories bc ON bc.id = bpc.business_category_id AND bc.is_active = true WHERE bp.deleted_at IS NULL AND bp.status = true AND (bp.province_id = resolve_location_id('PROVINCE', NULL, $1) OR bp.province = $1) GROUP BY bc.id, bc.name")
+	assert.NotContains(t, sql, "ANY(")
+	assert.Equal(t, []any{"Herat"}, (*calls)[0].args)
+}
@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// EmailDeliveryRepository persists the outbound email log (sent_emails) and
+// the undeliverable-address list (email_suppressions). Addresses are always
+// compared lower-cased so "Foo@x.com" and "foo@x.com" share one suppression.
+type EmailDeliveryRepository interface {
+	// LogSent records one send attempt and returns the new row id.
+	LogSent(ctx context.Context, email *models.SentEmail) (string, error)
+	// UpdateStatusByProviderID advances a row identified by the provider's
+	// message id. Returns the recipient address (empty when unknown).
+	UpdateStatusByProviderID(ctx context.Context, provider, providerMessageID string, status models.EmailDeliveryStatus, detail *string) (string, error)
+	ListByEmail(ctx context.Context, email string, limit int) ([]models.SentEmail, error)
+
+	GetSuppression(ctx context.Context, email string) (*models.EmailSuppression, error)
+	Suppress(ctx context.Context, email, reason string, detail *string) error
+	Unsuppress(ctx context.Context, email string) error
+}
+
+type emailDeliveryRepository struct {
+	db *database.DB
+}
+
+// NewEmailDeliveryRepository creates a new email delivery repository
+func NewEmailDeliveryRepository(db *database.DB) EmailDeliveryRepository {
+	return &emailDeliveryRepository{db: db}
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func (r *emailDeliveryRepository) LogSent(ctx context.Context, email *models.SentEmail) (string, error) {
+	var id string
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO sent_emails (to_email, subject, provider, provider_message_id, status, error)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id::text
+	`, normalizeEmail(email.ToEmail), email.Subject, email.Provider, email.ProviderMessageID, email.Status, email.Error).Scan(&id)
+	if err != nil {
+		return "", fmt.Errorf("sent_emails insert: %w", err)
+	}
+	return id, nil
+}
+
+// UpdateStatusByProviderID never moves a row backwards: a late "delivered"
+// webhook must not overwrite a bounce/complaint that already landed.
+func (r *emailDeliveryRepository) UpdateStatusByProviderID(ctx context.Context, provider, providerMessageID string, status models.EmailDeliveryStatus, detail *string) (string, error) {
+	var to string
+	err := r.db.Pool.QueryRow(ctx, `
+		UPDATE sent_emails
+		SET status = CASE WHEN status IN ('bounced', 'complained') THEN status ELSE $3 END,
+		    error = COALESCE($4, error),
+		    updated_at = NOW()
+		WHERE provider = $1 AND provider_message_id = $2
+		RETURNING to_email
+	`, provider, providerMessageID, status, detail).Scan(&to)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("sent_emails update: %w", err)
+	}
+	return to, nil
+}
+
+func (r *emailDeliveryRepository) ListByEmail(ctx context.Context, email string, limit int) ([]models.SentEmail, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT id::text, to_email, subject, provider, provider_message_id, status, error, created_at, updated_at
+		FROM sent_emails
+		WHERE lower(to_email) = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`, normalizeEmail(email), limit)
+	if err != nil {
+		return nil, fmt.Errorf("sent_emails list: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]models.SentEmail, 0, limit)
+	for rows.Next() {
+		var e models.SentEmail
+		if err := rows.Scan(&e.ID, &e.ToEmail, &e.Subject, &e.Provider, &e.ProviderMessageID,
+			&e.Status, &e.Error, &e.CreatedAt, &e.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("sent_emails scan: %w", err)
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// GetSuppression returns (nil, nil) when the address is deliverable.
+func (r *emailDeliveryRepository) GetSuppression(ctx context.Context, email string) (*models.EmailSuppression, error) {
+	var s models.EmailSuppression
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT email, reason, detail, created_at FROM email_suppressions WHERE email = $1
+	`, normalizeEmail(email)).Scan(&s.Email, &s.Reason, &s.Detail, &s.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("email_suppressions get: %w", err)
+	}
+	return &s, nil
+}
+
+// Suppress is idempotent; a complaint upgrades an existing bounce row so the
+// stronger signal is what support sees.
+func (r *emailDeliveryRepository) Suppress(ctx context.Context, email, reason string, detail *string) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		INSERT INTO email_suppressions (email, reason, detail)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (email) DO UPDATE SET
+			reason = CASE WHEN EXCLUDED.reason = 'complaint' THEN EXCLUDED.reason ELSE email_suppressions.reason END,
+			detail = COALESCE(EXCLUDED.detail, email_suppressions.detail)
+	`, normalizeEmail(email), reason, detail)
+	if err != nil {
+		return fmt.Errorf("email_suppressions upsert: %w", err)
+	}
+	return nil
+}
+
+func (r *emailDeliveryRepository) Unsuppress(ctx context.Context, email string) error {
+	_, err := r.db.Pool.Exec(ctx, `DELETE FROM email_suppressions WHERE email = $1`, normalizeEmail(email))
+	if err != nil {
+		return fmt.Errorf("email_suppressions delete: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,234 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// EndorsementRepository handles persistence for profile endorsements and
+// their reports.
+type EndorsementRepository interface {
+	// Upsert creates or replaces the author's endorsement of a user. One
+	// endorsement per (subject_id, author_id); replacing the text sends it
+	// back to PENDING so the owner approves what is actually shown.
+	Upsert(ctx context.Context, e *models.Endorsement) error
+
+	// GetByID returns a single endorsement.
+	GetByID(ctx context.Context, id string) (*models.Endorsement, error)
+
+	// ListForSubject returns a user's endorsements with author info, newest
+	// first. A nil status returns every status.
+	ListForSubject(ctx context.Context, subjectID string, status *models.EndorsementStatus, limit, offset int) ([]*models.EndorsementWithAuthor, int, error)
+
+	// CountApproved returns how many endorsements a user shows.
+	CountApproved(ctx context.Context, subjectID string) (int, error)
+
+	// SetStatus records the subject's decision.
+	SetStatus(ctx context.Context, id string, status models.EndorsementStatus) error
+
+	// Delete removes an endorsement and its reports.
+	Delete(ctx context.Context, id string) error
+
+	// CreateReport stores a report. Returns ErrAlreadyReported when the
+	// user already reported the endorsement.
+	CreateReport(ctx context.Context, report *models.EndorsementReport) error
+
+	// ListReported returns reported endorsements, most reported first.
+	ListReported(ctx context.Context, limit, offset int) ([]*models.ReportedEndorsement, int, error)
+}
+
+type endorsementRepository struct {
+	db *database.DB
+}
+
+// NewEndorsementRepository wires a new endorsement repository.
+func NewEndorsementRepository(db *database.DB) EndorsementRepository {
+	return &endorsementRepository{db: db}
+}
+
+// ErrEndorsementNotFound is returned when an endorsement id doesn't exist.
+var ErrEndorsementNotFound = newNotFoundError("endorsement not found")
+
+func (r *endorsementRepository) Upsert(ctx context.Context, e *models.Endorsement) error {
+	const q = `
+		INSERT INTO endorsements (id, subject_id, author_id, text, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, 'PENDING', NOW(), NOW())
+		ON CONFLICT (subject_id, author_id)
+		DO UPDATE SET text = EXCLUDED.text,
+		              status = 'PENDING',
+		              updated_at = NOW()
+		RETURNING id, status, created_at, updated_at
+	`
+	return r.db.Pool.QueryRow(ctx, q, e.ID, e.SubjectID, e.AuthorID, e.Text).
+		Scan(&e.ID, &e.Status, &e.CreatedAt, &e.UpdatedAt)
+}
+
+func (r *endorsementRepository) GetByID(ctx context.Context, id string) (*models.Endorsement, error) {
+	const q = `
+		SELECT id, subject_id, author_id, text, status, created_at, updated_at
+		FROM endorsements
+		WHERE id = $1
+	`
+	out := &models.Endorsement{}
+	err := r.db.Pool.QueryRow(ctx, q, id).Scan(
+		&out.ID, &out.SubjectID, &out.AuthorID, &out.Text,
+		&out.Status, &out.CreatedAt, &out.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrEndorsementNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get endorsement: %w", err)
+	}
+	return out, nil
+}
+
+func (r *endorsementRepository) ListForSubject(ctx context.Context, subjectID string, status *models.EndorsementStatus, limit, offset int) ([]*models.EndorsementWithAuthor, int, error) {
+	const q = `
+		SELECT
+			e.id, e.subject_id, e.author_id, e.text, e.status, e.created_at, e.updated_at,
+			p.first_name, p.last_name, p.avatar, p.avatar_color
+		FROM endorsements e
+		LEFT JOIN profiles p ON p.id = e.author_id
+		WHERE e.subject_id = $1 AND ($2::text IS NULL OR e.status = $2)
+		ORDER BY e.created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+	rows, err := r.db.Pool.Query(ctx, q, subjectID, status, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list endorsements: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]*models.EndorsementWithAuthor, 0)
+	for rows.Next() {
+		w := &models.EndorsementWithAuthor{}
+		if err := rows.Scan(
+			&w.ID, &w.SubjectID, &w.AuthorID, &w.Text, &w.Status, &w.CreatedAt, &w.UpdatedAt,
+			&w.AuthorFirstName, &w.AuthorLastName, &w.AuthorAvatar, &w.AuthorAvatarHex,
+		); err != nil {
+			return nil, 0, fmt.Errorf("scan endorsement: %w", err)
+		}
+		out = append(out, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("list endorsements: %w", err)
+	}
+
+	var total int
+	if err := r.db.Pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM endorsements WHERE subject_id = $1 AND ($2::text IS NULL OR status = $2)`,
+		subjectID, status,
+	).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count endorsements: %w", err)
+	}
+	return out, total, nil
+}
+
+func (r *endorsementRepository) CountApproved(ctx context.Context, subjectID string) (int, error) {
+	var n int
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM endorsements WHERE subject_id = $1 AND status = 'APPROVED'`,
+		subjectID,
+	).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("count approved endorsements: %w", err)
+	}
+	return n, nil
+}
+
+func (r *endorsementRepository) SetStatus(ctx context.Context, id string, status models.EndorsementStatus) error {
+	tag, err := r.db.Pool.Exec(ctx,
+		`UPDATE endorsements SET status = $1, updated_at = NOW() WHERE id = $2`,
+		status, id,
+	)
+	if err != nil {
+		return fmt.Errorf("set endorsement status: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrEndorsementNotFound
+	}
+	return nil
+}
+
+func (r *endorsementRepository) Delete(ctx context.Context, id string) error {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM endorsements WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete endorsement: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrEndorsementNotFound
+	}
+	return nil
+}
+
+func (r *endorsementRepository) CreateReport(ctx context.Context, report *models.EndorsementReport) error {
+	const q = `
+		INSERT INTO endorsement_reports (id, user_id, endorsement_id, reason, additional_comments, created_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		RETURNING created_at
+	`
+	err := r.db.Pool.QueryRow(ctx, q,
+		report.ID,
+		report.UserID,
+		report.EndorsementID,
+		report.Reason,
+		report.AdditionalComments,
+	).Scan(&report.CreatedAt)
+	if isUniqueViolation(err) {
+		return ErrAlreadyReported
+	}
+	return err
+}
+
+func (r *endorsementRepository) ListReported(ctx context.Context, limit, offset int) ([]*models.ReportedEndorsement, int, error) {
+	const q = `
+		SELECT
+			e.id, e.subject_id, e.author_id, e.text, e.status, e.created_at, e.updated_at,
+			p.first_name, p.last_name, p.avatar, p.avatar_color,
+			rc.report_count, rc.last_reported_at
+		FROM (
+			SELECT endorsement_id, COUNT(*) AS report_count, MAX(created_at) AS last_reported_at
+			FROM endorsement_reports
+			GROUP BY endorsement_id
+		) rc
+		JOIN endorsements e ON e.id = rc.endorsement_id
+		LEFT JOIN profiles p ON p.id = e.author_id
+		ORDER BY rc.report_count DESC, rc.last_reported_at DESC
+		LIMIT $1 OFFSET $2
+	`
+	rows, err := r.db.Pool.Query(ctx, q, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list reported endorsements: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]*models.ReportedEndorsement, 0)
+	for rows.Next() {
+		w := &models.ReportedEndorsement{}
+		if err := rows.Scan(
+			&w.ID, &w.SubjectID, &w.AuthorID, &w.Text, &w.Status, &w.CreatedAt, &w.UpdatedAt,
+			&w.AuthorFirstName, &w.AuthorLastName, &w.AuthorAvatar, &w.AuthorAvatarHex,
+			&w.ReportCount, &w.LastReportedAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("scan reported endorsement: %w", err)
+		}
+		out = append(out, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("list reported endorsements: %w", err)
+	}
+
+	var total int
+	if err := r.db.Pool.QueryRow(ctx,
+		`SELECT COUNT(DISTINCT endorsement_id) FROM endorsement_reports`,
+	).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count reported endorsements: %w", err)
+	}
+	return out, total, nil
+}
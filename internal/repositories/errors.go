@@ -0,0 +1,38 @@
+package repositories
+
+import "errors"
+
+// Error kinds shared by all repositories. Every repository sentinel wraps
+// one of them, so callers can still match the exact sentinel with errors.Is
+// while the HTTP layer maps the kind to a status code without looking at
+// the message.
+var (
+	// ErrNotFound is the kind of every "X not found" error.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is the kind of errors caused by the current state of a
+	// row: duplicates, already-resolved requests, stale versions.
+	ErrConflict = errors.New("conflict")
+	// ErrForbidden is the kind of errors for rows the caller may not touch.
+	ErrForbidden = errors.New("forbidden")
+)
+
+// SentinelError is a repository error of one of the kinds above. Its message
+// names the row, never SQL or internal state, so it is safe to show to
+// clients.
+type SentinelError struct {
+	Message string
+	Kind    error
+}
+
+func (e *SentinelError) Error() string { return e.Message }
+
+// Unwrap exposes the kind to errors.Is.
+func (e *SentinelError) Unwrap() error { return e.Kind }
+
+func newNotFoundError(message string) error {
+	return &SentinelError{Message: message, Kind: ErrNotFound}
+}
+
+func newConflictError(message string) error {
+	return &SentinelError{Message: message, Kind: ErrConflict}
+}
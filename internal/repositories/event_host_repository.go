@@ -0,0 +1,231 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// EventHostRepository handles co-host invitations of EVENT posts and the
+// attendee lookups hosts need to message them.
+type EventHostRepository interface {
+	// Invite inserts a PENDING co-host. Returns ErrEventHostExists when the
+	// user or business was already invited to the event.
+	Invite(ctx context.Context, host *models.EventHost) error
+	GetByID(ctx context.Context, hostID string) (*models.EventHost, error)
+	// Respond moves a PENDING invitation to status. Returns
+	// ErrEventHostConflict when it was already answered.
+	Respond(ctx context.Context, hostID string, status models.EventHostStatus) (*models.EventHost, error)
+	Delete(ctx context.Context, hostID string) error
+	// ListByPostIDs returns the co-hosts of each post, oldest invitation
+	// first. Only accepted ones unless includePending.
+	ListByPostIDs(ctx context.Context, postIDs []string, includePending bool) (map[string][]*models.EventHost, error)
+	// ListPendingForUser returns invitations waiting on userID, addressed to
+	// them or to a business they own.
+	ListPendingForUser(ctx context.Context, userID string) ([]*models.EventHostInvitation, error)
+	// IsCoHost reports whether userID co-hosts the event, themselves or
+	// through a business they own.
+	IsCoHost(ctx context.Context, postID, userID string) (bool, error)
+	// ListAttendeeIDs returns the users whose interest in the event is one
+	// of states.
+	ListAttendeeIDs(ctx context.Context, postID string, states []models.EventInterestState) ([]string, error)
+}
+
+type eventHostRepository struct {
+	db *database.DB
+}
+
+// NewEventHostRepository wires a new event host repository.
+func NewEventHostRepository(db *database.DB) EventHostRepository {
+	return &eventHostRepository{db: db}
+}
+
+var (
+	// ErrEventHostNotFound is returned when a co-host id doesn't exist.
+	ErrEventHostNotFound = newNotFoundError("event host not found")
+	// ErrEventHostExists is returned when the invitee already co-hosts or
+	// was already invited to the event.
+	ErrEventHostExists = newConflictError("event host already invited")
+	// ErrEventHostConflict is returned when an invitation was already
+	// answered.
+	ErrEventHostConflict = newConflictError("event host invitation already answered")
+)
+
+// eventHostSelect reads co-hosts with the display name and avatar of the
+// user or business behind them.
+const eventHostSelect = `
+	SELECT h.id, h.post_id, h.user_id, h.business_id, h.status, h.invited_by, h.created_at, h.responded_at,
+		COALESCE(bp.name, NULLIF(trim(COALESCE(pr.first_name, '') || ' ' || COALESCE(pr.last_name, '')), ''), '') AS name,
+		COALESCE(bp.avatar, pr.avatar) AS avatar
+	FROM event_hosts h
+	LEFT JOIN profiles pr ON pr.id = h.user_id
+	LEFT JOIN business_profiles bp ON bp.id = h.business_id AND bp.deleted_at IS NULL
+`
+
+func eventHostScanDest(h *models.EventHost) []interface{} {
+	return []interface{}{
+		&h.ID, &h.PostID, &h.UserID, &h.BusinessID, &h.Status, &h.InvitedBy, &h.CreatedAt, &h.RespondedAt,
+		&h.Name, &h.Avatar,
+	}
+}
+
+func (r *eventHostRepository) Invite(ctx context.Context, host *models.EventHost) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO event_hosts (id, post_id, user_id, business_id, status, invited_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		RETURNING created_at
+	`, host.ID, host.PostID, host.UserID, host.BusinessID, host.Status, host.InvitedBy,
+	).Scan(&host.CreatedAt)
+	if err != nil && isUniqueViolation(err) {
+		return ErrEventHostExists
+	}
+	if err != nil {
+		return fmt.Errorf("invite event host: %w", err)
+	}
+	return nil
+}
+
+func (r *eventHostRepository) GetByID(ctx context.Context, hostID string) (*models.EventHost, error) {
+	host := &models.EventHost{Role: models.EventHostRoleCoHost}
+	err := r.db.Pool.QueryRow(ctx, eventHostSelect+` WHERE h.id = $1`, hostID).Scan(eventHostScanDest(host)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrEventHostNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get event host: %w", err)
+	}
+	return host, nil
+}
+
+func (r *eventHostRepository) Respond(ctx context.Context, hostID string, status models.EventHostStatus) (*models.EventHost, error) {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE event_hosts SET status = $2, responded_at = NOW()
+		WHERE id = $1 AND status = 'PENDING'
+	`, hostID, status)
+	if err != nil {
+		return nil, fmt.Errorf("respond to event host invitation: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		if _, err := r.GetByID(ctx, hostID); err != nil {
+			return nil, err
+		}
+		return nil, ErrEventHostConflict
+	}
+	return r.GetByID(ctx, hostID)
+}
+
+func (r *eventHostRepository) Delete(ctx context.Context, hostID string) error {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM event_hosts WHERE id = $1`, hostID)
+	if err != nil {
+		return fmt.Errorf("delete event host: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrEventHostNotFound
+	}
+	return nil
+}
+
+func (r *eventHostRepository) ListByPostIDs(ctx context.Context, postIDs []string, includePending bool) (map[string][]*models.EventHost, error) {
+	out := make(map[string][]*models.EventHost)
+	if len(postIDs) == 0 {
+		return out, nil
+	}
+
+	query := eventHostSelect + ` WHERE h.post_id = ANY($1)`
+	if includePending {
+		query += ` AND h.status <> 'DECLINED'`
+	} else {
+		query += ` AND h.status = 'ACCEPTED'`
+	}
+	rows, err := r.db.Pool.Query(ctx, query+` ORDER BY h.created_at, h.id`, postIDs)
+	if err != nil {
+		return nil, fmt.Errorf("list event hosts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		host := &models.EventHost{Role: models.EventHostRoleCoHost}
+		if err := rows.Scan(eventHostScanDest(host)...); err != nil {
+			return nil, fmt.Errorf("scan event host: %w", err)
+		}
+		out[host.PostID] = append(out[host.PostID], host)
+	}
+	return out, rows.Err()
+}
+
+func (r *eventHostRepository) ListPendingForUser(ctx context.Context, userID string) ([]*models.EventHostInvitation, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT h.id, h.post_id, h.user_id, h.business_id, h.status, h.invited_by, h.created_at, h.responded_at,
+			COALESCE(bp.name, NULLIF(trim(COALESCE(pr.first_name, '') || ' ' || COALESCE(pr.last_name, '')), ''), '') AS name,
+			COALESCE(bp.avatar, pr.avatar) AS avatar,
+			p.title, p.start_date
+		FROM event_hosts h
+		JOIN posts p ON p.id = h.post_id AND p.deleted_at IS NULL
+		LEFT JOIN profiles pr ON pr.id = h.user_id
+		LEFT JOIN business_profiles bp ON bp.id = h.business_id AND bp.deleted_at IS NULL
+		WHERE h.status = 'PENDING' AND (h.user_id = $1 OR bp.user_id = $1)
+		ORDER BY h.created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list event host invitations: %w", err)
+	}
+	defer rows.Close()
+
+	invitations := []*models.EventHostInvitation{}
+	for rows.Next() {
+		inv := &models.EventHostInvitation{Host: &models.EventHost{Role: models.EventHostRoleCoHost}}
+		dest := append(eventHostScanDest(inv.Host), &inv.PostTitle, &inv.StartDate)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("scan event host invitation: %w", err)
+		}
+		invitations = append(invitations, inv)
+	}
+	return invitations, rows.Err()
+}
+
+func (r *eventHostRepository) IsCoHost(ctx context.Context, postID, userID string) (bool, error) {
+	var ok bool
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM event_hosts h
+			LEFT JOIN business_profiles bp ON bp.id = h.business_id AND bp.deleted_at IS NULL
+			WHERE h.post_id = $1 AND h.status = 'ACCEPTED'
+			  AND (h.user_id = $2 OR bp.user_id = $2)
+		)
+	`, postID, userID).Scan(&ok)
+	if err != nil {
+		return false, fmt.Errorf("check event co-host: %w", err)
+	}
+	return ok, nil
+}
+
+func (r *eventHostRepository) ListAttendeeIDs(ctx context.Context, postID string, states []models.EventInterestState) ([]string, error) {
+	stateStrs := make([]string, len(states))
+	for i, s := range states {
+		stateStrs[i] = string(s)
+	}
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT ei.user_id FROM event_interests ei
+		JOIN users u ON u.id = ei.user_id AND u.deleted_at IS NULL
+		WHERE ei.post_id = $1 AND ei.event_state = ANY($2)
+		ORDER BY ei.created_at
+	`, postID, stateStrs)
+	if err != nil {
+		return nil, fmt.Errorf("list event attendees: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan event attendee: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
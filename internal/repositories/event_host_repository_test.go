@@ -0,0 +1,76 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/testutil"
+)
+
+func newEventHostRepo(pool *testutil.MockPool) repositories.EventHostRepository {
+	return repositories.NewEventHostRepository(testutil.NewTestDB(pool))
+}
+
+func TestEventHostRepository_ListByPostIDs_SQL(t *testing.T) {
+	t.Run("accepted only", func(t *testing.T) {
+		pool := new(testutil.MockPool)
+		pages := capture(pool, "Query")
+
+		_, err := newEventHostRepo(pool).ListByPostIDs(context.Background(), []string{"post-1"}, false)
+		require.Error(t, err)
+		assert.Contains(t, (*pages)[0].sql, "WHERE h.post_id = ANY($1) AND h.status = 'ACCEPTED'")
+	})
+
+	t.Run("pending included", func(t *testing.T) {
+		pool := new(testutil.MockPool)
+		pages := capture(pool, "Query")
+
+		_, err := newEventHostRepo(pool).ListByPostIDs(context.Background(), []string{"post-1"}, true)
+		require.Error(t, err)
+		assert.Contains(t, (*pages)[0].sql, "AND h.status <> 'DECLINED'")
+	})
+
+	t.Run("no posts, no query", func(t *testing.T) {
+		pool := new(testutil.MockPool)
+		got, err := newEventHostRepo(pool).ListByPostIDs(context.Background(), nil, false)
+		require.NoError(t, err)
+		assert.Empty(t, got)
+		pool.AssertNotCalled(t, "Query", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestEventHostRepository_Invite_Duplicate(t *testing.T) {
+	pool := new(testutil.MockPool)
+	pool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).
+		Return(testutil.ErrRow(&pgconn.PgError{Code: "23505"}))
+
+	err := newEventHostRepo(pool).Invite(context.Background(), &models.EventHost{ID: "host-1", PostID: "post-1"})
+	assert.ErrorIs(t, err, repositories.ErrEventHostExists)
+}
+
+func TestEventHostRepository_Respond_AlreadyAnswered(t *testing.T) {
+	pool := new(testutil.MockPool)
+	pool.On("Exec", mock.Anything, mock.Anything, mock.Anything).Return(pgconn.NewCommandTag("UPDATE 0"), nil)
+	pool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(testutil.NewMockRow(func(dest ...any) error {
+		return nil
+	}))
+
+	_, err := newEventHostRepo(pool).Respond(context.Background(), "host-1", models.EventHostAccepted)
+	assert.ErrorIs(t, err, repositories.ErrEventHostConflict)
+}
+
+func TestEventHostRepository_GetByID_NotFound(t *testing.T) {
+	pool := new(testutil.MockPool)
+	pool.On("QueryRow", mock.Anything, mock.Anything, mock.Anything).Return(testutil.ErrRow(pgx.ErrNoRows))
+
+	_, err := newEventHostRepo(pool).GetByID(context.Background(), "host-1")
+	assert.ErrorIs(t, err, repositories.ErrEventHostNotFound)
+}
@@ -30,6 +30,10 @@ type FanoutRepository interface {
 	// GetCelebrityPostIDs returns post IDs from followed celebrity accounts
 	// (followers > CelebrityThreshold) queried directly from posts.
 	GetCelebrityPostIDs(ctx context.Context, viewerID string, cursor *time.Time, limit int) ([]string, error)
+	// FilterTimelinePostIDs returns the postIDs that may still show on
+	// viewerID's timeline (not in a group, author not shadowbanned), for
+	// timelines materialized outside user_feeds.
+	FilterTimelinePostIDs(ctx context.Context, viewerID string, postIDs []string) ([]string, error)
 }
 
 type fanoutRepository struct{ db *database.DB }
@@ -91,16 +95,22 @@ func (r *fanoutRepository) InsertFeedEntries(ctx context.Context, postID string,
 func (r *fanoutRepository) GetPersonalizedFeed(ctx context.Context, viewerID string, cursor *time.Time, limit int) ([]string, *time.Time, error) {
 	var rows pgx.Rows
 	var err error
+	// Entries fanned out before the author was shadowbanned stay in
+	// user_feeds; filter them at read time so the ban takes effect at once.
+	// Group posts are never fanned out, the group_id check is a backstop.
+	hideShadowbanned := ` AND EXISTS (
+			SELECT 1 FROM posts fp WHERE fp.id = user_feeds.post_id AND fp.group_id IS NULL` +
+		excludeShadowbanned("fp.user_id", 1) + `)`
 	if cursor != nil {
 		rows, err = r.db.Pool.Query(ctx,
 			`SELECT post_id, created_at FROM user_feeds
-			 WHERE user_id = $1 AND created_at < $2
+			 WHERE user_id = $1 AND created_at < $2`+hideShadowbanned+`
 			 ORDER BY created_at DESC LIMIT $3`,
 			viewerID, cursor, limit)
 	} else {
 		rows, err = r.db.Pool.Query(ctx,
 			`SELECT post_id, created_at FROM user_feeds
-			 WHERE user_id = $1
+			 WHERE user_id = $1`+hideShadowbanned+`
 			 ORDER BY created_at DESC LIMIT $2`,
 			viewerID, limit)
 	}
@@ -137,9 +147,9 @@ func (r *fanoutRepository) GetCelebrityPostIDs(ctx context.Context, viewerID str
 			FROM user_follows GROUP BY following_id
 		) fc ON fc.following_id = p.user_id
 		WHERE fc.fc > $2
-		  AND p.deleted_at IS NULL AND p.status = true
-		  %s
-		ORDER BY p.created_at DESC LIMIT $3`, cursorClause)
+		  AND p.deleted_at IS NULL AND p.status = true AND p.group_id IS NULL
+		  %s%s
+		ORDER BY p.created_at DESC LIMIT $3`, excludeShadowbanned("p.user_id", 1), cursorClause)
 	rows, err := r.db.Pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
@@ -155,3 +165,36 @@ func (r *fanoutRepository) GetCelebrityPostIDs(ctx context.Context, viewerID str
 	}
 	return ids, rows.Err()
 }
+
+func (r *fanoutRepository) FilterTimelinePostIDs(ctx context.Context, viewerID string, postIDs []string) ([]string, error) {
+	if len(postIDs) == 0 {
+		return nil, nil
+	}
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT p.id FROM posts p
+		WHERE p.id = ANY($2) AND p.group_id IS NULL`+excludeShadowbanned("p.user_id", 1),
+		viewerID, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	keep := make(map[string]bool, len(postIDs))
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		keep[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// Keep the timeline's order.
+	ids := make([]string, 0, len(keep))
+	for _, id := range postIDs {
+		if keep[id] {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
@@ -125,3 +125,17 @@ func TestFanoutRepository_GetPersonalizedFeed_Empty(t *testing.T) {
 	assert.Empty(t, ids)
 	assert.Nil(t, cursor)
 }
+
+func TestFanoutRepository_FilterTimelinePostIDs_SQL(t *testing.T) {
+	pool := new(testutil.MockPool)
+	pages := capture(pool, "Query")
+	ids := []string{"p-1", "p-2"}
+
+	_, err := newFanoutRepo(pool).FilterTimelinePostIDs(context.Background(), "viewer-1", ids)
+	require.Error(t, err)
+
+	sql := (*pages)[0].sql
+	assert.Contains(t, sql, "WHERE p.id = ANY($2) AND p.group_id IS NULL")
+	assert.Contains(t, sql, "sb.shadowbanned_at IS NOT NULL AND sb.id <> $1")
+	assert.Equal(t, []any{"viewer-1", ids}, (*pages)[0].args)
+}
@@ -0,0 +1,204 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// FeaturedBusinessRepository stores featured business placements and their
+// impression and click counters.
+type FeaturedBusinessRepository interface {
+	// Create inserts a placement; timestamps are filled on the struct.
+	Create(ctx context.Context, placement *models.FeaturedBusiness) error
+
+	// GetByID returns a placement.
+	GetByID(ctx context.Context, id string) (*models.FeaturedBusiness, error)
+
+	// Update persists province, position and the date window.
+	Update(ctx context.Context, placement *models.FeaturedBusiness) error
+
+	// Delete removes a placement.
+	Delete(ctx context.Context, id string) error
+
+	// List returns placements matching filter, newest window first, and
+	// how many match in total.
+	List(ctx context.Context, filter models.FeaturedBusinessFilter, limit, offset int) ([]*models.FeaturedBusiness, int, error)
+
+	// ListLive returns the province's placements live now, by position,
+	// skipping hidden businesses.
+	ListLive(ctx context.Context, province string, limit int) ([]*models.FeaturedBusiness, error)
+
+	// IncrementImpression counts one impression of a placement.
+	IncrementImpression(ctx context.Context, id string) error
+
+	// IncrementClick counts one click on a placement.
+	IncrementClick(ctx context.Context, id string) error
+}
+
+type featuredBusinessRepository struct {
+	db *database.DB
+}
+
+// NewFeaturedBusinessRepository wires a new featured business repository.
+func NewFeaturedBusinessRepository(db *database.DB) FeaturedBusinessRepository {
+	return &featuredBusinessRepository{db: db}
+}
+
+// ErrFeaturedBusinessNotFound is returned when a placement id doesn't exist.
+var ErrFeaturedBusinessNotFound = newNotFoundError("featured placement not found")
+
+const featuredBusinessColumns = `f.id, f.business_id, b.name, f.province, f.position, f.starts_at, f.ends_at,
+	f.impressions, f.clicks, f.created_by, f.created_at, f.updated_at`
+
+const featuredBusinessFrom = ` FROM featured_businesses f JOIN business_profiles b ON b.id = f.business_id`
+
+func scanFeaturedBusiness(row pgx.Row) (*models.FeaturedBusiness, error) {
+	f := &models.FeaturedBusiness{}
+	if err := row.Scan(&f.ID, &f.BusinessID, &f.BusinessName, &f.Province, &f.Position, &f.StartsAt, &f.EndsAt,
+		&f.Impressions, &f.Clicks, &f.CreatedBy, &f.CreatedAt, &f.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (r *featuredBusinessRepository) Create(ctx context.Context, placement *models.FeaturedBusiness) error {
+	const q = `
+		INSERT INTO featured_businesses (id, business_id, province, position, starts_at, ends_at, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+		RETURNING created_at, updated_at
+	`
+	if err := r.db.Pool.QueryRow(ctx, q,
+		placement.ID, placement.BusinessID, placement.Province, placement.Position,
+		placement.StartsAt, placement.EndsAt, placement.CreatedBy,
+	).Scan(&placement.CreatedAt, &placement.UpdatedAt); err != nil {
+		return fmt.Errorf("create featured placement: %w", err)
+	}
+	return nil
+}
+
+func (r *featuredBusinessRepository) GetByID(ctx context.Context, id string) (*models.FeaturedBusiness, error) {
+	q := `SELECT ` + featuredBusinessColumns + featuredBusinessFrom + ` WHERE f.id = $1`
+	placement, err := scanFeaturedBusiness(r.db.Pool.QueryRow(ctx, q, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrFeaturedBusinessNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get featured placement: %w", err)
+	}
+	return placement, nil
+}
+
+func (r *featuredBusinessRepository) Update(ctx context.Context, placement *models.FeaturedBusiness) error {
+	const q = `
+		UPDATE featured_businesses
+		SET province = $1, position = $2, starts_at = $3, ends_at = $4, updated_at = NOW()
+		WHERE id = $5
+		RETURNING updated_at
+	`
+	err := r.db.Pool.QueryRow(ctx, q,
+		placement.Province, placement.Position, placement.StartsAt, placement.EndsAt, placement.ID,
+	).Scan(&placement.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrFeaturedBusinessNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("update featured placement: %w", err)
+	}
+	return nil
+}
+
+func (r *featuredBusinessRepository) Delete(ctx context.Context, id string) error {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM featured_businesses WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete featured placement: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrFeaturedBusinessNotFound
+	}
+	return nil
+}
+
+func (r *featuredBusinessRepository) List(ctx context.Context, filter models.FeaturedBusinessFilter, limit, offset int) ([]*models.FeaturedBusiness, int, error) {
+	var conds []string
+	var args []any
+	if filter.Province != "" {
+		args = append(args, filter.Province)
+		conds = append(conds, fmt.Sprintf("f.province = $%d", len(args)))
+	}
+	if filter.BusinessID != "" {
+		args = append(args, filter.BusinessID)
+		conds = append(conds, fmt.Sprintf("f.business_id = $%d", len(args)))
+	}
+	switch filter.Status {
+	case models.FeaturedStatusScheduled:
+		conds = append(conds, "f.starts_at > NOW()")
+	case models.FeaturedStatusLive:
+		conds = append(conds, "f.starts_at <= NOW() AND f.ends_at > NOW()")
+	case models.FeaturedStatusEnded:
+		conds = append(conds, "f.ends_at <= NOW()")
+	}
+	where := ""
+	if len(conds) > 0 {
+		where = " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	var total int
+	if err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*)`+featuredBusinessFrom+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count featured placements: %w", err)
+	}
+
+	args = append(args, limit, offset)
+	q := `SELECT ` + featuredBusinessColumns + featuredBusinessFrom + where +
+		fmt.Sprintf(` ORDER BY f.starts_at DESC, f.position ASC LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
+	out, err := r.query(ctx, q, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list featured placements: %w", err)
+	}
+	return out, total, nil
+}
+
+func (r *featuredBusinessRepository) ListLive(ctx context.Context, province string, limit int) ([]*models.FeaturedBusiness, error) {
+	q := `SELECT ` + featuredBusinessColumns + featuredBusinessFrom + `
+		WHERE f.province = $1 AND f.starts_at <= NOW() AND f.ends_at > NOW() AND b.status = true
+		ORDER BY f.position ASC, f.starts_at ASC
+		LIMIT $2`
+	out, err := r.query(ctx, q, province, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list live featured placements: %w", err)
+	}
+	return out, nil
+}
+
+func (r *featuredBusinessRepository) query(ctx context.Context, q string, args ...any) ([]*models.FeaturedBusiness, error) {
+	rows, err := r.db.Pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]*models.FeaturedBusiness, 0)
+	for rows.Next() {
+		placement, err := scanFeaturedBusiness(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, placement)
+	}
+	return out, rows.Err()
+}
+
+func (r *featuredBusinessRepository) IncrementImpression(ctx context.Context, id string) error {
+	_, err := r.db.Pool.Exec(ctx, `UPDATE featured_businesses SET impressions = impressions + 1 WHERE id = $1`, id)
+	return err
+}
+
+func (r *featuredBusinessRepository) IncrementClick(ctx context.Context, id string) error {
+	_, err := r.db.Pool.Exec(ctx, `UPDATE featured_businesses SET clicks = clicks + 1 WHERE id = $1`, id)
+	return err
+}
@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// FeedProvider stores and serves home timelines: the posts fanned out on
+// write to each follower of their author. Posts of celebrity authors are
+// never pushed; they're pulled on read through FanoutRepository.
+//
+// The Postgres user_feeds table is the default backend. High-traffic
+// deployments can put a Redis-materialized timeline in front of it (see
+// services.RedisTimeline).
+type FeedProvider interface {
+	// Push adds postID, created at createdAt, to each follower's timeline.
+	Push(ctx context.Context, postID string, createdAt time.Time, followerIDs []string) error
+	// Timeline returns up to limit post IDs from viewerID's timeline, newest
+	// first and older than cursor when set, plus the cursor of the last one.
+	Timeline(ctx context.Context, viewerID string, cursor *time.Time, limit int) ([]string, *time.Time, error)
+}
+
+type postgresFeedProvider struct {
+	fanout FanoutRepository
+}
+
+// NewPostgresFeedProvider serves timelines from the user_feeds table.
+func NewPostgresFeedProvider(fanout FanoutRepository) FeedProvider {
+	return &postgresFeedProvider{fanout: fanout}
+}
+
+// Push ignores createdAt: user_feeds rows are stamped with the time they're
+// written, which is what its cursor pages on.
+func (p *postgresFeedProvider) Push(ctx context.Context, postID string, _ time.Time, followerIDs []string) error {
+	return p.fanout.InsertFeedEntries(ctx, postID, followerIDs)
+}
+
+func (p *postgresFeedProvider) Timeline(ctx context.Context, viewerID string, cursor *time.Time, limit int) ([]string, *time.Time, error) {
+	return p.fanout.GetPersonalizedFeed(ctx, viewerID, cursor, limit)
+}
@@ -2,12 +2,14 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/utils"
 	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 )
 
@@ -80,7 +82,7 @@ func (r *feedbackRepository) GetUserFeedbackStatus(ctx context.Context, userID s
 	err := r.db.Pool.QueryRow(ctx, query, userID).Scan(&lastFeedback)
 	
 	if err != nil {
-		if err.Error() == "no rows in result set" {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return false, nil, nil
 		}
 		r.logger.Errorw("Failed to get feedback status", "user_id", userID, "error", err)
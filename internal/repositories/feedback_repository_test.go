@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -71,7 +72,7 @@ func TestFeedbackRepository_GetUserFeedbackStatus_NoFeedback(t *testing.T) {
 	repo := newFeedbackRepo(pool)
 
 	pool.On("QueryRow", mock.Anything, mock.AnythingOfType("string"), mock.Anything).
-		Return(testutil.ErrRow(errors.New("no rows in result set")))
+		Return(testutil.ErrRow(pgx.ErrNoRows))
 
 	hasFeedback, lastAt, err := repo.GetUserFeedbackStatus(context.Background(), "user-1")
 	require.NoError(t, err)
@@ -0,0 +1,405 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// GroupRepository handles persistence for groups and their memberships.
+type GroupRepository interface {
+	// Create inserts the group and makes its creator an ACTIVE admin in one
+	// transaction.
+	Create(ctx context.Context, group *models.Group) error
+	GetByID(ctx context.Context, groupID string) (*models.Group, error)
+	Update(ctx context.Context, group *models.Group) error
+	// Delete soft-deletes the group. Its posts stay in place but are no
+	// longer reachable through the group feed.
+	Delete(ctx context.Context, groupID string) error
+	// List returns non-deleted groups matching the filter, largest first.
+	List(ctx context.Context, filter *models.GroupListFilter) ([]*models.Group, int, error)
+	// ListByMember returns the groups where the user is an ACTIVE member.
+	ListByMember(ctx context.Context, userID string, limit, offset int) ([]*models.Group, int, error)
+
+	// GetMember returns the user's membership, or (nil, nil) when there is none.
+	GetMember(ctx context.Context, groupID, userID string) (*models.GroupMember, error)
+	// GetMemberships returns the viewer's memberships keyed by group id.
+	GetMemberships(ctx context.Context, userID string, groupIDs []string) (map[string]*models.GroupMember, error)
+	// AddMember inserts a membership or join request. member_count only
+	// counts ACTIVE members.
+	AddMember(ctx context.Context, member *models.GroupMember) error
+	// ApproveMember turns a PENDING request into an ACTIVE membership.
+	// Returns ErrGroupMemberNotFound when there is no pending request.
+	ApproveMember(ctx context.Context, groupID, userID string) error
+	SetMemberRole(ctx context.Context, groupID, userID string, role models.GroupRole) error
+	// RemoveMember deletes a membership or pending request.
+	RemoveMember(ctx context.Context, groupID, userID string) error
+	ListMembers(ctx context.Context, groupID string, status models.GroupMemberStatus, limit, offset int) ([]*models.GroupMemberWithProfile, int, error)
+	CountAdmins(ctx context.Context, groupID string) (int, error)
+}
+
+type groupRepository struct {
+	db *database.DB
+}
+
+// NewGroupRepository wires a new group repository.
+func NewGroupRepository(db *database.DB) GroupRepository {
+	return &groupRepository{db: db}
+}
+
+var (
+	// ErrGroupNotFound is returned when a group id doesn't exist or is deleted.
+	ErrGroupNotFound = newNotFoundError("group not found")
+	// ErrGroupMemberNotFound is returned when a membership doesn't exist.
+	ErrGroupMemberNotFound = newNotFoundError("group member not found")
+)
+
+const groupColumns = `id, name, description, kind, privacy, cover, province, district,
+		neighborhood, created_by, member_count, created_at, updated_at`
+
+func scanGroup(row pgx.Row) (*models.Group, error) {
+	g := &models.Group{}
+	if err := row.Scan(
+		&g.ID, &g.Name, &g.Description, &g.Kind, &g.Privacy, &g.Cover, &g.Province, &g.District,
+		&g.Neighborhood, &g.CreatedBy, &g.MemberCount, &g.CreatedAt, &g.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func (r *groupRepository) Create(ctx context.Context, group *models.Group) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO groups (id, name, description, kind, privacy, cover, province, district,
+			neighborhood, created_by, member_count, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, 1, NOW(), NOW())
+		RETURNING member_count, created_at, updated_at
+	`,
+		group.ID, group.Name, group.Description, group.Kind, group.Privacy, group.Cover,
+		group.Province, group.District, group.Neighborhood, group.CreatedBy,
+	).Scan(&group.MemberCount, &group.CreatedAt, &group.UpdatedAt); err != nil {
+		return fmt.Errorf("create group: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO group_members (group_id, user_id, role, status, created_at, updated_at)
+		VALUES ($1, $2, 'ADMIN', 'ACTIVE', NOW(), NOW())
+	`, group.ID, group.CreatedBy); err != nil {
+		return fmt.Errorf("add group creator: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *groupRepository) GetByID(ctx context.Context, groupID string) (*models.Group, error) {
+	g, err := scanGroup(r.db.Pool.QueryRow(ctx,
+		`SELECT `+groupColumns+` FROM groups WHERE id = $1 AND deleted_at IS NULL`, groupID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrGroupNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get group: %w", err)
+	}
+	return g, nil
+}
+
+func (r *groupRepository) Update(ctx context.Context, group *models.Group) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE groups
+		SET name = $2, description = $3, privacy = $4, cover = $5,
+		    province = $6, district = $7, neighborhood = $8, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`, group.ID, group.Name, group.Description, group.Privacy, group.Cover,
+		group.Province, group.District, group.Neighborhood)
+	if err != nil {
+		return fmt.Errorf("update group: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrGroupNotFound
+	}
+	return nil
+}
+
+func (r *groupRepository) Delete(ctx context.Context, groupID string) error {
+	tag, err := r.db.Pool.Exec(ctx,
+		`UPDATE groups SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, groupID)
+	if err != nil {
+		return fmt.Errorf("delete group: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrGroupNotFound
+	}
+	return nil
+}
+
+func (r *groupRepository) List(ctx context.Context, filter *models.GroupListFilter) ([]*models.Group, int, error) {
+	conditions := []string{"deleted_at IS NULL"}
+	args := []interface{}{}
+	argCount := 1
+
+	if filter.Search != nil && *filter.Search != "" {
+		conditions = append(conditions, fmt.Sprintf(`(name ILIKE $%d ESCAPE '\' OR description ILIKE $%d ESCAPE '\')`, argCount, argCount))
+		args = append(args, "%"+EscapeLike(*filter.Search)+"%")
+		argCount++
+	}
+	if filter.Kind != nil {
+		conditions = append(conditions, fmt.Sprintf("kind = $%d", argCount))
+		args = append(args, string(*filter.Kind))
+		argCount++
+	}
+	if filter.Province != nil && *filter.Province != "" {
+		conditions = append(conditions, provinceFilter("", argCount))
+		args = append(args, *filter.Province)
+		argCount++
+	}
+	if filter.District != nil && *filter.District != "" {
+		conditions = append(conditions, fmt.Sprintf("district = $%d", argCount))
+		args = append(args, *filter.District)
+		argCount++
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.Reader().QueryRow(ctx, `SELECT COUNT(*) FROM groups WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count groups: %w", err)
+	}
+
+	q := fmt.Sprintf(`SELECT %s FROM groups WHERE %s ORDER BY member_count DESC, created_at DESC LIMIT $%d OFFSET $%d`,
+		groupColumns, where, argCount, argCount+1)
+	args = append(args, filter.Limit, filter.Offset)
+	groups, err := r.queryGroups(ctx, q, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return groups, total, nil
+}
+
+func (r *groupRepository) ListByMember(ctx context.Context, userID string, limit, offset int) ([]*models.Group, int, error) {
+	var total int
+	if err := r.db.Reader().QueryRow(ctx, `
+		SELECT COUNT(*) FROM group_members gm
+		JOIN groups g ON g.id = gm.group_id
+		WHERE gm.user_id = $1 AND gm.status = 'ACTIVE' AND g.deleted_at IS NULL
+	`, userID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count my groups: %w", err)
+	}
+
+	groups, err := r.queryGroups(ctx, `
+		SELECT g.id, g.name, g.description, g.kind, g.privacy, g.cover, g.province, g.district,
+			g.neighborhood, g.created_by, g.member_count, g.created_at, g.updated_at
+		FROM group_members gm
+		JOIN groups g ON g.id = gm.group_id
+		WHERE gm.user_id = $1 AND gm.status = 'ACTIVE' AND g.deleted_at IS NULL
+		ORDER BY gm.created_at DESC
+		LIMIT $2 OFFSET $3
+	`, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return groups, total, nil
+}
+
+func (r *groupRepository) queryGroups(ctx context.Context, q string, args ...interface{}) ([]*models.Group, error) {
+	rows, err := r.db.Reader().Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list groups: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]*models.Group, 0)
+	for rows.Next() {
+		g, err := scanGroup(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan group: %w", err)
+		}
+		out = append(out, g)
+	}
+	return out, rows.Err()
+}
+
+func (r *groupRepository) GetMember(ctx context.Context, groupID, userID string) (*models.GroupMember, error) {
+	m := &models.GroupMember{}
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT group_id, user_id, role, status, created_at, updated_at
+		FROM group_members WHERE group_id = $1 AND user_id = $2
+	`, groupID, userID).Scan(&m.GroupID, &m.UserID, &m.Role, &m.Status, &m.CreatedAt, &m.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get group member: %w", err)
+	}
+	return m, nil
+}
+
+func (r *groupRepository) GetMemberships(ctx context.Context, userID string, groupIDs []string) (map[string]*models.GroupMember, error) {
+	out := make(map[string]*models.GroupMember, len(groupIDs))
+	if len(groupIDs) == 0 {
+		return out, nil
+	}
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT group_id, user_id, role, status, created_at, updated_at
+		FROM group_members WHERE user_id = $1 AND group_id = ANY($2)
+	`, userID, groupIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get group memberships: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		m := &models.GroupMember{}
+		if err := rows.Scan(&m.GroupID, &m.UserID, &m.Role, &m.Status, &m.CreatedAt, &m.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan group membership: %w", err)
+		}
+		out[m.GroupID] = m
+	}
+	return out, rows.Err()
+}
+
+func (r *groupRepository) AddMember(ctx context.Context, member *models.GroupMember) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO group_members (group_id, user_id, role, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		ON CONFLICT (group_id, user_id) DO NOTHING
+	`, member.GroupID, member.UserID, member.Role, member.Status)
+	if err != nil {
+		return fmt.Errorf("add group member: %w", err)
+	}
+	if tag.RowsAffected() > 0 && member.Status == models.GroupMemberActive {
+		if _, err := tx.Exec(ctx,
+			`UPDATE groups SET member_count = member_count + 1 WHERE id = $1`, member.GroupID); err != nil {
+			return fmt.Errorf("bump member count: %w", err)
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *groupRepository) ApproveMember(ctx context.Context, groupID, userID string) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE group_members SET status = 'ACTIVE', updated_at = NOW()
+		WHERE group_id = $1 AND user_id = $2 AND status = 'PENDING'
+	`, groupID, userID)
+	if err != nil {
+		return fmt.Errorf("approve group member: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrGroupMemberNotFound
+	}
+	if _, err := tx.Exec(ctx,
+		`UPDATE groups SET member_count = member_count + 1 WHERE id = $1`, groupID); err != nil {
+		return fmt.Errorf("bump member count: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *groupRepository) SetMemberRole(ctx context.Context, groupID, userID string, role models.GroupRole) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE group_members SET role = $3, updated_at = NOW()
+		WHERE group_id = $1 AND user_id = $2 AND status = 'ACTIVE'
+	`, groupID, userID, role)
+	if err != nil {
+		return fmt.Errorf("set group member role: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrGroupMemberNotFound
+	}
+	return nil
+}
+
+func (r *groupRepository) RemoveMember(ctx context.Context, groupID, userID string) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var status models.GroupMemberStatus
+	err = tx.QueryRow(ctx, `
+		DELETE FROM group_members WHERE group_id = $1 AND user_id = $2
+		RETURNING status
+	`, groupID, userID).Scan(&status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrGroupMemberNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("remove group member: %w", err)
+	}
+	if status == models.GroupMemberActive {
+		if _, err := tx.Exec(ctx,
+			`UPDATE groups SET member_count = GREATEST(member_count - 1, 0) WHERE id = $1`, groupID); err != nil {
+			return fmt.Errorf("decrement member count: %w", err)
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *groupRepository) ListMembers(ctx context.Context, groupID string, status models.GroupMemberStatus, limit, offset int) ([]*models.GroupMemberWithProfile, int, error) {
+	var total int
+	if err := r.db.Reader().QueryRow(ctx,
+		`SELECT COUNT(*) FROM group_members WHERE group_id = $1 AND status = $2`, groupID, status,
+	).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count group members: %w", err)
+	}
+
+	// Admins and moderators first so the member list shows who runs the group.
+	rows, err := r.db.Reader().Query(ctx, `
+		SELECT gm.group_id, gm.user_id, gm.role, gm.status, gm.created_at, gm.updated_at,
+			p.first_name, p.last_name, p.avatar, p.avatar_color
+		FROM group_members gm
+		LEFT JOIN profiles p ON p.id = gm.user_id
+		WHERE gm.group_id = $1 AND gm.status = $2
+		ORDER BY CASE gm.role WHEN 'ADMIN' THEN 0 WHEN 'MODERATOR' THEN 1 ELSE 2 END, gm.created_at ASC
+		LIMIT $3 OFFSET $4
+	`, groupID, status, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list group members: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]*models.GroupMemberWithProfile, 0)
+	for rows.Next() {
+		m := &models.GroupMemberWithProfile{}
+		if err := rows.Scan(&m.GroupID, &m.UserID, &m.Role, &m.Status, &m.CreatedAt, &m.UpdatedAt,
+			&m.FirstName, &m.LastName, &m.Avatar, &m.AvatarColor); err != nil {
+			return nil, 0, fmt.Errorf("scan group member: %w", err)
+		}
+		out = append(out, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("list group members: %w", err)
+	}
+	return out, total, nil
+}
+
+func (r *groupRepository) CountAdmins(ctx context.Context, groupID string) (int, error) {
+	var n int
+	if err := r.db.Pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM group_members
+		WHERE group_id = $1 AND role = 'ADMIN' AND status = 'ACTIVE'
+	`, groupID).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count group admins: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,287 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// HelpPledgeRepository handles persistence for pledges on HELP posts and
+// the request-level fulfillment mark.
+type HelpPledgeRepository interface {
+	// Create inserts a PLEDGED pledge. Returns ErrPledgeExists when the user
+	// already has a live pledge on the post.
+	Create(ctx context.Context, pledge *models.HelpPledge) error
+	GetByID(ctx context.Context, pledgeID string) (*models.HelpPledge, error)
+	// GetActive returns the user's live (not WITHDRAWN) pledge on the post,
+	// or (nil, nil) when there is none.
+	GetActive(ctx context.Context, postID, userID string) (*models.HelpPledge, error)
+	// UpdateDetails rewrites kind, note and quantity of a PLEDGED pledge.
+	UpdateDetails(ctx context.Context, pledge *models.HelpPledge) error
+	// Transition moves a pledge from one of the from statuses to to.
+	// Returns ErrPledgeConflict when the pledge is no longer in a from status.
+	Transition(ctx context.Context, pledgeID string, from []models.PledgeStatus, to models.PledgeStatus) (*models.HelpPledge, error)
+	// ListByPost returns the post's live pledges, oldest first.
+	ListByPost(ctx context.Context, postID string, limit, offset int) ([]*models.HelpPledgeWithProfile, int, error)
+	// ListPledgerIDs returns the users with a live pledge on the post.
+	ListPledgerIDs(ctx context.Context, postID string) ([]string, error)
+	// GetSummaries returns pledge counts, fulfillment and the viewer's own
+	// pledge for each post. viewerID may be empty.
+	GetSummaries(ctx context.Context, postIDs []string, viewerID string) (map[string]*models.HelpSummary, error)
+	// MarkFulfilled records the request as fulfilled. Returns
+	// ErrHelpAlreadyFulfilled when it already was.
+	MarkFulfilled(ctx context.Context, postID string) (time.Time, error)
+}
+
+type helpPledgeRepository struct {
+	db *database.DB
+}
+
+// NewHelpPledgeRepository wires a new help pledge repository.
+func NewHelpPledgeRepository(db *database.DB) HelpPledgeRepository {
+	return &helpPledgeRepository{db: db}
+}
+
+var (
+	// ErrPledgeNotFound is returned when a pledge id doesn't exist.
+	ErrPledgeNotFound = newNotFoundError("pledge not found")
+	// ErrPledgeExists is returned when the user already pledged on the post.
+	ErrPledgeExists = newConflictError("pledge already exists")
+	// ErrPledgeConflict is returned when a transition doesn't apply to the
+	// pledge's current status.
+	ErrPledgeConflict = newConflictError("pledge status changed")
+	// ErrHelpAlreadyFulfilled is returned when the request was already
+	// marked fulfilled.
+	ErrHelpAlreadyFulfilled = newConflictError("help request already fulfilled")
+)
+
+const pledgeColumns = `id, post_id, user_id, kind, note, quantity, status, fulfilled_at, created_at, updated_at`
+
+func pledgeScanDest(p *models.HelpPledge) []interface{} {
+	return []interface{}{
+		&p.ID, &p.PostID, &p.UserID, &p.Kind, &p.Note, &p.Quantity, &p.Status,
+		&p.FulfilledAt, &p.CreatedAt, &p.UpdatedAt,
+	}
+}
+
+func (r *helpPledgeRepository) Create(ctx context.Context, pledge *models.HelpPledge) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO help_pledges (id, post_id, user_id, kind, note, quantity, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+		RETURNING created_at, updated_at
+	`, pledge.ID, pledge.PostID, pledge.UserID, pledge.Kind, pledge.Note, pledge.Quantity, pledge.Status,
+	).Scan(&pledge.CreatedAt, &pledge.UpdatedAt)
+	if err != nil && isUniqueViolation(err) {
+		return ErrPledgeExists
+	}
+	if err != nil {
+		return fmt.Errorf("create pledge: %w", err)
+	}
+	return nil
+}
+
+func (r *helpPledgeRepository) GetByID(ctx context.Context, pledgeID string) (*models.HelpPledge, error) {
+	out := &models.HelpPledge{}
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT `+pledgeColumns+` FROM help_pledges WHERE id = $1`, pledgeID,
+	).Scan(pledgeScanDest(out)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrPledgeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get pledge: %w", err)
+	}
+	return out, nil
+}
+
+func (r *helpPledgeRepository) GetActive(ctx context.Context, postID, userID string) (*models.HelpPledge, error) {
+	out := &models.HelpPledge{}
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT `+pledgeColumns+` FROM help_pledges
+		WHERE post_id = $1 AND user_id = $2 AND status <> 'WITHDRAWN'
+	`, postID, userID).Scan(pledgeScanDest(out)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get active pledge: %w", err)
+	}
+	return out, nil
+}
+
+func (r *helpPledgeRepository) UpdateDetails(ctx context.Context, pledge *models.HelpPledge) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		UPDATE help_pledges
+		SET kind = $2, note = $3, quantity = $4, updated_at = NOW()
+		WHERE id = $1 AND status = 'PLEDGED'
+		RETURNING updated_at
+	`, pledge.ID, pledge.Kind, pledge.Note, pledge.Quantity).Scan(&pledge.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrPledgeConflict
+	}
+	if err != nil {
+		return fmt.Errorf("update pledge: %w", err)
+	}
+	return nil
+}
+
+func (r *helpPledgeRepository) Transition(ctx context.Context, pledgeID string, from []models.PledgeStatus, to models.PledgeStatus) (*models.HelpPledge, error) {
+	fromStrs := make([]string, len(from))
+	for i, s := range from {
+		fromStrs[i] = string(s)
+	}
+	q := `
+		UPDATE help_pledges
+		SET status = $1,
+		    fulfilled_at = CASE WHEN $1 = 'FULFILLED' THEN NOW() ELSE fulfilled_at END,
+		    updated_at = NOW()
+		WHERE id = $2 AND status = ANY($3)
+		RETURNING ` + pledgeColumns
+	out := &models.HelpPledge{}
+	err := r.db.Pool.QueryRow(ctx, q, string(to), pledgeID, fromStrs).Scan(pledgeScanDest(out)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrPledgeConflict
+	}
+	if err != nil {
+		return nil, fmt.Errorf("update pledge status: %w", err)
+	}
+	return out, nil
+}
+
+func (r *helpPledgeRepository) ListByPost(ctx context.Context, postID string, limit, offset int) ([]*models.HelpPledgeWithProfile, int, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT
+			h.id, h.post_id, h.user_id, h.kind, h.note, h.quantity, h.status,
+			h.fulfilled_at, h.created_at, h.updated_at,
+			p.first_name, p.last_name, p.avatar, p.avatar_color
+		FROM help_pledges h
+		LEFT JOIN profiles p ON p.id = h.user_id
+		WHERE h.post_id = $1 AND h.status <> 'WITHDRAWN'
+		ORDER BY h.created_at ASC
+		LIMIT $2 OFFSET $3
+	`, postID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list pledges: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]*models.HelpPledgeWithProfile, 0)
+	for rows.Next() {
+		w := &models.HelpPledgeWithProfile{}
+		dest := append(pledgeScanDest(&w.HelpPledge), &w.FirstName, &w.LastName, &w.Avatar, &w.AvatarColor)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, 0, fmt.Errorf("scan pledge: %w", err)
+		}
+		out = append(out, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("list pledges: %w", err)
+	}
+
+	var total int
+	if err := r.db.Pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM help_pledges WHERE post_id = $1 AND status <> 'WITHDRAWN'`, postID,
+	).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count pledges: %w", err)
+	}
+	return out, total, nil
+}
+
+func (r *helpPledgeRepository) ListPledgerIDs(ctx context.Context, postID string) ([]string, error) {
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT user_id FROM help_pledges WHERE post_id = $1 AND status <> 'WITHDRAWN'`, postID)
+	if err != nil {
+		return nil, fmt.Errorf("list pledgers: %w", err)
+	}
+	defer rows.Close()
+
+	var out []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan pledger: %w", err)
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+func (r *helpPledgeRepository) GetSummaries(ctx context.Context, postIDs []string, viewerID string) (map[string]*models.HelpSummary, error) {
+	out := make(map[string]*models.HelpSummary, len(postIDs))
+	if len(postIDs) == 0 {
+		return out, nil
+	}
+	for _, id := range postIDs {
+		out[id] = &models.HelpSummary{}
+	}
+
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT ids.post_id,
+		       COUNT(h.id) FILTER (WHERE h.status <> 'WITHDRAWN'),
+		       COUNT(h.id) FILTER (WHERE h.status = 'FULFILLED'),
+		       f.fulfilled_at
+		FROM UNNEST($1::uuid[]) AS ids(post_id)
+		LEFT JOIN help_pledges h ON h.post_id = ids.post_id
+		LEFT JOIN help_fulfillments f ON f.post_id = ids.post_id
+		GROUP BY ids.post_id, f.fulfilled_at
+	`, postIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get pledge summaries: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var postID string
+		var s models.HelpSummary
+		if err := rows.Scan(&postID, &s.PledgeCount, &s.FulfilledCount, &s.FulfilledAt); err != nil {
+			return nil, fmt.Errorf("scan pledge summary: %w", err)
+		}
+		*out[postID] = s
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get pledge summaries: %w", err)
+	}
+
+	if viewerID == "" {
+		return out, nil
+	}
+	mine, err := r.db.Pool.Query(ctx, `
+		SELECT `+pledgeColumns+` FROM help_pledges
+		WHERE user_id = $1 AND post_id = ANY($2) AND status <> 'WITHDRAWN'
+	`, viewerID, postIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get viewer pledges: %w", err)
+	}
+	defer mine.Close()
+	for mine.Next() {
+		p := &models.HelpPledge{}
+		if err := mine.Scan(pledgeScanDest(p)...); err != nil {
+			return nil, fmt.Errorf("scan viewer pledge: %w", err)
+		}
+		if s, ok := out[p.PostID]; ok {
+			s.MyPledge = p
+		}
+	}
+	return out, mine.Err()
+}
+
+func (r *helpPledgeRepository) MarkFulfilled(ctx context.Context, postID string) (time.Time, error) {
+	var at time.Time
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO help_fulfillments (post_id, fulfilled_at)
+		VALUES ($1, NOW())
+		ON CONFLICT (post_id) DO NOTHING
+		RETURNING fulfilled_at
+	`, postID).Scan(&at)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return time.Time{}, ErrHelpAlreadyFulfilled
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("mark help fulfilled: %w", err)
+	}
+	return at, nil
+}
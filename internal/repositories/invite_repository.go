@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// InviteRepository stores users' invite codes and the referrals made with
+// them.
+type InviteRepository interface {
+	// GetCode returns the user's invite code. Returns ErrInviteCodeNotFound
+	// when the user has none yet.
+	GetCode(ctx context.Context, userID string) (string, error)
+
+	// CreateCode gives the user the code, or returns the one they already
+	// have. Returns ErrInviteCodeTaken when another user holds the code.
+	CreateCode(ctx context.Context, userID, code string) (string, error)
+
+	// GetUserIDByCode returns the owner of an invite code. Returns
+	// ErrInviteCodeNotFound for unknown codes.
+	GetUserIDByCode(ctx context.Context, code string) (string, error)
+
+	// CreateReferral records the referral. A user already attributed to a
+	// referrer keeps the first one; created reports whether this call
+	// recorded it.
+	CreateReferral(ctx context.Context, referral *models.Referral) (created bool, err error)
+
+	// CountReferrals counts the users the referrer brought in who still
+	// have an account.
+	CountReferrals(ctx context.Context, referrerID string) (int, error)
+
+	// TopReferrers returns the users with the most referrals since the
+	// given time (all time when nil), most first.
+	TopReferrers(ctx context.Context, since *time.Time, limit int) ([]*models.TopReferrer, error)
+}
+
+type inviteRepository struct {
+	db *database.DB
+}
+
+// NewInviteRepository wires a new invite repository.
+func NewInviteRepository(db *database.DB) InviteRepository {
+	return &inviteRepository{db: db}
+}
+
+var (
+	// ErrInviteCodeNotFound is returned for an unknown invite code, or a
+	// user without one.
+	ErrInviteCodeNotFound = newNotFoundError("invite code not found")
+	// ErrInviteCodeTaken is returned when a new invite code is already in
+	// use; the caller retries with a fresh code.
+	ErrInviteCodeTaken = newConflictError("invite code taken")
+)
+
+func (r *inviteRepository) GetCode(ctx context.Context, userID string) (string, error) {
+	var code string
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT code FROM user_invite_codes WHERE user_id = $1`, userID,
+	).Scan(&code)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrInviteCodeNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("get invite code: %w", err)
+	}
+	return code, nil
+}
+
+func (r *inviteRepository) CreateCode(ctx context.Context, userID, code string) (string, error) {
+	// The no-op update makes RETURNING yield the existing code when two
+	// requests for the same user race.
+	var out string
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO user_invite_codes (user_id, code, created_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id) DO UPDATE SET user_id = EXCLUDED.user_id
+		RETURNING code
+	`, userID, code).Scan(&out)
+	if err != nil && isUniqueViolation(err) {
+		return "", ErrInviteCodeTaken
+	}
+	if err != nil {
+		return "", fmt.Errorf("create invite code: %w", err)
+	}
+	return out, nil
+}
+
+func (r *inviteRepository) GetUserIDByCode(ctx context.Context, code string) (string, error) {
+	var userID string
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT ic.user_id
+		FROM user_invite_codes ic
+		JOIN users u ON u.id = ic.user_id AND u.deleted_at IS NULL
+		WHERE ic.code = $1
+	`, code).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrInviteCodeNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("resolve invite code: %w", err)
+	}
+	return userID, nil
+}
+
+func (r *inviteRepository) CreateReferral(ctx context.Context, referral *models.Referral) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx, `
+		INSERT INTO referrals (referred_user_id, referrer_id, code, created_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (referred_user_id) DO NOTHING
+	`, referral.ReferredUserID, referral.ReferrerID, referral.Code)
+	if err != nil {
+		return false, fmt.Errorf("create referral: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *inviteRepository) CountReferrals(ctx context.Context, referrerID string) (int, error) {
+	var n int
+	if err := r.db.Reader().QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM referrals rf
+		JOIN users u ON u.id = rf.referred_user_id AND u.deleted_at IS NULL
+		WHERE rf.referrer_id = $1
+	`, referrerID).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count referrals: %w", err)
+	}
+	return n, nil
+}
+
+func (r *inviteRepository) TopReferrers(ctx context.Context, since *time.Time, limit int) ([]*models.TopReferrer, error) {
+	rows, err := r.db.Reader().Query(ctx, `
+		SELECT rf.referrer_id, ref.email, p.first_name, p.last_name,
+			COUNT(*) AS invites, MAX(rf.created_at)
+		FROM referrals rf
+		JOIN users u ON u.id = rf.referred_user_id AND u.deleted_at IS NULL
+		JOIN users ref ON ref.id = rf.referrer_id
+		LEFT JOIN profiles p ON p.id = rf.referrer_id
+		WHERE ($1::timestamptz IS NULL OR rf.created_at >= $1)
+		GROUP BY rf.referrer_id, ref.email, p.first_name, p.last_name
+		ORDER BY invites DESC, MAX(rf.created_at) DESC
+		LIMIT $2
+	`, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("top referrers: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]*models.TopReferrer, 0)
+	for rows.Next() {
+		t := &models.TopReferrer{}
+		if err := rows.Scan(&t.UserID, &t.Email, &t.FirstName, &t.LastName, &t.Invites, &t.LastReferralAt); err != nil {
+			return nil, fmt.Errorf("scan top referrer: %w", err)
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// LifecycleTemplateRepository stores admin overrides of lifecycle message
+// copy. Kinds without a row use the built-in copy.
+type LifecycleTemplateRepository interface {
+	// List returns every override.
+	List(ctx context.Context) ([]*models.LifecycleTemplate, error)
+
+	// Get returns the override for kind.
+	Get(ctx context.Context, kind models.NotificationType) (*models.LifecycleTemplate, error)
+
+	// Upsert stores the override for t.Kind.
+	Upsert(ctx context.Context, t *models.LifecycleTemplate) error
+
+	// Delete removes the override for kind, restoring the built-in copy.
+	Delete(ctx context.Context, kind models.NotificationType) error
+}
+
+type lifecycleTemplateRepository struct {
+	db *database.DB
+}
+
+// NewLifecycleTemplateRepository wires a new lifecycle template repository.
+func NewLifecycleTemplateRepository(db *database.DB) LifecycleTemplateRepository {
+	return &lifecycleTemplateRepository{db: db}
+}
+
+// ErrLifecycleTemplateNotFound is returned when a kind has no override.
+var ErrLifecycleTemplateNotFound = newNotFoundError("lifecycle template not found")
+
+const lifecycleTemplateColumns = `kind, title, message, enabled, updated_by::text, updated_at`
+
+func scanLifecycleTemplate(row pgx.Row) (*models.LifecycleTemplate, error) {
+	t := &models.LifecycleTemplate{Customized: true}
+	if err := row.Scan(&t.Kind, &t.Title, &t.Message, &t.Enabled, &t.UpdatedBy, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (r *lifecycleTemplateRepository) List(ctx context.Context) ([]*models.LifecycleTemplate, error) {
+	rows, err := r.db.Pool.Query(ctx, `SELECT `+lifecycleTemplateColumns+` FROM lifecycle_templates ORDER BY kind`)
+	if err != nil {
+		return nil, fmt.Errorf("list lifecycle templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []*models.LifecycleTemplate
+	for rows.Next() {
+		t, err := scanLifecycleTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan lifecycle template: %w", err)
+		}
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
+
+func (r *lifecycleTemplateRepository) Get(ctx context.Context, kind models.NotificationType) (*models.LifecycleTemplate, error) {
+	t, err := scanLifecycleTemplate(r.db.Pool.QueryRow(ctx,
+		`SELECT `+lifecycleTemplateColumns+` FROM lifecycle_templates WHERE kind = $1`, kind))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrLifecycleTemplateNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get lifecycle template: %w", err)
+	}
+	return t, nil
+}
+
+func (r *lifecycleTemplateRepository) Upsert(ctx context.Context, t *models.LifecycleTemplate) error {
+	const q = `
+		INSERT INTO lifecycle_templates (kind, title, message, enabled, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (kind) DO UPDATE SET
+			title = EXCLUDED.title,
+			message = EXCLUDED.message,
+			enabled = EXCLUDED.enabled,
+			updated_by = EXCLUDED.updated_by,
+			updated_at = EXCLUDED.updated_at
+		RETURNING updated_at`
+	if err := r.db.Pool.QueryRow(ctx, q, t.Kind, t.Title, t.Message, t.Enabled, t.UpdatedBy).Scan(&t.UpdatedAt); err != nil {
+		return fmt.Errorf("upsert lifecycle template: %w", err)
+	}
+	return nil
+}
+
+func (r *lifecycleTemplateRepository) Delete(ctx context.Context, kind models.NotificationType) error {
+	if _, err := r.db.Pool.Exec(ctx, `DELETE FROM lifecycle_templates WHERE kind = $1`, kind); err != nil {
+		return fmt.Errorf("delete lifecycle template: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// LinkPreviewRepository stores fetched link previews keyed by URL hash and
+// which preview each post shows.
+type LinkPreviewRepository interface {
+	// GetByHash returns the cached preview for a URL hash.
+	GetByHash(ctx context.Context, urlHash string) (*models.LinkPreview, error)
+
+	// Upsert stores or refreshes the preview for a URL hash.
+	Upsert(ctx context.Context, urlHash string, preview *models.LinkPreview) error
+
+	// AttachToPost makes the post show the preview, replacing any other.
+	AttachToPost(ctx context.Context, postID, urlHash string) error
+
+	// DetachFromPost removes the post's preview, if any.
+	DetachFromPost(ctx context.Context, postID string) error
+
+	// GetByPostIDs returns the preview of each post, keyed by post ID.
+	// Posts without one, or whose fetch failed, are absent from the map.
+	GetByPostIDs(ctx context.Context, postIDs []string) (map[string]*models.LinkPreview, error)
+}
+
+type linkPreviewRepository struct {
+	db *database.DB
+}
+
+// NewLinkPreviewRepository wires a new link preview repository.
+func NewLinkPreviewRepository(db *database.DB) LinkPreviewRepository {
+	return &linkPreviewRepository{db: db}
+}
+
+// ErrLinkPreviewNotFound is returned when a URL hasn't been fetched yet.
+var ErrLinkPreviewNotFound = newNotFoundError("link preview not found")
+
+const linkPreviewColumns = `lp.url, lp.title, lp.description, lp.image_url, lp.site_name, lp.failed, lp.fetched_at`
+
+func scanLinkPreview(row pgx.Row, lead ...any) (*models.LinkPreview, error) {
+	p := &models.LinkPreview{}
+	dest := append(lead, &p.URL, &p.Title, &p.Description, &p.ImageURL, &p.SiteName, &p.Failed, &p.FetchedAt)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *linkPreviewRepository) GetByHash(ctx context.Context, urlHash string) (*models.LinkPreview, error) {
+	q := `SELECT ` + linkPreviewColumns + ` FROM link_previews lp WHERE lp.url_hash = $1`
+	preview, err := scanLinkPreview(r.db.Pool.QueryRow(ctx, q, urlHash))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrLinkPreviewNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get link preview: %w", err)
+	}
+	return preview, nil
+}
+
+func (r *linkPreviewRepository) Upsert(ctx context.Context, urlHash string, preview *models.LinkPreview) error {
+	const q = `
+		INSERT INTO link_previews (url_hash, url, title, description, image_url, site_name, failed, fetched_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (url_hash) DO UPDATE SET
+			url = EXCLUDED.url,
+			title = EXCLUDED.title,
+			description = EXCLUDED.description,
+			image_url = EXCLUDED.image_url,
+			site_name = EXCLUDED.site_name,
+			failed = EXCLUDED.failed,
+			fetched_at = EXCLUDED.fetched_at`
+	_, err := r.db.Pool.Exec(ctx, q, urlHash, preview.URL, preview.Title, preview.Description,
+		preview.ImageURL, preview.SiteName, preview.Failed, preview.FetchedAt)
+	if err != nil {
+		return fmt.Errorf("upsert link preview: %w", err)
+	}
+	return nil
+}
+
+func (r *linkPreviewRepository) AttachToPost(ctx context.Context, postID, urlHash string) error {
+	const q = `
+		INSERT INTO post_link_previews (post_id, url_hash)
+		VALUES ($1, $2)
+		ON CONFLICT (post_id) DO UPDATE SET url_hash = EXCLUDED.url_hash, created_at = NOW()`
+	if _, err := r.db.Pool.Exec(ctx, q, postID, urlHash); err != nil {
+		return fmt.Errorf("attach link preview: %w", err)
+	}
+	return nil
+}
+
+func (r *linkPreviewRepository) DetachFromPost(ctx context.Context, postID string) error {
+	if _, err := r.db.Pool.Exec(ctx, `DELETE FROM post_link_previews WHERE post_id = $1`, postID); err != nil {
+		return fmt.Errorf("detach link preview: %w", err)
+	}
+	return nil
+}
+
+func (r *linkPreviewRepository) GetByPostIDs(ctx context.Context, postIDs []string) (map[string]*models.LinkPreview, error) {
+	out := make(map[string]*models.LinkPreview)
+	if len(postIDs) == 0 {
+		return out, nil
+	}
+	q := `SELECT plp.post_id, ` + linkPreviewColumns + `
+		FROM post_link_previews plp
+		JOIN link_previews lp ON lp.url_hash = plp.url_hash
+		WHERE plp.post_id = ANY($1) AND NOT lp.failed`
+	rows, err := r.db.Reader().Query(ctx, q, postIDs)
+	if err != nil {
+		return nil, fmt.Errorf("link previews for posts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID string
+		preview, err := scanLinkPreview(rows, &postID)
+		if err != nil {
+			return nil, fmt.Errorf("scan link preview: %w", err)
+		}
+		out[postID] = preview
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// LocationRepository handles persistence for the province / district /
+// neighborhood reference list.
+type LocationRepository interface {
+	// Create inserts a location. Returns ErrLocationExists when a sibling
+	// already has the same English name.
+	Create(ctx context.Context, loc *models.LocationRef) error
+	GetByID(ctx context.Context, id string) (*models.LocationRef, error)
+	// Update saves the location. A rename is propagated to the province /
+	// district text of every profile, business, post and group that points
+	// at it, in the same transaction.
+	Update(ctx context.Context, loc *models.LocationRef, renamed bool) error
+	// Delete removes the location and its children. Rows that referenced it
+	// keep their text but lose the id.
+	Delete(ctx context.Context, id string) error
+	// List returns the children of filter.ParentID (provinces when nil),
+	// ordered by position then name.
+	List(ctx context.Context, filter *models.LocationFilter) ([]*models.LocationRef, error)
+}
+
+type locationRepository struct {
+	db *database.DB
+}
+
+// NewLocationRepository wires a new location repository.
+func NewLocationRepository(db *database.DB) LocationRepository {
+	return &locationRepository{db: db}
+}
+
+var (
+	// ErrLocationNotFound is returned when a location id doesn't exist.
+	ErrLocationNotFound = newNotFoundError("location not found")
+	// ErrLocationExists is returned when a sibling already uses the name.
+	ErrLocationExists = newConflictError("location already exists")
+)
+
+// locationTextColumns maps a level to the free-text column that mirrors it
+// on referencing tables. Neighborhoods are reference-only.
+var locationTextColumns = map[models.LocationLevel][2]string{
+	models.LocationProvince: {"province", "province_id"},
+	models.LocationDistrict: {"district", "district_id"},
+}
+
+// locationReferencingTables carry province/district text plus the
+// normalized ids maintained by the normalize_location_fields trigger.
+var locationReferencingTables = []string{"profiles", "business_profiles", "posts", "groups"}
+
+// provinceFilter returns a WHERE fragment matching rows (alias like "pr."
+// or "") whose province is the one named by placeholder $n, in any
+// supported spelling. Rows with a province outside the reference list still
+// match on exact text.
+func provinceFilter(alias string, n int) string {
+	return fmt.Sprintf("(%[1]sprovince_id = resolve_location_id('PROVINCE', NULL, $%[2]d) OR %[1]sprovince = $%[2]d)", alias, n)
+}
+
+const locationColumns = `id, parent_id, level, name, name_dari, name_pashto, is_active, position, created_at, updated_at`
+
+func locationScanDest(l *models.LocationRef) []interface{} {
+	return []interface{}{
+		&l.ID, &l.ParentID, &l.Level, &l.Name, &l.NameDari, &l.NamePashto,
+		&l.IsActive, &l.Position, &l.CreatedAt, &l.UpdatedAt,
+	}
+}
+
+func (r *locationRepository) Create(ctx context.Context, loc *models.LocationRef) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO locations (id, parent_id, level, name, name_dari, name_pashto, is_active, position, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
+		RETURNING created_at, updated_at
+	`, loc.ID, loc.ParentID, loc.Level, loc.Name, loc.NameDari, loc.NamePashto, loc.IsActive, loc.Position,
+	).Scan(&loc.CreatedAt, &loc.UpdatedAt)
+	if err != nil && isUniqueViolation(err) {
+		return ErrLocationExists
+	}
+	if err != nil {
+		return fmt.Errorf("create location: %w", err)
+	}
+	return nil
+}
+
+func (r *locationRepository) GetByID(ctx context.Context, id string) (*models.LocationRef, error) {
+	out := &models.LocationRef{}
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT `+locationColumns+` FROM locations WHERE id = $1`, id,
+	).Scan(locationScanDest(out)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrLocationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get location: %w", err)
+	}
+	return out, nil
+}
+
+func (r *locationRepository) Update(ctx context.Context, loc *models.LocationRef, renamed bool) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	err = tx.QueryRow(ctx, `
+		UPDATE locations
+		SET name = $2, name_dari = $3, name_pashto = $4, is_active = $5, position = $6, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`, loc.ID, loc.Name, loc.NameDari, loc.NamePashto, loc.IsActive, loc.Position).Scan(&loc.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrLocationNotFound
+	}
+	if err != nil && isUniqueViolation(err) {
+		return ErrLocationExists
+	}
+	if err != nil {
+		return fmt.Errorf("update location: %w", err)
+	}
+
+	if cols, ok := locationTextColumns[loc.Level]; ok && renamed {
+		for _, table := range locationReferencingTables {
+			q := fmt.Sprintf(`UPDATE %s SET %s = $1 WHERE %s = $2`, table, cols[0], cols[1])
+			if _, err := tx.Exec(ctx, q, loc.Name, loc.ID); err != nil {
+				return fmt.Errorf("propagate location rename to %s: %w", table, err)
+			}
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *locationRepository) Delete(ctx context.Context, id string) error {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM locations WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete location: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrLocationNotFound
+	}
+	return nil
+}
+
+func (r *locationRepository) List(ctx context.Context, filter *models.LocationFilter) ([]*models.LocationRef, error) {
+	q := `SELECT ` + locationColumns + ` FROM locations WHERE parent_id IS NOT DISTINCT FROM $1`
+	if !filter.IncludeInactive {
+		q += ` AND is_active = TRUE`
+	}
+	q += ` ORDER BY position ASC, name ASC`
+
+	rows, err := r.db.Reader().Query(ctx, q, filter.ParentID)
+	if err != nil {
+		return nil, fmt.Errorf("list locations: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]*models.LocationRef, 0)
+	for rows.Next() {
+		l := &models.LocationRef{}
+		if err := rows.Scan(locationScanDest(l)...); err != nil {
+			return nil, fmt.Errorf("scan location: %w", err)
+		}
+		out = append(out, l)
+	}
+	return out, rows.Err()
+}
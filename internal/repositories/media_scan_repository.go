@@ -0,0 +1,168 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+)
+
+// MediaScanRepository feeds the media moderation queues to the image
+// classifier and records its verdicts. Post attachments are queued by
+// PostRepository.CreateAttachment; business images by EnqueueBusinessMedia.
+type MediaScanRepository interface {
+	// ListUnscanned returns the oldest pending images the classifier hasn't
+	// seen yet, post attachments, business images and held profile media
+	// together. Video and
+	// audio attachments are left out.
+	ListUnscanned(ctx context.Context, limit int) ([]*models.MediaScanJob, error)
+
+	// RecordScan stores the classifier output and marks the image scanned.
+	// blurredURL is the blurred copy of a flagged image, if one was made.
+	RecordScan(ctx context.Context, job *models.MediaScanJob, labels *models.MediaScanLabels, flagged bool, blurredURL *string) error
+
+	// RecordScanFailure counts a failed attempt. After maxAttempts the image
+	// is marked scanned with the error in its labels, so it stops being
+	// retried and an admin still sees it unclassified.
+	RecordScanFailure(ctx context.Context, job *models.MediaScanJob, reason string, maxAttempts int) error
+
+	// EnqueueBusinessMedia queues a business image for review. An image
+	// already queued is left as it is.
+	EnqueueBusinessMedia(ctx context.Context, businessID, url string) error
+
+	// FlaggedAttachments returns which of the attachments are flagged and
+	// not yet reviewed, mapped to their blurred copy (nil when none).
+	FlaggedAttachments(ctx context.Context, attachmentIDs []string) (map[string]*string, error)
+}
+
+type mediaScanRepository struct {
+	db *database.DB
+}
+
+// NewMediaScanRepository wires a new media scan repository.
+func NewMediaScanRepository(db *database.DB) MediaScanRepository {
+	return &mediaScanRepository{db: db}
+}
+
+// mediaQueueRow names the queue table and row a job lives in.
+func mediaQueueRow(job *models.MediaScanJob) (table, keyColumn, key string) {
+	if job.AttachmentID != "" {
+		return "media_moderation_queue", "attachment_id", job.AttachmentID
+	}
+	if job.ProfileMediaID != "" {
+		return "profile_media_reviews", "id", job.ProfileMediaID
+	}
+	return "business_media_moderation_queue", "id", job.BusinessMediaID
+}
+
+func (r *mediaScanRepository) ListUnscanned(ctx context.Context, limit int) ([]*models.MediaScanJob, error) {
+	const q = `
+		SELECT attachment_id, business_media_id, profile_media_id, url, mime_type, scan_attempts FROM (
+			SELECT q.attachment_id::text AS attachment_id, '' AS business_media_id, '' AS profile_media_id,
+			       a.photo->>'url' AS url, COALESCE(a.photo->>'mime_type', '') AS mime_type,
+			       q.scan_attempts, q.enqueued_at
+			FROM media_moderation_queue q
+			JOIN attachments a ON a.id = q.attachment_id
+			WHERE q.status = 'pending' AND q.scanned_at IS NULL
+			  AND a.deleted_at IS NULL
+			  AND COALESCE(a.photo->>'mime_type', '') NOT LIKE 'video/%'
+			  AND COALESCE(a.photo->>'mime_type', '') NOT LIKE 'audio/%'
+			UNION ALL
+			SELECT '', b.id::text, '', b.url, '', b.scan_attempts, b.enqueued_at
+			FROM business_media_moderation_queue b
+			WHERE b.status = 'pending' AND b.scanned_at IS NULL
+			UNION ALL
+			SELECT '', '', m.id::text, m.photo->>'url', COALESCE(m.photo->>'mime_type', ''), m.scan_attempts, m.enqueued_at
+			FROM profile_media_reviews m
+			WHERE m.status = 'pending' AND m.scanned_at IS NULL
+		) pending
+		ORDER BY enqueued_at ASC
+		LIMIT $1
+	`
+	rows, err := r.db.Pool.Query(ctx, q, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list unscanned media: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]*models.MediaScanJob, 0)
+	for rows.Next() {
+		job := &models.MediaScanJob{}
+		if err := rows.Scan(&job.AttachmentID, &job.BusinessMediaID, &job.ProfileMediaID, &job.URL, &job.MimeType, &job.Attempts); err != nil {
+			return nil, fmt.Errorf("scan media job: %w", err)
+		}
+		out = append(out, job)
+	}
+	return out, rows.Err()
+}
+
+func (r *mediaScanRepository) RecordScan(ctx context.Context, job *models.MediaScanJob, labels *models.MediaScanLabels, flagged bool, blurredURL *string) error {
+	table, keyColumn, key := mediaQueueRow(job)
+	q := fmt.Sprintf(`
+		UPDATE %s
+		SET auto_labels = $2, flagged = $3, blurred_url = $4,
+		    scanned_at = NOW(), scan_attempts = scan_attempts + 1
+		WHERE %s = $1
+	`, table, keyColumn)
+	if _, err := r.db.Pool.Exec(ctx, q, key, labels, flagged, blurredURL); err != nil {
+		return fmt.Errorf("record media scan: %w", err)
+	}
+	return nil
+}
+
+func (r *mediaScanRepository) RecordScanFailure(ctx context.Context, job *models.MediaScanJob, reason string, maxAttempts int) error {
+	table, keyColumn, key := mediaQueueRow(job)
+	labels := &models.MediaScanLabels{Error: reason, ScannedAt: time.Now()}
+	q := fmt.Sprintf(`
+		UPDATE %s
+		SET scan_attempts = scan_attempts + 1,
+		    scanned_at = CASE WHEN scan_attempts + 1 >= $2 THEN NOW() ELSE scanned_at END,
+		    auto_labels = CASE WHEN scan_attempts + 1 >= $2 THEN $3::jsonb ELSE auto_labels END
+		WHERE %s = $1
+	`, table, keyColumn)
+	if _, err := r.db.Pool.Exec(ctx, q, key, maxAttempts, labels); err != nil {
+		return fmt.Errorf("record media scan failure: %w", err)
+	}
+	return nil
+}
+
+func (r *mediaScanRepository) EnqueueBusinessMedia(ctx context.Context, businessID, url string) error {
+	const q = `
+		INSERT INTO business_media_moderation_queue (business_id, url)
+		VALUES ($1, $2)
+		ON CONFLICT (url) DO NOTHING
+	`
+	if _, err := r.db.Pool.Exec(ctx, q, businessID, url); err != nil {
+		return fmt.Errorf("enqueue business media: %w", err)
+	}
+	return nil
+}
+
+func (r *mediaScanRepository) FlaggedAttachments(ctx context.Context, attachmentIDs []string) (map[string]*string, error) {
+	out := make(map[string]*string)
+	if len(attachmentIDs) == 0 {
+		return out, nil
+	}
+	const q = `
+		SELECT attachment_id::text, blurred_url
+		FROM media_moderation_queue
+		WHERE attachment_id = ANY($1::uuid[]) AND flagged AND status = 'pending'
+	`
+	rows, err := r.db.Reader().Query(ctx, q, attachmentIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get flagged attachments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var blurred *string
+		if err := rows.Scan(&id, &blurred); err != nil {
+			return nil, fmt.Errorf("scan flagged attachment: %w", err)
+		}
+		out[id] = blurred
+	}
+	return out, rows.Err()
+}
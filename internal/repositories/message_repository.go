@@ -33,6 +33,10 @@ type MessageRepository interface {
 	// the user has individually delete-for-me'd so the badge matches their
 	// thread view.
 	GetUnreadCount(ctx context.Context, conversationID, userID string) (int, error)
+	// GetUnreadCountsByUser returns the user's unread count for every
+	// conversation that has any, keyed by conversation id. Source of truth
+	// for the Redis unread counters.
+	GetUnreadCountsByUser(ctx context.Context, userID string) (map[string]int, error)
 
 	// Get last message in conversation. viewerID excludes per-user-deleted
 	// rows so the conversation list preview reflects what the viewer sees.
@@ -45,6 +49,11 @@ type MessageRepository interface {
 	// per-emoji {emoji,count,reacted} lists keyed by message id. `reacted` is
 	// relative to viewerID.
 	GetReactions(ctx context.Context, messageIDs []string, viewerID string) (map[string][]models.MessageReaction, error)
+
+	// DeleteExpired hard-deletes up to limit disappearing messages past
+	// expires_at and returns them, so their attachments can be removed and
+	// participants told.
+	DeleteExpired(ctx context.Context, limit int) ([]*models.Message, error)
 }
 
 type messageRepository struct {
@@ -56,24 +65,29 @@ func NewMessageRepository(db *database.DB) MessageRepository {
 	return &messageRepository{db: db}
 }
 
-// Create creates a new message
+// Create creates a new message. The database assigns message.Seq.
 func (r *messageRepository) Create(ctx context.Context, message *models.Message) error {
 	query := `
 		INSERT INTO messages (
-			id, conversation_id, sender_id, content, message_type, product_id, reply_to_message_id, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			id, conversation_id, sender_id, content, message_type, product_id, business_product_id, reply_to_message_id, created_at, shared_post_id, sticker_id, expires_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING seq
 	`
 
-	_, err := r.db.Pool.Exec(ctx, query,
+	err := r.db.Pool.QueryRow(ctx, query,
 		message.ID,
 		message.ConversationID,
 		message.SenderID,
 		message.Content,
 		message.MessageType,
 		message.ProductID,
+		message.BusinessProductID,
 		message.ReplyToMessageID,
 		message.CreatedAt,
-	)
+		message.SharedPostID,
+		message.StickerID,
+		message.ExpiresAt,
+	).Scan(&message.Seq)
 
 	if err != nil {
 		return fmt.Errorf("failed to create message: %w", err)
@@ -85,9 +99,9 @@ func (r *messageRepository) Create(ctx context.Context, message *models.Message)
 // GetByID retrieves a message by ID
 func (r *messageRepository) GetByID(ctx context.Context, messageID string) (*models.Message, error) {
 	query := `
-		SELECT id, conversation_id, sender_id, content, message_type, product_id, reply_to_message_id, read_at, created_at, edited_at, deleted_at
+		SELECT id, conversation_id, sender_id, content, message_type, product_id, business_product_id, reply_to_message_id, read_at, created_at, edited_at, deleted_at, shared_post_id, sticker_id, expires_at, seq
 		FROM messages
-		WHERE id = $1 AND deleted_at IS NULL
+		WHERE id = $1 AND deleted_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
 	`
 
 	message := &models.Message{}
@@ -98,16 +112,21 @@ func (r *messageRepository) GetByID(ctx context.Context, messageID string) (*mod
 		&message.Content,
 		&message.MessageType,
 		&message.ProductID,
+		&message.BusinessProductID,
 		&message.ReplyToMessageID,
 		&message.ReadAt,
 		&message.CreatedAt,
 		&message.EditedAt,
 		&message.DeletedAt,
+		&message.SharedPostID,
+		&message.StickerID,
+		&message.ExpiresAt,
+		&message.Seq,
 	)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("message not found")
+			return nil, newNotFoundError("message not found")
 		}
 		return nil, fmt.Errorf("failed to get message: %w", err)
 	}
@@ -117,19 +136,30 @@ func (r *messageRepository) GetByID(ctx context.Context, messageID string) (*mod
 
 // List retrieves messages in a conversation, excluding messages the viewer
 // has individually delete-for-me'd. Messages deleted-for-everyone are
-// already filtered via `deleted_at IS NULL`.
+// already filtered via `deleted_at IS NULL`, and disappearing messages
+// past expires_at are hidden until the purge job removes them.
 func (r *messageRepository) List(ctx context.Context, filter *models.GetMessagesFilter) ([]*models.Message, error) {
 	query := `
-		SELECT id, conversation_id, sender_id, content, message_type, product_id, reply_to_message_id, read_at, created_at, edited_at, deleted_at
+		SELECT id, conversation_id, sender_id, content, message_type, product_id, business_product_id, reply_to_message_id, read_at, created_at, edited_at, deleted_at, shared_post_id, sticker_id, expires_at, seq
 		FROM messages
 		WHERE conversation_id = $1
-		  AND deleted_at IS NULL
-		  AND NOT ($2::uuid = ANY(deleted_for_user_ids))
-		ORDER BY created_at DESC
-		LIMIT $3 OFFSET $4
-	`
+		  AND deleted_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+		  AND NOT ($2::uuid = ANY(deleted_for_user_ids))`
+	args := []interface{}{filter.ConversationID, filter.ViewerID}
+
+	switch {
+	case filter.AfterSeq != nil:
+		query += " AND seq > $3 ORDER BY seq ASC LIMIT $4"
+		args = append(args, *filter.AfterSeq, filter.Limit)
+	case filter.BeforeSeq != nil:
+		query += " AND seq < $3 ORDER BY seq DESC LIMIT $4"
+		args = append(args, *filter.BeforeSeq, filter.Limit)
+	default:
+		query += " ORDER BY seq DESC LIMIT $3 OFFSET $4"
+		args = append(args, filter.Limit, filter.Offset)
+	}
 
-	rows, err := r.db.Pool.Query(ctx, query, filter.ConversationID, filter.ViewerID, filter.Limit, filter.Offset)
+	rows, err := r.db.Pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list messages: %w", err)
 	}
@@ -145,11 +175,16 @@ func (r *messageRepository) List(ctx context.Context, filter *models.GetMessages
 			&message.Content,
 			&message.MessageType,
 			&message.ProductID,
+			&message.BusinessProductID,
 			&message.ReplyToMessageID,
 			&message.ReadAt,
 			&message.CreatedAt,
 			&message.EditedAt,
 			&message.DeletedAt,
+			&message.SharedPostID,
+			&message.StickerID,
+			&message.ExpiresAt,
+			&message.Seq,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan message: %w", err)
@@ -169,7 +204,7 @@ func (r *messageRepository) Delete(ctx context.Context, messageID string) error
 	query := `
 		UPDATE messages
 		SET deleted_at = $2
-		WHERE id = $1 AND deleted_at IS NULL
+		WHERE id = $1 AND deleted_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
 	`
 
 	result, err := r.db.Pool.Exec(ctx, query, messageID, time.Now())
@@ -178,7 +213,7 @@ func (r *messageRepository) Delete(ctx context.Context, messageID string) error
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("message not found")
+		return newNotFoundError("message not found")
 	}
 
 	return nil
@@ -193,7 +228,7 @@ func (r *messageRepository) DeleteForUser(ctx context.Context, messageID, userID
 		SET deleted_for_user_ids = (
 			SELECT ARRAY(SELECT DISTINCT unnest(array_append(deleted_for_user_ids, $2::uuid)))
 		)
-		WHERE id = $1 AND deleted_at IS NULL
+		WHERE id = $1 AND deleted_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
 	`
 
 	result, err := r.db.Pool.Exec(ctx, query, messageID, userID)
@@ -202,7 +237,7 @@ func (r *messageRepository) DeleteForUser(ctx context.Context, messageID, userID
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("message not found")
+		return newNotFoundError("message not found")
 	}
 
 	return nil
@@ -214,8 +249,8 @@ func (r *messageRepository) UpdateContent(ctx context.Context, messageID, conten
 	query := `
 		UPDATE messages
 		SET content = $2, edited_at = NOW()
-		WHERE id = $1 AND deleted_at IS NULL
-		RETURNING id, conversation_id, sender_id, content, message_type, product_id, reply_to_message_id, read_at, created_at, edited_at, deleted_at
+		WHERE id = $1 AND deleted_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+		RETURNING id, conversation_id, sender_id, content, message_type, product_id, business_product_id, reply_to_message_id, read_at, created_at, edited_at, deleted_at, shared_post_id, sticker_id, expires_at, seq
 	`
 
 	message := &models.Message{}
@@ -226,15 +261,20 @@ func (r *messageRepository) UpdateContent(ctx context.Context, messageID, conten
 		&message.Content,
 		&message.MessageType,
 		&message.ProductID,
+		&message.BusinessProductID,
 		&message.ReplyToMessageID,
 		&message.ReadAt,
 		&message.CreatedAt,
 		&message.EditedAt,
 		&message.DeletedAt,
+		&message.SharedPostID,
+		&message.StickerID,
+		&message.ExpiresAt,
+		&message.Seq,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("message not found")
+			return nil, newNotFoundError("message not found")
 		}
 		return nil, fmt.Errorf("failed to update message: %w", err)
 	}
@@ -266,7 +306,7 @@ func (r *messageRepository) MarkConversationAsRead(ctx context.Context, conversa
 		WHERE conversation_id = $1
 		  AND sender_id != $2
 		  AND read_at IS NULL
-		  AND deleted_at IS NULL
+		  AND deleted_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
 	`
 
 	_, err := r.db.Pool.Exec(ctx, query, conversationID, userID)
@@ -285,7 +325,7 @@ func (r *messageRepository) GetUnreadCount(ctx context.Context, conversationID,
 		WHERE conversation_id = $1
 		  AND sender_id != $2
 		  AND read_at IS NULL
-		  AND deleted_at IS NULL
+		  AND deleted_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
 		  AND NOT ($2::uuid = ANY(deleted_for_user_ids))
 	`
 
@@ -298,16 +338,48 @@ func (r *messageRepository) GetUnreadCount(ctx context.Context, conversationID,
 	return count, nil
 }
 
+// GetUnreadCountsByUser returns per-conversation unread counts for a user
+func (r *messageRepository) GetUnreadCountsByUser(ctx context.Context, userID string) (map[string]int, error) {
+	query := `
+		SELECT m.conversation_id, COUNT(*)
+		FROM messages m
+		JOIN conversations c ON c.id = m.conversation_id
+		WHERE (c.participant1_id = $1 OR c.participant2_id = $1)
+		  AND m.sender_id != $1
+		  AND m.read_at IS NULL
+		  AND m.deleted_at IS NULL AND (m.expires_at IS NULL OR m.expires_at > NOW())
+		  AND NOT ($1::uuid = ANY(m.deleted_for_user_ids))
+		GROUP BY m.conversation_id
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unread counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var conversationID string
+		var count int
+		if err := rows.Scan(&conversationID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan unread count: %w", err)
+		}
+		counts[conversationID] = count
+	}
+	return counts, rows.Err()
+}
+
 // GetLastMessage retrieves the last message in a conversation that the
 // viewer can still see (i.e. not in their per-user delete list).
 func (r *messageRepository) GetLastMessage(ctx context.Context, conversationID, viewerID string) (*models.Message, error) {
 	query := `
-		SELECT id, conversation_id, sender_id, content, message_type, product_id, reply_to_message_id, read_at, created_at, deleted_at
+		SELECT id, conversation_id, sender_id, content, message_type, product_id, business_product_id, reply_to_message_id, read_at, created_at, deleted_at, shared_post_id, sticker_id, expires_at, seq
 		FROM messages
 		WHERE conversation_id = $1
-		  AND deleted_at IS NULL
+		  AND deleted_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
 		  AND NOT ($2::uuid = ANY(deleted_for_user_ids))
-		ORDER BY created_at DESC
+		ORDER BY seq DESC
 		LIMIT 1
 	`
 
@@ -319,10 +391,15 @@ func (r *messageRepository) GetLastMessage(ctx context.Context, conversationID,
 		&message.Content,
 		&message.MessageType,
 		&message.ProductID,
+		&message.BusinessProductID,
 		&message.ReplyToMessageID,
 		&message.ReadAt,
 		&message.CreatedAt,
 		&message.DeletedAt,
+		&message.SharedPostID,
+		&message.StickerID,
+		&message.ExpiresAt,
+		&message.Seq,
 	)
 
 	if err != nil {
@@ -386,3 +463,41 @@ func (r *messageRepository) GetReactions(ctx context.Context, messageIDs []strin
 	}
 	return out, rows.Err()
 }
+
+// DeleteExpired hard-deletes a batch of messages past expires_at.
+// Reactions cascade; replies keep their row with reply_to_message_id unset.
+func (r *messageRepository) DeleteExpired(ctx context.Context, limit int) ([]*models.Message, error) {
+	query := `
+		DELETE FROM messages
+		WHERE id IN (
+			SELECT id FROM messages
+			WHERE expires_at IS NOT NULL AND expires_at <= NOW()
+			ORDER BY expires_at
+			LIMIT $1
+		)
+		RETURNING id, conversation_id, sender_id, content, message_type, created_at, expires_at
+	`
+	rows, err := r.db.Pool.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete expired messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []*models.Message
+	for rows.Next() {
+		message := &models.Message{}
+		if err := rows.Scan(
+			&message.ID,
+			&message.ConversationID,
+			&message.SenderID,
+			&message.Content,
+			&message.MessageType,
+			&message.CreatedAt,
+			&message.ExpiresAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan expired message: %w", err)
+		}
+		messages = append(messages, message)
+	}
+	return messages, rows.Err()
+}
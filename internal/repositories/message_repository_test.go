@@ -33,10 +33,11 @@ func makeMessageScanFn(m *models.Message) func(dest ...any) error {
 		*dest[3].(**string) = contentPtr
 		*dest[4].(*models.MessageType) = m.MessageType
 		*dest[5].(**string) = m.ProductID
-		*dest[6].(**string) = m.ReplyToMessageID
-		*dest[7].(**time.Time) = m.ReadAt
-		*dest[8].(*time.Time) = m.CreatedAt
-		*dest[9].(**time.Time) = m.DeletedAt
+		*dest[6].(**string) = m.BusinessProductID
+		*dest[7].(**string) = m.ReplyToMessageID
+		*dest[8].(**time.Time) = m.ReadAt
+		*dest[9].(*time.Time) = m.CreatedAt
+		*dest[10].(**time.Time) = m.DeletedAt
 		return nil
 	}
 }
@@ -45,8 +46,11 @@ func TestMessageRepository_Create_Success(t *testing.T) {
 	pool := new(testutil.MockPool)
 	repo := newMessageRepo(pool)
 
-	pool.On("Exec", mock.Anything, mock.AnythingOfType("string"), mock.Anything).
-		Return(pgconn.NewCommandTag("INSERT 1"), nil)
+	pool.On("QueryRow", mock.Anything, mock.AnythingOfType("string"), mock.Anything).
+		Return(testutil.NewMockRow(func(dest ...any) error {
+			*dest[0].(*int64) = 7
+			return nil
+		}))
 
 	content := "Hello"
 	msg := &models.Message{
@@ -55,14 +59,15 @@ func TestMessageRepository_Create_Success(t *testing.T) {
 	}
 	err := repo.Create(context.Background(), msg)
 	require.NoError(t, err)
+	assert.Equal(t, int64(7), msg.Seq)
 }
 
 func TestMessageRepository_Create_DBError(t *testing.T) {
 	pool := new(testutil.MockPool)
 	repo := newMessageRepo(pool)
 
-	pool.On("Exec", mock.Anything, mock.AnythingOfType("string"), mock.Anything).
-		Return(pgconn.NewCommandTag(""), errors.New("db error"))
+	pool.On("QueryRow", mock.Anything, mock.AnythingOfType("string"), mock.Anything).
+		Return(testutil.ErrRow(errors.New("db error")))
 
 	content := "Hello"
 	err := repo.Create(context.Background(), &models.Message{
@@ -271,3 +276,44 @@ func TestMessageRepository_GetUnreadCount_IncludesViewerFilter(t *testing.T) {
 	assert.Contains(t, capturedSQL, "deleted_for_user_ids",
 		"GetUnreadCount must skip per-user-deleted rows so badge matches list")
 }
+
+func TestMessageRepository_List_SeqCursors(t *testing.T) {
+	seq := int64(41)
+	tests := []struct {
+		name   string
+		filter models.GetMessagesFilter
+		clause string
+		args   []any
+	}{
+		{
+			name:   "after seq oldest first",
+			filter: models.GetMessagesFilter{ConversationID: "conv-1", ViewerID: "user-1", AfterSeq: &seq, Limit: 50, Offset: 10},
+			clause: "AND seq > $3 ORDER BY seq ASC LIMIT $4",
+			args:   []any{"conv-1", "user-1", seq, 50},
+		},
+		{
+			name:   "before seq newest first",
+			filter: models.GetMessagesFilter{ConversationID: "conv-1", ViewerID: "user-1", BeforeSeq: &seq, Limit: 50, Offset: 10},
+			clause: "AND seq < $3 ORDER BY seq DESC LIMIT $4",
+			args:   []any{"conv-1", "user-1", seq, 50},
+		},
+		{
+			name:   "offset paging",
+			filter: models.GetMessagesFilter{ConversationID: "conv-1", ViewerID: "user-1", Limit: 50, Offset: 10},
+			clause: "ORDER BY seq DESC LIMIT $3 OFFSET $4",
+			args:   []any{"conv-1", "user-1", 50, 10},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := new(testutil.MockPool)
+			pages := capture(pool, "Query")
+
+			_, err := newMessageRepo(pool).List(context.Background(), &tt.filter)
+			require.Error(t, err)
+
+			assert.Contains(t, (*pages)[0].sql, tt.clause)
+			assert.Equal(t, tt.args, (*pages)[0].args)
+		})
+	}
+}
@@ -117,7 +117,7 @@ func (r *mfaRepository) GetFactorByID(ctx context.Context, factorID string) (*mo
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("MFA factor not found")
+			return nil, newNotFoundError("MFA factor not found")
 		}
 		return nil, fmt.Errorf("failed to get MFA factor: %w", err)
 	}
@@ -191,7 +191,7 @@ func (r *mfaRepository) UpdateFactorStatus(ctx context.Context, factorID, status
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("MFA factor not found")
+		return newNotFoundError("MFA factor not found")
 	}
 
 	return nil
@@ -211,7 +211,7 @@ func (r *mfaRepository) DeleteFactor(ctx context.Context, factorID string) error
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("MFA factor not found")
+		return newNotFoundError("MFA factor not found")
 	}
 
 	return nil
@@ -271,7 +271,7 @@ func (r *mfaRepository) GetBackupCode(ctx context.Context, userID, code string)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("backup code not found")
+			return nil, newNotFoundError("backup code not found")
 		}
 		return nil, fmt.Errorf("failed to get backup code: %w", err)
 	}
@@ -294,7 +294,7 @@ func (r *mfaRepository) MarkBackupCodeAsUsed(ctx context.Context, codeID string)
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("backup code not found")
+		return newNotFoundError("backup code not found")
 	}
 
 	return nil
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// MutedTermRepository stores the keywords and hashtags users muted.
+type MutedTermRepository interface {
+	// Create inserts a muted term; ErrMutedTermExists if the user already
+	// muted it.
+	Create(ctx context.Context, term *models.MutedTerm) error
+
+	// Delete removes one of the user's muted terms; ErrMutedTermNotFound
+	// when the user has no such term.
+	Delete(ctx context.Context, userID, termID string) error
+
+	// ListByUser returns the user's muted terms, newest first.
+	ListByUser(ctx context.Context, userID string) ([]*models.MutedTerm, error)
+
+	// CountByUser returns how many terms the user muted.
+	CountByUser(ctx context.Context, userID string) (int, error)
+}
+
+type mutedTermRepository struct {
+	db *database.DB
+}
+
+// NewMutedTermRepository wires a new muted term repository.
+func NewMutedTermRepository(db *database.DB) MutedTermRepository {
+	return &mutedTermRepository{db: db}
+}
+
+var (
+	// ErrMutedTermExists is returned when the user already muted the term.
+	ErrMutedTermExists = newConflictError("term already muted")
+	// ErrMutedTermNotFound is returned when a term id isn't the user's.
+	ErrMutedTermNotFound = newNotFoundError("muted term not found")
+)
+
+func (r *mutedTermRepository) Create(ctx context.Context, term *models.MutedTerm) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO muted_terms (user_id, term)
+		VALUES ($1, $2)
+		RETURNING id, created_at
+	`, term.UserID, term.Term).Scan(&term.ID, &term.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrMutedTermExists
+		}
+		return fmt.Errorf("create muted term: %w", err)
+	}
+	return nil
+}
+
+func (r *mutedTermRepository) Delete(ctx context.Context, userID, termID string) error {
+	tag, err := r.db.Pool.Exec(ctx,
+		`DELETE FROM muted_terms WHERE id = $1 AND user_id = $2`, termID, userID)
+	if err != nil {
+		return fmt.Errorf("delete muted term: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrMutedTermNotFound
+	}
+	return nil
+}
+
+func (r *mutedTermRepository) ListByUser(ctx context.Context, userID string) ([]*models.MutedTerm, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT id, user_id, term, created_at
+		FROM muted_terms
+		WHERE user_id = $1
+		ORDER BY created_at DESC, term
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list muted terms: %w", err)
+	}
+	defer rows.Close()
+
+	terms := []*models.MutedTerm{}
+	for rows.Next() {
+		t := &models.MutedTerm{}
+		if err := rows.Scan(&t.ID, &t.UserID, &t.Term, &t.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan muted term: %w", err)
+		}
+		terms = append(terms, t)
+	}
+	return terms, rows.Err()
+}
+
+func (r *mutedTermRepository) CountByUser(ctx context.Context, userID string) (int, error) {
+	var n int
+	if err := r.db.Pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM muted_terms WHERE user_id = $1`, userID,
+	).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count muted terms: %w", err)
+	}
+	return n, nil
+}
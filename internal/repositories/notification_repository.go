@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/pkg/database"
@@ -23,11 +24,27 @@ type NotificationRepository interface {
 	// notifications for a conversation as read. Returns rows updated.
 	MarkMessageNotificationsReadByConversation(ctx context.Context, userID, conversationID string) (int64, error)
 	Delete(ctx context.Context, notificationID string) error
+	// DeleteForUser bulk-deletes a user's notifications (all, or only read
+	// ones) and returns how many were removed.
+	DeleteForUser(ctx context.Context, filter *models.ClearNotificationsFilter) (int64, error)
+	// DeleteOlderThan prunes notifications created before cutoff, for every
+	// user, in batches. Returns rows deleted.
+	DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error)
 
 	// Unread count. When businessID is set, count only notifications for that business.
 	GetUnreadCount(ctx context.Context, userID string, businessID *string) (int, error)
 }
 
+// notificationPruneBatch bounds each DELETE of the retention job so a large
+// backlog doesn't hold locks on the table for the whole run.
+const notificationPruneBatch = 5000
+
+// userFeedScope is the business scoping of the personal (user-level) feed:
+// user-level notifications plus NEW_POST and MESSAGE even when stamped with a
+// business_id — owners must see DMs to their business in the personal bell,
+// otherwise they only surface inside the business notification page.
+const userFeedScope = " AND (data->>'business_id' IS NULL OR data->>'business_id' = '' OR type IN ('NEW_POST', 'MESSAGE'))"
+
 type notificationRepository struct {
 	db *database.DB
 }
@@ -46,8 +63,8 @@ func (r *notificationRepository) Create(ctx context.Context, notification *model
 
 	query := `
 		INSERT INTO notifications (
-			id, user_id, type, title, message, data, read, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			id, user_id, type, title, message, data, read, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
 	`
 
 	_, err = r.db.Pool.Exec(ctx, query,
@@ -64,6 +81,7 @@ func (r *notificationRepository) Create(ctx context.Context, notification *model
 	if err != nil {
 		return fmt.Errorf("failed to create notification: %w", err)
 	}
+	notification.UpdatedAt = notification.CreatedAt
 
 	return nil
 }
@@ -71,7 +89,7 @@ func (r *notificationRepository) Create(ctx context.Context, notification *model
 // GetByID retrieves a notification by ID
 func (r *notificationRepository) GetByID(ctx context.Context, notificationID string) (*models.Notification, error) {
 	query := `
-		SELECT id, user_id, type, title, message, data, read, created_at
+		SELECT id, user_id, type, title, message, data, read, created_at, updated_at
 		FROM notifications
 		WHERE id = $1
 	`
@@ -88,11 +106,12 @@ func (r *notificationRepository) GetByID(ctx context.Context, notificationID str
 		&dataJSON,
 		&notification.Read,
 		&notification.CreatedAt,
+		&notification.UpdatedAt,
 	)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("notification not found")
+			return nil, newNotFoundError("notification not found")
 		}
 		return nil, fmt.Errorf("failed to get notification: %w", err)
 	}
@@ -111,7 +130,7 @@ func (r *notificationRepository) GetByID(ctx context.Context, notificationID str
 func (r *notificationRepository) List(ctx context.Context, filter *models.GetNotificationsFilter) ([]*models.Notification, error) {
 	queryBuilder := strings.Builder{}
 	queryBuilder.WriteString(`
-		SELECT id, user_id, type, title, message, data, read, created_at
+		SELECT id, user_id, type, title, message, data, read, created_at, updated_at
 		FROM notifications
 		WHERE user_id = $1
 	`)
@@ -120,9 +139,13 @@ func (r *notificationRepository) List(ctx context.Context, filter *models.GetNot
 	argCount := 2
 
 	// Apply type filter
-	if filter.Type != nil {
-		fmt.Fprintf(&queryBuilder, " AND type = $%d", argCount)
-		args = append(args, *filter.Type)
+	if len(filter.Types) > 0 {
+		types := make([]string, len(filter.Types))
+		for i, t := range filter.Types {
+			types[i] = string(t)
+		}
+		fmt.Fprintf(&queryBuilder, " AND type = ANY($%d)", argCount)
+		args = append(args, types)
 		argCount++
 	}
 
@@ -132,23 +155,38 @@ func (r *notificationRepository) List(ctx context.Context, filter *models.GetNot
 	}
 
 	// Business scope: when filter.BusinessID is set, only that business's notifications;
-	// when not set (user feed), show user-level notifications PLUS NEW_POST and MESSAGE
-	// even when stamped with business_id — owners must see DMs to their business in the
-	// personal bell, otherwise they only surface inside the business notification page.
+	// when not set (user feed), see userFeedScope.
 	if filter.BusinessID != nil && *filter.BusinessID != "" {
 		fmt.Fprintf(&queryBuilder, " AND data->>'business_id' = $%d", argCount)
 		args = append(args, *filter.BusinessID)
 		argCount++
 	} else {
-		queryBuilder.WriteString(" AND (data->>'business_id' IS NULL OR data->>'business_id' = '' OR type IN ('NEW_POST', 'MESSAGE'))")
+		queryBuilder.WriteString(userFeedScope)
 	}
 
-	// Order by created_at DESC
-	queryBuilder.WriteString(" ORDER BY created_at DESC")
+	// Delta sync walks changes oldest first from UpdatedAfter; otherwise
+	// keyset pagination uses the created_at of the last row seen.
+	if filter.UpdatedAfter != nil {
+		fmt.Fprintf(&queryBuilder, " AND updated_at > $%d ORDER BY updated_at ASC", argCount)
+		args = append(args, *filter.UpdatedAfter)
+		argCount++
+	} else {
+		if filter.Cursor != nil {
+			fmt.Fprintf(&queryBuilder, " AND created_at < $%d", argCount)
+			args = append(args, *filter.Cursor)
+			argCount++
+		}
+		queryBuilder.WriteString(" ORDER BY created_at DESC")
+	}
 
 	// Pagination
-	fmt.Fprintf(&queryBuilder, " LIMIT $%d OFFSET $%d", argCount, argCount+1)
-	args = append(args, filter.Limit, filter.Offset)
+	if filter.Cursor != nil || filter.UpdatedAfter != nil {
+		fmt.Fprintf(&queryBuilder, " LIMIT $%d", argCount)
+		args = append(args, filter.Limit)
+	} else {
+		fmt.Fprintf(&queryBuilder, " LIMIT $%d OFFSET $%d", argCount, argCount+1)
+		args = append(args, filter.Limit, filter.Offset)
+	}
 
 	rows, err := r.db.Pool.Query(ctx, queryBuilder.String(), args...)
 	if err != nil {
@@ -170,6 +208,7 @@ func (r *notificationRepository) List(ctx context.Context, filter *models.GetNot
 			&dataJSON,
 			&notification.Read,
 			&notification.CreatedAt,
+			&notification.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan notification: %w", err)
@@ -206,7 +245,7 @@ func (r *notificationRepository) MarkAsRead(ctx context.Context, notificationID
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("notification not found")
+		return newNotFoundError("notification not found")
 	}
 
 	return nil
@@ -259,12 +298,60 @@ func (r *notificationRepository) Delete(ctx context.Context, notificationID stri
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("notification not found")
+		return newNotFoundError("notification not found")
 	}
 
 	return nil
 }
 
+// DeleteForUser deletes the user's notifications in the filter's business
+// scope, optionally only those already read.
+func (r *notificationRepository) DeleteForUser(ctx context.Context, filter *models.ClearNotificationsFilter) (int64, error) {
+	query := `DELETE FROM notifications WHERE user_id = $1`
+	args := []interface{}{filter.UserID}
+
+	if filter.ReadOnly {
+		query += " AND read = true"
+	}
+	if filter.BusinessID != nil && *filter.BusinessID != "" {
+		query += " AND data->>'business_id' = $2"
+		args = append(args, *filter.BusinessID)
+	} else {
+		query += userFeedScope
+	}
+
+	result, err := r.db.Pool.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear notifications: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+// DeleteOlderThan deletes notifications created before cutoff,
+// notificationPruneBatch rows at a time, until none are left.
+func (r *notificationRepository) DeleteOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := `
+		DELETE FROM notifications
+		WHERE id IN (
+			SELECT id FROM notifications
+			WHERE created_at < $1
+			LIMIT $2
+		)
+	`
+
+	var total int64
+	for {
+		result, err := r.db.Pool.Exec(ctx, query, cutoff, notificationPruneBatch)
+		if err != nil {
+			return total, fmt.Errorf("failed to prune notifications: %w", err)
+		}
+		total += result.RowsAffected()
+		if result.RowsAffected() < notificationPruneBatch {
+			return total, nil
+		}
+	}
+}
+
 // GetUnreadCount gets the count of unread notifications for a user.
 // When businessID is set, counts only notifications for that business.
 // When businessID is nil, counts user-level and NEW_POST (so badge matches main list including "X posted").
@@ -284,8 +371,7 @@ func (r *notificationRepository) GetUnreadCount(ctx context.Context, userID stri
 			SELECT COUNT(*)
 			FROM notifications
 			WHERE user_id = $1 AND read = false
-			  AND (data->>'business_id' IS NULL OR data->>'business_id' = '' OR type IN ('NEW_POST', 'MESSAGE'))
-		`
+		` + userFeedScope
 		args = []interface{}{userID}
 	}
 
@@ -220,3 +220,77 @@ func TestNotificationRepository_GetUnreadCount(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestNotificationRepository_List_TypesAndCursor(t *testing.T) {
+	pool := new(testutil.MockPool)
+	pages := capture(pool, "Query")
+	cursor := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := newNotifRepo(pool).List(context.Background(), &models.GetNotificationsFilter{
+		UserID: "user-1",
+		Types:  []models.NotificationType{models.NotificationTypeLike, models.NotificationTypeComment},
+		Cursor: &cursor,
+		Limit:  20,
+		Offset: 40,
+	})
+	require.Error(t, err)
+
+	sql := (*pages)[0].sql
+	assert.Contains(t, sql, "WHERE user_id = $1 AND type = ANY($2)")
+	assert.Contains(t, sql, "AND created_at < $3 ORDER BY created_at DESC LIMIT $4")
+	assert.NotContains(t, sql, "OFFSET")
+	assert.Equal(t, []any{"user-1", []string{"LIKE", "COMMENT"}, cursor, 20}, (*pages)[0].args)
+}
+
+func TestNotificationRepository_List_UpdatedAfter(t *testing.T) {
+	pool := new(testutil.MockPool)
+	pages := capture(pool, "Query")
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := newNotifRepo(pool).List(context.Background(), &models.GetNotificationsFilter{
+		UserID:       "user-1",
+		UpdatedAfter: &since,
+		Cursor:       &since,
+		Limit:        50,
+		Offset:       40,
+	})
+	require.Error(t, err)
+
+	sql := (*pages)[0].sql
+	assert.Contains(t, sql, "AND updated_at > $2 ORDER BY updated_at ASC LIMIT $3")
+	assert.NotContains(t, sql, "created_at <")
+	assert.NotContains(t, sql, "OFFSET")
+	assert.Equal(t, []any{"user-1", since, 50}, (*pages)[0].args)
+}
+
+func TestNotificationRepository_DeleteForUser(t *testing.T) {
+	pool := new(testutil.MockPool)
+	var sql string
+	pool.On("Exec", mock.Anything, mock.AnythingOfType("string"), []any{"user-1", "biz-1"}).
+		Run(func(a mock.Arguments) { sql = compactSQL(a.String(1)) }).
+		Return(pgconn.NewCommandTag("DELETE 7"), nil)
+
+	biz := "biz-1"
+	n, err := newNotifRepo(pool).DeleteForUser(context.Background(), &models.ClearNotificationsFilter{
+		UserID: "user-1", ReadOnly: true, BusinessID: &biz,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), n)
+	assert.Equal(t, "DELETE FROM notifications WHERE user_id = $1 AND read = true AND data->>'business_id' = $2", sql)
+}
+
+func TestNotificationRepository_DeleteOlderThan_Batches(t *testing.T) {
+	pool := new(testutil.MockPool)
+	cutoff := time.Now().AddDate(0, 0, -90)
+	pool.On("Exec", mock.Anything, mock.AnythingOfType("string"), []any{cutoff, 5000}).
+		Return(pgconn.NewCommandTag("DELETE 5000"), nil).Once()
+	pool.On("Exec", mock.Anything, mock.AnythingOfType("string"), []any{cutoff, 5000}).
+		Return(pgconn.NewCommandTag("DELETE 12"), nil).Once()
+
+	n, err := newNotifRepo(pool).DeleteOlderThan(context.Background(), cutoff)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(5012), n)
+	pool.AssertNumberOfCalls(t, "Exec", 2)
+}
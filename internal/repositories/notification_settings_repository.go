@@ -92,7 +92,7 @@ func (r *notificationSettingsRepository) GetByProfileAndCategory(ctx context.Con
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("notification setting not found")
+			return nil, newNotFoundError("notification setting not found")
 		}
 		return nil, fmt.Errorf("failed to get notification setting: %w", err)
 	}
@@ -142,7 +142,7 @@ func (r *notificationSettingsRepository) UpdateCategory(ctx context.Context, pro
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("notification setting not found")
+		return newNotFoundError("notification setting not found")
 	}
 
 	return nil
@@ -172,6 +172,7 @@ func (r *notificationSettingsRepository) InitializeDefaults(ctx context.Context,
 		models.NotificationCategoryEvents,
 		models.NotificationCategorySales,
 		models.NotificationCategoryBusiness,
+		models.NotificationCategoryTips,
 	}
 
 	query := `
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// OAuthRevocationRepository tracks accounts that must sign in with their
+// OAuth provider again after the provider revoked our access.
+type OAuthRevocationRepository interface {
+	// Mark records (or refreshes) the revocation for the user.
+	Mark(ctx context.Context, userID, provider, reason string) error
+	// Get returns the user's revocation; ErrOAuthRevocationNotFound when
+	// there is none.
+	Get(ctx context.Context, userID string) (*models.OAuthRevocation, error)
+	// Clear removes the user's revocation. Reports whether there was one.
+	Clear(ctx context.Context, userID string) (bool, error)
+}
+
+type oauthRevocationRepository struct {
+	db *database.DB
+}
+
+// NewOAuthRevocationRepository wires a new OAuth revocation repository.
+func NewOAuthRevocationRepository(db *database.DB) OAuthRevocationRepository {
+	return &oauthRevocationRepository{db: db}
+}
+
+// ErrOAuthRevocationNotFound is returned when the user has no pending
+// revocation.
+var ErrOAuthRevocationNotFound = newNotFoundError("oauth revocation not found")
+
+func (r *oauthRevocationRepository) Mark(ctx context.Context, userID, provider, reason string) error {
+	if _, err := r.db.Pool.Exec(ctx, `
+		INSERT INTO oauth_revocations (user_id, provider, reason)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET provider = EXCLUDED.provider, reason = EXCLUDED.reason, revoked_at = NOW()
+	`, userID, provider, reason); err != nil {
+		return fmt.Errorf("mark oauth revocation: %w", err)
+	}
+	return nil
+}
+
+func (r *oauthRevocationRepository) Get(ctx context.Context, userID string) (*models.OAuthRevocation, error) {
+	rev := &models.OAuthRevocation{}
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT user_id::text, provider, reason, revoked_at
+		FROM oauth_revocations
+		WHERE user_id = $1
+	`, userID).Scan(&rev.UserID, &rev.Provider, &rev.Reason, &rev.RevokedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrOAuthRevocationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get oauth revocation: %w", err)
+	}
+	return rev, nil
+}
+
+func (r *oauthRevocationRepository) Clear(ctx context.Context, userID string) (bool, error) {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM oauth_revocations WHERE user_id = $1`, userID)
+	if err != nil {
+		return false, fmt.Errorf("clear oauth revocation: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
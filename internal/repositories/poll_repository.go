@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -27,12 +28,24 @@ type PollRepository interface {
 
 	// User Votes
 	VotePoll(ctx context.Context, vote *models.UserPoll) error
+	// ChangeVote moves the user's live vote to newOptionID in a transaction
+	// holding the vote row lock; the vote-count trigger moves the count in
+	// the same transaction. Returns ErrPollVoteNotFound when the user hasn't
+	// voted and ErrPollOptionNotFound when the option isn't a live option of
+	// the poll.
 	ChangeVote(ctx context.Context, userID, pollID, newOptionID string) error
 	DeleteVote(ctx context.Context, userID, pollID string) error
 	GetUserVote(ctx context.Context, userID, pollID string) (*models.UserPoll, error)
 	HasUserVoted(ctx context.Context, userID, pollID string) (bool, error)
 }
 
+var (
+	// ErrPollVoteNotFound is returned when the user has no live vote.
+	ErrPollVoteNotFound = newNotFoundError("poll vote not found")
+	// ErrPollOptionNotFound is returned when an option isn't part of the poll.
+	ErrPollOptionNotFound = newNotFoundError("poll option not found")
+)
+
 type pollRepository struct {
 	db *database.DB
 }
@@ -45,13 +58,19 @@ func NewPollRepository(db *database.DB) PollRepository {
 // Create creates a new poll
 func (r *pollRepository) Create(ctx context.Context, poll *models.Poll) error {
 	query := `
-		INSERT INTO polls (id, post_id, created_at, updated_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO polls (id, post_id, results_visibility, closes_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
 	`
 
+	visibility := poll.ResultsVisibility
+	if visibility == "" {
+		visibility = models.PollResultsAlways
+	}
 	_, err := r.db.Pool.Exec(ctx, query,
 		poll.ID,
 		poll.PostID,
+		visibility,
+		poll.ClosesAt,
 		poll.CreatedAt,
 		poll.UpdatedAt,
 	)
@@ -62,7 +81,7 @@ func (r *pollRepository) Create(ctx context.Context, poll *models.Poll) error {
 // GetByID gets a poll by ID
 func (r *pollRepository) GetByID(ctx context.Context, pollID string) (*models.Poll, error) {
 	query := `
-		SELECT id, post_id, created_at, updated_at, deleted_at
+		SELECT id, post_id, results_visibility, closes_at, created_at, updated_at, deleted_at
 		FROM polls
 		WHERE id = $1 AND deleted_at IS NULL
 	`
@@ -71,13 +90,15 @@ func (r *pollRepository) GetByID(ctx context.Context, pollID string) (*models.Po
 	err := r.db.Pool.QueryRow(ctx, query, pollID).Scan(
 		&poll.ID,
 		&poll.PostID,
+		&poll.ResultsVisibility,
+		&poll.ClosesAt,
 		&poll.CreatedAt,
 		&poll.UpdatedAt,
 		&poll.DeletedAt,
 	)
 
 	if err == pgx.ErrNoRows {
-		return nil, fmt.Errorf("poll not found")
+		return nil, newNotFoundError("poll not found")
 	}
 
 	return poll, err
@@ -86,7 +107,7 @@ func (r *pollRepository) GetByID(ctx context.Context, pollID string) (*models.Po
 // GetByPostID gets a poll by post ID
 func (r *pollRepository) GetByPostID(ctx context.Context, postID string) (*models.Poll, error) {
 	query := `
-		SELECT id, post_id, created_at, updated_at, deleted_at
+		SELECT id, post_id, results_visibility, closes_at, created_at, updated_at, deleted_at
 		FROM polls
 		WHERE post_id = $1 AND deleted_at IS NULL
 	`
@@ -95,13 +116,15 @@ func (r *pollRepository) GetByPostID(ctx context.Context, postID string) (*model
 	err := r.db.Pool.QueryRow(ctx, query, postID).Scan(
 		&poll.ID,
 		&poll.PostID,
+		&poll.ResultsVisibility,
+		&poll.ClosesAt,
 		&poll.CreatedAt,
 		&poll.UpdatedAt,
 		&poll.DeletedAt,
 	)
 
 	if err == pgx.ErrNoRows {
-		return nil, fmt.Errorf("poll not found")
+		return nil, newNotFoundError("poll not found")
 	}
 
 	return poll, err
@@ -192,7 +215,7 @@ func (r *pollRepository) GetOptionByID(ctx context.Context, optionID string) (*m
 	)
 
 	if err == pgx.ErrNoRows {
-		return nil, fmt.Errorf("poll option not found")
+		return nil, newNotFoundError("poll option not found")
 	}
 
 	return option, err
@@ -242,24 +265,45 @@ func (r *pollRepository) VotePoll(ctx context.Context, vote *models.UserPoll) er
 	return err
 }
 
-// ChangeVote changes a user's vote
+// ChangeVote changes a user's vote. Locking the vote row first serialises
+// concurrent changes by the same user, so each sees the option the previous
+// one left and the trigger never decrements an option twice.
 func (r *pollRepository) ChangeVote(ctx context.Context, userID, pollID, newOptionID string) error {
-	query := `
-		UPDATE user_polls
-		SET poll_option_id = $3
-		WHERE user_id = $1 AND poll_id = $2 AND deleted_at IS NULL
-	`
-
-	result, err := r.db.Pool.Exec(ctx, query, userID, pollID, newOptionID)
-	if err != nil {
-		return err
-	}
-
-	if result.RowsAffected() == 0 {
-		return fmt.Errorf("vote not found")
-	}
+	return r.db.WithTransaction(ctx, func(tx pgx.Tx) error {
+		var voteID, currentOptionID string
+		err := tx.QueryRow(ctx, `
+			SELECT id, poll_option_id
+			FROM user_polls
+			WHERE user_id = $1 AND poll_id = $2 AND deleted_at IS NULL
+			FOR UPDATE
+		`, userID, pollID).Scan(&voteID, &currentOptionID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrPollVoteNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("lock poll vote: %w", err)
+		}
+		if currentOptionID == newOptionID {
+			return nil
+		}
 
-	return nil
+		result, err := tx.Exec(ctx, `
+			UPDATE user_polls
+			SET poll_option_id = $2
+			WHERE id = $1
+			  AND EXISTS (
+				SELECT 1 FROM poll_options
+				WHERE id = $2 AND poll_id = $3 AND deleted_at IS NULL
+			  )
+		`, voteID, newOptionID, pollID)
+		if err != nil {
+			return fmt.Errorf("change poll vote: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return ErrPollOptionNotFound
+		}
+		return nil
+	})
 }
 
 // DeleteVote deletes a user's vote
@@ -3,9 +3,11 @@ package repositories_test
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -52,9 +54,11 @@ func TestPollRepository_GetByID_Success(t *testing.T) {
 		Return(testutil.NewMockRow(func(dest ...any) error {
 			*dest[0].(*string) = "poll-1"
 			*dest[1].(*string) = "post-1"
-			*dest[2].(*time.Time) = now
-			*dest[3].(*time.Time) = now
-			*dest[4].(**time.Time) = nil
+			*dest[2].(*models.PollResultsVisibility) = models.PollResultsAfterVote
+			*dest[3].(**time.Time) = nil
+			*dest[4].(*time.Time) = now
+			*dest[5].(*time.Time) = now
+			*dest[6].(**time.Time) = nil
 			return nil
 		}))
 
@@ -62,6 +66,7 @@ func TestPollRepository_GetByID_Success(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "poll-1", poll.ID)
 	assert.Equal(t, "post-1", poll.PostID)
+	assert.Equal(t, models.PollResultsAfterVote, poll.ResultsVisibility)
 }
 
 func TestPollRepository_GetByID_NotFound(t *testing.T) {
@@ -174,3 +179,73 @@ func TestPollRepository_UpdateOptionVoteCount_Success(t *testing.T) {
 	err := repo.UpdateOptionVoteCount(context.Background(), "opt-1", 1)
 	require.NoError(t, err)
 }
+
+func TestPollRepository_ChangeVote(t *testing.T) {
+	lockedRow := func(optionID string) *testutil.MockRow {
+		return testutil.NewMockRow(func(dest ...any) error {
+			*dest[0].(*string) = "vote-1"
+			*dest[1].(*string) = optionID
+			return nil
+		})
+	}
+
+	t.Run("moves the locked vote", func(t *testing.T) {
+		pool := new(testutil.MockPool)
+		tx := new(testutil.MockTx)
+		repo := newPollRepo(pool)
+
+		pool.On("Begin", mock.Anything).Return(tx, nil)
+		tx.On("QueryRow", mock.Anything, mock.MatchedBy(func(sql string) bool {
+			return strings.Contains(sql, "FOR UPDATE")
+		}), mock.Anything).Return(lockedRow("opt-1"))
+		tx.On("Exec", mock.Anything, mock.AnythingOfType("string"), []any{"vote-1", "opt-2", "poll-1"}).
+			Return(pgconn.NewCommandTag("UPDATE 1"), nil)
+		tx.On("Commit", mock.Anything).Return(nil)
+
+		err := repo.ChangeVote(context.Background(), "user-1", "poll-1", "opt-2")
+		require.NoError(t, err)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("same option is a no-op", func(t *testing.T) {
+		pool := new(testutil.MockPool)
+		tx := new(testutil.MockTx)
+		repo := newPollRepo(pool)
+
+		pool.On("Begin", mock.Anything).Return(tx, nil)
+		tx.On("QueryRow", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(lockedRow("opt-2"))
+		tx.On("Commit", mock.Anything).Return(nil)
+
+		err := repo.ChangeVote(context.Background(), "user-1", "poll-1", "opt-2")
+		require.NoError(t, err)
+		tx.AssertNotCalled(t, "Exec", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("no vote", func(t *testing.T) {
+		pool := new(testutil.MockPool)
+		tx := new(testutil.MockTx)
+		repo := newPollRepo(pool)
+
+		pool.On("Begin", mock.Anything).Return(tx, nil)
+		tx.On("QueryRow", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(testutil.ErrRow(pgx.ErrNoRows))
+		tx.On("Rollback", mock.Anything).Return(nil)
+
+		err := repo.ChangeVote(context.Background(), "user-1", "poll-1", "opt-2")
+		require.ErrorIs(t, err, repositories.ErrPollVoteNotFound)
+	})
+
+	t.Run("option not in poll", func(t *testing.T) {
+		pool := new(testutil.MockPool)
+		tx := new(testutil.MockTx)
+		repo := newPollRepo(pool)
+
+		pool.On("Begin", mock.Anything).Return(tx, nil)
+		tx.On("QueryRow", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(lockedRow("opt-1"))
+		tx.On("Exec", mock.Anything, mock.AnythingOfType("string"), mock.Anything).
+			Return(pgconn.NewCommandTag("UPDATE 0"), nil)
+		tx.On("Rollback", mock.Anything).Return(nil)
+
+		err := repo.ChangeVote(context.Background(), "user-1", "poll-1", "opt-9")
+		require.ErrorIs(t, err, repositories.ErrPollOptionNotFound)
+	})
+}
@@ -0,0 +1,239 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrBroadcastNotFound is returned when a post broadcast doesn't exist (or is
+// not in the state the update expects).
+var ErrBroadcastNotFound = newNotFoundError("post broadcast not found")
+
+// ErrBroadcastExists is returned when the post already has a broadcast that
+// wasn't rejected.
+var ErrBroadcastExists = newConflictError("post broadcast already exists")
+
+// PostBroadcastRepository persists geofenced post broadcasts and resolves
+// their recipients.
+type PostBroadcastRepository interface {
+	Create(ctx context.Context, b *models.PostBroadcast) error
+	GetByID(ctx context.Context, id string) (*models.PostBroadcast, error)
+	// GetLatestByPost returns the post's most recent broadcast, or
+	// ErrBroadcastNotFound.
+	GetLatestByPost(ctx context.Context, postID string) (*models.PostBroadcast, error)
+	// List returns admin queue rows (optionally filtered by status) plus total.
+	List(ctx context.Context, status *string, limit, offset int) ([]*models.PostBroadcastListItem, int, error)
+	// Review moves a PENDING_APPROVAL broadcast to QUEUED or REJECTED.
+	Review(ctx context.Context, id, reviewerID, status string, reason *string) error
+	// NextToSend returns the oldest QUEUED or SENDING broadcast, or nil when
+	// there is nothing to send.
+	NextToSend(ctx context.Context) (*models.PostBroadcast, error)
+	// RecordProgress marks a batch as handed to the notifier: the cursor
+	// moves to lastUserID and sent_count grows by sent.
+	RecordProgress(ctx context.Context, id, lastUserID string, sent int) error
+	// MarkSent finishes a broadcast.
+	MarkSent(ctx context.Context, id string) error
+
+	// CountRecipients counts active profiles whose saved location lies
+	// between excludeRadiusKm and radiusKm of the point, excluding the author.
+	CountRecipients(ctx context.Context, lat, lng, radiusKm, excludeRadiusKm float64, excludeUserID string) (int, error)
+	// RecipientsAfter returns the next batch of the broadcast's recipients in
+	// id order, starting after afterUserID (nil for the first batch).
+	RecipientsAfter(ctx context.Context, b *models.PostBroadcast, excludeUserID string, afterUserID *string, limit int) ([]string, error)
+}
+
+type postBroadcastRepository struct {
+	db *database.DB
+}
+
+// NewPostBroadcastRepository creates the repository.
+func NewPostBroadcastRepository(db *database.DB) PostBroadcastRepository {
+	return &postBroadcastRepository{db: db}
+}
+
+func (r *postBroadcastRepository) Create(ctx context.Context, b *models.PostBroadcast) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO post_broadcasts
+			(id, post_id, requested_by, radius_km, exclude_radius_km, latitude, longitude,
+			 status, estimated_recipients, max_recipients)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING created_at, updated_at
+	`, b.ID, b.PostID, b.RequestedBy, b.RadiusKm, b.ExcludeRadiusKm, b.Latitude, b.Longitude,
+		b.Status, b.EstimatedRecipients, b.MaxRecipients,
+	).Scan(&b.CreatedAt, &b.UpdatedAt)
+	if err != nil && isUniqueViolation(err) {
+		return ErrBroadcastExists
+	}
+	return err
+}
+
+const broadcastColumns = `
+	id, post_id, requested_by, radius_km, exclude_radius_km, latitude, longitude,
+	status, estimated_recipients, max_recipients, sent_count, cursor_user_id,
+	rejection_reason, reviewed_by, reviewed_at, completed_at, created_at, updated_at`
+
+func broadcastScanTargets(b *models.PostBroadcast) []any {
+	return []any{
+		&b.ID, &b.PostID, &b.RequestedBy, &b.RadiusKm, &b.ExcludeRadiusKm, &b.Latitude, &b.Longitude,
+		&b.Status, &b.EstimatedRecipients, &b.MaxRecipients, &b.SentCount, &b.CursorUserID,
+		&b.RejectionReason, &b.ReviewedBy, &b.ReviewedAt, &b.CompletedAt, &b.CreatedAt, &b.UpdatedAt,
+	}
+}
+
+func scanBroadcast(row pgx.Row) (*models.PostBroadcast, error) {
+	b := &models.PostBroadcast{}
+	if err := row.Scan(broadcastScanTargets(b)...); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrBroadcastNotFound
+		}
+		return nil, err
+	}
+	return b, nil
+}
+
+func (r *postBroadcastRepository) GetByID(ctx context.Context, id string) (*models.PostBroadcast, error) {
+	return scanBroadcast(r.db.Pool.QueryRow(ctx, `
+		SELECT`+broadcastColumns+`
+		FROM post_broadcasts
+		WHERE id = $1
+	`, id))
+}
+
+func (r *postBroadcastRepository) GetLatestByPost(ctx context.Context, postID string) (*models.PostBroadcast, error) {
+	return scanBroadcast(r.db.Pool.QueryRow(ctx, `
+		SELECT`+broadcastColumns+`
+		FROM post_broadcasts
+		WHERE post_id = $1
+		ORDER BY created_at DESC
+		LIMIT 1
+	`, postID))
+}
+
+func (r *postBroadcastRepository) List(ctx context.Context, status *string, limit, offset int) ([]*models.PostBroadcastListItem, int, error) {
+	var total int
+	if err := r.db.Pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM post_broadcasts b
+		WHERE ($1::text IS NULL OR b.status = $1)
+	`, status).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT
+			b.id, b.post_id, b.requested_by, b.radius_km, b.exclude_radius_km, b.latitude, b.longitude,
+			b.status, b.estimated_recipients, b.max_recipients, b.sent_count, b.cursor_user_id,
+			b.rejection_reason, b.reviewed_by, b.reviewed_at, b.completed_at, b.created_at, b.updated_at,
+			p.type, p.title, p.description
+		FROM post_broadcasts b
+		JOIN posts p ON p.id = b.post_id
+		WHERE ($1::text IS NULL OR b.status = $1)
+		ORDER BY b.created_at ASC
+		LIMIT $2 OFFSET $3
+	`, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]*models.PostBroadcastListItem, 0, limit)
+	for rows.Next() {
+		item := &models.PostBroadcastListItem{}
+		targets := append(broadcastScanTargets(&item.PostBroadcast), &item.PostType, &item.PostTitle, &item.PostDescription)
+		if err := rows.Scan(targets...); err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+	}
+	return items, total, rows.Err()
+}
+
+func (r *postBroadcastRepository) Review(ctx context.Context, id, reviewerID, status string, reason *string) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE post_broadcasts
+		SET status = $2, rejection_reason = $3, reviewed_by = $4, reviewed_at = NOW()
+		WHERE id = $1 AND status = 'PENDING_APPROVAL'
+	`, id, status, reason, reviewerID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrBroadcastNotFound
+	}
+	return nil
+}
+
+func (r *postBroadcastRepository) NextToSend(ctx context.Context) (*models.PostBroadcast, error) {
+	b, err := scanBroadcast(r.db.Pool.QueryRow(ctx, `
+		SELECT`+broadcastColumns+`
+		FROM post_broadcasts
+		WHERE status IN ('QUEUED', 'SENDING')
+		ORDER BY created_at ASC
+		LIMIT 1
+	`))
+	if errors.Is(err, ErrBroadcastNotFound) {
+		return nil, nil
+	}
+	return b, err
+}
+
+func (r *postBroadcastRepository) RecordProgress(ctx context.Context, id, lastUserID string, sent int) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE post_broadcasts
+		SET status = 'SENDING', cursor_user_id = $2, sent_count = sent_count + $3
+		WHERE id = $1
+	`, id, lastUserID, sent)
+	return err
+}
+
+func (r *postBroadcastRepository) MarkSent(ctx context.Context, id string) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE post_broadcasts
+		SET status = 'SENT', completed_at = NOW()
+		WHERE id = $1
+	`, id)
+	return err
+}
+
+// recipientsWhere matches active profiles in the ring around ($2, $3): within
+// $4 metres but not within $5 metres (no inner ring when $5 is 0), excluding
+// the author ($1).
+const recipientsWhere = `
+	WHERE deleted_at IS NULL
+		AND id <> $1
+		AND location IS NOT NULL
+		AND ST_DWithin(location, ST_SetSRID(ST_MakePoint($2, $3), 4326)::geography, $4)
+		AND ($5::float8 = 0 OR NOT ST_DWithin(location, ST_SetSRID(ST_MakePoint($2, $3), 4326)::geography, $5))`
+
+func (r *postBroadcastRepository) CountRecipients(ctx context.Context, lat, lng, radiusKm, excludeRadiusKm float64, excludeUserID string) (int, error) {
+	var count int
+	err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM profiles`+recipientsWhere,
+		excludeUserID, lng, lat, radiusKm*1000, excludeRadiusKm*1000,
+	).Scan(&count)
+	return count, err
+}
+
+func (r *postBroadcastRepository) RecipientsAfter(ctx context.Context, b *models.PostBroadcast, excludeUserID string, afterUserID *string, limit int) ([]string, error) {
+	rows, err := r.db.Pool.Query(ctx, `SELECT id FROM profiles`+recipientsWhere+`
+		AND ($6::uuid IS NULL OR id > $6)
+		ORDER BY id
+		LIMIT $7
+	`, excludeUserID, b.Longitude, b.Latitude, b.RadiusKm*1000, b.ExcludeRadiusKm*1000, afterUserID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0, limit)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
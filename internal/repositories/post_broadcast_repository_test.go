@@ -0,0 +1,47 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/testutil"
+)
+
+func newPostBroadcastRepo(pool *testutil.MockPool) repositories.PostBroadcastRepository {
+	return repositories.NewPostBroadcastRepository(testutil.NewTestDB(pool))
+}
+
+func TestPostBroadcastRepository_RecipientsAfter_SQL(t *testing.T) {
+	pool := new(testutil.MockPool)
+	pages := capture(pool, "Query")
+	cursor := "user-100"
+	broadcast := &models.PostBroadcast{RadiusKm: 30, ExcludeRadiusKm: 10, Latitude: 34.5, Longitude: 69.2}
+
+	_, err := newPostBroadcastRepo(pool).RecipientsAfter(context.Background(), broadcast, "author-1", &cursor, 200)
+	require.Error(t, err)
+
+	sql := (*pages)[0].sql
+	assert.Contains(t, sql, "ST_DWithin(location, ST_SetSRID(ST_MakePoint($2, $3), 4326)::geography, $4)")
+	assert.Contains(t, sql, "($5::float8 = 0 OR NOT ST_DWithin(location, ST_SetSRID(ST_MakePoint($2, $3), 4326)::geography, $5))")
+	assert.Contains(t, sql, "AND ($6::uuid IS NULL OR id > $6) ORDER BY id LIMIT $7")
+	assert.Equal(t, []any{"author-1", 69.2, 34.5, 30000.0, 10000.0, &cursor, 200}, (*pages)[0].args)
+}
+
+func TestPostBroadcastRepository_CountRecipients(t *testing.T) {
+	pool := new(testutil.MockPool)
+	pool.On("QueryRow", mock.Anything, mock.Anything, []any{"author-1", 69.2, 34.5, 5000.0, 0.0}).
+		Return(testutil.NewMockRow(func(dest ...any) error {
+			*dest[0].(*int) = 42
+			return nil
+		}))
+
+	count, err := newPostBroadcastRepo(pool).CountRecipients(context.Background(), 34.5, 69.2, 5, 0, "author-1")
+	require.NoError(t, err)
+	assert.Equal(t, 42, count)
+}
@@ -2,7 +2,9 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
@@ -22,8 +24,14 @@ type PostRepository interface {
 	// idempotency token for the user, or (nil, nil) if none. Backs the
 	// idempotent create path for the mobile durable upload queue.
 	GetByClientToken(ctx context.Context, userID, clientToken string) (*models.Post, error)
+	// Update saves the post's editable fields if it is still at
+	// post.Version, bumping the version; ErrVersionConflict otherwise.
 	Update(ctx context.Context, post *models.Post) error
 	Delete(ctx context.Context, postID string) error
+	// FillAddress sets the post's empty country / province / district /
+	// neighborhood from a reverse-geocoded address. It's a no-op if the
+	// post has since moved away from (lat, lng).
+	FillAddress(ctx context.Context, postID string, lat, lng float64, addr *models.ResolvedAddress) error
 
 	// Attachments
 	CreateAttachment(ctx context.Context, attachment *models.Attachment) error
@@ -31,6 +39,15 @@ type PostRepository interface {
 	GetAttachmentsByPostIDs(ctx context.Context, postIDs []string) (map[string][]*models.Attachment, error)
 	DeleteAttachment(ctx context.Context, attachmentID string) error
 	DeleteAttachmentForPost(ctx context.Context, postID, attachmentID string) error
+	// ReorderAttachments sets each listed attachment's position to its index
+	// in attachmentIDs. IDs not on the post are ignored.
+	ReorderAttachments(ctx context.Context, postID string, attachmentIDs []string) error
+	// SetAttachmentCaption sets or (nil) clears a caption on the post's
+	// attachment.
+	SetAttachmentCaption(ctx context.Context, postID, attachmentID string, caption *string) error
+	// SetAttachmentAltText sets or (empty) clears the alt text stored in the
+	// attachment's photo.
+	SetAttachmentAltText(ctx context.Context, postID, attachmentID, altText string) error
 
 	// Likes
 	LikePost(ctx context.Context, userID, postID string) error
@@ -45,6 +62,12 @@ type PostRepository interface {
 	CountPostViews(ctx context.Context, postID string) (int, error)
 	// RecordPostView records a unique viewer for a post (idempotent per user).
 	RecordPostView(ctx context.Context, userID, postID string) error
+	// IncrementDailyViews counts one view in today's bucket for the post,
+	// unless userID is its author. Callers deduplicate per user per day.
+	IncrementDailyViews(ctx context.Context, userID, postID string) error
+	// GetViewCounts returns the total (daily-deduplicated) views per post.
+	// Posts without views are left out.
+	GetViewCounts(ctx context.Context, postIDs []string) (map[string]int, error)
 
 	// Bookmarks
 	BookmarkPost(ctx context.Context, userID, postID string) error
@@ -52,16 +75,38 @@ type PostRepository interface {
 	IsBookmarkedByUser(ctx context.Context, userID, postID string) (bool, error)
 	// GetBookmarkerIDs returns the ids of users who bookmarked the post.
 	GetBookmarkerIDs(ctx context.Context, postID string) ([]string, error)
+	// GetBookmarkCounts returns how many users bookmarked each post. Posts
+	// without bookmarks are left out.
+	GetBookmarkCounts(ctx context.Context, postIDs []string) (map[string]int, error)
+	// ClaimBookmarkMilestone records the highest of thresholds the post's
+	// bookmark count has reached and returns it, or 0 when that milestone
+	// was already recorded. Concurrent callers never claim the same one.
+	ClaimBookmarkMilestone(ctx context.Context, postID string, thresholds []int) (int, error)
 	GetUserBookmarks(ctx context.Context, userID string, limit, offset int) ([]*models.Post, error)
+	// GetUserBookmarksInCollection is GetUserBookmarks narrowed to one
+	// collection, or to unfiled bookmarks for models.BookmarkCollectionNone.
+	GetUserBookmarksInCollection(ctx context.Context, userID, collectionID string, limit, offset int) ([]*models.Post, error)
 	GetUserEventPosts(ctx context.Context, userID string, eventState models.EventInterestState, limit, offset int) ([]*models.Post, error)
 
 	// Shares
 	SharePost(ctx context.Context, share *models.PostShare) error
 	GetPostShares(ctx context.Context, postID string, limit, offset int) ([]*models.PostShare, error)
+	// RecordExternalShare bumps the post's external_shares counter and
+	// returns the sharer's short link for the channel, creating it with
+	// link.Code on first use. Returns ErrShareLinkCodeTaken when link.Code
+	// collides with another link.
+	RecordExternalShare(ctx context.Context, link *models.PostShareLink) (*models.PostShareLink, int, error)
+	// ResolveShareLink counts a click on the short link and returns it.
+	// Returns ErrShareLinkNotFound for unknown codes.
+	ResolveShareLink(ctx context.Context, code string) (*models.PostShareLink, error)
 
 	// Feed
 	GetFeed(ctx context.Context, filter *models.FeedFilter) ([]*models.Post, error)
 	CountFeed(ctx context.Context, filter *models.FeedFilter) (int64, error)
+	// RemovedPostIDs lists posts in filter's user, business or group scope
+	// that left the feed after since: deleted, deactivated or sold. Oldest
+	// first, at most limit.
+	RemovedPostIDs(ctx context.Context, filter *models.FeedFilter, since time.Time, limit int) ([]string, error)
 	GetUserPosts(ctx context.Context, userID string, limit, offset int) ([]*models.Post, error)
 	GetBusinessPosts(ctx context.Context, businessID string, limit, offset int) ([]*models.Post, error)
 
@@ -83,8 +128,30 @@ type PostRepository interface {
 	// Called after SELL_EXPIRED notifications have been sent so posts are hidden from feeds.
 	MarkSellPostsExpired(ctx context.Context, postIDs []string) error
 
-	// ReactivateSellPost sets status=true, sold=false, and resets expired_at to now+30 days.
-	ReactivateSellPost(ctx context.Context, postID string) error
+	// ReactivateSellPost sets status=true, sold=false, and resets expired_at to expiresAt.
+	ReactivateSellPost(ctx context.Context, postID string, expiresAt time.Time) error
+
+	// SetExpiry sets a post's expired_at; nil clears it.
+	SetExpiry(ctx context.Context, postID string, expiresAt *time.Time) error
+
+	// ArchiveExpiredPosts deactivates live EVENT and ALERT posts whose
+	// expired_at is at or before asOf (events are also marked ended) and
+	// returns how many it archived.
+	ArchiveExpiredPosts(ctx context.Context, asOf time.Time) (int64, error)
+
+	// ListUserListings returns the user's SELL posts in one listing state,
+	// with the total for paging. Expired listings that were relisted are
+	// left out; the relisted copy replaces them.
+	ListUserListings(ctx context.Context, userID string, state models.ListingState, limit, offset int) ([]*models.Post, int64, error)
+
+	// HasActionableReports reports whether the post has reports that are
+	// open or were upheld (anything but REJECTED).
+	HasActionableReports(ctx context.Context, postID string) (bool, error)
+
+	// RenewSellPost resets expired_at to expiresAt and bumps the post to
+	// the top of the recent feed, unless it was already bumped within
+	// cooldown. Returns false when the cooldown blocked it.
+	RenewSellPost(ctx context.Context, postID string, cooldown time.Duration, expiresAt time.Time) (bool, error)
 }
 
 // locationSelectFragment selects post location columns as four doubles instead
@@ -137,7 +204,9 @@ func (r *postRepository) Create(ctx context.Context, post *models.Post) error {
 			start_date, start_time, end_date, end_time, event_state, interested_count, going_count, expired_at,
 			address_location, user_location, country, province, district, neighborhood,
 			total_comments, total_likes, total_shares,
-			created_at, updated_at, client_token
+			created_at, updated_at, client_token, business_product_id, group_id,
+			lost_found_kind, item_description, last_seen_place, last_seen_at, urgency, lang, bumped_at,
+			business_location_id
 		) VALUES (
 			$1, $2, $3, $4, $5,
 			$6, $7, $8, $9, $10,
@@ -145,7 +214,9 @@ func (r *postRepository) Create(ctx context.Context, post *models.Post) error {
 			$20, $21, $22, $23, $24, $25, $26, $27,
 			ST_GeogFromText($28), ST_GeogFromText($29), $30, $31, $32, $33,
 			$34, $35, $36,
-			$37, $38, $39
+			$37, $38, $39, $40, $41,
+			$42, $43, $44, $45, $46, $47, $37,
+			$48
 		)
 	`
 
@@ -156,7 +227,9 @@ func (r *postRepository) Create(ctx context.Context, post *models.Post) error {
 		post.StartDate, post.StartTime, post.EndDate, post.EndTime, post.EventState, post.InterestedCount, post.GoingCount, post.ExpiredAt,
 		pointToWKT(post.AddressLocation), pointToWKT(post.UserLocation), post.Country, post.Province, post.District, post.Neighborhood,
 		post.TotalComments, post.TotalLikes, post.TotalShares,
-		post.CreatedAt, post.UpdatedAt, post.ClientToken,
+		post.CreatedAt, post.UpdatedAt, post.ClientToken, post.BusinessProductID, post.GroupID,
+		post.LostFoundKind, post.ItemDescription, post.LastSeenPlace, post.LastSeenAt, post.Urgency, post.Lang,
+		post.BranchID,
 	)
 
 	return err
@@ -192,7 +265,8 @@ func (r *postRepository) GetByID(ctx context.Context, postID string) (*models.Po
 			` + locationSelectFragment + `,
 			country, province, district, neighborhood,
 			total_comments, total_likes, total_shares,
-			created_at, updated_at, deleted_at
+			created_at, updated_at, deleted_at, group_id,
+			lost_found_kind, item_description, last_seen_place, last_seen_at, urgency, lang, bumped_at, version
 		FROM posts
 		WHERE id = $1 AND deleted_at IS NULL
 	`
@@ -207,14 +281,15 @@ func (r *postRepository) GetByID(ctx context.Context, postID string) (*models.Po
 		&addrLng, &addrLat, &userLng, &userLat,
 		&post.Country, &post.Province, &post.District, &post.Neighborhood,
 		&post.TotalComments, &post.TotalLikes, &post.TotalShares,
-		&post.CreatedAt, &post.UpdatedAt, &post.DeletedAt,
+		&post.CreatedAt, &post.UpdatedAt, &post.DeletedAt, &post.GroupID,
+		&post.LostFoundKind, &post.ItemDescription, &post.LastSeenPlace, &post.LastSeenAt, &post.Urgency, &post.Lang, &post.BumpedAt, &post.Version,
 	)
 	if err == nil {
 		scanPostLocations(float8ToFloat64(addrLng), float8ToFloat64(addrLat), float8ToFloat64(userLng), float8ToFloat64(userLat), post)
 	}
 
 	if err == pgx.ErrNoRows {
-		return nil, fmt.Errorf("post not found")
+		return nil, newNotFoundError("post not found")
 	}
 
 	return post, err
@@ -247,11 +322,19 @@ func (r *postRepository) Update(ctx context.Context, post *models.Post) error {
 			start_time = $15,
 			end_date = $16,
 			end_time = $17,
-			updated_at = $18
-		WHERE id = $1 AND deleted_at IS NULL
+			updated_at = $18,
+			lost_found_kind = $19,
+			item_description = $20,
+			last_seen_place = $21,
+			last_seen_at = $22,
+			urgency = $23,
+			lang = $24,
+			version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND version = $25
+		RETURNING version
 	`
 
-	_, err := r.db.Pool.Exec(ctx, query,
+	err := r.db.Pool.QueryRow(ctx, query,
 		post.ID,
 		post.Title,
 		post.Description,
@@ -270,8 +353,17 @@ func (r *postRepository) Update(ctx context.Context, post *models.Post) error {
 		post.EndDate,
 		post.EndTime,
 		time.Now(),
-	)
-
+		post.LostFoundKind,
+		post.ItemDescription,
+		post.LastSeenPlace,
+		post.LastSeenAt,
+		post.Urgency,
+		post.Lang,
+		post.Version,
+	).Scan(&post.Version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrVersionConflict
+	}
 	return err
 }
 
@@ -287,20 +379,43 @@ func (r *postRepository) Delete(ctx context.Context, postID string) error {
 	return err
 }
 
+// FillAddress backfills empty address text; the location trigger then
+// resolves province_id / district_id from it.
+func (r *postRepository) FillAddress(ctx context.Context, postID string, lat, lng float64, addr *models.ResolvedAddress) error {
+	query := `
+		UPDATE posts
+		SET country = COALESCE(NULLIF(country, ''), NULLIF($4, '')),
+			province = COALESCE(NULLIF(province, ''), NULLIF($5, '')),
+			district = COALESCE(NULLIF(district, ''), NULLIF($6, '')),
+			neighborhood = COALESCE(NULLIF(neighborhood, ''), NULLIF($7, ''))
+		WHERE id = $1 AND deleted_at IS NULL
+			AND ST_DWithin(address_location, ST_SetSRID(ST_MakePoint($2, $3), 4326)::geography, 1)
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, postID, lng, lat,
+		addr.Country, addr.Province, addr.District, addr.Neighborhood,
+	); err != nil {
+		return fmt.Errorf("fill post address: %w", err)
+	}
+	return nil
+}
+
 // CreateAttachment creates a new attachment AND auto-enqueues it for
 // media moderation. The enqueue is best-effort — a queue insert failure
 // must not block the attachment write (loss of moderation coverage on a
 // single row is acceptable; loss of the post is not).
 func (r *postRepository) CreateAttachment(ctx context.Context, attachment *models.Attachment) error {
 	query := `
-		INSERT INTO attachments (id, post_id, photo, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO attachments (id, post_id, photo, position, caption, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
 
 	if _, err := r.db.Pool.Exec(ctx, query,
 		attachment.ID,
 		attachment.PostID,
 		attachment.Photo,
+		attachment.Position,
+		attachment.Caption,
 		attachment.CreatedAt,
 		attachment.UpdatedAt,
 	); err != nil {
@@ -322,13 +437,13 @@ func (r *postRepository) CreateAttachment(ctx context.Context, attachment *model
 	return nil
 }
 
-// GetAttachmentsByPostID gets all attachments for a post
+// GetAttachmentsByPostID gets all attachments for a post, by position
 func (r *postRepository) GetAttachmentsByPostID(ctx context.Context, postID string) ([]*models.Attachment, error) {
 	query := `
-		SELECT id, post_id, photo, created_at, updated_at
+		SELECT id, post_id, photo, position, caption, created_at, updated_at
 		FROM attachments
 		WHERE post_id = $1 AND deleted_at IS NULL
-		ORDER BY created_at ASC
+		ORDER BY position ASC, created_at ASC
 	`
 
 	rows, err := r.db.Pool.Query(ctx, query, postID)
@@ -344,6 +459,8 @@ func (r *postRepository) GetAttachmentsByPostID(ctx context.Context, postID stri
 			&attachment.ID,
 			&attachment.PostID,
 			&attachment.Photo,
+			&attachment.Position,
+			&attachment.Caption,
 			&attachment.CreatedAt,
 			&attachment.UpdatedAt,
 		)
@@ -364,10 +481,10 @@ func (r *postRepository) GetAttachmentsByPostIDs(ctx context.Context, postIDs []
 	}
 
 	query := `
-		SELECT id, post_id, photo, created_at, updated_at
+		SELECT id, post_id, photo, position, caption, created_at, updated_at
 		FROM attachments
 		WHERE post_id = ANY($1) AND deleted_at IS NULL
-		ORDER BY post_id, created_at ASC
+		ORDER BY post_id, position ASC, created_at ASC
 	`
 
 	rows, err := r.db.Pool.Query(ctx, query, postIDs)
@@ -379,7 +496,7 @@ func (r *postRepository) GetAttachmentsByPostIDs(ctx context.Context, postIDs []
 	out := make(map[string][]*models.Attachment, len(postIDs))
 	for rows.Next() {
 		att := &models.Attachment{}
-		if err := rows.Scan(&att.ID, &att.PostID, &att.Photo, &att.CreatedAt, &att.UpdatedAt); err != nil {
+		if err := rows.Scan(&att.ID, &att.PostID, &att.Photo, &att.Position, &att.Caption, &att.CreatedAt, &att.UpdatedAt); err != nil {
 			return nil, err
 		}
 		out[att.PostID] = append(out[att.PostID], att)
@@ -407,6 +524,39 @@ func (r *postRepository) DeleteAttachmentForPost(ctx context.Context, postID, at
 	return err
 }
 
+// ReorderAttachments rewrites the positions of the post's attachments in
+// one statement.
+func (r *postRepository) ReorderAttachments(ctx context.Context, postID string, attachmentIDs []string) error {
+	query := `
+		UPDATE attachments a
+		SET position = o.ord - 1, updated_at = NOW()
+		FROM unnest($2::uuid[]) WITH ORDINALITY AS o(id, ord)
+		WHERE a.id = o.id AND a.post_id = $1 AND a.deleted_at IS NULL
+	`
+	_, err := r.db.Pool.Exec(ctx, query, postID, attachmentIDs)
+	return err
+}
+
+// SetAttachmentCaption updates one attachment's caption.
+func (r *postRepository) SetAttachmentCaption(ctx context.Context, postID, attachmentID string, caption *string) error {
+	query := `UPDATE attachments SET caption = $3, updated_at = NOW() WHERE id = $1 AND post_id = $2 AND deleted_at IS NULL`
+	_, err := r.db.Pool.Exec(ctx, query, attachmentID, postID, caption)
+	return err
+}
+
+// SetAttachmentAltText updates the alt_text key of one attachment's photo.
+func (r *postRepository) SetAttachmentAltText(ctx context.Context, postID, attachmentID, altText string) error {
+	query := `
+		UPDATE attachments
+		SET photo = CASE WHEN $3 = '' THEN photo - 'alt_text'
+			ELSE jsonb_set(photo, '{alt_text}', to_jsonb($3::text)) END,
+			updated_at = NOW()
+		WHERE id = $1 AND post_id = $2 AND deleted_at IS NULL
+	`
+	_, err := r.db.Pool.Exec(ctx, query, attachmentID, postID, altText)
+	return err
+}
+
 // LikePost likes a post (idempotent)
 func (r *postRepository) LikePost(ctx context.Context, userID, postID string) error {
 	query := `
@@ -550,6 +700,46 @@ func (r *postRepository) RecordPostView(ctx context.Context, userID, postID stri
 	return err
 }
 
+// IncrementDailyViews adds a view to today's bucket. The author's own views
+// are skipped, as in RecordPostView.
+func (r *postRepository) IncrementDailyViews(ctx context.Context, userID, postID string) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		INSERT INTO post_daily_views (post_id, day, views)
+		SELECT p.id, CURRENT_DATE, 1
+		FROM posts p
+		WHERE p.id = $1 AND p.user_id IS DISTINCT FROM $2
+		ON CONFLICT (post_id, day) DO UPDATE SET views = post_daily_views.views + 1
+	`, postID, userID)
+	return err
+}
+
+// GetViewCounts sums the daily view buckets of each post.
+func (r *postRepository) GetViewCounts(ctx context.Context, postIDs []string) (map[string]int, error) {
+	counts := make(map[string]int)
+	if len(postIDs) == 0 {
+		return counts, nil
+	}
+	rows, err := r.db.Reader().Query(ctx, `
+		SELECT post_id::text, SUM(views)
+		FROM post_daily_views
+		WHERE post_id = ANY($1::uuid[])
+		GROUP BY post_id
+	`, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		var n int
+		if err := rows.Scan(&id, &n); err != nil {
+			return nil, err
+		}
+		counts[id] = n
+	}
+	return counts, rows.Err()
+}
+
 // BookmarkPost bookmarks a post (idempotent)
 func (r *postRepository) BookmarkPost(ctx context.Context, userID, postID string) error {
 	query := `
@@ -588,6 +778,52 @@ func (r *postRepository) GetBookmarkerIDs(ctx context.Context, postID string) ([
 	return ids, rows.Err()
 }
 
+// GetBookmarkCounts reads the trigger-maintained bookmark counter of each post.
+func (r *postRepository) GetBookmarkCounts(ctx context.Context, postIDs []string) (map[string]int, error) {
+	counts := make(map[string]int)
+	if len(postIDs) == 0 {
+		return counts, nil
+	}
+	rows, err := r.db.Reader().Query(ctx, `
+		SELECT id::text, total_bookmarks
+		FROM posts
+		WHERE id = ANY($1::uuid[]) AND total_bookmarks > 0
+	`, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		var n int
+		if err := rows.Scan(&id, &n); err != nil {
+			return nil, err
+		}
+		counts[id] = n
+	}
+	return counts, rows.Err()
+}
+
+// ClaimBookmarkMilestone moves bookmark_milestone up to the highest threshold
+// reached; the conditional UPDATE makes the claim atomic.
+func (r *postRepository) ClaimBookmarkMilestone(ctx context.Context, postID string, thresholds []int) (int, error) {
+	var milestone int
+	err := r.db.Pool.QueryRow(ctx, `
+		UPDATE posts p SET bookmark_milestone = m.threshold
+		FROM (
+			SELECT MAX(t) AS threshold
+			FROM unnest($2::int[]) AS t, posts
+			WHERE posts.id = $1 AND t <= posts.total_bookmarks
+		) m
+		WHERE p.id = $1 AND m.threshold > p.bookmark_milestone
+		RETURNING p.bookmark_milestone
+	`, postID, thresholds).Scan(&milestone)
+	if err == pgx.ErrNoRows {
+		return 0, nil
+	}
+	return milestone, err
+}
+
 // UnbookmarkPost removes a bookmark
 func (r *postRepository) UnbookmarkPost(ctx context.Context, userID, postID string) error {
 	query := `
@@ -624,7 +860,8 @@ func (r *postRepository) GetUserBookmarks(ctx context.Context, userID string, li
 			ST_X(p.address_location::geometry)::double precision, ST_Y(p.address_location::geometry)::double precision, ST_X(p.user_location::geometry)::double precision, ST_Y(p.user_location::geometry)::double precision,
 			p.country, p.province, p.district, p.neighborhood,
 			p.total_comments, p.total_likes, p.total_shares,
-			p.created_at, p.updated_at, p.deleted_at
+			p.created_at, p.updated_at, p.deleted_at, p.group_id,
+			p.lost_found_kind, p.item_description, p.last_seen_place, p.last_seen_at, p.urgency, p.lang, p.bumped_at, p.version
 		FROM posts p
 		INNER JOIN post_bookmarks pb ON p.id = pb.post_id
 		WHERE pb.user_id = $1 AND p.deleted_at IS NULL
@@ -635,6 +872,36 @@ func (r *postRepository) GetUserBookmarks(ctx context.Context, userID string, li
 	return r.queryPosts(ctx, query, userID, limit, offset)
 }
 
+// GetUserBookmarksInCollection gets the user's bookmarked posts in one collection
+func (r *postRepository) GetUserBookmarksInCollection(ctx context.Context, userID, collectionID string, limit, offset int) ([]*models.Post, error) {
+	args := []interface{}{userID, limit, offset}
+	collectionFilter := `pb.collection_id IS NULL`
+	if collectionID != models.BookmarkCollectionNone {
+		collectionFilter = `pb.collection_id = $4`
+		args = append(args, collectionID)
+	}
+
+	query := `
+		SELECT
+			p.id, p.user_id, p.business_id, p.original_post_id, p.category_id,
+			p.title, p.description, p.type, p.status, p.visibility,
+			p.currency, p.price, p.discount, p.free, p.sold, p.is_promoted, p.country_code, p.contact_no, p.is_location,
+			p.start_date, p.start_time, p.end_date, p.end_time, p.event_state, p.interested_count, p.going_count, p.expired_at,
+			ST_X(p.address_location::geometry)::double precision, ST_Y(p.address_location::geometry)::double precision, ST_X(p.user_location::geometry)::double precision, ST_Y(p.user_location::geometry)::double precision,
+			p.country, p.province, p.district, p.neighborhood,
+			p.total_comments, p.total_likes, p.total_shares,
+			p.created_at, p.updated_at, p.deleted_at, p.group_id,
+			p.lost_found_kind, p.item_description, p.last_seen_place, p.last_seen_at, p.urgency, p.lang, p.bumped_at, p.version
+		FROM posts p
+		INNER JOIN post_bookmarks pb ON p.id = pb.post_id
+		WHERE pb.user_id = $1 AND p.deleted_at IS NULL AND ` + collectionFilter + `
+		ORDER BY pb.created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	return r.queryPosts(ctx, query, args...)
+}
+
 // GetUserEventPosts gets EVENT posts that the user is going to or interested in
 func (r *postRepository) GetUserEventPosts(ctx context.Context, userID string, eventState models.EventInterestState, limit, offset int) ([]*models.Post, error) {
 	query := `
@@ -646,7 +913,8 @@ func (r *postRepository) GetUserEventPosts(ctx context.Context, userID string, e
 			ST_X(p.address_location::geometry)::double precision, ST_Y(p.address_location::geometry)::double precision, ST_X(p.user_location::geometry)::double precision, ST_Y(p.user_location::geometry)::double precision,
 			p.country, p.province, p.district, p.neighborhood,
 			p.total_comments, p.total_likes, p.total_shares,
-			p.created_at, p.updated_at, p.deleted_at
+			p.created_at, p.updated_at, p.deleted_at, p.group_id,
+			p.lost_found_kind, p.item_description, p.last_seen_place, p.last_seen_at, p.urgency, p.lang, p.bumped_at, p.version
 		FROM posts p
 		INNER JOIN event_interests ei ON p.id = ei.post_id
 		WHERE ei.user_id = $1 AND ei.event_state = $2 AND p.deleted_at IS NULL AND p.type = $3
@@ -711,321 +979,326 @@ func (r *postRepository) GetPostShares(ctx context.Context, postID string, limit
 	return shares, rows.Err()
 }
 
-// GetFeed gets posts based on filter criteria
-func (r *postRepository) GetFeed(ctx context.Context, filter *models.FeedFilter) ([]*models.Post, error) {
-	queryBuilder := strings.Builder{}
-	queryBuilder.WriteString(`
-		SELECT
-			id, user_id, business_id, original_post_id, category_id,
-			title, description, type, status, visibility,
-			currency, price, discount, free, sold, is_promoted, country_code, contact_no, is_location,
-			start_date, start_time, end_date, end_time, event_state, interested_count, going_count, expired_at,
-			` + locationSelectFragment + `,
-			country, province, district, neighborhood,
-			total_comments, total_likes, total_shares,
-			created_at, updated_at, deleted_at
-		FROM posts
-		WHERE deleted_at IS NULL
-	`)
+var (
+	// ErrShareLinkNotFound is returned for an unknown short link code.
+	ErrShareLinkNotFound = newNotFoundError("share link not found")
+	// ErrShareLinkCodeTaken is returned when a new short link's code is
+	// already in use; the caller retries with a fresh code.
+	ErrShareLinkCodeTaken = newConflictError("share link code taken")
+)
 
-	if !filter.IncludeInactive {
-		queryBuilder.WriteString(" AND status = true")
+const shareLinkColumns = `code, post_id, user_id, channel, share_count, click_count, created_at, last_shared_at`
+
+func shareLinkScanDest(l *models.PostShareLink) []interface{} {
+	return []interface{}{&l.Code, &l.PostID, &l.UserID, &l.Channel, &l.ShareCount, &l.ClickCount, &l.CreatedAt, &l.LastSharedAt}
+}
+
+// RecordExternalShare upserts the share link and bumps posts.external_shares
+// in one transaction.
+func (r *postRepository) RecordExternalShare(ctx context.Context, link *models.PostShareLink) (*models.PostShareLink, int, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	out := &models.PostShareLink{}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO post_share_links (code, post_id, user_id, channel, share_count, click_count, created_at, last_shared_at)
+		VALUES ($1, $2, $3, $4, 1, 0, NOW(), NOW())
+		ON CONFLICT (post_id, user_id, channel) DO UPDATE
+		SET share_count = post_share_links.share_count + 1, last_shared_at = NOW()
+		RETURNING `+shareLinkColumns,
+		link.Code, link.PostID, link.UserID, link.Channel,
+	).Scan(shareLinkScanDest(out)...)
+	if err != nil && isUniqueViolation(err) {
+		return nil, 0, ErrShareLinkCodeTaken
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("upsert share link: %w", err)
+	}
+
+	var externalShares int
+	err = tx.QueryRow(ctx, `
+		UPDATE posts SET external_shares = external_shares + 1
+		WHERE id = $1
+		RETURNING external_shares
+	`, link.PostID).Scan(&externalShares)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count external share: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, 0, fmt.Errorf("commit external share: %w", err)
+	}
+	return out, externalShares, nil
+}
+
+// ResolveShareLink bumps the click counter and returns the link.
+func (r *postRepository) ResolveShareLink(ctx context.Context, code string) (*models.PostShareLink, error) {
+	out := &models.PostShareLink{}
+	err := r.db.Pool.QueryRow(ctx, `
+		UPDATE post_share_links SET click_count = click_count + 1
+		WHERE code = $1
+		RETURNING `+shareLinkColumns, code,
+	).Scan(shareLinkScanDest(out)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrShareLinkNotFound
 	}
+	if err != nil {
+		return nil, fmt.Errorf("resolve share link: %w", err)
+	}
+	return out, nil
+}
 
-	args := []interface{}{}
-	argCount := 1
+// mutedTermPattern matches term as a whole word in a case-insensitive
+// Postgres regex.
+func mutedTermPattern(term string) string {
+	return `(^|[^[:alnum:]_])` + regexp.QuoteMeta(term) + `($|[^[:alnum:]_])`
+}
+
+// feedWhere builds the WHERE clause shared by GetFeed and CountFeed, so
+// the total always counts exactly the posts the feed can page through.
+func feedWhere(filter *models.FeedFilter) *whereBuilder {
+	b := newWhereBuilder("deleted_at IS NULL")
+
+	if !filter.IncludeInactive {
+		b.where("status = true")
+	}
 
-	// Apply filters
 	if filter.Type != nil {
-		fmt.Fprintf(&queryBuilder, " AND type = $%d", argCount)
-		args = append(args, string(*filter.Type))
-		argCount++
+		b.where("type = ?", string(*filter.Type))
+	}
+
+	if filter.LostFoundKind != nil {
+		b.where("lost_found_kind = ?", string(*filter.LostFoundKind))
+	}
+
+	if filter.MinUrgency != nil {
+		b.where("urgency = ANY(?)", filter.MinUrgency.AtLeast())
 	}
 
 	if filter.UserID != nil {
-		fmt.Fprintf(&queryBuilder, " AND user_id = $%d", argCount)
-		args = append(args, *filter.UserID)
-		argCount++
+		b.where("user_id = ?", *filter.UserID)
 	}
 
 	// Bidirectional block filter — hide authors the viewer blocked AND any
 	// author who blocked the viewer. Apple UGC compliance.
 	if filter.ViewerID != "" {
-		fmt.Fprintf(&queryBuilder, ` AND user_id NOT IN (
-			SELECT blocked_id FROM user_blocks WHERE blocker_id = $%d
+		b.where(`user_id NOT IN (
+			SELECT blocked_id FROM user_blocks WHERE blocker_id = ?
 			UNION
-			SELECT blocker_id FROM user_blocks WHERE blocked_id = $%d
-		)`, argCount, argCount)
-		args = append(args, filter.ViewerID)
-		argCount++
+			SELECT blocker_id FROM user_blocks WHERE blocked_id = ?
+		)`, filter.ViewerID)
 
 		// Shadowban filter — exclude shadowbanned authors UNLESS the
 		// viewer is the author themselves. Author keeps seeing their
 		// own posts so they don't suspect the action and just rotate
 		// to a fresh account.
-		fmt.Fprintf(&queryBuilder, ` AND NOT EXISTS (
-			SELECT 1 FROM users u
-			WHERE u.id = posts.user_id
-			  AND u.shadowbanned_at IS NOT NULL
-			  AND u.id <> $%d
-		)`, argCount)
-		args = append(args, filter.ViewerID)
-		argCount++
+		b.where(shadowbanPredicate("posts.user_id", b.bindIndex(filter.ViewerID)))
 	} else {
 		// Anonymous / public feed: hide all shadowbanned authors.
-		queryBuilder.WriteString(` AND NOT EXISTS (
-			SELECT 1 FROM users u
-			WHERE u.id = posts.user_id AND u.shadowbanned_at IS NOT NULL
-		)`)
+		b.where(shadowbanPredicate("posts.user_id", 0))
+	}
+
+	// Group posts only appear in their group's feed.
+	if filter.GroupID != nil {
+		b.where("group_id = ?", *filter.GroupID)
+	} else {
+		b.where("group_id IS NULL")
 	}
 
 	if filter.BusinessID != nil {
-		fmt.Fprintf(&queryBuilder, " AND business_id = $%d", argCount)
-		args = append(args, *filter.BusinessID)
-		argCount++
+		b.where("business_id = ?", *filter.BusinessID)
 	} else if filter.OnlyBusiness {
 		// Home "business & service updates" feed: only business-authored posts.
-		queryBuilder.WriteString(" AND business_id IS NOT NULL")
+		b.where("business_id IS NOT NULL")
 	}
 
 	if filter.CategoryID != nil {
-		fmt.Fprintf(&queryBuilder, " AND category_id = $%d", argCount)
-		args = append(args, *filter.CategoryID)
-		argCount++
+		b.where("category_id = ?", *filter.CategoryID)
 	}
 
 	if filter.Province != nil {
 		// Filter by author's province (profiles.province); post-level province is often null for FEED/EVENT/PULL
-		fmt.Fprintf(&queryBuilder, " AND EXISTS (SELECT 1 FROM profiles pr WHERE pr.id = posts.user_id AND pr.province = $%d)", argCount)
-		args = append(args, *filter.Province)
-		argCount++
+		b.where(fmt.Sprintf("EXISTS (SELECT 1 FROM profiles pr WHERE pr.id = posts.user_id AND %s)",
+			provinceFilter("pr.", b.bindIndex(*filter.Province))))
+	}
+
+	// Posts with no detected language (photo-only, emoji, prices) stay in
+	// every language feed.
+	if len(filter.Languages) > 0 {
+		b.where("(lang IS NULL OR lang = ANY(?))", filter.Languages)
+	}
+
+	if len(filter.MutedTerms) > 0 {
+		patterns := make([]string, len(filter.MutedTerms))
+		for i, term := range filter.MutedTerms {
+			patterns[i] = mutedTermPattern(term)
+		}
+		b.where("(COALESCE(title, '') || ' ' || COALESCE(description, '')) !~* ALL(?)", patterns)
 	}
 
 	if filter.IsFree != nil && *filter.IsFree {
-		queryBuilder.WriteString(" AND free = true")
+		b.where("free = true")
 	}
 
 	if filter.HasDiscount != nil && *filter.HasDiscount {
-		queryBuilder.WriteString(" AND discount IS NOT NULL AND discount > 0")
+		b.where("discount IS NOT NULL AND discount > 0")
 	}
 
 	if filter.Search != nil && *filter.Search != "" {
 		searchPattern := "%" + EscapeLike(*filter.Search) + "%"
-		fmt.Fprintf(&queryBuilder, ` AND (title ILIKE $%d ESCAPE '\' OR description ILIKE $%d ESCAPE '\' OR EXISTS (SELECT 1 FROM sell_categories sc WHERE sc.id = posts.category_id AND sc.name ILIKE $%d ESCAPE '\'))`,
-			argCount, argCount+1, argCount+2)
-		args = append(args, searchPattern, searchPattern, searchPattern)
-		argCount += 3
+		b.where(`(title ILIKE ? ESCAPE '\' OR description ILIKE ? ESCAPE '\' OR EXISTS (SELECT 1 FROM sell_categories sc WHERE sc.id = posts.category_id AND sc.name ILIKE ? ESCAPE '\'))`,
+			searchPattern, searchPattern, searchPattern)
 	}
 
 	if filter.Sold != nil {
-		fmt.Fprintf(&queryBuilder, " AND sold = $%d", argCount)
-		args = append(args, *filter.Sold)
-		argCount++
+		b.where("sold = ?", *filter.Sold)
 	} else {
 		// Exclude SELL posts marked as sold from feed and listings (they appear only in "my posts" with sold=true)
-		queryBuilder.WriteString(" AND (type != 'SELL' OR sold = false)")
+		b.where("(type != 'SELL' OR sold = false)")
 	}
 
 	// Home feed suppression: hide SELL posts unless explicitly requested
 	// by Type=SELL or unless they are paid/promoted listings. Keeps the
 	// marketplace from drowning out social posts in the home feed.
 	if filter.HideUnpromotedSell && (filter.Type == nil || *filter.Type != models.PostTypeSell) {
-		queryBuilder.WriteString(" AND (type != 'SELL' OR is_promoted = true)")
+		b.where("(type != 'SELL' OR is_promoted = true)")
 	}
 
 	// Location-based filtering (radius search)
-	var locationSearchActive bool
-	if filter.Latitude != nil && filter.Longitude != nil && filter.RadiusKm != nil {
+	if feedRadiusSearch(filter) {
 		// PostGIS radius search: ST_DWithin expects geography and distance in meters
-		fmt.Fprintf(&queryBuilder, `
-			AND ST_DWithin(
+		b.where(`ST_DWithin(
 				address_location::geography,
-				ST_SetSRID(ST_MakePoint($%d, $%d), 4326)::geography,
-				$%d
-			)
-		`, argCount, argCount+1, argCount+2)
-		args = append(args, *filter.Longitude, *filter.Latitude, *filter.RadiusKm*1000) // Convert km to meters
-		argCount += 3
-		locationSearchActive = true
+				ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography,
+				?
+			)`, *filter.Longitude, *filter.Latitude, *filter.RadiusKm*1000) // Convert km to meters
 	}
 
+	return b
+}
+
+func feedRadiusSearch(filter *models.FeedFilter) bool {
+	return filter.Latitude != nil && filter.Longitude != nil && filter.RadiusKm != nil
+}
+
+// GetFeed gets posts based on filter criteria
+func (r *postRepository) GetFeed(ctx context.Context, filter *models.FeedFilter) ([]*models.Post, error) {
+	where := feedWhere(filter)
+
 	// Cursor-based pagination: when a cursor is provided, filter out older posts
 	// instead of using OFFSET (which degrades linearly with page depth).
-	if filter.Cursor != nil && filter.SortBy != "trending" && filter.SortBy != "nearby" {
-		fmt.Fprintf(&queryBuilder, " AND created_at < $%d", argCount)
-		args = append(args, *filter.Cursor)
-		argCount++
+	delta := filter.UpdatedAfter != nil
+	useCursor := !delta && filter.Cursor != nil && filter.SortBy != "trending" && filter.SortBy != "nearby"
+	if useCursor {
+		where.where("bumped_at < ?", *filter.Cursor)
+	}
+	if delta {
+		where.where("updated_at > ?", *filter.UpdatedAfter)
 	}
 
-	// Sorting
-	switch filter.SortBy {
-	case "trending":
+	queryBuilder := strings.Builder{}
+	queryBuilder.WriteString(`
+		SELECT
+			id, user_id, business_id, original_post_id, category_id,
+			title, description, type, status, visibility,
+			currency, price, discount, free, sold, is_promoted, country_code, contact_no, is_location,
+			start_date, start_time, end_date, end_time, event_state, interested_count, going_count, expired_at,
+			` + locationSelectFragment + `,
+			country, province, district, neighborhood,
+			total_comments, total_likes, total_shares,
+			created_at, updated_at, deleted_at, group_id,
+			lost_found_kind, item_description, last_seen_place, last_seen_at, urgency, lang, bumped_at, version
+		FROM posts
+		WHERE `)
+	queryBuilder.WriteString(where.clause())
+
+	// Sorting; delta sync walks changes oldest first.
+	switch {
+	case delta:
+		queryBuilder.WriteString(" ORDER BY updated_at ASC")
+	case filter.SortBy == "trending":
 		// Trending score = (likes * 2 + comments * 3 + shares * 5) / age_hours^1.5
 		queryBuilder.WriteString(`
 			ORDER BY ((total_likes * 2 + total_comments * 3 + total_shares * 5) /
 			POWER(EXTRACT(EPOCH FROM (NOW() - created_at)) / 3600 + 1, 1.5)) DESC
 		`)
-	case "nearby":
+	case filter.SortBy == "nearby":
 		// Distance-based sorting when location is provided
-		if locationSearchActive && filter.Latitude != nil && filter.Longitude != nil {
+		if feedRadiusSearch(filter) {
 			// Sort by distance (nearest first)
 			fmt.Fprintf(&queryBuilder, `
 				ORDER BY ST_Distance(
 					address_location::geography,
-					ST_SetSRID(ST_MakePoint($%d, $%d), 4326)::geography
+					ST_SetSRID(ST_MakePoint(%s, %s), 4326)::geography
 				) ASC
-			`, argCount, argCount+1)
-			args = append(args, *filter.Longitude, *filter.Latitude)
-			argCount += 2
+			`, where.bind(*filter.Longitude), where.bind(*filter.Latitude))
 		} else {
 			// Fallback to recent if no location provided
-			queryBuilder.WriteString(" ORDER BY created_at DESC")
+			queryBuilder.WriteString(" ORDER BY bumped_at DESC")
 		}
-	default: // recent
-		queryBuilder.WriteString(" ORDER BY created_at DESC")
+	default: // recent; bumped_at is created_at until a SELL listing is renewed
+		queryBuilder.WriteString(" ORDER BY bumped_at DESC")
 	}
 
 	// Use LIMIT only (cursor replaces OFFSET for default/recent sorting)
-	if filter.Cursor != nil && filter.SortBy != "trending" && filter.SortBy != "nearby" {
-		fmt.Fprintf(&queryBuilder, " LIMIT $%d", argCount)
-		args = append(args, filter.Limit)
+	if useCursor || delta {
+		fmt.Fprintf(&queryBuilder, " LIMIT %s", where.bind(filter.Limit))
 	} else {
-		fmt.Fprintf(&queryBuilder, " LIMIT $%d OFFSET $%d", argCount, argCount+1)
-		args = append(args, filter.Limit, filter.Offset)
+		fmt.Fprintf(&queryBuilder, " LIMIT %s OFFSET %s", where.bind(filter.Limit), where.bind(filter.Offset))
 	}
 
-	return r.queryPosts(ctx, queryBuilder.String(), args...)
+	return r.queryPosts(ctx, queryBuilder.String(), where.params()...)
 }
 
 // CountFeed counts total posts matching the filter (without pagination)
 func (r *postRepository) CountFeed(ctx context.Context, filter *models.FeedFilter) (int64, error) {
-	queryBuilder := strings.Builder{}
-	queryBuilder.WriteString(`
-		SELECT COUNT(*)
-		FROM posts
-		WHERE deleted_at IS NULL
-	`)
-	if !filter.IncludeInactive {
-		queryBuilder.WriteString(" AND status = true")
-	}
-
-	args := []interface{}{}
-	argCount := 1
+	where := feedWhere(filter)
 
-	// Apply same filters as GetFeed
-	if filter.Type != nil {
-		fmt.Fprintf(&queryBuilder, " AND type = $%d", argCount)
-		args = append(args, string(*filter.Type))
-		argCount++
-	}
-
-	if filter.UserID != nil {
-		fmt.Fprintf(&queryBuilder, " AND user_id = $%d", argCount)
-		args = append(args, *filter.UserID)
-		argCount++
+	var count int64
+	err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM posts WHERE `+where.clause(), where.params()...).Scan(&count)
+	if err != nil {
+		return 0, err
 	}
 
-	// Bidirectional block filter — hide authors the viewer blocked AND any
-	// author who blocked the viewer. Apple UGC compliance.
-	if filter.ViewerID != "" {
-		fmt.Fprintf(&queryBuilder, ` AND user_id NOT IN (
-			SELECT blocked_id FROM user_blocks WHERE blocker_id = $%d
-			UNION
-			SELECT blocker_id FROM user_blocks WHERE blocked_id = $%d
-		)`, argCount, argCount)
-		args = append(args, filter.ViewerID)
-		argCount++
+	return count, nil
+}
 
-		// Shadowban filter — exclude shadowbanned authors UNLESS the
-		// viewer is the author themselves. Author keeps seeing their
-		// own posts so they don't suspect the action and just rotate
-		// to a fresh account.
-		fmt.Fprintf(&queryBuilder, ` AND NOT EXISTS (
-			SELECT 1 FROM users u
-			WHERE u.id = posts.user_id
-			  AND u.shadowbanned_at IS NOT NULL
-			  AND u.id <> $%d
-		)`, argCount)
-		args = append(args, filter.ViewerID)
-		argCount++
-	} else {
-		// Anonymous / public feed: hide all shadowbanned authors.
-		queryBuilder.WriteString(` AND NOT EXISTS (
-			SELECT 1 FROM users u
-			WHERE u.id = posts.user_id AND u.shadowbanned_at IS NOT NULL
-		)`)
+// RemovedPostIDs lists posts that left the feed after since. Only the
+// user, business and group scope of filter applies: the client drops IDs it
+// doesn't hold, so a wider list is harmless. A soft delete, deactivation or
+// sale bumps updated_at, so the updated_at index bounds the scan.
+func (r *postRepository) RemovedPostIDs(ctx context.Context, filter *models.FeedFilter, since time.Time, limit int) ([]string, error) {
+	b := newWhereBuilder("(deleted_at IS NOT NULL OR status = false OR (type = 'SELL' AND sold = true))")
+	b.where("updated_at > ?", since)
+	if filter.UserID != nil {
+		b.where("user_id = ?", *filter.UserID)
 	}
-
 	if filter.BusinessID != nil {
-		fmt.Fprintf(&queryBuilder, " AND business_id = $%d", argCount)
-		args = append(args, *filter.BusinessID)
-		argCount++
-	} else if filter.OnlyBusiness {
-		// Home "business & service updates" feed: only business-authored posts.
-		queryBuilder.WriteString(" AND business_id IS NOT NULL")
+		b.where("business_id = ?", *filter.BusinessID)
 	}
-
-	if filter.CategoryID != nil {
-		fmt.Fprintf(&queryBuilder, " AND category_id = $%d", argCount)
-		args = append(args, *filter.CategoryID)
-		argCount++
+	if filter.GroupID != nil {
+		b.where("group_id = ?", *filter.GroupID)
 	}
 
-	if filter.Province != nil {
-		// Filter by author's province (profiles.province); post-level province is often null for FEED/EVENT/PULL
-		fmt.Fprintf(&queryBuilder, " AND EXISTS (SELECT 1 FROM profiles pr WHERE pr.id = posts.user_id AND pr.province = $%d)", argCount)
-		args = append(args, *filter.Province)
-		argCount++
-	}
-
-	if filter.IsFree != nil && *filter.IsFree {
-		queryBuilder.WriteString(" AND free = true")
-	}
-
-	if filter.HasDiscount != nil && *filter.HasDiscount {
-		queryBuilder.WriteString(" AND discount IS NOT NULL AND discount > 0")
-	}
-
-	if filter.Search != nil && *filter.Search != "" {
-		searchPattern := "%" + EscapeLike(*filter.Search) + "%"
-		fmt.Fprintf(&queryBuilder, ` AND (title ILIKE $%d ESCAPE '\' OR description ILIKE $%d ESCAPE '\' OR EXISTS (SELECT 1 FROM sell_categories sc WHERE sc.id = posts.category_id AND sc.name ILIKE $%d ESCAPE '\'))`,
-			argCount, argCount+1, argCount+2)
-		args = append(args, searchPattern, searchPattern, searchPattern)
-		argCount += 3
-	}
-
-	if filter.Sold != nil {
-		fmt.Fprintf(&queryBuilder, " AND sold = $%d", argCount)
-		args = append(args, *filter.Sold)
-		argCount++
-	} else {
-		queryBuilder.WriteString(" AND (type != 'SELL' OR sold = false)")
-	}
-
-	// Mirror of HideUnpromotedSell from GetFeed so total counts match.
-	if filter.HideUnpromotedSell && (filter.Type == nil || *filter.Type != models.PostTypeSell) {
-		queryBuilder.WriteString(" AND (type != 'SELL' OR is_promoted = true)")
-	}
-
-	// Location-based filtering (radius search)
-	if filter.Latitude != nil && filter.Longitude != nil && filter.RadiusKm != nil {
-		fmt.Fprintf(&queryBuilder, `
-			AND ST_DWithin(
-				address_location::geography,
-				ST_SetSRID(ST_MakePoint($%d, $%d), 4326)::geography,
-				$%d
-			)
-		`, argCount, argCount+1, argCount+2)
-		args = append(args, *filter.Longitude, *filter.Latitude, *filter.RadiusKm*1000)
-	}
-
-	var count int64
-	err := r.db.Pool.QueryRow(ctx, queryBuilder.String(), args...).Scan(&count)
+	query := fmt.Sprintf("SELECT id FROM posts WHERE %s ORDER BY updated_at ASC LIMIT %s", b.clause(), b.bind(limit))
+	rows, err := r.db.Pool.Query(ctx, query, b.params()...)
 	if err != nil {
-		return 0, err
+		return nil, fmt.Errorf("failed to list removed posts: %w", err)
 	}
+	defer rows.Close()
 
-	return count, nil
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan removed post: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
 }
 
 // GetUserPosts gets all posts by a user
@@ -1039,9 +1312,10 @@ func (r *postRepository) GetUserPosts(ctx context.Context, userID string, limit,
 			` + locationSelectFragment + `,
 			country, province, district, neighborhood,
 			total_comments, total_likes, total_shares,
-			created_at, updated_at, deleted_at
+			created_at, updated_at, deleted_at, group_id,
+			lost_found_kind, item_description, last_seen_place, last_seen_at, urgency, lang, bumped_at, version
 		FROM posts
-		WHERE user_id = $1 AND deleted_at IS NULL
+		WHERE user_id = $1 AND deleted_at IS NULL AND group_id IS NULL
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
@@ -1060,9 +1334,10 @@ func (r *postRepository) GetBusinessPosts(ctx context.Context, businessID string
 			` + locationSelectFragment + `,
 			country, province, district, neighborhood,
 			total_comments, total_likes, total_shares,
-			created_at, updated_at, deleted_at
+			created_at, updated_at, deleted_at, group_id,
+			lost_found_kind, item_description, last_seen_place, last_seen_at, urgency, lang, bumped_at, version
 		FROM posts
-		WHERE business_id = $1 AND deleted_at IS NULL
+		WHERE business_id = $1 AND deleted_at IS NULL AND group_id IS NULL
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
@@ -1142,7 +1417,8 @@ func (r *postRepository) ListExpiredSellPostsNeedingNotification(ctx context.Con
 			` + locationSelectFragment + `,
 			p.country, p.province, p.district, p.neighborhood,
 			p.total_comments, p.total_likes, p.total_shares,
-			p.created_at, p.updated_at, p.deleted_at
+			p.created_at, p.updated_at, p.deleted_at, p.group_id,
+			p.lost_found_kind, p.item_description, p.last_seen_place, p.last_seen_at, p.urgency, p.lang, p.bumped_at, p.version
 		FROM posts p
 		WHERE p.type = 'SELL'
 		  AND p.sold = false
@@ -1177,7 +1453,8 @@ func (r *postRepository) ListExpiredSellPostsNeedingNotification(ctx context.Con
 			&addrLng, &addrLat, &userLng, &userLat,
 			&post.Country, &post.Province, &post.District, &post.Neighborhood,
 			&post.TotalComments, &post.TotalLikes, &post.TotalShares,
-			&post.CreatedAt, &post.UpdatedAt, &post.DeletedAt,
+			&post.CreatedAt, &post.UpdatedAt, &post.DeletedAt, &post.GroupID,
+			&post.LostFoundKind, &post.ItemDescription, &post.LastSeenPlace, &post.LastSeenAt, &post.Urgency, &post.Lang, &post.BumpedAt, &post.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -1198,17 +1475,125 @@ func (r *postRepository) MarkSellPostsExpired(ctx context.Context, postIDs []str
 	return err
 }
 
-// ReactivateSellPost sets status=true, sold=false, and resets expired_at to now+30 days.
-func (r *postRepository) ReactivateSellPost(ctx context.Context, postID string) error {
+// ReactivateSellPost sets status=true, sold=false, and resets expired_at to expiresAt.
+func (r *postRepository) ReactivateSellPost(ctx context.Context, postID string, expiresAt time.Time) error {
 	query := `
 		UPDATE posts
-		SET status = true, sold = false, sold_at = NULL, expired_at = NOW() + INTERVAL '30 days', updated_at = NOW()
+		SET status = true, sold = false, sold_at = NULL, expired_at = $2, updated_at = NOW()
 		WHERE id = $1 AND deleted_at IS NULL
 	`
-	_, err := r.db.Pool.Exec(ctx, query, postID)
+	_, err := r.db.Pool.Exec(ctx, query, postID, expiresAt)
 	return err
 }
 
+// SetExpiry sets a post's expired_at; nil clears it.
+func (r *postRepository) SetExpiry(ctx context.Context, postID string, expiresAt *time.Time) error {
+	query := `UPDATE posts SET expired_at = $2, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+	_, err := r.db.Pool.Exec(ctx, query, postID, expiresAt)
+	return err
+}
+
+// ArchiveExpiredPosts deactivates live EVENT and ALERT posts past their
+// expired_at. SELL posts are left to the sell-expiry job, which notifies
+// the seller first.
+func (r *postRepository) ArchiveExpiredPosts(ctx context.Context, asOf time.Time) (int64, error) {
+	query := `
+		UPDATE posts
+		SET status = false,
+		    event_state = CASE WHEN type = 'EVENT' THEN 'ended' ELSE event_state END,
+		    updated_at = NOW()
+		WHERE type IN ('EVENT', 'ALERT')
+		  AND status = true
+		  AND deleted_at IS NULL
+		  AND expired_at IS NOT NULL
+		  AND expired_at <= $1
+	`
+	tag, err := r.db.Pool.Exec(ctx, query, asOf)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// RenewSellPost resets expired_at and bumped_at for an active, unsold SELL
+// post. The cooldown check is in the WHERE clause so two racing renewals
+// can't both bump.
+func (r *postRepository) RenewSellPost(ctx context.Context, postID string, cooldown time.Duration, expiresAt time.Time) (bool, error) {
+	query := `
+		UPDATE posts
+		SET expired_at = $3, bumped_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND type = 'SELL' AND sold = false AND status = true AND deleted_at IS NULL
+		  AND bumped_at <= $2
+	`
+	tag, err := r.db.Pool.Exec(ctx, query, postID, time.Now().Add(-cooldown), expiresAt)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// ListUserListings returns one tab of the seller's listings. Sold listings
+// are newest sale first, expired ones most recently expired first.
+func (r *postRepository) ListUserListings(ctx context.Context, userID string, state models.ListingState, limit, offset int) ([]*models.Post, int64, error) {
+	var where, orderBy string
+	switch state {
+	case models.ListingStateSold:
+		where = `sold = true`
+		orderBy = `sold_at DESC NULLS LAST, created_at DESC`
+	case models.ListingStateExpired:
+		where = `sold = false AND expired_at IS NOT NULL AND expired_at <= NOW()
+		  AND NOT EXISTS (
+			SELECT 1 FROM posts rp
+			WHERE rp.user_id = posts.user_id AND rp.client_token = '` + models.RelistClientTokenPrefix + `' || posts.id::text AND rp.deleted_at IS NULL
+		  )`
+		orderBy = `expired_at DESC`
+	default:
+		where = `sold = false AND status = true AND (expired_at IS NULL OR expired_at > NOW())`
+		orderBy = `bumped_at DESC`
+	}
+	where = `user_id = $1 AND type = 'SELL' AND deleted_at IS NULL AND ` + where
+
+	var total int64
+	if err := r.db.Reader().QueryRow(ctx, `SELECT COUNT(*) FROM posts WHERE `+where, userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT
+			id, user_id, business_id, original_post_id, category_id,
+			title, description, type, status, visibility,
+			currency, price, discount, free, sold, is_promoted, country_code, contact_no, is_location,
+			start_date, start_time, end_date, end_time, event_state, interested_count, going_count, expired_at,
+			` + locationSelectFragment + `,
+			country, province, district, neighborhood,
+			total_comments, total_likes, total_shares,
+			created_at, updated_at, deleted_at, group_id,
+			lost_found_kind, item_description, last_seen_place, last_seen_at, urgency, lang, bumped_at, version
+		FROM posts
+		WHERE ` + where + `
+		ORDER BY ` + orderBy + `
+		LIMIT $2 OFFSET $3
+	`
+	posts, err := r.queryPosts(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return posts, total, nil
+}
+
+// HasActionableReports is true while a report on the post is pending or
+// under review, or after one was resolved against it.
+func (r *postRepository) HasActionableReports(ctx context.Context, postID string) (bool, error) {
+	var reported bool
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM post_reports
+			WHERE post_id = $1 AND COALESCE(report_status, 'PENDING') <> 'REJECTED'
+		)
+	`, postID).Scan(&reported)
+	return reported, err
+}
+
 // GetPostsByIDs fetches multiple posts by their IDs in a single query.
 // Used by the fanout feed to hydrate post IDs returned from user_feeds.
 func (r *postRepository) GetPostsByIDs(ctx context.Context, ids []string) ([]*models.Post, error) {
@@ -1223,7 +1608,8 @@ func (r *postRepository) GetPostsByIDs(ctx context.Context, ids []string) ([]*mo
 		       ` + locationSelectFragment + `,
 		       country, province, district, neighborhood,
 		       total_comments, total_likes, total_shares,
-		       created_at, updated_at, deleted_at
+		       created_at, updated_at, deleted_at, group_id,
+		       lost_found_kind, item_description, last_seen_place, last_seen_at, urgency, lang, bumped_at, version
 		FROM posts
 		WHERE id = ANY($1) AND deleted_at IS NULL AND status = true`
 	return r.queryPosts(ctx, query, ids)
@@ -1253,7 +1639,8 @@ func (r *postRepository) queryPosts(ctx context.Context, query string, args ...i
 			&addrLng, &addrLat, &userLng, &userLat,
 			&post.Country, &post.Province, &post.District, &post.Neighborhood,
 			&post.TotalComments, &post.TotalLikes, &post.TotalShares,
-			&post.CreatedAt, &post.UpdatedAt, &post.DeletedAt,
+			&post.CreatedAt, &post.UpdatedAt, &post.DeletedAt, &post.GroupID,
+			&post.LostFoundKind, &post.ItemDescription, &post.LastSeenPlace, &post.LastSeenAt, &post.Urgency, &post.Lang, &post.BumpedAt, &post.Version,
 		)
 		if err != nil {
 			return nil, err
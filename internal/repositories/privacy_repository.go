@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+)
+
+// PrivacyRepository persists per-user profile privacy settings.
+type PrivacyRepository interface {
+	// Get returns the user's settings, or the defaults when none are stored.
+	Get(ctx context.Context, userID string) (*models.PrivacySettings, error)
+	// GetMany returns stored settings keyed by user id. Users without a
+	// row are absent; callers fall back to the defaults.
+	GetMany(ctx context.Context, userIDs []string) (map[string]*models.PrivacySettings, error)
+	Upsert(ctx context.Context, settings *models.PrivacySettings) error
+}
+
+type privacyRepository struct {
+	db *database.DB
+}
+
+// NewPrivacyRepository creates a new privacy repository
+func NewPrivacyRepository(db *database.DB) PrivacyRepository {
+	return &privacyRepository{db: db}
+}
+
+func (r *privacyRepository) Get(ctx context.Context, userID string) (*models.PrivacySettings, error) {
+	query := `
+		SELECT user_id, location_visibility, email_visibility, phone_visibility, profile_view_visibility, updated_at
+		FROM privacy_settings
+		WHERE user_id = $1
+	`
+	s := &models.PrivacySettings{}
+	err := r.db.Pool.QueryRow(ctx, query, userID).Scan(
+		&s.UserID, &s.LocationVisibility, &s.EmailVisibility, &s.PhoneVisibility, &s.ProfileViewVisibility, &s.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.DefaultPrivacySettings(userID), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get privacy settings: %w", err)
+	}
+	return s, nil
+}
+
+func (r *privacyRepository) GetMany(ctx context.Context, userIDs []string) (map[string]*models.PrivacySettings, error) {
+	settings := make(map[string]*models.PrivacySettings)
+	if len(userIDs) == 0 {
+		return settings, nil
+	}
+
+	query := `
+		SELECT user_id, location_visibility, email_visibility, phone_visibility, profile_view_visibility, updated_at
+		FROM privacy_settings
+		WHERE user_id = ANY($1)
+	`
+	rows, err := r.db.Pool.Query(ctx, query, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get privacy settings: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		s := &models.PrivacySettings{}
+		if err := rows.Scan(&s.UserID, &s.LocationVisibility, &s.EmailVisibility, &s.PhoneVisibility, &s.ProfileViewVisibility, &s.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan privacy settings: %w", err)
+		}
+		settings[s.UserID] = s
+	}
+	return settings, rows.Err()
+}
+
+func (r *privacyRepository) Upsert(ctx context.Context, s *models.PrivacySettings) error {
+	query := `
+		INSERT INTO privacy_settings (user_id, location_visibility, email_visibility, phone_visibility, profile_view_visibility, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (user_id) DO UPDATE SET
+			location_visibility = EXCLUDED.location_visibility,
+			email_visibility = EXCLUDED.email_visibility,
+			phone_visibility = EXCLUDED.phone_visibility,
+			profile_view_visibility = EXCLUDED.profile_view_visibility,
+			updated_at = NOW()
+		RETURNING updated_at
+	`
+	if err := r.db.Pool.QueryRow(ctx, query,
+		s.UserID, s.LocationVisibility, s.EmailVisibility, s.PhoneVisibility, s.ProfileViewVisibility,
+	).Scan(&s.UpdatedAt); err != nil {
+		return fmt.Errorf("failed to save privacy settings: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// ProfileMediaRepository stores avatars and covers held for review before
+// they replace a user's or business's current image.
+type ProfileMediaRepository interface {
+	// Submit holds a new image for review, superseding the owner's pending
+	// image of the same kind. Sets review.ID and EnqueuedAt.
+	Submit(ctx context.Context, review *models.ProfileMediaReview) error
+
+	// CancelPending supersedes the owner's pending image of that kind, if
+	// any.
+	CancelPending(ctx context.Context, ownerType, ownerID, kind string) error
+
+	// GetByID returns a review; ErrProfileMediaNotFound when there is none.
+	GetByID(ctx context.Context, id string) (*models.ProfileMediaReview, error)
+
+	// List returns up to limit reviews, optionally by status and flagged
+	// only. Pending first, flagged pending at the very top, newest first
+	// within each group.
+	List(ctx context.Context, status string, flaggedOnly bool, limit int) ([]*models.ProfileMediaReview, error)
+
+	// Counts returns the size of each status bucket, plus "flagged" for
+	// flagged images still pending.
+	Counts(ctx context.Context) (map[string]int64, error)
+
+	// Resolve moves a pending review to status ("approved" or "rejected").
+	// reviewerID is nil for automatic approvals. ErrProfileMediaNotPending
+	// when the review was already resolved or superseded.
+	Resolve(ctx context.Context, id, status string, reviewerID *string, notes string) error
+}
+
+type profileMediaRepository struct {
+	db *database.DB
+}
+
+// NewProfileMediaRepository wires a new profile media repository.
+func NewProfileMediaRepository(db *database.DB) ProfileMediaRepository {
+	return &profileMediaRepository{db: db}
+}
+
+var (
+	// ErrProfileMediaNotFound is returned for an unknown review id.
+	ErrProfileMediaNotFound = newNotFoundError("profile media review not found")
+	// ErrProfileMediaNotPending is returned when resolving a review that is
+	// no longer pending.
+	ErrProfileMediaNotPending = newConflictError("profile media review is not pending")
+)
+
+func (r *profileMediaRepository) Submit(ctx context.Context, review *models.ProfileMediaReview) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE profile_media_reviews SET status = 'superseded'
+		WHERE owner_type = $1 AND owner_id = $2 AND kind = $3 AND status = 'pending'
+	`, review.OwnerType, review.OwnerID, review.Kind); err != nil {
+		return fmt.Errorf("supersede profile media: %w", err)
+	}
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO profile_media_reviews (owner_type, owner_id, kind, photo)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, enqueued_at, status
+	`, review.OwnerType, review.OwnerID, review.Kind, review.Photo).Scan(&review.ID, &review.EnqueuedAt, &review.Status); err != nil {
+		return fmt.Errorf("submit profile media: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *profileMediaRepository) CancelPending(ctx context.Context, ownerType, ownerID, kind string) error {
+	if _, err := r.db.Pool.Exec(ctx, `
+		UPDATE profile_media_reviews SET status = 'superseded'
+		WHERE owner_type = $1 AND owner_id = $2 AND kind = $3 AND status = 'pending'
+	`, ownerType, ownerID, kind); err != nil {
+		return fmt.Errorf("cancel profile media: %w", err)
+	}
+	return nil
+}
+
+// profileMediaSelect reads reviews with the owner's display name.
+const profileMediaSelect = `
+	SELECT m.id::text, m.owner_type, m.owner_id::text,
+	       COALESCE(b.name, NULLIF(trim(COALESCE(p.first_name, '') || ' ' || COALESCE(p.last_name, '')), ''), u.email, ''),
+	       m.kind, m.photo, m.enqueued_at, m.status,
+	       m.reviewed_at, m.reviewed_by::text, m.review_notes,
+	       m.flagged, m.auto_labels, m.blurred_url
+	FROM profile_media_reviews m
+	LEFT JOIN business_profiles b ON m.owner_type = 'business' AND b.id = m.owner_id
+	LEFT JOIN users u ON m.owner_type = 'user' AND u.id = m.owner_id
+	LEFT JOIN profiles p ON m.owner_type = 'user' AND p.user_id = m.owner_id
+`
+
+func scanProfileMediaReview(row pgx.Row) (*models.ProfileMediaReview, error) {
+	m := &models.ProfileMediaReview{}
+	err := row.Scan(
+		&m.ID, &m.OwnerType, &m.OwnerID, &m.OwnerName,
+		&m.Kind, &m.Photo, &m.EnqueuedAt, &m.Status,
+		&m.ReviewedAt, &m.ReviewedBy, &m.ReviewNotes,
+		&m.Flagged, &m.AutoLabels, &m.BlurredURL,
+	)
+	return m, err
+}
+
+func (r *profileMediaRepository) GetByID(ctx context.Context, id string) (*models.ProfileMediaReview, error) {
+	m, err := scanProfileMediaReview(r.db.Pool.QueryRow(ctx, profileMediaSelect+` WHERE m.id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrProfileMediaNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get profile media: %w", err)
+	}
+	return m, nil
+}
+
+func (r *profileMediaRepository) List(ctx context.Context, status string, flaggedOnly bool, limit int) ([]*models.ProfileMediaReview, error) {
+	q := profileMediaSelect + ` WHERE TRUE`
+	args := []interface{}{}
+	if status != "" {
+		args = append(args, status)
+		q += fmt.Sprintf(" AND m.status = $%d", len(args))
+	}
+	if flaggedOnly {
+		q += " AND m.flagged"
+	}
+	args = append(args, limit)
+	q += fmt.Sprintf(" ORDER BY (m.status = 'pending') DESC, (m.status = 'pending' AND m.flagged) DESC, m.enqueued_at DESC LIMIT $%d", len(args))
+
+	rows, err := r.db.Pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list profile media: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]*models.ProfileMediaReview, 0)
+	for rows.Next() {
+		m, err := scanProfileMediaReview(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan profile media: %w", err)
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+func (r *profileMediaRepository) Counts(ctx context.Context) (map[string]int64, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT status, COUNT(*) FROM profile_media_reviews GROUP BY status
+		UNION ALL
+		SELECT 'flagged', COUNT(*) FROM profile_media_reviews WHERE status = 'pending' AND flagged`)
+	if err != nil {
+		return nil, fmt.Errorf("count profile media: %w", err)
+	}
+	defer rows.Close()
+
+	out := map[string]int64{"pending": 0, "approved": 0, "rejected": 0, "flagged": 0}
+	for rows.Next() {
+		var status string
+		var n int64
+		if err := rows.Scan(&status, &n); err != nil {
+			return nil, fmt.Errorf("scan profile media count: %w", err)
+		}
+		out[status] = n
+	}
+	return out, rows.Err()
+}
+
+func (r *profileMediaRepository) Resolve(ctx context.Context, id, status string, reviewerID *string, notes string) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE profile_media_reviews
+		SET status = $2, reviewed_at = NOW(), reviewed_by = $3, review_notes = NULLIF($4, '')
+		WHERE id = $1 AND status = 'pending'
+	`, id, status, reviewerID, notes)
+	if err != nil {
+		return fmt.Errorf("resolve profile media: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrProfileMediaNotPending
+	}
+	return nil
+}
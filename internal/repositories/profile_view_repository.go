@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+)
+
+// ProfileViewRepository stores profile views: anonymous daily counts for
+// personal profiles and per-day viewers of business profiles.
+type ProfileViewRepository interface {
+	// IncrementProfileView adds one view to the user's bucket for today.
+	IncrementProfileView(ctx context.Context, userID string) error
+	// GetDailyProfileViews returns the user's views per day for the
+	// trailing days window, zero-filled.
+	GetDailyProfileViews(ctx context.Context, userID string, days int) ([]models.DailyCount, error)
+
+	// RecordBusinessViewer notes that viewerID viewed the business today.
+	RecordBusinessViewer(ctx context.Context, businessID, viewerID string) error
+	// ListBusinessViewers returns the viewers of the last days days whose
+	// privacy settings share the visit with the business, most recent
+	// first. total counts the listed viewers, all every distinct viewer.
+	ListBusinessViewers(ctx context.Context, businessID string, days, limit, offset int) (viewers []*models.BusinessViewer, total, all int, err error)
+	// GetDailyUniqueViewers returns distinct signed-in viewers per day,
+	// zero-filled. Days older than the retention window read as zero.
+	GetDailyUniqueViewers(ctx context.Context, businessID string, days int) ([]models.DailyCount, error)
+	// DeleteBusinessViewersBefore prunes viewer rows older than before.
+	DeleteBusinessViewersBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type profileViewRepository struct {
+	db *database.DB
+}
+
+// NewProfileViewRepository creates a new profile view repository.
+func NewProfileViewRepository(db *database.DB) ProfileViewRepository {
+	return &profileViewRepository{db: db}
+}
+
+func (r *profileViewRepository) IncrementProfileView(ctx context.Context, userID string) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		INSERT INTO profile_daily_views (user_id, day, views)
+		VALUES ($1, CURRENT_DATE, 1)
+		ON CONFLICT (user_id, day)
+		DO UPDATE SET views = profile_daily_views.views + 1
+	`, userID)
+	if err != nil {
+		return fmt.Errorf("increment profile views: %w", err)
+	}
+	return nil
+}
+
+func (r *profileViewRepository) GetDailyProfileViews(ctx context.Context, userID string, days int) ([]models.DailyCount, error) {
+	return r.queryDailyCounts(ctx, `
+		SELECT d::date, COALESCE(v.views, 0)
+		FROM generate_series(CURRENT_DATE - ($2::int - 1), CURRENT_DATE, '1 day') AS d
+		LEFT JOIN profile_daily_views v
+		  ON v.user_id = $1 AND v.day = d::date
+		ORDER BY d
+	`, userID, days)
+}
+
+func (r *profileViewRepository) RecordBusinessViewer(ctx context.Context, businessID, viewerID string) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		INSERT INTO business_profile_viewers (business_id, viewer_id, day, viewed_at)
+		VALUES ($1, $2, CURRENT_DATE, NOW())
+		ON CONFLICT (business_id, day, viewer_id)
+		DO UPDATE SET viewed_at = NOW()
+	`, businessID, viewerID)
+	if err != nil {
+		return fmt.Errorf("record business viewer: %w", err)
+	}
+	return nil
+}
+
+func (r *profileViewRepository) ListBusinessViewers(ctx context.Context, businessID string, days, limit, offset int) ([]*models.BusinessViewer, int, int, error) {
+	// Users without privacy settings share visits with businesses they
+	// follow, same as DefaultPrivacySettings.
+	const visits = `
+		WITH visits AS (
+			SELECT v.viewer_id, MAX(v.viewed_at) AS last_viewed_at, COUNT(*) AS visit_days
+			FROM business_profile_viewers v
+			JOIN users u ON u.id = v.viewer_id AND u.deleted_at IS NULL
+			WHERE v.business_id = $1 AND v.day >= CURRENT_DATE - ($2::int - 1)
+			GROUP BY v.viewer_id
+		), shared AS (
+			SELECT visits.*,
+			       EXISTS (
+			           SELECT 1 FROM business_profile_followers f
+			           WHERE f.business_id = $1 AND f.follower_id = visits.viewer_id AND f.is_active = true
+			       ) AS is_follower,
+			       COALESCE(ps.profile_view_visibility, 'FOLLOWERS') AS visibility
+			FROM visits
+			LEFT JOIN privacy_settings ps ON ps.user_id = visits.viewer_id
+		), listed AS (
+			SELECT * FROM shared
+			WHERE visibility = 'EVERYONE' OR (visibility = 'FOLLOWERS' AND is_follower)
+		)`
+
+	var total, all int
+	if err := r.db.Reader().QueryRow(ctx, visits+`
+		SELECT (SELECT COUNT(*) FROM listed), (SELECT COUNT(*) FROM visits)
+	`, businessID, days).Scan(&total, &all); err != nil {
+		return nil, 0, 0, fmt.Errorf("count business viewers: %w", err)
+	}
+
+	rows, err := r.db.Reader().Query(ctx, visits+`
+		SELECT l.viewer_id, p.first_name, p.last_name, p.avatar, p.avatar_color,
+		       l.is_follower, l.visit_days, l.last_viewed_at
+		FROM listed l
+		LEFT JOIN profiles p ON p.id = l.viewer_id
+		ORDER BY l.last_viewed_at DESC, l.viewer_id
+		LIMIT $3 OFFSET $4
+	`, businessID, days, limit, offset)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("list business viewers: %w", err)
+	}
+	defer rows.Close()
+
+	viewers := make([]*models.BusinessViewer, 0)
+	for rows.Next() {
+		v := &models.BusinessViewer{}
+		if err := rows.Scan(&v.UserID, &v.FirstName, &v.LastName, &v.Avatar, &v.AvatarColor,
+			&v.IsFollower, &v.VisitDays, &v.LastViewed); err != nil {
+			return nil, 0, 0, fmt.Errorf("scan business viewer: %w", err)
+		}
+		viewers = append(viewers, v)
+	}
+	return viewers, total, all, rows.Err()
+}
+
+func (r *profileViewRepository) GetDailyUniqueViewers(ctx context.Context, businessID string, days int) ([]models.DailyCount, error) {
+	return r.queryDailyCounts(ctx, `
+		SELECT d::date, COALESCE(v.cnt, 0)
+		FROM generate_series(CURRENT_DATE - ($2::int - 1), CURRENT_DATE, '1 day') AS d
+		LEFT JOIN (
+		  SELECT day, COUNT(*) AS cnt
+		  FROM business_profile_viewers
+		  WHERE business_id = $1 AND day >= CURRENT_DATE - ($2::int - 1)
+		  GROUP BY day
+		) v ON v.day = d::date
+		ORDER BY d
+	`, businessID, days)
+}
+
+func (r *profileViewRepository) DeleteBusinessViewersBefore(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM business_profile_viewers WHERE day < $1::date`, before)
+	if err != nil {
+		return 0, fmt.Errorf("prune business viewers: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *profileViewRepository) queryDailyCounts(ctx context.Context, query, id string, days int) ([]models.DailyCount, error) {
+	rows, err := r.db.Pool.Query(ctx, query, id, days)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make([]models.DailyCount, 0, days)
+	for rows.Next() {
+		var day time.Time
+		var count int
+		if err := rows.Scan(&day, &count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, models.DailyCount{Date: day.Format("2006-01-02"), Count: count})
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,268 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// RegionalTrendingRepository maintains the per-province / per-district
+// trending rollup and reads it back. Region arguments are expected lower-cased
+// and trimmed; district "" means province-wide.
+type RegionalTrendingRepository interface {
+	// Rollup recomputes every region from activity since the given time,
+	// keeping the top perKind items of each kind. It replaces the previous
+	// rollup atomically.
+	Rollup(ctx context.Context, since time.Time, perKind int) error
+	// GetActivity returns the region's totals, or nil when the rollup has
+	// nothing for it.
+	GetActivity(ctx context.Context, province, district string) (*models.RegionalActivity, error)
+	// ListActivity returns the totals of every region with activity.
+	ListActivity(ctx context.Context) ([]*models.RegionalActivity, error)
+	// ListPosts returns the region's ranked posts of kind POST or EVENT,
+	// skipping any deleted or hidden since the rollup ran.
+	ListPosts(ctx context.Context, province, district, kind string, limit int) ([]*models.TrendingPost, error)
+	// ListBusinesses returns the region's new businesses, best first.
+	ListBusinesses(ctx context.Context, province, district string, limit int) ([]*models.TrendingBusiness, error)
+	// DigestRecipients returns users who opted into the weekly digest and
+	// haven't received the one for week yet, in id order after afterUserID.
+	DigestRecipients(ctx context.Context, week string, afterUserID *string, limit int) ([]*models.DigestRecipient, error)
+}
+
+type regionalTrendingRepository struct {
+	db *database.DB
+}
+
+// NewRegionalTrendingRepository creates the repository.
+func NewRegionalTrendingRepository(db *database.DB) RegionalTrendingRepository {
+	return &regionalTrendingRepository{db: db}
+}
+
+// regionalItems lists everything that happened in a region since $1: public
+// posts and upcoming events with their engagement score, and businesses that
+// joined. Each item is counted in its district and in its province (district
+// ”); items without a district only count province-wide.
+var regionalItems = `
+	WITH items AS (
+		SELECT CASE WHEN p.type = 'EVENT' THEN 'EVENT' ELSE 'POST' END AS kind,
+			p.id AS item_id,
+			LOWER(TRIM(p.province)) AS province,
+			NULLIF(LOWER(TRIM(p.district)), '') AS district,
+			(p.total_likes * 2 + p.total_comments * 3 + p.total_shares * 5
+				+ CASE WHEN p.type = 'EVENT' THEN p.going_count * 3 + p.interested_count * 2 ELSE 0 END
+			)::float8 AS score,
+			p.created_at
+		FROM posts p
+		WHERE p.deleted_at IS NULL
+			AND p.status = true
+			AND p.visibility = 'PUBLIC'
+			AND p.group_id IS NULL
+			AND NULLIF(TRIM(p.province), '') IS NOT NULL
+			AND (p.type <> 'SELL' OR p.sold = false)
+			AND (
+				(p.type <> 'EVENT' AND p.created_at >= $1)
+				OR (p.type = 'EVENT'
+					AND (p.start_date IS NULL OR p.start_date >= CURRENT_DATE)
+					AND (p.created_at >= $1 OR p.start_date < CURRENT_DATE + 7))
+			)` + excludeShadowbanned("p.user_id", 0) + `
+		UNION ALL
+		SELECT 'BUSINESS', bp.id,
+			LOWER(TRIM(bp.province)),
+			NULLIF(LOWER(TRIM(bp.district)), ''),
+			bp.total_follow::float8,
+			bp.created_at
+		FROM business_profiles bp
+		WHERE bp.deleted_at IS NULL
+			AND bp.status = true
+			AND bp.created_at >= $1
+			AND NULLIF(TRIM(bp.province), '') IS NOT NULL
+	),
+	regional AS (
+		SELECT i.kind, i.item_id, i.province, r.district, i.score, i.created_at
+		FROM items i
+		CROSS JOIN LATERAL (VALUES (i.district), ('')) r(district)
+		WHERE r.district IS NOT NULL
+	)`
+
+func (r *regionalTrendingRepository) Rollup(ctx context.Context, since time.Time, perKind int) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `DELETE FROM regional_trending`); err != nil {
+		return fmt.Errorf("clear regional trending: %w", err)
+	}
+	if _, err := tx.Exec(ctx, regionalItems+`
+		INSERT INTO regional_trending (province, district, kind, item_id, rank, score, computed_at)
+		SELECT province, district, kind, item_id, rank, score, NOW()
+		FROM (
+			SELECT province, district, kind, item_id, score,
+				ROW_NUMBER() OVER (PARTITION BY province, district, kind ORDER BY score DESC, created_at DESC) AS rank
+			FROM regional
+		) ranked
+		WHERE rank <= $2
+	`, since, perKind); err != nil {
+		return fmt.Errorf("rank regional trending: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM regional_activity`); err != nil {
+		return fmt.Errorf("clear regional activity: %w", err)
+	}
+	if _, err := tx.Exec(ctx, regionalItems+`
+		INSERT INTO regional_activity (province, district, new_posts, upcoming_events, new_businesses, computed_at)
+		SELECT province, district,
+			COUNT(*) FILTER (WHERE kind = 'POST'),
+			COUNT(*) FILTER (WHERE kind = 'EVENT'),
+			COUNT(*) FILTER (WHERE kind = 'BUSINESS'),
+			NOW()
+		FROM regional
+		GROUP BY province, district
+	`, since); err != nil {
+		return fmt.Errorf("count regional activity: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+const regionalActivityColumns = `province, district, new_posts, upcoming_events, new_businesses, computed_at`
+
+func (r *regionalTrendingRepository) GetActivity(ctx context.Context, province, district string) (*models.RegionalActivity, error) {
+	a := &models.RegionalActivity{}
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT `+regionalActivityColumns+`
+		FROM regional_activity
+		WHERE province = $1 AND district = $2
+	`, province, district).Scan(&a.Province, &a.District, &a.NewPosts, &a.UpcomingEvents, &a.NewBusinesses, &a.ComputedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (r *regionalTrendingRepository) ListActivity(ctx context.Context) ([]*models.RegionalActivity, error) {
+	rows, err := r.db.Pool.Query(ctx, `SELECT `+regionalActivityColumns+` FROM regional_activity`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*models.RegionalActivity
+	for rows.Next() {
+		a := &models.RegionalActivity{}
+		if err := rows.Scan(&a.Province, &a.District, &a.NewPosts, &a.UpcomingEvents, &a.NewBusinesses, &a.ComputedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+func (r *regionalTrendingRepository) ListPosts(ctx context.Context, province, district, kind string, limit int) ([]*models.TrendingPost, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT p.id, p.type, p.title, p.description, p.province, p.district, p.neighborhood,
+			p.total_likes, p.total_comments, p.total_shares, p.going_count,
+			to_char(p.start_date, 'YYYY-MM-DD'), to_char(p.start_time, 'HH24:MI'),
+			t.score, p.created_at
+		FROM regional_trending t
+		JOIN posts p ON p.id = t.item_id
+		WHERE t.province = $1 AND t.district = $2 AND t.kind = $3
+			AND p.deleted_at IS NULL
+			AND p.status = true
+	`+excludeShadowbanned("p.user_id", 0)+`
+		ORDER BY t.rank
+		LIMIT $4
+	`, province, district, kind, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trending posts: %w", err)
+	}
+	defer rows.Close()
+
+	posts := make([]*models.TrendingPost, 0, limit)
+	for rows.Next() {
+		p := &models.TrendingPost{}
+		if err := rows.Scan(
+			&p.ID, &p.Type, &p.Title, &p.Description, &p.Province, &p.District, &p.Neighborhood,
+			&p.TotalLikes, &p.TotalComments, &p.TotalShares, &p.GoingCount,
+			&p.StartDate, &p.StartTime,
+			&p.Score, &p.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan trending post: %w", err)
+		}
+		posts = append(posts, p)
+	}
+	return posts, rows.Err()
+}
+
+func (r *regionalTrendingRepository) ListBusinesses(ctx context.Context, province, district string, limit int) ([]*models.TrendingBusiness, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT bp.id, bp.user_id, bp.name, bp.avatar, bp.province, bp.district, bp.neighborhood,
+			bp.total_follow, bp.created_at
+		FROM regional_trending t
+		JOIN business_profiles bp ON bp.id = t.item_id
+		WHERE t.province = $1 AND t.district = $2 AND t.kind = 'BUSINESS'
+			AND bp.deleted_at IS NULL
+			AND bp.status = true
+		ORDER BY t.rank
+		LIMIT $3
+	`, province, district, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trending businesses: %w", err)
+	}
+	defer rows.Close()
+
+	businesses := make([]*models.TrendingBusiness, 0, limit)
+	for rows.Next() {
+		b := &models.TrendingBusiness{}
+		if err := rows.Scan(
+			&b.ID, &b.UserID, &b.Name, &b.Avatar, &b.Province, &b.District, &b.Neighborhood,
+			&b.TotalFollow, &b.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan trending business: %w", err)
+		}
+		businesses = append(businesses, b)
+	}
+	return businesses, rows.Err()
+}
+
+func (r *regionalTrendingRepository) DigestRecipients(ctx context.Context, week string, afterUserID *string, limit int) ([]*models.DigestRecipient, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT pr.id, TRIM(pr.province), COALESCE(TRIM(pr.district), '')
+		FROM profiles pr
+		JOIN notification_settings ns
+			ON ns.profile_id = pr.id AND ns.category = 'DIGEST' AND ns.push_pref = true
+		WHERE pr.deleted_at IS NULL
+			AND NULLIF(TRIM(pr.province), '') IS NOT NULL
+			AND ($2::uuid IS NULL OR pr.id > $2)
+			AND NOT EXISTS (
+				SELECT 1 FROM notifications n
+				WHERE n.user_id = pr.id
+					AND n.type = 'WEEKLY_DIGEST'
+					AND n.data->>'week' = $1
+			)
+		ORDER BY pr.id
+		LIMIT $3
+	`, week, afterUserID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recipients := make([]*models.DigestRecipient, 0, limit)
+	for rows.Next() {
+		d := &models.DigestRecipient{}
+		if err := rows.Scan(&d.UserID, &d.Province, &d.District); err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, d)
+	}
+	return recipients, rows.Err()
+}
@@ -0,0 +1,60 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/testutil"
+)
+
+func newRegionalTrendingRepo(pool *testutil.MockPool) repositories.RegionalTrendingRepository {
+	return repositories.NewRegionalTrendingRepository(testutil.NewTestDB(pool))
+}
+
+func TestRegionalTrendingRepository_ListPosts_SQL(t *testing.T) {
+	pool := new(testutil.MockPool)
+	pages := capture(pool, "Query")
+
+	_, err := newRegionalTrendingRepo(pool).ListPosts(context.Background(), "kabul", "", models.TrendingKindEvent, 5)
+	require.Error(t, err)
+
+	sql := (*pages)[0].sql
+	assert.Contains(t, sql, "FROM regional_trending t JOIN posts p ON p.id = t.item_id")
+	assert.Contains(t, sql, "WHERE t.province = $1 AND t.district = $2 AND t.kind = $3 AND p.deleted_at IS NULL AND p.status = true")
+	assert.Contains(t, sql, "sb.shadowbanned_at IS NOT NULL")
+	assert.Contains(t, sql, "ORDER BY t.rank LIMIT $4")
+	assert.Equal(t, []any{"kabul", "", "EVENT", 5}, (*pages)[0].args)
+}
+
+func TestRegionalTrendingRepository_GetActivity_NoRollup(t *testing.T) {
+	pool := new(testutil.MockPool)
+	pool.On("QueryRow", mock.Anything, mock.Anything, []any{"kabul", "khair khana"}).
+		Return(testutil.ErrRow(pgx.ErrNoRows))
+
+	activity, err := newRegionalTrendingRepo(pool).GetActivity(context.Background(), "kabul", "khair khana")
+	require.NoError(t, err)
+	assert.Nil(t, activity)
+}
+
+func TestRegionalTrendingRepository_DigestRecipients_SQL(t *testing.T) {
+	pool := new(testutil.MockPool)
+	pages := capture(pool, "Query")
+	cursor := "user-9"
+
+	_, err := newRegionalTrendingRepo(pool).DigestRecipients(context.Background(), "2026-W42", &cursor, 500)
+	require.Error(t, err)
+
+	sql := (*pages)[0].sql
+	assert.Contains(t, sql, "ns.category = 'DIGEST' AND ns.push_pref = true")
+	assert.Contains(t, sql, "n.type = 'WEEKLY_DIGEST' AND n.data->>'week' = $1")
+	assert.Contains(t, sql, "AND ($2::uuid IS NULL OR pr.id > $2)")
+	assert.Contains(t, sql, "ORDER BY pr.id LIMIT $3")
+	assert.Equal(t, []any{"2026-W42", &cursor, 500}, (*pages)[0].args)
+}
@@ -2,11 +2,14 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
 )
 
 // RelationshipsRepository defines the interface for user relationship operations
@@ -25,11 +28,23 @@ type RelationshipsRepository interface {
 	UnblockUser(ctx context.Context, blockerID, blockedID string) error
 	IsBlocked(ctx context.Context, blockerID, blockedID string) (bool, error)
 	GetBlockedUsers(ctx context.Context, blockerID string, limit, offset int) ([]*models.UserBlock, error)
+	CountBlockedUsers(ctx context.Context, blockerID string) (int, error)
+	// BlockUsers blocks every live account in blockedIDs (other than the
+	// blocker) and drops follows either way between them, returning the IDs
+	// newly blocked. Nothing is written and ErrBlockListFull is returned when
+	// the block list would exceed maxBlocks.
+	BlockUsers(ctx context.Context, blockerID string, blockedIDs []string, maxBlocks int) ([]string, error)
 
 	// Relationship status
 	GetRelationshipStatus(ctx context.Context, viewerID, targetUserID string) (*models.RelationshipStatus, error)
+	// GetRelationshipStatuses is the batch form of GetRelationshipStatus,
+	// keyed by target user id.
+	GetRelationshipStatuses(ctx context.Context, viewerID string, targetUserIDs []string) (map[string]*models.RelationshipStatus, error)
 }
 
+// ErrBlockListFull is returned when a block would exceed the block list cap.
+var ErrBlockListFull = errors.New("block list is full")
+
 type relationshipsRepository struct {
 	db *database.DB
 }
@@ -251,6 +266,67 @@ func (r *relationshipsRepository) GetBlockedUsers(ctx context.Context, blockerID
 	return blocks, rows.Err()
 }
 
+// CountBlockedUsers counts the users blockerID has blocked.
+func (r *relationshipsRepository) CountBlockedUsers(ctx context.Context, blockerID string) (int, error) {
+	var count int
+	err := r.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM user_blocks WHERE blocker_id = $1`, blockerID).Scan(&count)
+	return count, err
+}
+
+// BlockUsers blocks many users in one transaction.
+func (r *relationshipsRepository) BlockUsers(ctx context.Context, blockerID string, blockedIDs []string, maxBlocks int) ([]string, error) {
+	var blocked []string
+	err := r.db.WithTransaction(ctx, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			INSERT INTO user_blocks (id, blocker_id, blocked_id, created_at)
+			SELECT uuid_generate_v4(), $1, u.id, NOW()
+			FROM users u
+			WHERE u.id = ANY($2::uuid[]) AND u.id <> $1 AND u.deleted_at IS NULL
+			ON CONFLICT (blocker_id, blocked_id) DO NOTHING
+			RETURNING blocked_id
+		`, blockerID, blockedIDs)
+		if err != nil {
+			return fmt.Errorf("bulk block: %w", err)
+		}
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return fmt.Errorf("bulk block: %w", err)
+			}
+			blocked = append(blocked, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("bulk block: %w", err)
+		}
+		if len(blocked) == 0 {
+			return nil
+		}
+
+		var count int
+		if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM user_blocks WHERE blocker_id = $1`, blockerID).Scan(&count); err != nil {
+			return fmt.Errorf("count blocks: %w", err)
+		}
+		if count > maxBlocks {
+			return ErrBlockListFull
+		}
+
+		if _, err := tx.Exec(ctx, `
+			DELETE FROM user_follows
+			WHERE (follower_id = $1 AND following_id = ANY($2::uuid[]))
+			   OR (following_id = $1 AND follower_id = ANY($2::uuid[]))
+		`, blockerID, blocked); err != nil {
+			return fmt.Errorf("drop follows of blocked users: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return blocked, nil
+}
+
 // GetRelationshipStatus gets the complete relationship status between two users
 func (r *relationshipsRepository) GetRelationshipStatus(ctx context.Context, viewerID, targetUserID string) (*models.RelationshipStatus, error) {
 	query := `
@@ -271,3 +347,38 @@ func (r *relationshipsRepository) GetRelationshipStatus(ctx context.Context, vie
 
 	return status, err
 }
+
+// GetRelationshipStatuses gets the relationship status between viewerID and
+// each target in one query.
+func (r *relationshipsRepository) GetRelationshipStatuses(ctx context.Context, viewerID string, targetUserIDs []string) (map[string]*models.RelationshipStatus, error) {
+	out := make(map[string]*models.RelationshipStatus, len(targetUserIDs))
+	if len(targetUserIDs) == 0 {
+		return out, nil
+	}
+
+	query := `
+		SELECT
+			t.id::text,
+			EXISTS(SELECT 1 FROM user_follows WHERE follower_id = $1 AND following_id = t.id) AS is_following,
+			EXISTS(SELECT 1 FROM user_follows WHERE follower_id = t.id AND following_id = $1) AS is_followed_by,
+			EXISTS(SELECT 1 FROM user_blocks WHERE blocker_id = $1 AND blocked_id = t.id) AS is_blocked,
+			EXISTS(SELECT 1 FROM user_blocks WHERE blocker_id = t.id AND blocked_id = $1) AS has_blocked_me
+		FROM unnest($2::uuid[]) AS t(id)
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, viewerID, targetUserIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		status := &models.RelationshipStatus{}
+		if err := rows.Scan(&id, &status.IsFollowing, &status.IsFollowedBy, &status.IsBlocked, &status.HasBlockedMe); err != nil {
+			return nil, err
+		}
+		out[id] = status
+	}
+	return out, rows.Err()
+}
@@ -3,6 +3,7 @@ package repositories_test
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/jackc/pgx/v5/pgconn"
@@ -153,3 +154,57 @@ func TestRelationshipsRepository_BlockUser(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestRelationshipsRepository_BlockUsers(t *testing.T) {
+	insertedRows := func(ids ...string) *testutil.FuncRows {
+		scans := make([]func(dest ...any) error, len(ids))
+		for i, id := range ids {
+			id := id
+			scans[i] = func(dest ...any) error {
+				*dest[0].(*string) = id
+				return nil
+			}
+		}
+		return testutil.NewFuncRows(scans...)
+	}
+	countRow := func(n int) *testutil.MockRow {
+		return testutil.NewMockRow(func(dest ...any) error {
+			*dest[0].(*int) = n
+			return nil
+		})
+	}
+
+	t.Run("blocks and drops follows", func(t *testing.T) {
+		pool := new(testutil.MockPool)
+		tx := new(testutil.MockTx)
+		repo := newRelRepo(pool)
+
+		pool.On("Begin", mock.Anything).Return(tx, nil)
+		tx.On("Query", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(insertedRows("user-2"), nil)
+		tx.On("QueryRow", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(countRow(10))
+		tx.On("Exec", mock.Anything, mock.MatchedBy(func(sql string) bool {
+			return strings.Contains(sql, "DELETE FROM user_follows")
+		}), []any{"user-1", []string{"user-2"}}).Return(pgconn.NewCommandTag("DELETE 1"), nil)
+		tx.On("Commit", mock.Anything).Return(nil)
+
+		blocked, err := repo.BlockUsers(context.Background(), "user-1", []string{"user-2", "user-3"}, 100)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"user-2"}, blocked)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("rolls back over the cap", func(t *testing.T) {
+		pool := new(testutil.MockPool)
+		tx := new(testutil.MockTx)
+		repo := newRelRepo(pool)
+
+		pool.On("Begin", mock.Anything).Return(tx, nil)
+		tx.On("Query", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(insertedRows("user-2", "user-3"), nil)
+		tx.On("QueryRow", mock.Anything, mock.AnythingOfType("string"), mock.Anything).Return(countRow(101))
+		tx.On("Rollback", mock.Anything).Return(nil)
+
+		_, err := repo.BlockUsers(context.Background(), "user-1", []string{"user-2", "user-3"}, 100)
+		require.ErrorIs(t, err, repositories.ErrBlockListFull)
+		tx.AssertNotCalled(t, "Commit", mock.Anything)
+	})
+}
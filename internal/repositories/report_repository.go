@@ -2,7 +2,6 @@ package repositories
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,6 +11,10 @@ import (
 	"go.uber.org/zap"
 )
 
+// ErrAlreadyReported is returned by the Create*Report methods when the
+// reporter has already reported the same item.
+var ErrAlreadyReported = newConflictError("already reported")
+
 // ReportRepository defines the interface for report operations
 type ReportRepository interface {
 	// Post reports
@@ -38,6 +41,9 @@ type ReportRepository interface {
 	ListBusinessReports(ctx context.Context, limit, offset int) ([]*models.BusinessReport, int, error)
 	UpdateBusinessReportStatus(ctx context.Context, id string, status models.ReportStatus) error
 
+	// Message reports
+	CreateMessageReport(ctx context.Context, report *models.MessageReport) error
+
 	// Auto-action helpers — run after each new report so the platform reacts
 	// without admin intervention when a content item has crossed the
 	// community-flag threshold.
@@ -90,6 +96,9 @@ func (r *reportRepository) CreatePostReport(ctx context.Context, report *models.
 		report.CreatedAt,
 		report.UpdatedAt,
 	)
+	if isUniqueViolation(err) {
+		return ErrAlreadyReported
+	}
 
 	return err
 }
@@ -194,7 +203,7 @@ func (r *reportRepository) UpdatePostReportStatus(ctx context.Context, id string
 
 	if result.RowsAffected() == 0 {
 		r.logger.Warnw("Post report not found for status update", "report_id", id)
-		return fmt.Errorf("report not found")
+		return newNotFoundError("report not found")
 	}
 
 	r.logger.Infow("Post report status updated successfully",
@@ -234,6 +243,9 @@ func (r *reportRepository) CreateCommentReport(ctx context.Context, report *mode
 		report.UpdatedAt,
 	)
 
+	if isUniqueViolation(err) {
+		return ErrAlreadyReported
+	}
 	if err != nil {
 		r.logger.Errorw("Failed to create comment report", "error", err)
 	}
@@ -331,7 +343,7 @@ func (r *reportRepository) UpdateCommentReportStatus(ctx context.Context, id str
 
 	if result.RowsAffected() == 0 {
 		r.logger.Warnw("Comment report not found for status update", "report_id", id)
-		return fmt.Errorf("report not found")
+		return newNotFoundError("report not found")
 	}
 
 	return nil
@@ -367,6 +379,9 @@ func (r *reportRepository) CreateUserReport(ctx context.Context, report *models.
 		report.UpdatedAt,
 	)
 
+	if isUniqueViolation(err) {
+		return ErrAlreadyReported
+	}
 	if err != nil {
 		r.logger.Errorw("Failed to create user report", "error", err)
 	}
@@ -464,7 +479,7 @@ func (r *reportRepository) UpdateUserReportResolved(ctx context.Context, id stri
 
 	if result.RowsAffected() == 0 {
 		r.logger.Warnw("User report not found for resolved status update", "report_id", id)
-		return fmt.Errorf("report not found")
+		return newNotFoundError("report not found")
 	}
 
 	return nil
@@ -500,6 +515,9 @@ func (r *reportRepository) CreateBusinessReport(ctx context.Context, report *mod
 		report.UpdatedAt,
 	)
 
+	if isUniqueViolation(err) {
+		return ErrAlreadyReported
+	}
 	if err != nil {
 		r.logger.Errorw("Failed to create business report", "error", err)
 	}
@@ -597,12 +615,59 @@ func (r *reportRepository) UpdateBusinessReportStatus(ctx context.Context, id st
 
 	if result.RowsAffected() == 0 {
 		r.logger.Warnw("Business report not found for status update", "report_id", id)
-		return fmt.Errorf("report not found")
+		return newNotFoundError("report not found")
 	}
 
 	return nil
 }
 
+// Message Reports
+
+func (r *reportRepository) CreateMessageReport(ctx context.Context, report *models.MessageReport) error {
+	report.ID = uuid.New().String()
+	report.CreatedAt = time.Now()
+	report.UpdatedAt = time.Now()
+
+	r.logger.Infow("Creating message report",
+		"report_id", report.ID,
+		"reporter_id", report.UserID,
+		"message_id", report.MessageID,
+		"reason", report.Reason,
+	)
+
+	query := `
+		INSERT INTO message_reports (
+			id, user_id, message_id, conversation_id, sender_id, message_content, message_type, message_sent_at,
+			reason, additional_comments, report_status, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+
+	_, err := r.db.Pool.Exec(ctx, query,
+		report.ID,
+		report.UserID,
+		report.MessageID,
+		report.ConversationID,
+		report.SenderID,
+		report.MessageContent,
+		report.MessageType,
+		report.MessageSentAt,
+		report.Reason,
+		report.AdditionalComments,
+		report.ReportStatus,
+		report.CreatedAt,
+		report.UpdatedAt,
+	)
+
+	if isUniqueViolation(err) {
+		return ErrAlreadyReported
+	}
+	if err != nil {
+		r.logger.Errorw("Failed to create message report", "error", err)
+	}
+	return err
+}
+
 // ─── Auto-action helpers ──────────────────────────────────────────────────
 
 func (r *reportRepository) CountPendingPostReports(ctx context.Context, postID string) (int, error) {
@@ -13,10 +13,28 @@ import (
 // SearchRepository defines the interface for search operations
 type SearchRepository interface {
 	SearchPosts(ctx context.Context, filter *models.SearchFilter) ([]*models.Post, error)
+	GetPostSearchFacets(ctx context.Context, filter *models.SearchFilter) (*models.SearchFacets, error)
+	GetPostHighlights(ctx context.Context, postIDs []string, query string) (map[string]*models.SearchHighlight, error)
 	SearchUsers(ctx context.Context, filter *models.SearchFilter) ([]*models.Profile, error)
 	SearchBusinesses(ctx context.Context, filter *models.SearchFilter) ([]*models.BusinessProfile, error)
 	GetDiscoverPosts(ctx context.Context, lat, lng, radiusKm float64, postType *models.PostType, limit int) ([]*models.Post, error)
 	GetDiscoverBusinesses(ctx context.Context, lat, lng, radiusKm float64, limit int) ([]*models.BusinessProfile, error)
+
+	// Autocomplete
+	SuggestUsers(ctx context.Context, prefix string, viewerID *string, limit int) ([]*models.SearchSuggestion, error)
+	SuggestBusinesses(ctx context.Context, prefix string, limit int) ([]*models.SearchSuggestion, error)
+	SuggestCategories(ctx context.Context, prefix string, limit int) ([]*models.SearchSuggestion, error)
+	SuggestHashtags(ctx context.Context, prefix string, limit int) ([]*models.SearchSuggestion, error)
+	IncrementHashtags(ctx context.Context, tags []string) error
+
+	// AdminSearch searches every entity in filter.Entities, including
+	// hidden content, newest first. Returns the page and the total.
+	AdminSearch(ctx context.Context, filter *models.AdminSearchFilter) ([]*models.AdminSearchResult, int, error)
+
+	// Recent searches
+	AddRecentSearch(ctx context.Context, userID, query string) error
+	ListRecentSearches(ctx context.Context, userID, prefix string, limit int) ([]*models.RecentSearch, error)
+	ClearRecentSearches(ctx context.Context, userID string) error
 }
 
 type searchRepository struct {
@@ -46,6 +64,7 @@ func (r *searchRepository) SearchPosts(ctx context.Context, filter *models.Searc
 			p.country, p.province, p.district, p.neighborhood,
 			p.total_comments, p.total_likes, p.total_shares,
 			p.created_at, p.updated_at, p.deleted_at,
+			p.lost_found_kind, p.item_description, p.last_seen_place, p.last_seen_at, p.urgency, p.lang, p.version,
 			ST_Y(p.address_location::geometry) as latitude,
 			ST_X(p.address_location::geometry) as longitude
 	`
@@ -62,46 +81,11 @@ func (r *searchRepository) SearchPosts(ctx context.Context, filter *models.Searc
 		argCount += 2
 	}
 
+	conditions, condArgs, argCount := postSearchConditions(filter, argCount, true)
 	query += `
 		FROM posts p
-		WHERE p.deleted_at IS NULL
-			AND p.status = true
-			AND (p.type != 'SELL' OR p.sold = false)
-	`
-
-	// Full-text search using tsvector/tsquery (GIN indexed) for performance at scale.
-	// Falls back to ILIKE for short queries where full-text may be too strict.
-	if filter.Query != "" {
-		if len(filter.Query) >= 3 {
-			// Use PostgreSQL full-text search with tsquery
-			query += fmt.Sprintf(`
-				AND p.search_vector @@ plainto_tsquery('english', $%d)
-			`, argCount)
-			args = append(args, filter.Query)
-		} else {
-			// Short queries: use prefix match with ILIKE
-			searchTerm := "%" + EscapeLike(strings.ToLower(filter.Query)) + "%"
-			query += fmt.Sprintf(`
-				AND (LOWER(p.title) LIKE $%d ESCAPE '\' OR LOWER(p.description) LIKE $%d ESCAPE '\')
-			`, argCount, argCount)
-			args = append(args, searchTerm)
-		}
-		argCount++
-	}
-
-	// Location-based filtering (radius constraint)
-	if hasLocation && filter.RadiusKm != nil {
-		query += fmt.Sprintf(`
-			AND p.address_location IS NOT NULL
-			AND ST_DWithin(
-				p.address_location::geography,
-				ST_SetSRID(ST_MakePoint($%d, $%d), 4326)::geography,
-				$%d
-			)
-		`, argCount, argCount+1, argCount+2)
-		args = append(args, *filter.Longitude, *filter.Latitude, *filter.RadiusKm*1000)
-		argCount += 3
-	}
+		WHERE ` + conditions
+	args = append(args, condArgs...)
 
 	// Order by relevance and recency
 	if hasLocation {
@@ -166,6 +150,13 @@ func (r *searchRepository) SearchPosts(ctx context.Context, filter *models.Searc
 			&post.CreatedAt,
 			&post.UpdatedAt,
 			&post.DeletedAt,
+			&post.LostFoundKind,
+			&post.ItemDescription,
+			&post.LastSeenPlace,
+			&post.LastSeenAt,
+			&post.Urgency,
+			&post.Lang,
+			&post.Version,
 			&lat,
 			&lng,
 		}
@@ -188,6 +179,176 @@ func (r *searchRepository) SearchPosts(ctx context.Context, filter *models.Searc
 	return posts, nil
 }
 
+// postSearchConditions builds the WHERE clause shared by SearchPosts and
+// GetPostSearchFacets, with placeholders numbered from argCount. withChips
+// adds the type / category / province filters.
+func postSearchConditions(filter *models.SearchFilter, argCount int, withChips bool) (string, []interface{}, int) {
+	args := []interface{}{}
+	query := `p.deleted_at IS NULL
+			AND p.status = true
+			AND (p.type != 'SELL' OR p.sold = false)
+			AND p.group_id IS NULL
+	`
+
+	// Shadowbanned authors only find their own posts.
+	if filter.UserID != nil && *filter.UserID != "" {
+		query += excludeShadowbanned("p.user_id", argCount)
+		args = append(args, *filter.UserID)
+		argCount++
+	} else {
+		query += excludeShadowbanned("p.user_id", 0)
+	}
+
+	// Full-text search using tsvector/tsquery (GIN indexed) for performance at scale.
+	// Falls back to ILIKE for short queries where full-text may be too strict.
+	if filter.Query != "" {
+		if len(filter.Query) >= 3 {
+			// Use PostgreSQL full-text search with tsquery
+			query += fmt.Sprintf(`
+				AND p.search_vector @@ plainto_tsquery('english', $%d)
+			`, argCount)
+			args = append(args, filter.Query)
+		} else {
+			// Short queries: use prefix match with ILIKE
+			searchTerm := "%" + EscapeLike(strings.ToLower(filter.Query)) + "%"
+			query += fmt.Sprintf(`
+				AND (LOWER(p.title) LIKE $%d ESCAPE '\' OR LOWER(p.description) LIKE $%d ESCAPE '\')
+			`, argCount, argCount)
+			args = append(args, searchTerm)
+		}
+		argCount++
+	}
+
+	// Location-based filtering (radius constraint)
+	if filter.Latitude != nil && filter.Longitude != nil && filter.RadiusKm != nil {
+		query += fmt.Sprintf(`
+			AND p.address_location IS NOT NULL
+			AND ST_DWithin(
+				p.address_location::geography,
+				ST_SetSRID(ST_MakePoint($%d, $%d), 4326)::geography,
+				$%d
+			)
+		`, argCount, argCount+1, argCount+2)
+		args = append(args, *filter.Longitude, *filter.Latitude, *filter.RadiusKm*1000)
+		argCount += 3
+	}
+
+	if len(filter.Languages) > 0 {
+		query += fmt.Sprintf(` AND (p.lang IS NULL OR p.lang = ANY($%d))`, argCount)
+		args = append(args, filter.Languages)
+		argCount++
+	}
+
+	if withChips {
+		if filter.PostType != nil {
+			query += fmt.Sprintf(` AND p.type = $%d`, argCount)
+			args = append(args, *filter.PostType)
+			argCount++
+		}
+		if filter.CategoryID != nil {
+			query += fmt.Sprintf(` AND p.category_id = $%d`, argCount)
+			args = append(args, *filter.CategoryID)
+			argCount++
+		}
+		if filter.Province != nil {
+			query += fmt.Sprintf(` AND p.province = $%d`, argCount)
+			args = append(args, *filter.Province)
+			argCount++
+		}
+	}
+
+	return query, args, argCount
+}
+
+// GetPostSearchFacets counts the posts matching filter (ignoring its chip
+// filters) per type, category and province in one grouped query. Only
+// PUBLIC and VIEW_ONLY posts are counted so restricted posts don't show up
+// in the numbers.
+func (r *searchRepository) GetPostSearchFacets(ctx context.Context, filter *models.SearchFilter) (*models.SearchFacets, error) {
+	conditions, args, _ := postSearchConditions(filter, 1, false)
+	query := `
+		SELECT GROUPING(p.type), GROUPING(p.category_id), p.type::text, p.category_id::text,
+			MAX(c.name), p.province, COUNT(*)
+		FROM posts p
+		LEFT JOIN sell_categories c ON c.id = p.category_id
+		WHERE ` + conditions + `
+			AND p.visibility IN ('PUBLIC', 'VIEW_ONLY')
+		GROUP BY GROUPING SETS ((p.type), (p.category_id), (p.province))
+		ORDER BY COUNT(*) DESC
+	`
+
+	rows, err := r.db.Reader().Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count search facets: %w", err)
+	}
+	defer rows.Close()
+
+	facets := &models.SearchFacets{
+		Types:      []*models.FacetCount{},
+		Categories: []*models.FacetCount{},
+		Provinces:  []*models.FacetCount{},
+	}
+	for rows.Next() {
+		var groupedType, groupedCategory int
+		var postType, categoryID, categoryName, province *string
+		var count int
+		if err := rows.Scan(&groupedType, &groupedCategory, &postType, &categoryID, &categoryName, &province, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan search facet: %w", err)
+		}
+		switch {
+		case groupedType == 0:
+			facets.Types = append(facets.Types, &models.FacetCount{Value: *postType, Count: count})
+		case groupedCategory == 0:
+			if categoryID == nil {
+				continue
+			}
+			f := &models.FacetCount{Value: *categoryID, Count: count}
+			if categoryName != nil {
+				f.Label = *categoryName
+			}
+			facets.Categories = append(facets.Categories, f)
+		default:
+			if province == nil || *province == "" {
+				continue
+			}
+			facets.Provinces = append(facets.Provinces, &models.FacetCount{Value: *province, Count: count})
+		}
+	}
+	return facets, rows.Err()
+}
+
+// GetPostHighlights returns ts_headline snippets of the given posts for a
+// full-text query, keyed by post id. Matched words are wrapped in <mark>.
+func (r *searchRepository) GetPostHighlights(ctx context.Context, postIDs []string, query string) (map[string]*models.SearchHighlight, error) {
+	highlights := map[string]*models.SearchHighlight{}
+	if len(postIDs) == 0 || query == "" {
+		return highlights, nil
+	}
+	rows, err := r.db.Reader().Query(ctx, `
+		SELECT p.id,
+			ts_headline('english', p.title, q,
+				'StartSel=<mark>, StopSel=</mark>, HighlightAll=true'),
+			ts_headline('english', p.description, q,
+				'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MinWords=8, MaxWords=24, FragmentDelimiter=" … "')
+		FROM posts p, plainto_tsquery('english', $2) AS q
+		WHERE p.id = ANY($1)
+	`, postIDs, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to highlight posts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		h := &models.SearchHighlight{}
+		if err := rows.Scan(&id, &h.Title, &h.Description); err != nil {
+			return nil, fmt.Errorf("failed to scan post highlight: %w", err)
+		}
+		highlights[id] = h
+	}
+	return highlights, rows.Err()
+}
+
 // SearchUsers searches for users using full-text search
 func (r *searchRepository) SearchUsers(ctx context.Context, filter *models.SearchFilter) ([]*models.Profile, error) {
 	query := `
@@ -195,7 +356,7 @@ func (r *searchRepository) SearchUsers(ctx context.Context, filter *models.Searc
 			ST_X(p.location::geometry) as longitude,
 			ST_Y(p.location::geometry) as latitude,
 			p.country, p.province, p.district, p.neighborhood, p.is_complete,
-			p.created_at, p.updated_at, p.deleted_at,
+			p.created_at, p.updated_at, p.deleted_at, p.handle,
 			u.email,
 			(SELECT COUNT(*) FROM user_follows WHERE following_id = p.id) as follower_count,
 			(SELECT COUNT(*) FROM user_follows WHERE follower_id = p.id) as following_count
@@ -214,18 +375,30 @@ func (r *searchRepository) SearchUsers(ctx context.Context, filter *models.Searc
 	args := []interface{}{}
 	argCount := 1
 
-	// Search on name using ILIKE with prefix match for user names
+	// Shadowbanned accounts still find themselves, nobody else does.
+	if filter.UserID != nil && *filter.UserID != "" {
+		query += excludeShadowbanned("u.id", argCount)
+		args = append(args, *filter.UserID)
+		argCount++
+	} else {
+		query += excludeShadowbanned("u.id", 0)
+	}
+
+	// Search on name using ILIKE, or on handle by prefix ("@ali" or "ali")
 	if filter.Query != "" {
-		searchTerm := "%" + EscapeLike(strings.ToLower(filter.Query)) + "%"
+		term := strings.ToLower(filter.Query)
+		searchTerm := "%" + EscapeLike(term) + "%"
+		handleTerm := EscapeLike(strings.TrimPrefix(term, "@")) + "%"
 		query += fmt.Sprintf(`
 			AND (
 				LOWER(p.first_name) LIKE $%d ESCAPE '\'
 				OR LOWER(p.last_name) LIKE $%d ESCAPE '\'
 				OR LOWER(CONCAT(p.first_name, ' ', p.last_name)) LIKE $%d ESCAPE '\'
+				OR p.handle LIKE $%d ESCAPE '\'
 			)
-		`, argCount, argCount, argCount)
-		args = append(args, searchTerm)
-		argCount++
+		`, argCount, argCount, argCount, argCount+1)
+		args = append(args, searchTerm, handleTerm)
+		argCount += 2
 	}
 
 	// Location-based filtering
@@ -283,6 +456,7 @@ func (r *searchRepository) SearchUsers(ctx context.Context, filter *models.Searc
 			&profile.CreatedAt,
 			&profile.UpdatedAt,
 			&profile.DeletedAt,
+			&profile.Handle,
 			&email,
 			&followerCount,
 			&followingCount,
@@ -329,18 +503,26 @@ func (r *searchRepository) SearchBusinesses(ctx context.Context, filter *models.
 	// indices were computed from len(filter.Query) and the args were never
 	// appended at this point, so the $N numbers never matched and any location
 	// business search 500'd (pgx "placeholder out of range"). Mirrors SearchPosts.
+	// Branches count too: a business matches by whichever of its main
+	// address and branches is nearest.
+	// argCount is still 1 here, so the origin is always $1/$2.
+	var origin string
 	if hasLocation {
-		query += fmt.Sprintf(`,
-			ST_Distance(
-				bp.address_location::geography,
-				ST_SetSRID(ST_MakePoint($%d, $%d), 4326)::geography
-			) / 1000 as distance`, argCount, argCount+1)
+		origin = fmt.Sprintf("ST_SetSRID(ST_MakePoint($%d, $%d), 4326)::geography", argCount, argCount+1)
+		query += `,
+			ST_Distance(bp.address_location::geography, ` + origin + `) / 1000 as distance,
+			` + nearestBranchColumns + `,
+			LEAST(ST_Distance(bp.address_location::geography, ` + origin + `) / 1000, nb.distance_km) as nearest_distance`
+		query += `
+		FROM business_profiles bp` + nearestBranchJoin(argCount, argCount+1)
 		args = append(args, *filter.Longitude, *filter.Latitude)
 		argCount += 2
+	} else {
+		query += `
+		FROM business_profiles bp`
 	}
 
 	query += `
-		FROM business_profiles bp
 		WHERE bp.deleted_at IS NULL
 			AND bp.status = true
 	`
@@ -362,23 +544,21 @@ func (r *searchRepository) SearchBusinesses(ctx context.Context, filter *models.
 		argCount++
 	}
 
-	// Location-based filtering
-	if filter.Latitude != nil && filter.Longitude != nil && filter.RadiusKm != nil {
+	// Location-based filtering: the main address or any branch in range.
+	if hasLocation && filter.RadiusKm != nil {
 		query += fmt.Sprintf(`
-			AND bp.address_location IS NOT NULL
-			AND ST_DWithin(
-				bp.address_location::geography,
-				ST_SetSRID(ST_MakePoint($%d, $%d), 4326)::geography,
-				$%d
+			AND (
+				(bp.address_location IS NOT NULL AND ST_DWithin(bp.address_location::geography, `+origin+`, $%d))
+				OR %s
 			)
-		`, argCount, argCount+1, argCount+2)
-		args = append(args, *filter.Longitude, *filter.Latitude, *filter.RadiusKm*1000)
-		argCount += 3
+		`, argCount, branchWithin(1, 2, argCount))
+		args = append(args, *filter.RadiusKm*1000)
+		argCount++
 	}
 
 	// Order by relevance
-	if filter.Latitude != nil && filter.Longitude != nil {
-		query += ` ORDER BY distance ASC, bp.total_follow DESC`
+	if hasLocation {
+		query += ` ORDER BY nearest_distance ASC NULLS LAST, bp.total_follow DESC`
 	} else {
 		query += ` ORDER BY bp.total_follow DESC`
 	}
@@ -427,13 +607,20 @@ func (r *searchRepository) SearchBusinesses(ctx context.Context, filter *models.
 			&lng,
 		}
 
-		if filter.Latitude != nil && filter.Longitude != nil {
+		var branch nearestBranchScan
+		var nearest *float64
+		if hasLocation {
 			scanArgs = append(scanArgs, &distance)
+			scanArgs = append(scanArgs, branch.dest()...)
+			scanArgs = append(scanArgs, &nearest)
 		}
 
 		if err := rows.Scan(scanArgs...); err != nil {
 			return nil, fmt.Errorf("failed to scan business: %w", err)
 		}
+		if hasLocation {
+			branch.apply(business, distance)
+		}
 
 		// Build AddressLocation from lat/lng
 		if lat != nil && lng != nil {
@@ -476,12 +663,14 @@ func (r *searchRepository) GetDiscoverPosts(ctx context.Context, lat, lng, radiu
 			AND p.status = true
 			AND p.type IN ('EVENT', 'SELL')
 			AND (p.type != 'SELL' OR p.sold = false)
+			AND p.group_id IS NULL
 			AND p.address_location IS NOT NULL
 			AND ST_DWithin(
 				p.address_location::geography,
 				ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography,
 				$3
 			)
+	` + excludeShadowbanned("p.user_id", 0) + `
 	`
 
 	args := []interface{}{lng, lat, radiusKm * 1000}
@@ -574,7 +763,9 @@ func (r *searchRepository) GetDiscoverPosts(ctx context.Context, lat, lng, radiu
 	return posts, nil
 }
 
-// GetDiscoverBusinesses gets businesses within a radius for map discovery
+// GetDiscoverBusinesses gets businesses within a radius for map discovery.
+// A business is in range when its main address (if shown) or any of its
+// branches is; it is placed at whichever of those is nearest.
 func (r *searchRepository) GetDiscoverBusinesses(ctx context.Context, lat, lng, radiusKm float64, limit int) ([]*models.BusinessProfile, error) {
 	query := `
 		SELECT
@@ -583,23 +774,34 @@ func (r *searchRepository) GetDiscoverBusinesses(ctx context.Context, lat, lng,
 			bp.additional_info, bp.country, bp.province,
 			bp.district, bp.neighborhood, bp.show_location, bp.total_views,
 			bp.total_follow, bp.created_at, bp.updated_at, bp.deleted_at,
-			ST_Y(bp.address_location::geometry) as latitude,
-			ST_X(bp.address_location::geometry) as longitude,
-			ST_Distance(
-				bp.address_location::geography,
-				ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography
-			) / 1000 as distance
+			main.latitude, main.longitude, main.distance,
+			` + nearestBranchColumns + `
 		FROM business_profiles bp
+		LEFT JOIN LATERAL (
+			SELECT
+				ST_Y(bp.address_location::geometry) as latitude,
+				ST_X(bp.address_location::geometry) as longitude,
+				ST_Distance(
+					bp.address_location::geography,
+					ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography
+				) / 1000 as distance
+			WHERE bp.show_location = true AND bp.address_location IS NOT NULL
+		) main ON true` + nearestBranchJoin(1, 2) + `
 		WHERE bp.deleted_at IS NULL
 			AND bp.status = true
-			AND bp.address_location IS NOT NULL
-			AND bp.show_location = true
-			AND ST_DWithin(
-				bp.address_location::geography,
-				ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography,
-				$3
+			AND (
+				(
+					bp.show_location = true
+					AND bp.address_location IS NOT NULL
+					AND ST_DWithin(
+						bp.address_location::geography,
+						ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography,
+						$3
+					)
+				)
+				OR ` + branchWithin(1, 2, 3) + `
 			)
-		ORDER BY distance ASC
+		ORDER BY LEAST(main.distance, nb.distance_km) ASC
 		LIMIT $4
 	`
 
@@ -613,8 +815,9 @@ func (r *searchRepository) GetDiscoverBusinesses(ctx context.Context, lat, lng,
 	for rows.Next() {
 		business := &models.BusinessProfile{}
 		var bLat, bLng, distance *float64
+		var branch nearestBranchScan
 
-		err := rows.Scan(
+		err := rows.Scan(append([]any{
 			&business.ID,
 			&business.UserID,
 			&business.Name,
@@ -641,7 +844,7 @@ func (r *searchRepository) GetDiscoverBusinesses(ctx context.Context, lat, lng,
 			&bLat,
 			&bLng,
 			&distance,
-		)
+		}, branch.dest()...)...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan business: %w", err)
 		}
@@ -652,6 +855,7 @@ func (r *searchRepository) GetDiscoverBusinesses(ctx context.Context, lat, lng,
 				Valid: true,
 			}
 		}
+		branch.apply(business, distance)
 
 		businesses = append(businesses, business)
 	}
@@ -662,3 +866,318 @@ func (r *searchRepository) GetDiscoverBusinesses(ctx context.Context, lat, lng,
 
 	return businesses, nil
 }
+
+// maxRecentSearches is how many recent searches are kept per user.
+const maxRecentSearches = 20
+
+// SuggestUsers returns users whose first, last or full name starts with
+// prefix, most followed first. Same exclusions as SearchUsers.
+func (r *searchRepository) SuggestUsers(ctx context.Context, prefix string, viewerID *string, limit int) ([]*models.SearchSuggestion, error) {
+	pattern := EscapeLike(strings.ToLower(prefix)) + "%"
+	handlePattern := EscapeLike(strings.TrimPrefix(strings.ToLower(prefix), "@")) + "%"
+	args := []interface{}{pattern, limit, handlePattern}
+	query := `
+		SELECT p.id, TRIM(CONCAT(p.first_name, ' ', p.last_name)), p.handle, p.avatar, p.avatar_color,
+			(SELECT COUNT(*) FROM user_follows WHERE following_id = p.id) AS follower_count
+		FROM profiles p
+		JOIN users u ON u.id = p.id
+		WHERE p.deleted_at IS NULL
+			AND u.deleted_at IS NULL
+			AND p.is_complete = TRUE
+			AND u.is_active = TRUE
+			AND (u.locked_until IS NULL OR u.locked_until <= NOW())
+			AND (
+				LOWER(p.first_name) LIKE $1 ESCAPE '\'
+				OR LOWER(p.last_name) LIKE $1 ESCAPE '\'
+				OR LOWER(CONCAT(p.first_name, ' ', p.last_name)) LIKE $1 ESCAPE '\'
+				OR p.handle LIKE $3 ESCAPE '\'
+			)`
+	if viewerID != nil && *viewerID != "" {
+		query += excludeShadowbanned("u.id", 4)
+		args = append(args, *viewerID)
+	} else {
+		query += excludeShadowbanned("u.id", 0)
+	}
+	query += ` ORDER BY follower_count DESC, p.first_name LIMIT $2`
+
+	rows, err := r.db.Reader().Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest users: %w", err)
+	}
+	defer rows.Close()
+
+	suggestions := []*models.SearchSuggestion{}
+	for rows.Next() {
+		s := &models.SearchSuggestion{Kind: models.SuggestionUser}
+		if err := rows.Scan(&s.ID, &s.Label, &s.Handle, &s.Avatar, &s.AvatarColor, &s.Popularity); err != nil {
+			return nil, fmt.Errorf("failed to scan user suggestion: %w", err)
+		}
+		suggestions = append(suggestions, s)
+	}
+	return suggestions, rows.Err()
+}
+
+// SuggestBusinesses returns active businesses whose name starts with
+// prefix (or has a word that does), most followed first.
+func (r *searchRepository) SuggestBusinesses(ctx context.Context, prefix string, limit int) ([]*models.SearchSuggestion, error) {
+	escaped := EscapeLike(strings.ToLower(prefix))
+	rows, err := r.db.Reader().Query(ctx, `
+		SELECT bp.id, bp.name, bp.avatar, bp.avatar_color, COALESCE(bp.total_follow, 0)
+		FROM business_profiles bp
+		WHERE bp.deleted_at IS NULL
+			AND bp.status = TRUE
+			AND (LOWER(bp.name) LIKE $1 ESCAPE '\' OR LOWER(bp.name) LIKE $2 ESCAPE '\')
+		ORDER BY COALESCE(bp.total_follow, 0) DESC, bp.name
+		LIMIT $3
+	`, escaped+"%", "% "+escaped+"%", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest businesses: %w", err)
+	}
+	defer rows.Close()
+
+	suggestions := []*models.SearchSuggestion{}
+	for rows.Next() {
+		s := &models.SearchSuggestion{Kind: models.SuggestionBusiness}
+		if err := rows.Scan(&s.ID, &s.Label, &s.Avatar, &s.AvatarColor, &s.Popularity); err != nil {
+			return nil, fmt.Errorf("failed to scan business suggestion: %w", err)
+		}
+		suggestions = append(suggestions, s)
+	}
+	return suggestions, rows.Err()
+}
+
+// SuggestCategories returns active sell categories whose name in any
+// locale starts with prefix, ranked by live post count.
+func (r *searchRepository) SuggestCategories(ctx context.Context, prefix string, limit int) ([]*models.SearchSuggestion, error) {
+	rows, err := r.db.Reader().Query(ctx, `
+		SELECT c.id, c.name,
+			(SELECT COUNT(*) FROM posts p
+			 WHERE p.category_id = c.id AND p.deleted_at IS NULL AND p.status = TRUE) AS post_count
+		FROM sell_categories c
+		WHERE c.status = 'ACTIVE'
+			AND (
+				LOWER(c.name) LIKE $1 ESCAPE '\'
+				OR LOWER(COALESCE(c.name_dari, '')) LIKE $1 ESCAPE '\'
+				OR LOWER(COALESCE(c.name_pashto, '')) LIKE $1 ESCAPE '\'
+			)
+		ORDER BY post_count DESC, c.name
+		LIMIT $2
+	`, EscapeLike(strings.ToLower(prefix))+"%", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest categories: %w", err)
+	}
+	defer rows.Close()
+
+	suggestions := []*models.SearchSuggestion{}
+	for rows.Next() {
+		s := &models.SearchSuggestion{Kind: models.SuggestionCategory}
+		if err := rows.Scan(&s.ID, &s.Label, &s.Popularity); err != nil {
+			return nil, fmt.Errorf("failed to scan category suggestion: %w", err)
+		}
+		suggestions = append(suggestions, s)
+	}
+	return suggestions, rows.Err()
+}
+
+// SuggestHashtags returns hashtags starting with prefix, most used first.
+func (r *searchRepository) SuggestHashtags(ctx context.Context, prefix string, limit int) ([]*models.SearchSuggestion, error) {
+	rows, err := r.db.Reader().Query(ctx, `
+		SELECT tag, post_count
+		FROM hashtags
+		WHERE tag LIKE $1 ESCAPE '\' AND post_count > 0
+		ORDER BY post_count DESC, last_used_at DESC
+		LIMIT $2
+	`, EscapeLike(strings.ToLower(prefix))+"%", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest hashtags: %w", err)
+	}
+	defer rows.Close()
+
+	suggestions := []*models.SearchSuggestion{}
+	for rows.Next() {
+		s := &models.SearchSuggestion{Kind: models.SuggestionHashtag}
+		if err := rows.Scan(&s.Label, &s.Popularity); err != nil {
+			return nil, fmt.Errorf("failed to scan hashtag suggestion: %w", err)
+		}
+		s.ID = s.Label
+		suggestions = append(suggestions, s)
+	}
+	return suggestions, rows.Err()
+}
+
+// IncrementHashtags bumps the post count of each tag, creating new ones.
+func (r *searchRepository) IncrementHashtags(ctx context.Context, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	_, err := r.db.Pool.Exec(ctx, `
+		INSERT INTO hashtags (tag, post_count, last_used_at)
+		SELECT t, 1, NOW() FROM unnest($1::text[]) AS t
+		ON CONFLICT (tag) DO UPDATE
+		SET post_count = hashtags.post_count + 1, last_used_at = NOW()
+	`, tags)
+	if err != nil {
+		return fmt.Errorf("failed to increment hashtags: %w", err)
+	}
+	return nil
+}
+
+// AddRecentSearch records query (already normalized) as the user's newest
+// search and trims the list to maxRecentSearches.
+func (r *searchRepository) AddRecentSearch(ctx context.Context, userID, query string) error {
+	if _, err := r.db.Pool.Exec(ctx, `
+		INSERT INTO user_recent_searches (user_id, query, searched_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id, query) DO UPDATE SET searched_at = NOW()
+	`, userID, query); err != nil {
+		return fmt.Errorf("failed to add recent search: %w", err)
+	}
+	if _, err := r.db.Pool.Exec(ctx, `
+		DELETE FROM user_recent_searches
+		WHERE user_id = $1
+			AND query NOT IN (
+				SELECT query FROM user_recent_searches
+				WHERE user_id = $1
+				ORDER BY searched_at DESC
+				LIMIT $2
+			)
+	`, userID, maxRecentSearches); err != nil {
+		return fmt.Errorf("failed to trim recent searches: %w", err)
+	}
+	return nil
+}
+
+// ListRecentSearches returns the user's recent searches starting with
+// prefix (all when empty), newest first.
+func (r *searchRepository) ListRecentSearches(ctx context.Context, userID, prefix string, limit int) ([]*models.RecentSearch, error) {
+	rows, err := r.db.Reader().Query(ctx, `
+		SELECT query, searched_at
+		FROM user_recent_searches
+		WHERE user_id = $1 AND query LIKE $2 ESCAPE '\'
+		ORDER BY searched_at DESC
+		LIMIT $3
+	`, userID, EscapeLike(strings.ToLower(prefix))+"%", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recent searches: %w", err)
+	}
+	defer rows.Close()
+
+	searches := []*models.RecentSearch{}
+	for rows.Next() {
+		s := &models.RecentSearch{}
+		if err := rows.Scan(&s.Query, &s.SearchedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan recent search: %w", err)
+		}
+		searches = append(searches, s)
+	}
+	return searches, rows.Err()
+}
+
+// ClearRecentSearches deletes all of the user's recent searches.
+func (r *searchRepository) ClearRecentSearches(ctx context.Context, userID string) error {
+	if _, err := r.db.Pool.Exec(ctx, `DELETE FROM user_recent_searches WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to clear recent searches: %w", err)
+	}
+	return nil
+}
+
+// adminSearchBranches is one SELECT per entity, all with the same columns.
+// $1 is the lower-cased LIKE pattern, $2 the raw query for full-text.
+var adminSearchBranches = map[models.AdminSearchEntity]func(fullText bool) string{
+	models.AdminSearchPost: func(fullText bool) string {
+		match := `(LOWER(p.title) LIKE $1 ESCAPE '\' OR LOWER(p.description) LIKE $1 ESCAPE '\')`
+		if fullText {
+			match = `p.search_vector @@ plainto_tsquery('english', $2)`
+		}
+		return `SELECT 'post' AS type, p.id::text AS id,
+				COALESCE(NULLIF(p.title, ''), LEFT(p.description, 80), '') AS title,
+				LEFT(p.description, 200) AS snippet, p.user_id::text AS owner_id, NULL::text AS parent_id,
+				NOT p.status AS hidden, p.created_at
+			FROM posts p
+			WHERE p.deleted_at IS NULL AND ` + match
+	},
+	models.AdminSearchComment: func(bool) string {
+		return `SELECT 'comment', c.id::text, LEFT(c.text, 80), LEFT(c.text, 200),
+				c.user_id::text, c.post_id::text, FALSE, c.created_at
+			FROM post_comments c
+			WHERE c.deleted_at IS NULL AND LOWER(c.text) LIKE $1 ESCAPE '\'`
+	},
+	models.AdminSearchUser: func(bool) string {
+		return `SELECT 'user', u.id::text,
+				COALESCE(NULLIF(TRIM(CONCAT(pr.first_name, ' ', pr.last_name)), ''), u.email),
+				u.email, u.id::text, NULL::text,
+				(NOT u.is_active OR (u.locked_until IS NOT NULL AND u.locked_until > NOW())), u.created_at
+			FROM users u
+			LEFT JOIN profiles pr ON pr.id = u.id
+			WHERE u.deleted_at IS NULL
+				AND (LOWER(u.email) LIKE $1 ESCAPE '\'
+					OR LOWER(CONCAT(pr.first_name, ' ', pr.last_name)) LIKE $1 ESCAPE '\')`
+	},
+	models.AdminSearchBusiness: func(fullText bool) string {
+		match := `LOWER(bp.name) LIKE $1 ESCAPE '\'`
+		if fullText {
+			match = `(bp.search_vector @@ plainto_tsquery('english', $2) OR LOWER(bp.name) LIKE $1 ESCAPE '\')`
+		}
+		return `SELECT 'business', bp.id::text, bp.name, LEFT(bp.description, 200),
+				bp.user_id::text, NULL::text, NOT bp.status, bp.created_at
+			FROM business_profiles bp
+			WHERE bp.deleted_at IS NULL AND ` + match
+	},
+	// Messages are only searchable once reported, through the report's
+	// snapshot; one row per message however many reports it has.
+	models.AdminSearchMessage: func(bool) string {
+		return `SELECT * FROM (
+				SELECT DISTINCT ON (mr.message_id) 'message', mr.message_id::text,
+					COALESCE(LEFT(mr.message_content, 80), ''), LEFT(mr.message_content, 200),
+					mr.sender_id::text, mr.conversation_id::text,
+					(m.id IS NULL OR m.deleted_at IS NOT NULL), mr.message_sent_at
+				FROM message_reports mr
+				LEFT JOIN messages m ON m.id = mr.message_id
+				WHERE LOWER(mr.message_content) LIKE $1 ESCAPE '\'
+				ORDER BY mr.message_id, mr.created_at
+			) mr`
+	},
+}
+
+// AdminSearch runs one UNION ALL query over the requested entities. Posts
+// and businesses use the full-text index for queries of 3+ characters, the
+// rest substring-match on trigram indexes.
+func (r *searchRepository) AdminSearch(ctx context.Context, filter *models.AdminSearchFilter) ([]*models.AdminSearchResult, int, error) {
+	fullText := len(filter.Query) >= 3
+	var branches []string
+	for _, entity := range filter.Entities {
+		if branch, ok := adminSearchBranches[entity]; ok {
+			branches = append(branches, branch(fullText))
+		}
+	}
+	if len(branches) == 0 {
+		return []*models.AdminSearchResult{}, 0, nil
+	}
+
+	query := `
+		SELECT r.type, r.id, r.title, r.snippet, r.owner_id, r.parent_id, r.hidden, r.created_at,
+			COUNT(*) OVER() AS total
+		FROM (` + strings.Join(branches, "\n\t\tUNION ALL\n\t\t") + `) r
+		ORDER BY r.created_at DESC, r.id
+		LIMIT $3 OFFSET $4
+	`
+	pattern := "%" + EscapeLike(strings.ToLower(filter.Query)) + "%"
+
+	rows, err := r.db.Reader().Query(ctx, query, pattern, filter.Query, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to run admin search: %w", err)
+	}
+	defer rows.Close()
+
+	results := []*models.AdminSearchResult{}
+	total := 0
+	for rows.Next() {
+		res := &models.AdminSearchResult{}
+		if err := rows.Scan(&res.Type, &res.ID, &res.Title, &res.Snippet, &res.OwnerID, &res.ParentID,
+			&res.Hidden, &res.CreatedAt, &total); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan admin search result: %w", err)
+		}
+		results = append(results, res)
+	}
+	return results, total, rows.Err()
+}
@@ -31,6 +31,21 @@ func TestSearchRepository_SearchPosts_Empty(t *testing.T) {
 	assert.Empty(t, posts)
 }
 
+func TestSearchRepository_SearchPosts_HidesShadowbannedAuthors(t *testing.T) {
+	pool := new(testutil.MockPool)
+	repo := newSearchRepo(pool)
+
+	var query string
+	pool.On("Query", mock.Anything, mock.MatchedBy(func(q string) bool { query = q; return true }), mock.Anything).
+		Return(testutil.EmptyRows(), nil)
+
+	viewer := "viewer-1"
+	_, err := repo.SearchPosts(context.Background(), &models.SearchFilter{Query: "bike", Limit: 10, UserID: &viewer})
+	require.NoError(t, err)
+	assert.Contains(t, query, "shadowbanned_at IS NOT NULL")
+	assert.Contains(t, query, "sb.id <> $1")
+}
+
 func TestSearchRepository_SearchPosts_QueryError(t *testing.T) {
 	pool := new(testutil.MockPool)
 	repo := newSearchRepo(pool)
@@ -114,3 +129,39 @@ func TestSearchRepository_GetDiscoverBusinesses_Empty(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, businesses)
 }
+
+func TestSearchRepository_SearchBusinesses_BranchRadiusSQL(t *testing.T) {
+	pool := new(testutil.MockPool)
+	repo := newSearchRepo(pool)
+	calls := capture(pool, "Query")
+
+	lat, lng, radius := 34.5, 69.2, 3.0
+	_, err := repo.SearchBusinesses(context.Background(), &models.SearchFilter{
+		Query: "cafe", Latitude: &lat, Longitude: &lng, RadiusKm: &radius, Limit: 10,
+	})
+	require.Error(t, err)
+	require.Len(t, *calls, 1)
+
+	q := (*calls)[0]
+	assert.Contains(t, q.sql, "LEFT JOIN LATERAL (")
+	assert.Contains(t, q.sql, "FROM business_locations bl WHERE bl.business_id = bp.id AND ST_DWithin(bl.location, ST_SetSRID(ST_MakePoint($1, $2), 4326)::geography, $4)")
+	assert.Contains(t, q.sql, "ORDER BY nearest_distance ASC NULLS LAST")
+	assert.Equal(t, []any{lng, lat, "cafe", radius * 1000, 10, 0}, q.args)
+}
+
+func TestSearchRepository_GetDiscoverBusinesses_IncludesBranches(t *testing.T) {
+	pool := new(testutil.MockPool)
+	repo := newSearchRepo(pool)
+	calls := capture(pool, "Query")
+
+	_, err := repo.GetDiscoverBusinesses(context.Background(), 34.5, 69.2, 5.0, 10)
+	require.Error(t, err)
+	require.Len(t, *calls, 1)
+
+	q := (*calls)[0]
+	// Businesses hiding their main address still show through a branch.
+	assert.Contains(t, q.sql, ") main ON true")
+	assert.Contains(t, q.sql, "OR EXISTS ( SELECT 1 FROM business_locations bl")
+	assert.Contains(t, q.sql, "ORDER BY LEAST(main.distance, nb.distance_km) ASC")
+	assert.Equal(t, []any{69.2, 34.5, 5000.0, 10}, q.args)
+}
@@ -0,0 +1,32 @@
+package repositories
+
+import "fmt"
+
+// excludeShadowbanned returns an SQL predicate (with a leading AND) that
+// drops rows whose author is shadowbanned. authorCol is the column holding
+// the author's users.id. viewerParam is the placeholder index of the viewing
+// user's ID; the author always keeps seeing their own content so the ban
+// isn't obvious to them. Pass 0 for anonymous viewers.
+//
+// Every viewer-facing list of user content (feeds, search, discover) goes
+// through this so the rule lives in one place.
+func excludeShadowbanned(authorCol string, viewerParam int) string {
+	return " AND " + shadowbanPredicate(authorCol, viewerParam)
+}
+
+// shadowbanPredicate is excludeShadowbanned without the leading AND, for
+// queries assembled with whereBuilder.
+func shadowbanPredicate(authorCol string, viewerParam int) string {
+	if viewerParam == 0 {
+		return fmt.Sprintf(`NOT EXISTS (
+			SELECT 1 FROM users sb
+			WHERE sb.id = %s AND sb.shadowbanned_at IS NOT NULL
+		)`, authorCol)
+	}
+	return fmt.Sprintf(`NOT EXISTS (
+			SELECT 1 FROM users sb
+			WHERE sb.id = %s
+			  AND sb.shadowbanned_at IS NOT NULL
+			  AND sb.id <> $%d
+		)`, authorCol, viewerParam)
+}
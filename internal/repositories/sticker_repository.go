@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// StickerRepository stores the chat sticker catalog.
+type StickerRepository interface {
+	// ListPacks returns the packs with their stickers by position. Inactive
+	// packs are included only when includeInactive is set.
+	ListPacks(ctx context.Context, includeInactive bool) ([]*models.StickerPack, error)
+
+	// CreatePack inserts the pack and its stickers in one transaction.
+	CreatePack(ctx context.Context, pack *models.StickerPack) error
+
+	// SetPackActive shows or hides a pack in the catalog. Stickers already
+	// sent keep rendering.
+	SetPackActive(ctx context.Context, packID string, active bool) error
+
+	// DeletePack removes a pack and its stickers.
+	DeletePack(ctx context.Context, packID string) error
+
+	// GetSticker returns a sticker. activeOnly rejects stickers of hidden
+	// packs, for sending.
+	GetSticker(ctx context.Context, stickerID string, activeOnly bool) (*models.Sticker, error)
+}
+
+type stickerRepository struct {
+	db *database.DB
+}
+
+// NewStickerRepository wires a new sticker repository.
+func NewStickerRepository(db *database.DB) StickerRepository {
+	return &stickerRepository{db: db}
+}
+
+var (
+	// ErrStickerPackNotFound is returned when a pack id doesn't exist.
+	ErrStickerPackNotFound = newNotFoundError("sticker pack not found")
+	// ErrStickerNotFound is returned when a sticker id doesn't exist (or
+	// its pack is hidden, for active-only lookups).
+	ErrStickerNotFound = newNotFoundError("sticker not found")
+)
+
+func (r *stickerRepository) ListPacks(ctx context.Context, includeInactive bool) ([]*models.StickerPack, error) {
+	rows, err := r.db.Reader().Query(ctx, `
+		SELECT sp.id, sp.name, sp.position, sp.is_active, sp.created_at,
+			s.id, s.image_url, s.emoji, s.position
+		FROM sticker_packs sp
+		LEFT JOIN stickers s ON s.pack_id = sp.id
+		WHERE sp.is_active OR $1
+		ORDER BY sp.position ASC, sp.created_at ASC, s.position ASC
+	`, includeInactive)
+	if err != nil {
+		return nil, fmt.Errorf("list sticker packs: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]*models.StickerPack, 0)
+	var current *models.StickerPack
+	for rows.Next() {
+		pack := &models.StickerPack{}
+		var (
+			stickerID, imageURL *string
+			emoji               *string
+			position            *int
+		)
+		if err := rows.Scan(&pack.ID, &pack.Name, &pack.Position, &pack.IsActive, &pack.CreatedAt,
+			&stickerID, &imageURL, &emoji, &position); err != nil {
+			return nil, fmt.Errorf("scan sticker pack: %w", err)
+		}
+		if current == nil || current.ID != pack.ID {
+			pack.Stickers = make([]*models.Sticker, 0)
+			out = append(out, pack)
+			current = pack
+		}
+		if stickerID != nil {
+			sticker := &models.Sticker{ID: *stickerID, PackID: current.ID, ImageURL: *imageURL, Emoji: emoji}
+			if position != nil {
+				sticker.Position = *position
+			}
+			current.Stickers = append(current.Stickers, sticker)
+		}
+	}
+	return out, rows.Err()
+}
+
+func (r *stickerRepository) CreatePack(ctx context.Context, pack *models.StickerPack) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO sticker_packs (id, name, position, is_active, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		RETURNING created_at
+	`, pack.ID, pack.Name, pack.Position, pack.IsActive).Scan(&pack.CreatedAt); err != nil {
+		return fmt.Errorf("create sticker pack: %w", err)
+	}
+	for _, s := range pack.Stickers {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO stickers (id, pack_id, image_url, emoji, position)
+			VALUES ($1, $2, $3, $4, $5)
+		`, s.ID, pack.ID, s.ImageURL, s.Emoji, s.Position); err != nil {
+			return fmt.Errorf("create sticker: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit sticker pack: %w", err)
+	}
+	return nil
+}
+
+func (r *stickerRepository) SetPackActive(ctx context.Context, packID string, active bool) error {
+	tag, err := r.db.Pool.Exec(ctx, `UPDATE sticker_packs SET is_active = $2 WHERE id = $1`, packID, active)
+	if err != nil {
+		return fmt.Errorf("update sticker pack: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrStickerPackNotFound
+	}
+	return nil
+}
+
+func (r *stickerRepository) DeletePack(ctx context.Context, packID string) error {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM sticker_packs WHERE id = $1`, packID)
+	if err != nil {
+		return fmt.Errorf("delete sticker pack: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrStickerPackNotFound
+	}
+	return nil
+}
+
+func (r *stickerRepository) GetSticker(ctx context.Context, stickerID string, activeOnly bool) (*models.Sticker, error) {
+	s := &models.Sticker{}
+	err := r.db.Reader().QueryRow(ctx, `
+		SELECT s.id, s.pack_id, s.image_url, s.emoji, s.position
+		FROM stickers s
+		JOIN sticker_packs sp ON sp.id = s.pack_id
+		WHERE s.id = $1 AND (sp.is_active OR NOT $2)
+	`, stickerID, activeOnly).Scan(&s.ID, &s.PackID, &s.ImageURL, &s.Emoji, &s.Position)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrStickerNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get sticker: %w", err)
+	}
+	return s, nil
+}
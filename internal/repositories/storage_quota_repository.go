@@ -0,0 +1,201 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrStorageObjectNotFound is returned when a storage object doesn't exist
+// or belongs to someone else.
+var ErrStorageObjectNotFound = newNotFoundError("storage object not found")
+
+// StorageQuotaRepository keeps the per-user upload ledger and running
+// storage totals.
+type StorageQuotaRepository interface {
+	// GetUsage returns the user's total; zero when they never uploaded.
+	GetUsage(ctx context.Context, userID string) (*models.StorageUsage, error)
+	// Record adds an upload to the ledger and the user's total. Recording
+	// the same URL twice counts it once.
+	Record(ctx context.Context, obj *models.StorageObject) error
+	// Release removes the object stored at url from the ledger and its
+	// owner's total. Unknown URLs are a no-op.
+	Release(ctx context.Context, url string) error
+	// List returns a page of the user's uploads plus total.
+	List(ctx context.Context, filter *models.StorageObjectFilter) ([]*models.StorageObject, int, error)
+	// Get returns one of the user's uploads, or ErrStorageObjectNotFound.
+	Get(ctx context.Context, userID, id string) (*models.StorageObject, error)
+}
+
+type storageQuotaRepository struct {
+	db *database.DB
+}
+
+// NewStorageQuotaRepository creates the repository.
+func NewStorageQuotaRepository(db *database.DB) StorageQuotaRepository {
+	return &storageQuotaRepository{db: db}
+}
+
+func (r *storageQuotaRepository) GetUsage(ctx context.Context, userID string) (*models.StorageUsage, error) {
+	usage := &models.StorageUsage{}
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT used_bytes, object_count FROM storage_usage WHERE user_id = $1
+	`, userID).Scan(&usage.UsedBytes, &usage.ObjectCount)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get storage usage: %w", err)
+	}
+	return usage, nil
+}
+
+func (r *storageQuotaRepository) Record(ctx context.Context, obj *models.StorageObject) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO storage_objects (user_id, url, thumb_url, medium_url, mime_type, size_bytes, purpose)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (url) DO NOTHING
+		RETURNING id, created_at
+	`, obj.UserID, obj.URL, obj.ThumbURL, obj.MediumURL, obj.MimeType, obj.SizeBytes, obj.Purpose,
+	).Scan(&obj.ID, &obj.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("record storage object: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO storage_usage (user_id, used_bytes, object_count)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (user_id) DO UPDATE SET
+			used_bytes = storage_usage.used_bytes + EXCLUDED.used_bytes,
+			object_count = storage_usage.object_count + 1
+	`, obj.UserID, obj.SizeBytes); err != nil {
+		return fmt.Errorf("add storage usage: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *storageQuotaRepository) Release(ctx context.Context, url string) error {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	var userID string
+	var size int64
+	err = tx.QueryRow(ctx, `
+		DELETE FROM storage_objects WHERE url = $1
+		RETURNING user_id, size_bytes
+	`, url).Scan(&userID, &size)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("release storage object: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE storage_usage
+		SET used_bytes = GREATEST(used_bytes - $2, 0),
+			object_count = GREATEST(object_count - 1, 0)
+		WHERE user_id = $1
+	`, userID, size); err != nil {
+		return fmt.Errorf("subtract storage usage: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// storageObjectInUse is true while a live post, comment or profile still
+// shows the object o.
+const storageObjectInUse = `(
+	EXISTS (
+		SELECT 1 FROM attachments a JOIN posts p ON p.id = a.post_id
+		WHERE a.photo->>'url' = o.url AND a.deleted_at IS NULL AND p.deleted_at IS NULL
+	)
+	OR EXISTS (
+		SELECT 1 FROM comment_attachments ca JOIN post_comments c ON c.id = ca.comment_id
+		WHERE ca.photo->>'url' = o.url AND ca.deleted_at IS NULL AND c.deleted_at IS NULL
+	)
+	OR EXISTS (
+		SELECT 1 FROM profiles pr
+		WHERE pr.id = o.user_id AND (pr.avatar->>'url' = o.url OR pr.cover->>'url' = o.url)
+	)
+	OR EXISTS (
+		SELECT 1 FROM business_profiles bp
+		WHERE bp.user_id = o.user_id AND bp.deleted_at IS NULL
+			AND (bp.avatar->>'url' = o.url OR bp.cover->>'url' = o.url)
+	)
+)`
+
+const storageObjectColumns = `
+	o.id, o.user_id, o.url, o.thumb_url, o.medium_url, o.mime_type, o.size_bytes, o.purpose, o.created_at,
+	` + storageObjectInUse
+
+func scanStorageObject(row pgx.Row) (*models.StorageObject, error) {
+	o := &models.StorageObject{}
+	err := row.Scan(&o.ID, &o.UserID, &o.URL, &o.ThumbURL, &o.MediumURL, &o.MimeType, &o.SizeBytes, &o.Purpose, &o.CreatedAt, &o.InUse)
+	return o, err
+}
+
+func (r *storageQuotaRepository) List(ctx context.Context, filter *models.StorageObjectFilter) ([]*models.StorageObject, int, error) {
+	var total int
+	if err := r.db.Pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM storage_objects WHERE user_id = $1
+	`, filter.UserID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count storage objects: %w", err)
+	}
+
+	order := "o.created_at ASC"
+	switch filter.Sort {
+	case "largest":
+		order = "o.size_bytes DESC, o.created_at ASC"
+	case "newest":
+		order = "o.created_at DESC"
+	}
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT`+storageObjectColumns+`
+		FROM storage_objects o
+		WHERE o.user_id = $1
+		ORDER BY `+order+`, o.id
+		LIMIT $2 OFFSET $3
+	`, filter.UserID, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list storage objects: %w", err)
+	}
+	defer rows.Close()
+
+	objects := make([]*models.StorageObject, 0, filter.Limit)
+	for rows.Next() {
+		o, err := scanStorageObject(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan storage object: %w", err)
+		}
+		objects = append(objects, o)
+	}
+	return objects, total, rows.Err()
+}
+
+func (r *storageQuotaRepository) Get(ctx context.Context, userID, id string) (*models.StorageObject, error) {
+	o, err := scanStorageObject(r.db.Pool.QueryRow(ctx, `
+		SELECT`+storageObjectColumns+`
+		FROM storage_objects o
+		WHERE o.id = $1 AND o.user_id = $2
+	`, id, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrStorageObjectNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return o, nil
+}
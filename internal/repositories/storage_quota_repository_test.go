@@ -0,0 +1,41 @@
+package repositories_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/testutil"
+)
+
+func newStorageQuotaRepo(pool *testutil.MockPool) repositories.StorageQuotaRepository {
+	return repositories.NewStorageQuotaRepository(testutil.NewTestDB(pool))
+}
+
+func TestStorageQuotaRepository_GetUsage_NeverUploaded(t *testing.T) {
+	pool := new(testutil.MockPool)
+	pool.On("QueryRow", mock.Anything, mock.Anything, []any{"user-1"}).
+		Return(testutil.ErrRow(pgx.ErrNoRows))
+
+	usage, err := newStorageQuotaRepo(pool).GetUsage(context.Background(), "user-1")
+	require.NoError(t, err)
+	assert.Zero(t, usage.UsedBytes)
+	assert.Zero(t, usage.ObjectCount)
+}
+
+func TestStorageQuotaRepository_Get_NotFound(t *testing.T) {
+	pool := new(testutil.MockPool)
+	pool.On("QueryRow", mock.Anything, mock.MatchedBy(func(sql string) bool {
+		return strings.Contains(sql, "WHERE o.id = $1 AND o.user_id = $2") &&
+			strings.Contains(sql, "bp.avatar->>'url' = o.url")
+	}), []any{"obj-1", "user-1"}).Return(testutil.ErrRow(pgx.ErrNoRows))
+
+	_, err := newStorageQuotaRepo(pool).Get(context.Background(), "user-1", "obj-1")
+	assert.ErrorIs(t, err, repositories.ErrStorageObjectNotFound)
+}
@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+)
+
+// StorageReconcileRepository backs the orphaned-storage reconciliation job:
+// it answers which bucket keys the database still points at and keeps the
+// run history shown to admins.
+type StorageReconcileRepository interface {
+	// ReferencedKeys returns the subset of keys some row still points at.
+	// Rows soft-deleted before deletedBefore no longer count, so media of
+	// posts, comments and businesses deleted longer ago than the grace
+	// period becomes reclaimable.
+	ReferencedKeys(ctx context.Context, keys []string, deletedBefore time.Time) (map[string]bool, error)
+	RecordRun(ctx context.Context, run *models.StorageReconcileRun) error
+	ListRuns(ctx context.Context, limit int) ([]*models.StorageReconcileRun, error)
+	// Totals sums the objects deleted and bytes reclaimed by every run.
+	Totals(ctx context.Context) (deletedObjects, reclaimedBytes int64, err error)
+}
+
+type storageReconcileRepository struct {
+	db *database.DB
+}
+
+// NewStorageReconcileRepository creates the repository.
+func NewStorageReconcileRepository(db *database.DB) StorageReconcileRepository {
+	return &storageReconcileRepository{db: db}
+}
+
+// storageReferences lists every stored media URL the database knows about.
+// $2 is the soft-delete cutoff: rows deleted before it no longer hold on to
+// their files. Keep this in sync when a new table starts storing uploads.
+const storageReferences = `
+	SELECT v.url FROM attachments a
+	JOIN posts p ON p.id = a.post_id
+	CROSS JOIN LATERAL (VALUES (a.photo->>'url'), (a.photo->>'thumb_url'), (a.photo->>'medium_url')) v(url)
+	WHERE COALESCE(GREATEST(a.deleted_at, p.deleted_at), 'infinity') > $2
+	UNION ALL
+	SELECT q.blurred_url FROM media_moderation_queue q WHERE q.blurred_url IS NOT NULL
+	UNION ALL
+	SELECT v.url FROM comment_attachments ca
+	JOIN post_comments c ON c.id = ca.comment_id
+	CROSS JOIN LATERAL (VALUES (ca.photo->>'url'), (ca.photo->>'thumb_url'), (ca.photo->>'medium_url')) v(url)
+	WHERE COALESCE(GREATEST(ca.deleted_at, c.deleted_at), 'infinity') > $2
+	UNION ALL
+	SELECT v.url FROM profiles pr
+	CROSS JOIN LATERAL (VALUES
+		(pr.avatar->>'url'), (pr.avatar->>'thumb_url'), (pr.avatar->>'medium_url'),
+		(pr.cover->>'url'), (pr.cover->>'thumb_url'), (pr.cover->>'medium_url')) v(url)
+	UNION ALL
+	SELECT v.url FROM business_profiles bp
+	CROSS JOIN LATERAL (VALUES
+		(bp.avatar->>'url'), (bp.avatar->>'thumb_url'), (bp.avatar->>'medium_url'),
+		(bp.cover->>'url'), (bp.cover->>'thumb_url'), (bp.cover->>'medium_url')) v(url)
+	WHERE COALESCE(bp.deleted_at, 'infinity') > $2
+	UNION ALL
+	SELECT v.url FROM business_attachments ba
+	CROSS JOIN LATERAL (VALUES (ba.photo->>'url'), (ba.photo->>'thumb_url'), (ba.photo->>'medium_url')) v(url)
+	WHERE COALESCE(ba.deleted_at, 'infinity') > $2
+	UNION ALL
+	SELECT q.url FROM business_media_moderation_queue q
+	UNION ALL
+	SELECT q.blurred_url FROM business_media_moderation_queue q WHERE q.blurred_url IS NOT NULL
+	UNION ALL
+	SELECT v.url FROM business_products bpr
+	CROSS JOIN LATERAL jsonb_array_elements(bpr.photos) ph
+	CROSS JOIN LATERAL (VALUES (ph->>'url'), (ph->>'thumb_url'), (ph->>'medium_url')) v(url)
+	WHERE COALESCE(bpr.deleted_at, 'infinity') > $2
+	UNION ALL
+	SELECT doc->>'url' FROM business_verification_requests bvr
+	CROSS JOIN LATERAL jsonb_array_elements(bvr.documents) doc
+	UNION ALL
+	SELECT v.url FROM groups g
+	CROSS JOIN LATERAL (VALUES (g.cover->>'url'), (g.cover->>'thumb_url'), (g.cover->>'medium_url')) v(url)
+	WHERE COALESCE(g.deleted_at, 'infinity') > $2
+	UNION ALL
+	SELECT m.content FROM messages m
+	WHERE m.message_type IN ('IMAGE', 'FILE', 'VOICE') AND COALESCE(m.deleted_at, 'infinity') > $2
+	UNION ALL
+	SELECT ad.image_url FROM ads ad WHERE ad.image_url IS NOT NULL
+	UNION ALL
+	SELECT st.image_url FROM stickers st
+	UNION ALL
+	SELECT usf.url FROM upload_session_files usf
+`
+
+func (r *storageReconcileRepository) ReferencedKeys(ctx context.Context, keys []string, deletedBefore time.Time) (map[string]bool, error) {
+	referenced := make(map[string]bool)
+	if len(keys) == 0 {
+		return referenced, nil
+	}
+
+	// Media keys are "<folder>/<file>", so the last two path segments of a
+	// stored URL are its key whatever CDN base or bucket layout it was
+	// minted with.
+	rows, err := r.db.Pool.Query(ctx, `
+		WITH refs(url) AS (`+storageReferences+`)
+		SELECT DISTINCT k.key
+		FROM unnest($1::text[]) AS k(key)
+		JOIN refs ON substring(refs.url FROM '([^/]+/[^/?#]+)$') = k.key
+	`, keys, deletedBefore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check storage references: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("failed to scan referenced key: %w", err)
+		}
+		referenced[key] = true
+	}
+	return referenced, rows.Err()
+}
+
+func (r *storageReconcileRepository) RecordRun(ctx context.Context, run *models.StorageReconcileRun) error {
+	err := r.db.Pool.QueryRow(ctx, `
+		INSERT INTO storage_reconcile_runs (
+			started_at, finished_at, dry_run, scanned_objects, scanned_bytes,
+			orphaned_objects, orphaned_bytes, deleted_objects, reclaimed_bytes, error
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id
+	`, run.StartedAt, run.FinishedAt, run.DryRun, run.ScannedObjects, run.ScannedBytes,
+		run.OrphanedObjects, run.OrphanedBytes, run.DeletedObjects, run.ReclaimedBytes, run.Error,
+	).Scan(&run.ID)
+	if err != nil {
+		return fmt.Errorf("failed to record reconcile run: %w", err)
+	}
+	return nil
+}
+
+func (r *storageReconcileRepository) ListRuns(ctx context.Context, limit int) ([]*models.StorageReconcileRun, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT id, started_at, finished_at, dry_run, scanned_objects, scanned_bytes,
+			orphaned_objects, orphaned_bytes, deleted_objects, reclaimed_bytes, error
+		FROM storage_reconcile_runs
+		ORDER BY started_at DESC
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reconcile runs: %w", err)
+	}
+	defer rows.Close()
+
+	runs := make([]*models.StorageReconcileRun, 0, limit)
+	for rows.Next() {
+		run := &models.StorageReconcileRun{}
+		if err := rows.Scan(&run.ID, &run.StartedAt, &run.FinishedAt, &run.DryRun, &run.ScannedObjects, &run.ScannedBytes,
+			&run.OrphanedObjects, &run.OrphanedBytes, &run.DeletedObjects, &run.ReclaimedBytes, &run.Error); err != nil {
+			return nil, fmt.Errorf("failed to scan reconcile run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+func (r *storageReconcileRepository) Totals(ctx context.Context) (int64, int64, error) {
+	var deleted, reclaimed int64
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT COALESCE(SUM(deleted_objects), 0), COALESCE(SUM(reclaimed_bytes), 0)
+		FROM storage_reconcile_runs
+	`).Scan(&deleted, &reclaimed)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to sum reconcile runs: %w", err)
+	}
+	return deleted, reclaimed, nil
+}
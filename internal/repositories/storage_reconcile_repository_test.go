@@ -0,0 +1,41 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/testutil"
+)
+
+func TestStorageReconcileRepository_ReferencedKeys_SQL(t *testing.T) {
+	pool := new(testutil.MockPool)
+	pages := capture(pool, "Query")
+	cutoff := time.Date(2026, 10, 9, 0, 0, 0, 0, time.UTC)
+	keys := []string{"post/a.webp", "avatar/b.jpg"}
+
+	_, err := repositories.NewStorageReconcileRepository(testutil.NewTestDB(pool)).
+		ReferencedKeys(context.Background(), keys, cutoff)
+	require.Error(t, err)
+
+	sql := (*pages)[0].sql
+	assert.Contains(t, sql, "JOIN refs ON substring(refs.url FROM '([^/]+/[^/?#]+)$') = k.key")
+	assert.Contains(t, sql, "WHERE COALESCE(GREATEST(a.deleted_at, p.deleted_at), 'infinity') > $2")
+	assert.Contains(t, sql, "jsonb_array_elements(bvr.documents)")
+	assert.Contains(t, sql, "FROM upload_session_files")
+	assert.Equal(t, []any{keys, cutoff}, (*pages)[0].args)
+}
+
+func TestStorageReconcileRepository_ReferencedKeys_NoKeys(t *testing.T) {
+	pool := new(testutil.MockPool)
+
+	referenced, err := repositories.NewStorageReconcileRepository(testutil.NewTestDB(pool)).
+		ReferencedKeys(context.Background(), nil, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, referenced)
+	pool.AssertNotCalled(t, "Query")
+}
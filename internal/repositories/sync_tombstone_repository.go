@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+)
+
+// SyncTombstoneRepository reads the tombstones database triggers leave
+// behind when notifications and conversations are hard-deleted.
+type SyncTombstoneRepository interface {
+	// DeletedSince returns the IDs of userID's entities of the given kind
+	// removed after since, oldest first, at most limit.
+	DeletedSince(ctx context.Context, userID string, entity models.SyncEntity, since time.Time, limit int) ([]string, error)
+	// PurgeOlderThan drops tombstones recorded before cutoff and returns how
+	// many were removed.
+	PurgeOlderThan(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+type syncTombstoneRepository struct {
+	db *database.DB
+}
+
+// NewSyncTombstoneRepository creates a new sync tombstone repository
+func NewSyncTombstoneRepository(db *database.DB) SyncTombstoneRepository {
+	return &syncTombstoneRepository{db: db}
+}
+
+func (r *syncTombstoneRepository) DeletedSince(ctx context.Context, userID string, entity models.SyncEntity, since time.Time, limit int) ([]string, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT entity_id
+		FROM sync_tombstones
+		WHERE user_id = $1 AND entity_type = $2 AND deleted_at > $3
+		ORDER BY deleted_at ASC
+		LIMIT $4
+	`, userID, string(entity), since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tombstones: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan tombstone: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *syncTombstoneRepository) PurgeOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := r.db.Pool.Exec(ctx, `DELETE FROM sync_tombstones WHERE deleted_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge tombstones: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
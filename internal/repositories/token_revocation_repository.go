@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hamsaya/backend/pkg/database"
+)
+
+// TokenRevocationRepository is the durable copy of access-token
+// revocations. Redis answers the per-request checks; these rows let a
+// restarted Redis be refilled so revoked tokens stay revoked.
+type TokenRevocationRepository interface {
+	// AddBlacklisted records a revoked token (by JTI) until it expires.
+	AddBlacklisted(ctx context.Context, tokenID string, expiresAt time.Time, reason string) error
+
+	// ListBlacklisted returns unexpired revoked tokens with their expiry.
+	ListBlacklisted(ctx context.Context) (map[string]time.Time, error)
+
+	// DeleteExpiredBlacklisted removes revocations of tokens that have
+	// expired anyway.
+	DeleteExpiredBlacklisted(ctx context.Context) (int64, error)
+
+	// SetTokensInvalidBefore records a user's logout-all cutoff.
+	SetTokensInvalidBefore(ctx context.Context, userID string, at time.Time) error
+
+	// ListTokensInvalidBefore returns the cutoffs set after since, by user.
+	ListTokensInvalidBefore(ctx context.Context, since time.Time) (map[string]time.Time, error)
+
+	// IsRevoked reports whether the token was revoked or issued (at
+	// issuedAt) no later than its user's logout-all cutoff. The fallback
+	// for when Redis can't answer.
+	IsRevoked(ctx context.Context, tokenID, userID string, issuedAt time.Time) (bool, error)
+}
+
+type tokenRevocationRepository struct {
+	db *database.DB
+}
+
+// NewTokenRevocationRepository wires a new token revocation repository.
+func NewTokenRevocationRepository(db *database.DB) TokenRevocationRepository {
+	return &tokenRevocationRepository{db: db}
+}
+
+func (r *tokenRevocationRepository) AddBlacklisted(ctx context.Context, tokenID string, expiresAt time.Time, reason string) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		INSERT INTO token_blacklist (token_hash, expires_at, reason)
+		VALUES ($1, $2, NULLIF($3, ''))
+		ON CONFLICT (token_hash) DO NOTHING
+	`, tokenID, expiresAt, reason)
+	if err != nil {
+		return fmt.Errorf("failed to record revoked token: %w", err)
+	}
+	return nil
+}
+
+func (r *tokenRevocationRepository) ListBlacklisted(ctx context.Context) (map[string]time.Time, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT token_hash, expires_at FROM token_blacklist WHERE expires_at > NOW()
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revoked tokens: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[string]time.Time)
+	for rows.Next() {
+		var id string
+		var expiresAt time.Time
+		if err := rows.Scan(&id, &expiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan revoked token: %w", err)
+		}
+		out[id] = expiresAt
+	}
+	return out, rows.Err()
+}
+
+func (r *tokenRevocationRepository) DeleteExpiredBlacklisted(ctx context.Context) (int64, error) {
+	tag, err := r.db.Pool.Exec(ctx, `DELETE FROM token_blacklist WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired revocations: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (r *tokenRevocationRepository) SetTokensInvalidBefore(ctx context.Context, userID string, at time.Time) error {
+	_, err := r.db.Pool.Exec(ctx, `
+		UPDATE users SET tokens_invalid_before = $2 WHERE id = $1
+	`, userID, at)
+	if err != nil {
+		return fmt.Errorf("failed to set tokens_invalid_before: %w", err)
+	}
+	return nil
+}
+
+func (r *tokenRevocationRepository) ListTokensInvalidBefore(ctx context.Context, since time.Time) (map[string]time.Time, error) {
+	rows, err := r.db.Pool.Query(ctx, `
+		SELECT id::text, tokens_invalid_before FROM users WHERE tokens_invalid_before > $1
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tokens_invalid_before: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[string]time.Time)
+	for rows.Next() {
+		var userID string
+		var at time.Time
+		if err := rows.Scan(&userID, &at); err != nil {
+			return nil, fmt.Errorf("failed to scan tokens_invalid_before: %w", err)
+		}
+		out[userID] = at
+	}
+	return out, rows.Err()
+}
+
+func (r *tokenRevocationRepository) IsRevoked(ctx context.Context, tokenID, userID string, issuedAt time.Time) (bool, error) {
+	var revoked bool
+	err := r.db.Pool.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM token_blacklist WHERE token_hash = $1 AND expires_at > NOW())
+		    OR EXISTS (SELECT 1 FROM users WHERE id = $2 AND tokens_invalid_before >= $3)
+	`, tokenID, userID, issuedAt).Scan(&revoked)
+	if err != nil {
+		return false, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+	return revoked, nil
+}
@@ -0,0 +1,199 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// UploadSessionRepository tracks images uploaded ahead of post creation so
+// the ones never attached to a post can be removed from storage.
+type UploadSessionRepository interface {
+	// Create inserts a session; created_at is filled on the struct.
+	Create(ctx context.Context, session *models.UploadSession) error
+
+	// GetByID returns a session.
+	GetByID(ctx context.Context, sessionID string) (*models.UploadSession, error)
+
+	// AddFile records an image uploaded into a session.
+	AddFile(ctx context.Context, file *models.UploadSessionFile) error
+
+	// ListFiles returns the session's uploads, oldest first.
+	ListFiles(ctx context.Context, sessionID string) ([]*models.UploadSessionFile, error)
+
+	// Claim marks the session as used by postID and the uploads among urls
+	// as kept. False when the session was already claimed.
+	Claim(ctx context.Context, sessionID, postID string, urls []string) (bool, error)
+
+	// ListStaleFiles returns unclaimed uploads created before cutoff. An
+	// image some attachment points at is left out even when unclaimed, so
+	// a post created without its session never loses its images.
+	ListStaleFiles(ctx context.Context, cutoff time.Time, limit int) ([]*models.UploadSessionFile, error)
+
+	// DeleteFiles removes upload records.
+	DeleteFiles(ctx context.Context, fileIDs []string) error
+
+	// DeleteEmptySessions removes unclaimed sessions created before cutoff
+	// that have no uploads left.
+	DeleteEmptySessions(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+type uploadSessionRepository struct {
+	db *database.DB
+}
+
+// NewUploadSessionRepository wires a new upload session repository.
+func NewUploadSessionRepository(db *database.DB) UploadSessionRepository {
+	return &uploadSessionRepository{db: db}
+}
+
+// ErrUploadSessionNotFound is returned when an upload session id doesn't exist.
+var ErrUploadSessionNotFound = newNotFoundError("upload session not found")
+
+func (r *uploadSessionRepository) Create(ctx context.Context, session *models.UploadSession) error {
+	const q = `
+		INSERT INTO upload_sessions (id, user_id, created_at)
+		VALUES ($1, $2, NOW())
+		RETURNING created_at
+	`
+	if err := r.db.Pool.QueryRow(ctx, q, session.ID, session.UserID).Scan(&session.CreatedAt); err != nil {
+		return fmt.Errorf("create upload session: %w", err)
+	}
+	return nil
+}
+
+func (r *uploadSessionRepository) GetByID(ctx context.Context, sessionID string) (*models.UploadSession, error) {
+	const q = `
+		SELECT id, user_id, post_id, claimed_at, created_at
+		FROM upload_sessions
+		WHERE id = $1
+	`
+	s := &models.UploadSession{}
+	err := r.db.Pool.QueryRow(ctx, q, sessionID).Scan(&s.ID, &s.UserID, &s.PostID, &s.ClaimedAt, &s.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrUploadSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get upload session: %w", err)
+	}
+	return s, nil
+}
+
+func (r *uploadSessionRepository) AddFile(ctx context.Context, file *models.UploadSessionFile) error {
+	const q = `
+		INSERT INTO upload_session_files (id, session_id, url, photo, created_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		RETURNING created_at
+	`
+	if err := r.db.Pool.QueryRow(ctx, q, file.ID, file.SessionID, file.Photo.URL, file.Photo).Scan(&file.CreatedAt); err != nil {
+		return fmt.Errorf("add upload session file: %w", err)
+	}
+	return nil
+}
+
+func scanUploadSessionFiles(rows pgx.Rows) ([]*models.UploadSessionFile, error) {
+	defer rows.Close()
+	var files []*models.UploadSessionFile
+	for rows.Next() {
+		f := &models.UploadSessionFile{}
+		if err := rows.Scan(&f.ID, &f.SessionID, &f.Photo, &f.ClaimedAt, &f.CreatedAt); err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return files, rows.Err()
+}
+
+func (r *uploadSessionRepository) ListFiles(ctx context.Context, sessionID string) ([]*models.UploadSessionFile, error) {
+	const q = `
+		SELECT id, session_id, photo, claimed_at, created_at
+		FROM upload_session_files
+		WHERE session_id = $1
+		ORDER BY created_at ASC
+	`
+	rows, err := r.db.Pool.Query(ctx, q, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("list upload session files: %w", err)
+	}
+	files, err := scanUploadSessionFiles(rows)
+	if err != nil {
+		return nil, fmt.Errorf("scan upload session files: %w", err)
+	}
+	return files, nil
+}
+
+func (r *uploadSessionRepository) Claim(ctx context.Context, sessionID, postID string, urls []string) (bool, error) {
+	tx, err := r.db.Pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin claim upload session: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE upload_sessions SET post_id = $2, claimed_at = NOW()
+		WHERE id = $1 AND claimed_at IS NULL
+	`, sessionID, postID)
+	if err != nil {
+		return false, fmt.Errorf("claim upload session: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE upload_session_files SET claimed_at = NOW()
+		WHERE session_id = $1 AND url = ANY($2)
+	`, sessionID, urls); err != nil {
+		return false, fmt.Errorf("claim upload session files: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit claim upload session: %w", err)
+	}
+	return true, nil
+}
+
+func (r *uploadSessionRepository) ListStaleFiles(ctx context.Context, cutoff time.Time, limit int) ([]*models.UploadSessionFile, error) {
+	const q = `
+		SELECT f.id, f.session_id, f.photo, f.claimed_at, f.created_at
+		FROM upload_session_files f
+		WHERE f.claimed_at IS NULL AND f.created_at < $1
+		  AND NOT EXISTS (SELECT 1 FROM attachments a WHERE a.photo->>'url' = f.url)
+		ORDER BY f.created_at ASC
+		LIMIT $2
+	`
+	rows, err := r.db.Pool.Query(ctx, q, cutoff, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list stale upload files: %w", err)
+	}
+	files, err := scanUploadSessionFiles(rows)
+	if err != nil {
+		return nil, fmt.Errorf("scan stale upload files: %w", err)
+	}
+	return files, nil
+}
+
+func (r *uploadSessionRepository) DeleteFiles(ctx context.Context, fileIDs []string) error {
+	if len(fileIDs) == 0 {
+		return nil
+	}
+	if _, err := r.db.Pool.Exec(ctx, `DELETE FROM upload_session_files WHERE id = ANY($1)`, fileIDs); err != nil {
+		return fmt.Errorf("delete upload session files: %w", err)
+	}
+	return nil
+}
+
+func (r *uploadSessionRepository) DeleteEmptySessions(ctx context.Context, cutoff time.Time) (int64, error) {
+	tag, err := r.db.Pool.Exec(ctx, `
+		DELETE FROM upload_sessions s
+		WHERE s.claimed_at IS NULL AND s.created_at < $1
+		  AND NOT EXISTS (SELECT 1 FROM upload_session_files f WHERE f.session_id = s.id)
+	`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("delete empty upload sessions: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
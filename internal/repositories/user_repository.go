@@ -30,6 +30,8 @@ type UserRepository interface {
 	Update(ctx context.Context, user *models.User) error
 	UpdateLoginAttempts(ctx context.Context, userID string, attempts int, lockedUntil *time.Time) error
 	UpdateLastLogin(ctx context.Context, userID string) error
+	// IsShadowbanned reports whether an admin has shadowbanned the user.
+	IsShadowbanned(ctx context.Context, userID string) (bool, error)
 
 	// Profile operations
 	CreateProfile(ctx context.Context, profile *models.Profile) error
@@ -40,7 +42,31 @@ type UserRepository interface {
 	// province/district/neighborhood (case-insensitive), excluding excludeUserID.
 	// Used to notify neighbors when someone posts in their area.
 	GetUserIDsByNeighborhood(ctx context.Context, province, district, neighborhood, excludeUserID string, limit, offset int) ([]string, error)
+	// GetUserIDsWithinRadius returns active profile IDs whose saved location
+	// is within radiusKm of (lat, lng), excluding excludeUserID. Used for
+	// safety alert pushes.
+	GetUserIDsWithinRadius(ctx context.Context, lat, lng, radiusKm float64, excludeUserID string, limit, offset int) ([]string, error)
+	// UpdateProfile saves the profile if it is still at profile.Version,
+	// bumping the version; ErrVersionConflict otherwise.
 	UpdateProfile(ctx context.Context, profile *models.Profile) error
+	// FillProfileAddress sets the profile's empty country / province /
+	// district / neighborhood from a reverse-geocoded address. It's a no-op
+	// if the saved location has since moved away from (lat, lng).
+	FillProfileAddress(ctx context.Context, profileID string, lat, lng float64, addr *models.ResolvedAddress) error
+	// GetContentLanguages returns the languages the user reads; empty
+	// means no preference.
+	GetContentLanguages(ctx context.Context, userID string) ([]string, error)
+	SetContentLanguages(ctx context.Context, userID string, languages []string) error
+	// SetProfileHandle sets the user's @handle unless it was last changed
+	// after changedBefore. Returns ErrHandleTaken when another profile has
+	// it and ErrHandleCooldown when changed too recently.
+	SetProfileHandle(ctx context.Context, userID, handle string, changedBefore time.Time) error
+	// GetUserIDByHandle returns the active profile with the handle, or
+	// ErrHandleNotFound.
+	GetUserIDByHandle(ctx context.Context, handle string) (string, error)
+	// GetUserIDsByHandles maps each handle held by an active profile to its
+	// user ID; unknown handles are left out.
+	GetUserIDsByHandles(ctx context.Context, handles []string) (map[string]string, error)
 
 	// Transactional operations
 	CreateUserWithProfile(ctx context.Context, user *models.User, profile *models.Profile) error
@@ -121,6 +147,20 @@ func (r *userRepository) Create(ctx context.Context, user *models.User) error {
 	return nil
 }
 
+// IsShadowbanned reports whether the user is currently shadowbanned.
+// Unknown users report false.
+func (r *userRepository) IsShadowbanned(ctx context.Context, userID string) (bool, error) {
+	var banned bool
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM users WHERE id = $1 AND shadowbanned_at IS NOT NULL)`,
+		userID,
+	).Scan(&banned)
+	if err != nil {
+		return false, fmt.Errorf("failed to check shadowban: %w", err)
+	}
+	return banned, nil
+}
+
 // GetByID retrieves a user by ID
 func (r *userRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
 	query := `
@@ -154,7 +194,7 @@ func (r *userRepository) GetByID(ctx context.Context, id string) (*models.User,
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("user not found")
+			return nil, newNotFoundError("user not found")
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
@@ -195,7 +235,7 @@ func (r *userRepository) GetByIDIncludingDeleted(ctx context.Context, id string)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("user not found")
+			return nil, newNotFoundError("user not found")
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
@@ -236,7 +276,7 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("user not found")
+			return nil, newNotFoundError("user not found")
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
@@ -277,7 +317,7 @@ func (r *userRepository) GetByOAuthProviderID(ctx context.Context, provider, pro
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("user not found")
+			return nil, newNotFoundError("user not found")
 		}
 		return nil, fmt.Errorf("failed to get user by oauth provider id: %w", err)
 	}
@@ -318,7 +358,7 @@ func (r *userRepository) GetByEmailIncludingDeleted(ctx context.Context, email s
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("user not found")
+			return nil, newNotFoundError("user not found")
 		}
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
@@ -350,11 +390,15 @@ func (r *userRepository) Update(ctx context.Context, user *models.User) error {
 	)
 
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+			return ErrEmailTaken
+		}
 		return fmt.Errorf("failed to update user: %w", err)
 	}
 
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("user not found")
+		return newNotFoundError("user not found")
 	}
 
 	return nil
@@ -447,7 +491,7 @@ func (r *userRepository) GetProfileByUserID(ctx context.Context, userID string)
 			ST_X(location::geometry) as longitude,
 			ST_Y(location::geometry) as latitude,
 			country, province, district, neighborhood, is_complete,
-			created_at, updated_at, deleted_at
+			created_at, updated_at, deleted_at, version, handle, handle_changed_at
 		FROM profiles
 		WHERE id = $1 AND deleted_at IS NULL
 	`
@@ -475,11 +519,14 @@ func (r *userRepository) GetProfileByUserID(ctx context.Context, userID string)
 		&profile.CreatedAt,
 		&profile.UpdatedAt,
 		&profile.DeletedAt,
+		&profile.Version,
+		&profile.Handle,
+		&profile.HandleChangedAt,
 	)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("profile not found")
+			return nil, newNotFoundError("profile not found")
 		}
 		return nil, fmt.Errorf("failed to get profile: %w", err)
 	}
@@ -508,7 +555,7 @@ func (r *userRepository) GetProfilesByUserIDs(ctx context.Context, userIDs []str
 			ST_X(location::geometry) as longitude,
 			ST_Y(location::geometry) as latitude,
 			country, province, district, neighborhood, is_complete,
-			created_at, updated_at, deleted_at
+			created_at, updated_at, deleted_at, version, handle, handle_changed_at
 		FROM profiles
 		WHERE id = ANY($1) AND deleted_at IS NULL
 	`
@@ -544,6 +591,9 @@ func (r *userRepository) GetProfilesByUserIDs(ctx context.Context, userIDs []str
 			&profile.CreatedAt,
 			&profile.UpdatedAt,
 			&profile.DeletedAt,
+			&profile.Version,
+			&profile.Handle,
+			&profile.HandleChangedAt,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan profile: %w", err)
 		}
@@ -559,6 +609,38 @@ func (r *userRepository) GetProfilesByUserIDs(ctx context.Context, userIDs []str
 	return profiles, rows.Err()
 }
 
+// GetUserIDsWithinRadius returns active profile IDs whose profiles.location
+// lies within radiusKm of the point, excluding the poster. Profiles without a
+// saved location are never matched.
+func (r *userRepository) GetUserIDsWithinRadius(ctx context.Context, lat, lng, radiusKm float64, excludeUserID string, limit, offset int) ([]string, error) {
+	query := `
+		SELECT id
+		FROM profiles
+		WHERE deleted_at IS NULL
+			AND id <> $1
+			AND location IS NOT NULL
+			AND ST_DWithin(location, ST_SetSRID(ST_MakePoint($2, $3), 4326)::geography, $4)
+		ORDER BY id
+		LIMIT $5 OFFSET $6
+	`
+
+	rows, err := r.db.Pool.Query(ctx, query, excludeUserID, lng, lat, radiusKm*1000, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user ids within radius: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0, limit)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan nearby user id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
 // GetUserIDsByNeighborhood returns active profile IDs in the same
 // province/district/neighborhood (case-insensitive, trimmed), excluding the
 // poster. neighborhood must be non-empty; province/district narrow the match so
@@ -604,7 +686,7 @@ func (r *userRepository) GetProfileByUserIDIncludingDeleted(ctx context.Context,
 			ST_X(location::geometry) as longitude,
 			ST_Y(location::geometry) as latitude,
 			country, province, district, neighborhood, is_complete,
-			created_at, updated_at, deleted_at
+			created_at, updated_at, deleted_at, version, handle, handle_changed_at
 		FROM profiles
 		WHERE id = $1
 	`
@@ -632,11 +714,14 @@ func (r *userRepository) GetProfileByUserIDIncludingDeleted(ctx context.Context,
 		&profile.CreatedAt,
 		&profile.UpdatedAt,
 		&profile.DeletedAt,
+		&profile.Version,
+		&profile.Handle,
+		&profile.HandleChangedAt,
 	)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("profile not found")
+			return nil, newNotFoundError("profile not found")
 		}
 		return nil, fmt.Errorf("failed to get profile: %w", err)
 	}
@@ -651,6 +736,122 @@ func (r *userRepository) GetProfileByUserIDIncludingDeleted(ctx context.Context,
 	return profile, nil
 }
 
+// FillProfileAddress backfills empty address text; the location trigger
+// then resolves province_id / district_id from it.
+func (r *userRepository) FillProfileAddress(ctx context.Context, profileID string, lat, lng float64, addr *models.ResolvedAddress) error {
+	query := `
+		UPDATE profiles
+		SET country = COALESCE(NULLIF(country, ''), NULLIF($4, '')),
+			province = COALESCE(NULLIF(province, ''), NULLIF($5, '')),
+			district = COALESCE(NULLIF(district, ''), NULLIF($6, '')),
+			neighborhood = COALESCE(NULLIF(neighborhood, ''), NULLIF($7, ''))
+		WHERE id = $1 AND deleted_at IS NULL
+			AND ST_DWithin(location, ST_SetSRID(ST_MakePoint($2, $3), 4326)::geography, 1)
+	`
+
+	if _, err := r.db.Pool.Exec(ctx, query, profileID, lng, lat,
+		addr.Country, addr.Province, addr.District, addr.Neighborhood,
+	); err != nil {
+		return fmt.Errorf("fill profile address: %w", err)
+	}
+	return nil
+}
+
+func (r *userRepository) GetContentLanguages(ctx context.Context, userID string) ([]string, error) {
+	var languages []string
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT content_languages FROM profiles WHERE id = $1`, userID,
+	).Scan(&languages)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get content languages: %w", err)
+	}
+	return languages, nil
+}
+
+func (r *userRepository) SetContentLanguages(ctx context.Context, userID string, languages []string) error {
+	if languages == nil {
+		languages = []string{}
+	}
+	if _, err := r.db.Pool.Exec(ctx,
+		`UPDATE profiles SET content_languages = $2, updated_at = NOW() WHERE id = $1`,
+		userID, languages,
+	); err != nil {
+		return fmt.Errorf("set content languages: %w", err)
+	}
+	return nil
+}
+
+// SetProfileHandle sets the handle in one statement so the cooldown and
+// uniqueness checks can't race a concurrent rename.
+func (r *userRepository) SetProfileHandle(ctx context.Context, userID, handle string, changedBefore time.Time) error {
+	tag, err := r.db.Pool.Exec(ctx, `
+		UPDATE profiles
+		SET handle = $2, handle_changed_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+			AND (handle_changed_at IS NULL OR handle_changed_at <= $3)
+	`, userID, handle, changedBefore)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrHandleTaken
+		}
+		return fmt.Errorf("set profile handle: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		var exists bool
+		if err := r.db.Pool.QueryRow(ctx,
+			`SELECT EXISTS(SELECT 1 FROM profiles WHERE id = $1 AND deleted_at IS NULL)`, userID,
+		).Scan(&exists); err != nil {
+			return fmt.Errorf("set profile handle: %w", err)
+		}
+		if !exists {
+			return newNotFoundError("profile not found")
+		}
+		return ErrHandleCooldown
+	}
+	return nil
+}
+
+// GetUserIDByHandle looks up an active profile by handle.
+func (r *userRepository) GetUserIDByHandle(ctx context.Context, handle string) (string, error) {
+	var id string
+	err := r.db.Pool.QueryRow(ctx,
+		`SELECT id FROM profiles WHERE handle = $1 AND deleted_at IS NULL`, handle,
+	).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrHandleNotFound
+		}
+		return "", fmt.Errorf("get user by handle: %w", err)
+	}
+	return id, nil
+}
+
+// GetUserIDsByHandles resolves handles to user IDs in one query.
+func (r *userRepository) GetUserIDsByHandles(ctx context.Context, handles []string) (map[string]string, error) {
+	ids := make(map[string]string, len(handles))
+	if len(handles) == 0 {
+		return ids, nil
+	}
+	rows, err := r.db.Pool.Query(ctx,
+		`SELECT handle, id FROM profiles WHERE handle = ANY($1) AND deleted_at IS NULL`, handles)
+	if err != nil {
+		return nil, fmt.Errorf("get users by handles: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var handle, id string
+		if err := rows.Scan(&handle, &id); err != nil {
+			return nil, fmt.Errorf("scan user handle: %w", err)
+		}
+		ids[handle] = id
+	}
+	return ids, rows.Err()
+}
+
 // UpdateProfile updates a user profile
 func (r *userRepository) UpdateProfile(ctx context.Context, profile *models.Profile) error {
 	// Build query based on whether location is provided
@@ -665,8 +866,9 @@ func (r *userRepository) UpdateProfile(ctx context.Context, profile *models.Prof
 				location = ST_SetSRID(ST_MakePoint($4, $5), 4326)::geography,
 				about = $6, gender = $7, dob = $8, website = $9, country = $10,
 				province = $11, district = $12, neighborhood = $13, avatar = $14, avatar_color = $15, cover = $16,
-				is_complete = $17, updated_at = $18
-			WHERE id = $1 AND deleted_at IS NULL
+				is_complete = $17, updated_at = $18, version = version + 1
+			WHERE id = $1 AND deleted_at IS NULL AND version = $19
+			RETURNING version
 		`
 		args = []interface{}{
 			profile.ID,
@@ -687,6 +889,7 @@ func (r *userRepository) UpdateProfile(ctx context.Context, profile *models.Prof
 			profile.Cover,
 			profile.IsComplete,
 			time.Now(),
+			profile.Version,
 		}
 	} else {
 		query = `
@@ -694,8 +897,9 @@ func (r *userRepository) UpdateProfile(ctx context.Context, profile *models.Prof
 			SET first_name = $2, last_name = $3, about = $4, gender = $5,
 				dob = $6, website = $7, country = $8, province = $9,
 				district = $10, neighborhood = $11, avatar = $12, avatar_color = $13, cover = $14,
-				is_complete = $15, updated_at = $16
-			WHERE id = $1 AND deleted_at IS NULL
+				is_complete = $15, updated_at = $16, version = version + 1
+			WHERE id = $1 AND deleted_at IS NULL AND version = $17
+			RETURNING version
 		`
 		args = []interface{}{
 			profile.ID,
@@ -714,18 +918,28 @@ func (r *userRepository) UpdateProfile(ctx context.Context, profile *models.Prof
 			profile.Cover,
 			profile.IsComplete,
 			time.Now(),
+			profile.Version,
 		}
 	}
 
-	result, err := r.db.Pool.Exec(ctx, query, args...)
+	err := r.db.Pool.QueryRow(ctx, query, args...).Scan(&profile.Version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Either gone or saved elsewhere since profile was loaded.
+		var exists bool
+		if err := r.db.Pool.QueryRow(ctx,
+			`SELECT EXISTS (SELECT 1 FROM profiles WHERE id = $1 AND deleted_at IS NULL)`, profile.ID,
+		).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to update profile: %w", err)
+		}
+		if exists {
+			return ErrVersionConflict
+		}
+		return newNotFoundError("profile not found")
+	}
 	if err != nil {
 		return fmt.Errorf("failed to update profile: %w", err)
 	}
 
-	if result.RowsAffected() == 0 {
-		return fmt.Errorf("profile not found")
-	}
-
 	return nil
 }
 
@@ -733,8 +947,9 @@ func (r *userRepository) UpdateProfile(ctx context.Context, profile *models.Prof
 func (r *userRepository) CreateSession(ctx context.Context, session *models.UserSession) error {
 	query := `
 		INSERT INTO user_sessions (id, user_id, refresh_token, refresh_token_hash, access_token_hash,
-			family_id, device_info, ip_address, user_agent, expires_at, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			family_id, device_info, ip_address, user_agent, expires_at, created_at, updated_at,
+			aal, is_admin)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 	`
 
 	// Convert device_info string to JSONB format.
@@ -776,6 +991,9 @@ func (r *userRepository) CreateSession(ctx context.Context, session *models.User
 		familyID = &session.ID
 		session.FamilyID = familyID
 	}
+	if session.AAL == 0 {
+		session.AAL = models.AAL1
+	}
 
 	_, err := r.db.Pool.Exec(ctx, query,
 		session.ID,
@@ -790,6 +1008,8 @@ func (r *userRepository) CreateSession(ctx context.Context, session *models.User
 		session.ExpiresAt,
 		session.CreatedAt,
 		session.UpdatedAt,
+		session.AAL,
+		session.Admin,
 	)
 
 	return err
@@ -799,7 +1019,7 @@ func (r *userRepository) CreateSession(ctx context.Context, session *models.User
 // matching scan order. Update this list whenever the columns change.
 const sessionSelectCols = `id, user_id, refresh_token, refresh_token_hash, access_token_hash,
 	family_id, replaced_by_session_id, device_info, ip_address::text, user_agent,
-	expires_at, revoked, revoked_at, created_at, updated_at`
+	expires_at, revoked, revoked_at, created_at, updated_at, aal, is_admin`
 
 func scanSession(row interface {
 	Scan(dest ...any) error
@@ -808,7 +1028,7 @@ func scanSession(row interface {
 	if err := row.Scan(
 		&s.ID, &s.UserID, &s.RefreshToken, &s.RefreshTokenHash, &s.AccessTokenHash,
 		&s.FamilyID, &s.ReplacedBySessionID, &s.DeviceInfo, &s.IPAddress, &s.UserAgent,
-		&s.ExpiresAt, &s.Revoked, &s.RevokedAt, &s.CreatedAt, &s.UpdatedAt,
+		&s.ExpiresAt, &s.Revoked, &s.RevokedAt, &s.CreatedAt, &s.UpdatedAt, &s.AAL, &s.Admin,
 	); err != nil {
 		return nil, err
 	}
@@ -821,7 +1041,7 @@ func (r *userRepository) GetSessionByID(ctx context.Context, sessionID string) (
 	session, err := scanSession(r.db.Pool.QueryRow(ctx, query, sessionID))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("session not found")
+			return nil, newNotFoundError("session not found")
 		}
 		return nil, fmt.Errorf("failed to get session: %w", err)
 	}
@@ -834,7 +1054,7 @@ func (r *userRepository) GetSessionByRefreshToken(ctx context.Context, refreshTo
 	session, err := scanSession(r.db.Pool.QueryRow(ctx, query, refreshToken))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("session not found")
+			return nil, newNotFoundError("session not found")
 		}
 		return nil, fmt.Errorf("failed to get session: %w", err)
 	}
@@ -848,7 +1068,7 @@ func (r *userRepository) GetSessionByRefreshTokenHash(ctx context.Context, refre
 	session, err := scanSession(r.db.Pool.QueryRow(ctx, query, refreshTokenHash))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("session not found")
+			return nil, newNotFoundError("session not found")
 		}
 		return nil, fmt.Errorf("failed to get session: %w", err)
 	}
@@ -864,7 +1084,7 @@ func (r *userRepository) GetSessionByRefreshTokenHashAny(ctx context.Context, re
 	session, err := scanSession(r.db.Pool.QueryRow(ctx, query, refreshTokenHash))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("session not found")
+			return nil, newNotFoundError("session not found")
 		}
 		return nil, fmt.Errorf("failed to get session: %w", err)
 	}
@@ -909,7 +1129,7 @@ func (r *userRepository) SoftDelete(ctx context.Context, userID string) error {
 		return fmt.Errorf("failed to soft delete user: %w", err)
 	}
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("user not found or already deleted")
+		return newNotFoundError("user not found or already deleted")
 	}
 	return nil
 }
@@ -926,7 +1146,7 @@ func (r *userRepository) Restore(ctx context.Context, userID string) error {
 		return fmt.Errorf("failed to restore user: %w", err)
 	}
 	if result.RowsAffected() == 0 {
-		return fmt.Errorf("user not found or not deleted")
+		return newNotFoundError("user not found or not deleted")
 	}
 	return nil
 }
@@ -1010,7 +1230,7 @@ func (r *userRepository) GetDeviceCredentialByHash(ctx context.Context, credenti
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("device credential not found")
+			return nil, newNotFoundError("device credential not found")
 		}
 		return nil, fmt.Errorf("failed to get device credential: %w", err)
 	}
@@ -1027,7 +1247,18 @@ func (r *userRepository) TouchDeviceCredential(ctx context.Context, credentialID
 
 // ErrDeviceCredentialNotFound is returned when a revoke targets a credential
 // that doesn't exist or isn't owned by the caller.
-var ErrDeviceCredentialNotFound = errors.New("device credential not found")
+var ErrDeviceCredentialNotFound = newNotFoundError("device credential not found")
+
+// ErrEmailTaken is returned by Update when the new email belongs to another
+// account.
+var ErrEmailTaken = newConflictError("email already in use")
+
+// Handle errors returned by SetProfileHandle and GetUserIDByHandle.
+var (
+	ErrHandleTaken    = newConflictError("handle already taken")
+	ErrHandleCooldown = errors.New("handle changed too recently")
+	ErrHandleNotFound = newNotFoundError("handle not found")
+)
 
 // RevokeDeviceCredential marks a single credential dead. Existing sessions
 // minted from it remain valid until their natural expiry.
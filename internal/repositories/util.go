@@ -12,3 +12,7 @@ var likeReplacer = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
 func EscapeLike(s string) string {
 	return likeReplacer.Replace(s)
 }
+
+// ErrVersionConflict is returned by versioned updates (posts, profiles) when
+// the row changed since the caller loaded it.
+var ErrVersionConflict = newConflictError("version conflict")
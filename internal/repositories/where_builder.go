@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"strconv"
+	"strings"
+)
+
+// whereBuilder assembles a dynamic WHERE clause and its positional
+// arguments, so list queries with optional filters don't count $N by hand.
+//
+// Conditions are written with ? for their arguments:
+//
+//	b.where("u.role = ?", filter.Role)
+//	b.where(`(u.email ILIKE ? OR p.first_name ILIKE ?)`, pattern)
+//
+// With one argument every ? in the condition refers to it; with several,
+// the i-th ? takes the i-th argument. Conditions are only ever written in
+// code, never taken from input, so ? can't be confused with a value. Don't
+// use it for the jsonb ? operator; write jsonb_exists() instead.
+type whereBuilder struct {
+	conds []string
+	args  []interface{}
+}
+
+// newWhereBuilder starts a clause with the given argument-free conditions
+// (typically "x.deleted_at IS NULL").
+func newWhereBuilder(conds ...string) *whereBuilder {
+	return &whereBuilder{conds: conds}
+}
+
+// where adds a condition, numbering its ? placeholders.
+func (b *whereBuilder) where(cond string, args ...interface{}) *whereBuilder {
+	if len(args) == 0 {
+		b.conds = append(b.conds, cond)
+		return b
+	}
+
+	var sb strings.Builder
+	shared := ""
+	if len(args) == 1 {
+		shared = b.bind(args[0])
+	}
+	next := 0
+	for i := 0; i < len(cond); i++ {
+		if cond[i] != '?' {
+			sb.WriteByte(cond[i])
+			continue
+		}
+		if shared != "" {
+			sb.WriteString(shared)
+			continue
+		}
+		if next >= len(args) {
+			panic("whereBuilder: more placeholders than arguments in " + cond)
+		}
+		sb.WriteString(b.bind(args[next]))
+		next++
+	}
+	if shared == "" && next != len(args) {
+		panic("whereBuilder: more arguments than placeholders in " + cond)
+	}
+	b.conds = append(b.conds, sb.String())
+	return b
+}
+
+// bind appends v to the arguments and returns its placeholder ("$3"), for
+// values outside the WHERE clause such as LIMIT and OFFSET.
+func (b *whereBuilder) bind(v interface{}) string {
+	return "$" + strconv.Itoa(b.bindIndex(v))
+}
+
+// bindIndex appends v and returns its parameter number, for helpers that
+// format their own placeholders (provinceFilter, shadowbanPredicate).
+func (b *whereBuilder) bindIndex(v interface{}) int {
+	b.args = append(b.args, v)
+	return len(b.args)
+}
+
+// clause returns the conditions joined with AND; "1=1" when there are none.
+func (b *whereBuilder) clause() string {
+	if len(b.conds) == 0 {
+		return "1=1"
+	}
+	return strings.Join(b.conds, " AND ")
+}
+
+// params returns the arguments bound so far, in placeholder order.
+func (b *whereBuilder) params() []interface{} {
+	return b.args
+}
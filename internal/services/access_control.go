@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+)
+
+// AccessControl decides who may change an object: edit or delete a post,
+// manage a business, delete a comment. Every service asks it instead of
+// comparing user ids itself, so a business owner is treated the same on
+// every path. Who may read a post is PostAuthorizer's job.
+type AccessControl struct {
+	businessRepo repositories.BusinessRepository
+}
+
+// NewAccessControl creates an access control.
+func NewAccessControl(businessRepo repositories.BusinessRepository) *AccessControl {
+	return &AccessControl{businessRepo: businessRepo}
+}
+
+// CanManageBusiness reports whether userID owns the business.
+func (a *AccessControl) CanManageBusiness(business *models.BusinessProfile, userID string) bool {
+	return business != nil && userID != "" && business.UserID == userID
+}
+
+// RequireBusinessOwner loads the business; 404 when it doesn't exist, 403
+// when userID doesn't own it.
+func (a *AccessControl) RequireBusinessOwner(ctx context.Context, businessID, userID string) (*models.BusinessProfile, error) {
+	business, err := a.businessRepo.GetByID(ctx, businessID)
+	if err != nil || business == nil {
+		return nil, utils.NewNotFoundError("Business not found", err)
+	}
+	if !a.CanManageBusiness(business, userID) {
+		return nil, utils.NewForbiddenError("Only the business owner has permission to do this", nil)
+	}
+	return business, nil
+}
+
+// CanEditPost reports whether userID authored the post or owns the business
+// it was published as. A failed business lookup denies, and a nil
+// AccessControl only admits the author.
+func (a *AccessControl) CanEditPost(ctx context.Context, post *models.Post, userID string) bool {
+	if post == nil || userID == "" {
+		return false
+	}
+	if post.UserID != nil && *post.UserID == userID {
+		return true
+	}
+	if a == nil || post.BusinessID == nil || *post.BusinessID == "" {
+		return false
+	}
+	business, err := a.businessRepo.GetByID(ctx, *post.BusinessID)
+	return err == nil && a.CanManageBusiness(business, userID)
+}
+
+// CanEditComment reports whether userID wrote the comment.
+func (a *AccessControl) CanEditComment(comment *models.PostComment, userID string) bool {
+	return comment != nil && userID != "" && comment.UserID == userID
+}
+
+// CanDeleteComment reports whether userID wrote the comment or may edit the
+// post it is on; owners moderate their own threads. post may be nil when it
+// couldn't be loaded, leaving only the author.
+func (a *AccessControl) CanDeleteComment(ctx context.Context, comment *models.PostComment, post *models.Post, userID string) bool {
+	if a.CanEditComment(comment, userID) {
+		return true
+	}
+	return comment != nil && post != nil && post.ID == comment.PostID && a.CanEditPost(ctx, post, userID)
+}
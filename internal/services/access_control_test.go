@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessControl_CanEditPost(t *testing.T) {
+	ctx := context.Background()
+	businessRepo := new(mocks.MockBusinessRepository)
+	businessRepo.On("GetByID", mock.Anything, "biz-1").
+		Return(&models.BusinessProfile{ID: "biz-1", UserID: "biz-owner"}, nil)
+	businessRepo.On("GetByID", mock.Anything, "biz-gone").Return(nil, errors.New("not found"))
+	access := NewAccessControl(businessRepo)
+
+	post := testutil.CreateTestPost("post-1", "author", models.PostTypeFeed)
+	assert.True(t, access.CanEditPost(ctx, post, "author"))
+	assert.False(t, access.CanEditPost(ctx, post, "stranger"))
+	assert.False(t, access.CanEditPost(ctx, post, ""))
+
+	businessID := "biz-1"
+	post.BusinessID = &businessID
+	assert.True(t, access.CanEditPost(ctx, post, "biz-owner"), "the business owner edits its posts")
+	assert.False(t, access.CanEditPost(ctx, post, "stranger"))
+
+	gone := "biz-gone"
+	post.BusinessID = &gone
+	assert.False(t, access.CanEditPost(ctx, post, "biz-owner"), "a failed lookup denies")
+
+	var authorsOnly *AccessControl
+	post.BusinessID = &businessID
+	assert.True(t, authorsOnly.CanEditPost(ctx, post, "author"))
+	assert.False(t, authorsOnly.CanEditPost(ctx, post, "biz-owner"))
+}
+
+func TestAccessControl_RequireBusinessOwner(t *testing.T) {
+	ctx := context.Background()
+	businessRepo := new(mocks.MockBusinessRepository)
+	businessRepo.On("GetByID", mock.Anything, "biz-1").
+		Return(&models.BusinessProfile{ID: "biz-1", UserID: "owner"}, nil)
+	businessRepo.On("GetByID", mock.Anything, "missing").Return(nil, errors.New("not found"))
+	access := NewAccessControl(businessRepo)
+
+	business, err := access.RequireBusinessOwner(ctx, "biz-1", "owner")
+	require.NoError(t, err)
+	assert.Equal(t, "biz-1", business.ID)
+
+	_, err = access.RequireBusinessOwner(ctx, "biz-1", "someone")
+	requireAppErrorCode(t, err, http.StatusForbidden)
+
+	_, err = access.RequireBusinessOwner(ctx, "missing", "owner")
+	requireAppErrorCode(t, err, http.StatusNotFound)
+}
+
+func TestAccessControl_CanDeleteComment(t *testing.T) {
+	ctx := context.Background()
+	access := NewAccessControl(new(mocks.MockBusinessRepository))
+	post := testutil.CreateTestPost("post-1", "author", models.PostTypeFeed)
+	comment := buildComment("c-1", "post-1", "commenter")
+
+	assert.True(t, access.CanDeleteComment(ctx, comment, post, "commenter"))
+	assert.True(t, access.CanDeleteComment(ctx, comment, post, "author"))
+	assert.False(t, access.CanDeleteComment(ctx, comment, post, "stranger"))
+	assert.False(t, access.CanDeleteComment(ctx, comment, nil, "author"), "unknown post leaves only the commenter")
+
+	other := testutil.CreateTestPost("post-2", "author", models.PostTypeFeed)
+	assert.False(t, access.CanDeleteComment(ctx, comment, other, "author"), "a different post's owner")
+}
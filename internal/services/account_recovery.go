@@ -0,0 +1,347 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/hamsaya/backend/pkg/bgtasks"
+	"go.uber.org/zap"
+)
+
+const (
+	// accountRecoveryTTL is how long a recovery code can be used by support.
+	accountRecoveryTTL = 72 * time.Hour
+	// maxAccountRecoveryAttempts wrong codes reject the recovery.
+	maxAccountRecoveryAttempts = 5
+	// recoveryCodeAlphabet leaves out look-alike characters (0/O, 1/I) so
+	// the code survives being read out over the phone.
+	recoveryCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	recoveryCodeLength   = 10
+)
+
+// WithAccountRecovery enables trusted contact recovery for users who lose
+// access to their email.
+func (s *AuthService) WithAccountRecovery(repo repositories.AccountRecoveryRepository) *AuthService {
+	s.recovery = repo
+	return s
+}
+
+func (s *AuthService) requireAccountRecovery() error {
+	if s.recovery == nil || s.notificationService == nil {
+		return utils.NewNotImplementedError("Account recovery is not available", nil)
+	}
+	return nil
+}
+
+// GetTrustedContact returns the user's trusted contact, or nil when none is
+// set.
+func (s *AuthService) GetTrustedContact(ctx context.Context, userID string) (*models.TrustedContactResponse, error) {
+	if err := s.requireAccountRecovery(); err != nil {
+		return nil, err
+	}
+	contactID, since, err := s.recovery.GetTrustedContact(ctx, userID)
+	if errors.Is(err, repositories.ErrTrustedContactNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to get trusted contact", err)
+	}
+
+	resp := &models.TrustedContactResponse{UserID: contactID, Since: since}
+	if profile, err := s.userRepo.GetProfileByUserID(ctx, contactID); err == nil && profile != nil {
+		resp.FullName = strings.TrimSpace(stringOrEmpty(profile.FirstName) + " " + stringOrEmpty(profile.LastName))
+		resp.Handle = profile.Handle
+		resp.Avatar = profile.Avatar
+	}
+	return resp, nil
+}
+
+// SetTrustedContact names another user as the one who receives recovery
+// codes for the account. The contact is told in the app.
+func (s *AuthService) SetTrustedContact(ctx context.Context, userID string, req *models.SetTrustedContactRequest) (*models.TrustedContactResponse, error) {
+	if err := s.requireAccountRecovery(); err != nil {
+		return nil, err
+	}
+	if req.ContactUserID == userID {
+		return nil, utils.NewBadRequestError("You can't be your own trusted contact", nil)
+	}
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, utils.NewNotFoundError("User not found", err)
+	}
+	if user.PasswordHash != nil && !s.passwordService.Verify(req.Password, *user.PasswordHash) {
+		return nil, utils.NewUnauthorizedError("Password is incorrect", nil)
+	}
+	if _, err := s.userRepo.GetByID(ctx, req.ContactUserID); err != nil {
+		return nil, utils.NewNotFoundError("Contact not found", err)
+	}
+
+	if err := s.recovery.SetTrustedContact(ctx, userID, req.ContactUserID); err != nil {
+		s.logger.Error("Failed to set trusted contact", zap.String("user_id", userID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to set trusted contact", err)
+	}
+
+	name := s.recipientName(ctx, user)
+	s.notifyAccount(req.ContactUserID, models.NotificationTypeTrustedContactAdded,
+		"You're a trusted contact",
+		name+" named you as their trusted contact. If they ever lose access to their email, Hamsaya will send you a code to pass on to them.",
+		map[string]interface{}{"user_id": userID})
+
+	s.logger.Info("Trusted contact set", zap.String("user_id", userID))
+	return s.GetTrustedContact(ctx, userID)
+}
+
+// RemoveTrustedContact removes the user's trusted contact.
+func (s *AuthService) RemoveTrustedContact(ctx context.Context, userID string) error {
+	if err := s.requireAccountRecovery(); err != nil {
+		return err
+	}
+	if err := s.recovery.DeleteTrustedContact(ctx, userID); err != nil {
+		return utils.NewInternalError("Failed to remove trusted contact", err)
+	}
+	return nil
+}
+
+// StartAccountRecovery opens a recovery for the account registered with
+// the email: a one-time code goes to its trusted contact in the app, and the
+// account owner is warned so they can cancel it. The outcome isn't revealed
+// to the caller, so this can't be used to find accounts or their contacts.
+func (s *AuthService) StartAccountRecovery(ctx context.Context, req *models.StartAccountRecoveryRequest, ip string) error {
+	if err := s.requireAccountRecovery(); err != nil {
+		return err
+	}
+	user, err := s.userRepo.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(req.Email)))
+	if err != nil || user == nil {
+		return nil
+	}
+	contactID, _, err := s.recovery.GetTrustedContact(ctx, user.ID)
+	if errors.Is(err, repositories.ErrTrustedContactNotFound) {
+		s.logger.Info("Account recovery requested without a trusted contact", zap.String("user_id", user.ID))
+		return nil
+	}
+	if err != nil {
+		return utils.NewInternalError("Failed to start account recovery", err)
+	}
+
+	code, err := newRecoveryCode()
+	if err != nil {
+		return utils.NewInternalError("Failed to start account recovery", err)
+	}
+	recovery := &models.AccountRecovery{
+		UserID:    user.ID,
+		ContactID: &contactID,
+		CodeHash:  hashToken(normalizeRecoveryCode(code)),
+		ExpiresAt: time.Now().Add(accountRecoveryTTL),
+	}
+	if ip != "" {
+		recovery.RequestedIP = &ip
+	}
+	if err := s.recovery.CreateRecovery(ctx, recovery); err != nil {
+		if errors.Is(err, repositories.ErrRecoveryInProgress) {
+			return nil
+		}
+		s.logger.Error("Failed to create account recovery", zap.String("user_id", user.ID), zap.Error(err))
+		return utils.NewInternalError("Failed to start account recovery", err)
+	}
+
+	// The code only goes out in this notification: if it can't be
+	// delivered the recovery is useless, so cancel it and let the user
+	// retry.
+	name := s.recipientName(ctx, user)
+	title := "Account recovery code"
+	msg := name + " is trying to recover their account. Only if you're sure it's them, give them this code in person or by phone: " +
+		code + ". Hamsaya staff will never ask you for it."
+	if _, err := s.notificationService.CreateNotification(ctx, &models.CreateNotificationRequest{
+		UserID:  contactID,
+		Type:    models.NotificationTypeAccountRecoveryCode,
+		Title:   &title,
+		Message: &msg,
+		Data:    map[string]interface{}{"user_id": user.ID, "recovery_id": recovery.ID},
+	}); err != nil {
+		_, _ = s.recovery.CancelPendingRecovery(ctx, user.ID)
+		s.logger.Error("Failed to send account recovery code", zap.String("user_id", user.ID), zap.Error(err))
+		return utils.NewInternalError("Failed to start account recovery", err)
+	}
+
+	s.notifyAccount(user.ID, models.NotificationTypeAccountRecoveryStarted,
+		"Account recovery started",
+		"Someone asked to recover your account through your trusted contact. If this wasn't you, cancel it in your security settings.",
+		map[string]interface{}{"recovery_id": recovery.ID})
+
+	s.logger.Info("Account recovery started", zap.String("user_id", user.ID), zap.String("recovery_id", recovery.ID))
+	return nil
+}
+
+// CancelAccountRecovery cancels the user's pending recovery.
+func (s *AuthService) CancelAccountRecovery(ctx context.Context, userID string) error {
+	if err := s.requireAccountRecovery(); err != nil {
+		return err
+	}
+	cancelled, err := s.recovery.CancelPendingRecovery(ctx, userID)
+	if err != nil {
+		return utils.NewInternalError("Failed to cancel account recovery", err)
+	}
+	if !cancelled {
+		return utils.NewNotFoundError("No account recovery in progress", nil)
+	}
+	s.logger.Info("Account recovery cancelled by owner", zap.String("user_id", userID))
+	return nil
+}
+
+// ListAccountRecoveries returns recovery requests for support, newest
+// first.
+func (s *AuthService) ListAccountRecoveries(ctx context.Context, status string, limit int) ([]*models.AccountRecovery, error) {
+	if err := s.requireAccountRecovery(); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	recoveries, err := s.recovery.ListRecoveries(ctx, status, limit)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to list account recoveries", err)
+	}
+	return recoveries, nil
+}
+
+// CompleteAccountRecovery is support's second step: with the code the user
+// got from their trusted contact, the account moves to the new address,
+// every session is revoked and a password reset code goes to the new
+// address.
+func (s *AuthService) CompleteAccountRecovery(ctx context.Context, id, adminID string, req *models.CompleteAccountRecoveryRequest) (*models.AccountRecovery, error) {
+	recovery, err := s.pendingAccountRecovery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashToken(normalizeRecoveryCode(req.Code))), []byte(recovery.CodeHash)) != 1 {
+		attempts, err := s.recovery.RecordFailedAttempt(ctx, id)
+		if err != nil {
+			s.logger.Warn("Failed to record account recovery attempt", zap.String("recovery_id", id), zap.Error(err))
+		}
+		if attempts >= maxAccountRecoveryAttempts {
+			_ = s.recovery.ResolveRecovery(ctx, id, models.AccountRecoveryRejected, &adminID, "Too many invalid codes", nil, nil)
+			return nil, utils.NewBadRequestError("Too many invalid codes; the recovery was rejected", nil)
+		}
+		return nil, utils.NewBadRequestError("Invalid recovery code", nil)
+	}
+
+	newEmail := strings.ToLower(strings.TrimSpace(req.NewEmail))
+	user, err := s.userRepo.GetByID(ctx, recovery.UserID)
+	if err != nil {
+		return nil, utils.NewNotFoundError("User not found", err)
+	}
+	oldEmail := user.Email
+	if newEmail != strings.ToLower(oldEmail) {
+		if existing, err := s.userRepo.GetByEmail(ctx, newEmail); err == nil && existing != nil {
+			return nil, utils.NewConflictError("Email is already in use", nil)
+		}
+	}
+	if err := s.setUserEmail(ctx, user, newEmail); err != nil {
+		return nil, err
+	}
+	if err := s.recovery.ResolveRecovery(ctx, id, models.AccountRecoveryCompleted, &adminID, req.Notes, &oldEmail, &newEmail); err != nil {
+		s.logger.Error("Failed to close completed account recovery", zap.String("recovery_id", id), zap.Error(err))
+	}
+	// Dropping any pending email change keeps an old request from moving
+	// the account back.
+	if err := s.tokenStorage.DeleteEmailChange(ctx, user.ID); err != nil {
+		s.logger.Warn("Failed to delete pending email change", zap.String("user_id", user.ID), zap.Error(err))
+	}
+	if err := s.ForgotPassword(ctx, &models.ForgotPasswordRequest{Email: newEmail}); err != nil {
+		s.logger.Warn("Failed to send password reset after account recovery", zap.String("user_id", user.ID), zap.Error(err))
+	}
+
+	s.logger.Info("Account recovered", zap.String("user_id", user.ID), zap.String("recovery_id", id), zap.String("admin_id", adminID))
+	return s.recovery.GetRecovery(ctx, id)
+}
+
+// RejectAccountRecovery closes a recovery support couldn't verify.
+func (s *AuthService) RejectAccountRecovery(ctx context.Context, id, adminID, notes string) error {
+	if notes == "" {
+		return utils.NewBadRequestError("Rejection requires notes", nil)
+	}
+	if _, err := s.pendingAccountRecovery(ctx, id); err != nil {
+		return err
+	}
+	if err := s.recovery.ResolveRecovery(ctx, id, models.AccountRecoveryRejected, &adminID, notes, nil, nil); err != nil {
+		if errors.Is(err, repositories.ErrRecoveryNotPending) {
+			return utils.NewBadRequestError("Recovery is no longer pending", err)
+		}
+		return utils.NewInternalError("Failed to reject account recovery", err)
+	}
+	return nil
+}
+
+// pendingAccountRecovery loads a recovery that can still be completed.
+func (s *AuthService) pendingAccountRecovery(ctx context.Context, id string) (*models.AccountRecovery, error) {
+	if err := s.requireAccountRecovery(); err != nil {
+		return nil, err
+	}
+	recovery, err := s.recovery.GetRecovery(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRecoveryNotFound) {
+			return nil, utils.NewNotFoundError("Account recovery not found", err)
+		}
+		return nil, utils.NewInternalError("Failed to get account recovery", err)
+	}
+	if recovery.Status != models.AccountRecoveryPending {
+		return nil, utils.NewBadRequestError("Recovery is already "+recovery.Status, nil)
+	}
+	if !recovery.ExpiresAt.After(time.Now()) {
+		return nil, utils.NewBadRequestError("Recovery has expired; the user must start a new one", nil)
+	}
+	return recovery, nil
+}
+
+// notifyAccount sends a best-effort account notification.
+func (s *AuthService) notifyAccount(userID string, kind models.NotificationType, title, msg string, data map[string]interface{}) {
+	if s.notificationService == nil {
+		return
+	}
+	bgtasks.Submit(func(ctxDetach context.Context) {
+		_, _ = s.notificationService.CreateNotification(ctxDetach, &models.CreateNotificationRequest{
+			UserID:  userID,
+			Type:    kind,
+			Title:   &title,
+			Message: &msg,
+			Data:    data,
+		})
+	})
+}
+
+// newRecoveryCode returns a random code formatted as XXXXX-XXXXX.
+func newRecoveryCode() (string, error) {
+	var b strings.Builder
+	size := big.NewInt(int64(len(recoveryCodeAlphabet)))
+	for i := 0; i < recoveryCodeLength; i++ {
+		if i == recoveryCodeLength/2 {
+			b.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, size)
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(recoveryCodeAlphabet[n.Int64()])
+	}
+	return b.String(), nil
+}
+
+// normalizeRecoveryCode uppercases a code and drops the separator and any
+// spaces, as it is stored.
+func normalizeRecoveryCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(code))
+}
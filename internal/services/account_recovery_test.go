@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var recoveryCodePattern = regexp.MustCompile(`[A-Z2-9]{5}-[A-Z2-9]{5}`)
+
+func newRecoveryAuthService(t *testing.T, userRepo *mocks.MockUserRepository, repo *mocks.MockAccountRecoveryRepository, notifRepo *mocks.MockNotificationRepository) *AuthService {
+	ts, _ := newTestTokenStorage(t)
+	settingsRepo := new(mocks.MockNotificationSettingsRepository)
+	settingsRepo.On("GetByProfileID", mock.Anything, mock.Anything).
+		Return([]*models.NotificationSetting{{Category: models.NotificationCategoryAccount, PushPref: false}}, nil)
+	svc := newTestAuthService(userRepo, ts).WithAccountRecovery(repo)
+	svc.SetNotificationService(NewNotificationService(notifRepo, settingsRepo, nil, nil, nil, nil, zap.NewNop()))
+	return svc
+}
+
+func TestNewRecoveryCode(t *testing.T) {
+	code, err := newRecoveryCode()
+	require.NoError(t, err)
+	assert.Regexp(t, `^[A-Z2-9]{5}-[A-Z2-9]{5}$`, code)
+	assert.Equal(t, normalizeRecoveryCode(code), normalizeRecoveryCode(" "+code[:5]+" "+code[6:]))
+	assert.Equal(t, "ABCDE23456", normalizeRecoveryCode("abcde-23456"))
+}
+
+func TestAuthService_SetTrustedContact(t *testing.T) {
+	svc := newRecoveryAuthService(t, new(mocks.MockUserRepository), new(mocks.MockAccountRecoveryRepository), new(mocks.MockNotificationRepository))
+	_, err := svc.SetTrustedContact(context.Background(), "user-1", &models.SetTrustedContactRequest{ContactUserID: "user-1"})
+	requireAppErrCode(t, err, http.StatusBadRequest)
+}
+
+func TestAuthService_StartAccountRecovery(t *testing.T) {
+	ctx := context.Background()
+	req := &models.StartAccountRecoveryRequest{Email: " Ali@Example.com"}
+	user := testutil.CreateTestUser("user-1", "ali@example.com")
+
+	t.Run("unknown account reveals nothing", func(t *testing.T) {
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetByEmail", mock.Anything, "ali@example.com").Return(nil, errors.New("not found"))
+		repo := new(mocks.MockAccountRecoveryRepository)
+		svc := newRecoveryAuthService(t, userRepo, repo, new(mocks.MockNotificationRepository))
+
+		require.NoError(t, svc.StartAccountRecovery(ctx, req, "10.0.0.1"))
+		repo.AssertNotCalled(t, "CreateRecovery", mock.Anything, mock.Anything)
+	})
+
+	t.Run("no trusted contact reveals nothing", func(t *testing.T) {
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetByEmail", mock.Anything, "ali@example.com").Return(user, nil)
+		repo := new(mocks.MockAccountRecoveryRepository)
+		repo.On("GetTrustedContact", mock.Anything, "user-1").Return("", time.Time{}, repositories.ErrTrustedContactNotFound)
+		svc := newRecoveryAuthService(t, userRepo, repo, new(mocks.MockNotificationRepository))
+
+		require.NoError(t, svc.StartAccountRecovery(ctx, req, "10.0.0.1"))
+		repo.AssertNotCalled(t, "CreateRecovery", mock.Anything, mock.Anything)
+	})
+
+	t.Run("sends the code to the contact only", func(t *testing.T) {
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetByEmail", mock.Anything, "ali@example.com").Return(user, nil)
+		userRepo.On("GetProfileByUserID", mock.Anything, "user-1").Return(testutil.CreateTestProfile("user-1", "Ali", "Rahimi"), nil)
+		repo := new(mocks.MockAccountRecoveryRepository)
+		repo.On("GetTrustedContact", mock.Anything, "user-1").Return("contact-1", time.Now(), nil)
+		var stored *models.AccountRecovery
+		repo.On("CreateRecovery", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*models.AccountRecovery)
+			stored.ID = "rec-1"
+		}).Return(nil)
+
+		var sent *models.Notification
+		notifRepo := new(mocks.MockNotificationRepository)
+		notifRepo.On("Create", mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+			return n.Type == models.NotificationTypeAccountRecoveryCode
+		})).Run(func(args mock.Arguments) { sent = args.Get(1).(*models.Notification) }).Return(nil)
+		notifRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		svc := newRecoveryAuthService(t, userRepo, repo, notifRepo)
+
+		require.NoError(t, svc.StartAccountRecovery(ctx, req, "10.0.0.1"))
+		require.NotNil(t, stored)
+		assert.Equal(t, "contact-1", *stored.ContactID)
+		assert.Equal(t, "10.0.0.1", *stored.RequestedIP)
+		assert.WithinDuration(t, time.Now().Add(accountRecoveryTTL), stored.ExpiresAt, time.Minute)
+
+		require.NotNil(t, sent)
+		assert.Equal(t, "contact-1", sent.UserID)
+		code := recoveryCodePattern.FindString(*sent.Message)
+		require.NotEmpty(t, code)
+		assert.Equal(t, hashToken(normalizeRecoveryCode(code)), stored.CodeHash)
+	})
+
+	t.Run("recovery in progress is not restarted", func(t *testing.T) {
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetByEmail", mock.Anything, "ali@example.com").Return(user, nil)
+		repo := new(mocks.MockAccountRecoveryRepository)
+		repo.On("GetTrustedContact", mock.Anything, "user-1").Return("contact-1", time.Now(), nil)
+		repo.On("CreateRecovery", mock.Anything, mock.Anything).Return(repositories.ErrRecoveryInProgress)
+		notifRepo := new(mocks.MockNotificationRepository)
+		svc := newRecoveryAuthService(t, userRepo, repo, notifRepo)
+
+		require.NoError(t, svc.StartAccountRecovery(ctx, req, ""))
+		notifRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuthService_CompleteAccountRecovery(t *testing.T) {
+	ctx := context.Background()
+	pending := func() *models.AccountRecovery {
+		contact := "contact-1"
+		return &models.AccountRecovery{
+			ID: "rec-1", UserID: "user-1", ContactID: &contact, Status: models.AccountRecoveryPending,
+			CodeHash: hashToken("ABCDE23456"), ExpiresAt: time.Now().Add(time.Hour),
+		}
+	}
+	req := &models.CompleteAccountRecoveryRequest{Code: "abcde-23456", NewEmail: "New@Example.com", Notes: "Verified by phone"}
+
+	t.Run("wrong code counts an attempt", func(t *testing.T) {
+		repo := new(mocks.MockAccountRecoveryRepository)
+		repo.On("GetRecovery", mock.Anything, "rec-1").Return(pending(), nil)
+		repo.On("RecordFailedAttempt", mock.Anything, "rec-1").Return(1, nil)
+		svc := newRecoveryAuthService(t, new(mocks.MockUserRepository), repo, new(mocks.MockNotificationRepository))
+
+		_, err := svc.CompleteAccountRecovery(ctx, "rec-1", "admin-1", &models.CompleteAccountRecoveryRequest{Code: "ZZZZZ-ZZZZZ", NewEmail: "new@example.com", Notes: "x"})
+		requireAppErrCode(t, err, http.StatusBadRequest)
+		repo.AssertNotCalled(t, "ResolveRecovery", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("too many wrong codes reject it", func(t *testing.T) {
+		repo := new(mocks.MockAccountRecoveryRepository)
+		repo.On("GetRecovery", mock.Anything, "rec-1").Return(pending(), nil)
+		repo.On("RecordFailedAttempt", mock.Anything, "rec-1").Return(maxAccountRecoveryAttempts, nil)
+		repo.On("ResolveRecovery", mock.Anything, "rec-1", models.AccountRecoveryRejected, mock.Anything, mock.Anything, (*string)(nil), (*string)(nil)).Return(nil)
+		svc := newRecoveryAuthService(t, new(mocks.MockUserRepository), repo, new(mocks.MockNotificationRepository))
+
+		_, err := svc.CompleteAccountRecovery(ctx, "rec-1", "admin-1", &models.CompleteAccountRecoveryRequest{Code: "ZZZZZ-ZZZZZ", NewEmail: "new@example.com", Notes: "x"})
+		requireAppErrCode(t, err, http.StatusBadRequest)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		expired := pending()
+		expired.ExpiresAt = time.Now().Add(-time.Minute)
+		repo := new(mocks.MockAccountRecoveryRepository)
+		repo.On("GetRecovery", mock.Anything, "rec-1").Return(expired, nil)
+		svc := newRecoveryAuthService(t, new(mocks.MockUserRepository), repo, new(mocks.MockNotificationRepository))
+
+		_, err := svc.CompleteAccountRecovery(ctx, "rec-1", "admin-1", req)
+		requireAppErrCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("moves the account to the new address", func(t *testing.T) {
+		repo := new(mocks.MockAccountRecoveryRepository)
+		repo.On("GetRecovery", mock.Anything, "rec-1").Return(pending(), nil)
+		repo.On("ResolveRecovery", mock.Anything, "rec-1", models.AccountRecoveryCompleted,
+			mock.MatchedBy(func(id *string) bool { return id != nil && *id == "admin-1" }), "Verified by phone",
+			mock.MatchedBy(func(e *string) bool { return e != nil && *e == "old@example.com" }),
+			mock.MatchedBy(func(e *string) bool { return e != nil && *e == "new@example.com" }),
+		).Return(nil)
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetByID", mock.Anything, "user-1").Return(testutil.CreateTestUser("user-1", "old@example.com"), nil)
+		userRepo.On("GetByEmail", mock.Anything, "new@example.com").Return(nil, errors.New("not found"))
+		userRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *models.User) bool {
+			return u.Email == "new@example.com" && u.EmailVerified
+		})).Return(nil)
+		userRepo.On("RevokeAllUserSessions", mock.Anything, "user-1").Return(nil)
+		svc := newRecoveryAuthService(t, userRepo, repo, new(mocks.MockNotificationRepository))
+
+		_, err := svc.CompleteAccountRecovery(ctx, "rec-1", "admin-1", req)
+		require.NoError(t, err)
+		userRepo.AssertExpectations(t)
+		repo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/bgtasks"
+	"github.com/hamsaya/backend/pkg/geocoding"
+	"go.uber.org/zap"
+)
+
+// addressBackfillTimeout bounds one background lookup + write. The public
+// Nominatim throttle can queue callers, so this is generous.
+const addressBackfillTimeout = 30 * time.Second
+
+// needsAddressBackfill reports whether a location write came without the
+// province text that feed and group filters key on.
+func needsAddressBackfill(province *string) bool {
+	return province == nil || *province == ""
+}
+
+// backfillAddress reverse-geocodes (lat, lng) off the request path and
+// hands the result to fill. Failures are logged and dropped: the address
+// is a nicety for filters, not something to fail a write over.
+func backfillAddress(geocoder geocoding.ReverseGeocoder, logger *zap.Logger, lat, lng float64,
+	fill func(ctx context.Context, addr *models.ResolvedAddress) error,
+) {
+	bgtasks.Submit(func(taskCtx context.Context) {
+		ctx, cancel := context.WithTimeout(taskCtx, addressBackfillTimeout)
+		defer cancel()
+
+		rev, err := geocoder.Reverse(ctx, lat, lng)
+		if err != nil {
+			logger.Warn("Reverse geocode failed", zap.Float64("lat", lat), zap.Float64("lng", lng), zap.Error(err))
+			return
+		}
+		if rev == nil {
+			return
+		}
+		addr := &models.ResolvedAddress{
+			Country:      rev.Country,
+			Province:     rev.Province,
+			District:     rev.District,
+			Neighborhood: rev.Neighborhood,
+		}
+		if err := fill(ctx, addr); err != nil {
+			logger.Warn("Failed to save reverse-geocoded address", zap.Error(err))
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/utils"
+)
+
+const (
+	defaultAdminSearchLimit = 20
+	maxAdminSearchLimit     = 100
+)
+
+// moderatorSearchScopes is what a moderator without a custom role may
+// search: the content and reports they already moderate.
+var moderatorSearchScopes = []string{models.PermPostsView, models.PermCommentsView, models.PermReportsView}
+
+// AdminSearch searches posts, comments, users, businesses and reported
+// chat messages in one query, hidden content included. types narrows the entities; empty means every
+// entity the admin may view. Asking for an entity outside the admin's
+// scopes is forbidden rather than silently dropped.
+func (s *SearchService) AdminSearch(ctx context.Context, admin *models.User, query string, types []models.AdminSearchEntity, limit, offset int) ([]*models.AdminSearchResult, int, []models.AdminSearchEntity, error) {
+	query = models.NormalizeSearchQuery(query)
+	if len([]rune(query)) < minSuggestPrefix {
+		return nil, 0, nil, utils.NewBadRequestError(fmt.Sprintf("Query must be at least %d characters", minSuggestPrefix), nil)
+	}
+	if limit <= 0 || limit > maxAdminSearchLimit {
+		limit = defaultAdminSearchLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	allowed, err := s.adminSearchScopes(ctx, admin)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	var entities []models.AdminSearchEntity
+	if len(types) == 0 {
+		for _, e := range models.AllAdminSearchEntities {
+			if allowed[e] {
+				entities = append(entities, e)
+			}
+		}
+		if len(entities) == 0 {
+			return nil, 0, nil, utils.NewForbiddenError("No search permissions", nil)
+		}
+	} else {
+		for _, e := range types {
+			if models.AdminSearchPermission(e) == "" {
+				return nil, 0, nil, utils.NewBadRequestError("Unknown search type: "+string(e), nil)
+			}
+			if !allowed[e] {
+				return nil, 0, nil, utils.NewForbiddenError("Not allowed to search "+string(e)+"s", nil)
+			}
+			entities = append(entities, e)
+		}
+	}
+
+	results, total, err := s.searchRepo.AdminSearch(ctx, &models.AdminSearchFilter{
+		Query:    query,
+		Entities: entities,
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		return nil, 0, nil, utils.NewInternalError("Failed to search", err)
+	}
+	return results, total, entities, nil
+}
+
+// adminSearchScopes resolves which entities admin may search. Admins see
+// everything; a moderator is limited to their custom role's permissions,
+// or to posts, comments and reported messages without one.
+func (s *SearchService) adminSearchScopes(ctx context.Context, admin *models.User) (map[models.AdminSearchEntity]bool, error) {
+	allowed := make(map[models.AdminSearchEntity]bool, len(models.AllAdminSearchEntities))
+	if admin == nil || !admin.IsAdminOrModerator() {
+		return allowed, nil
+	}
+	if admin.IsAdmin() {
+		for _, e := range models.AllAdminSearchEntities {
+			allowed[e] = true
+		}
+		return allowed, nil
+	}
+
+	perms := moderatorSearchScopes
+	if s.customRoleRepo != nil {
+		role, err := s.customRoleRepo.GetUserCustomRole(ctx, admin.ID)
+		if err != nil {
+			return nil, utils.NewInternalError("Failed to load admin permissions", err)
+		}
+		if role != nil {
+			perms = role.Permissions
+		}
+	}
+	granted := make(map[string]bool, len(perms))
+	for _, p := range perms {
+		granted[p] = true
+	}
+	for _, e := range models.AllAdminSearchEntities {
+		allowed[e] = granted[models.AdminSearchPermission(e)]
+	}
+	return allowed, nil
+}
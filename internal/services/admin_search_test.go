@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchService_AdminSearch(t *testing.T) {
+	admin := &models.User{ID: "admin-1", Role: models.RoleAdmin}
+	moderator := &models.User{ID: "mod-1", Role: models.RoleModerator}
+	entitiesOf := func(want ...models.AdminSearchEntity) interface{} {
+		return mock.MatchedBy(func(f *models.AdminSearchFilter) bool { return assert.ObjectsAreEqual(want, f.Entities) })
+	}
+
+	t.Run("admin searches every entity", func(t *testing.T) {
+		searchRepo := new(mocks.MockSearchRepository)
+		searchRepo.On("AdminSearch", mock.Anything, entitiesOf(models.AllAdminSearchEntities...)).
+			Return([]*models.AdminSearchResult{{Type: models.AdminSearchUser, ID: "u-1"}}, 1, nil)
+
+		got, total, types, err := newSuggestService(searchRepo).AdminSearch(context.Background(), admin, " Karimi ", nil, 0, 0)
+		require.NoError(t, err)
+		assert.Len(t, got, 1)
+		assert.Equal(t, 1, total)
+		assert.Equal(t, models.AllAdminSearchEntities, types)
+	})
+
+	t.Run("moderator without a custom role gets content and reported messages", func(t *testing.T) {
+		searchRepo := new(mocks.MockSearchRepository)
+		roles := new(mocks.MockCustomRoleRepository)
+		roles.On("GetUserCustomRole", mock.Anything, "mod-1").Return(nil, nil)
+		searchRepo.On("AdminSearch", mock.Anything, entitiesOf(models.AdminSearchPost, models.AdminSearchComment, models.AdminSearchMessage)).
+			Return([]*models.AdminSearchResult{}, 0, nil)
+
+		svc := newSuggestService(searchRepo).WithCustomRoles(roles)
+		_, _, _, err := svc.AdminSearch(context.Background(), moderator, "spam", nil, 20, 0)
+		require.NoError(t, err)
+		searchRepo.AssertExpectations(t)
+	})
+
+	t.Run("type outside the custom role is forbidden", func(t *testing.T) {
+		searchRepo := new(mocks.MockSearchRepository)
+		roles := new(mocks.MockCustomRoleRepository)
+		roles.On("GetUserCustomRole", mock.Anything, "mod-1").
+			Return(&models.CustomRole{Permissions: []string{models.PermUsersView}}, nil)
+
+		svc := newSuggestService(searchRepo).WithCustomRoles(roles)
+		_, _, _, err := svc.AdminSearch(context.Background(), moderator, "spam",
+			[]models.AdminSearchEntity{models.AdminSearchUser, models.AdminSearchPost}, 20, 0)
+		requireAppErrCode(t, err, http.StatusForbidden)
+		searchRepo.AssertNotCalled(t, "AdminSearch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("short query and unknown type are rejected", func(t *testing.T) {
+		svc := newSuggestService(new(mocks.MockSearchRepository))
+		_, _, _, err := svc.AdminSearch(context.Background(), admin, "a", nil, 20, 0)
+		requireAppErrCode(t, err, http.StatusBadRequest)
+		_, _, _, err = svc.AdminSearch(context.Background(), admin, "spam", []models.AdminSearchEntity{"group"}, 20, 0)
+		requireAppErrCode(t, err, http.StatusBadRequest)
+	})
+}
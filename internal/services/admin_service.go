@@ -4,7 +4,9 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/hamsaya/backend/internal/models"
@@ -12,6 +14,8 @@ import (
 	"github.com/hamsaya/backend/internal/utils"
 	"github.com/hamsaya/backend/pkg/database"
 	"github.com/hamsaya/backend/pkg/notification"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"go.uber.org/zap"
 )
 
@@ -21,6 +25,8 @@ type AdminService struct {
 	db                  *database.DB
 	fcmClient           *notification.FCMClient
 	notificationService *NotificationService
+	emailDelivery       *EmailDeliveryService
+	loginGuard          *LoginGuard
 	logger              *zap.Logger
 }
 
@@ -41,6 +47,13 @@ func NewAdminService(
 	}
 }
 
+// WithEmailDelivery attaches the email delivery log so user detail can show
+// suppression state and recent sends for support troubleshooting.
+func (s *AdminService) WithEmailDelivery(d *EmailDeliveryService) *AdminService {
+	s.emailDelivery = d
+	return s
+}
+
 // GetDashboardStats retrieves dashboard statistics
 func (s *AdminService) GetDashboardStats(ctx context.Context) (*models.DashboardStats, error) {
 	stats, err := s.adminRepo.GetDashboardStats(ctx)
@@ -213,15 +226,48 @@ func (s *AdminService) GetUserDetail(ctx context.Context, userID string) (*model
 		businessesVal[i] = *b
 	}
 
+	var delivery *models.AdminEmailDeliveryInfo
+	if s.emailDelivery != nil && user.Email != "" {
+		delivery, err = s.emailDelivery.GetDeliveryInfo(ctx, user.Email, 10)
+		if err != nil {
+			s.logger.Warn("Failed to get email delivery info", zap.String("user_id", userID), zap.Error(err))
+		}
+	}
+
 	return &models.AdminUserDetailResponse{
 		AdminUserResponse: *user,
 		Bio:               bio,
 		BusinessCount:     int64(len(businesses)),
 		RecentPosts:       postsVal,
 		Businesses:        businessesVal,
+		EmailDelivery:     delivery,
 	}, nil
 }
 
+// WithLoginGuard lets the locked-accounts view include accounts the login
+// guard is currently delaying, and lets unlocking clear them.
+func (s *AdminService) WithLoginGuard(g *LoginGuard) *AdminService {
+	s.loginGuard = g
+	return s
+}
+
+// ClearUserEmailSuppression removes the user's address from the suppression
+// list so transactional emails are attempted again. Returns the address
+// that was cleared for the audit log.
+func (s *AdminService) ClearUserEmailSuppression(ctx context.Context, userID string) (string, error) {
+	if s.emailDelivery == nil {
+		return "", utils.NewNotImplementedError("Email delivery tracking is not enabled", nil)
+	}
+	user, err := s.adminRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return "", utils.NewNotFoundError("User not found", err)
+	}
+	if err := s.emailDelivery.ClearSuppression(ctx, user.Email); err != nil {
+		return "", err
+	}
+	return user.Email, nil
+}
+
 // SuspendUser suspends a user for a specified number of days
 func (s *AdminService) SuspendUser(ctx context.Context, userID string, days int, reason string, adminID string) error {
 	until := time.Now().AddDate(0, 0, days)
@@ -670,6 +716,42 @@ func (s *AdminService) UpdateBusinessStatus(ctx context.Context, businessID, sta
 	return nil
 }
 
+// SetBusinessCategories recategorizes a business, replacing whatever its
+// owner picked. An empty list clears its categories. Returns the new
+// category names.
+func (s *AdminService) SetBusinessCategories(ctx context.Context, businessID string, categoryIDs []string, adminID string) ([]string, error) {
+	if _, err := s.adminRepo.GetBusinessByID(ctx, businessID); err != nil {
+		return nil, utils.NewNotFoundError("Business not found", err)
+	}
+	before, _ := s.adminRepo.GetBusinessCategories(ctx, businessID)
+
+	ids := make([]string, 0, len(categoryIDs))
+	seen := make(map[string]bool, len(categoryIDs))
+	for _, id := range categoryIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if err := s.adminRepo.SetBusinessCategories(ctx, businessID, ids); err != nil {
+		if errors.Is(err, repositories.ErrUnknownBusinessCategory) {
+			return nil, utils.NewBadRequestError("Unknown or inactive business category", err)
+		}
+		s.logger.Error("Failed to set business categories", zap.String("business_id", businessID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to update business categories", err)
+	}
+
+	after, _ := s.adminRepo.GetBusinessCategories(ctx, businessID)
+	s.writeAuditLog(ctx, adminID, "set_business_categories", "business", businessID,
+		map[string]interface{}{"before": before, "after": after}, "")
+	s.logger.Info("Business recategorized",
+		zap.String("business_id", businessID),
+		zap.String("admin_id", adminID),
+		zap.Strings("categories", after),
+	)
+	return after, nil
+}
+
 // DeleteBusiness soft deletes a business
 func (s *AdminService) DeleteBusiness(ctx context.Context, businessID, adminID string) error {
 	// Resolve business owner before deletion so we can notify them.
@@ -742,14 +824,37 @@ func (s *AdminService) ListPostReports(ctx context.Context, filter *models.Admin
 	}, nil
 }
 
-// GetPostReport returns a single post report by ID
-func (s *AdminService) GetPostReport(ctx context.Context, reportID string) (*models.AdminPostReportResponse, error) {
+// GetPostReport returns a post report with the post, its author and the
+// other reports on the post.
+func (s *AdminService) GetPostReport(ctx context.Context, reportID string) (*models.AdminPostReportDetail, error) {
 	report, err := s.adminRepo.GetPostReportByID(ctx, reportID)
 	if err != nil {
 		s.logger.Error("Failed to get post report", zap.String("report_id", reportID), zap.Error(err))
 		return nil, utils.NewNotFoundError("Post report not found", err)
 	}
-	return report, nil
+
+	detail := &models.AdminPostReportDetail{AdminPostReportResponse: *report, OtherReports: []*models.AdminPostReportResponse{}}
+	if report.PostID == "" {
+		return detail, nil
+	}
+	if post, err := s.adminRepo.GetPostByID(ctx, report.PostID); err == nil {
+		detail.Post = post
+	} else {
+		s.logger.Warn("Failed to load reported post", zap.String("post_id", report.PostID), zap.Error(err))
+	}
+	others, total, err := s.adminRepo.ListPostReports(ctx, &models.AdminReportFilter{PostID: report.PostID, Limit: reportDetailOtherLimit})
+	if err != nil {
+		s.logger.Warn("Failed to list other post reports", zap.String("post_id", report.PostID), zap.Error(err))
+	}
+	detail.OtherReports = withoutReport(others, func(r *models.AdminPostReportResponse) string { return r.ID }, reportID)
+	detail.AdminReportContext = s.reportContext(ctx, report.PostAuthorID, report.PostID, total,
+		append([]models.AdminReportAction{
+			{Action: "resolve_report", Method: http.MethodPut, Path: "/admin/reports/posts/" + reportID + "/status"},
+			{Action: "resolve_all_reports", Method: http.MethodPut, Path: "/admin/reports/grouped/posts/" + report.PostID + "/status"},
+			{Action: "hide_post", Method: http.MethodPut, Path: "/admin/posts/" + report.PostID + "/status"},
+			{Action: "delete_post", Method: http.MethodDelete, Path: "/admin/posts/" + report.PostID},
+		}, authorReportActions(report.PostAuthorID)...))
+	return detail, nil
 }
 
 // ListCommentReports lists comment reports with filtering and pagination
@@ -782,14 +887,36 @@ func (s *AdminService) ListCommentReports(ctx context.Context, filter *models.Ad
 	}, nil
 }
 
-// GetCommentReport returns a single comment report by ID
-func (s *AdminService) GetCommentReport(ctx context.Context, reportID string) (*models.AdminCommentReportResponse, error) {
+// GetCommentReport returns a comment report with the comment, its author
+// and the other reports on the comment.
+func (s *AdminService) GetCommentReport(ctx context.Context, reportID string) (*models.AdminCommentReportDetail, error) {
 	report, err := s.adminRepo.GetCommentReportByID(ctx, reportID)
 	if err != nil {
 		s.logger.Error("Failed to get comment report", zap.String("report_id", reportID), zap.Error(err))
 		return nil, utils.NewNotFoundError("Comment report not found", err)
 	}
-	return report, nil
+
+	detail := &models.AdminCommentReportDetail{AdminCommentReportResponse: *report, OtherReports: []*models.AdminCommentReportResponse{}}
+	if report.CommentID == "" {
+		return detail, nil
+	}
+	if comment, err := s.adminRepo.GetCommentByID(ctx, report.CommentID); err == nil {
+		detail.Comment = comment
+	} else {
+		s.logger.Warn("Failed to load reported comment", zap.String("comment_id", report.CommentID), zap.Error(err))
+	}
+	others, total, err := s.adminRepo.ListCommentReports(ctx, &models.AdminReportFilter{CommentID: report.CommentID, Limit: reportDetailOtherLimit})
+	if err != nil {
+		s.logger.Warn("Failed to list other comment reports", zap.String("comment_id", report.CommentID), zap.Error(err))
+	}
+	detail.OtherReports = withoutReport(others, func(r *models.AdminCommentReportResponse) string { return r.ID }, reportID)
+	detail.AdminReportContext = s.reportContext(ctx, report.CommentAuthorID, report.CommentID, total,
+		append([]models.AdminReportAction{
+			{Action: "resolve_report", Method: http.MethodPut, Path: "/admin/reports/comments/" + reportID + "/status"},
+			{Action: "resolve_all_reports", Method: http.MethodPut, Path: "/admin/reports/grouped/comments/" + report.CommentID + "/status"},
+			{Action: "delete_comment", Method: http.MethodDelete, Path: "/admin/comments/" + report.CommentID},
+		}, authorReportActions(report.CommentAuthorID)...))
+	return detail, nil
 }
 
 // ListUserReports lists user reports with filtering and pagination
@@ -822,14 +949,30 @@ func (s *AdminService) ListUserReports(ctx context.Context, filter *models.Admin
 	}, nil
 }
 
-// GetUserReport returns a single user report by ID
-func (s *AdminService) GetUserReport(ctx context.Context, reportID string) (*models.AdminUserReportResponse, error) {
+// GetUserReport returns a user report with the reported account and the
+// other reports against it.
+func (s *AdminService) GetUserReport(ctx context.Context, reportID string) (*models.AdminUserReportDetail, error) {
 	report, err := s.adminRepo.GetUserReportByID(ctx, reportID)
 	if err != nil {
 		s.logger.Error("Failed to get user report", zap.String("report_id", reportID), zap.Error(err))
 		return nil, utils.NewNotFoundError("User report not found", err)
 	}
-	return report, nil
+
+	detail := &models.AdminUserReportDetail{AdminUserReportResponse: *report, OtherReports: []*models.AdminUserReportResponse{}}
+	if report.ReportedUserID == "" {
+		return detail, nil
+	}
+	others, total, err := s.adminRepo.ListUserReports(ctx, &models.AdminReportFilter{UserID: report.ReportedUserID, Limit: reportDetailOtherLimit})
+	if err != nil {
+		s.logger.Warn("Failed to list other user reports", zap.String("user_id", report.ReportedUserID), zap.Error(err))
+	}
+	detail.OtherReports = withoutReport(others, func(r *models.AdminUserReportResponse) string { return r.ID }, reportID)
+	detail.AdminReportContext = s.reportContext(ctx, report.ReportedUserID, report.ReportedUserID, total,
+		append([]models.AdminReportAction{
+			{Action: "resolve_report", Method: http.MethodPut, Path: "/admin/reports/users/" + reportID + "/status"},
+			{Action: "resolve_all_reports", Method: http.MethodPut, Path: "/admin/reports/grouped/users/" + report.ReportedUserID + "/status"},
+		}, authorReportActions(report.ReportedUserID)...))
+	return detail, nil
 }
 
 // ListBusinessReports lists business reports with filtering and pagination
@@ -862,14 +1005,145 @@ func (s *AdminService) ListBusinessReports(ctx context.Context, filter *models.A
 	}, nil
 }
 
-// GetBusinessReport returns a single business report by ID
-func (s *AdminService) GetBusinessReport(ctx context.Context, reportID string) (*models.AdminBusinessReportResponse, error) {
+// GetBusinessReport returns a business report with the business, its owner
+// and the other reports on the business.
+func (s *AdminService) GetBusinessReport(ctx context.Context, reportID string) (*models.AdminBusinessReportDetail, error) {
 	report, err := s.adminRepo.GetBusinessReportByID(ctx, reportID)
 	if err != nil {
 		s.logger.Error("Failed to get business report", zap.String("report_id", reportID), zap.Error(err))
 		return nil, utils.NewNotFoundError("Business report not found", err)
 	}
-	return report, nil
+
+	detail := &models.AdminBusinessReportDetail{AdminBusinessReportResponse: *report, OtherReports: []*models.AdminBusinessReportResponse{}}
+	if report.BusinessID == "" {
+		return detail, nil
+	}
+	if business, err := s.adminRepo.GetBusinessByID(ctx, report.BusinessID); err == nil {
+		detail.Business = business
+	} else {
+		s.logger.Warn("Failed to load reported business", zap.String("business_id", report.BusinessID), zap.Error(err))
+	}
+	others, total, err := s.adminRepo.ListBusinessReports(ctx, &models.AdminReportFilter{BusinessID: report.BusinessID, Limit: reportDetailOtherLimit})
+	if err != nil {
+		s.logger.Warn("Failed to list other business reports", zap.String("business_id", report.BusinessID), zap.Error(err))
+	}
+	detail.OtherReports = withoutReport(others, func(r *models.AdminBusinessReportResponse) string { return r.ID }, reportID)
+	detail.AdminReportContext = s.reportContext(ctx, report.BusinessOwnerID, report.BusinessID, total,
+		append([]models.AdminReportAction{
+			{Action: "resolve_report", Method: http.MethodPut, Path: "/admin/reports/businesses/" + reportID + "/status"},
+			{Action: "resolve_all_reports", Method: http.MethodPut, Path: "/admin/reports/grouped/businesses/" + report.BusinessID + "/status"},
+			{Action: "update_business_status", Method: http.MethodPut, Path: "/admin/businesses/" + report.BusinessID + "/status"},
+			{Action: "delete_business", Method: http.MethodDelete, Path: "/admin/businesses/" + report.BusinessID, AdminOnly: true},
+		}, authorReportActions(report.BusinessOwnerID)...))
+	return detail, nil
+}
+
+// ListMessageReports lists chat message reports with filtering and pagination
+func (s *AdminService) ListMessageReports(ctx context.Context, filter *models.AdminReportFilter) (*models.PaginatedResponse, error) {
+	reports, total, err := s.adminRepo.ListMessageReports(ctx, filter)
+	if err != nil {
+		s.logger.Error("Failed to list message reports", zap.Error(err))
+		return nil, utils.NewInternalError("Failed to list message reports", err)
+	}
+
+	limit := 20
+	if filter.Limit > 0 {
+		limit = filter.Limit
+	}
+	page := 1
+	if filter.Page > 0 {
+		page = filter.Page
+	}
+	totalPages := int(total) / limit
+	if int(total)%limit > 0 {
+		totalPages++
+	}
+
+	return &models.PaginatedResponse{
+		Items:      reports,
+		TotalCount: total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetMessageReport returns a chat message report with its sender and the
+// other reports on the message.
+func (s *AdminService) GetMessageReport(ctx context.Context, reportID string) (*models.AdminMessageReportDetail, error) {
+	report, err := s.adminRepo.GetMessageReportByID(ctx, reportID)
+	if err != nil {
+		s.logger.Error("Failed to get message report", zap.String("report_id", reportID), zap.Error(err))
+		return nil, utils.NewNotFoundError("Message report not found", err)
+	}
+
+	detail := &models.AdminMessageReportDetail{AdminMessageReportResponse: *report, OtherReports: []*models.AdminMessageReportResponse{}}
+	if report.MessageID == "" {
+		return detail, nil
+	}
+	others, total, err := s.adminRepo.ListMessageReports(ctx, &models.AdminReportFilter{MessageID: report.MessageID, Limit: reportDetailOtherLimit})
+	if err != nil {
+		s.logger.Warn("Failed to list other message reports", zap.String("message_id", report.MessageID), zap.Error(err))
+	}
+	detail.OtherReports = withoutReport(others, func(r *models.AdminMessageReportResponse) string { return r.ID }, reportID)
+	detail.AdminReportContext = s.reportContext(ctx, report.SenderID, report.MessageID, total,
+		append([]models.AdminReportAction{
+			{Action: "resolve_report", Method: http.MethodPut, Path: "/admin/reports/messages/" + reportID + "/status"},
+			{Action: "resolve_all_reports", Method: http.MethodPut, Path: "/admin/reports/grouped/messages/" + report.MessageID + "/status"},
+		}, authorReportActions(report.SenderID)...))
+	return detail, nil
+}
+
+// reportDetailOtherLimit caps the other reports listed on a report detail;
+// OtherReportCount still counts them all.
+const reportDetailOtherLimit = 20
+
+// reportContext loads the author and their prior violations for a report
+// detail. total is how many reports the target has, the viewed one included.
+// Lookup failures only leave fields empty: the report itself is what the
+// moderator asked for.
+func (s *AdminService) reportContext(ctx context.Context, authorID, targetID string, total int64, actions []models.AdminReportAction) models.AdminReportContext {
+	out := models.AdminReportContext{Actions: actions}
+	if total > 0 {
+		out.OtherReportCount = total - 1
+	}
+	if authorID == "" {
+		return out
+	}
+	if author, err := s.adminRepo.GetUserByID(ctx, authorID); err == nil {
+		out.Author = author
+	} else {
+		s.logger.Warn("Failed to load reported author", zap.String("user_id", authorID), zap.Error(err))
+	}
+	if n, err := s.adminRepo.CountPriorViolations(ctx, authorID, targetID); err == nil {
+		out.PriorViolations = n
+	} else {
+		s.logger.Warn("Failed to count prior violations", zap.String("user_id", authorID), zap.Error(err))
+	}
+	return out
+}
+
+// authorReportActions are the account actions offered on every report.
+func authorReportActions(authorID string) []models.AdminReportAction {
+	if authorID == "" {
+		return nil
+	}
+	return []models.AdminReportAction{
+		{Action: "view_author", Method: http.MethodGet, Path: "/admin/users/" + authorID},
+		{Action: "suspend_author", Method: http.MethodPost, Path: "/admin/users/" + authorID + "/suspend", AdminOnly: true},
+		{Action: "shadowban_author", Method: http.MethodPost, Path: "/admin/users/" + authorID + "/shadowban", AdminOnly: true},
+	}
+}
+
+// withoutReport drops the viewed report from its target's report list.
+func withoutReport[T any](reports []*T, id func(*T) string, reportID string) []*T {
+	out := make([]*T, 0, len(reports))
+	for _, r := range reports {
+		if id(r) != reportID {
+			out = append(out, r)
+		}
+	}
+	return out
 }
 
 // UpdateReportStatus updates a report's status based on type
@@ -886,6 +1160,8 @@ func (s *AdminService) UpdateReportStatus(ctx context.Context, reportType, repor
 		err = s.adminRepo.UpdateUserReportResolved(ctx, reportID, resolved)
 	case "businesses":
 		err = s.adminRepo.UpdateBusinessReportStatus(ctx, reportID, status)
+	case "messages":
+		err = s.adminRepo.UpdateMessageReportStatus(ctx, reportID, status)
 	default:
 		return utils.NewBadRequestError("Invalid report type", nil)
 	}
@@ -909,6 +1185,68 @@ func (s *AdminService) UpdateReportStatus(ctx context.Context, reportType, repor
 	return nil
 }
 
+// reportGroupTypes are the report_type path values that support grouping.
+var reportGroupTypes = map[string]bool{"posts": true, "comments": true, "users": true, "businesses": true, "messages": true}
+
+// ListReportGroups lists reported items with their reports collapsed into
+// one row each: reporter count, open count and reasons breakdown.
+func (s *AdminService) ListReportGroups(ctx context.Context, reportType string, filter *models.AdminReportFilter) (*models.PaginatedResponse, error) {
+	if !reportGroupTypes[reportType] {
+		return nil, utils.NewBadRequestError("Invalid report type", nil)
+	}
+	groups, total, err := s.adminRepo.ListReportGroups(ctx, reportType, filter)
+	if err != nil {
+		s.logger.Error("Failed to list report groups", zap.String("report_type", reportType), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to list reports", err)
+	}
+
+	limit := 20
+	if filter.Limit > 0 && filter.Limit <= 100 {
+		limit = filter.Limit
+	}
+	page := 1
+	if filter.Page > 0 {
+		page = filter.Page
+	}
+	totalPages := int(total) / limit
+	if int(total)%limit > 0 {
+		totalPages++
+	}
+
+	return &models.PaginatedResponse{
+		Items:      groups,
+		TotalCount: total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// ResolveReportGroup closes every open report on one item with status
+// (RESOLVED or REJECTED) and returns how many were closed.
+func (s *AdminService) ResolveReportGroup(ctx context.Context, reportType, targetID, status, adminID string) (int64, error) {
+	if !reportGroupTypes[reportType] {
+		return 0, utils.NewBadRequestError("Invalid report type", nil)
+	}
+	if status != string(models.ReportStatusResolved) && status != string(models.ReportStatusRejected) {
+		return 0, utils.NewBadRequestError("Status must be RESOLVED or REJECTED", nil)
+	}
+
+	closed, err := s.adminRepo.ResolveReportGroup(ctx, reportType, targetID, status)
+	if err != nil {
+		s.logger.Error("Failed to resolve report group",
+			zap.String("report_type", reportType),
+			zap.String("target_id", targetID),
+			zap.Error(err),
+		)
+		return 0, utils.NewInternalError("Failed to resolve reports", err)
+	}
+
+	s.writeAuditLog(ctx, adminID, "resolve_report_group", "report", targetID,
+		map[string]interface{}{"type": reportType, "status": status, "closed": closed}, "")
+	return closed, nil
+}
+
 // BroadcastNotification sends a notification to multiple users, persisting each
 // notification and delivering via push/WebSocket through NotificationService.
 func (s *AdminService) BroadcastNotification(ctx context.Context, req *models.BroadcastNotificationRequest, adminID string) error {
@@ -1177,28 +1515,117 @@ func (s *AdminService) DeleteRateLimitOverride(ctx context.Context, userID strin
 	return err
 }
 
+// LoginLockedUser is a user locked out by failed logins (not an admin
+// suspension) for the admin locked-accounts view.
+type LoginLockedUser struct {
+	UserID              string    `json:"user_id"`
+	Email               string    `json:"email"`
+	FailedLoginAttempts int       `json:"failed_login_attempts"`
+	LockedUntil         time.Time `json:"locked_until"`
+}
+
+// LockedAccounts lists who can't log in right now: users the database has
+// locked after MaxLoginAttempts failures, and accounts the login guard is
+// delaying or asking for a CAPTCHA.
+type LockedAccounts struct {
+	Locked    []LoginLockedUser `json:"locked"`
+	Throttled []LoginThrottle   `json:"throttled"`
+}
+
+// ListLockedAccounts returns the accounts currently locked or throttled by
+// failed logins.
+func (s *AdminService) ListLockedAccounts(ctx context.Context) (*LockedAccounts, error) {
+	rows, err := s.db.Pool.Query(ctx, `
+		SELECT id::text, email, failed_login_attempts, locked_until
+		FROM users
+		WHERE deleted_at IS NULL
+		  AND locked_until > NOW()
+		  AND failed_login_attempts >= $1
+		ORDER BY locked_until DESC
+		LIMIT 200
+	`, MaxLoginAttempts)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to list locked accounts", err)
+	}
+	defer rows.Close()
+	out := &LockedAccounts{Locked: make([]LoginLockedUser, 0)}
+	for rows.Next() {
+		var u LoginLockedUser
+		if err := rows.Scan(&u.UserID, &u.Email, &u.FailedLoginAttempts, &u.LockedUntil); err != nil {
+			return nil, utils.NewInternalError("Failed to list locked accounts", err)
+		}
+		out.Locked = append(out.Locked, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, utils.NewInternalError("Failed to list locked accounts", err)
+	}
+
+	out.Throttled, err = s.loginGuard.ListThrottled(ctx)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to list throttled logins", err)
+	}
+	return out, nil
+}
+
+// ClearLoginLock lets a user log in again after failed attempts: it resets
+// the failure counter, lifts a lock that came from failures, and clears the
+// login guard's delay. An admin suspension (locked_until set without
+// failures) is left in place. Returns the user's email for the audit log.
+func (s *AdminService) ClearLoginLock(ctx context.Context, userID string) (string, error) {
+	var email string
+	err := s.db.Pool.QueryRow(ctx, `
+		UPDATE users
+		SET locked_until = CASE WHEN failed_login_attempts >= $2 THEN NULL ELSE locked_until END,
+		    failed_login_attempts = 0,
+		    updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING email
+	`, userID, MaxLoginAttempts).Scan(&email)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", utils.NewNotFoundError("User not found", err)
+	}
+	if err != nil {
+		return "", utils.NewInternalError("Failed to clear login lock", err)
+	}
+	if err := s.loginGuard.Clear(ctx, email); err != nil {
+		return "", utils.NewInternalError("Failed to clear login throttle", err)
+	}
+	return email, nil
+}
+
 // SetShadowban flips shadowban state on a user. enabled=true sets
 // shadowbanned_at=NOW() + reason; enabled=false clears all three columns.
-// Audit logging is the caller's responsibility.
+// Re-enabling keeps the original shadowbanned_at so appeal timelines stay
+// accurate. Audit logging is the caller's responsibility.
+//
+// See docs/MODERATION.md for what a shadowbanned user can and can't see.
 func (s *AdminService) SetShadowban(ctx context.Context, userID string, enabled bool, adminID, reason string) error {
+	var tag pgconn.CommandTag
+	var err error
 	if enabled {
-		_, err := s.db.Pool.Exec(ctx, `
+		tag, err = s.db.Pool.Exec(ctx, `
 			UPDATE users
-			SET shadowbanned_at  = NOW(),
+			SET shadowbanned_at  = COALESCE(shadowbanned_at, NOW()),
 			    shadowbanned_by  = $1,
 			    shadowban_reason = NULLIF($2,'')
-			WHERE id = $3
+			WHERE id = $3 AND deleted_at IS NULL
 		`, adminID, reason, userID)
-		return err
+	} else {
+		tag, err = s.db.Pool.Exec(ctx, `
+			UPDATE users
+			SET shadowbanned_at  = NULL,
+			    shadowbanned_by  = NULL,
+			    shadowban_reason = NULL
+			WHERE id = $1 AND deleted_at IS NULL
+		`, userID)
 	}
-	_, err := s.db.Pool.Exec(ctx, `
-		UPDATE users
-		SET shadowbanned_at  = NULL,
-		    shadowbanned_by  = NULL,
-		    shadowban_reason = NULL
-		WHERE id = $1
-	`, userID)
-	return err
+	if err != nil {
+		return utils.NewInternalError("Failed to update shadowban", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return utils.NewNotFoundError("User not found", nil)
+	}
+	return nil
 }
 
 // SetUserVerification flips email_verified / phone_verified flags. Skips
@@ -3,16 +3,19 @@ package services
 import (
 	"context"
 	"errors"
+	"net/http"
 	"testing"
 	"time"
 
 	"github.com/hamsaya/backend/internal/mocks"
 	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
 	"github.com/hamsaya/backend/internal/testutil"
 	"github.com/hamsaya/backend/internal/utils"
 	"github.com/hamsaya/backend/pkg/notification"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
@@ -684,6 +687,60 @@ func TestAdminService_UpdateReportStatus(t *testing.T) {
 	}
 }
 
+func TestAdminService_ResolveReportGroup(t *testing.T) {
+	tests := []struct {
+		name          string
+		reportType    string
+		status        string
+		setupMocks    func(*mocks.MockAdminRepository)
+		expectedError string
+	}{
+		{
+			name:          "invalid report type",
+			reportType:    "unknown",
+			status:        "RESOLVED",
+			setupMocks:    func(r *mocks.MockAdminRepository) {},
+			expectedError: "Invalid report type",
+		},
+		{
+			name:          "status must close the reports",
+			reportType:    "posts",
+			status:        "REVIEWING",
+			setupMocks:    func(r *mocks.MockAdminRepository) {},
+			expectedError: "RESOLVED or REJECTED",
+		},
+		{
+			name:       "closes every open report on the post",
+			reportType: "posts",
+			status:     "REJECTED",
+			setupMocks: func(r *mocks.MockAdminRepository) {
+				r.On("ResolveReportGroup", mock.Anything, "posts", "post-1", "REJECTED").Return(int64(4), nil)
+				r.On("CreateAuditLog", mock.Anything, mock.AnythingOfType("*models.CreateAuditLogRequest")).
+					Return(nil)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			adminRepo := &mocks.MockAdminRepository{}
+			tc.setupMocks(adminRepo)
+
+			svc := newTestAdminService(adminRepo)
+			closed, err := svc.ResolveReportGroup(context.Background(), tc.reportType, "post-1", tc.status, "admin-1")
+
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, appErrMessage(err), tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(4), closed)
+			}
+			adminRepo.AssertExpectations(t)
+		})
+	}
+}
+
 // ---------------------------------------------------------------------------
 // ListAuditLogs
 // ---------------------------------------------------------------------------
@@ -1470,6 +1527,48 @@ func TestAdminService_UpdateBusinessStatus(t *testing.T) {
 	})
 }
 
+func TestAdminService_SetBusinessCategories(t *testing.T) {
+	ctx := context.Background()
+	business := &models.AdminBusinessDetailResponse{ID: "b-1"}
+
+	t.Run("unknown business", func(t *testing.T) {
+		adminRepo := &mocks.MockAdminRepository{}
+		adminRepo.On("GetBusinessByID", mock.Anything, "b-9").Return(nil, errors.New("no rows"))
+		svc := newTestAdminService(adminRepo)
+		_, err := svc.SetBusinessCategories(ctx, "b-9", []string{"c-1"}, "admin-1")
+		requireAppErrorCode(t, err, http.StatusNotFound)
+		adminRepo.AssertNotCalled(t, "SetBusinessCategories", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown category", func(t *testing.T) {
+		adminRepo := &mocks.MockAdminRepository{}
+		adminRepo.On("GetBusinessByID", mock.Anything, "b-1").Return(business, nil)
+		adminRepo.On("GetBusinessCategories", mock.Anything, "b-1").Return([]string{"Bakery"}, nil)
+		adminRepo.On("SetBusinessCategories", mock.Anything, "b-1", []string{"c-1"}).
+			Return(repositories.ErrUnknownBusinessCategory)
+		svc := newTestAdminService(adminRepo)
+		_, err := svc.SetBusinessCategories(ctx, "b-1", []string{"c-1"}, "admin-1")
+		requireAppErrorCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("replaces and audits", func(t *testing.T) {
+		adminRepo := &mocks.MockAdminRepository{}
+		adminRepo.On("GetBusinessByID", mock.Anything, "b-1").Return(business, nil)
+		adminRepo.On("GetBusinessCategories", mock.Anything, "b-1").Return([]string{"Bakery"}, nil).Once()
+		adminRepo.On("SetBusinessCategories", mock.Anything, "b-1", []string{"c-1", "c-2"}).Return(nil)
+		adminRepo.On("GetBusinessCategories", mock.Anything, "b-1").Return([]string{"Cafe", "Restaurant"}, nil).Once()
+		adminRepo.On("CreateAuditLog", mock.Anything, mock.MatchedBy(func(r *models.CreateAuditLogRequest) bool {
+			return r.Action == "set_business_categories" && r.EntityID == "b-1"
+		})).Return(nil)
+		svc := newTestAdminService(adminRepo)
+
+		names, err := svc.SetBusinessCategories(ctx, "b-1", []string{"c-1", "c-2", "c-1"}, "admin-1")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Cafe", "Restaurant"}, names)
+		adminRepo.AssertExpectations(t)
+	})
+}
+
 func TestAdminService_AdminDeleteBusiness(t *testing.T) {
 	t.Run("repo error", func(t *testing.T) {
 		adminRepo := &mocks.MockAdminRepository{}
@@ -1533,6 +1632,63 @@ func TestAdminService_GetPostReport(t *testing.T) {
 	})
 }
 
+func TestAdminService_GetPostReportContext(t *testing.T) {
+	ctx := context.Background()
+	adminRepo := &mocks.MockAdminRepository{}
+	adminRepo.On("GetPostReportByID", ctx, "r-1").
+		Return(&models.AdminPostReportResponse{ID: "r-1", PostID: "p-1", PostAuthorID: "u-1", Reason: "spam"}, nil)
+	adminRepo.On("GetPostByID", ctx, "p-1").Return(&models.AdminPostDetailResponse{ID: "p-1", AuthorID: "u-1"}, nil)
+	adminRepo.On("ListPostReports", ctx, &models.AdminReportFilter{PostID: "p-1", Limit: reportDetailOtherLimit}).
+		Return([]*models.AdminPostReportResponse{{ID: "r-2"}, {ID: "r-1"}, {ID: "r-3"}}, int64(3), nil)
+	adminRepo.On("GetUserByID", ctx, "u-1").Return(&models.AdminUserResponse{ID: "u-1", Email: "author@example.com"}, nil)
+	adminRepo.On("CountPriorViolations", ctx, "u-1", "p-1").Return(int64(2), nil)
+	svc := newTestAdminService(adminRepo)
+
+	detail, err := svc.GetPostReport(ctx, "r-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, "spam", detail.Reason)
+	require.NotNil(t, detail.Post)
+	require.NotNil(t, detail.Author)
+	assert.Equal(t, "author@example.com", detail.Author.Email)
+	assert.Equal(t, int64(2), detail.PriorViolations)
+	assert.Equal(t, int64(2), detail.OtherReportCount)
+	require.Len(t, detail.OtherReports, 2)
+	assert.Equal(t, "r-2", detail.OtherReports[0].ID)
+	assert.Equal(t, "r-3", detail.OtherReports[1].ID)
+
+	actions := map[string]models.AdminReportAction{}
+	for _, a := range detail.Actions {
+		actions[a.Action] = a
+	}
+	assert.Equal(t, "/admin/reports/posts/r-1/status", actions["resolve_report"].Path)
+	assert.Equal(t, http.MethodDelete, actions["delete_post"].Method)
+	assert.True(t, actions["suspend_author"].AdminOnly)
+	assert.Equal(t, "/admin/users/u-1/suspend", actions["suspend_author"].Path)
+	adminRepo.AssertExpectations(t)
+}
+
+func TestAdminService_GetMessageReportContextDegrades(t *testing.T) {
+	ctx := context.Background()
+	adminRepo := &mocks.MockAdminRepository{}
+	adminRepo.On("GetMessageReportByID", ctx, "r-1").
+		Return(&models.AdminMessageReportResponse{ID: "r-1", MessageID: "m-1", SenderID: "u-9"}, nil)
+	adminRepo.On("ListMessageReports", ctx, mock.AnythingOfType("*models.AdminReportFilter")).
+		Return(nil, int64(0), errors.New("db down"))
+	adminRepo.On("GetUserByID", ctx, "u-9").Return(nil, errors.New("not found"))
+	adminRepo.On("CountPriorViolations", ctx, "u-9", "m-1").Return(int64(0), errors.New("db down"))
+	svc := newTestAdminService(adminRepo)
+
+	// Context lookups failing still returns the report itself.
+	detail, err := svc.GetMessageReport(ctx, "r-1")
+	require.NoError(t, err)
+	assert.Equal(t, "m-1", detail.MessageID)
+	assert.Nil(t, detail.Author)
+	assert.NotNil(t, detail.OtherReports)
+	assert.Empty(t, detail.OtherReports)
+	assert.NotEmpty(t, detail.Actions)
+}
+
 func TestAdminService_ListCommentReports(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		adminRepo := &mocks.MockAdminRepository{}
@@ -16,12 +16,16 @@ import (
 // keys Apple uses to sign Sign-In-with-Apple identity tokens.
 const appleJWKSURL = "https://appleid.apple.com/auth/keys"
 
-// appleJWKSCacheTTL is how long fetched keys stay in memory before a refresh
-// is forced. Apple rotates keys infrequently; 1 hour is a safe balance
+// googleJWKSURL serves the keys Google signs security event tokens with.
+const googleJWKSURL = "https://www.googleapis.com/oauth2/v3/certs"
+
+// jwksCacheTTL is how long fetched keys stay in memory before a refresh
+// is forced. Providers rotate keys infrequently; 1 hour is a safe balance
 // between freshness and avoiding the keys endpoint on every login.
-const appleJWKSCacheTTL = time.Hour
+const jwksCacheTTL = time.Hour
 
-// appleJWK is one entry from Apple's JWKS endpoint.
+// appleJWK is one entry from Apple's JWKS endpoint. Google's uses the same
+// shape.
 type appleJWK struct {
 	Kty string `json:"kty"`
 	Kid string `json:"kid"`
@@ -35,10 +39,10 @@ type appleJWKS struct {
 	Keys []appleJWK `json:"keys"`
 }
 
-// appleKeyCache fetches and caches Apple's public keys. Safe for concurrent
-// use; refreshes on demand once the TTL elapses. The URL is a field so tests
-// can point it at an httptest.Server instead of the real Apple endpoint.
-type appleKeyCache struct {
+// jwksKeyCache fetches and caches a provider's public keys. Safe for
+// concurrent use; refreshes on demand once the TTL elapses. The URL is a field
+// so tests can point it at an httptest.Server instead of the real endpoint.
+type jwksKeyCache struct {
 	mu        sync.RWMutex
 	keys      map[string]*rsa.PublicKey
 	fetchedAt time.Time
@@ -48,17 +52,26 @@ type appleKeyCache struct {
 }
 
 // newAppleKeyCache builds a cache pointed at the live Apple JWKS endpoint.
-func newAppleKeyCache() *appleKeyCache {
-	return &appleKeyCache{
+func newAppleKeyCache() *jwksKeyCache {
+	return &jwksKeyCache{
 		httpc: &http.Client{Timeout: 5 * time.Second},
 		url:   appleJWKSURL,
-		ttl:   appleJWKSCacheTTL,
+		ttl:   jwksCacheTTL,
+	}
+}
+
+// newGoogleKeyCache builds a cache pointed at Google's JWKS endpoint.
+func newGoogleKeyCache() *jwksKeyCache {
+	return &jwksKeyCache{
+		httpc: &http.Client{Timeout: 5 * time.Second},
+		url:   googleJWKSURL,
+		ttl:   jwksCacheTTL,
 	}
 }
 
 // publicKey returns the RSA public key for the given kid, refreshing the
-// cache from Apple if needed.
-func (c *appleKeyCache) publicKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+// cache from the provider if needed.
+func (c *jwksKeyCache) publicKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
 	c.mu.RLock()
 	if k, ok := c.keys[kid]; ok && time.Since(c.fetchedAt) < c.ttl {
 		c.mu.RUnlock()
@@ -74,28 +87,28 @@ func (c *appleKeyCache) publicKey(ctx context.Context, kid string) (*rsa.PublicK
 	defer c.mu.RUnlock()
 	k, ok := c.keys[kid]
 	if !ok {
-		return nil, fmt.Errorf("apple jwks: kid %q not found", kid)
+		return nil, fmt.Errorf("jwks: kid %q not found", kid)
 	}
 	return k, nil
 }
 
-func (c *appleKeyCache) refresh(ctx context.Context) error {
+func (c *jwksKeyCache) refresh(ctx context.Context) error {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
 	if err != nil {
-		return fmt.Errorf("apple jwks request: %w", err)
+		return fmt.Errorf("jwks request: %w", err)
 	}
 	resp, err := c.httpc.Do(req)
 	if err != nil {
-		return fmt.Errorf("apple jwks fetch: %w", err)
+		return fmt.Errorf("jwks fetch: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("apple jwks status %d", resp.StatusCode)
+		return fmt.Errorf("jwks status %d", resp.StatusCode)
 	}
 
 	var jwks appleJWKS
 	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
-		return fmt.Errorf("apple jwks decode: %w", err)
+		return fmt.Errorf("jwks decode: %w", err)
 	}
 
 	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
@@ -110,7 +123,7 @@ func (c *appleKeyCache) refresh(ctx context.Context) error {
 		keys[k.Kid] = pub
 	}
 	if len(keys) == 0 {
-		return fmt.Errorf("apple jwks: no usable keys")
+		return fmt.Errorf("jwks: no usable keys")
 	}
 
 	c.mu.Lock()
@@ -166,7 +166,7 @@ func TestAppleKeyCache_RefreshAndCache(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	cache := &appleKeyCache{
+	cache := &jwksKeyCache{
 		httpc: srv.Client(),
 		url:   srv.URL,
 		ttl:   time.Hour,
@@ -35,6 +35,11 @@ type AuthService struct {
 	tokenStorage        *TokenStorageService
 	mfaService          *MFAService
 	notificationService *NotificationService
+	loginGuard          *LoginGuard
+	invites             *InviteService
+	recovery            repositories.AccountRecoveryRepository // optional; nil = trusted contact recovery off
+	oauthRevocations    repositories.OAuthRevocationRepository // optional; nil = revocations aren't remembered
+	lifecycle           *LifecycleMessageService               // optional; nil = built-in welcome copy
 	logger              *zap.Logger
 	cfg                 *config.Config
 }
@@ -71,6 +76,35 @@ func (s *AuthService) SetNotificationService(n *NotificationService) {
 	s.notificationService = n
 }
 
+// WithLoginGuard adds per-account and per-IP progressive delays and CAPTCHA
+// flags to password logins.
+func (s *AuthService) WithLoginGuard(g *LoginGuard) *AuthService {
+	s.loginGuard = g
+	return s
+}
+
+// WithInvites attributes signups that carry an invite code to the inviter.
+func (s *AuthService) WithInvites(invites *InviteService) *AuthService {
+	s.invites = invites
+	return s
+}
+
+// WithLifecycleMessages sets the copy of the welcome notification.
+func (s *AuthService) WithLifecycleMessages(lifecycle *LifecycleMessageService) *AuthService {
+	s.lifecycle = lifecycle
+	return s
+}
+
+// invalidCredentials records a failed password login with the login guard
+// and returns the generic 401, carrying the guard's challenge (a delay
+// before the next attempt, a CAPTCHA) once one applies.
+func (s *AuthService) invalidCredentials(ctx context.Context, email string, ipAddress *string) error {
+	if challenge := s.loginGuard.RecordFailure(ctx, email, stringOrEmpty(ipAddress)); challenge != nil {
+		return utils.NewUnauthorizedError("Invalid email or password", challenge)
+	}
+	return utils.NewUnauthorizedError("Invalid email or password", nil)
+}
+
 // Register creates a complete user profile with firstname, lastname, and location
 // This endpoint requires email, password, firstname, lastname, latitude, and longitude
 func (s *AuthService) Register(ctx context.Context, req *models.RegisterRequest) (*models.AuthResponse, error) {
@@ -156,6 +190,7 @@ func (s *AuthService) Register(ctx context.Context, req *models.RegisterRequest)
 			zap.String("email", email),
 		)
 		observability.RecordUserCreated(ctx, "email")
+		s.invites.Attribute(ctx, userID, req.InviteCode)
 
 		// Welcome notification — best-effort; failures don't break registration.
 		s.sendWelcomeNotification(ctx, userID, req.FirstName)
@@ -212,6 +247,10 @@ func (s *AuthService) UnifiedAuth(ctx context.Context, req *models.UnifiedAuthRe
 	// Normalize email
 	email := strings.ToLower(strings.TrimSpace(req.Email))
 
+	if err := s.loginGuard.Check(ctx, email, stringOrEmpty(req.IPAddress)); err != nil {
+		return nil, err
+	}
+
 	// Check if user exists
 	existingUser, err := s.userRepo.GetByEmail(ctx, email)
 
@@ -239,8 +278,9 @@ func (s *AuthService) UnifiedAuth(ctx context.Context, req *models.UnifiedAuthRe
 				zap.Int("attempts", attempts),
 			)
 
-			return nil, utils.NewUnauthorizedError("Invalid email or password", nil)
+			return nil, s.invalidCredentials(ctx, email, req.IPAddress)
 		}
+		s.loginGuard.RecordSuccess(ctx, email)
 
 		// Password is correct. If the account is locked / suspended,
 		// surface a clear message so the mobile client can log the user
@@ -364,6 +404,7 @@ func (s *AuthService) UnifiedAuth(ctx context.Context, req *models.UnifiedAuthRe
 		zap.String("user_id", userID),
 		zap.String("email", email),
 	)
+	s.invites.Attribute(ctx, userID, req.InviteCode)
 
 	// Generate AAL1 token pair (basic authentication)
 	sessionID := uuid.New().String()
@@ -421,13 +462,32 @@ func (s *AuthService) UnifiedAuth(ctx context.Context, req *models.UnifiedAuthRe
 // Login authenticates a user and returns tokens
 // If user doesn't exist, it auto-registers them with email and password only
 func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest) (*models.AuthResponse, error) {
+	return s.login(ctx, req, false)
+}
+
+// AdminLogin is Login for the admin dashboard. Only existing admins and
+// moderators get in (no auto-registration or reactivation); with
+// Admin.RequireMFA their accounts must have MFA, so every dashboard
+// session is AAL2. The session is an admin session: see issueSession.
+func (s *AuthService) AdminLogin(ctx context.Context, req *models.LoginRequest) (*models.AuthResponse, error) {
+	return s.login(ctx, req, true)
+}
+
+func (s *AuthService) login(ctx context.Context, req *models.LoginRequest, admin bool) (*models.AuthResponse, error) {
 	// Normalize email
 	email := strings.ToLower(strings.TrimSpace(req.Email))
 
+	if err := s.loginGuard.Check(ctx, email, stringOrEmpty(req.IPAddress)); err != nil {
+		return nil, err
+	}
+
 	// Get user by email (active only)
 	user, err := s.userRepo.GetByEmail(ctx, email)
 
 	// USER NOT FOUND - Check if deactivated (soft-deleted) for reactivation
+	if err != nil && admin {
+		return nil, s.invalidCredentials(ctx, email, req.IPAddress)
+	}
 	if err != nil {
 		deletedUser, delErr := s.userRepo.GetByEmailIncludingDeleted(ctx, email)
 		if delErr == nil && deletedUser != nil && deletedUser.DeletedAt != nil {
@@ -442,7 +502,7 @@ func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest) (*mod
 				user.DeletedAt = nil
 				// Fall through to normal login flow below
 			} else {
-				return nil, utils.NewUnauthorizedError("Invalid email or password", nil)
+				return nil, s.invalidCredentials(ctx, email, req.IPAddress)
 			}
 		} else {
 			// Truly new user - auto-register
@@ -528,8 +588,9 @@ func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest) (*mod
 			zap.Int("attempts", attempts),
 		)
 
-		return nil, utils.NewUnauthorizedError("Invalid email or password", nil)
+		return nil, s.invalidCredentials(ctx, email, req.IPAddress)
 	}
+	s.loginGuard.RecordSuccess(ctx, email)
 
 	// Password is correct. Now surface suspension as a clear 403 so the
 	// mobile client can log the user out and route to a "contact
@@ -542,6 +603,18 @@ func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest) (*mod
 		return nil, utils.NewForbiddenError("Your account has been suspended", utils.ErrForbidden)
 	}
 
+	if admin {
+		if !user.IsAdminOrModerator() {
+			return nil, utils.NewForbiddenError("Admin privileges required", utils.ErrForbidden)
+		}
+		if !user.MFAEnabled && s.cfg.Admin.RequireMFA {
+			s.logger.Warn("Admin login refused: MFA not enabled", zap.String("user_id", user.ID))
+			return nil, utils.NewForbiddenError(
+				"Turn on two-factor authentication in the app before signing in to the dashboard",
+				utils.ErrMFARequired)
+		}
+	}
+
 	// Check if MFA is enabled
 	if user.MFAEnabled {
 		// Generate MFA challenge
@@ -565,11 +638,21 @@ func (s *AuthService) Login(ctx context.Context, req *models.LoginRequest) (*mod
 	}
 
 	// Generate AAL1 token pair (basic authentication, no MFA)
-	return s.generateAuthResponse(ctx, user, models.AAL1, req.DeviceInfo, req.IPAddress, req.UserAgent)
+	return s.issueSession(ctx, user, models.AAL1, admin, req.DeviceInfo, req.IPAddress, req.UserAgent)
 }
 
 // VerifyMFA verifies an MFA code and returns tokens
 func (s *AuthService) VerifyMFA(ctx context.Context, req *models.MFAVerifyChallengeRequest) (*models.AuthResponse, error) {
+	return s.verifyMFA(ctx, req, false)
+}
+
+// AdminVerifyMFA is VerifyMFA for the admin dashboard: the user must be an
+// admin or moderator, and the session opened is an admin session.
+func (s *AuthService) AdminVerifyMFA(ctx context.Context, req *models.MFAVerifyChallengeRequest) (*models.AuthResponse, error) {
+	return s.verifyMFA(ctx, req, true)
+}
+
+func (s *AuthService) verifyMFA(ctx context.Context, req *models.MFAVerifyChallengeRequest, admin bool) (*models.AuthResponse, error) {
 	// Get user ID from MFA challenge
 	userID, err := s.tokenStorage.GetUserIDFromMFAChallenge(ctx, req.ChallengeID)
 	if err != nil {
@@ -583,6 +666,9 @@ func (s *AuthService) VerifyMFA(ctx context.Context, req *models.MFAVerifyChalle
 		s.logger.Error("Failed to get user", zap.Error(err))
 		return nil, utils.NewInternalError("Failed to verify MFA", err)
 	}
+	if admin && !user.IsAdminOrModerator() {
+		return nil, utils.NewForbiddenError("Admin privileges required", utils.ErrForbidden)
+	}
 
 	// Verify TOTP code
 	valid, err := s.mfaService.VerifyTOTP(ctx, userID, req.Code)
@@ -606,7 +692,7 @@ func (s *AuthService) VerifyMFA(ctx context.Context, req *models.MFAVerifyChalle
 	}
 
 	// Generate AAL2 token pair (MFA verified)
-	response, err := s.generateAuthResponse(ctx, user, models.AAL2, nil, nil, nil)
+	response, err := s.issueSession(ctx, user, models.AAL2, admin, nil, nil, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -668,6 +754,18 @@ func (s *AuthService) VerifyMFAWithBackupCode(ctx context.Context, req *models.M
 //   - Genuinely revoked (logout / explicit kill): reject.
 //   - Expired: reject. Client should fall back to /auth/device/login.
 func (s *AuthService) RefreshToken(ctx context.Context, req *models.RefreshTokenRequest) (*models.TokenPair, error) {
+	return s.refreshToken(ctx, req, false)
+}
+
+// AdminRefreshToken rotates an admin-dashboard session. It follows the
+// RefreshToken rules, except that the new pair keeps the admin access-token
+// lifetime and the session keeps its original expiry, so a dashboard login
+// can't be kept alive past Admin.SessionDuration by refreshing.
+func (s *AuthService) AdminRefreshToken(ctx context.Context, req *models.RefreshTokenRequest) (*models.TokenPair, error) {
+	return s.refreshToken(ctx, req, true)
+}
+
+func (s *AuthService) refreshToken(ctx context.Context, req *models.RefreshTokenRequest, admin bool) (*models.TokenPair, error) {
 	refreshTokenHash := s.jwtService.HashToken(req.RefreshToken)
 
 	// Look up the row regardless of revoked state so we can distinguish
@@ -688,6 +786,15 @@ func (s *AuthService) RefreshToken(ctx context.Context, req *models.RefreshToken
 		return nil, utils.NewUnauthorizedError("Refresh token has expired", nil)
 	}
 
+	// App and dashboard sessions only refresh through their own endpoint.
+	if session.Admin != admin {
+		s.logger.Warn("Refresh token presented to the wrong endpoint",
+			zap.String("session_id", session.ID),
+			zap.Bool("admin_session", session.Admin),
+		)
+		return nil, utils.NewUnauthorizedError("Invalid refresh token", nil)
+	}
+
 	grace := s.cfg.JWT.RefreshGrace
 	if grace <= 0 {
 		grace = 60 * time.Second
@@ -702,7 +809,7 @@ func (s *AuthService) RefreshToken(ctx context.Context, req *models.RefreshToken
 			s.logger.Warn("Refresh attempted on logged-out session",
 				zap.String("session_id", session.ID),
 			)
-			return nil, utils.NewUnauthorizedError("Refresh token has been revoked", nil)
+			return nil, s.revokedSessionError(ctx, session.UserID)
 		}
 		withinGrace := session.RevokedAt != nil && time.Since(*session.RevokedAt) < grace
 		if !withinGrace {
@@ -752,14 +859,26 @@ func (s *AuthService) RefreshToken(ctx context.Context, req *models.RefreshToken
 		return nil, utils.NewInternalError("Failed to refresh token", err)
 	}
 
-	aal := models.AAL1
-	if user.MFAEnabled {
-		// Track AAL on session in a future change; refresh keeps AAL1 for now.
+	if admin && !user.IsAdminOrModerator() {
+		s.logger.Warn("Admin refresh by a user no longer admin", zap.String("user_id", user.ID))
+		return nil, utils.NewUnauthorizedError("Admin privileges required", nil)
+	}
+
+	// The new session keeps the assurance level the login reached.
+	aal := session.AAL
+	if aal < models.AAL1 {
 		aal = models.AAL1
 	}
 
 	newSessionID := uuid.New().String()
-	tokenPair, err := s.jwtService.GenerateTokenPair(user.ID, user.Email, aal, newSessionID)
+	var tokenPair *models.TokenPair
+	expiresAt := time.Now().Add(s.cfg.JWT.RefreshTokenDuration)
+	if admin {
+		tokenPair, err = s.jwtService.GenerateTokenPairWithTTL(user.ID, user.Email, aal, newSessionID, s.cfg.Admin.AccessTokenDuration)
+		expiresAt = session.ExpiresAt
+	} else {
+		tokenPair, err = s.jwtService.GenerateTokenPair(user.ID, user.Email, aal, newSessionID)
+	}
 	if err != nil {
 		s.logger.Error("Failed to generate tokens", zap.Error(err))
 		return nil, utils.NewInternalError("Failed to generate tokens", err)
@@ -782,10 +901,12 @@ func (s *AuthService) RefreshToken(ctx context.Context, req *models.RefreshToken
 		DeviceInfo:       session.DeviceInfo,
 		IPAddress:        session.IPAddress,
 		UserAgent:        session.UserAgent,
-		ExpiresAt:        now.Add(s.cfg.JWT.RefreshTokenDuration),
+		ExpiresAt:        expiresAt,
 		Revoked:          false,
 		CreatedAt:        now,
 		UpdatedAt:        now,
+		AAL:              aal,
+		Admin:            admin,
 	}
 
 	if err := s.userRepo.CreateSession(ctx, newSession); err != nil {
@@ -844,17 +965,33 @@ func (s *AuthService) Logout(ctx context.Context, sessionID, jti string, accessT
 	return nil
 }
 
-// LogoutAll revokes all sessions for a user
+// LogoutAll revokes all sessions for a user. Refresh tokens die with the
+// sessions; access tokens already handed out are cut off by a per-user
+// "invalid before" timestamp the auth middleware checks, rather than by
+// denylisting each one.
 func (s *AuthService) LogoutAll(ctx context.Context, userID string) error {
 	if err := s.userRepo.RevokeAllUserSessions(ctx, userID); err != nil {
 		s.logger.Error("Failed to revoke all sessions", zap.Error(err))
 		return utils.NewInternalError("Failed to logout from all devices", err)
 	}
+	s.invalidateAccessTokens(ctx, userID)
 
 	s.logger.Info("User logged out from all devices", zap.String("user_id", userID))
 	return nil
 }
 
+// invalidateAccessTokens cuts off every access token issued to the user so
+// far. Sessions are already revoked by the caller, so a failure here only
+// leaves tokens valid until they expire; it is logged, not returned.
+func (s *AuthService) invalidateAccessTokens(ctx context.Context, userID string) {
+	if s.tokenStorage == nil {
+		return
+	}
+	if err := s.tokenStorage.InvalidateUserTokens(ctx, userID, s.cfg.JWT.AccessTokenDuration); err != nil {
+		s.logger.Warn("Failed to invalidate access tokens", zap.String("user_id", userID), zap.Error(err))
+	}
+}
+
 // VerifyEmail verifies a user's email address
 func (s *AuthService) VerifyEmail(ctx context.Context, req *models.VerifyEmailRequest) error {
 	// Get user ID from verification token
@@ -1044,6 +1181,7 @@ func (s *AuthService) ResetPassword(ctx context.Context, req *models.ResetPasswo
 		s.logger.Error("Failed to revoke sessions", zap.Error(err))
 		// Continue anyway
 	}
+	s.invalidateAccessTokens(ctx, userID)
 
 	// Delete reset token
 	if err := s.tokenStorage.DeletePasswordResetToken(ctx, req.Token); err != nil {
@@ -1180,6 +1318,19 @@ func (s *AuthService) generateAuthResponse(
 	user *models.User,
 	aal int,
 	deviceInfo, ipAddress, userAgent *string,
+) (*models.AuthResponse, error) {
+	return s.issueSession(ctx, user, aal, false, deviceInfo, ipAddress, userAgent)
+}
+
+// issueSession opens a session and returns the login response. Admin
+// (dashboard) sessions get the shorter Admin.AccessTokenDuration tokens and
+// last Admin.SessionDuration.
+func (s *AuthService) issueSession(
+	ctx context.Context,
+	user *models.User,
+	aal int,
+	admin bool,
+	deviceInfo, ipAddress, userAgent *string,
 ) (*models.AuthResponse, error) {
 	// Get profile
 	profile, err := s.userRepo.GetProfileByUserID(ctx, user.ID)
@@ -1197,7 +1348,14 @@ func (s *AuthService) generateAuthResponse(
 
 	// Generate token pair
 	sessionID := uuid.New().String()
-	tokenPair, err := s.jwtService.GenerateTokenPair(user.ID, user.Email, aal, sessionID)
+	var tokenPair *models.TokenPair
+	sessionTTL := s.cfg.JWT.RefreshTokenDuration
+	if admin {
+		tokenPair, err = s.jwtService.GenerateTokenPairWithTTL(user.ID, user.Email, aal, sessionID, s.cfg.Admin.AccessTokenDuration)
+		sessionTTL = s.cfg.Admin.SessionDuration
+	} else {
+		tokenPair, err = s.jwtService.GenerateTokenPair(user.ID, user.Email, aal, sessionID)
+	}
 	if err != nil {
 		s.logger.Error("Failed to generate tokens", zap.Error(err))
 		return nil, utils.NewInternalError("Failed to generate tokens", err)
@@ -1214,10 +1372,12 @@ func (s *AuthService) generateAuthResponse(
 		DeviceInfo:       deviceInfo,
 		IPAddress:        ipAddress,
 		UserAgent:        userAgent,
-		ExpiresAt:        now.Add(s.cfg.JWT.RefreshTokenDuration),
+		ExpiresAt:        now.Add(sessionTTL),
 		Revoked:          false,
 		CreatedAt:        now,
 		UpdatedAt:        now,
+		AAL:              aal,
+		Admin:            admin,
 	}
 
 	if err := s.userRepo.CreateSession(ctx, session); err != nil {
@@ -1234,6 +1394,7 @@ func (s *AuthService) generateAuthResponse(
 	s.logger.Info("User logged in successfully",
 		zap.String("user_id", user.ID),
 		zap.Int("aal", aal),
+		zap.Bool("admin", admin),
 		zap.String("session_id", sessionID),
 	)
 
@@ -1359,10 +1520,9 @@ func (s *AuthService) sendWelcomeNotification(ctx context.Context, userID, first
 		return
 	}
 	bgtasks.Submit(func(ctxDetach context.Context) {
-		title := "Welcome to Hamsaya!"
-		msg := "Discover neighbors, businesses, and listings in your area."
-		if firstName != "" {
-			title = "Welcome, " + firstName + "!"
+		title, msg, ok := s.lifecycle.Render(ctxDetach, models.NotificationTypeWelcome, LifecycleVars{FirstName: firstName})
+		if !ok {
+			return
 		}
 		_, _ = s.notificationService.CreateNotification(ctxDetach, &models.CreateNotificationRequest{
 			UserID:  userID,
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"net/http"
 	"strings"
 	"testing"
 	"time"
@@ -12,6 +13,7 @@ import (
 	"github.com/hamsaya/backend/internal/mocks"
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/hamsaya/backend/internal/utils"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -541,6 +543,35 @@ func TestAuthService_LogoutAll(t *testing.T) {
 	}
 }
 
+func TestAuthService_LogoutAllCutsOffAccessTokens(t *testing.T) {
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	userRepo.On("RevokeAllUserSessions", ctx, "user-1").Return(nil)
+	ts, _ := newTestTokenStorage(t)
+	svc := newTestAuthService(userRepo, ts)
+
+	token, _, err := svc.jwtService.GenerateAccessToken("user-1", "a@example.com", 1, "session-1")
+	require.NoError(t, err)
+	claims, err := svc.jwtService.ValidateAccessToken(token)
+	require.NoError(t, err)
+
+	require.NoError(t, svc.LogoutAll(ctx, "user-1"))
+
+	revoked, err := ts.IsAccessTokenRevoked(ctx, claims.JTI, claims.UserID, claims.IssuedAtMillis())
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	// Signing straight back in, within the same second, yields a live token.
+	time.Sleep(2 * time.Millisecond)
+	token, _, err = svc.jwtService.GenerateAccessToken("user-1", "a@example.com", 1, "session-2")
+	require.NoError(t, err)
+	claims, err = svc.jwtService.ValidateAccessToken(token)
+	require.NoError(t, err)
+	revoked, err = ts.IsAccessTokenRevoked(ctx, claims.JTI, claims.UserID, claims.IssuedAtMillis())
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
+
 func TestAuthService_ChangePassword(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -993,6 +1024,148 @@ func TestAuthService_RefreshToken_GraceCacheHit(t *testing.T) {
 	userRepo.AssertNotCalled(t, "MarkSessionRotated", mock.Anything, mock.Anything, mock.Anything)
 }
 
+func TestAuthService_AdminLogin(t *testing.T) {
+	setup := func(t *testing.T, requireMFA bool, role models.UserRole, mfa bool) (*AuthService, *mocks.MockUserRepository) {
+		userRepo := new(mocks.MockUserRepository)
+		user := testutil.CreateTestUser("user-1", "admin@example.com")
+		user.PasswordHash = func() *string { s := testPasswordHash; return &s }()
+		user.Role = role
+		user.MFAEnabled = mfa
+		userRepo.On("GetByEmail", mock.Anything, "admin@example.com").Return(user, nil)
+		ts, _ := newTestTokenStorage(t)
+		svc := newTestAuthService(userRepo, ts)
+		svc.cfg.Admin = config.AdminConfig{
+			RequireMFA:          requireMFA,
+			AccessTokenDuration: 5 * time.Minute,
+			SessionDuration:     time.Hour,
+		}
+		return svc, userRepo
+	}
+	login := &models.LoginRequest{Email: "admin@example.com", Password: "password"}
+
+	t.Run("unknown email is not auto-registered", func(t *testing.T) {
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetByEmail", mock.Anything, "new@example.com").Return(nil, errors.New("not found"))
+		svc := newTestAuthService(userRepo, newFailingTokenStorage())
+
+		_, err := svc.AdminLogin(context.Background(), &models.LoginRequest{Email: "new@example.com", Password: "password"})
+		require.Error(t, err)
+		userRepo.AssertNotCalled(t, "GetByEmailIncludingDeleted", mock.Anything, mock.Anything)
+		userRepo.AssertNotCalled(t, "CreateUserWithProfile", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("regular user refused", func(t *testing.T) {
+		svc, userRepo := setup(t, true, models.RoleUser, true)
+		_, err := svc.AdminLogin(context.Background(), login)
+		requireAppErrorCode(t, err, http.StatusForbidden)
+		userRepo.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything)
+	})
+
+	t.Run("admin without MFA refused", func(t *testing.T) {
+		svc, userRepo := setup(t, true, models.RoleAdmin, false)
+		_, err := svc.AdminLogin(context.Background(), login)
+		requireAppErrorCode(t, err, http.StatusForbidden)
+		assert.Equal(t, utils.ErrMFARequired, err.(*utils.AppError).Err)
+		userRepo.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything)
+	})
+
+	t.Run("admin with MFA gets a challenge", func(t *testing.T) {
+		svc, _ := setup(t, true, models.RoleModerator, true)
+		resp, err := svc.AdminLogin(context.Background(), login)
+		require.NoError(t, err)
+		assert.True(t, resp.RequiresMFA)
+		assert.Nil(t, resp.Tokens)
+	})
+
+	t.Run("MFA not required opens a short admin session", func(t *testing.T) {
+		svc, userRepo := setup(t, false, models.RoleAdmin, false)
+		userRepo.On("GetProfileByUserID", mock.Anything, "user-1").Return(testutil.CreateTestProfile("user-1", "Ada", "Admin"), nil)
+		userRepo.On("CreateSession", mock.Anything, mock.MatchedBy(func(s *models.UserSession) bool {
+			return s.Admin && s.AAL == models.AAL1 && time.Until(s.ExpiresAt) <= time.Hour
+		})).Return(nil)
+		userRepo.On("UpdateLastLogin", mock.Anything, "user-1").Return(nil)
+
+		resp, err := svc.AdminLogin(context.Background(), login)
+		require.NoError(t, err)
+		require.NotNil(t, resp.Tokens)
+		assert.WithinDuration(t, time.Now().Add(5*time.Minute), resp.Tokens.ExpiresAt, 5*time.Second)
+		userRepo.AssertExpectations(t)
+	})
+}
+
+func TestAuthService_AdminRefreshToken(t *testing.T) {
+	cfg := getTestConfig()
+	jwtSvc := NewJWTService(&cfg.JWT)
+	refresh, err := jwtSvc.GenerateRefreshToken()
+	require.NoError(t, err)
+	familyID := "fam-1"
+	sessionEnd := time.Now().Add(40 * time.Minute)
+	adminSession := func() *models.UserSession {
+		return &models.UserSession{
+			ID:               "session-1",
+			UserID:           "user-1",
+			RefreshToken:     refresh,
+			RefreshTokenHash: jwtSvc.HashToken(refresh),
+			FamilyID:         &familyID,
+			ExpiresAt:        sessionEnd,
+			AAL:              models.AAL2,
+			Admin:            true,
+		}
+	}
+	newSvc := func(t *testing.T, userRepo *mocks.MockUserRepository) *AuthService {
+		ts, _ := newTestTokenStorage(t)
+		svc := newTestAuthService(userRepo, ts)
+		svc.cfg.Admin = config.AdminConfig{AccessTokenDuration: 5 * time.Minute, SessionDuration: time.Hour}
+		return svc
+	}
+
+	t.Run("admin session refused on the app endpoint", func(t *testing.T) {
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetSessionByRefreshTokenHashAny", mock.Anything, mock.Anything).Return(adminSession(), nil)
+		_, err := newSvc(t, userRepo).RefreshToken(context.Background(), &models.RefreshTokenRequest{RefreshToken: refresh})
+		requireAppErrorCode(t, err, http.StatusUnauthorized)
+		userRepo.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything)
+	})
+
+	t.Run("app session refused on the admin endpoint", func(t *testing.T) {
+		userRepo := new(mocks.MockUserRepository)
+		session := adminSession()
+		session.Admin = false
+		userRepo.On("GetSessionByRefreshTokenHashAny", mock.Anything, mock.Anything).Return(session, nil)
+		_, err := newSvc(t, userRepo).AdminRefreshToken(context.Background(), &models.RefreshTokenRequest{RefreshToken: refresh})
+		requireAppErrorCode(t, err, http.StatusUnauthorized)
+	})
+
+	t.Run("demoted admin refused", func(t *testing.T) {
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetSessionByRefreshTokenHashAny", mock.Anything, mock.Anything).Return(adminSession(), nil)
+		userRepo.On("GetByID", mock.Anything, "user-1").Return(testutil.CreateTestUser("user-1", "admin@example.com"), nil)
+		_, err := newSvc(t, userRepo).AdminRefreshToken(context.Background(), &models.RefreshTokenRequest{RefreshToken: refresh})
+		requireAppErrorCode(t, err, http.StatusUnauthorized)
+		userRepo.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything)
+	})
+
+	t.Run("keeps AAL2 and the original expiry", func(t *testing.T) {
+		userRepo := new(mocks.MockUserRepository)
+		admin := testutil.CreateTestUser("user-1", "admin@example.com")
+		admin.Role = models.RoleAdmin
+		userRepo.On("GetSessionByRefreshTokenHashAny", mock.Anything, mock.Anything).Return(adminSession(), nil)
+		userRepo.On("GetByID", mock.Anything, "user-1").Return(admin, nil)
+		userRepo.On("CreateSession", mock.Anything, mock.MatchedBy(func(s *models.UserSession) bool {
+			return s.Admin && s.AAL == models.AAL2 && s.ExpiresAt.Equal(sessionEnd)
+		})).Return(nil)
+		userRepo.On("MarkSessionRotated", mock.Anything, "session-1", mock.AnythingOfType("string")).Return(nil)
+
+		pair, err := newSvc(t, userRepo).AdminRefreshToken(context.Background(), &models.RefreshTokenRequest{RefreshToken: refresh})
+		require.NoError(t, err)
+		claims, err := jwtSvc.ValidateAccessToken(pair.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, models.AAL2, claims.AAL)
+		assert.WithinDuration(t, time.Now().Add(5*time.Minute), pair.ExpiresAt, 5*time.Second)
+		userRepo.AssertExpectations(t)
+	})
+}
+
 // TestAuthService_DeviceCredential exercises the register / login / revoke
 // flow for long-lived device credentials. Plaintext is returned exactly once
 // at registration; only the SHA-256 hash is persisted server-side.
@@ -13,6 +13,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/hamsaya/backend/config"
+	"github.com/hamsaya/backend/internal/utils"
 	"github.com/hamsaya/backend/pkg/database"
 	"github.com/jackc/pgx/v5"
 	"github.com/minio/minio-go/v7"
@@ -326,12 +327,12 @@ func (s *BackupService) OpenDownload(ctx context.Context, id string) (*DownloadS
 		`SELECT object_key, size_bytes FROM db_backups WHERE id=$1`, id,
 	).Scan(&key, &size); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("backup not found")
+			return nil, utils.NewNotFoundError("Backup not found", nil)
 		}
 		return nil, err
 	}
 	if key == nil || *key == "" {
-		return nil, fmt.Errorf("backup has no object_key (upload failed or not finished)")
+		return nil, utils.NewConflictError("Backup has no object_key (upload failed or not finished)", nil)
 	}
 	obj, err := s.minio.GetObject(ctx, s.cfg.Backup.Bucket, *key, minio.GetObjectOptions{})
 	if err != nil {
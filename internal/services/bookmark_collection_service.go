@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// maxBookmarkCollections caps folders per user; nobody needs more and it
+// keeps the picker usable.
+const maxBookmarkCollections = 50
+
+// BookmarkCollectionService manages a user's named bookmark folders.
+type BookmarkCollectionService struct {
+	collectionRepo repositories.BookmarkCollectionRepository
+	logger         *zap.Logger
+}
+
+// NewBookmarkCollectionService creates a new bookmark collection service.
+func NewBookmarkCollectionService(collectionRepo repositories.BookmarkCollectionRepository, logger *zap.Logger) *BookmarkCollectionService {
+	return &BookmarkCollectionService{
+		collectionRepo: collectionRepo,
+		logger:         logger,
+	}
+}
+
+func (s *BookmarkCollectionService) get(ctx context.Context, collectionID, userID string) (*models.BookmarkCollection, error) {
+	if _, err := uuid.Parse(collectionID); err != nil {
+		return nil, utils.NewNotFoundError("Collection not found", err)
+	}
+	c, err := s.collectionRepo.GetByID(ctx, collectionID, userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrBookmarkCollectionNotFound) {
+			return nil, utils.NewNotFoundError("Collection not found", err)
+		}
+		return nil, utils.NewInternalError("Failed to load collection", err)
+	}
+	return c, nil
+}
+
+// List returns the user's collections with bookmark counts.
+func (s *BookmarkCollectionService) List(ctx context.Context, userID string) (*models.BookmarkCollectionsResponse, error) {
+	out, err := s.collectionRepo.List(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to list bookmark collections", zap.String("user_id", userID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to retrieve collections", err)
+	}
+	return out, nil
+}
+
+// Create adds a collection for the user.
+func (s *BookmarkCollectionService) Create(ctx context.Context, userID string, req *models.BookmarkCollectionRequest) (*models.BookmarkCollection, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, utils.NewBadRequestError("Collection name is required", nil)
+	}
+
+	existing, err := s.collectionRepo.List(ctx, userID)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to create collection", err)
+	}
+	if len(existing.Collections) >= maxBookmarkCollections {
+		return nil, utils.NewBadRequestError("You have reached the maximum number of collections", nil)
+	}
+
+	c := &models.BookmarkCollection{ID: uuid.NewString(), UserID: userID, Name: name}
+	if err := s.collectionRepo.Create(ctx, c); err != nil {
+		if errors.Is(err, repositories.ErrBookmarkCollectionExists) {
+			return nil, utils.NewConflictError("You already have a collection with this name", err)
+		}
+		s.logger.Error("Failed to create bookmark collection", zap.String("user_id", userID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to create collection", err)
+	}
+	return c, nil
+}
+
+// Rename changes a collection's name.
+func (s *BookmarkCollectionService) Rename(ctx context.Context, collectionID, userID string, req *models.BookmarkCollectionRequest) (*models.BookmarkCollection, error) {
+	c, err := s.get(ctx, collectionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, utils.NewBadRequestError("Collection name is required", nil)
+	}
+	c.Name = name
+
+	if err := s.collectionRepo.Rename(ctx, c); err != nil {
+		if errors.Is(err, repositories.ErrBookmarkCollectionExists) {
+			return nil, utils.NewConflictError("You already have a collection with this name", err)
+		}
+		if errors.Is(err, repositories.ErrBookmarkCollectionNotFound) {
+			return nil, utils.NewNotFoundError("Collection not found", err)
+		}
+		return nil, utils.NewInternalError("Failed to rename collection", err)
+	}
+	return c, nil
+}
+
+// Delete removes a collection. Its bookmarks are kept, unfiled.
+func (s *BookmarkCollectionService) Delete(ctx context.Context, collectionID, userID string) error {
+	if _, err := uuid.Parse(collectionID); err != nil {
+		return utils.NewNotFoundError("Collection not found", err)
+	}
+	if err := s.collectionRepo.Delete(ctx, collectionID, userID); err != nil {
+		if errors.Is(err, repositories.ErrBookmarkCollectionNotFound) {
+			return utils.NewNotFoundError("Collection not found", err)
+		}
+		return utils.NewInternalError("Failed to delete collection", err)
+	}
+	return nil
+}
+
+// AssignBookmark files the user's bookmark of postID into a collection, or
+// unfiles it when collectionID is nil.
+func (s *BookmarkCollectionService) AssignBookmark(ctx context.Context, userID, postID string, collectionID *string) error {
+	if collectionID != nil && *collectionID == "" {
+		collectionID = nil
+	}
+	if collectionID != nil {
+		if _, err := s.get(ctx, *collectionID, userID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.collectionRepo.AssignBookmark(ctx, userID, postID, collectionID); err != nil {
+		if errors.Is(err, repositories.ErrBookmarkNotFound) {
+			return utils.NewNotFoundError("Bookmark not found", err)
+		}
+		s.logger.Error("Failed to assign bookmark", zap.String("post_id", postID), zap.Error(err))
+		return utils.NewInternalError("Failed to move bookmark", err)
+	}
+	return nil
+}
+
+// CheckOwned returns a not-found error unless collectionID is
+// models.BookmarkCollectionNone or one of the user's collections. Backs
+// the collection_id filter on the bookmarks list.
+func (s *BookmarkCollectionService) CheckOwned(ctx context.Context, collectionID, userID string) error {
+	if collectionID == models.BookmarkCollectionNone {
+		return nil
+	}
+	_, err := s.get(ctx, collectionID, userID)
+	return err
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const testCollectionID = "6f1c2d3e-4a5b-4c6d-8e7f-901234567890"
+
+func TestBookmarkCollectionService_Create(t *testing.T) {
+	t.Run("trims the name", func(t *testing.T) {
+		repo := new(mocks.MockBookmarkCollectionRepository)
+		repo.On("List", mock.Anything, "user-1").Return(&models.BookmarkCollectionsResponse{}, nil)
+		repo.On("Create", mock.Anything, mock.MatchedBy(func(c *models.BookmarkCollection) bool {
+			return c.UserID == "user-1" && c.Name == "Furniture ideas"
+		})).Return(nil)
+		svc := NewBookmarkCollectionService(repo, zap.NewNop())
+
+		got, err := svc.Create(context.Background(), "user-1", &models.BookmarkCollectionRequest{Name: "  Furniture ideas "})
+		require.NoError(t, err)
+		assert.Equal(t, "Furniture ideas", got.Name)
+	})
+
+	t.Run("duplicate name conflicts", func(t *testing.T) {
+		repo := new(mocks.MockBookmarkCollectionRepository)
+		repo.On("List", mock.Anything, "user-1").Return(&models.BookmarkCollectionsResponse{}, nil)
+		repo.On("Create", mock.Anything, mock.Anything).Return(repositories.ErrBookmarkCollectionExists)
+		svc := NewBookmarkCollectionService(repo, zap.NewNop())
+
+		_, err := svc.Create(context.Background(), "user-1", &models.BookmarkCollectionRequest{Name: "Events"})
+		requireAppErrCode(t, err, http.StatusConflict)
+	})
+
+	t.Run("cap on collections", func(t *testing.T) {
+		repo := new(mocks.MockBookmarkCollectionRepository)
+		repo.On("List", mock.Anything, "user-1").Return(&models.BookmarkCollectionsResponse{
+			Collections: make([]*models.BookmarkCollection, maxBookmarkCollections),
+		}, nil)
+		svc := NewBookmarkCollectionService(repo, zap.NewNop())
+
+		_, err := svc.Create(context.Background(), "user-1", &models.BookmarkCollectionRequest{Name: "One more"})
+		requireAppErrCode(t, err, http.StatusBadRequest)
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestBookmarkCollectionService_AssignBookmark(t *testing.T) {
+	t.Run("someone else's collection is not found", func(t *testing.T) {
+		repo := new(mocks.MockBookmarkCollectionRepository)
+		repo.On("GetByID", mock.Anything, testCollectionID, "user-1").
+			Return(nil, repositories.ErrBookmarkCollectionNotFound)
+		svc := NewBookmarkCollectionService(repo, zap.NewNop())
+
+		id := testCollectionID
+		err := svc.AssignBookmark(context.Background(), "user-1", "post-1", &id)
+		requireAppErrCode(t, err, http.StatusNotFound)
+		repo.AssertNotCalled(t, "AssignBookmark", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("post not bookmarked", func(t *testing.T) {
+		repo := new(mocks.MockBookmarkCollectionRepository)
+		repo.On("GetByID", mock.Anything, testCollectionID, "user-1").
+			Return(&models.BookmarkCollection{ID: testCollectionID, UserID: "user-1"}, nil)
+		repo.On("AssignBookmark", mock.Anything, "user-1", "post-1", mock.Anything).Return(repositories.ErrBookmarkNotFound)
+		svc := NewBookmarkCollectionService(repo, zap.NewNop())
+
+		id := testCollectionID
+		err := svc.AssignBookmark(context.Background(), "user-1", "post-1", &id)
+		requireAppErrCode(t, err, http.StatusNotFound)
+	})
+
+	t.Run("empty id unfiles", func(t *testing.T) {
+		repo := new(mocks.MockBookmarkCollectionRepository)
+		repo.On("AssignBookmark", mock.Anything, "user-1", "post-1", (*string)(nil)).Return(nil)
+		svc := NewBookmarkCollectionService(repo, zap.NewNop())
+
+		empty := ""
+		require.NoError(t, svc.AssignBookmark(context.Background(), "user-1", "post-1", &empty))
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestPostService_GetUserBookmarks_Collection(t *testing.T) {
+	postRepo := new(mocks.MockPostRepository)
+	collectionRepo := new(mocks.MockBookmarkCollectionRepository)
+	svc := newTestPostService(postRepo, new(mocks.MockUserRepository)).
+		WithBookmarkCollections(NewBookmarkCollectionService(collectionRepo, zap.NewNop()))
+
+	_, err := svc.GetUserBookmarks(context.Background(), "user-1", "not-a-uuid", 20, 0)
+	requireAppErrCode(t, err, http.StatusNotFound)
+
+	postRepo.On("GetUserBookmarksInCollection", mock.Anything, "user-1", models.BookmarkCollectionNone, 20, 0).
+		Return([]*models.Post{}, nil)
+	got, err := svc.GetUserBookmarks(context.Background(), "user-1", models.BookmarkCollectionNone, 20, 0)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+	postRepo.AssertNotCalled(t, "GetUserBookmarks", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
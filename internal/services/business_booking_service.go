@@ -0,0 +1,306 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/hamsaya/backend/pkg/bgtasks"
+	"go.uber.org/zap"
+)
+
+const (
+	// bookingMaxAdvance bounds how far ahead a customer can request a slot.
+	bookingMaxAdvance = 365 * 24 * time.Hour
+	// bookingMaxRange bounds one calendar query.
+	bookingMaxRange = 92 * 24 * time.Hour
+	// bookingDefaultRange is the calendar window when no "to" is given.
+	bookingDefaultRange = 31 * 24 * time.Hour
+)
+
+// BusinessBookingService runs the booking request flow: customers request a
+// slot, the owner accepts or declines, and either side is notified of the
+// other's move.
+type BusinessBookingService struct {
+	bookingRepo         repositories.BusinessBookingRepository
+	businessRepo        repositories.BusinessRepository
+	access              *AccessControl
+	userRepo            repositories.UserRepository
+	notificationService *NotificationService
+	logger              *zap.Logger
+}
+
+// NewBusinessBookingService wires the booking service.
+func NewBusinessBookingService(
+	bookingRepo repositories.BusinessBookingRepository,
+	businessRepo repositories.BusinessRepository,
+	userRepo repositories.UserRepository,
+	notificationService *NotificationService,
+	logger *zap.Logger,
+) *BusinessBookingService {
+	return &BusinessBookingService{
+		bookingRepo:         bookingRepo,
+		businessRepo:        businessRepo,
+		access:              NewAccessControl(businessRepo),
+		userRepo:            userRepo,
+		notificationService: notificationService,
+		logger:              logger,
+	}
+}
+
+// Request creates a PENDING booking and notifies the business owner.
+func (s *BusinessBookingService) Request(ctx context.Context, businessID, customerID string, req *models.CreateBookingRequest) (*models.BusinessBooking, error) {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		return nil, utils.NewNotFoundError("Business profile not found", err)
+	}
+	if business.UserID == customerID {
+		return nil, utils.NewBadRequestError("You cannot book your own business", nil)
+	}
+	now := time.Now()
+	if !req.RequestedAt.After(now) {
+		return nil, utils.NewBadRequestError("Booking time must be in the future", nil)
+	}
+	if req.RequestedAt.After(now.Add(bookingMaxAdvance)) {
+		return nil, utils.NewBadRequestError("Booking time is too far in the future", nil)
+	}
+
+	booking := &models.BusinessBooking{
+		ID:          uuid.NewString(),
+		BusinessID:  businessID,
+		CustomerID:  customerID,
+		RequestedAt: req.RequestedAt.UTC(),
+		Note:        req.Note,
+		Status:      models.BookingPending,
+	}
+	if err := s.bookingRepo.Create(ctx, booking); err != nil {
+		s.logger.Error("Failed to create booking", zap.Error(err), zap.String("business_id", businessID))
+		return nil, utils.NewInternalError("Failed to request booking", err)
+	}
+
+	s.notify(business.UserID, customerID, models.NotificationTypeBookingRequest, business, booking,
+		"requested a booking at")
+	return booking, nil
+}
+
+// Accept confirms a PENDING booking (owner only) and notifies the customer.
+func (s *BusinessBookingService) Accept(ctx context.Context, businessID, bookingID, ownerID string, req *models.RespondBookingRequest) (*models.BusinessBooking, error) {
+	return s.respond(ctx, businessID, bookingID, ownerID, req, models.BookingAccepted)
+}
+
+// Decline rejects a PENDING booking (owner only) and notifies the customer.
+func (s *BusinessBookingService) Decline(ctx context.Context, businessID, bookingID, ownerID string, req *models.RespondBookingRequest) (*models.BusinessBooking, error) {
+	return s.respond(ctx, businessID, bookingID, ownerID, req, models.BookingDeclined)
+}
+
+func (s *BusinessBookingService) respond(ctx context.Context, businessID, bookingID, ownerID string, req *models.RespondBookingRequest, to models.BookingStatus) (*models.BusinessBooking, error) {
+	business, err := s.access.RequireBusinessOwner(ctx, businessID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.getForBusiness(ctx, businessID, bookingID); err != nil {
+		return nil, err
+	}
+
+	var note *string
+	if req != nil {
+		note = req.Note
+	}
+	updated, err := s.bookingRepo.Transition(ctx, bookingID, []models.BookingStatus{models.BookingPending}, to, note)
+	if err != nil {
+		return nil, s.transitionError(err)
+	}
+
+	notifType, verb := models.NotificationTypeBookingAccepted, "accepted your booking at"
+	if to == models.BookingDeclined {
+		notifType, verb = models.NotificationTypeBookingDeclined, "declined your booking at"
+	}
+	s.notify(updated.CustomerID, ownerID, notifType, business, updated, verb)
+	return updated, nil
+}
+
+// Cancel withdraws a PENDING or ACCEPTED booking (customer only) and
+// notifies the owner.
+func (s *BusinessBookingService) Cancel(ctx context.Context, businessID, bookingID, customerID string) (*models.BusinessBooking, error) {
+	booking, err := s.getForBusiness(ctx, businessID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if booking.CustomerID != customerID {
+		return nil, utils.NewNotFoundError("Booking not found", nil)
+	}
+
+	updated, err := s.bookingRepo.Transition(ctx, bookingID,
+		[]models.BookingStatus{models.BookingPending, models.BookingAccepted}, models.BookingCancelled, nil)
+	if err != nil {
+		return nil, s.transitionError(err)
+	}
+
+	if business, berr := s.businessRepo.GetByID(ctx, businessID); berr == nil {
+		s.notify(business.UserID, customerID, models.NotificationTypeBookingCancelled, business, updated,
+			"cancelled their booking at")
+	}
+	return updated, nil
+}
+
+// Calendar returns the owner's bookings in [from, to) grouped by Kabul
+// calendar day. A zero from defaults to the start of today; a zero to
+// defaults to a month after from.
+func (s *BusinessBookingService) Calendar(ctx context.Context, businessID, ownerID string, filter *models.BookingFilter) ([]models.BookingDay, int, error) {
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, ownerID); err != nil {
+		return nil, 0, err
+	}
+
+	loc := bookingLocation()
+	if filter.From.IsZero() {
+		y, m, d := time.Now().In(loc).Date()
+		filter.From = time.Date(y, m, d, 0, 0, 0, 0, loc)
+	}
+	if filter.To.IsZero() {
+		filter.To = filter.From.Add(bookingDefaultRange)
+	}
+	if !filter.To.After(filter.From) {
+		return nil, 0, utils.NewBadRequestError("'to' must be after 'from'", nil)
+	}
+	if filter.To.Sub(filter.From) > bookingMaxRange {
+		return nil, 0, utils.NewBadRequestError("Date range cannot exceed 92 days", nil)
+	}
+
+	bookings, err := s.bookingRepo.ListByBusiness(ctx, businessID, filter)
+	if err != nil {
+		return nil, 0, utils.NewInternalError("Failed to load bookings", err)
+	}
+	return groupBookingsByDay(bookings, loc), len(bookings), nil
+}
+
+// ListMine returns the caller's own booking requests across businesses.
+func (s *BusinessBookingService) ListMine(ctx context.Context, customerID string, limit, offset int) ([]*models.BusinessBooking, int, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	bookings, total, err := s.bookingRepo.ListByCustomer(ctx, customerID, limit, offset)
+	if err != nil {
+		return nil, 0, utils.NewInternalError("Failed to load bookings", err)
+	}
+	return bookings, total, nil
+}
+
+// ParseBookingStatuses parses a comma-separated status filter.
+func ParseBookingStatuses(raw string) ([]models.BookingStatus, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var out []models.BookingStatus
+	for _, part := range strings.Split(raw, ",") {
+		st := models.BookingStatus(strings.ToUpper(strings.TrimSpace(part)))
+		switch st {
+		case models.BookingPending, models.BookingAccepted, models.BookingDeclined, models.BookingCancelled:
+			out = append(out, st)
+		default:
+			return nil, utils.NewBadRequestError("status must be one of PENDING, ACCEPTED, DECLINED, CANCELLED", nil)
+		}
+	}
+	return out, nil
+}
+
+// ParseBookingDate parses a calendar bound given as RFC3339 or as a Kabul
+// date (YYYY-MM-DD). A date-only endOfRange bound covers that whole day, so
+// from=2026-10-01&to=2026-10-31 includes the 31st.
+func ParseBookingDate(raw string, endOfRange bool) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseInLocation("2006-01-02", raw, bookingLocation())
+	if err != nil {
+		return time.Time{}, utils.NewBadRequestError("Dates must be YYYY-MM-DD or RFC3339", err)
+	}
+	if endOfRange {
+		d = d.AddDate(0, 0, 1)
+	}
+	return d, nil
+}
+
+func (s *BusinessBookingService) getForBusiness(ctx context.Context, businessID, bookingID string) (*models.BusinessBooking, error) {
+	booking, err := s.bookingRepo.GetByID(ctx, bookingID)
+	if errors.Is(err, repositories.ErrBookingNotFound) || (err == nil && booking.BusinessID != businessID) {
+		return nil, utils.NewNotFoundError("Booking not found", err)
+	}
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load booking", err)
+	}
+	return booking, nil
+}
+
+func (s *BusinessBookingService) transitionError(err error) error {
+	if errors.Is(err, repositories.ErrBookingConflict) {
+		return utils.NewConflictError("Booking can no longer be changed", err)
+	}
+	return utils.NewInternalError("Failed to update booking", err)
+}
+
+// groupBookingsByDay buckets bookings (already sorted by time) into days.
+func groupBookingsByDay(bookings []*models.BusinessBookingWithCustomer, loc *time.Location) []models.BookingDay {
+	days := make([]models.BookingDay, 0)
+	for _, b := range bookings {
+		date := b.RequestedAt.In(loc).Format("2006-01-02")
+		if n := len(days); n == 0 || days[n-1].Date != date {
+			days = append(days, models.BookingDay{Date: date})
+		}
+		last := &days[len(days)-1]
+		last.Bookings = append(last.Bookings, b)
+	}
+	return days
+}
+
+// bookingLocation is the business calendar timezone. Falls back to a fixed
+// +04:30 offset when tzdata is unavailable, like inQuietHours.
+func bookingLocation() *time.Location {
+	loc, err := time.LoadLocation("Asia/Kabul")
+	if err != nil {
+		return time.FixedZone("AFT", 4*3600+1800)
+	}
+	return loc
+}
+
+// notify sends a best-effort booking notification in the background.
+func (s *BusinessBookingService) notify(recipientID, actorID string, notifType models.NotificationType, business *models.BusinessProfile, booking *models.BusinessBooking, verb string) {
+	if s.notificationService == nil {
+		return
+	}
+	bgtasks.Submit(func(ctx context.Context) {
+		actorName := ""
+		if actor, err := s.userRepo.GetProfileByUserID(ctx, actorID); err == nil && actor != nil {
+			actorName = actor.FullName()
+		}
+		title := strings.TrimSpace(actorName + " " + verb + " " + business.Name)
+		msg := booking.RequestedAt.In(bookingLocation()).Format("Mon 2 Jan, 15:04")
+		if _, err := s.notificationService.CreateNotification(ctx, &models.CreateNotificationRequest{
+			UserID:  recipientID,
+			Type:    notifType,
+			Title:   &title,
+			Message: &msg,
+			Data: map[string]interface{}{
+				"actor_id":     actorID,
+				"actor_name":   actorName,
+				"business_id":  business.ID,
+				"booking_id":   booking.ID,
+				"status":       string(booking.Status),
+				"requested_at": booking.RequestedAt,
+			},
+		}); err != nil {
+			s.logger.Warn("Failed to send booking notification",
+				zap.String("booking_id", booking.ID), zap.String("type", string(notifType)), zap.Error(err))
+		}
+	})
+}
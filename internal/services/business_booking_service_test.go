@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestBookingService(
+	bookingRepo *mocks.MockBusinessBookingRepository,
+	businessRepo *mocks.MockBusinessRepository,
+) *BusinessBookingService {
+	// notificationService is nil so notify is a no-op.
+	return NewBusinessBookingService(bookingRepo, businessRepo, &mocks.MockUserRepository{}, nil, zap.NewNop())
+}
+
+func TestBusinessBookingService_Request(t *testing.T) {
+	business := &models.BusinessProfile{ID: "biz-1", UserID: "owner-1", Name: "Salon"}
+
+	t.Run("own business rejected", func(t *testing.T) {
+		businessRepo := &mocks.MockBusinessRepository{}
+		businessRepo.On("GetByID", mock.Anything, "biz-1").Return(business, nil)
+		svc := newTestBookingService(&mocks.MockBusinessBookingRepository{}, businessRepo)
+
+		_, err := svc.Request(context.Background(), "biz-1", "owner-1",
+			&models.CreateBookingRequest{RequestedAt: time.Now().Add(time.Hour)})
+		requireAppErrCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("past time rejected", func(t *testing.T) {
+		businessRepo := &mocks.MockBusinessRepository{}
+		businessRepo.On("GetByID", mock.Anything, "biz-1").Return(business, nil)
+		svc := newTestBookingService(&mocks.MockBusinessBookingRepository{}, businessRepo)
+
+		_, err := svc.Request(context.Background(), "biz-1", "cust-1",
+			&models.CreateBookingRequest{RequestedAt: time.Now().Add(-time.Hour)})
+		requireAppErrCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("creates pending booking", func(t *testing.T) {
+		businessRepo := &mocks.MockBusinessRepository{}
+		businessRepo.On("GetByID", mock.Anything, "biz-1").Return(business, nil)
+		bookingRepo := &mocks.MockBusinessBookingRepository{}
+		bookingRepo.On("Create", mock.Anything, mock.MatchedBy(func(b *models.BusinessBooking) bool {
+			return b.BusinessID == "biz-1" && b.CustomerID == "cust-1" && b.Status == models.BookingPending
+		})).Return(nil)
+		svc := newTestBookingService(bookingRepo, businessRepo)
+
+		got, err := svc.Request(context.Background(), "biz-1", "cust-1",
+			&models.CreateBookingRequest{RequestedAt: time.Now().Add(24 * time.Hour)})
+		require.NoError(t, err)
+		assert.Equal(t, models.BookingPending, got.Status)
+		bookingRepo.AssertExpectations(t)
+	})
+}
+
+func TestBusinessBookingService_Respond(t *testing.T) {
+	business := &models.BusinessProfile{ID: "biz-1", UserID: "owner-1", Name: "Salon"}
+	pending := &models.BusinessBooking{ID: "bk-1", BusinessID: "biz-1", CustomerID: "cust-1", Status: models.BookingPending}
+
+	t.Run("non-owner forbidden", func(t *testing.T) {
+		businessRepo := &mocks.MockBusinessRepository{}
+		businessRepo.On("GetByID", mock.Anything, "biz-1").Return(business, nil)
+		bookingRepo := &mocks.MockBusinessBookingRepository{}
+		svc := newTestBookingService(bookingRepo, businessRepo)
+
+		_, err := svc.Accept(context.Background(), "biz-1", "bk-1", "cust-1", nil)
+		requireAppErrCode(t, err, http.StatusForbidden)
+		bookingRepo.AssertNotCalled(t, "Transition", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("accept moves pending to accepted", func(t *testing.T) {
+		businessRepo := &mocks.MockBusinessRepository{}
+		businessRepo.On("GetByID", mock.Anything, "biz-1").Return(business, nil)
+		bookingRepo := &mocks.MockBusinessBookingRepository{}
+		bookingRepo.On("GetByID", mock.Anything, "bk-1").Return(pending, nil)
+		note := "See you then"
+		accepted := *pending
+		accepted.Status = models.BookingAccepted
+		bookingRepo.On("Transition", mock.Anything, "bk-1",
+			[]models.BookingStatus{models.BookingPending}, models.BookingAccepted, &note).
+			Return(&accepted, nil)
+		svc := newTestBookingService(bookingRepo, businessRepo)
+
+		got, err := svc.Accept(context.Background(), "biz-1", "bk-1", "owner-1", &models.RespondBookingRequest{Note: &note})
+		require.NoError(t, err)
+		assert.Equal(t, models.BookingAccepted, got.Status)
+	})
+
+	t.Run("already handled is a conflict", func(t *testing.T) {
+		businessRepo := &mocks.MockBusinessRepository{}
+		businessRepo.On("GetByID", mock.Anything, "biz-1").Return(business, nil)
+		bookingRepo := &mocks.MockBusinessBookingRepository{}
+		bookingRepo.On("GetByID", mock.Anything, "bk-1").Return(pending, nil)
+		bookingRepo.On("Transition", mock.Anything, "bk-1", mock.Anything, models.BookingDeclined, mock.Anything).
+			Return(nil, repositories.ErrBookingConflict)
+		svc := newTestBookingService(bookingRepo, businessRepo)
+
+		_, err := svc.Decline(context.Background(), "biz-1", "bk-1", "owner-1", nil)
+		requireAppErrCode(t, err, http.StatusConflict)
+	})
+}
+
+func TestBusinessBookingService_Cancel_OtherCustomer(t *testing.T) {
+	bookingRepo := &mocks.MockBusinessBookingRepository{}
+	bookingRepo.On("GetByID", mock.Anything, "bk-1").
+		Return(&models.BusinessBooking{ID: "bk-1", BusinessID: "biz-1", CustomerID: "cust-1"}, nil)
+	svc := newTestBookingService(bookingRepo, &mocks.MockBusinessRepository{})
+
+	_, err := svc.Cancel(context.Background(), "biz-1", "bk-1", "cust-2")
+	requireAppErrCode(t, err, http.StatusNotFound)
+}
+
+func TestBusinessBookingService_Calendar(t *testing.T) {
+	business := &models.BusinessProfile{ID: "biz-1", UserID: "owner-1"}
+	loc := bookingLocation()
+
+	t.Run("range too wide", func(t *testing.T) {
+		businessRepo := &mocks.MockBusinessRepository{}
+		businessRepo.On("GetByID", mock.Anything, "biz-1").Return(business, nil)
+		svc := newTestBookingService(&mocks.MockBusinessBookingRepository{}, businessRepo)
+
+		from := time.Date(2026, 1, 1, 0, 0, 0, 0, loc)
+		_, _, err := svc.Calendar(context.Background(), "biz-1", "owner-1",
+			&models.BookingFilter{From: from, To: from.AddDate(0, 6, 0)})
+		requireAppErrCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("groups by Kabul day", func(t *testing.T) {
+		businessRepo := &mocks.MockBusinessRepository{}
+		businessRepo.On("GetByID", mock.Anything, "biz-1").Return(business, nil)
+		bookingRepo := &mocks.MockBusinessBookingRepository{}
+		// 20:00 UTC on the 1st is 00:30 on the 2nd in Kabul.
+		mk := func(id string, at time.Time) *models.BusinessBookingWithCustomer {
+			return &models.BusinessBookingWithCustomer{BusinessBooking: models.BusinessBooking{ID: id, RequestedAt: at}}
+		}
+		bookingRepo.On("ListByBusiness", mock.Anything, "biz-1", mock.Anything).Return([]*models.BusinessBookingWithCustomer{
+			mk("a", time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)),
+			mk("b", time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)),
+			mk("c", time.Date(2026, 3, 2, 6, 0, 0, 0, time.UTC)),
+		}, nil)
+		svc := newTestBookingService(bookingRepo, businessRepo)
+
+		from := time.Date(2026, 3, 1, 0, 0, 0, 0, loc)
+		days, total, err := svc.Calendar(context.Background(), "biz-1", "owner-1",
+			&models.BookingFilter{From: from})
+		require.NoError(t, err)
+		assert.Equal(t, 3, total)
+		require.Len(t, days, 2)
+		assert.Equal(t, "2026-03-01", days[0].Date)
+		assert.Len(t, days[0].Bookings, 1)
+		assert.Equal(t, "2026-03-02", days[1].Date)
+		assert.Len(t, days[1].Bookings, 2)
+	})
+}
+
+func TestParseBookingInputs(t *testing.T) {
+	statuses, err := ParseBookingStatuses("pending, ACCEPTED")
+	require.NoError(t, err)
+	assert.Equal(t, []models.BookingStatus{models.BookingPending, models.BookingAccepted}, statuses)
+
+	_, err = ParseBookingStatuses("DONE")
+	assert.Error(t, err)
+
+	to, err := ParseBookingDate("2026-10-31", true)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, bookingLocation()), to)
+
+	_, err = ParseBookingDate("31/10/2026", false)
+	assert.Error(t, err)
+}
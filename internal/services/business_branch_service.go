@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// maxBusinessBranches caps the branches per business; the profile lists
+// them all in one section.
+const maxBusinessBranches = 50
+
+// BusinessBranchService manages the extra locations of a business. Anyone
+// can list them; only the owner edits them.
+type BusinessBranchService struct {
+	branchRepo   repositories.BusinessBranchRepository
+	businessRepo repositories.BusinessRepository
+	access       *AccessControl
+	logger       *zap.Logger
+}
+
+// NewBusinessBranchService wires the branch service.
+func NewBusinessBranchService(
+	branchRepo repositories.BusinessBranchRepository,
+	businessRepo repositories.BusinessRepository,
+	logger *zap.Logger,
+) *BusinessBranchService {
+	return &BusinessBranchService{
+		branchRepo:   branchRepo,
+		businessRepo: businessRepo,
+		access:       NewAccessControl(businessRepo),
+		logger:       logger,
+	}
+}
+
+// getForBusiness loads a branch and 404s when it belongs to another
+// business.
+func (s *BusinessBranchService) getForBusiness(ctx context.Context, businessID, branchID string) (*models.BusinessBranch, error) {
+	branch, err := s.branchRepo.GetByID(ctx, branchID)
+	if errors.Is(err, repositories.ErrBranchNotFound) || (err == nil && branch.BusinessID != businessID) {
+		return nil, utils.NewNotFoundError("Branch not found", err)
+	}
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load branch", err)
+	}
+	return branch, nil
+}
+
+// List returns the business's branches in display order.
+func (s *BusinessBranchService) List(ctx context.Context, businessID string) ([]*models.BusinessBranch, error) {
+	if _, err := s.businessRepo.GetByID(ctx, businessID); err != nil {
+		return nil, utils.NewNotFoundError("Business profile not found", err)
+	}
+	branches, err := s.branchRepo.ListByBusiness(ctx, businessID)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load branches", err)
+	}
+	return branches, nil
+}
+
+// Create adds a branch.
+func (s *BusinessBranchService) Create(ctx context.Context, businessID, userID string, req *models.CreateBusinessBranchRequest) (*models.BusinessBranch, error) {
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return nil, err
+	}
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		return nil, utils.NewBadRequestError("Latitude and longitude must be sent together", nil)
+	}
+	hours, err := branchHours(req.Hours)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.branchRepo.CountByBusiness(ctx, businessID)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to create branch", err)
+	}
+	if count >= maxBusinessBranches {
+		return nil, utils.NewBadRequestError("A business can have up to 50 branches", nil)
+	}
+
+	branch := &models.BusinessBranch{
+		ID:           uuid.NewString(),
+		BusinessID:   businessID,
+		Name:         strings.TrimSpace(req.Name),
+		Address:      req.Address,
+		Latitude:     req.Latitude,
+		Longitude:    req.Longitude,
+		Country:      req.Country,
+		Province:     req.Province,
+		District:     req.District,
+		Neighborhood: req.Neighborhood,
+		PhoneNumber:  req.PhoneNumber,
+		Hours:        hours,
+	}
+	if req.Position != nil {
+		branch.Position = *req.Position
+	}
+	if err := s.branchRepo.Create(ctx, branch); err != nil {
+		s.logger.Error("Failed to create branch", zap.Error(err), zap.String("business_id", businessID))
+		return nil, utils.NewInternalError("Failed to create branch", err)
+	}
+	return branch, nil
+}
+
+// Update edits a branch. Sending both coordinates moves it; there is no way
+// to clear the point short of deleting the branch.
+func (s *BusinessBranchService) Update(ctx context.Context, businessID, branchID, userID string, req *models.UpdateBusinessBranchRequest) (*models.BusinessBranch, error) {
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return nil, err
+	}
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		return nil, utils.NewBadRequestError("Latitude and longitude must be sent together", nil)
+	}
+	branch, err := s.getForBusiness(ctx, businessID, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		branch.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Address != nil {
+		branch.Address = req.Address
+	}
+	if req.Latitude != nil {
+		branch.Latitude, branch.Longitude = req.Latitude, req.Longitude
+	}
+	if req.Country != nil {
+		branch.Country = req.Country
+	}
+	if req.Province != nil {
+		branch.Province = req.Province
+	}
+	if req.District != nil {
+		branch.District = req.District
+	}
+	if req.Neighborhood != nil {
+		branch.Neighborhood = req.Neighborhood
+	}
+	if req.PhoneNumber != nil {
+		branch.PhoneNumber = req.PhoneNumber
+	}
+	if req.Hours != nil {
+		if branch.Hours, err = branchHours(req.Hours); err != nil {
+			return nil, err
+		}
+	}
+	if req.Position != nil {
+		branch.Position = *req.Position
+	}
+
+	if err := s.branchRepo.Update(ctx, branch); err != nil {
+		if errors.Is(err, repositories.ErrBranchNotFound) {
+			return nil, utils.NewNotFoundError("Branch not found", err)
+		}
+		return nil, utils.NewInternalError("Failed to update branch", err)
+	}
+	return branch, nil
+}
+
+// Delete removes a branch. Posts made from it stay up without a branch.
+func (s *BusinessBranchService) Delete(ctx context.Context, businessID, branchID, userID string) error {
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return err
+	}
+	if _, err := s.getForBusiness(ctx, businessID, branchID); err != nil {
+		return err
+	}
+	if err := s.branchRepo.Delete(ctx, branchID); err != nil {
+		if errors.Is(err, repositories.ErrBranchNotFound) {
+			return utils.NewNotFoundError("Branch not found", err)
+		}
+		return utils.NewInternalError("Failed to delete branch", err)
+	}
+	return nil
+}
+
+// ResolveForPost validates the branch chosen for a post: the post must be
+// made as a business and the branch must be one of its own.
+func (s *BusinessBranchService) ResolveForPost(ctx context.Context, branchID string, businessID *string) (*models.BusinessBranch, error) {
+	if businessID == nil || *businessID == "" {
+		return nil, utils.NewBadRequestError("Only business posts can name a branch", nil)
+	}
+	branch, err := s.branchRepo.GetByID(ctx, branchID)
+	if errors.Is(err, repositories.ErrBranchNotFound) || (err == nil && branch.BusinessID != *businessID) {
+		return nil, utils.NewBadRequestError("Branch not found for this business", err)
+	}
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load branch", err)
+	}
+	return branch, nil
+}
+
+// BranchesForPosts returns the branch per post ID for enrichment.
+func (s *BusinessBranchService) BranchesForPosts(ctx context.Context, postIDs []string) (map[string]*models.BusinessBranch, error) {
+	return s.branchRepo.GetByPostIDs(ctx, postIDs)
+}
+
+// branchHours checks a branch's weekly hours and puts them in response
+// form. Unlike SetBusinessHours, a malformed time is rejected rather than
+// dropped, since the whole week is stored as one value.
+func branchHours(req []models.BusinessHoursRequest) ([]models.BusinessHoursResponse, error) {
+	out := make([]models.BusinessHoursResponse, 0, len(req))
+	seen := make(map[string]bool, len(req))
+	for _, h := range req {
+		if seen[h.Day] {
+			return nil, utils.NewBadRequestError("Each day can appear once in branch hours", nil)
+		}
+		seen[h.Day] = true
+		day := models.BusinessHoursResponse{Day: h.Day, IsClosed: h.IsClosed}
+		if !h.IsClosed {
+			for _, t := range []struct {
+				raw string
+				dst **string
+			}{{h.OpenTime, &day.OpenTime}, {h.CloseTime, &day.CloseTime}} {
+				if t.raw == "" {
+					continue
+				}
+				parsed, err := time.Parse("15:04", t.raw)
+				if err != nil {
+					return nil, utils.NewBadRequestError("Branch hours must use HH:MM times", err)
+				}
+				formatted := parsed.Format("15:04")
+				*t.dst = &formatted
+			}
+		}
+		out = append(out, day)
+	}
+	return out, nil
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestBranchService(t *testing.T) (*BusinessBranchService, *mocks.MockBusinessBranchRepository) {
+	t.Helper()
+	branchRepo := new(mocks.MockBusinessBranchRepository)
+	bizRepo := new(mocks.MockBusinessRepository)
+	bizRepo.On("GetByID", mock.Anything, "biz-1").
+		Return(&models.BusinessProfile{ID: "biz-1", UserID: "owner-1", Name: "Kabul Bakery"}, nil).Maybe()
+	return NewBusinessBranchService(branchRepo, bizRepo, zap.NewNop()), branchRepo
+}
+
+func TestBusinessBranchService_Create(t *testing.T) {
+	ctx := context.Background()
+	lat, lng := 34.52, 69.18
+
+	t.Run("owner only", func(t *testing.T) {
+		svc, branchRepo := newTestBranchService(t)
+		_, err := svc.Create(ctx, "biz-1", "someone-else", &models.CreateBusinessBranchRequest{Name: "Shar-e-Naw"})
+		requireAppErrorCode(t, err, http.StatusForbidden)
+		branchRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("coordinates come in pairs", func(t *testing.T) {
+		svc, _ := newTestBranchService(t)
+		_, err := svc.Create(ctx, "biz-1", "owner-1", &models.CreateBusinessBranchRequest{Name: "Shar-e-Naw", Latitude: &lat})
+		requireAppErrorCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("rejects malformed hours", func(t *testing.T) {
+		svc, _ := newTestBranchService(t)
+		_, err := svc.Create(ctx, "biz-1", "owner-1", &models.CreateBusinessBranchRequest{
+			Name:  "Shar-e-Naw",
+			Hours: []models.BusinessHoursRequest{{Day: "Monday", OpenTime: "9am", CloseTime: "17:00"}},
+		})
+		requireAppErrorCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("capped per business", func(t *testing.T) {
+		svc, branchRepo := newTestBranchService(t)
+		branchRepo.On("CountByBusiness", ctx, "biz-1").Return(maxBusinessBranches, nil)
+		_, err := svc.Create(ctx, "biz-1", "owner-1", &models.CreateBusinessBranchRequest{Name: "Shar-e-Naw"})
+		requireAppErrorCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("saves", func(t *testing.T) {
+		svc, branchRepo := newTestBranchService(t)
+		branchRepo.On("CountByBusiness", ctx, "biz-1").Return(1, nil)
+		branchRepo.On("Create", ctx, mock.MatchedBy(func(b *models.BusinessBranch) bool {
+			return b.BusinessID == "biz-1" && b.Name == "Shar-e-Naw" && b.HasLocation() &&
+				len(b.Hours) == 2 && *b.Hours[0].OpenTime == "09:00" && b.Hours[1].OpenTime == nil
+		})).Return(nil)
+		branch, err := svc.Create(ctx, "biz-1", "owner-1", &models.CreateBusinessBranchRequest{
+			Name:      " Shar-e-Naw ",
+			Latitude:  &lat,
+			Longitude: &lng,
+			Hours: []models.BusinessHoursRequest{
+				{Day: "Monday", OpenTime: "9:00", CloseTime: "17:00"},
+				{Day: "Friday", IsClosed: true, OpenTime: "9:00"},
+			},
+		})
+		require.NoError(t, err)
+		assert.NotEmpty(t, branch.ID)
+	})
+}
+
+func TestBusinessBranchService_UpdateAndDelete(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("branch of another business is not found", func(t *testing.T) {
+		svc, branchRepo := newTestBranchService(t)
+		branchRepo.On("GetByID", ctx, "br-9").Return(&models.BusinessBranch{ID: "br-9", BusinessID: "biz-2"}, nil)
+		_, err := svc.Update(ctx, "biz-1", "br-9", "owner-1", &models.UpdateBusinessBranchRequest{Name: ptrStr("Moved")})
+		requireAppErrorCode(t, err, http.StatusNotFound)
+		err = svc.Delete(ctx, "biz-1", "br-9", "owner-1")
+		requireAppErrorCode(t, err, http.StatusNotFound)
+		branchRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		branchRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("updates only sent fields", func(t *testing.T) {
+		svc, branchRepo := newTestBranchService(t)
+		phone := "+93700000000"
+		branchRepo.On("GetByID", ctx, "br-1").
+			Return(&models.BusinessBranch{ID: "br-1", BusinessID: "biz-1", Name: "Old", PhoneNumber: &phone}, nil)
+		branchRepo.On("Update", ctx, mock.MatchedBy(func(b *models.BusinessBranch) bool {
+			return b.Name == "New" && b.PhoneNumber == &phone
+		})).Return(nil)
+		branch, err := svc.Update(ctx, "biz-1", "br-1", "owner-1", &models.UpdateBusinessBranchRequest{Name: ptrStr("New")})
+		require.NoError(t, err)
+		assert.Equal(t, "New", branch.Name)
+	})
+}
+
+func TestBusinessBranchService_ResolveForPost(t *testing.T) {
+	ctx := context.Background()
+	svc, branchRepo := newTestBranchService(t)
+	branchRepo.On("GetByID", ctx, "br-1").Return(&models.BusinessBranch{ID: "br-1", BusinessID: "biz-1"}, nil)
+	branchRepo.On("GetByID", ctx, "gone").Return(nil, repositories.ErrBranchNotFound)
+	branchRepo.On("GetByID", ctx, "broken").Return(nil, errors.New("db down"))
+
+	branch, err := svc.ResolveForPost(ctx, "br-1", ptrStr("biz-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "br-1", branch.ID)
+
+	_, err = svc.ResolveForPost(ctx, "br-1", nil)
+	requireAppErrorCode(t, err, http.StatusBadRequest)
+
+	_, err = svc.ResolveForPost(ctx, "br-1", ptrStr("biz-2"))
+	requireAppErrorCode(t, err, http.StatusBadRequest)
+
+	_, err = svc.ResolveForPost(ctx, "gone", ptrStr("biz-1"))
+	requireAppErrorCode(t, err, http.StatusBadRequest)
+
+	_, err = svc.ResolveForPost(ctx, "broken", ptrStr("biz-1"))
+	requireAppErrorCode(t, err, http.StatusInternalServerError)
+}
+
+func TestPostService_CreatePostChecksBranch(t *testing.T) {
+	ctx := context.Background()
+	branches, branchRepo := newTestBranchService(t)
+	branchRepo.On("GetByID", mock.Anything, "br-1").Return(&models.BusinessBranch{ID: "br-1", BusinessID: "biz-2"}, nil)
+
+	postRepo := new(mocks.MockPostRepository)
+	bizRepo := new(mocks.MockBusinessRepository)
+	bizRepo.On("GetByID", mock.Anything, "biz-1").Return(&models.BusinessProfile{ID: "biz-1", UserID: "owner-1"}, nil)
+	svc := newTestPostService(postRepo, new(mocks.MockUserRepository)).WithBranches(branches)
+	svc.businessRepo = bizRepo
+
+	desc := "Fresh bread at our new shop"
+	_, err := svc.CreatePost(ctx, "owner-1", &models.CreatePostRequest{
+		Type: models.PostTypeFeed, Description: &desc, BranchID: ptrStr("br-1"),
+	})
+	requireAppErrorCode(t, err, http.StatusBadRequest)
+
+	_, err = svc.CreatePost(ctx, "owner-1", &models.CreatePostRequest{
+		Type: models.PostTypeFeed, Description: &desc, BusinessID: ptrStr("biz-1"), BranchID: ptrStr("br-1"),
+	})
+	requireAppErrorCode(t, err, http.StatusBadRequest)
+	postRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
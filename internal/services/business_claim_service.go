@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/hamsaya/backend/pkg/cache"
+	"go.uber.org/zap"
+)
+
+// supersededClaimReason is stored on pending claims that lose to an approved one.
+const supersededClaimReason = "Another claim for this business was approved"
+
+// BusinessClaimService lets members claim businesses nobody owns yet —
+// imported or admin-created ones, which stay on a staff account — and lets
+// admins approve the claim and transfer ownership.
+type BusinessClaimService struct {
+	claimRepo    repositories.BusinessClaimRepository
+	businessRepo repositories.BusinessRepository
+	userRepo     repositories.UserRepository
+	notification *NotificationService
+	logger       *zap.Logger
+
+	// Optional — the business-profile cache namespace (same one
+	// BusinessService uses). Busted on approval so the new owner can manage
+	// the business immediately.
+	businessCache *cache.Cache
+}
+
+// NewBusinessClaimService constructs the service.
+func NewBusinessClaimService(
+	claimRepo repositories.BusinessClaimRepository,
+	businessRepo repositories.BusinessRepository,
+	userRepo repositories.UserRepository,
+	notification *NotificationService,
+	logger *zap.Logger,
+) *BusinessClaimService {
+	return &BusinessClaimService{
+		claimRepo:    claimRepo,
+		businessRepo: businessRepo,
+		userRepo:     userRepo,
+		notification: notification,
+		logger:       logger,
+	}
+}
+
+// WithBusinessCache attaches the business-profile cache namespace so
+// approvals invalidate cached profiles. Call once at startup.
+func (s *BusinessClaimService) WithBusinessCache(c *cache.Cache) *BusinessClaimService {
+	s.businessCache = c
+	return s
+}
+
+// Submit files a claim on a business held by staff. Requires at least one
+// proof image; a user can have one pending claim per business.
+func (s *BusinessClaimService) Submit(
+	ctx context.Context, businessID, userID string, note *string, proof []models.Photo,
+) (*models.BusinessClaimRequest, error) {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil || business == nil {
+		return nil, utils.NewNotFoundError("Business not found", err)
+	}
+	if business.UserID == userID {
+		return nil, utils.NewBadRequestError("You already own this business", nil)
+	}
+	if !s.claimable(ctx, business) {
+		return nil, utils.NewConflictError("This business already has an owner", nil)
+	}
+	if len(proof) == 0 {
+		return nil, utils.NewBadRequestError("At least one proof image is required", nil)
+	}
+
+	claim := &models.BusinessClaimRequest{
+		ID:         uuid.NewString(),
+		BusinessID: businessID,
+		UserID:     userID,
+		Note:       note,
+		Proof:      proof,
+	}
+	if err := s.claimRepo.Create(ctx, claim); err != nil {
+		if errors.Is(err, repositories.ErrBusinessClaimPending) {
+			return nil, utils.NewBadRequestError("You already have a pending claim for this business", err)
+		}
+		s.logger.Error("Failed to create business claim",
+			zap.String("business_id", businessID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to submit claim", err)
+	}
+
+	s.logger.Info("Business claim submitted",
+		zap.String("business_id", businessID), zap.String("claim_id", claim.ID))
+	return claim, nil
+}
+
+// claimable reports whether the business is still held by a staff account.
+// An owner that can't be loaded leaves it unclaimable rather than open.
+func (s *BusinessClaimService) claimable(ctx context.Context, business *models.BusinessProfile) bool {
+	owner, err := s.userRepo.GetByID(ctx, business.UserID)
+	if err != nil || owner == nil {
+		return false
+	}
+	return owner.IsAdminOrModerator()
+}
+
+// MyClaim returns the caller's latest claim on a business, or nil when they
+// never claimed it.
+func (s *BusinessClaimService) MyClaim(ctx context.Context, businessID, userID string) (*models.BusinessClaimRequest, error) {
+	claim, err := s.claimRepo.GetLatestForUser(ctx, businessID, userID)
+	if errors.Is(err, repositories.ErrBusinessClaimNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load claim", err)
+	}
+	return claim, nil
+}
+
+// List returns the admin queue (status filter optional).
+func (s *BusinessClaimService) List(ctx context.Context, status *string, limit, offset int) ([]*models.BusinessClaimListItem, int, error) {
+	items, total, err := s.claimRepo.List(ctx, status, limit, offset)
+	if err != nil {
+		return nil, 0, utils.NewInternalError("Failed to list business claims", err)
+	}
+	return items, total, nil
+}
+
+// Review approves or rejects a pending claim. Approval transfers the
+// business to the claimant and rejects the other pending claims on it;
+// every claimant affected is notified.
+func (s *BusinessClaimService) Review(ctx context.Context, claimID, reviewerID, action string, reason *string) (*models.BusinessClaimRequest, error) {
+	claim, err := s.claimRepo.GetByID(ctx, claimID)
+	if errors.Is(err, repositories.ErrBusinessClaimNotFound) {
+		return nil, utils.NewNotFoundError("Claim not found", err)
+	}
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load claim", err)
+	}
+	if claim.Status != models.BusinessClaimStatusPending {
+		return nil, utils.NewBadRequestError("Claim has already been reviewed", nil)
+	}
+
+	businessName := "the business"
+	if action != "approve" {
+		if err := s.claimRepo.Reject(ctx, claimID, reviewerID, reason); err != nil {
+			return nil, s.reviewError(claimID, err)
+		}
+		if business, err := s.businessRepo.GetByID(ctx, claim.BusinessID); err == nil && business.Name != "" {
+			businessName = business.Name
+		}
+		s.notifyClaimant(ctx, claim, businessName, models.BusinessClaimStatusRejected, reason)
+		return s.claimRepo.GetByID(ctx, claimID)
+	}
+
+	business, err := s.businessRepo.GetByID(ctx, claim.BusinessID)
+	if err != nil || business == nil {
+		return nil, utils.NewNotFoundError("Business not found", err)
+	}
+	if !s.claimable(ctx, business) {
+		return nil, utils.NewConflictError("This business already has an owner", nil)
+	}
+	superseded, err := s.claimRepo.Approve(ctx, claim, reviewerID, reason, supersededClaimReason)
+	if err != nil {
+		return nil, s.reviewError(claimID, err)
+	}
+	if s.businessCache != nil {
+		s.businessCache.DelPattern(ctx, claim.BusinessID+":*")
+	}
+	s.logger.Info("Business claim approved",
+		zap.String("business_id", claim.BusinessID), zap.String("claim_id", claimID),
+		zap.String("previous_owner_id", business.UserID), zap.String("new_owner_id", claim.UserID))
+
+	if business.Name != "" {
+		businessName = business.Name
+	}
+	s.notifyClaimant(ctx, claim, businessName, models.BusinessClaimStatusApproved, reason)
+	lost := supersededClaimReason
+	for _, other := range superseded {
+		s.notifyClaimant(ctx, other, businessName, models.BusinessClaimStatusRejected, &lost)
+	}
+	return s.claimRepo.GetByID(ctx, claimID)
+}
+
+func (s *BusinessClaimService) reviewError(claimID string, err error) error {
+	switch {
+	case errors.Is(err, repositories.ErrBusinessClaimReviewed):
+		return utils.NewBadRequestError("Claim has already been reviewed", err)
+	case errors.Is(err, repositories.ErrNotFound):
+		return utils.NewNotFoundError("Business not found", err)
+	}
+	s.logger.Error("Failed to review business claim", zap.String("claim_id", claimID), zap.Error(err))
+	return utils.NewInternalError("Failed to review claim", err)
+}
+
+// OwnershipHistory returns who held a business before, newest change first.
+func (s *BusinessClaimService) OwnershipHistory(ctx context.Context, businessID string) ([]*models.BusinessOwnershipChange, error) {
+	if _, err := s.businessRepo.GetByID(ctx, businessID); err != nil {
+		return nil, utils.NewNotFoundError("Business not found", err)
+	}
+	changes, err := s.claimRepo.ListOwnershipHistory(ctx, businessID)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load ownership history", err)
+	}
+	return changes, nil
+}
+
+func (s *BusinessClaimService) notifyClaimant(ctx context.Context, claim *models.BusinessClaimRequest, businessName, status string, reason *string) {
+	if s.notification == nil {
+		return
+	}
+
+	var notifType models.NotificationType
+	var title, msg string
+	if status == models.BusinessClaimStatusApproved {
+		notifType = models.NotificationTypeBusinessClaimApproved
+		title = fmt.Sprintf("You now manage %s", businessName)
+		msg = "Your claim was approved. You can now edit the business profile and reply to customers."
+	} else {
+		notifType = models.NotificationTypeBusinessClaimRejected
+		title = fmt.Sprintf("Your claim for %s was declined", businessName)
+		msg = "Your claim was not approved."
+		if reason != nil && *reason != "" {
+			msg = "Reason: " + *reason
+		}
+	}
+
+	if _, err := s.notification.CreateNotification(ctx, &models.CreateNotificationRequest{
+		UserID:  claim.UserID,
+		Type:    notifType,
+		Title:   &title,
+		Message: &msg,
+		Data: map[string]interface{}{
+			"type":        string(notifType),
+			"business_id": claim.BusinessID,
+			"claim_id":    claim.ID,
+		},
+	}); err != nil {
+		s.logger.Warn("Failed to notify claimant of claim outcome", zap.Error(err))
+	}
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newTestClaimService returns a claim service whose business biz-1 is held
+// by staff (admin-1) and biz-2 by a member (owner-1).
+func newTestClaimService(claimRepo *mocks.MockBusinessClaimRepository) *BusinessClaimService {
+	businessRepo := new(mocks.MockBusinessRepository)
+	businessRepo.On("GetByID", mock.Anything, "biz-1").
+		Return(&models.BusinessProfile{ID: "biz-1", UserID: "admin-1", Name: "Kabul Bakery"}, nil)
+	businessRepo.On("GetByID", mock.Anything, "biz-2").
+		Return(&models.BusinessProfile{ID: "biz-2", UserID: "owner-1"}, nil)
+
+	admin := testutil.CreateTestUser("admin-1", "admin@example.com")
+	admin.Role = models.RoleAdmin
+	userRepo := new(mocks.MockUserRepository)
+	userRepo.On("GetByID", mock.Anything, "admin-1").Return(admin, nil)
+	userRepo.On("GetByID", mock.Anything, "owner-1").Return(testutil.CreateTestUser("owner-1", "owner@example.com"), nil)
+
+	// notification service is nil — notifyClaimant no-ops without it.
+	return NewBusinessClaimService(claimRepo, businessRepo, userRepo, nil, zap.NewNop())
+}
+
+func TestBusinessClaimService_Submit(t *testing.T) {
+	ctx := context.Background()
+	claimRepo := new(mocks.MockBusinessClaimRepository)
+	claimRepo.On("Create", ctx, mock.MatchedBy(func(c *models.BusinessClaimRequest) bool {
+		return c.BusinessID == "biz-1" && c.UserID == "user-1" && len(c.Proof) == 1
+	})).Return(nil).Once()
+	claimRepo.On("Create", ctx, mock.MatchedBy(func(c *models.BusinessClaimRequest) bool {
+		return c.UserID == "user-2"
+	})).Return(repositories.ErrBusinessClaimPending).Once()
+	svc := newTestClaimService(claimRepo)
+
+	claim, err := svc.Submit(ctx, "biz-1", "user-1", nil, docPhotos(1))
+	require.NoError(t, err)
+	assert.Equal(t, "biz-1", claim.BusinessID)
+
+	_, err = svc.Submit(ctx, "biz-1", "user-2", nil, docPhotos(1))
+	requireAppErrorCode(t, err, http.StatusBadRequest)
+
+	_, err = svc.Submit(ctx, "biz-2", "user-1", nil, docPhotos(1))
+	requireAppErrorCode(t, err, http.StatusConflict)
+
+	_, err = svc.Submit(ctx, "biz-1", "user-1", nil, nil)
+	requireAppErrorCode(t, err, http.StatusBadRequest)
+
+	_, err = svc.Submit(ctx, "biz-2", "owner-1", nil, docPhotos(1))
+	requireAppErrorCode(t, err, http.StatusBadRequest)
+	claimRepo.AssertExpectations(t)
+}
+
+func TestBusinessClaimService_Review(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("approve transfers and supersedes", func(t *testing.T) {
+		claim := &models.BusinessClaimRequest{ID: "claim-1", BusinessID: "biz-1", UserID: "user-1", Status: models.BusinessClaimStatusPending}
+		approved := *claim
+		approved.Status = models.BusinessClaimStatusApproved
+		claimRepo := new(mocks.MockBusinessClaimRepository)
+		claimRepo.On("GetByID", ctx, "claim-1").Return(claim, nil).Once()
+		claimRepo.On("GetByID", ctx, "claim-1").Return(&approved, nil).Once()
+		claimRepo.On("Approve", ctx, claim, "admin-2", (*string)(nil), supersededClaimReason).
+			Return([]*models.BusinessClaimRequest{{ID: "claim-2", BusinessID: "biz-1", UserID: "user-2"}}, nil).Once()
+		svc := newTestClaimService(claimRepo)
+
+		result, err := svc.Review(ctx, "claim-1", "admin-2", "approve", nil)
+		require.NoError(t, err)
+		assert.Equal(t, models.BusinessClaimStatusApproved, result.Status)
+		claimRepo.AssertExpectations(t)
+	})
+
+	t.Run("approve an owned business", func(t *testing.T) {
+		claim := &models.BusinessClaimRequest{ID: "claim-3", BusinessID: "biz-2", UserID: "user-1", Status: models.BusinessClaimStatusPending}
+		claimRepo := new(mocks.MockBusinessClaimRepository)
+		claimRepo.On("GetByID", ctx, "claim-3").Return(claim, nil)
+		svc := newTestClaimService(claimRepo)
+
+		_, err := svc.Review(ctx, "claim-3", "admin-2", "approve", nil)
+		requireAppErrorCode(t, err, http.StatusConflict)
+		claimRepo.AssertNotCalled(t, "Approve", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		reason := "License photo is unreadable"
+		claim := &models.BusinessClaimRequest{ID: "claim-4", BusinessID: "biz-1", UserID: "user-1", Status: models.BusinessClaimStatusPending}
+		claimRepo := new(mocks.MockBusinessClaimRepository)
+		claimRepo.On("GetByID", ctx, "claim-4").Return(claim, nil)
+		claimRepo.On("Reject", ctx, "claim-4", "admin-2", &reason).Return(nil).Once()
+		svc := newTestClaimService(claimRepo)
+
+		_, err := svc.Review(ctx, "claim-4", "admin-2", "reject", &reason)
+		require.NoError(t, err)
+		claimRepo.AssertExpectations(t)
+	})
+
+	t.Run("already reviewed", func(t *testing.T) {
+		claimRepo := new(mocks.MockBusinessClaimRepository)
+		claimRepo.On("GetByID", ctx, "claim-5").
+			Return(&models.BusinessClaimRequest{ID: "claim-5", Status: models.BusinessClaimStatusRejected}, nil)
+		svc := newTestClaimService(claimRepo)
+
+		_, err := svc.Review(ctx, "claim-5", "admin-2", "approve", nil)
+		requireAppErrorCode(t, err, http.StatusBadRequest)
+	})
+}
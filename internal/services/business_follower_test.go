@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBusinessService_ListFollowers(t *testing.T) {
+	t.Run("only the owner sees followers", func(t *testing.T) {
+		businessRepo := new(mocks.MockBusinessRepository)
+		businessRepo.On("GetByID", mock.Anything, "biz-1").Return(testutil.CreateTestBusiness("biz-1", "owner-1", "Acme"), nil)
+		svc := newTestBusinessService(businessRepo, new(mocks.MockUserRepository))
+
+		_, _, err := svc.ListFollowers(context.Background(), "biz-1", "user-2", 20, 0)
+		requireAppErrCode(t, err, http.StatusForbidden)
+		businessRepo.AssertNotCalled(t, "ListFollowers", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("returns followers with display names", func(t *testing.T) {
+		businessRepo := new(mocks.MockBusinessRepository)
+		businessRepo.On("GetByID", mock.Anything, "biz-1").Return(testutil.CreateTestBusiness("biz-1", "owner-1", "Acme"), nil)
+		businessRepo.On("ListFollowers", mock.Anything, "biz-1", 20, 0).Return([]*models.BusinessFollowerResponse{
+			{UserID: "user-2", FirstName: ptrStr("Ahmad"), LastName: ptrStr("Karimi")},
+			{UserID: "user-3", FirstName: ptrStr("Zahra")},
+		}, 2, nil)
+		svc := newTestBusinessService(businessRepo, new(mocks.MockUserRepository))
+
+		got, total, err := svc.ListFollowers(context.Background(), "biz-1", "owner-1", 20, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		assert.Equal(t, "Ahmad Karimi", got[0].FullName)
+		assert.Equal(t, "Zahra", got[1].FullName)
+	})
+}
+
+func TestBusinessService_GetFollowerStats(t *testing.T) {
+	businessRepo := new(mocks.MockBusinessRepository)
+	businessRepo.On("GetByID", mock.Anything, "biz-1").Return(testutil.CreateTestBusiness("biz-1", "owner-1", "Acme"), nil)
+	businessRepo.On("GetFollowerProvinceCounts", mock.Anything, "biz-1").Return([]*models.ProvinceCount{
+		{Province: "Kabul", Count: 7},
+		{Province: "Herat", Count: 2},
+		{Province: "", Count: 1},
+	}, nil)
+	svc := newTestBusinessService(businessRepo, new(mocks.MockUserRepository))
+
+	stats, err := svc.GetFollowerStats(context.Background(), "biz-1", "owner-1")
+	require.NoError(t, err)
+	assert.Equal(t, 10, stats.TotalFollowers)
+	assert.Len(t, stats.ByProvince, 3)
+}
+
+func TestBusinessService_UpdateFollowerNotificationSettings(t *testing.T) {
+	t.Run("non-owner is rejected", func(t *testing.T) {
+		businessRepo := new(mocks.MockBusinessRepository)
+		businessRepo.On("GetByID", mock.Anything, "biz-1").Return(testutil.CreateTestBusiness("biz-1", "owner-1", "Acme"), nil)
+		svc := newTestBusinessService(businessRepo, new(mocks.MockUserRepository))
+
+		off := false
+		_, err := svc.UpdateFollowerNotificationSettings(context.Background(), "biz-1", "user-2",
+			&models.UpdateFollowerNotificationSettingsRequest{NotifyLowPriorityPosts: &off})
+		requireAppErrCode(t, err, http.StatusForbidden)
+	})
+
+	t.Run("owner turns low-priority notifications off", func(t *testing.T) {
+		businessRepo := new(mocks.MockBusinessRepository)
+		businessRepo.On("GetByID", mock.Anything, "biz-1").Return(testutil.CreateTestBusiness("biz-1", "owner-1", "Acme"), nil)
+		businessRepo.On("SetFollowerNotificationSettings", mock.Anything, "biz-1",
+			&models.BusinessFollowerNotificationSettings{NotifyLowPriorityPosts: false}).Return(nil)
+		svc := newTestBusinessService(businessRepo, new(mocks.MockUserRepository))
+
+		off := false
+		got, err := svc.UpdateFollowerNotificationSettings(context.Background(), "biz-1", "owner-1",
+			&models.UpdateFollowerNotificationSettingsRequest{NotifyLowPriorityPosts: &off})
+		require.NoError(t, err)
+		assert.False(t, got.NotifyLowPriorityPosts)
+		businessRepo.AssertExpectations(t)
+	})
+}
+
+func TestIsLowPriorityBusinessPost(t *testing.T) {
+	assert.True(t, models.IsLowPriorityBusinessPost(models.PostTypeFeed))
+	assert.True(t, models.IsLowPriorityBusinessPost(models.PostTypePull))
+	assert.False(t, models.IsLowPriorityBusinessPost(models.PostTypeEvent))
+}
@@ -0,0 +1,525 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// Business import limits. A file is imported businessImportRowsPerRun rows
+// per worker run so one huge directory can't hold the job lock for long;
+// the next run resumes after the last recorded row.
+const (
+	MaxBusinessImportRows    = 5000
+	businessImportRowsPerRun = 500
+	businessImportPreviewMax = 20
+	businessOwnerInviteTTL   = 30 * 24 * time.Hour
+)
+
+// businessImportColumns maps CreateBusinessRequest fields to the CSV columns
+// they are read from, for row errors.
+var businessImportColumns = map[string]string{
+	"Name":          "name",
+	"LicenseNo":     "license_no",
+	"Description":   "description",
+	"Address":       "address",
+	"PhoneNumber":   "phone_number",
+	"Email":         "email",
+	"Website":       "website",
+	"Country":       "country",
+	"Province":      "province",
+	"District":      "district",
+	"Neighborhood":  "neighborhood",
+	"CategoryNames": "categories",
+}
+
+// businessOwnerMailer sends the owner invite. Implemented by EmailService.
+type businessOwnerMailer interface {
+	SendBusinessOwnerInviteEmail(email, name, businessName, claimURL string) error
+}
+
+// BusinessImportService imports business directories (CSV) for admins. A
+// dry run validates the file and reports row-level errors; a real import
+// becomes a job the worker works through in the background. Each business
+// goes to the user registered with the row's owner_email; when there is
+// none, the importing admin holds it and the owner is emailed a link to
+// claim it once they sign up.
+type BusinessImportService struct {
+	repo            repositories.BusinessImportRepository
+	userRepo        repositories.UserRepository
+	businessService *BusinessService
+	mailer          businessOwnerMailer // optional; nil = no owner emails
+	claimBaseURL    string
+	logger          *zap.Logger
+}
+
+// NewBusinessImportService creates the import service. claimBaseURL is the
+// web page that posts claim tokens to /businesses/claim.
+func NewBusinessImportService(
+	repo repositories.BusinessImportRepository,
+	userRepo repositories.UserRepository,
+	businessService *BusinessService,
+	mailer businessOwnerMailer,
+	claimBaseURL string,
+	logger *zap.Logger,
+) *BusinessImportService {
+	return &BusinessImportService{
+		repo:            repo,
+		userRepo:        userRepo,
+		businessService: businessService,
+		mailer:          mailer,
+		claimBaseURL:    claimBaseURL,
+		logger:          logger,
+	}
+}
+
+// importRecord is one data row of a file: parsed when valid, otherwise
+// the reasons it will be skipped.
+type importRecord struct {
+	row  *models.BusinessImportRow
+	errs []models.BusinessImportRowError
+}
+
+// Preview validates the file without importing anything.
+func (s *BusinessImportService) Preview(ctx context.Context, data string) (*models.BusinessImportPreview, error) {
+	records, err := s.check(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	preview := &models.BusinessImportPreview{
+		TotalRows: len(records),
+		Errors:    []models.BusinessImportRowError{},
+		Rows:      []models.BusinessImportRow{},
+	}
+	for _, rec := range records {
+		if len(rec.errs) > 0 {
+			preview.Errors = append(preview.Errors, rec.errs...)
+			continue
+		}
+		preview.ValidRows++
+		if len(preview.Rows) < businessImportPreviewMax {
+			preview.Rows = append(preview.Rows, *rec.row)
+		}
+	}
+	return preview, nil
+}
+
+// StartImport validates the file and queues it for the worker. Invalid rows
+// are skipped and reported on the job; a file without a single valid row is
+// rejected.
+func (s *BusinessImportService) StartImport(ctx context.Context, adminID, fileName, data string) (*models.BusinessImportJob, error) {
+	preview, err := s.Preview(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	if preview.ValidRows == 0 {
+		return nil, utils.NewBadRequestError("The file has no valid rows to import", nil)
+	}
+	job := &models.BusinessImportJob{
+		ID:        uuid.New().String(),
+		CreatedBy: adminID,
+		FileName:  fileName,
+		Status:    models.BusinessImportStatusQueued,
+		TotalRows: preview.TotalRows,
+		RowErrors: []models.BusinessImportRowError{},
+		CSVData:   data,
+	}
+	if err := s.repo.CreateJob(ctx, job); err != nil {
+		s.logger.Error("Failed to create business import", zap.String("admin_id", adminID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to start import", err)
+	}
+	s.logger.Info("Business import queued",
+		zap.String("job_id", job.ID), zap.String("admin_id", adminID), zap.Int("rows", job.TotalRows))
+	return job, nil
+}
+
+// GetJob returns an import job's progress.
+func (s *BusinessImportService) GetJob(ctx context.Context, jobID string) (*models.BusinessImportJob, error) {
+	job, err := s.repo.GetJob(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrBusinessImportNotFound) {
+			return nil, utils.NewNotFoundError("Import not found", err)
+		}
+		return nil, utils.NewInternalError("Failed to get import", err)
+	}
+	return job, nil
+}
+
+// ListJobs lists import jobs, newest first.
+func (s *BusinessImportService) ListJobs(ctx context.Context, status *string, limit, offset int) ([]*models.BusinessImportJob, int, error) {
+	jobs, total, err := s.repo.ListJobs(ctx, status, limit, offset)
+	if err != nil {
+		s.logger.Error("Failed to list business imports", zap.Error(err))
+		return nil, 0, utils.NewInternalError("Failed to list imports", err)
+	}
+	return jobs, total, nil
+}
+
+// ProcessQueued imports queued files, oldest first, until there is nothing
+// left or the per-run row budget is spent. Every row's outcome is saved
+// before the next one, so an interrupted run resumes where it stopped.
+func (s *BusinessImportService) ProcessQueued(ctx context.Context) error {
+	budget := businessImportRowsPerRun
+	for budget > 0 {
+		job, err := s.repo.NextToProcess(ctx)
+		if err != nil {
+			return fmt.Errorf("next business import: %w", err)
+		}
+		if job == nil {
+			return nil
+		}
+		used, err := s.process(ctx, job, budget)
+		if err != nil {
+			return err
+		}
+		budget -= used
+	}
+	return nil
+}
+
+// process imports up to budget rows of the job and returns how many it
+// handled, finishing the job after its last row.
+func (s *BusinessImportService) process(ctx context.Context, job *models.BusinessImportJob, budget int) (int, error) {
+	records, err := s.check(ctx, job.CSVData)
+	if err != nil {
+		msg := err.Error()
+		s.logger.Warn("Business import failed", zap.String("job_id", job.ID), zap.Error(err))
+		return 0, s.repo.Finish(ctx, job.ID, models.BusinessImportStatusFailed, &msg)
+	}
+
+	used := 0
+	for job.ProcessedRows < len(records) && used < budget {
+		rec := records[job.ProcessedRows]
+		created, invited := false, false
+		rowErrs := rec.errs
+		if len(rowErrs) == 0 {
+			// A failed owner lookup means the database is unwell; stop and
+			// retry the row next run rather than failing it.
+			ownerID, err := s.resolveOwner(ctx, job, rec.row.OwnerEmail)
+			if err != nil {
+				return used, fmt.Errorf("business import %s owner: %w", job.ID, err)
+			}
+			created, invited, err = s.importRow(ctx, job, rec.row, ownerID)
+			if err != nil {
+				s.logger.Warn("Business import row failed",
+					zap.String("job_id", job.ID), zap.Int("row", rec.row.Row), zap.Error(err))
+				rowErrs = []models.BusinessImportRowError{{Row: rec.row.Row, Message: "Could not create the business"}}
+			}
+		}
+		if err := s.repo.RecordRow(ctx, job.ID, created, invited, rowErrs); err != nil {
+			return used, fmt.Errorf("business import %s progress: %w", job.ID, err)
+		}
+		job.ProcessedRows++
+		used++
+	}
+	if job.ProcessedRows >= len(records) {
+		if err := s.repo.Finish(ctx, job.ID, models.BusinessImportStatusCompleted, nil); err != nil {
+			return used, fmt.Errorf("business import %s finish: %w", job.ID, err)
+		}
+		s.logger.Info("Business import completed", zap.String("job_id", job.ID), zap.Int("rows", len(records)))
+	}
+	return used, nil
+}
+
+// resolveOwner returns the user registered with email, or the importing
+// admin when there is none.
+func (s *BusinessImportService) resolveOwner(ctx context.Context, job *models.BusinessImportJob, email string) (string, error) {
+	owner, err := s.userRepo.GetByEmail(ctx, email)
+	switch {
+	case err == nil && owner != nil:
+		return owner.ID, nil
+	case err == nil || errors.Is(err, repositories.ErrNotFound):
+		return job.CreatedBy, nil
+	default:
+		return "", err
+	}
+}
+
+// importRow creates one business for ownerID. When that is the importing
+// admin, the owner named in the row gets an invite to claim it.
+func (s *BusinessImportService) importRow(ctx context.Context, job *models.BusinessImportJob, row *models.BusinessImportRow, ownerID string) (created, invited bool, err error) {
+	business, err := s.businessService.CreateBusiness(ctx, ownerID, &row.Business)
+	if err != nil {
+		return false, false, err
+	}
+	if ownerID != job.CreatedBy {
+		s.sendOwnerEmail(row, business.Name, "")
+		return true, false, nil
+	}
+
+	token, err := newBusinessClaimToken()
+	if err != nil {
+		return true, false, nil
+	}
+	jobID := job.ID
+	invite := &models.BusinessOwnerInvite{
+		ID:          uuid.New().String(),
+		BusinessID:  business.ID,
+		ImportJobID: &jobID,
+		Email:       row.OwnerEmail,
+		TokenHash:   hashToken(token),
+		ExpiresAt:   time.Now().Add(businessOwnerInviteTTL),
+	}
+	if err := s.repo.CreateInvite(ctx, invite); err != nil {
+		s.logger.Warn("Failed to create business owner invite", zap.String("business_id", business.ID), zap.Error(err))
+		return true, false, nil
+	}
+	s.sendOwnerEmail(row, business.Name, s.claimURL(token))
+	return true, true, nil
+}
+
+func (s *BusinessImportService) sendOwnerEmail(row *models.BusinessImportRow, businessName, claimURL string) {
+	if s.mailer == nil {
+		return
+	}
+	if err := s.mailer.SendBusinessOwnerInviteEmail(row.OwnerEmail, row.OwnerName, businessName, claimURL); err != nil {
+		s.logger.Warn("Failed to send business owner email", zap.Int("row", row.Row), zap.Error(err))
+	}
+}
+
+func (s *BusinessImportService) claimURL(token string) string {
+	base := strings.TrimRight(s.claimBaseURL, "/")
+	if base == "" {
+		base = "https://hamsaya.com"
+	}
+	return base + "/business/claim?token=" + url.QueryEscape(token)
+}
+
+func newBusinessClaimToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// ClaimBusiness hands an imported business to the owner it was sent to.
+// The signed-in user's verified email must be the one the invite went to.
+func (s *BusinessImportService) ClaimBusiness(ctx context.Context, userID, token string) (*models.BusinessResponse, error) {
+	invite, err := s.repo.GetInviteByTokenHash(ctx, hashToken(strings.TrimSpace(token)))
+	if err != nil {
+		if errors.Is(err, repositories.ErrBusinessInviteNotFound) {
+			return nil, utils.NewNotFoundError("Invite not found", err)
+		}
+		return nil, utils.NewInternalError("Failed to claim business", err)
+	}
+	if invite.ClaimedAt != nil {
+		return nil, utils.NewConflictError("This business was already claimed", nil)
+	}
+	if time.Now().After(invite.ExpiresAt) {
+		return nil, utils.NewBadRequestError("This invite has expired", nil)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		return nil, utils.NewNotFoundError("User not found", err)
+	}
+	if !strings.EqualFold(user.Email, invite.Email) {
+		return nil, utils.NewForbiddenError("This invite was sent to a different email address", nil)
+	}
+	if !user.EmailVerified {
+		return nil, utils.NewForbiddenError("Verify your email address to claim this business", nil)
+	}
+
+	if err := s.repo.ClaimInvite(ctx, invite, userID); err != nil {
+		if errors.Is(err, repositories.ErrBusinessInviteClaimed) {
+			return nil, utils.NewConflictError("This business was already claimed", err)
+		}
+		s.logger.Error("Failed to claim business", zap.String("business_id", invite.BusinessID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to claim business", err)
+	}
+	s.businessService.invalidateBusinessCache(ctx, invite.BusinessID)
+	s.logger.Info("Imported business claimed",
+		zap.String("business_id", invite.BusinessID), zap.String("user_id", userID))
+	return s.businessService.GetBusiness(ctx, invite.BusinessID, &userID)
+}
+
+// check parses and validates every data row of the file. Errors for the
+// file as a whole (unreadable, missing columns, too many rows) are returned
+// as a bad request.
+func (s *BusinessImportService) check(ctx context.Context, data string) ([]importRecord, error) {
+	records, err := parseBusinessImport(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var licenses []string
+	for _, rec := range records {
+		if rec.row != nil && rec.row.Business.LicenseNo != nil {
+			licenses = append(licenses, *rec.row.Business.LicenseNo)
+		}
+	}
+	taken, err := s.repo.ExistingLicenses(ctx, licenses)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to check the file", err)
+	}
+	for i := range records {
+		rec := &records[i]
+		if rec.row != nil && rec.row.Business.LicenseNo != nil && taken[strings.ToLower(*rec.row.Business.LicenseNo)] {
+			rec.errs = append(rec.errs, models.BusinessImportRowError{
+				Row: rec.row.Row, Field: "license_no", Message: "A business with this license number already exists",
+			})
+			rec.row = nil
+		}
+	}
+	return records, nil
+}
+
+// parseBusinessImport reads the CSV. The header names the columns (any
+// order, case-insensitive); name and owner_email are required, categories
+// holds category names separated by ";".
+func parseBusinessImport(data string) ([]importRecord, error) {
+	r := csv.NewReader(strings.NewReader(strings.TrimPrefix(data, "\ufeff")))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, utils.NewBadRequestError("The file is empty or not a valid CSV", err)
+	}
+	cols := make(map[string]int, len(header))
+	for i, h := range header {
+		cols[strings.ReplaceAll(strings.ToLower(strings.TrimSpace(h)), " ", "_")] = i
+	}
+	for _, required := range []string{"name", "owner_email"} {
+		if _, ok := cols[required]; !ok {
+			return nil, utils.NewBadRequestError("The file has no "+required+" column", nil)
+		}
+	}
+
+	var records []importRecord
+	seenNames := make(map[string]int)
+	seenLicenses := make(map[string]int)
+	for {
+		fields, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, utils.NewBadRequestError("The file is not a valid CSV: "+err.Error(), err)
+		}
+		if len(records) == MaxBusinessImportRows {
+			return nil, utils.NewBadRequestError(
+				fmt.Sprintf("The file has more than %d rows; split it into smaller files", MaxBusinessImportRows), nil)
+		}
+		line, _ := r.FieldPos(0)
+		rec := parseBusinessImportRow(line, cols, fields)
+		if rec.row != nil {
+			key := strings.ToLower(rec.row.Business.Name) + "|" + rec.row.OwnerEmail
+			if first, dup := seenNames[key]; dup {
+				rec = duplicateImportRow(line, "name", first)
+			} else if l := rec.row.Business.LicenseNo; l != nil && seenLicenses[strings.ToLower(*l)] > 0 {
+				rec = duplicateImportRow(line, "license_no", seenLicenses[strings.ToLower(*l)])
+			} else {
+				seenNames[key] = line
+				if l != nil {
+					seenLicenses[strings.ToLower(*l)] = line
+				}
+			}
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+func duplicateImportRow(line int, field string, first int) importRecord {
+	return importRecord{errs: []models.BusinessImportRowError{{
+		Row: line, Field: field, Message: fmt.Sprintf("Duplicate of row %d", first),
+	}}}
+}
+
+// parseBusinessImportRow turns one CSV record into a create request.
+func parseBusinessImportRow(line int, cols map[string]int, fields []string) importRecord {
+	get := func(col string) string {
+		if i, ok := cols[col]; ok && i < len(fields) {
+			return strings.TrimSpace(fields[i])
+		}
+		return ""
+	}
+	opt := func(col string) *string {
+		if v := get(col); v != "" {
+			return &v
+		}
+		return nil
+	}
+
+	var errs []models.BusinessImportRowError
+	fail := func(field, msg string) {
+		errs = append(errs, models.BusinessImportRowError{Row: line, Field: field, Message: msg})
+	}
+
+	row := &models.BusinessImportRow{
+		Row:        line,
+		OwnerEmail: strings.ToLower(get("owner_email")),
+		OwnerName:  get("owner_name"),
+		Business: models.CreateBusinessRequest{
+			Name:         get("name"),
+			LicenseNo:    opt("license_no"),
+			Description:  opt("description"),
+			Address:      opt("address"),
+			PhoneNumber:  opt("phone_number"),
+			Email:        opt("email"),
+			Website:      opt("website"),
+			Country:      opt("country"),
+			Province:     opt("province"),
+			District:     opt("district"),
+			Neighborhood: opt("neighborhood"),
+		},
+	}
+	if err := utils.ValidateVar(row.OwnerEmail, "required,email"); err != nil {
+		fail("owner_email", "owner_email must be a valid email address")
+	}
+	for _, name := range strings.Split(get("categories"), ";") {
+		if name = strings.TrimSpace(name); name != "" {
+			row.Business.CategoryNames = append(row.Business.CategoryNames, name)
+		}
+	}
+
+	lat, lng := get("latitude"), get("longitude")
+	if lat != "" || lng != "" {
+		latV, latErr := strconv.ParseFloat(lat, 64)
+		lngV, lngErr := strconv.ParseFloat(lng, 64)
+		switch {
+		case latErr != nil || latV < -90 || latV > 90:
+			fail("latitude", "latitude must be a number between -90 and 90")
+		case lngErr != nil || lngV < -180 || lngV > 180:
+			fail("longitude", "longitude must be a number between -180 and 180")
+		default:
+			row.Business.Latitude, row.Business.Longitude = &latV, &lngV
+		}
+	}
+
+	fieldErrs := utils.ValidateStructAll(&row.Business)
+	fieldNames := make([]string, 0, len(fieldErrs))
+	for field := range fieldErrs {
+		fieldNames = append(fieldNames, field)
+	}
+	sort.Strings(fieldNames)
+	for _, field := range fieldNames {
+		col, ok := businessImportColumns[field]
+		if !ok {
+			col = strings.ToLower(field)
+		}
+		fail(col, col+strings.TrimPrefix(fieldErrs[field], field))
+	}
+
+	if len(errs) > 0 {
+		return importRecord{errs: errs}
+	}
+	return importRecord{row: row}
+}
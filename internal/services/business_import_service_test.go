@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeOwnerMailer struct {
+	sent []fakeOwnerEmail
+}
+
+type fakeOwnerEmail struct {
+	email, businessName, claimURL string
+}
+
+func (m *fakeOwnerMailer) SendBusinessOwnerInviteEmail(email, name, businessName, claimURL string) error {
+	m.sent = append(m.sent, fakeOwnerEmail{email, businessName, claimURL})
+	return nil
+}
+
+const testImportCSV = "\ufeffName,Owner Email,License_No,Categories,Latitude,Longitude\n" +
+	"Kabul Bakery,baker@example.com,LIC-1,Bakery; Food,34.5,69.2\n" +
+	",nobody@example.com,,,,\n" +
+	"Herat Tailor,not-an-email,,,,\n" +
+	"Mazar Books,books@example.com,LIC-2,,95,\n" +
+	"Kabul Bakery,BAKER@example.com,,,,\n" +
+	"Old Pharmacy,pharma@example.com,lic-9,,,\n"
+
+func TestParseBusinessImport(t *testing.T) {
+	records, err := parseBusinessImport(testImportCSV)
+	require.NoError(t, err)
+	require.Len(t, records, 6)
+
+	first := records[0].row
+	require.NotNil(t, first)
+	assert.Equal(t, 2, first.Row, "rows are spreadsheet lines, header first")
+	assert.Equal(t, "Kabul Bakery", first.Business.Name)
+	assert.Equal(t, []string{"Bakery", "Food"}, first.Business.CategoryNames)
+	require.NotNil(t, first.Business.Latitude)
+	assert.Equal(t, 34.5, *first.Business.Latitude)
+
+	assert.Equal(t, "name", records[1].errs[0].Field)
+	assert.Equal(t, "owner_email", records[2].errs[0].Field)
+	assert.Equal(t, "latitude", records[3].errs[0].Field)
+	assert.Equal(t, "Duplicate of row 2", records[4].errs[0].Message, "same name and owner, any case")
+	assert.Equal(t, 7, records[5].row.Row)
+
+	_, err = parseBusinessImport("name,phone\nShop,123\n")
+	requireAppErrorCode(t, err, http.StatusBadRequest)
+}
+
+func TestBusinessImportService_Preview(t *testing.T) {
+	ctx := context.Background()
+	repo := new(mocks.MockBusinessImportRepository)
+	repo.On("ExistingLicenses", ctx, []string{"LIC-1", "lic-9"}).Return(map[string]bool{"lic-9": true}, nil)
+	svc := NewBusinessImportService(repo, nil, nil, nil, "", zap.NewNop())
+
+	preview, err := svc.Preview(ctx, testImportCSV)
+	require.NoError(t, err)
+	assert.Equal(t, 6, preview.TotalRows)
+	assert.Equal(t, 1, preview.ValidRows)
+	require.Len(t, preview.Rows, 1)
+	assert.Equal(t, "Kabul Bakery", preview.Rows[0].Business.Name)
+	require.Len(t, preview.Errors, 5)
+	assert.Equal(t, models.BusinessImportRowError{
+		Row: 7, Field: "license_no", Message: "A business with this license number already exists",
+	}, preview.Errors[4])
+}
+
+func TestBusinessImportService_ProcessQueued(t *testing.T) {
+	ctx := context.Background()
+	csv := "name,owner_email,owner_name\n" +
+		"Kabul Bakery,baker@example.com,Ali\n" +
+		"Herat Tailor,tailor@example.com,\n" +
+		"X,bad\n"
+	job := &models.BusinessImportJob{ID: "job-1", CreatedBy: "admin-1", Status: models.BusinessImportStatusQueued, CSVData: csv}
+
+	repo := new(mocks.MockBusinessImportRepository)
+	repo.On("NextToProcess", ctx).Return(job, nil).Once()
+	repo.On("NextToProcess", ctx).Return(nil, nil).Once()
+	repo.On("ExistingLicenses", ctx, []string(nil)).Return(map[string]bool{}, nil)
+	repo.On("RecordRow", ctx, "job-1", true, false, []models.BusinessImportRowError(nil)).Return(nil).Once()
+	repo.On("RecordRow", ctx, "job-1", true, true, []models.BusinessImportRowError(nil)).Return(nil).Once()
+	repo.On("RecordRow", ctx, "job-1", false, false, mock.MatchedBy(func(errs []models.BusinessImportRowError) bool {
+		return len(errs) == 2 && errs[0].Row == 4
+	})).Return(nil).Once()
+	repo.On("CreateInvite", ctx, mock.MatchedBy(func(inv *models.BusinessOwnerInvite) bool {
+		return inv.Email == "tailor@example.com" && inv.TokenHash != "" && *inv.ImportJobID == "job-1"
+	})).Return(nil).Once()
+	repo.On("Finish", ctx, "job-1", models.BusinessImportStatusCompleted, (*string)(nil)).Return(nil).Once()
+
+	userRepo := new(mocks.MockUserRepository)
+	userRepo.On("GetByEmail", ctx, "baker@example.com").Return(testutil.CreateTestUser("owner-1", "baker@example.com"), nil)
+	userRepo.On("GetByEmail", ctx, "tailor@example.com").Return(nil, repositories.ErrNotFound)
+
+	businessRepo := new(mocks.MockBusinessRepository)
+	businessRepo.On("Create", mock.Anything, mock.MatchedBy(func(b *models.BusinessProfile) bool {
+		return b.Name == "Kabul Bakery" && b.UserID == "owner-1"
+	})).Return(nil).Once()
+	businessRepo.On("Create", mock.Anything, mock.MatchedBy(func(b *models.BusinessProfile) bool {
+		return b.Name == "Herat Tailor" && b.UserID == "admin-1"
+	})).Return(nil).Once()
+	created := testutil.CreateTestBusiness("biz-1", "owner-1", "Kabul Bakery")
+	created.Status = true
+	businessRepo.On("GetByID", mock.Anything, mock.Anything).Return(created, nil)
+	businessRepo.On("GetCategoriesByBusinessID", mock.Anything, mock.Anything).Return([]*models.BusinessCategory{}, nil)
+	businessRepo.On("GetHoursByBusinessID", mock.Anything, mock.Anything).Return([]*models.BusinessHours{}, nil)
+	businessRepo.On("GetAttachmentsByBusinessID", mock.Anything, mock.Anything).Return([]*models.BusinessAttachment{}, nil)
+	businessRepo.On("IsFollowing", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+
+	mailer := &fakeOwnerMailer{}
+	svc := NewBusinessImportService(repo, userRepo, newTestBusinessService(businessRepo, userRepo), mailer, "https://web.example", zap.NewNop())
+
+	require.NoError(t, svc.ProcessQueued(ctx))
+	repo.AssertExpectations(t)
+	businessRepo.AssertExpectations(t)
+
+	require.Len(t, mailer.sent, 2)
+	assert.Empty(t, mailer.sent[0].claimURL, "registered owners just get the business")
+	assert.Equal(t, "tailor@example.com", mailer.sent[1].email)
+	assert.Contains(t, mailer.sent[1].claimURL, "https://web.example/business/claim?token=")
+}
+
+func TestBusinessImportService_ClaimBusiness(t *testing.T) {
+	ctx := context.Background()
+	invite := &models.BusinessOwnerInvite{
+		ID: "inv-1", BusinessID: "biz-1", Email: "tailor@example.com", ExpiresAt: time.Now().Add(time.Hour),
+	}
+	repo := new(mocks.MockBusinessImportRepository)
+	repo.On("GetInviteByTokenHash", ctx, hashToken("tok")).Return(invite, nil)
+	repo.On("GetInviteByTokenHash", ctx, hashToken("bad")).Return(nil, repositories.ErrBusinessInviteNotFound)
+	repo.On("ClaimInvite", ctx, invite, "user-2").Return(nil).Once()
+
+	userRepo := new(mocks.MockUserRepository)
+	userRepo.On("GetByID", ctx, "user-1").Return(testutil.CreateTestUser("user-1", "someone@example.com"), nil)
+	tailor := testutil.CreateTestUser("user-2", "Tailor@example.com")
+	tailor.EmailVerified = true
+	userRepo.On("GetByID", ctx, "user-2").Return(tailor, nil)
+
+	businessRepo := new(mocks.MockBusinessRepository)
+	businessRepo.On("GetByID", mock.Anything, "biz-1").Return(testutil.CreateTestBusiness("biz-1", "user-2", "Herat Tailor"), nil)
+	businessRepo.On("GetCategoriesByBusinessID", mock.Anything, mock.Anything).Return([]*models.BusinessCategory{}, nil)
+	businessRepo.On("GetHoursByBusinessID", mock.Anything, mock.Anything).Return([]*models.BusinessHours{}, nil)
+	businessRepo.On("GetAttachmentsByBusinessID", mock.Anything, mock.Anything).Return([]*models.BusinessAttachment{}, nil)
+	businessRepo.On("IsFollowing", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+	svc := NewBusinessImportService(repo, userRepo, newTestBusinessService(businessRepo, userRepo), nil, "", zap.NewNop())
+
+	_, err := svc.ClaimBusiness(ctx, "user-1", "bad")
+	requireAppErrorCode(t, err, http.StatusNotFound)
+
+	_, err = svc.ClaimBusiness(ctx, "user-1", "tok")
+	requireAppErrorCode(t, err, http.StatusForbidden)
+
+	business, err := svc.ClaimBusiness(ctx, "user-2", "tok")
+	require.NoError(t, err)
+	assert.Equal(t, "user-2", business.UserID)
+	repo.AssertExpectations(t)
+}
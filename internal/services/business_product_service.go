@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// BusinessProductService manages a business's product catalog. Writes are
+// limited to the business owner; reads are public, with discontinued items
+// hidden from everyone but the owner.
+type BusinessProductService struct {
+	productRepo  repositories.BusinessProductRepository
+	businessRepo repositories.BusinessRepository
+	access       *AccessControl
+	logger       *zap.Logger
+}
+
+// NewBusinessProductService wires the product service.
+func NewBusinessProductService(
+	productRepo repositories.BusinessProductRepository,
+	businessRepo repositories.BusinessRepository,
+	logger *zap.Logger,
+) *BusinessProductService {
+	return &BusinessProductService{
+		productRepo:  productRepo,
+		businessRepo: businessRepo,
+		access:       NewAccessControl(businessRepo),
+		logger:       logger,
+	}
+}
+
+// getForBusiness loads a product and 404s when it belongs to another business,
+// so product ids can't be probed through an unrelated business's routes.
+func (s *BusinessProductService) getForBusiness(ctx context.Context, businessID, productID string) (*models.BusinessProduct, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if errors.Is(err, repositories.ErrProductNotFound) || (err == nil && product.BusinessID != businessID) {
+		return nil, utils.NewNotFoundError("Product not found", err)
+	}
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load product", err)
+	}
+	return product, nil
+}
+
+// Create adds a product to the business catalog.
+func (s *BusinessProductService) Create(ctx context.Context, businessID, userID string, req *models.CreateBusinessProductRequest) (*models.BusinessProduct, error) {
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return nil, err
+	}
+
+	product := &models.BusinessProduct{
+		ID:           uuid.NewString(),
+		BusinessID:   businessID,
+		Name:         req.Name,
+		Description:  req.Description,
+		Price:        req.Price,
+		Currency:     req.Currency,
+		Photos:       req.Photos,
+		Availability: req.Availability,
+	}
+	if product.Availability == "" {
+		product.Availability = models.ProductInStock
+	}
+	if req.Position != nil {
+		product.Position = *req.Position
+	}
+	if err := s.productRepo.Create(ctx, product); err != nil {
+		s.logger.Error("Failed to create product", zap.Error(err), zap.String("business_id", businessID))
+		return nil, utils.NewInternalError("Failed to create product", err)
+	}
+	return product, nil
+}
+
+// Update edits a product. Only the business owner may edit.
+func (s *BusinessProductService) Update(ctx context.Context, businessID, productID, userID string, req *models.UpdateBusinessProductRequest) (*models.BusinessProduct, error) {
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return nil, err
+	}
+	product, err := s.getForBusiness(ctx, businessID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		product.Name = *req.Name
+	}
+	if req.Description != nil {
+		product.Description = req.Description
+	}
+	if req.Price != nil {
+		product.Price = req.Price
+	}
+	if req.Currency != nil {
+		product.Currency = req.Currency
+	}
+	if req.Photos != nil {
+		product.Photos = *req.Photos
+	}
+	if req.Availability != nil {
+		product.Availability = *req.Availability
+	}
+	if req.Position != nil {
+		product.Position = *req.Position
+	}
+
+	if err := s.productRepo.Update(ctx, product); err != nil {
+		if errors.Is(err, repositories.ErrProductNotFound) {
+			return nil, utils.NewNotFoundError("Product not found", err)
+		}
+		return nil, utils.NewInternalError("Failed to update product", err)
+	}
+	return product, nil
+}
+
+// Delete removes a product from the catalog. Posts and messages that
+// reference it stop showing the product card.
+func (s *BusinessProductService) Delete(ctx context.Context, businessID, productID, userID string) error {
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return err
+	}
+	if _, err := s.getForBusiness(ctx, businessID, productID); err != nil {
+		return err
+	}
+	if err := s.productRepo.Delete(ctx, productID); err != nil {
+		if errors.Is(err, repositories.ErrProductNotFound) {
+			return utils.NewNotFoundError("Product not found", err)
+		}
+		return utils.NewInternalError("Failed to delete product", err)
+	}
+	return nil
+}
+
+// Get returns a single product of a business.
+func (s *BusinessProductService) Get(ctx context.Context, businessID, productID string) (*models.BusinessProduct, error) {
+	return s.getForBusiness(ctx, businessID, productID)
+}
+
+// List returns paginated products + total for a business. The owner also
+// sees discontinued products so they can re-stock or delete them.
+func (s *BusinessProductService) List(ctx context.Context, businessID, viewerID string, limit, offset int) ([]*models.BusinessProduct, int, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	includeDiscontinued := false
+	if viewerID != "" {
+		if business, err := s.businessRepo.GetByID(ctx, businessID); err == nil && business.UserID == viewerID {
+			includeDiscontinued = true
+		}
+	}
+
+	products, total, err := s.productRepo.ListByBusiness(ctx, businessID, includeDiscontinued, limit, offset)
+	if err != nil {
+		return nil, 0, utils.NewInternalError("Failed to load products", err)
+	}
+	return products, total, nil
+}
+
+// ResolveAttachment validates a product being attached to a post or message.
+// When businessID is set (a business post, or a business-scoped chat) the
+// product must belong to that business.
+func (s *BusinessProductService) ResolveAttachment(ctx context.Context, productID string, businessID *string) (*models.BusinessProduct, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if errors.Is(err, repositories.ErrProductNotFound) {
+		return nil, utils.NewBadRequestError("Attached product not found", err)
+	}
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load product", err)
+	}
+	if businessID != nil && *businessID != "" && product.BusinessID != *businessID {
+		return nil, utils.NewBadRequestError("Attached product belongs to a different business", nil)
+	}
+	return product, nil
+}
+
+// ProductsForPosts returns the attached product per post ID for enrichment.
+func (s *BusinessProductService) ProductsForPosts(ctx context.Context, postIDs []string) (map[string]*models.BusinessProduct, error) {
+	return s.productRepo.GetByPostIDs(ctx, postIDs)
+}
+
+// GetProduct returns a live product by ID regardless of business, or nil.
+// Used to render the product card on chat messages.
+func (s *BusinessProductService) GetProduct(ctx context.Context, productID string) *models.BusinessProduct {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil
+	}
+	return product
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestProductService(
+	productRepo *mocks.MockBusinessProductRepository,
+	businessRepo *mocks.MockBusinessRepository,
+) *BusinessProductService {
+	return NewBusinessProductService(productRepo, businessRepo, zap.NewNop())
+}
+
+func requireAppErrCode(t *testing.T, err error, code int) {
+	t.Helper()
+	require.Error(t, err)
+	appErr, ok := err.(*utils.AppError)
+	require.True(t, ok, "expected *utils.AppError, got %T", err)
+	assert.Equal(t, code, appErr.Code)
+}
+
+func TestBusinessProductService_Create(t *testing.T) {
+	t.Run("non-owner forbidden", func(t *testing.T) {
+		productRepo := &mocks.MockBusinessProductRepository{}
+		businessRepo := &mocks.MockBusinessRepository{}
+		businessRepo.On("GetByID", mock.Anything, "biz-1").
+			Return(&models.BusinessProfile{ID: "biz-1", UserID: "owner-1"}, nil)
+
+		svc := newTestProductService(productRepo, businessRepo)
+		got, err := svc.Create(context.Background(), "biz-1", "user-2", &models.CreateBusinessProductRequest{Name: "Rice"})
+
+		requireAppErrCode(t, err, http.StatusForbidden)
+		assert.Nil(t, got)
+		productRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("defaults availability to in stock", func(t *testing.T) {
+		productRepo := &mocks.MockBusinessProductRepository{}
+		businessRepo := &mocks.MockBusinessRepository{}
+		businessRepo.On("GetByID", mock.Anything, "biz-1").
+			Return(&models.BusinessProfile{ID: "biz-1", UserID: "owner-1"}, nil)
+		productRepo.On("Create", mock.Anything, mock.MatchedBy(func(p *models.BusinessProduct) bool {
+			return p.BusinessID == "biz-1" && p.Name == "Rice" && p.Availability == models.ProductInStock && p.ID != ""
+		})).Return(nil)
+
+		svc := newTestProductService(productRepo, businessRepo)
+		got, err := svc.Create(context.Background(), "biz-1", "owner-1", &models.CreateBusinessProductRequest{Name: "Rice"})
+
+		require.NoError(t, err)
+		assert.Equal(t, models.ProductInStock, got.Availability)
+		productRepo.AssertExpectations(t)
+	})
+}
+
+func TestBusinessProductService_Update(t *testing.T) {
+	t.Run("product of another business is not found", func(t *testing.T) {
+		productRepo := &mocks.MockBusinessProductRepository{}
+		businessRepo := &mocks.MockBusinessRepository{}
+		businessRepo.On("GetByID", mock.Anything, "biz-1").
+			Return(&models.BusinessProfile{ID: "biz-1", UserID: "owner-1"}, nil)
+		productRepo.On("GetByID", mock.Anything, "prod-1").
+			Return(&models.BusinessProduct{ID: "prod-1", BusinessID: "biz-2"}, nil)
+
+		svc := newTestProductService(productRepo, businessRepo)
+		name := "New"
+		_, err := svc.Update(context.Background(), "biz-1", "prod-1", "owner-1", &models.UpdateBusinessProductRequest{Name: &name})
+
+		requireAppErrCode(t, err, http.StatusNotFound)
+		productRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("applies only provided fields", func(t *testing.T) {
+		productRepo := &mocks.MockBusinessProductRepository{}
+		businessRepo := &mocks.MockBusinessRepository{}
+		businessRepo.On("GetByID", mock.Anything, "biz-1").
+			Return(&models.BusinessProfile{ID: "biz-1", UserID: "owner-1"}, nil)
+		price := 120.0
+		productRepo.On("GetByID", mock.Anything, "prod-1").
+			Return(&models.BusinessProduct{ID: "prod-1", BusinessID: "biz-1", Name: "Rice", Price: &price, Availability: models.ProductInStock}, nil)
+		productRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.BusinessProduct")).Return(nil)
+
+		svc := newTestProductService(productRepo, businessRepo)
+		out := models.ProductOutOfStock
+		got, err := svc.Update(context.Background(), "biz-1", "prod-1", "owner-1", &models.UpdateBusinessProductRequest{Availability: &out})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Rice", got.Name)
+		assert.Equal(t, &price, got.Price)
+		assert.Equal(t, models.ProductOutOfStock, got.Availability)
+	})
+}
+
+func TestBusinessProductService_List(t *testing.T) {
+	t.Run("public view hides discontinued", func(t *testing.T) {
+		productRepo := &mocks.MockBusinessProductRepository{}
+		businessRepo := &mocks.MockBusinessRepository{}
+		productRepo.On("ListByBusiness", mock.Anything, "biz-1", false, 20, 0).
+			Return([]*models.BusinessProduct{}, 0, nil)
+
+		svc := newTestProductService(productRepo, businessRepo)
+		_, _, err := svc.List(context.Background(), "biz-1", "", 0, -5)
+
+		require.NoError(t, err)
+		productRepo.AssertExpectations(t)
+		businessRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("owner sees discontinued", func(t *testing.T) {
+		productRepo := &mocks.MockBusinessProductRepository{}
+		businessRepo := &mocks.MockBusinessRepository{}
+		businessRepo.On("GetByID", mock.Anything, "biz-1").
+			Return(&models.BusinessProfile{ID: "biz-1", UserID: "owner-1"}, nil)
+		productRepo.On("ListByBusiness", mock.Anything, "biz-1", true, 50, 10).
+			Return([]*models.BusinessProduct{{ID: "prod-1"}}, 1, nil)
+
+		svc := newTestProductService(productRepo, businessRepo)
+		got, total, err := svc.List(context.Background(), "biz-1", "owner-1", 50, 10)
+
+		require.NoError(t, err)
+		assert.Len(t, got, 1)
+		assert.Equal(t, 1, total)
+		productRepo.AssertExpectations(t)
+	})
+}
+
+func TestBusinessProductService_ResolveAttachment(t *testing.T) {
+	productRepo := &mocks.MockBusinessProductRepository{}
+	productRepo.On("GetByID", mock.Anything, "prod-1").
+		Return(&models.BusinessProduct{ID: "prod-1", BusinessID: "biz-1"}, nil)
+	productRepo.On("GetByID", mock.Anything, "gone").
+		Return(nil, repositories.ErrProductNotFound)
+	productRepo.On("GetByID", mock.Anything, "broken").
+		Return(nil, errors.New("db down"))
+	svc := newTestProductService(productRepo, &mocks.MockBusinessRepository{})
+	ctx := context.Background()
+
+	_, err := svc.ResolveAttachment(ctx, "prod-1", ptrStr("biz-1"))
+	assert.NoError(t, err)
+
+	_, err = svc.ResolveAttachment(ctx, "prod-1", nil)
+	assert.NoError(t, err, "personal posts and chats may share any product")
+
+	_, err = svc.ResolveAttachment(ctx, "prod-1", ptrStr("biz-2"))
+	requireAppErrCode(t, err, http.StatusBadRequest)
+
+	_, err = svc.ResolveAttachment(ctx, "gone", nil)
+	requireAppErrCode(t, err, http.StatusBadRequest)
+
+	_, err = svc.ResolveAttachment(ctx, "broken", nil)
+	requireAppErrCode(t, err, http.StatusInternalServerError)
+}
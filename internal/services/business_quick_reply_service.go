@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/hamsaya/backend/pkg/events"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxQuickReplies caps the saved replies per business; the composer
+	// shows them all in one sheet.
+	maxQuickReplies = 50
+	// autoReplyCooldown is how long after an away message the same
+	// conversation gets another one.
+	autoReplyCooldown = 12 * time.Hour
+)
+
+// BusinessQuickReplyService manages a business's saved chat replies and
+// its away message. Everything here is owner-only; customers only ever see
+// the replies once sent.
+type BusinessQuickReplyService struct {
+	replyRepo    repositories.BusinessQuickReplyRepository
+	businessRepo repositories.BusinessRepository
+	access       *AccessControl
+	logger       *zap.Logger
+}
+
+// NewBusinessQuickReplyService wires the quick reply service.
+func NewBusinessQuickReplyService(
+	replyRepo repositories.BusinessQuickReplyRepository,
+	businessRepo repositories.BusinessRepository,
+	logger *zap.Logger,
+) *BusinessQuickReplyService {
+	return &BusinessQuickReplyService{
+		replyRepo:    replyRepo,
+		businessRepo: businessRepo,
+		access:       NewAccessControl(businessRepo),
+		logger:       logger,
+	}
+}
+
+// getForBusiness loads a quick reply and 404s when it belongs to another
+// business.
+func (s *BusinessQuickReplyService) getForBusiness(ctx context.Context, businessID, replyID string) (*models.BusinessQuickReply, error) {
+	reply, err := s.replyRepo.GetByID(ctx, replyID)
+	if errors.Is(err, repositories.ErrQuickReplyNotFound) || (err == nil && reply.BusinessID != businessID) {
+		return nil, utils.NewNotFoundError("Quick reply not found", err)
+	}
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load quick reply", err)
+	}
+	return reply, nil
+}
+
+// List returns the business's quick replies for the chat composer.
+func (s *BusinessQuickReplyService) List(ctx context.Context, businessID, userID string) ([]*models.BusinessQuickReply, error) {
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return nil, err
+	}
+	replies, err := s.replyRepo.ListByBusiness(ctx, businessID)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load quick replies", err)
+	}
+	return replies, nil
+}
+
+// Create saves a new quick reply.
+func (s *BusinessQuickReplyService) Create(ctx context.Context, businessID, userID string, req *models.CreateQuickReplyRequest) (*models.BusinessQuickReply, error) {
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return nil, err
+	}
+	count, err := s.replyRepo.CountByBusiness(ctx, businessID)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to create quick reply", err)
+	}
+	if count >= maxQuickReplies {
+		return nil, utils.NewBadRequestError("A business can save up to 50 quick replies", nil)
+	}
+
+	reply := &models.BusinessQuickReply{
+		ID:         uuid.NewString(),
+		BusinessID: businessID,
+		Title:      strings.TrimSpace(req.Title),
+		Body:       req.Body,
+	}
+	if req.Position != nil {
+		reply.Position = *req.Position
+	}
+	if err := s.replyRepo.Create(ctx, reply); err != nil {
+		s.logger.Error("Failed to create quick reply", zap.Error(err), zap.String("business_id", businessID))
+		return nil, utils.NewInternalError("Failed to create quick reply", err)
+	}
+	return reply, nil
+}
+
+// Update edits a quick reply.
+func (s *BusinessQuickReplyService) Update(ctx context.Context, businessID, replyID, userID string, req *models.UpdateQuickReplyRequest) (*models.BusinessQuickReply, error) {
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return nil, err
+	}
+	reply, err := s.getForBusiness(ctx, businessID, replyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		reply.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Body != nil {
+		reply.Body = *req.Body
+	}
+	if req.Position != nil {
+		reply.Position = *req.Position
+	}
+
+	if err := s.replyRepo.Update(ctx, reply); err != nil {
+		if errors.Is(err, repositories.ErrQuickReplyNotFound) {
+			return nil, utils.NewNotFoundError("Quick reply not found", err)
+		}
+		return nil, utils.NewInternalError("Failed to update quick reply", err)
+	}
+	return reply, nil
+}
+
+// Delete removes a quick reply.
+func (s *BusinessQuickReplyService) Delete(ctx context.Context, businessID, replyID, userID string) error {
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return err
+	}
+	if _, err := s.getForBusiness(ctx, businessID, replyID); err != nil {
+		return err
+	}
+	if err := s.replyRepo.Delete(ctx, replyID); err != nil {
+		if errors.Is(err, repositories.ErrQuickReplyNotFound) {
+			return utils.NewNotFoundError("Quick reply not found", err)
+		}
+		return utils.NewInternalError("Failed to delete quick reply", err)
+	}
+	return nil
+}
+
+// GetAutoReply returns the business's away message settings.
+func (s *BusinessQuickReplyService) GetAutoReply(ctx context.Context, businessID, userID string) (*models.BusinessAutoReply, error) {
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return nil, err
+	}
+	reply, err := s.replyRepo.GetAutoReply(ctx, businessID)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load auto-reply", err)
+	}
+	return reply, nil
+}
+
+// SetAutoReply turns the away message on or off. It only goes out while
+// the business is closed, so it needs business hours to have any effect.
+func (s *BusinessQuickReplyService) SetAutoReply(ctx context.Context, businessID, userID string, req *models.SetAutoReplyRequest) (*models.BusinessAutoReply, error) {
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return nil, err
+	}
+	message := strings.TrimSpace(req.Message)
+	if req.Enabled && message == "" {
+		return nil, utils.NewBadRequestError("An auto-reply needs a message", nil)
+	}
+
+	reply := &models.BusinessAutoReply{BusinessID: businessID, Enabled: req.Enabled, Message: message}
+	if err := s.replyRepo.SetAutoReply(ctx, reply); err != nil {
+		s.logger.Error("Failed to set auto-reply", zap.Error(err), zap.String("business_id", businessID))
+		return nil, utils.NewInternalError("Failed to save auto-reply", err)
+	}
+	return reply, nil
+}
+
+// SubscribeAutoReplies answers customers who write to a business while it
+// is closed with the business's away message, sent by chat as the owner. A
+// conversation gets it at most once per autoReplyCooldown.
+func (s *BusinessQuickReplyService) SubscribeAutoReplies(bus *events.Bus, chat *ChatService) {
+	events.Subscribe(bus, func(ctx context.Context, e MessageSent) {
+		s.autoReply(ctx, chat, e, time.Now())
+	})
+}
+
+func (s *BusinessQuickReplyService) autoReply(ctx context.Context, chat *ChatService, e MessageSent, now time.Time) {
+	convo := e.Conversation
+	if convo == nil || convo.BusinessID == nil || *convo.BusinessID == "" || e.Message == nil {
+		return
+	}
+	business, err := s.businessRepo.GetByID(ctx, *convo.BusinessID)
+	if err != nil || business == nil || business.UserID != e.RecipientID {
+		// Messages the owner sends, including the away message itself.
+		return
+	}
+
+	reply, err := s.replyRepo.GetAutoReply(ctx, business.ID)
+	if err != nil || !reply.Enabled || reply.Message == "" {
+		return
+	}
+	hours, err := s.businessRepo.GetHoursByBusinessID(ctx, business.ID)
+	if err != nil {
+		s.logger.Warn("Failed to load business hours for auto-reply", zap.String("business_id", business.ID), zap.Error(err))
+		return
+	}
+	if open, known := businessOpenAt(hours, now); open || !known {
+		return
+	}
+
+	claimed, err := s.replyRepo.ClaimAutoReply(ctx, convo.ID, autoReplyCooldown)
+	if err != nil || !claimed {
+		return
+	}
+	message := reply.Message
+	if _, err := chat.SendMessage(ctx, business.UserID, &models.SendMessageRequest{
+		RecipientID: e.Message.SenderID,
+		Content:     &message,
+		MessageType: models.MessageTypeText,
+		BusinessID:  &business.ID,
+	}); err != nil {
+		s.logger.Warn("Failed to send auto-reply",
+			zap.String("business_id", business.ID),
+			zap.String("conversation_id", convo.ID),
+			zap.Error(err),
+		)
+	}
+}
+
+// businessOpenAt reports whether the business is open at t (Kabul time).
+// known is false when the business never set its hours. A day without an
+// entry counts as closed; a closing time at or before the opening time runs
+// past midnight.
+func businessOpenAt(hours []*models.BusinessHours, t time.Time) (open, known bool) {
+	if len(hours) == 0 {
+		return false, false
+	}
+	local := t.In(bookingLocation())
+	minute := local.Hour()*60 + local.Minute()
+	today := local.Weekday().String()
+	yesterday := local.AddDate(0, 0, -1).Weekday().String()
+
+	for _, h := range hours {
+		if h.IsClosed || h.OpenTime == nil || h.CloseTime == nil {
+			continue
+		}
+		opens := h.OpenTime.Hour()*60 + h.OpenTime.Minute()
+		closes := h.CloseTime.Hour()*60 + h.CloseTime.Minute()
+		switch {
+		case h.Day == today && closes > opens:
+			if minute >= opens && minute < closes {
+				return true, true
+			}
+		case h.Day == today:
+			if minute >= opens {
+				return true, true
+			}
+		case h.Day == yesterday && closes <= opens:
+			if minute < closes {
+				return true, true
+			}
+		}
+	}
+	return false, true
+}
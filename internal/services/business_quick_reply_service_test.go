@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestQuickReplyService(t *testing.T) (*BusinessQuickReplyService, *mocks.MockBusinessQuickReplyRepository, *mocks.MockBusinessRepository) {
+	t.Helper()
+	replyRepo := new(mocks.MockBusinessQuickReplyRepository)
+	bizRepo := new(mocks.MockBusinessRepository)
+	bizRepo.On("GetByID", mock.Anything, "biz-1").
+		Return(&models.BusinessProfile{ID: "biz-1", UserID: "owner-1", Name: "Kabul Bakery"}, nil).Maybe()
+	return NewBusinessQuickReplyService(replyRepo, bizRepo, zap.NewNop()), replyRepo, bizRepo
+}
+
+func requireAppErrorCode(t *testing.T, err error, code int) {
+	t.Helper()
+	var appErr *utils.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, code, appErr.Code)
+}
+
+func TestBusinessQuickReplyService_Create(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("owner only", func(t *testing.T) {
+		svc, replyRepo, _ := newTestQuickReplyService(t)
+		_, err := svc.Create(ctx, "biz-1", "someone-else", &models.CreateQuickReplyRequest{Title: "Hours", Body: "9-5"})
+		requireAppErrorCode(t, err, http.StatusForbidden)
+		replyRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("capped per business", func(t *testing.T) {
+		svc, replyRepo, _ := newTestQuickReplyService(t)
+		replyRepo.On("CountByBusiness", ctx, "biz-1").Return(maxQuickReplies, nil)
+		_, err := svc.Create(ctx, "biz-1", "owner-1", &models.CreateQuickReplyRequest{Title: "Hours", Body: "9-5"})
+		requireAppErrorCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("saves", func(t *testing.T) {
+		svc, replyRepo, _ := newTestQuickReplyService(t)
+		replyRepo.On("CountByBusiness", ctx, "biz-1").Return(3, nil)
+		replyRepo.On("Create", ctx, mock.MatchedBy(func(r *models.BusinessQuickReply) bool {
+			return r.BusinessID == "biz-1" && r.Title == "Hours" && r.Body == "Open 9 to 5"
+		})).Return(nil)
+		reply, err := svc.Create(ctx, "biz-1", "owner-1", &models.CreateQuickReplyRequest{Title: " Hours ", Body: "Open 9 to 5"})
+		require.NoError(t, err)
+		assert.NotEmpty(t, reply.ID)
+	})
+}
+
+func TestBusinessQuickReplyService_UpdateOtherBusiness(t *testing.T) {
+	ctx := context.Background()
+	svc, replyRepo, _ := newTestQuickReplyService(t)
+	replyRepo.On("GetByID", ctx, "reply-1").Return(&models.BusinessQuickReply{ID: "reply-1", BusinessID: "biz-2"}, nil)
+
+	title := "Changed"
+	_, err := svc.Update(ctx, "biz-1", "reply-1", "owner-1", &models.UpdateQuickReplyRequest{Title: &title})
+	requireAppErrorCode(t, err, http.StatusNotFound)
+	replyRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestBusinessQuickReplyService_SetAutoReplyNeedsMessage(t *testing.T) {
+	svc, _, _ := newTestQuickReplyService(t)
+	_, err := svc.SetAutoReply(context.Background(), "biz-1", "owner-1", &models.SetAutoReplyRequest{Enabled: true, Message: "  "})
+	requireAppErrorCode(t, err, http.StatusBadRequest)
+}
+
+func hoursAt(day, opens, closes string) *models.BusinessHours {
+	o, _ := time.Parse("15:04", opens)
+	c, _ := time.Parse("15:04", closes)
+	return &models.BusinessHours{Day: day, OpenTime: &o, CloseTime: &c}
+}
+
+func TestBusinessOpenAt(t *testing.T) {
+	kabul := bookingLocation()
+	// 2026-10-14 is a Wednesday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, kabul)
+	}
+	hours := []*models.BusinessHours{
+		hoursAt("Wednesday", "09:00", "17:00"),
+		hoursAt("Thursday", "20:00", "02:00"),
+		{Day: "Friday", IsClosed: true},
+	}
+
+	cases := []struct {
+		name string
+		t    time.Time
+		open bool
+	}{
+		{"inside hours", at(14, 10, 0), true},
+		{"before opening", at(14, 8, 59), false},
+		{"at closing", at(14, 17, 0), false},
+		{"overnight, evening", at(15, 23, 0), true},
+		{"overnight, after midnight", at(16, 1, 30), true},
+		{"overnight, after close", at(16, 2, 0), false},
+		{"closed day", at(16, 12, 0), false},
+		{"day without hours", at(17, 12, 0), false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			open, known := businessOpenAt(hours, tc.t)
+			assert.True(t, known)
+			assert.Equal(t, tc.open, open)
+		})
+	}
+
+	_, known := businessOpenAt(nil, at(14, 10, 0))
+	assert.False(t, known, "no hours set")
+}
+
+func TestBusinessQuickReplyService_AutoReply(t *testing.T) {
+	ctx := context.Background()
+	bizID := "biz-1"
+	closedNow := time.Date(2026, 10, 14, 20, 0, 0, 0, bookingLocation()) // Wednesday evening
+	openNow := time.Date(2026, 10, 14, 10, 0, 0, 0, bookingLocation())
+
+	newEvent := func(senderID, recipientID string) MessageSent {
+		conv := newTestConversation("conv-1")
+		conv.BusinessID = &bizID
+		return MessageSent{
+			Message:      newTestMessage("msg-1", "conv-1", senderID),
+			Conversation: conv,
+			RecipientID:  recipientID,
+		}
+	}
+	setup := func(t *testing.T) (*BusinessQuickReplyService, *mocks.MockBusinessQuickReplyRepository, *mocks.MockBusinessRepository) {
+		svc, replyRepo, bizRepo := newTestQuickReplyService(t)
+		replyRepo.On("GetAutoReply", mock.Anything, bizID).
+			Return(&models.BusinessAutoReply{BusinessID: bizID, Enabled: true, Message: "We're closed, back at 9"}, nil).Maybe()
+		bizRepo.On("GetHoursByBusinessID", mock.Anything, bizID).
+			Return([]*models.BusinessHours{hoursAt("Wednesday", "09:00", "17:00")}, nil).Maybe()
+		return svc, replyRepo, bizRepo
+	}
+
+	t.Run("sends the away message while closed", func(t *testing.T) {
+		svc, replyRepo, bizRepo := setup(t)
+		replyRepo.On("ClaimAutoReply", mock.Anything, "conv-1", autoReplyCooldown).Return(true, nil)
+
+		convRepo := &mocks.MockConversationRepository{}
+		msgRepo := &mocks.MockMessageRepository{}
+		conv := newTestConversation("conv-1")
+		conv.BusinessID = &bizID
+		convRepo.On("GetOrCreate", mock.Anything, "owner-1", "customer-1", &bizID).Return(conv, nil)
+		msgRepo.On("Create", mock.Anything, mock.MatchedBy(func(m *models.Message) bool {
+			return m.SenderID == "owner-1" && *m.Content == "We're closed, back at 9"
+		})).Return(nil)
+		convRepo.On("UpdateLastMessageAt", mock.Anything, "conv-1").Return(nil)
+		msgRepo.On("GetReactions", mock.Anything, mock.Anything, mock.Anything).Return(map[string][]models.MessageReaction{}, nil).Maybe()
+		chat := NewChatService(convRepo, msgRepo, new(mocks.MockUserRepository), bizRepo, nil, nil, nil, zap.NewNop())
+
+		svc.autoReply(ctx, chat, newEvent("customer-1", "owner-1"), closedNow)
+		msgRepo.AssertExpectations(t)
+	})
+
+	t.Run("not while open", func(t *testing.T) {
+		svc, replyRepo, _ := setup(t)
+		svc.autoReply(ctx, nil, newEvent("customer-1", "owner-1"), openNow)
+		replyRepo.AssertNotCalled(t, "ClaimAutoReply", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("not for the owner's own messages", func(t *testing.T) {
+		svc, replyRepo, _ := setup(t)
+		svc.autoReply(ctx, nil, newEvent("owner-1", "customer-1"), closedNow)
+		replyRepo.AssertNotCalled(t, "GetAutoReply", mock.Anything, mock.Anything)
+	})
+
+	t.Run("once per cooldown", func(t *testing.T) {
+		svc, replyRepo, _ := setup(t)
+		replyRepo.On("ClaimAutoReply", mock.Anything, "conv-1", autoReplyCooldown).Return(false, nil)
+		// A nil chat service would panic if the reply were sent.
+		svc.autoReply(ctx, nil, newEvent("customer-1", "owner-1"), closedNow)
+		replyRepo.AssertExpectations(t)
+	})
+}
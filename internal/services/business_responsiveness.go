@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"go.uber.org/zap"
+)
+
+const (
+	// responseStatsWindow is how far back the nightly rollup looks.
+	responseStatsWindow = 30 * 24 * time.Hour
+	// minResponseSamples is how many answered turns a business needs
+	// before its median is shown; a handful of chats says little.
+	minResponseSamples = 5
+	// slowResponseThreshold is the usual reply time past which customers
+	// are told a reply may take a while, even while the business is open.
+	slowResponseThreshold = time.Hour
+)
+
+// BusinessResponsivenessService tells customers what to expect before they
+// write to a business: whether it is open now (from its business hours) and
+// how quickly it usually replies (from the nightly rollup).
+//
+// A nil *BusinessResponsivenessService reports nothing, so services work
+// unwired.
+type BusinessResponsivenessService struct {
+	statsRepo    repositories.BusinessResponseStatsRepository
+	businessRepo repositories.BusinessRepository
+	logger       *zap.Logger
+
+	// now is swapped in tests.
+	now func() time.Time
+}
+
+// NewBusinessResponsivenessService wires the responsiveness service.
+func NewBusinessResponsivenessService(
+	statsRepo repositories.BusinessResponseStatsRepository,
+	businessRepo repositories.BusinessRepository,
+	logger *zap.Logger,
+) *BusinessResponsivenessService {
+	return &BusinessResponsivenessService{
+		statsRepo:    statsRepo,
+		businessRepo: businessRepo,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// For returns the business's responsiveness right now. Lookup failures are
+// logged and leave the matching field unset rather than failing the page.
+func (s *BusinessResponsivenessService) For(ctx context.Context, businessID string) *models.BusinessResponsiveness {
+	if s == nil {
+		return nil
+	}
+	hours, err := s.businessRepo.GetHoursByBusinessID(ctx, businessID)
+	if err != nil {
+		s.logger.Warn("Failed to load business hours for responsiveness", zap.String("business_id", businessID), zap.Error(err))
+		hours = nil
+	}
+	stats, err := s.statsRepo.Get(ctx, businessID)
+	if err != nil {
+		s.logger.Warn("Failed to load business response stats", zap.String("business_id", businessID), zap.Error(err))
+		stats = nil
+	}
+	return responsiveness(hours, stats, s.now())
+}
+
+// responsiveness combines business hours and response stats at now.
+func responsiveness(hours []*models.BusinessHours, stats *models.BusinessResponseStats, now time.Time) *models.BusinessResponsiveness {
+	r := &models.BusinessResponsiveness{}
+	if open, known := businessOpenAt(hours, now); known {
+		r.IsOpen = &open
+		r.MayBeDelayed = !open
+	}
+	if stats != nil && stats.SampleSize >= minResponseSamples {
+		median := stats.MedianResponseSeconds
+		r.MedianResponseSeconds = &median
+		if time.Duration(median)*time.Second > slowResponseThreshold {
+			r.MayBeDelayed = true
+		}
+	}
+	return r
+}
+
+// RunRollup recomputes every business's median response time over the
+// last responseStatsWindow. Runs nightly.
+func (s *BusinessResponsivenessService) RunRollup(ctx context.Context) error {
+	count, err := s.statsRepo.Rollup(ctx, s.now().Add(-responseStatsWindow))
+	if err != nil {
+		return fmt.Errorf("business response stats rollup: %w", err)
+	}
+	s.logger.Info("Business response stats rollup completed", zap.Int64("businesses", count))
+	return nil
+}
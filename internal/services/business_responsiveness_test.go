@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestResponsiveness(t *testing.T) {
+	clock := func(h int) *time.Time {
+		v := time.Date(0, 1, 1, h, 0, 0, 0, time.UTC)
+		return &v
+	}
+	// Every day 08:00-17:00 Kabul time.
+	var hours []*models.BusinessHours
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		hours = append(hours, &models.BusinessHours{Day: d.String(), OpenTime: clock(8), CloseTime: clock(17)})
+	}
+	openNow := time.Date(2026, 3, 10, 10, 0, 0, 0, bookingLocation())
+	closedNow := time.Date(2026, 3, 10, 22, 0, 0, 0, bookingLocation())
+	quick := &models.BusinessResponseStats{MedianResponseSeconds: 600, SampleSize: 12}
+
+	t.Run("open and quick", func(t *testing.T) {
+		r := responsiveness(hours, quick, openNow)
+		require.NotNil(t, r.IsOpen)
+		assert.True(t, *r.IsOpen)
+		require.NotNil(t, r.MedianResponseSeconds)
+		assert.Equal(t, 600, *r.MedianResponseSeconds)
+		assert.False(t, r.MayBeDelayed)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		r := responsiveness(hours, quick, closedNow)
+		require.NotNil(t, r.IsOpen)
+		assert.False(t, *r.IsOpen)
+		assert.True(t, r.MayBeDelayed)
+	})
+
+	t.Run("open but usually slow", func(t *testing.T) {
+		r := responsiveness(hours, &models.BusinessResponseStats{MedianResponseSeconds: 3 * 3600, SampleSize: 12}, openNow)
+		assert.True(t, r.MayBeDelayed)
+	})
+
+	t.Run("too few replies and no hours", func(t *testing.T) {
+		r := responsiveness(nil, &models.BusinessResponseStats{MedianResponseSeconds: 3 * 3600, SampleSize: 2}, openNow)
+		assert.Nil(t, r.IsOpen)
+		assert.Nil(t, r.MedianResponseSeconds)
+		assert.False(t, r.MayBeDelayed)
+	})
+}
+
+func TestBusinessResponsivenessService_For(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("nil service reports nothing", func(t *testing.T) {
+		var svc *BusinessResponsivenessService
+		assert.Nil(t, svc.For(ctx, "biz-1"))
+	})
+
+	t.Run("lookup failures leave fields unset", func(t *testing.T) {
+		statsRepo := new(mocks.MockBusinessResponseStatsRepository)
+		businessRepo := new(mocks.MockBusinessRepository)
+		businessRepo.On("GetHoursByBusinessID", mock.Anything, "biz-1").Return(nil, errors.New("db down"))
+		statsRepo.On("Get", mock.Anything, "biz-1").Return(nil, errors.New("db down"))
+
+		r := NewBusinessResponsivenessService(statsRepo, businessRepo, zap.NewNop()).For(ctx, "biz-1")
+		require.NotNil(t, r)
+		assert.Nil(t, r.IsOpen)
+		assert.Nil(t, r.MedianResponseSeconds)
+	})
+}
+
+func TestBusinessResponsivenessService_RunRollup(t *testing.T) {
+	statsRepo := new(mocks.MockBusinessResponseStatsRepository)
+	svc := NewBusinessResponsivenessService(statsRepo, nil, zap.NewNop())
+	now := time.Date(2026, 3, 10, 2, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	statsRepo.On("Rollup", mock.Anything, now.Add(-30*24*time.Hour)).Return(int64(7), nil)
+	require.NoError(t, svc.RunRollup(context.Background()))
+	statsRepo.AssertExpectations(t)
+}
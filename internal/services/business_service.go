@@ -2,9 +2,9 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"hash/fnv"
-	"math"
 	"strconv"
 	"strings"
 	"sync"
@@ -16,6 +16,7 @@ import (
 	"github.com/hamsaya/backend/internal/utils"
 	"github.com/hamsaya/backend/pkg/bgtasks"
 	"github.com/hamsaya/backend/pkg/cache"
+	"github.com/hamsaya/backend/pkg/events"
 	"github.com/hamsaya/backend/pkg/geocoding"
 	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
@@ -36,10 +37,26 @@ type BusinessService struct {
 	businessRepo        repositories.BusinessRepository
 	userRepo            repositories.UserRepository
 	notificationService *NotificationService
+	access              *AccessControl
 	logger              *zap.Logger
 	cache               *cache.Cache // optional; nil = no caching
+	events              *events.Bus
+	featuredPosts       featuredPostLoader
+	maxGalleryImages    int                  // 0 = defaultMaxGalleryImages
+	mediaReview         *ProfileMediaService // optional; nil = new images go live right away
+	responsiveness      *BusinessResponsivenessService
+	profileViews        *ProfileViewService // optional; nil = no viewer list
 }
 
+// featuredPostLoader renders post ids as viewer-specific post responses,
+// keeping their order. Implemented by PostService.
+type featuredPostLoader interface {
+	GetPostsInOrder(ctx context.Context, postIDs []string, viewerID *string) []*models.PostResponse
+}
+
+// maxFeaturedPosts caps the posts a business can pin to its profile.
+const maxFeaturedPosts = 6
+
 // NewBusinessService creates a new business service
 func NewBusinessService(
 	businessRepo repositories.BusinessRepository,
@@ -51,6 +68,7 @@ func NewBusinessService(
 		businessRepo:        businessRepo,
 		userRepo:            userRepo,
 		notificationService: notificationService,
+		access:              NewAccessControl(businessRepo),
 		logger:              logger,
 	}
 }
@@ -62,6 +80,73 @@ func (s *BusinessService) WithCache(c *cache.Cache) *BusinessService {
 	return s
 }
 
+// WithEvents publishes BusinessMediaUploaded for new business images on bus.
+func (s *BusinessService) WithEvents(bus *events.Bus) *BusinessService {
+	s.events = bus
+	return s
+}
+
+// WithFeaturedPosts adds the featured posts section to GetBusiness.
+func (s *BusinessService) WithFeaturedPosts(loader featuredPostLoader) *BusinessService {
+	s.featuredPosts = loader
+	return s
+}
+
+// WithResponsiveness adds open-now and usual reply time to GetBusiness.
+func (s *BusinessService) WithResponsiveness(responsiveness *BusinessResponsivenessService) *BusinessService {
+	s.responsiveness = responsiveness
+	return s
+}
+
+// WithProfileViews records signed-in visitors for the owner's viewer list
+// and the unique-viewer insights.
+func (s *BusinessService) WithProfileViews(profileViews *ProfileViewService) *BusinessService {
+	s.profileViews = profileViews
+	return s
+}
+
+// WithGalleryLimit caps the images a business can add to its gallery.
+// n <= 0 keeps the default of 10.
+func (s *BusinessService) WithGalleryLimit(n int) *BusinessService {
+	s.maxGalleryImages = n
+	return s
+}
+
+// WithMediaReview holds new avatars and covers for review when the review
+// service is in hold mode.
+func (s *BusinessService) WithMediaReview(mediaReview *ProfileMediaService) *BusinessService {
+	s.mediaReview = mediaReview
+	return s
+}
+
+// MediaHeldForReview reports whether new avatars and covers wait for
+// review before they are shown.
+func (s *BusinessService) MediaHeldForReview() bool {
+	return s.mediaReview.Holds()
+}
+
+// recordView bumps the business's view count and, for signed-in viewers,
+// notes them for the viewer list. Runs in the background.
+func (s *BusinessService) recordView(businessID string, viewerID *string) {
+	bgtasks.Submit(func(taskCtx context.Context) {
+		_ = s.businessRepo.IncrementViews(taskCtx, businessID)
+		if viewerID != nil {
+			s.profileViews.RecordBusinessView(taskCtx, businessID, *viewerID)
+		}
+	})
+}
+
+// ListViewers returns who recently viewed the business (owner only).
+func (s *BusinessService) ListViewers(ctx context.Context, businessID, userID string, days, limit, offset int) (*models.BusinessViewersResponse, error) {
+	if s.profileViews == nil {
+		if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+			return nil, err
+		}
+		return &models.BusinessViewersResponse{Items: []*models.BusinessViewer{}, Limit: limit, Offset: offset}, nil
+	}
+	return s.profileViews.BusinessViewers(ctx, businessID, userID, days, limit, offset)
+}
+
 // businessCacheKey produces a per-viewer key. Anonymous viewers share
 // the same cached payload ("anon"); authenticated viewers each get their
 // own slot because the enriched response includes per-viewer fields
@@ -191,9 +276,16 @@ func (s *BusinessService) CreateBusiness(ctx context.Context, userID string, req
 	return resp, nil
 }
 
-// GetBusiness gets a business profile by ID.
-// If status is false (not visible to others), only the owner can view; others get 404.
+// GetBusiness gets a business profile by ID, with the first page of its
+// gallery.
 func (s *BusinessService) GetBusiness(ctx context.Context, businessID string, viewerID *string) (*models.BusinessResponse, error) {
+	return s.GetBusinessWithGalleryPage(ctx, businessID, viewerID, 1, DefaultGalleryPageSize)
+}
+
+// GetBusinessWithGalleryPage gets a business profile by ID with the given
+// page of its gallery.
+// If status is false (not visible to others), only the owner can view; others get 404.
+func (s *BusinessService) GetBusinessWithGalleryPage(ctx context.Context, businessID string, viewerID *string, galleryPage, galleryLimit int) (*models.BusinessResponse, error) {
 	cacheKey := businessCacheKey(businessID, viewerID)
 
 	// Try cache first. A hit still triggers the async view increment for
@@ -211,10 +303,11 @@ func (s *BusinessService) GetBusiness(ctx context.Context, businessID string, vi
 			}
 			isOwnerCached := viewerID != nil && *viewerID == cached.UserID
 			if !isOwnerCached {
-				bgtasks.Submit(func(taskCtx context.Context) {
-					_ = s.businessRepo.IncrementViews(taskCtx, businessID)
-				})
+				s.recordView(businessID, viewerID)
 			}
+			pageGallery(&cached, galleryPage, galleryLimit)
+			cached.FeaturedPosts = s.loadFeaturedPosts(ctx, businessID, viewerID)
+			cached.Responsiveness = s.responsiveness.For(ctx, businessID)
 			return &cached, nil
 		}
 	}
@@ -240,9 +333,7 @@ func (s *BusinessService) GetBusiness(ctx context.Context, businessID string, vi
 	// context would cancel mid-write whenever the client disconnects.
 	isOwner := viewerID != nil && *viewerID == business.UserID
 	if !isOwner {
-		bgtasks.Submit(func(taskCtx context.Context) {
-			_ = s.businessRepo.IncrementViews(taskCtx, businessID)
-		})
+		s.recordView(businessID, viewerID)
 	}
 
 	// Enrich business
@@ -250,12 +341,127 @@ func (s *BusinessService) GetBusiness(ctx context.Context, businessID string, vi
 	if err != nil {
 		return nil, err
 	}
+	// The whole gallery is cached with the profile (it's capped at
+	// maxGalleryImages) and paged per request; gallery edits bust the cache.
+	resp.Gallery = s.loadGallery(ctx, businessID)
 	if s.cache != nil && resp != nil {
 		_ = s.cache.Set(ctx, cacheKey, resp, businessProfileTTL)
 	}
+	pageGallery(resp, galleryPage, galleryLimit)
+	// Featured posts stay out of the cache: they carry per-post state
+	// (likes, edits, deletions) that the business cache isn't busted for.
+	// Open-now changes by the minute, so it stays out too.
+	resp.FeaturedPosts = s.loadFeaturedPosts(ctx, businessID, viewerID)
+	resp.Responsiveness = s.responsiveness.For(ctx, businessID)
 	return resp, nil
 }
 
+func (s *BusinessService) loadFeaturedPosts(ctx context.Context, businessID string, viewerID *string) []*models.PostResponse {
+	if s.featuredPosts == nil {
+		return nil
+	}
+	ids, err := s.businessRepo.GetFeaturedPostIDs(ctx, businessID)
+	if err != nil {
+		s.logger.Warn("Failed to load featured posts", zap.String("business_id", businessID), zap.Error(err))
+		return nil
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return s.featuredPosts.GetPostsInOrder(ctx, ids, viewerID)
+}
+
+// GetFeaturedPosts returns the business's featured posts as the viewer
+// sees them.
+func (s *BusinessService) GetFeaturedPosts(ctx context.Context, businessID string, viewerID *string) ([]*models.PostResponse, error) {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil || (!business.Status && (viewerID == nil || *viewerID != business.UserID)) {
+		return nil, utils.NewNotFoundError("Business not found", err)
+	}
+	posts := s.loadFeaturedPosts(ctx, businessID, viewerID)
+	if posts == nil {
+		posts = []*models.PostResponse{}
+	}
+	return posts, nil
+}
+
+// FeaturePost pins one of the business's posts to its profile, after the
+// ones already featured.
+func (s *BusinessService) FeaturePost(ctx context.Context, businessID, userID, postID string) error {
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return err
+	}
+	ids, err := s.businessRepo.GetFeaturedPostIDs(ctx, businessID)
+	if err != nil {
+		return utils.NewInternalError("Failed to feature post", err)
+	}
+	for _, id := range ids {
+		if id == postID {
+			return nil
+		}
+	}
+	if len(ids) >= maxFeaturedPosts {
+		return utils.NewBadRequestError(fmt.Sprintf("A business can feature up to %d posts", maxFeaturedPosts), nil)
+	}
+
+	if err := s.businessRepo.AddFeaturedPost(ctx, businessID, postID); err != nil {
+		if errors.Is(err, repositories.ErrFeaturedPostNotFound) {
+			return utils.NewNotFoundError("Post not found on this business", err)
+		}
+		s.logger.Error("Failed to feature post", zap.String("business_id", businessID), zap.String("post_id", postID), zap.Error(err))
+		return utils.NewInternalError("Failed to feature post", err)
+	}
+	return nil
+}
+
+// UnfeaturePost removes a post from the business's featured section.
+func (s *BusinessService) UnfeaturePost(ctx context.Context, businessID, userID, postID string) error {
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return err
+	}
+	if err := s.businessRepo.RemoveFeaturedPost(ctx, businessID, postID); err != nil {
+		return utils.NewInternalError("Failed to unfeature post", err)
+	}
+	return nil
+}
+
+// ReorderFeaturedPosts sets the featured order. postIDs must list exactly
+// the currently featured posts.
+func (s *BusinessService) ReorderFeaturedPosts(ctx context.Context, businessID, userID string, postIDs []string) error {
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return err
+	}
+	current, err := s.businessRepo.GetFeaturedPostIDs(ctx, businessID)
+	if err != nil {
+		return utils.NewInternalError("Failed to reorder featured posts", err)
+	}
+	if !sameIDSet(current, postIDs) {
+		return utils.NewBadRequestError("post_ids must list every featured post exactly once", nil)
+	}
+	if err := s.businessRepo.ReorderFeaturedPosts(ctx, businessID, postIDs); err != nil {
+		return utils.NewInternalError("Failed to reorder featured posts", err)
+	}
+	return nil
+}
+
+// sameIDSet reports whether a and b hold the same ids, each once.
+func sameIDSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]bool, len(a))
+	for _, id := range a {
+		seen[id] = true
+	}
+	for _, id := range b {
+		if !seen[id] {
+			return false
+		}
+		delete(seen, id)
+	}
+	return true
+}
+
 // GetUserBusinesses gets all businesses for a user
 func (s *BusinessService) GetUserBusinesses(ctx context.Context, userID string, limit, offset int) ([]*models.BusinessResponse, error) {
 	// Get businesses
@@ -281,15 +487,9 @@ func (s *BusinessService) GetUserBusinesses(ctx context.Context, userID string,
 
 // UpdateBusiness updates a business profile
 func (s *BusinessService) UpdateBusiness(ctx context.Context, businessID, userID string, req *models.UpdateBusinessRequest) (*models.BusinessResponse, error) {
-	// Get existing business
-	business, err := s.businessRepo.GetByID(ctx, businessID)
+	business, err := s.access.RequireBusinessOwner(ctx, businessID, userID)
 	if err != nil {
-		return nil, utils.NewNotFoundError("Business not found", err)
-	}
-
-	// Check ownership
-	if business.UserID != userID {
-		return nil, utils.NewUnauthorizedError("You don't have permission to update this business", nil)
+		return nil, err
 	}
 
 	// Update fields
@@ -338,6 +538,12 @@ func (s *BusinessService) UpdateBusiness(ctx context.Context, businessID, userID
 	if req.AvatarColor != nil {
 		business.AvatarColor = req.AvatarColor
 	}
+	if req.AvatarAltText != nil && business.Avatar != nil {
+		business.Avatar.AltText = strings.TrimSpace(*req.AvatarAltText)
+	}
+	if req.CoverAltText != nil && business.Cover != nil {
+		business.Cover.AltText = strings.TrimSpace(*req.CoverAltText)
+	}
 
 	// Handle location update
 	if req.Latitude != nil && req.Longitude != nil {
@@ -424,15 +630,8 @@ func (s *BusinessService) UpdateBusiness(ctx context.Context, businessID, userID
 
 // DeleteBusiness soft deletes a business profile
 func (s *BusinessService) DeleteBusiness(ctx context.Context, businessID, userID string) error {
-	// Get existing business
-	business, err := s.businessRepo.GetByID(ctx, businessID)
-	if err != nil {
-		return utils.NewNotFoundError("Business not found", err)
-	}
-
-	// Check ownership
-	if business.UserID != userID {
-		return utils.NewUnauthorizedError("You don't have permission to delete this business", nil)
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return err
 	}
 
 	// Delete business
@@ -448,15 +647,8 @@ func (s *BusinessService) DeleteBusiness(ctx context.Context, businessID, userID
 
 // SetBusinessHours sets operating hours for a business
 func (s *BusinessService) SetBusinessHours(ctx context.Context, businessID, userID string, req *models.SetBusinessHoursRequest) error {
-	// Get existing business
-	business, err := s.businessRepo.GetByID(ctx, businessID)
-	if err != nil {
-		return utils.NewNotFoundError("Business not found", err)
-	}
-
-	// Check ownership
-	if business.UserID != userID {
-		return utils.NewUnauthorizedError("You don't have permission to update this business", nil)
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return err
 	}
 
 	// Delete existing hours
@@ -507,20 +699,20 @@ func (s *BusinessService) SetBusinessHours(ctx context.Context, businessID, user
 }
 
 // UploadAvatar uploads a business avatar
-func (s *BusinessService) UploadAvatar(ctx context.Context, businessID, userID, photoURL string) error {
-	// Get existing business
-	business, err := s.businessRepo.GetByID(ctx, businessID)
+func (s *BusinessService) UploadAvatar(ctx context.Context, businessID, userID, photoURL, altText string) error {
+	business, err := s.access.RequireBusinessOwner(ctx, businessID, userID)
 	if err != nil {
-		return utils.NewNotFoundError("Business not found", err)
+		return err
 	}
 
-	// Check ownership
-	if business.UserID != userID {
-		return utils.NewUnauthorizedError("You don't have permission to update this business", nil)
+	// Held images are scanned and reviewed from their own queue, so they
+	// skip BusinessMediaUploaded.
+	if s.mediaReview.Holds() {
+		return s.mediaReview.Submit(ctx, models.ProfileMediaOwnerBusiness, businessID, models.ProfileMediaAvatar, models.Photo{URL: photoURL, AltText: altText})
 	}
 
 	// Update avatar
-	business.Avatar = &models.Photo{URL: photoURL}
+	business.Avatar = &models.Photo{URL: photoURL, AltText: altText}
 	business.UpdatedAt = time.Now()
 
 	if err := s.businessRepo.Update(ctx, business); err != nil {
@@ -535,24 +727,25 @@ func (s *BusinessService) UploadAvatar(ctx context.Context, businessID, userID,
 	s.invalidateBusinessCache(ctx, businessID)
 
 	s.logger.Info("Business avatar uploaded", zap.String("business_id", businessID))
+	s.events.Publish(ctx, BusinessMediaUploaded{BusinessID: businessID, URL: photoURL})
 	return nil
 }
 
 // UploadCover uploads a business cover photo
-func (s *BusinessService) UploadCover(ctx context.Context, businessID, userID, photoURL string) error {
-	// Get existing business
-	business, err := s.businessRepo.GetByID(ctx, businessID)
+func (s *BusinessService) UploadCover(ctx context.Context, businessID, userID, photoURL, altText string) error {
+	business, err := s.access.RequireBusinessOwner(ctx, businessID, userID)
 	if err != nil {
-		return utils.NewNotFoundError("Business not found", err)
+		return err
 	}
 
-	// Check ownership
-	if business.UserID != userID {
-		return utils.NewUnauthorizedError("You don't have permission to update this business", nil)
+	// Held images are scanned and reviewed from their own queue, so they
+	// skip BusinessMediaUploaded.
+	if s.mediaReview.Holds() {
+		return s.mediaReview.Submit(ctx, models.ProfileMediaOwnerBusiness, businessID, models.ProfileMediaCover, models.Photo{URL: photoURL, AltText: altText})
 	}
 
 	// Update cover
-	business.Cover = &models.Photo{URL: photoURL}
+	business.Cover = &models.Photo{URL: photoURL, AltText: altText}
 	business.UpdatedAt = time.Now()
 
 	if err := s.businessRepo.Update(ctx, business); err != nil {
@@ -565,22 +758,52 @@ func (s *BusinessService) UploadCover(ctx context.Context, businessID, userID, p
 	s.invalidateBusinessCache(ctx, businessID)
 
 	s.logger.Info("Business cover uploaded", zap.String("business_id", businessID))
+	s.events.Publish(ctx, BusinessMediaUploaded{BusinessID: businessID, URL: photoURL})
 	return nil
 }
 
-const maxBusinessGalleryImages = 10
-
-// AddGalleryImage adds an image to business gallery (max 10 per business).
-func (s *BusinessService) AddGalleryImage(ctx context.Context, businessID, userID, photoURL string) error {
-	// Get existing business
+// ApplyReviewedMedia puts an approved avatar or cover on the business.
+func (s *BusinessService) ApplyReviewedMedia(ctx context.Context, businessID, kind string, photo models.Photo) error {
 	business, err := s.businessRepo.GetByID(ctx, businessID)
 	if err != nil {
 		return utils.NewNotFoundError("Business not found", err)
 	}
+	if kind == models.ProfileMediaCover {
+		business.Cover = &photo
+	} else {
+		business.Avatar = &photo
+	}
+	business.UpdatedAt = time.Now()
+	if err := s.businessRepo.Update(ctx, business); err != nil {
+		s.logger.Error("Failed to apply reviewed business media", zap.String("business_id", businessID), zap.Error(err))
+		return utils.NewInternalError("Failed to update business", err)
+	}
+	s.invalidateBusinessCache(ctx, businessID)
+	return nil
+}
+
+const (
+	// defaultMaxGalleryImages is the gallery cap when WithGalleryLimit isn't
+	// set (BUSINESS_GALLERY_MAX_IMAGES).
+	defaultMaxGalleryImages = 10
+	// DefaultGalleryPageSize and MaxGalleryPageSize bound the gallery page
+	// on the business detail response.
+	DefaultGalleryPageSize = 12
+	MaxGalleryPageSize     = 50
+)
+
+func (s *BusinessService) galleryLimit() int {
+	if s.maxGalleryImages > 0 {
+		return s.maxGalleryImages
+	}
+	return defaultMaxGalleryImages
+}
 
-	// Check ownership
-	if business.UserID != userID {
-		return utils.NewUnauthorizedError("You don't have permission to update this business", nil)
+// AddGalleryImage adds an image to the end of the business gallery, up to
+// the configured gallery limit.
+func (s *BusinessService) AddGalleryImage(ctx context.Context, businessID, userID, photoURL, altText, caption string) error {
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return err
 	}
 
 	// Enforce gallery limit
@@ -588,8 +811,8 @@ func (s *BusinessService) AddGalleryImage(ctx context.Context, businessID, userI
 	if err != nil {
 		return utils.NewInternalError("Failed to get gallery", err)
 	}
-	if len(existing) >= maxBusinessGalleryImages {
-		return utils.NewBadRequestError("Gallery limit reached (max 10 images)", nil)
+	if limit := s.galleryLimit(); len(existing) >= limit {
+		return utils.NewBadRequestError(fmt.Sprintf("Gallery limit reached (max %d images)", limit), nil)
 	}
 
 	// Add attachment
@@ -597,7 +820,8 @@ func (s *BusinessService) AddGalleryImage(ctx context.Context, businessID, userI
 	attachment := &models.BusinessAttachment{
 		ID:                uuid.New().String(),
 		BusinessProfileID: businessID,
-		Photo:             models.Photo{URL: photoURL},
+		Photo:             models.Photo{URL: photoURL, AltText: altText},
+		Caption:           galleryCaption(caption),
 		CreatedAt:         now,
 		UpdatedAt:         now,
 	}
@@ -606,31 +830,151 @@ func (s *BusinessService) AddGalleryImage(ctx context.Context, businessID, userI
 		s.logger.Error("Failed to add gallery image", zap.String("business_id", businessID), zap.Error(err))
 		return utils.NewInternalError("Failed to add gallery image", err)
 	}
+	s.invalidateBusinessCache(ctx, businessID)
 
 	s.logger.Info("Gallery image added", zap.String("business_id", businessID))
+	s.events.Publish(ctx, BusinessMediaUploaded{BusinessID: businessID, URL: photoURL})
 	return nil
 }
 
 // DeleteGalleryImage removes an image from business gallery
 func (s *BusinessService) DeleteGalleryImage(ctx context.Context, businessID, userID, attachmentID string) error {
-	// Get existing business
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return err
+	}
+
+	// Delete attachment
+	if err := s.businessRepo.DeleteAttachment(ctx, businessID, attachmentID); err != nil {
+		if errors.Is(err, repositories.ErrGalleryImageNotFound) {
+			return utils.NewNotFoundError("Gallery image not found", err)
+		}
+		s.logger.Error("Failed to delete gallery image", zap.String("attachment_id", attachmentID), zap.Error(err))
+		return utils.NewInternalError("Failed to delete gallery image", err)
+	}
+	s.invalidateBusinessCache(ctx, businessID)
+
+	s.logger.Info("Gallery image deleted", zap.String("attachment_id", attachmentID))
+	return nil
+}
+
+// ReorderGallery sets the gallery order. attachmentIDs must list every
+// gallery image exactly once. Returns the gallery in its new order.
+func (s *BusinessService) ReorderGallery(ctx context.Context, businessID, userID string, attachmentIDs []string) ([]*models.GalleryItem, error) {
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return nil, err
+	}
+	attachments, err := s.businessRepo.GetAttachmentsByBusinessID(ctx, businessID)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to reorder gallery", err)
+	}
+	current := make([]string, len(attachments))
+	for i, att := range attachments {
+		current[i] = att.ID
+	}
+	if !sameIDSet(current, attachmentIDs) {
+		return nil, utils.NewBadRequestError("attachment_ids must list every gallery image exactly once", nil)
+	}
+	if err := s.businessRepo.ReorderAttachments(ctx, businessID, attachmentIDs); err != nil {
+		s.logger.Error("Failed to reorder gallery", zap.String("business_id", businessID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to reorder gallery", err)
+	}
+	s.invalidateBusinessCache(ctx, businessID)
+	return s.GetBusinessGallery(ctx, businessID)
+}
+
+// UpdateGalleryCaption sets or, with an empty caption, clears a gallery
+// image's caption.
+func (s *BusinessService) UpdateGalleryCaption(ctx context.Context, businessID, userID, attachmentID, caption string) error {
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return err
+	}
+	if err := s.businessRepo.SetAttachmentCaption(ctx, businessID, attachmentID, galleryCaption(caption)); err != nil {
+		if errors.Is(err, repositories.ErrGalleryImageNotFound) {
+			return utils.NewNotFoundError("Gallery image not found", err)
+		}
+		s.logger.Error("Failed to update gallery caption", zap.String("attachment_id", attachmentID), zap.Error(err))
+		return utils.NewInternalError("Failed to update caption", err)
+	}
+	s.invalidateBusinessCache(ctx, businessID)
+	return nil
+}
+
+// SetCoverFromGallery makes one of the business's gallery images its
+// cover. The image stays in the gallery.
+func (s *BusinessService) SetCoverFromGallery(ctx context.Context, businessID, userID, attachmentID string) (*models.Photo, error) {
+	business, err := s.access.RequireBusinessOwner(ctx, businessID, userID)
+	if err != nil {
+		return nil, err
+	}
+	attachment, err := s.businessRepo.GetAttachment(ctx, businessID, attachmentID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrGalleryImageNotFound) {
+			return nil, utils.NewNotFoundError("Gallery image not found", err)
+		}
+		return nil, utils.NewInternalError("Failed to set cover", err)
+	}
+
+	cover := attachment.Photo
+	business.Cover = &cover
+	business.UpdatedAt = time.Now()
+	if err := s.businessRepo.Update(ctx, business); err != nil {
+		s.logger.Error("Failed to set business cover from gallery", zap.String("business_id", businessID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to set cover", err)
+	}
+	s.invalidateBusinessCache(ctx, businessID)
+
+	s.logger.Info("Business cover set from gallery", zap.String("business_id", businessID), zap.String("attachment_id", attachmentID))
+	return &cover, nil
+}
+
+// galleryCaption trims a caption; empty means none.
+func galleryCaption(caption string) *string {
+	caption = strings.TrimSpace(caption)
+	if caption == "" {
+		return nil
+	}
+	return &caption
+}
+
+// RemoveMedia takes an image off a business wherever it is used: avatar,
+// cover or gallery. Used by moderation; there is no ownership check.
+func (s *BusinessService) RemoveMedia(ctx context.Context, businessID, photoURL string) error {
 	business, err := s.businessRepo.GetByID(ctx, businessID)
 	if err != nil {
 		return utils.NewNotFoundError("Business not found", err)
 	}
 
-	// Check ownership
-	if business.UserID != userID {
-		return utils.NewUnauthorizedError("You don't have permission to update this business", nil)
+	changed := false
+	if business.Avatar != nil && business.Avatar.URL == photoURL {
+		business.Avatar = nil
+		changed = true
+	}
+	if business.Cover != nil && business.Cover.URL == photoURL {
+		business.Cover = nil
+		changed = true
+	}
+	if changed {
+		business.UpdatedAt = time.Now()
+		if err := s.businessRepo.Update(ctx, business); err != nil {
+			return utils.NewInternalError("Failed to update business", err)
+		}
 	}
 
-	// Delete attachment
-	if err := s.businessRepo.DeleteAttachment(ctx, attachmentID); err != nil {
-		s.logger.Error("Failed to delete gallery image", zap.String("attachment_id", attachmentID), zap.Error(err))
-		return utils.NewInternalError("Failed to delete gallery image", err)
+	gallery, err := s.businessRepo.GetAttachmentsByBusinessID(ctx, businessID)
+	if err != nil {
+		return utils.NewInternalError("Failed to get gallery", err)
+	}
+	for _, a := range gallery {
+		if a.Photo.URL != photoURL {
+			continue
+		}
+		if err := s.businessRepo.DeleteAttachment(ctx, businessID, a.ID); err != nil {
+			return utils.NewInternalError("Failed to delete gallery image", err)
+		}
 	}
 
-	s.logger.Info("Gallery image deleted", zap.String("attachment_id", attachmentID))
+	s.invalidateBusinessCache(ctx, businessID)
+	s.logger.Info("Business media removed", zap.String("business_id", businessID), zap.String("url", photoURL))
 	return nil
 }
 
@@ -730,6 +1074,74 @@ func (s *BusinessService) UnfollowBusiness(ctx context.Context, businessID, user
 	return nil
 }
 
+// ListFollowers returns the business's followers, newest first (owner only).
+func (s *BusinessService) ListFollowers(ctx context.Context, businessID, userID string, limit, offset int) ([]*models.BusinessFollowerResponse, int, error) {
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return nil, 0, err
+	}
+
+	followers, total, err := s.businessRepo.ListFollowers(ctx, businessID, limit, offset)
+	if err != nil {
+		s.logger.Error("Failed to list business followers", zap.String("business_id", businessID), zap.Error(err))
+		return nil, 0, utils.NewInternalError("Failed to get followers", err)
+	}
+	for _, f := range followers {
+		f.FullName = (&models.Profile{FirstName: f.FirstName, LastName: f.LastName}).FullName()
+	}
+	return followers, total, nil
+}
+
+// GetFollowerStats returns follower counts by province (owner only).
+func (s *BusinessService) GetFollowerStats(ctx context.Context, businessID, userID string) (*models.BusinessFollowerStats, error) {
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return nil, err
+	}
+
+	counts, err := s.businessRepo.GetFollowerProvinceCounts(ctx, businessID)
+	if err != nil {
+		s.logger.Error("Failed to count followers by province", zap.String("business_id", businessID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to get follower stats", err)
+	}
+	stats := &models.BusinessFollowerStats{ByProvince: counts}
+	for _, c := range counts {
+		stats.TotalFollowers += c.Count
+	}
+	return stats, nil
+}
+
+// GetFollowerNotificationSettings returns the owner's new-post fan-out
+// setting (owner only).
+func (s *BusinessService) GetFollowerNotificationSettings(ctx context.Context, businessID, userID string) (*models.BusinessFollowerNotificationSettings, error) {
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return nil, err
+	}
+
+	settings, err := s.businessRepo.GetFollowerNotificationSettings(ctx, businessID)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to get notification settings", err)
+	}
+	return settings, nil
+}
+
+// UpdateFollowerNotificationSettings turns follower notifications for
+// low-priority posts (updates, polls) on or off. Events always notify.
+func (s *BusinessService) UpdateFollowerNotificationSettings(ctx context.Context, businessID, userID string, req *models.UpdateFollowerNotificationSettingsRequest) (*models.BusinessFollowerNotificationSettings, error) {
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return nil, err
+	}
+
+	settings := &models.BusinessFollowerNotificationSettings{NotifyLowPriorityPosts: *req.NotifyLowPriorityPosts}
+	if err := s.businessRepo.SetFollowerNotificationSettings(ctx, businessID, settings); err != nil {
+		s.logger.Error("Failed to save follower notification settings", zap.String("business_id", businessID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to update notification settings", err)
+	}
+
+	s.logger.Info("Business follower notifications updated",
+		zap.String("business_id", businessID),
+		zap.Bool("notify_low_priority_posts", settings.NotifyLowPriorityPosts))
+	return settings, nil
+}
+
 // ListBusinesses lists business profiles with filters
 func (s *BusinessService) ListBusinesses(ctx context.Context, filter *models.BusinessListFilter, viewerID *string) ([]*models.BusinessResponse, error) {
 	// Get businesses
@@ -753,6 +1165,36 @@ func (s *BusinessService) ListBusinesses(ctx context.Context, filter *models.Bus
 	return enrichedBusinesses, nil
 }
 
+// GetBusinessCards returns card payloads for businessIDs in the given
+// order, skipping businesses that are missing or hidden.
+func (s *BusinessService) GetBusinessCards(ctx context.Context, businessIDs []string, viewerID *string) []*models.BusinessCardResponse {
+	cards := make([]*models.BusinessCardResponse, 0, len(businessIDs))
+	for _, id := range businessIDs {
+		business, err := s.businessRepo.GetByID(ctx, id)
+		if err != nil || !business.Status {
+			continue
+		}
+		enriched, err := s.enrichBusiness(ctx, business, viewerID)
+		if err != nil {
+			s.logger.Warn("Failed to enrich business", zap.String("business_id", id), zap.Error(err))
+			continue
+		}
+		cards = append(cards, models.NewBusinessCardResponse(enriched))
+	}
+	return cards
+}
+
+// SearchFacets counts the businesses matching filter per category, for the
+// category chips on business search.
+func (s *BusinessService) SearchFacets(ctx context.Context, filter *models.BusinessListFilter) (*models.BusinessSearchFacets, error) {
+	counts, err := s.businessRepo.CountByCategory(ctx, filter)
+	if err != nil {
+		s.logger.Error("Failed to count businesses by category", zap.Error(err))
+		return nil, utils.NewInternalError("Failed to count businesses", err)
+	}
+	return &models.BusinessSearchFacets{Categories: counts}, nil
+}
+
 // GetAllCategories gets all business categories, optionally filtered by search (name).
 func (s *BusinessService) GetAllCategories(ctx context.Context, search *string) ([]*models.BusinessCategory, error) {
 	categories, err := s.businessRepo.GetAllCategories(ctx, search)
@@ -795,7 +1237,7 @@ func (s *BusinessService) GetBusinessHours(ctx context.Context, businessID strin
 	return out, nil
 }
 
-// GetBusinessGallery returns all gallery attachments for a business (separate from profile).
+// GetBusinessGallery returns all gallery attachments for a business (separate from profile), in gallery order.
 func (s *BusinessService) GetBusinessGallery(ctx context.Context, businessID string) ([]*models.GalleryItem, error) {
 	attachments, err := s.businessRepo.GetAttachmentsByBusinessID(ctx, businessID)
 	if err != nil {
@@ -807,11 +1249,54 @@ func (s *BusinessService) GetBusinessGallery(ctx context.Context, businessID str
 	}
 	out := make([]*models.GalleryItem, len(attachments))
 	for i, att := range attachments {
-		out[i] = &models.GalleryItem{ID: att.ID, Photo: att.Photo}
+		// Deletes leave gaps in the stored positions; report the index.
+		out[i] = &models.GalleryItem{ID: att.ID, Photo: att.Photo, Position: i, Caption: att.Caption}
 	}
 	return out, nil
 }
 
+// loadGallery returns the whole gallery for the detail response. Errors
+// are logged and leave the gallery out rather than failing the profile.
+func (s *BusinessService) loadGallery(ctx context.Context, businessID string) []models.GalleryItem {
+	items, err := s.GetBusinessGallery(ctx, businessID)
+	if err != nil {
+		return nil
+	}
+	gallery := make([]models.GalleryItem, len(items))
+	for i, item := range items {
+		gallery[i] = *item
+	}
+	return gallery
+}
+
+// pageGallery cuts resp.Gallery, the whole gallery, down to one page and
+// fills in GalleryMeta. page starts at 1; limit is clamped to
+// MaxGalleryPageSize.
+func pageGallery(resp *models.BusinessResponse, page, limit int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = DefaultGalleryPageSize
+	}
+	if limit > MaxGalleryPageSize {
+		limit = MaxGalleryPageSize
+	}
+	total := len(resp.Gallery)
+	start := total
+	if page-1 <= total/limit {
+		start = min((page-1)*limit, total)
+	}
+	end := min(start+limit, total)
+	resp.Gallery = resp.Gallery[start:end]
+	resp.GalleryMeta = &models.GalleryPagination{
+		CurrentPage:  page,
+		ItemsPerPage: limit,
+		TotalItems:   total,
+		TotalPages:   (total + limit - 1) / limit,
+	}
+}
+
 // defaultAvatarColorForBusiness returns a deterministic hex color for businessID when DB has no avatar_color (e.g. old rows).
 var defaultBusinessAvatarColors = []string{
 	"#7C6274", "#6B8E9F", "#8B9A6B", "#9B7B8E", "#6A8B7C", "#8B756B", "#7B8B9E", "#9A7B6C",
@@ -823,23 +1308,26 @@ func defaultAvatarColorForBusiness(businessID string) string {
 	return defaultBusinessAvatarColors[int(h.Sum32())%len(defaultBusinessAvatarColors)]
 }
 
-// GetBusinessInsights returns the owner-only analytics payload: per-day
-// views / new-followers / new-reviews series for the trailing `days` window
-// plus all-time totals. Non-owners get an authorization error.
-func (s *BusinessService) GetBusinessInsights(ctx context.Context, businessID, userID string, days int) (*models.BusinessInsightsResponse, error) {
+// clampInsightDays bounds an analytics window to 1..365 days, 28 by default.
+func clampInsightDays(days int) int {
 	if days < 1 {
-		days = 28
+		return 28
 	}
 	if days > 365 {
-		days = 365
+		return 365
 	}
+	return days
+}
 
-	business, err := s.businessRepo.GetByID(ctx, businessID)
+// GetBusinessInsights returns the owner-only analytics payload: per-day
+// views / new-followers / new-reviews series for the trailing `days` window
+// plus all-time totals. Non-owners get an authorization error.
+func (s *BusinessService) GetBusinessInsights(ctx context.Context, businessID, userID string, days int) (*models.BusinessInsightsResponse, error) {
+	days = clampInsightDays(days)
+
+	business, err := s.access.RequireBusinessOwner(ctx, businessID, userID)
 	if err != nil {
-		return nil, utils.NewNotFoundError("Business not found", err)
-	}
-	if business.UserID != userID {
-		return nil, utils.NewUnauthorizedError("You don't have permission to view this business's insights", nil)
+		return nil, err
 	}
 
 	views, err := s.businessRepo.GetDailyViews(ctx, businessID, days)
@@ -872,6 +1360,11 @@ func (s *BusinessService) GetBusinessInsights(ctx context.Context, businessID, u
 		s.logger.Error("Failed to get daily post views", zap.String("business_id", businessID), zap.Error(err))
 		return nil, utils.NewInternalError("Failed to get insights", err)
 	}
+	postImpressions, err := s.businessRepo.GetDailyPostImpressions(ctx, businessID, days)
+	if err != nil {
+		s.logger.Error("Failed to get daily post impressions", zap.String("business_id", businessID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to get insights", err)
+	}
 	distribution, err := s.businessRepo.GetRatingDistribution(ctx, businessID)
 	if err != nil {
 		s.logger.Error("Failed to get rating distribution", zap.String("business_id", businessID), zap.Error(err))
@@ -911,8 +1404,10 @@ func (s *BusinessService) GetBusinessInsights(ctx context.Context, businessID, u
 		Likes:               likes,
 		Comments:            comments,
 		PostViews:           postViews,
+		PostImpressions:     postImpressions,
 		Sold:                sold,
 		EventRSVPs:          eventRSVPs,
+		UniqueViewers:       s.profileViews.DailyUniqueViewers(ctx, businessID, days),
 		RatingDistribution:  dist,
 		AvgRating:           business.AvgRating,
 		TotalViews:          business.TotalViews,
@@ -1060,10 +1555,8 @@ func (s *BusinessService) enrichBusiness(ctx context.Context, business *models.B
 		response.Email = nil
 		response.PhoneNumber = nil
 		if response.Location != nil && response.Location.Latitude != nil && response.Location.Longitude != nil {
-			lat := math.Round(*response.Location.Latitude*100) / 100
-			lng := math.Round(*response.Location.Longitude*100) / 100
-			response.Location.Latitude = &lat
-			response.Location.Longitude = &lng
+			areaLocation.applyInfo(response.Location)
+			lat, lng := *response.Location.Latitude, *response.Location.Longitude
 			addrLoc := fmt.Sprintf("(%.2f,%.2f)", lat, lng)
 			response.AddressLocation = &addrLoc
 		}
@@ -3,14 +3,18 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
 	"strings"
 	"testing"
 
 	"github.com/hamsaya/backend/internal/mocks"
 	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
 	"github.com/hamsaya/backend/internal/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
@@ -47,6 +51,7 @@ func TestBusinessService_CreateBusiness(t *testing.T) {
 				)
 				br.On("GetCategoriesByBusinessID", mock.Anything, mock.AnythingOfType("string")).Return([]*models.BusinessCategory{}, nil)
 				br.On("GetHoursByBusinessID", mock.Anything, mock.AnythingOfType("string")).Return([]*models.BusinessHours{}, nil)
+				br.On("GetAttachmentsByBusinessID", mock.Anything, mock.AnythingOfType("string")).Return([]*models.BusinessAttachment{}, nil)
 				br.On("IsFollowing", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(false, nil)
 			},
 			expectError: false,
@@ -112,6 +117,7 @@ func TestBusinessService_GetBusiness(t *testing.T) {
 				br.On("GetByID", mock.Anything, "biz-1").Return(biz, nil)
 				br.On("GetCategoriesByBusinessID", mock.Anything, "biz-1").Return([]*models.BusinessCategory{}, nil)
 				br.On("GetHoursByBusinessID", mock.Anything, "biz-1").Return([]*models.BusinessHours{}, nil)
+				br.On("GetAttachmentsByBusinessID", mock.Anything, "biz-1").Return([]*models.BusinessAttachment{}, nil)
 				br.On("IsFollowing", mock.Anything, "biz-1", "user-1").Return(false, nil)
 				// Non-owner triggers IncrementViews in a goroutine — allow it
 				br.On("IncrementViews", mock.Anything, "biz-1").Return(nil).Maybe()
@@ -196,6 +202,7 @@ func TestBusinessService_UpdateBusiness(t *testing.T) {
 				// GetBusiness called at the end
 				br.On("GetCategoriesByBusinessID", mock.Anything, "biz-1").Return([]*models.BusinessCategory{}, nil)
 				br.On("GetHoursByBusinessID", mock.Anything, "biz-1").Return([]*models.BusinessHours{}, nil)
+				br.On("GetAttachmentsByBusinessID", mock.Anything, "biz-1").Return([]*models.BusinessAttachment{}, nil)
 				br.On("IsFollowing", mock.Anything, "biz-1", "owner-1").Return(false, nil)
 			},
 			expectError: false,
@@ -515,5 +522,192 @@ func TestBusinessService_GetAllCategories(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// TestBusinessService_FeaturedPosts
+// ---------------------------------------------------------------------------
+
+type fakeFeaturedPostLoader struct{ requested []string }
+
+func (f *fakeFeaturedPostLoader) GetPostsInOrder(ctx context.Context, postIDs []string, viewerID *string) []*models.PostResponse {
+	f.requested = postIDs
+	out := make([]*models.PostResponse, len(postIDs))
+	for i, id := range postIDs {
+		out[i] = &models.PostResponse{ID: id}
+	}
+	return out
+}
+
+func TestBusinessService_FeaturedPosts(t *testing.T) {
+	ctx := context.Background()
+	biz := testutil.CreateTestBusiness("biz-1", "owner-1", "Test Biz")
+	biz.Status = true
+
+	t.Run("detail includes featured posts in order", func(t *testing.T) {
+		br := new(mocks.MockBusinessRepository)
+		br.On("GetByID", mock.Anything, "biz-1").Return(biz, nil)
+		br.On("GetCategoriesByBusinessID", mock.Anything, "biz-1").Return([]*models.BusinessCategory{}, nil)
+		br.On("GetHoursByBusinessID", mock.Anything, "biz-1").Return([]*models.BusinessHours{}, nil)
+		br.On("IncrementViews", mock.Anything, "biz-1").Return(nil).Maybe()
+		br.On("GetAttachmentsByBusinessID", mock.Anything, "biz-1").Return([]*models.BusinessAttachment{}, nil)
+		br.On("GetFeaturedPostIDs", mock.Anything, "biz-1").Return([]string{"post-2", "post-1"}, nil)
+
+		loader := &fakeFeaturedPostLoader{}
+		svc := newTestBusinessService(br, new(mocks.MockUserRepository)).WithFeaturedPosts(loader)
+		resp, err := svc.GetBusiness(ctx, "biz-1", nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"post-2", "post-1"}, loader.requested)
+		if assert.Len(t, resp.FeaturedPosts, 2) {
+			assert.Equal(t, "post-2", resp.FeaturedPosts[0].ID)
+		}
+	})
+
+	t.Run("owner only", func(t *testing.T) {
+		br := new(mocks.MockBusinessRepository)
+		br.On("GetByID", mock.Anything, "biz-1").Return(biz, nil)
+
+		svc := newTestBusinessService(br, new(mocks.MockUserRepository))
+		err := svc.FeaturePost(ctx, "biz-1", "someone-else", "post-1")
+
+		assert.Error(t, err)
+		br.AssertNotCalled(t, "AddFeaturedPost", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("capped", func(t *testing.T) {
+		br := new(mocks.MockBusinessRepository)
+		br.On("GetByID", mock.Anything, "biz-1").Return(biz, nil)
+		br.On("GetFeaturedPostIDs", mock.Anything, "biz-1").Return([]string{"a", "b", "c", "d", "e", "f"}, nil)
+
+		svc := newTestBusinessService(br, new(mocks.MockUserRepository))
+		err := svc.FeaturePost(ctx, "biz-1", "owner-1", "post-7")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "up to 6")
+		br.AssertNotCalled(t, "AddFeaturedPost", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("reorder must list every featured post", func(t *testing.T) {
+		br := new(mocks.MockBusinessRepository)
+		br.On("GetByID", mock.Anything, "biz-1").Return(biz, nil)
+		br.On("GetFeaturedPostIDs", mock.Anything, "biz-1").Return([]string{"post-1", "post-2"}, nil)
+		br.On("ReorderFeaturedPosts", mock.Anything, "biz-1", []string{"post-2", "post-1"}).Return(nil)
+
+		svc := newTestBusinessService(br, new(mocks.MockUserRepository))
+
+		assert.Error(t, svc.ReorderFeaturedPosts(ctx, "biz-1", "owner-1", []string{"post-2"}))
+		assert.Error(t, svc.ReorderFeaturedPosts(ctx, "biz-1", "owner-1", []string{"post-2", "post-2"}))
+		assert.NoError(t, svc.ReorderFeaturedPosts(ctx, "biz-1", "owner-1", []string{"post-2", "post-1"}))
+		br.AssertNumberOfCalls(t, "ReorderFeaturedPosts", 1)
+	})
+}
+
+func TestBusinessService_Gallery(t *testing.T) {
+	ctx := context.Background()
+	biz := testutil.CreateTestBusiness("biz-1", "owner-1", "Test Biz")
+	biz.Status = true
+	gallery := func(n int) []*models.BusinessAttachment {
+		out := make([]*models.BusinessAttachment, n)
+		for i := range out {
+			out[i] = &models.BusinessAttachment{ID: fmt.Sprintf("att-%d", i), Photo: models.Photo{URL: fmt.Sprintf("https://cdn/img-%d.jpg", i)}, Position: i * 2}
+		}
+		return out
+	}
+
+	t.Run("detail pages the gallery in order", func(t *testing.T) {
+		br := new(mocks.MockBusinessRepository)
+		br.On("GetByID", mock.Anything, "biz-1").Return(biz, nil)
+		br.On("GetCategoriesByBusinessID", mock.Anything, "biz-1").Return([]*models.BusinessCategory{}, nil)
+		br.On("GetHoursByBusinessID", mock.Anything, "biz-1").Return([]*models.BusinessHours{}, nil)
+		br.On("IncrementViews", mock.Anything, "biz-1").Return(nil).Maybe()
+		br.On("GetAttachmentsByBusinessID", mock.Anything, "biz-1").Return(gallery(5), nil)
+
+		svc := newTestBusinessService(br, new(mocks.MockUserRepository))
+		resp, err := svc.GetBusinessWithGalleryPage(ctx, "biz-1", nil, 2, 2)
+
+		require.NoError(t, err)
+		require.Len(t, resp.Gallery, 2)
+		assert.Equal(t, "att-2", resp.Gallery[0].ID)
+		assert.Equal(t, 2, resp.Gallery[0].Position, "positions are renumbered without gaps")
+		assert.Equal(t, &models.GalleryPagination{CurrentPage: 2, ItemsPerPage: 2, TotalItems: 5, TotalPages: 3}, resp.GalleryMeta)
+	})
+
+	t.Run("page past the end is empty", func(t *testing.T) {
+		resp := &models.BusinessResponse{Gallery: make([]models.GalleryItem, 3)}
+		pageGallery(resp, 9, 500)
+		assert.Empty(t, resp.Gallery)
+		assert.Equal(t, MaxGalleryPageSize, resp.GalleryMeta.ItemsPerPage)
+		assert.Equal(t, 1, resp.GalleryMeta.TotalPages)
+	})
+
+	t.Run("configured limit", func(t *testing.T) {
+		br := new(mocks.MockBusinessRepository)
+		br.On("GetByID", mock.Anything, "biz-1").Return(biz, nil)
+		br.On("GetAttachmentsByBusinessID", mock.Anything, "biz-1").Return(gallery(3), nil)
+
+		svc := newTestBusinessService(br, new(mocks.MockUserRepository)).WithGalleryLimit(3)
+		err := svc.AddGalleryImage(ctx, "biz-1", "owner-1", "https://cdn/new.jpg", "", "")
+
+		requireAppErrCode(t, err, http.StatusBadRequest)
+		assert.Contains(t, err.Error(), "max 3")
+		br.AssertNotCalled(t, "AddAttachment", mock.Anything, mock.Anything)
+	})
+
+	t.Run("add trims the caption", func(t *testing.T) {
+		br := new(mocks.MockBusinessRepository)
+		br.On("GetByID", mock.Anything, "biz-1").Return(biz, nil)
+		br.On("GetAttachmentsByBusinessID", mock.Anything, "biz-1").Return(gallery(1), nil)
+		br.On("AddAttachment", mock.Anything, mock.MatchedBy(func(a *models.BusinessAttachment) bool {
+			return a.Caption != nil && *a.Caption == "Our shop front"
+		})).Return(nil)
+
+		svc := newTestBusinessService(br, new(mocks.MockUserRepository))
+		require.NoError(t, svc.AddGalleryImage(ctx, "biz-1", "owner-1", "https://cdn/new.jpg", "", "  Our shop front "))
+		br.AssertExpectations(t)
+	})
+
+	t.Run("reorder must list every image", func(t *testing.T) {
+		br := new(mocks.MockBusinessRepository)
+		br.On("GetByID", mock.Anything, "biz-1").Return(biz, nil)
+		br.On("GetAttachmentsByBusinessID", mock.Anything, "biz-1").Return(gallery(2), nil)
+		br.On("ReorderAttachments", mock.Anything, "biz-1", []string{"att-1", "att-0"}).Return(nil)
+
+		svc := newTestBusinessService(br, new(mocks.MockUserRepository))
+
+		_, err := svc.ReorderGallery(ctx, "biz-1", "owner-1", []string{"att-1"})
+		requireAppErrCode(t, err, http.StatusBadRequest)
+		_, err = svc.ReorderGallery(ctx, "biz-1", "someone-else", []string{"att-1", "att-0"})
+		requireAppErrCode(t, err, http.StatusForbidden)
+		_, err = svc.ReorderGallery(ctx, "biz-1", "owner-1", []string{"att-1", "att-0"})
+		require.NoError(t, err)
+		br.AssertNumberOfCalls(t, "ReorderAttachments", 1)
+	})
+
+	t.Run("caption of another business's image", func(t *testing.T) {
+		br := new(mocks.MockBusinessRepository)
+		br.On("GetByID", mock.Anything, "biz-1").Return(biz, nil)
+		br.On("SetAttachmentCaption", mock.Anything, "biz-1", "att-9", (*string)(nil)).Return(repositories.ErrGalleryImageNotFound)
+
+		svc := newTestBusinessService(br, new(mocks.MockUserRepository))
+		err := svc.UpdateGalleryCaption(ctx, "biz-1", "owner-1", "att-9", " ")
+		requireAppErrCode(t, err, http.StatusNotFound)
+	})
+
+	t.Run("cover from gallery", func(t *testing.T) {
+		owned := *biz
+		br := new(mocks.MockBusinessRepository)
+		br.On("GetByID", mock.Anything, "biz-1").Return(&owned, nil)
+		br.On("GetAttachment", mock.Anything, "biz-1", "att-1").Return(gallery(2)[1], nil)
+		br.On("Update", mock.Anything, mock.MatchedBy(func(b *models.BusinessProfile) bool {
+			return b.Cover != nil && b.Cover.URL == "https://cdn/img-1.jpg"
+		})).Return(nil)
+
+		svc := newTestBusinessService(br, new(mocks.MockUserRepository))
+		photo, err := svc.SetCoverFromGallery(ctx, "biz-1", "owner-1", "att-1")
+		require.NoError(t, err)
+		assert.Equal(t, "https://cdn/img-1.jpg", photo.URL)
+		br.AssertExpectations(t)
+	})
+}
+
 // strPtr is a local helper (avoids importing testutil for tiny usage).
 func strPtr(s string) *string { return &s }
@@ -18,6 +18,7 @@ import (
 type BusinessVerificationService struct {
 	verificationRepo repositories.BusinessVerificationRepository
 	businessRepo     repositories.BusinessRepository
+	access           *AccessControl
 	notification     *NotificationService
 	logger           *zap.Logger
 
@@ -44,6 +45,7 @@ func NewBusinessVerificationService(
 	return &BusinessVerificationService{
 		verificationRepo: verificationRepo,
 		businessRepo:     businessRepo,
+		access:           NewAccessControl(businessRepo),
 		notification:     notification,
 		logger:           logger,
 	}
@@ -56,12 +58,9 @@ func (s *BusinessVerificationService) Submit(
 	ctx context.Context, businessID, userID string,
 	licenseNo, note *string, documents []models.Photo,
 ) (*models.BusinessVerificationRequest, error) {
-	business, err := s.businessRepo.GetByID(ctx, businessID)
+	business, err := s.access.RequireBusinessOwner(ctx, businessID, userID)
 	if err != nil {
-		return nil, utils.NewNotFoundError("Business not found", err)
-	}
-	if business.UserID != userID {
-		return nil, utils.NewUnauthorizedError("You don't own this business", nil)
+		return nil, err
 	}
 	if business.IsVerified {
 		return nil, utils.NewBadRequestError("Business is already verified", nil)
@@ -96,12 +95,8 @@ func (s *BusinessVerificationService) Submit(
 // Status returns the latest request for a business (owner only). Nil when the
 // owner has never submitted.
 func (s *BusinessVerificationService) Status(ctx context.Context, businessID, userID string) (*models.BusinessVerificationRequest, error) {
-	business, err := s.businessRepo.GetByID(ctx, businessID)
-	if err != nil {
-		return nil, utils.NewNotFoundError("Business not found", err)
-	}
-	if business.UserID != userID {
-		return nil, utils.NewUnauthorizedError("You don't own this business", nil)
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return nil, err
 	}
 	req, err := s.verificationRepo.GetLatestByBusiness(ctx, businessID)
 	if errors.Is(err, repositories.ErrVerificationNotFound) {
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/hamsaya/backend/pkg/ical"
+	"go.uber.org/zap"
+)
+
+const (
+	// calendarFeedLimit caps the events in a personal feed, most recently
+	// RSVP'd first.
+	calendarFeedLimit = 500
+	// calendarFeedRefresh is how often subscribed calendar apps are asked
+	// to poll the feed.
+	calendarFeedRefresh = 6 * time.Hour
+	// defaultEventDuration is used for events with a start time but no end.
+	defaultEventDuration = time.Hour
+	calendarUIDDomain    = "@hamsaya.af"
+)
+
+// CalendarService exports EVENT posts as iCalendar: one event to add to a
+// calendar, or a personal feed of every event the user is going to that
+// calendar apps subscribe to with a secret token.
+type CalendarService struct {
+	feedRepo   repositories.CalendarFeedRepository
+	postRepo   repositories.PostRepository
+	authorizer *PostAuthorizer
+	postURL    string
+	logger     *zap.Logger
+}
+
+// NewCalendarService constructs the service. postURL is the public landing
+// page events link to (post id appended); empty uses the website.
+func NewCalendarService(
+	feedRepo repositories.CalendarFeedRepository,
+	postRepo repositories.PostRepository,
+	authorizer *PostAuthorizer,
+	postURL string,
+	logger *zap.Logger,
+) *CalendarService {
+	if postURL == "" {
+		postURL = "https://hamsaya.af/posts/"
+	}
+	return &CalendarService{
+		feedRepo:   feedRepo,
+		postRepo:   postRepo,
+		authorizer: authorizer,
+		postURL:    strings.TrimRight(postURL, "/") + "/",
+		logger:     logger,
+	}
+}
+
+// EventICS renders a single event the viewer can see.
+func (s *CalendarService) EventICS(ctx context.Context, postID string, viewerID *string) ([]byte, error) {
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil || (s.authorizer != nil && !s.authorizer.CanView(ctx, post, viewerID)) {
+		return nil, utils.NewNotFoundError("Post not found", err)
+	}
+	if post.Type != models.PostTypeEvent {
+		return nil, utils.NewBadRequestError("Only events can be exported to a calendar", nil)
+	}
+	event, ok := s.calendarEvent(post)
+	if !ok {
+		return nil, utils.NewBadRequestError("This event has no start date", nil)
+	}
+	return s.render(&ical.Calendar{Location: bookingLocation(), Events: []ical.Event{event}})
+}
+
+// FeedICS renders the feed behind a subscription token: the events its
+// owner is going to.
+func (s *CalendarService) FeedICS(ctx context.Context, token string) ([]byte, error) {
+	if token == "" {
+		return nil, utils.NewNotFoundError("Calendar feed not found", nil)
+	}
+	userID, err := s.feedRepo.UserIDByToken(ctx, hashToken(token))
+	if errors.Is(err, repositories.ErrCalendarFeedNotFound) {
+		return nil, utils.NewNotFoundError("Calendar feed not found", err)
+	}
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load calendar feed", err)
+	}
+
+	posts, err := s.postRepo.GetUserEventPosts(ctx, userID, models.EventInterestGoing, calendarFeedLimit, 0)
+	if err != nil {
+		s.logger.Error("Failed to load calendar feed events", zap.String("user_id", userID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to load calendar feed", err)
+	}
+	if s.authorizer != nil {
+		posts = s.authorizer.FilterVisible(ctx, posts, &userID)
+	}
+
+	cal := &ical.Calendar{
+		Name:            "Hamsaya events",
+		Location:        bookingLocation(),
+		RefreshInterval: calendarFeedRefresh,
+	}
+	for _, post := range posts {
+		if event, ok := s.calendarEvent(post); ok {
+			cal.Events = append(cal.Events, event)
+		}
+	}
+	return s.render(cal)
+}
+
+// EnableFeed issues a new feed token for the user, replacing any earlier
+// one. The plaintext is only returned here.
+func (s *CalendarService) EnableFeed(ctx context.Context, userID string) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", utils.NewInternalError("Failed to create calendar feed", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	if err := s.feedRepo.SetToken(ctx, userID, hashToken(token)); err != nil {
+		return "", utils.NewInternalError("Failed to create calendar feed", err)
+	}
+	return token, nil
+}
+
+// DisableFeed revokes the user's feed token; subscribed apps stop updating.
+func (s *CalendarService) DisableFeed(ctx context.Context, userID string) error {
+	if err := s.feedRepo.DeleteToken(ctx, userID); err != nil {
+		return utils.NewInternalError("Failed to disable calendar feed", err)
+	}
+	return nil
+}
+
+func (s *CalendarService) render(cal *ical.Calendar) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := cal.Write(&buf); err != nil {
+		return nil, utils.NewInternalError("Failed to render calendar", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// calendarEvent maps an EVENT post to a VEVENT. Dates and times are stored
+// as Kabul wall-clock values. Without a start time the event spans whole
+// days; without an end it lasts defaultEventDuration. Events without a start
+// date are skipped.
+func (s *CalendarService) calendarEvent(post *models.Post) (ical.Event, bool) {
+	if post.StartDate == nil {
+		return ical.Event{}, false
+	}
+	loc := bookingLocation()
+	event := ical.Event{
+		UID:   post.ID + calendarUIDDomain,
+		URL:   s.postURL + post.ID,
+		Stamp: post.UpdatedAt,
+	}
+	if post.Title != nil {
+		event.Summary = *post.Title
+	}
+	if post.Description != nil {
+		event.Description = *post.Description
+	}
+
+	endDate := post.StartDate
+	if post.EndDate != nil && !post.EndDate.Before(*post.StartDate) {
+		endDate = post.EndDate
+	}
+	if post.StartTime == nil {
+		event.AllDay = true
+		event.Start = *post.StartDate
+		event.End = endDate.AddDate(0, 0, 1)
+	} else {
+		event.Start = wallClock(*post.StartDate, *post.StartTime, loc)
+		event.End = event.Start.Add(defaultEventDuration)
+		if post.EndTime != nil {
+			if end := wallClock(*endDate, *post.EndTime, loc); end.After(event.Start) {
+				event.End = end
+			}
+		} else if post.EndDate != nil && endDate.After(*post.StartDate) {
+			// Multi-day event with no end time: end at the start time of its
+			// last day.
+			event.End = wallClock(*endDate, *post.StartTime, loc)
+		}
+	}
+
+	var place []string
+	for _, part := range []*string{post.Neighborhood, post.District, post.Province, post.Country} {
+		if part != nil && strings.TrimSpace(*part) != "" {
+			place = append(place, strings.TrimSpace(*part))
+		}
+	}
+	event.Location = strings.Join(place, ", ")
+	if post.AddressLocation != nil && post.AddressLocation.Valid {
+		lat, lon := post.AddressLocation.P.Y, post.AddressLocation.P.X
+		event.Latitude, event.Longitude = &lat, &lon
+	}
+	return event, true
+}
+
+// wallClock combines a DATE and a TIME column into an instant in loc.
+func wallClock(date, clock time.Time, loc *time.Location) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, loc)
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func calendarPost(startDate string, startTime, endTime *string) *models.Post {
+	post := testutil.CreateTestPost("post-1", "owner-1", models.PostTypeEvent)
+	post.Title = ptrStr("Book fair")
+	date, _ := time.Parse("2006-01-02", startDate)
+	post.StartDate = &date
+	clock := func(s string) *time.Time {
+		t, _ := time.Parse("2006-01-02 15:04", "2000-01-01 "+s)
+		return &t
+	}
+	if startTime != nil {
+		post.StartTime = clock(*startTime)
+	}
+	if endTime != nil {
+		post.EndTime = clock(*endTime)
+	}
+	return post
+}
+
+func TestCalendarService_CalendarEvent(t *testing.T) {
+	svc := NewCalendarService(nil, nil, nil, "", zap.NewNop())
+	kabul := bookingLocation()
+
+	t.Run("timed event in Kabul time", func(t *testing.T) {
+		event, ok := svc.calendarEvent(calendarPost("2026-11-02", ptrStr("17:30"), ptrStr("20:00")))
+		require.True(t, ok)
+		assert.False(t, event.AllDay)
+		assert.Equal(t, time.Date(2026, 11, 2, 17, 30, 0, 0, kabul).Unix(), event.Start.Unix())
+		assert.Equal(t, time.Date(2026, 11, 2, 20, 0, 0, 0, kabul).Unix(), event.End.Unix())
+		assert.Equal(t, "Book fair", event.Summary)
+		assert.Equal(t, "https://hamsaya.af/posts/post-1", event.URL)
+	})
+
+	t.Run("no end time lasts the default duration", func(t *testing.T) {
+		event, ok := svc.calendarEvent(calendarPost("2026-11-02", ptrStr("17:30"), nil))
+		require.True(t, ok)
+		assert.Equal(t, defaultEventDuration, event.End.Sub(event.Start))
+	})
+
+	t.Run("no start time spans whole days", func(t *testing.T) {
+		post := calendarPost("2026-11-02", nil, nil)
+		end := post.StartDate.AddDate(0, 0, 2)
+		post.EndDate = &end
+		event, ok := svc.calendarEvent(post)
+		require.True(t, ok)
+		assert.True(t, event.AllDay)
+		assert.Equal(t, "20261105", event.End.Format("20060102"), "DTEND is exclusive")
+	})
+
+	t.Run("no start date", func(t *testing.T) {
+		_, ok := svc.calendarEvent(testutil.CreateTestPost("post-1", "owner-1", models.PostTypeEvent))
+		assert.False(t, ok)
+	})
+}
+
+func TestCalendarService_EventICS(t *testing.T) {
+	t.Run("only events", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").
+			Return(testutil.CreateTestPost("post-1", "owner-1", models.PostTypeFeed), nil)
+		svc := NewCalendarService(new(mocks.MockCalendarFeedRepository), postRepo, nil, "", zap.NewNop())
+
+		_, err := svc.EventICS(context.Background(), "post-1", nil)
+		requireAppErrCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("renders the event", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(calendarPost("2026-11-02", ptrStr("17:30"), nil), nil)
+		svc := NewCalendarService(new(mocks.MockCalendarFeedRepository), postRepo, nil, "", zap.NewNop())
+
+		body, err := svc.EventICS(context.Background(), "post-1", nil)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), "SUMMARY:Book fair\r\n")
+		assert.Contains(t, string(body), "UID:post-1@hamsaya.af\r\n")
+	})
+}
+
+func TestCalendarService_Feed(t *testing.T) {
+	t.Run("unknown token", func(t *testing.T) {
+		feedRepo := new(mocks.MockCalendarFeedRepository)
+		feedRepo.On("UserIDByToken", mock.Anything, hashToken("nope")).Return("", repositories.ErrCalendarFeedNotFound)
+		svc := NewCalendarService(feedRepo, new(mocks.MockPostRepository), nil, "", zap.NewNop())
+
+		_, err := svc.FeedICS(context.Background(), "nope")
+		requireAppErrCode(t, err, http.StatusNotFound)
+	})
+
+	t.Run("enable stores only the hash", func(t *testing.T) {
+		var stored string
+		feedRepo := new(mocks.MockCalendarFeedRepository)
+		feedRepo.On("SetToken", mock.Anything, "user-1", mock.AnythingOfType("string")).
+			Run(func(args mock.Arguments) { stored = args.String(2) }).Return(nil)
+		svc := NewCalendarService(feedRepo, new(mocks.MockPostRepository), nil, "", zap.NewNop())
+
+		token, err := svc.EnableFeed(context.Background(), "user-1")
+		require.NoError(t, err)
+		assert.NotEqual(t, token, stored)
+		assert.Equal(t, hashToken(token), stored)
+	})
+
+	t.Run("lists going events with a start date", func(t *testing.T) {
+		feedRepo := new(mocks.MockCalendarFeedRepository)
+		feedRepo.On("UserIDByToken", mock.Anything, hashToken("secret")).Return("user-1", nil)
+		undated := testutil.CreateTestPost("post-2", "owner-1", models.PostTypeEvent)
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetUserEventPosts", mock.Anything, "user-1", models.EventInterestGoing, calendarFeedLimit, 0).
+			Return([]*models.Post{calendarPost("2026-11-02", nil, nil), undated}, nil)
+		svc := NewCalendarService(feedRepo, postRepo, nil, "", zap.NewNop())
+
+		body, err := svc.FeedICS(context.Background(), "secret")
+		require.NoError(t, err)
+		assert.Equal(t, 1, strings.Count(string(body), "BEGIN:VEVENT"))
+		assert.Contains(t, string(body), "REFRESH-INTERVAL;VALUE=DURATION:PT6H\r\n")
+	})
+}
@@ -2,13 +2,14 @@ package services
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/repositories"
 	"github.com/hamsaya/backend/internal/utils"
-	"github.com/hamsaya/backend/pkg/observability"
+	"github.com/hamsaya/backend/pkg/events"
 	ws "github.com/hamsaya/backend/pkg/websocket"
 	"go.uber.org/zap"
 )
@@ -19,9 +20,19 @@ type ChatService struct {
 	messageRepo         repositories.MessageRepository
 	userRepo            repositories.UserRepository
 	businessRepo        repositories.BusinessRepository
+	access              *AccessControl
 	relationshipsRepo   repositories.RelationshipsRepository
 	notificationService *NotificationService
 	wsHub               *ws.Hub
+	creationThrottle    *CreationThrottle
+	productService      *BusinessProductService
+	postRepo            repositories.PostRepository
+	unreadCounters      *UnreadCounters
+	stickerService      *StickerService
+	attachmentStorage   uploadDeleter
+	events              *events.Bus
+	tombstones          repositories.SyncTombstoneRepository
+	responsiveness      *BusinessResponsivenessService
 	logger              *zap.Logger
 }
 
@@ -41,6 +52,7 @@ func NewChatService(
 		messageRepo:         messageRepo,
 		userRepo:            userRepo,
 		businessRepo:        businessRepo,
+		access:              NewAccessControl(businessRepo),
 		relationshipsRepo:   relationshipsRepo,
 		notificationService: notificationService,
 		wsHub:               wsHub,
@@ -48,14 +60,87 @@ func NewChatService(
 	}
 }
 
+// WithEvents publishes MessageSent on bus.
+func (s *ChatService) WithEvents(bus *events.Bus) *ChatService {
+	s.events = bus
+	return s
+}
+
+// WithCreationThrottle enables the per-user message send limit.
+func (s *ChatService) WithCreationThrottle(t *CreationThrottle) *ChatService {
+	s.creationThrottle = t
+	return s
+}
+
+// WithProducts enables attaching catalog products to messages.
+func (s *ChatService) WithProducts(productService *BusinessProductService) *ChatService {
+	s.productService = productService
+	return s
+}
+
+// WithPosts enables sharing posts into chats and the post card on POST
+// messages.
+func (s *ChatService) WithPosts(postRepo repositories.PostRepository) *ChatService {
+	s.postRepo = postRepo
+	return s
+}
+
+// WithUnreadCounters serves unread counts from Redis instead of counting
+// messages per conversation.
+func (s *ChatService) WithUnreadCounters(counters *UnreadCounters) *ChatService {
+	s.unreadCounters = counters
+	return s
+}
+
+// WithStickers enables STICKER messages from the sticker catalog.
+func (s *ChatService) WithStickers(stickerService *StickerService) *ChatService {
+	s.stickerService = stickerService
+	return s
+}
+
+// WithAttachmentStorage lets the disappearing-messages purge delete the
+// stored files of expired IMAGE, FILE and VOICE messages.
+func (s *ChatService) WithAttachmentStorage(storage uploadDeleter) *ChatService {
+	s.attachmentStorage = storage
+	return s
+}
+
+// WithTombstones lets conversation delta sync report deleted chats.
+func (s *ChatService) WithTombstones(repo repositories.SyncTombstoneRepository) *ChatService {
+	s.tombstones = repo
+	return s
+}
+
+// WithResponsiveness shows customers a business's open-now status and
+// usual reply time in the chat header.
+func (s *ChatService) WithResponsiveness(responsiveness *BusinessResponsivenessService) *ChatService {
+	s.responsiveness = responsiveness
+	return s
+}
+
 // SendMessage sends a message to another user
 func (s *ChatService) SendMessage(ctx context.Context, senderID string, req *models.SendMessageRequest) (*models.MessageResponse, error) {
-	// Validate message type — accept TEXT, IMAGE, FILE, LOCATION.
+	// Validate message type — accept TEXT, IMAGE, FILE, LOCATION, VOICE, STICKER.
 	switch req.MessageType {
 	case models.MessageTypeText, models.MessageTypeImage, models.MessageTypeFile, models.MessageTypeLocation, models.MessageTypeVoice:
 		// valid
+	case models.MessageTypeSticker:
+		if s.stickerService == nil {
+			return nil, utils.NewBadRequestError("Stickers are not available", nil)
+		}
+		if req.StickerID == nil || *req.StickerID == "" {
+			return nil, utils.NewBadRequestError("sticker_id is required for sticker messages", nil)
+		}
+	case models.MessageTypePost:
+		// Only via SharePost, which resolves the post first.
+		if req.SharedPostID == nil {
+			return nil, utils.NewBadRequestError("message_type must be one of: TEXT IMAGE FILE LOCATION VOICE STICKER", nil)
+		}
 	default:
-		return nil, utils.NewBadRequestError("message_type must be one of: TEXT IMAGE FILE LOCATION VOICE", nil)
+		return nil, utils.NewBadRequestError("message_type must be one of: TEXT IMAGE FILE LOCATION VOICE STICKER", nil)
+	}
+	if req.MessageType != models.MessageTypeSticker {
+		req.StickerID = nil
 	}
 
 	// Validate message content
@@ -82,6 +167,25 @@ func (s *ChatService) SendMessage(ctx context.Context, senderID string, req *mod
 		}
 	}
 
+	if req.BusinessProductID != nil && *req.BusinessProductID != "" {
+		if s.productService == nil {
+			return nil, utils.NewBadRequestError("Product attachments are not available", nil)
+		}
+		if _, err := s.productService.ResolveAttachment(ctx, *req.BusinessProductID, req.BusinessID); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.StickerID != nil {
+		if _, err := s.stickerService.ResolveForSend(ctx, *req.StickerID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.creationThrottle.Allow(ctx, CreationMessage, senderID); err != nil {
+		return nil, err
+	}
+
 	// Get or create conversation (optionally scoped to a business)
 	conversation, err := s.conversationRepo.GetOrCreate(ctx, senderID, req.RecipientID, req.BusinessID)
 	if err != nil {
@@ -104,6 +208,14 @@ func (s *ChatService) SendMessage(ctx context.Context, senderID string, req *mod
 		ProductID:        req.ProductID,
 		ReplyToMessageID: req.ReplyToMessageID,
 		CreatedAt:        time.Now(),
+
+		BusinessProductID: req.BusinessProductID,
+		SharedPostID:      req.SharedPostID,
+		StickerID:         req.StickerID,
+	}
+	if conversation.DisappearingTTL != nil {
+		expiresAt := message.CreatedAt.Add(time.Duration(*conversation.DisappearingTTL) * time.Second)
+		message.ExpiresAt = &expiresAt
 	}
 
 	if err := s.messageRepo.Create(ctx, message); err != nil {
@@ -114,8 +226,6 @@ func (s *ChatService) SendMessage(ctx context.Context, senderID string, req *mod
 		return nil, utils.NewInternalError("Failed to send message", err)
 	}
 
-	observability.RecordMessageCreated(ctx)
-
 	// Update conversation's last_message_at
 	if err := s.conversationRepo.UpdateLastMessageAt(ctx, conversation.ID); err != nil {
 		s.logger.Warn("Failed to update last_message_at",
@@ -130,18 +240,25 @@ func (s *ChatService) SendMessage(ctx context.Context, senderID string, req *mod
 		zap.String("recipient_id", req.RecipientID),
 	)
 
-	// Send real-time notification to recipient via WebSocket. Pass the
+	// Real-time delivery, push and metrics are event subscribers. Pass the
 	// conversation so the persisted notification can be stamped with
 	// business_id when the chat is business-scoped.
-	go s.notifyMessageSent(message, req.RecipientID, conversation)
+	s.events.Publish(ctx, MessageSent{Message: message, Conversation: conversation, RecipientID: req.RecipientID})
 
 	// Get enriched message response
-	return s.enrichMessage(ctx, message, senderID)
+	return s.enrichMessage(ctx, message, senderID, s.conversationBusiness(ctx, conversation))
 }
 
 // GetConversations retrieves all conversations for a user. businessID nil =
-// personal chats only; non-nil = chats scoped to that business.
+// personal chats only; non-nil = the business inbox, chats scoped to that
+// business, which only its owner may read.
 func (s *ChatService) GetConversations(ctx context.Context, userID string, limit, offset int, businessID *string) ([]*models.ConversationResponse, error) {
+	if businessID != nil {
+		if err := s.requireBusinessInbox(ctx, userID, *businessID); err != nil {
+			return nil, err
+		}
+	}
+
 	filter := &models.GetConversationsFilter{
 		UserID:     userID,
 		BusinessID: businessID,
@@ -158,10 +275,79 @@ func (s *ChatService) GetConversations(ctx context.Context, userID string, limit
 		return nil, utils.NewInternalError("Failed to get conversations", err)
 	}
 
+	return s.enrichConversations(ctx, conversations, userID), nil
+}
+
+// GetConversationChanges is the delta-sync form of GetConversations:
+// conversations created or changed (new message, settings) after since,
+// plus the IDs of those deleted since. Read state lives on messages, so
+// reading a chat doesn't count as a change.
+func (s *ChatService) GetConversationChanges(ctx context.Context, userID string, limit int, businessID *string, since time.Time) (*models.DeltaPage, error) {
+	if businessID != nil {
+		if err := s.requireBusinessInbox(ctx, userID, *businessID); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	if deltaSyncExpired(since, now) {
+		return resetDeltaPage(now), nil
+	}
+
+	conversations, err := s.conversationRepo.List(ctx, &models.GetConversationsFilter{
+		UserID:       userID,
+		BusinessID:   businessID,
+		UpdatedAfter: &since,
+		Limit:        limit,
+	})
+	if err != nil {
+		s.logger.Error("Failed to list conversation changes",
+			zap.Error(err),
+			zap.String("user_id", userID),
+		)
+		return nil, utils.NewInternalError("Failed to get conversations", err)
+	}
+
+	// Tombstones are per participant, not per inbox; the client ignores
+	// IDs it doesn't hold.
+	deleted := []string{}
+	if s.tombstones != nil {
+		deleted, err = s.tombstones.DeletedSince(ctx, userID, models.SyncEntityConversation, since, maxDeltaTombstones+1)
+		if err != nil {
+			s.logger.Error("Failed to get deleted conversations", zap.Error(err), zap.String("user_id", userID))
+			return nil, utils.NewInternalError("Failed to get conversations", err)
+		}
+	}
+
+	var lastUpdated time.Time
+	if len(conversations) > 0 {
+		lastUpdated = conversations[len(conversations)-1].UpdatedAt
+	}
+	items := s.enrichConversations(ctx, conversations, userID)
+	if items == nil {
+		items = []*models.ConversationResponse{}
+	}
+	return newDeltaPage(items, len(conversations), limit, lastUpdated, deleted, since, now), nil
+}
+
+// enrichConversations enriches a page of conversations for userID,
+// skipping any that fail.
+func (s *ChatService) enrichConversations(ctx context.Context, conversations []*models.Conversation, userID string) []*models.ConversationResponse {
+	// One lookup for every conversation's unread count; nil falls back to
+	// counting per conversation.
+	var unread map[string]int
+	if s.unreadCounters != nil {
+		var err error
+		if unread, err = s.unreadCounters.Counts(ctx, userID); err != nil {
+			s.logger.Warn("Failed to get unread counters", zap.Error(err), zap.String("user_id", userID))
+			unread = nil
+		}
+	}
+
 	// Enrich conversations
 	var enrichedConversations []*models.ConversationResponse
 	for _, conversation := range conversations {
-		enriched, err := s.enrichConversation(ctx, conversation, userID)
+		enriched, err := s.enrichConversation(ctx, conversation, userID, unread)
 		if err != nil {
 			s.logger.Warn("Failed to enrich conversation",
 				zap.Error(err),
@@ -172,11 +358,121 @@ func (s *ChatService) GetConversations(ctx context.Context, userID string, limit
 		enrichedConversations = append(enrichedConversations, enriched)
 	}
 
-	return enrichedConversations, nil
+	return enrichedConversations
+}
+
+// requireBusinessInbox checks that userID may read businessID's inbox.
+func (s *ChatService) requireBusinessInbox(ctx context.Context, userID, businessID string) error {
+	if s.businessRepo == nil {
+		return utils.NewBadRequestError("Business chats are not available", nil)
+	}
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil || business == nil {
+		return utils.NewNotFoundError("Business not found", err)
+	}
+	if !s.access.CanManageBusiness(business, userID) {
+		return utils.NewForbiddenError("Only the business owner can read its conversations", nil)
+	}
+	return nil
+}
+
+// conversationBusiness returns the business a conversation is scoped to,
+// or nil for personal chats (and when it can't be loaded). Its owner speaks
+// as the business in that chat.
+func (s *ChatService) conversationBusiness(ctx context.Context, conversation *models.Conversation) *models.BusinessProfile {
+	if conversation == nil || conversation.BusinessID == nil || *conversation.BusinessID == "" || s.businessRepo == nil {
+		return nil
+	}
+	business, err := s.businessRepo.GetByID(ctx, *conversation.BusinessID)
+	if err != nil {
+		return nil
+	}
+	return business
+}
+
+// conversationBusinessByID is conversationBusiness for callers holding only
+// the conversation ID.
+func (s *ChatService) conversationBusinessByID(ctx context.Context, conversationID string) *models.BusinessProfile {
+	if s.businessRepo == nil {
+		return nil
+	}
+	conversation, err := s.conversationRepo.GetByID(ctx, conversationID)
+	if err != nil {
+		return nil
+	}
+	return s.conversationBusiness(ctx, conversation)
+}
+
+// businessUserInfo presents a business owner as their business in a
+// business-scoped chat, instead of their personal profile.
+func businessUserInfo(business *models.BusinessProfile) *models.UserInfo {
+	return &models.UserInfo{
+		UserID:      business.UserID,
+		FirstName:   business.Name,
+		FullName:    business.Name,
+		Avatar:      business.Avatar,
+		AvatarColor: business.AvatarColor,
+		BusinessID:  &business.ID,
+	}
 }
 
 // GetMessages retrieves messages in a conversation
 func (s *ChatService) GetMessages(ctx context.Context, userID, conversationID string, limit, offset int) ([]*models.MessageResponse, error) {
+	responses, _, err := s.listMessages(ctx, userID, &models.GetMessagesFilter{
+		ConversationID: conversationID,
+		ViewerID:       userID,
+		Limit:          limit,
+		Offset:         offset,
+	})
+	return responses, err
+}
+
+// GetMessagesBefore pages history backwards from beforeSeq, newest first.
+// Unlike offset paging it neither skips nor repeats messages when new ones
+// arrive while the user scrolls.
+func (s *ChatService) GetMessagesBefore(ctx context.Context, userID, conversationID string, beforeSeq int64, limit int) ([]*models.MessageResponse, error) {
+	responses, _, err := s.listMessages(ctx, userID, &models.GetMessagesFilter{
+		ConversationID: conversationID,
+		ViewerID:       userID,
+		BeforeSeq:      &beforeSeq,
+		Limit:          limit,
+	})
+	return responses, err
+}
+
+// SyncMessages returns the messages after afterSeq, oldest first, for a
+// client catching up after a reconnect. Calling again with NextSeq until
+// HasMore is false delivers every message exactly once.
+func (s *ChatService) SyncMessages(ctx context.Context, userID, conversationID string, afterSeq int64, limit int) (*models.MessageSyncPage, error) {
+	responses, messages, err := s.listMessages(ctx, userID, &models.GetMessagesFilter{
+		ConversationID: conversationID,
+		ViewerID:       userID,
+		AfterSeq:       &afterSeq,
+		Limit:          limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if responses == nil {
+		responses = []*models.MessageResponse{}
+	}
+
+	// The cursor follows the rows read, not the responses, so a message
+	// that failed to enrich is not fetched again forever.
+	page := &models.MessageSyncPage{Messages: responses, NextSeq: afterSeq}
+	if n := len(messages); n > 0 {
+		page.NextSeq = messages[n-1].Seq
+		page.HasMore = n >= limit
+	}
+	return page, nil
+}
+
+// listMessages checks access, lists messages and enriches them. It returns
+// the rows read as well, since a message that fails to enrich is left out
+// of the responses.
+func (s *ChatService) listMessages(ctx context.Context, userID string, filter *models.GetMessagesFilter) ([]*models.MessageResponse, []*models.Message, error) {
+	conversationID := filter.ConversationID
+
 	// Check if user is participant
 	isParticipant, err := s.conversationRepo.IsParticipant(ctx, conversationID, userID)
 	if err != nil {
@@ -184,19 +480,11 @@ func (s *ChatService) GetMessages(ctx context.Context, userID, conversationID st
 			zap.Error(err),
 			zap.String("conversation_id", conversationID),
 		)
-		return nil, utils.NewInternalError("Failed to verify access", err)
+		return nil, nil, utils.NewInternalError("Failed to verify access", err)
 	}
 
 	if !isParticipant {
-		return nil, utils.NewForbiddenError("You don't have access to this conversation", nil)
-	}
-
-	// Get messages
-	filter := &models.GetMessagesFilter{
-		ConversationID: conversationID,
-		ViewerID:       userID,
-		Limit:          limit,
-		Offset:         offset,
+		return nil, nil, utils.NewForbiddenError("You don't have access to this conversation", nil)
 	}
 
 	messages, err := s.messageRepo.List(ctx, filter)
@@ -205,13 +493,14 @@ func (s *ChatService) GetMessages(ctx context.Context, userID, conversationID st
 			zap.Error(err),
 			zap.String("conversation_id", conversationID),
 		)
-		return nil, utils.NewInternalError("Failed to get messages", err)
+		return nil, nil, utils.NewInternalError("Failed to get messages", err)
 	}
 
 	// Enrich messages
+	business := s.conversationBusinessByID(ctx, conversationID)
 	var enrichedMessages []*models.MessageResponse
 	for _, message := range messages {
-		enriched, err := s.enrichMessage(ctx, message, userID)
+		enriched, err := s.enrichMessage(ctx, message, userID, business)
 		if err != nil {
 			s.logger.Warn("Failed to enrich message",
 				zap.Error(err),
@@ -222,7 +511,7 @@ func (s *ChatService) GetMessages(ctx context.Context, userID, conversationID st
 		enrichedMessages = append(enrichedMessages, enriched)
 	}
 
-	return enrichedMessages, nil
+	return enrichedMessages, messages, nil
 }
 
 // MarkConversationAsRead marks all unread messages in a conversation as read
@@ -251,6 +540,10 @@ func (s *ChatService) MarkConversationAsRead(ctx context.Context, userID, conver
 		zap.String("user_id", userID),
 	)
 
+	if s.unreadCounters != nil {
+		s.unreadCounters.Reset(ctx, userID, conversationID)
+	}
+
 	// Also clear the MESSAGE notifications for this conversation from the bell
 	// badge, so the user doesn't have to open the notification screen to mark
 	// them read. Best-effort — never fail the read flow on this.
@@ -261,6 +554,39 @@ func (s *ChatService) MarkConversationAsRead(ctx context.Context, userID, conver
 	return nil
 }
 
+// GetUnreadBadge returns the app icon badge: unread messages across all
+// conversations plus unread notifications.
+func (s *ChatService) GetUnreadBadge(ctx context.Context, userID string) (*models.UnreadBadge, error) {
+	badge := &models.UnreadBadge{}
+
+	if s.unreadCounters != nil {
+		total, err := s.unreadCounters.Total(ctx, userID)
+		if err != nil {
+			return nil, utils.NewInternalError("Failed to get unread messages", err)
+		}
+		badge.Messages = total
+	} else {
+		counts, err := s.messageRepo.GetUnreadCountsByUser(ctx, userID)
+		if err != nil {
+			return nil, utils.NewInternalError("Failed to get unread messages", err)
+		}
+		for _, n := range counts {
+			badge.Messages += n
+		}
+	}
+
+	if s.notificationService != nil {
+		notifications, err := s.notificationService.GetUnreadCount(ctx, userID, nil)
+		if err != nil {
+			return nil, err
+		}
+		badge.Notifications = notifications
+	}
+
+	badge.Total = badge.Messages + badge.Notifications
+	return badge, nil
+}
+
 // DeleteMessage soft-deletes a message for everyone in the conversation
 // (sender-only). Broadcasts a WS "message_deleted" frame to the other
 // participant so their UI removes the bubble in real time.
@@ -409,7 +735,7 @@ func (s *ChatService) EditMessage(ctx context.Context, userID, messageID, conten
 		go s.broadcastMessageEdited(updated)
 	}
 
-	return s.enrichMessage(ctx, updated, userID)
+	return s.enrichMessage(ctx, updated, userID, s.conversationBusinessByID(ctx, updated.ConversationID))
 }
 
 // broadcastMessageEdited notifies the other conversation participant that a
@@ -460,33 +786,43 @@ func (s *ChatService) broadcastMessageEdited(message *models.Message) {
 
 // ReactToMessage toggles an emoji reaction by the user on a message. add=true
 // adds the reaction, add=false removes it. Authorizes the user as a participant
-// and broadcasts the change to the other participant over WebSocket.
-func (s *ChatService) ReactToMessage(ctx context.Context, userID, messageID, emoji string, add bool) error {
+// and broadcasts the change to the other participant over WebSocket. Returns
+// the message's aggregated reactions after the change, relative to the user.
+func (s *ChatService) ReactToMessage(ctx context.Context, userID, messageID, emoji string, add bool) ([]models.MessageReaction, error) {
 	message, err := s.messageRepo.GetByID(ctx, messageID)
 	if err != nil {
-		return utils.NewNotFoundError("Message not found", err)
+		return nil, utils.NewNotFoundError("Message not found", err)
 	}
 
 	isParticipant, perr := s.conversationRepo.IsParticipant(ctx, message.ConversationID, userID)
 	if perr != nil {
-		return utils.NewInternalError("Failed to verify access", perr)
+		return nil, utils.NewInternalError("Failed to verify access", perr)
 	}
 	if !isParticipant {
-		return utils.NewForbiddenError("You don't have access to this message", nil)
+		return nil, utils.NewForbiddenError("You don't have access to this message", nil)
 	}
 
 	if add {
 		if err := s.messageRepo.AddReaction(ctx, messageID, userID, emoji); err != nil {
-			return utils.NewInternalError("Failed to add reaction", err)
+			return nil, utils.NewInternalError("Failed to add reaction", err)
 		}
 	} else {
 		if err := s.messageRepo.RemoveReaction(ctx, messageID, userID, emoji); err != nil {
-			return utils.NewInternalError("Failed to remove reaction", err)
+			return nil, utils.NewInternalError("Failed to remove reaction", err)
 		}
 	}
 
 	go s.broadcastReaction(message, userID, emoji, add)
-	return nil
+
+	reactions, err := s.messageRepo.GetReactions(ctx, []string{messageID}, userID)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load reactions", err)
+	}
+	out := reactions[messageID]
+	if out == nil {
+		out = []models.MessageReaction{}
+	}
+	return out, nil
 }
 
 // broadcastReaction pushes a reaction add/remove to the other participant.
@@ -494,10 +830,15 @@ func (s *ChatService) broadcastReaction(message *models.Message, userID, emoji s
 	if s.wsHub == nil {
 		return
 	}
-	other, oerr := s.conversationRepo.GetOtherParticipantID(context.Background(), message.ConversationID, userID)
+	ctx := context.Background()
+	other, oerr := s.conversationRepo.GetOtherParticipantID(ctx, message.ConversationID, userID)
 	if oerr != nil || other == "" {
 		return
 	}
+	reactions := []models.MessageReaction{}
+	if byMessage, err := s.messageRepo.GetReactions(ctx, []string{message.ID}, other); err == nil && byMessage[message.ID] != nil {
+		reactions = byMessage[message.ID]
+	}
 	frame := models.WSMessage{
 		Type: "message_reaction",
 		Payload: models.WSReactionPayload{
@@ -506,6 +847,7 @@ func (s *ChatService) broadcastReaction(message *models.Message, userID, emoji s
 			UserID:         userID,
 			Emoji:          emoji,
 			Added:          added,
+			Reactions:      reactions,
 		},
 	}
 	if err := s.wsHub.SendToUser(other, frame); err != nil {
@@ -513,23 +855,216 @@ func (s *ChatService) broadcastReaction(message *models.Message, userID, emoji s
 	}
 }
 
-// enrichConversation enriches a conversation with participant and last message info
-func (s *ChatService) enrichConversation(ctx context.Context, conversation *models.Conversation, viewerID string) (*models.ConversationResponse, error) {
+// expiredMessagesBatch bounds one DeleteExpired call of the purge.
+const expiredMessagesBatch = 500
+
+// SetDisappearingMessages turns disappearing messages on (ttl "24h" or
+// "7d") or off ("off") for a conversation. Either participant may change it;
+// it applies to messages sent afterwards. The change is recorded in the
+// chat as a SYSTEM message, which is returned (nil when nothing changed).
+func (s *ChatService) SetDisappearingMessages(ctx context.Context, userID, conversationID, ttl string) (*models.MessageResponse, error) {
+	var ttlSeconds *int
+	var content string
+	switch ttl {
+	case "off":
+		content = "Disappearing messages turned off"
+	case "24h":
+		v := models.DisappearingTTL24h
+		ttlSeconds = &v
+		content = "Disappearing messages turned on. New messages disappear after 24 hours"
+	case "7d":
+		v := models.DisappearingTTL7d
+		ttlSeconds = &v
+		content = "Disappearing messages turned on. New messages disappear after 7 days"
+	default:
+		return nil, utils.NewBadRequestError("ttl must be one of: off 24h 7d", nil)
+	}
+
+	conversation, err := s.conversationRepo.GetByID(ctx, conversationID)
+	if err != nil {
+		return nil, utils.NewNotFoundError("Conversation not found", err)
+	}
+	if conversation.Participant1ID != userID && conversation.Participant2ID != userID {
+		return nil, utils.NewForbiddenError("You don't have access to this conversation", nil)
+	}
+	if equalTTL(conversation.DisappearingTTL, ttlSeconds) {
+		return nil, nil
+	}
+
+	if err := s.conversationRepo.SetDisappearingTTL(ctx, conversationID, ttlSeconds); err != nil {
+		return nil, utils.NewInternalError("Failed to update conversation", err)
+	}
+	conversation.DisappearingTTL = ttlSeconds
+
+	message := &models.Message{
+		ID:             uuid.New().String(),
+		ConversationID: conversationID,
+		SenderID:       userID,
+		Content:        &content,
+		MessageType:    models.MessageTypeSystem,
+		CreatedAt:      time.Now(),
+	}
+	if err := s.messageRepo.Create(ctx, message); err != nil {
+		return nil, utils.NewInternalError("Failed to record setting change", err)
+	}
+	// A settings notice isn't something to answer; keep it out of unread
+	// badges.
+	if err := s.messageRepo.MarkAsRead(ctx, message.ID); err != nil {
+		s.logger.Warn("Failed to mark system message read", zap.Error(err), zap.String("message_id", message.ID))
+	}
+	if err := s.conversationRepo.UpdateLastMessageAt(ctx, conversationID); err != nil {
+		s.logger.Warn("Failed to update last_message_at", zap.Error(err), zap.String("conversation_id", conversationID))
+	}
+
+	s.logger.Info("Disappearing messages changed",
+		zap.String("conversation_id", conversationID),
+		zap.String("user_id", userID),
+		zap.String("ttl", ttl),
+	)
+
+	if s.wsHub != nil {
+		go s.broadcastDisappearingChanged(conversation, message)
+	}
+	return s.enrichMessage(ctx, message, userID, s.conversationBusiness(ctx, conversation))
+}
+
+func equalTTL(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// broadcastDisappearingChanged tells the other participant the setting
+// changed, carrying the SYSTEM message so it shows up without a refetch.
+func (s *ChatService) broadcastDisappearingChanged(conversation *models.Conversation, message *models.Message) {
+	other := conversation.Participant1ID
+	if other == message.SenderID {
+		other = conversation.Participant2ID
+	}
+	frame := models.WSMessage{
+		Type: "disappearing_messages_changed",
+		Payload: models.WSDisappearingPayload{
+			ConversationID:  conversation.ID,
+			DisappearingTTL: conversation.DisappearingTTL,
+			ChangedBy:       message.SenderID,
+			MessageID:       message.ID,
+			Content:         message.Content,
+			BusinessID:      conversation.BusinessID,
+			CreatedAt:       message.CreatedAt,
+		},
+	}
+	if err := s.wsHub.SendToUser(other, frame); err != nil {
+		s.logger.Debug("Failed to send WS disappearing_messages_changed", zap.Error(err), zap.String("recipient_id", other))
+	}
+}
+
+// PurgeExpiredMessages hard-deletes disappearing messages past their
+// expiry, removes their stored attachments and tells both participants to
+// drop the bubbles. Run on a ticker by the leader.
+func (s *ChatService) PurgeExpiredMessages(ctx context.Context) error {
+	purged := 0
+	for {
+		messages, err := s.messageRepo.DeleteExpired(ctx, expiredMessagesBatch)
+		if err != nil {
+			return err
+		}
+		for _, message := range messages {
+			s.deleteAttachment(ctx, message)
+		}
+		s.broadcastExpired(ctx, messages)
+		purged += len(messages)
+		if len(messages) < expiredMessagesBatch {
+			break
+		}
+	}
+	if purged > 0 {
+		s.logger.Info("Expired messages purged", zap.Int("count", purged))
+	}
+	return nil
+}
+
+// deleteAttachment removes the stored file of an expired media message.
+// A failed delete is logged; the message itself is already gone.
+func (s *ChatService) deleteAttachment(ctx context.Context, message *models.Message) {
+	if s.attachmentStorage == nil || message.Content == nil {
+		return
+	}
+	switch message.MessageType {
+	case models.MessageTypeImage, models.MessageTypeFile, models.MessageTypeVoice:
+	default:
+		return
+	}
+	url := strings.TrimSpace(*message.Content)
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return
+	}
+	if err := s.attachmentStorage.DeleteImage(ctx, url); err != nil {
+		s.logger.Warn("Failed to delete expired message attachment",
+			zap.Error(err),
+			zap.String("message_id", message.ID),
+		)
+	}
+}
+
+// broadcastExpired sends message_deleted frames for purged messages to both
+// participants, so the sender's other devices drop them too.
+func (s *ChatService) broadcastExpired(ctx context.Context, messages []*models.Message) {
+	if s.wsHub == nil || len(messages) == 0 {
+		return
+	}
+	conversations := make(map[string]*models.Conversation)
+	for _, message := range messages {
+		conversation, ok := conversations[message.ConversationID]
+		if !ok {
+			c, err := s.conversationRepo.GetByID(ctx, message.ConversationID)
+			if err != nil {
+				c = nil
+			}
+			conversations[message.ConversationID] = c
+			conversation = c
+		}
+		if conversation == nil {
+			continue
+		}
+		frame := models.WSMessage{
+			Type: "message_deleted",
+			Payload: models.WSMessageDeletedPayload{
+				ConversationID: message.ConversationID,
+				MessageID:      message.ID,
+				BusinessID:     conversation.BusinessID,
+			},
+		}
+		for _, participant := range []string{conversation.Participant1ID, conversation.Participant2ID} {
+			if err := s.wsHub.SendToUser(participant, frame); err != nil {
+				s.logger.Debug("Failed to send WS message_deleted", zap.Error(err), zap.String("recipient_id", participant))
+			}
+		}
+	}
+}
+
+// enrichConversation enriches a conversation with participant and last message info.
+// unread holds the viewer's unread counts by conversation; nil counts from
+// the database.
+func (s *ChatService) enrichConversation(ctx context.Context, conversation *models.Conversation, viewerID string, unread map[string]int) (*models.ConversationResponse, error) {
 	response := &models.ConversationResponse{
-		ID:            conversation.ID,
-		LastMessageAt: conversation.LastMessageAt,
-		CreatedAt:     conversation.CreatedAt,
+		ID:              conversation.ID,
+		LastMessageAt:   conversation.LastMessageAt,
+		CreatedAt:       conversation.CreatedAt,
+		UpdatedAt:       conversation.UpdatedAt,
+		DisappearingTTL: conversation.DisappearingTTL,
 	}
 
 	// Attach business reference when this conversation is business-scoped.
-	if conversation.BusinessID != nil && *conversation.BusinessID != "" && s.businessRepo != nil {
-		biz, berr := s.businessRepo.GetByID(ctx, *conversation.BusinessID)
-		if berr == nil && biz != nil {
-			response.Business = &models.ConversationBizRef{
-				ID:     biz.ID,
-				Name:   biz.Name,
-				Avatar: biz.Avatar,
-			}
+	biz := s.conversationBusiness(ctx, conversation)
+	if biz != nil {
+		response.Business = &models.ConversationBizRef{
+			ID:     biz.ID,
+			Name:   biz.Name,
+			Avatar: biz.Avatar,
+		}
+		if viewerID != biz.UserID {
+			response.Business.Responsiveness = s.responsiveness.For(ctx, biz.ID)
 		}
 	}
 
@@ -539,9 +1074,11 @@ func (s *ChatService) enrichConversation(ctx context.Context, conversation *mode
 		return nil, err
 	}
 
-	// Get other participant's profile
-	profile, err := s.userRepo.GetProfileByUserID(ctx, otherParticipantID)
-	if err == nil {
+	// Get other participant's profile. The customer sees the business
+	// rather than the owner behind it.
+	if biz != nil && biz.UserID == otherParticipantID {
+		response.OtherParticipant = businessUserInfo(biz)
+	} else if profile, err := s.userRepo.GetProfileByUserID(ctx, otherParticipantID); err == nil {
 		firstName := ""
 		if profile.FirstName != nil {
 			firstName = *profile.FirstName
@@ -578,16 +1115,19 @@ func (s *ChatService) enrichConversation(ctx context.Context, conversation *mode
 	}
 
 	// Get unread count
-	unreadCount, err := s.messageRepo.GetUnreadCount(ctx, conversation.ID, viewerID)
-	if err == nil {
+	if unread != nil {
+		response.UnreadCount = unread[conversation.ID]
+	} else if unreadCount, err := s.messageRepo.GetUnreadCount(ctx, conversation.ID, viewerID); err == nil {
 		response.UnreadCount = unreadCount
 	}
 
 	return response, nil
 }
 
-// enrichMessage enriches a message with sender info
-func (s *ChatService) enrichMessage(ctx context.Context, message *models.Message, viewerID string) (*models.MessageResponse, error) {
+// enrichMessage enriches a message with sender info. business is the
+// business the conversation is scoped to (nil for personal chats); messages
+// from its owner are shown as sent by the business.
+func (s *ChatService) enrichMessage(ctx context.Context, message *models.Message, viewerID string, business *models.BusinessProfile) (*models.MessageResponse, error) {
 	response := &models.MessageResponse{
 		ID:             message.ID,
 		ConversationID: message.ConversationID,
@@ -597,6 +1137,23 @@ func (s *ChatService) enrichMessage(ctx context.Context, message *models.Message
 		IsRead:         message.ReadAt != nil,
 		CreatedAt:      message.CreatedAt,
 		EditedAt:       message.EditedAt,
+		ExpiresAt:      message.ExpiresAt,
+		Seq:            message.Seq,
+	}
+
+	// Attached catalog product card. A deleted product simply drops the card.
+	if message.BusinessProductID != nil && *message.BusinessProductID != "" && s.productService != nil {
+		response.Product = s.productService.GetProduct(ctx, *message.BusinessProductID)
+	}
+
+	// Shared post card. A deleted post simply drops the card.
+	if message.SharedPostID != nil && *message.SharedPostID != "" && s.postRepo != nil {
+		response.SharedPost = s.sharedPostPreview(ctx, *message.SharedPostID)
+	}
+
+	// Sticker image. A deleted pack leaves a STICKER message without it.
+	if message.StickerID != nil && *message.StickerID != "" && s.stickerService != nil {
+		response.Sticker = s.stickerService.GetSticker(ctx, *message.StickerID)
 	}
 
 	// Quoted message preview (reply target).
@@ -617,8 +1174,9 @@ func (s *ChatService) enrichMessage(ctx context.Context, message *models.Message
 	}
 
 	// Get sender's profile
-	profile, err := s.userRepo.GetProfileByUserID(ctx, message.SenderID)
-	if err == nil {
+	if business != nil && business.UserID == message.SenderID {
+		response.Sender = businessUserInfo(business)
+	} else if profile, err := s.userRepo.GetProfileByUserID(ctx, message.SenderID); err == nil {
 		firstName := ""
 		if profile.FirstName != nil {
 			firstName = *profile.FirstName
@@ -645,6 +1203,87 @@ func (s *ChatService) enrichMessage(ctx context.Context, message *models.Message
 	return response, nil
 }
 
+// SharePost sends postID to each recipient as a POST message with the
+// sender's optional note. Recipients are independent: a block or throttle
+// on one is reported in Failed without stopping the rest.
+func (s *ChatService) SharePost(ctx context.Context, senderID, postID string, req *models.ShareToChatRequest) (*models.ShareToChatResponse, error) {
+	if s.postRepo == nil {
+		return nil, utils.NewBadRequestError("Sharing posts to chat is not available", nil)
+	}
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil || post == nil || post.DeletedAt != nil {
+		return nil, utils.NewNotFoundError("Post not found", err)
+	}
+	if !postShareable(post) {
+		return nil, utils.NewBadRequestError("This post can't be shared", nil)
+	}
+
+	out := &models.ShareToChatResponse{
+		Sent:   make([]*models.MessageResponse, 0, len(req.RecipientIDs)),
+		Failed: make([]*models.ShareToChatFailure, 0),
+	}
+	seen := make(map[string]bool, len(req.RecipientIDs))
+	for _, recipientID := range req.RecipientIDs {
+		if seen[recipientID] {
+			continue
+		}
+		seen[recipientID] = true
+
+		msg, err := s.SendMessage(ctx, senderID, &models.SendMessageRequest{
+			RecipientID:  recipientID,
+			Content:      req.Message,
+			MessageType:  models.MessageTypePost,
+			SharedPostID: &post.ID,
+		})
+		if err != nil {
+			reason := "Failed to send"
+			if appErr, ok := err.(*utils.AppError); ok {
+				reason = appErr.Message
+			}
+			out.Failed = append(out.Failed, &models.ShareToChatFailure{RecipientID: recipientID, Error: reason})
+			continue
+		}
+		out.Sent = append(out.Sent, msg)
+	}
+
+	s.logger.Info("Post shared to chat",
+		zap.String("post_id", postID),
+		zap.String("user_id", senderID),
+		zap.Int("sent", len(out.Sent)),
+		zap.Int("failed", len(out.Failed)),
+	)
+	return out, nil
+}
+
+// sharedPostPreview builds the card for a POST message, or nil when the
+// post is gone.
+func (s *ChatService) sharedPostPreview(ctx context.Context, postID string) *models.SharedPostPreview {
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil || post == nil || post.DeletedAt != nil {
+		return nil
+	}
+	preview := &models.SharedPostPreview{
+		ID:          post.ID,
+		Type:        post.Type,
+		Title:       post.Title,
+		Description: post.Description,
+		Price:       post.Price,
+		Currency:    post.Currency,
+	}
+	// Rune-safe truncation — post text is often Dari/Pashto (multibyte).
+	if preview.Description != nil {
+		if r := []rune(*preview.Description); len(r) > 200 {
+			d := string(r[:200]) + "…"
+			preview.Description = &d
+		}
+	}
+	if attachments, aErr := s.postRepo.GetAttachmentsByPostID(ctx, postID); aErr == nil && len(attachments) > 0 {
+		cover := attachments[0].Photo
+		preview.Cover = &cover
+	}
+	return preview
+}
+
 // notifyMessageSent sends a WebSocket notification to the recipient and
 // triggers a persisted notification + FCM push so the user sees it when offline.
 // [conversation] is optional — when supplied and BusinessID is set, the
@@ -657,6 +1296,10 @@ func (s *ChatService) notifyMessageSent(message *models.Message, recipientID str
 		if conversation != nil && conversation.BusinessID != nil && *conversation.BusinessID != "" {
 			businessID = conversation.BusinessID
 		}
+		var sticker *models.Sticker
+		if message.StickerID != nil && s.stickerService != nil {
+			sticker = s.stickerService.GetSticker(context.Background(), *message.StickerID)
+		}
 		wsMessage := models.WSMessage{
 			Type: "message",
 			Payload: models.WSMessagePayload{
@@ -666,6 +1309,7 @@ func (s *ChatService) notifyMessageSent(message *models.Message, recipientID str
 				BusinessID:     businessID,
 				Content:        message.Content,
 				MessageType:    message.MessageType,
+				Sticker:        sticker,
 				CreatedAt:      message.CreatedAt,
 			},
 		}
@@ -712,6 +1356,15 @@ func (s *ChatService) notifyMessageSent(message *models.Message, recipientID str
 		preview = "📎 File"
 	case models.MessageTypeVoice:
 		preview = "🎤 Voice message"
+	case models.MessageTypePost:
+		preview = "🔗 Shared a post"
+	case models.MessageTypeSticker:
+		preview = "Sent a sticker"
+		if message.StickerID != nil && s.stickerService != nil {
+			if sticker := s.stickerService.GetSticker(ctx, *message.StickerID); sticker != nil && sticker.Emoji != nil {
+				preview = *sticker.Emoji + " Sticker"
+			}
+		}
 	default:
 		if message.Content != nil && *message.Content != "" {
 			c := *message.Content
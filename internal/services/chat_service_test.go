@@ -3,12 +3,15 @@ package services
 import (
 	"context"
 	"errors"
+	"net/http"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/hamsaya/backend/internal/mocks"
 	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -245,6 +248,50 @@ func TestChatService_GetMessages(t *testing.T) {
 	})
 }
 
+func TestChatService_SyncMessages(t *testing.T) {
+	setup := func(messages []*models.Message) (*ChatService, *mocks.MockMessageRepository) {
+		convRepo := &mocks.MockConversationRepository{}
+		msgRepo := &mocks.MockMessageRepository{}
+		userRepo := new(mocks.MockUserRepository)
+
+		convRepo.On("IsParticipant", mock.Anything, "conv-1", "user-1").Return(true, nil)
+		msgRepo.On("List", mock.Anything, mock.MatchedBy(func(f *models.GetMessagesFilter) bool {
+			return f.AfterSeq != nil && *f.AfterSeq == 10 && f.Limit == 2
+		})).Return(messages, nil)
+		userRepo.On("GetProfileByUserID", mock.Anything, "user-1").
+			Return(&models.Profile{ID: "user-1"}, nil)
+		msgRepo.On("GetReactions", mock.Anything, mock.Anything, mock.Anything).Return(map[string][]models.MessageReaction{}, nil).Maybe()
+		return newTestChatService(convRepo, msgRepo, userRepo), msgRepo
+	}
+
+	t.Run("full page has more", func(t *testing.T) {
+		m1 := newTestMessage("msg-1", "conv-1", "user-1")
+		m1.Seq = 11
+		m2 := newTestMessage("msg-2", "conv-1", "user-1")
+		m2.Seq = 13
+		svc, msgRepo := setup([]*models.Message{m1, m2})
+
+		page, err := svc.SyncMessages(context.Background(), "user-1", "conv-1", 10, 2)
+		require.NoError(t, err)
+		require.Len(t, page.Messages, 2)
+		assert.Equal(t, int64(11), page.Messages[0].Seq)
+		assert.Equal(t, int64(13), page.NextSeq)
+		assert.True(t, page.HasMore)
+		msgRepo.AssertExpectations(t)
+	})
+
+	t.Run("caught up keeps the cursor", func(t *testing.T) {
+		svc, _ := setup([]*models.Message{})
+
+		page, err := svc.SyncMessages(context.Background(), "user-1", "conv-1", 10, 2)
+		require.NoError(t, err)
+		assert.Empty(t, page.Messages)
+		assert.NotNil(t, page.Messages)
+		assert.Equal(t, int64(10), page.NextSeq)
+		assert.False(t, page.HasMore)
+	})
+}
+
 func TestChatService_MarkConversationAsRead(t *testing.T) {
 	t.Run("not participant", func(t *testing.T) {
 		convRepo := &mocks.MockConversationRepository{}
@@ -413,3 +460,253 @@ func TestChatService_DeleteMessageForMe(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestChatService_BusinessInbox(t *testing.T) {
+	bizID := "biz-1"
+	business := &models.BusinessProfile{ID: bizID, UserID: "owner-1", Name: "Kabul Bakery"}
+
+	t.Run("only the owner reads the inbox", func(t *testing.T) {
+		convRepo := &mocks.MockConversationRepository{}
+		bizRepo := new(mocks.MockBusinessRepository)
+		bizRepo.On("GetByID", mock.Anything, bizID).Return(business, nil)
+
+		svc := NewChatService(convRepo, &mocks.MockMessageRepository{}, new(mocks.MockUserRepository), bizRepo, nil, nil, nil, zap.NewNop())
+		_, err := svc.GetConversations(context.Background(), "someone-else", 10, 0, &bizID)
+
+		var appErr *utils.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusForbidden, appErr.Code)
+		convRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+	})
+
+	t.Run("customer sees the business, not the owner", func(t *testing.T) {
+		convRepo := &mocks.MockConversationRepository{}
+		msgRepo := &mocks.MockMessageRepository{}
+		userRepo := new(mocks.MockUserRepository)
+		bizRepo := new(mocks.MockBusinessRepository)
+
+		conv := newTestConversation("conv-1")
+		conv.BusinessID = &bizID
+		convRepo.On("List", mock.Anything, mock.AnythingOfType("*models.GetConversationsFilter")).
+			Return([]*models.Conversation{conv}, nil)
+		convRepo.On("GetOtherParticipantID", mock.Anything, "conv-1", "customer-1").Return("owner-1", nil)
+		bizRepo.On("GetByID", mock.Anything, bizID).Return(business, nil)
+		msgRepo.On("GetLastMessage", mock.Anything, "conv-1", "customer-1").Return(nil, nil)
+		msgRepo.On("GetUnreadCount", mock.Anything, "conv-1", "customer-1").Return(0, nil)
+
+		svc := NewChatService(convRepo, msgRepo, userRepo, bizRepo, nil, nil, nil, zap.NewNop())
+		result, err := svc.GetConversations(context.Background(), "customer-1", 10, 0, nil)
+
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		other := result[0].OtherParticipant
+		require.NotNil(t, other)
+		assert.Equal(t, "owner-1", other.UserID)
+		assert.Equal(t, "Kabul Bakery", other.FullName)
+		require.NotNil(t, other.BusinessID)
+		assert.Equal(t, bizID, *other.BusinessID)
+		userRepo.AssertNotCalled(t, "GetProfileByUserID", mock.Anything, "owner-1")
+	})
+
+	t.Run("owner messages are sent as the business", func(t *testing.T) {
+		convRepo := &mocks.MockConversationRepository{}
+		msgRepo := &mocks.MockMessageRepository{}
+		userRepo := new(mocks.MockUserRepository)
+		bizRepo := new(mocks.MockBusinessRepository)
+
+		conv := newTestConversation("conv-1")
+		conv.BusinessID = &bizID
+		convRepo.On("IsParticipant", mock.Anything, "conv-1", "customer-1").Return(true, nil)
+		convRepo.On("GetByID", mock.Anything, "conv-1").Return(conv, nil)
+		bizRepo.On("GetByID", mock.Anything, bizID).Return(business, nil)
+		msgRepo.On("List", mock.Anything, mock.AnythingOfType("*models.GetMessagesFilter")).Return([]*models.Message{
+			newTestMessage("msg-1", "conv-1", "customer-1"),
+			newTestMessage("msg-2", "conv-1", "owner-1"),
+		}, nil)
+		msgRepo.On("GetReactions", mock.Anything, mock.Anything, "customer-1").Return(nil, nil)
+		userRepo.On("GetProfileByUserID", mock.Anything, "customer-1").Return(&models.Profile{ID: "customer-1"}, nil)
+
+		svc := NewChatService(convRepo, msgRepo, userRepo, bizRepo, nil, nil, nil, zap.NewNop())
+		result, err := svc.GetMessages(context.Background(), "customer-1", "conv-1", 10, 0)
+
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Nil(t, result[0].Sender.BusinessID)
+		require.NotNil(t, result[1].Sender.BusinessID)
+		assert.Equal(t, "Kabul Bakery", result[1].Sender.FullName)
+		userRepo.AssertNotCalled(t, "GetProfileByUserID", mock.Anything, "owner-1")
+	})
+}
+
+func TestChatService_SendSticker(t *testing.T) {
+	const stickerID = "5b7e1c2f-8d5b-4c1e-9a55-0f8a2b7e1c01"
+	sticker := &models.Sticker{ID: stickerID, PackID: "pack-1", ImageURL: "https://cdn.example.com/s.png"}
+
+	t.Run("unknown or hidden sticker rejected", func(t *testing.T) {
+		stickerRepo := new(mocks.MockStickerRepository)
+		stickerRepo.On("GetSticker", mock.Anything, stickerID, true).Return(nil, repositories.ErrStickerNotFound)
+		svc := newTestChatService(&mocks.MockConversationRepository{}, &mocks.MockMessageRepository{}, new(mocks.MockUserRepository)).
+			WithStickers(NewStickerService(stickerRepo, zap.NewNop()))
+
+		_, err := svc.SendMessage(context.Background(), "sender-1", &models.SendMessageRequest{
+			RecipientID: "recv-1",
+			MessageType: models.MessageTypeSticker,
+			StickerID:   ptrStr(stickerID),
+		})
+		requireAppErrorCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("sticker_id required", func(t *testing.T) {
+		svc := newTestChatService(&mocks.MockConversationRepository{}, &mocks.MockMessageRepository{}, new(mocks.MockUserRepository)).
+			WithStickers(NewStickerService(new(mocks.MockStickerRepository), zap.NewNop()))
+
+		_, err := svc.SendMessage(context.Background(), "sender-1", &models.SendMessageRequest{
+			RecipientID: "recv-1",
+			MessageType: models.MessageTypeSticker,
+		})
+		requireAppErrorCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("stores the sticker and returns it", func(t *testing.T) {
+		convRepo := &mocks.MockConversationRepository{}
+		msgRepo := &mocks.MockMessageRepository{}
+		userRepo := new(mocks.MockUserRepository)
+		stickerRepo := new(mocks.MockStickerRepository)
+		stickerRepo.On("GetSticker", mock.Anything, stickerID, true).Return(sticker, nil)
+		stickerRepo.On("GetSticker", mock.Anything, stickerID, false).Return(sticker, nil)
+		convRepo.On("GetOrCreate", mock.Anything, "sender-1", "recv-1", mock.Anything).Return(newTestConversation("conv-1"), nil)
+		msgRepo.On("Create", mock.Anything, mock.MatchedBy(func(m *models.Message) bool {
+			return m.StickerID != nil && *m.StickerID == stickerID && m.MessageType == models.MessageTypeSticker
+		})).Return(nil)
+		convRepo.On("UpdateLastMessageAt", mock.Anything, "conv-1").Return(nil)
+		userRepo.On("GetProfileByUserID", mock.Anything, "sender-1").Return(&models.Profile{ID: "sender-1"}, nil)
+		msgRepo.On("GetReactions", mock.Anything, mock.Anything, mock.Anything).Return(map[string][]models.MessageReaction{}, nil).Maybe()
+
+		svc := newTestChatService(convRepo, msgRepo, userRepo).WithStickers(NewStickerService(stickerRepo, zap.NewNop()))
+		resp, err := svc.SendMessage(context.Background(), "sender-1", &models.SendMessageRequest{
+			RecipientID: "recv-1",
+			MessageType: models.MessageTypeSticker,
+			StickerID:   ptrStr(stickerID),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, sticker, resp.Sticker)
+		msgRepo.AssertExpectations(t)
+	})
+}
+
+func TestChatService_ReactToMessage(t *testing.T) {
+	convRepo := &mocks.MockConversationRepository{}
+	msgRepo := &mocks.MockMessageRepository{}
+	msg := newTestMessage("msg-1", "conv-1", "sender-1")
+	msgRepo.On("GetByID", mock.Anything, "msg-1").Return(msg, nil)
+	convRepo.On("IsParticipant", mock.Anything, "conv-1", "user-1").Return(true, nil)
+	msgRepo.On("AddReaction", mock.Anything, "msg-1", "user-1", "👍").Return(nil)
+	msgRepo.On("GetReactions", mock.Anything, []string{"msg-1"}, "user-1").Return(map[string][]models.MessageReaction{
+		"msg-1": {{Emoji: "👍", Count: 2, Reacted: true}},
+	}, nil)
+
+	svc := newTestChatService(convRepo, msgRepo, new(mocks.MockUserRepository))
+	reactions, err := svc.ReactToMessage(context.Background(), "user-1", "msg-1", "👍", true)
+	require.NoError(t, err)
+	assert.Equal(t, []models.MessageReaction{{Emoji: "👍", Count: 2, Reacted: true}}, reactions)
+}
+
+func TestChatService_SetDisappearingMessages(t *testing.T) {
+	ctx := context.Background()
+	conv := func(ttl *int) *models.Conversation {
+		return &models.Conversation{ID: "conv-1", Participant1ID: "user-1", Participant2ID: "user-2", DisappearingTTL: ttl}
+	}
+
+	t.Run("non-participant rejected", func(t *testing.T) {
+		convRepo := &mocks.MockConversationRepository{}
+		convRepo.On("GetByID", mock.Anything, "conv-1").Return(conv(nil), nil)
+		svc := newTestChatService(convRepo, &mocks.MockMessageRepository{}, new(mocks.MockUserRepository))
+
+		_, err := svc.SetDisappearingMessages(ctx, "stranger", "conv-1", "24h")
+		requireAppErrorCode(t, err, http.StatusForbidden)
+		convRepo.AssertNotCalled(t, "SetDisappearingTTL", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unchanged setting records nothing", func(t *testing.T) {
+		convRepo := &mocks.MockConversationRepository{}
+		day := models.DisappearingTTL24h
+		convRepo.On("GetByID", mock.Anything, "conv-1").Return(conv(&day), nil)
+		msgRepo := &mocks.MockMessageRepository{}
+		svc := newTestChatService(convRepo, msgRepo, new(mocks.MockUserRepository))
+
+		resp, err := svc.SetDisappearingMessages(ctx, "user-1", "conv-1", "24h")
+		require.NoError(t, err)
+		assert.Nil(t, resp)
+		msgRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("turning on records a system message", func(t *testing.T) {
+		convRepo := &mocks.MockConversationRepository{}
+		msgRepo := &mocks.MockMessageRepository{}
+		userRepo := new(mocks.MockUserRepository)
+		convRepo.On("GetByID", mock.Anything, "conv-1").Return(conv(nil), nil)
+		convRepo.On("SetDisappearingTTL", mock.Anything, "conv-1", mock.MatchedBy(func(ttl *int) bool {
+			return ttl != nil && *ttl == models.DisappearingTTL7d
+		})).Return(nil)
+		msgRepo.On("Create", mock.Anything, mock.MatchedBy(func(m *models.Message) bool {
+			return m.MessageType == models.MessageTypeSystem && m.SenderID == "user-2" && m.ExpiresAt == nil
+		})).Return(nil)
+		msgRepo.On("MarkAsRead", mock.Anything, mock.Anything).Return(nil)
+		convRepo.On("UpdateLastMessageAt", mock.Anything, "conv-1").Return(nil)
+		userRepo.On("GetProfileByUserID", mock.Anything, "user-2").Return(&models.Profile{ID: "user-2"}, nil)
+		msgRepo.On("GetReactions", mock.Anything, mock.Anything, mock.Anything).Return(map[string][]models.MessageReaction{}, nil).Maybe()
+		svc := newTestChatService(convRepo, msgRepo, userRepo)
+
+		resp, err := svc.SetDisappearingMessages(ctx, "user-2", "conv-1", "7d")
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+		assert.Equal(t, models.MessageTypeSystem, resp.MessageType)
+		assert.Contains(t, *resp.Content, "7 days")
+		convRepo.AssertExpectations(t)
+		msgRepo.AssertExpectations(t)
+	})
+}
+
+func TestChatService_SendMessageStampsExpiry(t *testing.T) {
+	convRepo := &mocks.MockConversationRepository{}
+	msgRepo := &mocks.MockMessageRepository{}
+	userRepo := new(mocks.MockUserRepository)
+	day := models.DisappearingTTL24h
+	convRepo.On("GetOrCreate", mock.Anything, "sender-1", "recv-1", mock.Anything).
+		Return(&models.Conversation{ID: "conv-1", DisappearingTTL: &day}, nil)
+	var created *models.Message
+	msgRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Message")).
+		Run(func(args mock.Arguments) { created = args.Get(1).(*models.Message) }).
+		Return(nil)
+	convRepo.On("UpdateLastMessageAt", mock.Anything, "conv-1").Return(nil)
+	userRepo.On("GetProfileByUserID", mock.Anything, "sender-1").Return(&models.Profile{ID: "sender-1"}, nil)
+	msgRepo.On("GetReactions", mock.Anything, mock.Anything, mock.Anything).Return(map[string][]models.MessageReaction{}, nil).Maybe()
+
+	svc := newTestChatService(convRepo, msgRepo, userRepo)
+	resp, err := svc.SendMessage(context.Background(), "sender-1", &models.SendMessageRequest{
+		RecipientID: "recv-1",
+		MessageType: models.MessageTypeText,
+		Content:     ptrStr("gone tomorrow"),
+	})
+	require.NoError(t, err)
+	require.NotNil(t, created.ExpiresAt)
+	assert.Equal(t, 24*time.Hour, created.ExpiresAt.Sub(created.CreatedAt))
+	assert.Equal(t, created.ExpiresAt, resp.ExpiresAt)
+}
+
+func TestChatService_PurgeExpiredMessages(t *testing.T) {
+	ctx := context.Background()
+	msgRepo := &mocks.MockMessageRepository{}
+	photo := "https://cdn.example.com/chat/photo.jpg"
+	msgRepo.On("DeleteExpired", mock.Anything, expiredMessagesBatch).Return([]*models.Message{
+		{ID: "msg-1", ConversationID: "conv-1", MessageType: models.MessageTypeImage, Content: &photo},
+		{ID: "msg-2", ConversationID: "conv-1", MessageType: models.MessageTypeText, Content: ptrStr("https://example.com is a link")},
+	}, nil).Once()
+	storage := &fakeUploadDeleter{}
+
+	svc := newTestChatService(&mocks.MockConversationRepository{}, msgRepo, new(mocks.MockUserRepository)).
+		WithAttachmentStorage(storage)
+	require.NoError(t, svc.PurgeExpiredMessages(ctx))
+	assert.Equal(t, []string{photo}, storage.deleted)
+	msgRepo.AssertExpectations(t)
+}
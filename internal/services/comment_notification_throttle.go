@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/runtimeconfig"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Runtime setting keys for COMMENT notification throttling. Admins change
+// them through /admin/system/settings.
+const (
+	SettingCommentNotifyBurst    = "notifications.comment_burst"
+	SettingCommentNotifyInterval = "notifications.comment_digest_interval"
+)
+
+const (
+	// defaultCommentNotifyBurst is how many COMMENT notifications about one
+	// post its owner gets individually per interval.
+	defaultCommentNotifyBurst = 5
+	// defaultCommentNotifyInterval is the burst window and how often the
+	// held-back comments are sent as one notification.
+	defaultCommentNotifyInterval = 10 * time.Minute
+
+	// commentBurstPrefix keys a per-(owner, post) counter of comment
+	// notifications in the current window; it expires with the window.
+	commentBurstPrefix = "notif:comment:burst:"
+	// commentPendingPrefix keys a per-(owner, post) HASH of the comments
+	// held back for the next digest: count plus the latest commenter.
+	commentPendingPrefix = "notif:comment:pending:"
+	// commentDigestDueKey is a ZSET of "owner:post" members scored by when
+	// their digest is due (unix seconds).
+	commentDigestDueKey = "notif:comment:due"
+)
+
+// WithCommentThrottle batches COMMENT notifications on busy posts: the
+// first notifications-per-window go out individually, the rest are held in
+// Redis and sent as one "X and N others commented" notification each
+// interval by FlushCommentDigests. Needs Redis; optional.
+func (s *NotificationService) WithCommentThrottle(store *runtimeconfig.Store) *NotificationService {
+	store.Register(runtimeconfig.Setting{
+		Key:         SettingCommentNotifyBurst,
+		Kind:        runtimeconfig.KindInt,
+		Default:     strconv.Itoa(defaultCommentNotifyBurst),
+		Description: "COMMENT notifications about one post its owner gets individually per interval before the rest are batched",
+	})
+	store.Register(runtimeconfig.Setting{
+		Key:         SettingCommentNotifyInterval,
+		Kind:        runtimeconfig.KindDuration,
+		Default:     defaultCommentNotifyInterval.String(),
+		Description: "Window for the COMMENT notification burst, and how often batched comments are sent",
+	})
+	s.commentSettings = store
+	return s
+}
+
+func (s *NotificationService) commentThrottle() (burst int, interval time.Duration) {
+	return s.commentSettings.Int(SettingCommentNotifyBurst, defaultCommentNotifyBurst),
+		s.commentSettings.Duration(SettingCommentNotifyInterval, defaultCommentNotifyInterval)
+}
+
+// holdComment counts a COMMENT notification against its post's burst and,
+// past the burst, holds it for the next digest. True when held. Redis
+// errors fail open: the notification goes out individually.
+func (s *NotificationService) holdComment(ctx context.Context, req *models.CreateNotificationRequest) bool {
+	if s.commentSettings == nil || s.redisClient == nil || req.Type != models.NotificationTypeComment {
+		return false
+	}
+	if digest, _ := req.Data["aggregated"].(bool); digest {
+		return false
+	}
+	postID, _ := req.Data["post_id"].(string)
+	if postID == "" {
+		return false
+	}
+	burst, interval := s.commentThrottle()
+	member := req.UserID + ":" + postID
+
+	pipe := s.redisClient.TxPipeline()
+	incr := pipe.Incr(ctx, commentBurstPrefix+member)
+	pipe.ExpireNX(ctx, commentBurstPrefix+member, interval)
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.logger.Warn("Comment notification throttle unavailable", zap.Error(err))
+		return false
+	}
+	if incr.Val() <= int64(burst) {
+		return false
+	}
+
+	pending := commentPendingPrefix + member
+	latest := map[string]interface{}{}
+	for _, key := range []string{"actor_id", "actor_name", "actor_avatar_color", "post_type", "business_id"} {
+		if v, ok := req.Data[key].(string); ok {
+			latest[key] = v
+		}
+	}
+	pipe = s.redisClient.TxPipeline()
+	pipe.HIncrBy(ctx, pending, "count", 1)
+	if len(latest) > 0 {
+		pipe.HSet(ctx, pending, latest)
+	}
+	// Outlives a missed flush or two, but not forever.
+	pipe.Expire(ctx, pending, 3*interval)
+	pipe.ZAddNX(ctx, commentDigestDueKey, redis.Z{Score: float64(time.Now().Add(interval).Unix()), Member: member})
+	if _, err := pipe.Exec(ctx); err != nil {
+		s.logger.Warn("Failed to hold comment notification", zap.Error(err))
+		return false
+	}
+	return true
+}
+
+// FlushCommentDigests sends one notification per post whose held-back
+// comments are due. Runs every minute on the leader; a member is claimed by
+// removing it from the due set, so instances never send the same digest.
+func (s *NotificationService) FlushCommentDigests(ctx context.Context) error {
+	if s.redisClient == nil {
+		return nil
+	}
+	due, err := s.redisClient.ZRangeByScore(ctx, commentDigestDueKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(time.Now().Unix(), 10),
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("list due comment digests: %w", err)
+	}
+
+	for _, member := range due {
+		claimed, err := s.redisClient.ZRem(ctx, commentDigestDueKey, member).Result()
+		if err != nil {
+			return fmt.Errorf("claim comment digest: %w", err)
+		}
+		if claimed == 0 {
+			continue
+		}
+		var held *redis.MapStringStringCmd
+		if _, err := s.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			held = pipe.HGetAll(ctx, commentPendingPrefix+member)
+			pipe.Del(ctx, commentPendingPrefix+member)
+			return nil
+		}); err != nil {
+			s.logger.Warn("Failed to read held comment notifications", zap.String("member", member), zap.Error(err))
+			continue
+		}
+		userID, postID, ok := strings.Cut(member, ":")
+		if !ok {
+			continue
+		}
+		req := commentDigestRequest(userID, postID, held.Val())
+		if req == nil {
+			continue
+		}
+		if _, err := s.CreateNotification(ctx, req); err != nil {
+			s.logger.Warn("Failed to send comment digest",
+				zap.String("user_id", userID), zap.String("post_id", postID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// commentDigestRequest builds the batched notification from the held
+// fields; nil when nothing was held.
+func commentDigestRequest(userID, postID string, held map[string]string) *models.CreateNotificationRequest {
+	count, _ := strconv.Atoi(held["count"])
+	if count <= 0 {
+		return nil
+	}
+	actor := held["actor_name"]
+	var title string
+	switch {
+	case actor == "":
+		title = fmt.Sprintf("%d new comments on your post", count)
+	case count == 1:
+		title = actor + " commented on your post"
+	case count == 2:
+		title = actor + " and 1 other commented on your post"
+	default:
+		title = fmt.Sprintf("%s and %d others commented on your post", actor, count-1)
+	}
+	data := map[string]interface{}{
+		"post_id":       postID,
+		"aggregated":    true,
+		"comment_count": count,
+	}
+	for _, key := range []string{"actor_id", "actor_name", "actor_avatar_color", "post_type", "business_id"} {
+		if v := held[key]; v != "" {
+			data[key] = v
+		}
+	}
+	msg := title
+	return &models.CreateNotificationRequest{
+		UserID:  userID,
+		Type:    models.NotificationTypeComment,
+		Title:   &title,
+		Message: &msg,
+		Data:    data,
+	}
+}
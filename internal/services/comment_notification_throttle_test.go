@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/runtimeconfig"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func commentRequest(owner, postID, actorID, actorName string) *models.CreateNotificationRequest {
+	title := actorName + " commented on your post"
+	return &models.CreateNotificationRequest{
+		UserID: owner,
+		Type:   models.NotificationTypeComment,
+		Title:  &title,
+		Data: map[string]interface{}{
+			"actor_id":   actorID,
+			"actor_name": actorName,
+			"post_id":    postID,
+			"post_type":  "FEED",
+		},
+	}
+}
+
+func TestNotificationService_CommentThrottle(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	notifRepo := new(mocks.MockNotificationRepository)
+	settingsRepo := new(mocks.MockNotificationSettingsRepository)
+	settingsRepo.On("GetByProfileID", mock.Anything, "owner").Return([]*models.NotificationSetting{}, nil)
+
+	var sent []*models.Notification
+	notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).
+		Run(func(args mock.Arguments) { sent = append(sent, args.Get(1).(*models.Notification)) }).
+		Return(nil)
+
+	store := runtimeconfig.New(rdb, zap.NewNop())
+	svc := NewNotificationService(notifRepo, settingsRepo, nil, nil, rdb, nil, zap.NewNop()).
+		WithCommentThrottle(store)
+	require.NoError(t, store.Set(ctx, SettingCommentNotifyBurst, "2"))
+
+	for i := 1; i <= 5; i++ {
+		_, err := svc.CreateNotification(ctx, commentRequest("owner", "post-1", fmt.Sprintf("u%d", i), fmt.Sprintf("User %d", i)))
+		require.NoError(t, err)
+	}
+	// Another post has its own burst.
+	_, err := svc.CreateNotification(ctx, commentRequest("owner", "post-2", "u9", "User 9"))
+	require.NoError(t, err)
+
+	require.Len(t, sent, 3, "first two on post-1 plus the one on post-2")
+	assert.Equal(t, "User 1 commented on your post", *sent[0].Title)
+
+	t.Run("nothing is flushed before it is due", func(t *testing.T) {
+		require.NoError(t, svc.FlushCommentDigests(ctx))
+		assert.Len(t, sent, 3)
+	})
+
+	t.Run("held comments go out as one digest", func(t *testing.T) {
+		rdb.ZAdd(ctx, commentDigestDueKey, redis.Z{Score: 0, Member: "owner:post-1"})
+		require.NoError(t, svc.FlushCommentDigests(ctx))
+
+		require.Len(t, sent, 4)
+		digest := sent[3]
+		assert.Equal(t, "User 5 and 2 others commented on your post", *digest.Title)
+		assert.Equal(t, true, digest.Data["aggregated"])
+		assert.Equal(t, 3, digest.Data["comment_count"])
+		assert.Equal(t, "post-1", digest.Data["post_id"])
+		assert.Equal(t, "u5", digest.Data["actor_id"])
+
+		assert.False(t, mr.Exists(commentPendingPrefix+"owner:post-1"))
+		require.NoError(t, svc.FlushCommentDigests(ctx))
+		assert.Len(t, sent, 4, "a digest is sent once")
+	})
+}
+
+func TestNotificationService_CommentThrottle_Disabled(t *testing.T) {
+	notifRepo := new(mocks.MockNotificationRepository)
+	settingsRepo := new(mocks.MockNotificationSettingsRepository)
+	notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
+	settingsRepo.On("GetByProfileID", mock.Anything, "owner").Return([]*models.NotificationSetting{}, nil)
+
+	// Without WithCommentThrottle every comment is notified.
+	svc := NewNotificationService(notifRepo, settingsRepo, nil, nil, nil, nil, zap.NewNop())
+	for i := 0; i < defaultCommentNotifyBurst+3; i++ {
+		_, err := svc.CreateNotification(context.Background(), commentRequest("owner", "post-1", "u1", "User 1"))
+		require.NoError(t, err)
+	}
+	notifRepo.AssertNumberOfCalls(t, "Create", defaultCommentNotifyBurst+3)
+}
+
+func TestCommentDigestRequest(t *testing.T) {
+	assert.Nil(t, commentDigestRequest("owner", "p1", map[string]string{}))
+
+	req := commentDigestRequest("owner", "p1", map[string]string{"count": "2", "actor_name": "Ali"})
+	assert.Equal(t, "Ali and 1 other commented on your post", *req.Title)
+
+	req = commentDigestRequest("owner", "p1", map[string]string{"count": "4"})
+	assert.Equal(t, "4 new comments on your post", *req.Title)
+	assert.Equal(t, models.NotificationTypeComment, req.Type)
+}
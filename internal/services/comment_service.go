@@ -31,7 +31,10 @@ type CommentService struct {
 	postRepo            repositories.PostRepository
 	userRepo            repositories.UserRepository
 	businessRepo        repositories.BusinessRepository
+	access              *AccessControl
 	notificationService *NotificationService
+	creationThrottle    *CreationThrottle
+	limits              *models.ContentLimits
 	logger              *zap.Logger
 }
 
@@ -49,13 +52,46 @@ func NewCommentService(
 		postRepo:            postRepo,
 		userRepo:            userRepo,
 		businessRepo:        businessRepo,
+		access:              NewAccessControl(businessRepo),
 		notificationService: notificationService,
 		logger:              logger,
 	}
 }
 
+// WithCreationThrottle enables the per-user comment creation limit.
+func (s *CommentService) WithCreationThrottle(t *CreationThrottle) *CommentService {
+	s.creationThrottle = t
+	return s
+}
+
+// WithContentLimits sets the comment length and attachment limits
+// (CONTENT_COMMENT_*). Without it the built-in defaults apply.
+func (s *CommentService) WithContentLimits(limits models.ContentLimits) *CommentService {
+	s.limits = &limits
+	return s
+}
+
+// checkCommentLimits holds a comment's text and attachments (newAttachments
+// on top of kept ones when editing) to the configured limits.
+func (s *CommentService) checkCommentLimits(text string, kept, newAttachments int) error {
+	limits := defaultContentLimits
+	if s.limits != nil {
+		limits = *s.limits
+	}
+	if err := checkTextLength("Comment", text, limits.CommentMaxLength); err != nil {
+		return err
+	}
+	if newAttachments == 0 {
+		return nil
+	}
+	return checkAttachmentCount(kept+newAttachments, limits.CommentMaxAttachments)
+}
+
 // CreateComment creates a new comment
 func (s *CommentService) CreateComment(ctx context.Context, postID, userID string, req *models.CreateCommentRequest) (*models.CommentResponse, error) {
+	if err := s.checkCommentLimits(req.Text, 0, len(req.Attachments)); err != nil {
+		return nil, err
+	}
 	post, err := s.postRepo.GetByID(ctx, postID)
 	if err != nil {
 		return nil, utils.NewNotFoundError("Post not found", err)
@@ -95,6 +131,17 @@ func (s *CommentService) CreateComment(ctx context.Context, postID, userID strin
 		}
 	}
 
+	// Commenting as a business requires owning it, same as posting as one.
+	if req.BusinessID != nil && *req.BusinessID != "" {
+		if _, err := s.access.RequireBusinessOwner(ctx, *req.BusinessID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.creationThrottle.Allow(ctx, CreationComment, userID); err != nil {
+		return nil, err
+	}
+
 	// Create comment
 	commentID := uuid.New().String()
 	now := time.Now()
@@ -119,9 +166,12 @@ func (s *CommentService) CreateComment(ctx context.Context, postID, userID strin
 		comment.Longitude = req.Longitude
 	}
 
-	// Store mentioned user IDs (order matches @mentions in text for client)
-	if len(req.TaggedUserIDs) > 0 {
-		comment.MentionedUserIDs = req.TaggedUserIDs
+	// Store mentioned user IDs (order matches @mentions in text for client).
+	// @handles in the text are resolved server-side; tagged IDs from older
+	// clients are kept after them.
+	mentionedUserIDs := resolveMentions(ctx, s.userRepo, s.logger, req.Text, req.TaggedUserIDs)
+	if len(mentionedUserIDs) > 0 {
+		comment.MentionedUserIDs = mentionedUserIDs
 	}
 
 	// Create comment in database
@@ -256,7 +306,7 @@ func (s *CommentService) CreateComment(ctx context.Context, postID, userID strin
 	}
 
 	// Notify each tagged/mentioned user (skip self and post owner to avoid duplicate)
-	if len(req.TaggedUserIDs) > 0 && s.notificationService != nil {
+	if len(mentionedUserIDs) > 0 && s.notificationService != nil {
 		bgtasks.Submit(func(ctxDetach context.Context) {
 			actorName := ""
 			var actorAvatar interface{}
@@ -280,7 +330,7 @@ func (s *CommentService) CreateComment(ctx context.Context, postID, userID strin
 					notified[parent.UserID] = true
 				}
 			}
-			for _, taggedID := range req.TaggedUserIDs {
+			for _, taggedID := range mentionedUserIDs {
 				if taggedID == "" || notified[taggedID] {
 					continue
 				}
@@ -325,20 +375,49 @@ func (s *CommentService) GetComment(ctx context.Context, commentID string, viewe
 		return nil, utils.NewNotFoundError("Comment not found", err)
 	}
 
+	// Post is needed for the is_author flag; a missing post just leaves it false.
+	post, _ := s.postRepo.GetByID(ctx, comment.PostID)
+
 	// Enrich comment
-	return s.enrichComment(ctx, comment, viewerID, false)
+	return s.enrichComment(ctx, comment, post, viewerID, false)
 }
 
-// GetPostComments gets comments for a post
-func (s *CommentService) GetPostComments(ctx context.Context, postID string, limit, offset int, viewerID *string) ([]*models.CommentResponse, error) {
+// popularThreadComments is the comment count from which a feed post's thread
+// defaults to most liked first.
+const popularThreadComments = 20
+
+// defaultCommentSort picks the order for a post's thread when the client
+// doesn't ask for one: events read like a conversation from the start, busy
+// feed threads surface the best comments, everything else shows the newest.
+func defaultCommentSort(post *models.Post) models.CommentSort {
+	switch {
+	case post.Type == models.PostTypeEvent:
+		return models.CommentSortOldest
+	case post.Type == models.PostTypeFeed && post.TotalComments >= popularThreadComments:
+		return models.CommentSortMostLiked
+	default:
+		return models.CommentSortNewest
+	}
+}
+
+// GetPostComments gets comments for a post. An empty sort uses the post
+// type's default.
+func (s *CommentService) GetPostComments(ctx context.Context, postID string, sort models.CommentSort, limit, offset int, viewerID *string) ([]*models.CommentResponse, error) {
+	if sort != "" && !sort.IsValid() {
+		return nil, utils.NewBadRequestError("sort must be newest, oldest or most_liked", nil)
+	}
+
 	// Validate post exists
-	_, err := s.postRepo.GetByID(ctx, postID)
+	post, err := s.postRepo.GetByID(ctx, postID)
 	if err != nil {
 		return nil, utils.NewNotFoundError("Post not found", err)
 	}
+	if sort == "" {
+		sort = defaultCommentSort(post)
+	}
 
-	// Get top-level comments
-	comments, err := s.commentRepo.GetByPostID(ctx, postID, limit, offset)
+	// Get top-level comments (pinned comment first)
+	comments, err := s.commentRepo.GetByPostID(ctx, postID, sort, limit, offset)
 	if err != nil {
 		s.logger.Error("Failed to get post comments", zap.String("post_id", postID), zap.Error(err))
 		return nil, utils.NewInternalError("Failed to get comments", err)
@@ -347,7 +426,7 @@ func (s *CommentService) GetPostComments(ctx context.Context, postID string, lim
 	// Enrich comments
 	var enrichedComments []*models.CommentResponse
 	for _, comment := range comments {
-		enrichedComment, err := s.enrichComment(ctx, comment, viewerID, true)
+		enrichedComment, err := s.enrichComment(ctx, comment, post, viewerID, true)
 		if err != nil {
 			s.logger.Warn("Failed to enrich comment", zap.String("comment_id", comment.ID), zap.Error(err))
 			continue
@@ -361,12 +440,18 @@ func (s *CommentService) GetPostComments(ctx context.Context, postID string, lim
 // GetCommentReplies gets replies to a comment
 func (s *CommentService) GetCommentReplies(ctx context.Context, commentID string, limit, offset int, viewerID *string) ([]*models.CommentResponse, error) {
 	// Validate parent comment exists
-	_, err := s.commentRepo.GetByID(ctx, commentID)
+	parent, err := s.commentRepo.GetByID(ctx, commentID)
 	if err != nil {
 		return nil, utils.NewNotFoundError("Comment not found", err)
 	}
+	post, _ := s.postRepo.GetByID(ctx, parent.PostID)
+
+	return s.getReplies(ctx, commentID, post, limit, offset, viewerID)
+}
 
-	// Get replies
+// getReplies loads and enriches replies under a comment whose post is
+// already known, so nested reply previews don't refetch the post.
+func (s *CommentService) getReplies(ctx context.Context, commentID string, post *models.Post, limit, offset int, viewerID *string) ([]*models.CommentResponse, error) {
 	replies, err := s.commentRepo.GetReplies(ctx, commentID, limit, offset)
 	if err != nil {
 		s.logger.Error("Failed to get comment replies", zap.String("comment_id", commentID), zap.Error(err))
@@ -376,7 +461,7 @@ func (s *CommentService) GetCommentReplies(ctx context.Context, commentID string
 	// Enrich replies
 	var enrichedReplies []*models.CommentResponse
 	for _, reply := range replies {
-		enrichedReply, err := s.enrichComment(ctx, reply, viewerID, false)
+		enrichedReply, err := s.enrichComment(ctx, reply, post, viewerID, false)
 		if err != nil {
 			s.logger.Warn("Failed to enrich reply", zap.String("comment_id", reply.ID), zap.Error(err))
 			continue
@@ -396,10 +481,30 @@ func (s *CommentService) UpdateComment(ctx context.Context, commentID, userID st
 	}
 
 	// Check ownership
-	if comment.UserID != userID {
+	if !s.access.CanEditComment(comment, userID) {
 		return nil, utils.NewForbiddenError("You don't have permission to update this comment", nil)
 	}
 
+	kept := 0
+	if len(req.Attachments) > 0 {
+		existing, err := s.commentRepo.GetAttachmentsByCommentID(ctx, commentID)
+		if err != nil {
+			return nil, utils.NewInternalError("Failed to load comment attachments", err)
+		}
+		deleted := make(map[string]bool, len(req.DeletedAttachmentIDs))
+		for _, id := range req.DeletedAttachmentIDs {
+			deleted[id] = true
+		}
+		for _, att := range existing {
+			if !deleted[att.ID] {
+				kept++
+			}
+		}
+	}
+	if err := s.checkCommentLimits(req.Text, kept, len(req.Attachments)); err != nil {
+		return nil, err
+	}
+
 	// Update comment text
 	comment.Text = req.Text
 	comment.UpdatedAt = time.Now()
@@ -447,9 +552,12 @@ func (s *CommentService) DeleteComment(ctx context.Context, commentID, userID st
 		return utils.NewNotFoundError("Comment not found", err)
 	}
 
-	// Check ownership
-	if comment.UserID != userID {
-		return utils.NewForbiddenError("You don't have permission to delete this comment", nil)
+	// Authors delete their own comments; post owners moderate their threads.
+	if !s.access.CanEditComment(comment, userID) {
+		post, _ := s.postRepo.GetByID(ctx, comment.PostID)
+		if !s.access.CanDeleteComment(ctx, comment, post, userID) {
+			return utils.NewForbiddenError("You don't have permission to delete this comment", nil)
+		}
 	}
 
 	// Delete comment
@@ -532,8 +640,74 @@ func (s *CommentService) UnlikeComment(ctx context.Context, userID, commentID st
 	return nil
 }
 
-// enrichComment enriches a comment with author, attachments, and engagement status
-func (s *CommentService) enrichComment(ctx context.Context, comment *models.PostComment, viewerID *string, includeReplies bool) (*models.CommentResponse, error) {
+// PinComment pins a top-level comment to the top of its post. Only the post
+// owner (or the owner of the business the post was published as) may pin;
+// pinning replaces any previously pinned comment.
+func (s *CommentService) PinComment(ctx context.Context, commentID, userID string) error {
+	comment, post, err := s.loadForPinning(ctx, commentID, userID)
+	if err != nil {
+		return err
+	}
+	if comment.ParentCommentID != nil {
+		return utils.NewBadRequestError("Only top-level comments can be pinned", nil)
+	}
+
+	if err := s.commentRepo.PinComment(ctx, post.ID, commentID); err != nil {
+		s.logger.Error("Failed to pin comment", zap.String("comment_id", commentID), zap.Error(err))
+		return utils.NewInternalError("Failed to pin comment", err)
+	}
+
+	s.logger.Info("Comment pinned", zap.String("comment_id", commentID), zap.String("post_id", post.ID), zap.String("user_id", userID))
+	return nil
+}
+
+// UnpinComment removes the pin from a comment. Idempotent.
+func (s *CommentService) UnpinComment(ctx context.Context, commentID, userID string) error {
+	if _, _, err := s.loadForPinning(ctx, commentID, userID); err != nil {
+		return err
+	}
+
+	if err := s.commentRepo.UnpinComment(ctx, commentID); err != nil {
+		s.logger.Error("Failed to unpin comment", zap.String("comment_id", commentID), zap.Error(err))
+		return utils.NewInternalError("Failed to unpin comment", err)
+	}
+
+	s.logger.Info("Comment unpinned", zap.String("comment_id", commentID), zap.String("user_id", userID))
+	return nil
+}
+
+// loadForPinning fetches the comment and its post and checks that userID
+// owns the post.
+func (s *CommentService) loadForPinning(ctx context.Context, commentID, userID string) (*models.PostComment, *models.Post, error) {
+	comment, err := s.commentRepo.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, nil, utils.NewNotFoundError("Comment not found", err)
+	}
+	post, err := s.postRepo.GetByID(ctx, comment.PostID)
+	if err != nil {
+		return nil, nil, utils.NewNotFoundError("Post not found", err)
+	}
+	if !s.access.CanEditPost(ctx, post, userID) {
+		return nil, nil, utils.NewForbiddenError("Only the post owner can pin comments", nil)
+	}
+	return comment, post, nil
+}
+
+// isPostAuthorComment reports whether the comment was written by the post's
+// author, or posted as the same business the post belongs to.
+func isPostAuthorComment(post *models.Post, comment *models.PostComment) bool {
+	if post == nil {
+		return false
+	}
+	if post.BusinessID != nil && comment.BusinessID != nil && *post.BusinessID == *comment.BusinessID {
+		return true
+	}
+	return post.UserID != nil && *post.UserID == comment.UserID
+}
+
+// enrichComment enriches a comment with author, attachments, and engagement
+// status. post may be nil when it couldn't be loaded; is_author is then false.
+func (s *CommentService) enrichComment(ctx context.Context, comment *models.PostComment, post *models.Post, viewerID *string, includeReplies bool) (*models.CommentResponse, error) {
 	response := &models.CommentResponse{
 		ID:              comment.ID,
 		PostID:          comment.PostID,
@@ -541,6 +715,8 @@ func (s *CommentService) enrichComment(ctx context.Context, comment *models.Post
 		ParentCommentID: comment.ParentCommentID,
 		TotalLikes:      comment.TotalLikes,
 		TotalReplies:    comment.TotalReplies,
+		IsAuthor:        isPostAuthorComment(post, comment),
+		IsPinned:        comment.PinnedAt != nil,
 		CreatedAt:       comment.CreatedAt,
 		UpdatedAt:       comment.UpdatedAt,
 	}
@@ -619,6 +795,7 @@ func (s *CommentService) enrichComment(ctx context.Context, comment *models.Post
 			Latitude:  comment.Latitude,
 			Longitude: comment.Longitude,
 		}
+		locationPrecisionFor(response.IsMine, viewerID).applyInfo(response.Location)
 	}
 
 	// Populate mentioned users (ordered to match @mentions in text) for tap-to-profile
@@ -635,6 +812,7 @@ func (s *CommentService) enrichComment(ctx context.Context, comment *models.Post
 			response.MentionedUsers = append(response.MentionedUsers, models.MentionedUser{
 				UserID:   uid,
 				FullName: profile.FullName(),
+				Handle:   profile.Handle,
 			})
 		}
 	}
@@ -669,7 +847,7 @@ func (s *CommentService) enrichComment(ctx context.Context, comment *models.Post
 
 	// Get first few replies if requested
 	if includeReplies && comment.TotalReplies > 0 {
-		replies, err := s.GetCommentReplies(ctx, comment.ID, 3, 0, viewerID)
+		replies, err := s.getReplies(ctx, comment.ID, post, 3, 0, viewerID)
 		if err == nil {
 			response.Replies = replies
 		}
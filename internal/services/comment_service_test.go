@@ -3,9 +3,11 @@ package services
 import (
 	"context"
 	"errors"
+	"net/http"
 	"strings"
 	"testing"
 
+	"github.com/hamsaya/backend/config"
 	"github.com/hamsaya/backend/internal/mocks"
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/testutil"
@@ -65,6 +67,17 @@ func TestCommentService_CreateComment(t *testing.T) {
 		commentRepo.AssertExpectations(t)
 	})
 
+	t.Run("too long", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		svc := newTestCommentService(new(mocks.MockCommentRepository), postRepo, new(mocks.MockUserRepository), new(mocks.MockBusinessRepository))
+
+		req := &models.CreateCommentRequest{Text: strings.Repeat("x", 1001)}
+		_, err := svc.CreateComment(context.Background(), "post-1", "user-1", req)
+
+		requireAppErrorCode(t, err, http.StatusBadRequest)
+		postRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	})
+
 	t.Run("success", func(t *testing.T) {
 		commentRepo := new(mocks.MockCommentRepository)
 		postRepo := new(mocks.MockPostRepository)
@@ -105,6 +118,26 @@ func TestCommentService_CreateComment(t *testing.T) {
 		commentRepo.AssertExpectations(t)
 		userRepo.AssertExpectations(t)
 	})
+
+	t.Run("as a business the user doesn't own", func(t *testing.T) {
+		commentRepo := new(mocks.MockCommentRepository)
+		postRepo := new(mocks.MockPostRepository)
+		userRepo := new(mocks.MockUserRepository)
+		businessRepo := new(mocks.MockBusinessRepository)
+		svc := newTestCommentService(commentRepo, postRepo, userRepo, businessRepo)
+
+		postRepo.On("GetByID", mock.Anything, "post-1").
+			Return(testutil.CreateTestPost("post-1", "user-2", models.PostTypeFeed), nil)
+		businessRepo.On("GetByID", mock.Anything, "biz-1").
+			Return(&models.BusinessProfile{ID: "biz-1", UserID: "user-2"}, nil)
+
+		businessID := "biz-1"
+		req := &models.CreateCommentRequest{Text: "hello", BusinessID: &businessID}
+		_, err := svc.CreateComment(context.Background(), "post-1", "user-1", req)
+
+		requireAppErrorCode(t, err, http.StatusForbidden)
+		commentRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
 }
 
 // ─── DeleteComment ────────────────────────────────────────────────────────────
@@ -137,12 +170,38 @@ func TestCommentService_DeleteComment(t *testing.T) {
 		comment := buildComment("comment-1", "post-1", "owner-user")
 		commentRepo.On("GetByID", mock.Anything, "comment-1").
 			Return(comment, nil)
+		postRepo.On("GetByID", mock.Anything, "post-1").
+			Return(testutil.CreateTestPost("post-1", "post-author", models.PostTypeFeed), nil)
 
 		err := svc.DeleteComment(context.Background(), "comment-1", "other-user")
 
 		assert.Error(t, err)
 		assert.Contains(t, strings.ToLower(err.Error()), "permission")
 		commentRepo.AssertExpectations(t)
+		commentRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("post owner moderates their thread", func(t *testing.T) {
+		commentRepo := new(mocks.MockCommentRepository)
+		postRepo := new(mocks.MockPostRepository)
+		userRepo := new(mocks.MockUserRepository)
+		businessRepo := new(mocks.MockBusinessRepository)
+		svc := newTestCommentService(commentRepo, postRepo, userRepo, businessRepo)
+
+		commentRepo.On("GetByID", mock.Anything, "comment-1").
+			Return(buildComment("comment-1", "post-1", "commenter"), nil)
+		post := testutil.CreateTestPost("post-1", "someone", models.PostTypeFeed)
+		businessID := "biz-1"
+		post.BusinessID = &businessID
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(post, nil)
+		businessRepo.On("GetByID", mock.Anything, "biz-1").
+			Return(&models.BusinessProfile{ID: "biz-1", UserID: "biz-owner"}, nil)
+		commentRepo.On("Delete", mock.Anything, "comment-1").Return(nil)
+
+		err := svc.DeleteComment(context.Background(), "comment-1", "biz-owner")
+
+		assert.NoError(t, err)
+		commentRepo.AssertExpectations(t)
 	})
 
 	t.Run("success", func(t *testing.T) {
@@ -225,6 +284,8 @@ func TestCommentService_UpdateComment(t *testing.T) {
 		commentRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.PostComment")).
 			Return(nil)
 		// GetComment at the end of UpdateComment
+		postRepo.On("GetByID", mock.Anything, "post-1").
+			Return(testutil.CreateTestPost("post-1", "owner-1", models.PostTypeFeed), nil)
 		userRepo.On("GetProfileByUserID", mock.Anything, userID).
 			Return(profile, nil)
 		commentRepo.On("GetAttachmentsByCommentID", mock.Anything, "comment-1").
@@ -240,6 +301,24 @@ func TestCommentService_UpdateComment(t *testing.T) {
 		commentRepo.AssertExpectations(t)
 		userRepo.AssertExpectations(t)
 	})
+
+	t.Run("too many attachments", func(t *testing.T) {
+		commentRepo := new(mocks.MockCommentRepository)
+		svc := newTestCommentService(commentRepo, new(mocks.MockPostRepository), new(mocks.MockUserRepository), new(mocks.MockBusinessRepository)).
+			WithContentLimits(ContentLimitsFromConfig(config.ContentConfig{CommentMaxAttachments: 2}))
+
+		commentRepo.On("GetByID", mock.Anything, "comment-1").
+			Return(buildComment("comment-1", "post-1", "user-1"), nil)
+		commentRepo.On("GetAttachmentsByCommentID", mock.Anything, "comment-1").
+			Return([]*models.CommentAttachment{{ID: "att-1"}, {ID: "att-2"}}, nil)
+
+		// Deleting one makes room for exactly one more.
+		req := &models.UpdateCommentRequest{Text: "updated", Attachments: []string{"https://cdn/a.webp", "https://cdn/b.webp"}, DeletedAttachmentIDs: []string{"att-1"}}
+		_, err := svc.UpdateComment(context.Background(), "comment-1", "user-1", req)
+
+		requireAppErrorCode(t, err, http.StatusBadRequest)
+		commentRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
 }
 
 // ─── LikeComment ─────────────────────────────────────────────────────────────
@@ -323,6 +402,8 @@ func TestCommentService_GetComment(t *testing.T) {
 
 		commentRepo.On("GetByID", mock.Anything, "comment-1").
 			Return(comment, nil)
+		postRepo.On("GetByID", mock.Anything, "post-1").
+			Return(testutil.CreateTestPost("post-1", userID, models.PostTypeFeed), nil)
 		userRepo.On("GetProfileByUserID", mock.Anything, userID).
 			Return(profile, nil)
 		commentRepo.On("GetAttachmentsByCommentID", mock.Anything, "comment-1").
@@ -334,6 +415,8 @@ func TestCommentService_GetComment(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
 		assert.Equal(t, "comment-1", result.ID)
+		assert.True(t, result.IsAuthor)
+		assert.False(t, result.IsPinned)
 		commentRepo.AssertExpectations(t)
 		userRepo.AssertExpectations(t)
 	})
@@ -356,7 +439,7 @@ func TestCommentService_GetPostComments(t *testing.T) {
 
 		postRepo.On("GetByID", mock.Anything, "post-1").
 			Return(post, nil)
-		commentRepo.On("GetByPostID", mock.Anything, "post-1", 10, 0).
+		commentRepo.On("GetByPostID", mock.Anything, "post-1", models.CommentSortNewest, 10, 0).
 			Return([]*models.PostComment{comment}, nil)
 		// enrichComment for comment-1
 		userRepo.On("GetProfileByUserID", mock.Anything, ownerID).
@@ -365,7 +448,7 @@ func TestCommentService_GetPostComments(t *testing.T) {
 			Return(nil, errors.New("no attachments"))
 		// No viewer → IsLikedByUser not called
 
-		results, err := svc.GetPostComments(context.Background(), "post-1", 10, 0, nil)
+		results, err := svc.GetPostComments(context.Background(), "post-1", "", 10, 0, nil)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, results)
@@ -387,10 +470,10 @@ func TestCommentService_GetPostComments(t *testing.T) {
 
 		postRepo.On("GetByID", mock.Anything, "post-1").
 			Return(post, nil)
-		commentRepo.On("GetByPostID", mock.Anything, "post-1", 10, 0).
+		commentRepo.On("GetByPostID", mock.Anything, "post-1", models.CommentSortNewest, 10, 0).
 			Return([]*models.PostComment{}, nil)
 
-		results, err := svc.GetPostComments(context.Background(), "post-1", 10, 0, nil)
+		results, err := svc.GetPostComments(context.Background(), "post-1", "", 10, 0, nil)
 
 		assert.NoError(t, err)
 		// nil and empty slice are both acceptable empty results
@@ -398,4 +481,153 @@ func TestCommentService_GetPostComments(t *testing.T) {
 		postRepo.AssertExpectations(t)
 		commentRepo.AssertExpectations(t)
 	})
+
+	t.Run("explicit sort", func(t *testing.T) {
+		commentRepo := new(mocks.MockCommentRepository)
+		postRepo := new(mocks.MockPostRepository)
+		svc := newTestCommentService(commentRepo, postRepo, new(mocks.MockUserRepository), new(mocks.MockBusinessRepository))
+
+		postRepo.On("GetByID", mock.Anything, "post-1").
+			Return(testutil.CreateTestPost("post-1", "owner-1", models.PostTypeEvent), nil)
+		commentRepo.On("GetByPostID", mock.Anything, "post-1", models.CommentSortMostLiked, 10, 0).
+			Return([]*models.PostComment{}, nil)
+
+		_, err := svc.GetPostComments(context.Background(), "post-1", models.CommentSortMostLiked, 10, 0, nil)
+
+		assert.NoError(t, err)
+		commentRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown sort", func(t *testing.T) {
+		svc := newTestCommentService(new(mocks.MockCommentRepository), new(mocks.MockPostRepository), new(mocks.MockUserRepository), new(mocks.MockBusinessRepository))
+
+		_, err := svc.GetPostComments(context.Background(), "post-1", "random", 10, 0, nil)
+
+		requireAppErrCode(t, err, http.StatusBadRequest)
+	})
+}
+
+func TestDefaultCommentSort(t *testing.T) {
+	event := testutil.CreateTestPost("post-1", "owner-1", models.PostTypeEvent)
+	assert.Equal(t, models.CommentSortOldest, defaultCommentSort(event))
+
+	feed := testutil.CreateTestPost("post-2", "owner-1", models.PostTypeFeed)
+	assert.Equal(t, models.CommentSortNewest, defaultCommentSort(feed))
+	feed.TotalComments = popularThreadComments
+	assert.Equal(t, models.CommentSortMostLiked, defaultCommentSort(feed))
+
+	sell := testutil.CreateTestPost("post-3", "owner-1", models.PostTypeSell)
+	sell.TotalComments = 100
+	assert.Equal(t, models.CommentSortNewest, defaultCommentSort(sell))
+}
+
+// ─── PinComment ───────────────────────────────────────────────────────────────
+
+func TestCommentService_PinComment(t *testing.T) {
+	t.Run("not post owner", func(t *testing.T) {
+		commentRepo := new(mocks.MockCommentRepository)
+		postRepo := new(mocks.MockPostRepository)
+		userRepo := new(mocks.MockUserRepository)
+		businessRepo := new(mocks.MockBusinessRepository)
+		svc := newTestCommentService(commentRepo, postRepo, userRepo, businessRepo)
+
+		commentRepo.On("GetByID", mock.Anything, "comment-1").
+			Return(buildComment("comment-1", "post-1", "commenter"), nil)
+		postRepo.On("GetByID", mock.Anything, "post-1").
+			Return(testutil.CreateTestPost("post-1", "owner-1", models.PostTypeFeed), nil)
+
+		err := svc.PinComment(context.Background(), "comment-1", "someone-else")
+
+		assert.Error(t, err)
+		assert.Contains(t, strings.ToLower(err.Error()), "post owner")
+		commentRepo.AssertNotCalled(t, "PinComment", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("reply cannot be pinned", func(t *testing.T) {
+		commentRepo := new(mocks.MockCommentRepository)
+		postRepo := new(mocks.MockPostRepository)
+		userRepo := new(mocks.MockUserRepository)
+		businessRepo := new(mocks.MockBusinessRepository)
+		svc := newTestCommentService(commentRepo, postRepo, userRepo, businessRepo)
+
+		parentID := "comment-0"
+		reply := buildComment("comment-1", "post-1", "commenter")
+		reply.ParentCommentID = &parentID
+		commentRepo.On("GetByID", mock.Anything, "comment-1").
+			Return(reply, nil)
+		postRepo.On("GetByID", mock.Anything, "post-1").
+			Return(testutil.CreateTestPost("post-1", "owner-1", models.PostTypeFeed), nil)
+
+		err := svc.PinComment(context.Background(), "comment-1", "owner-1")
+
+		assert.Error(t, err)
+		assert.Contains(t, strings.ToLower(err.Error()), "top-level")
+	})
+
+	t.Run("post owner", func(t *testing.T) {
+		commentRepo := new(mocks.MockCommentRepository)
+		postRepo := new(mocks.MockPostRepository)
+		userRepo := new(mocks.MockUserRepository)
+		businessRepo := new(mocks.MockBusinessRepository)
+		svc := newTestCommentService(commentRepo, postRepo, userRepo, businessRepo)
+
+		commentRepo.On("GetByID", mock.Anything, "comment-1").
+			Return(buildComment("comment-1", "post-1", "commenter"), nil)
+		postRepo.On("GetByID", mock.Anything, "post-1").
+			Return(testutil.CreateTestPost("post-1", "owner-1", models.PostTypeFeed), nil)
+		commentRepo.On("PinComment", mock.Anything, "post-1", "comment-1").
+			Return(nil)
+
+		err := svc.PinComment(context.Background(), "comment-1", "owner-1")
+
+		assert.NoError(t, err)
+		commentRepo.AssertExpectations(t)
+	})
+
+	t.Run("business owner", func(t *testing.T) {
+		commentRepo := new(mocks.MockCommentRepository)
+		postRepo := new(mocks.MockPostRepository)
+		userRepo := new(mocks.MockUserRepository)
+		businessRepo := new(mocks.MockBusinessRepository)
+		svc := newTestCommentService(commentRepo, postRepo, userRepo, businessRepo)
+
+		businessID := "biz-1"
+		post := testutil.CreateTestPost("post-1", "", models.PostTypeFeed)
+		post.UserID = nil
+		post.BusinessID = &businessID
+		commentRepo.On("GetByID", mock.Anything, "comment-1").
+			Return(buildComment("comment-1", "post-1", "commenter"), nil)
+		postRepo.On("GetByID", mock.Anything, "post-1").
+			Return(post, nil)
+		businessRepo.On("GetByID", mock.Anything, businessID).
+			Return(testutil.CreateTestBusiness(businessID, "biz-owner", "Shop"), nil)
+		commentRepo.On("PinComment", mock.Anything, "post-1", "comment-1").
+			Return(nil)
+
+		err := svc.PinComment(context.Background(), "comment-1", "biz-owner")
+
+		assert.NoError(t, err)
+		commentRepo.AssertExpectations(t)
+		businessRepo.AssertExpectations(t)
+	})
+}
+
+func TestCommentService_UnpinComment(t *testing.T) {
+	commentRepo := new(mocks.MockCommentRepository)
+	postRepo := new(mocks.MockPostRepository)
+	userRepo := new(mocks.MockUserRepository)
+	businessRepo := new(mocks.MockBusinessRepository)
+	svc := newTestCommentService(commentRepo, postRepo, userRepo, businessRepo)
+
+	commentRepo.On("GetByID", mock.Anything, "comment-1").
+		Return(buildComment("comment-1", "post-1", "commenter"), nil)
+	postRepo.On("GetByID", mock.Anything, "post-1").
+		Return(testutil.CreateTestPost("post-1", "owner-1", models.PostTypeFeed), nil)
+	commentRepo.On("UnpinComment", mock.Anything, "comment-1").
+		Return(nil)
+
+	err := svc.UnpinComment(context.Background(), "comment-1", "owner-1")
+
+	assert.NoError(t, err)
+	commentRepo.AssertExpectations(t)
 }
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/hamsaya/backend/config"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/utils"
+)
+
+// defaultContentLimits apply to services not given WithContentLimits.
+var defaultContentLimits = models.DefaultContentLimits()
+
+// ContentLimitsFromConfig builds the content limits from CONTENT_*
+// settings, keeping defaults for any left at zero. A post type's own
+// settings win over CONTENT_POST_*.
+func ContentLimitsFromConfig(cfg config.ContentConfig) models.ContentLimits {
+	limits := models.DefaultContentLimits()
+	base := mergePostLimits(models.DefaultPostTypeLimits, cfg.Post)
+	for _, t := range models.AllPostTypes {
+		limits.Posts[t] = mergePostLimits(base, cfg.PostTypes[string(t)])
+	}
+	if cfg.PollMaxOptions > 0 {
+		limits.PollMaxOptions = cfg.PollMaxOptions
+	}
+	if cfg.PollOptionMaxLength > 0 {
+		limits.PollOptionMaxLength = cfg.PollOptionMaxLength
+	}
+	if cfg.CommentMaxLength > 0 {
+		limits.CommentMaxLength = cfg.CommentMaxLength
+	}
+	if cfg.CommentMaxAttachments > 0 {
+		limits.CommentMaxAttachments = cfg.CommentMaxAttachments
+	}
+	return limits
+}
+
+func mergePostLimits(base models.PostTypeLimits, override config.ContentPostLimits) models.PostTypeLimits {
+	if override.TitleMaxLength > 0 {
+		base.TitleMaxLength = override.TitleMaxLength
+	}
+	if override.DescriptionMaxLength > 0 {
+		base.DescriptionMaxLength = override.DescriptionMaxLength
+	}
+	if override.MaxAttachments > 0 {
+		base.MaxAttachments = override.MaxAttachments
+	}
+	return base
+}
+
+// checkTextLength rejects text longer than max characters; label names the
+// field in the message.
+func checkTextLength(label, text string, max int) error {
+	if utf8.RuneCountInString(text) > max {
+		return utils.NewBadRequestError(fmt.Sprintf("%s must be at most %d characters", label, max), nil)
+	}
+	return nil
+}
+
+// checkAttachmentCount rejects more than max attachments.
+func checkAttachmentCount(n, max int) error {
+	if n > max {
+		return utils.NewBadRequestError(fmt.Sprintf("Maximum %d attachments allowed", max), nil)
+	}
+	return nil
+}
+
+// checkPollOptions applies the poll option count and length limits.
+func checkPollOptions(options []string, limits models.ContentLimits) error {
+	if len(options) > limits.PollMaxOptions {
+		return utils.NewBadRequestError(fmt.Sprintf("Maximum %d poll options allowed", limits.PollMaxOptions), nil)
+	}
+	for _, option := range options {
+		if err := checkTextLength("Poll options", option, limits.PollOptionMaxLength); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hamsaya/backend/config"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/hamsaya/backend/pkg/runtimeconfig"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// CreationAction names a kind of user-generated content that is throttled
+// per user.
+type CreationAction string
+
+const (
+	CreationPost    CreationAction = "post"
+	CreationComment CreationAction = "comment"
+	CreationMessage CreationAction = "message"
+)
+
+// CreationLimit allows Max creations per Window. Max <= 0 disables the limit.
+type CreationLimit struct {
+	Max    int
+	Window time.Duration
+}
+
+// DefaultCreationLimits are generous enough that a person typing never hits
+// them; they exist to stop scripted bursts from a single account that the
+// IP-level middleware limits miss (shared NAT, rotating IPs).
+var DefaultCreationLimits = map[CreationAction]CreationLimit{
+	CreationPost:    {Max: 10, Window: time.Hour},
+	CreationComment: {Max: 10, Window: time.Minute},
+	CreationMessage: {Max: 20, Window: 10 * time.Second},
+}
+
+// CreationLimitsFromConfig builds the limit table from CREATE_LIMIT_*
+// settings, keeping defaults for any left at zero.
+func CreationLimitsFromConfig(cfg config.RateLimitConfig) map[CreationAction]CreationLimit {
+	limits := make(map[CreationAction]CreationLimit)
+	if cfg.CreatePostsPerHour > 0 {
+		limits[CreationPost] = CreationLimit{Max: cfg.CreatePostsPerHour, Window: time.Hour}
+	}
+	if cfg.CreateCommentsPerMinute > 0 {
+		limits[CreationComment] = CreationLimit{Max: cfg.CreateCommentsPerMinute, Window: time.Minute}
+	}
+	if cfg.CreateMessagesPer10s > 0 {
+		limits[CreationMessage] = CreationLimit{Max: cfg.CreateMessagesPer10s, Window: 10 * time.Second}
+	}
+	return limits
+}
+
+// CreationThrottle enforces per-user content creation limits with a fixed
+// window Redis counter under "create_throttle:{action}:{userID}".
+//
+// It is deliberately soft: a Redis failure lets the create through (the
+// IP-level limits still apply) rather than blocking every author.
+type CreationThrottle struct {
+	redis    *redis.Client
+	limits   map[CreationAction]CreationLimit
+	settings *runtimeconfig.Store
+	logger   *zap.Logger
+}
+
+// NewCreationThrottle creates a throttle. Actions missing from limits fall
+// back to DefaultCreationLimits.
+func NewCreationThrottle(redisClient *redis.Client, limits map[CreationAction]CreationLimit, logger *zap.Logger) *CreationThrottle {
+	merged := make(map[CreationAction]CreationLimit, len(DefaultCreationLimits))
+	for action, limit := range DefaultCreationLimits {
+		merged[action] = limit
+	}
+	for action, limit := range limits {
+		merged[action] = limit
+	}
+	return &CreationThrottle{
+		redis:  redisClient,
+		limits: merged,
+		logger: logger,
+	}
+}
+
+// WithRuntimeSettings makes each limit tunable at runtime via
+// "throttle.<action>.max" and "throttle.<action>.window".
+func (t *CreationThrottle) WithRuntimeSettings(store *runtimeconfig.Store) *CreationThrottle {
+	for action, limit := range t.limits {
+		if limit.Max <= 0 {
+			continue
+		}
+		store.Register(runtimeconfig.Setting{
+			Key:         "throttle." + string(action) + ".max",
+			Kind:        runtimeconfig.KindInt,
+			Default:     strconv.Itoa(limit.Max),
+			Description: "Per-user " + string(action) + " creations allowed per window",
+		})
+		store.Register(runtimeconfig.Setting{
+			Key:         "throttle." + string(action) + ".window",
+			Kind:        runtimeconfig.KindDuration,
+			Default:     limit.Window.String(),
+			Description: "Window for the per-user " + string(action) + " creation limit",
+		})
+	}
+	t.settings = store
+	return t
+}
+
+func (t *CreationThrottle) limitFor(action CreationAction) CreationLimit {
+	limit := t.limits[action]
+	if t.settings != nil && limit.Max > 0 {
+		limit.Max = t.settings.Int("throttle."+string(action)+".max", limit.Max)
+		limit.Window = t.settings.Duration("throttle."+string(action)+".window", limit.Window)
+	}
+	return limit
+}
+
+// Allow records one creation of action by userID. It returns a slow-down
+// AppError (429) when the user is over the limit for the current window.
+// Nil-safe so services wired without a throttle skip the check.
+func (t *CreationThrottle) Allow(ctx context.Context, action CreationAction, userID string) error {
+	if t == nil || t.redis == nil || userID == "" {
+		return nil
+	}
+	limit := t.limitFor(action)
+	if limit.Max <= 0 || limit.Window <= 0 {
+		return nil
+	}
+
+	key := fmt.Sprintf("create_throttle:%s:%s", action, userID)
+	count, err := t.redis.Incr(ctx, key).Result()
+	if err != nil {
+		t.logger.Warn("Creation throttle check failed; allowing",
+			zap.String("action", string(action)), zap.String("user_id", userID), zap.Error(err))
+		return nil
+	}
+	if count == 1 {
+		if err := t.redis.Expire(ctx, key, limit.Window).Err(); err != nil {
+			t.logger.Warn("Creation throttle expire failed",
+				zap.String("action", string(action)), zap.String("user_id", userID), zap.Error(err))
+		}
+	}
+	if count <= int64(limit.Max) {
+		return nil
+	}
+
+	retryAfter, err := t.redis.TTL(ctx, key).Result()
+	if err != nil || retryAfter <= 0 {
+		// Key lost its TTL (expire failed above); re-arm it so the user
+		// isn't throttled forever.
+		retryAfter = limit.Window
+		_ = t.redis.Expire(ctx, key, limit.Window).Err()
+	}
+	t.logger.Info("Creation throttled",
+		zap.String("action", string(action)),
+		zap.String("user_id", userID),
+		zap.Int64("count", count),
+		zap.Int("max", limit.Max),
+	)
+	return utils.NewSlowDownError(string(action), retryAfter)
+}
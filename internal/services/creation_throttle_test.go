@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/hamsaya/backend/pkg/runtimeconfig"
+)
+
+func newCreationThrottleTest(t *testing.T, limits map[CreationAction]CreationLimit) (*CreationThrottle, *miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	return NewCreationThrottle(rdb, limits, zap.NewNop()), mr, rdb
+}
+
+func TestCreationThrottle_SlowsDownOverLimit(t *testing.T) {
+	throttle, _, _ := newCreationThrottleTest(t, map[CreationAction]CreationLimit{
+		CreationComment: {Max: 2, Window: time.Minute},
+	})
+	ctx := context.Background()
+
+	require.NoError(t, throttle.Allow(ctx, CreationComment, "user-1"))
+	require.NoError(t, throttle.Allow(ctx, CreationComment, "user-1"))
+
+	err := throttle.Allow(ctx, CreationComment, "user-1")
+	require.Error(t, err)
+	var appErr *utils.AppError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, http.StatusTooManyRequests, appErr.Code)
+	slowDown, ok := appErr.Err.(*utils.SlowDownError)
+	require.True(t, ok)
+	assert.Equal(t, "comment", slowDown.Action)
+	assert.Greater(t, slowDown.RetryAfterSeconds, 0)
+	assert.LessOrEqual(t, slowDown.RetryAfterSeconds, 60)
+
+	// Other users and other actions have their own counters.
+	assert.NoError(t, throttle.Allow(ctx, CreationComment, "user-2"))
+	assert.NoError(t, throttle.Allow(ctx, CreationPost, "user-1"))
+}
+
+func TestCreationThrottle_WindowResets(t *testing.T) {
+	throttle, mr, _ := newCreationThrottleTest(t, map[CreationAction]CreationLimit{
+		CreationMessage: {Max: 1, Window: 10 * time.Second},
+	})
+	ctx := context.Background()
+
+	require.NoError(t, throttle.Allow(ctx, CreationMessage, "user-1"))
+	require.Error(t, throttle.Allow(ctx, CreationMessage, "user-1"))
+
+	mr.FastForward(11 * time.Second)
+	assert.NoError(t, throttle.Allow(ctx, CreationMessage, "user-1"))
+}
+
+func TestCreationThrottle_RuntimeOverride(t *testing.T) {
+	throttle, _, rdb := newCreationThrottleTest(t, map[CreationAction]CreationLimit{
+		CreationPost: {Max: 1, Window: time.Hour},
+	})
+	store := runtimeconfig.New(rdb, zap.NewNop())
+	throttle.WithRuntimeSettings(store)
+	ctx := context.Background()
+	require.NoError(t, store.Set(ctx, "throttle.post.max", "3"))
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, throttle.Allow(ctx, CreationPost, "user-1"))
+	}
+	assert.Error(t, throttle.Allow(ctx, CreationPost, "user-1"))
+}
+
+func TestCreationThrottle_FailsOpen(t *testing.T) {
+	throttle, mr, _ := newCreationThrottleTest(t, map[CreationAction]CreationLimit{
+		CreationPost: {Max: 1, Window: time.Hour},
+	})
+	mr.Close()
+
+	assert.NoError(t, throttle.Allow(context.Background(), CreationPost, "user-1"))
+	assert.NoError(t, throttle.Allow(context.Background(), CreationPost, "user-1"))
+
+	var nilThrottle *CreationThrottle
+	assert.NoError(t, nilThrottle.Allow(context.Background(), CreationPost, "user-1"))
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/hamsaya/backend/internal/utils"
 	"github.com/hamsaya/backend/pkg/database"
 	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
@@ -114,12 +115,12 @@ func (s *DeletionRequestService) Approve(ctx context.Context, id, adminID, notes
 		id,
 	).Scan(&userID, &status); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return fmt.Errorf("request not found")
+			return utils.NewNotFoundError("Deletion request not found", nil)
 		}
 		return err
 	}
 	if status != "pending" {
-		return fmt.Errorf("request is %s, not pending", status)
+		return utils.NewConflictError(fmt.Sprintf("Request is %s, not pending", status), nil)
 	}
 
 	if _, err := tx.Exec(ctx, `
@@ -155,7 +156,7 @@ func (s *DeletionRequestService) Approve(ctx context.Context, id, adminID, notes
 // (admins must explain rejections — kept on file for compliance).
 func (s *DeletionRequestService) Reject(ctx context.Context, id, adminID, notes string) error {
 	if notes == "" {
-		return fmt.Errorf("rejection requires notes")
+		return utils.NewBadRequestError("Rejection requires notes", nil)
 	}
 	tag, err := s.db.Pool.Exec(ctx, `
 		UPDATE account_deletion_requests
@@ -166,7 +167,7 @@ func (s *DeletionRequestService) Reject(ctx context.Context, id, adminID, notes
 		return err
 	}
 	if tag.RowsAffected() == 0 {
-		return fmt.Errorf("no pending request with id %s", id)
+		return utils.NewNotFoundError("No pending deletion request with this id", nil)
 	}
 	return nil
 }
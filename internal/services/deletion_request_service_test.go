@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -16,8 +17,8 @@ import (
 func TestDeletionRequestService_RejectRequiresNotes(t *testing.T) {
 	svc := &DeletionRequestService{db: nil, logger: zap.NewNop()}
 	err := svc.Reject(context.Background(), "deadbeef-id", "admin-id", "")
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "rejection requires notes")
+	requireAppErrCode(t, err, http.StatusBadRequest)
+	assert.Contains(t, err.Error(), "Rejection requires notes")
 }
 
 func TestDeletionRequestService_RejectNotesWhitespaceCountsAsEmpty(t *testing.T) {
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+)
+
+// maxDeltaTombstones caps the removed IDs in one delta page. A client that
+// missed more removals than this is better off refetching, so the page is
+// Reset instead.
+const maxDeltaTombstones = 1000
+
+// deltaClockSkew is taken off "now" when a delta page is complete. A
+// transaction that stamped updated_at before ours but committed after it is
+// then picked up on the next call; the client may see such a row twice and
+// upserts it by ID.
+const deltaClockSkew = 5 * time.Second
+
+// deltaSyncExpired reports whether since is older than the tombstone
+// retention, so removals may have been forgotten.
+func deltaSyncExpired(since, now time.Time) bool {
+	return now.Sub(since) > models.DeltaSyncWindow
+}
+
+// newDeltaPage assembles a delta page from n changed rows fetched with
+// limit; lastUpdated is the updated_at of the last of them. A full page
+// continues from lastUpdated, otherwise the client is caught up to now.
+func newDeltaPage(items interface{}, n, limit int, lastUpdated time.Time, deleted []string, since, now time.Time) *models.DeltaPage {
+	if len(deleted) > maxDeltaTombstones {
+		return resetDeltaPage(now)
+	}
+	page := &models.DeltaPage{Items: items, Deleted: deleted}
+	if n >= limit {
+		page.HasMore = true
+		page.NextSince = lastUpdated
+		return page
+	}
+	page.NextSince = now.Add(-deltaClockSkew)
+	if page.NextSince.Before(since) {
+		page.NextSince = since
+	}
+	return page
+}
+
+// resetDeltaPage tells the client to drop its cache and page the list from
+// the start, then sync from NextSince.
+func resetDeltaPage(now time.Time) *models.DeltaPage {
+	return &models.DeltaPage{
+		Items:     []interface{}{},
+		Deleted:   []string{},
+		NextSince: now.Add(-deltaClockSkew),
+		Reset:     true,
+	}
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDeltaPage(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	since := now.Add(-time.Hour)
+	last := now.Add(-10 * time.Minute)
+
+	t.Run("partial page catches the client up", func(t *testing.T) {
+		page := newDeltaPage([]string{"a"}, 1, 20, last, []string{"x"}, since, now)
+		assert.False(t, page.HasMore)
+		assert.False(t, page.Reset)
+		assert.Equal(t, now.Add(-deltaClockSkew), page.NextSince)
+		assert.Equal(t, []string{"x"}, page.Deleted)
+	})
+
+	t.Run("full page continues from the last change", func(t *testing.T) {
+		page := newDeltaPage([]string{"a", "b"}, 2, 2, last, []string{}, since, now)
+		assert.True(t, page.HasMore)
+		assert.Equal(t, last, page.NextSince)
+	})
+
+	t.Run("next since never moves backwards", func(t *testing.T) {
+		recent := now.Add(-time.Second)
+		page := newDeltaPage([]string{}, 0, 20, time.Time{}, []string{}, recent, now)
+		assert.Equal(t, recent, page.NextSince)
+	})
+
+	t.Run("too many removals resets", func(t *testing.T) {
+		deleted := make([]string, maxDeltaTombstones+1)
+		page := newDeltaPage([]string{}, 0, 20, time.Time{}, deleted, since, now)
+		assert.True(t, page.Reset)
+		assert.Empty(t, page.Deleted)
+	})
+}
+
+func TestNotificationService_GetNotificationChanges(t *testing.T) {
+	since := time.Now().Add(-time.Hour)
+
+	t.Run("returns changes and removals", func(t *testing.T) {
+		notifRepo := new(mocks.MockNotificationRepository)
+		tombstones := new(mocks.MockSyncTombstoneRepository)
+		svc := newTestNotificationService(notifRepo, new(mocks.MockNotificationSettingsRepository), nil).WithTombstones(tombstones)
+
+		updated := since.Add(time.Minute)
+		notifRepo.On("List", mock.Anything, mock.MatchedBy(func(f *models.GetNotificationsFilter) bool {
+			return f.UpdatedAfter != nil && f.UpdatedAfter.Equal(since)
+		})).Return([]*models.Notification{{ID: "n-1", UserID: "user-1", UpdatedAt: updated}}, nil)
+		tombstones.On("DeletedSince", mock.Anything, "user-1", models.SyncEntityNotification, since, maxDeltaTombstones+1).
+			Return([]string{"n-0"}, nil)
+
+		page, err := svc.GetNotificationChanges(context.Background(), &models.GetNotificationsFilter{UserID: "user-1", Limit: 20}, since)
+		require.NoError(t, err)
+		items := page.Items.([]*models.NotificationResponse)
+		require.Len(t, items, 1)
+		assert.Equal(t, "n-1", items[0].ID)
+		assert.Equal(t, []string{"n-0"}, page.Deleted)
+		assert.False(t, page.HasMore)
+	})
+
+	t.Run("since older than the sync window resets", func(t *testing.T) {
+		notifRepo := new(mocks.MockNotificationRepository)
+		svc := newTestNotificationService(notifRepo, new(mocks.MockNotificationSettingsRepository), nil)
+
+		page, err := svc.GetNotificationChanges(context.Background(), &models.GetNotificationsFilter{UserID: "user-1", Limit: 20},
+			time.Now().Add(-models.DeltaSyncWindow-time.Hour))
+		require.NoError(t, err)
+		assert.True(t, page.Reset)
+		notifRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		notifRepo := new(mocks.MockNotificationRepository)
+		svc := newTestNotificationService(notifRepo, new(mocks.MockNotificationSettingsRepository), nil)
+		notifRepo.On("List", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("db down"))
+
+		_, err := svc.GetNotificationChanges(context.Background(), &models.GetNotificationsFilter{UserID: "user-1", Limit: 20}, since)
+		require.Error(t, err)
+	})
+}
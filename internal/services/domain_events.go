@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/events"
+	"github.com/hamsaya/backend/pkg/observability"
+)
+
+// Domain events published by the services on the events bus. Publishers
+// don't know who listens; notification, analytics and moderation
+// subscribers are registered in cmd/server.
+
+// PostCreated is published once a post and its attachments are stored.
+type PostCreated struct {
+	Post *models.Post
+}
+
+func (PostCreated) EventName() string { return "post.created" }
+
+// PostEdited is published after the owner changes a post's title or
+// description.
+type PostEdited struct {
+	Post *models.Post
+}
+
+func (PostEdited) EventName() string { return "post.edited" }
+
+// PostLiked is published when ActorID likes a post owned by OwnerID.
+// OwnerID is empty for posts without a personal owner.
+type PostLiked struct {
+	PostID  string
+	OwnerID string
+	ActorID string
+}
+
+func (PostLiked) EventName() string { return "post.liked" }
+
+// BusinessMediaUploaded is published when an image is set as a business's
+// avatar or cover or added to its gallery. Post attachments are queued for
+// moderation by the repository instead.
+type BusinessMediaUploaded struct {
+	BusinessID string
+	URL        string
+}
+
+func (BusinessMediaUploaded) EventName() string { return "business.media_uploaded" }
+
+// MessageSent is published after a chat message is stored.
+type MessageSent struct {
+	Message      *models.Message
+	Conversation *models.Conversation
+	RecipientID  string
+}
+
+func (MessageSent) EventName() string { return "message.sent" }
+
+// Report kinds carried by ReportFiled.
+const (
+	ReportKindPost     = "post"
+	ReportKindComment  = "comment"
+	ReportKindUser     = "user"
+	ReportKindBusiness = "business"
+	ReportKindMessage  = "message"
+)
+
+// ReportFiled is published after a report is stored. TargetID is the id of
+// the reported post, comment, user, business or chat message.
+type ReportFiled struct {
+	Kind       string
+	TargetID   string
+	ReporterID string
+	Reason     string
+}
+
+func (ReportFiled) EventName() string { return "report.filed" }
+
+// SubscribeNotifications sends follower, nearby-alert and like
+// notifications for post events.
+func (s *PostService) SubscribeNotifications(bus *events.Bus) {
+	events.Subscribe(bus, func(ctx context.Context, e PostCreated) {
+		// Group posts stay inside the group.
+		if e.Post.GroupID != nil || e.Post.UserID == nil {
+			return
+		}
+		// Alerts go to everyone nearby instead of followers; a follower who
+		// lives in range still hears about it exactly once.
+		if e.Post.Type == models.PostTypeAlert {
+			s.notifyNearbyOfAlert(ctx, e.Post)
+			return
+		}
+		s.notifyFollowersOfNewPost(ctx, e.Post.ID, *e.Post.UserID, e.Post.BusinessID)
+	})
+	events.Subscribe(bus, func(ctx context.Context, e PostLiked) {
+		if e.OwnerID == "" || e.OwnerID == e.ActorID || s.notificationService == nil {
+			return
+		}
+		s.sendPostNotification(ctx, e.ActorID, e.OwnerID, e.PostID, models.NotificationTypeLike, "liked your post")
+	})
+}
+
+// SubscribeNotifications pushes new messages to the recipient over the
+// WebSocket and as a notification.
+func (s *ChatService) SubscribeNotifications(bus *events.Bus) {
+	events.Subscribe(bus, func(_ context.Context, e MessageSent) {
+		s.notifyMessageSent(e.Message, e.RecipientID, e.Conversation)
+	})
+}
+
+// SubscribeModeration auto-hides posts and comments once they collect
+// enough pending reports. Admins review and reinstate from the moderation
+// queue.
+func (s *ReportService) SubscribeModeration(bus *events.Bus) {
+	events.Subscribe(bus, func(ctx context.Context, e ReportFiled) {
+		switch e.Kind {
+		case ReportKindPost:
+			s.autoHidePost(ctx, e.TargetID)
+		case ReportKindComment:
+			s.autoHideComment(ctx, e.TargetID)
+		}
+	})
+}
+
+// SubscribeAnalytics records the business metrics for domain events.
+func SubscribeAnalytics(bus *events.Bus) {
+	events.Subscribe(bus, func(ctx context.Context, e PostCreated) {
+		observability.RecordPostCreated(ctx, string(e.Post.Type))
+	})
+	events.Subscribe(bus, func(ctx context.Context, _ MessageSent) {
+		observability.RecordMessageCreated(ctx)
+	})
+	events.Subscribe(bus, func(ctx context.Context, e ReportFiled) {
+		observability.RecordReportFiled(ctx, e.Kind)
+	})
+}
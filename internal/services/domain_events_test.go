@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/hamsaya/backend/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostService_LikePost_PublishesPostLiked(t *testing.T) {
+	postRepo := new(mocks.MockPostRepository)
+	postRepo.On("GetByID", mock.Anything, "post-1").
+		Return(testutil.CreateTestPost("post-1", "owner-1", models.PostTypeFeed), nil)
+	postRepo.On("LikePost", mock.Anything, "user-2", "post-1").Return(nil)
+
+	bus := events.NewSync(nil)
+	var got []PostLiked
+	events.Subscribe(bus, func(_ context.Context, e PostLiked) { got = append(got, e) })
+
+	svc := newTestPostService(postRepo, new(mocks.MockUserRepository)).WithEvents(bus)
+	require.NoError(t, svc.LikePost(context.Background(), "user-2", "post-1"))
+
+	assert.Equal(t, []PostLiked{{PostID: "post-1", OwnerID: "owner-1", ActorID: "user-2"}}, got)
+}
+
+func TestReportService_SubscribeModeration(t *testing.T) {
+	report := &models.CreatePostReportRequest{Reason: "Spam"}
+
+	t.Run("hides the post at the threshold", func(t *testing.T) {
+		reportRepo := new(mocks.MockReportRepository)
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").
+			Return(testutil.CreateTestPost("post-1", "owner-1", models.PostTypeFeed), nil)
+		reportRepo.On("CreatePostReport", mock.Anything, mock.AnythingOfType("*models.PostReport")).Return(nil)
+		reportRepo.On("CountPendingPostReports", mock.Anything, "post-1").Return(autoHidePostThreshold, nil)
+		reportRepo.On("HidePost", mock.Anything, "post-1").Return(nil)
+
+		bus := events.NewSync(nil)
+		svc := NewReportService(reportRepo, postRepo, new(mocks.MockUserRepository), testutil.CreateTestValidator()).
+			WithEvents(bus)
+		svc.SubscribeModeration(bus)
+
+		require.NoError(t, svc.ReportPost(context.Background(), "user-2", "post-1", report))
+		reportRepo.AssertExpectations(t)
+	})
+
+	t.Run("below the threshold the post stays", func(t *testing.T) {
+		reportRepo := new(mocks.MockReportRepository)
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").
+			Return(testutil.CreateTestPost("post-1", "owner-1", models.PostTypeFeed), nil)
+		reportRepo.On("CreatePostReport", mock.Anything, mock.AnythingOfType("*models.PostReport")).Return(nil)
+		reportRepo.On("CountPendingPostReports", mock.Anything, "post-1").Return(autoHidePostThreshold-1, nil)
+
+		bus := events.NewSync(nil)
+		svc := NewReportService(reportRepo, postRepo, new(mocks.MockUserRepository), testutil.CreateTestValidator()).
+			WithEvents(bus)
+		svc.SubscribeModeration(bus)
+
+		require.NoError(t, svc.ReportPost(context.Background(), "user-2", "post-1", report))
+		reportRepo.AssertNotCalled(t, "HidePost", mock.Anything, mock.Anything)
+	})
+}
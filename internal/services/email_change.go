@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	// emailChangeTTL is how long the code sent to the new address is valid.
+	emailChangeTTL = 24 * time.Hour
+	// emailChangeRevertTTL is how long the old address can undo the change.
+	emailChangeRevertTTL = 7 * 24 * time.Hour
+	// maxEmailChangeAttempts wrong codes cancel the pending change.
+	maxEmailChangeAttempts = 5
+)
+
+// RequestEmailChange starts moving the account to a new address: a code goes
+// to the new address and a notice with a revert link to the current one.
+// Nothing changes until ConfirmEmailChange.
+func (s *AuthService) RequestEmailChange(ctx context.Context, userID string, req *models.ChangeEmailRequest) (*models.EmailChangeResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, utils.NewNotFoundError("User not found", err)
+	}
+	if user.PasswordHash != nil && !s.passwordService.Verify(req.Password, *user.PasswordHash) {
+		return nil, utils.NewUnauthorizedError("Password is incorrect", nil)
+	}
+
+	newEmail := strings.ToLower(strings.TrimSpace(req.NewEmail))
+	if newEmail == strings.ToLower(user.Email) {
+		return nil, utils.NewBadRequestError("This is already your email address", nil)
+	}
+	if existing, err := s.userRepo.GetByEmail(ctx, newEmail); err == nil && existing != nil {
+		return nil, utils.NewConflictError("Email is already in use", nil)
+	}
+
+	code, err := s.jwtService.GenerateVerificationCode()
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to start email change", err)
+	}
+	revertToken, err := newEmailChangeRevertToken()
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to start email change", err)
+	}
+
+	expiresAt := time.Now().Add(emailChangeTTL)
+	change := &PendingEmailChange{
+		OldEmail:  user.Email,
+		NewEmail:  newEmail,
+		CodeHash:  hashToken(code),
+		ExpiresAt: expiresAt,
+	}
+	if err := s.tokenStorage.StoreEmailChange(ctx, userID, change, emailChangeTTL); err != nil {
+		return nil, utils.NewInternalError("Failed to start email change", err)
+	}
+	revert := &EmailChangeRevert{UserID: userID, OldEmail: user.Email, NewEmail: newEmail}
+	if err := s.tokenStorage.StoreEmailChangeRevert(ctx, revertToken, revert, emailChangeRevertTTL); err != nil {
+		_ = s.tokenStorage.DeleteEmailChange(ctx, userID)
+		return nil, utils.NewInternalError("Failed to start email change", err)
+	}
+
+	name := s.recipientName(ctx, user)
+	if err := s.emailService.SendEmailChangeCodeEmail(newEmail, name, code); err != nil {
+		_ = s.tokenStorage.DeleteEmailChange(ctx, userID)
+		s.logger.Error("Failed to send email change code", zap.String("user_id", userID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to send confirmation email", err)
+	}
+	// The old mailbox may be the reason for the change (lost access), so a
+	// failed notice doesn't block it.
+	if err := s.emailService.SendEmailChangeNoticeEmail(user.Email, name, newEmail, s.emailChangeRevertURL(revertToken)); err != nil {
+		s.logger.Warn("Failed to send email change notice", zap.String("user_id", userID), zap.Error(err))
+	}
+
+	s.logger.Info("Email change requested", zap.String("user_id", userID))
+	return &models.EmailChangeResponse{NewEmail: newEmail, ExpiresAt: expiresAt}, nil
+}
+
+// ConfirmEmailChange swaps in the new address once its code is entered, then
+// revokes every session so all devices sign in again.
+func (s *AuthService) ConfirmEmailChange(ctx context.Context, userID string, req *models.ConfirmEmailChangeRequest) error {
+	change, err := s.tokenStorage.GetEmailChange(ctx, userID)
+	if err != nil {
+		return utils.NewInternalError("Failed to confirm email change", err)
+	}
+	if change == nil {
+		return utils.NewBadRequestError("No pending email change, or it has expired", nil)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashToken(req.Code)), []byte(change.CodeHash)) != 1 {
+		change.Attempts++
+		if change.Attempts >= maxEmailChangeAttempts {
+			_ = s.tokenStorage.DeleteEmailChange(ctx, userID)
+			return utils.NewBadRequestError("Too many invalid codes; request the email change again", nil)
+		}
+		if err := s.tokenStorage.UpdateEmailChange(ctx, userID, change); err != nil {
+			s.logger.Warn("Failed to record email change attempt", zap.String("user_id", userID), zap.Error(err))
+		}
+		return utils.NewBadRequestError("Invalid confirmation code", nil)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return utils.NewNotFoundError("User not found", err)
+	}
+	if user.Email != change.OldEmail {
+		// The address changed some other way since the request.
+		_ = s.tokenStorage.DeleteEmailChange(ctx, userID)
+		return utils.NewConflictError("Your email address has changed since this request", nil)
+	}
+
+	if err := s.setUserEmail(ctx, user, change.NewEmail); err != nil {
+		if errors.Is(err, repositories.ErrEmailTaken) {
+			_ = s.tokenStorage.DeleteEmailChange(ctx, userID)
+		}
+		return err
+	}
+	if err := s.tokenStorage.DeleteEmailChange(ctx, userID); err != nil {
+		s.logger.Warn("Failed to delete pending email change", zap.String("user_id", userID), zap.Error(err))
+	}
+
+	s.logger.Info("Email changed", zap.String("user_id", userID))
+	return nil
+}
+
+// RevertEmailChange handles the link sent to the old address: it cancels a
+// pending change, or restores the old address if the change went through.
+// Either way every session is revoked.
+func (s *AuthService) RevertEmailChange(ctx context.Context, req *models.RevertEmailChangeRequest) error {
+	revert, err := s.tokenStorage.GetEmailChangeRevert(ctx, req.Token)
+	if err != nil {
+		return utils.NewInternalError("Failed to revert email change", err)
+	}
+	if revert == nil {
+		return utils.NewBadRequestError("Invalid or expired link", nil)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, revert.UserID)
+	if err != nil {
+		return utils.NewNotFoundError("User not found", err)
+	}
+	if err := s.tokenStorage.DeleteEmailChange(ctx, user.ID); err != nil {
+		return utils.NewInternalError("Failed to revert email change", err)
+	}
+
+	if user.Email == revert.NewEmail {
+		if err := s.setUserEmail(ctx, user, revert.OldEmail); err != nil {
+			return err
+		}
+	} else {
+		// Cancelled before confirmation; still sign out whoever asked.
+		if err := s.userRepo.RevokeAllUserSessions(ctx, user.ID); err != nil {
+			s.logger.Error("Failed to revoke sessions", zap.Error(err))
+		}
+		s.invalidateAccessTokens(ctx, user.ID)
+	}
+
+	if err := s.tokenStorage.DeleteEmailChangeRevert(ctx, req.Token); err != nil {
+		s.logger.Warn("Failed to delete email change revert token", zap.Error(err))
+	}
+	s.logger.Info("Email change reverted", zap.String("user_id", user.ID))
+	return nil
+}
+
+// setUserEmail stores a verified email for the user and revokes all of
+// their sessions and access tokens.
+func (s *AuthService) setUserEmail(ctx context.Context, user *models.User, email string) error {
+	user.Email = email
+	user.EmailVerified = true
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		if errors.Is(err, repositories.ErrEmailTaken) {
+			return utils.NewConflictError("Email is already in use", err)
+		}
+		s.logger.Error("Failed to update user email", zap.String("user_id", user.ID), zap.Error(err))
+		return utils.NewInternalError("Failed to change email", err)
+	}
+
+	if err := s.userRepo.RevokeAllUserSessions(ctx, user.ID); err != nil {
+		s.logger.Error("Failed to revoke sessions", zap.Error(err))
+		// Continue anyway
+	}
+	s.invalidateAccessTokens(ctx, user.ID)
+	return nil
+}
+
+// recipientName is the user's full name for emails, or their address.
+func (s *AuthService) recipientName(ctx context.Context, user *models.User) string {
+	profile, err := s.userRepo.GetProfileByUserID(ctx, user.ID)
+	if err == nil && profile != nil && profile.FirstName != nil && profile.LastName != nil {
+		if joined := strings.TrimSpace(*profile.FirstName + " " + *profile.LastName); joined != "" {
+			return joined
+		}
+	}
+	return user.Email
+}
+
+// emailChangeRevertURL is the web page that posts the token to
+// /auth/email/revert.
+func (s *AuthService) emailChangeRevertURL(token string) string {
+	base := strings.TrimRight(s.cfg.Email.EmailVerifyBaseURL, "/")
+	if base == "" {
+		base = "https://hamsaya.com"
+	}
+	return base + "/email-change/revert?token=" + url.QueryEscape(token)
+}
+
+func newEmailChangeRevertToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
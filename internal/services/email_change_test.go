@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hamsaya/backend/config"
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func emailChangeUser() *models.User {
+	user := testutil.CreateTestUser("user-1", "old@example.com")
+	hash := testPasswordHash
+	user.PasswordHash = &hash
+	return user
+}
+
+// newEmailChangeAuthService wires an email service that delivers to a fake
+// Resend endpoint.
+func newEmailChangeAuthService(t *testing.T, userRepo *mocks.MockUserRepository, ts *TokenStorageService) *AuthService {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"msg-1"}`))
+	}))
+	t.Cleanup(server.Close)
+	svc := newTestAuthService(userRepo, ts)
+	svc.emailService = NewEmailService(&config.EmailConfig{ResendAPIKey: "test-key", From: "noreply@hamsaya.com"}, zap.NewNop())
+	svc.emailService.httpClient = &http.Client{Transport: &rewriteTransport{target: server.URL}}
+	return svc
+}
+
+func TestAuthService_RequestEmailChange(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("wrong password", func(t *testing.T) {
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetByID", mock.Anything, "user-1").Return(emailChangeUser(), nil)
+		ts, _ := newTestTokenStorage(t)
+		svc := newEmailChangeAuthService(t, userRepo, ts)
+
+		_, err := svc.RequestEmailChange(ctx, "user-1", &models.ChangeEmailRequest{NewEmail: "new@example.com", Password: "nope"})
+		requireAppErrCode(t, err, http.StatusUnauthorized)
+	})
+
+	t.Run("same address", func(t *testing.T) {
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetByID", mock.Anything, "user-1").Return(emailChangeUser(), nil)
+		ts, _ := newTestTokenStorage(t)
+		svc := newEmailChangeAuthService(t, userRepo, ts)
+
+		_, err := svc.RequestEmailChange(ctx, "user-1", &models.ChangeEmailRequest{NewEmail: " OLD@example.com", Password: "password"})
+		requireAppErrCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("address in use", func(t *testing.T) {
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetByID", mock.Anything, "user-1").Return(emailChangeUser(), nil)
+		userRepo.On("GetByEmail", mock.Anything, "new@example.com").Return(testutil.CreateTestUser("user-2", "new@example.com"), nil)
+		ts, _ := newTestTokenStorage(t)
+		svc := newEmailChangeAuthService(t, userRepo, ts)
+
+		_, err := svc.RequestEmailChange(ctx, "user-1", &models.ChangeEmailRequest{NewEmail: "new@example.com", Password: "password"})
+		requireAppErrCode(t, err, http.StatusConflict)
+	})
+
+	t.Run("stores the pending change without touching the account", func(t *testing.T) {
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetByID", mock.Anything, "user-1").Return(emailChangeUser(), nil)
+		userRepo.On("GetByEmail", mock.Anything, "new@example.com").Return(nil, errors.New("not found"))
+		userRepo.On("GetProfileByUserID", mock.Anything, "user-1").Return(testutil.CreateTestProfile("user-1", "Test", "User"), nil)
+		ts, _ := newTestTokenStorage(t)
+		svc := newEmailChangeAuthService(t, userRepo, ts)
+
+		resp, err := svc.RequestEmailChange(ctx, "user-1", &models.ChangeEmailRequest{NewEmail: "New@example.com", Password: "password"})
+		require.NoError(t, err)
+		assert.Equal(t, "new@example.com", resp.NewEmail)
+
+		change, err := ts.GetEmailChange(ctx, "user-1")
+		require.NoError(t, err)
+		require.NotNil(t, change)
+		assert.Equal(t, "old@example.com", change.OldEmail)
+		assert.Equal(t, "new@example.com", change.NewEmail)
+		userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuthService_ConfirmEmailChange(t *testing.T) {
+	ctx := context.Background()
+	pending := func() *PendingEmailChange {
+		return &PendingEmailChange{OldEmail: "old@example.com", NewEmail: "new@example.com", CodeHash: hashToken("123456")}
+	}
+
+	t.Run("no pending change", func(t *testing.T) {
+		ts, _ := newTestTokenStorage(t)
+		svc := newTestAuthService(new(mocks.MockUserRepository), ts)
+
+		err := svc.ConfirmEmailChange(ctx, "user-1", &models.ConfirmEmailChangeRequest{Code: "123456"})
+		requireAppErrCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("too many wrong codes cancel the change", func(t *testing.T) {
+		ts, _ := newTestTokenStorage(t)
+		require.NoError(t, ts.StoreEmailChange(ctx, "user-1", pending(), time.Hour))
+		svc := newTestAuthService(new(mocks.MockUserRepository), ts)
+
+		for i := 0; i < maxEmailChangeAttempts; i++ {
+			err := svc.ConfirmEmailChange(ctx, "user-1", &models.ConfirmEmailChangeRequest{Code: "000000"})
+			requireAppErrCode(t, err, http.StatusBadRequest)
+		}
+		change, err := ts.GetEmailChange(ctx, "user-1")
+		require.NoError(t, err)
+		assert.Nil(t, change)
+	})
+
+	t.Run("swaps the address and revokes sessions", func(t *testing.T) {
+		ts, _ := newTestTokenStorage(t)
+		require.NoError(t, ts.StoreEmailChange(ctx, "user-1", pending(), time.Hour))
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetByID", mock.Anything, "user-1").Return(emailChangeUser(), nil)
+		userRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *models.User) bool {
+			return u.Email == "new@example.com" && u.EmailVerified
+		})).Return(nil)
+		userRepo.On("RevokeAllUserSessions", mock.Anything, "user-1").Return(nil)
+		svc := newTestAuthService(userRepo, ts)
+
+		require.NoError(t, svc.ConfirmEmailChange(ctx, "user-1", &models.ConfirmEmailChangeRequest{Code: "123456"}))
+		userRepo.AssertExpectations(t)
+
+		revoked, err := ts.IsAccessTokenRevoked(ctx, "jti-1", "user-1", time.Now().Add(-time.Minute).UnixMilli())
+		require.NoError(t, err)
+		assert.True(t, revoked)
+		change, _ := ts.GetEmailChange(ctx, "user-1")
+		assert.Nil(t, change)
+	})
+
+	t.Run("address taken meanwhile", func(t *testing.T) {
+		ts, _ := newTestTokenStorage(t)
+		require.NoError(t, ts.StoreEmailChange(ctx, "user-1", pending(), time.Hour))
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetByID", mock.Anything, "user-1").Return(emailChangeUser(), nil)
+		userRepo.On("Update", mock.Anything, mock.Anything).Return(repositories.ErrEmailTaken)
+		svc := newTestAuthService(userRepo, ts)
+
+		err := svc.ConfirmEmailChange(ctx, "user-1", &models.ConfirmEmailChangeRequest{Code: "123456"})
+		requireAppErrCode(t, err, http.StatusConflict)
+		userRepo.AssertNotCalled(t, "RevokeAllUserSessions", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAuthService_RevertEmailChange(t *testing.T) {
+	ctx := context.Background()
+	revert := &EmailChangeRevert{UserID: "user-1", OldEmail: "old@example.com", NewEmail: "new@example.com"}
+
+	t.Run("unknown token", func(t *testing.T) {
+		ts, _ := newTestTokenStorage(t)
+		svc := newTestAuthService(new(mocks.MockUserRepository), ts)
+
+		err := svc.RevertEmailChange(ctx, &models.RevertEmailChangeRequest{Token: "nope"})
+		requireAppErrCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("restores the old address after the change", func(t *testing.T) {
+		ts, _ := newTestTokenStorage(t)
+		require.NoError(t, ts.StoreEmailChangeRevert(ctx, "revert-token", revert, time.Hour))
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetByID", mock.Anything, "user-1").Return(testutil.CreateTestUser("user-1", "new@example.com"), nil)
+		userRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *models.User) bool {
+			return u.Email == "old@example.com"
+		})).Return(nil)
+		userRepo.On("RevokeAllUserSessions", mock.Anything, "user-1").Return(nil)
+		svc := newTestAuthService(userRepo, ts)
+
+		require.NoError(t, svc.RevertEmailChange(ctx, &models.RevertEmailChangeRequest{Token: "revert-token"}))
+		userRepo.AssertExpectations(t)
+
+		err := svc.RevertEmailChange(ctx, &models.RevertEmailChangeRequest{Token: "revert-token"})
+		requireAppErrCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("cancels a pending change", func(t *testing.T) {
+		ts, _ := newTestTokenStorage(t)
+		require.NoError(t, ts.StoreEmailChangeRevert(ctx, "revert-token", revert, time.Hour))
+		require.NoError(t, ts.StoreEmailChange(ctx, "user-1", &PendingEmailChange{OldEmail: "old@example.com", NewEmail: "new@example.com"}, time.Hour))
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetByID", mock.Anything, "user-1").Return(emailChangeUser(), nil)
+		userRepo.On("RevokeAllUserSessions", mock.Anything, "user-1").Return(nil)
+		svc := newTestAuthService(userRepo, ts)
+
+		require.NoError(t, svc.RevertEmailChange(ctx, &models.RevertEmailChangeRequest{Token: "revert-token"}))
+		change, _ := ts.GetEmailChange(ctx, "user-1")
+		assert.Nil(t, change)
+		userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
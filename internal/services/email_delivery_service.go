@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// resendWebhookTolerance bounds how old a signed webhook may be. Matches the
+// Svix default so replayed captures are rejected.
+const resendWebhookTolerance = 5 * time.Minute
+
+// EmailDeliveryService ingests provider delivery callbacks (bounces,
+// complaints, deliveries) and manages the suppression list surfaced to
+// support in the admin panel.
+type EmailDeliveryService struct {
+	repo          repositories.EmailDeliveryRepository
+	webhookSecret string
+	logger        *zap.Logger
+	now           func() time.Time
+}
+
+// NewEmailDeliveryService creates a new email delivery service. An empty
+// webhookSecret makes every webhook fail verification (fail closed).
+func NewEmailDeliveryService(repo repositories.EmailDeliveryRepository, webhookSecret string, logger *zap.Logger) *EmailDeliveryService {
+	return &EmailDeliveryService{
+		repo:          repo,
+		webhookSecret: webhookSecret,
+		logger:        logger,
+		now:           time.Now,
+	}
+}
+
+// ResendWebhookEvent is the subset of Resend's webhook payload we act on.
+type ResendWebhookEvent struct {
+	Type      string `json:"type"`
+	CreatedAt string `json:"created_at"`
+	Data      struct {
+		EmailID string   `json:"email_id"`
+		To      []string `json:"to"`
+		Bounce  *struct {
+			Type    string `json:"type"`
+			SubType string `json:"subType"`
+			Message string `json:"message"`
+		} `json:"bounce,omitempty"`
+	} `json:"data"`
+}
+
+// VerifyResendSignature checks the Svix signature headers Resend attaches to
+// every webhook: HMAC-SHA256 over "<svix-id>.<svix-timestamp>.<body>" keyed
+// with the base64 part of the "whsec_" secret. The signature header may carry
+// several space-separated "v1,<sig>" entries during secret rotation.
+func (s *EmailDeliveryService) VerifyResendSignature(msgID, timestamp, signatures string, body []byte) error {
+	if s.webhookSecret == "" {
+		return utils.NewUnauthorizedError("Email webhook secret not configured", nil)
+	}
+	if msgID == "" || timestamp == "" || signatures == "" {
+		return utils.NewUnauthorizedError("Missing webhook signature headers", nil)
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return utils.NewUnauthorizedError("Invalid webhook timestamp", err)
+	}
+	sent := time.Unix(ts, 0)
+	if d := s.now().Sub(sent); d > resendWebhookTolerance || d < -resendWebhookTolerance {
+		return utils.NewUnauthorizedError("Webhook timestamp outside tolerance", nil)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s.webhookSecret, "whsec_"))
+	if err != nil {
+		return utils.NewInternalError("Invalid email webhook secret", err)
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(msgID + "." + timestamp + "."))
+	mac.Write(body)
+	expected := mac.Sum(nil)
+
+	for _, entry := range strings.Fields(signatures) {
+		version, sig, ok := strings.Cut(entry, ",")
+		if !ok || version != "v1" {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(sig)
+		if err != nil {
+			continue
+		}
+		if hmac.Equal(decoded, expected) {
+			return nil
+		}
+	}
+	return utils.NewUnauthorizedError("Invalid webhook signature", nil)
+}
+
+// HandleResendEvent applies one verified webhook. Unknown event types are
+// acknowledged and ignored so Resend doesn't retry them forever.
+func (s *EmailDeliveryService) HandleResendEvent(ctx context.Context, body []byte) error {
+	var evt ResendWebhookEvent
+	if err := json.Unmarshal(body, &evt); err != nil {
+		return utils.NewBadRequestError("Invalid webhook payload", err)
+	}
+	if evt.Data.EmailID == "" {
+		return nil
+	}
+
+	var (
+		status         models.EmailDeliveryStatus
+		suppressReason string
+		detail         *string
+	)
+	switch evt.Type {
+	case "email.delivered":
+		status = models.EmailStatusDelivered
+	case "email.delivery_delayed":
+		status = models.EmailStatusDelayed
+	case "email.bounced":
+		status = models.EmailStatusBounced
+		if evt.Data.Bounce != nil {
+			d := strings.TrimSpace(evt.Data.Bounce.Type + " " + evt.Data.Bounce.SubType + ": " + evt.Data.Bounce.Message)
+			detail = &d
+		}
+		// Transient bounces (mailbox full, greylisting) resolve on their
+		// own; only permanent ones mark the address undeliverable.
+		if evt.Data.Bounce == nil || !strings.EqualFold(evt.Data.Bounce.Type, "Transient") {
+			suppressReason = models.EmailSuppressionBounce
+		}
+	case "email.complained":
+		status = models.EmailStatusComplained
+		suppressReason = models.EmailSuppressionComplaint
+	default:
+		return nil
+	}
+
+	to, err := s.repo.UpdateStatusByProviderID(ctx, "resend", evt.Data.EmailID, status, detail)
+	if err != nil {
+		return utils.NewInternalError("Failed to record email event", err)
+	}
+
+	if suppressReason == "" {
+		return nil
+	}
+	recipients := evt.Data.To
+	if len(recipients) == 0 && to != "" {
+		recipients = []string{to}
+	}
+	for _, addr := range recipients {
+		if err := s.repo.Suppress(ctx, addr, suppressReason, detail); err != nil {
+			return utils.NewInternalError("Failed to suppress address", err)
+		}
+		s.logger.Info("Email address suppressed",
+			zap.String("email", addr),
+			zap.String("reason", suppressReason),
+			zap.String("message_id", evt.Data.EmailID),
+		)
+	}
+	return nil
+}
+
+// GetDeliveryInfo assembles the admin-facing delivery block for an address.
+func (s *EmailDeliveryService) GetDeliveryInfo(ctx context.Context, email string, limit int) (*models.AdminEmailDeliveryInfo, error) {
+	suppression, err := s.repo.GetSuppression(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("get suppression: %w", err)
+	}
+	recent, err := s.repo.ListByEmail(ctx, email, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list sent emails: %w", err)
+	}
+	return &models.AdminEmailDeliveryInfo{
+		Suppression:  suppression,
+		RecentEmails: recent,
+	}, nil
+}
+
+// ClearSuppression lets support re-enable sends after the user confirms
+// their mailbox works again.
+func (s *EmailDeliveryService) ClearSuppression(ctx context.Context, email string) error {
+	if err := s.repo.Unsuppress(ctx, email); err != nil {
+		return utils.NewInternalError("Failed to clear email suppression", err)
+	}
+	return nil
+}
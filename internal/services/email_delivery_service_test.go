@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hamsaya/backend/config"
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const testWebhookKey = "dGVzdC13ZWJob29rLWtleQ==" // base64("test-webhook-key")
+
+func signSvix(t *testing.T, msgID, ts string, body []byte) string {
+	t.Helper()
+	key, err := base64.StdEncoding.DecodeString(testWebhookKey)
+	require.NoError(t, err)
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(msgID + "." + ts + "."))
+	mac.Write(body)
+	return "v1," + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestEmailDeliveryService_VerifyResendSignature(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	body := []byte(`{"type":"email.delivered"}`)
+	ts := strconv.FormatInt(now.Unix(), 10)
+
+	newSvc := func(secret string) *EmailDeliveryService {
+		svc := NewEmailDeliveryService(new(mocks.MockEmailDeliveryRepository), secret, zap.NewNop())
+		svc.now = func() time.Time { return now }
+		return svc
+	}
+
+	t.Run("valid signature", func(t *testing.T) {
+		svc := newSvc("whsec_" + testWebhookKey)
+		assert.NoError(t, svc.VerifyResendSignature("msg_1", ts, signSvix(t, "msg_1", ts, body), body))
+	})
+
+	t.Run("valid among rotated signatures", func(t *testing.T) {
+		svc := newSvc("whsec_" + testWebhookKey)
+		sigs := "v1,bm90LXRoZS1zaWc= " + signSvix(t, "msg_1", ts, body)
+		assert.NoError(t, svc.VerifyResendSignature("msg_1", ts, sigs, body))
+	})
+
+	t.Run("tampered body", func(t *testing.T) {
+		svc := newSvc("whsec_" + testWebhookKey)
+		sig := signSvix(t, "msg_1", ts, body)
+		assert.Error(t, svc.VerifyResendSignature("msg_1", ts, sig, []byte(`{"type":"email.bounced"}`)))
+	})
+
+	t.Run("stale timestamp", func(t *testing.T) {
+		svc := newSvc("whsec_" + testWebhookKey)
+		old := strconv.FormatInt(now.Add(-10*time.Minute).Unix(), 10)
+		assert.Error(t, svc.VerifyResendSignature("msg_1", old, signSvix(t, "msg_1", old, body), body))
+	})
+
+	t.Run("no secret configured fails closed", func(t *testing.T) {
+		svc := newSvc("")
+		assert.Error(t, svc.VerifyResendSignature("msg_1", ts, signSvix(t, "msg_1", ts, body), body))
+	})
+}
+
+func TestEmailDeliveryService_HandleResendEvent(t *testing.T) {
+	t.Run("permanent bounce suppresses recipient", func(t *testing.T) {
+		repo := new(mocks.MockEmailDeliveryRepository)
+		svc := NewEmailDeliveryService(repo, "", zap.NewNop())
+		body := []byte(`{"type":"email.bounced","data":{"email_id":"re_1","to":["a@x.com"],"bounce":{"type":"Permanent","subType":"General","message":"no such user"}}}`)
+
+		repo.On("UpdateStatusByProviderID", mock.Anything, "resend", "re_1", models.EmailStatusBounced, mock.Anything).Return("a@x.com", nil)
+		repo.On("Suppress", mock.Anything, "a@x.com", models.EmailSuppressionBounce, mock.Anything).Return(nil)
+
+		require.NoError(t, svc.HandleResendEvent(context.Background(), body))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("transient bounce only updates status", func(t *testing.T) {
+		repo := new(mocks.MockEmailDeliveryRepository)
+		svc := NewEmailDeliveryService(repo, "", zap.NewNop())
+		body := []byte(`{"type":"email.bounced","data":{"email_id":"re_2","to":["a@x.com"],"bounce":{"type":"Transient","message":"mailbox full"}}}`)
+
+		repo.On("UpdateStatusByProviderID", mock.Anything, "resend", "re_2", models.EmailStatusBounced, mock.Anything).Return("a@x.com", nil)
+
+		require.NoError(t, svc.HandleResendEvent(context.Background(), body))
+		repo.AssertExpectations(t)
+		repo.AssertNotCalled(t, "Suppress", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("complaint suppresses logged recipient", func(t *testing.T) {
+		repo := new(mocks.MockEmailDeliveryRepository)
+		svc := NewEmailDeliveryService(repo, "", zap.NewNop())
+		body := []byte(`{"type":"email.complained","data":{"email_id":"re_3"}}`)
+
+		repo.On("UpdateStatusByProviderID", mock.Anything, "resend", "re_3", models.EmailStatusComplained, (*string)(nil)).Return("b@x.com", nil)
+		repo.On("Suppress", mock.Anything, "b@x.com", models.EmailSuppressionComplaint, (*string)(nil)).Return(nil)
+
+		require.NoError(t, svc.HandleResendEvent(context.Background(), body))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("unknown event type is ignored", func(t *testing.T) {
+		repo := new(mocks.MockEmailDeliveryRepository)
+		svc := NewEmailDeliveryService(repo, "", zap.NewNop())
+		require.NoError(t, svc.HandleResendEvent(context.Background(), []byte(`{"type":"email.opened","data":{"email_id":"re_4"}}`)))
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestEmailService_SendEmail_SkipsSuppressedAddress(t *testing.T) {
+	repo := new(mocks.MockEmailDeliveryRepository)
+	svc := NewEmailService(&config.EmailConfig{ResendAPIKey: "k"}, zap.NewNop()).WithDeliveryTracking(repo)
+
+	repo.On("GetSuppression", mock.Anything, "gone@x.com").
+		Return(&models.EmailSuppression{Email: "gone@x.com", Reason: models.EmailSuppressionBounce}, nil)
+	repo.On("LogSent", mock.Anything, mock.MatchedBy(func(e *models.SentEmail) bool {
+		return e.Status == models.EmailStatusSuppressed
+	})).Return("row-1", nil)
+
+	err := svc.sendEmail("gone@x.com", "subject", "<p>body</p>")
+	assert.ErrorIs(t, err, ErrEmailSuppressed)
+	repo.AssertExpectations(t)
+}
@@ -2,8 +2,10 @@ package services
 
 import (
 	"bytes"
+	"context"
 	_ "embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"image/jpeg"
@@ -15,9 +17,16 @@ import (
 
 	"github.com/disintegration/imaging"
 	"github.com/hamsaya/backend/config"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
 	"go.uber.org/zap"
 )
 
+// ErrEmailSuppressed is returned by the send path when the recipient is on
+// the suppression list (hard bounce / spam complaint). Callers treat it like
+// any other delivery failure; it is never retried.
+var ErrEmailSuppressed = errors.New("recipient address is suppressed")
+
 //go:embed assets/icon.jpg
 var emailIconJPG []byte
 
@@ -59,6 +68,9 @@ type EmailService struct {
 	logger     *zap.Logger
 	httpClient *http.Client
 	iconURL    string
+	// deliveryRepo, when set, logs every send to sent_emails and consults
+	// email_suppressions before handing a message to the provider.
+	deliveryRepo repositories.EmailDeliveryRepository
 }
 
 // NewEmailService creates a new email service
@@ -78,6 +90,14 @@ func NewEmailService(cfg *config.EmailConfig, logger *zap.Logger) *EmailService
 	}
 }
 
+// WithDeliveryTracking enables the sent_emails log and suppression checks.
+// Without it the service sends blind, which is what unit tests and the
+// one-shot CLI tools want.
+func (s *EmailService) WithDeliveryTracking(repo repositories.EmailDeliveryRepository) *EmailService {
+	s.deliveryRepo = repo
+	return s
+}
+
 // deriveIconURL builds the absolute URL where the email icon is served. Empty
 // string disables the icon (template skips the <img>) — preferable to
 // rendering a broken image when no public base URL is configured.
@@ -96,6 +116,10 @@ type EmailData struct {
 	Subject        string
 	VerifyURL      string
 	ResetURL       string
+	RevertURL      string
+	NewEmail       string
+	BusinessName   string
+	ClaimURL       string // business owner invite; empty when already theirs
 	Token          string
 	ExpiresIn      string
 	AppName        string
@@ -220,6 +244,98 @@ func (s *EmailService) SendPasswordChangedEmail(email, name string) error {
 	return s.sendEmail(email, data.Subject, htmlBody)
 }
 
+// SendEmailChangeCodeEmail sends the code confirming a new account address
+// to that address.
+func (s *EmailService) SendEmailChangeCodeEmail(email, name, code string) error {
+	if !s.transportConfigured() {
+		s.logger.Warn("Email transport not configured — email change code in logs (dev only)",
+			zap.String("email", email),
+			zap.String("code", code),
+		)
+	}
+	data := EmailData{
+		RecipientName:  name,
+		RecipientEmail: email,
+		Subject:        "Confirm your new email address",
+		Token:          code,
+		ExpiresIn:      "24 hours",
+		AppName:        "Hamsaya",
+		AppURL:         "https://hamsaya.com",
+		SupportEmail:   "support@hamsaya.com",
+		Year:           strconv.Itoa(time.Now().Year()),
+		IconURL:        template.URL(s.iconURL),
+	}
+
+	htmlBody, err := s.renderTemplate(emailChangeCodeEmailTemplate, data)
+	if err != nil {
+		s.logger.Error("Failed to render email change code template", zap.Error(err))
+		return fmt.Errorf("failed to render email template: %w", err)
+	}
+
+	return s.sendEmail(email, data.Subject, htmlBody)
+}
+
+// SendEmailChangeNoticeEmail tells the current address that the account is
+// moving to newEmail, with a link to undo it.
+func (s *EmailService) SendEmailChangeNoticeEmail(email, name, newEmail, revertURL string) error {
+	data := EmailData{
+		RecipientName:  name,
+		RecipientEmail: email,
+		Subject:        "Your email address is being changed",
+		NewEmail:       newEmail,
+		RevertURL:      revertURL,
+		ExpiresIn:      "7 days",
+		AppName:        "Hamsaya",
+		AppURL:         "https://hamsaya.com",
+		SupportEmail:   "support@hamsaya.com",
+		Year:           strconv.Itoa(time.Now().Year()),
+		IconURL:        template.URL(s.iconURL),
+	}
+
+	htmlBody, err := s.renderTemplate(emailChangeNoticeEmailTemplate, data)
+	if err != nil {
+		s.logger.Error("Failed to render email change notice template", zap.Error(err))
+		return fmt.Errorf("failed to render email template: %w", err)
+	}
+
+	return s.sendEmail(email, data.Subject, htmlBody)
+}
+
+// SendBusinessOwnerInviteEmail tells the owner of a business imported from
+// a directory that it is on Hamsaya. With a claimURL the owner has no account
+// yet and gets a link to claim it after signing up; without one the business
+// was added to their account.
+func (s *EmailService) SendBusinessOwnerInviteEmail(email, name, businessName, claimURL string) error {
+	if strings.TrimSpace(name) == "" {
+		name = "there"
+	}
+	subject := businessName + " is now on Hamsaya"
+	if claimURL == "" {
+		subject = businessName + " was added to your Hamsaya account"
+	}
+	data := EmailData{
+		RecipientName:  name,
+		RecipientEmail: email,
+		Subject:        subject,
+		BusinessName:   businessName,
+		ClaimURL:       claimURL,
+		ExpiresIn:      "30 days",
+		AppName:        "Hamsaya",
+		AppURL:         "https://hamsaya.com",
+		SupportEmail:   "support@hamsaya.com",
+		Year:           strconv.Itoa(time.Now().Year()),
+		IconURL:        template.URL(s.iconURL),
+	}
+
+	htmlBody, err := s.renderTemplate(businessOwnerInviteEmailTemplate, data)
+	if err != nil {
+		s.logger.Error("Failed to render business owner invite template", zap.Error(err))
+		return fmt.Errorf("failed to render email template: %w", err)
+	}
+
+	return s.sendEmail(email, data.Subject, htmlBody)
+}
+
 // summaryLine builds the plain-text subhead, e.g. "1 unread message and 3
 // unread notifications waiting for you."
 func summaryLine(unreadMessages, unreadNotifications int) string {
@@ -500,18 +616,78 @@ func (s *EmailService) SendWinbackEmail(email, name, province string, recentPost
 
 // sendEmail sends an email using Resend API (if RESEND_API_KEY set) or SMTP.
 // Returns an error if neither is configured so callers can report failure.
+// Suppressed recipients are skipped with ErrEmailSuppressed; every attempt is
+// recorded in sent_emails when delivery tracking is enabled.
 func (s *EmailService) sendEmail(to, subject, htmlBody string) error {
-	if s.cfg.ResendAPIKey != "" {
-		return s.sendEmailResend(to, subject, htmlBody)
+	if s.deliveryRepo != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		suppression, err := s.deliveryRepo.GetSuppression(ctx, to)
+		cancel()
+		if err != nil {
+			// Fail open: a suppression-lookup hiccup must not block a
+			// verification code from going out.
+			s.logger.Warn("Email suppression lookup failed", zap.String("to", to), zap.Error(err))
+		} else if suppression != nil {
+			s.logger.Info("Skipping email to suppressed address",
+				zap.String("to", to), zap.String("reason", suppression.Reason))
+			s.logDelivery(to, subject, "none", "", models.EmailStatusSuppressed, nil)
+			return ErrEmailSuppressed
+		}
+	}
+
+	var (
+		provider  string
+		messageID string
+		err       error
+	)
+	switch {
+	case s.cfg.ResendAPIKey != "":
+		provider = "resend"
+		messageID, err = s.sendEmailResend(to, subject, htmlBody)
+	case s.cfg.SMTPHost != "" && s.cfg.SMTPPort != "":
+		provider = "smtp"
+		err = s.sendEmailSMTP(to, subject, htmlBody)
+	default:
+		return fmt.Errorf("email not configured: set RESEND_API_KEY or SMTP_HOST and SMTP_PORT to send emails")
+	}
+
+	if err != nil {
+		s.logDelivery(to, subject, provider, messageID, models.EmailStatusFailed, err)
+		return err
+	}
+	s.logDelivery(to, subject, provider, messageID, models.EmailStatusSent, nil)
+	return nil
+}
+
+// logDelivery writes one sent_emails row. Best-effort: logging failures are
+// warned about but never turn a successful send into an error.
+func (s *EmailService) logDelivery(to, subject, provider, messageID string, status models.EmailDeliveryStatus, sendErr error) {
+	if s.deliveryRepo == nil {
+		return
 	}
-	if s.cfg.SMTPHost != "" && s.cfg.SMTPPort != "" {
-		return s.sendEmailSMTP(to, subject, htmlBody)
+	row := &models.SentEmail{
+		ToEmail:  to,
+		Subject:  subject,
+		Provider: provider,
+		Status:   status,
+	}
+	if messageID != "" {
+		row.ProviderMessageID = &messageID
+	}
+	if sendErr != nil {
+		msg := sendErr.Error()
+		row.Error = &msg
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if _, err := s.deliveryRepo.LogSent(ctx, row); err != nil {
+		s.logger.Warn("Failed to record sent email", zap.String("to", to), zap.Error(err))
 	}
-	return fmt.Errorf("email not configured: set RESEND_API_KEY or SMTP_HOST and SMTP_PORT to send emails")
 }
 
-// sendEmailResend sends an email via Resend API
-func (s *EmailService) sendEmailResend(to, subject, htmlBody string) error {
+// sendEmailResend sends an email via Resend API and returns the provider
+// message id, which later bounce/complaint webhooks reference.
+func (s *EmailService) sendEmailResend(to, subject, htmlBody string) (string, error) {
 	from := s.cfg.From
 	if from == "" {
 		from = "Hamsaya <onboarding@resend.dev>"
@@ -525,12 +701,12 @@ func (s *EmailService) sendEmailResend(to, subject, htmlBody string) error {
 	}
 	jsonBody, err := json.Marshal(body)
 	if err != nil {
-		return fmt.Errorf("failed to marshal Resend request: %w", err)
+		return "", fmt.Errorf("failed to marshal Resend request: %w", err)
 	}
 
 	req, err := http.NewRequest(http.MethodPost, "https://api.resend.com/emails", bytes.NewReader(jsonBody))
 	if err != nil {
-		return fmt.Errorf("failed to create Resend request: %w", err)
+		return "", fmt.Errorf("failed to create Resend request: %w", err)
 	}
 	req.Header.Set("Authorization", "Bearer "+s.cfg.ResendAPIKey)
 	req.Header.Set("Content-Type", "application/json")
@@ -538,7 +714,7 @@ func (s *EmailService) sendEmailResend(to, subject, htmlBody string) error {
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
 		s.logger.Error("Resend API request failed", zap.String("to", to), zap.Error(err))
-		return fmt.Errorf("failed to send email via Resend: %w", err)
+		return "", fmt.Errorf("failed to send email via Resend: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
@@ -550,11 +726,16 @@ func (s *EmailService) sendEmailResend(to, subject, htmlBody string) error {
 			zap.Int("status", resp.StatusCode),
 			zap.String("body", errBody.String()),
 		)
-		return fmt.Errorf("resend API returned status %d: %s", resp.StatusCode, errBody.String())
+		return "", fmt.Errorf("resend API returned status %d: %s", resp.StatusCode, errBody.String())
 	}
 
-	s.logger.Info("Email sent via Resend", zap.String("to", to), zap.String("subject", subject))
-	return nil
+	var sent struct {
+		ID string `json:"id"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&sent) // id is optional; a malformed body still means 2xx/sent
+
+	s.logger.Info("Email sent via Resend", zap.String("to", to), zap.String("subject", subject), zap.String("message_id", sent.ID))
+	return sent.ID, nil
 }
 
 // sendEmailSMTP sends an email using SMTP (caller must ensure SMTP is configured).
@@ -806,3 +987,143 @@ const passwordChangedEmailTemplate = `
 </body>
 </html>
 `
+
+const emailChangeCodeEmailTemplate = `
+<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.Subject}}</title>
+    <style>
+        body { margin: 0; padding: 0; font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, 'Helvetica Neue', Arial, sans-serif; line-height: 1.6; color: #1f2937; background: #f3f4f6; }
+        .wrapper { max-width: 560px; margin: 0 auto; padding: 32px 16px; }
+        .card { background: #ffffff; border-radius: 16px; padding: 40px 32px; box-shadow: 0 4px 6px -1px rgba(0,0,0,0.1), 0 2px 4px -2px rgba(0,0,0,0.1); }
+        .brand-icon { display: block; width: 64px; height: 64px; margin: 0 0 12px 0; border-radius: 14px; }
+        .logo { font-size: 24px; font-weight: 700; color: #fc7b58; margin: 0 0 28px 0; }
+        .content { margin-bottom: 28px; }
+        .content h2 { font-size: 18px; font-weight: 600; color: #111827; margin: 0 0 16px 0; }
+        .content p { margin: 0 0 12px 0; font-size: 15px; color: #374151; }
+        .code-label { text-align: center; font-size: 13px; color: #6b7280; margin: 24px 0 8px 0; font-weight: 500; }
+        .code-box { background: linear-gradient(135deg, #fff7ed 0%, #ffedd5 100%); border: 2px solid #fc7b58; border-radius: 12px; padding: 20px 24px; text-align: center; margin: 0 0 20px 0; }
+        .code-box .code { font-size: 32px; font-weight: 700; letter-spacing: 10px; color: #c2410c; font-family: 'SF Mono', Monaco, 'Courier New', monospace; }
+        .expiry { font-size: 14px; color: #6b7280; margin: 16px 0 0 0; }
+        .footer { text-align: center; padding-top: 24px; border-top: 1px solid #e5e7eb; font-size: 13px; color: #9ca3af; }
+        .footer a { color: #fc7b58; text-decoration: none; }
+    </style>
+</head>
+<body>
+    <div class="wrapper">
+        <div class="card">
+            <div class="content">
+                {{if .IconURL}}<img class="brand-icon" src="{{.IconURL}}" alt="{{.AppName}}" width="64" height="64">{{end}}
+                <p class="logo">{{.AppName}}</p>
+                <h2>Hi {{.RecipientName}},</h2>
+                <p>You asked to use this address for your {{.AppName}} account. Enter the code below in the app to confirm it. You'll be signed out of all devices once the change is done.</p>
+                <p class="code-label">Your confirmation code</p>
+                <div class="code-box"><span class="code">{{.Token}}</span></div>
+                <p class="expiry"><strong>This code expires in {{.ExpiresIn}}.</strong></p>
+                <p style="margin-top: 20px; font-size: 14px; color: #6b7280;">If you didn't ask for this, you can safely ignore this email.</p>
+            </div>
+            <div class="footer">
+                <p>Need help? <a href="mailto:{{.SupportEmail}}">Contact us</a></p>
+                <p>&copy; {{.Year}} {{.AppName}}. All rights reserved.</p>
+            </div>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+const emailChangeNoticeEmailTemplate = `
+<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.Subject}}</title>
+    <style>
+        body { margin: 0; padding: 0; font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, 'Helvetica Neue', Arial, sans-serif; line-height: 1.6; color: #1f2937; background: #f3f4f6; }
+        .wrapper { max-width: 560px; margin: 0 auto; padding: 32px 16px; }
+        .card { background: #ffffff; border-radius: 16px; padding: 40px 32px; box-shadow: 0 4px 6px -1px rgba(0,0,0,0.1), 0 2px 4px -2px rgba(0,0,0,0.1); }
+        .brand-icon { display: block; width: 64px; height: 64px; margin: 0 0 12px 0; border-radius: 14px; }
+        .logo { font-size: 24px; font-weight: 700; color: #fc7b58; margin: 0 0 28px 0; }
+        .content { margin-bottom: 28px; }
+        .content h2 { font-size: 18px; font-weight: 600; color: #111827; margin: 0 0 16px 0; }
+        .content p { margin: 0 0 12px 0; font-size: 15px; color: #374151; }
+        .warning { background: #fef2f2; border-left: 4px solid #dc2626; padding: 16px 20px; margin: 20px 0 0 0; border-radius: 0 10px 10px 0; font-size: 14px; color: #991b1b; }
+        .cta { text-align: center; margin: 24px 0 0 0; }
+        .cta a { display: inline-block; padding: 14px 28px; background: #dc2626; color: #ffffff !important; text-decoration: none; border-radius: 10px; font-weight: 600; font-size: 16px; }
+        .footer { text-align: center; padding-top: 24px; border-top: 1px solid #e5e7eb; font-size: 13px; color: #9ca3af; }
+        .footer a { color: #fc7b58; text-decoration: none; }
+    </style>
+</head>
+<body>
+    <div class="wrapper">
+        <div class="card">
+            <div class="content">
+                {{if .IconURL}}<img class="brand-icon" src="{{.IconURL}}" alt="{{.AppName}}" width="64" height="64">{{end}}
+                <p class="logo">{{.AppName}}</p>
+                <h2>Hi {{.RecipientName}},</h2>
+                <p>Someone asked to change the email address of your {{.AppName}} account to <strong>{{.NewEmail}}</strong>. The change only happens once that address is confirmed. If you made this request, you don't need to do anything.</p>
+                <div class="warning"><strong>Didn't ask for this?</strong><br>Use the link below to cancel the change, or to undo it if it already went through. It works for {{.ExpiresIn}}. Then change your password.</div>
+                <div class="cta"><a href="{{.RevertURL}}">Keep {{.RecipientEmail}}</a></div>
+            </div>
+            <div class="footer">
+                <p>Need help? <a href="mailto:{{.SupportEmail}}">Contact us</a></p>
+                <p>&copy; {{.Year}} {{.AppName}}. All rights reserved.</p>
+            </div>
+        </div>
+    </div>
+</body>
+</html>
+`
+
+const businessOwnerInviteEmailTemplate = `
+<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>{{.Subject}}</title>
+    <style>
+        body { margin: 0; padding: 0; font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, 'Helvetica Neue', Arial, sans-serif; line-height: 1.6; color: #1f2937; background: #f3f4f6; }
+        .wrapper { max-width: 560px; margin: 0 auto; padding: 32px 16px; }
+        .card { background: #ffffff; border-radius: 16px; padding: 40px 32px; box-shadow: 0 4px 6px -1px rgba(0,0,0,0.1), 0 2px 4px -2px rgba(0,0,0,0.1); }
+        .brand-icon { display: block; width: 64px; height: 64px; margin: 0 0 12px 0; border-radius: 14px; }
+        .logo { font-size: 24px; font-weight: 700; color: #fc7b58; margin: 0 0 28px 0; }
+        .content { margin-bottom: 28px; }
+        .content h2 { font-size: 18px; font-weight: 600; color: #111827; margin: 0 0 16px 0; }
+        .content p { margin: 0 0 12px 0; font-size: 15px; color: #374151; }
+        .cta { text-align: center; margin: 24px 0; }
+        .cta a { display: inline-block; padding: 14px 28px; background: #fc7b58; color: #ffffff !important; text-decoration: none; border-radius: 10px; font-weight: 600; font-size: 16px; }
+        .footer { text-align: center; padding-top: 24px; border-top: 1px solid #e5e7eb; font-size: 13px; color: #9ca3af; }
+        .footer a { color: #fc7b58; text-decoration: none; }
+    </style>
+</head>
+<body>
+    <div class="wrapper">
+        <div class="card">
+            <div class="content">
+                {{if .IconURL}}<img class="brand-icon" src="{{.IconURL}}" alt="{{.AppName}}" width="64" height="64">{{end}}
+                <p class="logo">{{.AppName}}</p>
+                <h2>Hi {{.RecipientName}},</h2>
+                {{if .ClaimURL}}
+                <p><strong>{{.BusinessName}}</strong> was listed on {{.AppName}} from your local business directory, so neighbors can find it, follow it and message you.</p>
+                <p>Sign up with this email address ({{.RecipientEmail}}), then use the link below to take over the business page. It works for {{.ExpiresIn}}.</p>
+                <div class="cta"><a href="{{.ClaimURL}}">Claim {{.BusinessName}}</a></div>
+                {{else}}
+                <p><strong>{{.BusinessName}}</strong> was added to your {{.AppName}} account from your local business directory. Check its details, hours and photos so neighbors see it at its best.</p>
+                <div class="cta"><a href="{{.AppURL}}">Open {{.AppName}}</a></div>
+                {{end}}
+                <p>Not your business? Let us know at <a href="mailto:{{.SupportEmail}}" style="color: #fc7b58;">{{.SupportEmail}}</a>.</p>
+            </div>
+            <div class="footer">
+                <p>Need help? <a href="mailto:{{.SupportEmail}}">Contact us</a></p>
+                <p>&copy; {{.Year}} {{.AppName}}. All rights reserved.</p>
+            </div>
+        </div>
+    </div>
+</body>
+</html>
+`
@@ -50,8 +50,9 @@ func TestEmailService_SendEmailResend_Success(t *testing.T) {
 		Transport: &rewriteTransport{target: ts.URL},
 	}
 
-	err := svc.sendEmailResend("to@example.com", "Test Subject", "<p>hello</p>")
+	id, err := svc.sendEmailResend("to@example.com", "Test Subject", "<p>hello</p>")
 	require.NoError(t, err)
+	assert.Equal(t, "msg-1", id)
 }
 
 func TestEmailService_SendEmailResend_Error(t *testing.T) {
@@ -67,7 +68,7 @@ func TestEmailService_SendEmailResend_Error(t *testing.T) {
 		Transport: &rewriteTransport{target: ts.URL},
 	}
 
-	err := svc.sendEmailResend("to@example.com", "subject", "<p>body</p>")
+	_, err := svc.sendEmailResend("to@example.com", "subject", "<p>body</p>")
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "resend API returned status 401")
 }
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// EndorsementService handles recommendations users leave on each other's
+// profiles. New and edited endorsements wait for the profile owner's
+// approval; only approved ones are public. The author or the owner can
+// remove an endorsement, and anyone signed in can report one.
+type EndorsementService struct {
+	endorsementRepo     repositories.EndorsementRepository
+	userRepo            repositories.UserRepository
+	relationshipsRepo   repositories.RelationshipsRepository
+	notificationService *NotificationService
+	logger              *zap.Logger
+}
+
+// NewEndorsementService wires the endorsement service.
+func NewEndorsementService(
+	endorsementRepo repositories.EndorsementRepository,
+	userRepo repositories.UserRepository,
+	relationshipsRepo repositories.RelationshipsRepository,
+	notificationService *NotificationService,
+	logger *zap.Logger,
+) *EndorsementService {
+	return &EndorsementService{
+		endorsementRepo:     endorsementRepo,
+		userRepo:            userRepo,
+		relationshipsRepo:   relationshipsRepo,
+		notificationService: notificationService,
+		logger:              logger,
+	}
+}
+
+// Endorse creates or replaces authorID's endorsement of subjectID. Either
+// side blocking the other rejects it.
+func (s *EndorsementService) Endorse(ctx context.Context, subjectID, authorID string, req *models.CreateEndorsementRequest) (*models.Endorsement, error) {
+	if subjectID == authorID {
+		return nil, utils.NewBadRequestError("You cannot endorse yourself", nil)
+	}
+	if _, err := s.userRepo.GetByID(ctx, subjectID); err != nil {
+		return nil, utils.NewNotFoundError("User not found", err)
+	}
+	if blocked, err := s.eitherBlocked(ctx, subjectID, authorID); err != nil {
+		return nil, utils.NewInternalError("Failed to check block status", err)
+	} else if blocked {
+		return nil, utils.NewForbiddenError("You cannot endorse this user", nil)
+	}
+
+	endorsement := &models.Endorsement{
+		ID:        uuid.NewString(),
+		SubjectID: subjectID,
+		AuthorID:  authorID,
+		Text:      strings.TrimSpace(req.Text),
+	}
+	if err := s.endorsementRepo.Upsert(ctx, endorsement); err != nil {
+		s.logger.Error("Failed to upsert endorsement", zap.Error(err))
+		return nil, utils.NewInternalError("Failed to submit endorsement", err)
+	}
+
+	if s.notificationService != nil {
+		go s.notifySubject(endorsement)
+	}
+	return endorsement, nil
+}
+
+// List returns a user's endorsements. Others only see approved ones; the
+// owner sees everything and may filter by status (e.g. PENDING to review).
+func (s *EndorsementService) List(ctx context.Context, subjectID string, viewerID *string, status string, limit, offset int) ([]*models.EndorsementWithAuthor, int, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	approved := models.EndorsementApproved
+	filter := &approved
+	if viewerID != nil && *viewerID == subjectID {
+		switch st := models.EndorsementStatus(strings.ToUpper(status)); st {
+		case "":
+			filter = nil
+		case models.EndorsementPending, models.EndorsementApproved, models.EndorsementRejected:
+			filter = &st
+		default:
+			return nil, 0, utils.NewBadRequestError("Invalid status filter", nil)
+		}
+	}
+
+	endorsements, total, err := s.endorsementRepo.ListForSubject(ctx, subjectID, filter, limit, offset)
+	if err != nil {
+		return nil, 0, utils.NewInternalError("Failed to load endorsements", err)
+	}
+	return endorsements, total, nil
+}
+
+// Decide approves or rejects an endorsement on the owner's profile.
+func (s *EndorsementService) Decide(ctx context.Context, endorsementID, ownerID string, req *models.DecideEndorsementRequest) (*models.Endorsement, error) {
+	endorsement, err := s.get(ctx, endorsementID)
+	if err != nil {
+		return nil, err
+	}
+	if endorsement.SubjectID != ownerID {
+		return nil, utils.NewForbiddenError("Only the profile owner can approve endorsements", nil)
+	}
+	if err := s.endorsementRepo.SetStatus(ctx, endorsementID, req.Status); err != nil {
+		if errors.Is(err, repositories.ErrEndorsementNotFound) {
+			return nil, utils.NewNotFoundError("Endorsement not found", err)
+		}
+		return nil, utils.NewInternalError("Failed to update endorsement", err)
+	}
+	endorsement.Status = req.Status
+	return endorsement, nil
+}
+
+// Delete removes an endorsement. Its author and the endorsed user may
+// remove it.
+func (s *EndorsementService) Delete(ctx context.Context, endorsementID, userID string) error {
+	endorsement, err := s.get(ctx, endorsementID)
+	if err != nil {
+		return err
+	}
+	if endorsement.AuthorID != userID && endorsement.SubjectID != userID {
+		return utils.NewForbiddenError("You cannot remove this endorsement", nil)
+	}
+	return s.AdminDelete(ctx, endorsementID)
+}
+
+// Report files a report against an endorsement. Only visible endorsements
+// can be reported: approved ones, or any on the reporter's own profile.
+func (s *EndorsementService) Report(ctx context.Context, endorsementID, userID string, req *models.ReportEndorsementRequest) error {
+	endorsement, err := s.get(ctx, endorsementID)
+	if err != nil {
+		return err
+	}
+	if endorsement.Status != models.EndorsementApproved && endorsement.SubjectID != userID {
+		return utils.NewNotFoundError("Endorsement not found", nil)
+	}
+	if endorsement.AuthorID == userID {
+		return utils.NewBadRequestError("Cannot report your own endorsement", nil)
+	}
+
+	report := &models.EndorsementReport{
+		ID:                 uuid.NewString(),
+		UserID:             userID,
+		EndorsementID:      endorsementID,
+		Reason:             req.Reason,
+		AdditionalComments: req.AdditionalComments,
+	}
+	if err := s.endorsementRepo.CreateReport(ctx, report); err != nil {
+		if errors.Is(err, repositories.ErrAlreadyReported) {
+			return utils.NewConflictError("You have already reported this endorsement", err)
+		}
+		s.logger.Error("Failed to create endorsement report", zap.Error(err))
+		return utils.NewInternalError("Failed to create report", err)
+	}
+	return nil
+}
+
+// ListReported returns reported endorsements for admin review.
+func (s *EndorsementService) ListReported(ctx context.Context, limit, offset int) ([]*models.ReportedEndorsement, int, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	reported, total, err := s.endorsementRepo.ListReported(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, utils.NewInternalError("Failed to load reported endorsements", err)
+	}
+	return reported, total, nil
+}
+
+// AdminDelete removes an endorsement regardless of authorship.
+func (s *EndorsementService) AdminDelete(ctx context.Context, endorsementID string) error {
+	if err := s.endorsementRepo.Delete(ctx, endorsementID); err != nil {
+		if errors.Is(err, repositories.ErrEndorsementNotFound) {
+			return utils.NewNotFoundError("Endorsement not found", err)
+		}
+		return utils.NewInternalError("Failed to delete endorsement", err)
+	}
+	return nil
+}
+
+func (s *EndorsementService) get(ctx context.Context, endorsementID string) (*models.Endorsement, error) {
+	endorsement, err := s.endorsementRepo.GetByID(ctx, endorsementID)
+	if errors.Is(err, repositories.ErrEndorsementNotFound) {
+		return nil, utils.NewNotFoundError("Endorsement not found", err)
+	}
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load endorsement", err)
+	}
+	return endorsement, nil
+}
+
+func (s *EndorsementService) eitherBlocked(ctx context.Context, a, b string) (bool, error) {
+	blocked, err := s.relationshipsRepo.IsBlocked(ctx, a, b)
+	if err != nil || blocked {
+		return blocked, err
+	}
+	return s.relationshipsRepo.IsBlocked(ctx, b, a)
+}
+
+func (s *EndorsementService) notifySubject(endorsement *models.Endorsement) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Warn("notifySubject panicked", zap.Any("recover", r))
+		}
+	}()
+
+	ctx := context.Background()
+	authorName := ""
+	actorAvatar := ""
+	actorAvatarColor := ""
+	if actor, err := s.userRepo.GetProfileByUserID(ctx, endorsement.AuthorID); err == nil && actor != nil {
+		authorName = actor.FullName()
+		if actor.Avatar != nil && actor.Avatar.URL != "" {
+			actorAvatar = actor.Avatar.URL
+		}
+		if actor.AvatarColor != nil && *actor.AvatarColor != "" {
+			actorAvatarColor = *actor.AvatarColor
+		}
+	}
+	title := strings.TrimSpace(authorName + " recommended you")
+	msg := endorsement.Text
+	// Rune-safe truncation — endorsements are often Dari/Pashto (multibyte).
+	if r := []rune(msg); len(r) > 100 {
+		msg = string(r[:100]) + "…"
+	}
+	data := map[string]interface{}{
+		"actor_id":           endorsement.AuthorID,
+		"actor_name":         authorName,
+		"actor_avatar":       actorAvatar,
+		"actor_avatar_color": actorAvatarColor,
+		"endorsement_id":     endorsement.ID,
+	}
+	if _, err := s.notificationService.CreateNotification(ctx, &models.CreateNotificationRequest{
+		UserID:  endorsement.SubjectID,
+		Type:    models.NotificationTypeEndorsement,
+		Title:   &title,
+		Message: &msg,
+		Data:    data,
+	}); err != nil {
+		s.logger.Warn("Failed to notify user of endorsement", zap.Error(err))
+	}
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestEndorsementService(repo *mocks.MockEndorsementRepository, userRepo *mocks.MockUserRepository, relRepo *mocks.MockRelationshipsRepository) *EndorsementService {
+	return NewEndorsementService(repo, userRepo, relRepo, nil, zap.NewNop())
+}
+
+func assertAppErrorCode(t *testing.T, err error, code int) {
+	t.Helper()
+	appErr, ok := err.(*utils.AppError)
+	require.True(t, ok, "expected *utils.AppError, got %T", err)
+	assert.Equal(t, code, appErr.Code)
+}
+
+func TestEndorsementService_Endorse(t *testing.T) {
+	req := &models.CreateEndorsementRequest{Text: "  Fixed our roof quickly and fairly.  "}
+
+	t.Run("stores a pending endorsement", func(t *testing.T) {
+		repo := new(mocks.MockEndorsementRepository)
+		userRepo := new(mocks.MockUserRepository)
+		relRepo := new(mocks.MockRelationshipsRepository)
+		userRepo.On("GetByID", mock.Anything, "subject").Return(testutil.CreateTestUser("subject", "s@example.com"), nil)
+		relRepo.On("IsBlocked", mock.Anything, "subject", "author").Return(false, nil)
+		relRepo.On("IsBlocked", mock.Anything, "author", "subject").Return(false, nil)
+		repo.On("Upsert", mock.Anything, mock.MatchedBy(func(e *models.Endorsement) bool {
+			return e.SubjectID == "subject" && e.AuthorID == "author" && e.Text == "Fixed our roof quickly and fairly."
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.Endorsement).Status = models.EndorsementPending
+		}).Return(nil)
+
+		got, err := newTestEndorsementService(repo, userRepo, relRepo).Endorse(context.Background(), "subject", "author", req)
+		require.NoError(t, err)
+		assert.Equal(t, models.EndorsementPending, got.Status)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rejects self endorsement", func(t *testing.T) {
+		repo := new(mocks.MockEndorsementRepository)
+		_, err := newTestEndorsementService(repo, new(mocks.MockUserRepository), new(mocks.MockRelationshipsRepository)).
+			Endorse(context.Background(), "author", "author", req)
+		assertAppErrorCode(t, err, http.StatusBadRequest)
+		repo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects when the subject blocked the author", func(t *testing.T) {
+		repo := new(mocks.MockEndorsementRepository)
+		userRepo := new(mocks.MockUserRepository)
+		relRepo := new(mocks.MockRelationshipsRepository)
+		userRepo.On("GetByID", mock.Anything, "subject").Return(testutil.CreateTestUser("subject", "s@example.com"), nil)
+		relRepo.On("IsBlocked", mock.Anything, "subject", "author").Return(true, nil)
+
+		_, err := newTestEndorsementService(repo, userRepo, relRepo).Endorse(context.Background(), "subject", "author", req)
+		assertAppErrorCode(t, err, http.StatusForbidden)
+		repo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+	})
+}
+
+func TestEndorsementService_List(t *testing.T) {
+	approved := models.EndorsementApproved
+	pending := models.EndorsementPending
+	owner := "owner"
+	other := "other"
+
+	t.Run("others only see approved", func(t *testing.T) {
+		repo := new(mocks.MockEndorsementRepository)
+		repo.On("ListForSubject", mock.Anything, owner, &approved, 20, 0).
+			Return([]*models.EndorsementWithAuthor{}, 0, nil)
+
+		_, _, err := newTestEndorsementService(repo, nil, nil).List(context.Background(), owner, &other, "PENDING", 0, 0)
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("owner can filter pending", func(t *testing.T) {
+		repo := new(mocks.MockEndorsementRepository)
+		repo.On("ListForSubject", mock.Anything, owner, &pending, 20, 0).
+			Return([]*models.EndorsementWithAuthor{}, 0, nil)
+
+		_, _, err := newTestEndorsementService(repo, nil, nil).List(context.Background(), owner, &owner, "pending", 20, 0)
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("owner sees every status by default", func(t *testing.T) {
+		repo := new(mocks.MockEndorsementRepository)
+		repo.On("ListForSubject", mock.Anything, owner, (*models.EndorsementStatus)(nil), 20, 0).
+			Return([]*models.EndorsementWithAuthor{}, 0, nil)
+
+		_, _, err := newTestEndorsementService(repo, nil, nil).List(context.Background(), owner, &owner, "", 20, 0)
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestEndorsementService_DecideAndDelete(t *testing.T) {
+	endorsement := &models.Endorsement{ID: "e-1", SubjectID: "owner", AuthorID: "author", Status: models.EndorsementPending}
+
+	t.Run("only the owner decides", func(t *testing.T) {
+		repo := new(mocks.MockEndorsementRepository)
+		repo.On("GetByID", mock.Anything, "e-1").Return(endorsement, nil)
+
+		_, err := newTestEndorsementService(repo, nil, nil).Decide(context.Background(), "e-1", "author",
+			&models.DecideEndorsementRequest{Status: models.EndorsementApproved})
+		assertAppErrorCode(t, err, http.StatusForbidden)
+		repo.AssertNotCalled(t, "SetStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("owner approves", func(t *testing.T) {
+		repo := new(mocks.MockEndorsementRepository)
+		repo.On("GetByID", mock.Anything, "e-1").Return(&models.Endorsement{ID: "e-1", SubjectID: "owner", AuthorID: "author"}, nil)
+		repo.On("SetStatus", mock.Anything, "e-1", models.EndorsementApproved).Return(nil)
+
+		got, err := newTestEndorsementService(repo, nil, nil).Decide(context.Background(), "e-1", "owner",
+			&models.DecideEndorsementRequest{Status: models.EndorsementApproved})
+		require.NoError(t, err)
+		assert.Equal(t, models.EndorsementApproved, got.Status)
+	})
+
+	t.Run("author and owner may delete, others may not", func(t *testing.T) {
+		repo := new(mocks.MockEndorsementRepository)
+		repo.On("GetByID", mock.Anything, "e-1").Return(endorsement, nil)
+		repo.On("Delete", mock.Anything, "e-1").Return(nil).Twice()
+		svc := newTestEndorsementService(repo, nil, nil)
+
+		require.NoError(t, svc.Delete(context.Background(), "e-1", "author"))
+		require.NoError(t, svc.Delete(context.Background(), "e-1", "owner"))
+		assertAppErrorCode(t, svc.Delete(context.Background(), "e-1", "stranger"), http.StatusForbidden)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestEndorsementService_Report(t *testing.T) {
+	req := &models.ReportEndorsementRequest{Reason: "spam"}
+
+	t.Run("pending endorsements are hidden from others", func(t *testing.T) {
+		repo := new(mocks.MockEndorsementRepository)
+		repo.On("GetByID", mock.Anything, "e-1").
+			Return(&models.Endorsement{ID: "e-1", SubjectID: "owner", AuthorID: "author", Status: models.EndorsementPending}, nil)
+
+		err := newTestEndorsementService(repo, nil, nil).Report(context.Background(), "e-1", "stranger", req)
+		assertAppErrorCode(t, err, http.StatusNotFound)
+	})
+
+	t.Run("duplicate report is a conflict", func(t *testing.T) {
+		repo := new(mocks.MockEndorsementRepository)
+		repo.On("GetByID", mock.Anything, "e-1").
+			Return(&models.Endorsement{ID: "e-1", SubjectID: "owner", AuthorID: "author", Status: models.EndorsementApproved}, nil)
+		repo.On("CreateReport", mock.Anything, mock.AnythingOfType("*models.EndorsementReport")).
+			Return(repositories.ErrAlreadyReported)
+
+		err := newTestEndorsementService(repo, nil, nil).Report(context.Background(), "e-1", "stranger", req)
+		assertAppErrorCode(t, err, http.StatusConflict)
+	})
+}
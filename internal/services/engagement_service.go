@@ -32,6 +32,11 @@ type EngagementService struct {
 	// reminder job (needs to mint + store a fresh verification code).
 	jwt        *JWTService
 	tokenStore *TokenStorageService
+
+	// Optional — set via WithLifecycleMessages for admin-edited copy and
+	// timing of the first-post nudge and win-back; built-in defaults
+	// otherwise.
+	lifecycle *LifecycleMessageService
 }
 
 // _maxVerifyReminders caps lifetime verification-reminder emails per user, so
@@ -62,6 +67,22 @@ func (s *EngagementService) WithVerification(tokenStore *TokenStorageService, jw
 	return s
 }
 
+// WithLifecycleMessages sets the copy and timing of the first-post nudge and
+// the win-back push.
+func (s *EngagementService) WithLifecycleMessages(lifecycle *LifecycleMessageService) *EngagementService {
+	s.lifecycle = lifecycle
+	return s
+}
+
+// lifecycleOptOut excludes users (aliased u) who turned the TIPS
+// notification category off; lifecycle messages skip them entirely.
+const lifecycleOptOut = `NOT EXISTS (
+			SELECT 1 FROM notification_settings ns
+			WHERE ns.profile_id = u.id
+			  AND ns.category = 'TIPS'
+			  AND ns.push_pref = false
+		  )`
+
 // RunHourly runs every re-engagement job once. Intended to be invoked hourly.
 // Each job logs and swallows its own errors so one failure doesn't block the
 // others; the returned error is always nil to keep scheduler callers simple.
@@ -93,18 +114,23 @@ func (s *EngagementService) RunHourly(ctx context.Context) error {
 // simply prefers to lurk isn't nagged forever.
 const _maxFirstPostNudges = 3
 
-// sendFirstPostNudge pushes an in-app + push nudge to users who have never
-// created a post, encouraging them to share their first one. Targets verified
-// accounts aged 2–7 days (past the initial signup rush, before they go cold),
-// with zero non-deleted posts. Deduped to once per 3 days and capped at
-// _maxFirstPostNudges total — both enforced in SQL against the notifications
-// table (same approach as winback), so it's safe to run hourly.
+// _firstPostNudgeWindow is how long after the nudge threshold a newcomer
+// stays eligible, so accounts that went cold long ago aren't nudged.
+const _firstPostNudgeWindow = 5 * 24 * time.Hour
+
+// sendFirstPostNudge pushes an in-app + push nudge to newcomers who have
+// neither posted nor followed anyone, encouraging them to join in. Targets
+// verified accounts between lifecycle.nudge_after (2 days by default) and
+// _firstPostNudgeWindow past it, that haven't opted out of TIPS. Deduped to
+// once per 3 days and capped at _maxFirstPostNudges total — both enforced in
+// SQL against the notifications table (same approach as winback), so it's
+// safe to run hourly.
 func (s *EngagementService) sendFirstPostNudge(ctx context.Context) int {
 	if s.notif == nil {
 		return 0
 	}
 
-	const query = `
+	query := `
 		SELECT u.id,
 		       COALESCE(NULLIF(TRIM(pr.first_name), ''), '') AS first_name,
 		       COALESCE(NULLIF(TRIM(pr.province), ''), '')   AS province
@@ -112,10 +138,14 @@ func (s *EngagementService) sendFirstPostNudge(ctx context.Context) int {
 		JOIN profiles pr ON pr.id = u.id
 		WHERE u.deleted_at IS NULL
 		  AND u.email_verified = true
-		  AND u.created_at BETWEEN NOW() - INTERVAL '7 days' AND NOW() - INTERVAL '2 days'
+		  AND u.created_at BETWEEN $2 AND $3
 		  AND NOT EXISTS (
 			SELECT 1 FROM posts p WHERE p.user_id = u.id AND p.deleted_at IS NULL
 		  )
+		  AND NOT EXISTS (
+			SELECT 1 FROM user_follows f WHERE f.follower_id = u.id
+		  )
+		  AND ` + lifecycleOptOut + `
 		  AND (
 			SELECT COUNT(*) FROM notifications n
 			WHERE n.user_id = u.id AND n.type = 'FIRST_POST_NUDGE'
@@ -130,7 +160,8 @@ func (s *EngagementService) sendFirstPostNudge(ctx context.Context) int {
 		LIMIT 500
 	`
 
-	rows, err := s.db.Pool.Query(ctx, query, _maxFirstPostNudges)
+	newest := time.Now().Add(-s.lifecycle.NudgeAfter())
+	rows, err := s.db.Pool.Query(ctx, query, _maxFirstPostNudges, newest.Add(-_firstPostNudgeWindow), newest)
 	if err != nil {
 		s.logger.Error("first-post nudge query failed", zap.Error(err))
 		return 0
@@ -150,13 +181,10 @@ func (s *EngagementService) sendFirstPostNudge(ctx context.Context) int {
 
 	sent := 0
 	for _, t := range targets {
-		title := "Share your first post"
-		if t.firstName != "" {
-			title = fmt.Sprintf("%s, share your first post", t.firstName)
-		}
-		msg := "Your neighbors are waiting — post something and start the conversation."
-		if t.province != "" {
-			msg = fmt.Sprintf("See what's happening in %s — share your first post with your neighbors.", t.province)
+		title, msg, ok := s.lifecycle.Render(ctx, models.NotificationTypeFirstPostNudge,
+			LifecycleVars{FirstName: t.firstName, Province: t.province})
+		if !ok {
+			return sent // switched off by an admin
 		}
 
 		if _, err := s.notif.CreateNotification(ctx, &models.CreateNotificationRequest{
@@ -594,7 +622,9 @@ func (s *EngagementService) sendEventReminders(ctx context.Context) int {
 	return total
 }
 
-// sendWinback nudges users inactive for 14+ days with a localized message.
+// sendWinback nudges users who haven't logged in for lifecycle.reengage_after
+// (14 days by default) with a localized message, unless they opted out of
+// TIPS.
 func (s *EngagementService) sendWinback(ctx context.Context) int {
 	rows, err := s.db.Pool.Query(ctx, `
 		SELECT u.id, COALESCE(NULLIF(TRIM(pr.first_name), ''), '') AS first_name,
@@ -603,7 +633,8 @@ func (s *EngagementService) sendWinback(ctx context.Context) int {
 		LEFT JOIN profiles pr ON pr.id = u.id
 		WHERE u.deleted_at IS NULL
 		  AND u.email_verified = true
-		  AND (u.last_login_at IS NULL OR u.last_login_at < NOW() - INTERVAL '14 days')
+		  AND (u.last_login_at IS NULL OR u.last_login_at < $1)
+		  AND `+lifecycleOptOut+`
 		  AND NOT EXISTS (
 			SELECT 1 FROM notifications n
 			WHERE n.user_id = u.id
@@ -612,7 +643,7 @@ func (s *EngagementService) sendWinback(ctx context.Context) int {
 		  )
 		ORDER BY u.last_login_at ASC NULLS FIRST
 		LIMIT 2000
-	`)
+	`, time.Now().Add(-s.lifecycle.ReengageAfter()))
 	if err != nil {
 		s.logger.Error("winback query failed", zap.Error(err))
 		return 0
@@ -653,18 +684,10 @@ func (s *EngagementService) sendWinback(ctx context.Context) int {
 			`, t.province).Scan(&newestPostID, &newestPostType)
 		}
 
-		title := "Your neighborhood missed you"
-		if t.firstName != "" {
-			title = fmt.Sprintf("%s, your neighborhood missed you", t.firstName)
-		}
-		var msg string
-		switch {
-		case recent > 0 && t.province != "":
-			msg = fmt.Sprintf("%d new posts in %s this week. Come see what's happening.", recent, t.province)
-		case t.province != "":
-			msg = fmt.Sprintf("See what's new in %s on Hamsaya.", t.province)
-		default:
-			msg = "See what's new in your neighborhood on Hamsaya."
+		title, msg, ok := s.lifecycle.Render(ctx, models.NotificationTypeWinback,
+			LifecycleVars{FirstName: t.firstName, Province: t.province, NewPosts: recent})
+		if !ok {
+			return total // switched off by an admin
 		}
 
 		data := map[string]interface{}{
@@ -695,8 +718,8 @@ func (s *EngagementService) sendWinback(ctx context.Context) int {
 }
 
 // sendSellExpiring nudges sellers ~48h before an active, unsold listing expires
-// so they can repost/renew it — keeps the marketplace fresh and brings sellers
-// back. Deduped per post within 3 days.
+// so they renew it (POST /posts/:id/renew) — keeps the marketplace fresh and
+// brings sellers back. Deduped per post within 3 days.
 func (s *EngagementService) sendSellExpiring(ctx context.Context) int {
 	rows, err := s.db.Pool.Query(ctx, `
 		SELECT p.user_id, p.id, COALESCE(NULLIF(TRIM(p.title), ''), 'Your listing') AS title
@@ -736,7 +759,7 @@ func (s *EngagementService) sendSellExpiring(ctx context.Context) int {
 	total := 0
 	for _, t := range targets {
 		title := "Listing expiring soon"
-		msg := fmt.Sprintf("\"%s\" expires in 2 days. Repost it to keep it visible.", t.title)
+		msg := fmt.Sprintf("\"%s\" expires in 2 days. Renew it to keep it visible.", t.title)
 		if _, err := s.notif.CreateNotification(ctx, &models.CreateNotificationRequest{
 			UserID:  t.userID,
 			Type:    models.NotificationTypeSellExpiring,
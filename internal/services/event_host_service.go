@@ -0,0 +1,401 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/hamsaya/backend/pkg/bgtasks"
+	"go.uber.org/zap"
+)
+
+// maxEventCoHosts caps invitations per event, pending ones included.
+const maxEventCoHosts = 10
+
+// EventHostService runs co-hosting of EVENT posts. The event's author (a
+// user, or the business it was posted as) invites users or businesses; once
+// they accept, they're listed as hosts and can edit the event and message
+// its attendees. Only the author invites or removes co-hosts.
+type EventHostService struct {
+	hostRepo            repositories.EventHostRepository
+	postRepo            repositories.PostRepository
+	userRepo            repositories.UserRepository
+	businessRepo        repositories.BusinessRepository
+	access              *AccessControl
+	notificationService *NotificationService
+	logger              *zap.Logger
+}
+
+// NewEventHostService wires the event host service.
+func NewEventHostService(
+	hostRepo repositories.EventHostRepository,
+	postRepo repositories.PostRepository,
+	userRepo repositories.UserRepository,
+	businessRepo repositories.BusinessRepository,
+	notificationService *NotificationService,
+	logger *zap.Logger,
+) *EventHostService {
+	return &EventHostService{
+		hostRepo:            hostRepo,
+		postRepo:            postRepo,
+		userRepo:            userRepo,
+		businessRepo:        businessRepo,
+		access:              NewAccessControl(businessRepo),
+		notificationService: notificationService,
+		logger:              logger,
+	}
+}
+
+func (s *EventHostService) getEventPost(ctx context.Context, postID string) (*models.Post, error) {
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, utils.NewNotFoundError("Post not found", err)
+	}
+	if post.Type != models.PostTypeEvent {
+		return nil, utils.NewBadRequestError("Co-hosts are only available on events", nil)
+	}
+	return post, nil
+}
+
+// CanManage reports whether userID may edit the event and message its
+// attendees: its author or an accepted co-host.
+func (s *EventHostService) CanManage(ctx context.Context, post *models.Post, userID string) (bool, error) {
+	if s.access.CanEditPost(ctx, post, userID) {
+		return true, nil
+	}
+	if post.Type != models.PostTypeEvent {
+		return false, nil
+	}
+	return s.hostRepo.IsCoHost(ctx, post.ID, userID)
+}
+
+// isInvitee reports whether userID answers for the co-host: the invited user
+// or the owner of the invited business.
+func (s *EventHostService) isInvitee(ctx context.Context, host *models.EventHost, userID string) bool {
+	if host.UserID != nil {
+		return *host.UserID == userID
+	}
+	if host.BusinessID != nil {
+		business, err := s.businessRepo.GetByID(ctx, *host.BusinessID)
+		return err == nil && s.access.CanManageBusiness(business, userID)
+	}
+	return false
+}
+
+// inviteeRecipient returns the user to notify about an invitation.
+func (s *EventHostService) inviteeRecipient(ctx context.Context, host *models.EventHost) string {
+	if host.UserID != nil {
+		return *host.UserID
+	}
+	if host.BusinessID != nil {
+		if business, err := s.businessRepo.GetByID(ctx, *host.BusinessID); err == nil {
+			return business.UserID
+		}
+	}
+	return ""
+}
+
+// Invite asks a user or a business to co-host the event (author only).
+func (s *EventHostService) Invite(ctx context.Context, postID, inviterID string, req *models.InviteEventHostRequest) (*models.EventHost, error) {
+	post, err := s.getEventPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.access.CanEditPost(ctx, post, inviterID) {
+		return nil, utils.NewForbiddenError("Only the event's author can invite co-hosts", nil)
+	}
+
+	hasUser := req.UserID != nil && *req.UserID != ""
+	hasBusiness := req.BusinessID != nil && *req.BusinessID != ""
+	if hasUser == hasBusiness {
+		return nil, utils.NewBadRequestError("Invite either a user or a business", nil)
+	}
+
+	host := &models.EventHost{
+		ID:        uuid.NewString(),
+		PostID:    postID,
+		Role:      models.EventHostRoleCoHost,
+		Status:    models.EventHostPending,
+		InvitedBy: inviterID,
+	}
+	if hasUser {
+		if isPostOwner(post, *req.UserID) {
+			return nil, utils.NewBadRequestError("The author already hosts this event", nil)
+		}
+		profile, err := s.userRepo.GetProfileByUserID(ctx, *req.UserID)
+		if err != nil {
+			return nil, utils.NewNotFoundError("User not found", err)
+		}
+		host.UserID = req.UserID
+		host.Name = profile.FullName()
+		host.Avatar = profile.Avatar
+	} else {
+		if post.BusinessID != nil && *post.BusinessID == *req.BusinessID {
+			return nil, utils.NewBadRequestError("The author already hosts this event", nil)
+		}
+		business, err := s.businessRepo.GetByID(ctx, *req.BusinessID)
+		if err != nil {
+			return nil, utils.NewNotFoundError("Business not found", err)
+		}
+		host.BusinessID = req.BusinessID
+		host.Name = business.Name
+		host.Avatar = business.Avatar
+	}
+
+	existing, err := s.hostRepo.ListByPostIDs(ctx, []string{postID}, true)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load co-hosts", err)
+	}
+	if len(existing[postID]) >= maxEventCoHosts {
+		return nil, utils.NewBadRequestError("This event already has the maximum number of co-hosts", nil)
+	}
+
+	if err := s.hostRepo.Invite(ctx, host); err != nil {
+		if errors.Is(err, repositories.ErrEventHostExists) {
+			return nil, utils.NewConflictError("Already invited to co-host this event", err)
+		}
+		s.logger.Error("Failed to invite event co-host", zap.String("post_id", postID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to invite co-host", err)
+	}
+
+	if recipient := s.inviteeRecipient(ctx, host); recipient != "" {
+		s.notify([]string{recipient}, inviterID, models.NotificationTypeEventHostInvite, post,
+			"invited you to co-host", nil, host)
+	}
+	return host, nil
+}
+
+// Respond accepts or declines a co-host invitation (invitee only). The
+// author hears about acceptances.
+func (s *EventHostService) Respond(ctx context.Context, postID, hostID, userID string, accept bool) (*models.EventHost, error) {
+	post, err := s.getEventPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	host, err := s.getHost(ctx, postID, hostID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.isInvitee(ctx, host, userID) {
+		return nil, utils.NewForbiddenError("This invitation isn't addressed to you", nil)
+	}
+
+	status := models.EventHostDeclined
+	if accept {
+		status = models.EventHostAccepted
+	}
+	updated, err := s.hostRepo.Respond(ctx, hostID, status)
+	if err != nil {
+		if errors.Is(err, repositories.ErrEventHostConflict) {
+			return nil, utils.NewConflictError("This invitation was already answered", err)
+		}
+		return nil, utils.NewInternalError("Failed to answer invitation", err)
+	}
+
+	if accept && post.UserID != nil {
+		s.notify([]string{*post.UserID}, userID, models.NotificationTypeEventHostAccepted, post,
+			"accepted to co-host", nil, updated)
+	}
+	return updated, nil
+}
+
+// Remove drops a co-host. The author can remove anyone; a co-host can step
+// down themselves.
+func (s *EventHostService) Remove(ctx context.Context, postID, hostID, userID string) error {
+	post, err := s.getEventPost(ctx, postID)
+	if err != nil {
+		return err
+	}
+	host, err := s.getHost(ctx, postID, hostID)
+	if err != nil {
+		return err
+	}
+	if !s.access.CanEditPost(ctx, post, userID) && !s.isInvitee(ctx, host, userID) {
+		return utils.NewForbiddenError("You can't remove this co-host", nil)
+	}
+	if err := s.hostRepo.Delete(ctx, hostID); err != nil {
+		if errors.Is(err, repositories.ErrEventHostNotFound) {
+			return utils.NewNotFoundError("Co-host not found", err)
+		}
+		return utils.NewInternalError("Failed to remove co-host", err)
+	}
+	return nil
+}
+
+func (s *EventHostService) getHost(ctx context.Context, postID, hostID string) (*models.EventHost, error) {
+	host, err := s.hostRepo.GetByID(ctx, hostID)
+	if errors.Is(err, repositories.ErrEventHostNotFound) || (err == nil && host.PostID != postID) {
+		return nil, utils.NewNotFoundError("Co-host not found", err)
+	}
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load co-host", err)
+	}
+	return host, nil
+}
+
+// List returns every host of the event, author first. Pending invitations
+// are only shown to those who manage the event.
+func (s *EventHostService) List(ctx context.Context, postID string, viewerID *string) ([]*models.EventHost, error) {
+	post, err := s.getEventPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	includePending := false
+	if viewerID != nil && *viewerID != "" {
+		if includePending, err = s.CanManage(ctx, post, *viewerID); err != nil {
+			return nil, utils.NewInternalError("Failed to check co-hosts", err)
+		}
+	}
+	coHosts, err := s.hostRepo.ListByPostIDs(ctx, []string{postID}, includePending)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load co-hosts", err)
+	}
+
+	hosts := make([]*models.EventHost, 0, len(coHosts[postID])+1)
+	if author := s.authorHost(ctx, post); author != nil {
+		hosts = append(hosts, author)
+	}
+	return append(hosts, coHosts[postID]...), nil
+}
+
+// authorHost describes the event's author as its host: the business when
+// posted as one, the user otherwise.
+func (s *EventHostService) authorHost(ctx context.Context, post *models.Post) *models.EventHost {
+	if post.BusinessID != nil && *post.BusinessID != "" {
+		if business, err := s.businessRepo.GetByID(ctx, *post.BusinessID); err == nil {
+			return &models.EventHost{
+				PostID:     post.ID,
+				Role:       models.EventHostRoleHost,
+				BusinessID: post.BusinessID,
+				Name:       business.Name,
+				Avatar:     business.Avatar,
+			}
+		}
+	}
+	if post.UserID != nil {
+		if profile, err := s.userRepo.GetProfileByUserID(ctx, *post.UserID); err == nil {
+			return &models.EventHost{
+				PostID: post.ID,
+				Role:   models.EventHostRoleHost,
+				UserID: post.UserID,
+				Name:   profile.FullName(),
+				Avatar: profile.Avatar,
+			}
+		}
+	}
+	return nil
+}
+
+// ListInvitations returns the co-host invitations waiting on the user.
+func (s *EventHostService) ListInvitations(ctx context.Context, userID string) ([]*models.EventHostInvitation, error) {
+	invitations, err := s.hostRepo.ListPendingForUser(ctx, userID)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load invitations", err)
+	}
+	return invitations, nil
+}
+
+// CoHostsForPosts loads the accepted co-hosts of each event in the batch.
+func (s *EventHostService) CoHostsForPosts(ctx context.Context, postIDs []string) (map[string][]*models.EventHost, error) {
+	return s.hostRepo.ListByPostIDs(ctx, postIDs, false)
+}
+
+// MessageAttendees sends a host's message to the event's attendees as a
+// notification. Audience defaults to those going.
+func (s *EventHostService) MessageAttendees(ctx context.Context, postID, senderID string, req *models.MessageEventAttendeesRequest) (*models.MessageEventAttendeesResponse, error) {
+	post, err := s.getEventPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.CanManage(ctx, post, senderID)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to check co-hosts", err)
+	}
+	if !ok {
+		return nil, utils.NewForbiddenError("Only the event's hosts can message attendees", nil)
+	}
+	message := strings.TrimSpace(req.Message)
+	if message == "" {
+		return nil, utils.NewBadRequestError("Message is required", nil)
+	}
+
+	states := []models.EventInterestState{models.EventInterestGoing}
+	switch req.Audience {
+	case models.EventAudienceInterested:
+		states = []models.EventInterestState{models.EventInterestInterested}
+	case models.EventAudienceAll:
+		states = []models.EventInterestState{models.EventInterestGoing, models.EventInterestInterested}
+	}
+	attendees, err := s.hostRepo.ListAttendeeIDs(ctx, postID, states)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load attendees", err)
+	}
+
+	recipients := make([]string, 0, len(attendees))
+	for _, id := range attendees {
+		if id != senderID {
+			recipients = append(recipients, id)
+		}
+	}
+	s.notify(recipients, senderID, models.NotificationTypeEventHostMessage, post, "sent an update about", &message, nil)
+
+	s.logger.Info("Event hosts messaged attendees",
+		zap.String("post_id", postID),
+		zap.String("user_id", senderID),
+		zap.Int("recipients", len(recipients)),
+	)
+	return &models.MessageEventAttendeesResponse{Recipients: len(recipients)}, nil
+}
+
+// notify sends a best-effort event host notification in the background.
+// message overrides the default body (the event title).
+func (s *EventHostService) notify(recipients []string, actorID string, notifType models.NotificationType, post *models.Post, verb string, message *string, host *models.EventHost) {
+	if s.notificationService == nil || len(recipients) == 0 {
+		return
+	}
+	bgtasks.Submit(func(ctx context.Context) {
+		actorName := ""
+		if actor, err := s.userRepo.GetProfileByUserID(ctx, actorID); err == nil && actor != nil {
+			actorName = actor.FullName()
+		}
+		title := strings.TrimSpace(actorName + " " + verb + " an event")
+		if post.Title != nil && *post.Title != "" {
+			title = strings.TrimSpace(actorName + " " + verb + " " + *post.Title)
+		}
+		msg := message
+		if msg == nil {
+			msg = post.Title
+		}
+		data := map[string]interface{}{
+			"actor_id":   actorID,
+			"actor_name": actorName,
+			"post_id":    post.ID,
+			"post_type":  "EVENT",
+		}
+		if host != nil {
+			data["host_id"] = host.ID
+			if host.BusinessID != nil {
+				data["business_id"] = *host.BusinessID
+			}
+		}
+		for _, recipientID := range recipients {
+			if recipientID == actorID {
+				continue
+			}
+			if _, err := s.notificationService.CreateNotification(ctx, &models.CreateNotificationRequest{
+				UserID:  recipientID,
+				Type:    notifType,
+				Title:   &title,
+				Message: msg,
+				Data:    data,
+			}); err != nil {
+				s.logger.Warn("Failed to send event host notification",
+					zap.String("post_id", post.ID), zap.String("type", string(notifType)), zap.Error(err))
+			}
+		}
+	})
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestEventHostService(hostRepo *mocks.MockEventHostRepository, postRepo *mocks.MockPostRepository, userRepo *mocks.MockUserRepository, businessRepo *mocks.MockBusinessRepository) *EventHostService {
+	// notificationService is nil so notify is a no-op.
+	return NewEventHostService(hostRepo, postRepo, userRepo, businessRepo, nil, zap.NewNop())
+}
+
+func eventPost(owner string) *models.Post {
+	return testutil.CreateTestPost("post-1", owner, models.PostTypeEvent)
+}
+
+func TestEventHostService_Invite(t *testing.T) {
+	t.Run("only the author invites", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(eventPost("owner-1"), nil)
+		svc := newTestEventHostService(new(mocks.MockEventHostRepository), postRepo, new(mocks.MockUserRepository), new(mocks.MockBusinessRepository))
+
+		_, err := svc.Invite(context.Background(), "post-1", "user-2", &models.InviteEventHostRequest{UserID: ptrStr("user-3")})
+		requireAppErrCode(t, err, http.StatusForbidden)
+	})
+
+	t.Run("user or business, not both", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(eventPost("owner-1"), nil)
+		svc := newTestEventHostService(new(mocks.MockEventHostRepository), postRepo, new(mocks.MockUserRepository), new(mocks.MockBusinessRepository))
+
+		_, err := svc.Invite(context.Background(), "post-1", "owner-1", &models.InviteEventHostRequest{
+			UserID: ptrStr("user-3"), BusinessID: ptrStr("biz-1"),
+		})
+		requireAppErrCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("invites a business", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(eventPost("owner-1"), nil)
+		businessRepo := new(mocks.MockBusinessRepository)
+		businessRepo.On("GetByID", mock.Anything, "biz-1").
+			Return(&models.BusinessProfile{ID: "biz-1", UserID: "biz-owner", Name: "Kabul Books"}, nil)
+		hostRepo := new(mocks.MockEventHostRepository)
+		hostRepo.On("ListByPostIDs", mock.Anything, []string{"post-1"}, true).Return(map[string][]*models.EventHost{}, nil)
+		hostRepo.On("Invite", mock.Anything, mock.MatchedBy(func(h *models.EventHost) bool {
+			return *h.BusinessID == "biz-1" && h.UserID == nil && h.Status == models.EventHostPending && h.InvitedBy == "owner-1"
+		})).Return(nil)
+		svc := newTestEventHostService(hostRepo, postRepo, new(mocks.MockUserRepository), businessRepo)
+
+		host, err := svc.Invite(context.Background(), "post-1", "owner-1", &models.InviteEventHostRequest{BusinessID: ptrStr("biz-1")})
+		require.NoError(t, err)
+		assert.Equal(t, "Kabul Books", host.Name)
+		assert.Equal(t, models.EventHostRoleCoHost, host.Role)
+		hostRepo.AssertExpectations(t)
+	})
+
+	t.Run("already invited", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(eventPost("owner-1"), nil)
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetProfileByUserID", mock.Anything, "user-3").Return(&models.Profile{ID: "user-3"}, nil)
+		hostRepo := new(mocks.MockEventHostRepository)
+		hostRepo.On("ListByPostIDs", mock.Anything, []string{"post-1"}, true).Return(map[string][]*models.EventHost{}, nil)
+		hostRepo.On("Invite", mock.Anything, mock.Anything).Return(repositories.ErrEventHostExists)
+		svc := newTestEventHostService(hostRepo, postRepo, userRepo, new(mocks.MockBusinessRepository))
+
+		_, err := svc.Invite(context.Background(), "post-1", "owner-1", &models.InviteEventHostRequest{UserID: ptrStr("user-3")})
+		requireAppErrCode(t, err, http.StatusConflict)
+	})
+}
+
+func TestEventHostService_Respond(t *testing.T) {
+	pending := func() *models.EventHost {
+		return &models.EventHost{ID: "host-1", PostID: "post-1", UserID: ptrStr("user-3"), Status: models.EventHostPending}
+	}
+
+	t.Run("only the invitee answers", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(eventPost("owner-1"), nil)
+		hostRepo := new(mocks.MockEventHostRepository)
+		hostRepo.On("GetByID", mock.Anything, "host-1").Return(pending(), nil)
+		svc := newTestEventHostService(hostRepo, postRepo, new(mocks.MockUserRepository), new(mocks.MockBusinessRepository))
+
+		_, err := svc.Respond(context.Background(), "post-1", "host-1", "owner-1", true)
+		requireAppErrCode(t, err, http.StatusForbidden)
+		hostRepo.AssertNotCalled(t, "Respond", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("invitation of another event", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-2").Return(testutil.CreateTestPost("post-2", "owner-1", models.PostTypeEvent), nil)
+		hostRepo := new(mocks.MockEventHostRepository)
+		hostRepo.On("GetByID", mock.Anything, "host-1").Return(pending(), nil)
+		svc := newTestEventHostService(hostRepo, postRepo, new(mocks.MockUserRepository), new(mocks.MockBusinessRepository))
+
+		_, err := svc.Respond(context.Background(), "post-2", "host-1", "user-3", true)
+		requireAppErrCode(t, err, http.StatusNotFound)
+	})
+
+	t.Run("owner of the invited business accepts", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(eventPost("owner-1"), nil)
+		businessRepo := new(mocks.MockBusinessRepository)
+		businessRepo.On("GetByID", mock.Anything, "biz-1").Return(&models.BusinessProfile{ID: "biz-1", UserID: "biz-owner"}, nil)
+		invite := &models.EventHost{ID: "host-1", PostID: "post-1", BusinessID: ptrStr("biz-1"), Status: models.EventHostPending}
+		accepted := *invite
+		accepted.Status = models.EventHostAccepted
+		hostRepo := new(mocks.MockEventHostRepository)
+		hostRepo.On("GetByID", mock.Anything, "host-1").Return(invite, nil)
+		hostRepo.On("Respond", mock.Anything, "host-1", models.EventHostAccepted).Return(&accepted, nil)
+		svc := newTestEventHostService(hostRepo, postRepo, new(mocks.MockUserRepository), businessRepo)
+
+		got, err := svc.Respond(context.Background(), "post-1", "host-1", "biz-owner", true)
+		require.NoError(t, err)
+		assert.Equal(t, models.EventHostAccepted, got.Status)
+	})
+
+	t.Run("already answered", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(eventPost("owner-1"), nil)
+		hostRepo := new(mocks.MockEventHostRepository)
+		hostRepo.On("GetByID", mock.Anything, "host-1").Return(pending(), nil)
+		hostRepo.On("Respond", mock.Anything, "host-1", models.EventHostDeclined).Return(nil, repositories.ErrEventHostConflict)
+		svc := newTestEventHostService(hostRepo, postRepo, new(mocks.MockUserRepository), new(mocks.MockBusinessRepository))
+
+		_, err := svc.Respond(context.Background(), "post-1", "host-1", "user-3", false)
+		requireAppErrCode(t, err, http.StatusConflict)
+	})
+}
+
+func TestEventHostService_MessageAttendees(t *testing.T) {
+	req := &models.MessageEventAttendeesRequest{Message: "Doors open at 5", Audience: models.EventAudienceAll}
+
+	t.Run("attendees can't message", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(eventPost("owner-1"), nil)
+		hostRepo := new(mocks.MockEventHostRepository)
+		hostRepo.On("IsCoHost", mock.Anything, "post-1", "user-2").Return(false, nil)
+		svc := newTestEventHostService(hostRepo, postRepo, new(mocks.MockUserRepository), new(mocks.MockBusinessRepository))
+
+		_, err := svc.MessageAttendees(context.Background(), "post-1", "user-2", req)
+		requireAppErrCode(t, err, http.StatusForbidden)
+	})
+
+	t.Run("co-host messages everyone but themselves", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(eventPost("owner-1"), nil)
+		hostRepo := new(mocks.MockEventHostRepository)
+		hostRepo.On("IsCoHost", mock.Anything, "post-1", "cohost-1").Return(true, nil)
+		hostRepo.On("ListAttendeeIDs", mock.Anything, "post-1",
+			[]models.EventInterestState{models.EventInterestGoing, models.EventInterestInterested}).
+			Return([]string{"user-2", "cohost-1", "user-4"}, nil)
+		svc := newTestEventHostService(hostRepo, postRepo, new(mocks.MockUserRepository), new(mocks.MockBusinessRepository))
+
+		got, err := svc.MessageAttendees(context.Background(), "post-1", "cohost-1", req)
+		require.NoError(t, err)
+		assert.Equal(t, 2, got.Recipients)
+	})
+}
+
+func TestEventHosts_AuthorFirst(t *testing.T) {
+	coHost := &models.EventHost{ID: "host-1", Role: models.EventHostRoleCoHost, UserID: ptrStr("user-3")}
+
+	resp := &models.PostResponse{
+		ID:       "post-1",
+		Author:   &models.AuthorInfo{UserID: "owner-1", FullName: "Owner"},
+		Business: &models.BusinessInfo{BusinessID: "biz-1", Name: "Kabul Books"},
+	}
+	hosts := eventHosts(resp, []*models.EventHost{coHost})
+	require.Len(t, hosts, 2)
+	assert.Equal(t, models.EventHostRoleHost, hosts[0].Role)
+	assert.Equal(t, "biz-1", *hosts[0].BusinessID, "a business event is hosted by the business")
+	assert.Nil(t, hosts[0].UserID)
+	assert.Same(t, coHost, hosts[1])
+
+	resp.Business = nil
+	hosts = eventHosts(resp, nil)
+	require.Len(t, hosts, 1)
+	assert.Equal(t, "owner-1", *hosts[0].UserID)
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"github.com/hamsaya/backend/internal/repositories"
 	"go.uber.org/zap"
@@ -10,16 +11,28 @@ import (
 // FanoutService manages hybrid push/pull feed fanout.
 type FanoutService struct {
 	fanoutRepo repositories.FanoutRepository
+	feed       repositories.FeedProvider
 	logger     *zap.Logger
 }
 
-// NewFanoutService creates a new FanoutService.
+// NewFanoutService creates a new FanoutService. Timelines are written to
+// user_feeds unless WithFeedProvider picks another backend.
 func NewFanoutService(fanoutRepo repositories.FanoutRepository, logger *zap.Logger) *FanoutService {
-	return &FanoutService{fanoutRepo: fanoutRepo, logger: logger}
+	return &FanoutService{
+		fanoutRepo: fanoutRepo,
+		feed:       repositories.NewPostgresFeedProvider(fanoutRepo),
+		logger:     logger,
+	}
+}
+
+// WithFeedProvider swaps the timeline backend posts are pushed to.
+func (s *FanoutService) WithFeedProvider(feed repositories.FeedProvider) *FanoutService {
+	s.feed = feed
+	return s
 }
 
 // FanoutPost is called in a background goroutine immediately after a post is
-// persisted. It inserts the post into every follower's user_feeds row.
+// persisted. It pushes the post onto every follower's home timeline.
 //
 // Celebrity authors (> CelebrityThreshold followers) are skipped because their
 // posts are queried on read via GetCelebrityPostIDs to avoid write-amplification.
@@ -39,7 +52,7 @@ func (s *FanoutService) FanoutPost(ctx context.Context, postID, authorID string)
 		s.logger.Error("FanoutPost: get follower IDs", zap.String("author_id", authorID), zap.Error(err))
 		return
 	}
-	if err := s.fanoutRepo.InsertFeedEntries(ctx, postID, ids); err != nil {
+	if err := s.feed.Push(ctx, postID, time.Now(), ids); err != nil {
 		s.logger.Error("FanoutPost: insert feed entries", zap.String("post_id", postID), zap.Error(err))
 	}
 }
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// maxFeaturedBusinesses caps the featured businesses served per province;
+// it also caps the endpoint's limit.
+const maxFeaturedBusinesses = 10
+
+// businessCardLoader renders business ids as cards, keeping their order.
+// Implemented by BusinessService.
+type businessCardLoader interface {
+	GetBusinessCards(ctx context.Context, businessIDs []string, viewerID *string) []*models.BusinessCardResponse
+}
+
+// FeaturedBusinessService manages featured business placements: admins
+// schedule them per province, clients get the live ones on
+// GET /businesses/featured and at the top of /discover, and business owners
+// see how their placements performed.
+type FeaturedBusinessService struct {
+	repo         repositories.FeaturedBusinessRepository
+	businessRepo repositories.BusinessRepository
+	access       *AccessControl
+	cards        businessCardLoader
+	logger       *zap.Logger
+
+	// now is swapped in tests.
+	now func() time.Time
+}
+
+// NewFeaturedBusinessService wires the featured business service.
+func NewFeaturedBusinessService(
+	repo repositories.FeaturedBusinessRepository,
+	businessRepo repositories.BusinessRepository,
+	cards businessCardLoader,
+	logger *zap.Logger,
+) *FeaturedBusinessService {
+	return &FeaturedBusinessService{
+		repo:         repo,
+		businessRepo: businessRepo,
+		access:       NewAccessControl(businessRepo),
+		cards:        cards,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// withStatus fills the placement's status from its date window.
+func (s *FeaturedBusinessService) withStatus(placement *models.FeaturedBusiness) *models.FeaturedBusiness {
+	now := s.now()
+	switch {
+	case now.Before(placement.StartsAt):
+		placement.Status = models.FeaturedStatusScheduled
+	case now.Before(placement.EndsAt):
+		placement.Status = models.FeaturedStatusLive
+	default:
+		placement.Status = models.FeaturedStatusEnded
+	}
+	return placement
+}
+
+func (s *FeaturedBusinessService) get(ctx context.Context, id string) (*models.FeaturedBusiness, error) {
+	placement, err := s.repo.GetByID(ctx, id)
+	if errors.Is(err, repositories.ErrFeaturedBusinessNotFound) {
+		return nil, utils.NewNotFoundError("Featured placement not found", err)
+	}
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load featured placement", err)
+	}
+	return s.withStatus(placement), nil
+}
+
+// Create schedules a new placement.
+func (s *FeaturedBusinessService) Create(ctx context.Context, adminID string, req *models.CreateFeaturedBusinessRequest) (*models.FeaturedBusiness, error) {
+	province := normalizeRegion(req.Province)
+	if province == "" {
+		return nil, utils.NewBadRequestError("Province is required", nil)
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		return nil, utils.NewBadRequestError("ends_at must be after starts_at", nil)
+	}
+	business, err := s.businessRepo.GetByID(ctx, req.BusinessID)
+	if err != nil || business == nil {
+		return nil, utils.NewNotFoundError("Business not found", err)
+	}
+
+	placement := &models.FeaturedBusiness{
+		ID:           uuid.NewString(),
+		BusinessID:   business.ID,
+		BusinessName: business.Name,
+		Province:     province,
+		Position:     req.Position,
+		StartsAt:     req.StartsAt,
+		EndsAt:       req.EndsAt,
+		CreatedBy:    &adminID,
+	}
+	if err := s.repo.Create(ctx, placement); err != nil {
+		s.logger.Error("Failed to create featured placement", zap.Error(err), zap.String("business_id", business.ID))
+		return nil, utils.NewInternalError("Failed to create featured placement", err)
+	}
+	return s.withStatus(placement), nil
+}
+
+// Update edits a placement's province, position or date window.
+func (s *FeaturedBusinessService) Update(ctx context.Context, id string, req *models.UpdateFeaturedBusinessRequest) (*models.FeaturedBusiness, error) {
+	placement, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Province != nil {
+		placement.Province = normalizeRegion(*req.Province)
+		if placement.Province == "" {
+			return nil, utils.NewBadRequestError("Province is required", nil)
+		}
+	}
+	if req.Position != nil {
+		placement.Position = *req.Position
+	}
+	if req.StartsAt != nil {
+		placement.StartsAt = *req.StartsAt
+	}
+	if req.EndsAt != nil {
+		placement.EndsAt = *req.EndsAt
+	}
+	if !placement.EndsAt.After(placement.StartsAt) {
+		return nil, utils.NewBadRequestError("ends_at must be after starts_at", nil)
+	}
+
+	if err := s.repo.Update(ctx, placement); err != nil {
+		if errors.Is(err, repositories.ErrFeaturedBusinessNotFound) {
+			return nil, utils.NewNotFoundError("Featured placement not found", err)
+		}
+		return nil, utils.NewInternalError("Failed to update featured placement", err)
+	}
+	return s.withStatus(placement), nil
+}
+
+// Delete removes a placement.
+func (s *FeaturedBusinessService) Delete(ctx context.Context, id string) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repositories.ErrFeaturedBusinessNotFound) {
+			return utils.NewNotFoundError("Featured placement not found", err)
+		}
+		return utils.NewInternalError("Failed to delete featured placement", err)
+	}
+	return nil
+}
+
+// List returns placements for the admin panel.
+func (s *FeaturedBusinessService) List(ctx context.Context, filter models.FeaturedBusinessFilter, limit, offset int) ([]*models.FeaturedBusiness, int, error) {
+	filter.Province = normalizeRegion(filter.Province)
+	placements, total, err := s.repo.List(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, 0, utils.NewInternalError("Failed to list featured placements", err)
+	}
+	for _, p := range placements {
+		s.withStatus(p)
+	}
+	return placements, total, nil
+}
+
+// ListForBusiness returns the business's placements with their impression
+// and click counts, for its owner.
+func (s *FeaturedBusinessService) ListForBusiness(ctx context.Context, businessID, userID string, limit, offset int) ([]*models.FeaturedBusiness, int, error) {
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return nil, 0, err
+	}
+	return s.List(ctx, models.FeaturedBusinessFilter{BusinessID: businessID}, limit, offset)
+}
+
+// Featured returns the province's live featured businesses as cards. An
+// empty province, or a failed lookup, yields none; featuring never fails
+// the page it is shown on.
+func (s *FeaturedBusinessService) Featured(ctx context.Context, province string, viewerID *string, limit int) []*models.FeaturedBusinessCard {
+	out := []*models.FeaturedBusinessCard{}
+	province = normalizeRegion(province)
+	if s == nil || province == "" {
+		return out
+	}
+	if limit <= 0 || limit > maxFeaturedBusinesses {
+		limit = maxFeaturedBusinesses
+	}
+	placements, err := s.repo.ListLive(ctx, province, limit)
+	if err != nil {
+		s.logger.Warn("Failed to load featured businesses", zap.String("province", province), zap.Error(err))
+		return out
+	}
+	if len(placements) == 0 {
+		return out
+	}
+
+	// A business featured twice in the province shows once, at its best
+	// position.
+	ids := make([]string, 0, len(placements))
+	placementOf := make(map[string]string, len(placements))
+	for _, p := range placements {
+		if _, seen := placementOf[p.BusinessID]; seen {
+			continue
+		}
+		placementOf[p.BusinessID] = p.ID
+		ids = append(ids, p.BusinessID)
+	}
+	for _, card := range s.cards.GetBusinessCards(ctx, ids, viewerID) {
+		out = append(out, &models.FeaturedBusinessCard{PlacementID: placementOf[card.ID], Business: card})
+	}
+	return out
+}
+
+// RecordImpression counts one impression of a placement.
+func (s *FeaturedBusinessService) RecordImpression(ctx context.Context, id string) error {
+	return s.repo.IncrementImpression(ctx, id)
+}
+
+// RecordClick counts one click on a placement.
+func (s *FeaturedBusinessService) RecordClick(ctx context.Context, id string) error {
+	return s.repo.IncrementClick(ctx, id)
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// stubCards renders every id as a card, dropping those in hidden.
+type stubCards struct {
+	hidden map[string]bool
+	asked  []string
+}
+
+func (s *stubCards) GetBusinessCards(_ context.Context, ids []string, _ *string) []*models.BusinessCardResponse {
+	s.asked = ids
+	var out []*models.BusinessCardResponse
+	for _, id := range ids {
+		if !s.hidden[id] {
+			out = append(out, &models.BusinessCardResponse{ID: id})
+		}
+	}
+	return out
+}
+
+func newTestFeaturedService(t *testing.T) (*FeaturedBusinessService, *mocks.MockFeaturedBusinessRepository, *mocks.MockBusinessRepository, *stubCards) {
+	t.Helper()
+	repo := new(mocks.MockFeaturedBusinessRepository)
+	businessRepo := new(mocks.MockBusinessRepository)
+	cards := &stubCards{}
+	return NewFeaturedBusinessService(repo, businessRepo, cards, zap.NewNop()), repo, businessRepo, cards
+}
+
+func TestFeaturedBusinessService_Featured(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("province is normalized and cards keep placement order", func(t *testing.T) {
+		svc, repo, _, cards := newTestFeaturedService(t)
+		repo.On("ListLive", mock.Anything, "kabul", maxFeaturedBusinesses).Return([]*models.FeaturedBusiness{
+			{ID: "p1", BusinessID: "b2"},
+			{ID: "p2", BusinessID: "b1"},
+			{ID: "p3", BusinessID: "b2"}, // same business again
+		}, nil)
+
+		got := svc.Featured(ctx, "  Kabul ", nil, 50)
+
+		assert.Equal(t, []string{"b2", "b1"}, cards.asked)
+		require.Len(t, got, 2)
+		assert.Equal(t, "p1", got[0].PlacementID)
+		assert.Equal(t, "b2", got[0].Business.ID)
+		assert.Equal(t, "p2", got[1].PlacementID)
+	})
+
+	t.Run("hidden businesses drop out", func(t *testing.T) {
+		svc, repo, _, cards := newTestFeaturedService(t)
+		cards.hidden = map[string]bool{"b1": true}
+		repo.On("ListLive", mock.Anything, "herat", 3).Return([]*models.FeaturedBusiness{
+			{ID: "p1", BusinessID: "b1"},
+			{ID: "p2", BusinessID: "b2"},
+		}, nil)
+
+		got := svc.Featured(ctx, "herat", nil, 3)
+		require.Len(t, got, 1)
+		assert.Equal(t, "p2", got[0].PlacementID)
+	})
+
+	t.Run("no province or a failed lookup yields none", func(t *testing.T) {
+		svc, repo, _, _ := newTestFeaturedService(t)
+		assert.Empty(t, svc.Featured(ctx, " ", nil, 0))
+		repo.AssertNotCalled(t, "ListLive", mock.Anything, mock.Anything, mock.Anything)
+
+		repo.On("ListLive", mock.Anything, "kabul", maxFeaturedBusinesses).Return(nil, errors.New("db down"))
+		got := svc.Featured(ctx, "kabul", nil, 0)
+		assert.NotNil(t, got)
+		assert.Empty(t, got)
+
+		var unwired *FeaturedBusinessService
+		assert.Empty(t, unwired.Featured(ctx, "kabul", nil, 0))
+	})
+}
+
+func TestFeaturedBusinessService_Create(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("window must end after it starts", func(t *testing.T) {
+		svc, _, _, _ := newTestFeaturedService(t)
+		_, err := svc.Create(ctx, "admin-1", &models.CreateFeaturedBusinessRequest{
+			BusinessID: "b1", Province: "Kabul", StartsAt: start, EndsAt: start,
+		})
+		var appErr *utils.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, 400, appErr.Code)
+	})
+
+	t.Run("stores the normalized province and derives the status", func(t *testing.T) {
+		svc, repo, businessRepo, _ := newTestFeaturedService(t)
+		svc.now = func() time.Time { return start.Add(-time.Hour) }
+		businessRepo.On("GetByID", mock.Anything, "b1").Return(&models.BusinessProfile{ID: "b1", Name: "Bakery"}, nil)
+		repo.On("Create", mock.Anything, mock.MatchedBy(func(p *models.FeaturedBusiness) bool {
+			return p.Province == "kabul" && p.BusinessID == "b1" && *p.CreatedBy == "admin-1"
+		})).Return(nil)
+
+		placement, err := svc.Create(ctx, "admin-1", &models.CreateFeaturedBusinessRequest{
+			BusinessID: "b1", Province: " Kabul", StartsAt: start, EndsAt: start.Add(7 * 24 * time.Hour),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.FeaturedStatusScheduled, placement.Status)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestFeaturedBusinessService_ListForBusiness_OwnerOnly(t *testing.T) {
+	svc, repo, businessRepo, _ := newTestFeaturedService(t)
+	businessRepo.On("GetByID", mock.Anything, "b1").Return(&models.BusinessProfile{ID: "b1", UserID: "owner-1"}, nil)
+
+	_, _, err := svc.ListForBusiness(context.Background(), "b1", "someone-else", 20, 0)
+	var appErr *utils.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, 403, appErr.Code)
+	repo.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
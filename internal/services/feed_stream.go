@@ -0,0 +1,264 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sync"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/events"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// feedStreamChannel carries a hint for every new post to all instances.
+const feedStreamChannel = "feed:new_posts"
+
+// feedHint is the part of a new post needed to match it against open
+// streams. It goes over Redis, so every instance sees posts created on any
+// of them.
+type feedHint struct {
+	PostID     string          `json:"post_id"`
+	AuthorID   string          `json:"author_id,omitempty"`
+	Type       models.PostType `json:"type"`
+	BusinessID *string         `json:"business_id,omitempty"`
+	CategoryID *string         `json:"category_id,omitempty"`
+	Province   *string         `json:"province,omitempty"`
+	Latitude   *float64        `json:"latitude,omitempty"`
+	Longitude  *float64        `json:"longitude,omitempty"`
+}
+
+// FeedUpdate is what a stream reports: how many posts in its scope were
+// created since it opened and the newest one's id.
+type FeedUpdate struct {
+	Count    int    `json:"count"`
+	NewestID string `json:"newest_id"`
+}
+
+// FeedStream pushes "new posts available" hints to clients holding
+// GET /posts/stream open, so the app can show a "N new posts" pill
+// instead of polling the feed.
+//
+// Post creation is republished on a Redis channel; every instance
+// subscribes and matches each hint against its own open streams. Hints
+// are approximate: blocks, shadowbans and the author's profile province
+// are left to the feed request the client makes when the pill is tapped.
+type FeedStream struct {
+	redis  *redis.Client
+	logger *zap.Logger
+
+	mu   sync.Mutex
+	subs map[*FeedSubscription]struct{}
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewFeedStream creates the stream. Call Start to receive hints.
+func NewFeedStream(redisClient *redis.Client, logger *zap.Logger) *FeedStream {
+	return &FeedStream{
+		redis:  redisClient,
+		logger: logger,
+		subs:   make(map[*FeedSubscription]struct{}),
+	}
+}
+
+// SubscribePosts publishes a hint for every new post outside a group.
+func (f *FeedStream) SubscribePosts(bus *events.Bus) {
+	events.Subscribe(bus, func(ctx context.Context, e PostCreated) {
+		if e.Post.GroupID != nil || e.Post.Visibility == models.VisibilityPrivate {
+			return
+		}
+		f.Publish(ctx, e.Post)
+	})
+}
+
+// Publish sends a post's hint to every instance. Best-effort.
+func (f *FeedStream) Publish(ctx context.Context, post *models.Post) {
+	hint := feedHint{
+		PostID:     post.ID,
+		Type:       post.Type,
+		BusinessID: post.BusinessID,
+		CategoryID: post.CategoryID,
+		Province:   post.Province,
+	}
+	if post.UserID != nil {
+		hint.AuthorID = *post.UserID
+	}
+	if post.AddressLocation != nil && post.AddressLocation.Valid {
+		hint.Latitude = &post.AddressLocation.P.Y
+		hint.Longitude = &post.AddressLocation.P.X
+	}
+	body, err := json.Marshal(hint)
+	if err != nil {
+		return
+	}
+	if err := f.redis.Publish(ctx, feedStreamChannel, body).Err(); err != nil {
+		f.logger.Warn("Failed to publish feed hint", zap.String("post_id", post.ID), zap.Error(err))
+	}
+}
+
+// Start subscribes to the hint channel. Idempotent.
+func (f *FeedStream) Start() {
+	if f.cancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	f.cancel = cancel
+	pubsub := f.redis.Subscribe(ctx, feedStreamChannel)
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		defer func() { _ = pubsub.Close() }()
+		ch := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				var hint feedHint
+				if err := json.Unmarshal([]byte(msg.Payload), &hint); err != nil {
+					f.logger.Warn("feed stream: bad payload", zap.Error(err))
+					continue
+				}
+				f.dispatch(hint)
+			}
+		}
+	}()
+}
+
+// Stop ends the subscriber goroutine.
+func (f *FeedStream) Stop() {
+	if f.cancel == nil {
+		return
+	}
+	f.cancel()
+	f.wg.Wait()
+	f.cancel = nil
+}
+
+func (f *FeedStream) dispatch(hint feedHint) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for sub := range f.subs {
+		if sub.matches(hint) {
+			sub.add(hint.PostID)
+		}
+	}
+}
+
+// Subscribe opens a stream for the feed scope in filter. viewerID is empty
+// for anonymous viewers; their own posts are never counted. Close the
+// subscription when the client goes away.
+func (f *FeedStream) Subscribe(filter *models.FeedFilter, viewerID string) *FeedSubscription {
+	sub := &FeedSubscription{
+		stream:   f,
+		filter:   filter,
+		viewerID: viewerID,
+		notify:   make(chan struct{}, 1),
+	}
+	f.mu.Lock()
+	f.subs[sub] = struct{}{}
+	f.mu.Unlock()
+	return sub
+}
+
+// FeedSubscription is one open stream.
+type FeedSubscription struct {
+	stream   *FeedStream
+	filter   *models.FeedFilter
+	viewerID string
+	notify   chan struct{}
+
+	mu     sync.Mutex
+	update FeedUpdate
+}
+
+// Updates signals when the count changed. Signals coalesce: read Latest
+// after each one.
+func (s *FeedSubscription) Updates() <-chan struct{} {
+	return s.notify
+}
+
+// Latest returns the count so far.
+func (s *FeedSubscription) Latest() FeedUpdate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.update
+}
+
+// Close stops counting.
+func (s *FeedSubscription) Close() {
+	s.stream.mu.Lock()
+	delete(s.stream.subs, s)
+	s.stream.mu.Unlock()
+}
+
+func (s *FeedSubscription) add(postID string) {
+	s.mu.Lock()
+	s.update.Count++
+	s.update.NewestID = postID
+	s.mu.Unlock()
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// matches applies the feed filters that can be checked from the hint
+// alone, the same way GetFeed applies them in SQL.
+func (s *FeedSubscription) matches(h feedHint) bool {
+	if s.viewerID != "" && h.AuthorID == s.viewerID {
+		return false
+	}
+	f := s.filter
+	if f.Type != nil && *f.Type != h.Type {
+		return false
+	}
+	if f.Type == nil && f.HideUnpromotedSell && h.Type == models.PostTypeSell {
+		// Only promoted listings reach the home feed, and posts are
+		// promoted after creation.
+		return false
+	}
+	if f.BusinessID != nil {
+		if h.BusinessID == nil || *h.BusinessID != *f.BusinessID {
+			return false
+		}
+	} else if f.OnlyBusiness && h.BusinessID == nil {
+		return false
+	}
+	if f.CategoryID != nil && (h.CategoryID == nil || *h.CategoryID != *f.CategoryID) {
+		return false
+	}
+	if f.Province != nil && h.Province != nil && *h.Province != *f.Province {
+		return false
+	}
+	if feedRadiusScope(f) {
+		if h.Latitude == nil || h.Longitude == nil {
+			return false
+		}
+		if distanceKm(*f.Latitude, *f.Longitude, *h.Latitude, *h.Longitude) > *f.RadiusKm {
+			return false
+		}
+	}
+	return true
+}
+
+func feedRadiusScope(f *models.FeedFilter) bool {
+	return f.Latitude != nil && f.Longitude != nil && f.RadiusKm != nil
+}
+
+// distanceKm is the great-circle distance between two coordinates.
+func distanceKm(lat1, lng1, lat2, lng2 float64) float64 {
+	const earthRadiusKm = 6371.0
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLng := (lng2 - lng1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
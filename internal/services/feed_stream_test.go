@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func newTestFeedStream(t *testing.T) *FeedStream {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	stream := NewFeedStream(rdb, zap.NewNop())
+	stream.Start()
+	t.Cleanup(stream.Stop)
+	return stream
+}
+
+func TestFeedStream_CountsPostsInScope(t *testing.T) {
+	ctx := context.Background()
+	stream := newTestFeedStream(t)
+
+	sell := models.PostTypeSell
+	lat, lng, radius := 34.5553, 69.2075, 5.0 // Kabul
+	all := stream.Subscribe(&models.FeedFilter{}, "viewer-1")
+	defer all.Close()
+	sales := stream.Subscribe(&models.FeedFilter{Type: &sell}, "")
+	defer sales.Close()
+	nearby := stream.Subscribe(&models.FeedFilter{Latitude: &lat, Longitude: &lng, RadiusKm: &radius}, "")
+	defer nearby.Close()
+	// Miniredis needs a moment to register the subscription.
+	time.Sleep(50 * time.Millisecond)
+
+	author, viewer := "author-1", "viewer-1"
+	stream.Publish(ctx, &models.Post{ID: "post-1", UserID: &author, Type: models.PostTypeFeed,
+		AddressLocation: &pgtype.Point{P: pgtype.Vec2{X: 69.21, Y: 34.56}, Valid: true}})
+	stream.Publish(ctx, &models.Post{ID: "post-2", UserID: &viewer, Type: models.PostTypeFeed})
+	stream.Publish(ctx, &models.Post{ID: "post-3", UserID: &author, Type: models.PostTypeSell,
+		AddressLocation: &pgtype.Point{P: pgtype.Vec2{X: 62.20, Y: 34.35}, Valid: true}}) // Herat
+
+	assert.Eventually(t, func() bool {
+		return all.Latest() == FeedUpdate{Count: 2, NewestID: "post-3"}
+	}, time.Second, 10*time.Millisecond, "the viewer's own post is not counted")
+	assert.Equal(t, FeedUpdate{Count: 1, NewestID: "post-3"}, sales.Latest())
+	assert.Equal(t, FeedUpdate{Count: 1, NewestID: "post-1"}, nearby.Latest())
+}
+
+func TestFeedStream_HomeFeedSkipsListings(t *testing.T) {
+	stream := newTestFeedStream(t)
+	sub := stream.Subscribe(&models.FeedFilter{HideUnpromotedSell: true}, "")
+	defer sub.Close()
+
+	assert.False(t, sub.matches(feedHint{PostID: "post-1", Type: models.PostTypeSell}))
+	assert.True(t, sub.matches(feedHint{PostID: "post-2", Type: models.PostTypeEvent}))
+}
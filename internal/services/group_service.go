@@ -0,0 +1,438 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// GroupService runs neighborhood and interest groups: the directory,
+// membership with roles, join approval for private groups, and the group
+// feed. Posting into a group goes through PostService.CreatePost with
+// group_id set.
+type GroupService struct {
+	groupRepo   repositories.GroupRepository
+	postRepo    repositories.PostRepository
+	postService *PostService
+	logger      *zap.Logger
+}
+
+// NewGroupService wires the service.
+func NewGroupService(
+	groupRepo repositories.GroupRepository,
+	postRepo repositories.PostRepository,
+	postService *PostService,
+	logger *zap.Logger,
+) *GroupService {
+	return &GroupService{
+		groupRepo:   groupRepo,
+		postRepo:    postRepo,
+		postService: postService,
+		logger:      logger,
+	}
+}
+
+func (s *GroupService) getGroup(ctx context.Context, groupID string) (*models.Group, error) {
+	group, err := s.groupRepo.GetByID(ctx, groupID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrGroupNotFound) {
+			return nil, utils.NewNotFoundError("Group not found", err)
+		}
+		return nil, utils.NewInternalError("Failed to load group", err)
+	}
+	return group, nil
+}
+
+// activeRole returns the user's role when they are an ACTIVE member, or ""
+// otherwise.
+func (s *GroupService) activeRole(ctx context.Context, groupID, userID string) (models.GroupRole, error) {
+	if userID == "" {
+		return "", nil
+	}
+	member, err := s.groupRepo.GetMember(ctx, groupID, userID)
+	if err != nil {
+		return "", utils.NewInternalError("Failed to load group membership", err)
+	}
+	if member == nil || member.Status != models.GroupMemberActive {
+		return "", nil
+	}
+	return member.Role, nil
+}
+
+// requireRole loads the group and checks the actor's role against allow.
+func (s *GroupService) requireRole(ctx context.Context, groupID, actorID string, allow func(models.GroupRole) bool) (*models.Group, models.GroupRole, error) {
+	group, err := s.getGroup(ctx, groupID)
+	if err != nil {
+		return nil, "", err
+	}
+	role, err := s.activeRole(ctx, groupID, actorID)
+	if err != nil {
+		return nil, "", err
+	}
+	if role == "" || !allow(role) {
+		return nil, "", utils.NewForbiddenError("You don't have permission to manage this group", nil)
+	}
+	return group, role, nil
+}
+
+func isGroupAdmin(r models.GroupRole) bool { return r == models.GroupRoleAdmin }
+
+func toGroupResponse(group *models.Group, member *models.GroupMember) *models.GroupResponse {
+	resp := &models.GroupResponse{Group: group}
+	if member != nil {
+		role, status := member.Role, member.Status
+		resp.ViewerRole = &role
+		resp.ViewerStatus = &status
+	}
+	return resp
+}
+
+// Create makes a new group with the caller as its first admin.
+func (s *GroupService) Create(ctx context.Context, userID string, req *models.CreateGroupRequest) (*models.GroupResponse, error) {
+	group := &models.Group{
+		ID:           uuid.New().String(),
+		Name:         req.Name,
+		Description:  req.Description,
+		Kind:         req.Kind,
+		Privacy:      req.Privacy,
+		Cover:        req.Cover,
+		Province:     req.Province,
+		District:     req.District,
+		Neighborhood: req.Neighborhood,
+		CreatedBy:    userID,
+	}
+	if group.Kind == "" {
+		group.Kind = models.GroupKindNeighborhood
+	}
+	if group.Privacy == "" {
+		group.Privacy = models.GroupPublic
+	}
+	if group.Kind == models.GroupKindNeighborhood && (group.Province == nil || *group.Province == "") {
+		return nil, utils.NewBadRequestError("Neighborhood groups need a province", nil)
+	}
+
+	if err := s.groupRepo.Create(ctx, group); err != nil {
+		s.logger.Error("Failed to create group", zap.String("user_id", userID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to create group", err)
+	}
+	s.logger.Info("Group created", zap.String("group_id", group.ID), zap.String("user_id", userID))
+
+	return toGroupResponse(group, &models.GroupMember{
+		GroupID: group.ID, UserID: userID, Role: models.GroupRoleAdmin, Status: models.GroupMemberActive,
+	}), nil
+}
+
+// Get returns a group with the viewer's membership.
+func (s *GroupService) Get(ctx context.Context, groupID string, viewerID *string) (*models.GroupResponse, error) {
+	group, err := s.getGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	var member *models.GroupMember
+	if viewerID != nil && *viewerID != "" {
+		if member, err = s.groupRepo.GetMember(ctx, groupID, *viewerID); err != nil {
+			return nil, utils.NewInternalError("Failed to load group membership", err)
+		}
+	}
+	return toGroupResponse(group, member), nil
+}
+
+// Update edits the group's details (admins only).
+func (s *GroupService) Update(ctx context.Context, groupID, userID string, req *models.UpdateGroupRequest) (*models.GroupResponse, error) {
+	group, role, err := s.requireRole(ctx, groupID, userID, isGroupAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		group.Name = *req.Name
+	}
+	if req.Description != nil {
+		group.Description = req.Description
+	}
+	if req.Privacy != nil {
+		group.Privacy = *req.Privacy
+	}
+	if req.Cover != nil {
+		group.Cover = req.Cover
+	}
+	if req.Province != nil {
+		group.Province = req.Province
+	}
+	if req.District != nil {
+		group.District = req.District
+	}
+	if req.Neighborhood != nil {
+		group.Neighborhood = req.Neighborhood
+	}
+
+	if err := s.groupRepo.Update(ctx, group); err != nil {
+		return nil, utils.NewInternalError("Failed to update group", err)
+	}
+	return toGroupResponse(group, &models.GroupMember{
+		GroupID: groupID, UserID: userID, Role: role, Status: models.GroupMemberActive,
+	}), nil
+}
+
+// Delete removes the group (admins only).
+func (s *GroupService) Delete(ctx context.Context, groupID, userID string) error {
+	if _, _, err := s.requireRole(ctx, groupID, userID, isGroupAdmin); err != nil {
+		return err
+	}
+	if err := s.groupRepo.Delete(ctx, groupID); err != nil {
+		return utils.NewInternalError("Failed to delete group", err)
+	}
+	s.logger.Info("Group deleted", zap.String("group_id", groupID), zap.String("user_id", userID))
+	return nil
+}
+
+// List returns the group directory with the viewer's memberships.
+func (s *GroupService) List(ctx context.Context, filter *models.GroupListFilter, viewerID *string) ([]*models.GroupResponse, int, error) {
+	groups, total, err := s.groupRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, utils.NewInternalError("Failed to list groups", err)
+	}
+	return s.withMemberships(ctx, groups, viewerID), total, nil
+}
+
+// ListMine returns the groups the user belongs to.
+func (s *GroupService) ListMine(ctx context.Context, userID string, limit, offset int) ([]*models.GroupResponse, int, error) {
+	groups, total, err := s.groupRepo.ListByMember(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, 0, utils.NewInternalError("Failed to list groups", err)
+	}
+	return s.withMemberships(ctx, groups, &userID), total, nil
+}
+
+func (s *GroupService) withMemberships(ctx context.Context, groups []*models.Group, viewerID *string) []*models.GroupResponse {
+	var memberships map[string]*models.GroupMember
+	if viewerID != nil && *viewerID != "" && len(groups) > 0 {
+		ids := make([]string, len(groups))
+		for i, g := range groups {
+			ids[i] = g.ID
+		}
+		var err error
+		if memberships, err = s.groupRepo.GetMemberships(ctx, *viewerID, ids); err != nil {
+			// Membership badges are cosmetic on the list; keep serving it.
+			s.logger.Warn("Failed to load group memberships", zap.Error(err))
+		}
+	}
+	out := make([]*models.GroupResponse, len(groups))
+	for i, g := range groups {
+		out[i] = toGroupResponse(g, memberships[g.ID])
+	}
+	return out
+}
+
+// Join adds the user to a public group, or files a join request for a
+// private one. Joining again returns the existing membership.
+func (s *GroupService) Join(ctx context.Context, groupID, userID string) (*models.GroupMember, error) {
+	group, err := s.getGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.groupRepo.GetMember(ctx, groupID, userID)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load group membership", err)
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	member := &models.GroupMember{
+		GroupID: groupID,
+		UserID:  userID,
+		Role:    models.GroupRoleMember,
+		Status:  models.GroupMemberActive,
+	}
+	if group.Privacy == models.GroupPrivate {
+		member.Status = models.GroupMemberPending
+	}
+	if err := s.groupRepo.AddMember(ctx, member); err != nil {
+		return nil, utils.NewInternalError("Failed to join group", err)
+	}
+	return member, nil
+}
+
+// Leave removes the user's membership or cancels their pending request.
+// The last admin must hand over the group (or delete it) first.
+func (s *GroupService) Leave(ctx context.Context, groupID, userID string) error {
+	if _, err := s.getGroup(ctx, groupID); err != nil {
+		return err
+	}
+	member, err := s.groupRepo.GetMember(ctx, groupID, userID)
+	if err != nil {
+		return utils.NewInternalError("Failed to load group membership", err)
+	}
+	if member == nil {
+		return utils.NewNotFoundError("You are not a member of this group", nil)
+	}
+	if member.Role == models.GroupRoleAdmin && member.Status == models.GroupMemberActive {
+		if err := s.ensureAnotherAdmin(ctx, groupID); err != nil {
+			return err
+		}
+	}
+	if err := s.groupRepo.RemoveMember(ctx, groupID, userID); err != nil {
+		return utils.NewInternalError("Failed to leave group", err)
+	}
+	return nil
+}
+
+func (s *GroupService) ensureAnotherAdmin(ctx context.Context, groupID string) error {
+	admins, err := s.groupRepo.CountAdmins(ctx, groupID)
+	if err != nil {
+		return utils.NewInternalError("Failed to count group admins", err)
+	}
+	if admins <= 1 {
+		return utils.NewBadRequestError("A group needs at least one admin; promote someone else first", nil)
+	}
+	return nil
+}
+
+// ListMembers lists ACTIVE members, or PENDING join requests for
+// moderators. Members of a private group are only visible to its members.
+func (s *GroupService) ListMembers(ctx context.Context, groupID string, viewerID *string, status models.GroupMemberStatus, limit, offset int) ([]*models.GroupMemberWithProfile, int, error) {
+	group, err := s.getGroup(ctx, groupID)
+	if err != nil {
+		return nil, 0, err
+	}
+	viewer := ""
+	if viewerID != nil {
+		viewer = *viewerID
+	}
+	role, err := s.activeRole(ctx, groupID, viewer)
+	if err != nil {
+		return nil, 0, err
+	}
+	if status == models.GroupMemberPending && !role.CanModerate() {
+		return nil, 0, utils.NewForbiddenError("Only group admins and moderators can see join requests", nil)
+	}
+	if group.Privacy == models.GroupPrivate && role == "" {
+		return nil, 0, utils.NewForbiddenError("This group is private", nil)
+	}
+
+	members, total, err := s.groupRepo.ListMembers(ctx, groupID, status, limit, offset)
+	if err != nil {
+		return nil, 0, utils.NewInternalError("Failed to list group members", err)
+	}
+	return members, total, nil
+}
+
+// ApproveRequest accepts a pending join request (admins and moderators).
+func (s *GroupService) ApproveRequest(ctx context.Context, groupID, actorID, userID string) error {
+	if _, _, err := s.requireRole(ctx, groupID, actorID, models.GroupRole.CanModerate); err != nil {
+		return err
+	}
+	if err := s.groupRepo.ApproveMember(ctx, groupID, userID); err != nil {
+		if errors.Is(err, repositories.ErrGroupMemberNotFound) {
+			return utils.NewNotFoundError("Join request not found", err)
+		}
+		return utils.NewInternalError("Failed to approve join request", err)
+	}
+	return nil
+}
+
+// RemoveMember rejects a pending request or removes a member (admins and
+// moderators). Only admins may remove other admins or moderators.
+func (s *GroupService) RemoveMember(ctx context.Context, groupID, actorID, userID string) error {
+	_, actorRole, err := s.requireRole(ctx, groupID, actorID, models.GroupRole.CanModerate)
+	if err != nil {
+		return err
+	}
+	if actorID == userID {
+		return utils.NewBadRequestError("Use leave to remove yourself", nil)
+	}
+	target, err := s.groupRepo.GetMember(ctx, groupID, userID)
+	if err != nil {
+		return utils.NewInternalError("Failed to load group membership", err)
+	}
+	if target == nil {
+		return utils.NewNotFoundError("Member not found", nil)
+	}
+	if target.Role != models.GroupRoleMember && actorRole != models.GroupRoleAdmin {
+		return utils.NewForbiddenError("Only admins can remove admins and moderators", nil)
+	}
+	if err := s.groupRepo.RemoveMember(ctx, groupID, userID); err != nil {
+		return utils.NewInternalError("Failed to remove member", err)
+	}
+	return nil
+}
+
+// SetRole changes an active member's role (admins only). Demoting the
+// last admin is refused so the group never ends up unmanaged.
+func (s *GroupService) SetRole(ctx context.Context, groupID, actorID, userID string, role models.GroupRole) error {
+	if _, _, err := s.requireRole(ctx, groupID, actorID, isGroupAdmin); err != nil {
+		return err
+	}
+	target, err := s.groupRepo.GetMember(ctx, groupID, userID)
+	if err != nil {
+		return utils.NewInternalError("Failed to load group membership", err)
+	}
+	if target == nil || target.Status != models.GroupMemberActive {
+		return utils.NewNotFoundError("Member not found", nil)
+	}
+	if target.Role == role {
+		return nil
+	}
+	if target.Role == models.GroupRoleAdmin {
+		if err := s.ensureAnotherAdmin(ctx, groupID); err != nil {
+			return err
+		}
+	}
+	if err := s.groupRepo.SetMemberRole(ctx, groupID, userID, role); err != nil {
+		return utils.NewInternalError("Failed to change member role", err)
+	}
+	return nil
+}
+
+// Feed returns the group's posts, newest first. Private groups are only
+// readable by their members.
+func (s *GroupService) Feed(ctx context.Context, groupID string, viewerID *string, limit, offset int) ([]*models.PostResponse, int64, error) {
+	group, err := s.getGroup(ctx, groupID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if group.Privacy == models.GroupPrivate {
+		viewer := ""
+		if viewerID != nil {
+			viewer = *viewerID
+		}
+		role, err := s.activeRole(ctx, groupID, viewer)
+		if err != nil {
+			return nil, 0, err
+		}
+		if role == "" {
+			return nil, 0, utils.NewForbiddenError("Join this group to see its posts", nil)
+		}
+	}
+
+	return s.postService.GetFeed(ctx, &models.FeedFilter{
+		GroupID: &groupID,
+		SortBy:  "recent",
+		Limit:   limit,
+		Offset:  offset,
+	}, viewerID)
+}
+
+// RemovePost lets group admins and moderators take a post down from the
+// group. Authors delete their own posts through the regular post endpoint.
+func (s *GroupService) RemovePost(ctx context.Context, groupID, postID, actorID string) error {
+	if _, _, err := s.requireRole(ctx, groupID, actorID, models.GroupRole.CanModerate); err != nil {
+		return err
+	}
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil || post.GroupID == nil || *post.GroupID != groupID {
+		return utils.NewNotFoundError("Post not found", err)
+	}
+	if err := s.postRepo.Delete(ctx, postID); err != nil {
+		return utils.NewInternalError("Failed to remove post", err)
+	}
+	s.logger.Info("Group post removed",
+		zap.String("group_id", groupID), zap.String("post_id", postID), zap.String("actor_id", actorID))
+	return nil
+}
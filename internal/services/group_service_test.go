@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestGroupService(groupRepo *mocks.MockGroupRepository, postRepo *mocks.MockPostRepository) *GroupService {
+	postSvc := newTestPostService(postRepo, new(mocks.MockUserRepository)).WithGroups(groupRepo)
+	return NewGroupService(groupRepo, postRepo, postSvc, zap.NewNop())
+}
+
+func activeMember(groupID, userID string, role models.GroupRole) *models.GroupMember {
+	return &models.GroupMember{GroupID: groupID, UserID: userID, Role: role, Status: models.GroupMemberActive}
+}
+
+func TestGroupService_Create(t *testing.T) {
+	t.Run("neighborhood group needs a province", func(t *testing.T) {
+		groupRepo := new(mocks.MockGroupRepository)
+		svc := newTestGroupService(groupRepo, new(mocks.MockPostRepository))
+
+		_, err := svc.Create(context.Background(), "user-1", &models.CreateGroupRequest{Name: "Karte 3"})
+		requireAppErrCode(t, err, http.StatusBadRequest)
+		groupRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("creator becomes admin", func(t *testing.T) {
+		groupRepo := new(mocks.MockGroupRepository)
+		groupRepo.On("Create", mock.Anything, mock.MatchedBy(func(g *models.Group) bool {
+			return g.CreatedBy == "user-1" && g.Kind == models.GroupKindInterest && g.Privacy == models.GroupPublic
+		})).Return(nil)
+		svc := newTestGroupService(groupRepo, new(mocks.MockPostRepository))
+
+		got, err := svc.Create(context.Background(), "user-1",
+			&models.CreateGroupRequest{Name: "Kabul runners", Kind: models.GroupKindInterest})
+		require.NoError(t, err)
+		require.NotNil(t, got.ViewerRole)
+		assert.Equal(t, models.GroupRoleAdmin, *got.ViewerRole)
+		groupRepo.AssertExpectations(t)
+	})
+}
+
+func TestGroupService_Join(t *testing.T) {
+	t.Run("public group joins immediately", func(t *testing.T) {
+		groupRepo := new(mocks.MockGroupRepository)
+		groupRepo.On("GetByID", mock.Anything, "g-1").Return(&models.Group{ID: "g-1", Privacy: models.GroupPublic}, nil)
+		groupRepo.On("GetMember", mock.Anything, "g-1", "user-2").Return(nil, nil)
+		groupRepo.On("AddMember", mock.Anything, mock.MatchedBy(func(m *models.GroupMember) bool {
+			return m.Status == models.GroupMemberActive && m.Role == models.GroupRoleMember
+		})).Return(nil)
+		svc := newTestGroupService(groupRepo, new(mocks.MockPostRepository))
+
+		member, err := svc.Join(context.Background(), "g-1", "user-2")
+		require.NoError(t, err)
+		assert.Equal(t, models.GroupMemberActive, member.Status)
+	})
+
+	t.Run("private group files a request", func(t *testing.T) {
+		groupRepo := new(mocks.MockGroupRepository)
+		groupRepo.On("GetByID", mock.Anything, "g-1").Return(&models.Group{ID: "g-1", Privacy: models.GroupPrivate}, nil)
+		groupRepo.On("GetMember", mock.Anything, "g-1", "user-2").Return(nil, nil)
+		groupRepo.On("AddMember", mock.Anything, mock.Anything).Return(nil)
+		svc := newTestGroupService(groupRepo, new(mocks.MockPostRepository))
+
+		member, err := svc.Join(context.Background(), "g-1", "user-2")
+		require.NoError(t, err)
+		assert.Equal(t, models.GroupMemberPending, member.Status)
+	})
+}
+
+func TestGroupService_LastAdmin(t *testing.T) {
+	group := &models.Group{ID: "g-1", Privacy: models.GroupPublic}
+
+	t.Run("cannot leave", func(t *testing.T) {
+		groupRepo := new(mocks.MockGroupRepository)
+		groupRepo.On("GetByID", mock.Anything, "g-1").Return(group, nil)
+		groupRepo.On("GetMember", mock.Anything, "g-1", "admin-1").Return(activeMember("g-1", "admin-1", models.GroupRoleAdmin), nil)
+		groupRepo.On("CountAdmins", mock.Anything, "g-1").Return(1, nil)
+		svc := newTestGroupService(groupRepo, new(mocks.MockPostRepository))
+
+		err := svc.Leave(context.Background(), "g-1", "admin-1")
+		requireAppErrCode(t, err, http.StatusBadRequest)
+		groupRepo.AssertNotCalled(t, "RemoveMember", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("cannot demote themselves", func(t *testing.T) {
+		groupRepo := new(mocks.MockGroupRepository)
+		groupRepo.On("GetByID", mock.Anything, "g-1").Return(group, nil)
+		groupRepo.On("GetMember", mock.Anything, "g-1", "admin-1").Return(activeMember("g-1", "admin-1", models.GroupRoleAdmin), nil)
+		groupRepo.On("CountAdmins", mock.Anything, "g-1").Return(1, nil)
+		svc := newTestGroupService(groupRepo, new(mocks.MockPostRepository))
+
+		err := svc.SetRole(context.Background(), "g-1", "admin-1", "admin-1", models.GroupRoleMember)
+		requireAppErrCode(t, err, http.StatusBadRequest)
+	})
+}
+
+func TestGroupService_Moderation(t *testing.T) {
+	group := &models.Group{ID: "g-1", Privacy: models.GroupPrivate}
+
+	t.Run("members cannot approve requests", func(t *testing.T) {
+		groupRepo := new(mocks.MockGroupRepository)
+		groupRepo.On("GetByID", mock.Anything, "g-1").Return(group, nil)
+		groupRepo.On("GetMember", mock.Anything, "g-1", "user-1").Return(activeMember("g-1", "user-1", models.GroupRoleMember), nil)
+		svc := newTestGroupService(groupRepo, new(mocks.MockPostRepository))
+
+		err := svc.ApproveRequest(context.Background(), "g-1", "user-1", "user-2")
+		requireAppErrCode(t, err, http.StatusForbidden)
+	})
+
+	t.Run("moderators approve requests", func(t *testing.T) {
+		groupRepo := new(mocks.MockGroupRepository)
+		groupRepo.On("GetByID", mock.Anything, "g-1").Return(group, nil)
+		groupRepo.On("GetMember", mock.Anything, "g-1", "mod-1").Return(activeMember("g-1", "mod-1", models.GroupRoleModerator), nil)
+		groupRepo.On("ApproveMember", mock.Anything, "g-1", "user-2").Return(nil)
+		svc := newTestGroupService(groupRepo, new(mocks.MockPostRepository))
+
+		require.NoError(t, svc.ApproveRequest(context.Background(), "g-1", "mod-1", "user-2"))
+		groupRepo.AssertExpectations(t)
+	})
+
+	t.Run("moderators cannot remove admins", func(t *testing.T) {
+		groupRepo := new(mocks.MockGroupRepository)
+		groupRepo.On("GetByID", mock.Anything, "g-1").Return(group, nil)
+		groupRepo.On("GetMember", mock.Anything, "g-1", "mod-1").Return(activeMember("g-1", "mod-1", models.GroupRoleModerator), nil)
+		groupRepo.On("GetMember", mock.Anything, "g-1", "admin-1").Return(activeMember("g-1", "admin-1", models.GroupRoleAdmin), nil)
+		svc := newTestGroupService(groupRepo, new(mocks.MockPostRepository))
+
+		err := svc.RemoveMember(context.Background(), "g-1", "mod-1", "admin-1")
+		requireAppErrCode(t, err, http.StatusForbidden)
+	})
+
+	t.Run("post from another group is not found", func(t *testing.T) {
+		groupRepo := new(mocks.MockGroupRepository)
+		groupRepo.On("GetByID", mock.Anything, "g-1").Return(group, nil)
+		groupRepo.On("GetMember", mock.Anything, "g-1", "mod-1").Return(activeMember("g-1", "mod-1", models.GroupRoleModerator), nil)
+		postRepo := new(mocks.MockPostRepository)
+		post := testutil.CreateTestPost("post-1", "user-2", models.PostTypeFeed)
+		other := "g-2"
+		post.GroupID = &other
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(post, nil)
+		svc := newTestGroupService(groupRepo, postRepo)
+
+		err := svc.RemovePost(context.Background(), "g-1", "post-1", "mod-1")
+		requireAppErrCode(t, err, http.StatusNotFound)
+		postRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGroupService_Feed_PrivateNonMember(t *testing.T) {
+	groupRepo := new(mocks.MockGroupRepository)
+	groupRepo.On("GetByID", mock.Anything, "g-1").Return(&models.Group{ID: "g-1", Privacy: models.GroupPrivate}, nil)
+	groupRepo.On("GetMember", mock.Anything, "g-1", "user-9").Return(nil, nil)
+	postRepo := new(mocks.MockPostRepository)
+	svc := newTestGroupService(groupRepo, postRepo)
+
+	viewer := "user-9"
+	_, _, err := svc.Feed(context.Background(), "g-1", &viewer, 20, 0)
+	requireAppErrCode(t, err, http.StatusForbidden)
+	postRepo.AssertNotCalled(t, "GetFeed", mock.Anything, mock.Anything)
+}
+
+func TestPostService_GroupPosts(t *testing.T) {
+	t.Run("create requires active membership", func(t *testing.T) {
+		groupRepo := new(mocks.MockGroupRepository)
+		groupRepo.On("GetByID", mock.Anything, "g-1").Return(&models.Group{ID: "g-1", Privacy: models.GroupPublic}, nil)
+		groupRepo.On("GetMember", mock.Anything, "g-1", "user-1").
+			Return(&models.GroupMember{GroupID: "g-1", UserID: "user-1", Status: models.GroupMemberPending}, nil)
+		postRepo := new(mocks.MockPostRepository)
+		svc := newTestPostService(postRepo, new(mocks.MockUserRepository)).WithGroups(groupRepo)
+
+		desc := "Water cut tomorrow"
+		groupID := "g-1"
+		_, err := svc.CreatePost(context.Background(), "user-1", &models.CreatePostRequest{
+			Type: models.PostTypeFeed, Description: &desc, GroupID: &groupID,
+		})
+		requireAppErrCode(t, err, http.StatusForbidden)
+		postRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("private group post hidden from non-members", func(t *testing.T) {
+		groupRepo := new(mocks.MockGroupRepository)
+		groupRepo.On("GetByID", mock.Anything, "g-1").Return(&models.Group{ID: "g-1", Privacy: models.GroupPrivate}, nil)
+		groupRepo.On("GetMember", mock.Anything, "g-1", "user-9").Return(nil, nil)
+		postRepo := new(mocks.MockPostRepository)
+		post := testutil.CreateTestPost("post-1", "user-1", models.PostTypeFeed)
+		groupID := "g-1"
+		post.GroupID = &groupID
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(post, nil)
+		svc := newTestPostService(postRepo, new(mocks.MockUserRepository)).WithGroups(groupRepo)
+
+		viewer := "user-9"
+		_, err := svc.GetPost(context.Background(), "post-1", &viewer)
+		requireAppErrCode(t, err, http.StatusNotFound)
+	})
+}
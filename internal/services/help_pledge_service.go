@@ -0,0 +1,285 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/hamsaya/backend/pkg/bgtasks"
+	"go.uber.org/zap"
+)
+
+// HelpPledgeService runs pledges on HELP posts: neighbors pledge items or
+// time, the post owner confirms each delivered pledge and finally marks the
+// request fulfilled. Pledgers hear about confirmation, fulfillment and edits
+// to the request.
+type HelpPledgeService struct {
+	pledgeRepo          repositories.HelpPledgeRepository
+	postRepo            repositories.PostRepository
+	userRepo            repositories.UserRepository
+	notificationService *NotificationService
+	logger              *zap.Logger
+}
+
+// NewHelpPledgeService wires the pledge service.
+func NewHelpPledgeService(
+	pledgeRepo repositories.HelpPledgeRepository,
+	postRepo repositories.PostRepository,
+	userRepo repositories.UserRepository,
+	notificationService *NotificationService,
+	logger *zap.Logger,
+) *HelpPledgeService {
+	return &HelpPledgeService{
+		pledgeRepo:          pledgeRepo,
+		postRepo:            postRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+		logger:              logger,
+	}
+}
+
+func (s *HelpPledgeService) getHelpPost(ctx context.Context, postID string) (*models.Post, error) {
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, utils.NewNotFoundError("Post not found", err)
+	}
+	if post.Type != models.PostTypeHelp {
+		return nil, utils.NewBadRequestError("Pledges are only available on help requests", nil)
+	}
+	return post, nil
+}
+
+func isPostOwner(post *models.Post, userID string) bool {
+	return post.UserID != nil && *post.UserID == userID
+}
+
+func (s *HelpPledgeService) summary(ctx context.Context, postID, viewerID string) (*models.HelpSummary, error) {
+	summaries, err := s.pledgeRepo.GetSummaries(ctx, []string{postID}, viewerID)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load pledges", err)
+	}
+	if sum, ok := summaries[postID]; ok {
+		return sum, nil
+	}
+	return &models.HelpSummary{}, nil
+}
+
+// Pledge records the caller's offer on a HELP post and notifies the owner.
+// Pledging again while the earlier pledge is still open replaces its
+// details.
+func (s *HelpPledgeService) Pledge(ctx context.Context, postID, userID string, req *models.CreatePledgeRequest) (*models.HelpPledge, error) {
+	post, err := s.getHelpPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if isPostOwner(post, userID) {
+		return nil, utils.NewBadRequestError("You cannot pledge on your own request", nil)
+	}
+	sum, err := s.summary(ctx, postID, "")
+	if err != nil {
+		return nil, err
+	}
+	if sum.FulfilledAt != nil {
+		return nil, utils.NewBadRequestError("This request has already been fulfilled", nil)
+	}
+
+	existing, err := s.pledgeRepo.GetActive(ctx, postID, userID)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load pledge", err)
+	}
+	if existing != nil {
+		if existing.Status != models.PledgeStatusPledged {
+			return nil, utils.NewConflictError("Your pledge has already been fulfilled", nil)
+		}
+		existing.Kind, existing.Note, existing.Quantity = req.Kind, req.Note, req.Quantity
+		if err := s.pledgeRepo.UpdateDetails(ctx, existing); err != nil {
+			return nil, s.transitionError(err)
+		}
+		return existing, nil
+	}
+
+	pledge := &models.HelpPledge{
+		ID:       uuid.NewString(),
+		PostID:   postID,
+		UserID:   userID,
+		Kind:     req.Kind,
+		Note:     req.Note,
+		Quantity: req.Quantity,
+		Status:   models.PledgeStatusPledged,
+	}
+	if err := s.pledgeRepo.Create(ctx, pledge); err != nil {
+		if errors.Is(err, repositories.ErrPledgeExists) {
+			return nil, utils.NewConflictError("You have already pledged on this request", err)
+		}
+		s.logger.Error("Failed to create pledge", zap.String("post_id", postID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to pledge", err)
+	}
+
+	if post.UserID != nil {
+		s.notify([]string{*post.UserID}, userID, models.NotificationTypeHelpPledge, post, "pledged to help with")
+	}
+	return pledge, nil
+}
+
+// Withdraw takes back the caller's open pledge. Confirmed pledges stay.
+func (s *HelpPledgeService) Withdraw(ctx context.Context, postID, userID string) error {
+	if _, err := s.getHelpPost(ctx, postID); err != nil {
+		return err
+	}
+	pledge, err := s.pledgeRepo.GetActive(ctx, postID, userID)
+	if err != nil {
+		return utils.NewInternalError("Failed to load pledge", err)
+	}
+	if pledge == nil {
+		return utils.NewNotFoundError("Pledge not found", nil)
+	}
+	if _, err := s.pledgeRepo.Transition(ctx, pledge.ID,
+		[]models.PledgeStatus{models.PledgeStatusPledged}, models.PledgeStatusWithdrawn); err != nil {
+		return s.transitionError(err)
+	}
+	return nil
+}
+
+// List returns the live pledges on a HELP post, oldest first.
+func (s *HelpPledgeService) List(ctx context.Context, postID string, limit, offset int) ([]*models.HelpPledgeWithProfile, int, error) {
+	if _, err := s.getHelpPost(ctx, postID); err != nil {
+		return nil, 0, err
+	}
+	pledges, total, err := s.pledgeRepo.ListByPost(ctx, postID, limit, offset)
+	if err != nil {
+		return nil, 0, utils.NewInternalError("Failed to list pledges", err)
+	}
+	return pledges, total, nil
+}
+
+// ConfirmPledge marks one pledge as delivered (post owner only) and thanks
+// the pledger.
+func (s *HelpPledgeService) ConfirmPledge(ctx context.Context, postID, pledgeID, ownerID string) (*models.HelpPledge, error) {
+	post, err := s.getHelpPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if !isPostOwner(post, ownerID) {
+		return nil, utils.NewForbiddenError("Only the author can confirm pledges", nil)
+	}
+	pledge, err := s.pledgeRepo.GetByID(ctx, pledgeID)
+	if errors.Is(err, repositories.ErrPledgeNotFound) || (err == nil && pledge.PostID != postID) {
+		return nil, utils.NewNotFoundError("Pledge not found", err)
+	}
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load pledge", err)
+	}
+
+	updated, err := s.pledgeRepo.Transition(ctx, pledgeID,
+		[]models.PledgeStatus{models.PledgeStatusPledged}, models.PledgeStatusFulfilled)
+	if err != nil {
+		return nil, s.transitionError(err)
+	}
+	s.notify([]string{updated.UserID}, ownerID, models.NotificationTypeHelpPledgeConfirmed, post,
+		"confirmed your pledge on")
+	return updated, nil
+}
+
+// MarkFulfilled closes the request (post owner only) and tells every
+// pledger. New pledges are refused afterwards.
+func (s *HelpPledgeService) MarkFulfilled(ctx context.Context, postID, ownerID string) (*models.HelpSummary, error) {
+	post, err := s.getHelpPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if !isPostOwner(post, ownerID) {
+		return nil, utils.NewForbiddenError("Only the author can mark this request fulfilled", nil)
+	}
+	if _, err := s.pledgeRepo.MarkFulfilled(ctx, postID); err != nil {
+		if errors.Is(err, repositories.ErrHelpAlreadyFulfilled) {
+			return nil, utils.NewConflictError("This request is already fulfilled", err)
+		}
+		return nil, utils.NewInternalError("Failed to mark request fulfilled", err)
+	}
+	s.logger.Info("Help request fulfilled", zap.String("post_id", postID), zap.String("user_id", ownerID))
+
+	s.notifyPledgers(post, ownerID, models.NotificationTypeHelpRequestFulfilled, "marked as fulfilled")
+	return s.summary(ctx, postID, ownerID)
+}
+
+// NotifyUpdated tells pledgers that the owner edited the request, so they
+// can check whether their pledge still fits.
+func (s *HelpPledgeService) NotifyUpdated(post *models.Post) {
+	if post.UserID == nil {
+		return
+	}
+	s.notifyPledgers(post, *post.UserID, models.NotificationTypeHelpRequestUpdated, "updated")
+}
+
+// SummariesForPosts loads the pledge summary for each HELP post in the batch.
+func (s *HelpPledgeService) SummariesForPosts(ctx context.Context, postIDs []string, viewerID string) (map[string]*models.HelpSummary, error) {
+	return s.pledgeRepo.GetSummaries(ctx, postIDs, viewerID)
+}
+
+func (s *HelpPledgeService) transitionError(err error) error {
+	if errors.Is(err, repositories.ErrPledgeConflict) {
+		return utils.NewConflictError("Pledge can no longer be changed", err)
+	}
+	return utils.NewInternalError("Failed to update pledge", err)
+}
+
+// notifyPledgers fans a request-level update out to everyone with a live
+// pledge, in the background.
+func (s *HelpPledgeService) notifyPledgers(post *models.Post, actorID string, notifType models.NotificationType, verb string) {
+	if s.notificationService == nil {
+		return
+	}
+	bgtasks.Submit(func(ctx context.Context) {
+		pledgers, err := s.pledgeRepo.ListPledgerIDs(ctx, post.ID)
+		if err != nil {
+			s.logger.Warn("Failed to load pledgers", zap.String("post_id", post.ID), zap.Error(err))
+			return
+		}
+		s.send(ctx, pledgers, actorID, notifType, post, verb+" a request you pledged on")
+	})
+}
+
+// notify sends a best-effort pledge notification in the background.
+func (s *HelpPledgeService) notify(recipients []string, actorID string, notifType models.NotificationType, post *models.Post, verb string) {
+	if s.notificationService == nil {
+		return
+	}
+	bgtasks.Submit(func(ctx context.Context) {
+		s.send(ctx, recipients, actorID, notifType, post, verb+" your request")
+	})
+}
+
+func (s *HelpPledgeService) send(ctx context.Context, recipients []string, actorID string, notifType models.NotificationType, post *models.Post, verb string) {
+	actorName := ""
+	if actor, err := s.userRepo.GetProfileByUserID(ctx, actorID); err == nil && actor != nil {
+		actorName = actor.FullName()
+	}
+	title := strings.TrimSpace(actorName + " " + verb)
+	var msg *string
+	if post.Title != nil && *post.Title != "" {
+		msg = post.Title
+	}
+	for _, recipientID := range recipients {
+		if recipientID == actorID {
+			continue
+		}
+		if _, err := s.notificationService.CreateNotification(ctx, &models.CreateNotificationRequest{
+			UserID:  recipientID,
+			Type:    notifType,
+			Title:   &title,
+			Message: msg,
+			Data: map[string]interface{}{
+				"actor_id":   actorID,
+				"actor_name": actorName,
+				"post_id":    post.ID,
+			},
+		}); err != nil {
+			s.logger.Warn("Failed to send pledge notification",
+				zap.String("post_id", post.ID), zap.String("type", string(notifType)), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestPledgeService(pledgeRepo *mocks.MockHelpPledgeRepository, postRepo *mocks.MockPostRepository) *HelpPledgeService {
+	// notificationService is nil so notify is a no-op.
+	return NewHelpPledgeService(pledgeRepo, postRepo, new(mocks.MockUserRepository), nil, zap.NewNop())
+}
+
+func helpPost(owner string) *models.Post {
+	return testutil.CreateTestPost("post-1", owner, models.PostTypeHelp)
+}
+
+func TestHelpPledgeService_Pledge(t *testing.T) {
+	req := &models.CreatePledgeRequest{Kind: models.PledgeKindItem, Quantity: ptrInt(2)}
+
+	t.Run("owner cannot pledge", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(helpPost("owner-1"), nil)
+		svc := newTestPledgeService(new(mocks.MockHelpPledgeRepository), postRepo)
+
+		_, err := svc.Pledge(context.Background(), "post-1", "owner-1", req)
+		requireAppErrCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("only help posts take pledges", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").
+			Return(testutil.CreateTestPost("post-1", "owner-1", models.PostTypeFeed), nil)
+		svc := newTestPledgeService(new(mocks.MockHelpPledgeRepository), postRepo)
+
+		_, err := svc.Pledge(context.Background(), "post-1", "user-2", req)
+		requireAppErrCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("fulfilled request refuses pledges", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(helpPost("owner-1"), nil)
+		pledgeRepo := new(mocks.MockHelpPledgeRepository)
+		now := time.Now()
+		pledgeRepo.On("GetSummaries", mock.Anything, []string{"post-1"}, "").
+			Return(map[string]*models.HelpSummary{"post-1": {FulfilledAt: &now}}, nil)
+		svc := newTestPledgeService(pledgeRepo, postRepo)
+
+		_, err := svc.Pledge(context.Background(), "post-1", "user-2", req)
+		requireAppErrCode(t, err, http.StatusBadRequest)
+		pledgeRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("creates a pledge", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(helpPost("owner-1"), nil)
+		pledgeRepo := new(mocks.MockHelpPledgeRepository)
+		pledgeRepo.On("GetSummaries", mock.Anything, []string{"post-1"}, "").
+			Return(map[string]*models.HelpSummary{"post-1": {}}, nil)
+		pledgeRepo.On("GetActive", mock.Anything, "post-1", "user-2").Return(nil, nil)
+		pledgeRepo.On("Create", mock.Anything, mock.MatchedBy(func(p *models.HelpPledge) bool {
+			return p.UserID == "user-2" && p.Status == models.PledgeStatusPledged && *p.Quantity == 2
+		})).Return(nil)
+		svc := newTestPledgeService(pledgeRepo, postRepo)
+
+		got, err := svc.Pledge(context.Background(), "post-1", "user-2", req)
+		require.NoError(t, err)
+		assert.Equal(t, models.PledgeKindItem, got.Kind)
+		pledgeRepo.AssertExpectations(t)
+	})
+
+	t.Run("pledging again updates the open pledge", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(helpPost("owner-1"), nil)
+		pledgeRepo := new(mocks.MockHelpPledgeRepository)
+		pledgeRepo.On("GetSummaries", mock.Anything, []string{"post-1"}, "").
+			Return(map[string]*models.HelpSummary{"post-1": {}}, nil)
+		existing := &models.HelpPledge{ID: "pl-1", PostID: "post-1", UserID: "user-2",
+			Kind: models.PledgeKindTime, Status: models.PledgeStatusPledged}
+		pledgeRepo.On("GetActive", mock.Anything, "post-1", "user-2").Return(existing, nil)
+		pledgeRepo.On("UpdateDetails", mock.Anything, existing).Return(nil)
+		svc := newTestPledgeService(pledgeRepo, postRepo)
+
+		got, err := svc.Pledge(context.Background(), "post-1", "user-2", req)
+		require.NoError(t, err)
+		assert.Equal(t, "pl-1", got.ID)
+		assert.Equal(t, models.PledgeKindItem, got.Kind)
+		pledgeRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestHelpPledgeService_OwnerActions(t *testing.T) {
+	t.Run("only the owner confirms", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(helpPost("owner-1"), nil)
+		svc := newTestPledgeService(new(mocks.MockHelpPledgeRepository), postRepo)
+
+		_, err := svc.ConfirmPledge(context.Background(), "post-1", "pl-1", "user-2")
+		requireAppErrCode(t, err, http.StatusForbidden)
+	})
+
+	t.Run("pledge from another post is not found", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(helpPost("owner-1"), nil)
+		pledgeRepo := new(mocks.MockHelpPledgeRepository)
+		pledgeRepo.On("GetByID", mock.Anything, "pl-1").Return(&models.HelpPledge{ID: "pl-1", PostID: "post-9"}, nil)
+		svc := newTestPledgeService(pledgeRepo, postRepo)
+
+		_, err := svc.ConfirmPledge(context.Background(), "post-1", "pl-1", "owner-1")
+		requireAppErrCode(t, err, http.StatusNotFound)
+	})
+
+	t.Run("confirm moves pledge to fulfilled", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(helpPost("owner-1"), nil)
+		pledgeRepo := new(mocks.MockHelpPledgeRepository)
+		pledgeRepo.On("GetByID", mock.Anything, "pl-1").Return(&models.HelpPledge{ID: "pl-1", PostID: "post-1"}, nil)
+		pledgeRepo.On("Transition", mock.Anything, "pl-1",
+			[]models.PledgeStatus{models.PledgeStatusPledged}, models.PledgeStatusFulfilled).
+			Return(&models.HelpPledge{ID: "pl-1", PostID: "post-1", UserID: "user-2", Status: models.PledgeStatusFulfilled}, nil)
+		svc := newTestPledgeService(pledgeRepo, postRepo)
+
+		got, err := svc.ConfirmPledge(context.Background(), "post-1", "pl-1", "owner-1")
+		require.NoError(t, err)
+		assert.Equal(t, models.PledgeStatusFulfilled, got.Status)
+	})
+
+	t.Run("fulfilling twice conflicts", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(helpPost("owner-1"), nil)
+		pledgeRepo := new(mocks.MockHelpPledgeRepository)
+		pledgeRepo.On("MarkFulfilled", mock.Anything, "post-1").
+			Return(time.Time{}, repositories.ErrHelpAlreadyFulfilled)
+		svc := newTestPledgeService(pledgeRepo, postRepo)
+
+		_, err := svc.MarkFulfilled(context.Background(), "post-1", "owner-1")
+		requireAppErrCode(t, err, http.StatusConflict)
+	})
+}
+
+func TestPostService_ValidateHelpPost(t *testing.T) {
+	svc := newTestPostService(new(mocks.MockPostRepository), new(mocks.MockUserRepository))
+
+	err := svc.validatePostRequest(&models.CreatePostRequest{Type: models.PostTypeHelp, Description: ptrStr("Need blankets")})
+	requireAppErrCode(t, err, http.StatusBadRequest)
+
+	err = svc.validatePostRequest(&models.CreatePostRequest{
+		Type: models.PostTypeHelp, Title: ptrStr("Winter blankets"), Description: ptrStr("For a family of five"),
+	})
+	assert.NoError(t, err)
+}
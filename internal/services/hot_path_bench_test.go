@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/stretchr/testify/mock"
+)
+
+// Benchmarks for the request paths that carry most traffic: feed, search and
+// chat send. Repositories are mocks, so these measure the service-side work
+// (visibility filtering, batch enrichment, response building) that every
+// page pays on top of SQL. cmd/loadgen benchcheck compares their
+// allocs/op and ns/op against tests/load/bench_baseline.json.
+
+// benchPage is a feed-sized page: 20 posts from 10 authors, a mix of types,
+// two attachments each.
+func benchPage() ([]*models.Post, []*models.Profile, map[string][]*models.Attachment) {
+	posts := make([]*models.Post, 0, 20)
+	profiles := make([]*models.Profile, 0, 10)
+	attachments := make(map[string][]*models.Attachment, 20)
+	types := []models.PostType{models.PostTypeFeed, models.PostTypeSell, models.PostTypeEvent, models.PostTypeFeed}
+	for i := 0; i < 10; i++ {
+		profiles = append(profiles, testutil.CreateTestProfile(fmt.Sprintf("user-%d", i), "First", "Last"))
+	}
+	for i := 0; i < 20; i++ {
+		post := testutil.CreateTestPost(fmt.Sprintf("post-%d", i), fmt.Sprintf("user-%d", i%10), types[i%len(types)])
+		if post.Type == models.PostTypeSell {
+			category := "cat-1"
+			post.CategoryID = &category
+		}
+		posts = append(posts, post)
+		attachments[post.ID] = []*models.Attachment{
+			{ID: post.ID + "-a1", PostID: post.ID},
+			{ID: post.ID + "-a2", PostID: post.ID},
+		}
+	}
+	return posts, profiles, attachments
+}
+
+func BenchmarkPostService_GetFeed(b *testing.B) {
+	posts, profiles, attachments := benchPage()
+	viewer := "viewer-1"
+
+	postRepo := new(mocks.MockPostRepository)
+	userRepo := new(mocks.MockUserRepository)
+	svc := newTestPostService(postRepo, userRepo)
+	relRepo := svc.authorizer.relationshipsRepo.(*mocks.MockRelationshipsRepository)
+	categoryRepo := svc.categoryRepo.(*mocks.MockCategoryRepository)
+	eventRepo := svc.eventRepo.(*mocks.MockEventRepository)
+
+	userRepo.On("GetContentLanguages", mock.Anything, viewer).Return([]string{}, nil)
+	userRepo.On("GetProfilesByUserIDs", mock.Anything, mock.Anything).Return(profiles, nil)
+	relRepo.On("GetRelationshipStatuses", mock.Anything, viewer, mock.Anything).
+		Return(map[string]*models.RelationshipStatus{}, nil)
+	categoryRepo.On("GetByIDs", mock.Anything, mock.Anything).
+		Return([]*models.SellCategory{{ID: "cat-1", Name: "Electronics"}}, nil)
+	eventRepo.On("GetUserInterestsByPostIDs", mock.Anything, viewer, mock.Anything).
+		Return(map[string]*models.EventInterest{}, nil)
+	postRepo.On("CountFeed", mock.Anything, mock.Anything).Return(int64(1000), nil)
+	postRepo.On("GetFeed", mock.Anything, mock.Anything).Return(posts, nil)
+	postRepo.On("GetAttachmentsByPostIDs", mock.Anything, mock.Anything).Return(attachments, nil)
+	postRepo.On("GetEngagementStatusBatch", mock.Anything, viewer, mock.Anything).
+		Return(map[string]struct{}{"post-1": {}}, map[string]struct{}{}, nil)
+
+	ctx := context.Background()
+	if page, _, err := svc.GetFeed(ctx, &models.FeedFilter{Limit: 20}, &viewer); err != nil || len(page) != len(posts) {
+		b.Fatalf("feed returned %d posts, err %v", len(page), err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := svc.GetFeed(ctx, &models.FeedFilter{Limit: 20}, &viewer); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSearchService_SearchPosts(b *testing.B) {
+	posts, profiles, attachments := benchPage()
+
+	searchRepo := &mocks.MockSearchRepository{}
+	postRepo := &mocks.MockPostRepository{}
+	userRepo := new(mocks.MockUserRepository)
+	categoryRepo := &mocks.MockCategoryRepository{}
+	svc := newTestSearchService(searchRepo, postRepo, userRepo, &mocks.MockBusinessRepository{}, categoryRepo, &mocks.MockRelationshipsRepository{})
+
+	searchRepo.On("SearchPosts", mock.Anything, mock.Anything).Return(posts, nil)
+	searchRepo.On("GetPostHighlights", mock.Anything, mock.Anything, "kabul").
+		Return(map[string]*models.SearchHighlight{}, nil)
+	userRepo.On("GetProfilesByUserIDs", mock.Anything, mock.Anything).Return(profiles, nil).Maybe()
+	userRepo.On("GetProfileByUserID", mock.Anything, mock.Anything).Return(profiles[0], nil).Maybe()
+	categoryRepo.On("GetByIDs", mock.Anything, mock.Anything).
+		Return([]*models.SellCategory{{ID: "cat-1", Name: "Electronics"}}, nil).Maybe()
+	categoryRepo.On("GetByID", mock.Anything, mock.Anything).
+		Return(&models.SellCategory{ID: "cat-1", Name: "Electronics"}, nil).Maybe()
+	postRepo.On("GetAttachmentsByPostIDs", mock.Anything, mock.Anything).Return(attachments, nil).Maybe()
+	postRepo.On("GetAttachmentsByPostID", mock.Anything, mock.Anything).Return(attachments["post-0"], nil).Maybe()
+
+	ctx := context.Background()
+	if resp, err := svc.Search(ctx, nil, &models.SearchRequest{Query: "kabul", Type: models.SearchTypePosts, Limit: 20}); err != nil || len(resp.Posts) != len(posts) {
+		b.Fatalf("search returned %v, err %v", resp, err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := svc.Search(ctx, nil, &models.SearchRequest{Query: "kabul", Type: models.SearchTypePosts, Limit: 20}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkChatService_SendMessage(b *testing.B) {
+	convRepo := &mocks.MockConversationRepository{}
+	msgRepo := &mocks.MockMessageRepository{}
+	userRepo := new(mocks.MockUserRepository)
+	svc := newTestChatService(convRepo, msgRepo, userRepo)
+
+	convRepo.On("GetOrCreate", mock.Anything, "sender-1", "recv-1", mock.Anything).
+		Return(newTestConversation("conv-1"), nil)
+	convRepo.On("UpdateLastMessageAt", mock.Anything, "conv-1").Return(nil)
+	msgRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Message")).Return(nil)
+	msgRepo.On("GetReactions", mock.Anything, mock.Anything, mock.Anything).
+		Return(map[string][]models.MessageReaction{}, nil).Maybe()
+	userRepo.On("GetProfileByUserID", mock.Anything, "sender-1").
+		Return(testutil.CreateTestProfile("sender-1", "First", "Last"), nil)
+
+	ctx := context.Background()
+	content := "Is this still available?"
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := svc.SendMessage(ctx, "sender-1", &models.SendMessageRequest{
+			RecipientID: "recv-1",
+			MessageType: models.MessageTypeText,
+			Content:     &content,
+		}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
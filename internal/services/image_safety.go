@@ -0,0 +1,244 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"io"
+
+	"github.com/disintegration/imaging"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/hamsaya/backend/pkg/storage"
+	"go.uber.org/zap"
+)
+
+// Upload safety limits. They are checked from the image header before any
+// pixel data is decoded, so a small file that inflates to gigabytes of
+// pixels (a decompression bomb) is rejected cheaply.
+const (
+	// maxImageSide covers panoramas and scanned documents.
+	maxImageSide = 12000
+	// maxImagePixels allows 48 MP phone cameras.
+	maxImagePixels = 50_000_000
+	// maxGIFFrames caps animated GIFs; every frame is decoded in memory.
+	maxGIFFrames = 100
+	// maxAnimatedGIFSide caps animated GIFs, which are stored without
+	// resizing.
+	maxAnimatedGIFSide = 1024
+)
+
+// errMalformedGIF is returned by gifFrameCount for data that isn't a GIF.
+var errMalformedGIF = errors.New("malformed GIF")
+
+// preparedImage is an upload ready to store: re-encoded bytes with every
+// metadata block (EXIF, GPS, XMP, comments) dropped.
+type preparedImage struct {
+	data        []byte
+	img         image.Image // first frame for animated GIFs
+	format      string
+	contentType string
+}
+
+// inspectImage reads the image header and checks the format and the
+// dimension caps before the image is decoded.
+func (s *StorageService) inspectImage(data []byte) (string, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", utils.NewBadRequestError("Invalid image file", err)
+	}
+	if !s.isValidImageType(mimeFromFormat(format)) {
+		return "", utils.NewBadRequestError(
+			fmt.Sprintf("Decoded image format %q is not allowed. Only JPEG, PNG, WebP, and GIF are accepted", format), nil)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return "", utils.NewBadRequestError("Invalid image file", nil)
+	}
+	if cfg.Width > maxImageSide || cfg.Height > maxImageSide || cfg.Width*cfg.Height > maxImagePixels {
+		return "", utils.NewBadRequestError(
+			fmt.Sprintf("Image dimensions %dx%d exceed the %dx%d limit", cfg.Width, cfg.Height, maxImageSide, maxImageSide), nil)
+	}
+	if format == "gif" {
+		frames, err := gifFrameCount(data)
+		if err != nil {
+			return "", utils.NewBadRequestError("Invalid image file", err)
+		}
+		if frames > maxGIFFrames {
+			return "", utils.NewBadRequestError(
+				fmt.Sprintf("Animated GIF has %d frames; at most %d are allowed", frames, maxGIFFrames), nil)
+		}
+	}
+	return format, nil
+}
+
+// prepareImage decodes, processes and re-encodes an upload. The re-encode is
+// what strips EXIF and GPS data, so nothing may store the submitted bytes
+// directly. The EXIF orientation is applied before it is lost, so phone
+// photos keep their rotation.
+//
+// Animated GIFs stay animated on posts; elsewhere, and for still GIFs, the
+// first frame is stored as PNG.
+func (s *StorageService) prepareImage(data []byte, format string, imageType ImageType) (*preparedImage, error) {
+	if format == "gif" && imageType == ImageTypePost {
+		if prepared, err := prepareAnimatedGIF(data); prepared != nil || err != nil {
+			return prepared, err
+		}
+	}
+
+	img, err := imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(true))
+	if err != nil {
+		s.logger.Error("Failed to decode image", zap.Error(err))
+		return nil, utils.NewBadRequestError("Invalid image file", err)
+	}
+
+	// Process image based on type
+	var processedImg image.Image
+	switch imageType {
+	case ImageTypeAvatar:
+		// Process for avatar (crop to square, resize to 400x400)
+		processedImg, err = s.processor.ProcessForAvatar(img, 400)
+		if err != nil {
+			return nil, utils.NewInternalError("Failed to process avatar image", err)
+		}
+	case ImageTypeCover:
+		// Process for cover (resize to fit within 1600x900)
+		processedImg, err = s.processor.ProcessForCover(img, 1600, 900)
+		if err != nil {
+			return nil, utils.NewInternalError("Failed to process cover image", err)
+		}
+	case ImageTypePost, ImageTypeVerification:
+		// Process for post (resize to fit within 2048x2048). Verification
+		// documents use the same processing — only the key prefix differs.
+		processedImg, err = s.processor.ProcessForPost(img)
+		if err != nil {
+			return nil, utils.NewInternalError("Failed to process post image", err)
+		}
+	case ImageTypeAd:
+		// Process for ad (resize to fit within 2048x2048, same as post). The
+		// encode step below forces WebP regardless of source format.
+		processedImg, err = s.processor.ProcessForPost(img)
+		if err != nil {
+			return nil, utils.NewInternalError("Failed to process ad image", err)
+		}
+	default:
+		processedImg = img
+	}
+
+	// Force WebP for ads so served bytes are small regardless of source codec.
+	encodeFormat := format
+	switch {
+	case imageType == ImageTypeAd:
+		encodeFormat = "webp"
+	case format == "gif":
+		encodeFormat = "png"
+	}
+
+	reader, err := storage.EncodeImage(processedImg, encodeFormat)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to encode image", err)
+	}
+	encoded, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to read encoded image", err)
+	}
+
+	return &preparedImage{
+		data:        encoded,
+		img:         processedImg,
+		format:      encodeFormat,
+		contentType: mimeFromFormat(encodeFormat),
+	}, nil
+}
+
+// prepareAnimatedGIF re-encodes an animated GIF frame by frame, which drops
+// its comment and application blocks. Returns nil for a single-frame GIF,
+// which is stored like any still image.
+func prepareAnimatedGIF(data []byte) (*preparedImage, error) {
+	g, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		return nil, utils.NewBadRequestError("Invalid image file", err)
+	}
+	if len(g.Image) < 2 {
+		return nil, nil
+	}
+	if g.Config.Width > maxAnimatedGIFSide || g.Config.Height > maxAnimatedGIFSide {
+		return nil, utils.NewBadRequestError(
+			fmt.Sprintf("Animated GIFs must be at most %dx%d pixels", maxAnimatedGIFSide, maxAnimatedGIFSide), nil)
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, &gif.GIF{
+		Image:           g.Image,
+		Delay:           g.Delay,
+		LoopCount:       g.LoopCount,
+		Disposal:        g.Disposal,
+		Config:          g.Config,
+		BackgroundIndex: g.BackgroundIndex,
+	}); err != nil {
+		return nil, utils.NewInternalError("Failed to encode image", err)
+	}
+	return &preparedImage{
+		data:        buf.Bytes(),
+		img:         g.Image[0],
+		format:      "gif",
+		contentType: "image/gif",
+	}, nil
+}
+
+// gifFrameCount counts the frames of a GIF by walking its blocks without
+// decompressing any pixel data. A truncated file returns the frames seen so
+// far; the decoder rejects it later.
+func gifFrameCount(data []byte) (int, error) {
+	const (
+		extensionIntroducer = 0x21
+		imageSeparator      = 0x2C
+		trailer             = 0x3B
+	)
+	if len(data) < 13 || !bytes.HasPrefix(data, []byte("GIF8")) {
+		return 0, errMalformedGIF
+	}
+	pos := 13
+	if flags := data[10]; flags&0x80 != 0 {
+		pos += 3 << (flags&0x07 + 1) // global color table
+	}
+
+	frames := 0
+	for pos < len(data) {
+		switch data[pos] {
+		case imageSeparator:
+			if pos+10 > len(data) {
+				return frames, nil
+			}
+			flags := data[pos+9]
+			pos += 10
+			if flags&0x80 != 0 {
+				pos += 3 << (flags&0x07 + 1) // local color table
+			}
+			pos++ // LZW minimum code size
+			pos = skipGIFSubBlocks(data, pos)
+			frames++
+		case extensionIntroducer:
+			pos = skipGIFSubBlocks(data, pos+2)
+		case trailer:
+			return frames, nil
+		default:
+			return 0, errMalformedGIF
+		}
+	}
+	return frames, nil
+}
+
+// skipGIFSubBlocks returns the position after the data sub-blocks starting
+// at pos.
+func skipGIFSubBlocks(data []byte, pos int) int {
+	for pos < len(data) {
+		n := int(data[pos])
+		pos++
+		if n == 0 {
+			break
+		}
+		pos += n
+	}
+	return pos
+}
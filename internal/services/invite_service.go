@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	// inviteCodeLength and inviteCodeAlphabet make codes people can read
+	// out and type: no 0/O or 1/I/L lookalikes.
+	inviteCodeLength   = 8
+	inviteCodeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+	// maxTopReferrers caps the admin report.
+	maxTopReferrers = 100
+)
+
+// InviteService hands out per-user invite codes and attributes signups made
+// with them to the inviter.
+type InviteService struct {
+	inviteRepo repositories.InviteRepository
+	inviteURL  string
+	logger     *zap.Logger
+}
+
+// NewInviteService wires the invite service. inviteURL is the landing page
+// the code is appended to; empty uses the public site.
+func NewInviteService(inviteRepo repositories.InviteRepository, inviteURL string, logger *zap.Logger) *InviteService {
+	if inviteURL == "" {
+		inviteURL = "https://hamsaya.af/invite/"
+	}
+	return &InviteService{
+		inviteRepo: inviteRepo,
+		inviteURL:  inviteURL,
+		logger:     logger,
+	}
+}
+
+func generateInviteCode() (string, error) {
+	max := big.NewInt(int64(len(inviteCodeAlphabet)))
+	buf := make([]byte, inviteCodeLength)
+	for i := range buf {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		buf[i] = inviteCodeAlphabet[n.Int64()]
+	}
+	return string(buf), nil
+}
+
+// normalizeInviteCode accepts codes typed in lower case or pasted with
+// surrounding space.
+func normalizeInviteCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// GetMyInvite returns the user's invite code, creating it on first use,
+// with the link to share and how many signups it has brought in.
+func (s *InviteService) GetMyInvite(ctx context.Context, userID string) (*models.InviteLink, error) {
+	code, err := s.inviteRepo.GetCode(ctx, userID)
+	if errors.Is(err, repositories.ErrInviteCodeNotFound) {
+		// Retry the rare collision with another user's code.
+		for attempt := 0; attempt < 3; attempt++ {
+			fresh, gErr := generateInviteCode()
+			if gErr != nil {
+				return nil, utils.NewInternalError("Failed to create invite code", gErr)
+			}
+			code, err = s.inviteRepo.CreateCode(ctx, userID, fresh)
+			if !errors.Is(err, repositories.ErrInviteCodeTaken) {
+				break
+			}
+		}
+	}
+	if err != nil {
+		s.logger.Error("Failed to get invite code", zap.String("user_id", userID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to get invite code", err)
+	}
+
+	count, err := s.inviteRepo.CountReferrals(ctx, userID)
+	if err != nil {
+		s.logger.Warn("Failed to count referrals", zap.String("user_id", userID), zap.Error(err))
+	}
+	return &models.InviteLink{
+		Code:         code,
+		URL:          strings.TrimRight(s.inviteURL, "/") + "/" + code,
+		InvitesCount: count,
+	}, nil
+}
+
+// Attribute records that the newly registered user signed up with the
+// invite code. It is best-effort: an unknown or stale code must not fail
+// the signup, so problems are only logged. A nil service or empty code
+// does nothing.
+func (s *InviteService) Attribute(ctx context.Context, newUserID string, code *string) {
+	if s == nil || code == nil {
+		return
+	}
+	normalized := normalizeInviteCode(*code)
+	if normalized == "" {
+		return
+	}
+	referrerID, err := s.inviteRepo.GetUserIDByCode(ctx, normalized)
+	if err != nil {
+		if errors.Is(err, repositories.ErrInviteCodeNotFound) {
+			s.logger.Info("Signup used an unknown invite code", zap.String("user_id", newUserID), zap.String("code", normalized))
+		} else {
+			s.logger.Warn("Failed to resolve invite code", zap.String("user_id", newUserID), zap.Error(err))
+		}
+		return
+	}
+	if referrerID == newUserID {
+		return
+	}
+	created, err := s.inviteRepo.CreateReferral(ctx, &models.Referral{
+		ReferredUserID: newUserID,
+		ReferrerID:     referrerID,
+		Code:           normalized,
+	})
+	if err != nil {
+		s.logger.Warn("Failed to record referral", zap.String("user_id", newUserID), zap.String("referrer_id", referrerID), zap.Error(err))
+		return
+	}
+	if created {
+		s.logger.Info("Referral recorded", zap.String("user_id", newUserID), zap.String("referrer_id", referrerID))
+	}
+}
+
+// TopReferrers returns the users who brought in the most signups over the
+// last days (all time when days is 0).
+func (s *InviteService) TopReferrers(ctx context.Context, days, limit int) ([]*models.TopReferrer, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > maxTopReferrers {
+		limit = maxTopReferrers
+	}
+	var since *time.Time
+	if days > 0 {
+		t := time.Now().AddDate(0, 0, -days)
+		since = &t
+	}
+	top, err := s.inviteRepo.TopReferrers(ctx, since, limit)
+	if err != nil {
+		s.logger.Error("Failed to load top referrers", zap.Error(err))
+		return nil, utils.NewInternalError("Failed to load top referrers", err)
+	}
+	return top, nil
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestInviteService_GetMyInvite(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("existing code", func(t *testing.T) {
+		repo := new(mocks.MockInviteRepository)
+		repo.On("GetCode", ctx, "user-1").Return("ABCD2345", nil)
+		repo.On("CountReferrals", ctx, "user-1").Return(3, nil)
+		svc := NewInviteService(repo, "https://example.com/invite/", zap.NewNop())
+
+		invite, err := svc.GetMyInvite(ctx, "user-1")
+		require.NoError(t, err)
+		assert.Equal(t, "ABCD2345", invite.Code)
+		assert.Equal(t, "https://example.com/invite/ABCD2345", invite.URL)
+		assert.Equal(t, 3, invite.InvitesCount)
+		repo.AssertNotCalled(t, "CreateCode", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("first use retries a taken code", func(t *testing.T) {
+		repo := new(mocks.MockInviteRepository)
+		repo.On("GetCode", ctx, "user-1").Return("", repositories.ErrInviteCodeNotFound)
+		repo.On("CreateCode", ctx, "user-1", mock.AnythingOfType("string")).Return("", repositories.ErrInviteCodeTaken).Once()
+		repo.On("CreateCode", ctx, "user-1", mock.MatchedBy(func(code string) bool {
+			return len(code) == inviteCodeLength && strings.Trim(code, inviteCodeAlphabet) == ""
+		})).Return("QRST6789", nil).Once()
+		repo.On("CountReferrals", ctx, "user-1").Return(0, nil)
+		svc := NewInviteService(repo, "", zap.NewNop())
+
+		invite, err := svc.GetMyInvite(ctx, "user-1")
+		require.NoError(t, err)
+		assert.Equal(t, "QRST6789", invite.Code)
+		assert.Equal(t, "https://hamsaya.af/invite/QRST6789", invite.URL)
+		repo.AssertNumberOfCalls(t, "CreateCode", 2)
+	})
+
+	t.Run("storage failure", func(t *testing.T) {
+		repo := new(mocks.MockInviteRepository)
+		repo.On("GetCode", ctx, "user-1").Return("", errors.New("db down"))
+		svc := NewInviteService(repo, "", zap.NewNop())
+
+		_, err := svc.GetMyInvite(ctx, "user-1")
+		requireAppErrorCode(t, err, http.StatusInternalServerError)
+	})
+}
+
+func TestInviteService_Attribute(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("records the referral with the normalized code", func(t *testing.T) {
+		repo := new(mocks.MockInviteRepository)
+		repo.On("GetUserIDByCode", ctx, "ABCD2345").Return("referrer-1", nil)
+		repo.On("CreateReferral", ctx, &models.Referral{
+			ReferredUserID: "new-1",
+			ReferrerID:     "referrer-1",
+			Code:           "ABCD2345",
+		}).Return(true, nil)
+		svc := NewInviteService(repo, "", zap.NewNop())
+
+		svc.Attribute(ctx, "new-1", ptrStr(" abcd2345 "))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("unknown code is ignored", func(t *testing.T) {
+		repo := new(mocks.MockInviteRepository)
+		repo.On("GetUserIDByCode", ctx, "NOPE").Return("", repositories.ErrInviteCodeNotFound)
+		svc := NewInviteService(repo, "", zap.NewNop())
+
+		svc.Attribute(ctx, "new-1", ptrStr("nope"))
+		repo.AssertNotCalled(t, "CreateReferral", mock.Anything, mock.Anything)
+	})
+
+	t.Run("no code or no service", func(t *testing.T) {
+		repo := new(mocks.MockInviteRepository)
+		svc := NewInviteService(repo, "", zap.NewNop())
+		svc.Attribute(ctx, "new-1", nil)
+		svc.Attribute(ctx, "new-1", ptrStr("  "))
+
+		var nilSvc *InviteService
+		nilSvc.Attribute(ctx, "new-1", ptrStr("ABCD2345"))
+		repo.AssertNotCalled(t, "GetUserIDByCode", mock.Anything, mock.Anything)
+	})
+}
+
+func TestInviteService_TopReferrers(t *testing.T) {
+	ctx := context.Background()
+	repo := new(mocks.MockInviteRepository)
+	top := []*models.TopReferrer{{UserID: "referrer-1", Invites: 7}}
+	repo.On("TopReferrers", ctx, (*time.Time)(nil), maxTopReferrers).Return(top, nil).Once()
+	repo.On("TopReferrers", ctx, mock.MatchedBy(func(since *time.Time) bool {
+		return since != nil && time.Since(*since) > 29*24*time.Hour && time.Since(*since) < 31*24*time.Hour
+	}), 20).Return(top, nil).Once()
+	svc := NewInviteService(repo, "", zap.NewNop())
+
+	got, err := svc.TopReferrers(ctx, 0, 500)
+	require.NoError(t, err)
+	assert.Equal(t, top, got)
+
+	_, err = svc.TopReferrers(ctx, 30, 0)
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestAuthService_RegisterAttributesInvite(t *testing.T) {
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	userRepo.On("GetByEmail", mock.Anything, "new@example.com").Return(nil, errors.New("not found"))
+	userRepo.On("GetByEmailIncludingDeleted", mock.Anything, "new@example.com").Return(nil, errors.New("not found"))
+	var created *models.User
+	userRepo.On("CreateUserWithProfile", mock.Anything, mock.AnythingOfType("*models.User"), mock.AnythingOfType("*models.Profile")).
+		Run(func(args mock.Arguments) { created = args.Get(1).(*models.User) }).
+		Return(nil)
+	userRepo.On("GetProfileByUserID", mock.Anything, mock.Anything).Return(testutil.CreateTestProfile("any-id", "Test", "User"), nil)
+	userRepo.On("CreateSession", mock.Anything, mock.AnythingOfType("*models.UserSession")).Return(nil)
+	userRepo.On("UpdateLastLogin", mock.Anything, mock.Anything).Return(nil)
+
+	inviteRepo := new(mocks.MockInviteRepository)
+	inviteRepo.On("GetUserIDByCode", mock.Anything, "ABCD2345").Return("referrer-1", nil)
+	inviteRepo.On("CreateReferral", mock.Anything, mock.MatchedBy(func(r *models.Referral) bool {
+		return created != nil && r.ReferredUserID == created.ID && r.ReferrerID == "referrer-1"
+	})).Return(true, nil)
+
+	svc := newTestAuthService(userRepo, newFailingTokenStorage()).
+		WithInvites(NewInviteService(inviteRepo, "", zap.NewNop()))
+	_, err := svc.Register(ctx, &models.RegisterRequest{
+		Email:      "new@example.com",
+		Password:   "StrongPass1!",
+		InviteCode: ptrStr("abcd2345"),
+	})
+	require.NoError(t, err)
+	inviteRepo.AssertExpectations(t)
+}
@@ -2,9 +2,12 @@ package services
 
 import (
 	"crypto/rand"
+	"crypto/rsa"
 	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
+	"math/big"
+	"sort"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -14,22 +17,161 @@ import (
 	"github.com/hamsaya/backend/internal/utils"
 )
 
-// JWTService handles JWT token operations
+// jwtClockSkew is how early a staged signing key is already accepted, so
+// an instance whose clock runs slightly ahead doesn't have its tokens
+// rejected by the others around a scheduled rotation.
+const jwtClockSkew = time.Minute
+
+// signingKey is a parsed keyring entry.
+type signingKey struct {
+	id         string
+	method     jwt.SigningMethod
+	sign       interface{}
+	verify     interface{}
+	public     *rsa.PublicKey // RS256 only; published in the JWKS
+	activeFrom time.Time
+}
+
+// JWTService handles JWT token operations. Tokens are signed with the
+// current key of the configured keyring and validated against the current
+// key and the one it replaced, so rotating keys doesn't log anyone out.
 type JWTService struct {
-	cfg *config.JWTConfig
+	cfg  *config.JWTConfig
+	keys []signingKey // sorted by activeFrom
 }
 
 // NewJWTService creates a new JWT service
 func NewJWTService(cfg *config.JWTConfig) *JWTService {
 	return &JWTService{
-		cfg: cfg,
+		cfg:  cfg,
+		keys: parseSigningKeys(cfg),
+	}
+}
+
+// parseSigningKeys builds the keyring. Without JWT_KEYS it is the single
+// HS256 JWT_SECRET key with no key ID, matching tokens issued before
+// keyrings existed. Keys that fail to parse were already rejected by
+// config validation at startup and are skipped.
+func parseSigningKeys(cfg *config.JWTConfig) []signingKey {
+	if len(cfg.Keys) == 0 {
+		secret := []byte(cfg.Secret)
+		return []signingKey{{method: jwt.SigningMethodHS256, sign: secret, verify: secret}}
+	}
+	keys := make([]signingKey, 0, len(cfg.Keys))
+	for _, k := range cfg.Keys {
+		key := signingKey{id: k.ID, activeFrom: k.ActiveFrom}
+		switch k.Algorithm {
+		case config.JWTAlgHS256:
+			key.method = jwt.SigningMethodHS256
+			key.sign = []byte(k.Secret)
+			key.verify = key.sign
+		case config.JWTAlgRS256:
+			private, err := k.RSAPrivateKey()
+			if err != nil {
+				continue
+			}
+			key.method = jwt.SigningMethodRS256
+			key.sign = private
+			key.verify = &private.PublicKey
+			key.public = &private.PublicKey
+		default:
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.SliceStable(keys, func(i, j int) bool { return keys[i].activeFrom.Before(keys[j].activeFrom) })
+	return keys
+}
+
+// currentKeyIndex returns the newest key already active at now; the oldest
+// key when none is yet.
+func (s *JWTService) currentKeyIndex(now time.Time) int {
+	current := 0
+	for i, k := range s.keys {
+		if !k.activeFrom.After(now) {
+			current = i
+		}
+	}
+	return current
+}
+
+// validationKeys returns the keys tokens may be signed with right now: the
+// current key, the one it replaced, and the next one if it activates
+// within jwtClockSkew.
+func (s *JWTService) validationKeys(now time.Time) []signingKey {
+	if len(s.keys) == 0 {
+		return nil
+	}
+	current := s.currentKeyIndex(now)
+	from := current
+	if from > 0 {
+		from--
+	}
+	to := current + 1
+	if to < len(s.keys) && !s.keys[to].activeFrom.After(now.Add(jwtClockSkew)) {
+		to++
 	}
+	return s.keys[from:to]
+}
+
+// keyFunc picks the verification key for a token. Tokens carrying a kid
+// must name a key that is valid now and use its algorithm; tokens without
+// one (issued before keyrings) are tried against every valid key of their
+// algorithm. The explicit algorithm match rejects alg:none and HMAC/RSA
+// confusion.
+func (s *JWTService) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	var set jwt.VerificationKeySet
+	for _, k := range s.validationKeys(time.Now()) {
+		if k.method != token.Method {
+			continue
+		}
+		if kid != "" && k.id != kid {
+			continue
+		}
+		set.Keys = append(set.Keys, k.verify)
+	}
+	if len(set.Keys) == 0 {
+		return nil, fmt.Errorf("no valid key for kid %q and alg %v", kid, token.Header["alg"])
+	}
+	if len(set.Keys) == 1 {
+		return set.Keys[0], nil
+	}
+	return set, nil
+}
+
+// JWKS returns the public keys of the RS256 keys in the keyring, for
+// /.well-known/jwks.json. HMAC keys are secret and never published. Staged
+// keys are included ahead of their activation so verifiers can cache them.
+func (s *JWTService) JWKS() *models.JWKS {
+	set := &models.JWKS{Keys: make([]models.JWK, 0)}
+	current := s.currentKeyIndex(time.Now())
+	for i, k := range s.keys {
+		if k.public == nil || i < current-1 {
+			continue
+		}
+		set.Keys = append(set.Keys, models.JWK{
+			KeyType:   "RSA",
+			KeyID:     k.id,
+			Use:       "sig",
+			Algorithm: k.method.Alg(),
+			Modulus:   base64.RawURLEncoding.EncodeToString(k.public.N.Bytes()),
+			Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.public.E)).Bytes()),
+		})
+	}
+	return set
 }
 
 // GenerateTokenPair generates both access and refresh tokens
 func (s *JWTService) GenerateTokenPair(userID, email string, aal int, sessionID string) (*models.TokenPair, error) {
+	return s.GenerateTokenPairWithTTL(userID, email, aal, sessionID, s.cfg.AccessTokenDuration)
+}
+
+// GenerateTokenPairWithTTL is GenerateTokenPair with an access-token
+// lifetime other than the configured one, for admin-dashboard sessions.
+func (s *JWTService) GenerateTokenPairWithTTL(userID, email string, aal int, sessionID string, accessTTL time.Duration) (*models.TokenPair, error) {
 	// Generate access token
-	accessToken, expiresAt, err := s.GenerateAccessToken(userID, email, aal, sessionID)
+	accessToken, expiresAt, err := s.generateAccessToken(userID, email, aal, sessionID, accessTTL)
 	if err != nil {
 		return nil, err
 	}
@@ -51,8 +193,12 @@ func (s *JWTService) GenerateTokenPair(userID, email string, aal int, sessionID
 // GenerateAccessToken generates a new JWT access token. Each token includes
 // a unique JTI so it can be individually revoked via the access-token denylist.
 func (s *JWTService) GenerateAccessToken(userID, email string, aal int, sessionID string) (string, time.Time, error) {
+	return s.generateAccessToken(userID, email, aal, sessionID, s.cfg.AccessTokenDuration)
+}
+
+func (s *JWTService) generateAccessToken(userID, email string, aal int, sessionID string, ttl time.Duration) (string, time.Time, error) {
 	now := time.Now()
-	expiresAt := now.Add(s.cfg.AccessTokenDuration)
+	expiresAt := now.Add(ttl)
 	jti := uuid.New().String()
 
 	claims := jwt.MapClaims{
@@ -62,12 +208,17 @@ func (s *JWTService) GenerateAccessToken(userID, email string, aal int, sessionI
 		"session_id": sessionID,
 		"jti":        jti,
 		"iat":        now.Unix(),
+		"iat_ms":     now.UnixMilli(),
 		"exp":        expiresAt.Unix(),
 		"iss":        "hamsaya",
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString([]byte(s.cfg.Secret))
+	key := s.keys[s.currentKeyIndex(now)]
+	token := jwt.NewWithClaims(key.method, claims)
+	if key.id != "" {
+		token.Header["kid"] = key.id
+	}
+	signedToken, err := token.SignedString(key.sign)
 	if err != nil {
 		return "", time.Time{}, fmt.Errorf("failed to sign token: %w", err)
 	}
@@ -86,14 +237,7 @@ func (s *JWTService) GenerateRefreshToken() (string, error) {
 
 // ValidateAccessToken validates and parses an access token
 func (s *JWTService) ValidateAccessToken(tokenString string) (*models.JWTClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		// Explicitly require HS256 — reject none, RS256, and other algorithms
-		// to prevent algorithm confusion attacks (e.g. alg:none, RS256 with HMAC key)
-		if token.Method != jwt.SigningMethodHS256 {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(s.cfg.Secret), nil
-	})
+	token, err := jwt.Parse(tokenString, s.keyFunc)
 
 	if err != nil {
 		return nil, utils.NewUnauthorizedError("Invalid token", err)
@@ -142,15 +286,17 @@ func (s *JWTService) ValidateAccessToken(tokenString string) (*models.JWTClaims,
 		return nil, utils.NewUnauthorizedError("Invalid token: missing iss", nil)
 	}
 	jti, _ := claims["jti"].(string)
+	iatMs, _ := claims["iat_ms"].(float64)
 	jwtClaims := &models.JWTClaims{
-		UserID:    userID,
-		Email:     email,
-		AAL:       int(aalFloat),
-		SessionID: sessionID,
-		JTI:       jti,
-		IssuedAt:  int64(iatFloat),
-		ExpiresAt: int64(expFloat),
-		Issuer:    iss,
+		UserID:     userID,
+		Email:      email,
+		AAL:        int(aalFloat),
+		SessionID:  sessionID,
+		JTI:        jti,
+		IssuedAt:   int64(iatFloat),
+		IssuedAtMs: int64(iatMs),
+		ExpiresAt:  int64(expFloat),
+		Issuer:     iss,
 	}
 
 	// Verify not expired
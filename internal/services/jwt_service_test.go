@@ -1,9 +1,16 @@
 package services
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/hamsaya/backend/config"
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/stretchr/testify/assert"
@@ -383,3 +390,104 @@ func TestJWTService_ValidateAccessToken_LegacyTokenWithoutJTI(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotEmpty(t, claims.JTI, "current tokens populate JTI; legacy paths use the empty fallback")
 }
+
+func testRSAKeyPEM(t *testing.T) (string, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	block := &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+	return string(pem.EncodeToMemory(block)), key
+}
+
+func TestJWTService_KeyRotation(t *testing.T) {
+	now := time.Now()
+	oldKey := config.JWTKey{ID: "2026-08", Algorithm: config.JWTAlgHS256, Secret: "old-secret-key-at-least-32-characters-long", ActiveFrom: now.Add(-60 * 24 * time.Hour)}
+	prevKey := config.JWTKey{ID: "2026-09", Algorithm: config.JWTAlgHS256, Secret: "previous-secret-at-least-32-characters-long", ActiveFrom: now.Add(-30 * 24 * time.Hour)}
+	curKey := config.JWTKey{ID: "2026-10", Algorithm: config.JWTAlgHS256, Secret: "current-secret-at-least-32-characters-long!", ActiveFrom: now.Add(-time.Hour)}
+	nextKey := config.JWTKey{ID: "2026-11", Algorithm: config.JWTAlgHS256, Secret: "next-secret-key-at-least-32-characters-long", ActiveFrom: now.Add(24 * time.Hour)}
+
+	keyring := func(keys ...config.JWTKey) *JWTService {
+		cfg := getTestJWTConfig()
+		cfg.Keys = keys
+		return NewJWTService(cfg)
+	}
+	service := keyring(nextKey, oldKey, curKey, prevKey)
+
+	token, _, err := service.GenerateAccessToken("user-1", "a@example.com", models.AAL1, "s-1")
+	require.NoError(t, err)
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10", parsed.Header["kid"], "the newest active key signs")
+	_, err = service.ValidateAccessToken(token)
+	require.NoError(t, err)
+
+	// Tokens from the key just rotated out still validate; older ones don't.
+	prevToken, _, err := keyring(oldKey, prevKey).GenerateAccessToken("user-1", "a@example.com", models.AAL1, "s-1")
+	require.NoError(t, err)
+	_, err = service.ValidateAccessToken(prevToken)
+	assert.NoError(t, err)
+
+	oldToken, _, err := keyring(oldKey).GenerateAccessToken("user-1", "a@example.com", models.AAL1, "s-1")
+	require.NoError(t, err)
+	_, err = service.ValidateAccessToken(oldToken)
+	assert.Error(t, err)
+
+	// A staged key isn't accepted until it activates.
+	stagedToken, _, err := keyring(config.JWTKey{ID: "2026-11", Algorithm: config.JWTAlgHS256, Secret: nextKey.Secret}).
+		GenerateAccessToken("user-1", "a@example.com", models.AAL1, "s-1")
+	require.NoError(t, err)
+	_, err = service.ValidateAccessToken(stagedToken)
+	assert.Error(t, err)
+
+	// Tokens issued before keyrings (no kid) validate against any valid key.
+	legacyToken, _, err := NewJWTService(&config.JWTConfig{Secret: prevKey.Secret, AccessTokenDuration: time.Minute}).
+		GenerateAccessToken("user-1", "a@example.com", models.AAL1, "s-1")
+	require.NoError(t, err)
+	_, err = service.ValidateAccessToken(legacyToken)
+	assert.NoError(t, err)
+}
+
+func TestJWTService_RS256AndJWKS(t *testing.T) {
+	privatePEM, privateKey := testRSAKeyPEM(t)
+	cfg := getTestJWTConfig()
+	cfg.Keys = []config.JWTKey{
+		{ID: "legacy-hmac", Algorithm: config.JWTAlgHS256, Secret: cfg.Secret, ActiveFrom: time.Now().Add(-48 * time.Hour)},
+		{ID: "rsa-1", Algorithm: config.JWTAlgRS256, PrivateKey: base64.StdEncoding.EncodeToString([]byte(privatePEM)), ActiveFrom: time.Now().Add(-time.Hour)},
+	}
+	service := NewJWTService(cfg)
+
+	token, _, err := service.GenerateAccessToken("user-1", "a@example.com", models.AAL2, "s-1")
+	require.NoError(t, err)
+
+	// An independent verifier only needs the published key.
+	jwks := service.JWKS()
+	require.Len(t, jwks.Keys, 1)
+	jwk := jwks.Keys[0]
+	assert.Equal(t, "RSA", jwk.KeyType)
+	assert.Equal(t, "rsa-1", jwk.KeyID)
+	assert.Equal(t, "RS256", jwk.Algorithm)
+	n, err := base64.RawURLEncoding.DecodeString(jwk.Modulus)
+	require.NoError(t, err)
+	assert.Equal(t, 0, new(big.Int).SetBytes(n).Cmp(privateKey.N))
+
+	verified, err := jwt.Parse(token, func(*jwt.Token) (interface{}, error) { return &privateKey.PublicKey, nil },
+		jwt.WithValidMethods([]string{"RS256"}))
+	require.NoError(t, err)
+	assert.True(t, verified.Valid)
+
+	claims, err := service.ValidateAccessToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, models.AAL2, claims.AAL)
+
+	// A token claiming the RSA kid but HMAC-signed with the public modulus
+	// as secret is rejected (algorithm confusion).
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": "user-1", "email": "a@example.com", "aal": 2, "session_id": "s-1",
+		"iat": time.Now().Unix(), "exp": time.Now().Add(time.Minute).Unix(), "iss": "hamsaya",
+	})
+	forged.Header["kid"] = "rsa-1"
+	forgedToken, err := forged.SignedString(n)
+	require.NoError(t, err)
+	_, err = service.ValidateAccessToken(forgedToken)
+	assert.Error(t, err)
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/hamsaya/backend/pkg/runtimeconfig"
+	"go.uber.org/zap"
+)
+
+// Runtime setting keys for lifecycle messages. Admins change them through
+// /admin/system/settings; the hourly engagement run picks them up.
+const (
+	SettingLifecycleNudgeAfter    = "lifecycle.nudge_after"
+	SettingLifecycleReengageAfter = "lifecycle.reengage_after"
+)
+
+// Lifecycle defaults, used until runtime settings say otherwise.
+const (
+	defaultLifecycleNudgeAfter    = 48 * time.Hour
+	defaultLifecycleReengageAfter = 14 * 24 * time.Hour
+)
+
+// Placeholder fallbacks, for users who haven't filled in their profile.
+const (
+	lifecycleNameFallback     = "neighbor"
+	lifecycleProvinceFallback = "your neighborhood"
+)
+
+var lifecyclePlaceholderPattern = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+type lifecycleCopy struct {
+	title, message string
+	placeholders   []string
+}
+
+// lifecycleKinds lists the lifecycle messages in the order admins see them.
+var lifecycleKinds = []models.NotificationType{
+	models.NotificationTypeWelcome,
+	models.NotificationTypeFirstPostNudge,
+	models.NotificationTypeWinback,
+}
+
+// lifecycleDefaults is the built-in copy of each lifecycle message.
+var lifecycleDefaults = map[models.NotificationType]lifecycleCopy{
+	models.NotificationTypeWelcome: {
+		title:        "Welcome to Hamsaya, {first_name}!",
+		message:      "Discover neighbors, businesses, and listings in {province}.",
+		placeholders: []string{"first_name", "province"},
+	},
+	models.NotificationTypeFirstPostNudge: {
+		title:        "Say hello to your neighbors, {first_name}",
+		message:      "See what's happening in {province} — share your first post or follow a few neighbors.",
+		placeholders: []string{"first_name", "province"},
+	},
+	models.NotificationTypeWinback: {
+		title:        "Your neighborhood missed you, {first_name}",
+		message:      "See what's new in {province} on Hamsaya.",
+		placeholders: []string{"first_name", "province", "new_posts"},
+	},
+}
+
+// LifecycleVars fills a lifecycle message's placeholders.
+type LifecycleVars struct {
+	FirstName string
+	Province  string
+	// NewPosts is the number of posts in the user's province this week.
+	NewPosts int
+}
+
+// LifecycleMessageService owns lifecycle messaging: the copy of the welcome
+// notification, the first-post nudge and the re-engagement push, which
+// admins can edit or switch off, and how long a user may go without posting
+// or following anyone (nudge) or without opening the app (re-engagement)
+// before the hourly engagement run messages them.
+//
+// A nil *LifecycleMessageService uses the built-in copy and defaults, so
+// callers work unwired.
+type LifecycleMessageService struct {
+	repo     repositories.LifecycleTemplateRepository
+	settings *runtimeconfig.Store
+	logger   *zap.Logger
+}
+
+// NewLifecycleMessageService registers the lifecycle settings with store.
+func NewLifecycleMessageService(repo repositories.LifecycleTemplateRepository, store *runtimeconfig.Store, logger *zap.Logger) *LifecycleMessageService {
+	store.Register(runtimeconfig.Setting{
+		Key:         SettingLifecycleNudgeAfter,
+		Kind:        runtimeconfig.KindDuration,
+		Default:     defaultLifecycleNudgeAfter.String(),
+		Description: "How long after signup a user who hasn't posted or followed anyone is nudged",
+	})
+	store.Register(runtimeconfig.Setting{
+		Key:         SettingLifecycleReengageAfter,
+		Kind:        runtimeconfig.KindDuration,
+		Default:     defaultLifecycleReengageAfter.String(),
+		Description: "How long a user can stay away before the re-engagement push",
+	})
+	return &LifecycleMessageService{repo: repo, settings: store, logger: logger}
+}
+
+func (s *LifecycleMessageService) duration(key string, fallback time.Duration) time.Duration {
+	if s == nil || s.settings == nil {
+		return fallback
+	}
+	return s.settings.Duration(key, fallback)
+}
+
+// NudgeAfter is how long after signup an inactive newcomer is nudged.
+func (s *LifecycleMessageService) NudgeAfter() time.Duration {
+	return s.duration(SettingLifecycleNudgeAfter, defaultLifecycleNudgeAfter)
+}
+
+// ReengageAfter is how long since their last login a user is pushed to
+// come back.
+func (s *LifecycleMessageService) ReengageAfter() time.Duration {
+	return s.duration(SettingLifecycleReengageAfter, defaultLifecycleReengageAfter)
+}
+
+// List returns every lifecycle message's effective copy.
+func (s *LifecycleMessageService) List(ctx context.Context) ([]*models.LifecycleTemplate, error) {
+	overrides, err := s.repo.List(ctx)
+	if err != nil {
+		s.logger.Error("Failed to list lifecycle templates", zap.Error(err))
+		return nil, utils.NewInternalError("Failed to list lifecycle templates", err)
+	}
+	byKind := make(map[models.NotificationType]*models.LifecycleTemplate, len(overrides))
+	for _, t := range overrides {
+		byKind[t.Kind] = t
+	}
+
+	templates := make([]*models.LifecycleTemplate, 0, len(lifecycleKinds))
+	for _, kind := range lifecycleKinds {
+		t, ok := byKind[kind]
+		if !ok {
+			t = defaultLifecycleTemplate(kind)
+		}
+		t.Placeholders = lifecycleDefaults[kind].placeholders
+		templates = append(templates, t)
+	}
+	return templates, nil
+}
+
+// Update replaces the copy of a lifecycle message.
+func (s *LifecycleMessageService) Update(ctx context.Context, kind models.NotificationType, adminID string, req *models.UpdateLifecycleTemplateRequest) (*models.LifecycleTemplate, error) {
+	def, ok := lifecycleDefaults[kind]
+	if !ok {
+		return nil, utils.NewNotFoundError("Unknown lifecycle message", nil)
+	}
+	for _, text := range []string{req.Title, req.Message} {
+		if err := checkLifecyclePlaceholders(text, def.placeholders); err != nil {
+			return nil, err
+		}
+	}
+
+	t := &models.LifecycleTemplate{
+		Kind:         kind,
+		Title:        strings.TrimSpace(req.Title),
+		Message:      strings.TrimSpace(req.Message),
+		Enabled:      req.Enabled == nil || *req.Enabled,
+		Placeholders: def.placeholders,
+		Customized:   true,
+	}
+	if adminID != "" {
+		t.UpdatedBy = &adminID
+	}
+	if err := s.repo.Upsert(ctx, t); err != nil {
+		s.logger.Error("Failed to save lifecycle template", zap.String("kind", string(kind)), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to save lifecycle template", err)
+	}
+	return t, nil
+}
+
+// Reset restores a lifecycle message's built-in copy.
+func (s *LifecycleMessageService) Reset(ctx context.Context, kind models.NotificationType) (*models.LifecycleTemplate, error) {
+	if _, ok := lifecycleDefaults[kind]; !ok {
+		return nil, utils.NewNotFoundError("Unknown lifecycle message", nil)
+	}
+	if err := s.repo.Delete(ctx, kind); err != nil {
+		s.logger.Error("Failed to reset lifecycle template", zap.String("kind", string(kind)), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to reset lifecycle template", err)
+	}
+	t := defaultLifecycleTemplate(kind)
+	t.Placeholders = lifecycleDefaults[kind].placeholders
+	return t, nil
+}
+
+// Render returns the title and message of a lifecycle message for one user.
+// ok is false when an admin has switched the message off. If the override
+// can't be loaded the built-in copy is used, so a database hiccup never
+// drops a welcome.
+func (s *LifecycleMessageService) Render(ctx context.Context, kind models.NotificationType, vars LifecycleVars) (title, message string, ok bool) {
+	t := defaultLifecycleTemplate(kind)
+	if s != nil && s.repo != nil {
+		override, err := s.repo.Get(ctx, kind)
+		switch {
+		case err == nil:
+			t = override
+		case !errors.Is(err, repositories.ErrLifecycleTemplateNotFound):
+			s.logger.Warn("Failed to load lifecycle template", zap.String("kind", string(kind)), zap.Error(err))
+		}
+	}
+	if !t.Enabled {
+		return "", "", false
+	}
+	return renderLifecycle(t.Title, vars), renderLifecycle(t.Message, vars), true
+}
+
+func defaultLifecycleTemplate(kind models.NotificationType) *models.LifecycleTemplate {
+	def := lifecycleDefaults[kind]
+	return &models.LifecycleTemplate{Kind: kind, Title: def.title, Message: def.message, Enabled: true}
+}
+
+func renderLifecycle(text string, vars LifecycleVars) string {
+	name := strings.TrimSpace(vars.FirstName)
+	if name == "" {
+		name = lifecycleNameFallback
+	}
+	province := strings.TrimSpace(vars.Province)
+	if province == "" {
+		province = lifecycleProvinceFallback
+	}
+	return strings.NewReplacer(
+		"{first_name}", name,
+		"{province}", province,
+		"{new_posts}", strconv.Itoa(vars.NewPosts),
+	).Replace(text)
+}
+
+// checkLifecyclePlaceholders rejects placeholders the message can't fill,
+// so a typo doesn't reach users as a literal "{frist_name}".
+func checkLifecyclePlaceholders(text string, allowed []string) error {
+	for _, m := range lifecyclePlaceholderPattern.FindAllStringSubmatch(text, -1) {
+		known := false
+		for _, a := range allowed {
+			if m[1] == a {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return utils.NewBadRequestError(fmt.Sprintf("Unknown placeholder %s; use one of {%s}",
+				m[0], strings.Join(allowed, "}, {")), nil)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/pkg/runtimeconfig"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestLifecycleMessages(t *testing.T, repo *mocks.MockLifecycleTemplateRepository) (*LifecycleMessageService, *runtimeconfig.Store) {
+	mr := miniredis.RunT(t)
+	store := runtimeconfig.New(redis.NewClient(&redis.Options{Addr: mr.Addr()}), zap.NewNop())
+	return NewLifecycleMessageService(repo, store, zap.NewNop()), store
+}
+
+func TestLifecycleMessageService_Render(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("built-in copy with fallbacks", func(t *testing.T) {
+		repo := new(mocks.MockLifecycleTemplateRepository)
+		repo.On("Get", ctx, models.NotificationTypeWelcome).Return(nil, repositories.ErrLifecycleTemplateNotFound)
+		svc, _ := newTestLifecycleMessages(t, repo)
+
+		title, msg, ok := svc.Render(ctx, models.NotificationTypeWelcome, LifecycleVars{})
+		require.True(t, ok)
+		assert.Equal(t, "Welcome to Hamsaya, neighbor!", title)
+		assert.Equal(t, "Discover neighbors, businesses, and listings in your neighborhood.", msg)
+	})
+
+	t.Run("admin override", func(t *testing.T) {
+		repo := new(mocks.MockLifecycleTemplateRepository)
+		repo.On("Get", ctx, models.NotificationTypeWinback).Return(&models.LifecycleTemplate{
+			Kind: models.NotificationTypeWinback, Title: "{first_name}, come back", Message: "{new_posts} new posts in {province}", Enabled: true,
+		}, nil)
+		svc, _ := newTestLifecycleMessages(t, repo)
+
+		title, msg, ok := svc.Render(ctx, models.NotificationTypeWinback, LifecycleVars{FirstName: "Zahra", Province: "Herat", NewPosts: 12})
+		require.True(t, ok)
+		assert.Equal(t, "Zahra, come back", title)
+		assert.Equal(t, "12 new posts in Herat", msg)
+	})
+
+	t.Run("switched off", func(t *testing.T) {
+		repo := new(mocks.MockLifecycleTemplateRepository)
+		repo.On("Get", ctx, models.NotificationTypeFirstPostNudge).Return(&models.LifecycleTemplate{
+			Kind: models.NotificationTypeFirstPostNudge, Title: "x", Message: "y", Enabled: false,
+		}, nil)
+		svc, _ := newTestLifecycleMessages(t, repo)
+
+		_, _, ok := svc.Render(ctx, models.NotificationTypeFirstPostNudge, LifecycleVars{})
+		assert.False(t, ok)
+	})
+
+	t.Run("load error falls back to built-in copy", func(t *testing.T) {
+		repo := new(mocks.MockLifecycleTemplateRepository)
+		repo.On("Get", ctx, models.NotificationTypeWelcome).Return(nil, errors.New("db down"))
+		svc, _ := newTestLifecycleMessages(t, repo)
+
+		title, _, ok := svc.Render(ctx, models.NotificationTypeWelcome, LifecycleVars{FirstName: "Ahmad"})
+		require.True(t, ok)
+		assert.Equal(t, "Welcome to Hamsaya, Ahmad!", title)
+	})
+
+	t.Run("unwired service", func(t *testing.T) {
+		var svc *LifecycleMessageService
+		_, _, ok := svc.Render(ctx, models.NotificationTypeWinback, LifecycleVars{})
+		assert.True(t, ok)
+		assert.Equal(t, defaultLifecycleReengageAfter, svc.ReengageAfter())
+	})
+}
+
+func TestLifecycleMessageService_Update(t *testing.T) {
+	ctx := context.Background()
+	repo := new(mocks.MockLifecycleTemplateRepository)
+	svc, _ := newTestLifecycleMessages(t, repo)
+
+	_, err := svc.Update(ctx, "ADMIN", "admin-1", &models.UpdateLifecycleTemplateRequest{Title: "t", Message: "m"})
+	requireAppErrorCode(t, err, http.StatusNotFound)
+
+	_, err = svc.Update(ctx, models.NotificationTypeWelcome, "admin-1",
+		&models.UpdateLifecycleTemplateRequest{Title: "Hi {frist_name}", Message: "m"})
+	requireAppErrorCode(t, err, http.StatusBadRequest)
+
+	// {new_posts} only means something for the re-engagement push.
+	_, err = svc.Update(ctx, models.NotificationTypeWelcome, "admin-1",
+		&models.UpdateLifecycleTemplateRequest{Title: "Hi", Message: "{new_posts} posts"})
+	requireAppErrorCode(t, err, http.StatusBadRequest)
+
+	off := false
+	repo.On("Upsert", ctx, mock.MatchedBy(func(t *models.LifecycleTemplate) bool {
+		return t.Kind == models.NotificationTypeWinback && t.Title == "Hi {first_name}" && !t.Enabled && *t.UpdatedBy == "admin-1"
+	})).Return(nil).Once()
+	tmpl, err := svc.Update(ctx, models.NotificationTypeWinback, "admin-1",
+		&models.UpdateLifecycleTemplateRequest{Title: " Hi {first_name} ", Message: "{new_posts} new", Enabled: &off})
+	require.NoError(t, err)
+	assert.True(t, tmpl.Customized)
+	assert.Contains(t, tmpl.Placeholders, "new_posts")
+	repo.AssertExpectations(t)
+}
+
+func TestLifecycleMessageService_List(t *testing.T) {
+	ctx := context.Background()
+	repo := new(mocks.MockLifecycleTemplateRepository)
+	repo.On("List", ctx).Return([]*models.LifecycleTemplate{
+		{Kind: models.NotificationTypeWinback, Title: "Custom", Message: "m", Enabled: true, Customized: true},
+	}, nil)
+	svc, _ := newTestLifecycleMessages(t, repo)
+
+	templates, err := svc.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, templates, 3)
+	assert.Equal(t, models.NotificationTypeWelcome, templates[0].Kind)
+	assert.False(t, templates[0].Customized)
+	assert.Equal(t, "Custom", templates[2].Title)
+	assert.True(t, templates[2].Customized)
+}
+
+func TestLifecycleMessageService_Settings(t *testing.T) {
+	svc, store := newTestLifecycleMessages(t, new(mocks.MockLifecycleTemplateRepository))
+	assert.Equal(t, 48*time.Hour, svc.NudgeAfter())
+	assert.Equal(t, 14*24*time.Hour, svc.ReengageAfter())
+
+	require.NoError(t, store.Set(context.Background(), SettingLifecycleNudgeAfter, "72h"))
+	assert.Equal(t, 72*time.Hour, svc.NudgeAfter())
+}
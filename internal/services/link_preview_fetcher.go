@@ -0,0 +1,380 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hamsaya/backend/internal/models"
+	"golang.org/x/net/html"
+)
+
+const (
+	linkPreviewUserAgent = "HamsayaBot/1.0 (+https://hamsaya.app/bot)"
+	// linkPreviewRobotsAgent is the token matched against robots.txt
+	// User-agent lines.
+	linkPreviewRobotsAgent = "hamsayabot"
+	linkPreviewTimeout     = 5 * time.Second
+	linkPreviewMaxRedirect = 3
+	// Only the head is read; pages put their meta tags there.
+	linkPreviewMaxBody   = 512 << 10
+	linkPreviewMaxRobots = 64 << 10
+	// robotsCacheTTL is how long a host's robots.txt is trusted.
+	robotsCacheTTL = time.Hour
+
+	linkPreviewTitleMax       = 300
+	linkPreviewDescriptionMax = 500
+)
+
+// Errors returned by LinkPreviewFetcher.Fetch.
+var (
+	errLinkPreviewBlocked    = errors.New("link preview: address not allowed")
+	errLinkPreviewDisallowed = errors.New("link preview: disallowed by robots.txt")
+	errLinkPreviewNotHTML    = errors.New("link preview: not an HTML page")
+)
+
+// LinkPreviewFetcher downloads a page and reads its Open Graph / Twitter
+// card / <title> metadata. It honors robots.txt, caps time and size, and
+// refuses to connect to private, loopback and link-local addresses, since
+// the URLs come from users.
+type LinkPreviewFetcher struct {
+	client *http.Client
+
+	mu     sync.Mutex
+	robots map[string]robotsEntry
+}
+
+type robotsEntry struct {
+	rules   []robotsRule
+	expires time.Time
+}
+
+// NewLinkPreviewFetcher creates a fetcher.
+func NewLinkPreviewFetcher() *LinkPreviewFetcher {
+	dialer := &net.Dialer{
+		Timeout: 3 * time.Second,
+		// Control sees the resolved address, so a hostname pointing at an
+		// internal IP is refused too.
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return errLinkPreviewBlocked
+			}
+			return nil
+		},
+	}
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   3 * time.Second,
+		ResponseHeaderTimeout: 4 * time.Second,
+		MaxIdleConns:          20,
+		IdleConnTimeout:       30 * time.Second,
+	}
+	return &LinkPreviewFetcher{
+		client: &http.Client{
+			Timeout:   linkPreviewTimeout,
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= linkPreviewMaxRedirect {
+					return http.ErrUseLastResponse
+				}
+				if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+					return errLinkPreviewBlocked
+				}
+				return nil
+			},
+		},
+		robots: make(map[string]robotsEntry),
+	}
+}
+
+// isPublicIP reports whether ip is routable on the public internet.
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	// Carrier-grade NAT, 100.64.0.0/10.
+	if v4 := ip.To4(); v4 != nil && v4[0] == 100 && v4[1]&0xc0 == 64 {
+		return false
+	}
+	return true
+}
+
+// Fetch returns the preview of the page at rawURL.
+func (f *LinkPreviewFetcher) Fetch(ctx context.Context, rawURL string) (*models.LinkPreview, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("link preview: invalid url %q", rawURL)
+	}
+	if !f.robotsAllowed(ctx, u) {
+		return nil, errLinkPreviewDisallowed
+	}
+
+	resp, err := f.get(ctx, u.String(), "text/html,application/xhtml+xml")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("link preview: status %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.Contains(ct, "text/html") && !strings.Contains(ct, "application/xhtml") {
+		return nil, errLinkPreviewNotHTML
+	}
+
+	preview := parseLinkPreview(io.LimitReader(resp.Body, linkPreviewMaxBody), resp.Request.URL)
+	preview.URL = rawURL
+	preview.FetchedAt = time.Now()
+	return preview, nil
+}
+
+func (f *LinkPreviewFetcher) get(ctx context.Context, target, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", linkPreviewUserAgent)
+	req.Header.Set("Accept", accept)
+	return f.client.Do(req)
+}
+
+// robotsAllowed checks u against its host's robots.txt. A missing
+// robots.txt (4xx) allows everything; one that can't be read blocks the
+// fetch, as crawlers are expected to.
+func (f *LinkPreviewFetcher) robotsAllowed(ctx context.Context, u *url.URL) bool {
+	origin := u.Scheme + "://" + u.Host
+	f.mu.Lock()
+	entry, ok := f.robots[origin]
+	f.mu.Unlock()
+
+	if !ok || time.Now().After(entry.expires) {
+		rules, err := f.loadRobots(ctx, origin)
+		if err != nil {
+			return false
+		}
+		entry = robotsEntry{rules: rules, expires: time.Now().Add(robotsCacheTTL)}
+		f.mu.Lock()
+		f.robots[origin] = entry
+		f.mu.Unlock()
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return robotsPathAllowed(entry.rules, path)
+}
+
+func (f *LinkPreviewFetcher) loadRobots(ctx context.Context, origin string) ([]robotsRule, error) {
+	resp, err := f.get(ctx, origin+"/robots.txt", "text/plain")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		body, err := io.ReadAll(io.LimitReader(resp.Body, linkPreviewMaxRobots))
+		if err != nil {
+			return nil, err
+		}
+		return parseRobots(string(body), linkPreviewRobotsAgent), nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("robots.txt: status %d", resp.StatusCode)
+	}
+}
+
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+// parseRobots returns the rules of the group naming ua, or of the "*"
+// group when none does.
+func parseRobots(body, ua string) []robotsRule {
+	var specific, wildcard []robotsRule
+	var hasSpecific bool
+	var agents []string
+	inRules := false
+	for _, line := range strings.Split(body, "\n") {
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		switch key {
+		case "user-agent":
+			if inRules {
+				agents, inRules = nil, false
+			}
+			agent := strings.ToLower(value)
+			agents = append(agents, agent)
+			hasSpecific = hasSpecific || agent == ua
+		case "allow", "disallow":
+			inRules = true
+			if value == "" {
+				continue // "Disallow:" allows everything
+			}
+			rule := robotsRule{allow: key == "allow", pattern: value}
+			for _, a := range agents {
+				switch {
+				case a == ua:
+					specific = append(specific, rule)
+				case a == "*":
+					wildcard = append(wildcard, rule)
+				}
+			}
+		}
+	}
+	if hasSpecific {
+		return specific
+	}
+	return wildcard
+}
+
+// robotsPathAllowed applies the longest matching rule; Allow wins a tie.
+func robotsPathAllowed(rules []robotsRule, path string) bool {
+	allowed, best := true, -1
+	for _, r := range rules {
+		if !robotsMatch(r.pattern, path) {
+			continue
+		}
+		if n := len(r.pattern); n > best || (n == best && r.allow) {
+			allowed, best = r.allow, n
+		}
+	}
+	return allowed
+}
+
+// robotsMatch matches a robots.txt path pattern: a prefix match where "*"
+// matches any run of characters and a trailing "$" anchors the end.
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	parts := strings.Split(strings.TrimSuffix(pattern, "$"), "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	expr := "^" + strings.Join(parts, ".*")
+	if anchored {
+		expr += "$"
+	}
+	re, err := regexp.Compile(expr)
+	return err == nil && re.MatchString(path)
+}
+
+// parseLinkPreview reads the page's head metadata. base resolves relative
+// image URLs.
+func parseLinkPreview(r io.Reader, base *url.URL) *models.LinkPreview {
+	meta := make(map[string]string)
+	var title string
+	z := html.NewTokenizer(r)
+	inTitle := false
+loop:
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			break loop
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			switch string(name) {
+			case "body":
+				break loop
+			case "title":
+				inTitle = title == ""
+			case "meta":
+				var key, content string
+				for hasAttr {
+					var k, v []byte
+					k, v, hasAttr = z.TagAttr()
+					switch string(k) {
+					case "property", "name":
+						key = strings.ToLower(string(v))
+					case "content":
+						content = string(v)
+					}
+				}
+				if key != "" && content != "" {
+					if _, seen := meta[key]; !seen {
+						meta[key] = strings.TrimSpace(content)
+					}
+				}
+			}
+		case html.TextToken:
+			if inTitle {
+				title += string(z.Text())
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "title":
+				inTitle = false
+			case "head":
+				break loop
+			}
+		}
+	}
+
+	first := func(keys ...string) string {
+		for _, k := range keys {
+			if v := meta[k]; v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+	preview := &models.LinkPreview{}
+	if v := first("og:title", "twitter:title"); v != "" {
+		title = v
+	}
+	preview.Title = optionalText(title, linkPreviewTitleMax)
+	preview.Description = optionalText(first("og:description", "twitter:description", "description"), linkPreviewDescriptionMax)
+	preview.SiteName = optionalText(first("og:site_name"), linkPreviewTitleMax)
+	if preview.SiteName == nil && base != nil {
+		host := strings.TrimPrefix(base.Hostname(), "www.")
+		preview.SiteName = &host
+	}
+	if image := first("og:image:secure_url", "og:image", "twitter:image", "twitter:image:src"); image != "" && base != nil {
+		if u, err := base.Parse(image); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+			s := u.String()
+			preview.ImageURL = &s
+		}
+	}
+	preview.Failed = preview.Title == nil && preview.Description == nil && preview.ImageURL == nil
+	return preview
+}
+
+// optionalText collapses whitespace and cuts s to max characters; empty
+// becomes nil.
+func optionalText(s string, max int) *string {
+	s = strings.Join(strings.Fields(s), " ")
+	if s == "" {
+		return nil
+	}
+	if utf8.RuneCountInString(s) > max {
+		s = string([]rune(s)[:max-1]) + "…"
+	}
+	return &s
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPreviewPage = `<!doctype html><html><head>
+<title>Fallback title</title>
+<meta property="og:title" content="Bread prices in Kabul">
+<meta name="description" content="  Prices rose   again this week. ">
+<meta property="og:image" content="/img/bread.jpg">
+</head><body><meta property="og:title" content="ignored"></body></html>`
+
+func TestParseLinkPreview(t *testing.T) {
+	base, _ := url.Parse("https://www.news.af/a/1")
+	p := parseLinkPreview(strings.NewReader(testPreviewPage), base)
+
+	require.NotNil(t, p.Title)
+	assert.Equal(t, "Bread prices in Kabul", *p.Title)
+	assert.Equal(t, "Prices rose again this week.", *p.Description)
+	assert.Equal(t, "https://www.news.af/img/bread.jpg", *p.ImageURL)
+	assert.Equal(t, "news.af", *p.SiteName)
+	assert.False(t, p.Failed)
+
+	empty := parseLinkPreview(strings.NewReader("<html><body>hi</body></html>"), base)
+	assert.True(t, empty.Failed)
+}
+
+func TestRobotsRules(t *testing.T) {
+	body := `
+User-agent: *
+Disallow: /
+
+User-agent: HamsayaBot
+Disallow: /private
+Allow: /private/ok$
+Disallow: /*.pdf$
+`
+	rules := parseRobots(body, linkPreviewRobotsAgent)
+	assert.True(t, robotsPathAllowed(rules, "/news/1"))
+	assert.False(t, robotsPathAllowed(rules, "/private/x"))
+	assert.True(t, robotsPathAllowed(rules, "/private/ok"))
+	assert.False(t, robotsPathAllowed(rules, "/docs/a.pdf"))
+	assert.True(t, robotsPathAllowed(rules, "/docs/a.pdf?download=1"))
+
+	// Nothing for our agent: the "*" group applies.
+	assert.False(t, robotsPathAllowed(parseRobots("User-agent: *\nDisallow: /", linkPreviewRobotsAgent), "/a"))
+	// An empty Disallow in our group allows everything.
+	assert.True(t, robotsPathAllowed(parseRobots("User-agent: *\nDisallow: /\n\nUser-agent: HamsayaBot\nDisallow:", linkPreviewRobotsAgent), "/a"))
+}
+
+func TestLinkPreviewFetcher_Fetch(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			_, _ = w.Write([]byte("User-agent: *\nDisallow: /blocked"))
+		case "/file":
+			w.Header().Set("Content-Type", "application/pdf")
+		default:
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write([]byte(testPreviewPage))
+		}
+	}))
+	defer ts.Close()
+
+	f := NewLinkPreviewFetcher()
+	f.client = ts.Client() // the default client refuses loopback
+	ctx := context.Background()
+
+	p, err := f.Fetch(ctx, ts.URL+"/news")
+	require.NoError(t, err)
+	assert.Equal(t, ts.URL+"/news", p.URL)
+	assert.Equal(t, "Bread prices in Kabul", *p.Title)
+
+	_, err = f.Fetch(ctx, ts.URL+"/blocked/page")
+	assert.ErrorIs(t, err, errLinkPreviewDisallowed)
+
+	_, err = f.Fetch(ctx, ts.URL+"/file")
+	assert.ErrorIs(t, err, errLinkPreviewNotHTML)
+}
+
+func TestLinkPreviewFetcher_RefusesPrivateAddresses(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(testPreviewPage))
+	}))
+	defer ts.Close()
+
+	_, err := NewLinkPreviewFetcher().Fetch(context.Background(), ts.URL+"/")
+	assert.Error(t, err)
+
+	for _, ip := range []string{"127.0.0.1", "10.1.2.3", "192.168.0.1", "169.254.169.254", "100.64.0.1", "::1"} {
+		assert.False(t, isPublicIP(net.ParseIP(ip)), ip)
+	}
+	assert.True(t, isPublicIP(net.ParseIP("8.8.8.8")))
+}
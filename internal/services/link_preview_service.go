@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/pkg/events"
+	"go.uber.org/zap"
+)
+
+// linkPreviewTTL is how long a fetched preview is reused before the page is
+// fetched again.
+const linkPreviewTTL = 7 * 24 * time.Hour
+
+var linkPattern = regexp.MustCompile(`(?i)https?://[^\s<>"']+`)
+
+// linkFetcher fetches a page's preview; LinkPreviewFetcher in production.
+type linkFetcher interface {
+	Fetch(ctx context.Context, rawURL string) (*models.LinkPreview, error)
+}
+
+// LinkPreviewService unfurls the first link in a post: it fetches the
+// page's title, description and image in the background after the post is
+// created or edited, caches them by URL hash, and attaches the preview to
+// the post for PostResponse.link_preview.
+type LinkPreviewService struct {
+	repo    repositories.LinkPreviewRepository
+	fetcher linkFetcher
+	logger  *zap.Logger
+}
+
+// NewLinkPreviewService creates the service.
+func NewLinkPreviewService(repo repositories.LinkPreviewRepository, logger *zap.Logger) *LinkPreviewService {
+	return &LinkPreviewService{
+		repo:    repo,
+		fetcher: NewLinkPreviewFetcher(),
+		logger:  logger,
+	}
+}
+
+// SubscribePosts fetches previews for created and edited posts.
+func (s *LinkPreviewService) SubscribePosts(bus *events.Bus) {
+	events.Subscribe(bus, func(ctx context.Context, e PostCreated) {
+		s.Sync(ctx, e.Post)
+	})
+	events.Subscribe(bus, func(ctx context.Context, e PostEdited) {
+		s.Sync(ctx, e.Post)
+	})
+}
+
+// Sync attaches the preview of the post's first link, or removes the
+// post's preview when it has no link or the page has nothing to show.
+func (s *LinkPreviewService) Sync(ctx context.Context, post *models.Post) {
+	link := firstLink(postLinkText(post))
+	if link == "" {
+		s.detach(ctx, post.ID)
+		return
+	}
+
+	hash := linkHash(link)
+	preview, err := s.repo.GetByHash(ctx, hash)
+	if err != nil && !errors.Is(err, repositories.ErrLinkPreviewNotFound) {
+		s.logger.Warn("Failed to load link preview", zap.String("url", link), zap.Error(err))
+		return
+	}
+	if preview == nil || time.Since(preview.FetchedAt) > linkPreviewTTL {
+		preview, err = s.fetcher.Fetch(ctx, link)
+		if err != nil {
+			s.logger.Debug("Link preview fetch failed", zap.String("url", link), zap.Error(err))
+			preview = &models.LinkPreview{URL: link, Failed: true, FetchedAt: time.Now()}
+		}
+		if err := s.repo.Upsert(ctx, hash, preview); err != nil {
+			s.logger.Warn("Failed to store link preview", zap.String("url", link), zap.Error(err))
+			return
+		}
+	}
+
+	if preview.Failed {
+		s.detach(ctx, post.ID)
+		return
+	}
+	if err := s.repo.AttachToPost(ctx, post.ID, hash); err != nil {
+		s.logger.Warn("Failed to attach link preview", zap.String("post_id", post.ID), zap.Error(err))
+	}
+}
+
+// StripStale removes the post's preview right away when its text no longer
+// contains the previewed link, so an edit never returns a card for a URL
+// that was taken out. Sync attaches the new link's preview afterwards.
+func (s *LinkPreviewService) StripStale(ctx context.Context, post *models.Post) {
+	current, err := s.repo.GetByPostIDs(ctx, []string{post.ID})
+	if err != nil {
+		s.logger.Warn("Failed to load link preview", zap.String("post_id", post.ID), zap.Error(err))
+		return
+	}
+	preview, ok := current[post.ID]
+	if !ok || firstLink(postLinkText(post)) == preview.URL {
+		return
+	}
+	s.detach(ctx, post.ID)
+}
+
+// PreviewsForPosts returns the preview of each post, keyed by post ID.
+func (s *LinkPreviewService) PreviewsForPosts(ctx context.Context, postIDs []string) (map[string]*models.LinkPreview, error) {
+	return s.repo.GetByPostIDs(ctx, postIDs)
+}
+
+func (s *LinkPreviewService) detach(ctx context.Context, postID string) {
+	if err := s.repo.DetachFromPost(ctx, postID); err != nil {
+		s.logger.Warn("Failed to remove link preview", zap.String("post_id", postID), zap.Error(err))
+	}
+}
+
+func postLinkText(post *models.Post) string {
+	var parts []string
+	if post.Title != nil {
+		parts = append(parts, *post.Title)
+	}
+	if post.Description != nil {
+		parts = append(parts, *post.Description)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// firstLink returns the first http(s) URL in text, normalized, or "".
+// Punctuation right after a link ("see https://x.af/a.") isn't part of it.
+func firstLink(text string) string {
+	for _, raw := range linkPattern.FindAllString(text, -1) {
+		raw = strings.TrimRight(raw, ".,;:!?)]}")
+		u, err := url.Parse(raw)
+		if err != nil || u.Hostname() == "" {
+			continue
+		}
+		u.Scheme = strings.ToLower(u.Scheme)
+		u.Host = strings.ToLower(u.Host)
+		u.Fragment = ""
+		return u.String()
+	}
+	return ""
+}
+
+// linkHash keys the preview cache.
+func linkHash(link string) string {
+	sum := sha256.Sum256([]byte(link))
+	return hex.EncodeToString(sum[:])
+}
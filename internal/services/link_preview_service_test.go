@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type fakeLinkFetcher struct {
+	preview *models.LinkPreview
+	err     error
+	calls   int
+}
+
+func (f *fakeLinkFetcher) Fetch(_ context.Context, rawURL string) (*models.LinkPreview, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	p := *f.preview
+	p.URL = rawURL
+	p.FetchedAt = time.Now()
+	return &p, nil
+}
+
+func newTestLinkPreviewService(repo *mocks.MockLinkPreviewRepository, fetcher *fakeLinkFetcher) *LinkPreviewService {
+	svc := NewLinkPreviewService(repo, zap.NewNop())
+	svc.fetcher = fetcher
+	return svc
+}
+
+func linkPost(description string) *models.Post {
+	return &models.Post{ID: "post-1", Description: &description}
+}
+
+func TestFirstLink(t *testing.T) {
+	assert.Equal(t, "https://hamsaya.af/about?x=1",
+		firstLink("See HTTPS://Hamsaya.AF/about?x=1#team, then https://other.af"))
+	assert.Equal(t, "http://kabul.af/a", firstLink("(http://kabul.af/a)."))
+	assert.Equal(t, "", firstLink("no links here, just hamsaya.af"))
+}
+
+func TestLinkPreviewService_Sync(t *testing.T) {
+	ctx := context.Background()
+	link := "https://hamsaya.af/news"
+	hash := linkHash(link)
+	title := "Hamsaya news"
+
+	t.Run("fetches, caches and attaches", func(t *testing.T) {
+		repo := new(mocks.MockLinkPreviewRepository)
+		repo.On("GetByHash", ctx, hash).Return(nil, repositories.ErrLinkPreviewNotFound)
+		repo.On("Upsert", ctx, hash, mock.MatchedBy(func(p *models.LinkPreview) bool {
+			return p.URL == link && *p.Title == title
+		})).Return(nil).Once()
+		repo.On("AttachToPost", ctx, "post-1", hash).Return(nil).Once()
+		fetcher := &fakeLinkFetcher{preview: &models.LinkPreview{Title: &title}}
+
+		newTestLinkPreviewService(repo, fetcher).Sync(ctx, linkPost("Read "+link))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("fresh cache is not refetched", func(t *testing.T) {
+		repo := new(mocks.MockLinkPreviewRepository)
+		repo.On("GetByHash", ctx, hash).
+			Return(&models.LinkPreview{URL: link, Title: &title, FetchedAt: time.Now().Add(-time.Hour)}, nil)
+		repo.On("AttachToPost", ctx, "post-1", hash).Return(nil).Once()
+		fetcher := &fakeLinkFetcher{}
+
+		newTestLinkPreviewService(repo, fetcher).Sync(ctx, linkPost(link))
+		assert.Zero(t, fetcher.calls)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("failed fetch is cached and not shown", func(t *testing.T) {
+		repo := new(mocks.MockLinkPreviewRepository)
+		repo.On("GetByHash", ctx, hash).Return(nil, repositories.ErrLinkPreviewNotFound)
+		repo.On("Upsert", ctx, hash, mock.MatchedBy(func(p *models.LinkPreview) bool { return p.Failed })).Return(nil).Once()
+		repo.On("DetachFromPost", ctx, "post-1").Return(nil).Once()
+		fetcher := &fakeLinkFetcher{err: errLinkPreviewDisallowed}
+
+		newTestLinkPreviewService(repo, fetcher).Sync(ctx, linkPost(link))
+		repo.AssertExpectations(t)
+		repo.AssertNotCalled(t, "AttachToPost", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("no link removes the preview", func(t *testing.T) {
+		repo := new(mocks.MockLinkPreviewRepository)
+		repo.On("DetachFromPost", ctx, "post-1").Return(nil).Once()
+
+		newTestLinkPreviewService(repo, &fakeLinkFetcher{}).Sync(ctx, linkPost("nothing to see"))
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestLinkPreviewService_StripStale(t *testing.T) {
+	ctx := context.Background()
+	current := map[string]*models.LinkPreview{"post-1": {URL: "https://hamsaya.af/news"}}
+
+	repo := new(mocks.MockLinkPreviewRepository)
+	repo.On("GetByPostIDs", ctx, []string{"post-1"}).Return(current, nil)
+	svc := newTestLinkPreviewService(repo, &fakeLinkFetcher{})
+
+	svc.StripStale(ctx, linkPost("still https://hamsaya.af/news"))
+	repo.AssertNotCalled(t, "DetachFromPost", mock.Anything, mock.Anything)
+
+	repo.On("DetachFromPost", ctx, "post-1").Return(nil).Once()
+	svc.StripStale(ctx, linkPost("link removed"))
+	repo.AssertExpectations(t)
+}
+
+func TestLinkPreviewService_StripStale_LoadError(t *testing.T) {
+	repo := new(mocks.MockLinkPreviewRepository)
+	repo.On("GetByPostIDs", mock.Anything, []string{"post-1"}).Return(nil, errors.New("db down"))
+
+	newTestLinkPreviewService(repo, &fakeLinkFetcher{}).StripStale(context.Background(), linkPost(""))
+	repo.AssertNotCalled(t, "DetachFromPost", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"math"
+
+	"github.com/hamsaya/backend/internal/models"
+)
+
+// Exact coordinates (a post's pin, a profile's home) stay in the database
+// for the owner and for server-side work: distance sorting, radius search,
+// geofences and alert fan-out all read the stored columns. Responses built
+// for anyone else carry a coarsened copy, so a pin can't be walked back to a
+// door. Every enrichment path goes through locationPrecisionFor.
+
+// locationPrecision is how exactly a viewer may see someone else's pin.
+type locationPrecision int
+
+const (
+	// preciseLocation is the stored point; the owner's own view.
+	preciseLocation locationPrecision = iota
+	// gridLocation snaps to the centre of a locationGridMeters cell, for
+	// signed-in viewers.
+	gridLocation
+	// areaLocation rounds to 2 decimals (~1 km), for anonymous viewers.
+	areaLocation
+)
+
+// locationGridMeters is the cell size signed-in non-owners see pins at.
+// Snapping (rather than random jitter) gives every viewer and every request
+// the same answer, so averaging repeated reads recovers nothing.
+const locationGridMeters = 300
+
+// metersPerDegreeLat is the length of one degree of latitude.
+const metersPerDegreeLat = 111_320
+
+// locationPrecisionFor returns how viewerID sees a location; isOwner is
+// whether the viewer owns it (directly or through their business).
+func locationPrecisionFor(isOwner bool, viewerID *string) locationPrecision {
+	switch {
+	case viewerID == nil || *viewerID == "":
+		return areaLocation
+	case isOwner:
+		return preciseLocation
+	}
+	return gridLocation
+}
+
+// apply coarsens a point to the precision.
+func (p locationPrecision) apply(lat, lng float64) (float64, float64) {
+	switch p {
+	case gridLocation:
+		return snapToGrid(lat, lng, locationGridMeters)
+	case areaLocation:
+		return math.Round(lat*100) / 100, math.Round(lng*100) / 100
+	}
+	return lat, lng
+}
+
+// applyInfo coarsens info's coordinates. It swaps in new pointers rather
+// than writing through the old ones, which often point into a model.
+func (p locationPrecision) applyInfo(info *models.LocationInfo) {
+	if p == preciseLocation || info == nil || info.Latitude == nil || info.Longitude == nil {
+		return
+	}
+	lat, lng := p.apply(*info.Latitude, *info.Longitude)
+	info.Latitude = &lat
+	info.Longitude = &lng
+}
+
+// snapToGrid returns the centre of the gridMeters cell containing the
+// point. Rows are gridMeters of latitude; each row's columns are gridMeters
+// wide at that row's centre, so cells stay roughly square away from the
+// equator.
+func snapToGrid(lat, lng, gridMeters float64) (float64, float64) {
+	latStep := gridMeters / metersPerDegreeLat
+	cellLat := (math.Floor(lat/latStep) + 0.5) * latStep
+
+	lngStep := gridMeters / (metersPerDegreeLat * math.Cos(cellLat*math.Pi/180))
+	if math.IsInf(lngStep, 0) || lngStep > 360 {
+		return cellLat, 0
+	}
+	cellLng := (math.Floor(lng/lngStep) + 0.5) * lngStep
+	return cellLat, cellLng
+}
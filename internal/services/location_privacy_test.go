@@ -0,0 +1,71 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocationPrecisionFor(t *testing.T) {
+	viewer := "viewer-1"
+	empty := ""
+
+	assert.Equal(t, areaLocation, locationPrecisionFor(false, nil))
+	assert.Equal(t, areaLocation, locationPrecisionFor(true, &empty))
+	assert.Equal(t, preciseLocation, locationPrecisionFor(true, &viewer))
+	assert.Equal(t, gridLocation, locationPrecisionFor(false, &viewer))
+}
+
+func TestSnapToGrid(t *testing.T) {
+	lat, lng := 34.555312, 69.207488 // Kabul
+
+	cellLat, cellLng := snapToGrid(lat, lng, locationGridMeters)
+
+	// The cell centre is within half a diagonal of the real point...
+	dLat := (cellLat - lat) * metersPerDegreeLat
+	dLng := (cellLng - lng) * metersPerDegreeLat * math.Cos(lat*math.Pi/180)
+	assert.LessOrEqual(t, math.Hypot(dLat, dLng), locationGridMeters*math.Sqrt2/2+1)
+
+	// ...and every point in the cell gets the same answer.
+	nearLat, nearLng := snapToGrid(cellLat+0.0003, cellLng-0.0003, locationGridMeters)
+	assert.InDelta(t, cellLat, nearLat, 1e-9)
+	assert.InDelta(t, cellLng, nearLng, 1e-9)
+
+	againLat, againLng := snapToGrid(cellLat, cellLng, locationGridMeters)
+	assert.InDelta(t, cellLat, againLat, 1e-9)
+	assert.InDelta(t, cellLng, againLng, 1e-9)
+}
+
+func TestLocationPrecision_ApplyInfo(t *testing.T) {
+	stored := [2]float64{34.555312, 69.207488}
+	newInfo := func() *models.LocationInfo {
+		return &models.LocationInfo{Latitude: &stored[0], Longitude: &stored[1]}
+	}
+
+	t.Run("owner sees the stored point", func(t *testing.T) {
+		info := newInfo()
+		preciseLocation.applyInfo(info)
+		assert.Equal(t, 34.555312, *info.Latitude)
+	})
+
+	t.Run("anonymous gets 2 decimals", func(t *testing.T) {
+		info := newInfo()
+		areaLocation.applyInfo(info)
+		assert.Equal(t, 34.56, *info.Latitude)
+		assert.Equal(t, 69.21, *info.Longitude)
+	})
+
+	t.Run("signed-in gets the grid and the model is untouched", func(t *testing.T) {
+		info := newInfo()
+		gridLocation.applyInfo(info)
+		assert.NotEqual(t, 34.555312, *info.Latitude)
+		assert.Equal(t, [2]float64{34.555312, 69.207488}, stored)
+	})
+
+	t.Run("nil is a no-op", func(t *testing.T) {
+		gridLocation.applyInfo(nil)
+		gridLocation.applyInfo(&models.LocationInfo{})
+	})
+}
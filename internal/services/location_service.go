@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/hamsaya/backend/pkg/cache"
+	"go.uber.org/zap"
+)
+
+// locationListTTL is the cache lifetime for the public picker lists. Admin
+// edits bust the cache eagerly, like categories.
+const locationListTTL = 6 * time.Hour
+
+// LocationService manages the province / district / neighborhood reference
+// list and serves it to client pickers.
+type LocationService struct {
+	locationRepo repositories.LocationRepository
+	logger       *zap.Logger
+	cache        *cache.Cache // optional; nil = no caching
+}
+
+// NewLocationService creates a new location service.
+func NewLocationService(locationRepo repositories.LocationRepository, logger *zap.Logger) *LocationService {
+	return &LocationService{
+		locationRepo: locationRepo,
+		logger:       logger,
+	}
+}
+
+// WithCache attaches a cache namespace for the picker lists.
+func (s *LocationService) WithCache(c *cache.Cache) *LocationService {
+	s.cache = c
+	return s
+}
+
+func (s *LocationService) invalidateCache(ctx context.Context) {
+	if s.cache == nil {
+		return
+	}
+	s.cache.DelPattern(ctx, "*")
+}
+
+func (s *LocationService) get(ctx context.Context, id string) (*models.LocationRef, error) {
+	loc, err := s.locationRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrLocationNotFound) {
+			return nil, utils.NewNotFoundError("Location not found", err)
+		}
+		return nil, utils.NewInternalError("Failed to load location", err)
+	}
+	return loc, nil
+}
+
+// Options returns the active children of parentID (provinces when empty)
+// for a client picker, labelled in locale.
+func (s *LocationService) Options(ctx context.Context, parentID, locale string) ([]*models.LocationOption, error) {
+	cacheKey := "options:" + parentID + ":" + locale
+	if s.cache != nil {
+		var cached []*models.LocationOption
+		if hit, _ := s.cache.Get(ctx, cacheKey, &cached); hit {
+			return cached, nil
+		}
+	}
+
+	filter := &models.LocationFilter{}
+	if parentID != "" {
+		filter.ParentID = &parentID
+	}
+	locs, err := s.locationRepo.List(ctx, filter)
+	if err != nil {
+		s.logger.Error("Failed to list locations", zap.Error(err))
+		return nil, utils.NewInternalError("Failed to retrieve locations", err)
+	}
+
+	out := make([]*models.LocationOption, len(locs))
+	for i, l := range locs {
+		out[i] = &models.LocationOption{
+			ID:       l.ID,
+			ParentID: l.ParentID,
+			Level:    l.Level,
+			Value:    l.Name,
+			Label:    l.NameForLocale(locale),
+		}
+	}
+
+	if s.cache != nil {
+		_ = s.cache.Set(ctx, cacheKey, out, locationListTTL)
+	}
+	return out, nil
+}
+
+// List returns the children of parentID (provinces when empty), including
+// inactive entries (admin).
+func (s *LocationService) List(ctx context.Context, parentID string) ([]*models.LocationRef, error) {
+	filter := &models.LocationFilter{IncludeInactive: true}
+	if parentID != "" {
+		filter.ParentID = &parentID
+	}
+	locs, err := s.locationRepo.List(ctx, filter)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to retrieve locations", err)
+	}
+	return locs, nil
+}
+
+// Create adds a location (admin). The parent must exist and sit exactly one
+// level up.
+func (s *LocationService) Create(ctx context.Context, req *models.CreateLocationRequest) (*models.LocationRef, error) {
+	wantParent := req.Level.ParentLevel()
+	hasParent := req.ParentID != nil && *req.ParentID != ""
+	switch {
+	case wantParent == "" && hasParent:
+		return nil, utils.NewBadRequestError("Provinces cannot have a parent", nil)
+	case wantParent != "" && !hasParent:
+		return nil, utils.NewBadRequestError("parent_id is required for "+strings.ToLower(string(req.Level))+"s", nil)
+	}
+	if hasParent {
+		parent, err := s.get(ctx, *req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.Level != wantParent {
+			return nil, utils.NewBadRequestError("Parent must be a "+strings.ToLower(string(wantParent)), nil)
+		}
+	}
+
+	loc := &models.LocationRef{
+		ID:         uuid.NewString(),
+		Level:      req.Level,
+		Name:       strings.TrimSpace(req.Name),
+		NameDari:   req.NameDari,
+		NamePashto: req.NamePashto,
+		IsActive:   true,
+	}
+	if hasParent {
+		loc.ParentID = req.ParentID
+	}
+	if req.Position != nil {
+		loc.Position = *req.Position
+	}
+
+	if err := s.locationRepo.Create(ctx, loc); err != nil {
+		if errors.Is(err, repositories.ErrLocationExists) {
+			return nil, utils.NewConflictError("A location with this name already exists here", err)
+		}
+		s.logger.Error("Failed to create location", zap.String("name", loc.Name), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to create location", err)
+	}
+	s.logger.Info("Location created", zap.String("location_id", loc.ID), zap.String("level", string(loc.Level)))
+
+	s.invalidateCache(ctx)
+	return loc, nil
+}
+
+// Update edits a location (admin). Renames rewrite the stored province /
+// district text on referencing rows.
+func (s *LocationService) Update(ctx context.Context, id string, req *models.UpdateLocationRequest) (*models.LocationRef, error) {
+	loc, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	renamed := false
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		renamed = name != loc.Name
+		loc.Name = name
+	}
+	if req.NameDari != nil {
+		loc.NameDari = req.NameDari
+	}
+	if req.NamePashto != nil {
+		loc.NamePashto = req.NamePashto
+	}
+	if req.IsActive != nil {
+		loc.IsActive = *req.IsActive
+	}
+	if req.Position != nil {
+		loc.Position = *req.Position
+	}
+
+	if err := s.locationRepo.Update(ctx, loc, renamed); err != nil {
+		if errors.Is(err, repositories.ErrLocationExists) {
+			return nil, utils.NewConflictError("A location with this name already exists here", err)
+		}
+		return nil, utils.NewInternalError("Failed to update location", err)
+	}
+
+	s.invalidateCache(ctx)
+	return loc, nil
+}
+
+// Delete removes a location and everything under it (admin). Prefer
+// deactivating entries that are in use; deleting only drops the link.
+func (s *LocationService) Delete(ctx context.Context, id string) error {
+	if err := s.locationRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repositories.ErrLocationNotFound) {
+			return utils.NewNotFoundError("Location not found", err)
+		}
+		return utils.NewInternalError("Failed to delete location", err)
+	}
+	s.logger.Info("Location deleted", zap.String("location_id", id))
+
+	s.invalidateCache(ctx)
+	return nil
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestLocationService_Create(t *testing.T) {
+	t.Run("district needs a parent", func(t *testing.T) {
+		repo := new(mocks.MockLocationRepository)
+		svc := NewLocationService(repo, zap.NewNop())
+
+		_, err := svc.Create(context.Background(), &models.CreateLocationRequest{Level: models.LocationDistrict, Name: "Paghman"})
+		requireAppErrCode(t, err, http.StatusBadRequest)
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("neighborhood cannot hang under a province", func(t *testing.T) {
+		repo := new(mocks.MockLocationRepository)
+		repo.On("GetByID", mock.Anything, "kabul").
+			Return(&models.LocationRef{ID: "kabul", Level: models.LocationProvince, Name: "Kabul"}, nil)
+		svc := NewLocationService(repo, zap.NewNop())
+
+		_, err := svc.Create(context.Background(), &models.CreateLocationRequest{
+			Level: models.LocationNeighborhood, ParentID: ptrStr("kabul"), Name: "Karte 3",
+		})
+		requireAppErrCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("creates district under province", func(t *testing.T) {
+		repo := new(mocks.MockLocationRepository)
+		repo.On("GetByID", mock.Anything, "kabul").
+			Return(&models.LocationRef{ID: "kabul", Level: models.LocationProvince, Name: "Kabul"}, nil)
+		repo.On("Create", mock.Anything, mock.MatchedBy(func(l *models.LocationRef) bool {
+			return l.Name == "Paghman" && *l.ParentID == "kabul" && l.IsActive
+		})).Return(nil)
+		svc := NewLocationService(repo, zap.NewNop())
+
+		got, err := svc.Create(context.Background(), &models.CreateLocationRequest{
+			Level: models.LocationDistrict, ParentID: ptrStr("kabul"), Name: " Paghman ",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, models.LocationDistrict, got.Level)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestLocationService_UpdateRename(t *testing.T) {
+	repo := new(mocks.MockLocationRepository)
+	repo.On("GetByID", mock.Anything, "p-1").
+		Return(&models.LocationRef{ID: "p-1", Level: models.LocationProvince, Name: "Sar-e Pol"}, nil)
+	repo.On("Update", mock.Anything, mock.Anything, true).Return(nil)
+	svc := NewLocationService(repo, zap.NewNop())
+
+	got, err := svc.Update(context.Background(), "p-1", &models.UpdateLocationRequest{Name: ptrStr("Sar-e Pul")})
+	require.NoError(t, err)
+	assert.Equal(t, "Sar-e Pul", got.Name)
+	repo.AssertExpectations(t)
+}
+
+func TestLocation_NameForLocale(t *testing.T) {
+	loc := &models.LocationRef{Name: "Kabul", NameDari: ptrStr("کابل")}
+	assert.Equal(t, "کابل", loc.NameForLocale(models.LocaleDari))
+	assert.Equal(t, "Kabul", loc.NameForLocale(models.LocalePashto))
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// loginFailureWindow is how far back failed logins count towards delays
+	// and CAPTCHA flags.
+	loginFailureWindow = 15 * time.Minute
+	// loginBaseDelay is the wait enforced after the first failure past a
+	// window's delay threshold; it doubles with every further failure.
+	loginBaseDelay = 2 * time.Second
+	// loginMaxDelay caps the progressive delay.
+	loginMaxDelay = time.Minute
+	// loginThrottledListLimit caps how many accounts ListThrottled returns.
+	loginThrottledListLimit = 200
+
+	loginGuardAccountsKey = "login_guard:accounts"
+)
+
+// loginWindow is one sliding window of failed logins (per account or per
+// IP) and the failure counts at which it starts delaying and asking for a
+// CAPTCHA.
+type loginWindow struct {
+	prefix       string
+	delayAfter   int
+	captchaAfter int
+}
+
+var (
+	// Per account: a person mistyping a password a couple of times never
+	// waits; a third failure starts the delay, a fifth asks for a CAPTCHA.
+	// MaxLoginAttempts still locks the account in the database at five.
+	accountLoginWindow = loginWindow{prefix: "login_guard:account:", delayAfter: 3, captchaAfter: 5}
+	// Per IP: looser, since many users share carrier and office NAT
+	// addresses, but it catches one host spraying many accounts.
+	ipLoginWindow = loginWindow{prefix: "login_guard:ip:", delayAfter: 20, captchaAfter: 10}
+)
+
+// delay is the wait required after the last failure once failures have
+// reached the window's threshold.
+func (w loginWindow) delay(failures int) time.Duration {
+	if failures < w.delayAfter {
+		return 0
+	}
+	shift := failures - w.delayAfter
+	if shift > 5 {
+		return loginMaxDelay
+	}
+	d := loginBaseDelay << shift
+	if d > loginMaxDelay {
+		d = loginMaxDelay
+	}
+	return d
+}
+
+// loginWindowState is what a window holds right now.
+type loginWindowState struct {
+	failures    int
+	lastFailure time.Time
+}
+
+// LoginThrottle is one account with recent failed logins, for the admin
+// locked-accounts view.
+type LoginThrottle struct {
+	Email             string    `json:"email"`
+	RecentFailures    int       `json:"recent_failures"`
+	LastFailureAt     time.Time `json:"last_failure_at"`
+	RetryAfterSeconds int       `json:"retry_after_seconds"`
+	CaptchaRequired   bool      `json:"captcha_required"`
+}
+
+// LoginGuard coordinates brute-force protection for password logins. It
+// keeps sliding windows of failed logins in Redis per account (lowercased
+// email) and per IP, makes each further attempt wait progressively longer
+// once a window passes its threshold, and flags the login response as
+// needing a CAPTCHA. LimitLoginAttempts still caps raw attempts per IP and
+// email in middleware; this guard only counts failures, so users who get
+// their password right aren't slowed by it.
+//
+// Like CreationThrottle it is soft on Redis errors (the middleware already
+// fails closed), and a nil *LoginGuard does nothing.
+type LoginGuard struct {
+	redis  *redis.Client
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+// NewLoginGuard creates a login guard.
+func NewLoginGuard(redisClient *redis.Client, logger *zap.Logger) *LoginGuard {
+	return &LoginGuard{
+		redis:  redisClient,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+func (g *LoginGuard) windows(email, ip string) map[loginWindow]string {
+	keys := make(map[loginWindow]string, 2)
+	if email != "" {
+		keys[accountLoginWindow] = accountLoginWindow.prefix + email
+	}
+	if ip != "" {
+		keys[ipLoginWindow] = ipLoginWindow.prefix + ip
+	}
+	return keys
+}
+
+// Check runs before the password is verified. It returns a 429 carrying a
+// LoginChallengeError while the progressive delay since the last failure
+// for the account or IP hasn't passed.
+func (g *LoginGuard) Check(ctx context.Context, email, ip string) error {
+	if g == nil || g.redis == nil {
+		return nil
+	}
+	now := g.now()
+	var wait time.Duration
+	captcha := false
+	for w, key := range g.windows(email, ip) {
+		st, err := g.state(ctx, key, now)
+		if err != nil {
+			g.logger.Warn("Login guard check failed; allowing", zap.String("key", key), zap.Error(err))
+			return nil
+		}
+		if remaining := st.lastFailure.Add(w.delay(st.failures)).Sub(now); remaining > wait {
+			wait = remaining
+		}
+		captcha = captcha || st.failures >= w.captchaAfter
+	}
+	if wait <= 0 {
+		return nil
+	}
+	g.logger.Info("Login delayed after repeated failures",
+		zap.String("email", email),
+		zap.String("ip", ip),
+		zap.Duration("retry_after", wait),
+	)
+	return utils.NewLoginThrottledError(wait, captcha)
+}
+
+// RecordFailure counts a failed login against the account and IP. It
+// returns the challenge the next attempt faces (a delay, a CAPTCHA or
+// both), or nil while neither applies.
+func (g *LoginGuard) RecordFailure(ctx context.Context, email, ip string) *utils.LoginChallengeError {
+	if g == nil || g.redis == nil {
+		return nil
+	}
+	now := g.now()
+	member := uuid.NewString()
+	var wait time.Duration
+	captcha := false
+	for w, key := range g.windows(email, ip) {
+		pipe := g.redis.TxPipeline()
+		pipe.ZRemRangeByScore(ctx, key, "0", fmt.Sprintf("%d", now.Add(-loginFailureWindow).UnixNano()))
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(now.UnixNano()), Member: member})
+		count := pipe.ZCard(ctx, key)
+		pipe.Expire(ctx, key, loginFailureWindow)
+		if w == accountLoginWindow {
+			pipe.ZAdd(ctx, loginGuardAccountsKey, redis.Z{Score: float64(now.Unix()), Member: email})
+			pipe.Expire(ctx, loginGuardAccountsKey, loginFailureWindow)
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			g.logger.Warn("Login guard failed to record failure", zap.String("key", key), zap.Error(err))
+			return nil
+		}
+		failures := int(count.Val())
+		if d := w.delay(failures); d > wait {
+			wait = d
+		}
+		captcha = captcha || failures >= w.captchaAfter
+	}
+	if wait <= 0 && !captcha {
+		return nil
+	}
+	return &utils.LoginChallengeError{
+		RetryAfterSeconds: int((wait + time.Second - 1) / time.Second),
+		CaptchaRequired:   captcha,
+	}
+}
+
+// RecordSuccess clears the account's failures after a correct password.
+// The IP window is left alone: logging into one's own account must not
+// reset the count for an address that is spraying others.
+func (g *LoginGuard) RecordSuccess(ctx context.Context, email string) {
+	if g == nil || g.redis == nil || email == "" {
+		return
+	}
+	if err := g.Clear(ctx, email); err != nil {
+		g.logger.Warn("Login guard failed to clear account", zap.String("email", email), zap.Error(err))
+	}
+}
+
+// Clear drops the account's failure window, lifting any delay or CAPTCHA
+// flag. Used on success and by admins unlocking an account.
+func (g *LoginGuard) Clear(ctx context.Context, email string) error {
+	if g == nil || g.redis == nil {
+		return nil
+	}
+	pipe := g.redis.TxPipeline()
+	pipe.Del(ctx, accountLoginWindow.prefix+email)
+	pipe.ZRem(ctx, loginGuardAccountsKey, email)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// ListThrottled returns accounts with failed logins inside the window,
+// most recent first.
+func (g *LoginGuard) ListThrottled(ctx context.Context) ([]LoginThrottle, error) {
+	out := make([]LoginThrottle, 0)
+	if g == nil || g.redis == nil {
+		return out, nil
+	}
+	now := g.now()
+	cutoff := fmt.Sprintf("%d", now.Add(-loginFailureWindow).Unix())
+	if err := g.redis.ZRemRangeByScore(ctx, loginGuardAccountsKey, "0", "("+cutoff).Err(); err != nil {
+		return nil, err
+	}
+	emails, err := g.redis.ZRevRange(ctx, loginGuardAccountsKey, 0, loginThrottledListLimit-1).Result()
+	if err != nil {
+		return nil, err
+	}
+	for _, email := range emails {
+		st, err := g.state(ctx, accountLoginWindow.prefix+email, now)
+		if err != nil {
+			return nil, err
+		}
+		if st.failures == 0 {
+			continue
+		}
+		wait := st.lastFailure.Add(accountLoginWindow.delay(st.failures)).Sub(now)
+		if wait < 0 {
+			wait = 0
+		}
+		out = append(out, LoginThrottle{
+			Email:             email,
+			RecentFailures:    st.failures,
+			LastFailureAt:     st.lastFailure,
+			RetryAfterSeconds: int((wait + time.Second - 1) / time.Second),
+			CaptchaRequired:   st.failures >= accountLoginWindow.captchaAfter,
+		})
+	}
+	return out, nil
+}
+
+// state prunes failures older than the window and returns what's left.
+func (g *LoginGuard) state(ctx context.Context, key string, now time.Time) (loginWindowState, error) {
+	pipe := g.redis.Pipeline()
+	pipe.ZRemRangeByScore(ctx, key, "0", fmt.Sprintf("%d", now.Add(-loginFailureWindow).UnixNano()))
+	count := pipe.ZCard(ctx, key)
+	last := pipe.ZRangeWithScores(ctx, key, -1, -1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return loginWindowState{}, err
+	}
+	st := loginWindowState{failures: int(count.Val())}
+	if zs := last.Val(); len(zs) > 0 {
+		st.lastFailure = time.Unix(0, int64(zs[0].Score))
+	}
+	return st, nil
+}
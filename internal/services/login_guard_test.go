@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestLoginGuard(t *testing.T) (*LoginGuard, *time.Time) {
+	mr := miniredis.RunT(t)
+	g := NewLoginGuard(redis.NewClient(&redis.Options{Addr: mr.Addr()}), zap.NewNop())
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	g.now = func() time.Time { return now }
+	return g, &now
+}
+
+func TestLoginGuard_ProgressiveDelay(t *testing.T) {
+	ctx := context.Background()
+	g, now := newTestLoginGuard(t)
+
+	for i := 1; i < accountLoginWindow.delayAfter; i++ {
+		assert.Nil(t, g.RecordFailure(ctx, "ali@example.com", "10.0.0.1"), "failure %d", i)
+		*now = now.Add(time.Second)
+	}
+	require.NoError(t, g.Check(ctx, "ali@example.com", "10.0.0.1"))
+
+	challenge := g.RecordFailure(ctx, "ali@example.com", "10.0.0.1")
+	require.NotNil(t, challenge)
+	assert.Equal(t, 2, challenge.RetryAfterSeconds)
+	assert.False(t, challenge.CaptchaRequired)
+
+	err := g.Check(ctx, "ali@example.com", "10.0.0.2")
+	var throttled *utils.LoginChallengeError
+	require.True(t, errors.As(err.(*utils.AppError).Err, &throttled))
+	assert.Equal(t, http.StatusTooManyRequests, err.(*utils.AppError).Code)
+	assert.Equal(t, 2, throttled.RetryAfterSeconds)
+
+	// Other accounts from the same IP aren't delayed.
+	assert.NoError(t, g.Check(ctx, "sara@example.com", "10.0.0.1"))
+
+	*now = now.Add(2 * time.Second)
+	assert.NoError(t, g.Check(ctx, "ali@example.com", "10.0.0.1"))
+
+	// The delay doubles and the CAPTCHA flag appears at the threshold.
+	g.RecordFailure(ctx, "ali@example.com", "10.0.0.1")
+	challenge = g.RecordFailure(ctx, "ali@example.com", "10.0.0.1")
+	require.NotNil(t, challenge)
+	assert.Equal(t, 8, challenge.RetryAfterSeconds)
+	assert.True(t, challenge.CaptchaRequired)
+
+	// Failures age out of the window.
+	*now = now.Add(loginFailureWindow + time.Second)
+	assert.NoError(t, g.Check(ctx, "ali@example.com", "10.0.0.1"))
+}
+
+func TestLoginGuard_IPWindowSpansAccounts(t *testing.T) {
+	ctx := context.Background()
+	g, _ := newTestLoginGuard(t)
+
+	var challenge *utils.LoginChallengeError
+	for i := 0; i < ipLoginWindow.captchaAfter; i++ {
+		challenge = g.RecordFailure(ctx, "user"+string(rune('a'+i))+"@example.com", "10.0.0.9")
+	}
+	require.NotNil(t, challenge)
+	assert.True(t, challenge.CaptchaRequired)
+	assert.Zero(t, challenge.RetryAfterSeconds)
+}
+
+func TestLoginGuard_SuccessAndListing(t *testing.T) {
+	ctx := context.Background()
+	g, now := newTestLoginGuard(t)
+
+	for i := 0; i < 3; i++ {
+		g.RecordFailure(ctx, "ali@example.com", "10.0.0.1")
+	}
+	*now = now.Add(time.Minute)
+	g.RecordFailure(ctx, "sara@example.com", "10.0.0.2")
+
+	throttled, err := g.ListThrottled(ctx)
+	require.NoError(t, err)
+	require.Len(t, throttled, 2)
+	assert.Equal(t, "sara@example.com", throttled[0].Email)
+	assert.Equal(t, "ali@example.com", throttled[1].Email)
+	assert.Equal(t, 3, throttled[1].RecentFailures)
+
+	g.RecordSuccess(ctx, "ali@example.com")
+	throttled, err = g.ListThrottled(ctx)
+	require.NoError(t, err)
+	require.Len(t, throttled, 1)
+	assert.Equal(t, "sara@example.com", throttled[0].Email)
+
+	var nilGuard *LoginGuard
+	assert.NoError(t, nilGuard.Check(ctx, "ali@example.com", "10.0.0.1"))
+	assert.Nil(t, nilGuard.RecordFailure(ctx, "ali@example.com", "10.0.0.1"))
+}
+
+func TestAuthService_LoginGuardChallenges(t *testing.T) {
+	ctx := context.Background()
+	tokenStorage, _ := newTestTokenStorage(t)
+	userRepo := new(mocks.MockUserRepository)
+	hash := testPasswordHash
+	user := &models.User{ID: "user-1", Email: "ali@example.com", PasswordHash: &hash}
+	userRepo.On("GetByEmail", ctx, "ali@example.com").Return(user, nil)
+	userRepo.On("UpdateLoginAttempts", ctx, "user-1", mock.Anything, mock.Anything).Return(nil)
+
+	guard, _ := newTestLoginGuard(t)
+	svc := newTestAuthService(userRepo, tokenStorage).WithLoginGuard(guard)
+	ip := "10.0.0.1"
+	req := &models.LoginRequest{Email: "Ali@example.com", Password: "wrong", IPAddress: &ip}
+
+	for i := 1; i < accountLoginWindow.delayAfter; i++ {
+		_, err := svc.Login(ctx, req)
+		requireAppErrorCode(t, err, http.StatusUnauthorized)
+		assert.Nil(t, err.(*utils.AppError).Err)
+	}
+
+	_, err := svc.Login(ctx, req)
+	requireAppErrorCode(t, err, http.StatusUnauthorized)
+	var challenge *utils.LoginChallengeError
+	require.True(t, errors.As(err.(*utils.AppError).Err, &challenge))
+	assert.Equal(t, 2, challenge.RetryAfterSeconds)
+
+	// The next attempt is refused before the password is checked.
+	_, err = svc.Login(ctx, req)
+	requireAppErrorCode(t, err, http.StatusTooManyRequests)
+	userRepo.AssertNumberOfCalls(t, "GetByEmail", accountLoginWindow.delayAfter)
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -9,21 +10,36 @@ import (
 	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 
+	"github.com/hamsaya/backend/internal/utils"
 	"github.com/hamsaya/backend/pkg/database"
 )
 
 // MediaModerationService runs the admin-facing /media-moderation queue:
 // list pending media, approve (no-op metadata change), reject (soft
-// delete the underlying attachment so it stops being served).
+// delete the underlying attachment so it stops being served). Business
+// images have a queue of their own; rejecting one takes it off the
+// business. MediaScanner fills in auto_labels and flags unsafe images.
 type MediaModerationService struct {
-	db     *database.DB
-	logger *zap.Logger
+	db         *database.DB
+	businesses businessMediaRemover
+	logger     *zap.Logger
+}
+
+// businessMediaRemover takes a rejected image off a business.
+type businessMediaRemover interface {
+	RemoveMedia(ctx context.Context, businessID, photoURL string) error
 }
 
 func NewMediaModerationService(db *database.DB, logger *zap.Logger) *MediaModerationService {
 	return &MediaModerationService{db: db, logger: logger}
 }
 
+// WithBusinesses enables rejecting business images.
+func (s *MediaModerationService) WithBusinesses(b businessMediaRemover) *MediaModerationService {
+	s.businesses = b
+	return s
+}
+
 // MediaModerationItem mirrors a queue row joined with the attachment
 // payload + author info so the UI can render previews + context.
 type MediaModerationItem struct {
@@ -42,11 +58,17 @@ type MediaModerationItem struct {
 	ReviewedBy    *string    `json:"reviewed_by,omitempty"`
 	ReviewerEmail *string    `json:"reviewer_email,omitempty"`
 	ReviewNotes   *string    `json:"review_notes,omitempty"`
+	// Flagged is set when the classifier judged the image unsafe;
+	// AutoLabels holds its output once the image was scanned.
+	Flagged    bool            `json:"flagged"`
+	AutoLabels json.RawMessage `json:"auto_labels,omitempty"`
+	BlurredURL *string         `json:"blurred_url,omitempty"`
 }
 
-// List returns up to `limit` items, optionally filtered by status.
-// Newest pending first by default; reviewed items grouped at the bottom.
-func (s *MediaModerationService) List(ctx context.Context, status string, limit int) ([]MediaModerationItem, error) {
+// List returns up to `limit` items, optionally filtered by status and to
+// flagged images. Pending first, flagged pending at the very top, newest
+// first within each group.
+func (s *MediaModerationService) List(ctx context.Context, status string, flaggedOnly bool, limit int) ([]MediaModerationItem, error) {
 	if limit <= 0 || limit > 200 {
 		limit = 50
 	}
@@ -57,7 +79,7 @@ func (s *MediaModerationService) List(ctx context.Context, status string, limit
 		       a.photo->>'url',  a.photo->>'name',  a.photo->>'mime_type',
 		       q.enqueued_at, q.status,
 		       q.reviewed_at, q.reviewed_by::text, COALESCE(rv.email,''),
-		       q.review_notes
+		       q.review_notes, q.flagged, q.auto_labels, q.blurred_url
 		FROM media_moderation_queue q
 		JOIN attachments a ON a.id = q.attachment_id
 		LEFT JOIN posts p   ON p.id = q.post_id
@@ -65,11 +87,15 @@ func (s *MediaModerationService) List(ctx context.Context, status string, limit
 		LEFT JOIN users rv  ON rv.id = q.reviewed_by
 	`
 	args := []interface{}{}
+	q += " WHERE TRUE"
 	if status != "" {
-		q += " WHERE q.status = $1"
 		args = append(args, status)
+		q += " AND q.status = $" + fmt.Sprint(len(args))
 	}
-	q += " ORDER BY (q.status = 'pending') DESC, q.enqueued_at DESC LIMIT $" + fmt.Sprint(len(args)+1)
+	if flaggedOnly {
+		q += " AND q.flagged"
+	}
+	q += " ORDER BY (q.status = 'pending') DESC, (q.status = 'pending' AND q.flagged) DESC, q.enqueued_at DESC LIMIT $" + fmt.Sprint(len(args)+1)
 	args = append(args, limit)
 
 	rows, err := s.db.Pool.Query(ctx, q, args...)
@@ -89,7 +115,7 @@ func (s *MediaModerationService) List(ctx context.Context, status string, limit
 			&it.MediaURL, &it.MediaName, &it.MimeType,
 			&it.EnqueuedAt, &it.Status,
 			&it.ReviewedAt, &it.ReviewedBy, &reviewerEmail,
-			&it.ReviewNotes,
+			&it.ReviewNotes, &it.Flagged, &it.AutoLabels, &it.BlurredURL,
 		); err != nil {
 			return nil, err
 		}
@@ -101,15 +127,22 @@ func (s *MediaModerationService) List(ctx context.Context, status string, limit
 	return out, nil
 }
 
-// Counts returns the size of each status bucket. Used for the queue
-// dashboard cards.
+// Counts returns the size of each status bucket, plus "flagged" for
+// flagged images still pending. Used for the queue dashboard cards.
 func (s *MediaModerationService) Counts(ctx context.Context) (map[string]int64, error) {
-	rows, err := s.db.Pool.Query(ctx, `SELECT status, COUNT(*) FROM media_moderation_queue GROUP BY status`)
+	return s.counts(ctx, "media_moderation_queue")
+}
+
+func (s *MediaModerationService) counts(ctx context.Context, table string) (map[string]int64, error) {
+	rows, err := s.db.Pool.Query(ctx, `
+		SELECT status, COUNT(*) FROM `+table+` GROUP BY status
+		UNION ALL
+		SELECT 'flagged', COUNT(*) FROM `+table+` WHERE status = 'pending' AND flagged`)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	out := map[string]int64{"pending": 0, "approved": 0, "rejected": 0}
+	out := map[string]int64{"pending": 0, "approved": 0, "rejected": 0, "flagged": 0}
 	for rows.Next() {
 		var status string
 		var n int64
@@ -137,12 +170,12 @@ func (s *MediaModerationService) Approve(ctx context.Context, attachmentID, admi
 		attachmentID,
 	).Scan(&prevStatus); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return fmt.Errorf("queue row not found")
+			return utils.NewNotFoundError("Queue row not found", nil)
 		}
 		return err
 	}
 	if prevStatus == "approved" {
-		return fmt.Errorf("already approved")
+		return utils.NewConflictError("Already approved", nil)
 	}
 
 	if _, err := tx.Exec(ctx, `
@@ -170,7 +203,7 @@ func (s *MediaModerationService) Approve(ctx context.Context, attachmentID, admi
 // the rejection signals broader abuse.
 func (s *MediaModerationService) Reject(ctx context.Context, attachmentID, adminID, notes string) error {
 	if notes == "" {
-		return fmt.Errorf("rejection requires notes")
+		return utils.NewBadRequestError("Rejection requires notes", nil)
 	}
 	tx, err := s.db.Pool.Begin(ctx)
 	if err != nil {
@@ -185,12 +218,12 @@ func (s *MediaModerationService) Reject(ctx context.Context, attachmentID, admin
 		attachmentID,
 	).Scan(&status); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return fmt.Errorf("queue row not found")
+			return utils.NewNotFoundError("Queue row not found", nil)
 		}
 		return err
 	}
 	if status != "pending" {
-		return fmt.Errorf("already %s; cannot reject", status)
+		return utils.NewConflictError(fmt.Sprintf("Already %s; cannot reject", status), nil)
 	}
 
 	if _, err := tx.Exec(ctx, `
@@ -209,3 +242,128 @@ func (s *MediaModerationService) Reject(ctx context.Context, attachmentID, admin
 	}
 	return tx.Commit(ctx)
 }
+
+// BusinessMediaItem is a business image in the review queue.
+type BusinessMediaItem struct {
+	ID           string          `json:"id"`
+	BusinessID   string          `json:"business_id"`
+	BusinessName string          `json:"business_name"`
+	OwnerID      string          `json:"owner_id"`
+	MediaURL     string          `json:"media_url"`
+	EnqueuedAt   time.Time       `json:"enqueued_at"`
+	Status       string          `json:"status"`
+	ReviewedAt   *time.Time      `json:"reviewed_at,omitempty"`
+	ReviewedBy   *string         `json:"reviewed_by,omitempty"`
+	ReviewNotes  *string         `json:"review_notes,omitempty"`
+	Flagged      bool            `json:"flagged"`
+	AutoLabels   json.RawMessage `json:"auto_labels,omitempty"`
+	BlurredURL   *string         `json:"blurred_url,omitempty"`
+}
+
+// ListBusiness returns business images in review, ordered like List.
+func (s *MediaModerationService) ListBusiness(ctx context.Context, status string, flaggedOnly bool, limit int) ([]BusinessMediaItem, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	q := `
+		SELECT q.id::text, q.business_id::text, COALESCE(b.name, ''), COALESCE(b.user_id::text, ''),
+		       q.url, q.enqueued_at, q.status,
+		       q.reviewed_at, q.reviewed_by::text, q.review_notes,
+		       q.flagged, q.auto_labels, q.blurred_url
+		FROM business_media_moderation_queue q
+		LEFT JOIN business_profiles b ON b.id = q.business_id
+		WHERE TRUE
+	`
+	args := []interface{}{}
+	if status != "" {
+		args = append(args, status)
+		q += " AND q.status = $" + fmt.Sprint(len(args))
+	}
+	if flaggedOnly {
+		q += " AND q.flagged"
+	}
+	q += " ORDER BY (q.status = 'pending') DESC, (q.status = 'pending' AND q.flagged) DESC, q.enqueued_at DESC LIMIT $" + fmt.Sprint(len(args)+1)
+	args = append(args, limit)
+
+	rows, err := s.db.Pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]BusinessMediaItem, 0, limit)
+	for rows.Next() {
+		var it BusinessMediaItem
+		if err := rows.Scan(
+			&it.ID, &it.BusinessID, &it.BusinessName, &it.OwnerID,
+			&it.MediaURL, &it.EnqueuedAt, &it.Status,
+			&it.ReviewedAt, &it.ReviewedBy, &it.ReviewNotes,
+			&it.Flagged, &it.AutoLabels, &it.BlurredURL,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, it)
+	}
+	return out, rows.Err()
+}
+
+// BusinessCounts is Counts for the business image queue.
+func (s *MediaModerationService) BusinessCounts(ctx context.Context) (map[string]int64, error) {
+	return s.counts(ctx, "business_media_moderation_queue")
+}
+
+// ApproveBusiness marks a pending business image approved. Rejected
+// images are gone from the business and can't be approved back.
+func (s *MediaModerationService) ApproveBusiness(ctx context.Context, id, adminID, notes string) error {
+	tag, err := s.db.Pool.Exec(ctx, `
+		UPDATE business_media_moderation_queue
+		SET status='approved', reviewed_at=NOW(), reviewed_by=$1, review_notes=NULLIF($2,'')
+		WHERE id=$3 AND status='pending'
+	`, adminID, notes, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return utils.NewNotFoundError("Queue row not found or already reviewed", nil)
+	}
+	return nil
+}
+
+// RejectBusiness marks a pending business image rejected and takes it off
+// the business (avatar, cover or gallery).
+func (s *MediaModerationService) RejectBusiness(ctx context.Context, id, adminID, notes string) error {
+	if notes == "" {
+		return utils.NewBadRequestError("Rejection requires notes", nil)
+	}
+	if s.businesses == nil {
+		return utils.NewNotImplementedError("Business media moderation is not configured", nil)
+	}
+
+	var businessID, url, status string
+	if err := s.db.Pool.QueryRow(ctx,
+		`SELECT business_id::text, url, status FROM business_media_moderation_queue WHERE id=$1`,
+		id,
+	).Scan(&businessID, &url, &status); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return utils.NewNotFoundError("Queue row not found", nil)
+		}
+		return err
+	}
+	if status != "pending" {
+		return utils.NewConflictError(fmt.Sprintf("Already %s; cannot reject", status), nil)
+	}
+
+	// Take the image down first: if that fails the row stays pending and
+	// the admin can retry.
+	if err := s.businesses.RemoveMedia(ctx, businessID, url); err != nil {
+		return err
+	}
+	if _, err := s.db.Pool.Exec(ctx, `
+		UPDATE business_media_moderation_queue
+		SET status='rejected', reviewed_at=NOW(), reviewed_by=$1, review_notes=$2
+		WHERE id=$3
+	`, adminID, notes, id); err != nil {
+		return err
+	}
+	return nil
+}
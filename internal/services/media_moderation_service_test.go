@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -15,8 +16,8 @@ import (
 func TestMediaModerationService_RejectRequiresNotes(t *testing.T) {
 	svc := &MediaModerationService{db: nil, logger: zap.NewNop()}
 	err := svc.Reject(context.Background(), "att-id", "admin-id", "")
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "rejection requires notes")
+	requireAppErrCode(t, err, http.StatusBadRequest)
+	assert.Contains(t, err.Error(), "Rejection requires notes")
 }
 
 func TestNewMediaModerationService_ConstructorWires(t *testing.T) {
@@ -0,0 +1,281 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/jpeg"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/disintegration/imaging"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/pkg/events"
+	"github.com/hamsaya/backend/pkg/nsfw"
+	"github.com/hamsaya/backend/pkg/storage"
+	"go.uber.org/zap"
+)
+
+const (
+	// mediaScanBatch is how many queued images one worker pass scans.
+	mediaScanBatch = 50
+	// mediaScanMaxAttempts gives up on an image the classifier or storage
+	// keeps failing on.
+	mediaScanMaxAttempts = 5
+	// mediaScanMaxSize matches the image upload limit.
+	mediaScanMaxSize = 10 * 1024 * 1024
+	// blurredFolder holds the blurred copies served for flagged images.
+	blurredFolder = "blurred"
+)
+
+// mediaStore is the part of the storage client the scanner uses.
+type mediaStore interface {
+	ReadByURL(ctx context.Context, url string, maxSize int64) ([]byte, error)
+	UploadImage(ctx context.Context, reader io.Reader, contentType, folder string) (*storage.UploadResult, error)
+}
+
+// MediaScanner is the automatic half of media moderation. Uploaded images
+// land in the moderation queues; the scanner (ProcessPending, run on a
+// ticker by the leader) sends each one to the classifier, stores the
+// output as the row's auto_labels and flags unsafe images so they top the
+// admin review queue. Uploads never wait on the classifier.
+//
+// With blur enabled, post responses serve a blurred copy of a flagged
+// attachment until an admin approves or rejects it. Held avatars and
+// covers that scan safe are approved right away.
+//
+// A nil *MediaScanner does nothing, so services work unwired.
+type MediaScanner struct {
+	scanRepo   repositories.MediaScanRepository
+	classifier nsfw.Classifier
+	name       string
+	store      mediaStore
+	blur       bool
+	profiles   profileMediaApprover
+	logger     *zap.Logger
+}
+
+// profileMediaApprover puts held avatars and covers live. Implemented by
+// ProfileMediaService.
+type profileMediaApprover interface {
+	AutoApprove(ctx context.Context, reviewID string) error
+}
+
+// NewMediaScanner creates the scanner. name identifies the classifier in
+// the stored labels (e.g. "nudenet").
+func NewMediaScanner(
+	scanRepo repositories.MediaScanRepository,
+	classifier nsfw.Classifier,
+	name string,
+	store mediaStore,
+	blur bool,
+	logger *zap.Logger,
+) *MediaScanner {
+	return &MediaScanner{
+		scanRepo:   scanRepo,
+		classifier: classifier,
+		name:       name,
+		store:      store,
+		blur:       blur,
+		logger:     logger,
+	}
+}
+
+// WithProfileMedia approves held profile media the classifier scores safe.
+func (m *MediaScanner) WithProfileMedia(a profileMediaApprover) *MediaScanner {
+	if m != nil {
+		m.profiles = a
+	}
+	return m
+}
+
+// SubscribeBusinessMedia queues new business images for review.
+func (m *MediaScanner) SubscribeBusinessMedia(bus *events.Bus) {
+	if m == nil {
+		return
+	}
+	events.Subscribe(bus, func(ctx context.Context, e BusinessMediaUploaded) {
+		if err := m.scanRepo.EnqueueBusinessMedia(ctx, e.BusinessID, e.URL); err != nil {
+			m.logger.Warn("Failed to queue business media for moderation",
+				zap.String("business_id", e.BusinessID),
+				zap.String("url", e.URL),
+				zap.Error(err),
+			)
+		}
+	})
+}
+
+// ProcessPending scans a batch of queued images. An image that can't be
+// read or scanned is retried on later passes until it runs out of
+// attempts.
+func (m *MediaScanner) ProcessPending(ctx context.Context) error {
+	if m == nil {
+		return nil
+	}
+	jobs, err := m.scanRepo.ListUnscanned(ctx, mediaScanBatch)
+	if err != nil {
+		return err
+	}
+	var flagged int
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if m.scan(ctx, job) {
+			flagged++
+		}
+	}
+	if len(jobs) > 0 {
+		m.logger.Info("Media scan pass finished", zap.Int("scanned", len(jobs)), zap.Int("flagged", flagged))
+	}
+	return nil
+}
+
+// scan classifies one image and reports whether it was flagged.
+func (m *MediaScanner) scan(ctx context.Context, job *models.MediaScanJob) bool {
+	if !isScannableImage(job.URL, job.MimeType) {
+		m.record(ctx, job, &models.MediaScanLabels{Skipped: "not an image"}, false, nil)
+		return false
+	}
+	data, err := m.store.ReadByURL(ctx, job.URL, mediaScanMaxSize)
+	if err != nil {
+		m.recordFailure(ctx, job, err)
+		return false
+	}
+	res := m.classifier.Scan(ctx, data, path.Base(job.URL), job.MimeType)
+	if res.ScannerError != nil {
+		m.recordFailure(ctx, job, res.ScannerError)
+		return false
+	}
+
+	var blurredURL *string
+	if res.IsExplicit {
+		if m.blur {
+			blurredURL = m.uploadBlurred(ctx, data)
+		}
+		m.logger.Warn("Media flagged for review",
+			zap.String("attachment_id", job.AttachmentID),
+			zap.String("business_media_id", job.BusinessMediaID),
+			zap.String("profile_media_id", job.ProfileMediaID),
+			zap.String("top_class", res.TopClass),
+			zap.Float64("top_score", res.TopScore),
+		)
+	}
+	m.record(ctx, job, &models.MediaScanLabels{
+		IsExplicit: res.IsExplicit,
+		TopClass:   res.TopClass,
+		TopScore:   res.TopScore,
+	}, res.IsExplicit, blurredURL)
+	if job.ProfileMediaID != "" && !res.IsExplicit && m.profiles != nil {
+		if err := m.profiles.AutoApprove(ctx, job.ProfileMediaID); err != nil {
+			m.logger.Warn("Failed to auto-approve profile media", zap.String("profile_media_id", job.ProfileMediaID), zap.Error(err))
+		}
+	}
+	return res.IsExplicit
+}
+
+func (m *MediaScanner) record(ctx context.Context, job *models.MediaScanJob, labels *models.MediaScanLabels, flagged bool, blurredURL *string) {
+	labels.Classifier = m.name
+	labels.ScannedAt = time.Now()
+	if err := m.scanRepo.RecordScan(ctx, job, labels, flagged, blurredURL); err != nil {
+		m.logger.Warn("Failed to record media scan", zap.String("url", job.URL), zap.Error(err))
+	}
+}
+
+func (m *MediaScanner) recordFailure(ctx context.Context, job *models.MediaScanJob, cause error) {
+	m.logger.Warn("Media scan failed",
+		zap.String("url", job.URL),
+		zap.Int("attempts", job.Attempts+1),
+		zap.Error(cause),
+	)
+	if err := m.scanRepo.RecordScanFailure(ctx, job, cause.Error(), mediaScanMaxAttempts); err != nil {
+		m.logger.Warn("Failed to record media scan failure", zap.String("url", job.URL), zap.Error(err))
+	}
+}
+
+// isScannableImage reports whether a queued file is an image. Bare URLs
+// without a MIME type are judged by extension.
+func isScannableImage(url, mimeType string) bool {
+	if mimeType != "" {
+		return strings.HasPrefix(mimeType, "image/")
+	}
+	switch strings.ToLower(path.Ext(url)) {
+	case ".jpg", ".jpeg", ".png", ".webp", ".gif":
+		return true
+	}
+	return false
+}
+
+// uploadBlurred stores a small, heavily blurred JPEG of the image and
+// returns its URL, or nil when that fails.
+func (m *MediaScanner) uploadBlurred(ctx context.Context, data []byte) *string {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		m.logger.Warn("Failed to decode flagged image for blurring", zap.Error(err))
+		return nil
+	}
+	blurred := imaging.Blur(imaging.Fit(img, 480, 480, imaging.Linear), 20)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, blurred, &jpeg.Options{Quality: 70}); err != nil {
+		m.logger.Warn("Failed to encode blurred image", zap.Error(err))
+		return nil
+	}
+	result, err := m.store.UploadImage(ctx, &buf, "image/jpeg", blurredFolder)
+	if err != nil {
+		m.logger.Warn("Failed to upload blurred image", zap.Error(err))
+		return nil
+	}
+	return &result.URL
+}
+
+// BlurFlagged marks flagged, unreviewed post attachments sensitive and
+// swaps them for their blurred copies. Does nothing unless blurring is
+// enabled.
+func (m *MediaScanner) BlurFlagged(ctx context.Context, responses []*models.PostResponse) {
+	if m == nil || !m.blur {
+		return
+	}
+	var ids []string
+	for _, r := range responses {
+		if r == nil {
+			continue
+		}
+		for _, a := range r.Attachments {
+			ids = append(ids, a.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	flagged, err := m.scanRepo.FlaggedAttachments(ctx, ids)
+	if err != nil {
+		m.logger.Warn("Failed to look up flagged attachments", zap.Error(err))
+		return
+	}
+	if len(flagged) == 0 {
+		return
+	}
+	for _, r := range responses {
+		if r == nil {
+			continue
+		}
+		for i := range r.Attachments {
+			blurred, ok := flagged[r.Attachments[i].ID]
+			if !ok {
+				continue
+			}
+			a := &r.Attachments[i]
+			a.Sensitive = true
+			if blurred != nil {
+				a.Photo.URL = *blurred
+				a.Photo.ThumbURL = *blurred
+				a.Photo.MediumURL = *blurred
+			}
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"testing"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/nsfw"
+	"github.com/hamsaya/backend/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeClassifier struct {
+	result nsfw.Result
+	calls  int
+}
+
+func (f *fakeClassifier) Scan(ctx context.Context, data []byte, filename, contentType string) nsfw.Result {
+	f.calls++
+	return f.result
+}
+
+type fakeMediaStore struct {
+	data     []byte
+	readErr  error
+	uploaded int
+}
+
+func (f *fakeMediaStore) ReadByURL(ctx context.Context, url string, maxSize int64) ([]byte, error) {
+	return f.data, f.readErr
+}
+
+func (f *fakeMediaStore) UploadImage(ctx context.Context, reader io.Reader, contentType, folder string) (*storage.UploadResult, error) {
+	f.uploaded++
+	_, _ = io.Copy(io.Discard, reader)
+	return &storage.UploadResult{URL: "https://cdn.example.com/" + folder + "/b.jpg"}, nil
+}
+
+type fakeProfileMediaApprover struct {
+	approved []string
+}
+
+func (f *fakeProfileMediaApprover) AutoApprove(ctx context.Context, reviewID string) error {
+	f.approved = append(f.approved, reviewID)
+	return nil
+}
+
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 32, 32))
+	for x := 0; x < 32; x++ {
+		img.Set(x, x, color.RGBA{R: 255, A: 255})
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestMediaScanner_ProcessPending(t *testing.T) {
+	ctx := context.Background()
+	job := &models.MediaScanJob{AttachmentID: "att-1", URL: "https://cdn.example.com/posts/a.png", MimeType: "image/png"}
+
+	t.Run("safe image is recorded unflagged", func(t *testing.T) {
+		repo := new(mocks.MockMediaScanRepository)
+		repo.On("ListUnscanned", ctx, mediaScanBatch).Return([]*models.MediaScanJob{job}, nil)
+		repo.On("RecordScan", ctx, job, mock.MatchedBy(func(l *models.MediaScanLabels) bool {
+			return l.Classifier == "nudenet" && !l.IsExplicit
+		}), false, (*string)(nil)).Return(nil)
+
+		store := &fakeMediaStore{data: testPNG(t)}
+		scanner := NewMediaScanner(repo, &fakeClassifier{}, "nudenet", store, true, zap.NewNop())
+		require.NoError(t, scanner.ProcessPending(ctx))
+		assert.Zero(t, store.uploaded)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("explicit image is flagged with a blurred copy", func(t *testing.T) {
+		repo := new(mocks.MockMediaScanRepository)
+		repo.On("ListUnscanned", ctx, mediaScanBatch).Return([]*models.MediaScanJob{job}, nil)
+		repo.On("RecordScan", ctx, job, mock.MatchedBy(func(l *models.MediaScanLabels) bool {
+			return l.IsExplicit && l.TopClass == "FEMALE_BREAST_EXPOSED"
+		}), true, mock.MatchedBy(func(u *string) bool {
+			return u != nil && *u == "https://cdn.example.com/blurred/b.jpg"
+		})).Return(nil)
+
+		classifier := &fakeClassifier{result: nsfw.Result{IsExplicit: true, TopClass: "FEMALE_BREAST_EXPOSED", TopScore: 0.9}}
+		store := &fakeMediaStore{data: testPNG(t)}
+		scanner := NewMediaScanner(repo, classifier, "nudenet", store, true, zap.NewNop())
+		require.NoError(t, scanner.ProcessPending(ctx))
+		assert.Equal(t, 1, store.uploaded)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("no blurred copy when blurring is off", func(t *testing.T) {
+		repo := new(mocks.MockMediaScanRepository)
+		repo.On("ListUnscanned", ctx, mediaScanBatch).Return([]*models.MediaScanJob{job}, nil)
+		repo.On("RecordScan", ctx, job, mock.Anything, true, (*string)(nil)).Return(nil)
+
+		classifier := &fakeClassifier{result: nsfw.Result{IsExplicit: true}}
+		store := &fakeMediaStore{data: testPNG(t)}
+		scanner := NewMediaScanner(repo, classifier, "nudenet", store, false, zap.NewNop())
+		require.NoError(t, scanner.ProcessPending(ctx))
+		assert.Zero(t, store.uploaded)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("classifier error counts a failed attempt", func(t *testing.T) {
+		repo := new(mocks.MockMediaScanRepository)
+		repo.On("ListUnscanned", ctx, mediaScanBatch).Return([]*models.MediaScanJob{job}, nil)
+		repo.On("RecordScanFailure", ctx, job, "scanner down", mediaScanMaxAttempts).Return(nil)
+
+		classifier := &fakeClassifier{result: nsfw.Result{ScannerError: errors.New("scanner down")}}
+		scanner := NewMediaScanner(repo, classifier, "nudenet", &fakeMediaStore{data: testPNG(t)}, true, zap.NewNop())
+		require.NoError(t, scanner.ProcessPending(ctx))
+		repo.AssertExpectations(t)
+		repo.AssertNotCalled(t, "RecordScan", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("non-images are skipped without the classifier", func(t *testing.T) {
+		video := &models.MediaScanJob{BusinessMediaID: "bm-1", URL: "https://cdn.example.com/b/clip.mp4"}
+		repo := new(mocks.MockMediaScanRepository)
+		repo.On("ListUnscanned", ctx, mediaScanBatch).Return([]*models.MediaScanJob{video}, nil)
+		repo.On("RecordScan", ctx, video, mock.MatchedBy(func(l *models.MediaScanLabels) bool {
+			return l.Skipped != ""
+		}), false, (*string)(nil)).Return(nil)
+
+		classifier := &fakeClassifier{}
+		scanner := NewMediaScanner(repo, classifier, "nudenet", &fakeMediaStore{}, true, zap.NewNop())
+		require.NoError(t, scanner.ProcessPending(ctx))
+		assert.Zero(t, classifier.calls)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("held profile media is approved only when safe", func(t *testing.T) {
+		avatar := &models.MediaScanJob{ProfileMediaID: "pm-1", URL: "https://cdn.example.com/avatars/a.png", MimeType: "image/png"}
+		for _, explicit := range []bool{false, true} {
+			repo := new(mocks.MockMediaScanRepository)
+			repo.On("ListUnscanned", ctx, mediaScanBatch).Return([]*models.MediaScanJob{avatar}, nil)
+			repo.On("RecordScan", ctx, avatar, mock.Anything, explicit, (*string)(nil)).Return(nil)
+
+			approver := &fakeProfileMediaApprover{}
+			classifier := &fakeClassifier{result: nsfw.Result{IsExplicit: explicit}}
+			scanner := NewMediaScanner(repo, classifier, "nudenet", &fakeMediaStore{data: testPNG(t)}, false, zap.NewNop()).
+				WithProfileMedia(approver)
+			require.NoError(t, scanner.ProcessPending(ctx))
+			if explicit {
+				assert.Empty(t, approver.approved, "flagged images wait for an admin")
+			} else {
+				assert.Equal(t, []string{"pm-1"}, approver.approved)
+			}
+		}
+	})
+
+	t.Run("nil scanner is a no-op", func(t *testing.T) {
+		var scanner *MediaScanner
+		assert.NoError(t, scanner.ProcessPending(ctx))
+	})
+}
+
+func TestMediaScanner_BlurFlagged(t *testing.T) {
+	ctx := context.Background()
+	blurred := "https://cdn.example.com/blurred/b.jpg"
+	posts := []*models.PostResponse{{
+		Attachments: []models.AttachmentResponse{
+			{ID: "att-1", Photo: models.Photo{URL: "https://cdn.example.com/a.jpg"}},
+			{ID: "att-2", Photo: models.Photo{URL: "https://cdn.example.com/c.jpg"}},
+		},
+	}}
+
+	repo := new(mocks.MockMediaScanRepository)
+	repo.On("FlaggedAttachments", ctx, []string{"att-1", "att-2"}).Return(map[string]*string{"att-1": &blurred}, nil)
+
+	scanner := NewMediaScanner(repo, &fakeClassifier{}, "nudenet", &fakeMediaStore{}, true, zap.NewNop())
+	scanner.BlurFlagged(ctx, posts)
+
+	a := posts[0].Attachments
+	assert.True(t, a[0].Sensitive)
+	assert.Equal(t, blurred, a[0].Photo.URL)
+	assert.Equal(t, blurred, a[0].Photo.ThumbURL)
+	assert.False(t, a[1].Sensitive)
+	assert.Equal(t, "https://cdn.example.com/c.jpg", a[1].Photo.URL)
+
+	// Blurring off: responses are left alone and the repo isn't asked.
+	off := new(mocks.MockMediaScanRepository)
+	NewMediaScanner(off, &fakeClassifier{}, "nudenet", &fakeMediaStore{}, false, zap.NewNop()).BlurFlagged(ctx, posts)
+	off.AssertNotCalled(t, "FlaggedAttachments", mock.Anything, mock.Anything)
+}
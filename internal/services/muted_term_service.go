@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/hamsaya/backend/pkg/cache"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxMutedTerms caps how many keywords and hashtags one user can mute.
+	// Every term is a regex in the feed query, so the list stays short.
+	maxMutedTerms = 100
+	// mutedTermsTTL bounds how stale a cached term list can get; changes
+	// bust it right away.
+	mutedTermsTTL = 10 * time.Minute
+)
+
+// MutedTermService manages the keywords and hashtags users muted and
+// answers whether a post should be hidden from them.
+type MutedTermService struct {
+	repo     repositories.MutedTermRepository
+	postRepo repositories.PostRepository
+	cache    *cache.Cache // optional; nil = read the terms on every call
+	logger   *zap.Logger
+}
+
+// NewMutedTermService creates a new muted term service.
+func NewMutedTermService(repo repositories.MutedTermRepository, postRepo repositories.PostRepository, logger *zap.Logger) *MutedTermService {
+	return &MutedTermService{repo: repo, postRepo: postRepo, logger: logger}
+}
+
+// WithCache caches each user's term list, which every feed page and
+// post notification reads.
+func (s *MutedTermService) WithCache(c *cache.Cache) *MutedTermService {
+	s.cache = c
+	return s
+}
+
+// normalizeMutedTerm lowercases a term and collapses its whitespace. A
+// leading # is kept: "#tag" mutes the hashtag, "tag" mutes the word and
+// the hashtag.
+func normalizeMutedTerm(raw string) (string, error) {
+	term := strings.ToLower(strings.Join(strings.Fields(raw), " "))
+	if utf8.RuneCountInString(strings.TrimPrefix(term, "#")) < 2 {
+		return "", utils.NewBadRequestError("Muted term must be at least 2 characters", nil)
+	}
+	if utf8.RuneCountInString(term) > 100 {
+		return "", utils.NewBadRequestError("Muted term must be at most 100 characters", nil)
+	}
+	return term, nil
+}
+
+// List returns the user's muted terms, newest first.
+func (s *MutedTermService) List(ctx context.Context, userID string) ([]*models.MutedTerm, error) {
+	terms, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to list muted terms", zap.String("user_id", userID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to list muted terms", err)
+	}
+	return terms, nil
+}
+
+// Add mutes a keyword or hashtag for the user.
+func (s *MutedTermService) Add(ctx context.Context, userID string, req *models.AddMutedTermRequest) (*models.MutedTerm, error) {
+	term, err := normalizeMutedTerm(req.Term)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.repo.CountByUser(ctx, userID)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to mute term", err)
+	}
+	if count >= maxMutedTerms {
+		return nil, utils.NewBadRequestError("You can mute at most 100 terms", nil)
+	}
+
+	muted := &models.MutedTerm{UserID: userID, Term: term}
+	if err := s.repo.Create(ctx, muted); err != nil {
+		if errors.Is(err, repositories.ErrMutedTermExists) {
+			return nil, utils.NewConflictError("You already muted this term", err)
+		}
+		s.logger.Error("Failed to mute term", zap.String("user_id", userID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to mute term", err)
+	}
+	s.cache.Del(ctx, userID)
+	return muted, nil
+}
+
+// Delete unmutes one of the user's terms.
+func (s *MutedTermService) Delete(ctx context.Context, userID, termID string) error {
+	if _, err := uuid.Parse(termID); err != nil {
+		return utils.NewNotFoundError("Muted term not found", nil)
+	}
+	if err := s.repo.Delete(ctx, userID, termID); err != nil {
+		if errors.Is(err, repositories.ErrMutedTermNotFound) {
+			return utils.NewNotFoundError("Muted term not found", err)
+		}
+		s.logger.Error("Failed to unmute term", zap.String("user_id", userID), zap.Error(err))
+		return utils.NewInternalError("Failed to unmute term", err)
+	}
+	s.cache.Del(ctx, userID)
+	return nil
+}
+
+// Terms returns the user's muted terms for filtering, cached. Errors are
+// logged and filter nothing: a failed lookup must not break the feed.
+func (s *MutedTermService) Terms(ctx context.Context, userID string) []string {
+	if s == nil || userID == "" {
+		return nil
+	}
+	var terms []string
+	if hit, _ := s.cache.Get(ctx, userID, &terms); hit {
+		return terms
+	}
+	muted, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		s.logger.Warn("Failed to load muted terms", zap.String("user_id", userID), zap.Error(err))
+		return nil
+	}
+	terms = make([]string, 0, len(muted))
+	for _, m := range muted {
+		terms = append(terms, m.Term)
+	}
+	_ = s.cache.Set(ctx, userID, terms, mutedTermsTTL)
+	return terms
+}
+
+// FilterPosts drops the posts that contain one of the user's muted terms,
+// for feeds not built by the feed query (which filters them in SQL).
+func (s *MutedTermService) FilterPosts(ctx context.Context, userID string, posts []*models.Post) []*models.Post {
+	terms := s.Terms(ctx, userID)
+	if len(terms) == 0 {
+		return posts
+	}
+	kept := make([]*models.Post, 0, len(posts))
+	for _, post := range posts {
+		if !containsMutedTerm(postText(post), terms) {
+			kept = append(kept, post)
+		}
+	}
+	return kept
+}
+
+// MutesPost reports whether the post contains one of the user's muted
+// terms. Used to drop notifications about such posts.
+func (s *MutedTermService) MutesPost(ctx context.Context, userID, postID string) bool {
+	terms := s.Terms(ctx, userID)
+	if len(terms) == 0 || postID == "" {
+		return false
+	}
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return false
+	}
+	return containsMutedTerm(postText(post), terms)
+}
+
+// postText is the text muted terms are matched against.
+func postText(post *models.Post) string {
+	return stringOrEmpty(post.Title) + " " + stringOrEmpty(post.Description)
+}
+
+// containsMutedTerm reports whether text contains one of terms as a whole
+// word, ignoring case. Mirrors the feed query's mutedTermPattern.
+func containsMutedTerm(text string, terms []string) bool {
+	text = strings.ToLower(text)
+	for _, term := range terms {
+		for from := 0; ; {
+			i := strings.Index(text[from:], term)
+			if i < 0 {
+				break
+			}
+			start, end := from+i, from+i+len(term)
+			before, _ := utf8.DecodeLastRuneInString(text[:start])
+			after, _ := utf8.DecodeRuneInString(text[end:])
+			if (start == 0 || !isWordRune(before)) && (end == len(text) || !isWordRune(after)) {
+				return true
+			}
+			from = start + 1
+		}
+	}
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+}
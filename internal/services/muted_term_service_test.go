@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/pkg/cache"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestContainsMutedTerm(t *testing.T) {
+	tests := []struct {
+		text  string
+		terms []string
+		want  bool
+	}{
+		{"Talking Politics tonight", []string{"politics"}, true},
+		{"#politics is exhausting", []string{"politics"}, true},
+		{"geopolitics lecture", []string{"politics"}, false},
+		{"politics talk", []string{"#politics"}, false},
+		{"new #Spoilers thread", []string{"#spoilers"}, true},
+		{"final of the world cup!", []string{"world cup"}, true},
+		{"سیاست امروز", []string{"سیاست"}, true},
+		{"سیاستمدار", []string{"سیاست"}, false},
+		{"nothing here", nil, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, containsMutedTerm(tt.text, tt.terms), tt.text)
+	}
+}
+
+func TestNormalizeMutedTerm(t *testing.T) {
+	term, err := normalizeMutedTerm("  World   CUP ")
+	require.NoError(t, err)
+	assert.Equal(t, "world cup", term)
+
+	_, err = normalizeMutedTerm("#a")
+	requireAppErrCode(t, err, http.StatusBadRequest)
+}
+
+func TestMutedTermService_Add(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("limit", func(t *testing.T) {
+		repo := new(mocks.MockMutedTermRepository)
+		repo.On("CountByUser", mock.Anything, "user-1").Return(maxMutedTerms, nil)
+		svc := NewMutedTermService(repo, new(mocks.MockPostRepository), zap.NewNop())
+
+		_, err := svc.Add(ctx, "user-1", &models.AddMutedTermRequest{Term: "politics"})
+		requireAppErrCode(t, err, http.StatusBadRequest)
+		repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		repo := new(mocks.MockMutedTermRepository)
+		repo.On("CountByUser", mock.Anything, "user-1").Return(1, nil)
+		repo.On("Create", mock.Anything, mock.Anything).Return(repositories.ErrMutedTermExists)
+		svc := NewMutedTermService(repo, new(mocks.MockPostRepository), zap.NewNop())
+
+		_, err := svc.Add(ctx, "user-1", &models.AddMutedTermRequest{Term: "Politics"})
+		requireAppErrCode(t, err, http.StatusConflict)
+	})
+}
+
+func TestMutedTermService_TermsCache(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	repo := new(mocks.MockMutedTermRepository)
+	repo.On("ListByUser", mock.Anything, "user-1").
+		Return([]*models.MutedTerm{{Term: "politics"}}, nil).Once()
+	svc := NewMutedTermService(repo, new(mocks.MockPostRepository), zap.NewNop()).
+		WithCache(cache.New(rdb, "muted_terms", zap.NewNop()))
+
+	assert.Equal(t, []string{"politics"}, svc.Terms(ctx, "user-1"))
+	assert.Equal(t, []string{"politics"}, svc.Terms(ctx, "user-1"), "served from cache")
+	repo.AssertNumberOfCalls(t, "ListByUser", 1)
+
+	// Unmuting busts the cache.
+	repo.On("Delete", mock.Anything, "user-1", "8d6e2a84-9c3f-4a53-b8ad-2f1d3c7e9b10").Return(nil)
+	repo.On("ListByUser", mock.Anything, "user-1").Return([]*models.MutedTerm{}, nil).Once()
+	require.NoError(t, svc.Delete(ctx, "user-1", "8d6e2a84-9c3f-4a53-b8ad-2f1d3c7e9b10"))
+	assert.Empty(t, svc.Terms(ctx, "user-1"))
+}
+
+func TestMutedTermService_FilterAndMutesPost(t *testing.T) {
+	ctx := context.Background()
+	repo := new(mocks.MockMutedTermRepository)
+	repo.On("ListByUser", mock.Anything, "user-1").Return([]*models.MutedTerm{{Term: "spoilers"}}, nil)
+	postRepo := new(mocks.MockPostRepository)
+	svc := NewMutedTermService(repo, postRepo, zap.NewNop())
+
+	muted := "Huge spoilers ahead"
+	clean := "Lost cat near the bazaar"
+	posts := []*models.Post{
+		{ID: "post-1", Description: &muted},
+		{ID: "post-2", Title: &clean},
+	}
+	kept := svc.FilterPosts(ctx, "user-1", posts)
+	require.Len(t, kept, 1)
+	assert.Equal(t, "post-2", kept[0].ID)
+	assert.Equal(t, "post-1", posts[0].ID, "input left untouched")
+
+	postRepo.On("GetByID", mock.Anything, "post-1").Return(posts[0], nil)
+	assert.True(t, svc.MutesPost(ctx, "user-1", "post-1"))
+
+	var nilSvc *MutedTermService
+	assert.False(t, nilSvc.MutesPost(ctx, "user-1", "post-1"))
+	assert.Len(t, nilSvc.FilterPosts(ctx, "user-1", posts), 2)
+}
@@ -10,8 +10,10 @@ import (
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/repositories"
 	"github.com/hamsaya/backend/internal/utils"
+	"github.com/hamsaya/backend/pkg/bgtasks"
 	"github.com/hamsaya/backend/pkg/cache"
 	fcmclient "github.com/hamsaya/backend/pkg/notification"
+	"github.com/hamsaya/backend/pkg/runtimeconfig"
 	"github.com/hamsaya/backend/pkg/websocket"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
@@ -58,6 +60,13 @@ type NotificationService struct {
 	wsHub            *websocket.Hub
 	logger           *zap.Logger
 	cache            *cache.Cache // optional; nil = no caching for unread-count
+	// tombstones is optional; nil = delta sync reports no removals.
+	tombstones repositories.SyncTombstoneRepository
+	// mutedTerms is optional; nil = notifications ignore muted terms.
+	mutedTerms *MutedTermService
+	// commentSettings is optional; nil = every COMMENT notification is sent
+	// individually.
+	commentSettings *runtimeconfig.Store
 }
 
 // NewNotificationService creates a new notification service
@@ -88,6 +97,13 @@ func (s *NotificationService) WithCache(c *cache.Cache) *NotificationService {
 	return s
 }
 
+// WithMutedTerms drops notifications about posts containing the
+// recipient's muted keywords or hashtags.
+func (s *NotificationService) WithMutedTerms(m *MutedTermService) *NotificationService {
+	s.mutedTerms = m
+	return s
+}
+
 // WithAPNs attaches a direct-APNs client for iOS push. Call once at startup.
 // Optional — without it, iOS falls back to FCM (which fails in Afghanistan).
 func (s *NotificationService) WithAPNs(c *fcmclient.APNsClient) *NotificationService {
@@ -95,6 +111,12 @@ func (s *NotificationService) WithAPNs(c *fcmclient.APNsClient) *NotificationSer
 	return s
 }
 
+// WithTombstones lets delta sync report deleted notifications.
+func (s *NotificationService) WithTombstones(repo repositories.SyncTombstoneRepository) *NotificationService {
+	s.tombstones = repo
+	return s
+}
+
 // unreadCountKey builds a per-(user, businessScope) cache key. Empty
 // business scope = personal notifications.
 func unreadCountKey(userID string, businessID *string) string {
@@ -121,13 +143,18 @@ func channelForType(t models.NotificationType) string {
 	case models.NotificationTypeMessage:
 		return "messages"
 	case models.NotificationTypeEventInterest, models.NotificationTypeEventGoing,
-		models.NotificationTypeEventReminder:
+		models.NotificationTypeEventReminder, models.NotificationTypeNearbyEvent,
+		models.NotificationTypeEventHostInvite, models.NotificationTypeEventHostAccepted,
+		models.NotificationTypeEventHostMessage:
 		return "events"
-	case models.NotificationTypeWelcome,
-		models.NotificationTypePasswordChanged,
+	case models.NotificationTypePasswordChanged,
 		models.NotificationTypeEmailVerified,
 		models.NotificationTypeAccountSuspended,
 		models.NotificationTypeAccountUnsuspended,
+		models.NotificationTypeTrustedContactAdded,
+		models.NotificationTypeAccountRecoveryCode,
+		models.NotificationTypeAccountRecoveryStarted,
+		models.NotificationTypeOAuthAccessRevoked,
 		models.NotificationTypePostDeletedByAdmin,
 		models.NotificationTypeCommentDeletedByAdmin,
 		models.NotificationTypeBusinessDeletedByAdmin:
@@ -154,23 +181,39 @@ func typeToCategory(t models.NotificationType) models.NotificationCategory {
 	case models.NotificationTypeMessage:
 		return models.NotificationCategoryMessages
 	case models.NotificationTypeEventInterest, models.NotificationTypeEventGoing,
-		models.NotificationTypeEventReminder:
+		models.NotificationTypeEventReminder, models.NotificationTypeNearbyEvent,
+		models.NotificationTypeEventHostInvite, models.NotificationTypeEventHostAccepted,
+		models.NotificationTypeEventHostMessage:
 		return models.NotificationCategoryEvents
-	case models.NotificationTypeWinback:
-		return models.NotificationCategoryPosts
+	case models.NotificationTypeWelcome,
+		models.NotificationTypeFirstPostNudge,
+		models.NotificationTypeWinback:
+		return models.NotificationCategoryTips
+	case models.NotificationTypeWeeklyDigest:
+		return models.NotificationCategoryDigest
 	case models.NotificationTypeBusinessFollow,
-		models.NotificationTypeBusinessDeletedByAdmin:
+		models.NotificationTypeBusinessDeletedByAdmin,
+		models.NotificationTypeBookingRequest,
+		models.NotificationTypeBookingAccepted,
+		models.NotificationTypeBookingDeclined,
+		models.NotificationTypeBookingCancelled,
+		models.NotificationTypeBusinessClaimApproved,
+		models.NotificationTypeBusinessClaimRejected:
 		return models.NotificationCategoryBusiness
 	case models.NotificationTypeSellExpired,
 		models.NotificationTypeSellInterested,
 		models.NotificationTypeSellSold,
-		models.NotificationTypeSellExpiring:
+		models.NotificationTypeSellExpiring,
+		models.NotificationTypeSellBookmarkMilestone:
 		return models.NotificationCategorySales
-	case models.NotificationTypeWelcome,
-		models.NotificationTypePasswordChanged,
+	case models.NotificationTypePasswordChanged,
 		models.NotificationTypeEmailVerified,
 		models.NotificationTypeAccountSuspended,
 		models.NotificationTypeAccountUnsuspended,
+		models.NotificationTypeTrustedContactAdded,
+		models.NotificationTypeAccountRecoveryCode,
+		models.NotificationTypeAccountRecoveryStarted,
+		models.NotificationTypeOAuthAccessRevoked,
 		models.NotificationTypePostDeletedByAdmin,
 		models.NotificationTypeCommentDeletedByAdmin:
 		return models.NotificationCategoryAccount
@@ -191,6 +234,31 @@ func (s *NotificationService) CreateNotification(ctx context.Context, req *model
 		}
 	}
 
+	// Shadowbanned actors must not reach anyone: dropping the notification
+	// here covers likes, comments, follows and messages in one place.
+	if actorID, ok := req.Data["actor_id"].(string); ok && actorID != "" && s.userRepo != nil {
+		banned, err := s.userRepo.IsShadowbanned(ctx, actorID)
+		if err != nil {
+			s.logger.Warn("Shadowban check failed; delivering notification",
+				zap.String("actor_id", actorID), zap.Error(err))
+		} else if banned {
+			s.logger.Debug("Dropping notification from shadowbanned actor",
+				zap.String("actor_id", actorID), zap.String("type", string(req.Type)))
+			return nil, nil
+		}
+	}
+
+	if postID, ok := req.Data["post_id"].(string); ok && s.mutedTerms.MutesPost(ctx, req.UserID, postID) {
+		s.logger.Debug("Dropping notification about a post with a muted term",
+			zap.String("user_id", req.UserID), zap.String("type", string(req.Type)))
+		return nil, nil
+	}
+
+	// Past a post's comment burst, the rest wait for the periodic digest.
+	if s.holdComment(ctx, req) {
+		return nil, nil
+	}
+
 	// Always persist so it appears in the notification list (even when push is disabled)
 	notificationID := uuid.New().String()
 	notification := &models.Notification{
@@ -220,10 +288,12 @@ func (s *NotificationService) CreateNotification(ctx context.Context, req *model
 
 	// Send real-time notification via WebSocket. We also include the new
 	// unread count so the mobile badge updates instantly without an extra
-	// API call — same pattern as X/Twitter and Facebook.
+	// API call — same pattern as X/Twitter and Facebook. Delivery runs on
+	// the bgtasks pool so shutdown flushes it instead of abandoning it;
+	// SubmitFrom keeps it when CreateNotification itself runs as a pool task
+	// that is draining.
 	if s.wsHub != nil {
-		go func() {
-			ctxWS := context.WithoutCancel(ctx)
+		bgtasks.SubmitFrom(ctx, func(ctxWS context.Context) {
 			unread, _ := s.notificationRepo.GetUnreadCount(ctxWS, req.UserID, nil)
 			wsPayload := map[string]interface{}{
 				"type":         "notification",
@@ -236,7 +306,7 @@ func (s *NotificationService) CreateNotification(ctx context.Context, req *model
 					zap.String("user_id", req.UserID),
 				)
 			}
-		}()
+		})
 	}
 
 	// Check user push preference before sending push
@@ -253,7 +323,9 @@ func (s *NotificationService) CreateNotification(ctx context.Context, req *model
 	}
 
 	if sendPush {
-		go s.sendPushNotification(context.WithoutCancel(ctx), notification)
+		bgtasks.SubmitFrom(ctx, func(ctxPush context.Context) {
+			s.sendPushNotification(ctxPush, notification)
+		})
 	}
 
 	// New unread notification → drop cached counts for this recipient so
@@ -264,26 +336,59 @@ func (s *NotificationService) CreateNotification(ctx context.Context, req *model
 	return notification.ToNotificationResponse(), nil
 }
 
-// GetNotifications retrieves notifications for a user. businessID is optional; when set, only notifications with data.business_id equal to it are returned.
+// GetNotifications retrieves notifications for filter.UserID. filter.BusinessID is optional; when set, only notifications with data.business_id equal to it are returned.
 // Enriches each notification's data with actor_avatar_color from the actor's profile when missing (e.g. for notifications created before the field existed).
-func (s *NotificationService) GetNotifications(ctx context.Context, userID string, unreadOnly bool, limit, offset int, businessID *string) ([]*models.NotificationResponse, error) {
-	filter := &models.GetNotificationsFilter{
-		UserID:     userID,
-		UnreadOnly: unreadOnly,
-		BusinessID: businessID,
-		Limit:      limit,
-		Offset:     offset,
+func (s *NotificationService) GetNotifications(ctx context.Context, filter *models.GetNotificationsFilter) ([]*models.NotificationResponse, error) {
+	notifications, err := s.notificationRepo.List(ctx, filter)
+	if err != nil {
+		s.logger.Error("Failed to get notifications",
+			zap.Error(err),
+			zap.String("user_id", filter.UserID),
+		)
+		return nil, utils.NewInternalError("Failed to get notifications", err)
+	}
+
+	return s.toResponses(ctx, notifications), nil
+}
+
+// GetNotificationChanges is the delta-sync form of GetNotifications:
+// notifications in the filter's scope created or changed (e.g. read) after
+// since, plus the IDs of those deleted since.
+func (s *NotificationService) GetNotificationChanges(ctx context.Context, filter *models.GetNotificationsFilter, since time.Time) (*models.DeltaPage, error) {
+	now := time.Now()
+	if deltaSyncExpired(since, now) {
+		return resetDeltaPage(now), nil
 	}
 
+	filter.UpdatedAfter = &since
 	notifications, err := s.notificationRepo.List(ctx, filter)
 	if err != nil {
-		s.logger.Error("Failed to get notifications",
+		s.logger.Error("Failed to get notification changes",
 			zap.Error(err),
-			zap.String("user_id", userID),
+			zap.String("user_id", filter.UserID),
 		)
 		return nil, utils.NewInternalError("Failed to get notifications", err)
 	}
 
+	deleted := []string{}
+	if s.tombstones != nil {
+		deleted, err = s.tombstones.DeletedSince(ctx, filter.UserID, models.SyncEntityNotification, since, maxDeltaTombstones+1)
+		if err != nil {
+			s.logger.Error("Failed to get deleted notifications", zap.Error(err), zap.String("user_id", filter.UserID))
+			return nil, utils.NewInternalError("Failed to get notifications", err)
+		}
+	}
+
+	var lastUpdated time.Time
+	if len(notifications) > 0 {
+		lastUpdated = notifications[len(notifications)-1].UpdatedAt
+	}
+	return newDeltaPage(s.toResponses(ctx, notifications), len(notifications), filter.Limit, lastUpdated, deleted, since, now), nil
+}
+
+// toResponses converts notifications for the API, filling in
+// actor_avatar_color where it is missing.
+func (s *NotificationService) toResponses(ctx context.Context, notifications []*models.Notification) []*models.NotificationResponse {
 	responses := make([]*models.NotificationResponse, 0, len(notifications))
 	for _, notification := range notifications {
 		resp := notification.ToNotificationResponse()
@@ -308,8 +413,7 @@ func (s *NotificationService) GetNotifications(ctx context.Context, userID strin
 		}
 		responses = append(responses, resp)
 	}
-
-	return responses, nil
+	return responses
 }
 
 // MarkAsRead marks a notification as read
@@ -398,6 +502,41 @@ func (s *NotificationService) DeleteNotification(ctx context.Context, userID, no
 	return nil
 }
 
+// ClearNotifications deletes the user's notifications in one go — all of
+// them, or with filter.ReadOnly only those already read — and returns how
+// many were removed.
+func (s *NotificationService) ClearNotifications(ctx context.Context, filter *models.ClearNotificationsFilter) (int64, error) {
+	deleted, err := s.notificationRepo.DeleteForUser(ctx, filter)
+	if err != nil {
+		s.logger.Error("Failed to clear notifications",
+			zap.Error(err),
+			zap.String("user_id", filter.UserID),
+		)
+		return 0, utils.NewInternalError("Failed to clear notifications", err)
+	}
+
+	s.logger.Info("Notifications cleared",
+		zap.String("user_id", filter.UserID),
+		zap.Bool("read_only", filter.ReadOnly),
+		zap.Int64("deleted_count", deleted),
+	)
+	if deleted > 0 {
+		s.invalidateUnreadForUser(ctx, filter.UserID)
+	}
+	return deleted, nil
+}
+
+// PurgeExpired deletes notifications older than retentionDays for every
+// user. Run by the daily retention job; cached unread counts are left to
+// expire on their own since pruned rows are months old and almost always
+// read.
+func (s *NotificationService) PurgeExpired(ctx context.Context, retentionDays int) (int64, error) {
+	if retentionDays <= 0 {
+		return 0, nil
+	}
+	return s.notificationRepo.DeleteOlderThan(ctx, time.Now().AddDate(0, 0, -retentionDays))
+}
+
 // GetUnreadCount gets the count of unread notifications. When businessID is set, counts only that business's notifications.
 func (s *NotificationService) GetUnreadCount(ctx context.Context, userID string, businessID *string) (int, error) {
 	key := unreadCountKey(userID, businessID)
@@ -431,6 +570,7 @@ var defaultNotificationCategories = []models.NotificationCategory{
 	models.NotificationCategoryEvents,
 	models.NotificationCategorySales,
 	models.NotificationCategoryBusiness,
+	models.NotificationCategoryTips,
 }
 
 // GetNotificationSettings retrieves notification settings for a user
@@ -471,9 +611,33 @@ func (s *NotificationService) GetNotificationSettings(ctx context.Context, profi
 		}
 	}
 
-	// Ensure we never return nil (JSON would serialize as null)
-	if settings == nil {
-		settings = []*models.NotificationSetting{}
+	// Categories added after a profile's defaults were created have no row;
+	// show them at their default. The digest is opt-in, so it shows as off.
+	for _, implicit := range []struct {
+		category models.NotificationCategory
+		on       bool
+	}{
+		{models.NotificationCategoryTips, true},
+		{models.NotificationCategoryDigest, false},
+	} {
+		found := false
+		for _, setting := range settings {
+			if setting.Category == implicit.category {
+				found = true
+				break
+			}
+		}
+		if !found {
+			now := time.Now()
+			settings = append(settings, &models.NotificationSetting{
+				ID:        fmt.Sprintf("%s-%s", profileID, implicit.category),
+				ProfileID: profileID,
+				Category:  implicit.category,
+				PushPref:  implicit.on,
+				CreatedAt: now,
+				UpdatedAt: now,
+			})
+		}
 	}
 	return settings, nil
 }
@@ -502,7 +666,8 @@ func (s *NotificationService) UpdateNotificationSetting(ctx context.Context, pro
 // devices (iOS, Android, web) coexist for the same user; previously this was
 // a single STRING key per user, which caused the most-recently-registered
 // device to silently win and pushes to vanish on every other device.
-func (s *NotificationService) RegisterFCMToken(ctx context.Context, userID, token string) error {
+// deviceName is optional and only shown in the user's device list.
+func (s *NotificationService) RegisterFCMToken(ctx context.Context, userID, token string, deviceName *string) error {
 	key := fcmTokensPrefix + userID
 
 	if _, err := s.redisClient.SAdd(ctx, key, token).Result(); err != nil {
@@ -525,6 +690,7 @@ func (s *NotificationService) RegisterFCMToken(ctx context.Context, userID, toke
 	// re-registered into the new SET. Safe to ignore the error — worst
 	// case the orphan key expires on its existing TTL.
 	_ = s.redisClient.Del(ctx, fcmLegacyTokenPrefix+userID).Err()
+	s.trackPushDevice(ctx, userID, pushProviderFCM, token, deviceName)
 
 	s.logger.Info("FCM token registered", zap.String("user_id", userID))
 	return nil
@@ -545,6 +711,7 @@ func (s *NotificationService) UnregisterFCMToken(ctx context.Context, userID, to
 			)
 			return utils.NewInternalError("Failed to unregister device tokens", err)
 		}
+		s.forgetPushDevices(ctx, userID, pushProviderFCM)
 		s.logger.Info("All FCM tokens unregistered", zap.String("user_id", userID))
 		return nil
 	}
@@ -556,6 +723,7 @@ func (s *NotificationService) UnregisterFCMToken(ctx context.Context, userID, to
 		)
 		return utils.NewInternalError("Failed to unregister device token", err)
 	}
+	_ = s.redisClient.HDel(ctx, pushDevicesPrefix+userID, pushDeviceID(token)).Err()
 
 	s.logger.Info("FCM token unregistered", zap.String("user_id", userID))
 	return nil
@@ -563,7 +731,7 @@ func (s *NotificationService) UnregisterFCMToken(ctx context.Context, userID, to
 
 // RegisterAPNsToken adds a native APNs device token to the user's iOS token
 // set. iOS uses this instead of FCM so push works where Google is blocked.
-func (s *NotificationService) RegisterAPNsToken(ctx context.Context, userID, token string) error {
+func (s *NotificationService) RegisterAPNsToken(ctx context.Context, userID, token string, deviceName *string) error {
 	key := apnsTokensPrefix + userID
 
 	if _, err := s.redisClient.SAdd(ctx, key, token).Result(); err != nil {
@@ -575,6 +743,7 @@ func (s *NotificationService) RegisterAPNsToken(ctx context.Context, userID, tok
 		s.logger.Warn("Failed to refresh APNs token set TTL",
 			zap.Error(err), zap.String("user_id", userID))
 	}
+	s.trackPushDevice(ctx, userID, pushProviderAPNs, token, deviceName)
 	s.logger.Info("APNs token registered", zap.String("user_id", userID))
 	return nil
 }
@@ -590,6 +759,7 @@ func (s *NotificationService) UnregisterAPNsToken(ctx context.Context, userID, t
 				zap.Error(err), zap.String("user_id", userID))
 			return utils.NewInternalError("Failed to unregister device tokens", err)
 		}
+		s.forgetPushDevices(ctx, userID, pushProviderAPNs)
 		s.logger.Info("All APNs tokens unregistered", zap.String("user_id", userID))
 		return nil
 	}
@@ -599,6 +769,7 @@ func (s *NotificationService) UnregisterAPNsToken(ctx context.Context, userID, t
 			zap.Error(err), zap.String("user_id", userID))
 		return utils.NewInternalError("Failed to unregister device token", err)
 	}
+	_ = s.redisClient.HDel(ctx, pushDevicesPrefix+userID, pushDeviceID(token)).Err()
 	s.logger.Info("APNs token unregistered", zap.String("user_id", userID))
 	return nil
 }
@@ -626,6 +797,10 @@ func isUrgentPush(t models.NotificationType) bool {
 		models.NotificationTypeEmailVerified,
 		models.NotificationTypeAccountSuspended,
 		models.NotificationTypeAccountUnsuspended,
+		models.NotificationTypeTrustedContactAdded,
+		models.NotificationTypeAccountRecoveryCode,
+		models.NotificationTypeAccountRecoveryStarted,
+		models.NotificationTypeOAuthAccessRevoked,
 		models.NotificationTypePostDeletedByAdmin,
 		models.NotificationTypeCommentDeletedByAdmin,
 		models.NotificationTypeBusinessDeletedByAdmin:
@@ -766,12 +941,12 @@ func (s *NotificationService) sendViaFCM(ctx context.Context, notification *mode
 
 	for _, token := range tokens {
 		if err := s.fcmClient.SendNotification(ctx, token, payload); err != nil {
-			if errors.Is(err, fcmclient.ErrTokenInvalid) {
-				s.logger.Info("FCM token invalid, pruning", zap.String("user_id", notification.UserID))
-				if delErr := s.redisClient.SRem(ctx, key, token).Err(); delErr != nil {
-					s.logger.Warn("Failed to prune stale FCM token",
-						zap.Error(delErr), zap.String("user_id", notification.UserID))
-				}
+			if s.recordPushFailure(ctx, notification.UserID, pushProviderFCM, token, err) {
+				s.logger.Info("FCM token invalid, pruned", zap.String("user_id", notification.UserID))
+				continue
+			}
+			if errors.Is(err, fcmclient.ErrTokenUnregistered) {
+				s.logger.Info("FCM token reported unregistered", zap.String("user_id", notification.UserID))
 				continue
 			}
 			s.logger.Error("Failed to send push notification",
@@ -780,6 +955,7 @@ func (s *NotificationService) sendViaFCM(ctx context.Context, notification *mode
 				zap.String("notification_id", notification.ID))
 			continue
 		}
+		s.recordPushSuccess(ctx, notification.UserID, pushProviderFCM, token)
 		s.logger.Info("Push notification sent successfully",
 			zap.String("user_id", notification.UserID),
 			zap.String("notification_id", notification.ID))
@@ -807,12 +983,8 @@ func (s *NotificationService) sendViaAPNs(ctx context.Context, notification *mod
 
 	for _, token := range tokens {
 		if err := s.apnsClient.SendNotification(ctx, token, payload); err != nil {
-			if errors.Is(err, fcmclient.ErrAPNsTokenInvalid) {
-				s.logger.Info("APNs token invalid, pruning", zap.String("user_id", notification.UserID))
-				if delErr := s.redisClient.SRem(ctx, key, token).Err(); delErr != nil {
-					s.logger.Warn("Failed to prune stale APNs token",
-						zap.Error(delErr), zap.String("user_id", notification.UserID))
-				}
+			if s.recordPushFailure(ctx, notification.UserID, pushProviderAPNs, token, err) {
+				s.logger.Info("APNs token invalid, pruned", zap.String("user_id", notification.UserID))
 				continue
 			}
 			s.logger.Error("Failed to send APNs push",
@@ -821,6 +993,7 @@ func (s *NotificationService) sendViaAPNs(ctx context.Context, notification *mod
 				zap.String("notification_id", notification.ID))
 			continue
 		}
+		s.recordPushSuccess(ctx, notification.UserID, pushProviderAPNs, token)
 		s.logger.Info("APNs push sent successfully",
 			zap.String("user_id", notification.UserID),
 			zap.String("notification_id", notification.ID))
@@ -89,7 +89,7 @@ func TestNotificationService_GetNotifications(t *testing.T) {
 			tt.setupMocks(notifRepo, userRepo)
 
 			svc := newTestNotificationService(notifRepo, settingsRepo, userRepo)
-			resp, err := svc.GetNotifications(context.Background(), tt.userID, false, 20, 0, nil)
+			resp, err := svc.GetNotifications(context.Background(), &models.GetNotificationsFilter{UserID: tt.userID, Limit: 20})
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -215,6 +215,54 @@ func TestNotificationService_MarkAllAsRead(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// TestNotificationService_ClearNotifications
+// ---------------------------------------------------------------------------
+
+func TestNotificationService_ClearNotifications(t *testing.T) {
+	filter := &models.ClearNotificationsFilter{UserID: "user-1", ReadOnly: true}
+
+	t.Run("success", func(t *testing.T) {
+		notifRepo := new(mocks.MockNotificationRepository)
+		notifRepo.On("DeleteForUser", mock.Anything, filter).Return(int64(3), nil)
+
+		svc := newTestNotificationService(notifRepo, new(mocks.MockNotificationSettingsRepository), new(mocks.MockUserRepository))
+		deleted, err := svc.ClearNotifications(context.Background(), filter)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), deleted)
+		notifRepo.AssertExpectations(t)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		notifRepo := new(mocks.MockNotificationRepository)
+		notifRepo.On("DeleteForUser", mock.Anything, filter).Return(int64(0), errors.New("db down"))
+
+		svc := newTestNotificationService(notifRepo, new(mocks.MockNotificationSettingsRepository), new(mocks.MockUserRepository))
+		_, err := svc.ClearNotifications(context.Background(), filter)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestNotificationService_PurgeExpired(t *testing.T) {
+	notifRepo := new(mocks.MockNotificationRepository)
+	notifRepo.On("DeleteOlderThan", mock.Anything, mock.MatchedBy(func(cutoff time.Time) bool {
+		return time.Since(cutoff) > 29*24*time.Hour && time.Since(cutoff) < 31*24*time.Hour
+	})).Return(int64(42), nil)
+	svc := newTestNotificationService(notifRepo, new(mocks.MockNotificationSettingsRepository), new(mocks.MockUserRepository))
+
+	deleted, err := svc.PurgeExpired(context.Background(), 30)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), deleted)
+
+	// Zero retention keeps everything.
+	deleted, err = svc.PurgeExpired(context.Background(), 0)
+	assert.NoError(t, err)
+	assert.Zero(t, deleted)
+	notifRepo.AssertNumberOfCalls(t, "DeleteOlderThan", 1)
+}
+
 // ---------------------------------------------------------------------------
 // TestNotificationService_DeleteNotification
 // ---------------------------------------------------------------------------
@@ -356,6 +404,31 @@ func TestNotificationService_GetNotificationSettings(t *testing.T) {
 				sr.On("GetByProfileID", mock.Anything, "profile-1").Return(settings, nil)
 			},
 			expectError:   false,
+			expectedCount: 4, // + tips shown as on, the opt-in digest as off
+		},
+		{
+			name:      "digest opted in",
+			profileID: "profile-1",
+			setupMocks: func(sr *mocks.MockNotificationSettingsRepository) {
+				settings := []*models.NotificationSetting{
+					{ID: "setting-1", ProfileID: "profile-1", Category: models.NotificationCategoryPosts, PushPref: true},
+					{ID: "setting-2", ProfileID: "profile-1", Category: models.NotificationCategoryDigest, PushPref: true},
+				}
+				sr.On("GetByProfileID", mock.Anything, "profile-1").Return(settings, nil)
+			},
+			expectError:   false,
+			expectedCount: 3,
+		},
+		{
+			name:      "tips opted out",
+			profileID: "profile-1",
+			setupMocks: func(sr *mocks.MockNotificationSettingsRepository) {
+				settings := []*models.NotificationSetting{
+					{ID: "setting-1", ProfileID: "profile-1", Category: models.NotificationCategoryTips, PushPref: false},
+				}
+				sr.On("GetByProfileID", mock.Anything, "profile-1").Return(settings, nil)
+			},
+			expectError:   false,
 			expectedCount: 2,
 		},
 	}
@@ -375,6 +448,12 @@ func TestNotificationService_GetNotificationSettings(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				assert.Len(t, settings, tt.expectedCount)
+				byCategory := make(map[models.NotificationCategory]bool)
+				for _, setting := range settings {
+					byCategory[setting.Category] = setting.PushPref
+				}
+				assert.Equal(t, tt.name == "digest opted in", byCategory[models.NotificationCategoryDigest])
+				assert.Equal(t, tt.name != "tips opted out", byCategory[models.NotificationCategoryTips])
 			}
 
 			notifRepo.AssertExpectations(t)
@@ -437,6 +516,7 @@ func TestChannelForType(t *testing.T) {
 	assert.Equal(t, "messages", channelForType(models.NotificationTypeMessage))
 	assert.Equal(t, "events", channelForType(models.NotificationTypeEventInterest))
 	assert.Equal(t, "events", channelForType(models.NotificationTypeEventGoing))
+	assert.Equal(t, "events", channelForType(models.NotificationTypeNearbyEvent))
 	assert.Equal(t, "general", channelForType(models.NotificationTypeLike))
 }
 
@@ -444,7 +524,11 @@ func TestTypeToCategory(t *testing.T) {
 	assert.Equal(t, models.NotificationCategoryMessages, typeToCategory(models.NotificationTypeMessage))
 	assert.Equal(t, models.NotificationCategoryEvents, typeToCategory(models.NotificationTypeEventInterest))
 	assert.Equal(t, models.NotificationCategoryBusiness, typeToCategory(models.NotificationTypeBusinessFollow))
+	assert.Equal(t, models.NotificationCategoryBusiness, typeToCategory(models.NotificationTypeBookingRequest))
 	assert.Equal(t, models.NotificationCategorySales, typeToCategory(models.NotificationTypeSellExpired))
+	assert.Equal(t, models.NotificationCategoryDigest, typeToCategory(models.NotificationTypeWeeklyDigest))
+	assert.Equal(t, models.NotificationCategoryTips, typeToCategory(models.NotificationTypeWinback))
+	assert.Equal(t, models.NotificationCategoryTips, typeToCategory(models.NotificationTypeWelcome))
 	assert.Equal(t, models.NotificationCategoryPosts, typeToCategory(models.NotificationTypeLike))
 }
 
@@ -505,6 +589,25 @@ func TestNotificationService_CreateNotification(t *testing.T) {
 	})
 }
 
+func TestNotificationService_CreateNotification_ShadowbannedActor(t *testing.T) {
+	notifRepo := &mocks.MockNotificationRepository{}
+	settingsRepo := &mocks.MockNotificationSettingsRepository{}
+	userRepo := &mocks.MockUserRepository{}
+	userRepo.On("IsShadowbanned", mock.Anything, "spammer").Return(true, nil)
+
+	svc := newTestNotificationService(notifRepo, settingsRepo, userRepo)
+	result, err := svc.CreateNotification(context.Background(), &models.CreateNotificationRequest{
+		UserID: "u-2",
+		Type:   models.NotificationTypeComment,
+		Data:   map[string]interface{}{"actor_id": "spammer"},
+	})
+
+	require.NoError(t, err)
+	assert.Nil(t, result)
+	notifRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	userRepo.AssertExpectations(t)
+}
+
 func TestIsUrgentPush(t *testing.T) {
 	urgent := []models.NotificationType{
 		models.NotificationTypeMessage,
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	appleIssuer = "https://appleid.apple.com"
+	// googleRISCIssuer is the issuer of Google's Cross-Account Protection
+	// (RISC) security event tokens — note the trailing slash.
+	googleRISCIssuer = "https://accounts.google.com/"
+)
+
+// appleRevokingEvents are the Sign in with Apple notification types that
+// end our access to the account. email-disabled / email-enabled only change
+// private relay forwarding.
+var appleRevokingEvents = map[string]bool{
+	"consent-revoked": true,
+	"account-delete":  true,
+}
+
+// googleRevokingEvents are the RISC event types (the last path segment of
+// the event URI) after which the account must sign in with Google again.
+var googleRevokingEvents = map[string]bool{
+	"sessions-revoked": true,
+	"tokens-revoked":   true,
+	"account-disabled": true,
+	"account-purged":   true,
+}
+
+// OAuthProviderEvent is a verified notification from an OAuth provider about
+// one of its users.
+type OAuthProviderEvent struct {
+	Provider string
+	// Subject is the provider's user id — the sub of its ID tokens.
+	Subject string
+	// Type is the provider's event type, e.g. consent-revoked.
+	Type string
+	// Revokes reports whether the event ends our access to the account.
+	Revokes bool
+}
+
+// WithRevocations lets OAuth sign-in clear a provider revocation once the
+// user has signed in with the provider again.
+func (s *OAuthService) WithRevocations(repo repositories.OAuthRevocationRepository) *OAuthService {
+	s.revocations = repo
+	return s
+}
+
+// clearRevocation lifts the user's provider revocation, if any. Failures are
+// logged; the sign-in itself already succeeded.
+func (s *OAuthService) clearRevocation(ctx context.Context, userID string) {
+	if s.revocations == nil {
+		return
+	}
+	cleared, err := s.revocations.Clear(ctx, userID)
+	if err != nil {
+		s.logger.Warn("Failed to clear OAuth revocation", zap.String("user_id", userID), zap.Error(err))
+		return
+	}
+	if cleared {
+		s.logger.Info("OAuth revocation cleared by provider sign-in", zap.String("user_id", userID))
+	}
+}
+
+// VerifyAppleNotification verifies a Sign in with Apple server-to-server
+// notification payload and returns the event it carries.
+func (s *OAuthService) VerifyAppleNotification(ctx context.Context, payload string) (*OAuthProviderEvent, error) {
+	clientID := s.cfg.OAuth.Apple.ClientID
+	if clientID == "" {
+		return nil, utils.NewInternalError("Apple OAuth not configured (APPLE_CLIENT_ID missing)", nil)
+	}
+	claims, err := parseProviderToken(ctx, payload, s.appleKeys, appleIssuer, clientID)
+	if err != nil {
+		s.logger.Warn("Apple notification verification failed", zap.Error(err))
+		return nil, utils.NewUnauthorizedError("Invalid Apple notification", err)
+	}
+
+	// Apple sends events as a JSON-encoded string inside the JWT.
+	var event struct {
+		Type string `json:"type"`
+		Sub  string `json:"sub"`
+	}
+	if err := decodeClaim(claims["events"], &event); err != nil || event.Type == "" || event.Sub == "" {
+		return nil, utils.NewBadRequestError("Apple notification has no event", err)
+	}
+
+	return &OAuthProviderEvent{
+		Provider: "apple",
+		Subject:  event.Sub,
+		Type:     event.Type,
+		Revokes:  appleRevokingEvents[event.Type],
+	}, nil
+}
+
+// VerifyGoogleSecurityEvent verifies a Google Cross-Account Protection
+// security event token and returns the events about Google accounts in it.
+// Events about individual OAuth tokens are skipped: we never hold Google
+// refresh tokens.
+func (s *OAuthService) VerifyGoogleSecurityEvent(ctx context.Context, token string) ([]*OAuthProviderEvent, error) {
+	clientID := s.cfg.OAuth.Google.ClientID
+	if clientID == "" {
+		return nil, utils.NewInternalError("Google OAuth not configured (GOOGLE_CLIENT_ID missing)", nil)
+	}
+	claims, err := parseProviderToken(ctx, token, s.googleKeys, googleRISCIssuer, clientID)
+	if err != nil {
+		s.logger.Warn("Google security event verification failed", zap.Error(err))
+		return nil, utils.NewUnauthorizedError("Invalid Google security event", err)
+	}
+
+	var events map[string]struct {
+		Subject struct {
+			SubjectType string `json:"subject_type"`
+			Sub         string `json:"sub"`
+		} `json:"subject"`
+	}
+	if err := decodeClaim(claims["events"], &events); err != nil {
+		return nil, utils.NewBadRequestError("Google security event has no events", err)
+	}
+
+	out := make([]*OAuthProviderEvent, 0, len(events))
+	for uri, e := range events {
+		kind := uri[strings.LastIndex(uri, "/")+1:]
+		if e.Subject.SubjectType != "iss-sub" || e.Subject.Sub == "" {
+			s.logger.Info("Google security event skipped", zap.String("type", kind))
+			continue
+		}
+		out = append(out, &OAuthProviderEvent{
+			Provider: "google",
+			Subject:  e.Subject.Sub,
+			Type:     kind,
+			Revokes:  googleRevokingEvents[kind],
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Type < out[j].Type })
+	return out, nil
+}
+
+// parseProviderToken verifies an RS256 JWT signed with one of the provider's
+// published keys and returns its claims.
+func parseProviderToken(ctx context.Context, token string, keys *jwksKeyCache, issuer, audience string) (jwt.MapClaims, error) {
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithIssuer(issuer),
+		jwt.WithAudience(audience),
+		jwt.WithIssuedAt(),
+	)
+	parsed, err := parser.Parse(token, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		if kid == "" {
+			return nil, fmt.Errorf("token missing kid header")
+		}
+		return keys.publicKey(ctx, kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	if !ok || !parsed.Valid {
+		return nil, fmt.Errorf("invalid token claims")
+	}
+	return claims, nil
+}
+
+// decodeClaim decodes a JSON claim into dst, whether the issuer sent it as an
+// object or as a JSON-encoded string.
+func decodeClaim(v interface{}, dst interface{}) error {
+	if v == nil {
+		return fmt.Errorf("claim missing")
+	}
+	raw, ok := v.(string)
+	if !ok {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		raw = string(b)
+	}
+	return json.Unmarshal([]byte(raw), dst)
+}
+
+// WithOAuthRevocations remembers which accounts were signed out because their
+// provider revoked our access, so clients can be told why.
+func (s *AuthService) WithOAuthRevocations(repo repositories.OAuthRevocationRepository) *AuthService {
+	s.oauthRevocations = repo
+	return s
+}
+
+// HandleOAuthProviderEvent acts on a verified provider event. When the
+// provider revoked our access to an OAuth-only account, every session and
+// device credential is ended so the user has to sign in with the provider
+// again. Accounts with a password keep working. Either way the user is told.
+func (s *AuthService) HandleOAuthProviderEvent(ctx context.Context, ev *OAuthProviderEvent) error {
+	if !ev.Revokes {
+		s.logger.Info("OAuth provider event needs no action",
+			zap.String("provider", ev.Provider),
+			zap.String("type", ev.Type),
+		)
+		return nil
+	}
+
+	user, err := s.userRepo.GetByOAuthProviderID(ctx, ev.Provider, ev.Subject)
+	if errors.Is(err, repositories.ErrNotFound) || (err == nil && user == nil) {
+		// Never signed up, or already deleted: nothing to revoke.
+		s.logger.Info("OAuth revocation for unknown account",
+			zap.String("provider", ev.Provider),
+			zap.String("type", ev.Type),
+		)
+		return nil
+	}
+	if err != nil {
+		// Fail so the provider retries the notification.
+		return utils.NewInternalError("Failed to look up account", err)
+	}
+
+	name := oauthProviderName(ev.Provider)
+	msg := fmt.Sprintf("%s told us Hamsaya's access to your %s account was revoked. You can still sign in with your email and password.", name, name)
+	if user.PasswordHash == nil || *user.PasswordHash == "" {
+		if s.oauthRevocations != nil {
+			if err := s.oauthRevocations.Mark(ctx, user.ID, ev.Provider, ev.Type); err != nil {
+				return utils.NewInternalError("Failed to record OAuth revocation", err)
+			}
+		}
+		if err := s.userRepo.RevokeAllUserSessions(ctx, user.ID); err != nil {
+			return utils.NewInternalError("Failed to revoke sessions", err)
+		}
+		if err := s.userRepo.RevokeAllUserDeviceCredentials(ctx, user.ID); err != nil {
+			return utils.NewInternalError("Failed to revoke devices", err)
+		}
+		s.invalidateAccessTokens(ctx, user.ID)
+		msg = fmt.Sprintf("%s told us Hamsaya's access to your %s account was revoked, so you've been signed out. Sign in with %s again to continue.", name, name, name)
+	}
+
+	s.logger.Info("OAuth access revoked by provider",
+		zap.String("user_id", user.ID),
+		zap.String("provider", ev.Provider),
+		zap.String("type", ev.Type),
+	)
+	s.notifyAccount(user.ID, models.NotificationTypeOAuthAccessRevoked,
+		fmt.Sprintf("%s access revoked", name), msg,
+		map[string]interface{}{"provider": ev.Provider, "reason": ev.Type})
+	return nil
+}
+
+// revokedSessionError is the error for a refresh token whose session was
+// ended. Users signed out by a provider revocation are pointed back to the
+// provider's sign-in.
+func (s *AuthService) revokedSessionError(ctx context.Context, userID string) error {
+	if s.oauthRevocations != nil {
+		if rev, err := s.oauthRevocations.Get(ctx, userID); err == nil {
+			return utils.NewUnauthorizedError(fmt.Sprintf("%s access was revoked; sign in with %s again", oauthProviderName(rev.Provider), oauthProviderName(rev.Provider)), nil)
+		}
+	}
+	return utils.NewUnauthorizedError("Refresh token has been revoked", nil)
+}
+
+// oauthProviderName is the display name of an OAuth provider.
+func oauthProviderName(provider string) string {
+	switch provider {
+	case "apple":
+		return "Apple"
+	case "google":
+		return "Google"
+	case "facebook":
+		return "Facebook"
+	default:
+		return provider
+	}
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hamsaya/backend/config"
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestOAuthService_VerifyAppleNotification(t *testing.T) {
+	k := generateJWKSTestKey(t, "kid-1")
+	srv := newJWKSServer(t, k)
+	defer srv.Close()
+	svc := newAppleTestService(t, srv.URL)
+
+	t.Run("consent revoked", func(t *testing.T) {
+		payload := signAppleToken(t, k, jwt.MapClaims{
+			"iss":    "https://appleid.apple.com",
+			"aud":    "af.hamsaya",
+			"iat":    time.Now().Unix(),
+			"jti":    "abc",
+			"events": `{"type":"consent-revoked","sub":"001234.abcd5678","event_time":1700000000000}`,
+		})
+
+		ev, err := svc.VerifyAppleNotification(context.Background(), payload)
+		require.NoError(t, err)
+		assert.Equal(t, &OAuthProviderEvent{Provider: "apple", Subject: "001234.abcd5678", Type: "consent-revoked", Revokes: true}, ev)
+	})
+
+	t.Run("email relay changes revoke nothing", func(t *testing.T) {
+		payload := signAppleToken(t, k, jwt.MapClaims{
+			"iss":    "https://appleid.apple.com",
+			"aud":    "af.hamsaya",
+			"events": map[string]interface{}{"type": "email-disabled", "sub": "001234.abcd5678"},
+		})
+
+		ev, err := svc.VerifyAppleNotification(context.Background(), payload)
+		require.NoError(t, err)
+		assert.False(t, ev.Revokes)
+	})
+
+	t.Run("wrong audience", func(t *testing.T) {
+		payload := signAppleToken(t, k, jwt.MapClaims{
+			"iss":    "https://appleid.apple.com",
+			"aud":    "com.someone.else",
+			"events": `{"type":"consent-revoked","sub":"001234.abcd5678"}`,
+		})
+
+		_, err := svc.VerifyAppleNotification(context.Background(), payload)
+		requireAppErrCode(t, err, http.StatusUnauthorized)
+	})
+}
+
+func TestOAuthService_VerifyGoogleSecurityEvent(t *testing.T) {
+	k := generateJWKSTestKey(t, "kid-1")
+	srv := newJWKSServer(t, k)
+	defer srv.Close()
+	cfg := &config.Config{OAuth: config.OAuthConfig{Google: config.GoogleOAuthConfig{ClientID: "test-client-id"}}}
+	svc := NewOAuthService(cfg, new(mocks.MockUserRepository), zap.NewNop())
+	svc.googleKeys.url = srv.URL
+
+	token := signAppleToken(t, k, jwt.MapClaims{
+		"iss": "https://accounts.google.com/",
+		"aud": "test-client-id",
+		"iat": time.Now().Unix(),
+		"events": map[string]interface{}{
+			"https://schemas.openid.net/secevent/risc/event-type/sessions-revoked": map[string]interface{}{
+				"subject": map[string]interface{}{"subject_type": "iss-sub", "iss": "https://accounts.google.com/", "sub": "7375626a656374"},
+			},
+			"https://schemas.openid.net/secevent/risc/event-type/verification": map[string]interface{}{"state": "probe"},
+		},
+	})
+
+	events, err := svc.VerifyGoogleSecurityEvent(context.Background(), token)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, &OAuthProviderEvent{Provider: "google", Subject: "7375626a656374", Type: "sessions-revoked", Revokes: true}, events[0])
+
+	_, err = svc.VerifyGoogleSecurityEvent(context.Background(), "not-a-jwt")
+	requireAppErrCode(t, err, http.StatusUnauthorized)
+}
+
+func TestAuthService_HandleOAuthProviderEvent(t *testing.T) {
+	ctx := context.Background()
+	revoked := &OAuthProviderEvent{Provider: "apple", Subject: "sub-1", Type: "consent-revoked", Revokes: true}
+
+	t.Run("oauth-only account is signed out", func(t *testing.T) {
+		user := testutil.CreateTestUser("user-1", "apple_sub-1@no-email.hamsaya.af")
+		user.PasswordHash = nil
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetByOAuthProviderID", mock.Anything, "apple", "sub-1").Return(user, nil)
+		userRepo.On("RevokeAllUserSessions", mock.Anything, "user-1").Return(nil)
+		userRepo.On("RevokeAllUserDeviceCredentials", mock.Anything, "user-1").Return(nil)
+		repo := new(mocks.MockOAuthRevocationRepository)
+		repo.On("Mark", mock.Anything, "user-1", "apple", "consent-revoked").Return(nil)
+		ts, _ := newTestTokenStorage(t)
+		svc := newTestAuthService(userRepo, ts).WithOAuthRevocations(repo)
+
+		require.NoError(t, svc.HandleOAuthProviderEvent(ctx, revoked))
+		userRepo.AssertExpectations(t)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("account with a password keeps its sessions", func(t *testing.T) {
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetByOAuthProviderID", mock.Anything, "apple", "sub-1").Return(testutil.CreateTestUser("user-1", "ali@example.com"), nil)
+		repo := new(mocks.MockOAuthRevocationRepository)
+		ts, _ := newTestTokenStorage(t)
+		svc := newTestAuthService(userRepo, ts).WithOAuthRevocations(repo)
+
+		require.NoError(t, svc.HandleOAuthProviderEvent(ctx, revoked))
+		userRepo.AssertNotCalled(t, "RevokeAllUserSessions", mock.Anything, mock.Anything)
+		repo.AssertNotCalled(t, "Mark", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown account is acknowledged", func(t *testing.T) {
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetByOAuthProviderID", mock.Anything, "apple", "sub-1").Return(nil, fmt.Errorf("get user: %w", repositories.ErrNotFound))
+		ts, _ := newTestTokenStorage(t)
+		svc := newTestAuthService(userRepo, ts)
+
+		require.NoError(t, svc.HandleOAuthProviderEvent(ctx, revoked))
+	})
+
+	t.Run("lookup failure asks the provider to retry", func(t *testing.T) {
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetByOAuthProviderID", mock.Anything, "apple", "sub-1").Return(nil, errors.New("connection reset"))
+		ts, _ := newTestTokenStorage(t)
+		svc := newTestAuthService(userRepo, ts)
+
+		requireAppErrCode(t, svc.HandleOAuthProviderEvent(ctx, revoked), http.StatusInternalServerError)
+	})
+
+	t.Run("non-revoking events are ignored", func(t *testing.T) {
+		userRepo := new(mocks.MockUserRepository)
+		ts, _ := newTestTokenStorage(t)
+		svc := newTestAuthService(userRepo, ts)
+
+		require.NoError(t, svc.HandleOAuthProviderEvent(ctx, &OAuthProviderEvent{Provider: "apple", Subject: "sub-1", Type: "email-enabled"}))
+		userRepo.AssertNotCalled(t, "GetByOAuthProviderID", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
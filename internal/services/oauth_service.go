@@ -20,10 +20,12 @@ import (
 
 // OAuthService handles OAuth authentication with third-party providers
 type OAuthService struct {
-	cfg       *config.Config
-	userRepo  repositories.UserRepository
-	logger    *zap.Logger
-	appleKeys *appleKeyCache
+	cfg         *config.Config
+	userRepo    repositories.UserRepository
+	logger      *zap.Logger
+	appleKeys   *jwksKeyCache
+	googleKeys  *jwksKeyCache
+	revocations repositories.OAuthRevocationRepository // optional; cleared on provider sign-in
 }
 
 // NewOAuthService creates a new OAuth service
@@ -33,10 +35,11 @@ func NewOAuthService(
 	logger *zap.Logger,
 ) *OAuthService {
 	return &OAuthService{
-		cfg:       cfg,
-		userRepo:  userRepo,
-		logger:    logger,
-		appleKeys: newAppleKeyCache(),
+		cfg:        cfg,
+		userRepo:   userRepo,
+		logger:     logger,
+		appleKeys:  newAppleKeyCache(),
+		googleKeys: newGoogleKeyCache(),
 	}
 }
 
@@ -173,7 +176,7 @@ func (s *OAuthService) VerifyAppleToken(ctx context.Context, idToken string) (*O
 
 	parser := jwt.NewParser(
 		jwt.WithValidMethods([]string{"RS256"}),
-		jwt.WithIssuer("https://appleid.apple.com"),
+		jwt.WithIssuer(appleIssuer),
 		jwt.WithAudience(clientID),
 		jwt.WithExpirationRequired(),
 	)
@@ -413,6 +416,7 @@ func (s *OAuthService) AuthenticateWithOAuth(ctx context.Context, oauthInfo *OAu
 					s.logger.Error("Failed to get profile", zap.Error(profErr))
 					return nil, nil, false, utils.NewInternalError("Failed to get profile", profErr)
 				}
+				s.clearRevocation(ctx, existingByProvider.ID)
 				s.logger.Info("OAuth returning user recovered by provider id (no email claim)",
 					zap.String("user_id", existingByProvider.ID),
 					zap.String("provider", oauthInfo.Provider),
@@ -488,6 +492,8 @@ func (s *OAuthService) AuthenticateWithOAuth(ctx context.Context, oauthInfo *OAu
 			}
 		}
 
+		s.clearRevocation(ctx, existingUser.ID)
+
 		s.logger.Info("OAuth user logged in",
 			zap.String("user_id", existingUser.ID),
 			zap.String("provider", oauthInfo.Provider),
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/repositories"
 	"github.com/hamsaya/backend/internal/utils"
+	"github.com/hamsaya/backend/pkg/bgtasks"
 	"go.uber.org/zap"
 )
 
@@ -18,7 +20,11 @@ type PollService struct {
 	postRepo            repositories.PostRepository
 	userRepo            repositories.UserRepository
 	notificationService *NotificationService
-	logger              *zap.Logger
+	// voteLockAfter is how long after casting a vote it can still be changed
+	// or withdrawn; zero never locks.
+	voteLockAfter time.Duration
+	limits        *models.ContentLimits
+	logger        *zap.Logger
 }
 
 // NewPollService creates a new poll service
@@ -38,6 +44,20 @@ func NewPollService(
 	}
 }
 
+// WithVoteLockAfter locks votes d after they are cast: they can no longer be
+// changed or withdrawn. Withdrawing and voting again keeps the original cast
+// time, so it doesn't reopen the window.
+func (s *PollService) WithVoteLockAfter(d time.Duration) *PollService {
+	s.voteLockAfter = d
+	return s
+}
+
+// WithContentLimits sets the poll option limits; defaults apply without it.
+func (s *PollService) WithContentLimits(limits models.ContentLimits) *PollService {
+	s.limits = &limits
+	return s
+}
+
 // CreatePoll creates a new poll for a PULL post
 func (s *PollService) CreatePoll(ctx context.Context, postID string, req *models.CreatePollRequest) (*models.PollResponse, error) {
 	// Validate post exists and is of type PULL
@@ -50,6 +70,18 @@ func (s *PollService) CreatePoll(ctx context.Context, postID string, req *models
 		return nil, utils.NewBadRequestError("Polls can only be created for PULL type posts", nil)
 	}
 
+	if err := validatePollSettings(req.ResultsVisibility, req.ClosesAt, time.Now()); err != nil {
+		return nil, err
+	}
+
+	limits := defaultContentLimits
+	if s.limits != nil {
+		limits = *s.limits
+	}
+	if err := checkPollOptions(req.Options, limits); err != nil {
+		return nil, err
+	}
+
 	// Check if poll already exists for this post
 	existingPoll, _ := s.pollRepo.GetByPostID(ctx, postID)
 	if existingPoll != nil {
@@ -61,10 +93,12 @@ func (s *PollService) CreatePoll(ctx context.Context, postID string, req *models
 	now := time.Now()
 
 	poll := &models.Poll{
-		ID:        pollID,
-		PostID:    postID,
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:                pollID,
+		PostID:            postID,
+		ResultsVisibility: req.ResultsVisibility,
+		ClosesAt:          req.ClosesAt,
+		CreatedAt:         now,
+		UpdatedAt:         now,
 	}
 
 	if err := s.pollRepo.Create(ctx, poll); err != nil {
@@ -128,10 +162,13 @@ func (s *PollService) GetPollByPostID(ctx context.Context, postID string, viewer
 // VotePoll votes on a poll option
 func (s *PollService) VotePoll(ctx context.Context, pollID, userID, optionID string) (*models.PollResponse, error) {
 	// Validate poll exists
-	_, err := s.pollRepo.GetByID(ctx, pollID)
+	poll, err := s.pollRepo.GetByID(ctx, pollID)
 	if err != nil {
 		return nil, utils.NewNotFoundError("Poll not found", err)
 	}
+	if poll.IsClosed(time.Now()) {
+		return nil, utils.NewConflictError("This poll is closed", nil)
+	}
 
 	// Validate option exists and belongs to this poll
 	option, err := s.pollRepo.GetOptionByID(ctx, optionID)
@@ -156,11 +193,9 @@ func (s *PollService) VotePoll(ctx context.Context, pollID, userID, optionID str
 	}
 
 	// If user already voted for a different option, change vote
-	if existingVote != nil && existingVote.PollOptionID != optionID {
-		// Note: The database trigger will handle incrementing/decrementing vote counts
-		if err := s.pollRepo.ChangeVote(ctx, userID, pollID, optionID); err != nil {
-			s.logger.Error("Failed to change vote", zap.Error(err))
-			return nil, utils.NewInternalError("Failed to change vote", err)
+	if existingVote != nil {
+		if err := s.changeVote(ctx, existingVote, optionID); err != nil {
+			return nil, err
 		}
 	} else {
 		// Create new vote
@@ -185,8 +220,7 @@ func (s *PollService) VotePoll(ctx context.Context, pollID, userID, optionID str
 	)
 
 	if s.notificationService != nil && s.userRepo != nil {
-		go func() {
-			ctxDetach := context.WithoutCancel(ctx)
+		bgtasks.Submit(func(ctxDetach context.Context) {
 			poll, err := s.pollRepo.GetByID(ctxDetach, pollID)
 			if err != nil {
 				return
@@ -223,20 +257,82 @@ func (s *PollService) VotePoll(ctx context.Context, pollID, userID, optionID str
 				Message: &msg,
 				Data:    data,
 			})
-		}()
+		})
 	}
 
 	// Return enriched poll
 	return s.GetPoll(ctx, pollID, &userID)
 }
 
+// ChangeVote moves the user's existing vote to optionID. Unlike VotePoll it
+// fails when the user hasn't voted yet.
+func (s *PollService) ChangeVote(ctx context.Context, pollID, userID, optionID string) (*models.PollResponse, error) {
+	poll, err := s.pollRepo.GetByID(ctx, pollID)
+	if err != nil {
+		return nil, utils.NewNotFoundError("Poll not found", err)
+	}
+	if poll.IsClosed(time.Now()) {
+		return nil, utils.NewConflictError("This poll is closed", nil)
+	}
+
+	existingVote, err := s.pollRepo.GetUserVote(ctx, userID, pollID)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to check existing vote", err)
+	}
+	if existingVote == nil {
+		return nil, utils.NewBadRequestError("User has not voted on this poll", nil)
+	}
+	if err := s.changeVote(ctx, existingVote, optionID); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("User changed poll vote",
+		zap.String("poll_id", pollID),
+		zap.String("user_id", userID),
+		zap.String("option_id", optionID),
+	)
+	return s.GetPoll(ctx, pollID, &userID)
+}
+
+// changeVote moves an existing vote unless it is locked.
+func (s *PollService) changeVote(ctx context.Context, vote *models.UserPoll, optionID string) error {
+	if vote.PollOptionID == optionID {
+		return nil
+	}
+	if s.voteLocked(vote, time.Now()) {
+		return utils.NewConflictError("Your vote is locked and can no longer be changed", nil)
+	}
+
+	err := s.pollRepo.ChangeVote(ctx, vote.UserID, vote.PollID, optionID)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, repositories.ErrPollVoteNotFound):
+		// Withdrawn concurrently.
+		return utils.NewConflictError("Your vote was withdrawn, vote again", err)
+	case errors.Is(err, repositories.ErrPollOptionNotFound):
+		return utils.NewNotFoundError("Poll option not found", err)
+	default:
+		s.logger.Error("Failed to change vote", zap.Error(err))
+		return utils.NewInternalError("Failed to change vote", err)
+	}
+}
+
+// voteLocked reports whether the vote's change window has passed.
+func (s *PollService) voteLocked(vote *models.UserPoll, now time.Time) bool {
+	return s.voteLockAfter > 0 && now.Sub(vote.CreatedAt) > s.voteLockAfter
+}
+
 // DeleteVote removes a user's vote from a poll
 func (s *PollService) DeleteVote(ctx context.Context, pollID, userID string) error {
 	// Validate poll exists
-	_, err := s.pollRepo.GetByID(ctx, pollID)
+	poll, err := s.pollRepo.GetByID(ctx, pollID)
 	if err != nil {
 		return utils.NewNotFoundError("Poll not found", err)
 	}
+	if poll.IsClosed(time.Now()) {
+		return utils.NewConflictError("This poll is closed", nil)
+	}
 
 	// Check if user has voted
 	existingVote, err := s.pollRepo.GetUserVote(ctx, userID, pollID)
@@ -247,6 +343,9 @@ func (s *PollService) DeleteVote(ctx context.Context, pollID, userID string) err
 	if existingVote == nil {
 		return utils.NewBadRequestError("User has not voted on this poll", nil)
 	}
+	if s.voteLocked(existingVote, time.Now()) {
+		return utils.NewConflictError("Your vote is locked and can no longer be withdrawn", nil)
+	}
 
 	// Delete vote (trigger will handle decrementing vote count)
 	if err := s.pollRepo.DeleteVote(ctx, userID, pollID); err != nil {
@@ -262,14 +361,63 @@ func (s *PollService) DeleteVote(ctx context.Context, pollID, userID string) err
 	return nil
 }
 
-// enrichPoll enriches a poll with options and user vote status
+// validatePollSettings checks the results visibility and closing time of a
+// new poll.
+func validatePollSettings(visibility models.PollResultsVisibility, closesAt *time.Time, now time.Time) error {
+	switch visibility {
+	case "", models.PollResultsAlways, models.PollResultsAfterVote:
+	case models.PollResultsAfterClose:
+		if closesAt == nil {
+			return utils.NewBadRequestError("closes_at is required when results are shown after the poll closes", nil)
+		}
+	default:
+		return utils.NewBadRequestError("results_visibility must be always, after_vote or after_close", nil)
+	}
+	if closesAt != nil && !closesAt.After(now) {
+		return utils.NewBadRequestError("closes_at must be in the future", nil)
+	}
+	return nil
+}
+
+// resultsVisible reports whether the viewer may see the poll's counts. The
+// post author always can.
+func (s *PollService) resultsVisible(ctx context.Context, poll *models.Poll, viewerID *string, hasVoted, closed bool) bool {
+	switch poll.ResultsVisibility {
+	case models.PollResultsAfterVote:
+		if hasVoted || closed {
+			return true
+		}
+	case models.PollResultsAfterClose:
+		if closed {
+			return true
+		}
+	default:
+		return true
+	}
+	if viewerID == nil || *viewerID == "" {
+		return false
+	}
+	post, err := s.postRepo.GetByID(ctx, poll.PostID)
+	return err == nil && post.UserID != nil && *post.UserID == *viewerID
+}
+
+// enrichPoll enriches a poll with options and user vote status. Counts are
+// zeroed and ResultsHidden set while the poll's results visibility keeps
+// them from the viewer; their own vote is still reported.
 func (s *PollService) enrichPoll(ctx context.Context, poll *models.Poll, viewerID *string) (*models.PollResponse, error) {
+	visibility := poll.ResultsVisibility
+	if visibility == "" {
+		visibility = models.PollResultsAlways
+	}
 	response := &models.PollResponse{
-		ID:        poll.ID,
-		PostID:    poll.PostID,
-		HasVoted:  false,
-		CreatedAt: poll.CreatedAt,
-		UpdatedAt: poll.UpdatedAt,
+		ID:                poll.ID,
+		PostID:            poll.PostID,
+		HasVoted:          false,
+		ResultsVisibility: visibility,
+		ClosesAt:          poll.ClosesAt,
+		Closed:            poll.IsClosed(time.Now()),
+		CreatedAt:         poll.CreatedAt,
+		UpdatedAt:         poll.UpdatedAt,
 	}
 
 	// Get poll options
@@ -328,5 +476,14 @@ func (s *PollService) enrichPoll(ctx context.Context, poll *models.Poll, viewerI
 		}
 	}
 
+	if !s.resultsVisible(ctx, poll, viewerID, response.HasVoted, response.Closed) {
+		response.ResultsHidden = true
+		response.TotalVotes = 0
+		for _, option := range response.Options {
+			option.VoteCount = 0
+			option.Percentage = 0
+		}
+	}
+
 	return response, nil
 }
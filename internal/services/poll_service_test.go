@@ -3,12 +3,14 @@ package services
 import (
 	"context"
 	"errors"
+	"net/http"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/hamsaya/backend/internal/mocks"
 	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -91,6 +93,22 @@ func TestPollService_CreatePoll(t *testing.T) {
 	}
 }
 
+func TestPollService_CreatePoll_OptionLimits(t *testing.T) {
+	postRepo := &mocks.MockPostRepository{}
+	postRepo.On("GetByID", mock.Anything, "post-1").Return(newPullPost("post-1"), nil)
+	limits := models.DefaultContentLimits()
+	limits.PollMaxOptions = 3
+	limits.PollOptionMaxLength = 5
+	svc := newTestPollService(&mocks.MockPollRepository{}, postRepo, new(mocks.MockUserRepository)).
+		WithContentLimits(limits)
+
+	_, err := svc.CreatePoll(context.Background(), "post-1", &models.CreatePollRequest{Options: []string{"a", "b", "c", "d"}})
+	requireAppErrorCode(t, err, http.StatusBadRequest)
+
+	_, err = svc.CreatePoll(context.Background(), "post-1", &models.CreatePollRequest{Options: []string{"a", "toolong"}})
+	requireAppErrorCode(t, err, http.StatusBadRequest)
+}
+
 func TestPollService_GetPoll(t *testing.T) {
 	t.Run("poll not found", func(t *testing.T) {
 		pollRepo := &mocks.MockPollRepository{}
@@ -329,3 +347,169 @@ func TestPollService_DeleteVote(t *testing.T) {
 		pollRepo.AssertExpectations(t)
 	})
 }
+
+func TestPollService_ResultsVisibility(t *testing.T) {
+	opts := func() []*models.PollOption {
+		return []*models.PollOption{
+			{ID: "opt-1", PollID: "poll-1", Option: "A", VoteCount: 3},
+			{ID: "opt-2", PollID: "poll-1", Option: "B", VoteCount: 1},
+		}
+	}
+	viewerID := "user-1"
+
+	t.Run("after_vote hides counts until the viewer votes", func(t *testing.T) {
+		pollRepo := &mocks.MockPollRepository{}
+		postRepo := &mocks.MockPostRepository{}
+		poll := newTestPoll("poll-1", "post-1")
+		poll.ResultsVisibility = models.PollResultsAfterVote
+		pollRepo.On("GetByID", mock.Anything, "poll-1").Return(poll, nil)
+		pollRepo.On("GetOptionsByPollID", mock.Anything, "poll-1").Return(opts(), nil)
+		pollRepo.On("GetUserVote", mock.Anything, viewerID, "poll-1").Return(nil, nil)
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(newPullPost("post-1"), nil)
+
+		svc := newTestPollService(pollRepo, postRepo, new(mocks.MockUserRepository))
+		resp, err := svc.GetPoll(context.Background(), "poll-1", &viewerID)
+
+		require.NoError(t, err)
+		assert.True(t, resp.ResultsHidden)
+		assert.Zero(t, resp.TotalVotes)
+		assert.Zero(t, resp.Options[0].VoteCount)
+		assert.Equal(t, "A", resp.Options[0].Option)
+	})
+
+	t.Run("after_vote shows counts with the viewer's vote", func(t *testing.T) {
+		pollRepo := &mocks.MockPollRepository{}
+		poll := newTestPoll("poll-1", "post-1")
+		poll.ResultsVisibility = models.PollResultsAfterVote
+		pollRepo.On("GetByID", mock.Anything, "poll-1").Return(poll, nil)
+		pollRepo.On("GetOptionsByPollID", mock.Anything, "poll-1").Return(opts(), nil)
+		pollRepo.On("GetUserVote", mock.Anything, viewerID, "poll-1").
+			Return(&models.UserPoll{UserID: viewerID, PollID: "poll-1", PollOptionID: "opt-2"}, nil)
+
+		svc := newTestPollService(pollRepo, &mocks.MockPostRepository{}, new(mocks.MockUserRepository))
+		resp, err := svc.GetPoll(context.Background(), "poll-1", &viewerID)
+
+		require.NoError(t, err)
+		assert.False(t, resp.ResultsHidden)
+		assert.Equal(t, 4, resp.TotalVotes)
+		assert.Equal(t, "opt-2", *resp.UserVote)
+	})
+
+	t.Run("after_close keeps counts from voters but not the author", func(t *testing.T) {
+		closesAt := time.Now().Add(time.Hour)
+		poll := newTestPoll("poll-1", "post-1")
+		poll.ResultsVisibility = models.PollResultsAfterClose
+		poll.ClosesAt = &closesAt
+
+		pollRepo := &mocks.MockPollRepository{}
+		postRepo := &mocks.MockPostRepository{}
+		pollRepo.On("GetByID", mock.Anything, "poll-1").Return(poll, nil)
+		pollRepo.On("GetOptionsByPollID", mock.Anything, "poll-1").Return(opts(), nil)
+		pollRepo.On("GetUserVote", mock.Anything, mock.Anything, "poll-1").
+			Return(&models.UserPoll{PollID: "poll-1", PollOptionID: "opt-1"}, nil)
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(newPullPost("post-1"), nil)
+		svc := newTestPollService(pollRepo, postRepo, new(mocks.MockUserRepository))
+
+		resp, err := svc.GetPoll(context.Background(), "poll-1", &viewerID)
+		require.NoError(t, err)
+		assert.True(t, resp.ResultsHidden)
+		assert.True(t, resp.HasVoted, "the viewer's own vote is still reported")
+
+		owner := "owner-1"
+		resp, err = svc.GetPoll(context.Background(), "poll-1", &owner)
+		require.NoError(t, err)
+		assert.False(t, resp.ResultsHidden)
+		assert.Equal(t, 4, resp.TotalVotes)
+	})
+
+	t.Run("closed poll shows counts and rejects votes", func(t *testing.T) {
+		closedAt := time.Now().Add(-time.Minute)
+		poll := newTestPoll("poll-1", "post-1")
+		poll.ResultsVisibility = models.PollResultsAfterClose
+		poll.ClosesAt = &closedAt
+
+		pollRepo := &mocks.MockPollRepository{}
+		pollRepo.On("GetByID", mock.Anything, "poll-1").Return(poll, nil)
+		pollRepo.On("GetOptionsByPollID", mock.Anything, "poll-1").Return(opts(), nil)
+		svc := newTestPollService(pollRepo, &mocks.MockPostRepository{}, new(mocks.MockUserRepository))
+
+		resp, err := svc.GetPoll(context.Background(), "poll-1", nil)
+		require.NoError(t, err)
+		assert.True(t, resp.Closed)
+		assert.False(t, resp.ResultsHidden)
+
+		_, err = svc.VotePoll(context.Background(), "poll-1", viewerID, "opt-1")
+		requireAppErrCode(t, err, http.StatusConflict)
+	})
+}
+
+func TestValidatePollSettings(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+	earlier := now.Add(-time.Hour)
+
+	assert.NoError(t, validatePollSettings("", nil, now))
+	assert.NoError(t, validatePollSettings(models.PollResultsAfterVote, nil, now))
+	assert.NoError(t, validatePollSettings(models.PollResultsAfterClose, &later, now))
+	requireAppErrCode(t, validatePollSettings(models.PollResultsAfterClose, nil, now), http.StatusBadRequest)
+	requireAppErrCode(t, validatePollSettings(models.PollResultsAlways, &earlier, now), http.StatusBadRequest)
+	requireAppErrCode(t, validatePollSettings("never", nil, now), http.StatusBadRequest)
+}
+
+func TestPollService_ChangeVote(t *testing.T) {
+	castAt := func(ago time.Duration) *models.UserPoll {
+		return &models.UserPoll{UserID: "user-1", PollID: "poll-1", PollOptionID: "opt-1", CreatedAt: time.Now().Add(-ago)}
+	}
+
+	t.Run("not voted yet", func(t *testing.T) {
+		pollRepo := &mocks.MockPollRepository{}
+		pollRepo.On("GetByID", mock.Anything, "poll-1").Return(newTestPoll("poll-1", "post-1"), nil)
+		pollRepo.On("GetUserVote", mock.Anything, "user-1", "poll-1").Return(nil, nil)
+		svc := newTestPollService(pollRepo, &mocks.MockPostRepository{}, new(mocks.MockUserRepository))
+
+		_, err := svc.ChangeVote(context.Background(), "poll-1", "user-1", "opt-2")
+		requireAppErrCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("locked after the window", func(t *testing.T) {
+		pollRepo := &mocks.MockPollRepository{}
+		pollRepo.On("GetByID", mock.Anything, "poll-1").Return(newTestPoll("poll-1", "post-1"), nil)
+		pollRepo.On("GetUserVote", mock.Anything, "user-1", "poll-1").Return(castAt(2*time.Hour), nil)
+		svc := newTestPollService(pollRepo, &mocks.MockPostRepository{}, new(mocks.MockUserRepository)).
+			WithVoteLockAfter(time.Hour)
+
+		_, err := svc.ChangeVote(context.Background(), "poll-1", "user-1", "opt-2")
+		requireAppErrCode(t, err, http.StatusConflict)
+		pollRepo.AssertNotCalled(t, "ChangeVote", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+		err = svc.DeleteVote(context.Background(), "poll-1", "user-1")
+		requireAppErrCode(t, err, http.StatusConflict)
+		pollRepo.AssertNotCalled(t, "DeleteVote", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("within the window", func(t *testing.T) {
+		pollRepo := &mocks.MockPollRepository{}
+		pollRepo.On("GetByID", mock.Anything, "poll-1").Return(newTestPoll("poll-1", "post-1"), nil)
+		pollRepo.On("GetUserVote", mock.Anything, "user-1", "poll-1").Return(castAt(time.Minute), nil)
+		pollRepo.On("ChangeVote", mock.Anything, "user-1", "poll-1", "opt-2").Return(nil)
+		pollRepo.On("GetOptionsByPollID", mock.Anything, "poll-1").Return([]*models.PollOption{}, nil)
+		svc := newTestPollService(pollRepo, &mocks.MockPostRepository{}, new(mocks.MockUserRepository)).
+			WithVoteLockAfter(time.Hour)
+
+		resp, err := svc.ChangeVote(context.Background(), "poll-1", "user-1", "opt-2")
+		require.NoError(t, err)
+		assert.NotNil(t, resp)
+		pollRepo.AssertExpectations(t)
+	})
+
+	t.Run("withdrawn concurrently", func(t *testing.T) {
+		pollRepo := &mocks.MockPollRepository{}
+		pollRepo.On("GetByID", mock.Anything, "poll-1").Return(newTestPoll("poll-1", "post-1"), nil)
+		pollRepo.On("GetUserVote", mock.Anything, "user-1", "poll-1").Return(castAt(time.Minute), nil)
+		pollRepo.On("ChangeVote", mock.Anything, "user-1", "poll-1", "opt-2").Return(repositories.ErrPollVoteNotFound)
+		svc := newTestPollService(pollRepo, &mocks.MockPostRepository{}, new(mocks.MockUserRepository))
+
+		_, err := svc.ChangeVote(context.Background(), "poll-1", "user-1", "opt-2")
+		requireAppErrCode(t, err, http.StatusConflict)
+	})
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+)
+
+// PostAudience is how a viewer relates to a post's author, as far as
+// visibility is concerned.
+type PostAudience string
+
+const (
+	AudienceAuthor   PostAudience = "author"
+	AudiencePublic   PostAudience = "public" // signed out, or no relationship
+	AudienceFollower PostAudience = "follower"
+	AudienceBlocked  PostAudience = "blocked" // either side blocked the other
+)
+
+// ParsePreviewAudience parses the ?as= value of a post preview. Only
+// public, follower and blocked can be previewed.
+func ParsePreviewAudience(s string) (PostAudience, bool) {
+	switch a := PostAudience(strings.ToLower(strings.TrimSpace(s))); a {
+	case AudiencePublic, AudienceFollower, AudienceBlocked:
+		return a, true
+	}
+	return "", false
+}
+
+// postViewer is a resolved viewer: their audience plus what the rule needs
+// to know about the post's surroundings.
+type postViewer struct {
+	Audience PostAudience
+	// GroupAccess: the post's group is public or the viewer is an active
+	// member. Ignored for non-group posts.
+	GroupAccess bool
+	// BusinessActive: the post's business exists and is active. Ignored for
+	// personal posts.
+	BusinessActive bool
+}
+
+// postVisibleTo is the single visibility rule for reading a post. The author
+// always sees it. Anyone else needs an active post (and business), no block
+// either way, and group access for group posts; PRIVATE is author-only and
+// FRIENDS needs a follower.
+func postVisibleTo(post *models.Post, v postViewer) bool {
+	if v.Audience == AudienceAuthor {
+		return true
+	}
+	if !post.Status {
+		return false
+	}
+	if post.BusinessID != nil && !v.BusinessActive {
+		return false
+	}
+	if v.Audience == AudienceBlocked {
+		return false
+	}
+	if post.GroupID != nil && !v.GroupAccess {
+		return false
+	}
+	switch post.Visibility {
+	case models.VisibilityPrivate:
+		return false
+	case models.VisibilityFriends:
+		return v.Audience == AudienceFollower
+	}
+	return true
+}
+
+// PostAuthorizer decides who may read a post. It resolves the viewer's
+// relationship to the author, the business and the group, then applies
+// postVisibleTo, so every read path (single post, feeds, search, discover)
+// answers the same way. Lookup failures deny.
+type PostAuthorizer struct {
+	relationshipsRepo repositories.RelationshipsRepository
+	businessRepo      repositories.BusinessRepository
+	groupRepo         repositories.GroupRepository // optional; nil = group posts are readable
+}
+
+// NewPostAuthorizer creates a post authorizer.
+func NewPostAuthorizer(relationshipsRepo repositories.RelationshipsRepository, businessRepo repositories.BusinessRepository) *PostAuthorizer {
+	return &PostAuthorizer{
+		relationshipsRepo: relationshipsRepo,
+		businessRepo:      businessRepo,
+	}
+}
+
+// WithGroups enables the membership check on posts in private groups.
+func (a *PostAuthorizer) WithGroups(groupRepo repositories.GroupRepository) *PostAuthorizer {
+	a.groupRepo = groupRepo
+	return a
+}
+
+// CanView reports whether viewerID (nil when signed out) may read post.
+func (a *PostAuthorizer) CanView(ctx context.Context, post *models.Post, viewerID *string) bool {
+	if isPostAuthor(post, viewerID) {
+		return true
+	}
+	if !post.Status {
+		return false
+	}
+	v := postViewer{
+		Audience:       AudienceFollower,
+		GroupAccess:    a.groupAccess(ctx, post, viewerID),
+		BusinessActive: a.businessActive(ctx, post),
+	}
+	// Hidden even from a follower: skip the relationship lookup.
+	if !postVisibleTo(post, v) {
+		return false
+	}
+	audience, ok := a.audienceOf(ctx, post, viewerID)
+	if !ok {
+		return false
+	}
+	v.Audience = audience
+	return postVisibleTo(post, v)
+}
+
+// CanViewAs reports whether a hypothetical viewer in audience may read post.
+// Followers and blocked users are treated as non-members of the post's group.
+func (a *PostAuthorizer) CanViewAs(ctx context.Context, post *models.Post, audience PostAudience) bool {
+	return postVisibleTo(post, postViewer{
+		Audience:       audience,
+		GroupAccess:    a.groupAccess(ctx, post, nil),
+		BusinessActive: a.businessActive(ctx, post),
+	})
+}
+
+// FilterVisible returns the posts viewerID may read, in their original
+// order. Relationships and businesses are loaded with one query each, groups
+// once per group.
+func (a *PostAuthorizer) FilterVisible(ctx context.Context, posts []*models.Post, viewerID *string) []*models.Post {
+	if len(posts) == 0 {
+		return posts
+	}
+	signedIn := viewerID != nil && *viewerID != ""
+
+	var authorIDs, businessIDs []string
+	seen := map[string]bool{}
+	for _, p := range posts {
+		if isPostAuthor(p, viewerID) {
+			continue
+		}
+		if signedIn && p.UserID != nil && !seen["u:"+*p.UserID] {
+			seen["u:"+*p.UserID] = true
+			authorIDs = append(authorIDs, *p.UserID)
+		}
+		if p.BusinessID != nil && !seen["b:"+*p.BusinessID] {
+			seen["b:"+*p.BusinessID] = true
+			businessIDs = append(businessIDs, *p.BusinessID)
+		}
+	}
+
+	statuses := map[string]*models.RelationshipStatus{}
+	if len(authorIDs) > 0 {
+		var err error
+		if statuses, err = a.relationshipsRepo.GetRelationshipStatuses(ctx, *viewerID, authorIDs); err != nil {
+			statuses = nil // deny everything but the viewer's own posts
+		}
+	}
+
+	activeBusinesses := map[string]bool{}
+	if len(businessIDs) > 0 {
+		if businesses, err := a.businessRepo.GetByIDs(ctx, businessIDs); err == nil {
+			for _, b := range businesses {
+				activeBusinesses[b.ID] = b.Status
+			}
+		}
+	}
+
+	groupAccess := map[string]bool{}
+	out := make([]*models.Post, 0, len(posts))
+	for _, p := range posts {
+		if isPostAuthor(p, viewerID) {
+			out = append(out, p)
+			continue
+		}
+		v := postViewer{Audience: AudiencePublic, GroupAccess: true}
+		if signedIn && p.UserID != nil {
+			if statuses == nil {
+				continue
+			}
+			v.Audience = audienceFromStatus(statuses[*p.UserID])
+		}
+		if p.BusinessID != nil {
+			v.BusinessActive = activeBusinesses[*p.BusinessID]
+		}
+		if p.GroupID != nil {
+			access, ok := groupAccess[*p.GroupID]
+			if !ok {
+				access = a.groupAccess(ctx, p, viewerID)
+				groupAccess[*p.GroupID] = access
+			}
+			v.GroupAccess = access
+		}
+		if postVisibleTo(p, v) {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func isPostAuthor(post *models.Post, viewerID *string) bool {
+	return viewerID != nil && *viewerID != "" && post.UserID != nil && *post.UserID == *viewerID
+}
+
+func audienceFromStatus(status *models.RelationshipStatus) PostAudience {
+	switch {
+	case status == nil:
+		return AudiencePublic
+	case status.IsBlocked || status.HasBlockedMe:
+		return AudienceBlocked
+	case status.IsFollowing:
+		return AudienceFollower
+	}
+	return AudiencePublic
+}
+
+// audienceOf resolves viewerID's relationship to the post's author. ok is
+// false when the relationship couldn't be loaded.
+func (a *PostAuthorizer) audienceOf(ctx context.Context, post *models.Post, viewerID *string) (PostAudience, bool) {
+	if viewerID == nil || *viewerID == "" || post.UserID == nil {
+		return AudiencePublic, true
+	}
+	if *post.UserID == *viewerID {
+		return AudienceAuthor, true
+	}
+	status, err := a.relationshipsRepo.GetRelationshipStatus(ctx, *viewerID, *post.UserID)
+	if err != nil {
+		return "", false
+	}
+	return audienceFromStatus(status), true
+}
+
+// businessActive reports whether the post's business exists and is active.
+func (a *PostAuthorizer) businessActive(ctx context.Context, post *models.Post) bool {
+	if post.BusinessID == nil {
+		return true
+	}
+	business, err := a.businessRepo.GetByID(ctx, *post.BusinessID)
+	return err == nil && business != nil && business.Status
+}
+
+// groupAccess reports whether viewerID can read posts in the post's group.
+// Private (or deleted) groups are readable only by active members.
+func (a *PostAuthorizer) groupAccess(ctx context.Context, post *models.Post, viewerID *string) bool {
+	if post.GroupID == nil || a.groupRepo == nil {
+		return true
+	}
+	group, err := a.groupRepo.GetByID(ctx, *post.GroupID)
+	if err != nil {
+		return false
+	}
+	if group.Privacy == models.GroupPublic {
+		return true
+	}
+	if viewerID == nil || *viewerID == "" {
+		return false
+	}
+	member, err := a.groupRepo.GetMember(ctx, group.ID, *viewerID)
+	return err == nil && member != nil && member.Status == models.GroupMemberActive
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostVisibleTo(t *testing.T) {
+	visibilities := []models.PostVisibility{
+		models.VisibilityPublic, models.VisibilityViewOnly, models.VisibilityFriends,
+		models.VisibilityPrivate, models.VisibilityGroup,
+	}
+	audiences := []PostAudience{AudienceAuthor, AudienceFollower, AudiencePublic, AudienceBlocked}
+
+	// want[visibility][audience] for an active personal post outside a group.
+	want := map[models.PostVisibility]map[PostAudience]bool{
+		models.VisibilityPublic:   {AudienceAuthor: true, AudienceFollower: true, AudiencePublic: true, AudienceBlocked: false},
+		models.VisibilityViewOnly: {AudienceAuthor: true, AudienceFollower: true, AudiencePublic: true, AudienceBlocked: false},
+		models.VisibilityFriends:  {AudienceAuthor: true, AudienceFollower: true, AudiencePublic: false, AudienceBlocked: false},
+		models.VisibilityPrivate:  {AudienceAuthor: true, AudienceFollower: false, AudiencePublic: false, AudienceBlocked: false},
+		models.VisibilityGroup:    {AudienceAuthor: true, AudienceFollower: true, AudiencePublic: true, AudienceBlocked: false},
+	}
+
+	for _, vis := range visibilities {
+		for _, aud := range audiences {
+			post := testutil.CreateTestPost("post-1", "owner-1", models.PostTypeFeed)
+			post.Visibility = vis
+			viewer := postViewer{Audience: aud, GroupAccess: true, BusinessActive: true}
+
+			t.Run(string(vis)+"/"+string(aud), func(t *testing.T) {
+				assert.Equal(t, want[vis][aud], postVisibleTo(post, viewer))
+			})
+
+			t.Run(string(vis)+"/"+string(aud)+"/hidden post", func(t *testing.T) {
+				hidden := *post
+				hidden.Status = false
+				assert.Equal(t, aud == AudienceAuthor, postVisibleTo(&hidden, viewer))
+			})
+
+			t.Run(string(vis)+"/"+string(aud)+"/inactive business", func(t *testing.T) {
+				bizPost := *post
+				bizID := "biz-1"
+				bizPost.BusinessID = &bizID
+				v := viewer
+				v.BusinessActive = false
+				assert.Equal(t, aud == AudienceAuthor, postVisibleTo(&bizPost, v))
+
+				v.BusinessActive = true
+				assert.Equal(t, want[vis][aud], postVisibleTo(&bizPost, v))
+			})
+
+			t.Run(string(vis)+"/"+string(aud)+"/private group non-member", func(t *testing.T) {
+				groupPost := *post
+				groupID := "g-1"
+				groupPost.GroupID = &groupID
+				v := viewer
+				v.GroupAccess = false
+				assert.Equal(t, aud == AudienceAuthor, postVisibleTo(&groupPost, v))
+			})
+		}
+	}
+}
+
+func TestPostAuthorizer_CanView(t *testing.T) {
+	friendsPost := testutil.CreateTestPost("post-1", "owner-1", models.PostTypeFeed)
+	friendsPost.Visibility = models.VisibilityFriends
+	viewer := "user-2"
+
+	t.Run("follower sees friends-only post", func(t *testing.T) {
+		relRepo := new(mocks.MockRelationshipsRepository)
+		relRepo.On("GetRelationshipStatus", mock.Anything, viewer, "owner-1").
+			Return(&models.RelationshipStatus{IsFollowing: true}, nil)
+
+		assert.True(t, NewPostAuthorizer(relRepo, new(mocks.MockBusinessRepository)).CanView(context.Background(), friendsPost, &viewer))
+	})
+
+	t.Run("non-follower does not", func(t *testing.T) {
+		relRepo := new(mocks.MockRelationshipsRepository)
+		relRepo.On("GetRelationshipStatus", mock.Anything, viewer, "owner-1").
+			Return(&models.RelationshipStatus{}, nil)
+
+		assert.False(t, NewPostAuthorizer(relRepo, new(mocks.MockBusinessRepository)).CanView(context.Background(), friendsPost, &viewer))
+		assert.False(t, NewPostAuthorizer(relRepo, new(mocks.MockBusinessRepository)).CanView(context.Background(), friendsPost, nil))
+	})
+
+	t.Run("lookup failure denies", func(t *testing.T) {
+		relRepo := new(mocks.MockRelationshipsRepository)
+		relRepo.On("GetRelationshipStatus", mock.Anything, viewer, "owner-1").
+			Return(nil, errors.New("db down"))
+
+		publicPost := testutil.CreateTestPost("post-2", "owner-1", models.PostTypeFeed)
+		assert.False(t, NewPostAuthorizer(relRepo, new(mocks.MockBusinessRepository)).CanView(context.Background(), publicPost, &viewer))
+	})
+
+	t.Run("author always sees their post", func(t *testing.T) {
+		owner := "owner-1"
+		private := testutil.CreateTestPost("post-3", owner, models.PostTypeFeed)
+		private.Visibility = models.VisibilityPrivate
+
+		assert.True(t, NewPostAuthorizer(new(mocks.MockRelationshipsRepository), new(mocks.MockBusinessRepository)).CanView(context.Background(), private, &owner))
+	})
+}
+
+func TestPostService_PreviewPost(t *testing.T) {
+	t.Run("only the author can preview", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").
+			Return(testutil.CreateTestPost("post-1", "owner-1", models.PostTypeFeed), nil)
+		svc := newTestPostService(postRepo, new(mocks.MockUserRepository))
+
+		_, err := svc.PreviewPost(context.Background(), "post-1", "user-2", AudiencePublic)
+		requireAppErrCode(t, err, http.StatusForbidden)
+	})
+
+	t.Run("friends-only post is hidden from the public", func(t *testing.T) {
+		post := testutil.CreateTestPost("post-1", "owner-1", models.PostTypeFeed)
+		post.Visibility = models.VisibilityFriends
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(post, nil)
+		svc := newTestPostService(postRepo, new(mocks.MockUserRepository))
+
+		_, err := svc.PreviewPost(context.Background(), "post-1", "owner-1", AudiencePublic)
+		requireAppErrCode(t, err, http.StatusNotFound)
+		_, err = svc.PreviewPost(context.Background(), "post-1", "owner-1", AudienceBlocked)
+		requireAppErrCode(t, err, http.StatusNotFound)
+	})
+
+	t.Run("followers see the signed-out view", func(t *testing.T) {
+		post := testutil.CreateTestPost("post-1", "owner-1", models.PostTypeFeed)
+		post.Visibility = models.VisibilityFriends
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(post, nil)
+		postRepo.On("GetAttachmentsByPostID", mock.Anything, "post-1").Return(nil, nil)
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetProfileByUserID", mock.Anything, "owner-1").
+			Return(testutil.CreateTestProfile("owner-1", "Ahmad", "Karimi"), nil)
+		svc := newTestPostService(postRepo, userRepo)
+
+		got, err := svc.PreviewPost(context.Background(), "post-1", "owner-1", AudienceFollower)
+		require.NoError(t, err)
+		assert.Equal(t, "post-1", got.ID)
+		postRepo.AssertNotCalled(t, "GetEngagementStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestParsePreviewAudience(t *testing.T) {
+	a, ok := ParsePreviewAudience(" Follower ")
+	assert.True(t, ok)
+	assert.Equal(t, AudienceFollower, a)
+
+	_, ok = ParsePreviewAudience("author")
+	assert.False(t, ok)
+}
+
+func TestPostAuthorizer_FilterVisible(t *testing.T) {
+	viewer := "viewer-1"
+	public := testutil.CreateTestPost("p-public", "author-1", models.PostTypeFeed)
+	friends := testutil.CreateTestPost("p-friends", "author-1", models.PostTypeFeed)
+	friends.Visibility = models.VisibilityFriends
+	blocked := testutil.CreateTestPost("p-blocked", "author-2", models.PostTypeFeed)
+	own := testutil.CreateTestPost("p-own", viewer, models.PostTypeFeed)
+	own.Visibility = models.VisibilityPrivate
+	bizID := "biz-off"
+	bizPost := testutil.CreateTestPost("p-biz", "author-3", models.PostTypeEvent)
+	bizPost.BusinessID = &bizID
+	posts := []*models.Post{public, friends, blocked, own, bizPost}
+
+	t.Run("applies the rule per post in one batch", func(t *testing.T) {
+		relRepo := new(mocks.MockRelationshipsRepository)
+		relRepo.On("GetRelationshipStatuses", mock.Anything, viewer, []string{"author-1", "author-2", "author-3"}).
+			Return(map[string]*models.RelationshipStatus{
+				"author-1": {},
+				"author-2": {HasBlockedMe: true},
+			}, nil)
+		businessRepo := new(mocks.MockBusinessRepository)
+		businessRepo.On("GetByIDs", mock.Anything, []string{"biz-off"}).
+			Return([]*models.BusinessProfile{{ID: "biz-off", Status: false}}, nil)
+
+		got := NewPostAuthorizer(relRepo, businessRepo).FilterVisible(context.Background(), posts, &viewer)
+		assert.Equal(t, []*models.Post{public, own}, got)
+	})
+
+	t.Run("relationship failure keeps only the viewer's posts", func(t *testing.T) {
+		relRepo := new(mocks.MockRelationshipsRepository)
+		relRepo.On("GetRelationshipStatuses", mock.Anything, viewer, mock.Anything).Return(nil, errors.New("db down"))
+		businessRepo := new(mocks.MockBusinessRepository)
+		businessRepo.On("GetByIDs", mock.Anything, mock.Anything).Return([]*models.BusinessProfile{}, nil)
+
+		got := NewPostAuthorizer(relRepo, businessRepo).FilterVisible(context.Background(), posts, &viewer)
+		assert.Equal(t, []*models.Post{own}, got)
+	})
+
+	t.Run("signed out needs no relationship lookup", func(t *testing.T) {
+		relRepo := new(mocks.MockRelationshipsRepository)
+		businessRepo := new(mocks.MockBusinessRepository)
+		businessRepo.On("GetByIDs", mock.Anything, []string{"biz-off"}).
+			Return([]*models.BusinessProfile{{ID: "biz-off", Status: true}}, nil)
+
+		got := NewPostAuthorizer(relRepo, businessRepo).FilterVisible(context.Background(), posts, nil)
+		assert.Equal(t, []*models.Post{public, blocked, bizPost}, got)
+		relRepo.AssertNotCalled(t, "GetRelationshipStatuses", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/pkg/runtimeconfig"
+	"go.uber.org/zap"
+)
+
+// SettingBookmarkMilestones switches the seller bookmark-milestone
+// notifications on or off.
+const SettingBookmarkMilestones = "notifications.bookmark_milestones"
+
+// bookmarkMilestones are the save counts a seller is told about.
+var bookmarkMilestones = []int{5, 10, 25}
+
+// PostBookmarkCounter exposes a post's bookmark count (posts.total_bookmarks,
+// kept by trigger) to its owner, and tells sellers when their listing is
+// saved 5, 10 and 25 times. Each milestone is announced once per listing,
+// even if saves drop below it and climb back.
+//
+// A nil *PostBookmarkCounter does nothing, so services work unwired.
+type PostBookmarkCounter struct {
+	postRepo repositories.PostRepository
+	notif    *NotificationService
+	settings *runtimeconfig.Store
+	logger   *zap.Logger
+}
+
+// NewPostBookmarkCounter registers the milestone setting with store.
+func NewPostBookmarkCounter(postRepo repositories.PostRepository, notif *NotificationService, store *runtimeconfig.Store, logger *zap.Logger) *PostBookmarkCounter {
+	store.Register(runtimeconfig.Setting{
+		Key:         SettingBookmarkMilestones,
+		Kind:        runtimeconfig.KindBool,
+		Default:     "true",
+		Description: "Notify sellers when a listing is saved 5, 10 and 25 times",
+	})
+	return &PostBookmarkCounter{
+		postRepo: postRepo,
+		notif:    notif,
+		settings: store,
+		logger:   logger,
+	}
+}
+
+// ApplyToOwners sets TotalBookmarks on the responses the viewer owns
+// (IsMine). Nobody else sees it.
+func (c *PostBookmarkCounter) ApplyToOwners(ctx context.Context, responses []*models.PostResponse) {
+	if c == nil {
+		return
+	}
+	var ids []string
+	for _, r := range responses {
+		if r != nil && r.IsMine {
+			ids = append(ids, r.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	counts, err := c.postRepo.GetBookmarkCounts(ctx, ids)
+	if err != nil {
+		c.logger.Warn("Failed to get post bookmark counts", zap.Error(err))
+		return
+	}
+	for _, r := range responses {
+		if r != nil && r.IsMine {
+			n := counts[r.ID]
+			r.TotalBookmarks = &n
+		}
+	}
+}
+
+// RecordSave notifies the seller when a save by bookmarkerID takes their
+// listing past a milestone. Other posts, and sellers saving their own
+// listing, are ignored.
+func (c *PostBookmarkCounter) RecordSave(ctx context.Context, post *models.Post, bookmarkerID string) {
+	if c == nil || c.notif == nil || post.Type != models.PostTypeSell ||
+		post.UserID == nil || *post.UserID == bookmarkerID ||
+		!c.settings.Bool(SettingBookmarkMilestones, true) {
+		return
+	}
+
+	milestone, err := c.postRepo.ClaimBookmarkMilestone(ctx, post.ID, bookmarkMilestones)
+	if err != nil {
+		c.logger.Warn("Failed to claim bookmark milestone", zap.String("post_id", post.ID), zap.Error(err))
+		return
+	}
+	if milestone == 0 {
+		return
+	}
+
+	title := fmt.Sprintf("Your listing was saved %d times", milestone)
+	msg := fmt.Sprintf("%d neighbors saved your listing. It's getting attention!", milestone)
+	if post.Title != nil && strings.TrimSpace(*post.Title) != "" {
+		msg = fmt.Sprintf("%d neighbors saved \"%s\". It's getting attention!", milestone, strings.TrimSpace(*post.Title))
+	}
+	if _, err := c.notif.CreateNotification(ctx, &models.CreateNotificationRequest{
+		UserID:  *post.UserID,
+		Type:    models.NotificationTypeSellBookmarkMilestone,
+		Title:   &title,
+		Message: &msg,
+		Data: map[string]interface{}{
+			"post_id":   post.ID,
+			"post_type": strings.ToUpper(string(post.Type)),
+			"bookmarks": milestone,
+		},
+	}); err != nil {
+		c.logger.Warn("Failed to send bookmark milestone", zap.String("post_id", post.ID), zap.Error(err))
+	}
+}
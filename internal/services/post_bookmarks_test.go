@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/runtimeconfig"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestBookmarkCounter(t *testing.T, postRepo *mocks.MockPostRepository, notifRepo *mocks.MockNotificationRepository) (*PostBookmarkCounter, *runtimeconfig.Store) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	settingsRepo := new(mocks.MockNotificationSettingsRepository)
+	settingsRepo.On("GetByProfileID", mock.Anything, mock.Anything).Return([]*models.NotificationSetting{}, nil)
+	notif := NewNotificationService(notifRepo, settingsRepo, nil, nil, rdb, nil, zap.NewNop())
+	store := runtimeconfig.New(rdb, zap.NewNop())
+	return NewPostBookmarkCounter(postRepo, notif, store, zap.NewNop()), store
+}
+
+func TestPostBookmarkCounter_ApplyToOwners(t *testing.T) {
+	ctx := context.Background()
+	postRepo := new(mocks.MockPostRepository)
+	postRepo.On("GetBookmarkCounts", ctx, []string{"mine-1", "mine-2"}).
+		Return(map[string]int{"mine-1": 4}, nil)
+	counter, _ := newTestBookmarkCounter(t, postRepo, new(mocks.MockNotificationRepository))
+
+	responses := []*models.PostResponse{
+		{ID: "mine-1", IsMine: true},
+		{ID: "other", IsMine: false},
+		{ID: "mine-2", IsMine: true},
+	}
+	counter.ApplyToOwners(ctx, responses)
+
+	require.NotNil(t, responses[0].TotalBookmarks)
+	assert.Equal(t, 4, *responses[0].TotalBookmarks)
+	assert.Nil(t, responses[1].TotalBookmarks)
+	require.NotNil(t, responses[2].TotalBookmarks)
+	assert.Equal(t, 0, *responses[2].TotalBookmarks)
+
+	var nilCounter *PostBookmarkCounter
+	nilCounter.ApplyToOwners(ctx, responses)
+	nilCounter.RecordSave(ctx, &models.Post{ID: "post-1"}, "viewer-1")
+}
+
+func TestPostBookmarkCounter_RecordSave(t *testing.T) {
+	ctx := context.Background()
+	seller := "seller-1"
+	title := "Bicycle"
+	listing := &models.Post{ID: "post-1", Type: models.PostTypeSell, UserID: &seller, Title: &title}
+
+	t.Run("milestone reached notifies the seller", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("ClaimBookmarkMilestone", ctx, "post-1", bookmarkMilestones).Return(10, nil).Once()
+		notifRepo := new(mocks.MockNotificationRepository)
+		notifRepo.On("Create", mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+			return n.UserID == seller && n.Type == models.NotificationTypeSellBookmarkMilestone &&
+				*n.Title == "Your listing was saved 10 times" && n.Data["bookmarks"] == 10
+		})).Return(nil).Once()
+		counter, _ := newTestBookmarkCounter(t, postRepo, notifRepo)
+
+		counter.RecordSave(ctx, listing, "buyer-1")
+		postRepo.AssertExpectations(t)
+		notifRepo.AssertExpectations(t)
+	})
+
+	t.Run("no new milestone", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("ClaimBookmarkMilestone", ctx, "post-1", bookmarkMilestones).Return(0, nil).Once()
+		notifRepo := new(mocks.MockNotificationRepository)
+		counter, _ := newTestBookmarkCounter(t, postRepo, notifRepo)
+
+		counter.RecordSave(ctx, listing, "buyer-1")
+		notifRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("ignored for own saves, other posts and when switched off", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		counter, store := newTestBookmarkCounter(t, postRepo, new(mocks.MockNotificationRepository))
+
+		counter.RecordSave(ctx, listing, seller)
+		counter.RecordSave(ctx, &models.Post{ID: "post-2", Type: models.PostTypeFeed, UserID: &seller}, "buyer-1")
+		require.NoError(t, store.Set(ctx, SettingBookmarkMilestones, "false"))
+		counter.RecordSave(ctx, listing, "buyer-1")
+		postRepo.AssertNotCalled(t, "ClaimBookmarkMilestone", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
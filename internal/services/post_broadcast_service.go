@@ -0,0 +1,366 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/hamsaya/backend/pkg/runtimeconfig"
+	"go.uber.org/zap"
+)
+
+// Runtime setting keys for the broadcast caps.
+const (
+	SettingBroadcastMaxRadiusKm       = "broadcast.max_radius_km"
+	SettingBroadcastMaxRecipients     = "broadcast.max_recipients"
+	SettingBroadcastApprovalThreshold = "broadcast.approval_threshold"
+)
+
+// Broadcast cap defaults, used until runtime settings say otherwise.
+const (
+	defaultBroadcastMaxRadiusKm       = 50
+	defaultBroadcastMaxRecipients     = 20000
+	defaultBroadcastApprovalThreshold = 1000
+)
+
+// broadcastBatchSize is how many recipients are resolved and notified per
+// batch, and broadcastBatchesPerRun bounds one worker run so a huge broadcast
+// can't hold the job lock for long; the next run resumes from the cursor.
+const (
+	broadcastBatchSize     = 200
+	broadcastBatchesPerRun = 10
+)
+
+// PostBroadcastService lets the author of an EVENT or ALERT post (or an
+// admin) push it to everyone within a radius. Broadcasts that would reach
+// more people than the approval threshold wait for an admin; the worker
+// sends approved ones in batches.
+type PostBroadcastService struct {
+	broadcastRepo       repositories.PostBroadcastRepository
+	postRepo            repositories.PostRepository
+	notificationService *NotificationService
+	settings            *runtimeconfig.Store
+	access              *AccessControl // optional; nil = only the post's author
+	logger              *zap.Logger
+}
+
+// NewPostBroadcastService constructs the service.
+func NewPostBroadcastService(
+	broadcastRepo repositories.PostBroadcastRepository,
+	postRepo repositories.PostRepository,
+	notificationService *NotificationService,
+	logger *zap.Logger,
+) *PostBroadcastService {
+	return &PostBroadcastService{
+		broadcastRepo:       broadcastRepo,
+		postRepo:            postRepo,
+		notificationService: notificationService,
+		logger:              logger,
+	}
+}
+
+// WithAccessControl lets business owners broadcast the posts published as
+// their business.
+func (s *PostBroadcastService) WithAccessControl(a *AccessControl) *PostBroadcastService {
+	s.access = a
+	return s
+}
+
+// WithRuntimeSettings makes the radius, recipient and approval caps tunable
+// at runtime.
+func (s *PostBroadcastService) WithRuntimeSettings(store *runtimeconfig.Store) *PostBroadcastService {
+	store.Register(runtimeconfig.Setting{
+		Key:         SettingBroadcastMaxRadiusKm,
+		Kind:        runtimeconfig.KindInt,
+		Default:     strconv.Itoa(defaultBroadcastMaxRadiusKm),
+		Description: "Largest radius (km) a post broadcast may cover",
+	})
+	store.Register(runtimeconfig.Setting{
+		Key:         SettingBroadcastMaxRecipients,
+		Kind:        runtimeconfig.KindInt,
+		Default:     strconv.Itoa(defaultBroadcastMaxRecipients),
+		Description: "Most users a single post broadcast is sent to",
+	})
+	store.Register(runtimeconfig.Setting{
+		Key:         SettingBroadcastApprovalThreshold,
+		Kind:        runtimeconfig.KindInt,
+		Default:     strconv.Itoa(defaultBroadcastApprovalThreshold),
+		Description: "Post broadcasts reaching more users than this wait for admin approval",
+	})
+	s.settings = store
+	return s
+}
+
+func (s *PostBroadcastService) setting(key string, fallback int) int {
+	if s.settings == nil {
+		return fallback
+	}
+	return s.settings.Int(key, fallback)
+}
+
+// Request creates a broadcast of postID over radiusKm. Only the author may
+// ask unless asAdmin is set; admin broadcasts skip the approval queue.
+func (s *PostBroadcastService) Request(ctx context.Context, postID, requesterID string, asAdmin bool, radiusKm float64) (*models.PostBroadcast, error) {
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil || post == nil {
+		return nil, utils.NewNotFoundError("Post not found", err)
+	}
+	if !asAdmin && !s.access.CanEditPost(ctx, post, requesterID) {
+		return nil, utils.NewForbiddenError("Only the author can broadcast this post", nil)
+	}
+	if !models.IsBroadcastablePostType(post.Type) {
+		return nil, utils.NewBadRequestError("Only EVENT and ALERT posts can be broadcast", nil)
+	}
+	if post.GroupID != nil {
+		return nil, utils.NewBadRequestError("Group posts can't be broadcast outside the group", nil)
+	}
+	if !post.Status {
+		return nil, utils.NewBadRequestError("Hidden posts can't be broadcast", nil)
+	}
+	if post.AddressLocation == nil || !post.AddressLocation.Valid {
+		return nil, utils.NewBadRequestError("Post has no location to broadcast from", nil)
+	}
+
+	maxRadius := s.setting(SettingBroadcastMaxRadiusKm, defaultBroadcastMaxRadiusKm)
+	if radiusKm > float64(maxRadius) {
+		return nil, utils.NewBadRequestError(fmt.Sprintf("Broadcast radius can be at most %d km", maxRadius), nil)
+	}
+
+	// An alert already reaches its urgency radius on its own; the broadcast
+	// only adds the ring beyond it.
+	var excludeRadiusKm float64
+	if post.Type == models.PostTypeAlert && post.Urgency != nil {
+		excludeRadiusKm = post.Urgency.AlertRadiusKm()
+		if radiusKm <= excludeRadiusKm {
+			return nil, utils.NewBadRequestError(
+				fmt.Sprintf("This alert already reaches %g km; choose a larger radius", excludeRadiusKm), nil)
+		}
+	}
+
+	authorID := ""
+	if post.UserID != nil {
+		authorID = *post.UserID
+	}
+	lat, lng := post.AddressLocation.P.Y, post.AddressLocation.P.X
+	count, err := s.broadcastRepo.CountRecipients(ctx, lat, lng, radiusKm, excludeRadiusKm, authorID)
+	if err != nil {
+		s.logger.Error("Failed to count broadcast recipients", zap.String("post_id", postID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to create broadcast", err)
+	}
+
+	maxRecipients := s.setting(SettingBroadcastMaxRecipients, defaultBroadcastMaxRecipients)
+	broadcast := &models.PostBroadcast{
+		ID:                  uuid.NewString(),
+		PostID:              postID,
+		RequestedBy:         requesterID,
+		RadiusKm:            radiusKm,
+		ExcludeRadiusKm:     excludeRadiusKm,
+		Latitude:            lat,
+		Longitude:           lng,
+		Status:              models.BroadcastStatusQueued,
+		EstimatedRecipients: min(count, maxRecipients),
+		MaxRecipients:       maxRecipients,
+	}
+	if !asAdmin && broadcast.EstimatedRecipients > s.setting(SettingBroadcastApprovalThreshold, defaultBroadcastApprovalThreshold) {
+		broadcast.Status = models.BroadcastStatusPendingApproval
+	}
+
+	if err := s.broadcastRepo.Create(ctx, broadcast); err != nil {
+		if errors.Is(err, repositories.ErrBroadcastExists) {
+			return nil, utils.NewBadRequestError("This post has already been broadcast", err)
+		}
+		s.logger.Error("Failed to create broadcast", zap.String("post_id", postID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to create broadcast", err)
+	}
+
+	s.logger.Info("Post broadcast requested",
+		zap.String("broadcast_id", broadcast.ID),
+		zap.String("post_id", postID),
+		zap.Float64("radius_km", radiusKm),
+		zap.Int("estimated_recipients", broadcast.EstimatedRecipients),
+		zap.String("status", broadcast.Status))
+	return broadcast, nil
+}
+
+// Status returns the post's latest broadcast for its author. Nil when the
+// post has never been broadcast.
+func (s *PostBroadcastService) Status(ctx context.Context, postID, userID string) (*models.PostBroadcast, error) {
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil || post == nil {
+		return nil, utils.NewNotFoundError("Post not found", err)
+	}
+	if !s.access.CanEditPost(ctx, post, userID) {
+		return nil, utils.NewForbiddenError("Only the author can see this post's broadcast", nil)
+	}
+	broadcast, err := s.broadcastRepo.GetLatestByPost(ctx, postID)
+	if errors.Is(err, repositories.ErrBroadcastNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load broadcast", err)
+	}
+	return broadcast, nil
+}
+
+// List returns the admin queue (status filter optional).
+func (s *PostBroadcastService) List(ctx context.Context, status *string, limit, offset int) ([]*models.PostBroadcastListItem, int, error) {
+	items, total, err := s.broadcastRepo.List(ctx, status, limit, offset)
+	if err != nil {
+		return nil, 0, utils.NewInternalError("Failed to list broadcasts", err)
+	}
+	return items, total, nil
+}
+
+// Review approves (queues) or rejects a broadcast waiting for approval.
+func (s *PostBroadcastService) Review(ctx context.Context, broadcastID, reviewerID, action string, reason *string) (*models.PostBroadcast, error) {
+	broadcast, err := s.broadcastRepo.GetByID(ctx, broadcastID)
+	if errors.Is(err, repositories.ErrBroadcastNotFound) {
+		return nil, utils.NewNotFoundError("Broadcast not found", err)
+	}
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load broadcast", err)
+	}
+	if broadcast.Status != models.BroadcastStatusPendingApproval {
+		return nil, utils.NewBadRequestError("Broadcast is not waiting for approval", nil)
+	}
+
+	status := models.BroadcastStatusRejected
+	if action == "approve" {
+		status = models.BroadcastStatusQueued
+	}
+	if err := s.broadcastRepo.Review(ctx, broadcastID, reviewerID, status, reason); err != nil {
+		if errors.Is(err, repositories.ErrBroadcastNotFound) {
+			return nil, utils.NewBadRequestError("Broadcast is not waiting for approval", err)
+		}
+		s.logger.Error("Failed to review broadcast", zap.String("broadcast_id", broadcastID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to review broadcast", err)
+	}
+	return s.broadcastRepo.GetByID(ctx, broadcastID)
+}
+
+// ProcessQueued sends queued broadcasts, oldest first, a batch at a time
+// until there is nothing left or the per-run budget is spent. Progress is
+// saved after every batch, so a failed or interrupted run resumes where it
+// stopped and nobody is notified twice.
+func (s *PostBroadcastService) ProcessQueued(ctx context.Context) error {
+	if s.notificationService == nil {
+		return nil
+	}
+	budget := broadcastBatchesPerRun
+	for budget > 0 {
+		broadcast, err := s.broadcastRepo.NextToSend(ctx)
+		if err != nil {
+			return fmt.Errorf("next broadcast: %w", err)
+		}
+		if broadcast == nil {
+			return nil
+		}
+		used, err := s.send(ctx, broadcast, budget)
+		if err != nil {
+			return err
+		}
+		budget -= used
+	}
+	return nil
+}
+
+// send works through up to budget batches of one broadcast and returns how
+// many it used. It finishes the broadcast when recipients or its cap run out,
+// or when the post is no longer visible.
+func (s *PostBroadcastService) send(ctx context.Context, broadcast *models.PostBroadcast, budget int) (int, error) {
+	post, err := s.postRepo.GetByID(ctx, broadcast.PostID)
+	if err != nil || post == nil || !post.Status || post.UserID == nil {
+		s.logger.Info("Post broadcast stopped: post no longer available", zap.String("broadcast_id", broadcast.ID))
+		return 0, s.finish(ctx, broadcast)
+	}
+	notification := broadcastNotification(post, broadcast)
+
+	used := 0
+	for used < budget {
+		remaining := broadcast.MaxRecipients - broadcast.SentCount
+		if remaining <= 0 {
+			return used, s.finish(ctx, broadcast)
+		}
+		limit := min(broadcastBatchSize, remaining)
+		ids, err := s.broadcastRepo.RecipientsAfter(ctx, broadcast, *post.UserID, broadcast.CursorUserID, limit)
+		if err != nil {
+			return used, fmt.Errorf("broadcast %s recipients: %w", broadcast.ID, err)
+		}
+		used++
+
+		for _, recipientID := range ids {
+			req := *notification
+			req.UserID = recipientID
+			if _, err := s.notificationService.CreateNotification(ctx, &req); err != nil {
+				s.logger.Warn("CreateNotification (broadcast) failed",
+					zap.String("broadcast_id", broadcast.ID), zap.String("recipient_id", recipientID), zap.Error(err))
+			}
+		}
+		if len(ids) > 0 {
+			last := ids[len(ids)-1]
+			if err := s.broadcastRepo.RecordProgress(ctx, broadcast.ID, last, len(ids)); err != nil {
+				return used, fmt.Errorf("broadcast %s progress: %w", broadcast.ID, err)
+			}
+			broadcast.CursorUserID = &last
+			broadcast.SentCount += len(ids)
+		}
+		if len(ids) < limit {
+			return used, s.finish(ctx, broadcast)
+		}
+	}
+	return used, nil
+}
+
+func (s *PostBroadcastService) finish(ctx context.Context, broadcast *models.PostBroadcast) error {
+	if err := s.broadcastRepo.MarkSent(ctx, broadcast.ID); err != nil {
+		return fmt.Errorf("broadcast %s finish: %w", broadcast.ID, err)
+	}
+	s.logger.Info("Post broadcast sent",
+		zap.String("broadcast_id", broadcast.ID),
+		zap.String("post_id", broadcast.PostID),
+		zap.Int("sent", broadcast.SentCount))
+	return nil
+}
+
+// broadcastNotification is the notification every recipient of a broadcast
+// gets, minus UserID: alerts read like the automatic nearby alert, events
+// as an invitation.
+func broadcastNotification(post *models.Post, broadcast *models.PostBroadcast) *models.CreateNotificationRequest {
+	notifType := models.NotificationTypeNearbyEvent
+	var title, msg string
+	if post.Type == models.PostTypeAlert {
+		notifType = models.NotificationTypeSafetyAlert
+		title, msg = safetyAlertText(post)
+	} else {
+		title = "Event nearby"
+		if post.Title != nil && strings.TrimSpace(*post.Title) != "" {
+			title = "Event nearby: " + strings.TrimSpace(*post.Title)
+		}
+		if post.Description != nil {
+			msg = *post.Description
+		}
+	}
+
+	data := map[string]interface{}{
+		"actor_id":     *post.UserID,
+		"post_id":      post.ID,
+		"post_type":    string(post.Type),
+		"broadcast_id": broadcast.ID,
+		"radius_km":    broadcast.RadiusKm,
+	}
+	if post.Urgency != nil {
+		data["urgency"] = string(*post.Urgency)
+	}
+	return &models.CreateNotificationRequest{
+		Type:    notifType,
+		Title:   &title,
+		Message: &msg,
+		Data:    data,
+	}
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newBroadcastPost(postType models.PostType) *models.Post {
+	author := "author-1"
+	return &models.Post{
+		ID:              "post-1",
+		UserID:          &author,
+		Type:            postType,
+		Status:          true,
+		AddressLocation: &pgtype.Point{P: pgtype.Vec2{X: 69.17, Y: 34.52}, Valid: true},
+	}
+}
+
+func TestPostBroadcastService_Request(t *testing.T) {
+	setup := func(post *models.Post) (*PostBroadcastService, *mocks.MockPostBroadcastRepository) {
+		broadcastRepo := new(mocks.MockPostBroadcastRepository)
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, post.ID).Return(post, nil)
+		return NewPostBroadcastService(broadcastRepo, postRepo, nil, zap.NewNop()), broadcastRepo
+	}
+
+	t.Run("small broadcast is queued", func(t *testing.T) {
+		svc, broadcastRepo := setup(newBroadcastPost(models.PostTypeEvent))
+		broadcastRepo.On("CountRecipients", mock.Anything, 34.52, 69.17, 10.0, 0.0, "author-1").Return(250, nil)
+		broadcastRepo.On("Create", mock.Anything, mock.MatchedBy(func(b *models.PostBroadcast) bool {
+			return b.Status == models.BroadcastStatusQueued && b.EstimatedRecipients == 250 &&
+				b.MaxRecipients == defaultBroadcastMaxRecipients
+		})).Return(nil)
+
+		broadcast, err := svc.Request(context.Background(), "post-1", "author-1", false, 10)
+		require.NoError(t, err)
+		assert.Equal(t, models.BroadcastStatusQueued, broadcast.Status)
+		broadcastRepo.AssertExpectations(t)
+	})
+
+	t.Run("above the threshold waits for approval", func(t *testing.T) {
+		svc, broadcastRepo := setup(newBroadcastPost(models.PostTypeEvent))
+		broadcastRepo.On("CountRecipients", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(defaultBroadcastApprovalThreshold+1, nil)
+		broadcastRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		broadcast, err := svc.Request(context.Background(), "post-1", "author-1", false, 10)
+		require.NoError(t, err)
+		assert.Equal(t, models.BroadcastStatusPendingApproval, broadcast.Status)
+	})
+
+	t.Run("admin broadcasts skip approval", func(t *testing.T) {
+		svc, broadcastRepo := setup(newBroadcastPost(models.PostTypeEvent))
+		broadcastRepo.On("CountRecipients", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(defaultBroadcastMaxRecipients*2, nil)
+		broadcastRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		broadcast, err := svc.Request(context.Background(), "post-1", "admin-1", true, 10)
+		require.NoError(t, err)
+		assert.Equal(t, models.BroadcastStatusQueued, broadcast.Status)
+		assert.Equal(t, defaultBroadcastMaxRecipients, broadcast.EstimatedRecipients)
+		assert.Equal(t, "admin-1", broadcast.RequestedBy)
+	})
+
+	t.Run("alerts skip their automatic radius", func(t *testing.T) {
+		post := newBroadcastPost(models.PostTypeAlert)
+		urgency := models.AlertUrgencyHigh
+		post.Urgency = &urgency
+		svc, broadcastRepo := setup(post)
+		broadcastRepo.On("CountRecipients", mock.Anything, mock.Anything, mock.Anything, 30.0, 10.0, "author-1").Return(5, nil)
+		broadcastRepo.On("Create", mock.Anything, mock.MatchedBy(func(b *models.PostBroadcast) bool {
+			return b.ExcludeRadiusKm == 10
+		})).Return(nil)
+
+		_, err := svc.Request(context.Background(), "post-1", "author-1", false, 30)
+		require.NoError(t, err)
+
+		_, err = svc.Request(context.Background(), "post-1", "author-1", false, 10)
+		require.Error(t, err)
+		assert.Equal(t, 400, err.(*utils.AppError).Code)
+	})
+
+	t.Run("rejected requests", func(t *testing.T) {
+		noLocation := newBroadcastPost(models.PostTypeEvent)
+		noLocation.AddressLocation = nil
+		tests := []struct {
+			name   string
+			post   *models.Post
+			userID string
+			radius float64
+			code   int
+		}{
+			{"not the author", newBroadcastPost(models.PostTypeEvent), "someone-else", 10, 403},
+			{"wrong post type", newBroadcastPost(models.PostTypeSell), "author-1", 10, 400},
+			{"no location", noLocation, "author-1", 10, 400},
+			{"radius over the cap", newBroadcastPost(models.PostTypeEvent), "author-1", defaultBroadcastMaxRadiusKm + 1, 400},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				svc, broadcastRepo := setup(tt.post)
+				_, err := svc.Request(context.Background(), "post-1", tt.userID, false, tt.radius)
+				require.Error(t, err)
+				assert.Equal(t, tt.code, err.(*utils.AppError).Code)
+				broadcastRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			})
+		}
+	})
+
+	t.Run("post already broadcast", func(t *testing.T) {
+		svc, broadcastRepo := setup(newBroadcastPost(models.PostTypeEvent))
+		broadcastRepo.On("CountRecipients", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1, nil)
+		broadcastRepo.On("Create", mock.Anything, mock.Anything).Return(repositories.ErrBroadcastExists)
+
+		_, err := svc.Request(context.Background(), "post-1", "author-1", false, 10)
+		require.Error(t, err)
+		assert.Equal(t, 400, err.(*utils.AppError).Code)
+	})
+}
+
+func TestPostBroadcastService_Review(t *testing.T) {
+	t.Run("approve queues the broadcast", func(t *testing.T) {
+		broadcastRepo := new(mocks.MockPostBroadcastRepository)
+		svc := NewPostBroadcastService(broadcastRepo, new(mocks.MockPostRepository), nil, zap.NewNop())
+		broadcastRepo.On("GetByID", mock.Anything, "b-1").
+			Return(&models.PostBroadcast{ID: "b-1", Status: models.BroadcastStatusPendingApproval}, nil).Once()
+		broadcastRepo.On("Review", mock.Anything, "b-1", "admin-1", models.BroadcastStatusQueued, (*string)(nil)).Return(nil)
+		broadcastRepo.On("GetByID", mock.Anything, "b-1").
+			Return(&models.PostBroadcast{ID: "b-1", Status: models.BroadcastStatusQueued}, nil).Once()
+
+		result, err := svc.Review(context.Background(), "b-1", "admin-1", "approve", nil)
+		require.NoError(t, err)
+		assert.Equal(t, models.BroadcastStatusQueued, result.Status)
+		broadcastRepo.AssertExpectations(t)
+	})
+
+	t.Run("only pending broadcasts can be reviewed", func(t *testing.T) {
+		broadcastRepo := new(mocks.MockPostBroadcastRepository)
+		svc := NewPostBroadcastService(broadcastRepo, new(mocks.MockPostRepository), nil, zap.NewNop())
+		broadcastRepo.On("GetByID", mock.Anything, "b-1").
+			Return(&models.PostBroadcast{ID: "b-1", Status: models.BroadcastStatusSending}, nil)
+
+		_, err := svc.Review(context.Background(), "b-1", "admin-1", "reject", nil)
+		require.Error(t, err)
+		broadcastRepo.AssertNotCalled(t, "Review", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestPostBroadcastService_ProcessQueued(t *testing.T) {
+	setup := func() (*PostBroadcastService, *mocks.MockPostBroadcastRepository, *mocks.MockNotificationRepository) {
+		broadcastRepo := new(mocks.MockPostBroadcastRepository)
+		postRepo := new(mocks.MockPostRepository)
+		notifRepo := new(mocks.MockNotificationRepository)
+		settingsRepo := new(mocks.MockNotificationSettingsRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(newBroadcastPost(models.PostTypeEvent), nil)
+		notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
+		settingsRepo.On("GetByProfileID", mock.Anything, mock.Anything).Return([]*models.NotificationSetting{}, nil)
+		notifications := NewNotificationService(notifRepo, settingsRepo, nil, nil, nil, nil, zap.NewNop())
+		return NewPostBroadcastService(broadcastRepo, postRepo, notifications, zap.NewNop()), broadcastRepo, notifRepo
+	}
+
+	t.Run("resumes from the cursor and finishes on a short batch", func(t *testing.T) {
+		svc, broadcastRepo, notifRepo := setup()
+		cursor := "user-100"
+		broadcast := &models.PostBroadcast{
+			ID: "b-1", PostID: "post-1", RadiusKm: 10, Status: models.BroadcastStatusSending,
+			MaxRecipients: 1000, SentCount: 100, CursorUserID: &cursor,
+		}
+		broadcastRepo.On("NextToSend", mock.Anything).Return(broadcast, nil).Once()
+		broadcastRepo.On("RecipientsAfter", mock.Anything, broadcast, "author-1", &cursor, broadcastBatchSize).
+			Return([]string{"user-101", "user-102"}, nil)
+		broadcastRepo.On("RecordProgress", mock.Anything, "b-1", "user-102", 2).Return(nil)
+		broadcastRepo.On("MarkSent", mock.Anything, "b-1").Return(nil)
+		broadcastRepo.On("NextToSend", mock.Anything).Return(nil, nil).Once()
+
+		require.NoError(t, svc.ProcessQueued(context.Background()))
+		assert.Equal(t, 102, broadcast.SentCount)
+		notifRepo.AssertNumberOfCalls(t, "Create", 2)
+		notifRepo.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+			return n.UserID == "user-101" && n.Type == models.NotificationTypeNearbyEvent
+		}))
+		broadcastRepo.AssertExpectations(t)
+	})
+
+	t.Run("stops at the recipient cap", func(t *testing.T) {
+		svc, broadcastRepo, _ := setup()
+		broadcast := &models.PostBroadcast{
+			ID: "b-1", PostID: "post-1", RadiusKm: 10, Status: models.BroadcastStatusQueued, MaxRecipients: 1,
+		}
+		broadcastRepo.On("NextToSend", mock.Anything).Return(broadcast, nil).Once()
+		broadcastRepo.On("RecipientsAfter", mock.Anything, broadcast, "author-1", (*string)(nil), 1).
+			Return([]string{"user-1"}, nil)
+		broadcastRepo.On("RecordProgress", mock.Anything, "b-1", "user-1", 1).Return(nil)
+		broadcastRepo.On("MarkSent", mock.Anything, "b-1").Return(nil)
+		broadcastRepo.On("NextToSend", mock.Anything).Return(nil, nil).Once()
+
+		require.NoError(t, svc.ProcessQueued(context.Background()))
+		broadcastRepo.AssertExpectations(t)
+	})
+
+	t.Run("recipient lookup error keeps the broadcast for the next run", func(t *testing.T) {
+		svc, broadcastRepo, _ := setup()
+		broadcast := &models.PostBroadcast{ID: "b-1", PostID: "post-1", Status: models.BroadcastStatusQueued, MaxRecipients: 10}
+		broadcastRepo.On("NextToSend", mock.Anything).Return(broadcast, nil)
+		broadcastRepo.On("RecipientsAfter", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, errors.New("db down"))
+
+		require.Error(t, svc.ProcessQueued(context.Background()))
+		broadcastRepo.AssertNotCalled(t, "MarkSent", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/runtimeconfig"
+)
+
+// Runtime setting keys for post expiry. Admins change them through
+// /admin/system/settings; a new value applies to posts created (or SELL
+// listings renewed or relisted) after the change.
+const (
+	SettingPostExpirySell          = "post_expiry.sell"
+	SettingPostExpiryEventAfterEnd = "post_expiry.event_after_end"
+	SettingPostExpiryAlert         = "post_expiry.alert"
+)
+
+// Post expiry defaults, used until runtime settings say otherwise.
+const (
+	defaultSellExpiry          = 30 * 24 * time.Hour
+	defaultEventExpiryAfterEnd = 24 * time.Hour
+	defaultAlertExpiry         = 48 * time.Hour
+)
+
+// PostExpiryPolicy decides when a post stops being live. SELL listings
+// expire a fixed time after they go live and their owner is asked to renew;
+// EVENT posts are archived a grace period after they end; ALERT posts are
+// archived a fixed time after they are raised. Other types never expire.
+//
+// A nil *PostExpiryPolicy applies the defaults, so services work unwired.
+type PostExpiryPolicy struct {
+	settings *runtimeconfig.Store
+}
+
+// NewPostExpiryPolicy registers the expiry settings with store.
+func NewPostExpiryPolicy(store *runtimeconfig.Store) *PostExpiryPolicy {
+	store.Register(runtimeconfig.Setting{
+		Key:         SettingPostExpirySell,
+		Kind:        runtimeconfig.KindDuration,
+		Default:     defaultSellExpiry.String(),
+		Description: "How long a SELL listing stays live before it expires and the seller is asked to renew",
+	})
+	store.Register(runtimeconfig.Setting{
+		Key:         SettingPostExpiryEventAfterEnd,
+		Kind:        runtimeconfig.KindDuration,
+		Default:     defaultEventExpiryAfterEnd.String(),
+		Description: "How long after an EVENT ends it is archived",
+	})
+	store.Register(runtimeconfig.Setting{
+		Key:         SettingPostExpiryAlert,
+		Kind:        runtimeconfig.KindDuration,
+		Default:     defaultAlertExpiry.String(),
+		Description: "How long an ALERT stays live before it is archived",
+	})
+	return &PostExpiryPolicy{settings: store}
+}
+
+func (p *PostExpiryPolicy) duration(key string, fallback time.Duration) time.Duration {
+	if p == nil || p.settings == nil {
+		return fallback
+	}
+	return p.settings.Duration(key, fallback)
+}
+
+// SellTTL is how long a SELL listing is live from now.
+func (p *PostExpiryPolicy) SellTTL() time.Duration {
+	return p.duration(SettingPostExpirySell, defaultSellExpiry)
+}
+
+// ExpiresAt returns when post, live from now, expires; nil when its type
+// doesn't expire or an event has no date.
+func (p *PostExpiryPolicy) ExpiresAt(post *models.Post, now time.Time) *time.Time {
+	var at time.Time
+	switch post.Type {
+	case models.PostTypeSell:
+		at = now.Add(p.SellTTL())
+	case models.PostTypeAlert:
+		at = now.Add(p.duration(SettingPostExpiryAlert, defaultAlertExpiry))
+	case models.PostTypeEvent:
+		end := eventEnd(post)
+		if end == nil {
+			return nil
+		}
+		at = end.Add(p.duration(SettingPostExpiryEventAfterEnd, defaultEventExpiryAfterEnd))
+	default:
+		return nil
+	}
+	return &at
+}
+
+// eventEnd is when an event finishes: end_date + end_time, falling back to
+// the start date and to the end of the day when either is missing. Dates
+// are stored without a zone and read as UTC, like the reminder job does.
+func eventEnd(post *models.Post) *time.Time {
+	day := post.EndDate
+	if day == nil {
+		day = post.StartDate
+	}
+	if day == nil {
+		return nil
+	}
+	end := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	if post.EndTime != nil {
+		end = end.Add(time.Duration(post.EndTime.Hour())*time.Hour +
+			time.Duration(post.EndTime.Minute())*time.Minute +
+			time.Duration(post.EndTime.Second())*time.Second)
+	} else {
+		end = end.Add(24 * time.Hour)
+	}
+	return &end
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostExpiryPolicy_ExpiresAt(t *testing.T) {
+	var policy *PostExpiryPolicy // unwired: defaults
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	day := func(y int, m time.Month, d int) *time.Time {
+		v := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+		return &v
+	}
+	clock := func(h, m int) *time.Time {
+		v := time.Date(0, 1, 1, h, m, 0, 0, time.UTC)
+		return &v
+	}
+
+	t.Run("sell", func(t *testing.T) {
+		at := policy.ExpiresAt(&models.Post{Type: models.PostTypeSell}, now)
+		require.NotNil(t, at)
+		assert.Equal(t, now.Add(30*24*time.Hour), *at)
+	})
+
+	t.Run("alert", func(t *testing.T) {
+		at := policy.ExpiresAt(&models.Post{Type: models.PostTypeAlert}, now)
+		require.NotNil(t, at)
+		assert.Equal(t, now.Add(48*time.Hour), *at)
+	})
+
+	t.Run("event with end date and time", func(t *testing.T) {
+		post := &models.Post{Type: models.PostTypeEvent, StartDate: day(2026, 3, 12), EndDate: day(2026, 3, 13), EndTime: clock(18, 30)}
+		at := policy.ExpiresAt(post, now)
+		require.NotNil(t, at)
+		assert.Equal(t, time.Date(2026, 3, 14, 18, 30, 0, 0, time.UTC), *at)
+	})
+
+	t.Run("event with only a start date ends with that day", func(t *testing.T) {
+		post := &models.Post{Type: models.PostTypeEvent, StartDate: day(2026, 3, 12)}
+		at := policy.ExpiresAt(post, now)
+		require.NotNil(t, at)
+		assert.Equal(t, time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC), *at)
+	})
+
+	t.Run("undated event and other types never expire", func(t *testing.T) {
+		assert.Nil(t, policy.ExpiresAt(&models.Post{Type: models.PostTypeEvent}, now))
+		assert.Nil(t, policy.ExpiresAt(&models.Post{Type: models.PostTypeFeed}, now))
+		assert.Nil(t, policy.ExpiresAt(&models.Post{Type: models.PostTypeHelp}, now))
+	})
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectPostLang(t *testing.T) {
+	post := &models.Post{Title: testutil.StringPtr("Sale"), Description: testutil.StringPtr("ګل او ښکلي بوټي")}
+	require.NotNil(t, detectPostLang(post))
+	assert.Equal(t, "ps", *detectPostLang(post))
+
+	assert.Nil(t, detectPostLang(&models.Post{Description: testutil.StringPtr("👍")}))
+}
+
+func TestPostService_GetFeedLanguages(t *testing.T) {
+	viewer := "viewer-1"
+	hasLanguages := func(want ...string) interface{} {
+		return mock.MatchedBy(func(f *models.FeedFilter) bool {
+			return len(want) == len(f.Languages) && (len(want) == 0 || assert.ObjectsAreEqual(want, f.Languages))
+		})
+	}
+
+	t.Run("applies the viewer's content languages", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetContentLanguages", mock.Anything, viewer).Return([]string{"fa", "ps"}, nil)
+		postRepo.On("CountFeed", mock.Anything, hasLanguages("fa", "ps")).Return(int64(0), nil)
+		postRepo.On("GetFeed", mock.Anything, hasLanguages("fa", "ps")).Return([]*models.Post{}, nil)
+
+		_, _, err := newTestPostService(postRepo, userRepo).GetFeed(context.Background(), &models.FeedFilter{Limit: 20}, &viewer)
+		require.NoError(t, err)
+		postRepo.AssertExpectations(t)
+	})
+
+	t.Run("explicit lang wins", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		userRepo := new(mocks.MockUserRepository)
+		postRepo.On("CountFeed", mock.Anything, hasLanguages()).Return(int64(0), nil)
+		postRepo.On("GetFeed", mock.Anything, hasLanguages()).Return([]*models.Post{}, nil)
+
+		filter := &models.FeedFilter{Limit: 20, Languages: []string{}}
+		_, _, err := newTestPostService(postRepo, userRepo).GetFeed(context.Background(), filter, &viewer)
+		require.NoError(t, err)
+		userRepo.AssertNotCalled(t, "GetContentLanguages", mock.Anything, mock.Anything)
+	})
+
+	t.Run("profile feeds ignore the preference", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		userRepo := new(mocks.MockUserRepository)
+		postRepo.On("CountFeed", mock.Anything, mock.Anything).Return(int64(0), nil)
+		postRepo.On("GetFeed", mock.Anything, mock.Anything).Return([]*models.Post{}, nil)
+
+		author := "author-1"
+		_, _, err := newTestPostService(postRepo, userRepo).GetFeed(context.Background(), &models.FeedFilter{Limit: 20, UserID: &author}, &viewer)
+		require.NoError(t, err)
+		userRepo.AssertNotCalled(t, "GetContentLanguages", mock.Anything, mock.Anything)
+	})
+}
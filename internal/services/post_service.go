@@ -2,10 +2,12 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"hash/fnv"
-	"math"
 	"os"
 	"sort"
 	"strings"
@@ -17,7 +19,9 @@ import (
 	"github.com/hamsaya/backend/internal/repositories"
 	"github.com/hamsaya/backend/internal/utils"
 	"github.com/hamsaya/backend/pkg/bgtasks"
-	"github.com/hamsaya/backend/pkg/observability"
+	"github.com/hamsaya/backend/pkg/events"
+	"github.com/hamsaya/backend/pkg/geocoding"
+	"github.com/hamsaya/backend/pkg/langdetect"
 	"github.com/hamsaya/backend/pkg/storage"
 	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
@@ -42,8 +46,31 @@ type PostService struct {
 	notificationService *NotificationService
 	fanoutService       *FanoutService
 	fanoutRepo          repositories.FanoutRepository
+	feed                repositories.FeedProvider
 	dailyLimitService   *DailyLimitService
 	automodService      *AutomodService
+	creationThrottle    *CreationThrottle
+	productService      *BusinessProductService
+	branchService       *BusinessBranchService
+	linkPreviews        *LinkPreviewService
+	groupRepo           repositories.GroupRepository
+	authorizer          *PostAuthorizer
+	access              *AccessControl
+	pledgeService       *HelpPledgeService
+	eventHostService    *EventHostService
+	geocoder            geocoding.ReverseGeocoder
+	bookmarkCollections *BookmarkCollectionService
+	privacy             *ProfilePrivacy
+	expiry              *PostExpiryPolicy
+	mediaScanner        *MediaScanner
+	viewCounter         *PostViewCounter
+	bookmarkCounter     *PostBookmarkCounter
+	uploadSessions      *UploadSessionService
+	mutedTerms          *MutedTermService
+	limits              *models.ContentLimits
+	events              *events.Bus
+	shareLinkBaseURL    string
+	sharePostURL        string
 	storageBucketName   string
 	logger              *zap.Logger
 }
@@ -76,13 +103,192 @@ func NewPostService(
 		notificationService: notificationService,
 		fanoutService:       fanoutService,
 		fanoutRepo:          fanoutRepo,
+		feed:                repositories.NewPostgresFeedProvider(fanoutRepo),
 		dailyLimitService:   dailyLimitService,
 		automodService:      automodService,
+		authorizer:          NewPostAuthorizer(relationshipsRepo, businessRepo),
+		access:              NewAccessControl(businessRepo),
 		storageBucketName:   storageBucketName,
 		logger:              logger,
 	}
 }
 
+// WithFeedProvider swaps the backend home timelines are read from. It must
+// match the one the FanoutService pushes to.
+func (s *PostService) WithFeedProvider(feed repositories.FeedProvider) *PostService {
+	s.feed = feed
+	return s
+}
+
+// WithEvents publishes post domain events (PostCreated, PostLiked) on bus.
+func (s *PostService) WithEvents(bus *events.Bus) *PostService {
+	s.events = bus
+	return s
+}
+
+// WithCreationThrottle enables the per-user short-window post creation limit.
+func (s *PostService) WithCreationThrottle(t *CreationThrottle) *PostService {
+	s.creationThrottle = t
+	return s
+}
+
+// WithProducts enables attaching catalog products to posts and the product
+// card in post responses.
+func (s *PostService) WithProducts(productService *BusinessProductService) *PostService {
+	s.productService = productService
+	return s
+}
+
+// WithBranches enables choosing a business branch for business posts and
+// the branch in post responses.
+func (s *PostService) WithBranches(branchService *BusinessBranchService) *PostService {
+	s.branchService = branchService
+	return s
+}
+
+// WithLinkPreviews enables link previews in post responses and strips a
+// post's preview when an edit removes its link. Fetching is done by the
+// service's event subscribers.
+func (s *PostService) WithLinkPreviews(linkPreviews *LinkPreviewService) *PostService {
+	s.linkPreviews = linkPreviews
+	return s
+}
+
+// WithGroups enables posting into groups and the membership checks on
+// group posts.
+func (s *PostService) WithGroups(groupRepo repositories.GroupRepository) *PostService {
+	s.groupRepo = groupRepo
+	s.authorizer.WithGroups(groupRepo)
+	return s
+}
+
+// WithPledges enables the pledge summary on HELP posts and the pledger
+// heads-up when a help request is edited.
+func (s *PostService) WithPledges(pledgeService *HelpPledgeService) *PostService {
+	s.pledgeService = pledgeService
+	return s
+}
+
+// WithEventHosts lists co-hosts on EVENT posts and lets accepted co-hosts
+// edit the event.
+func (s *PostService) WithEventHosts(eventHostService *EventHostService) *PostService {
+	s.eventHostService = eventHostService
+	return s
+}
+
+// WithGeocoder enables filling in country / province / district /
+// neighborhood in the background for posts that only carry coordinates.
+func (s *PostService) WithGeocoder(geocoder geocoding.ReverseGeocoder) *PostService {
+	s.geocoder = geocoder
+	return s
+}
+
+// WithBookmarkCollections enables the collection_id filter on the
+// bookmarks list.
+func (s *PostService) WithBookmarkCollections(collectionService *BookmarkCollectionService) *PostService {
+	s.bookmarkCollections = collectionService
+	return s
+}
+
+// WithPrivacy hides post authors' location from viewers their privacy
+// settings exclude.
+func (s *PostService) WithPrivacy(privacy *ProfilePrivacy) *PostService {
+	s.privacy = privacy
+	return s
+}
+
+// WithExpiryPolicy makes post expiry follow the admin-tunable policy
+// instead of the defaults.
+func (s *PostService) WithExpiryPolicy(expiry *PostExpiryPolicy) *PostService {
+	s.expiry = expiry
+	return s
+}
+
+// WithMutedTerms hides posts containing the viewer's muted keywords and
+// hashtags from the general feeds.
+func (s *PostService) WithMutedTerms(m *MutedTermService) *PostService {
+	s.mutedTerms = m
+	return s
+}
+
+// WithMediaScanner serves blurred copies of attachments the image
+// classifier flagged, until an admin reviews them.
+func (s *PostService) WithMediaScanner(m *MediaScanner) *PostService {
+	s.mediaScanner = m
+	return s
+}
+
+// WithViewCounter enables per-day view counting and the owner-only
+// view_count on post responses.
+func (s *PostService) WithViewCounter(c *PostViewCounter) *PostService {
+	s.viewCounter = c
+	return s
+}
+
+// WithBookmarkCounter enables the owner-only total_bookmarks on post
+// responses and the seller bookmark-milestone notifications.
+func (s *PostService) WithBookmarkCounter(c *PostBookmarkCounter) *PostService {
+	s.bookmarkCounter = c
+	return s
+}
+
+// WithUploadSessions has CreatePost claim the upload session its
+// attachments were uploaded in.
+func (s *PostService) WithUploadSessions(u *UploadSessionService) *PostService {
+	s.uploadSessions = u
+	return s
+}
+
+// WithContentLimits sets the length, attachment and poll limits posts are
+// held to (CONTENT_*). Without it the built-in defaults apply.
+func (s *PostService) WithContentLimits(limits models.ContentLimits) *PostService {
+	s.limits = &limits
+	return s
+}
+
+func (s *PostService) contentLimits() models.ContentLimits {
+	if s.limits == nil {
+		return defaultContentLimits
+	}
+	return *s.limits
+}
+
+// WithShareLinks sets where external share links point: linkBaseURL is
+// the short-link prefix (code appended) and postURL the landing page the
+// short link redirects to (post id appended). Empty values fall back to
+// the public website.
+func (s *PostService) WithShareLinks(linkBaseURL, postURL string) *PostService {
+	s.shareLinkBaseURL = linkBaseURL
+	s.sharePostURL = postURL
+	return s
+}
+
+// backfillPostAddress queues a reverse-geocode for a post whose location
+// arrived without a province.
+func (s *PostService) backfillPostAddress(post *models.Post) {
+	if s.geocoder == nil || post.AddressLocation == nil || !post.AddressLocation.Valid ||
+		!needsAddressBackfill(post.Province) {
+		return
+	}
+	postID := post.ID
+	lat, lng := post.AddressLocation.P.Y, post.AddressLocation.P.X
+	backfillAddress(s.geocoder, s.logger, lat, lng, func(ctx context.Context, addr *models.ResolvedAddress) error {
+		return s.postRepo.FillAddress(ctx, postID, lat, lng, addr)
+	})
+}
+
+// Authorizer returns the post visibility authorizer, for other services that
+// read posts.
+func (s *PostService) Authorizer() *PostAuthorizer {
+	return s.authorizer
+}
+
+// Access returns the access control that decides who may change posts, for
+// other services that act on them.
+func (s *PostService) Access() *AccessControl {
+	return s.access
+}
+
 // GetDailyLimitService exposes the limit service so the handler can render
 // a 429 with the proper payload + power the GET /posts/daily-limits endpoint.
 func (s *PostService) GetDailyLimitService() *DailyLimitService {
@@ -154,7 +360,7 @@ func (s *PostService) CreatePost(ctx context.Context, userID string, req *models
 		if berr != nil {
 			return nil, utils.NewNotFoundError("Business not found", berr)
 		}
-		if business.UserID != userID {
+		if !s.access.CanManageBusiness(business, userID) {
 			if user, uerr := s.userRepo.GetByID(ctx, userID); uerr != nil ||
 				user == nil || user.Role != models.RoleAdmin {
 				return nil, utils.NewForbiddenError("You don't own this business", nil)
@@ -162,6 +368,32 @@ func (s *PostService) CreatePost(ctx context.Context, userID string, req *models
 		}
 	}
 
+	if req.BusinessProductID != nil && *req.BusinessProductID != "" {
+		if s.productService == nil {
+			return nil, utils.NewBadRequestError("Product attachments are not available", nil)
+		}
+		if _, err := s.productService.ResolveAttachment(ctx, *req.BusinessProductID, req.BusinessID); err != nil {
+			return nil, err
+		}
+	}
+
+	var branch *models.BusinessBranch
+	if req.BranchID != nil && *req.BranchID != "" {
+		if s.branchService == nil {
+			return nil, utils.NewBadRequestError("Business branches are not available", nil)
+		}
+		var err error
+		if branch, err = s.branchService.ResolveForPost(ctx, *req.BranchID, req.BusinessID); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.GroupID != nil && *req.GroupID != "" {
+		if err := s.requireGroupPoster(ctx, *req.GroupID, userID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Automod scan — runs before any DB writes so a 'block' rule rejects
 	// the request without bumping daily-limit counters or creating
 	// half-baked rows. 'flag' and 'shadow' continue creation; flagging
@@ -189,6 +421,27 @@ func (s *PostService) CreatePost(ctx context.Context, userID string, req *models
 	}
 	_ = automodMatch // referenced after the create completes; keep linter quiet here
 
+	// Attachments must come from the request's upload session, which the
+	// post claims once created. Relisted copies reuse the original's
+	// stored images, so they have no session.
+	uploadURLs := attachmentURLs(req.Attachments)
+	hasUploadSession := req.UploadSessionID != nil && *req.UploadSessionID != ""
+	if hasUploadSession {
+		if err := s.uploadSessions.ValidateAttachments(ctx, *req.UploadSessionID, userID, uploadURLs); err != nil {
+			return nil, err
+		}
+	} else if requireUploadSession && len(uploadURLs) > 0 &&
+		(req.ClientToken == nil || !strings.HasPrefix(*req.ClientToken, models.RelistClientTokenPrefix)) {
+		return nil, utils.NewBadRequestError("upload_session_id is required for posts with attachments", nil)
+	}
+	if err := validateAttachmentText(req.Attachments); err != nil {
+		return nil, err
+	}
+
+	if err := s.creationThrottle.Allow(ctx, CreationPost, userID); err != nil {
+		return nil, err
+	}
+
 	// Daily-limit gate (admin role bypassed inside the service). Counter is
 	// pre-incremented; if downstream creation fails we Refund() to restore
 	// the slot. Only checked when the limit service is wired (tests using
@@ -235,6 +488,11 @@ func (s *PostService) CreatePost(ctx context.Context, userID string, req *models
 		CreatedAt:   now,
 		UpdatedAt:   now,
 		ClientToken: req.ClientToken,
+
+		BusinessProductID: req.BusinessProductID,
+	}
+	if branch != nil {
+		post.BranchID = &branch.ID
 	}
 
 	// Set visibility if provided
@@ -242,6 +500,12 @@ func (s *PostService) CreatePost(ctx context.Context, userID string, req *models
 		post.Visibility = req.Visibility
 	}
 
+	// Group posts are only visible through the group, whatever was requested.
+	if req.GroupID != nil && *req.GroupID != "" {
+		post.GroupID = req.GroupID
+		post.Visibility = models.VisibilityGroup
+	}
+
 	// Handle sell-specific fields
 	if req.Type == models.PostTypeSell {
 		post.Currency = req.Currency
@@ -253,10 +517,6 @@ func (s *PostService) CreatePost(ctx context.Context, userID string, req *models
 		post.CategoryID = req.CategoryID
 		post.CountryCode = req.CountryCode
 		post.ContactNo = req.ContactNo
-
-		// Auto-expire SELL posts after 30 days
-		expiry := now.AddDate(0, 1, 0) // 1 month from creation
-		post.ExpiredAt = &expiry
 	}
 
 	// Handle event-specific fields
@@ -269,6 +529,18 @@ func (s *PostService) CreatePost(ctx context.Context, userID string, req *models
 		post.EventState = &eventState
 	}
 
+	switch req.Type {
+	case models.PostTypeLostFound:
+		post.LostFoundKind = req.LostFoundKind
+		post.ItemDescription = req.ItemDescription
+		post.LastSeenPlace = req.LastSeenPlace
+		post.LastSeenAt = req.LastSeenAt
+	case models.PostTypeAlert:
+		post.Urgency = req.Urgency
+	}
+	post.ExpiredAt = s.expiry.ExpiresAt(post, now)
+	post.Lang = detectPostLang(post)
+
 	// Handle location (top-level or nested from app) — must run before Create so DB has address_location/is_location
 	lat, lon := req.Latitude, req.Longitude
 	if (lat == nil || lon == nil) && req.Location != nil {
@@ -288,6 +560,17 @@ func (s *PostService) CreatePost(ctx context.Context, userID string, req *models
 		} else {
 			post.IsLocation = true
 		}
+	} else if branch != nil && branch.HasLocation() {
+		// A business post from a branch is placed at the branch.
+		post.AddressLocation = &pgtype.Point{
+			P:     pgtype.Vec2{X: *branch.Longitude, Y: *branch.Latitude},
+			Valid: true,
+		}
+		post.Country = branch.Country
+		post.Province = branch.Province
+		post.District = branch.District
+		post.Neighborhood = branch.Neighborhood
+		post.IsLocation = req.IsLocation == nil || *req.IsLocation
 	} else if req.IsLocation != nil {
 		post.IsLocation = *req.IsLocation
 	}
@@ -337,6 +620,10 @@ func (s *PostService) CreatePost(ctx context.Context, userID string, req *models
 				CreatedAt: now,
 				UpdatedAt: now,
 			}
+			if req.Poll != nil {
+				poll.ResultsVisibility = req.Poll.ResultsVisibility
+				poll.ClosesAt = req.Poll.ClosesAt
+			}
 
 			if err := s.pollRepo.Create(ctx, poll); err != nil {
 				s.logger.Error("Failed to create poll",
@@ -382,12 +669,11 @@ func (s *PostService) CreatePost(ctx context.Context, userID string, req *models
 		}
 	}
 
-	// Create attachments if provided (full Photo or URL-only).
-	// Stagger CreatedAt by index so attachments read back in upload order:
-	// reads use ORDER BY created_at ASC, and a shared timestamp would tie and
-	// resolve arbitrarily — dropping the user-chosen cover (index 0) from the
-	// front, which the SELL card relies on as the cover image.
+	// Create attachments if provided (full Photo or URL-only), positioned in
+	// request order so index 0 stays the user-chosen cover the SELL card
+	// relies on. CreatedAt is still staggered by index as a tiebreaker.
 	if len(req.Attachments) > 0 {
+		position := 0
 		for i, raw := range req.Attachments {
 			photo, err := models.ParseAttachmentPhoto(raw)
 			if err != nil {
@@ -397,14 +683,19 @@ func (s *PostService) CreatePost(ctx context.Context, userID string, req *models
 			if photo.URL == "" {
 				continue
 			}
+			photo.AltText = strings.TrimSpace(photo.AltText)
+			caption, _ := models.ParseAttachmentCaption(raw)
 			attachAt := now.Add(time.Duration(i) * time.Millisecond)
 			attachment := &models.Attachment{
 				ID:        uuid.New().String(),
 				PostID:    postID,
 				Photo:     photo,
+				Position:  position,
+				Caption:   caption,
 				CreatedAt: attachAt,
 				UpdatedAt: attachAt,
 			}
+			position++
 
 			if err := s.postRepo.CreateAttachment(ctx, attachment); err != nil {
 				s.logger.Error("Failed to create attachment",
@@ -415,21 +706,25 @@ func (s *PostService) CreatePost(ctx context.Context, userID string, req *models
 		}
 	}
 
+	if hasUploadSession {
+		s.uploadSessions.Claim(ctx, *req.UploadSessionID, postID, uploadURLs)
+	}
+
 	s.logger.Info("Post created",
 		zap.String("post_id", postID),
 		zap.String("user_id", userID),
 		zap.String("type", string(req.Type)),
 	)
 
-	observability.RecordPostCreated(ctx, string(req.Type))
+	s.backfillPostAddress(post)
 
-	// Notify followers of the new post (user followers or business followers).
-	// Dispatched through bgtasks so the work is awaited on graceful shutdown
-	// instead of leaking when the request context is cancelled.
-	businessID := req.BusinessID
-	bgtasks.Submit(func(taskCtx context.Context) {
-		s.notifyFollowersOfNewPost(taskCtx, postID, userID, businessID)
-	})
+	// Follower / nearby notifications and metrics are event subscribers.
+	s.events.Publish(ctx, PostCreated{Post: post})
+
+	// Group posts stay inside the group: no fan-out.
+	if post.GroupID != nil {
+		return s.GetPost(ctx, postID, &userID)
+	}
 
 	// Fan out post to followers' feeds (skipped for celebrity authors with >10K followers).
 	// SELL posts are explicitly excluded from fan-out: they are commerce, not
@@ -453,11 +748,89 @@ func (s *PostService) GetPost(ctx context.Context, postID string, viewerID *stri
 		s.logger.Warn("Post not found", zap.String("post_id", postID), zap.Error(err))
 		return nil, utils.NewNotFoundError("Post not found", err)
 	}
+	if !s.authorizer.CanView(ctx, post, viewerID) {
+		return nil, utils.NewNotFoundError("Post not found", nil)
+	}
 
 	// Enrich post
 	return s.enrichPost(ctx, post, viewerID)
 }
 
+// PreviewPost shows the author what audience would see for their post: the
+// signed-out view when that audience can read it, 404 otherwise.
+func (s *PostService) PreviewPost(ctx context.Context, postID, userID string, audience PostAudience) (*models.PostResponse, error) {
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, utils.NewNotFoundError("Post not found", err)
+	}
+	if !s.access.CanEditPost(ctx, post, userID) {
+		return nil, utils.NewForbiddenError("You can only preview your own posts", nil)
+	}
+	if !s.authorizer.CanViewAs(ctx, post, audience) {
+		return nil, utils.NewNotFoundError("This audience can't see the post", nil)
+	}
+	return s.enrichPost(ctx, post, nil)
+}
+
+// detectPostLang guesses the language of the post's text, nil when there
+// is too little to tell.
+func detectPostLang(post *models.Post) *string {
+	lang := langdetect.Detect(stringOrEmpty(post.Title), stringOrEmpty(post.Description), stringOrEmpty(post.ItemDescription))
+	if lang == "" {
+		return nil
+	}
+	return &lang
+}
+
+// contentLanguages returns the viewer's content languages, nil for
+// anonymous viewers, no preference or lookup failure.
+func contentLanguages(ctx context.Context, userRepo repositories.UserRepository, logger *zap.Logger, viewerID *string) []string {
+	if viewerID == nil || *viewerID == "" {
+		return nil
+	}
+	languages, err := userRepo.GetContentLanguages(ctx, *viewerID)
+	if err != nil {
+		logger.Warn("Failed to get content languages", zap.String("user_id", *viewerID), zap.Error(err))
+		return nil
+	}
+	return languages
+}
+
+// applyNoticeFields copies the lost & found / alert fields onto the response.
+func applyNoticeFields(response *models.PostResponse, post *models.Post) {
+	switch post.Type {
+	case models.PostTypeLostFound:
+		response.LostFoundKind = post.LostFoundKind
+		response.ItemDescription = post.ItemDescription
+		response.LastSeenPlace = post.LastSeenPlace
+		response.LastSeenAt = post.LastSeenAt
+	case models.PostTypeAlert:
+		response.Urgency = post.Urgency
+	}
+}
+
+// requireGroupPoster checks that userID may post into the group: it must
+// exist and the user must be an ACTIVE member.
+func (s *PostService) requireGroupPoster(ctx context.Context, groupID, userID string) error {
+	if s.groupRepo == nil {
+		return utils.NewBadRequestError("Groups are not available", nil)
+	}
+	if _, err := s.groupRepo.GetByID(ctx, groupID); err != nil {
+		if errors.Is(err, repositories.ErrGroupNotFound) {
+			return utils.NewNotFoundError("Group not found", err)
+		}
+		return utils.NewInternalError("Failed to load group", err)
+	}
+	member, err := s.groupRepo.GetMember(ctx, groupID, userID)
+	if err != nil {
+		return utils.NewInternalError("Failed to load group membership", err)
+	}
+	if member == nil || member.Status != models.GroupMemberActive {
+		return utils.NewForbiddenError("Join the group to post in it", nil)
+	}
+	return nil
+}
+
 // GetPostLikers returns the "liked by" payload: total likes, total views, and
 // the (paginated) list of likers newest-first.
 func (s *PostService) GetPostLikers(ctx context.Context, postID, viewerID string, limit, offset int) (*models.PostLikesResponse, error) {
@@ -482,16 +855,25 @@ func (s *PostService) GetPostLikers(ctx context.Context, postID, viewerID string
 	}, nil
 }
 
-// RecordPostView records that viewerID has seen the post (unique per user).
-func (s *PostService) RecordPostView(ctx context.Context, postID, viewerID string) error {
+// RecordPostView records that viewerID has seen the post: once ever as a
+// unique viewer, and once per day in the view count. Returns the updated
+// counts; they are best-effort, like the totals on the likers sheet.
+func (s *PostService) RecordPostView(ctx context.Context, postID, viewerID string) (*models.PostViewCounts, error) {
 	if viewerID == "" {
-		return nil
+		return &models.PostViewCounts{}, nil
 	}
 	if err := s.postRepo.RecordPostView(ctx, viewerID, postID); err != nil {
 		s.logger.Warn("Failed to record post view", zap.String("post_id", postID), zap.Error(err))
-		return utils.NewInternalError("Failed to record view", err)
+		return nil, utils.NewInternalError("Failed to record view", err)
 	}
-	return nil
+	if err := s.viewCounter.Record(ctx, postID, viewerID); err != nil {
+		s.logger.Warn("Failed to count post view", zap.String("post_id", postID), zap.Error(err))
+	}
+
+	counts := &models.PostViewCounts{}
+	counts.UniqueViewers, _ = s.postRepo.CountPostViews(ctx, postID)
+	counts.ViewCount, _ = s.viewCounter.Count(ctx, postID)
+	return counts, nil
 }
 
 // UpdatePost updates a post
@@ -502,9 +884,20 @@ func (s *PostService) UpdatePost(ctx context.Context, postID, userID string, req
 		return nil, utils.NewNotFoundError("Post not found", err)
 	}
 
-	// Check ownership
-	if post.UserID == nil || *post.UserID != userID {
-		return nil, utils.NewForbiddenError("You don't have permission to update this post", nil)
+	// Check ownership; co-hosts may edit events too.
+	if !s.access.CanEditPost(ctx, post, userID) {
+		if !s.isEventCoHost(ctx, post, userID) {
+			return nil, utils.NewForbiddenError("You don't have permission to update this post", nil)
+		}
+	}
+	if req.Version != nil && *req.Version != post.Version {
+		return nil, s.postVersionConflict(ctx, postID, userID)
+	}
+	if err := validateAttachmentText(req.Attachments); err != nil {
+		return nil, err
+	}
+	if err := s.checkUpdateLimits(ctx, post, req); err != nil {
+		return nil, err
 	}
 	// VIEW_ONLY visibility is only allowed for FEED posts
 	if req.Visibility != nil && *req.Visibility == models.VisibilityViewOnly && post.Type != models.PostTypeFeed {
@@ -549,6 +942,8 @@ func (s *PostService) UpdatePost(ctx context.Context, postID, userID string, req
 	if req.EndTime != nil {
 		post.EndTime = req.EndTime
 	}
+	rescheduled := post.Type == models.PostTypeEvent &&
+		(req.StartDate != nil || req.EndDate != nil || req.EndTime != nil)
 	if req.Currency != nil {
 		post.Currency = req.Currency
 	}
@@ -564,6 +959,24 @@ func (s *PostService) UpdatePost(ctx context.Context, postID, userID string, req
 	if req.CategoryID != nil {
 		post.CategoryID = req.CategoryID
 	}
+	if post.Type == models.PostTypeLostFound {
+		if req.LostFoundKind != nil {
+			post.LostFoundKind = req.LostFoundKind
+		}
+		if req.ItemDescription != nil {
+			post.ItemDescription = req.ItemDescription
+		}
+		if req.LastSeenPlace != nil {
+			post.LastSeenPlace = req.LastSeenPlace
+		}
+		if req.LastSeenAt != nil {
+			post.LastSeenAt = req.LastSeenAt
+		}
+	}
+	// Urgency edits don't re-send the nearby push.
+	if post.Type == models.PostTypeAlert && req.Urgency != nil {
+		post.Urgency = req.Urgency
+	}
 
 	// Location: same logic as create (top-level or nested)
 	lat, lon := req.Latitude, req.Longitude
@@ -594,10 +1007,15 @@ func (s *PostService) UpdatePost(ctx context.Context, postID, userID string, req
 		}
 	}
 
+	post.Lang = detectPostLang(post)
 	post.UpdatedAt = time.Now()
 
 	// Update in database
 	if err := s.postRepo.Update(ctx, post); err != nil {
+		if errors.Is(err, repositories.ErrVersionConflict) {
+			// Saved from another device between our read and write.
+			return nil, s.postVersionConflict(ctx, postID, userID)
+		}
 		s.logger.Error("Failed to update post", zap.String("post_id", postID), zap.Error(err))
 		return nil, utils.NewInternalError("Failed to update post", err)
 	}
@@ -608,6 +1026,22 @@ func (s *PostService) UpdatePost(ctx context.Context, postID, userID string, req
 		s.notifySellSoldToBookmarkers(post)
 	}
 
+	if lat != nil && lon != nil {
+		s.backfillPostAddress(post)
+	}
+
+	// A rescheduled event is archived relative to its new end.
+	if rescheduled {
+		if err := s.postRepo.SetExpiry(ctx, postID, s.expiry.ExpiresAt(post, time.Now())); err != nil {
+			s.logger.Warn("Failed to update event expiry", zap.String("post_id", postID), zap.Error(err))
+		}
+	}
+
+	// Help request edited — pledgers may need to adjust what they offered.
+	if post.Type == models.PostTypeHelp && s.pledgeService != nil {
+		s.pledgeService.NotifyUpdated(post)
+	}
+
 	// ── Attachment changes ──────────────────────────────────────────────
 
 	// Remove requested attachments (scoped to this post for safety).
@@ -624,10 +1058,13 @@ func (s *PostService) UpdatePost(ctx context.Context, postID, userID string, req
 		}
 	}
 
-	// Add new attachments (same parsing as create: accepts Photo objects or bare URL strings).
-	// Stagger CreatedAt by index to preserve upload/cover order (see create path).
+	// Add new attachments (same parsing as create: accepts Photo objects or
+	// bare URL strings) after the existing ones, then apply the requested
+	// order. Positions are renumbered from 0 so deletes leave no gaps.
+	attachmentsChanged := len(req.DeletedAttachments) > 0 || len(req.AttachmentOrder) > 0
 	if len(req.Attachments) > 0 {
 		now := time.Now()
+		position := 1 << 20 // after every existing attachment until renumbered
 		for i, raw := range req.Attachments {
 			photo, err := models.ParseAttachmentPhoto(raw)
 			if err != nil {
@@ -637,11 +1074,15 @@ func (s *PostService) UpdatePost(ctx context.Context, postID, userID string, req
 			if photo.URL == "" {
 				continue
 			}
+			photo.AltText = strings.TrimSpace(photo.AltText)
+			caption, _ := models.ParseAttachmentCaption(raw)
 			attachAt := now.Add(time.Duration(i) * time.Millisecond)
 			attachment := &models.Attachment{
 				ID:        uuid.New().String(),
 				PostID:    postID,
 				Photo:     photo,
+				Position:  position + i,
+				Caption:   caption,
 				CreatedAt: attachAt,
 				UpdatedAt: attachAt,
 			}
@@ -650,7 +1091,34 @@ func (s *PostService) UpdatePost(ctx context.Context, postID, userID string, req
 					zap.String("post_id", postID),
 					zap.Error(err),
 				)
+				continue
 			}
+			attachmentsChanged = true
+		}
+	}
+	if attachmentsChanged {
+		s.renumberAttachments(ctx, postID, req.AttachmentOrder)
+	}
+	for attID, caption := range req.AttachmentCaptions {
+		var c *string
+		if trimmed := strings.TrimSpace(caption); trimmed != "" {
+			c = &trimmed
+		}
+		if err := s.postRepo.SetAttachmentCaption(ctx, postID, attID, c); err != nil {
+			s.logger.Warn("Failed to set attachment caption",
+				zap.String("post_id", postID),
+				zap.String("attachment_id", attID),
+				zap.Error(err),
+			)
+		}
+	}
+	for attID, altText := range req.AttachmentAltTexts {
+		if err := s.postRepo.SetAttachmentAltText(ctx, postID, attID, strings.TrimSpace(altText)); err != nil {
+			s.logger.Warn("Failed to set attachment alt text",
+				zap.String("post_id", postID),
+				zap.String("attachment_id", attID),
+				zap.Error(err),
+			)
 		}
 	}
 
@@ -694,12 +1162,128 @@ func (s *PostService) UpdatePost(ctx context.Context, postID, userID string, req
 		s.logger.Info("Poll options updated", zap.String("post_id", postID), zap.Int("options", len(req.PollOptions)))
 	}
 
+	if req.Title != nil || req.Description != nil {
+		if s.linkPreviews != nil {
+			s.linkPreviews.StripStale(ctx, post)
+		}
+		s.events.Publish(ctx, PostEdited{Post: post})
+	}
+
 	s.logger.Info("Post updated", zap.String("post_id", postID), zap.String("user_id", userID))
 
 	// Return enriched post
 	return s.GetPost(ctx, postID, &userID)
 }
 
+// postVersionConflict builds the 409 for an edit based on a stale version,
+// carrying the post as it is now so the client can merge and retry.
+func (s *PostService) postVersionConflict(ctx context.Context, postID, userID string) error {
+	current, err := s.GetPost(ctx, postID, &userID)
+	if err != nil {
+		return err
+	}
+	return utils.NewVersionConflictError("This post was changed on another device", current)
+}
+
+// ReorderAttachments puts the post's attachments in the given order; the
+// first becomes the cover. attachmentIDs must list every attachment of the
+// post exactly once. Returns the attachments in their new order.
+func (s *PostService) ReorderAttachments(ctx context.Context, postID, userID string, attachmentIDs []string) ([]models.AttachmentResponse, error) {
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, utils.NewNotFoundError("Post not found", err)
+	}
+	if !s.access.CanEditPost(ctx, post, userID) {
+		return nil, utils.NewForbiddenError("You don't have permission to update this post", nil)
+	}
+
+	attachments, err := s.postRepo.GetAttachmentsByPostID(ctx, postID)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load attachments", err)
+	}
+	current := make(map[string]*models.Attachment, len(attachments))
+	for _, att := range attachments {
+		current[att.ID] = att
+	}
+	seen := make(map[string]bool, len(attachmentIDs))
+	for _, id := range attachmentIDs {
+		if current[id] == nil || seen[id] {
+			return nil, utils.NewBadRequestError("attachment_ids must list each of the post's attachments once", nil)
+		}
+		seen[id] = true
+	}
+	if len(seen) != len(current) {
+		return nil, utils.NewBadRequestError("attachment_ids must list each of the post's attachments once", nil)
+	}
+
+	if err := s.postRepo.ReorderAttachments(ctx, postID, attachmentIDs); err != nil {
+		return nil, utils.NewInternalError("Failed to reorder attachments", err)
+	}
+	bucket := s.storageBucketName
+	if bucket == "" {
+		bucket = "hamsaya-uploads"
+	}
+	out := make([]models.AttachmentResponse, 0, len(attachmentIDs))
+	for i, id := range attachmentIDs {
+		att := current[id]
+		photo := att.Photo
+		photo.URL = storage.EnsureBucketInStorageURL(photo.URL, bucket)
+		out = append(out, models.AttachmentResponse{
+			ID:       att.ID,
+			Photo:    photo,
+			Position: i,
+			Caption:  att.Caption,
+		})
+	}
+	return out, nil
+}
+
+// renumberAttachments rewrites the post's attachment positions from 0:
+// IDs in order first, then the rest in their current order. Best effort;
+// a failure leaves the previous order.
+func (s *PostService) renumberAttachments(ctx context.Context, postID string, order []string) {
+	attachments, err := s.postRepo.GetAttachmentsByPostID(ctx, postID)
+	if err != nil {
+		s.logger.Warn("Failed to load attachments to reorder", zap.String("post_id", postID), zap.Error(err))
+		return
+	}
+	remaining := make(map[string]bool, len(attachments))
+	for _, att := range attachments {
+		remaining[att.ID] = true
+	}
+	ids := make([]string, 0, len(attachments))
+	for _, id := range order {
+		if remaining[id] {
+			ids = append(ids, id)
+			delete(remaining, id)
+		}
+	}
+	for _, att := range attachments {
+		if remaining[att.ID] {
+			ids = append(ids, att.ID)
+		}
+	}
+	if err := s.postRepo.ReorderAttachments(ctx, postID, ids); err != nil {
+		s.logger.Warn("Failed to reorder attachments", zap.String("post_id", postID), zap.Error(err))
+	}
+}
+
+// validateAttachmentText rejects a request whose attachment captions or
+// alt texts are too long, before anything is written.
+func validateAttachmentText(raws []json.RawMessage) error {
+	for _, raw := range raws {
+		if _, err := models.ParseAttachmentCaption(raw); err != nil {
+			return utils.NewBadRequestError(err.Error(), nil)
+		}
+		if photo, err := models.ParseAttachmentPhoto(raw); err == nil {
+			if _, err := models.NormalizeAltText(photo.AltText); err != nil {
+				return utils.NewBadRequestError(err.Error(), nil)
+			}
+		}
+	}
+	return nil
+}
+
 // DeletePost soft deletes a post
 func (s *PostService) DeletePost(ctx context.Context, postID, userID string) error {
 	// Get existing post
@@ -709,7 +1293,7 @@ func (s *PostService) DeletePost(ctx context.Context, postID, userID string) err
 	}
 
 	// Check ownership
-	if post.UserID == nil || *post.UserID != userID {
+	if !s.access.CanEditPost(ctx, post, userID) {
 		return utils.NewForbiddenError("You don't have permission to delete this post", nil)
 	}
 
@@ -737,12 +1321,11 @@ func (s *PostService) LikePost(ctx context.Context, userID, postID string) error
 
 	s.logger.Info("Post liked", zap.String("post_id", postID), zap.String("user_id", userID))
 
-	if post.UserID != nil && *post.UserID != userID && s.notificationService != nil {
-		recipient := *post.UserID
-		bgtasks.Submit(func(taskCtx context.Context) {
-			s.sendPostNotification(taskCtx, userID, recipient, postID, models.NotificationTypeLike, "liked your post")
-		})
+	liked := PostLiked{PostID: postID, ActorID: userID}
+	if post.UserID != nil {
+		liked.OwnerID = *post.UserID
 	}
+	s.events.Publish(ctx, liked)
 
 	return nil
 }
@@ -781,6 +1364,7 @@ func (s *PostService) BookmarkPost(ctx context.Context, userID, postID string) e
 		bgtasks.Submit(func(taskCtx context.Context) {
 			s.sendPostNotification(taskCtx, userID, recipient, postID,
 				models.NotificationTypeSellInterested, "saved your listing")
+			s.bookmarkCounter.RecordSave(taskCtx, post, userID)
 		})
 	}
 
@@ -870,6 +1454,15 @@ func (s *PostService) GetFeed(ctx context.Context, filter *models.FeedFilter, vi
 	if viewerID != nil && *viewerID != "" && filter.ViewerID == "" {
 		filter.ViewerID = *viewerID
 	}
+	// Content languages narrow the general feeds only; a profile, business
+	// or group page shows everything unless ?lang asks otherwise.
+	if filter.Languages == nil && filter.UserID == nil && filter.BusinessID == nil && filter.GroupID == nil {
+		filter.Languages = contentLanguages(ctx, s.userRepo, s.logger, viewerID)
+	}
+	// Muted terms apply to the same general feeds.
+	if filter.UserID == nil && filter.BusinessID == nil && filter.GroupID == nil {
+		filter.MutedTerms = s.mutedTerms.Terms(ctx, filter.ViewerID)
+	}
 
 	// Get total count for pagination
 	totalCount, err := s.postRepo.CountFeed(ctx, filter)
@@ -884,19 +1477,185 @@ func (s *PostService) GetFeed(ctx context.Context, filter *models.FeedFilter, vi
 		s.logger.Error("Failed to get feed", zap.Error(err))
 		return nil, 0, utils.NewInternalError("Failed to get feed", err)
 	}
+	// The SQL already drops blocked authors and hidden posts; the authorizer
+	// also applies FRIENDS / PRIVATE and business status. A page can come
+	// back short, the total is an upper bound.
+	posts = s.authorizer.FilterVisible(ctx, posts, viewerID)
 
 	enrichedPosts := s.enrichPostsBatch(ctx, posts, viewerID)
 
 	return enrichedPosts, totalCount, nil
 }
 
-// GetUserBookmarks gets bookmarked posts for a user
-func (s *PostService) GetUserBookmarks(ctx context.Context, userID string, limit, offset int) ([]*models.PostResponse, error) {
-	posts, err := s.postRepo.GetUserBookmarks(ctx, userID, limit, offset)
+// GetFeedChanges is the delta-sync form of GetFeed: posts matching filter
+// that were created or changed after since, oldest change first, plus the
+// IDs of posts that left the feed since (deleted, deactivated or sold).
+func (s *PostService) GetFeedChanges(ctx context.Context, filter *models.FeedFilter, viewerID *string, since time.Time) (*models.DeltaPage, error) {
+	now := time.Now()
+	if deltaSyncExpired(since, now) {
+		return resetDeltaPage(now), nil
+	}
+
+	if viewerID != nil && *viewerID != "" && filter.ViewerID == "" {
+		filter.ViewerID = *viewerID
+	}
+	if filter.Languages == nil && filter.UserID == nil && filter.BusinessID == nil && filter.GroupID == nil {
+		filter.Languages = contentLanguages(ctx, s.userRepo, s.logger, viewerID)
+	}
+	if filter.UserID == nil && filter.BusinessID == nil && filter.GroupID == nil {
+		filter.MutedTerms = s.mutedTerms.Terms(ctx, filter.ViewerID)
+	}
+	filter.UpdatedAfter = &since
+
+	posts, err := s.postRepo.GetFeed(ctx, filter)
+	if err != nil {
+		s.logger.Error("Failed to get feed changes", zap.Error(err))
+		return nil, utils.NewInternalError("Failed to get feed", err)
+	}
+	deleted, err := s.postRepo.RemovedPostIDs(ctx, filter, since, maxDeltaTombstones+1)
+	if err != nil {
+		s.logger.Error("Failed to get removed posts", zap.Error(err))
+		return nil, utils.NewInternalError("Failed to get feed", err)
+	}
+
+	// Paging follows the rows the query returned, before the authorizer
+	// drops any the viewer may not see.
+	var lastUpdated time.Time
+	if len(posts) > 0 {
+		lastUpdated = posts[len(posts)-1].UpdatedAt
+	}
+	n := len(posts)
+	items := s.enrichPostsBatch(ctx, s.authorizer.FilterVisible(ctx, posts, viewerID), viewerID)
+	if items == nil {
+		items = []*models.PostResponse{}
+	}
+	return newDeltaPage(items, n, filter.Limit, lastUpdated, deleted, since, now), nil
+}
+
+// GetPostsInOrder returns the live posts among postIDs that the viewer may
+// see, in the order given. Used for curated lists such as a business's
+// featured posts.
+func (s *PostService) GetPostsInOrder(ctx context.Context, postIDs []string, viewerID *string) []*models.PostResponse {
+	posts, err := s.postRepo.GetPostsByIDs(ctx, postIDs)
+	if err != nil {
+		s.logger.Warn("Failed to load posts", zap.Int("count", len(postIDs)), zap.Error(err))
+		return nil
+	}
+	rank := make(map[string]int, len(postIDs))
+	for i, id := range postIDs {
+		rank[id] = i
+	}
+	sort.Slice(posts, func(i, j int) bool {
+		return rank[posts[i].ID] < rank[posts[j].ID]
+	})
+	return s.enrichPostsBatch(ctx, s.authorizer.FilterVisible(ctx, posts, viewerID), viewerID)
+}
+
+// postShareable reports whether a post may leave its audience: private and
+// group posts stay where they were posted.
+func postShareable(post *models.Post) bool {
+	return post.Visibility != models.VisibilityPrivate && post.Visibility != models.VisibilityGroup && post.GroupID == nil
+}
+
+// generateShareLinkCode returns an 8-character URL-safe code (48 random bits).
+func generateShareLinkCode() (string, error) {
+	buf := make([]byte, 6)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// CreateExternalShare returns a tracked short link for sharing a post
+// outside the app and counts the share in the post's external_shares.
+func (s *PostService) CreateExternalShare(ctx context.Context, userID, postID string, channel models.ShareChannel) (*models.ExternalShareResponse, error) {
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil || post == nil || post.DeletedAt != nil {
+		return nil, utils.NewNotFoundError("Post not found", err)
+	}
+	if !postShareable(post) {
+		return nil, utils.NewBadRequestError("This post can't be shared", nil)
+	}
+	if channel == "" {
+		channel = models.ShareChannelOther
+	}
+
+	var link *models.PostShareLink
+	var externalShares int
+	// A fresh code only matters the first time this user shares the post
+	// on this channel; retry the rare collision.
+	for attempt := 0; attempt < 3; attempt++ {
+		code, cErr := generateShareLinkCode()
+		if cErr != nil {
+			return nil, utils.NewInternalError("Failed to create share link", cErr)
+		}
+		link, externalShares, err = s.postRepo.RecordExternalShare(ctx, &models.PostShareLink{
+			Code:    code,
+			PostID:  postID,
+			UserID:  userID,
+			Channel: channel,
+		})
+		if !errors.Is(err, repositories.ErrShareLinkCodeTaken) {
+			break
+		}
+	}
+	if err != nil {
+		s.logger.Error("Failed to record external share", zap.String("post_id", postID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to create share link", err)
+	}
+
+	base := s.shareLinkBaseURL
+	if base == "" {
+		base = "https://hamsaya.af/s/"
+	}
+	return &models.ExternalShareResponse{
+		URL:            strings.TrimRight(base, "/") + "/" + link.Code,
+		Code:           link.Code,
+		Channel:        link.Channel,
+		ExternalShares: externalShares,
+	}, nil
+}
+
+// ResolveShareLink counts a click on a short link and returns the URL to
+// redirect to.
+func (s *PostService) ResolveShareLink(ctx context.Context, code string) (string, error) {
+	link, err := s.postRepo.ResolveShareLink(ctx, code)
+	if err != nil {
+		if errors.Is(err, repositories.ErrShareLinkNotFound) {
+			return "", utils.NewNotFoundError("Link not found", err)
+		}
+		return "", utils.NewInternalError("Failed to resolve link", err)
+	}
+
+	base := s.sharePostURL
+	if base == "" {
+		base = "https://hamsaya.af/posts/"
+	}
+	return strings.TrimRight(base, "/") + "/" + link.PostID, nil
+}
+
+// GetUserBookmarks gets bookmarked posts for a user. A non-empty
+// collectionID narrows to that collection (models.BookmarkCollectionNone
+// for unfiled bookmarks).
+func (s *PostService) GetUserBookmarks(ctx context.Context, userID, collectionID string, limit, offset int) ([]*models.PostResponse, error) {
+	var posts []*models.Post
+	var err error
+	if collectionID != "" {
+		if s.bookmarkCollections == nil {
+			return nil, utils.NewBadRequestError("Bookmark collections are not available", nil)
+		}
+		if err := s.bookmarkCollections.CheckOwned(ctx, collectionID, userID); err != nil {
+			return nil, err
+		}
+		posts, err = s.postRepo.GetUserBookmarksInCollection(ctx, userID, collectionID, limit, offset)
+	} else {
+		posts, err = s.postRepo.GetUserBookmarks(ctx, userID, limit, offset)
+	}
 	if err != nil {
 		s.logger.Error("Failed to get bookmarks", zap.String("user_id", userID), zap.Error(err))
 		return nil, utils.NewInternalError("Failed to get bookmarks", err)
 	}
+	posts = s.authorizer.FilterVisible(ctx, posts, &userID)
 
 	return s.enrichPostsBatch(ctx, posts, &userID), nil
 }
@@ -908,16 +1667,17 @@ func (s *PostService) GetUserEventPosts(ctx context.Context, userID string, even
 		s.logger.Error("Failed to get user event posts", zap.String("user_id", userID), zap.String("event_state", string(eventState)), zap.Error(err))
 		return nil, utils.NewInternalError("Failed to get event posts", err)
 	}
+	posts = s.authorizer.FilterVisible(ctx, posts, &userID)
 
 	return s.enrichPostsBatch(ctx, posts, &userID), nil
 }
 
 // GetPersonalizedFeed returns a cursor-paginated feed for viewerID assembled from
 // two sources merged together:
-//   - user_feeds rows (posts fanned out from non-celebrity authors)
+//   - the home timeline (posts fanned out from non-celebrity authors)
 //   - posts from celebrity authors (queried on read to avoid write-amplification)
 func (s *PostService) GetPersonalizedFeed(ctx context.Context, viewerID string, filter *models.FeedFilter) ([]*models.PostResponse, *time.Time, error) {
-	fanoutIDs, lastCursor, err := s.fanoutRepo.GetPersonalizedFeed(ctx, viewerID, filter.Cursor, filter.Limit)
+	fanoutIDs, lastCursor, err := s.feed.Timeline(ctx, viewerID, filter.Cursor, filter.Limit)
 	if err != nil {
 		return nil, nil, utils.NewInternalError("Failed to get personalized feed", err)
 	}
@@ -939,7 +1699,7 @@ func (s *PostService) GetPersonalizedFeed(ctx context.Context, viewerID string,
 		}
 		var nextCursor *time.Time
 		if len(posts) == filter.Limit && len(posts) > 0 {
-			t := posts[len(posts)-1].CreatedAt
+			t := posts[len(posts)-1].FeedTime()
 			nextCursor = &t
 		}
 		return posts, nextCursor, nil
@@ -971,14 +1731,18 @@ func (s *PostService) GetPersonalizedFeed(ctx context.Context, viewerID string,
 		posts = posts[:filter.Limit]
 	}
 
-	enrichedPosts := s.enrichPostsBatch(ctx, posts, &viewerID)
+	// Fan-out rows predate later visibility changes and blocks, and the
+	// timeline isn't filtered by muted terms; the cursor still follows the
+	// unfiltered page so paging doesn't stop early.
+	visible := s.mutedTerms.FilterPosts(ctx, viewerID, s.authorizer.FilterVisible(ctx, posts, &viewerID))
+	enrichedPosts := s.enrichPostsBatch(ctx, visible, &viewerID)
 
 	var nextCursor *time.Time
 	if len(posts) == filter.Limit {
 		if lastCursor != nil {
 			nextCursor = lastCursor
-		} else if len(enrichedPosts) > 0 {
-			t := enrichedPosts[len(enrichedPosts)-1].CreatedAt
+		} else {
+			t := posts[len(posts)-1].CreatedAt
 			nextCursor = &t
 		}
 	}
@@ -1081,6 +1845,12 @@ func (s *PostService) enrichPostsBatch(ctx context.Context, posts []*models.Post
 		attachmentsByPostID = map[string][]*models.Attachment{}
 	}
 
+	productsByPostID := s.productsForPosts(ctx, postIDs)
+	branchesByPostID := s.branchesForPosts(ctx, postIDs)
+	linkPreviewsByPostID := s.linkPreviewsForPosts(ctx, postIDs)
+	helpByPostID := s.helpForPosts(ctx, posts, viewerID)
+	coHostsByPostID := s.eventCoHostsForPosts(ctx, eventPostIDs)
+
 	// Engagement + event interest scoped to viewer.
 	var likedSet, bookmarkedSet map[string]struct{}
 	interestsByPostID := map[string]*models.EventInterest{}
@@ -1107,6 +1877,13 @@ func (s *PostService) enrichPostsBatch(ctx context.Context, posts []*models.Post
 	out := make([]*models.PostResponse, 0, len(posts))
 	for _, post := range posts {
 		response := s.buildPostResponse(post, viewerID, profilesByID, businessesByID, categoriesByID, attachmentsByPostID, likedSet, bookmarkedSet, interestsByPostID, bucket)
+		response.Product = productsByPostID[post.ID]
+		response.Branch = branchesByPostID[post.ID]
+		response.LinkPreview = linkPreviewsByPostID[post.ID]
+		response.Help = helpByPostID[post.ID]
+		if post.Type == models.PostTypeEvent && s.eventHostService != nil {
+			response.Hosts = eventHosts(response, coHostsByPostID[post.ID])
+		}
 
 		// OriginalPost (share) — keep per-post fetch since depth=1 and feed shares
 		// are sparse. Hot path optimization left for a follow-up.
@@ -1121,9 +1898,138 @@ func (s *PostService) enrichPostsBatch(ctx context.Context, posts []*models.Post
 		out = append(out, response)
 	}
 
+	// Authors who limit who sees their location.
+	s.privacy.ApplyToAuthors(ctx, out, viewerID)
+	s.mediaScanner.BlurFlagged(ctx, out)
+	s.viewCounter.ApplyToOwners(ctx, out)
+	s.bookmarkCounter.ApplyToOwners(ctx, out)
+
 	return out
 }
 
+// productsForPosts loads attached catalog products for a set of posts. The
+// product id isn't part of the post scan lists, so this is a separate join
+// keyed by post id. Returns an empty map when products aren't wired or the
+// lookup fails; a missing card must never fail the feed.
+func (s *PostService) productsForPosts(ctx context.Context, postIDs []string) map[string]*models.BusinessProduct {
+	if s.productService == nil || len(postIDs) == 0 {
+		return map[string]*models.BusinessProduct{}
+	}
+	products, err := s.productService.ProductsForPosts(ctx, postIDs)
+	if err != nil {
+		s.logger.Warn("Failed to load post products", zap.Error(err))
+		return map[string]*models.BusinessProduct{}
+	}
+	return products
+}
+
+// branchesForPosts loads the business branch of each post, like
+// productsForPosts.
+func (s *PostService) branchesForPosts(ctx context.Context, postIDs []string) map[string]*models.BusinessBranch {
+	if s.branchService == nil || len(postIDs) == 0 {
+		return map[string]*models.BusinessBranch{}
+	}
+	branches, err := s.branchService.BranchesForPosts(ctx, postIDs)
+	if err != nil {
+		s.logger.Warn("Failed to load post branches", zap.Error(err))
+		return map[string]*models.BusinessBranch{}
+	}
+	return branches
+}
+
+// linkPreviewsForPosts loads the link preview of each post, like
+// productsForPosts.
+func (s *PostService) linkPreviewsForPosts(ctx context.Context, postIDs []string) map[string]*models.LinkPreview {
+	if s.linkPreviews == nil || len(postIDs) == 0 {
+		return map[string]*models.LinkPreview{}
+	}
+	previews, err := s.linkPreviews.PreviewsForPosts(ctx, postIDs)
+	if err != nil {
+		s.logger.Warn("Failed to load link previews", zap.Error(err))
+		return map[string]*models.LinkPreview{}
+	}
+	return previews
+}
+
+// isEventCoHost reports whether userID is an accepted co-host of the event.
+func (s *PostService) isEventCoHost(ctx context.Context, post *models.Post, userID string) bool {
+	if s.eventHostService == nil || post.Type != models.PostTypeEvent {
+		return false
+	}
+	ok, err := s.eventHostService.CanManage(ctx, post, userID)
+	if err != nil {
+		s.logger.Warn("Failed to check event co-host", zap.String("post_id", post.ID), zap.Error(err))
+		return false
+	}
+	return ok
+}
+
+// eventCoHostsForPosts loads the accepted co-hosts of the EVENT posts in the
+// batch.
+func (s *PostService) eventCoHostsForPosts(ctx context.Context, eventPostIDs []string) map[string][]*models.EventHost {
+	if s.eventHostService == nil || len(eventPostIDs) == 0 {
+		return map[string][]*models.EventHost{}
+	}
+	coHosts, err := s.eventHostService.CoHostsForPosts(ctx, eventPostIDs)
+	if err != nil {
+		s.logger.Warn("Failed to load event co-hosts", zap.Error(err))
+		return map[string][]*models.EventHost{}
+	}
+	return coHosts
+}
+
+// eventHosts lists the event's author, as already resolved on the response,
+// followed by its co-hosts.
+func eventHosts(response *models.PostResponse, coHosts []*models.EventHost) []*models.EventHost {
+	hosts := make([]*models.EventHost, 0, len(coHosts)+1)
+	if b := response.Business; b != nil {
+		businessID := b.BusinessID
+		hosts = append(hosts, &models.EventHost{
+			PostID:     response.ID,
+			Role:       models.EventHostRoleHost,
+			BusinessID: &businessID,
+			Name:       b.Name,
+			Avatar:     b.Avatar,
+		})
+	} else if a := response.Author; a != nil {
+		userID := a.UserID
+		hosts = append(hosts, &models.EventHost{
+			PostID: response.ID,
+			Role:   models.EventHostRoleHost,
+			UserID: &userID,
+			Name:   a.FullName,
+			Avatar: a.Avatar,
+		})
+	}
+	return append(hosts, coHosts...)
+}
+
+// helpForPosts loads pledge summaries for the HELP posts in the batch.
+func (s *PostService) helpForPosts(ctx context.Context, posts []*models.Post, viewerID *string) map[string]*models.HelpSummary {
+	if s.pledgeService == nil {
+		return map[string]*models.HelpSummary{}
+	}
+	var helpIDs []string
+	for _, p := range posts {
+		if p.Type == models.PostTypeHelp {
+			helpIDs = append(helpIDs, p.ID)
+		}
+	}
+	if len(helpIDs) == 0 {
+		return map[string]*models.HelpSummary{}
+	}
+	viewer := ""
+	if viewerID != nil {
+		viewer = *viewerID
+	}
+	summaries, err := s.pledgeService.SummariesForPosts(ctx, helpIDs, viewer)
+	if err != nil {
+		s.logger.Warn("Failed to load pledge summaries", zap.Error(err))
+		return map[string]*models.HelpSummary{}
+	}
+	return summaries
+}
+
 // buildPostResponse populates a PostResponse from pre-fetched lookup maps.
 // All map lookups are O(1); no DB calls happen inside.
 func (s *PostService) buildPostResponse(
@@ -1143,15 +2049,18 @@ func (s *PostService) buildPostResponse(
 		Type:          post.Type,
 		Title:         post.Title,
 		Description:   post.Description,
+		Lang:          post.Lang,
 		Visibility:    post.Visibility,
 		Status:        post.Status,
 		UserID:        post.UserID,
 		BusinessID:    post.BusinessID,
+		GroupID:       post.GroupID,
 		TotalComments: post.TotalComments,
 		TotalLikes:    post.TotalLikes,
 		TotalShares:   post.TotalShares,
 		CreatedAt:     post.CreatedAt,
 		UpdatedAt:     post.UpdatedAt,
+		Version:       post.Version,
 	}
 
 	if post.UserID != nil {
@@ -1207,8 +2116,10 @@ func (s *PostService) buildPostResponse(
 			photo := att.Photo
 			photo.URL = storage.EnsureBucketInStorageURL(photo.URL, bucket)
 			response.Attachments = append(response.Attachments, models.AttachmentResponse{
-				ID:    att.ID,
-				Photo: photo,
+				ID:       att.ID,
+				Photo:    photo,
+				Position: att.Position,
+				Caption:  att.Caption,
 			})
 		}
 	}
@@ -1223,6 +2134,10 @@ func (s *PostService) buildPostResponse(
 		response.ContactNo = post.ContactNo
 		response.IsLocation = &post.IsLocation
 		response.ExpiredAt = post.ExpiredAt
+		if post.BumpedAt.After(post.CreatedAt) {
+			renewedAt := post.BumpedAt
+			response.RenewedAt = &renewedAt
+		}
 
 		if post.CategoryID != nil && *post.CategoryID != "" {
 			response.CategoryID = post.CategoryID
@@ -1249,6 +2164,7 @@ func (s *PostService) buildPostResponse(
 			response.UserEventState = &interest.EventState
 		}
 	}
+	applyNoticeFields(response, post)
 
 	if post.AddressLocation != nil && post.AddressLocation.Valid {
 		response.Location = &models.LocationInfo{
@@ -1276,6 +2192,7 @@ func (s *PostService) buildPostResponse(
 		}
 	}
 
+	locationPrecisionFor(response.IsMine, viewerID).applyInfo(response.Location)
 	if viewerID == nil || *viewerID == "" {
 		maskPostResponseForAnon(response)
 	}
@@ -1298,15 +2215,18 @@ func (s *PostService) enrichPost(ctx context.Context, post *models.Post, viewerI
 		Type:          post.Type,
 		Title:         post.Title,
 		Description:   post.Description,
+		Lang:          post.Lang,
 		Visibility:    post.Visibility,
 		Status:        post.Status,
 		UserID:        post.UserID,
 		BusinessID:    post.BusinessID,
+		GroupID:       post.GroupID,
 		TotalComments: post.TotalComments,
 		TotalLikes:    post.TotalLikes,
 		TotalShares:   post.TotalShares,
 		CreatedAt:     post.CreatedAt,
 		UpdatedAt:     post.UpdatedAt,
+		Version:       post.Version,
 	}
 
 	// Fan out the independent DB lookups (author, business, attachments)
@@ -1396,13 +2316,47 @@ func (s *PostService) enrichPost(ctx context.Context, post *models.Post, viewerI
 			photo := att.Photo
 			photo.URL = storage.EnsureBucketInStorageURL(photo.URL, bucket)
 			out = append(out, models.AttachmentResponse{
-				ID:    att.ID,
-				Photo: photo,
+				ID:       att.ID,
+				Photo:    photo,
+				Position: att.Position,
+				Caption:  att.Caption,
 			})
 		}
 		response.Attachments = out
 	}()
 
+	if s.productService != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			response.Product = s.productsForPosts(ctx, []string{post.ID})[post.ID]
+		}()
+	}
+
+	if s.branchService != nil && post.BusinessID != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			response.Branch = s.branchesForPosts(ctx, []string{post.ID})[post.ID]
+		}()
+	}
+
+	if s.linkPreviews != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			response.LinkPreview = s.linkPreviewsForPosts(ctx, []string{post.ID})[post.ID]
+		}()
+	}
+
+	if post.Type == models.PostTypeHelp {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			response.Help = s.helpForPosts(ctx, []*models.Post{post}, viewerID)[post.ID]
+		}()
+	}
+
 	wg.Wait()
 
 	// Add type-specific fields
@@ -1416,6 +2370,10 @@ func (s *PostService) enrichPost(ctx context.Context, post *models.Post, viewerI
 		response.ContactNo = post.ContactNo
 		response.IsLocation = &post.IsLocation
 		response.ExpiredAt = post.ExpiredAt
+		if post.BumpedAt.After(post.CreatedAt) {
+			renewedAt := post.BumpedAt
+			response.RenewedAt = &renewedAt
+		}
 
 		// Get category info if post has a category
 		if post.CategoryID != nil && *post.CategoryID != "" {
@@ -1446,7 +2404,11 @@ func (s *PostService) enrichPost(ctx context.Context, post *models.Post, viewerI
 				response.UserEventState = &userInterest.EventState
 			}
 		}
+		if s.eventHostService != nil {
+			response.Hosts = eventHosts(response, s.eventCoHostsForPosts(ctx, []string{post.ID})[post.ID])
+		}
 	}
+	applyNoticeFields(response, post)
 
 	// Add location info (is_location for both SELL and EVENT so discover/map work)
 	if post.AddressLocation != nil && post.AddressLocation.Valid {
@@ -1489,6 +2451,12 @@ func (s *PostService) enrichPost(ctx context.Context, post *models.Post, viewerI
 		}
 	}
 
+	s.privacy.ApplyToAuthors(ctx, []*models.PostResponse{response}, viewerID)
+	s.mediaScanner.BlurFlagged(ctx, []*models.PostResponse{response})
+	s.viewCounter.ApplyToOwners(ctx, []*models.PostResponse{response})
+	s.bookmarkCounter.ApplyToOwners(ctx, []*models.PostResponse{response})
+
+	locationPrecisionFor(response.IsMine, viewerID).applyInfo(response.Location)
 	if viewerID == nil || *viewerID == "" {
 		maskPostResponseForAnon(response)
 	}
@@ -1504,15 +2472,18 @@ func (s *PostService) enrichPostSimple(ctx context.Context, post *models.Post, v
 		Type:          post.Type,
 		Title:         post.Title,
 		Description:   post.Description,
+		Lang:          post.Lang,
 		Visibility:    post.Visibility,
 		Status:        post.Status,
 		UserID:        post.UserID,
 		BusinessID:    post.BusinessID,
+		GroupID:       post.GroupID,
 		TotalComments: post.TotalComments,
 		TotalLikes:    post.TotalLikes,
 		TotalShares:   post.TotalShares,
 		CreatedAt:     post.CreatedAt,
 		UpdatedAt:     post.UpdatedAt,
+		Version:       post.Version,
 	}
 
 	// Get author info
@@ -1578,8 +2549,10 @@ func (s *PostService) enrichPostSimple(ctx context.Context, post *models.Post, v
 			photo := att.Photo
 			photo.URL = storage.EnsureBucketInStorageURL(photo.URL, bucket)
 			response.Attachments = append(response.Attachments, models.AttachmentResponse{
-				ID:    att.ID,
-				Photo: photo,
+				ID:       att.ID,
+				Photo:    photo,
+				Position: att.Position,
+				Caption:  att.Caption,
 			})
 		}
 	}
@@ -1595,6 +2568,10 @@ func (s *PostService) enrichPostSimple(ctx context.Context, post *models.Post, v
 		response.ContactNo = post.ContactNo
 		response.IsLocation = &post.IsLocation
 		response.ExpiredAt = post.ExpiredAt
+		if post.BumpedAt.After(post.CreatedAt) {
+			renewedAt := post.BumpedAt
+			response.RenewedAt = &renewedAt
+		}
 
 		// Get category info if post has a category
 		if post.CategoryID != nil && *post.CategoryID != "" {
@@ -1625,7 +2602,11 @@ func (s *PostService) enrichPostSimple(ctx context.Context, post *models.Post, v
 				response.UserEventState = &userInterest.EventState
 			}
 		}
+		if s.eventHostService != nil {
+			response.Hosts = eventHosts(response, s.eventCoHostsForPosts(ctx, []string{post.ID})[post.ID])
+		}
 	}
+	applyNoticeFields(response, post)
 
 	// Add location info (is_location for both SELL and EVENT so discover/map work)
 	if post.AddressLocation != nil && post.AddressLocation.Valid {
@@ -1658,6 +2639,12 @@ func (s *PostService) enrichPostSimple(ctx context.Context, post *models.Post, v
 
 	// Note: OriginalPost is NOT enriched here to prevent infinite recursion
 
+	s.privacy.ApplyToAuthors(ctx, []*models.PostResponse{response}, viewerID)
+	s.mediaScanner.BlurFlagged(ctx, []*models.PostResponse{response})
+	s.viewCounter.ApplyToOwners(ctx, []*models.PostResponse{response})
+	s.bookmarkCounter.ApplyToOwners(ctx, []*models.PostResponse{response})
+
+	locationPrecisionFor(response.IsMine, viewerID).applyInfo(response.Location)
 	if viewerID == nil || *viewerID == "" {
 		maskPostResponseForAnon(response)
 	}
@@ -1750,6 +2737,74 @@ func (s *PostService) sendPostNotification(ctx context.Context, actorUserID, rec
 
 const _newPostNotifyBatchSize = 300
 
+// notifyNearbyOfAlert sends a SAFETY_ALERT to every user whose saved
+// location is within the urgency's radius of the alert.
+func (s *PostService) notifyNearbyOfAlert(ctx context.Context, post *models.Post) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("notifyNearbyOfAlert panic", zap.Any("panic", r), zap.String("post_id", post.ID))
+		}
+	}()
+
+	if s.notificationService == nil || post.AddressLocation == nil || !post.AddressLocation.Valid ||
+		post.Urgency == nil || post.UserID == nil {
+		return
+	}
+	posterID := *post.UserID
+	radiusKm := post.Urgency.AlertRadiusKm()
+	lat, lng := post.AddressLocation.P.Y, post.AddressLocation.P.X
+
+	title, msg := safetyAlertText(post)
+	data := map[string]interface{}{
+		"actor_id":  posterID,
+		"post_id":   post.ID,
+		"post_type": string(models.PostTypeAlert),
+		"urgency":   string(*post.Urgency),
+		"radius_km": radiusKm,
+	}
+
+	sent := 0
+	offset := 0
+	for {
+		ids, err := s.userRepo.GetUserIDsWithinRadius(ctx, lat, lng, radiusKm, posterID, _newPostNotifyBatchSize, offset)
+		if err != nil {
+			s.logger.Warn("GetUserIDsWithinRadius failed", zap.String("post_id", post.ID), zap.Error(err))
+			break
+		}
+		for _, recipientID := range ids {
+			if _, err := s.notificationService.CreateNotification(ctx, &models.CreateNotificationRequest{
+				UserID:  recipientID,
+				Type:    models.NotificationTypeSafetyAlert,
+				Title:   &title,
+				Message: &msg,
+				Data:    data,
+			}); err != nil {
+				s.logger.Warn("CreateNotification (SAFETY_ALERT) failed", zap.String("recipient_id", recipientID), zap.Error(err))
+				continue
+			}
+			sent++
+		}
+		if len(ids) < _newPostNotifyBatchSize {
+			break
+		}
+		offset += _newPostNotifyBatchSize
+	}
+	s.logger.Info("Safety alert: notifications sent",
+		zap.String("post_id", post.ID), zap.Float64("radius_km", radiusKm), zap.Int("sent", sent))
+}
+
+// safetyAlertText is the title and body of the push for an ALERT post.
+func safetyAlertText(post *models.Post) (title, msg string) {
+	title = "Safety alert nearby"
+	if post.Title != nil && strings.TrimSpace(*post.Title) != "" {
+		title = "Safety alert: " + strings.TrimSpace(*post.Title)
+	}
+	if post.Description != nil {
+		msg = *post.Description
+	}
+	return title, msg
+}
+
 // notifyFollowersOfNewPost notifies all followers of the user or business when a new post is created.
 func (s *PostService) notifyFollowersOfNewPost(ctx context.Context, postID, posterUserID string, businessID *string) {
 	defer func() {
@@ -1811,11 +2866,25 @@ func (s *PostService) notifyFollowersOfNewPost(ctx context.Context, postID, post
 		"actor_avatar_color": actorAvatarColor,
 		"post_id":            postID,
 	}
+	var postType models.PostType
 	if post, err := s.postRepo.GetByID(ctx, postID); err == nil {
+		postType = post.Type
 		data["post_type"] = strings.ToUpper(string(post.Type))
 	}
 	if businessID != nil && *businessID != "" {
 		data["business_id"] = *businessID
+
+		// Owners can keep routine updates and polls out of their followers'
+		// notifications; events still go out.
+		if models.IsLowPriorityBusinessPost(postType) {
+			if settings, err := s.businessRepo.GetFollowerNotificationSettings(ctx, *businessID); err == nil && !settings.NotifyLowPriorityPosts {
+				s.logger.Info("New post: business muted low-priority follower notifications",
+					zap.String("post_id", postID),
+					zap.String("business_id", *businessID),
+					zap.String("post_type", string(postType)))
+				return
+			}
+		}
 	}
 
 	var followerIDs []string
@@ -1929,6 +2998,21 @@ func (s *PostService) validatePostRequest(req *models.CreatePostRequest) error {
 	if req.Visibility == models.VisibilityViewOnly && req.Type != models.PostTypeFeed {
 		return utils.NewBadRequestError("View only visibility is only allowed for feed posts", nil)
 	}
+	limits := s.contentLimits()
+	postLimits := limits.ForPost(req.Type)
+	if req.Title != nil {
+		if err := checkTextLength("Title", *req.Title, postLimits.TitleMaxLength); err != nil {
+			return err
+		}
+	}
+	if req.Description != nil {
+		if err := checkTextLength("Description", *req.Description, postLimits.DescriptionMaxLength); err != nil {
+			return err
+		}
+	}
+	if err := checkAttachmentCount(len(req.Attachments), postLimits.MaxAttachments); err != nil {
+		return err
+	}
 	switch req.Type {
 	case models.PostTypeSell:
 		if req.Title == nil || *req.Title == "" {
@@ -1949,35 +3033,115 @@ func (s *PostService) validatePostRequest(req *models.CreatePostRequest) error {
 			return utils.NewBadRequestError("Description is required for pull posts", nil)
 		}
 		// Check both poll formats (poll_options or poll.options)
-		pollOptionsCount := len(req.PollOptions)
+		pollOptions := req.PollOptions
 		if req.Poll != nil {
-			pollOptionsCount = len(req.Poll.Options)
+			pollOptions = req.Poll.Options
 		}
-		if pollOptionsCount < 2 {
+		if len(pollOptions) < 2 {
 			return utils.NewBadRequestError("Poll options are required for pull posts (minimum 2 options)", nil)
 		}
-		if pollOptionsCount > 10 {
-			return utils.NewBadRequestError("Maximum 10 poll options allowed", nil)
+		if err := checkPollOptions(pollOptions, limits); err != nil {
+			return err
+		}
+		if req.Poll != nil {
+			if err := validatePollSettings(req.Poll.ResultsVisibility, req.Poll.ClosesAt, time.Now()); err != nil {
+				return err
+			}
 		}
 	case models.PostTypeFeed:
 		if req.Description == nil || *req.Description == "" {
 			return utils.NewBadRequestError("Description is required for feed posts", nil)
 		}
+	case models.PostTypeLostFound:
+		if req.LostFoundKind == nil {
+			return utils.NewBadRequestError("lost_found_kind (LOST or FOUND) is required for lost & found posts", nil)
+		}
+		if req.ItemDescription == nil || strings.TrimSpace(*req.ItemDescription) == "" {
+			return utils.NewBadRequestError("Item description is required for lost & found posts", nil)
+		}
+		if req.LastSeenAt != nil && req.LastSeenAt.After(time.Now().Add(time.Hour)) {
+			return utils.NewBadRequestError("Last seen time cannot be in the future", nil)
+		}
+	case models.PostTypeHelp:
+		if req.Title == nil || strings.TrimSpace(*req.Title) == "" {
+			return utils.NewBadRequestError("Title is required for help requests", nil)
+		}
+		if req.Description == nil || strings.TrimSpace(*req.Description) == "" {
+			return utils.NewBadRequestError("Description is required for help requests", nil)
+		}
+	case models.PostTypeAlert:
+		if req.Description == nil || strings.TrimSpace(*req.Description) == "" {
+			return utils.NewBadRequestError("Description is required for alert posts", nil)
+		}
+		if req.Urgency == nil {
+			return utils.NewBadRequestError("Urgency is required for alert posts", nil)
+		}
+		lat, lon := req.Latitude, req.Longitude
+		if (lat == nil || lon == nil) && req.Location != nil {
+			lat, lon = req.Location.Latitude, req.Location.Longitude
+		}
+		if lat == nil || lon == nil {
+			return utils.NewBadRequestError("Location is required for alert posts", nil)
+		}
+		if req.GroupID != nil && *req.GroupID != "" {
+			return utils.NewBadRequestError("Alerts can't be posted into a group", nil)
+		}
 	}
 
 	return nil
 }
 
+// checkUpdateLimits holds an edit to the post type's content limits. New
+// attachments count on top of the ones the edit keeps.
+func (s *PostService) checkUpdateLimits(ctx context.Context, post *models.Post, req *models.UpdatePostRequest) error {
+	limits := s.contentLimits()
+	postLimits := limits.ForPost(post.Type)
+	if req.Title != nil {
+		if err := checkTextLength("Title", *req.Title, postLimits.TitleMaxLength); err != nil {
+			return err
+		}
+	}
+	if req.Description != nil {
+		if err := checkTextLength("Description", *req.Description, postLimits.DescriptionMaxLength); err != nil {
+			return err
+		}
+	}
+	if post.Type == models.PostTypePull && len(req.PollOptions) > 0 {
+		if err := checkPollOptions(req.PollOptions, limits); err != nil {
+			return err
+		}
+	}
+	if len(req.Attachments) == 0 {
+		return nil
+	}
+	existing, err := s.postRepo.GetAttachmentsByPostID(ctx, post.ID)
+	if err != nil {
+		return utils.NewInternalError("Failed to load attachments", err)
+	}
+	deleted := make(map[string]bool, len(req.DeletedAttachments))
+	for _, id := range req.DeletedAttachments {
+		deleted[id] = true
+	}
+	kept := 0
+	for _, att := range existing {
+		if !deleted[att.ID] {
+			kept++
+		}
+	}
+	return checkAttachmentCount(kept+len(req.Attachments), postLimits.MaxAttachments)
+}
+
 // ResellPost reactivates an expired SELL post owned by userID.
-// It sets status=true, sold=false, and resets expired_at to 30 days from now so the
-// post is live again and the expiry job will re-evaluate it after the new window.
+// It sets status=true, sold=false, and resets expired_at to the SELL expiry
+// from now so the post is live again and the expiry job will re-evaluate it
+// after the new window.
 func (s *PostService) ResellPost(ctx context.Context, postID, userID string) (*models.PostResponse, error) {
 	post, err := s.postRepo.GetByID(ctx, postID)
 	if err != nil {
 		return nil, utils.NewNotFoundError("Post not found", err)
 	}
 
-	if post.UserID == nil || *post.UserID != userID {
+	if !s.access.CanEditPost(ctx, post, userID) {
 		return nil, utils.NewForbiddenError("You don't have permission to resell this post", nil)
 	}
 
@@ -1985,7 +3149,7 @@ func (s *PostService) ResellPost(ctx context.Context, postID, userID string) (*m
 		return nil, utils.NewBadRequestError("Only sell posts can be resold", nil)
 	}
 
-	if err := s.postRepo.ReactivateSellPost(ctx, postID); err != nil {
+	if err := s.postRepo.ReactivateSellPost(ctx, postID, time.Now().Add(s.expiry.SellTTL())); err != nil {
 		return nil, utils.NewInternalError("Failed to resell post", err)
 	}
 
@@ -2003,6 +3167,151 @@ func (s *PostService) ResellPost(ctx context.Context, postID, userID string) (*m
 	return s.enrichPost(ctx, post, &userID)
 }
 
+// sellRenewCooldown is how often a seller may renew (bump) one listing.
+const sellRenewCooldown = 72 * time.Hour
+
+// RenewPost renews an active, unsold SELL post owned by userID: expired_at
+// moves to the SELL expiry from now and the post goes back to the top of the recent
+// feed. A listing can be renewed once per sellRenewCooldown, so sellers
+// don't need to delete and recreate it to stay visible.
+func (s *PostService) RenewPost(ctx context.Context, postID, userID string) (*models.PostResponse, error) {
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, utils.NewNotFoundError("Post not found", err)
+	}
+
+	if !s.access.CanEditPost(ctx, post, userID) {
+		return nil, utils.NewForbiddenError("You don't have permission to renew this post", nil)
+	}
+	if post.Type != models.PostTypeSell {
+		return nil, utils.NewBadRequestError("Only sell posts can be renewed", nil)
+	}
+	if post.Sold || !post.Status {
+		return nil, utils.NewBadRequestError("Only active listings can be renewed; relist it instead", nil)
+	}
+
+	renewed, err := s.postRepo.RenewSellPost(ctx, postID, sellRenewCooldown, time.Now().Add(s.expiry.SellTTL()))
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to renew post", err)
+	}
+	if !renewed {
+		wait := time.Until(post.BumpedAt.Add(sellRenewCooldown))
+		hours := int((wait + time.Hour - 1) / time.Hour)
+		if hours < 1 {
+			hours = 1
+		}
+		return nil, utils.NewTooManyRequestsError(fmt.Sprintf("You can renew this listing again in %d hours", hours), nil)
+	}
+
+	post, err = s.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to reload post after renewal", err)
+	}
+
+	s.logger.Info("Sell post renewed",
+		zap.String("post_id", postID),
+		zap.String("user_id", userID),
+	)
+
+	return s.enrichPost(ctx, post, &userID)
+}
+
+// GetMyListings returns one tab of the user's SELL listings: active, sold
+// (the sold-item archive) or expired.
+func (s *PostService) GetMyListings(ctx context.Context, userID string, state models.ListingState, limit, offset int) ([]*models.PostResponse, int64, error) {
+	posts, total, err := s.postRepo.ListUserListings(ctx, userID, state, limit, offset)
+	if err != nil {
+		s.logger.Error("Failed to list listings", zap.String("user_id", userID), zap.String("state", string(state)), zap.Error(err))
+		return nil, 0, utils.NewInternalError("Failed to get listings", err)
+	}
+	return s.enrichPostsBatch(ctx, posts, &userID), total, nil
+}
+
+// RelistPost copies a sold or expired SELL post owned by userID into a new
+// listing with the same details and attachments. The copy goes through
+// CreatePost, so automod, throttles and daily limits apply as for any new
+// post. Listings with open or upheld reports can't be relisted, and
+// relisting the same listing again returns the existing copy.
+func (s *PostService) RelistPost(ctx context.Context, postID, userID string) (*models.PostResponse, error) {
+	post, err := s.postRepo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, utils.NewNotFoundError("Post not found", err)
+	}
+
+	if !s.access.CanEditPost(ctx, post, userID) {
+		return nil, utils.NewForbiddenError("You don't have permission to relist this post", nil)
+	}
+	if post.Type != models.PostTypeSell {
+		return nil, utils.NewBadRequestError("Only sell posts can be relisted", nil)
+	}
+	expired := post.ExpiredAt != nil && !post.ExpiredAt.After(time.Now())
+	if !post.Sold && !expired {
+		return nil, utils.NewBadRequestError("Only sold or expired listings can be relisted", nil)
+	}
+
+	reported, err := s.postRepo.HasActionableReports(ctx, postID)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to relist post", err)
+	}
+	if reported {
+		return nil, utils.NewForbiddenError("This listing was reported and can't be relisted", nil)
+	}
+
+	attachments, err := s.postRepo.GetAttachmentsByPostID(ctx, postID)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to relist post", err)
+	}
+
+	token := models.RelistClientToken(postID)
+	req := &models.CreatePostRequest{
+		Title:        post.Title,
+		Description:  post.Description,
+		Type:         models.PostTypeSell,
+		Visibility:   post.Visibility,
+		Currency:     post.Currency,
+		Price:        post.Price,
+		Discount:     post.Discount,
+		Free:         &post.Free,
+		CategoryID:   post.CategoryID,
+		CountryCode:  post.CountryCode,
+		ContactNo:    post.ContactNo,
+		IsLocation:   &post.IsLocation,
+		Country:      post.Country,
+		Province:     post.Province,
+		District:     post.District,
+		Neighborhood: post.Neighborhood,
+		BusinessID:   post.BusinessID,
+		GroupID:      post.GroupID,
+		ClientToken:  &token,
+	}
+	if post.AddressLocation != nil && post.AddressLocation.Valid {
+		lat, lng := post.AddressLocation.P.Y, post.AddressLocation.P.X
+		req.Latitude, req.Longitude = &lat, &lng
+	}
+	for _, a := range attachments {
+		raw, err := json.Marshal(struct {
+			models.Photo
+			Caption *string `json:"caption,omitempty"`
+		}{a.Photo, a.Caption})
+		if err != nil {
+			continue
+		}
+		req.Attachments = append(req.Attachments, raw)
+	}
+
+	relisted, err := s.CreatePost(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Sell post relisted",
+		zap.String("post_id", postID),
+		zap.String("new_post_id", relisted.ID),
+		zap.String("user_id", userID),
+	)
+	return relisted, nil
+}
+
 // ProcessExpiredSellPosts finds all SELL posts that have passed their expiry date without
 // being sold, sends a SELL_EXPIRED push notification to each owner, then deactivates the posts
 // so they no longer appear in feeds. Returns the number of posts processed.
@@ -2068,19 +3377,23 @@ func (s *PostService) ProcessExpiredSellPosts(ctx context.Context) (int, error)
 	return len(expiredIDs), nil
 }
 
+// ArchiveExpiredPosts takes EVENT and ALERT posts past their expiry out of
+// the feeds. Returns the number of posts archived.
+func (s *PostService) ArchiveExpiredPosts(ctx context.Context) (int, error) {
+	count, err := s.postRepo.ArchiveExpiredPosts(ctx, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive expired posts: %w", err)
+	}
+	return int(count), nil
+}
+
 // maskPostResponseForAnon strips PII that unauthenticated callers must not
-// scrape from the public read endpoints: seller phone numbers and precise
-// coordinates. Coordinates are rounded to 2 decimals (~1 km) so map browsing
-// stays useful without exposing an exact home/shop pin. Client-side gating
-// is cosmetic — this is the real boundary.
+// scrape from the public read endpoints: seller phone numbers and business
+// contacts. Coordinates are coarsened for every non-owner by
+// locationPrecisionFor. Client-side gating is cosmetic — this is the real
+// boundary.
 func maskPostResponseForAnon(response *models.PostResponse) {
 	response.ContactNo = nil
-	if response.Location != nil && response.Location.Latitude != nil && response.Location.Longitude != nil {
-		lat := math.Round(*response.Location.Latitude*100) / 100
-		lng := math.Round(*response.Location.Longitude*100) / 100
-		response.Location.Latitude = &lat
-		response.Location.Longitude = &lng
-	}
 	if response.Business != nil {
 		response.Business.PhoneNumber = nil
 		response.Business.Email = nil
@@ -2,15 +2,22 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"net/http"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/hamsaya/backend/config"
 	"github.com/hamsaya/backend/internal/mocks"
 	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
 	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/hamsaya/backend/internal/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
@@ -240,6 +247,11 @@ func TestPostService_GetPost(t *testing.T) {
 		// GetEngagementStatus is called when viewerID is set
 		postRepo.On("GetEngagementStatus", mock.Anything, viewerID, "post-1").
 			Return(false, false, nil)
+		// The authorizer checks blocks between viewer and author
+		relRepo := new(mocks.MockRelationshipsRepository)
+		relRepo.On("GetRelationshipStatus", mock.Anything, viewerID, ownerID).
+			Return(&models.RelationshipStatus{}, nil)
+		svc.authorizer = NewPostAuthorizer(relRepo, new(mocks.MockBusinessRepository))
 
 		result, err := svc.GetPost(context.Background(), "post-1", &viewerID)
 
@@ -250,3 +262,407 @@ func TestPostService_GetPost(t *testing.T) {
 		userRepo.AssertExpectations(t)
 	})
 }
+
+// ─── UpdatePost version check ────────────────────────────────────────────────
+
+func TestPostService_UpdatePostVersionConflict(t *testing.T) {
+	setup := func(t *testing.T) (*PostService, *mocks.MockPostRepository) {
+		postRepo := new(mocks.MockPostRepository)
+		userRepo := new(mocks.MockUserRepository)
+		svc := newTestPostService(postRepo, userRepo)
+
+		post := testutil.CreateTestPost("post-1", "user-1", models.PostTypeFeed)
+		post.Version = 4
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(post, nil)
+		postRepo.On("GetAttachmentsByPostID", mock.Anything, "post-1").Return([]*models.Attachment{}, nil)
+		postRepo.On("GetEngagementStatus", mock.Anything, "user-1", "post-1").Return(false, false, nil).Maybe()
+		userRepo.On("GetProfileByUserID", mock.Anything, "user-1").
+			Return(testutil.CreateTestProfile("user-1", "John", "Doe"), nil).Maybe()
+		return svc, postRepo
+	}
+	requireConflict := func(t *testing.T, err error) {
+		var appErr *utils.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusConflict, appErr.Code)
+		conflict, ok := appErr.Err.(*utils.VersionConflictError)
+		require.True(t, ok)
+		current, ok := conflict.Current.(*models.PostResponse)
+		require.True(t, ok)
+		assert.Equal(t, 4, current.Version)
+	}
+
+	t.Run("stale version is refused before writing", func(t *testing.T) {
+		svc, postRepo := setup(t)
+		stale := 3
+		_, err := svc.UpdatePost(context.Background(), "post-1", "user-1", &models.UpdatePostRequest{
+			Description: testutil.StringPtr("edited"),
+			Version:     &stale,
+		})
+
+		requireConflict(t, err)
+		postRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("saved elsewhere between read and write", func(t *testing.T) {
+		svc, postRepo := setup(t)
+		postRepo.On("Update", mock.Anything, mock.Anything).Return(repositories.ErrVersionConflict)
+		_, err := svc.UpdatePost(context.Background(), "post-1", "user-1", &models.UpdatePostRequest{
+			Description: testutil.StringPtr("edited"),
+		})
+
+		requireConflict(t, err)
+	})
+}
+
+// ─── RenewPost ───────────────────────────────────────────────────────────────
+
+func TestPostService_RenewPost(t *testing.T) {
+	t.Run("only sell posts", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		svc := newTestPostService(postRepo, new(mocks.MockUserRepository))
+
+		post := testutil.CreateTestPost("post-1", "user-1", models.PostTypeFeed)
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(post, nil)
+
+		_, err := svc.RenewPost(context.Background(), "post-1", "user-1")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "Only sell posts")
+		postRepo.AssertNotCalled(t, "RenewSellPost", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("sold listings are relisted, not renewed", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		svc := newTestPostService(postRepo, new(mocks.MockUserRepository))
+
+		post := testutil.CreateTestPost("post-1", "user-1", models.PostTypeSell)
+		post.Sold = true
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(post, nil)
+
+		_, err := svc.RenewPost(context.Background(), "post-1", "user-1")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "relist")
+		postRepo.AssertNotCalled(t, "RenewSellPost", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("cooldown", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		svc := newTestPostService(postRepo, new(mocks.MockUserRepository))
+
+		post := testutil.CreateTestPost("post-1", "user-1", models.PostTypeSell)
+		post.BumpedAt = time.Now().Add(-24 * time.Hour)
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(post, nil)
+		postRepo.On("RenewSellPost", mock.Anything, "post-1", sellRenewCooldown, mock.AnythingOfType("time.Time")).Return(false, nil)
+
+		_, err := svc.RenewPost(context.Background(), "post-1", "user-1")
+
+		var appErr *utils.AppError
+		assert.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusTooManyRequests, appErr.Code)
+		assert.Contains(t, appErr.Message, "48 hours")
+		postRepo.AssertExpectations(t)
+	})
+}
+
+// ─── RelistPost ──────────────────────────────────────────────────────────────
+
+func TestPostService_RelistPost(t *testing.T) {
+	t.Run("active listings are renewed, not relisted", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		svc := newTestPostService(postRepo, new(mocks.MockUserRepository))
+
+		post := testutil.CreateTestPost("post-1", "user-1", models.PostTypeSell)
+		expiry := time.Now().Add(24 * time.Hour)
+		post.ExpiredAt = &expiry
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(post, nil)
+
+		_, err := svc.RelistPost(context.Background(), "post-1", "user-1")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "sold or expired")
+	})
+
+	t.Run("reported listings can't be relisted", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		svc := newTestPostService(postRepo, new(mocks.MockUserRepository))
+
+		post := testutil.CreateTestPost("post-1", "user-1", models.PostTypeSell)
+		post.Sold = true
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(post, nil)
+		postRepo.On("HasActionableReports", mock.Anything, "post-1").Return(true, nil)
+
+		_, err := svc.RelistPost(context.Background(), "post-1", "user-1")
+
+		var appErr *utils.AppError
+		assert.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusForbidden, appErr.Code)
+		postRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("copies the listing with the relist token", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		svc := newTestPostService(postRepo, new(mocks.MockUserRepository))
+
+		post := testutil.CreateTestPost("post-1", "user-1", models.PostTypeSell)
+		post.Sold = true
+		title := "Bicycle"
+		price := 50.0
+		post.Title = &title
+		post.Price = &price
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(post, nil)
+		postRepo.On("HasActionableReports", mock.Anything, "post-1").Return(false, nil)
+		postRepo.On("GetAttachmentsByPostID", mock.Anything, "post-1").Return([]*models.Attachment{}, nil)
+		postRepo.On("GetByClientToken", mock.Anything, "user-1", models.RelistClientToken("post-1")).Return(nil, nil)
+		postRepo.On("Create", mock.Anything, mock.Anything).Return(errors.New("stop here"))
+
+		_, err := svc.RelistPost(context.Background(), "post-1", "user-1")
+
+		// The token lets CreatePost return the earlier copy on a repeat.
+		assert.Error(t, err)
+		postRepo.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(p *models.Post) bool {
+			return p.ClientToken != nil && *p.ClientToken == "relist:post-1" && p.Price != nil && *p.Price == 50
+		}))
+	})
+}
+
+// ─── Content limits ──────────────────────────────────────────────────────────
+
+func TestPostService_ValidateContentLimits(t *testing.T) {
+	limits := ContentLimitsFromConfig(config.ContentConfig{
+		Post:      config.ContentPostLimits{DescriptionMaxLength: 20},
+		PostTypes: map[string]config.ContentPostLimits{"SELL": {MaxAttachments: 1}},
+	})
+	svc := newTestPostService(new(mocks.MockPostRepository), new(mocks.MockUserRepository)).
+		WithContentLimits(limits)
+	short, long := "Lovely weather", strings.Repeat("ب", 21)
+	title := "Bicycle"
+	free := true
+	attachments := []json.RawMessage{json.RawMessage(`"https://cdn/a.webp"`), json.RawMessage(`"https://cdn/b.webp"`)}
+
+	tests := []struct {
+		name    string
+		req     *models.CreatePostRequest
+		wantErr string
+	}{
+		{"description fits", &models.CreatePostRequest{Type: models.PostTypeFeed, Description: &short}, ""},
+		{"description counts characters", &models.CreatePostRequest{Type: models.PostTypeFeed, Description: &long}, "at most 20 characters"},
+		{"feed keeps default attachments", &models.CreatePostRequest{Type: models.PostTypeFeed, Description: &short, Attachments: attachments}, ""},
+		{"sell has its own attachment cap", &models.CreatePostRequest{Type: models.PostTypeSell, Title: &title, Free: &free, Attachments: attachments}, "maximum 1 attachments"},
+		{"poll option length", &models.CreatePostRequest{Type: models.PostTypePull, Description: &short,
+			PollOptions: []string{"Yes", strings.Repeat("x", 101)}}, "poll options must be at most 100"},
+		{"poll option count", &models.CreatePostRequest{Type: models.PostTypePull, Description: &short,
+			PollOptions: strings.Split("a,b,c,d,e,f,g,h,i,j,k", ",")}, "maximum 10 poll options"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.validatePostRequest(tt.req)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			if assert.Error(t, err) {
+				assert.Contains(t, strings.ToLower(err.Error()), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPostService_UpdatePost_AttachmentLimit(t *testing.T) {
+	postRepo := new(mocks.MockPostRepository)
+	svc := newTestPostService(postRepo, new(mocks.MockUserRepository)).
+		WithContentLimits(ContentLimitsFromConfig(config.ContentConfig{Post: config.ContentPostLimits{MaxAttachments: 2}}))
+
+	post := testutil.CreateTestPost("post-1", "user-1", models.PostTypeFeed)
+	postRepo.On("GetByID", mock.Anything, "post-1").Return(post, nil)
+	postRepo.On("GetAttachmentsByPostID", mock.Anything, "post-1").Return([]*models.Attachment{{ID: "att-1"}, {ID: "att-2"}}, nil)
+
+	_, err := svc.UpdatePost(context.Background(), "post-1", "user-1", &models.UpdatePostRequest{
+		Attachments: []json.RawMessage{json.RawMessage(`"https://cdn/c.webp"`)},
+	})
+	requireAppErrorCode(t, err, http.StatusBadRequest)
+	postRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// ─── Lost & found / alert validation ─────────────────────────────────────────
+
+func TestPostService_ValidateNoticePosts(t *testing.T) {
+	svc := newTestPostService(new(mocks.MockPostRepository), new(mocks.MockUserRepository))
+	item := "Black backpack with a laptop"
+	desc := "Road closed after a gas leak"
+	lost := models.LostFoundLost
+	high := models.AlertUrgencyHigh
+	lat, lng := 34.5553, 69.2075
+
+	tests := []struct {
+		name    string
+		req     *models.CreatePostRequest
+		wantErr string
+	}{
+		{"lost & found needs kind", &models.CreatePostRequest{Type: models.PostTypeLostFound, ItemDescription: &item}, "lost_found_kind"},
+		{"lost & found needs item", &models.CreatePostRequest{Type: models.PostTypeLostFound, LostFoundKind: &lost}, "item description"},
+		{"lost & found ok", &models.CreatePostRequest{Type: models.PostTypeLostFound, LostFoundKind: &lost, ItemDescription: &item}, ""},
+		{"alert needs urgency", &models.CreatePostRequest{Type: models.PostTypeAlert, Description: &desc, Latitude: &lat, Longitude: &lng}, "urgency"},
+		{"alert needs location", &models.CreatePostRequest{Type: models.PostTypeAlert, Description: &desc, Urgency: &high}, "location"},
+		{"alert ok", &models.CreatePostRequest{Type: models.PostTypeAlert, Description: &desc, Urgency: &high,
+			Location: &models.CreatePostLocation{Latitude: &lat, Longitude: &lng}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.validatePostRequest(tt.req)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			if assert.Error(t, err) {
+				assert.Contains(t, strings.ToLower(err.Error()), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAlertUrgency(t *testing.T) {
+	assert.Equal(t, []string{"HIGH", "CRITICAL"}, models.AlertUrgencyHigh.AtLeast())
+	assert.Len(t, models.AlertUrgencyLow.AtLeast(), 4)
+	assert.Nil(t, models.AlertUrgency("SEVERE").AtLeast())
+	assert.Greater(t, models.AlertUrgencyCritical.AlertRadiusKm(), models.AlertUrgencyLow.AlertRadiusKm())
+}
+
+func TestPostService_ReorderAttachments(t *testing.T) {
+	setup := func() (*PostService, *mocks.MockPostRepository) {
+		postRepo := new(mocks.MockPostRepository)
+		svc := newTestPostService(postRepo, new(mocks.MockUserRepository))
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(testutil.CreateTestPost("post-1", "user-1", models.PostTypeSell), nil)
+		postRepo.On("GetAttachmentsByPostID", mock.Anything, "post-1").Return([]*models.Attachment{
+			{ID: "att-a", PostID: "post-1", Photo: models.Photo{URL: "https://cdn.example.com/hamsaya-uploads/a.jpg"}, Position: 0},
+			{ID: "att-b", PostID: "post-1", Photo: models.Photo{URL: "https://cdn.example.com/hamsaya-uploads/b.jpg"}, Position: 1, Caption: testutil.StringPtr("back")},
+		}, nil).Maybe()
+		return svc, postRepo
+	}
+
+	t.Run("not owner", func(t *testing.T) {
+		svc, postRepo := setup()
+		_, err := svc.ReorderAttachments(context.Background(), "post-1", "user-2", []string{"att-b", "att-a"})
+		requireAppErrorCode(t, err, http.StatusForbidden)
+		postRepo.AssertNotCalled(t, "ReorderAttachments", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	for name, ids := range map[string][]string{
+		"missing an attachment": {"att-b"},
+		"duplicate":             {"att-b", "att-b"},
+		"foreign attachment":    {"att-b", "att-a", "att-z"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			svc, postRepo := setup()
+			_, err := svc.ReorderAttachments(context.Background(), "post-1", "user-1", ids)
+			requireAppErrorCode(t, err, http.StatusBadRequest)
+			postRepo.AssertNotCalled(t, "ReorderAttachments", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("new cover first", func(t *testing.T) {
+		svc, postRepo := setup()
+		postRepo.On("ReorderAttachments", mock.Anything, "post-1", []string{"att-b", "att-a"}).Return(nil)
+		out, err := svc.ReorderAttachments(context.Background(), "post-1", "user-1", []string{"att-b", "att-a"})
+		require.NoError(t, err)
+		require.Len(t, out, 2)
+		assert.Equal(t, "att-b", out[0].ID)
+		assert.Equal(t, 0, out[0].Position)
+		assert.Equal(t, "back", *out[0].Caption)
+		assert.Equal(t, 1, out[1].Position)
+	})
+}
+
+func TestPostService_UpdatePostAttachmentOrder(t *testing.T) {
+	postRepo := new(mocks.MockPostRepository)
+	userRepo := new(mocks.MockUserRepository)
+	svc := newTestPostService(postRepo, userRepo)
+
+	post := testutil.CreateTestPost("post-1", "user-1", models.PostTypeFeed)
+	postRepo.On("GetByID", mock.Anything, "post-1").Return(post, nil)
+	postRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	postRepo.On("CreateAttachment", mock.Anything, mock.MatchedBy(func(a *models.Attachment) bool {
+		return a.Photo.URL == "https://cdn.example.com/new.jpg" && a.Caption != nil && *a.Caption == "new one"
+	})).Return(nil)
+	postRepo.On("GetAttachmentsByPostID", mock.Anything, "post-1").Return([]*models.Attachment{
+		{ID: "att-a", PostID: "post-1", Position: 0},
+		{ID: "att-b", PostID: "post-1", Position: 1},
+		{ID: "att-new", PostID: "post-1", Position: 1 << 20},
+	}, nil)
+	postRepo.On("ReorderAttachments", mock.Anything, "post-1", []string{"att-b", "att-a", "att-new"}).Return(nil)
+	postRepo.On("SetAttachmentCaption", mock.Anything, "post-1", "att-a", (*string)(nil)).Return(nil)
+	postRepo.On("GetEngagementStatus", mock.Anything, "user-1", "post-1").Return(false, false, nil).Maybe()
+	userRepo.On("GetProfileByUserID", mock.Anything, "user-1").
+		Return(testutil.CreateTestProfile("user-1", "John", "Doe"), nil).Maybe()
+
+	_, err := svc.UpdatePost(context.Background(), "post-1", "user-1", &models.UpdatePostRequest{
+		Attachments:        []json.RawMessage{json.RawMessage(`{"url":"https://cdn.example.com/new.jpg","caption":" new one "}`)},
+		AttachmentOrder:    []string{"att-b"},
+		AttachmentCaptions: map[string]string{"att-a": "  "},
+	})
+	require.NoError(t, err)
+	postRepo.AssertExpectations(t)
+}
+
+func TestPostService_CreatePostRejectsLongCaption(t *testing.T) {
+	postRepo := new(mocks.MockPostRepository)
+	svc := newTestPostService(postRepo, new(mocks.MockUserRepository))
+
+	raw, _ := json.Marshal(map[string]string{
+		"url":     "https://cdn.example.com/a.jpg",
+		"caption": strings.Repeat("x", models.MaxAttachmentCaptionLength+1),
+	})
+	_, err := svc.CreatePost(context.Background(), "user-1", &models.CreatePostRequest{
+		Type:        models.PostTypeFeed,
+		Description: testutil.StringPtr("hello"),
+		Attachments: []json.RawMessage{raw},
+	})
+	requireAppErrorCode(t, err, http.StatusBadRequest)
+	postRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestPostService_CreatePostRejectsLongAltText(t *testing.T) {
+	postRepo := new(mocks.MockPostRepository)
+	svc := newTestPostService(postRepo, new(mocks.MockUserRepository))
+
+	raw, _ := json.Marshal(map[string]string{
+		"url":      "https://cdn.example.com/a.jpg",
+		"alt_text": strings.Repeat("x", models.MaxAltTextLength+1),
+	})
+	_, err := svc.CreatePost(context.Background(), "user-1", &models.CreatePostRequest{
+		Type:        models.PostTypeFeed,
+		Description: testutil.StringPtr("hello"),
+		Attachments: []json.RawMessage{raw},
+	})
+	requireAppErrorCode(t, err, http.StatusBadRequest)
+	postRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestPostService_UpdatePostAttachmentAltText(t *testing.T) {
+	postRepo := new(mocks.MockPostRepository)
+	userRepo := new(mocks.MockUserRepository)
+	svc := newTestPostService(postRepo, userRepo)
+
+	post := testutil.CreateTestPost("post-1", "user-1", models.PostTypeFeed)
+	postRepo.On("GetByID", mock.Anything, "post-1").Return(post, nil)
+	postRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	postRepo.On("CreateAttachment", mock.Anything, mock.MatchedBy(func(a *models.Attachment) bool {
+		return a.Photo.AltText == "A red bicycle"
+	})).Return(nil)
+	postRepo.On("GetAttachmentsByPostID", mock.Anything, "post-1").Return([]*models.Attachment{
+		{ID: "att-a", PostID: "post-1", Position: 0},
+		{ID: "att-new", PostID: "post-1", Position: 1 << 20},
+	}, nil)
+	postRepo.On("ReorderAttachments", mock.Anything, "post-1", []string{"att-a", "att-new"}).Return(nil)
+	postRepo.On("SetAttachmentAltText", mock.Anything, "post-1", "att-a", "").Return(nil)
+	postRepo.On("GetEngagementStatus", mock.Anything, "user-1", "post-1").Return(false, false, nil).Maybe()
+	userRepo.On("GetProfileByUserID", mock.Anything, "user-1").
+		Return(testutil.CreateTestProfile("user-1", "John", "Doe"), nil).Maybe()
+
+	_, err := svc.UpdatePost(context.Background(), "post-1", "user-1", &models.UpdatePostRequest{
+		Attachments:        []json.RawMessage{json.RawMessage(`{"url":"https://cdn.example.com/new.jpg","alt_text":" A red bicycle "}`)},
+		AttachmentAltTexts: map[string]string{"att-a": " "},
+	})
+	require.NoError(t, err)
+	postRepo.AssertExpectations(t)
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatService_SharePost(t *testing.T) {
+	t.Run("private post can't be shared", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		post := testutil.CreateTestPost("post-1", "owner-1", models.PostTypeFeed)
+		post.Visibility = models.VisibilityPrivate
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(post, nil)
+		svc := newTestChatService(&mocks.MockConversationRepository{}, &mocks.MockMessageRepository{}, new(mocks.MockUserRepository)).
+			WithPosts(postRepo)
+
+		_, err := svc.SharePost(context.Background(), "sender-1", "post-1", &models.ShareToChatRequest{RecipientIDs: []string{"recv-1"}})
+		requireAppErrCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("one bad recipient doesn't stop the rest", func(t *testing.T) {
+		convRepo := &mocks.MockConversationRepository{}
+		msgRepo := &mocks.MockMessageRepository{}
+		userRepo := new(mocks.MockUserRepository)
+		postRepo := new(mocks.MockPostRepository)
+
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(testutil.CreateTestPost("post-1", "owner-1", models.PostTypeFeed), nil)
+		postRepo.On("GetAttachmentsByPostID", mock.Anything, "post-1").Return([]*models.Attachment{}, nil)
+		convRepo.On("GetOrCreate", mock.Anything, "sender-1", "recv-1", mock.Anything).Return(newTestConversation("conv-1"), nil)
+		msgRepo.On("Create", mock.Anything, mock.MatchedBy(func(m *models.Message) bool {
+			return m.MessageType == models.MessageTypePost && m.SharedPostID != nil && *m.SharedPostID == "post-1"
+		})).Return(nil).Once()
+		convRepo.On("UpdateLastMessageAt", mock.Anything, "conv-1").Return(nil)
+		userRepo.On("GetProfileByUserID", mock.Anything, "sender-1").Return(&models.Profile{ID: "sender-1"}, nil)
+		msgRepo.On("GetReactions", mock.Anything, mock.Anything, mock.Anything).Return(map[string][]models.MessageReaction{}, nil).Maybe()
+
+		svc := newTestChatService(convRepo, msgRepo, userRepo).WithPosts(postRepo)
+		// sender-1 is also listed: sending to yourself fails on its own.
+		got, err := svc.SharePost(context.Background(), "sender-1", "post-1", &models.ShareToChatRequest{
+			RecipientIDs: []string{"recv-1", "sender-1", "recv-1"},
+		})
+		require.NoError(t, err)
+		require.Len(t, got.Sent, 1)
+		require.NotNil(t, got.Sent[0].SharedPost)
+		assert.Equal(t, "post-1", got.Sent[0].SharedPost.ID)
+		require.Len(t, got.Failed, 1)
+		assert.Equal(t, "sender-1", got.Failed[0].RecipientID)
+		msgRepo.AssertExpectations(t)
+	})
+
+	t.Run("clients can't send POST messages directly", func(t *testing.T) {
+		svc := newTestChatService(&mocks.MockConversationRepository{}, &mocks.MockMessageRepository{}, new(mocks.MockUserRepository))
+		_, err := svc.SendMessage(context.Background(), "sender-1", &models.SendMessageRequest{
+			RecipientID: "recv-1",
+			MessageType: models.MessageTypePost,
+		})
+		requireAppErrCode(t, err, http.StatusBadRequest)
+	})
+}
+
+func TestPostService_CreateExternalShare(t *testing.T) {
+	t.Run("retries a colliding code", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(testutil.CreateTestPost("post-1", "owner-1", models.PostTypeSell), nil)
+		postRepo.On("RecordExternalShare", mock.Anything, mock.Anything).
+			Return(nil, 0, repositories.ErrShareLinkCodeTaken).Once()
+		postRepo.On("RecordExternalShare", mock.Anything, mock.MatchedBy(func(l *models.PostShareLink) bool {
+			return l.Channel == models.ShareChannelOther && len(l.Code) == 8
+		})).Return(&models.PostShareLink{Code: "abcd1234", PostID: "post-1", Channel: models.ShareChannelOther}, 7, nil).Once()
+		svc := newTestPostService(postRepo, new(mocks.MockUserRepository)).
+			WithShareLinks("https://hamsaya.test/s", "")
+
+		got, err := svc.CreateExternalShare(context.Background(), "user-2", "post-1", "")
+		require.NoError(t, err)
+		assert.Equal(t, "https://hamsaya.test/s/abcd1234", got.URL)
+		assert.Equal(t, 7, got.ExternalShares)
+		postRepo.AssertExpectations(t)
+	})
+
+	t.Run("group posts can't leave the group", func(t *testing.T) {
+		postRepo := new(mocks.MockPostRepository)
+		post := testutil.CreateTestPost("post-1", "owner-1", models.PostTypeFeed)
+		groupID := "group-1"
+		post.GroupID = &groupID
+		postRepo.On("GetByID", mock.Anything, "post-1").Return(post, nil)
+		svc := newTestPostService(postRepo, new(mocks.MockUserRepository))
+
+		_, err := svc.CreateExternalShare(context.Background(), "user-2", "post-1", models.ShareChannelWhatsApp)
+		requireAppErrCode(t, err, http.StatusBadRequest)
+		postRepo.AssertNotCalled(t, "RecordExternalShare", mock.Anything, mock.Anything)
+	})
+}
+
+func TestPostService_ResolveShareLink(t *testing.T) {
+	postRepo := new(mocks.MockPostRepository)
+	postRepo.On("ResolveShareLink", mock.Anything, "abcd1234").Return(&models.PostShareLink{PostID: "post-1"}, nil)
+	postRepo.On("ResolveShareLink", mock.Anything, "missing").Return(nil, repositories.ErrShareLinkNotFound)
+	svc := newTestPostService(postRepo, new(mocks.MockUserRepository))
+
+	target, err := svc.ResolveShareLink(context.Background(), "abcd1234")
+	require.NoError(t, err)
+	assert.Equal(t, "https://hamsaya.af/posts/post-1", target)
+
+	_, err = svc.ResolveShareLink(context.Background(), "missing")
+	requireAppErrCode(t, err, http.StatusNotFound)
+}
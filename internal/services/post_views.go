@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const postViewDedupePrefix = "post_view:"
+
+// PostViewCounter counts post views, once per user per post per day. The
+// dedupe is a Redis key "post_view:{postID}:{userID}:{YYYY-MM-DD}" set with
+// SETNX; only the first view of the day reaches the post_daily_views
+// buckets that the owner's view count and the business insights read.
+//
+// Like the ad impression dedupe it fails open: a Redis error counts the
+// view rather than dropping it.
+//
+// A nil *PostViewCounter does nothing, so services work unwired.
+type PostViewCounter struct {
+	redis    *redis.Client
+	postRepo repositories.PostRepository
+	logger   *zap.Logger
+}
+
+// NewPostViewCounter creates the counter.
+func NewPostViewCounter(redisClient *redis.Client, postRepo repositories.PostRepository, logger *zap.Logger) *PostViewCounter {
+	return &PostViewCounter{
+		redis:    redisClient,
+		postRepo: postRepo,
+		logger:   logger,
+	}
+}
+
+// Record counts viewerID's view of the post unless they already viewed it
+// today.
+func (c *PostViewCounter) Record(ctx context.Context, postID, viewerID string) error {
+	if c == nil || !c.firstToday(ctx, postID, viewerID) {
+		return nil
+	}
+	return c.postRepo.IncrementDailyViews(ctx, viewerID, postID)
+}
+
+func (c *PostViewCounter) firstToday(ctx context.Context, postID, viewerID string) bool {
+	if c.redis == nil {
+		return true
+	}
+	key := postViewDedupePrefix + postID + ":" + viewerID + ":" + time.Now().UTC().Format("2006-01-02")
+	ok, err := c.redis.SetNX(ctx, key, "1", 24*time.Hour).Result()
+	if err != nil {
+		c.logger.Warn("Post view dedupe failed", zap.String("post_id", postID), zap.Error(err))
+		return true
+	}
+	return ok
+}
+
+// Count returns the post's total views.
+func (c *PostViewCounter) Count(ctx context.Context, postID string) (int, error) {
+	if c == nil {
+		return 0, nil
+	}
+	counts, err := c.postRepo.GetViewCounts(ctx, []string{postID})
+	if err != nil {
+		return 0, err
+	}
+	return counts[postID], nil
+}
+
+// ApplyToOwners sets ViewCount on the responses the viewer owns (IsMine:
+// their own posts and their business's posts). Nobody else sees it.
+func (c *PostViewCounter) ApplyToOwners(ctx context.Context, responses []*models.PostResponse) {
+	if c == nil {
+		return
+	}
+	var ids []string
+	for _, r := range responses {
+		if r != nil && r.IsMine {
+			ids = append(ids, r.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	counts, err := c.postRepo.GetViewCounts(ctx, ids)
+	if err != nil {
+		c.logger.Warn("Failed to get post view counts", zap.Error(err))
+		return
+	}
+	for _, r := range responses {
+		if r != nil && r.IsMine {
+			n := counts[r.ID]
+			r.ViewCount = &n
+		}
+	}
+}
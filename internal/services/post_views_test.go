@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPostViewCounter_Record(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	postRepo := new(mocks.MockPostRepository)
+	postRepo.On("IncrementDailyViews", ctx, "viewer-1", "post-1").Return(nil).Once()
+	postRepo.On("IncrementDailyViews", ctx, "viewer-2", "post-1").Return(nil).Once()
+	counter := NewPostViewCounter(rdb, postRepo, zap.NewNop())
+
+	// Repeat views by the same user on the same day count once.
+	require.NoError(t, counter.Record(ctx, "post-1", "viewer-1"))
+	require.NoError(t, counter.Record(ctx, "post-1", "viewer-1"))
+	require.NoError(t, counter.Record(ctx, "post-1", "viewer-2"))
+	postRepo.AssertExpectations(t)
+
+	// Redis down: fail open and count.
+	mr.Close()
+	postRepo.On("IncrementDailyViews", ctx, "viewer-1", "post-1").Return(nil).Once()
+	require.NoError(t, counter.Record(ctx, "post-1", "viewer-1"))
+	postRepo.AssertExpectations(t)
+}
+
+func TestPostViewCounter_ApplyToOwners(t *testing.T) {
+	ctx := context.Background()
+	postRepo := new(mocks.MockPostRepository)
+	postRepo.On("GetViewCounts", ctx, []string{"mine-1", "mine-2"}).
+		Return(map[string]int{"mine-1": 7}, nil)
+	counter := NewPostViewCounter(nil, postRepo, zap.NewNop())
+
+	responses := []*models.PostResponse{
+		{ID: "mine-1", IsMine: true},
+		{ID: "other", IsMine: false},
+		{ID: "mine-2", IsMine: true},
+	}
+	counter.ApplyToOwners(ctx, responses)
+
+	require.NotNil(t, responses[0].ViewCount)
+	assert.Equal(t, 7, *responses[0].ViewCount)
+	assert.Nil(t, responses[1].ViewCount)
+	require.NotNil(t, responses[2].ViewCount)
+	assert.Equal(t, 0, *responses[2].ViewCount)
+
+	var nilCounter *PostViewCounter
+	nilCounter.ApplyToOwners(ctx, responses)
+	assert.NoError(t, nilCounter.Record(ctx, "post-1", "viewer-1"))
+}
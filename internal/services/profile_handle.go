@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	// handleRenameCooldown is how long a handle stays put after a change.
+	// Setting the first handle is free.
+	handleRenameCooldown = 30 * 24 * time.Hour
+	// maxMentionsPerComment caps how many @handles in one comment are
+	// resolved (and notified).
+	maxMentionsPerComment = 20
+)
+
+// handlePattern matches the profiles.handle CHECK constraint.
+var handlePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{2,29}$`)
+
+// mentionPattern finds @handle tokens not preceded by a word character, so
+// email addresses aren't read as mentions. The captured name is validated
+// separately.
+var mentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_@.])@([A-Za-z0-9_]+)`)
+
+// reservedHandles can't be claimed: they impersonate staff or the app, or
+// collide with client routes and mention keywords.
+var reservedHandles = map[string]bool{
+	"admin": true, "administrator": true, "api": true, "app": true,
+	"everyone": true, "hamsaya": true, "help": true, "here": true,
+	"info": true, "me": true, "mod": true, "moderator": true,
+	"null": true, "official": true, "root": true, "security": true,
+	"settings": true, "staff": true, "support": true, "system": true,
+	"team": true, "undefined": true, "user": true, "users": true,
+}
+
+// normalizeHandle lowercases a handle and strips surrounding spaces and a
+// leading @.
+func normalizeHandle(raw string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(raw), "@"))
+}
+
+// validateHandle checks a normalized handle's shape and the reserved list.
+func validateHandle(handle string) error {
+	if !handlePattern.MatchString(handle) {
+		return utils.NewBadRequestError("Handle must be 3-30 characters: a letter followed by letters, digits or underscores", nil)
+	}
+	if reservedHandles[handle] || strings.HasPrefix(handle, "hamsaya") {
+		return utils.NewBadRequestError("This handle is reserved", nil)
+	}
+	return nil
+}
+
+// handleChangeableAt is when the profile's handle can next be changed, or
+// nil if it can be changed now.
+func handleChangeableAt(profile *models.Profile) *time.Time {
+	if profile.HandleChangedAt == nil {
+		return nil
+	}
+	at := profile.HandleChangedAt.Add(handleRenameCooldown)
+	if !at.After(time.Now()) {
+		return nil
+	}
+	return &at
+}
+
+// SetHandle claims a new @handle for the user, subject to the rename
+// cooldown, and returns the updated profile.
+func (s *ProfileService) SetHandle(ctx context.Context, userID string, req *models.UpdateHandleRequest) (*models.FullProfileResponse, error) {
+	handle := normalizeHandle(req.Handle)
+	if err := validateHandle(handle); err != nil {
+		return nil, err
+	}
+
+	profile, err := s.userRepo.GetProfileByUserID(ctx, userID)
+	if err != nil {
+		return nil, utils.NewNotFoundError("Profile not found", err)
+	}
+	if profile.Handle != nil && *profile.Handle == handle {
+		return s.GetProfile(ctx, userID, &userID)
+	}
+	if at := handleChangeableAt(profile); at != nil {
+		return nil, utils.NewTooManyRequestsError("You can change your handle again on "+at.Format("2006-01-02"), nil)
+	}
+
+	if err := s.userRepo.SetProfileHandle(ctx, userID, handle, time.Now().Add(-handleRenameCooldown)); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrHandleTaken):
+			return nil, utils.NewConflictError("This handle is already taken", err)
+		case errors.Is(err, repositories.ErrHandleCooldown):
+			return nil, utils.NewTooManyRequestsError("You changed your handle too recently", err)
+		}
+		s.logger.Error("Failed to set handle", zap.String("user_id", userID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to update handle", err)
+	}
+
+	s.logger.Info("Handle updated", zap.String("user_id", userID), zap.String("handle", handle))
+	return s.GetProfile(ctx, userID, &userID)
+}
+
+// GetProfileByHandle gets a user's profile by @handle.
+func (s *ProfileService) GetProfileByHandle(ctx context.Context, handle string, viewerID *string) (*models.FullProfileResponse, error) {
+	handle = normalizeHandle(handle)
+	if !handlePattern.MatchString(handle) {
+		return nil, utils.NewNotFoundError("User not found", nil)
+	}
+	userID, err := s.userRepo.GetUserIDByHandle(ctx, handle)
+	if err != nil {
+		if errors.Is(err, repositories.ErrHandleNotFound) {
+			return nil, utils.NewNotFoundError("User not found", err)
+		}
+		s.logger.Error("Failed to look up handle", zap.String("handle", handle), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to get profile", err)
+	}
+	return s.GetProfile(ctx, userID, viewerID)
+}
+
+// extractHandleMentions returns the distinct valid @handles in text, in the
+// order they first appear, normalized.
+func extractHandleMentions(text string) []string {
+	var handles []string
+	seen := make(map[string]bool)
+	for _, m := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		handle := strings.ToLower(m[1])
+		if seen[handle] || !handlePattern.MatchString(handle) {
+			continue
+		}
+		seen[handle] = true
+		handles = append(handles, handle)
+		if len(handles) == maxMentionsPerComment {
+			break
+		}
+	}
+	return handles
+}
+
+// resolveMentions returns the users mentioned in text by @handle, in text
+// order, followed by any explicitly tagged users not already mentioned.
+// Unknown handles are dropped; a failed lookup falls back to the tagged
+// users alone.
+func resolveMentions(ctx context.Context, userRepo repositories.UserRepository, logger *zap.Logger, text string, taggedUserIDs []string) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	if handles := extractHandleMentions(text); len(handles) > 0 {
+		byHandle, err := userRepo.GetUserIDsByHandles(ctx, handles)
+		if err != nil {
+			logger.Warn("Failed to resolve mentioned handles", zap.Error(err))
+		}
+		for _, handle := range handles {
+			add(byHandle[handle])
+		}
+	}
+	for _, id := range taggedUserIDs {
+		add(id)
+	}
+	return ids
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestValidateHandle(t *testing.T) {
+	for _, handle := range []string{"ali", "ali_rahimi", "a1234567890123456789012345678x"} {
+		assert.NoError(t, validateHandle(handle), handle)
+	}
+	for _, handle := range []string{"al", "1ali", "_ali", "ali-r", "ali.r", "a12345678901234567890123456789x", "admin", "hamsaya_team"} {
+		assert.Error(t, validateHandle(handle), handle)
+	}
+	assert.Equal(t, "ali_r", normalizeHandle("  @Ali_R "))
+}
+
+func TestExtractHandleMentions(t *testing.T) {
+	handles := extractHandleMentions("@Sara thanks! cc @ali, @sara and mail me at me@example.com or @x")
+	assert.Equal(t, []string{"sara", "ali"}, handles)
+}
+
+func TestProfileService_SetHandle(t *testing.T) {
+	ctx := context.Background()
+	req := &models.UpdateHandleRequest{Handle: "@Ali_R"}
+
+	t.Run("reserved", func(t *testing.T) {
+		svc := newTestProfileService(new(mocks.MockUserRepository), new(mocks.MockPostRepository), new(mocks.MockRelationshipsRepository))
+		_, err := svc.SetHandle(ctx, "user-1", &models.UpdateHandleRequest{Handle: "support"})
+		requireAppErrCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("rename cooldown", func(t *testing.T) {
+		userRepo := new(mocks.MockUserRepository)
+		profile := testutil.CreateTestProfile("user-1", "Ali", "Rahimi")
+		old, changedAt := "ali", time.Now().Add(-24*time.Hour)
+		profile.Handle, profile.HandleChangedAt = &old, &changedAt
+		userRepo.On("GetProfileByUserID", mock.Anything, "user-1").Return(profile, nil)
+		svc := newTestProfileService(userRepo, new(mocks.MockPostRepository), new(mocks.MockRelationshipsRepository))
+
+		_, err := svc.SetHandle(ctx, "user-1", req)
+		requireAppErrCode(t, err, http.StatusTooManyRequests)
+		userRepo.AssertNotCalled(t, "SetProfileHandle", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("taken", func(t *testing.T) {
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetProfileByUserID", mock.Anything, "user-1").Return(testutil.CreateTestProfile("user-1", "Ali", "Rahimi"), nil)
+		userRepo.On("SetProfileHandle", mock.Anything, "user-1", "ali_r", mock.Anything).Return(repositories.ErrHandleTaken)
+		svc := newTestProfileService(userRepo, new(mocks.MockPostRepository), new(mocks.MockRelationshipsRepository))
+
+		_, err := svc.SetHandle(ctx, "user-1", req)
+		requireAppErrCode(t, err, http.StatusConflict)
+	})
+
+	t.Run("first handle", func(t *testing.T) {
+		userRepo := new(mocks.MockUserRepository)
+		postRepo := new(mocks.MockPostRepository)
+		relRepo := new(mocks.MockRelationshipsRepository)
+		profile := testutil.CreateTestProfile("user-1", "Ali", "Rahimi")
+		userRepo.On("GetProfileByUserID", mock.Anything, "user-1").Return(profile, nil).Once()
+		userRepo.On("SetProfileHandle", mock.Anything, "user-1", "ali_r", mock.MatchedBy(func(before time.Time) bool {
+			return time.Since(before) >= handleRenameCooldown-time.Minute
+		})).Return(nil)
+
+		handle, changedAt := "ali_r", time.Now()
+		updated := testutil.CreateTestProfile("user-1", "Ali", "Rahimi")
+		updated.Handle, updated.HandleChangedAt = &handle, &changedAt
+		userRepo.On("GetByID", mock.Anything, "user-1").Return(testutil.CreateTestUser("user-1", "ali@example.com"), nil)
+		userRepo.On("GetProfileByUserID", mock.Anything, "user-1").Return(updated, nil)
+		relRepo.On("GetFollowersCount", mock.Anything, "user-1").Return(0, nil)
+		relRepo.On("GetFollowingCount", mock.Anything, "user-1").Return(0, nil)
+		postRepo.On("CountPostsByUser", mock.Anything, "user-1").Return(0, nil)
+		svc := newTestProfileService(userRepo, postRepo, relRepo)
+
+		resp, err := svc.SetHandle(ctx, "user-1", req)
+		require.NoError(t, err)
+		assert.Equal(t, &handle, resp.Handle)
+		require.NotNil(t, resp.HandleChangeableAt)
+		assert.WithinDuration(t, changedAt.Add(handleRenameCooldown), *resp.HandleChangeableAt, time.Second)
+	})
+}
+
+func TestProfileService_GetProfileByHandle(t *testing.T) {
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	userRepo.On("GetUserIDByHandle", mock.Anything, "nobody").Return("", repositories.ErrHandleNotFound)
+	svc := newTestProfileService(userRepo, new(mocks.MockPostRepository), new(mocks.MockRelationshipsRepository))
+
+	_, err := svc.GetProfileByHandle(ctx, "@nobody", nil)
+	requireAppErrCode(t, err, http.StatusNotFound)
+
+	_, err = svc.GetProfileByHandle(ctx, "not a handle", nil)
+	requireAppErrCode(t, err, http.StatusNotFound)
+}
+
+func TestResolveMentions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("handles in text order, then tagged users", func(t *testing.T) {
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetUserIDsByHandles", mock.Anything, []string{"sara", "ghost", "ali"}).
+			Return(map[string]string{"sara": "user-2", "ali": "user-3"}, nil)
+
+		ids := resolveMentions(ctx, userRepo, zap.NewNop(), "@sara @ghost @ali", []string{"user-4", "user-3"})
+		assert.Equal(t, []string{"user-2", "user-3", "user-4"}, ids)
+	})
+
+	t.Run("lookup failure keeps tagged users", func(t *testing.T) {
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetUserIDsByHandles", mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+
+		ids := resolveMentions(ctx, userRepo, zap.NewNop(), "hi @sara", []string{"user-4"})
+		assert.Equal(t, []string{"user-4"}, ids)
+	})
+
+	t.Run("no handles, no lookup", func(t *testing.T) {
+		userRepo := new(mocks.MockUserRepository)
+		assert.Empty(t, resolveMentions(ctx, userRepo, zap.NewNop(), "hello", nil))
+		userRepo.AssertNotCalled(t, "GetUserIDsByHandles", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// autoApprovalNote is recorded on reviews the image scanner cleared.
+const autoApprovalNote = "Scored safe by the image scanner"
+
+// ProfileMediaService holds new avatars and covers for review when profile
+// media moderation is on. The owner keeps the old image until an admin
+// approves the new one, or the scanner scores it safe.
+type ProfileMediaService struct {
+	repo       repositories.ProfileMediaRepository
+	hold       bool
+	users      profileMediaTarget
+	businesses profileMediaTarget
+	logger     *zap.Logger
+}
+
+// profileMediaTarget puts an approved avatar or cover on its owner.
+type profileMediaTarget interface {
+	ApplyReviewedMedia(ctx context.Context, ownerID, kind string, photo models.Photo) error
+}
+
+// NewProfileMediaService creates a new profile media service. With hold
+// off, uploads go live right away and only the queue endpoints work.
+func NewProfileMediaService(repo repositories.ProfileMediaRepository, hold bool, logger *zap.Logger) *ProfileMediaService {
+	return &ProfileMediaService{repo: repo, hold: hold, logger: logger}
+}
+
+// WithTargets sets where approved user and business images are applied.
+func (s *ProfileMediaService) WithTargets(users, businesses profileMediaTarget) *ProfileMediaService {
+	s.users = users
+	s.businesses = businesses
+	return s
+}
+
+// Holds reports whether new avatars and covers wait for review.
+func (s *ProfileMediaService) Holds() bool {
+	return s != nil && s.hold
+}
+
+// Submit holds photo as the owner's next avatar or cover, replacing any
+// image of that kind still waiting.
+func (s *ProfileMediaService) Submit(ctx context.Context, ownerType, ownerID, kind string, photo models.Photo) error {
+	review := &models.ProfileMediaReview{OwnerType: ownerType, OwnerID: ownerID, Kind: kind, Photo: photo}
+	if err := s.repo.Submit(ctx, review); err != nil {
+		s.logger.Error("Failed to hold profile media for review",
+			zap.String("owner_type", ownerType), zap.String("owner_id", ownerID), zap.Error(err))
+		return utils.NewInternalError("Failed to upload image", err)
+	}
+	return nil
+}
+
+// Cancel drops the owner's image of kind still waiting for review, so a
+// removed avatar isn't brought back by a later approval. Best effort.
+func (s *ProfileMediaService) Cancel(ctx context.Context, ownerType, ownerID, kind string) {
+	if s == nil {
+		return
+	}
+	if err := s.repo.CancelPending(ctx, ownerType, ownerID, kind); err != nil {
+		s.logger.Warn("Failed to cancel pending profile media",
+			zap.String("owner_type", ownerType), zap.String("owner_id", ownerID), zap.Error(err))
+	}
+}
+
+// List returns held images, optionally by status and flagged only.
+func (s *ProfileMediaService) List(ctx context.Context, status string, flaggedOnly bool, limit int) ([]*models.ProfileMediaReview, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	reviews, err := s.repo.List(ctx, status, flaggedOnly, limit)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to list profile media", err)
+	}
+	return reviews, nil
+}
+
+// Counts returns the size of each status bucket of the queue.
+func (s *ProfileMediaService) Counts(ctx context.Context) (map[string]int64, error) {
+	counts, err := s.repo.Counts(ctx)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to count profile media", err)
+	}
+	return counts, nil
+}
+
+// Approve puts a held image on its owner.
+func (s *ProfileMediaService) Approve(ctx context.Context, id, adminID, notes string) error {
+	return s.approve(ctx, id, &adminID, notes)
+}
+
+// AutoApprove approves a held image the scanner scored safe.
+func (s *ProfileMediaService) AutoApprove(ctx context.Context, id string) error {
+	return s.approve(ctx, id, nil, autoApprovalNote)
+}
+
+func (s *ProfileMediaService) approve(ctx context.Context, id string, reviewerID *string, notes string) error {
+	review, err := s.pending(ctx, id)
+	if err != nil {
+		return err
+	}
+	target := s.users
+	if review.OwnerType == models.ProfileMediaOwnerBusiness {
+		target = s.businesses
+	}
+	if target == nil {
+		return utils.NewInternalError("Profile media moderation is not configured", nil)
+	}
+
+	// Apply first: if that fails the review stays pending and can be
+	// retried.
+	if err := target.ApplyReviewedMedia(ctx, review.OwnerID, review.Kind, review.Photo); err != nil {
+		return err
+	}
+	if err := s.resolve(ctx, id, "approved", reviewerID, notes); err != nil {
+		return err
+	}
+	s.logger.Info("Profile media approved",
+		zap.String("review_id", id), zap.String("owner_type", review.OwnerType),
+		zap.String("owner_id", review.OwnerID), zap.Bool("automatic", reviewerID == nil))
+	return nil
+}
+
+// Reject discards a held image; the owner keeps their current one.
+func (s *ProfileMediaService) Reject(ctx context.Context, id, adminID, notes string) error {
+	if notes == "" {
+		return utils.NewBadRequestError("Rejection requires notes", nil)
+	}
+	if _, err := s.pending(ctx, id); err != nil {
+		return err
+	}
+	return s.resolve(ctx, id, "rejected", &adminID, notes)
+}
+
+// pending loads a review that is still waiting.
+func (s *ProfileMediaService) pending(ctx context.Context, id string) (*models.ProfileMediaReview, error) {
+	review, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrProfileMediaNotFound) {
+			return nil, utils.NewNotFoundError("Review not found", err)
+		}
+		return nil, utils.NewInternalError("Failed to get review", err)
+	}
+	if review.Status != "pending" {
+		return nil, utils.NewBadRequestError("Review is already "+review.Status, nil)
+	}
+	return review, nil
+}
+
+func (s *ProfileMediaService) resolve(ctx context.Context, id, status string, reviewerID *string, notes string) error {
+	if err := s.repo.Resolve(ctx, id, status, reviewerID, notes); err != nil {
+		if errors.Is(err, repositories.ErrProfileMediaNotPending) {
+			return utils.NewBadRequestError("Review is no longer pending", err)
+		}
+		return utils.NewInternalError("Failed to resolve review", err)
+	}
+	return nil
+}
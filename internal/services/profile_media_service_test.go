@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestProfileService_UpdateAvatarHeldForReview(t *testing.T) {
+	ctx := context.Background()
+	photo := &models.Photo{URL: "https://cdn.example.com/avatars/new.jpg"}
+
+	t.Run("hold submits instead of updating", func(t *testing.T) {
+		repo := new(mocks.MockProfileMediaRepository)
+		repo.On("Submit", mock.Anything, mock.MatchedBy(func(r *models.ProfileMediaReview) bool {
+			return r.OwnerType == models.ProfileMediaOwnerUser && r.OwnerID == "user-1" &&
+				r.Kind == models.ProfileMediaAvatar && r.Photo.URL == photo.URL
+		})).Return(nil)
+		userRepo := new(mocks.MockUserRepository)
+		svc := newTestProfileService(userRepo, new(mocks.MockPostRepository), new(mocks.MockRelationshipsRepository)).
+			WithMediaReview(NewProfileMediaService(repo, true, zap.NewNop()))
+
+		require.NoError(t, svc.UpdateAvatar(ctx, "user-1", photo))
+		assert.True(t, svc.MediaHeldForReview())
+		repo.AssertExpectations(t)
+		userRepo.AssertNotCalled(t, "UpdateProfile", mock.Anything, mock.Anything)
+	})
+
+	t.Run("no hold goes live", func(t *testing.T) {
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetProfileByUserID", mock.Anything, "user-1").Return(testutil.CreateTestProfile("user-1", "Ali", "Rahimi"), nil)
+		userRepo.On("UpdateProfile", mock.Anything, mock.MatchedBy(func(p *models.Profile) bool {
+			return p.Avatar == photo
+		})).Return(nil)
+		repo := new(mocks.MockProfileMediaRepository)
+		svc := newTestProfileService(userRepo, new(mocks.MockPostRepository), new(mocks.MockRelationshipsRepository)).
+			WithMediaReview(NewProfileMediaService(repo, false, zap.NewNop()))
+
+		require.NoError(t, svc.UpdateAvatar(ctx, "user-1", photo))
+		assert.False(t, svc.MediaHeldForReview())
+		repo.AssertNotCalled(t, "Submit", mock.Anything, mock.Anything)
+	})
+}
+
+func TestProfileMediaService_Review(t *testing.T) {
+	ctx := context.Background()
+	pending := func(ownerType string) *models.ProfileMediaReview {
+		return &models.ProfileMediaReview{
+			ID: "pm-1", OwnerType: ownerType, OwnerID: "owner-1", Kind: models.ProfileMediaCover,
+			Photo: models.Photo{URL: "https://cdn.example.com/covers/new.jpg"}, Status: "pending",
+		}
+	}
+
+	t.Run("approve applies the cover to the user", func(t *testing.T) {
+		repo := new(mocks.MockProfileMediaRepository)
+		repo.On("GetByID", mock.Anything, "pm-1").Return(pending(models.ProfileMediaOwnerUser), nil)
+		repo.On("Resolve", mock.Anything, "pm-1", "approved", mock.MatchedBy(func(id *string) bool {
+			return id != nil && *id == "admin-1"
+		}), "").Return(nil)
+		userRepo := new(mocks.MockUserRepository)
+		userRepo.On("GetProfileByUserID", mock.Anything, "owner-1").Return(testutil.CreateTestProfile("owner-1", "Ali", "Rahimi"), nil)
+		userRepo.On("UpdateProfile", mock.Anything, mock.MatchedBy(func(p *models.Profile) bool {
+			return p.Cover != nil && p.Cover.URL == "https://cdn.example.com/covers/new.jpg"
+		})).Return(nil)
+		users := newTestProfileService(userRepo, new(mocks.MockPostRepository), new(mocks.MockRelationshipsRepository))
+		svc := NewProfileMediaService(repo, true, zap.NewNop()).WithTargets(users, nil)
+
+		require.NoError(t, svc.Approve(ctx, "pm-1", "admin-1", ""))
+		userRepo.AssertExpectations(t)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("auto-approve applies the cover to the business", func(t *testing.T) {
+		repo := new(mocks.MockProfileMediaRepository)
+		repo.On("GetByID", mock.Anything, "pm-1").Return(pending(models.ProfileMediaOwnerBusiness), nil)
+		repo.On("Resolve", mock.Anything, "pm-1", "approved", (*string)(nil), autoApprovalNote).Return(nil)
+		businessRepo := new(mocks.MockBusinessRepository)
+		businessRepo.On("GetByID", mock.Anything, "owner-1").Return(&models.BusinessProfile{ID: "owner-1"}, nil)
+		businessRepo.On("Update", mock.Anything, mock.MatchedBy(func(b *models.BusinessProfile) bool {
+			return b.Cover != nil && b.Cover.URL == "https://cdn.example.com/covers/new.jpg"
+		})).Return(nil)
+		businesses := newTestBusinessService(businessRepo, new(mocks.MockUserRepository))
+		svc := NewProfileMediaService(repo, true, zap.NewNop()).WithTargets(nil, businesses)
+
+		require.NoError(t, svc.AutoApprove(ctx, "pm-1"))
+		businessRepo.AssertExpectations(t)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("reject requires notes", func(t *testing.T) {
+		svc := NewProfileMediaService(new(mocks.MockProfileMediaRepository), true, zap.NewNop())
+		requireAppErrCode(t, svc.Reject(ctx, "pm-1", "admin-1", ""), http.StatusBadRequest)
+	})
+
+	t.Run("resolved reviews can't be approved again", func(t *testing.T) {
+		review := pending(models.ProfileMediaOwnerUser)
+		review.Status = "superseded"
+		repo := new(mocks.MockProfileMediaRepository)
+		repo.On("GetByID", mock.Anything, "pm-1").Return(review, nil)
+		repo.On("GetByID", mock.Anything, "pm-2").Return(nil, repositories.ErrProfileMediaNotFound)
+		svc := NewProfileMediaService(repo, true, zap.NewNop())
+
+		requireAppErrCode(t, svc.Approve(ctx, "pm-1", "admin-1", ""), http.StatusBadRequest)
+		requireAppErrCode(t, svc.Reject(ctx, "pm-2", "admin-1", "nudity"), http.StatusNotFound)
+		repo.AssertNotCalled(t, "Resolve", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// ProfilePrivacy applies users' privacy settings to what other users see of
+// them: the profile page and the author block on posts. The owner always
+// sees everything. Anonymous viewers never see email or phone, whatever the
+// setting, and only EVERYONE fields otherwise.
+//
+// A nil *ProfilePrivacy applies the defaults, so services work unwired.
+type ProfilePrivacy struct {
+	privacyRepo       repositories.PrivacyRepository
+	relationshipsRepo repositories.RelationshipsRepository
+	logger            *zap.Logger
+}
+
+// NewProfilePrivacy creates the privacy policy.
+func NewProfilePrivacy(
+	privacyRepo repositories.PrivacyRepository,
+	relationshipsRepo repositories.RelationshipsRepository,
+	logger *zap.Logger,
+) *ProfilePrivacy {
+	return &ProfilePrivacy{
+		privacyRepo:       privacyRepo,
+		relationshipsRepo: relationshipsRepo,
+		logger:            logger,
+	}
+}
+
+// visibleFields is what a particular viewer may see of a profile.
+type visibleFields struct {
+	Location bool
+	Email    bool
+	Phone    bool
+}
+
+// GetSettings returns the user's privacy settings.
+func (p *ProfilePrivacy) GetSettings(ctx context.Context, userID string) (*models.PrivacySettings, error) {
+	settings, err := p.privacyRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to get privacy settings", err)
+	}
+	return settings, nil
+}
+
+// UpdateSettings changes the fields set in req.
+func (p *ProfilePrivacy) UpdateSettings(ctx context.Context, userID string, req *models.UpdatePrivacySettingsRequest) (*models.PrivacySettings, error) {
+	settings, err := p.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.LocationVisibility != nil {
+		settings.LocationVisibility = *req.LocationVisibility
+	}
+	if req.EmailVisibility != nil {
+		settings.EmailVisibility = *req.EmailVisibility
+	}
+	if req.PhoneVisibility != nil {
+		settings.PhoneVisibility = *req.PhoneVisibility
+	}
+	if req.ProfileViewVisibility != nil {
+		settings.ProfileViewVisibility = *req.ProfileViewVisibility
+	}
+	if err := p.privacyRepo.Upsert(ctx, settings); err != nil {
+		return nil, utils.NewInternalError("Failed to update privacy settings", err)
+	}
+	return settings, nil
+}
+
+// Visible returns which fields of ownerID's profile viewerID may see.
+// Lookup failures fall back to the defaults.
+func (p *ProfilePrivacy) Visible(ctx context.Context, ownerID string, viewerID *string) visibleFields {
+	viewer := stringOrEmpty(viewerID)
+	if viewer == ownerID {
+		return visibleFields{Location: true, Email: true, Phone: true}
+	}
+
+	settings := models.DefaultPrivacySettings(ownerID)
+	if p != nil {
+		if stored, err := p.privacyRepo.Get(ctx, ownerID); err != nil {
+			p.logger.Warn("Failed to get privacy settings", zap.String("user_id", ownerID), zap.Error(err))
+		} else {
+			settings = stored
+		}
+	}
+
+	var follows *bool
+	allowed := func(audience models.PrivacyAudience) bool {
+		switch audience {
+		case models.AudienceEveryone:
+			return true
+		case models.AudienceFollowers:
+			if follows == nil {
+				f := p.follows(ctx, viewer, ownerID)
+				follows = &f
+			}
+			return *follows
+		}
+		return false
+	}
+
+	return visibleFields{
+		Location: allowed(settings.LocationVisibility),
+		Email:    viewer != "" && allowed(settings.EmailVisibility),
+		Phone:    viewer != "" && allowed(settings.PhoneVisibility),
+	}
+}
+
+// ApplyToAuthors hides the location of post authors who don't share it
+// with the viewer. One settings lookup for the whole batch; follow checks
+// only for authors limited to followers.
+func (p *ProfilePrivacy) ApplyToAuthors(ctx context.Context, responses []*models.PostResponse, viewerID *string) {
+	if p == nil {
+		return
+	}
+	viewer := stringOrEmpty(viewerID)
+
+	var authorIDs []string
+	seen := make(map[string]bool)
+	for _, r := range responses {
+		if r == nil || r.Author == nil || r.Author.UserID == viewer || seen[r.Author.UserID] {
+			continue
+		}
+		seen[r.Author.UserID] = true
+		authorIDs = append(authorIDs, r.Author.UserID)
+	}
+	if len(authorIDs) == 0 {
+		return
+	}
+
+	settings, err := p.privacyRepo.GetMany(ctx, authorIDs)
+	if err != nil {
+		p.logger.Warn("Failed to get author privacy settings", zap.Error(err))
+		return
+	}
+
+	hidden := make(map[string]bool)
+	for authorID, s := range settings {
+		switch s.LocationVisibility {
+		case models.AudienceNobody:
+			hidden[authorID] = true
+		case models.AudienceFollowers:
+			hidden[authorID] = !p.follows(ctx, viewer, authorID)
+		}
+	}
+
+	for _, r := range responses {
+		if r != nil && r.Author != nil && hidden[r.Author.UserID] {
+			r.Author.Province = nil
+			r.Author.District = nil
+			r.Author.Neighborhood = nil
+		}
+	}
+}
+
+// follows reports whether viewer follows ownerID. False for anonymous
+// viewers and on lookup failure.
+func (p *ProfilePrivacy) follows(ctx context.Context, viewer, ownerID string) bool {
+	if p == nil || viewer == "" {
+		return false
+	}
+	following, err := p.relationshipsRepo.IsFollowing(ctx, viewer, ownerID)
+	if err != nil {
+		p.logger.Warn("Failed to check follow for privacy",
+			zap.String("viewer_id", viewer),
+			zap.String("user_id", ownerID),
+			zap.Error(err),
+		)
+		return false
+	}
+	return following
+}
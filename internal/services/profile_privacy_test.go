@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestProfileService_GetProfile_Privacy(t *testing.T) {
+	setup := func(settings *models.PrivacySettings) (*ProfileService, *mocks.MockRelationshipsRepository) {
+		userRepo := new(mocks.MockUserRepository)
+		postRepo := new(mocks.MockPostRepository)
+		relRepo := new(mocks.MockRelationshipsRepository)
+		privacyRepo := new(mocks.MockPrivacyRepository)
+
+		user := testutil.CreateTestUser("user-1", "owner@example.com")
+		user.Phone = testutil.StringPtr("700000000")
+		profile := testutil.CreateTestProfile("user-1", "Test", "User")
+		profile.Province = testutil.StringPtr("Kabul")
+		profile.District = testutil.StringPtr("District 4")
+		userRepo.On("GetByID", mock.Anything, "user-1").Return(user, nil)
+		userRepo.On("GetProfileByUserID", mock.Anything, "user-1").Return(profile, nil)
+		relRepo.On("GetFollowersCount", mock.Anything, "user-1").Return(0, nil)
+		relRepo.On("GetFollowingCount", mock.Anything, "user-1").Return(0, nil)
+		postRepo.On("CountPostsByUser", mock.Anything, "user-1").Return(0, nil)
+		relRepo.On("GetRelationshipStatus", mock.Anything, "viewer-1", "user-1").Return(&models.RelationshipStatus{}, nil)
+		privacyRepo.On("Get", mock.Anything, "user-1").Return(settings, nil)
+
+		svc := newTestProfileService(userRepo, postRepo, relRepo).
+			WithPrivacy(NewProfilePrivacy(privacyRepo, relRepo, zap.NewNop()))
+		return svc, relRepo
+	}
+	viewer := testutil.StringPtr("viewer-1")
+
+	t.Run("defaults keep location and hide contact info", func(t *testing.T) {
+		svc, _ := setup(models.DefaultPrivacySettings("user-1"))
+		resp, err := svc.GetProfile(context.Background(), "user-1", viewer)
+		require.NoError(t, err)
+		assert.Equal(t, "Kabul", *resp.Province)
+		assert.Empty(t, resp.Email)
+		assert.Nil(t, resp.Phone)
+	})
+
+	t.Run("location for followers only", func(t *testing.T) {
+		settings := models.DefaultPrivacySettings("user-1")
+		settings.LocationVisibility = models.AudienceFollowers
+		settings.EmailVisibility = models.AudienceEveryone
+
+		svc, relRepo := setup(settings)
+		relRepo.On("IsFollowing", mock.Anything, "viewer-1", "user-1").Return(false, nil)
+		resp, err := svc.GetProfile(context.Background(), "user-1", viewer)
+		require.NoError(t, err)
+		assert.Nil(t, resp.Province)
+		assert.Nil(t, resp.District)
+		assert.Equal(t, "owner@example.com", resp.Email)
+
+		svc, relRepo = setup(settings)
+		relRepo.On("IsFollowing", mock.Anything, "viewer-1", "user-1").Return(true, nil)
+		resp, err = svc.GetProfile(context.Background(), "user-1", viewer)
+		require.NoError(t, err)
+		assert.Equal(t, "Kabul", *resp.Province)
+	})
+
+	t.Run("anonymous viewers never get contact info", func(t *testing.T) {
+		settings := models.DefaultPrivacySettings("user-1")
+		settings.EmailVisibility = models.AudienceEveryone
+		settings.PhoneVisibility = models.AudienceEveryone
+
+		svc, _ := setup(settings)
+		resp, err := svc.GetProfile(context.Background(), "user-1", nil)
+		require.NoError(t, err)
+		assert.Empty(t, resp.Email)
+		assert.Nil(t, resp.Phone)
+	})
+}
+
+func TestProfilePrivacy_ApplyToAuthors(t *testing.T) {
+	privacyRepo := new(mocks.MockPrivacyRepository)
+	relRepo := new(mocks.MockRelationshipsRepository)
+	hidden := models.DefaultPrivacySettings("author-1")
+	hidden.LocationVisibility = models.AudienceNobody
+	privacyRepo.On("GetMany", mock.Anything, []string{"author-1", "author-2"}).
+		Return(map[string]*models.PrivacySettings{"author-1": hidden}, nil)
+
+	author := func(id string) *models.PostResponse {
+		return &models.PostResponse{Author: &models.AuthorInfo{UserID: id, Province: testutil.StringPtr("Herat")}}
+	}
+	posts := []*models.PostResponse{author("author-1"), author("author-2"), author("viewer-1"), author("author-1")}
+
+	NewProfilePrivacy(privacyRepo, relRepo, zap.NewNop()).
+		ApplyToAuthors(context.Background(), posts, testutil.StringPtr("viewer-1"))
+
+	assert.Nil(t, posts[0].Author.Province)
+	assert.NotNil(t, posts[1].Author.Province)
+	assert.NotNil(t, posts[2].Author.Province)
+	assert.Nil(t, posts[3].Author.Province)
+	privacyRepo.AssertExpectations(t)
+}
@@ -2,11 +2,16 @@ package services
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/repositories"
 	"github.com/hamsaya/backend/internal/utils"
+	"github.com/hamsaya/backend/pkg/bgtasks"
+	"github.com/hamsaya/backend/pkg/geocoding"
+	"github.com/hamsaya/backend/pkg/langdetect"
 	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
 )
@@ -17,6 +22,12 @@ type ProfileService struct {
 	postRepo          repositories.PostRepository
 	commentRepo       repositories.CommentRepository
 	relationshipsRepo repositories.RelationshipsRepository
+	geocoder          geocoding.ReverseGeocoder
+	privacy           *ProfilePrivacy
+	endorsementRepo   repositories.EndorsementRepository
+	inviteRepo        repositories.InviteRepository
+	mediaReview       *ProfileMediaService // optional; nil = new images go live right away
+	profileViews      *ProfileViewService  // optional; nil = views aren't counted
 	logger            *zap.Logger
 }
 
@@ -37,6 +48,51 @@ func NewProfileService(
 	}
 }
 
+// WithGeocoder enables filling in country / province / district /
+// neighborhood in the background when a profile only saves coordinates.
+func (s *ProfileService) WithGeocoder(geocoder geocoding.ReverseGeocoder) *ProfileService {
+	s.geocoder = geocoder
+	return s
+}
+
+// WithPrivacy applies users' privacy settings to profiles shown to others
+// and enables the privacy settings endpoints.
+func (s *ProfileService) WithPrivacy(privacy *ProfilePrivacy) *ProfileService {
+	s.privacy = privacy
+	return s
+}
+
+// WithEndorsements adds the approved endorsement count to profiles.
+func (s *ProfileService) WithEndorsements(endorsementRepo repositories.EndorsementRepository) *ProfileService {
+	s.endorsementRepo = endorsementRepo
+	return s
+}
+
+// WithInvites adds the count of users the profile owner invited.
+func (s *ProfileService) WithInvites(inviteRepo repositories.InviteRepository) *ProfileService {
+	s.inviteRepo = inviteRepo
+	return s
+}
+
+// WithMediaReview holds new avatars and covers for review when the review
+// service is in hold mode.
+func (s *ProfileService) WithMediaReview(mediaReview *ProfileMediaService) *ProfileService {
+	s.mediaReview = mediaReview
+	return s
+}
+
+// WithProfileViews counts signed-in views of profiles for their owners.
+func (s *ProfileService) WithProfileViews(profileViews *ProfileViewService) *ProfileService {
+	s.profileViews = profileViews
+	return s
+}
+
+// MediaHeldForReview reports whether new avatars and covers wait for
+// review before they are shown.
+func (s *ProfileService) MediaHeldForReview() bool {
+	return s.mediaReview.Holds()
+}
+
 // GetProfile gets a user's profile by user ID
 func (s *ProfileService) GetProfile(ctx context.Context, userID string, viewerID *string) (*models.FullProfileResponse, error) {
 	// Get user (active only)
@@ -94,18 +150,56 @@ func (s *ProfileService) GetProfile(ctx context.Context, userID string, viewerID
 	}
 	response.PostsCount = postsCount
 
-	// PII lockdown. Contact/identity data is owner-only: email, phone, DOB,
-	// exact home coordinates, and MFA status must never ship to other users
-	// (they previously did). Anonymous callers additionally get coarse
-	// location only (province — no district/neighborhood).
+	if s.endorsementRepo != nil {
+		endorsementsCount, err := s.endorsementRepo.CountApproved(ctx, userID)
+		if err != nil {
+			s.logger.Warn("Failed to get endorsements count", zap.String("user_id", userID), zap.Error(err))
+		}
+		response.EndorsementsCount = endorsementsCount
+	}
+
+	if s.inviteRepo != nil {
+		invitesCount, err := s.inviteRepo.CountReferrals(ctx, userID)
+		if err != nil {
+			s.logger.Warn("Failed to get invites count", zap.String("user_id", userID), zap.Error(err))
+		}
+		response.InvitesCount = invitesCount
+	}
+
+	// PII lockdown. DOB, exact home coordinates and MFA status are
+	// owner-only. Email, phone and the named location follow the owner's
+	// privacy settings (email and phone default to owner-only); signed-in
+	// viewers allowed the location get the home snapped to a grid cell.
+	// Anonymous callers additionally get coarse location only (province — no
+	// district/neighborhood, no coordinates).
 	isSelf := viewerID != nil && *viewerID == userID
-	if !isSelf {
-		response.Email = ""
-		response.Phone = nil
-		response.PhoneCountryCode = nil
+	if isSelf {
+		response.HandleChangeableAt = handleChangeableAt(profile)
+	} else {
+		visible := s.privacy.Visible(ctx, userID, viewerID)
+		if !visible.Email {
+			response.Email = ""
+		}
+		if !visible.Phone {
+			response.Phone = nil
+			response.PhoneCountryCode = nil
+		}
+		if !visible.Location {
+			response.Country = nil
+			response.Province = nil
+			response.District = nil
+			response.Neighborhood = nil
+		}
+		if visible.Location && response.Latitude != nil && response.Longitude != nil &&
+			viewerID != nil && *viewerID != "" {
+			lat, lng := gridLocation.apply(*response.Latitude, *response.Longitude)
+			response.Latitude = &lat
+			response.Longitude = &lng
+		} else {
+			response.Latitude = nil
+			response.Longitude = nil
+		}
 		response.DOB = nil
-		response.Latitude = nil
-		response.Longitude = nil
 		response.MFAEnabled = false
 		response.CompletionPercent = 0
 		response.MissingFields = nil
@@ -117,6 +211,12 @@ func (s *ProfileService) GetProfile(ctx context.Context, userID string, viewerID
 
 	// Populate relationship status (is_blocked, has_blocked_me) if viewer is authenticated
 	if viewerID != nil && *viewerID != "" && *viewerID != userID {
+		if s.profileViews != nil {
+			viewer := *viewerID
+			bgtasks.Submit(func(taskCtx context.Context) {
+				s.profileViews.RecordProfileView(taskCtx, userID, viewer)
+			})
+		}
 		status, err := s.relationshipsRepo.GetRelationshipStatus(ctx, *viewerID, userID)
 		if err == nil {
 			response.IsBlocked = status.IsBlocked    // viewer blocks target (I am blocking them)
@@ -135,6 +235,59 @@ func (s *ProfileService) GetProfile(ctx context.Context, userID string, viewerID
 	return response, nil
 }
 
+// GetMyProfileViews returns the daily view counts of the user's own profile.
+func (s *ProfileService) GetMyProfileViews(ctx context.Context, userID string, days int) (*models.ProfileViewStats, error) {
+	return s.profileViews.ProfileViews(ctx, userID, days)
+}
+
+// GetPrivacySettings returns the user's profile privacy settings.
+func (s *ProfileService) GetPrivacySettings(ctx context.Context, userID string) (*models.PrivacySettings, error) {
+	if s.privacy == nil {
+		return nil, utils.NewBadRequestError("Privacy settings are not available", nil)
+	}
+	return s.privacy.GetSettings(ctx, userID)
+}
+
+// GetContentLanguages returns the languages the user reads.
+func (s *ProfileService) GetContentLanguages(ctx context.Context, userID string) (*models.ContentLanguages, error) {
+	languages, err := s.userRepo.GetContentLanguages(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to get content languages", zap.String("user_id", userID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to get content languages", err)
+	}
+	if languages == nil {
+		languages = []string{}
+	}
+	return &models.ContentLanguages{Languages: languages}, nil
+}
+
+// UpdateContentLanguages replaces the languages the user reads. An empty
+// list shows every language again.
+func (s *ProfileService) UpdateContentLanguages(ctx context.Context, userID string, req *models.ContentLanguages) (*models.ContentLanguages, error) {
+	languages := langdetect.ParseList(strings.Join(req.Languages, ","))
+	if languages == nil {
+		languages = []string{}
+	}
+	if err := s.userRepo.SetContentLanguages(ctx, userID, languages); err != nil {
+		s.logger.Error("Failed to update content languages", zap.String("user_id", userID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to update content languages", err)
+	}
+	return &models.ContentLanguages{Languages: languages}, nil
+}
+
+// UpdatePrivacySettings changes the user's profile privacy settings.
+func (s *ProfileService) UpdatePrivacySettings(ctx context.Context, userID string, req *models.UpdatePrivacySettingsRequest) (*models.PrivacySettings, error) {
+	if s.privacy == nil {
+		return nil, utils.NewBadRequestError("Privacy settings are not available", nil)
+	}
+	settings, err := s.privacy.UpdateSettings(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("Privacy settings updated", zap.String("user_id", userID))
+	return settings, nil
+}
+
 // UpdateProfile updates a user's profile.
 // When IsComplete transitions from false → true and the user's email is not yet
 // verified, an OTP verification email is sent so users confirm their email only
@@ -146,6 +299,9 @@ func (s *ProfileService) UpdateProfile(ctx context.Context, userID string, req *
 		s.logger.Error("Failed to get profile", zap.String("user_id", userID), zap.Error(err))
 		return nil, utils.NewInternalError("Failed to get profile", err)
 	}
+	if req.Version != nil && *req.Version != profile.Version {
+		return nil, s.profileVersionConflict(ctx, userID)
+	}
 
 	// Update fields if provided
 	if req.FirstName != nil {
@@ -181,6 +337,14 @@ func (s *ProfileService) UpdateProfile(ctx context.Context, userID string, req *
 	if req.AvatarColor != nil {
 		profile.AvatarColor = req.AvatarColor
 	}
+	// Alt text belongs to the current image, so there's nothing to set
+	// without one.
+	if req.AvatarAltText != nil && profile.Avatar != nil {
+		profile.Avatar.AltText = strings.TrimSpace(*req.AvatarAltText)
+	}
+	if req.CoverAltText != nil && profile.Cover != nil {
+		profile.Cover.AltText = strings.TrimSpace(*req.CoverAltText)
+	}
 
 	// Handle location update (Latitude/Longitude -> pgtype.Point)
 	// Support both nested location object and flat latitude/longitude fields
@@ -208,6 +372,10 @@ func (s *ProfileService) UpdateProfile(ctx context.Context, userID string, req *
 
 	// Update profile
 	if err := s.userRepo.UpdateProfile(ctx, profile); err != nil {
+		if errors.Is(err, repositories.ErrVersionConflict) {
+			// Saved from another device between our read and write.
+			return nil, s.profileVersionConflict(ctx, userID)
+		}
 		s.logger.Error("Failed to update profile", zap.String("user_id", userID), zap.Error(err))
 		return nil, utils.NewInternalError("Failed to update profile", err)
 	}
@@ -231,6 +399,16 @@ func (s *ProfileService) UpdateProfile(ctx context.Context, userID string, req *
 		}
 	}
 
+	// Coordinates without a province: fill the address in the background.
+	locationChanged := req.Location != nil || (req.Latitude != nil && req.Longitude != nil) //nolint:staticcheck
+	if s.geocoder != nil && locationChanged && needsAddressBackfill(profile.Province) {
+		profileID := profile.ID
+		lat, lng := profile.Location.P.Y, profile.Location.P.X
+		backfillAddress(s.geocoder, s.logger, lat, lng, func(ctx context.Context, addr *models.ResolvedAddress) error {
+			return s.userRepo.FillProfileAddress(ctx, profileID, lat, lng, addr)
+		})
+	}
+
 	s.logger.Info("Profile updated",
 		zap.String("user_id", userID),
 		zap.Bool("is_complete", profile.IsComplete),
@@ -243,8 +421,25 @@ func (s *ProfileService) UpdateProfile(ctx context.Context, userID string, req *
 	return s.GetProfile(ctx, userID, nil)
 }
 
-// UpdateAvatar updates a user's avatar
+// profileVersionConflict builds the 409 for an edit based on a stale
+// version, carrying the profile as it is now so the client can merge.
+func (s *ProfileService) profileVersionConflict(ctx context.Context, userID string) error {
+	current, err := s.GetProfile(ctx, userID, nil)
+	if err != nil {
+		return err
+	}
+	return utils.NewVersionConflictError("Your profile was changed on another device", current)
+}
+
+// UpdateAvatar updates a user's avatar, or holds it for review.
 func (s *ProfileService) UpdateAvatar(ctx context.Context, userID string, photo *models.Photo) error {
+	if photo != nil && s.mediaReview.Holds() {
+		return s.mediaReview.Submit(ctx, models.ProfileMediaOwnerUser, userID, models.ProfileMediaAvatar, *photo)
+	}
+	return s.setAvatar(ctx, userID, photo)
+}
+
+func (s *ProfileService) setAvatar(ctx context.Context, userID string, photo *models.Photo) error {
 	// Get current profile
 	profile, err := s.userRepo.GetProfileByUserID(ctx, userID)
 	if err != nil {
@@ -268,6 +463,8 @@ func (s *ProfileService) UpdateAvatar(ctx context.Context, userID string, photo
 
 // DeleteAvatar deletes a user's avatar
 func (s *ProfileService) DeleteAvatar(ctx context.Context, userID string) error {
+	s.mediaReview.Cancel(ctx, models.ProfileMediaOwnerUser, userID, models.ProfileMediaAvatar)
+
 	// Get current profile
 	profile, err := s.userRepo.GetProfileByUserID(ctx, userID)
 	if err != nil {
@@ -289,8 +486,15 @@ func (s *ProfileService) DeleteAvatar(ctx context.Context, userID string) error
 	return nil
 }
 
-// UpdateCover updates a user's cover photo
+// UpdateCover updates a user's cover photo, or holds it for review.
 func (s *ProfileService) UpdateCover(ctx context.Context, userID string, photo *models.Photo) error {
+	if photo != nil && s.mediaReview.Holds() {
+		return s.mediaReview.Submit(ctx, models.ProfileMediaOwnerUser, userID, models.ProfileMediaCover, *photo)
+	}
+	return s.setCover(ctx, userID, photo)
+}
+
+func (s *ProfileService) setCover(ctx context.Context, userID string, photo *models.Photo) error {
 	// Get current profile
 	profile, err := s.userRepo.GetProfileByUserID(ctx, userID)
 	if err != nil {
@@ -327,6 +531,8 @@ func (s *ProfileService) DeactivateAccount(ctx context.Context, userID string) e
 }
 
 func (s *ProfileService) DeleteCover(ctx context.Context, userID string) error {
+	s.mediaReview.Cancel(ctx, models.ProfileMediaOwnerUser, userID, models.ProfileMediaCover)
+
 	// Get current profile
 	profile, err := s.userRepo.GetProfileByUserID(ctx, userID)
 	if err != nil {
@@ -348,6 +554,15 @@ func (s *ProfileService) DeleteCover(ctx context.Context, userID string) error {
 	return nil
 }
 
+// ApplyReviewedMedia puts an approved avatar or cover on the user's
+// profile.
+func (s *ProfileService) ApplyReviewedMedia(ctx context.Context, userID, kind string, photo models.Photo) error {
+	if kind == models.ProfileMediaCover {
+		return s.setCover(ctx, userID, &photo)
+	}
+	return s.setAvatar(ctx, userID, &photo)
+}
+
 // isProfileComplete checks if a profile has all required fields
 func (s *ProfileService) isProfileComplete(profile *models.Profile) bool {
 	// A profile is complete when the user has set their location.
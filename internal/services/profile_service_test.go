@@ -9,6 +9,7 @@ import (
 
 	"github.com/hamsaya/backend/internal/mocks"
 	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
 	"github.com/hamsaya/backend/internal/testutil"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/assert"
@@ -223,6 +224,57 @@ func TestProfileService_UpdateProfile(t *testing.T) {
 				Longitude: func() *float64 { v := 69.2; return &v }(),
 			},
 		},
+		{
+			name: "alt text set on the current avatar only",
+			setupMocks: func(userRepo *mocks.MockUserRepository, postRepo *mocks.MockPostRepository, relRepo *mocks.MockRelationshipsRepository) {
+				profile := testutil.CreateTestProfile("user-1", "Test", "User")
+				profile.Avatar = &models.Photo{URL: "https://cdn.example.com/a.webp"}
+				userRepo.On("GetProfileByUserID", mock.Anything, "user-1").Return(profile, nil)
+				userRepo.On("UpdateProfile", mock.Anything, mock.MatchedBy(func(p *models.Profile) bool {
+					return p.Avatar.AltText == "Me at the lake" && p.Cover == nil
+				})).Return(nil)
+				userRepo.On("GetByID", mock.Anything, "user-1").Return(testutil.CreateTestUser("user-1", "test@example.com"), nil)
+				relRepo.On("GetFollowersCount", mock.Anything, "user-1").Return(0, nil)
+				relRepo.On("GetFollowingCount", mock.Anything, "user-1").Return(0, nil)
+				postRepo.On("CountPostsByUser", mock.Anything, "user-1").Return(0, nil)
+			},
+			request: &models.UpdateProfileRequest{
+				AvatarAltText: testutil.StringPtr(" Me at the lake "),
+				CoverAltText:  testutil.StringPtr("No cover yet"),
+			},
+		},
+		{
+			name: "stale version returns the current profile",
+			setupMocks: func(userRepo *mocks.MockUserRepository, postRepo *mocks.MockPostRepository, relRepo *mocks.MockRelationshipsRepository) {
+				profile := testutil.CreateTestProfile("user-1", "Test", "User")
+				profile.Version = 3
+				userRepo.On("GetProfileByUserID", mock.Anything, "user-1").Return(profile, nil)
+				userRepo.On("GetByID", mock.Anything, "user-1").Return(testutil.CreateTestUser("user-1", "test@example.com"), nil)
+				relRepo.On("GetFollowersCount", mock.Anything, "user-1").Return(0, nil)
+				relRepo.On("GetFollowingCount", mock.Anything, "user-1").Return(0, nil)
+				postRepo.On("CountPostsByUser", mock.Anything, "user-1").Return(0, nil)
+			},
+			request: &models.UpdateProfileRequest{
+				FirstName: testutil.StringPtr("New"),
+				Version:   func() *int { v := 2; return &v }(),
+			},
+			expectedError: "changed on another device",
+		},
+		{
+			name: "saved elsewhere between read and write",
+			setupMocks: func(userRepo *mocks.MockUserRepository, postRepo *mocks.MockPostRepository, relRepo *mocks.MockRelationshipsRepository) {
+				profile := testutil.CreateTestProfile("user-1", "Test", "User")
+				userRepo.On("GetProfileByUserID", mock.Anything, "user-1").Return(profile, nil)
+				userRepo.On("UpdateProfile", mock.Anything, mock.AnythingOfType("*models.Profile")).
+					Return(repositories.ErrVersionConflict)
+				userRepo.On("GetByID", mock.Anything, "user-1").Return(testutil.CreateTestUser("user-1", "test@example.com"), nil)
+				relRepo.On("GetFollowersCount", mock.Anything, "user-1").Return(0, nil)
+				relRepo.On("GetFollowingCount", mock.Anything, "user-1").Return(0, nil)
+				postRepo.On("CountPostsByUser", mock.Anything, "user-1").Return(0, nil)
+			},
+			request:       &models.UpdateProfileRequest{FirstName: testutil.StringPtr("New")},
+			expectedError: "changed on another device",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	profileViewDedupePrefix  = "profile_view:"
+	businessViewDedupePrefix = "business_view:"
+
+	// businessViewerRetention is how long per-viewer business visits are
+	// kept, and so the longest window the viewer list covers.
+	businessViewerRetention = 90 * 24 * time.Hour
+)
+
+// ProfileViewService tracks signed-in views of profiles, once per viewer
+// per profile per day (a Redis SETNX like PostViewCounter, failing open).
+// Personal profiles only get anonymous daily counts for their owner. A
+// business owner also sees who viewed it, limited to the viewers whose
+// profile_view_visibility privacy setting shares the visit with the business.
+//
+// A nil *ProfileViewService records nothing, so services work unwired.
+type ProfileViewService struct {
+	redis  *redis.Client
+	repo   repositories.ProfileViewRepository
+	access *AccessControl
+	logger *zap.Logger
+}
+
+// NewProfileViewService creates the profile view service.
+func NewProfileViewService(
+	redisClient *redis.Client,
+	repo repositories.ProfileViewRepository,
+	businessRepo repositories.BusinessRepository,
+	logger *zap.Logger,
+) *ProfileViewService {
+	return &ProfileViewService{
+		redis:  redisClient,
+		repo:   repo,
+		access: NewAccessControl(businessRepo),
+		logger: logger,
+	}
+}
+
+// RecordProfileView counts viewerID's view of userID's profile unless they
+// already viewed it today. Owners viewing themselves don't count.
+func (s *ProfileViewService) RecordProfileView(ctx context.Context, userID, viewerID string) {
+	if s == nil || viewerID == "" || viewerID == userID ||
+		!s.firstToday(ctx, profileViewDedupePrefix+userID+":"+viewerID) {
+		return
+	}
+	if err := s.repo.IncrementProfileView(ctx, userID); err != nil {
+		s.logger.Warn("Failed to record profile view", zap.String("user_id", userID), zap.Error(err))
+	}
+}
+
+// RecordBusinessView notes viewerID's visit to the business for its viewer
+// list. The caller skips the owner.
+func (s *ProfileViewService) RecordBusinessView(ctx context.Context, businessID, viewerID string) {
+	if s == nil || viewerID == "" ||
+		!s.firstToday(ctx, businessViewDedupePrefix+businessID+":"+viewerID) {
+		return
+	}
+	if err := s.repo.RecordBusinessViewer(ctx, businessID, viewerID); err != nil {
+		s.logger.Warn("Failed to record business viewer", zap.String("business_id", businessID), zap.Error(err))
+	}
+}
+
+func (s *ProfileViewService) firstToday(ctx context.Context, key string) bool {
+	if s.redis == nil {
+		return true
+	}
+	ok, err := s.redis.SetNX(ctx, key+":"+time.Now().UTC().Format("2006-01-02"), "1", 24*time.Hour).Result()
+	if err != nil {
+		s.logger.Warn("Profile view dedupe failed", zap.Error(err))
+		return true
+	}
+	return ok
+}
+
+// ProfileViews returns the daily view counts of the user's own profile.
+func (s *ProfileViewService) ProfileViews(ctx context.Context, userID string, days int) (*models.ProfileViewStats, error) {
+	days = clampInsightDays(days)
+	stats := &models.ProfileViewStats{Days: days, Views: []models.DailyCount{}}
+	if s == nil {
+		return stats, nil
+	}
+	views, err := s.repo.GetDailyProfileViews(ctx, userID, days)
+	if err != nil {
+		s.logger.Error("Failed to get profile views", zap.String("user_id", userID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to get profile views", err)
+	}
+	stats.Views = views
+	for _, v := range views {
+		stats.Total += v.Count
+	}
+	return stats, nil
+}
+
+// BusinessViewers lists who viewed the business in the last days days
+// (owner only, at most the retention window).
+func (s *ProfileViewService) BusinessViewers(ctx context.Context, businessID, userID string, days, limit, offset int) (*models.BusinessViewersResponse, error) {
+	if _, err := s.access.RequireBusinessOwner(ctx, businessID, userID); err != nil {
+		return nil, err
+	}
+	if maxDays := int(businessViewerRetention / (24 * time.Hour)); days < 1 || days > maxDays {
+		days = maxDays
+	}
+	viewers, total, all, err := s.repo.ListBusinessViewers(ctx, businessID, days, limit, offset)
+	if err != nil {
+		s.logger.Error("Failed to list business viewers", zap.String("business_id", businessID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to get viewers", err)
+	}
+	for _, v := range viewers {
+		v.FullName = (&models.Profile{FirstName: v.FirstName, LastName: v.LastName}).FullName()
+	}
+	return &models.BusinessViewersResponse{
+		Items:  viewers,
+		Total:  total,
+		Hidden: all - total,
+		Days:   days,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+// DailyUniqueViewers returns distinct signed-in viewers of the business per
+// day for the insights; empty when unwired or on error.
+func (s *ProfileViewService) DailyUniqueViewers(ctx context.Context, businessID string, days int) []models.DailyCount {
+	if s == nil {
+		return []models.DailyCount{}
+	}
+	counts, err := s.repo.GetDailyUniqueViewers(ctx, businessID, days)
+	if err != nil {
+		s.logger.Warn("Failed to get unique business viewers", zap.String("business_id", businessID), zap.Error(err))
+		return []models.DailyCount{}
+	}
+	return counts
+}
+
+// PruneBusinessViewers drops per-viewer visits past the retention window.
+// Runs nightly on the leader.
+func (s *ProfileViewService) PruneBusinessViewers(ctx context.Context) error {
+	n, err := s.repo.DeleteBusinessViewersBefore(ctx, time.Now().Add(-businessViewerRetention))
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		s.logger.Info("Pruned business viewers", zap.Int64("rows", n))
+	}
+	return nil
+}
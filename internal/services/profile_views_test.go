@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestProfileViewService_RecordProfileView(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	repo := new(mocks.MockProfileViewRepository)
+	repo.On("IncrementProfileView", ctx, "user-1").Return(nil).Twice()
+	svc := NewProfileViewService(rdb, repo, new(mocks.MockBusinessRepository), zap.NewNop())
+
+	// Once per viewer per day; owners viewing themselves don't count.
+	svc.RecordProfileView(ctx, "user-1", "viewer-1")
+	svc.RecordProfileView(ctx, "user-1", "viewer-1")
+	svc.RecordProfileView(ctx, "user-1", "viewer-2")
+	svc.RecordProfileView(ctx, "user-1", "user-1")
+	repo.AssertExpectations(t)
+
+	var unwired *ProfileViewService
+	unwired.RecordProfileView(ctx, "user-1", "viewer-3")
+	stats, err := unwired.ProfileViews(ctx, "user-1", 0)
+	require.NoError(t, err)
+	assert.Equal(t, 28, stats.Days)
+	assert.Empty(t, stats.Views)
+}
+
+func TestProfileViewService_ProfileViews(t *testing.T) {
+	ctx := context.Background()
+	repo := new(mocks.MockProfileViewRepository)
+	repo.On("GetDailyProfileViews", ctx, "user-1", 7).Return([]models.DailyCount{
+		{Date: "2026-10-15", Count: 2},
+		{Date: "2026-10-16", Count: 3},
+	}, nil)
+	svc := NewProfileViewService(nil, repo, new(mocks.MockBusinessRepository), zap.NewNop())
+
+	stats, err := svc.ProfileViews(ctx, "user-1", 7)
+	require.NoError(t, err)
+	assert.Equal(t, 7, stats.Days)
+	assert.Equal(t, 5, stats.Total)
+	assert.Len(t, stats.Views, 2)
+}
+
+func TestProfileViewService_BusinessViewers(t *testing.T) {
+	ctx := context.Background()
+	businessRepo := new(mocks.MockBusinessRepository)
+	businessRepo.On("GetByID", mock.Anything, "biz-1").
+		Return(&models.BusinessProfile{ID: "biz-1", UserID: "owner"}, nil)
+	first, last := "Sara", "Ahmadi"
+	repo := new(mocks.MockProfileViewRepository)
+	repo.On("ListBusinessViewers", ctx, "biz-1", 90, 20, 0).Return([]*models.BusinessViewer{
+		{UserID: "viewer-1", FirstName: &first, LastName: &last, IsFollower: true, VisitDays: 2},
+	}, 1, 4, nil)
+	svc := NewProfileViewService(nil, repo, businessRepo, zap.NewNop())
+
+	resp, err := svc.BusinessViewers(ctx, "biz-1", "owner", 365, 20, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 90, resp.Days, "the window is capped at the retention")
+	assert.Equal(t, 1, resp.Total)
+	assert.Equal(t, 3, resp.Hidden, "private viewers are counted, not listed")
+	require.Len(t, resp.Items, 1)
+	assert.Equal(t, "Sara Ahmadi", resp.Items[0].FullName)
+
+	_, err = svc.BusinessViewers(ctx, "biz-1", "someone", 30, 20, 0)
+	requireAppErrorCode(t, err, http.StatusForbidden)
+	repo.AssertNumberOfCalls(t, "ListBusinessViewers", 1)
+}
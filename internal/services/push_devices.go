@@ -0,0 +1,279 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/utils"
+	fcmclient "github.com/hamsaya/backend/pkg/notification"
+	"go.uber.org/zap"
+)
+
+const (
+	// pushDevicesPrefix keys a Redis HASH of per-device delivery state for a
+	// user: device id -> JSON pushDeviceMeta. The token SETs stay the source
+	// of truth for delivery; the hash only tracks hygiene.
+	pushDevicesPrefix = "push:devices:"
+	// maxPushDevicesPerUser caps registered tokens per user across FCM and
+	// APNs. Registering past it evicts the least recently active device.
+	maxPushDevicesPerUser = 10
+	// maxUnregisteredFailures is how many consecutive FCM "unregistered"
+	// errors prune a token. FCM sometimes reports it for live tokens, so a
+	// single one isn't trusted.
+	maxUnregisteredFailures = 3
+
+	pushProviderFCM  = "fcm"
+	pushProviderAPNs = "apns"
+)
+
+// pushDeviceMeta is the stored state of one registered token.
+type pushDeviceMeta struct {
+	Provider   string  `json:"provider"`
+	Token      string  `json:"token"`
+	DeviceName *string `json:"device_name,omitempty"`
+	// RegisteredAt is the most recent registration; zero for tokens
+	// registered before device tracking.
+	RegisteredAt         time.Time  `json:"registered_at"`
+	LastSuccessAt        *time.Time `json:"last_success_at,omitempty"`
+	LastFailureAt        *time.Time `json:"last_failure_at,omitempty"`
+	UnregisteredFailures int        `json:"unregistered_failures"`
+}
+
+func (m *pushDeviceMeta) lastActive() time.Time {
+	if m.LastSuccessAt != nil && m.LastSuccessAt.After(m.RegisteredAt) {
+		return *m.LastSuccessAt
+	}
+	return m.RegisteredAt
+}
+
+// pushDeviceID identifies a token without exposing it.
+func pushDeviceID(token string) string {
+	return hashToken(token)[:16]
+}
+
+func pushTokensKey(provider, userID string) string {
+	if provider == pushProviderAPNs {
+		return apnsTokensPrefix + userID
+	}
+	return fcmTokensPrefix + userID
+}
+
+// pushDevices returns the user's registered tokens by device id, merged
+// with their stored state. Tokens without state get a zero RegisteredAt;
+// state left behind by tokens no longer registered is ignored.
+func (s *NotificationService) pushDevices(ctx context.Context, userID string) (map[string]*pushDeviceMeta, error) {
+	stored, err := s.redisClient.HGetAll(ctx, pushDevicesPrefix+userID).Result()
+	if err != nil {
+		return nil, err
+	}
+	devices := make(map[string]*pushDeviceMeta)
+	for _, provider := range []string{pushProviderFCM, pushProviderAPNs} {
+		tokens, err := s.redisClient.SMembers(ctx, pushTokensKey(provider, userID)).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, token := range tokens {
+			id := pushDeviceID(token)
+			meta := &pushDeviceMeta{}
+			if raw, ok := stored[id]; !ok || json.Unmarshal([]byte(raw), meta) != nil {
+				meta = &pushDeviceMeta{}
+			}
+			meta.Provider, meta.Token = provider, token
+			devices[id] = meta
+		}
+	}
+	return devices, nil
+}
+
+// pushDevice loads the state of one token, or a fresh one.
+func (s *NotificationService) pushDevice(ctx context.Context, userID, provider, token string) *pushDeviceMeta {
+	meta := &pushDeviceMeta{}
+	raw, err := s.redisClient.HGet(ctx, pushDevicesPrefix+userID, pushDeviceID(token)).Result()
+	if err != nil || json.Unmarshal([]byte(raw), meta) != nil {
+		meta = &pushDeviceMeta{}
+	}
+	meta.Provider, meta.Token = provider, token
+	return meta
+}
+
+func (s *NotificationService) savePushDevice(ctx context.Context, userID string, meta *pushDeviceMeta) error {
+	raw, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	key := pushDevicesPrefix + userID
+	pipe := s.redisClient.TxPipeline()
+	pipe.HSet(ctx, key, pushDeviceID(meta.Token), raw)
+	pipe.Expire(ctx, key, fcmTokenTTL)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// removePushDevice unregisters a token and drops its state.
+func (s *NotificationService) removePushDevice(ctx context.Context, userID string, meta *pushDeviceMeta) error {
+	pipe := s.redisClient.TxPipeline()
+	pipe.SRem(ctx, pushTokensKey(meta.Provider, userID), meta.Token)
+	pipe.HDel(ctx, pushDevicesPrefix+userID, pushDeviceID(meta.Token))
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// trackPushDevice records a (re-)registration and evicts the least recently
+// active devices above maxPushDevicesPerUser. Best effort: failures are
+// logged, the registration itself already succeeded.
+func (s *NotificationService) trackPushDevice(ctx context.Context, userID, provider, token string, deviceName *string) {
+	meta := s.pushDevice(ctx, userID, provider, token)
+	meta.RegisteredAt = time.Now().UTC()
+	if deviceName != nil {
+		meta.DeviceName = deviceName
+	}
+	if err := s.savePushDevice(ctx, userID, meta); err != nil {
+		s.logger.Warn("Failed to track push device", zap.Error(err), zap.String("user_id", userID))
+		return
+	}
+
+	devices, err := s.pushDevices(ctx, userID)
+	if err != nil {
+		s.logger.Warn("Failed to load push devices", zap.Error(err), zap.String("user_id", userID))
+		return
+	}
+	if len(devices) <= maxPushDevicesPerUser {
+		return
+	}
+	ordered := sortedPushDevices(devices)
+	for _, stale := range ordered[maxPushDevicesPerUser:] {
+		if err := s.removePushDevice(ctx, userID, stale); err != nil {
+			s.logger.Warn("Failed to evict push device", zap.Error(err), zap.String("user_id", userID))
+			return
+		}
+		s.logger.Info("Push device evicted over cap",
+			zap.String("user_id", userID), zap.String("provider", stale.Provider))
+	}
+}
+
+// recordPushSuccess notes a delivered push and clears the failure streak.
+func (s *NotificationService) recordPushSuccess(ctx context.Context, userID, provider, token string) {
+	meta := s.pushDevice(ctx, userID, provider, token)
+	now := time.Now().UTC()
+	meta.LastSuccessAt = &now
+	meta.UnregisteredFailures = 0
+	if err := s.savePushDevice(ctx, userID, meta); err != nil {
+		s.logger.Warn("Failed to record push success", zap.Error(err), zap.String("user_id", userID))
+	}
+}
+
+// recordPushFailure notes a failed push and prunes the token when the error
+// says it's dead: at once for malformed FCM tokens and APNs rejections,
+// after maxUnregisteredFailures in a row for FCM "unregistered". Reports
+// whether the token was pruned.
+func (s *NotificationService) recordPushFailure(ctx context.Context, userID, provider, token string, sendErr error) bool {
+	meta := s.pushDevice(ctx, userID, provider, token)
+	now := time.Now().UTC()
+	meta.LastFailureAt = &now
+
+	prune := false
+	switch {
+	case errors.Is(sendErr, fcmclient.ErrTokenUnregistered):
+		meta.UnregisteredFailures++
+		prune = meta.UnregisteredFailures >= maxUnregisteredFailures
+	case errors.Is(sendErr, fcmclient.ErrTokenInvalid), errors.Is(sendErr, fcmclient.ErrAPNsTokenInvalid):
+		prune = true
+	}
+
+	if prune {
+		if err := s.removePushDevice(ctx, userID, meta); err != nil {
+			s.logger.Warn("Failed to prune stale push token",
+				zap.Error(err), zap.String("user_id", userID), zap.String("provider", provider))
+			return false
+		}
+		return true
+	}
+	if err := s.savePushDevice(ctx, userID, meta); err != nil {
+		s.logger.Warn("Failed to record push failure", zap.Error(err), zap.String("user_id", userID))
+	}
+	return false
+}
+
+// forgetPushDevices drops the stored state of the user's tokens for one
+// provider, after its whole token set was unregistered.
+func (s *NotificationService) forgetPushDevices(ctx context.Context, userID, provider string) {
+	stored, err := s.redisClient.HGetAll(ctx, pushDevicesPrefix+userID).Result()
+	if err != nil {
+		return
+	}
+	var ids []string
+	for id, raw := range stored {
+		var meta pushDeviceMeta
+		if json.Unmarshal([]byte(raw), &meta) == nil && meta.Provider == provider {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > 0 {
+		_ = s.redisClient.HDel(ctx, pushDevicesPrefix+userID, ids...).Err()
+	}
+}
+
+// sortedPushDevices orders devices most recently active first.
+func sortedPushDevices(devices map[string]*pushDeviceMeta) []*pushDeviceMeta {
+	ordered := make([]*pushDeviceMeta, 0, len(devices))
+	for _, meta := range devices {
+		ordered = append(ordered, meta)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		a, b := ordered[i].lastActive(), ordered[j].lastActive()
+		if !a.Equal(b) {
+			return a.After(b)
+		}
+		return pushDeviceID(ordered[i].Token) < pushDeviceID(ordered[j].Token)
+	})
+	return ordered
+}
+
+// ListPushDevices returns the devices registered for the user's push
+// notifications, most recently active first.
+func (s *NotificationService) ListPushDevices(ctx context.Context, userID string) ([]*models.PushDevice, error) {
+	devices, err := s.pushDevices(ctx, userID)
+	if err != nil {
+		s.logger.Error("Failed to list push devices", zap.Error(err), zap.String("user_id", userID))
+		return nil, utils.NewInternalError("Failed to list devices", err)
+	}
+	result := make([]*models.PushDevice, 0, len(devices))
+	for _, meta := range sortedPushDevices(devices) {
+		device := &models.PushDevice{
+			ID:                   pushDeviceID(meta.Token),
+			Provider:             meta.Provider,
+			DeviceName:           meta.DeviceName,
+			LastSuccessAt:        meta.LastSuccessAt,
+			LastFailureAt:        meta.LastFailureAt,
+			UnregisteredFailures: meta.UnregisteredFailures,
+		}
+		if !meta.RegisteredAt.IsZero() {
+			registeredAt := meta.RegisteredAt
+			device.RegisteredAt = &registeredAt
+		}
+		result = append(result, device)
+	}
+	return result, nil
+}
+
+// RevokePushDevice unregisters one of the user's devices; it stops
+// receiving pushes until it registers again.
+func (s *NotificationService) RevokePushDevice(ctx context.Context, userID, deviceID string) error {
+	devices, err := s.pushDevices(ctx, userID)
+	if err != nil {
+		return utils.NewInternalError("Failed to revoke device", err)
+	}
+	meta, ok := devices[deviceID]
+	if !ok {
+		return utils.NewNotFoundError("Device not found", nil)
+	}
+	if err := s.removePushDevice(ctx, userID, meta); err != nil {
+		return utils.NewInternalError("Failed to revoke device", err)
+	}
+	s.logger.Info("Push device revoked", zap.String("user_id", userID), zap.String("provider", meta.Provider))
+	return nil
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/hamsaya/backend/internal/mocks"
+	fcmclient "github.com/hamsaya/backend/pkg/notification"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newPushDeviceTestService(t *testing.T) (*NotificationService, *redis.Client) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	svc := NewNotificationService(
+		new(mocks.MockNotificationRepository),
+		new(mocks.MockNotificationSettingsRepository),
+		new(mocks.MockUserRepository),
+		nil, rdb, nil, zap.NewNop(),
+	)
+	return svc, rdb
+}
+
+func TestNotificationService_PushDeviceList(t *testing.T) {
+	ctx := context.Background()
+	svc, rdb := newPushDeviceTestService(t)
+	name := "Pixel 8"
+	require.NoError(t, svc.RegisterFCMToken(ctx, "user-1", "fcm-token-aaaa", &name))
+	require.NoError(t, svc.RegisterAPNsToken(ctx, "user-1", "apns-token-bbbb", nil))
+	// Registered before device tracking: listed without a registration time.
+	rdb.SAdd(ctx, fcmTokensPrefix+"user-1", "fcm-token-legacy")
+
+	devices, err := svc.ListPushDevices(ctx, "user-1")
+	require.NoError(t, err)
+	require.Len(t, devices, 3)
+	assert.Equal(t, "apns", devices[0].Provider, "most recently registered first")
+	assert.Equal(t, "fcm", devices[1].Provider)
+	assert.Equal(t, &name, devices[1].DeviceName)
+	assert.Nil(t, devices[2].RegisteredAt)
+
+	t.Run("revoke", func(t *testing.T) {
+		require.NoError(t, svc.RevokePushDevice(ctx, "user-1", devices[1].ID))
+		isMember, _ := rdb.SIsMember(ctx, fcmTokensPrefix+"user-1", "fcm-token-aaaa").Result()
+		assert.False(t, isMember)
+
+		err := svc.RevokePushDevice(ctx, "user-1", devices[1].ID)
+		requireAppErrCode(t, err, http.StatusNotFound)
+	})
+
+	t.Run("other users' devices can't be revoked", func(t *testing.T) {
+		err := svc.RevokePushDevice(ctx, "user-2", devices[0].ID)
+		requireAppErrCode(t, err, http.StatusNotFound)
+	})
+}
+
+func TestNotificationService_PushDeviceCap(t *testing.T) {
+	ctx := context.Background()
+	svc, rdb := newPushDeviceTestService(t)
+	rdb.SAdd(ctx, fcmTokensPrefix+"user-1", "fcm-token-legacy")
+	for i := 0; i < maxPushDevicesPerUser; i++ {
+		require.NoError(t, svc.RegisterFCMToken(ctx, "user-1", fmt.Sprintf("fcm-token-%02d", i), nil))
+	}
+
+	tokens, err := rdb.SMembers(ctx, fcmTokensPrefix+"user-1").Result()
+	require.NoError(t, err)
+	assert.Len(t, tokens, maxPushDevicesPerUser)
+	assert.NotContains(t, tokens, "fcm-token-legacy", "least recently active evicted")
+}
+
+func TestNotificationService_RecordPushFailure(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unregistered prunes after repeated failures", func(t *testing.T) {
+		svc, rdb := newPushDeviceTestService(t)
+		require.NoError(t, svc.RegisterFCMToken(ctx, "user-1", "fcm-token-aaaa", nil))
+
+		for i := 1; i < maxUnregisteredFailures; i++ {
+			assert.False(t, svc.recordPushFailure(ctx, "user-1", pushProviderFCM, "fcm-token-aaaa", fcmclient.ErrTokenUnregistered))
+		}
+		devices, err := svc.ListPushDevices(ctx, "user-1")
+		require.NoError(t, err)
+		require.Len(t, devices, 1)
+		assert.Equal(t, maxUnregisteredFailures-1, devices[0].UnregisteredFailures)
+		assert.NotNil(t, devices[0].LastFailureAt)
+
+		assert.True(t, svc.recordPushFailure(ctx, "user-1", pushProviderFCM, "fcm-token-aaaa", fcmclient.ErrTokenUnregistered))
+		n, _ := rdb.SCard(ctx, fcmTokensPrefix+"user-1").Result()
+		assert.Zero(t, n)
+	})
+
+	t.Run("success resets the streak", func(t *testing.T) {
+		svc, _ := newPushDeviceTestService(t)
+		require.NoError(t, svc.RegisterFCMToken(ctx, "user-1", "fcm-token-aaaa", nil))
+		for i := 1; i < maxUnregisteredFailures; i++ {
+			svc.recordPushFailure(ctx, "user-1", pushProviderFCM, "fcm-token-aaaa", fcmclient.ErrTokenUnregistered)
+		}
+		svc.recordPushSuccess(ctx, "user-1", pushProviderFCM, "fcm-token-aaaa")
+		assert.False(t, svc.recordPushFailure(ctx, "user-1", pushProviderFCM, "fcm-token-aaaa", fcmclient.ErrTokenUnregistered))
+
+		devices, err := svc.ListPushDevices(ctx, "user-1")
+		require.NoError(t, err)
+		require.Len(t, devices, 1)
+		assert.Equal(t, 1, devices[0].UnregisteredFailures)
+		assert.NotNil(t, devices[0].LastSuccessAt)
+	})
+
+	t.Run("malformed token prunes at once", func(t *testing.T) {
+		svc, _ := newPushDeviceTestService(t)
+		require.NoError(t, svc.RegisterFCMToken(ctx, "user-1", "fcm-token-aaaa", nil))
+		assert.True(t, svc.recordPushFailure(ctx, "user-1", pushProviderFCM, "fcm-token-aaaa", fcmclient.ErrTokenInvalid))
+	})
+
+	t.Run("transient errors keep the token", func(t *testing.T) {
+		svc, _ := newPushDeviceTestService(t)
+		require.NoError(t, svc.RegisterFCMToken(ctx, "user-1", "fcm-token-aaaa", nil))
+		assert.False(t, svc.recordPushFailure(ctx, "user-1", pushProviderFCM, "fcm-token-aaaa", assert.AnError))
+
+		devices, err := svc.ListPushDevices(ctx, "user-1")
+		require.NoError(t, err)
+		require.Len(t, devices, 1)
+		assert.Zero(t, devices[0].UnregisteredFailures)
+	})
+}
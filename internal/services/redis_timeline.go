@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// FlagRedisHomeTimeline is the feature flag that serves home timelines from
+// Redis instead of user_feeds.
+const FlagRedisHomeTimeline = "redis_home_timeline"
+
+const (
+	redisTimelinePrefix = "timeline:"
+	// redisTimelineCap is how many recent posts each timeline keeps; older
+	// pages are read from user_feeds.
+	redisTimelineCap = 800
+	// redisTimelineTTL drops the timelines of users who stopped opening the
+	// app; every push moves it forward.
+	redisTimelineTTL = 14 * 24 * time.Hour
+)
+
+// RedisTimeline materializes each user's home timeline in a Redis sorted set,
+// "timeline:{userID}" → {postID: created-at millis}, so reading the first
+// pages of the home feed doesn't touch Postgres.
+//
+// It sits in front of user_feeds, which stays the source of truth: pushes go
+// to both (Redis best-effort), and reads fall back to user_feeds when the
+// flag is off, Redis fails, the viewer has no Redis timeline yet, or the page
+// runs past the capped set. Writing both regardless of the flag means it can
+// be flipped either way at any time without gaps.
+type RedisTimeline struct {
+	redis      *redis.Client
+	fallback   repositories.FeedProvider
+	fanoutRepo repositories.FanoutRepository
+	enabled    func() bool
+	logger     *zap.Logger
+}
+
+// NewRedisTimeline creates the timeline. enabled is consulted on every read,
+// so the flag takes effect without a restart.
+func NewRedisTimeline(
+	redisClient *redis.Client,
+	fanoutRepo repositories.FanoutRepository,
+	enabled func() bool,
+	logger *zap.Logger,
+) *RedisTimeline {
+	return &RedisTimeline{
+		redis:      redisClient,
+		fallback:   repositories.NewPostgresFeedProvider(fanoutRepo),
+		fanoutRepo: fanoutRepo,
+		enabled:    enabled,
+		logger:     logger,
+	}
+}
+
+func redisTimelineKey(userID string) string {
+	return redisTimelinePrefix + userID
+}
+
+// Push writes the post to user_feeds, then to each follower's Redis timeline,
+// trimming it to the cap.
+func (t *RedisTimeline) Push(ctx context.Context, postID string, createdAt time.Time, followerIDs []string) error {
+	if err := t.fallback.Push(ctx, postID, createdAt, followerIDs); err != nil {
+		return err
+	}
+	if len(followerIDs) == 0 {
+		return nil
+	}
+
+	score := float64(createdAt.UnixMilli())
+	pipe := t.redis.Pipeline()
+	for _, id := range followerIDs {
+		key := redisTimelineKey(id)
+		pipe.ZAdd(ctx, key, redis.Z{Score: score, Member: postID})
+		pipe.ZRemRangeByRank(ctx, key, 0, -redisTimelineCap-1)
+		pipe.PExpire(ctx, key, redisTimelineTTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		t.logger.Warn("Failed to push post to Redis timelines",
+			zap.String("post_id", postID),
+			zap.Int("followers", len(followerIDs)),
+			zap.Error(err),
+		)
+	}
+	return nil
+}
+
+// Timeline reads the page from Redis when the flag is on, topping a short
+// page up from user_feeds.
+func (t *RedisTimeline) Timeline(ctx context.Context, viewerID string, cursor *time.Time, limit int) ([]string, *time.Time, error) {
+	if t.enabled == nil || !t.enabled() {
+		return t.fallback.Timeline(ctx, viewerID, cursor, limit)
+	}
+
+	key := redisTimelineKey(viewerID)
+	maxScore := "+inf"
+	if cursor != nil {
+		maxScore = "(" + strconv.FormatInt(cursor.UnixMilli(), 10)
+	}
+	entries, err := t.redis.ZRevRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   maxScore,
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		t.logger.Warn("Failed to read Redis timeline", zap.String("user_id", viewerID), zap.Error(err))
+		return t.fallback.Timeline(ctx, viewerID, cursor, limit)
+	}
+	if len(entries) == 0 {
+		// No timeline yet, or the page is past what Redis keeps.
+		return t.fallback.Timeline(ctx, viewerID, cursor, limit)
+	}
+
+	ids := make([]string, len(entries))
+	for i, e := range entries {
+		ids[i], _ = e.Member.(string)
+	}
+	last := time.UnixMilli(int64(entries[len(entries)-1].Score))
+	lastCursor := &last
+
+	if len(entries) < limit {
+		more, moreCursor, err := t.fallback.Timeline(ctx, viewerID, lastCursor, limit-len(entries))
+		if err != nil {
+			return nil, nil, err
+		}
+		ids = mergeDedupe(ids, more)
+		if moreCursor != nil {
+			lastCursor = moreCursor
+		}
+	}
+
+	// user_feeds filters shadowbanned authors at read time; entries pushed
+	// to Redis before the ban are dropped here instead.
+	ids, err = t.fanoutRepo.FilterTimelinePostIDs(ctx, viewerID, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ids, lastCursor, nil
+}
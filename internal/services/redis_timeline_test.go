@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestRedisTimeline(t *testing.T, enabled bool) (*RedisTimeline, *mocks.MockFanoutRepository, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	repo := &mocks.MockFanoutRepository{}
+	return NewRedisTimeline(rdb, repo, func() bool { return enabled }, zap.NewNop()), repo, mr
+}
+
+// keepAll makes FilterTimelinePostIDs drop none of ids.
+func keepAll(repo *mocks.MockFanoutRepository, ids ...string) {
+	repo.On("FilterTimelinePostIDs", mock.Anything, "viewer-1", ids).Return(ids, nil)
+}
+
+func TestRedisTimeline_Push(t *testing.T) {
+	tl, repo, mr := newTestRedisTimeline(t, false)
+	followers := []string{"f-1", "f-2"}
+	repo.On("InsertFeedEntries", mock.Anything, "post-1", followers).Return(nil)
+
+	require.NoError(t, tl.Push(context.Background(), "post-1", time.UnixMilli(1000), followers))
+
+	repo.AssertExpectations(t)
+	for _, f := range followers {
+		members, err := mr.ZMembers(redisTimelineKey(f))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"post-1"}, members)
+		assert.Positive(t, mr.TTL(redisTimelineKey(f)))
+	}
+}
+
+func TestRedisTimeline_Timeline(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("flag off reads user_feeds", func(t *testing.T) {
+		tl, repo, mr := newTestRedisTimeline(t, false)
+		_, _ = mr.ZAdd(redisTimelineKey("viewer-1"), 3000, "post-r")
+		repo.On("GetPersonalizedFeed", mock.Anything, "viewer-1", (*time.Time)(nil), 10).
+			Return([]string{"post-pg"}, nil, nil)
+
+		ids, _, err := tl.Timeline(ctx, "viewer-1", nil, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"post-pg"}, ids)
+	})
+
+	t.Run("full page comes from Redis newest first", func(t *testing.T) {
+		tl, repo, mr := newTestRedisTimeline(t, true)
+		key := redisTimelineKey("viewer-1")
+		_, _ = mr.ZAdd(key, 1000, "post-1")
+		_, _ = mr.ZAdd(key, 2000, "post-2")
+		_, _ = mr.ZAdd(key, 3000, "post-3")
+		keepAll(repo, "post-3", "post-2")
+		keepAll(repo, "post-1")
+
+		ids, cursor, err := tl.Timeline(ctx, "viewer-1", nil, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"post-3", "post-2"}, ids)
+		require.NotNil(t, cursor)
+		assert.Equal(t, int64(2000), cursor.UnixMilli())
+		repo.AssertNotCalled(t, "GetPersonalizedFeed", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+		ids, _, err = tl.Timeline(ctx, "viewer-1", cursor, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"post-1"}, ids)
+	})
+
+	t.Run("short page is topped up from user_feeds", func(t *testing.T) {
+		tl, repo, mr := newTestRedisTimeline(t, true)
+		_, _ = mr.ZAdd(redisTimelineKey("viewer-1"), 3000, "post-3")
+		older := time.UnixMilli(500)
+		repo.On("GetPersonalizedFeed", mock.Anything, "viewer-1", mock.MatchedBy(func(c *time.Time) bool {
+			return c != nil && c.UnixMilli() == 3000
+		}), 2).Return([]string{"post-3", "post-0"}, &older, nil)
+		keepAll(repo, "post-3", "post-0")
+
+		ids, cursor, err := tl.Timeline(ctx, "viewer-1", nil, 3)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"post-3", "post-0"}, ids)
+		assert.Equal(t, &older, cursor)
+	})
+
+	t.Run("no Redis timeline falls back", func(t *testing.T) {
+		tl, repo, _ := newTestRedisTimeline(t, true)
+		repo.On("GetPersonalizedFeed", mock.Anything, "viewer-1", (*time.Time)(nil), 10).
+			Return([]string{"post-pg"}, nil, nil)
+
+		ids, _, err := tl.Timeline(ctx, "viewer-1", nil, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"post-pg"}, ids)
+	})
+
+	t.Run("shadowbanned authors are filtered", func(t *testing.T) {
+		tl, repo, mr := newTestRedisTimeline(t, true)
+		key := redisTimelineKey("viewer-1")
+		_, _ = mr.ZAdd(key, 1000, "post-1")
+		_, _ = mr.ZAdd(key, 2000, "post-banned")
+		repo.On("FilterTimelinePostIDs", mock.Anything, "viewer-1", []string{"post-banned", "post-1"}).
+			Return([]string{"post-1"}, nil)
+
+		ids, cursor, err := tl.Timeline(ctx, "viewer-1", nil, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"post-1"}, ids)
+		assert.Equal(t, int64(1000), cursor.UnixMilli())
+	})
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/hamsaya/backend/pkg/runtimeconfig"
+	"go.uber.org/zap"
+)
+
+// SettingDigestWeekday is the runtime setting for the day (0 = Sunday) the
+// weekly digest goes out.
+const SettingDigestWeekday = "digest.weekday"
+
+// defaultDigestWeekday is Friday, the start of the Afghan weekend.
+const defaultDigestWeekday = int(time.Friday)
+
+const (
+	// trendingWindow is how far back the rollup looks.
+	trendingWindow = 7 * 24 * time.Hour
+	// trendingPerKind is how many items of each kind the rollup keeps per
+	// region; it also caps the endpoint's limit.
+	trendingPerKind = 20
+	// digestBatchSize bounds each recipient page of the digest run.
+	digestBatchSize = 500
+)
+
+// RegionalTrendingService serves the province / district trending view from
+// a scheduled rollup and sends the opt-in weekly neighborhood digest.
+type RegionalTrendingService struct {
+	repo                repositories.RegionalTrendingRepository
+	notificationService *NotificationService
+	settings            *runtimeconfig.Store
+	logger              *zap.Logger
+
+	// now is swapped in tests.
+	now func() time.Time
+}
+
+// NewRegionalTrendingService constructs the service. notificationService may
+// be nil, which disables the digest.
+func NewRegionalTrendingService(
+	repo repositories.RegionalTrendingRepository,
+	notificationService *NotificationService,
+	logger *zap.Logger,
+) *RegionalTrendingService {
+	return &RegionalTrendingService{
+		repo:                repo,
+		notificationService: notificationService,
+		logger:              logger,
+		now:                 time.Now,
+	}
+}
+
+// WithRuntimeSettings makes the digest weekday tunable at runtime.
+func (s *RegionalTrendingService) WithRuntimeSettings(store *runtimeconfig.Store) *RegionalTrendingService {
+	store.Register(runtimeconfig.Setting{
+		Key:         SettingDigestWeekday,
+		Kind:        runtimeconfig.KindInt,
+		Default:     strconv.Itoa(defaultDigestWeekday),
+		Description: "Day of the week (0 = Sunday) the weekly neighborhood digest is sent",
+	})
+	s.settings = store
+	return s
+}
+
+func (s *RegionalTrendingService) digestWeekday() time.Weekday {
+	if s.settings == nil {
+		return time.Weekday(defaultDigestWeekday)
+	}
+	return time.Weekday(s.settings.Int(SettingDigestWeekday, defaultDigestWeekday))
+}
+
+// normalizeRegion matches the rollup's region keys.
+func normalizeRegion(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// GetTrending returns the top posts, events and new businesses of a province,
+// or of one of its districts when district is set.
+func (s *RegionalTrendingService) GetTrending(ctx context.Context, province, district string, limit int) (*models.RegionalTrendingResponse, error) {
+	province, district = strings.TrimSpace(province), strings.TrimSpace(district)
+	if province == "" {
+		return nil, utils.NewBadRequestError("province is required", nil)
+	}
+	if limit <= 0 || limit > trendingPerKind {
+		limit = trendingPerKind
+	}
+	p, d := normalizeRegion(province), normalizeRegion(district)
+
+	activity, err := s.repo.GetActivity(ctx, p, d)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load regional activity", err)
+	}
+	posts, err := s.repo.ListPosts(ctx, p, d, models.TrendingKindPost, limit)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load trending posts", err)
+	}
+	events, err := s.repo.ListPosts(ctx, p, d, models.TrendingKindEvent, limit)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load trending events", err)
+	}
+	businesses, err := s.repo.ListBusinesses(ctx, p, d, limit)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load new businesses", err)
+	}
+
+	return &models.RegionalTrendingResponse{
+		Province:   province,
+		District:   district,
+		Activity:   activity,
+		Posts:      posts,
+		Events:     events,
+		Businesses: businesses,
+	}, nil
+}
+
+// RunRollup recomputes the trending rollup and, on the digest weekday, sends
+// the weekly digest. Intended to run hourly from a leader-elected job.
+func (s *RegionalTrendingService) RunRollup(ctx context.Context) error {
+	now := s.now()
+	if err := s.repo.Rollup(ctx, now.Add(-trendingWindow), trendingPerKind); err != nil {
+		return fmt.Errorf("regional trending rollup: %w", err)
+	}
+	if s.notificationService == nil || now.Weekday() != s.digestWeekday() {
+		return nil
+	}
+	sent, err := s.sendWeeklyDigest(ctx, now)
+	if sent > 0 {
+		s.logger.Info("weekly digest sent", zap.Int("recipients", sent))
+	}
+	return err
+}
+
+// digestWeek is the ISO week key digests are deduped on, e.g. "2026-W42".
+func digestWeek(t time.Time) string {
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+type digestRegion struct {
+	activity  *models.RegionalActivity
+	top       *models.TrendingPost
+	topLoaded bool
+}
+
+// sendWeeklyDigest notifies every opted-in user with a profile region about
+// the week there: the district when it had activity, the province otherwise.
+// Users whose region was quiet are skipped.
+func (s *RegionalTrendingService) sendWeeklyDigest(ctx context.Context, now time.Time) (int, error) {
+	activity, err := s.repo.ListActivity(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("load regional activity: %w", err)
+	}
+	regions := make(map[[2]string]*digestRegion, len(activity))
+	for _, a := range activity {
+		regions[[2]string{a.Province, a.District}] = &digestRegion{activity: a}
+	}
+
+	week := digestWeek(now)
+	sent := 0
+	var after *string
+	for {
+		recipients, err := s.repo.DigestRecipients(ctx, week, after, digestBatchSize)
+		if err != nil {
+			return sent, fmt.Errorf("load digest recipients: %w", err)
+		}
+		for _, r := range recipients {
+			name := r.District
+			region := regions[[2]string{normalizeRegion(r.Province), normalizeRegion(r.District)}]
+			if r.District == "" || region == nil {
+				name = r.Province
+				region = regions[[2]string{normalizeRegion(r.Province), ""}]
+			}
+			if region == nil {
+				continue
+			}
+			if !region.topLoaded {
+				region.topLoaded = true
+				if top, err := s.repo.ListPosts(ctx, region.activity.Province, region.activity.District, models.TrendingKindPost, 1); err == nil && len(top) > 0 {
+					region.top = top[0]
+				}
+			}
+			if _, err := s.notificationService.CreateNotification(ctx, digestNotification(r.UserID, name, week, region)); err != nil {
+				s.logger.Warn("failed to send weekly digest", zap.String("user_id", r.UserID), zap.Error(err))
+				continue
+			}
+			sent++
+		}
+		if len(recipients) < digestBatchSize {
+			return sent, nil
+		}
+		after = &recipients[len(recipients)-1].UserID
+	}
+}
+
+func digestNotification(userID, regionName, week string, region *digestRegion) *models.CreateNotificationRequest {
+	a := region.activity
+	title := fmt.Sprintf("This week in %s", regionName)
+	message := fmt.Sprintf("%d new posts, %d upcoming events and %d new businesses nearby.",
+		a.NewPosts, a.UpcomingEvents, a.NewBusinesses)
+	data := map[string]interface{}{
+		"type":     string(models.NotificationTypeWeeklyDigest),
+		"week":     week,
+		"province": a.Province,
+		"district": a.District,
+		"action":   "view_trending",
+	}
+	if top := region.top; top != nil {
+		if text := digestPostText(top); text != "" {
+			message += fmt.Sprintf(" Trending: %q", text)
+		}
+		data["post_id"] = top.ID
+	}
+	return &models.CreateNotificationRequest{
+		UserID:  userID,
+		Type:    models.NotificationTypeWeeklyDigest,
+		Title:   &title,
+		Message: &message,
+		Data:    data,
+	}
+}
+
+// digestPostText is the post's title, or the start of its description when
+// it has none.
+func digestPostText(post *models.TrendingPost) string {
+	if post.Title != nil && strings.TrimSpace(*post.Title) != "" {
+		return strings.TrimSpace(*post.Title)
+	}
+	if post.Description == nil {
+		return ""
+	}
+	text := []rune(strings.TrimSpace(*post.Description))
+	if len(text) > 80 {
+		return string(text[:80]) + "…"
+	}
+	return string(text)
+}
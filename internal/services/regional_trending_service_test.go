@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRegionalTrendingService_GetTrending(t *testing.T) {
+	t.Run("province is required", func(t *testing.T) {
+		svc := NewRegionalTrendingService(new(mocks.MockRegionalTrendingRepository), nil, zap.NewNop())
+		_, err := svc.GetTrending(context.Background(), "  ", "", 0)
+		var appErr *utils.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, 400, appErr.Code)
+	})
+
+	t.Run("reads the normalized region and caps the limit", func(t *testing.T) {
+		repo := new(mocks.MockRegionalTrendingRepository)
+		activity := &models.RegionalActivity{Province: "kabul", District: "khair khana", NewPosts: 12}
+		repo.On("GetActivity", mock.Anything, "kabul", "khair khana").Return(activity, nil)
+		repo.On("ListPosts", mock.Anything, "kabul", "khair khana", models.TrendingKindPost, trendingPerKind).
+			Return([]*models.TrendingPost{{ID: "post-1"}}, nil)
+		repo.On("ListPosts", mock.Anything, "kabul", "khair khana", models.TrendingKindEvent, trendingPerKind).
+			Return([]*models.TrendingPost{}, nil)
+		repo.On("ListBusinesses", mock.Anything, "kabul", "khair khana", trendingPerKind).
+			Return([]*models.TrendingBusiness{{ID: "biz-1"}}, nil)
+
+		svc := NewRegionalTrendingService(repo, nil, zap.NewNop())
+		result, err := svc.GetTrending(context.Background(), " Kabul ", "Khair Khana", 500)
+		require.NoError(t, err)
+		assert.Equal(t, "Kabul", result.Province)
+		assert.Equal(t, "Khair Khana", result.District)
+		assert.Same(t, activity, result.Activity)
+		assert.Len(t, result.Posts, 1)
+		assert.Len(t, result.Businesses, 1)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestRegionalTrendingService_RunRollup(t *testing.T) {
+	friday := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	setup := func(now time.Time) (*RegionalTrendingService, *mocks.MockRegionalTrendingRepository, *mocks.MockNotificationRepository) {
+		repo := new(mocks.MockRegionalTrendingRepository)
+		notifRepo := new(mocks.MockNotificationRepository)
+		settingsRepo := new(mocks.MockNotificationSettingsRepository)
+		notifRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Notification")).Return(nil)
+		settingsRepo.On("GetByProfileID", mock.Anything, mock.Anything).Return([]*models.NotificationSetting{}, nil)
+		notifications := NewNotificationService(notifRepo, settingsRepo, nil, nil, nil, nil, zap.NewNop())
+		repo.On("Rollup", mock.Anything, now.Add(-trendingWindow), trendingPerKind).Return(nil)
+
+		svc := NewRegionalTrendingService(repo, notifications, zap.NewNop())
+		svc.now = func() time.Time { return now }
+		return svc, repo, notifRepo
+	}
+
+	t.Run("no digest on other days", func(t *testing.T) {
+		svc, repo, notifRepo := setup(friday.AddDate(0, 0, 1))
+		require.NoError(t, svc.RunRollup(context.Background()))
+		repo.AssertNotCalled(t, "DigestRecipients", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		notifRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("digest falls back to the province and skips quiet regions", func(t *testing.T) {
+		svc, repo, notifRepo := setup(friday)
+		title := "Road cleanup on Saturday"
+		repo.On("ListActivity", mock.Anything).Return([]*models.RegionalActivity{
+			{Province: "kabul", District: "", NewPosts: 40, UpcomingEvents: 3, NewBusinesses: 2},
+			{Province: "kabul", District: "khair khana", NewPosts: 12, UpcomingEvents: 1},
+		}, nil)
+		repo.On("DigestRecipients", mock.Anything, "2026-W42", (*string)(nil), digestBatchSize).
+			Return([]*models.DigestRecipient{
+				{UserID: "user-1", Province: "Kabul", District: "Khair Khana"},
+				{UserID: "user-2", Province: "Kabul", District: "Paghman"},
+				{UserID: "user-3", Province: "Herat"},
+			}, nil)
+		repo.On("ListPosts", mock.Anything, "kabul", "khair khana", models.TrendingKindPost, 1).
+			Return([]*models.TrendingPost{{ID: "post-1", Title: &title}}, nil)
+		repo.On("ListPosts", mock.Anything, "kabul", "", models.TrendingKindPost, 1).
+			Return([]*models.TrendingPost{}, nil)
+
+		require.NoError(t, svc.RunRollup(context.Background()))
+		notifRepo.AssertNumberOfCalls(t, "Create", 2)
+		notifRepo.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+			return n.UserID == "user-1" && n.Type == models.NotificationTypeWeeklyDigest &&
+				*n.Title == "This week in Khair Khana" && n.Data["post_id"] == "post-1" &&
+				*n.Message == `12 new posts, 1 upcoming events and 0 new businesses nearby. Trending: "Road cleanup on Saturday"`
+		}))
+		notifRepo.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+			return n.UserID == "user-2" && *n.Title == "This week in Kabul" && n.Data["district"] == ""
+		}))
+		repo.AssertExpectations(t)
+	})
+}
@@ -2,8 +2,12 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/repositories"
 	"github.com/hamsaya/backend/internal/utils"
@@ -254,6 +258,14 @@ func (s *RelationshipsService) BlockUser(ctx context.Context, blockerID, blocked
 		return utils.NewNotFoundError("User not found", err)
 	}
 
+	count, err := s.relationshipsRepo.CountBlockedUsers(ctx, blockerID)
+	if err != nil {
+		return utils.NewInternalError("Failed to block user", err)
+	}
+	if count >= models.MaxBlockedUsers {
+		return utils.NewBadRequestError(fmt.Sprintf("You can block at most %d users", models.MaxBlockedUsers), nil)
+	}
+
 	// Block user (this will also remove any existing follow relationships via database triggers or manually)
 	if err := s.relationshipsRepo.BlockUser(ctx, blockerID, blockedID); err != nil {
 		s.logger.Error("Failed to block user",
@@ -276,6 +288,61 @@ func (s *RelationshipsService) BlockUser(ctx context.Context, blockerID, blocked
 	return nil
 }
 
+// BulkBlockUsers blocks up to MaxBulkBlockUsers users at once. Unknown and
+// deleted accounts, the caller and users already blocked are skipped.
+func (s *RelationshipsService) BulkBlockUsers(ctx context.Context, blockerID string, req *models.BulkBlockRequest) (*models.BulkBlockResponse, error) {
+	seen := make(map[string]bool, len(req.UserIDs))
+	ids := make([]string, 0, len(req.UserIDs))
+	for _, id := range req.UserIDs {
+		id = strings.ToLower(strings.TrimSpace(id))
+		if _, err := uuid.Parse(id); err != nil {
+			return nil, utils.NewBadRequestError("user_ids must be UUIDs", err)
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, utils.NewBadRequestError("user_ids is required", nil)
+	}
+	if len(ids) > models.MaxBulkBlockUsers {
+		return nil, utils.NewBadRequestError(fmt.Sprintf("At most %d users can be blocked at once", models.MaxBulkBlockUsers), nil)
+	}
+
+	blocked, err := s.relationshipsRepo.BlockUsers(ctx, blockerID, ids, models.MaxBlockedUsers)
+	if errors.Is(err, repositories.ErrBlockListFull) {
+		return nil, utils.NewBadRequestError(fmt.Sprintf("You can block at most %d users", models.MaxBlockedUsers), err)
+	}
+	if err != nil {
+		s.logger.Error("Failed to bulk block users", zap.String("blocker_id", blockerID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to block users", err)
+	}
+
+	s.logger.Info("Users bulk blocked",
+		zap.String("blocker_id", blockerID),
+		zap.Int("requested", len(ids)),
+		zap.Int("blocked", len(blocked)),
+	)
+	return &models.BulkBlockResponse{Blocked: len(blocked), Skipped: len(ids) - len(blocked)}, nil
+}
+
+// ExportBlockList returns every user the caller has blocked, most recent
+// first.
+func (s *RelationshipsService) ExportBlockList(ctx context.Context, blockerID string) (*models.BlockListExport, error) {
+	blocks, err := s.relationshipsRepo.GetBlockedUsers(ctx, blockerID, models.MaxBlockedUsers, 0)
+	if err != nil {
+		s.logger.Error("Failed to export block list", zap.String("blocker_id", blockerID), zap.Error(err))
+		return nil, utils.NewInternalError("Failed to export block list", err)
+	}
+	export := &models.BlockListExport{UserIDs: make([]string, 0, len(blocks)), ExportedAt: time.Now().UTC()}
+	for _, block := range blocks {
+		export.UserIDs = append(export.UserIDs, block.BlockedID)
+	}
+	export.Count = len(export.UserIDs)
+	return export, nil
+}
+
 // UnblockUser unblocks a user
 func (s *RelationshipsService) UnblockUser(ctx context.Context, blockerID, blockedID string) error {
 	// Validate that users are not the same
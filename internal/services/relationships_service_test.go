@@ -3,11 +3,14 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
 	"strings"
 	"testing"
 
 	"github.com/hamsaya/backend/internal/mocks"
 	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
 	"github.com/hamsaya/backend/internal/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -182,6 +185,7 @@ func TestRelationshipsService_BlockUser(t *testing.T) {
 			setupMocks: func(relRepo *mocks.MockRelationshipsRepository, userRepo *mocks.MockUserRepository) {
 				user := testutil.CreateTestUser("user-456", "target@example.com")
 				userRepo.On("GetByID", mock.Anything, "user-456").Return(user, nil)
+				relRepo.On("CountBlockedUsers", mock.Anything, "user-123").Return(3, nil)
 				relRepo.On("BlockUser", mock.Anything, "user-123", "user-456").Return(nil)
 				// UnfollowUser is called twice (ignoring errors), so allow any call
 				relRepo.On("UnfollowUser", mock.Anything, "user-123", "user-456").Return(nil).Maybe()
@@ -189,6 +193,17 @@ func TestRelationshipsService_BlockUser(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name:      "block list full",
+			blockerID: "user-123",
+			blockedID: "user-456",
+			setupMocks: func(relRepo *mocks.MockRelationshipsRepository, userRepo *mocks.MockUserRepository) {
+				user := testutil.CreateTestUser("user-456", "target@example.com")
+				userRepo.On("GetByID", mock.Anything, "user-456").Return(user, nil)
+				relRepo.On("CountBlockedUsers", mock.Anything, "user-123").Return(models.MaxBlockedUsers, nil)
+			},
+			expectedError: "at most",
+		},
 	}
 
 	for _, tt := range tests {
@@ -415,3 +430,48 @@ func TestRelationshipsService_GetBlockedUsers(t *testing.T) {
 		_ = result
 	})
 }
+
+func TestRelationshipsService_BulkBlockUsers(t *testing.T) {
+	id := func(n int) string { return fmt.Sprintf("00000000-0000-4000-8000-%012d", n) }
+
+	t.Run("too many", func(t *testing.T) {
+		ids := make([]string, models.MaxBulkBlockUsers+1)
+		for i := range ids {
+			ids[i] = id(i)
+		}
+		service := NewRelationshipsService(new(mocks.MockRelationshipsRepository), new(mocks.MockUserRepository), nil, zap.NewNop())
+
+		_, err := service.BulkBlockUsers(context.Background(), "user-123", &models.BulkBlockRequest{UserIDs: ids})
+		requireAppErrCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		service := NewRelationshipsService(new(mocks.MockRelationshipsRepository), new(mocks.MockUserRepository), nil, zap.NewNop())
+
+		_, err := service.BulkBlockUsers(context.Background(), "user-123", &models.BulkBlockRequest{})
+		requireAppErrCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("list full", func(t *testing.T) {
+		relRepo := new(mocks.MockRelationshipsRepository)
+		relRepo.On("BlockUsers", mock.Anything, "user-123", []string{id(1)}, models.MaxBlockedUsers).
+			Return(nil, repositories.ErrBlockListFull)
+		service := NewRelationshipsService(relRepo, new(mocks.MockUserRepository), nil, zap.NewNop())
+
+		_, err := service.BulkBlockUsers(context.Background(), "user-123", &models.BulkBlockRequest{UserIDs: []string{id(1)}})
+		requireAppErrCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("dedupes and normalises", func(t *testing.T) {
+		relRepo := new(mocks.MockRelationshipsRepository)
+		relRepo.On("BlockUsers", mock.Anything, "user-123", []string{id(1), id(2)}, models.MaxBlockedUsers).
+			Return([]string{id(2)}, nil)
+		service := NewRelationshipsService(relRepo, new(mocks.MockUserRepository), nil, zap.NewNop())
+
+		got, err := service.BulkBlockUsers(context.Background(), "user-123", &models.BulkBlockRequest{
+			UserIDs: []string{id(1), " " + strings.ToUpper(id(2)), id(1)},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &models.BulkBlockResponse{Blocked: 1, Skipped: 1}, got)
+	})
+}
@@ -2,10 +2,12 @@ package services
 
 import (
 	"context"
+	"errors"
 
 	"github.com/hamsaya/backend/internal/models"
 	"github.com/hamsaya/backend/internal/repositories"
 	"github.com/hamsaya/backend/internal/utils"
+	"github.com/hamsaya/backend/pkg/events"
 	"go.uber.org/zap"
 )
 
@@ -24,7 +26,12 @@ type ReportService struct {
 	postRepo   repositories.PostRepository
 	userRepo   repositories.UserRepository
 	validator  *utils.Validator
+	events     *events.Bus
 	logger     *zap.SugaredLogger
+
+	// Optional; message reports are rejected without them.
+	conversationRepo repositories.ConversationRepository
+	messageRepo      repositories.MessageRepository
 }
 
 // NewReportService creates a new report service
@@ -43,6 +50,19 @@ func NewReportService(
 	}
 }
 
+// WithEvents publishes ReportFiled on bus.
+func (s *ReportService) WithEvents(bus *events.Bus) *ReportService {
+	s.events = bus
+	return s
+}
+
+// WithChat enables chat message reports.
+func (s *ReportService) WithChat(conversationRepo repositories.ConversationRepository, messageRepo repositories.MessageRepository) *ReportService {
+	s.conversationRepo = conversationRepo
+	s.messageRepo = messageRepo
+	return s
+}
+
 // ReportPost creates a report for a post
 func (s *ReportService) ReportPost(ctx context.Context, userID, postID string, req *models.CreatePostReportRequest) error {
 	s.logger.Infow("Processing post report request",
@@ -84,16 +104,23 @@ func (s *ReportService) ReportPost(ctx context.Context, userID, postID string, r
 	}
 
 	if err := s.reportRepo.CreatePostReport(ctx, report); err != nil {
+		if errors.Is(err, repositories.ErrAlreadyReported) {
+			return utils.NewConflictError("You have already reported this post", err)
+		}
 		s.logger.Errorw("Failed to create post report", "user_id", userID, "post_id", postID, "error", err)
 		return utils.NewInternalServerError("Failed to create report", err)
 	}
 
 	s.logger.Infow("Post report created successfully", "user_id", userID, "post_id", postID)
 
-	// Auto-action: when this post crosses [autoHidePostThreshold] pending
-	// reports, soft-hide it (status=false). Admin can review + reinstate
-	// from the moderation queue. Best-effort — if the count or hide fails
-	// we don't bubble that to the reporting user.
+	s.events.Publish(ctx, ReportFiled{Kind: ReportKindPost, TargetID: postID, ReporterID: userID, Reason: req.Reason})
+	return nil
+}
+
+// autoHidePost soft-hides a post (status=false) once it crosses
+// [autoHidePostThreshold] pending reports. Admin can review + reinstate
+// from the moderation queue. Best-effort — failures are only logged.
+func (s *ReportService) autoHidePost(ctx context.Context, postID string) {
 	if count, cerr := s.reportRepo.CountPendingPostReports(ctx, postID); cerr == nil &&
 		count >= autoHidePostThreshold {
 		if herr := s.reportRepo.HidePost(ctx, postID); herr == nil {
@@ -101,7 +128,6 @@ func (s *ReportService) ReportPost(ctx context.Context, userID, postID string, r
 				"post_id", postID, "report_count", count, "threshold", autoHidePostThreshold)
 		}
 	}
-	return nil
 }
 
 // ReportComment creates a report for a comment
@@ -121,9 +147,18 @@ func (s *ReportService) ReportComment(ctx context.Context, userID, commentID str
 	}
 
 	if err := s.reportRepo.CreateCommentReport(ctx, report); err != nil {
+		if errors.Is(err, repositories.ErrAlreadyReported) {
+			return utils.NewConflictError("You have already reported this comment", err)
+		}
 		return utils.NewInternalServerError("Failed to create report", err)
 	}
 
+	s.events.Publish(ctx, ReportFiled{Kind: ReportKindComment, TargetID: commentID, ReporterID: userID, Reason: req.Reason})
+	return nil
+}
+
+// autoHideComment is [autoHidePost] for comments.
+func (s *ReportService) autoHideComment(ctx context.Context, commentID string) {
 	if count, cerr := s.reportRepo.CountPendingCommentReports(ctx, commentID); cerr == nil &&
 		count >= autoHideCommentThreshold {
 		if herr := s.reportRepo.HideComment(ctx, commentID); herr == nil {
@@ -131,7 +166,6 @@ func (s *ReportService) ReportComment(ctx context.Context, userID, commentID str
 				"comment_id", commentID, "report_count", count, "threshold", autoHideCommentThreshold)
 		}
 	}
-	return nil
 }
 
 // ReportUser creates a report for a user
@@ -175,11 +209,16 @@ func (s *ReportService) ReportUser(ctx context.Context, reporterID, reportedUser
 	}
 
 	if err := s.reportRepo.CreateUserReport(ctx, report); err != nil {
+		if errors.Is(err, repositories.ErrAlreadyReported) {
+			return utils.NewConflictError("You have already reported this user", err)
+		}
 		s.logger.Errorw("Failed to create user report", "reporter_id", reporterID, "reported_user_id", reportedUserID, "error", err)
 		return utils.NewInternalServerError("Failed to create report", err)
 	}
 
 	s.logger.Infow("User report created successfully", "reporter_id", reporterID, "reported_user_id", reportedUserID)
+
+	s.events.Publish(ctx, ReportFiled{Kind: ReportKindUser, TargetID: reportedUserID, ReporterID: reporterID, Reason: req.Reason})
 	return nil
 }
 
@@ -200,9 +239,65 @@ func (s *ReportService) ReportBusiness(ctx context.Context, userID, businessID s
 	}
 
 	if err := s.reportRepo.CreateBusinessReport(ctx, report); err != nil {
+		if errors.Is(err, repositories.ErrAlreadyReported) {
+			return utils.NewConflictError("You have already reported this business", err)
+		}
+		return utils.NewInternalServerError("Failed to create report", err)
+	}
+
+	s.events.Publish(ctx, ReportFiled{Kind: ReportKindBusiness, TargetID: businessID, ReporterID: userID, Reason: req.Reason})
+	return nil
+}
+
+// ReportMessage reports a chat message. Only participants of the
+// conversation can report it, and not their own messages. The report keeps
+// a copy of the message so later edits or deletion don't erase it.
+func (s *ReportService) ReportMessage(ctx context.Context, userID, messageID string, req *models.CreateMessageReportRequest) error {
+	if err := s.validator.Validate(req); err != nil {
+		return utils.NewBadRequestError("Invalid request", err)
+	}
+	if s.messageRepo == nil || s.conversationRepo == nil {
+		return utils.NewInternalServerError("Message reports are not available", nil)
+	}
+
+	message, err := s.messageRepo.GetByID(ctx, messageID)
+	if err != nil || message == nil || message.DeletedAt != nil {
+		return utils.NewNotFoundError("Message not found", err)
+	}
+	isParticipant, err := s.conversationRepo.IsParticipant(ctx, message.ConversationID, userID)
+	if err != nil {
+		return utils.NewInternalServerError("Failed to verify access", err)
+	}
+	if !isParticipant {
+		// Same answer as a missing message so ids can't be probed.
+		return utils.NewNotFoundError("Message not found", nil)
+	}
+	if message.SenderID == userID {
+		return utils.NewBadRequestError("Cannot report your own message", nil)
+	}
+
+	report := &models.MessageReport{
+		UserID:             userID,
+		MessageID:          message.ID,
+		ConversationID:     message.ConversationID,
+		SenderID:           message.SenderID,
+		MessageContent:     message.Content,
+		MessageType:        message.MessageType,
+		MessageSentAt:      message.CreatedAt,
+		Reason:             req.Reason,
+		AdditionalComments: req.AdditionalComments,
+		ReportStatus:       models.ReportStatusPending,
+	}
+
+	if err := s.reportRepo.CreateMessageReport(ctx, report); err != nil {
+		if errors.Is(err, repositories.ErrAlreadyReported) {
+			return utils.NewConflictError("You have already reported this message", err)
+		}
+		s.logger.Errorw("Failed to create message report", "user_id", userID, "message_id", messageID, "error", err)
 		return utils.NewInternalServerError("Failed to create report", err)
 	}
 
+	s.events.Publish(ctx, ReportFiled{Kind: ReportKindMessage, TargetID: messageID, ReporterID: userID, Reason: req.Reason})
 	return nil
 }
 
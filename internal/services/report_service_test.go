@@ -8,6 +8,7 @@ import (
 
 	"github.com/hamsaya/backend/internal/mocks"
 	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
 	"github.com/hamsaya/backend/internal/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -40,6 +41,21 @@ func TestReportService_ReportPost(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name:   "already reported",
+			userID: "user-123",
+			postID: "post-456",
+			request: &models.CreatePostReportRequest{
+				Reason: "Spam",
+			},
+			setupMocks: func(reportRepo *mocks.MockReportRepository, postRepo *mocks.MockPostRepository, userRepo *mocks.MockUserRepository) {
+				post := testutil.CreateTestPost("post-456", "other-user", models.PostTypeFeed)
+				postRepo.On("GetByID", mock.Anything, "post-456").Return(post, nil)
+				reportRepo.On("CreatePostReport", mock.Anything, mock.AnythingOfType("*models.PostReport")).
+					Return(repositories.ErrAlreadyReported)
+			},
+			expectedError: "You have already reported this post",
+		},
 		{
 			name:   "cannot report own post",
 			userID: "user-123",
@@ -1133,3 +1149,86 @@ func TestReportService_GetBusinessReport(t *testing.T) {
 		})
 	}
 }
+
+func TestReportService_ReportMessage(t *testing.T) {
+	content := "pay me or else"
+	message := &models.Message{
+		ID:             "msg-1",
+		ConversationID: "conv-1",
+		SenderID:       "sender-1",
+		Content:        &content,
+		MessageType:    models.MessageTypeText,
+	}
+
+	tests := []struct {
+		name          string
+		userID        string
+		setupMocks    func(*mocks.MockReportRepository, *mocks.MockConversationRepository, *mocks.MockMessageRepository)
+		expectedError string
+	}{
+		{
+			name:   "snapshots the message",
+			userID: "user-2",
+			setupMocks: func(reportRepo *mocks.MockReportRepository, convRepo *mocks.MockConversationRepository, msgRepo *mocks.MockMessageRepository) {
+				msgRepo.On("GetByID", mock.Anything, "msg-1").Return(message, nil)
+				convRepo.On("IsParticipant", mock.Anything, "conv-1", "user-2").Return(true, nil)
+				reportRepo.On("CreateMessageReport", mock.Anything, mock.MatchedBy(func(r *models.MessageReport) bool {
+					return r.SenderID == "sender-1" && r.ConversationID == "conv-1" &&
+						r.MessageContent != nil && *r.MessageContent == content
+				})).Return(nil)
+			},
+		},
+		{
+			name:   "non-participant sees not found",
+			userID: "stranger",
+			setupMocks: func(reportRepo *mocks.MockReportRepository, convRepo *mocks.MockConversationRepository, msgRepo *mocks.MockMessageRepository) {
+				msgRepo.On("GetByID", mock.Anything, "msg-1").Return(message, nil)
+				convRepo.On("IsParticipant", mock.Anything, "conv-1", "stranger").Return(false, nil)
+			},
+			expectedError: "Message not found",
+		},
+		{
+			name:   "cannot report own message",
+			userID: "sender-1",
+			setupMocks: func(reportRepo *mocks.MockReportRepository, convRepo *mocks.MockConversationRepository, msgRepo *mocks.MockMessageRepository) {
+				msgRepo.On("GetByID", mock.Anything, "msg-1").Return(message, nil)
+				convRepo.On("IsParticipant", mock.Anything, "conv-1", "sender-1").Return(true, nil)
+			},
+			expectedError: "Cannot report your own message",
+		},
+		{
+			name:   "already reported",
+			userID: "user-2",
+			setupMocks: func(reportRepo *mocks.MockReportRepository, convRepo *mocks.MockConversationRepository, msgRepo *mocks.MockMessageRepository) {
+				msgRepo.On("GetByID", mock.Anything, "msg-1").Return(message, nil)
+				convRepo.On("IsParticipant", mock.Anything, "conv-1", "user-2").Return(true, nil)
+				reportRepo.On("CreateMessageReport", mock.Anything, mock.AnythingOfType("*models.MessageReport")).
+					Return(repositories.ErrAlreadyReported)
+			},
+			expectedError: "You have already reported this message",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reportRepo := new(mocks.MockReportRepository)
+			convRepo := new(mocks.MockConversationRepository)
+			msgRepo := new(mocks.MockMessageRepository)
+			tt.setupMocks(reportRepo, convRepo, msgRepo)
+
+			service := NewReportService(reportRepo, new(mocks.MockPostRepository), new(mocks.MockUserRepository),
+				testutil.CreateTestValidator()).WithChat(convRepo, msgRepo)
+
+			err := service.ReportMessage(context.Background(), tt.userID, "msg-1",
+				&models.CreateMessageReportRequest{Reason: "Harassment"})
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			reportRepo.AssertExpectations(t)
+		})
+	}
+}
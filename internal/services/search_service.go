@@ -29,8 +29,11 @@ type SearchService struct {
 	businessRepo      repositories.BusinessRepository
 	categoryRepo      repositories.CategoryRepository
 	relationshipsRepo repositories.RelationshipsRepository
+	authorizer        *PostAuthorizer
 	logger            *zap.Logger
-	cache             *cache.Cache // optional; nil = no discover caching
+	cache             *cache.Cache                      // optional; nil = no discover caching
+	customRoleRepo    repositories.CustomRoleRepository // optional; scopes admin search
+	featured          *FeaturedBusinessService          // optional; nil = no featured businesses on discover
 }
 
 // NewSearchService creates a new search service
@@ -50,22 +53,45 @@ func NewSearchService(
 		businessRepo:      businessRepo,
 		categoryRepo:      categoryRepo,
 		relationshipsRepo: relationshipsRepo,
+		authorizer:        NewPostAuthorizer(relationshipsRepo, businessRepo),
 		logger:            logger,
 	}
 }
 
+// WithAuthorizer shares the post service's authorizer so search results
+// follow the same visibility rules (including groups) as every other read.
+func (s *SearchService) WithAuthorizer(a *PostAuthorizer) *SearchService {
+	s.authorizer = a
+	return s
+}
+
+// WithCustomRoles lets admin search narrow a moderator's scopes to their
+// custom role. Optional.
+func (s *SearchService) WithCustomRoles(r repositories.CustomRoleRepository) *SearchService {
+	s.customRoleRepo = r
+	return s
+}
+
 // WithCache attaches a cache namespace. Call once at startup. Optional.
 func (s *SearchService) WithCache(c *cache.Cache) *SearchService {
 	s.cache = c
 	return s
 }
 
+// WithFeaturedBusinesses puts the province's featured businesses at the
+// top of discover. Optional.
+func (s *SearchService) WithFeaturedBusinesses(f *FeaturedBusinessService) *SearchService {
+	s.featured = f
+	return s
+}
+
 // discoverCacheKey buckets the (lat, lng) inputs to 3 decimal places —
 // roughly 110m at the equator, smaller toward the poles — so callers in
 // the same neighbourhood hit the same cache entry. Other inputs (filter,
 // type, radius, limit) participate verbatim because their cardinality is
-// small and they radically change the result set.
-func discoverCacheKey(req *models.DiscoverRequest) string {
+// small and they radically change the result set. Signed-out and signed-in
+// viewers get separate entries since they see pins at different precision.
+func discoverCacheKey(req *models.DiscoverRequest, anon bool) string {
 	bucket := func(f float64) float64 {
 		return math.Round(f*1000) / 1000
 	}
@@ -73,8 +99,12 @@ func discoverCacheKey(req *models.DiscoverRequest) string {
 	if req.Type != nil {
 		pt = string(*req.Type)
 	}
-	return fmt.Sprintf("d:%s:%s:%.3f:%.3f:%.0f:%d",
-		req.Filter, pt, bucket(req.Latitude), bucket(req.Longitude), req.RadiusKm, req.Limit)
+	viewer := "member"
+	if anon {
+		viewer = "anon"
+	}
+	return fmt.Sprintf("d:%s:%s:%s:%.3f:%.3f:%.0f:%d",
+		viewer, req.Filter, pt, bucket(req.Latitude), bucket(req.Longitude), req.RadiusKm, req.Limit)
 }
 
 // Search performs a global search across posts, users, and businesses
@@ -88,6 +118,14 @@ func (s *SearchService) Search(ctx context.Context, userID *string, req *models.
 		Latitude:  req.Latitude,
 		Longitude: req.Longitude,
 		RadiusKm:  req.RadiusKm,
+
+		PostType:   req.PostType,
+		CategoryID: req.CategoryID,
+		Province:   req.Province,
+		Languages:  req.Languages,
+	}
+	if filter.Languages == nil && filter.Type != models.SearchTypeUsers && filter.Type != models.SearchTypeBusinesses {
+		filter.Languages = contentLanguages(ctx, s.userRepo, s.logger, userID)
 	}
 
 	// Set default limit
@@ -108,8 +146,12 @@ func (s *SearchService) Search(ctx context.Context, userID *string, req *models.
 		if err != nil {
 			return nil, utils.NewInternalError("Failed to search posts", err)
 		}
-		response.Posts = s.enrichPosts(ctx, posts, userID)
+		response.Posts = s.enrichPosts(ctx, s.authorizer.FilterVisible(ctx, posts, userID), userID)
+		s.highlightPosts(ctx, response.Posts, req.Query)
 		response.Total = len(response.Posts)
+		if req.IncludeFacets {
+			response.Facets = s.postFacets(ctx, filter)
+		}
 
 	case models.SearchTypeUsers:
 		profiles, err := s.searchRepo.SearchUsers(ctx, filter)
@@ -132,7 +174,11 @@ func (s *SearchService) Search(ctx context.Context, userID *string, req *models.
 		filter.Limit = 10
 
 		posts, _ := s.searchRepo.SearchPosts(ctx, filter)
-		response.Posts = s.enrichPosts(ctx, posts, userID)
+		response.Posts = s.enrichPosts(ctx, s.authorizer.FilterVisible(ctx, posts, userID), userID)
+		s.highlightPosts(ctx, response.Posts, req.Query)
+		if req.IncludeFacets {
+			response.Facets = s.postFacets(ctx, filter)
+		}
 
 		profiles, _ := s.searchRepo.SearchUsers(ctx, filter)
 		response.Users = s.enrichUsers(ctx, profiles, userID)
@@ -143,6 +189,8 @@ func (s *SearchService) Search(ctx context.Context, userID *string, req *models.
 		response.Total = len(response.Posts) + len(response.Users) + len(response.Businesses)
 	}
 
+	s.recordRecentSearch(userID, req.Query)
+
 	s.logger.Info("Search completed",
 		zap.String("query", req.Query),
 		zap.String("type", string(req.Type)),
@@ -152,6 +200,41 @@ func (s *SearchService) Search(ctx context.Context, userID *string, req *models.
 	return response, nil
 }
 
+// minFullTextQuery is the shortest query matched with full-text search;
+// shorter ones use substring matching, which ts_headline can't highlight.
+const minFullTextQuery = 3
+
+// highlightPosts attaches ts_headline snippets to post results.
+// Best-effort: on failure the results go out without highlights.
+func (s *SearchService) highlightPosts(ctx context.Context, posts []*models.PostResponse, query string) {
+	if len(posts) == 0 || len(query) < minFullTextQuery {
+		return
+	}
+	ids := make([]string, len(posts))
+	for i, p := range posts {
+		ids[i] = p.ID
+	}
+	highlights, err := s.searchRepo.GetPostHighlights(ctx, ids, query)
+	if err != nil {
+		s.logger.Warn("Failed to highlight search results", zap.Error(err))
+		return
+	}
+	for _, p := range posts {
+		p.Highlight = highlights[p.ID]
+	}
+}
+
+// postFacets counts post results per type, category and province. nil on
+// failure, so the response simply has no facets.
+func (s *SearchService) postFacets(ctx context.Context, filter *models.SearchFilter) *models.SearchFacets {
+	facets, err := s.searchRepo.GetPostSearchFacets(ctx, filter)
+	if err != nil {
+		s.logger.Warn("Failed to count search facets", zap.Error(err))
+		return nil
+	}
+	return facets
+}
+
 // Discover performs location-based discovery for map view.
 // Filter: all = posts (EVENT+SELL) + businesses; business = only businesses; event = only EVENT posts; sell = only SELL posts.
 func (s *SearchService) Discover(ctx context.Context, userID *string, req *models.DiscoverRequest) (*models.DiscoverResponse, error) {
@@ -164,12 +247,15 @@ func (s *SearchService) Discover(ctx context.Context, userID *string, req *model
 
 	// Cache lookup — discover response is intentionally viewer-agnostic
 	// (no liked-by-me / following fields in the markers), so all viewers
-	// in the same geographic bucket share a single cache entry. Massive
-	// hit rate on the Discover tab cold-open and radius-slider drag.
-	cacheKey := discoverCacheKey(req)
+	// of the same kind (signed-out or signed-in) in the same geographic
+	// bucket share a single cache entry. Massive hit rate on the Discover
+	// tab cold-open and radius-slider drag.
+	anon := userID == nil || *userID == ""
+	cacheKey := discoverCacheKey(req, anon)
 	if s.cache != nil {
 		var cached models.DiscoverResponse
 		if hit, _ := s.cache.Get(ctx, cacheKey, &cached); hit {
+			cached.Featured = s.discoverFeatured(ctx, userID, req)
 			return &cached, nil
 		}
 	}
@@ -200,7 +286,12 @@ func (s *SearchService) Discover(ctx context.Context, userID *string, req *model
 		if err != nil {
 			s.logger.Error("Failed to get discover posts", zap.Error(err))
 		} else {
-			response.Posts = s.enrichDiscoverPosts(ctx, posts, userID == nil || *userID == "")
+			// The response is shared by every viewer of this kind, so it gets
+			// the signed-out visibility and the non-owner pin precision —
+			// even a post's owner sees their own pin coarsened here, or
+			// whoever filled the cache would decide what everyone sees.
+			precision := locationPrecisionFor(false, userID)
+			response.Posts = s.enrichDiscoverPosts(ctx, s.authorizer.FilterVisible(ctx, posts, nil), precision)
 		}
 	}
 
@@ -210,7 +301,7 @@ func (s *SearchService) Discover(ctx context.Context, userID *string, req *model
 		if err != nil {
 			s.logger.Error("Failed to get discover businesses", zap.Error(err))
 		} else {
-			response.Businesses = s.enrichDiscoverBusinesses(ctx, businesses, userID == nil || *userID == "")
+			response.Businesses = s.enrichDiscoverBusinesses(ctx, businesses, anon)
 		}
 	}
 
@@ -226,10 +317,29 @@ func (s *SearchService) Discover(ctx context.Context, userID *string, req *model
 	if s.cache != nil {
 		_ = s.cache.Set(ctx, cacheKey, response, discoverTTL)
 	}
+	// Featured businesses stay out of the shared cache: placements start and
+	// end on their own schedule and the province can come from the viewer.
+	response.Featured = s.discoverFeatured(ctx, userID, req)
 
 	return response, nil
 }
 
+// discoverFeatured returns the featured businesses for a discover request:
+// those of the requested province, else of the viewer's own province. Only
+// the all and business filters show them.
+func (s *SearchService) discoverFeatured(ctx context.Context, userID *string, req *models.DiscoverRequest) []*models.FeaturedBusinessCard {
+	if s.featured == nil || (req.Filter != "" && req.Filter != models.DiscoverFilterAll && req.Filter != models.DiscoverFilterBusiness) {
+		return nil
+	}
+	province := req.Province
+	if province == "" && userID != nil && *userID != "" {
+		if profile, err := s.userRepo.GetProfileByUserID(ctx, *userID); err == nil && profile.Province != nil {
+			province = *profile.Province
+		}
+	}
+	return s.featured.Featured(ctx, province, userID, 0)
+}
+
 // enrichPosts enriches post search results
 func (s *SearchService) enrichPosts(ctx context.Context, posts []*models.Post, userID *string) []*models.PostResponse {
 	var responses []*models.PostResponse
@@ -282,6 +392,7 @@ func (s *SearchService) enrichPosts(ctx context.Context, posts []*models.Post, u
 			Type:           post.Type,
 			Title:          post.Title,
 			Description:    post.Description,
+			Lang:           post.Lang,
 			Visibility:     post.Visibility,
 			Status:         post.Status,
 			Attachments:    []models.AttachmentResponse{}, // Will be populated if needed
@@ -355,7 +466,25 @@ func (s *SearchService) enrichUsers(ctx context.Context, profiles []*models.Prof
 func (s *SearchService) enrichBusinesses(ctx context.Context, businesses []*models.BusinessProfile, userID *string) []*models.BusinessSearchResult {
 	var results []*models.BusinessSearchResult
 
+	categoriesByBusiness := map[string][]string{}
+	if len(businesses) > 0 {
+		ids := make([]string, 0, len(businesses))
+		for _, b := range businesses {
+			ids = append(ids, b.ID)
+		}
+		if c, err := s.businessRepo.GetCategoriesByBusinessIDs(ctx, ids); err == nil {
+			categoriesByBusiness = c
+		} else {
+			s.logger.Warn("Failed to batch-load business categories for search", zap.Error(err))
+		}
+	}
+
 	for _, business := range businesses {
+		categories := categoriesByBusiness[business.ID]
+		if categories == nil {
+			categories = []string{}
+		}
+
 		// Extract location
 		var location *models.Location
 		if business.AddressLocation != nil {
@@ -375,11 +504,14 @@ func (s *SearchService) enrichBusinesses(ctx context.Context, businesses []*mode
 			Address:     business.Address,
 			PhoneNumber: business.PhoneNumber,
 			Website:     business.Website,
-			Categories:  []string{}, // Categories will be empty for now
+			Categories:  categories,
 			Location:    location,
 			TotalFollow: business.TotalFollow,
 			TotalViews:  business.TotalViews,
 			IsFollowing: false, // Can be enriched if needed
+
+			Distance:      business.DistanceKm,
+			NearestBranch: business.NearestBranch,
 		}
 
 		results = append(results, result)
@@ -391,7 +523,7 @@ func (s *SearchService) enrichBusinesses(ctx context.Context, businesses []*mode
 // enrichDiscoverPosts enriches discover post results. Fetches first
 // attachment per post in a single batched query so the mobile client doesn't
 // have to issue one /posts/{id}/attachments request per marker card.
-func (s *SearchService) enrichDiscoverPosts(ctx context.Context, posts []*models.Post, anon bool) []*models.DiscoverPost {
+func (s *SearchService) enrichDiscoverPosts(ctx context.Context, posts []*models.Post, precision locationPrecision) []*models.DiscoverPost {
 	results := make([]*models.DiscoverPost, 0, len(posts))
 
 	// Batched fetch of first attachment per post.
@@ -411,13 +543,7 @@ func (s *SearchService) enrichDiscoverPosts(ctx context.Context, posts []*models
 	for _, post := range posts {
 		var location *models.Location
 		if post.AddressLocation != nil && post.AddressLocation.Valid {
-			lat, lng := post.AddressLocation.P.Y, post.AddressLocation.P.X
-			if anon {
-				// Anonymous map browsing gets area-level pins (~1 km), not
-				// exact seller/home coordinates.
-				lat = math.Round(lat*100) / 100
-				lng = math.Round(lng*100) / 100
-			}
+			lat, lng := precision.apply(post.AddressLocation.P.Y, post.AddressLocation.P.X)
 			location = &models.Location{
 				Latitude:  lat,
 				Longitude: lng,
@@ -485,12 +611,20 @@ func (s *SearchService) enrichDiscoverBusinesses(ctx context.Context, businesses
 	}
 
 	for _, business := range businesses {
+		// The pin goes on the nearest branch when it beats the main address.
 		var location *models.Location
-		if business.AddressLocation != nil && business.AddressLocation.Valid {
+		nearest := business.NearestBranch
+		if nearest != nil {
+			branch := *nearest
+			if anon {
+				branch.Latitude, branch.Longitude = areaLocation.apply(branch.Latitude, branch.Longitude)
+			}
+			nearest = &branch
+			location = &models.Location{Latitude: branch.Latitude, Longitude: branch.Longitude}
+		} else if business.AddressLocation != nil && business.AddressLocation.Valid {
 			lat, lng := business.AddressLocation.P.Y, business.AddressLocation.P.X
 			if anon {
-				lat = math.Round(lat*100) / 100
-				lng = math.Round(lng*100) / 100
+				lat, lng = areaLocation.apply(lat, lng)
 			}
 			location = &models.Location{
 				Latitude:  lat,
@@ -500,6 +634,10 @@ func (s *SearchService) enrichDiscoverBusinesses(ctx context.Context, businesses
 				District:  business.District,
 			}
 		}
+		var distance float64
+		if business.DistanceKm != nil {
+			distance = *business.DistanceKm
+		}
 
 		categories := categoriesByBusiness[business.ID]
 		if categories == nil {
@@ -516,6 +654,9 @@ func (s *SearchService) enrichDiscoverBusinesses(ctx context.Context, businesses
 			Location:    location,
 			Categories:  categories,
 			TotalFollow: business.TotalFollow,
+
+			Distance:      distance,
+			NearestBranch: nearest,
 		}
 
 		results = append(results, result)
@@ -5,8 +5,12 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/hamsaya/backend/internal/mocks"
 	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/cache"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -33,9 +37,12 @@ func TestSearchService_Search(t *testing.T) {
 		categoryRepo := &mocks.MockCategoryRepository{}
 		relRepo := &mocks.MockRelationshipsRepository{}
 
-		posts := []*models.Post{{ID: "p-1", Type: models.PostTypeFeed}}
+		posts := []*models.Post{{ID: "p-1", Type: models.PostTypeFeed, Status: true}}
 		searchRepo.On("SearchPosts", mock.Anything, mock.AnythingOfType("*models.SearchFilter")).
 			Return(posts, nil)
+		title := "A <mark>test</mark> post"
+		searchRepo.On("GetPostHighlights", mock.Anything, []string{"p-1"}, "test").
+			Return(map[string]*models.SearchHighlight{"p-1": {Title: &title}}, nil)
 
 		svc := newTestSearchService(searchRepo, postRepo, userRepo, businessRepo, categoryRepo, relRepo)
 		resp, err := svc.Search(context.Background(), nil, &models.SearchRequest{
@@ -45,6 +52,9 @@ func TestSearchService_Search(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, 1, resp.Total)
 		assert.Len(t, resp.Posts, 1)
+		require.NotNil(t, resp.Posts[0].Highlight)
+		assert.Equal(t, title, *resp.Posts[0].Highlight.Title)
+		assert.Nil(t, resp.Facets)
 		searchRepo.AssertExpectations(t)
 	})
 
@@ -100,6 +110,8 @@ func TestSearchService_Search(t *testing.T) {
 
 		businesses := []*models.BusinessProfile{{ID: "biz-1", Name: "Test Biz"}}
 		searchRepo.On("SearchBusinesses", mock.Anything, mock.Anything).Return(businesses, nil)
+		businessRepo.On("GetCategoriesByBusinessIDs", mock.Anything, []string{"biz-1"}).
+			Return(map[string][]string{"biz-1": {"Bakery"}}, nil)
 
 		svc := newTestSearchService(searchRepo, postRepo, userRepo, businessRepo, categoryRepo, relRepo)
 		resp, err := svc.Search(context.Background(), nil, &models.SearchRequest{
@@ -108,7 +120,8 @@ func TestSearchService_Search(t *testing.T) {
 
 		require.NoError(t, err)
 		assert.Equal(t, 1, resp.Total)
-		assert.Len(t, resp.Businesses, 1)
+		require.Len(t, resp.Businesses, 1)
+		assert.Equal(t, []string{"Bakery"}, resp.Businesses[0].Categories)
 	})
 
 	t.Run("search all types", func(t *testing.T) {
@@ -120,7 +133,9 @@ func TestSearchService_Search(t *testing.T) {
 		relRepo := &mocks.MockRelationshipsRepository{}
 
 		searchRepo.On("SearchPosts", mock.Anything, mock.Anything).
-			Return([]*models.Post{{ID: "p-1", Type: models.PostTypeFeed}}, nil)
+			Return([]*models.Post{{ID: "p-1", Type: models.PostTypeFeed, Status: true}}, nil)
+		searchRepo.On("GetPostHighlights", mock.Anything, []string{"p-1"}, "test").
+			Return(map[string]*models.SearchHighlight{}, nil)
 		searchRepo.On("SearchUsers", mock.Anything, mock.Anything).
 			Return([]*models.Profile{}, nil)
 		searchRepo.On("SearchBusinesses", mock.Anything, mock.Anything).
@@ -136,6 +151,54 @@ func TestSearchService_Search(t *testing.T) {
 		searchRepo.AssertExpectations(t)
 	})
 
+	t.Run("facets ignore the selected chip", func(t *testing.T) {
+		searchRepo := &mocks.MockSearchRepository{}
+		postRepo := &mocks.MockPostRepository{}
+		userRepo := new(mocks.MockUserRepository)
+		businessRepo := &mocks.MockBusinessRepository{}
+		categoryRepo := &mocks.MockCategoryRepository{}
+		relRepo := &mocks.MockRelationshipsRepository{}
+
+		sell := models.PostTypeSell
+		searchRepo.On("SearchPosts", mock.Anything, mock.MatchedBy(func(f *models.SearchFilter) bool {
+			return f.PostType != nil && *f.PostType == sell
+		})).Return([]*models.Post{}, nil)
+		facets := &models.SearchFacets{Types: []*models.FacetCount{{Value: "SELL", Count: 4}, {Value: "FEED", Count: 2}}}
+		searchRepo.On("GetPostSearchFacets", mock.Anything, mock.Anything).Return(facets, nil)
+
+		svc := newTestSearchService(searchRepo, postRepo, userRepo, businessRepo, categoryRepo, relRepo)
+		resp, err := svc.Search(context.Background(), nil, &models.SearchRequest{
+			Query: "bike", Type: models.SearchTypePosts, PostType: &sell, IncludeFacets: true,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, facets, resp.Facets)
+		searchRepo.AssertNotCalled(t, "GetPostHighlights", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("highlight failure still returns results", func(t *testing.T) {
+		searchRepo := &mocks.MockSearchRepository{}
+		postRepo := &mocks.MockPostRepository{}
+		userRepo := new(mocks.MockUserRepository)
+		businessRepo := &mocks.MockBusinessRepository{}
+		categoryRepo := &mocks.MockCategoryRepository{}
+		relRepo := &mocks.MockRelationshipsRepository{}
+
+		searchRepo.On("SearchPosts", mock.Anything, mock.Anything).
+			Return([]*models.Post{{ID: "p-1", Type: models.PostTypeFeed, Status: true}}, nil)
+		searchRepo.On("GetPostHighlights", mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, errors.New("db error"))
+
+		svc := newTestSearchService(searchRepo, postRepo, userRepo, businessRepo, categoryRepo, relRepo)
+		resp, err := svc.Search(context.Background(), nil, &models.SearchRequest{
+			Query: "test", Type: models.SearchTypePosts,
+		})
+
+		require.NoError(t, err)
+		require.Len(t, resp.Posts, 1)
+		assert.Nil(t, resp.Posts[0].Highlight)
+	})
+
 	t.Run("default limit applied", func(t *testing.T) {
 		searchRepo := &mocks.MockSearchRepository{}
 		postRepo := &mocks.MockPostRepository{}
@@ -170,7 +233,7 @@ func TestSearchService_Discover(t *testing.T) {
 		relRepo := &mocks.MockRelationshipsRepository{}
 
 		searchRepo.On("GetDiscoverPosts", mock.Anything, 34.5, 69.2, 10.0, (*models.PostType)(nil), 100).
-			Return([]*models.Post{{ID: "p-1", Type: models.PostTypeEvent}}, nil)
+			Return([]*models.Post{{ID: "p-1", Type: models.PostTypeEvent, Status: true}}, nil)
 		searchRepo.On("GetDiscoverBusinesses", mock.Anything, 34.5, 69.2, 10.0, 100).
 			Return([]*models.BusinessProfile{{ID: "biz-1", Name: "Biz"}}, nil)
 		postRepo.On("GetAttachmentsByPostIDs", mock.Anything, []string{"p-1"}).
@@ -238,6 +301,78 @@ func TestSearchService_Discover(t *testing.T) {
 		searchRepo.AssertNotCalled(t, "GetDiscoverPosts")
 	})
 
+	t.Run("cached pins never carry the owner's exact location", func(t *testing.T) {
+		searchRepo := &mocks.MockSearchRepository{}
+		postRepo := &mocks.MockPostRepository{}
+		owner := "owner-1"
+		sellType := models.PostTypeSell
+		searchRepo.On("GetDiscoverPosts", mock.Anything, 34.5, 69.2, 5.0, &sellType, 100).
+			Return([]*models.Post{{
+				ID: "p-1", Type: models.PostTypeSell, Status: true, UserID: &owner,
+				AddressLocation: &pgtype.Point{P: pgtype.Vec2{X: 69.207123, Y: 34.512345}, Valid: true},
+			}}, nil)
+		postRepo.On("GetAttachmentsByPostIDs", mock.Anything, []string{"p-1"}).
+			Return(map[string][]*models.Attachment{}, nil)
+
+		mr := miniredis.RunT(t)
+		rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+		svc := newTestSearchService(searchRepo, postRepo, new(mocks.MockUserRepository), &mocks.MockBusinessRepository{}, &mocks.MockCategoryRepository{}, &mocks.MockRelationshipsRepository{}).
+			WithCache(cache.New(rdb, "search", zap.NewNop()))
+		req := func() *models.DiscoverRequest {
+			return &models.DiscoverRequest{Latitude: 34.5, Longitude: 69.2, RadiusKm: 5.0, Filter: models.DiscoverFilterSell}
+		}
+
+		// The owner warms the cache first.
+		mine, err := svc.Discover(context.Background(), &owner, req())
+		require.NoError(t, err)
+		require.Len(t, mine.Posts, 1)
+		assert.NotEqual(t, 34.512345, mine.Posts[0].Location.Latitude)
+
+		anon, err := svc.Discover(context.Background(), nil, req())
+		require.NoError(t, err)
+		require.Len(t, anon.Posts, 1)
+		assert.Equal(t, 34.51, anon.Posts[0].Location.Latitude)
+		assert.Equal(t, 69.21, anon.Posts[0].Location.Longitude)
+
+		// Signed-in viewers share the owner's (coarsened) entry.
+		other := "viewer-2"
+		theirs, err := svc.Discover(context.Background(), &other, req())
+		require.NoError(t, err)
+		assert.Equal(t, mine.Posts[0].Location, theirs.Posts[0].Location)
+		searchRepo.AssertNumberOfCalls(t, "GetDiscoverPosts", 2)
+	})
+
+	t.Run("business pinned at its nearest branch", func(t *testing.T) {
+		searchRepo := &mocks.MockSearchRepository{}
+		businessRepo := &mocks.MockBusinessRepository{}
+
+		distance := 1.25
+		searchRepo.On("GetDiscoverBusinesses", mock.Anything, 34.5, 69.2, 5.0, 100).
+			Return([]*models.BusinessProfile{{
+				ID: "biz-1", Name: "Biz", DistanceKm: &distance,
+				NearestBranch: &models.NearestBranch{ID: "br-1", Name: "Karte-4", Latitude: 34.5123, Longitude: 69.2071, DistanceKm: distance},
+			}}, nil)
+		businessRepo.On("GetCategoriesByBusinessIDs", mock.Anything, []string{"biz-1"}).
+			Return(map[string][]string{}, nil)
+
+		svc := newTestSearchService(searchRepo, &mocks.MockPostRepository{}, new(mocks.MockUserRepository), businessRepo, &mocks.MockCategoryRepository{}, &mocks.MockRelationshipsRepository{})
+		resp, err := svc.Discover(context.Background(), nil, &models.DiscoverRequest{
+			Latitude: 34.5, Longitude: 69.2, RadiusKm: 5.0,
+			Filter: models.DiscoverFilterBusiness,
+		})
+
+		require.NoError(t, err)
+		require.Len(t, resp.Businesses, 1)
+		biz := resp.Businesses[0]
+		assert.Equal(t, distance, biz.Distance)
+		require.NotNil(t, biz.NearestBranch)
+		assert.Equal(t, "br-1", biz.NearestBranch.ID)
+		// Anonymous viewers get area-level pins for branches too.
+		assert.Equal(t, 34.51, biz.Location.Latitude)
+		assert.Equal(t, 69.21, biz.Location.Longitude)
+		assert.Equal(t, 34.51, biz.NearestBranch.Latitude)
+	})
+
 	t.Run("default limit 100 applied", func(t *testing.T) {
 		searchRepo := &mocks.MockSearchRepository{}
 		postRepo := &mocks.MockPostRepository{}
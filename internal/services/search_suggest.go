@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/hamsaya/backend/pkg/bgtasks"
+	"github.com/hamsaya/backend/pkg/events"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultSuggestLimit = 5
+	maxSuggestLimit     = 10
+	// minSuggestPrefix: shorter prefixes only return recent searches; one
+	// letter matches too much to be useful and defeats the trigram index.
+	minSuggestPrefix = 2
+)
+
+// Suggest returns autocomplete entries for q, up to limit per kind: the
+// caller's matching recent searches plus users, businesses, categories and
+// hashtags ranked by popularity. A leading '#' restricts to hashtags.
+// A failing kind is logged and left empty rather than failing the lookup.
+func (s *SearchService) Suggest(ctx context.Context, userID *string, q string, limit int) (*models.SearchSuggestResponse, error) {
+	if limit <= 0 {
+		limit = defaultSuggestLimit
+	}
+	if limit > maxSuggestLimit {
+		limit = maxSuggestLimit
+	}
+
+	resp := &models.SearchSuggestResponse{
+		Recent:     []*models.RecentSearch{},
+		Users:      []*models.SearchSuggestion{},
+		Businesses: []*models.SearchSuggestion{},
+		Categories: []*models.SearchSuggestion{},
+		Hashtags:   []*models.SearchSuggestion{},
+	}
+	prefix := models.NormalizeSearchQuery(q)
+	signedIn := userID != nil && *userID != ""
+
+	if signedIn {
+		if recent, err := s.searchRepo.ListRecentSearches(ctx, *userID, prefix, limit); err != nil {
+			s.logger.Warn("Failed to list recent searches", zap.String("user_id", *userID), zap.Error(err))
+		} else {
+			resp.Recent = recent
+		}
+	}
+
+	hashtagOnly := strings.HasPrefix(prefix, "#")
+	tagPrefix := strings.TrimLeft(prefix, "#")
+	if len([]rune(tagPrefix)) < minSuggestPrefix {
+		return resp, nil
+	}
+
+	if tags, err := s.searchRepo.SuggestHashtags(ctx, tagPrefix, limit); err != nil {
+		s.logger.Warn("Failed to suggest hashtags", zap.Error(err))
+	} else {
+		resp.Hashtags = tags
+	}
+	if hashtagOnly {
+		return resp, nil
+	}
+
+	var viewer *string
+	if signedIn {
+		viewer = userID
+	}
+	if users, err := s.searchRepo.SuggestUsers(ctx, prefix, viewer, limit); err != nil {
+		s.logger.Warn("Failed to suggest users", zap.Error(err))
+	} else {
+		resp.Users = users
+	}
+	if businesses, err := s.searchRepo.SuggestBusinesses(ctx, prefix, limit); err != nil {
+		s.logger.Warn("Failed to suggest businesses", zap.Error(err))
+	} else {
+		resp.Businesses = businesses
+	}
+	if categories, err := s.searchRepo.SuggestCategories(ctx, prefix, limit); err != nil {
+		s.logger.Warn("Failed to suggest categories", zap.Error(err))
+	} else {
+		resp.Categories = categories
+	}
+
+	return resp, nil
+}
+
+// ListRecentSearches returns the user's recent searches, newest first.
+func (s *SearchService) ListRecentSearches(ctx context.Context, userID string) ([]*models.RecentSearch, error) {
+	searches, err := s.searchRepo.ListRecentSearches(ctx, userID, "", maxSuggestLimit*2)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to get recent searches", err)
+	}
+	return searches, nil
+}
+
+// ClearRecentSearches deletes the user's recent searches.
+func (s *SearchService) ClearRecentSearches(ctx context.Context, userID string) error {
+	if err := s.searchRepo.ClearRecentSearches(ctx, userID); err != nil {
+		return utils.NewInternalError("Failed to clear recent searches", err)
+	}
+	return nil
+}
+
+// recordRecentSearch stores query in the user's recent searches in the
+// background. Best-effort.
+func (s *SearchService) recordRecentSearch(userID *string, query string) {
+	query = models.NormalizeSearchQuery(query)
+	if userID == nil || *userID == "" || query == "" {
+		return
+	}
+	uid := *userID
+	bgtasks.Submit(func(ctx context.Context) {
+		if err := s.searchRepo.AddRecentSearch(ctx, uid, query); err != nil {
+			s.logger.Warn("Failed to record recent search", zap.String("user_id", uid), zap.Error(err))
+		}
+	})
+}
+
+// SubscribeHashtags counts the hashtags of new public posts for hashtag
+// suggestions. Group, friends-only and private posts are skipped so their
+// tags don't leak through autocomplete.
+func (s *SearchService) SubscribeHashtags(bus *events.Bus) {
+	events.Subscribe(bus, func(ctx context.Context, e PostCreated) {
+		p := e.Post
+		if p.GroupID != nil || !p.Status ||
+			(p.Visibility != models.VisibilityPublic && p.Visibility != models.VisibilityViewOnly) {
+			return
+		}
+		tags := models.ExtractHashtags(p.Title, p.Description)
+		if err := s.searchRepo.IncrementHashtags(ctx, tags); err != nil {
+			s.logger.Warn("Failed to count hashtags", zap.String("post_id", p.ID), zap.Error(err))
+		}
+	})
+}
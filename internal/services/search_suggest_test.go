@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newSuggestService(searchRepo *mocks.MockSearchRepository) *SearchService {
+	return newTestSearchService(searchRepo, new(mocks.MockPostRepository), new(mocks.MockUserRepository),
+		new(mocks.MockBusinessRepository), new(mocks.MockCategoryRepository), new(mocks.MockRelationshipsRepository))
+}
+
+func TestSearchService_Suggest(t *testing.T) {
+	userID := "user-1"
+
+	t.Run("short prefix returns recent searches only", func(t *testing.T) {
+		searchRepo := new(mocks.MockSearchRepository)
+		searchRepo.On("ListRecentSearches", mock.Anything, userID, "k", 5).
+			Return([]*models.RecentSearch{{Query: "kabul bakery"}}, nil)
+
+		got, err := newSuggestService(searchRepo).Suggest(context.Background(), &userID, " K", 0)
+		require.NoError(t, err)
+		assert.Len(t, got.Recent, 1)
+		assert.Empty(t, got.Users)
+		searchRepo.AssertNotCalled(t, "SuggestUsers", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("hash prefix returns hashtags only", func(t *testing.T) {
+		searchRepo := new(mocks.MockSearchRepository)
+		searchRepo.On("SuggestHashtags", mock.Anything, "nowruz", 5).
+			Return([]*models.SearchSuggestion{{Kind: models.SuggestionHashtag, Label: "nowruz", Popularity: 12}}, nil)
+
+		got, err := newSuggestService(searchRepo).Suggest(context.Background(), nil, "#Nowruz", 0)
+		require.NoError(t, err)
+		assert.Len(t, got.Hashtags, 1)
+		searchRepo.AssertNotCalled(t, "SuggestBusinesses", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("a failing kind is left empty", func(t *testing.T) {
+		searchRepo := new(mocks.MockSearchRepository)
+		searchRepo.On("SuggestHashtags", mock.Anything, "ahm", 10).Return([]*models.SearchSuggestion{}, nil)
+		searchRepo.On("SuggestUsers", mock.Anything, "ahm", (*string)(nil), 10).
+			Return([]*models.SearchSuggestion{{Kind: models.SuggestionUser, ID: "u-2", Label: "Ahmad Karimi"}}, nil)
+		searchRepo.On("SuggestBusinesses", mock.Anything, "ahm", 10).Return(nil, errors.New("db down"))
+		searchRepo.On("SuggestCategories", mock.Anything, "ahm", 10).Return([]*models.SearchSuggestion{}, nil)
+
+		got, err := newSuggestService(searchRepo).Suggest(context.Background(), nil, "Ahm", 50)
+		require.NoError(t, err)
+		assert.Len(t, got.Users, 1)
+		assert.NotNil(t, got.Businesses)
+		assert.Empty(t, got.Businesses)
+		assert.Empty(t, got.Recent)
+	})
+}
+
+func TestSearchService_SubscribeHashtags(t *testing.T) {
+	desc := "Fresh bread for #Nowruz at #kabul_bakery #nowruz"
+	publicPost := &models.Post{ID: "p-1", Status: true, Visibility: models.VisibilityPublic, Description: &desc}
+	friendsPost := &models.Post{ID: "p-2", Status: true, Visibility: models.VisibilityFriends, Description: &desc}
+
+	searchRepo := new(mocks.MockSearchRepository)
+	searchRepo.On("IncrementHashtags", mock.Anything, []string{"nowruz", "kabul_bakery"}).Return(nil).Once()
+
+	bus := events.NewSync(nil)
+	newSuggestService(searchRepo).SubscribeHashtags(bus)
+	bus.Publish(context.Background(), PostCreated{Post: publicPost})
+	bus.Publish(context.Background(), PostCreated{Post: friendsPost})
+
+	searchRepo.AssertExpectations(t)
+}
+
+func TestExtractHashtags(t *testing.T) {
+	title := "#Sale"
+	desc := "نان تازه #نوروز و #sale"
+	assert.Equal(t, []string{"sale", "نوروز"}, models.ExtractHashtags(&title, nil, &desc))
+	assert.Empty(t, models.ExtractHashtags(nil))
+}
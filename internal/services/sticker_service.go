@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// StickerService serves the chat sticker catalog. Packs are curated by
+// admins; users only pick stickers from active packs.
+type StickerService struct {
+	stickerRepo repositories.StickerRepository
+	logger      *zap.Logger
+}
+
+// NewStickerService wires the sticker service.
+func NewStickerService(stickerRepo repositories.StickerRepository, logger *zap.Logger) *StickerService {
+	return &StickerService{stickerRepo: stickerRepo, logger: logger}
+}
+
+// Catalog returns the active packs with their stickers, for the picker.
+func (s *StickerService) Catalog(ctx context.Context) ([]*models.StickerPack, error) {
+	packs, err := s.stickerRepo.ListPacks(ctx, false)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load stickers", err)
+	}
+	return packs, nil
+}
+
+// ListPacks returns every pack, hidden ones included, for admins.
+func (s *StickerService) ListPacks(ctx context.Context) ([]*models.StickerPack, error) {
+	packs, err := s.stickerRepo.ListPacks(ctx, true)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load sticker packs", err)
+	}
+	return packs, nil
+}
+
+// CreatePack adds an active pack; stickers keep the order they were given in.
+func (s *StickerService) CreatePack(ctx context.Context, req *models.CreateStickerPackRequest) (*models.StickerPack, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, utils.NewBadRequestError("name is required", nil)
+	}
+	pack := &models.StickerPack{
+		ID:       uuid.New().String(),
+		Name:     name,
+		Position: req.Position,
+		IsActive: true,
+		Stickers: make([]*models.Sticker, 0, len(req.Stickers)),
+	}
+	for i, sr := range req.Stickers {
+		pack.Stickers = append(pack.Stickers, &models.Sticker{
+			ID:       uuid.New().String(),
+			PackID:   pack.ID,
+			ImageURL: sr.ImageURL,
+			Emoji:    sr.Emoji,
+			Position: i,
+		})
+	}
+	if err := s.stickerRepo.CreatePack(ctx, pack); err != nil {
+		return nil, utils.NewInternalError("Failed to create sticker pack", err)
+	}
+	s.logger.Info("Sticker pack created", zap.String("pack_id", pack.ID), zap.Int("stickers", len(pack.Stickers)))
+	return pack, nil
+}
+
+// SetPackActive shows or hides a pack. Hidden stickers can't be sent but
+// still render in messages that already use them.
+func (s *StickerService) SetPackActive(ctx context.Context, packID string, active bool) error {
+	err := s.stickerRepo.SetPackActive(ctx, packID, active)
+	if errors.Is(err, repositories.ErrStickerPackNotFound) {
+		return utils.NewNotFoundError("Sticker pack not found", err)
+	}
+	if err != nil {
+		return utils.NewInternalError("Failed to update sticker pack", err)
+	}
+	return nil
+}
+
+// DeletePack removes a pack and its stickers. Messages that used them fall
+// back to a sticker message without the image.
+func (s *StickerService) DeletePack(ctx context.Context, packID string) error {
+	err := s.stickerRepo.DeletePack(ctx, packID)
+	if errors.Is(err, repositories.ErrStickerPackNotFound) {
+		return utils.NewNotFoundError("Sticker pack not found", err)
+	}
+	if err != nil {
+		return utils.NewInternalError("Failed to delete sticker pack", err)
+	}
+	return nil
+}
+
+// ResolveForSend loads a sticker the user is sending; it must belong to an
+// active pack.
+func (s *StickerService) ResolveForSend(ctx context.Context, stickerID string) (*models.Sticker, error) {
+	sticker, err := s.stickerRepo.GetSticker(ctx, stickerID, true)
+	if errors.Is(err, repositories.ErrStickerNotFound) {
+		return nil, utils.NewBadRequestError("Sticker not found", err)
+	}
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load sticker", err)
+	}
+	return sticker, nil
+}
+
+// GetSticker returns the sticker a message shows, or nil once it's gone.
+func (s *StickerService) GetSticker(ctx context.Context, stickerID string) *models.Sticker {
+	sticker, err := s.stickerRepo.GetSticker(ctx, stickerID, false)
+	if err != nil {
+		return nil
+	}
+	return sticker
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestStickerService_CreatePack(t *testing.T) {
+	ctx := context.Background()
+	repo := new(mocks.MockStickerRepository)
+	repo.On("CreatePack", ctx, mock.AnythingOfType("*models.StickerPack")).Return(nil)
+	svc := NewStickerService(repo, zap.NewNop())
+
+	pack, err := svc.CreatePack(ctx, &models.CreateStickerPackRequest{
+		Name: "  Greetings ",
+		Stickers: []models.CreateStickerRequest{
+			{ImageURL: "https://cdn.example.com/hi.png", Emoji: ptrStr("👋")},
+			{ImageURL: "https://cdn.example.com/bye.png"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Greetings", pack.Name)
+	assert.True(t, pack.IsActive)
+	require.Len(t, pack.Stickers, 2)
+	for i, s := range pack.Stickers {
+		assert.Equal(t, i, s.Position)
+		assert.Equal(t, pack.ID, s.PackID)
+		assert.NotEmpty(t, s.ID)
+	}
+}
+
+func TestStickerService_NotFound(t *testing.T) {
+	ctx := context.Background()
+	repo := new(mocks.MockStickerRepository)
+	repo.On("DeletePack", ctx, "missing").Return(repositories.ErrStickerPackNotFound)
+	repo.On("SetPackActive", ctx, "missing", false).Return(repositories.ErrStickerPackNotFound)
+	repo.On("GetSticker", ctx, "gone", false).Return(nil, repositories.ErrStickerNotFound)
+	svc := NewStickerService(repo, zap.NewNop())
+
+	requireAppErrorCode(t, svc.DeletePack(ctx, "missing"), http.StatusNotFound)
+	requireAppErrorCode(t, svc.SetPackActive(ctx, "missing", false), http.StatusNotFound)
+	assert.Nil(t, svc.GetSticker(ctx, "gone"))
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+// storageQuotaBytes is the per-user limit currently configured.
+func (s *StorageService) storageQuotaBytes() int64 {
+	mb := defaultStorageQuotaMB
+	if s.settings != nil {
+		mb = s.settings.Int(SettingStorageQuotaMB, defaultStorageQuotaMB)
+	}
+	return int64(mb) * 1024 * 1024
+}
+
+// checkQuota refuses an upload of size bytes that wouldn't fit in the
+// owner's remaining storage. It is a soft limit: concurrent uploads are
+// checked against the same total, so a user can overshoot by one batch.
+// Verification documents are never refused, since verifying a business
+// mustn't depend on how many photos its owner posted. A failed usage read
+// lets the upload through rather than blocking everyone on a DB hiccup.
+func (s *StorageService) checkQuota(ctx context.Context, ownerID string, purpose ImageType, size int64) error {
+	if s.quota == nil || ownerID == "" || purpose == ImageTypeVerification {
+		return nil
+	}
+	usage, err := s.quota.GetUsage(ctx, ownerID)
+	if err != nil {
+		s.logger.Warn("Storage quota check skipped", zap.Error(err), zap.String("user_id", ownerID))
+		return nil
+	}
+	limit := s.storageQuotaBytes()
+	if usage.UsedBytes+size > limit {
+		return utils.NewStorageQuotaError(usage.UsedBytes, limit, size)
+	}
+	return nil
+}
+
+// recordUpload adds a stored upload to its owner's total. Failures are
+// logged, not returned: the file is already stored, and a missed record
+// only undercounts the owner's usage.
+func (s *StorageService) recordUpload(ctx context.Context, ownerID string, purpose ImageType, photo *models.Photo) {
+	if s.quota == nil || ownerID == "" {
+		return
+	}
+	obj := &models.StorageObject{
+		UserID:    ownerID,
+		URL:       photo.URL,
+		SizeBytes: photo.Size,
+		Purpose:   string(purpose),
+	}
+	if photo.ThumbURL != "" {
+		obj.ThumbURL = &photo.ThumbURL
+	}
+	if photo.MediumURL != "" {
+		obj.MediumURL = &photo.MediumURL
+	}
+	if photo.MimeType != "" {
+		obj.MimeType = &photo.MimeType
+	}
+	if err := s.quota.Record(ctx, obj); err != nil {
+		s.logger.Warn("Failed to record storage usage", zap.Error(err), zap.String("user_id", ownerID))
+	}
+}
+
+// GetStorageUsage returns the user's usage against their quota and a page of
+// their uploads (sort: oldest, largest or newest).
+func (s *StorageService) GetStorageUsage(ctx context.Context, userID, sort string, limit, offset int) (*models.StorageUsageResponse, error) {
+	if s.quota == nil {
+		return nil, utils.NewNotImplementedError("Storage usage is not available", nil)
+	}
+	usage, err := s.quota.GetUsage(ctx, userID)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to get storage usage", err)
+	}
+	objects, total, err := s.quota.List(ctx, &models.StorageObjectFilter{
+		UserID: userID, Sort: sort, Limit: limit, Offset: offset,
+	})
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to list uploads", err)
+	}
+
+	limitBytes := s.storageQuotaBytes()
+	warnPercent := defaultStorageWarnPercent
+	if s.settings != nil {
+		warnPercent = s.settings.Int(SettingStorageWarnPercent, defaultStorageWarnPercent)
+	}
+	return &models.StorageUsageResponse{
+		UsedBytes:   usage.UsedBytes,
+		LimitBytes:  limitBytes,
+		ObjectCount: usage.ObjectCount,
+		NearLimit:   usage.UsedBytes*100 >= limitBytes*int64(warnPercent),
+		Objects:     objects,
+		Total:       total,
+		Limit:       limit,
+		Offset:      offset,
+	}, nil
+}
+
+// DeleteStorageObject deletes one of the user's uploads and its resized
+// variants to free space. Files still shown on a live post, comment or
+// profile are refused; the user removes those from where they're shown.
+func (s *StorageService) DeleteStorageObject(ctx context.Context, userID, objectID string) error {
+	if s.quota == nil {
+		return utils.NewNotImplementedError("Storage usage is not available", nil)
+	}
+	if _, err := uuid.Parse(objectID); err != nil {
+		return utils.NewNotFoundError("Upload not found", err)
+	}
+	obj, err := s.quota.Get(ctx, userID, objectID)
+	if errors.Is(err, repositories.ErrStorageObjectNotFound) {
+		return utils.NewNotFoundError("Upload not found", err)
+	}
+	if err != nil {
+		return utils.NewInternalError("Failed to get upload", err)
+	}
+	if obj.InUse {
+		return utils.NewConflictError("This file is still shown on a post, comment or profile. Remove it there first.", nil)
+	}
+
+	for _, variant := range []*string{obj.ThumbURL, obj.MediumURL} {
+		if variant != nil && *variant != obj.URL {
+			if err := s.DeleteImage(ctx, *variant); err != nil {
+				return err
+			}
+		}
+	}
+	return s.DeleteImage(ctx, obj.URL)
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newQuotaStorageService() (*StorageService, *mocks.MockStorageQuotaRepository) {
+	repo := new(mocks.MockStorageQuotaRepository)
+	svc := newTestStorageService()
+	svc.quota = repo
+	return svc, repo
+}
+
+func TestStorageService_UploadQuota(t *testing.T) {
+	ctx := context.Background()
+	limit := int64(defaultStorageQuotaMB) * 1024 * 1024
+
+	t.Run("upload is recorded against the owner", func(t *testing.T) {
+		svc, repo := newQuotaStorageService()
+		repo.On("GetUsage", mock.Anything, "user-1").Return(&models.StorageUsage{UsedBytes: 1024}, nil)
+		repo.On("Record", mock.Anything, mock.MatchedBy(func(o *models.StorageObject) bool {
+			return o.UserID == "user-1" && o.Purpose == "post" && o.SizeBytes > 0 && o.URL != ""
+		})).Return(nil)
+
+		data := makeJPEG(t, 200, 200)
+		_, err := svc.UploadImage(ctx, "user-1", makeTestFile(data),
+			makeHeader("photo.jpg", "image/jpeg", int64(len(data))), ImageTypePost)
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("upload past the quota is refused", func(t *testing.T) {
+		svc, repo := newQuotaStorageService()
+		repo.On("GetUsage", mock.Anything, "user-1").Return(&models.StorageUsage{UsedBytes: limit - 10}, nil)
+
+		data := makeJPEG(t, 200, 200)
+		_, err := svc.UploadImage(ctx, "user-1", makeTestFile(data),
+			makeHeader("photo.jpg", "image/jpeg", int64(len(data))), ImageTypePost)
+		var appErr *utils.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, appErr.Code)
+		var quotaErr *utils.StorageQuotaError
+		require.ErrorAs(t, appErr.Err, &quotaErr)
+		assert.Equal(t, limit, quotaErr.LimitBytes)
+		repo.AssertNotCalled(t, "Record", mock.Anything, mock.Anything)
+	})
+
+	t.Run("verification documents skip the check", func(t *testing.T) {
+		svc, repo := newQuotaStorageService()
+		repo.On("Record", mock.Anything, mock.Anything).Return(nil)
+
+		data := makeJPEG(t, 200, 200)
+		_, err := svc.UploadImage(ctx, "user-1", makeTestFile(data),
+			makeHeader("license.jpg", "image/jpeg", int64(len(data))), ImageTypeVerification)
+		require.NoError(t, err)
+		repo.AssertNotCalled(t, "GetUsage", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unowned uploads aren't tracked", func(t *testing.T) {
+		svc, repo := newQuotaStorageService()
+		data := makeJPEG(t, 200, 200)
+		_, err := svc.UploadImage(ctx, "", makeTestFile(data),
+			makeHeader("photo.jpg", "image/jpeg", int64(len(data))), ImageTypePost)
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestStorageService_GetStorageUsage(t *testing.T) {
+	svc, repo := newQuotaStorageService()
+	limit := int64(defaultStorageQuotaMB) * 1024 * 1024
+	repo.On("GetUsage", mock.Anything, "user-1").
+		Return(&models.StorageUsage{UsedBytes: limit * 95 / 100, ObjectCount: 3}, nil)
+	repo.On("List", mock.Anything, &models.StorageObjectFilter{UserID: "user-1", Sort: "largest", Limit: 20}).
+		Return([]*models.StorageObject{{ID: "obj-1"}}, 3, nil)
+
+	usage, err := svc.GetStorageUsage(context.Background(), "user-1", "largest", 20, 0)
+	require.NoError(t, err)
+	assert.Equal(t, limit, usage.LimitBytes)
+	assert.True(t, usage.NearLimit)
+	assert.Equal(t, 3, usage.Total)
+	assert.Len(t, usage.Objects, 1)
+}
+
+func TestStorageService_DeleteStorageObject(t *testing.T) {
+	ctx := context.Background()
+	const objectID = "5b0f4a57-1c2d-4e5f-8a9b-0c1d2e3f4a5b"
+
+	t.Run("frees the file and its variants", func(t *testing.T) {
+		svc, repo := newQuotaStorageService()
+		thumb := "https://cdn/post/a-thumb.webp"
+		repo.On("Get", mock.Anything, "user-1", objectID).
+			Return(&models.StorageObject{ID: objectID, URL: "https://cdn/post/a.webp", ThumbURL: &thumb}, nil)
+		repo.On("Release", mock.Anything, thumb).Return(nil)
+		repo.On("Release", mock.Anything, "https://cdn/post/a.webp").Return(nil)
+
+		require.NoError(t, svc.DeleteStorageObject(ctx, "user-1", objectID))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("files still in use are refused", func(t *testing.T) {
+		svc, repo := newQuotaStorageService()
+		repo.On("Get", mock.Anything, "user-1", objectID).
+			Return(&models.StorageObject{ID: objectID, URL: "https://cdn/post/a.webp", InUse: true}, nil)
+
+		err := svc.DeleteStorageObject(ctx, "user-1", objectID)
+		var appErr *utils.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusConflict, appErr.Code)
+		repo.AssertNotCalled(t, "Release", mock.Anything, mock.Anything)
+	})
+
+	t.Run("someone else's upload is not found", func(t *testing.T) {
+		svc, repo := newQuotaStorageService()
+		repo.On("Get", mock.Anything, "user-1", objectID).Return(nil, repositories.ErrStorageObjectNotFound)
+
+		err := svc.DeleteStorageObject(ctx, "user-1", objectID)
+		var appErr *utils.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, http.StatusNotFound, appErr.Code)
+		assert.ErrorIs(t, appErr.Err, repositories.ErrStorageObjectNotFound)
+	})
+}
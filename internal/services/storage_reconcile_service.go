@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"github.com/hamsaya/backend/pkg/observability"
+	"github.com/hamsaya/backend/pkg/runtimeconfig"
+	"github.com/hamsaya/backend/pkg/storage"
+	"go.uber.org/zap"
+)
+
+// Runtime setting keys for the orphaned-storage reconciliation job.
+const (
+	SettingStorageOrphanGraceHours = "storage.orphan_grace_hours"
+	SettingStorageReconcileDryRun  = "storage.reconcile_dry_run"
+)
+
+const (
+	// defaultOrphanGraceHours keeps unreferenced objects for a week before
+	// they're deleted: long enough to cover uploads whose post hasn't been
+	// written yet and soft-deleted content that may still be restored.
+	defaultOrphanGraceHours = 7 * 24
+	// reconcileBatchSize is how many listed keys are checked against the
+	// database in one query.
+	reconcileBatchSize = 1000
+)
+
+// reconcilePrefixes are the bucket folders the API writes media to. Nothing
+// outside them is touched, so files an operator put in the bucket by hand
+// are safe.
+var reconcilePrefixes = []string{
+	string(ImageTypeAvatar) + "/",
+	string(ImageTypeCover) + "/",
+	string(ImageTypePost) + "/",
+	string(ImageTypeVerification) + "/",
+	string(ImageTypeAd) + "/",
+	blurredFolder + "/",
+}
+
+// orphanBucket is the slice of the storage client reconciliation uses.
+type orphanBucket interface {
+	ListObjects(ctx context.Context, prefix string, fn func(storage.ObjectInfo) error) error
+	Delete(ctx context.Context, key string) error
+	PublicURL(key string) string
+}
+
+// StorageReconcileService deletes stored media nothing points at any more:
+// attachments of hard-deleted posts, replaced avatars, abandoned uploads.
+// Deleting a post or business doesn't delete its files, so without this the
+// bucket only grows.
+type StorageReconcileService struct {
+	repo     repositories.StorageReconcileRepository
+	bucket   orphanBucket
+	quota    repositories.StorageQuotaRepository
+	settings *runtimeconfig.Store
+	logger   *zap.Logger
+
+	// now is swapped in tests.
+	now func() time.Time
+}
+
+// NewStorageReconcileService constructs the service. Only wire it when real
+// storage is configured.
+func NewStorageReconcileService(repo repositories.StorageReconcileRepository, bucket orphanBucket, logger *zap.Logger) *StorageReconcileService {
+	return &StorageReconcileService{
+		repo:   repo,
+		bucket: bucket,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// WithQuota releases deleted orphans from their owner's storage total.
+func (s *StorageReconcileService) WithQuota(repo repositories.StorageQuotaRepository) *StorageReconcileService {
+	s.quota = repo
+	return s
+}
+
+// WithRuntimeSettings makes the grace period and dry-run switch tunable at
+// runtime.
+func (s *StorageReconcileService) WithRuntimeSettings(store *runtimeconfig.Store) *StorageReconcileService {
+	store.Register(runtimeconfig.Setting{
+		Key:         SettingStorageOrphanGraceHours,
+		Kind:        runtimeconfig.KindInt,
+		Default:     strconv.Itoa(defaultOrphanGraceHours),
+		Description: "Hours an unreferenced storage object is kept before the reconciliation job deletes it",
+	})
+	store.Register(runtimeconfig.Setting{
+		Key:         SettingStorageReconcileDryRun,
+		Kind:        runtimeconfig.KindBool,
+		Default:     "false",
+		Description: "Count orphaned storage objects without deleting them",
+	})
+	s.settings = store
+	return s
+}
+
+func (s *StorageReconcileService) gracePeriod() time.Duration {
+	hours := defaultOrphanGraceHours
+	if s.settings != nil {
+		hours = s.settings.Int(SettingStorageOrphanGraceHours, defaultOrphanGraceHours)
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+func (s *StorageReconcileService) dryRun() bool {
+	return s.settings != nil && s.settings.Bool(SettingStorageReconcileDryRun, false)
+}
+
+// Run lists the media folders of the bucket and deletes every object older
+// than the grace period that no row references, then records the run.
+// Intended to run daily from a leader-elected job.
+func (s *StorageReconcileService) Run(ctx context.Context) error {
+	run := &models.StorageReconcileRun{StartedAt: s.now(), DryRun: s.dryRun()}
+	cutoff := run.StartedAt.Add(-s.gracePeriod())
+
+	err := s.reconcile(ctx, run, cutoff)
+	run.FinishedAt = s.now()
+	if err != nil {
+		msg := err.Error()
+		run.Error = &msg
+	}
+	if run.DeletedObjects > 0 {
+		observability.RecordStorageReclaimed(ctx, run.DeletedObjects, run.ReclaimedBytes)
+	}
+	if recErr := s.repo.RecordRun(ctx, run); recErr != nil {
+		s.logger.Warn("Failed to record storage reconcile run", zap.Error(recErr))
+	}
+
+	s.logger.Info("Storage reconciliation finished",
+		zap.Bool("dry_run", run.DryRun),
+		zap.Int64("scanned_objects", run.ScannedObjects),
+		zap.Int64("orphaned_objects", run.OrphanedObjects),
+		zap.Int64("deleted_objects", run.DeletedObjects),
+		zap.Int64("reclaimed_bytes", run.ReclaimedBytes),
+	)
+	return err
+}
+
+func (s *StorageReconcileService) reconcile(ctx context.Context, run *models.StorageReconcileRun, cutoff time.Time) error {
+	batch := make([]storage.ObjectInfo, 0, reconcileBatchSize)
+	for _, prefix := range reconcilePrefixes {
+		err := s.bucket.ListObjects(ctx, prefix, func(obj storage.ObjectInfo) error {
+			run.ScannedObjects++
+			run.ScannedBytes += obj.Size
+			// A fresh object may belong to an upload whose post or
+			// profile row hasn't been written yet.
+			if obj.LastModified.After(cutoff) {
+				return nil
+			}
+			batch = append(batch, obj)
+			if len(batch) < reconcileBatchSize {
+				return nil
+			}
+			err := s.sweep(ctx, run, cutoff, batch)
+			batch = batch[:0]
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("reconcile %s: %w", prefix, err)
+		}
+	}
+	return s.sweep(ctx, run, cutoff, batch)
+}
+
+// sweep deletes the objects of batch no row references.
+func (s *StorageReconcileService) sweep(ctx context.Context, run *models.StorageReconcileRun, cutoff time.Time, batch []storage.ObjectInfo) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	keys := make([]string, len(batch))
+	for i, obj := range batch {
+		keys[i] = obj.Key
+	}
+	referenced, err := s.repo.ReferencedKeys(ctx, keys, cutoff)
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range batch {
+		if referenced[obj.Key] {
+			continue
+		}
+		run.OrphanedObjects++
+		run.OrphanedBytes += obj.Size
+		if run.DryRun {
+			continue
+		}
+		if err := s.bucket.Delete(ctx, obj.Key); err != nil {
+			s.logger.Warn("Failed to delete orphaned storage object", zap.Error(err), zap.String("key", obj.Key))
+			continue
+		}
+		run.DeletedObjects++
+		run.ReclaimedBytes += obj.Size
+		if s.quota != nil {
+			if err := s.quota.Release(ctx, s.bucket.PublicURL(obj.Key)); err != nil {
+				s.logger.Warn("Failed to release storage usage", zap.Error(err), zap.String("key", obj.Key))
+			}
+		}
+	}
+	return nil
+}
+
+// Report returns the most recent runs and the totals reclaimed so far.
+func (s *StorageReconcileService) Report(ctx context.Context, limit int) (*models.StorageReconcileReport, error) {
+	runs, err := s.repo.ListRuns(ctx, limit)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to list reconcile runs", err)
+	}
+	deleted, reclaimed, err := s.repo.Totals(ctx)
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to sum reconcile runs", err)
+	}
+	return &models.StorageReconcileReport{
+		Runs:                runs,
+		TotalDeletedObjects: deleted,
+		TotalReclaimedBytes: reclaimed,
+	}, nil
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeBucket lists a fixed set of objects and records deletes.
+type fakeBucket struct {
+	objects   []storage.ObjectInfo
+	deleted   []string
+	deleteErr map[string]error
+}
+
+func (b *fakeBucket) ListObjects(_ context.Context, prefix string, fn func(storage.ObjectInfo) error) error {
+	for _, obj := range b.objects {
+		if strings.HasPrefix(obj.Key, prefix) {
+			if err := fn(obj); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (b *fakeBucket) Delete(_ context.Context, key string) error {
+	if err := b.deleteErr[key]; err != nil {
+		return err
+	}
+	b.deleted = append(b.deleted, key)
+	return nil
+}
+
+func (b *fakeBucket) PublicURL(key string) string {
+	return "https://cdn.example/" + key
+}
+
+func TestStorageReconcileService_Run(t *testing.T) {
+	now := time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC)
+	old := now.Add(-30 * 24 * time.Hour)
+	cutoff := now.Add(-defaultOrphanGraceHours * time.Hour)
+
+	newBucket := func() *fakeBucket {
+		return &fakeBucket{objects: []storage.ObjectInfo{
+			{Key: "post/kept.webp", Size: 100, LastModified: old},
+			{Key: "post/orphan.webp", Size: 200, LastModified: old},
+			{Key: "avatar/fresh.jpg", Size: 300, LastModified: now.Add(-time.Hour)},
+			{Key: "backups/daily.sql.gz", Size: 9000, LastModified: old},
+		}}
+	}
+
+	t.Run("deletes unreferenced objects past the grace period", func(t *testing.T) {
+		repo := new(mocks.MockStorageReconcileRepository)
+		quota := new(mocks.MockStorageQuotaRepository)
+		bucket := newBucket()
+		svc := NewStorageReconcileService(repo, bucket, zap.NewNop()).WithQuota(quota)
+		svc.now = func() time.Time { return now }
+
+		repo.On("ReferencedKeys", mock.Anything, []string{"post/kept.webp", "post/orphan.webp"}, cutoff).
+			Return(map[string]bool{"post/kept.webp": true}, nil)
+		quota.On("Release", mock.Anything, "https://cdn.example/post/orphan.webp").Return(nil)
+		var recorded *models.StorageReconcileRun
+		repo.On("RecordRun", mock.Anything, mock.Anything).
+			Run(func(a mock.Arguments) { recorded = a.Get(1).(*models.StorageReconcileRun) }).
+			Return(nil)
+
+		require.NoError(t, svc.Run(context.Background()))
+		assert.Equal(t, []string{"post/orphan.webp"}, bucket.deleted)
+		require.NotNil(t, recorded)
+		assert.Equal(t, int64(3), recorded.ScannedObjects)
+		assert.Equal(t, int64(1), recorded.OrphanedObjects)
+		assert.Equal(t, int64(1), recorded.DeletedObjects)
+		assert.Equal(t, int64(200), recorded.ReclaimedBytes)
+		assert.Nil(t, recorded.Error)
+		quota.AssertExpectations(t)
+	})
+
+	t.Run("failed deletes aren't counted as reclaimed", func(t *testing.T) {
+		repo := new(mocks.MockStorageReconcileRepository)
+		bucket := newBucket()
+		bucket.deleteErr = map[string]error{"post/orphan.webp": errors.New("boom")}
+		svc := NewStorageReconcileService(repo, bucket, zap.NewNop())
+		svc.now = func() time.Time { return now }
+
+		repo.On("ReferencedKeys", mock.Anything, mock.Anything, cutoff).Return(map[string]bool{}, nil)
+		var recorded *models.StorageReconcileRun
+		repo.On("RecordRun", mock.Anything, mock.Anything).
+			Run(func(a mock.Arguments) { recorded = a.Get(1).(*models.StorageReconcileRun) }).
+			Return(nil)
+
+		require.NoError(t, svc.Run(context.Background()))
+		assert.Equal(t, []string{"post/kept.webp"}, bucket.deleted)
+		assert.Equal(t, int64(2), recorded.OrphanedObjects)
+		assert.Equal(t, int64(1), recorded.DeletedObjects)
+		assert.Equal(t, int64(100), recorded.ReclaimedBytes)
+	})
+
+	t.Run("reference lookup failure deletes nothing", func(t *testing.T) {
+		repo := new(mocks.MockStorageReconcileRepository)
+		bucket := newBucket()
+		svc := NewStorageReconcileService(repo, bucket, zap.NewNop())
+		svc.now = func() time.Time { return now }
+
+		repo.On("ReferencedKeys", mock.Anything, mock.Anything, cutoff).Return(nil, errors.New("db down"))
+		var recorded *models.StorageReconcileRun
+		repo.On("RecordRun", mock.Anything, mock.Anything).
+			Run(func(a mock.Arguments) { recorded = a.Get(1).(*models.StorageReconcileRun) }).
+			Return(nil)
+
+		require.Error(t, svc.Run(context.Background()))
+		assert.Empty(t, bucket.deleted)
+		require.NotNil(t, recorded.Error)
+		assert.Contains(t, *recorded.Error, "db down")
+	})
+}
@@ -14,12 +14,15 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/hamsaya/backend/config"
 	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
 	"github.com/hamsaya/backend/internal/utils"
 	"github.com/hamsaya/backend/pkg/nsfw"
+	"github.com/hamsaya/backend/pkg/runtimeconfig"
 	"github.com/hamsaya/backend/pkg/storage"
 	"go.uber.org/zap"
 	_ "golang.org/x/image/webp"
@@ -54,8 +57,25 @@ type StorageService struct {
 	// classifier marks IsExplicit, the upload is rejected with 400. Wiring
 	// is a single call to WithNSFWScanner from main.go.
 	nsfwClient *nsfw.Client
+	// quota is optional. When set (WithQuota), uploads made on behalf of a
+	// user are recorded against their storage total and refused once the
+	// configured limit would be exceeded.
+	quota    repositories.StorageQuotaRepository
+	settings *runtimeconfig.Store
 }
 
+// Runtime setting keys for storage quotas.
+const (
+	SettingStorageQuotaMB     = "storage.quota_mb"
+	SettingStorageWarnPercent = "storage.warn_percent"
+)
+
+// Storage quota defaults, used until runtime settings say otherwise.
+const (
+	defaultStorageQuotaMB     = 1024
+	defaultStorageWarnPercent = 90
+)
+
 // NewStorageService creates a new storage service
 func NewStorageService(cfg *config.Config, logger *zap.Logger) *StorageService {
 	// Create storage client (if storage is configured)
@@ -99,6 +119,26 @@ func (s *StorageService) WithNSFWScanner(c *nsfw.Client) *StorageService {
 	return s
 }
 
+// WithQuota enables per-user storage accounting and the upload quota, with
+// the limit and warning threshold tunable at runtime.
+func (s *StorageService) WithQuota(repo repositories.StorageQuotaRepository, store *runtimeconfig.Store) *StorageService {
+	store.Register(runtimeconfig.Setting{
+		Key:         SettingStorageQuotaMB,
+		Kind:        runtimeconfig.KindInt,
+		Default:     strconv.Itoa(defaultStorageQuotaMB),
+		Description: "Storage each user may fill with uploads, in MB",
+	})
+	store.Register(runtimeconfig.Setting{
+		Key:         SettingStorageWarnPercent,
+		Kind:        runtimeconfig.KindInt,
+		Default:     strconv.Itoa(defaultStorageWarnPercent),
+		Description: "Storage usage (percent of the quota) at which users are warned",
+	})
+	s.quota = repo
+	s.settings = store
+	return s
+}
+
 // scanForNSFW runs the optional NudeNet pass on raw image bytes. A
 // scanner outage is non-fatal — we log and let the upload through so a
 // flaky sidecar can't take down the whole upload pipeline.
@@ -136,14 +176,16 @@ func (s *StorageService) Client() *storage.Client {
 }
 
 // UploadImage uploads an image and returns photo metadata
-func (s *StorageService) UploadImage(ctx context.Context, file multipart.File, header *multipart.FileHeader, imageType ImageType) (*models.Photo, error) {
+// ownerID is the user the upload is counted against ("" for uploads that
+// belong to nobody).
+func (s *StorageService) UploadImage(ctx context.Context, ownerID string, file multipart.File, header *multipart.FileHeader, imageType ImageType) (*models.Photo, error) {
 	const maxSize = int64(10 * 1024 * 1024) // 10 MB
 
 	// Validate Content-Type header BEFORE reading bytes to reject non-images cheaply.
 	contentType := header.Header.Get("Content-Type")
 	if !s.isValidImageType(contentType) {
 		return nil, utils.NewBadRequestError(
-			fmt.Sprintf("Invalid image type: %s. Only JPEG, PNG, and WebP are allowed", contentType), nil)
+			fmt.Sprintf("Invalid image type: %s. Only JPEG, PNG, WebP, and GIF are allowed", contentType), nil)
 	}
 
 	// Use LimitReader so we never allocate more than maxSize+1 bytes regardless of
@@ -157,17 +199,10 @@ func (s *StorageService) UploadImage(ctx context.Context, file multipart.File, h
 		return nil, utils.NewBadRequestError("File size exceeds 10MB limit", nil)
 	}
 
-	// Decode image to validate it and get format/dimensions
-	img, format, err := image.Decode(bytes.NewReader(data))
+	// Check format and dimensions from the header before decoding pixels.
+	format, err := s.inspectImage(data)
 	if err != nil {
-		s.logger.Error("Failed to decode image", zap.Error(err))
-		return nil, utils.NewBadRequestError("Invalid image file", err)
-	}
-
-	// Re-check MIME using the actual decoded format in case the header was misleading
-	if !s.isValidImageType(mimeFromFormat(format)) {
-		return nil, utils.NewBadRequestError(
-			fmt.Sprintf("Decoded image format %q is not allowed. Only JPEG, PNG, and WebP are accepted", format), nil)
+		return nil, err
 	}
 
 	// NSFW gate. Runs on the raw decoded bytes so the classifier sees the
@@ -177,55 +212,18 @@ func (s *StorageService) UploadImage(ctx context.Context, file multipart.File, h
 		return nil, err
 	}
 
-	// Process image based on type
-	var processedImg image.Image
-	switch imageType {
-	case ImageTypeAvatar:
-		// Process for avatar (crop to square, resize to 400x400)
-		processedImg, err = s.processor.ProcessForAvatar(img, 400)
-		if err != nil {
-			return nil, utils.NewInternalError("Failed to process avatar image", err)
-		}
-	case ImageTypeCover:
-		// Process for cover (resize to fit within 1600x900)
-		processedImg, err = s.processor.ProcessForCover(img, 1600, 900)
-		if err != nil {
-			return nil, utils.NewInternalError("Failed to process cover image", err)
-		}
-	case ImageTypePost, ImageTypeVerification:
-		// Process for post (resize to fit within 2048x2048). Verification
-		// documents use the same processing — only the key prefix differs.
-		processedImg, err = s.processor.ProcessForPost(img)
-		if err != nil {
-			return nil, utils.NewInternalError("Failed to process post image", err)
-		}
-	case ImageTypeAd:
-		// Process for ad (resize to fit within 2048x2048, same as post). The
-		// encode step below forces WebP regardless of source format.
-		processedImg, err = s.processor.ProcessForPost(img)
-		if err != nil {
-			return nil, utils.NewInternalError("Failed to process ad image", err)
-		}
-	default:
-		processedImg = img
-	}
-
-	// Force WebP for ads so served bytes are small regardless of source codec.
-	encodeFormat := format
-	if imageType == ImageTypeAd {
-		encodeFormat = "webp"
-		contentType = "image/webp"
-	}
-
-	// Encode processed image
-	reader, err := storage.EncodeImage(processedImg, encodeFormat)
+	// Decode, process and re-encode. Only the re-encoded bytes are stored,
+	// so EXIF (including GPS coordinates) never reaches the bucket.
+	prepared, err := s.prepareImage(data, format, imageType)
 	if err != nil {
-		return nil, utils.NewInternalError("Failed to encode image", err)
+		return nil, err
 	}
+	data = prepared.data
+	contentType = prepared.contentType
 
-	data, err = io.ReadAll(reader)
-	if err != nil {
-		return nil, utils.NewInternalError("Failed to read encoded image", err)
+	// Quota is checked on the re-encoded size, which is what gets stored.
+	if err := s.checkQuota(ctx, ownerID, imageType, int64(len(data))); err != nil {
+		return nil, err
 	}
 
 	// Upload to storage
@@ -239,7 +237,7 @@ func (s *StorageService) UploadImage(ctx context.Context, file multipart.File, h
 		}
 	} else {
 		// Fall back to mock storage
-		result = s.generateMockUploadResult(string(imageType), format, contentType, int64(len(data)), processedImg)
+		result = s.generateMockUploadResult(string(imageType), prepared.format, contentType, int64(len(data)), prepared.img)
 	}
 
 	// Create photo model
@@ -261,6 +259,7 @@ func (s *StorageService) UploadImage(ctx context.Context, file multipart.File, h
 		zap.Int("width", result.Width),
 		zap.Int("height", result.Height),
 	)
+	s.recordUpload(ctx, ownerID, imageType, photo)
 
 	return photo, nil
 }
@@ -323,7 +322,8 @@ func detectVideoMime(head []byte) string {
 
 // ffmpegFaststart rewrites an MP4 in-memory so the moov atom is at the front,
 // allowing the player to start decoding immediately without downloading the
-// whole file. Returns original data unchanged if ffmpeg is unavailable or fails.
+// whole file. Container metadata (including the GPS location phones record)
+// is dropped on the way. Returns original data unchanged if ffmpeg is unavailable or fails.
 func ffmpegFaststart(data []byte) []byte {
 	in, err := os.CreateTemp("", "vid-in-*.mp4")
 	if err != nil {
@@ -344,7 +344,7 @@ func ffmpegFaststart(data []byte) []byte {
 	out.Close()
 	defer os.Remove(outName)
 
-	if err := exec.Command("ffmpeg", "-y", "-i", in.Name(), "-c", "copy", "-movflags", "+faststart", outName).Run(); err != nil {
+	if err := exec.Command("ffmpeg", "-y", "-i", in.Name(), "-c", "copy", "-map_metadata", "-1", "-movflags", "+faststart", outName).Run(); err != nil {
 		return data
 	}
 	processed, err := os.ReadFile(outName)
@@ -398,7 +398,7 @@ func ffmpegThumbnail(data []byte) []byte {
 
 // UploadPostAttachment uploads an image or video for a post. Images are limited to 10MB and processed;
 // videos are limited to 50MB and stored as-is.
-func (s *StorageService) UploadPostAttachment(ctx context.Context, file multipart.File, header *multipart.FileHeader) (*models.Photo, error) {
+func (s *StorageService) UploadPostAttachment(ctx context.Context, ownerID string, file multipart.File, header *multipart.FileHeader) (*models.Photo, error) {
 	contentType := header.Header.Get("Content-Type")
 	if contentType == "" {
 		contentType = "application/octet-stream"
@@ -458,6 +458,9 @@ func (s *StorageService) UploadPostAttachment(ctx context.Context, file multipar
 		// ffmpeg is absent).
 		data = ffmpegFaststart(data)
 		size = int64(len(data))
+		if err := s.checkQuota(ctx, ownerID, ImageTypePost, size); err != nil {
+			return nil, err
+		}
 
 		var result *storage.UploadResult
 		if s.client != nil {
@@ -501,6 +504,7 @@ func (s *StorageService) UploadPostAttachment(ctx context.Context, file multipar
 				s.logger.Warn("Video thumbnail upload failed", zap.Error(thumbErr))
 			}
 		}
+		s.recordUpload(ctx, ownerID, ImageTypePost, photo)
 
 		return photo, nil
 	}
@@ -520,6 +524,9 @@ func (s *StorageService) UploadPostAttachment(ctx context.Context, file multipar
 			return nil, utils.NewBadRequestError("Audio file size exceeds 10MB limit", nil)
 		}
 		size := int64(len(data))
+		if err := s.checkQuota(ctx, ownerID, ImageTypePost, size); err != nil {
+			return nil, err
+		}
 		var result *storage.UploadResult
 		if s.client != nil {
 			result, err = s.client.UploadFile(ctx, bytes.NewReader(data), size, mimeBase, string(ImageTypePost), header.Filename)
@@ -535,16 +542,18 @@ func (s *StorageService) UploadPostAttachment(ctx context.Context, file multipar
 				MimeType: mimeBase,
 			}
 		}
-		return &models.Photo{
+		photo := &models.Photo{
 			URL:      result.URL,
 			Name:     header.Filename,
 			Size:     result.Size,
 			MimeType: result.MimeType,
-		}, nil
+		}
+		s.recordUpload(ctx, ownerID, ImageTypePost, photo)
+		return photo, nil
 	}
 
 	// Image: use existing 10MB limit and image processing.
-	return s.UploadImage(ctx, file, header, ImageTypePost)
+	return s.UploadImage(ctx, ownerID, file, header, ImageTypePost)
 }
 
 // DeleteImage deletes an image from storage
@@ -561,6 +570,12 @@ func (s *StorageService) DeleteImage(ctx context.Context, url string) error {
 		}
 	}
 
+	if s.quota != nil {
+		if err := s.quota.Release(ctx, url); err != nil {
+			s.logger.Warn("Failed to release storage usage", zap.Error(err), zap.String("url", url))
+		}
+	}
+
 	s.logger.Info("Image deleted", zap.String("url", url))
 	return nil
 }
@@ -572,6 +587,7 @@ func (s *StorageService) isValidImageType(contentType string) bool {
 		"image/jpg",
 		"image/png",
 		"image/webp",
+		"image/gif",
 	}
 
 	for _, validType := range validTypes {
@@ -3,8 +3,10 @@ package services
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
 	"image"
 	"image/color"
+	"image/gif"
 	"image/jpeg"
 	"image/png"
 	"mime/multipart"
@@ -58,6 +60,44 @@ func makePNG(t *testing.T, w, h int) []byte {
 	return buf.Bytes()
 }
 
+// makeGIF builds a GIF with the given number of frames.
+func makeGIF(t *testing.T, w, h, frames int) []byte {
+	t.Helper()
+	g := &gif.GIF{}
+	for i := 0; i < frames; i++ {
+		frame := image.NewPaletted(image.Rect(0, 0, w, h), color.Palette{color.Black, color.White})
+		frame.SetColorIndex(i%w, 0, 1)
+		g.Image = append(g.Image, frame)
+		g.Delay = append(g.Delay, 10)
+	}
+	var buf bytes.Buffer
+	require.NoError(t, gif.EncodeAll(&buf, g))
+	return buf.Bytes()
+}
+
+// withEXIF inserts an EXIF APP1 segment with the given orientation and a
+// stand-in GPS payload after the JPEG SOI marker.
+func withEXIF(jpg []byte, orientation uint16) []byte {
+	var tiff bytes.Buffer
+	tiff.WriteString("II*\x00")
+	_ = binary.Write(&tiff, binary.LittleEndian, uint32(8))
+	_ = binary.Write(&tiff, binary.LittleEndian, uint16(1))
+	_ = binary.Write(&tiff, binary.LittleEndian, []uint16{0x0112, 3})
+	_ = binary.Write(&tiff, binary.LittleEndian, uint32(1))
+	_ = binary.Write(&tiff, binary.LittleEndian, []uint16{orientation, 0})
+	_ = binary.Write(&tiff, binary.LittleEndian, uint32(0))
+	tiff.WriteString("GPS 34.5553N 69.2075E")
+
+	payload := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	out := append([]byte{}, jpg[:2]...)
+	out = append(out, segment...)
+	return append(out, jpg[2:]...)
+}
+
 func newTestStorageService() *StorageService {
 	return NewStorageService(&config.Config{}, zap.NewNop())
 }
@@ -70,8 +110,8 @@ func TestStorageService_UploadImage(t *testing.T) {
 	t.Run("invalid content type rejected", func(t *testing.T) {
 		svc := newTestStorageService()
 		data := []byte("fake data")
-		_, err := svc.UploadImage(ctx, makeTestFile(data),
-			makeHeader("test.gif", "image/gif", int64(len(data))), ImageTypePost)
+		_, err := svc.UploadImage(ctx, "", makeTestFile(data),
+			makeHeader("test.bmp", "image/bmp", int64(len(data))), ImageTypePost)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "Invalid image type")
 	})
@@ -79,7 +119,7 @@ func TestStorageService_UploadImage(t *testing.T) {
 	t.Run("non-image content type rejected", func(t *testing.T) {
 		svc := newTestStorageService()
 		data := []byte("fake data")
-		_, err := svc.UploadImage(ctx, makeTestFile(data),
+		_, err := svc.UploadImage(ctx, "", makeTestFile(data),
 			makeHeader("doc.pdf", "application/pdf", int64(len(data))), ImageTypePost)
 		assert.Error(t, err)
 	})
@@ -87,7 +127,7 @@ func TestStorageService_UploadImage(t *testing.T) {
 	t.Run("corrupted image data rejected", func(t *testing.T) {
 		svc := newTestStorageService()
 		data := []byte("not real image bytes")
-		_, err := svc.UploadImage(ctx, makeTestFile(data),
+		_, err := svc.UploadImage(ctx, "", makeTestFile(data),
 			makeHeader("bad.jpg", "image/jpeg", int64(len(data))), ImageTypePost)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "Invalid image file")
@@ -97,7 +137,7 @@ func TestStorageService_UploadImage(t *testing.T) {
 		svc := newTestStorageService()
 		// 10MB + 1 byte of zeros — no need to be a real image, size check fires first
 		data := make([]byte, 10*1024*1024+1)
-		_, err := svc.UploadImage(ctx, makeTestFile(data),
+		_, err := svc.UploadImage(ctx, "", makeTestFile(data),
 			makeHeader("big.jpg", "image/jpeg", int64(len(data))), ImageTypePost)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "10MB")
@@ -106,7 +146,7 @@ func TestStorageService_UploadImage(t *testing.T) {
 	t.Run("valid JPEG post image succeeds (mock storage)", func(t *testing.T) {
 		svc := newTestStorageService()
 		data := makeJPEG(t, 800, 600)
-		photo, err := svc.UploadImage(ctx, makeTestFile(data),
+		photo, err := svc.UploadImage(ctx, "", makeTestFile(data),
 			makeHeader("photo.jpg", "image/jpeg", int64(len(data))), ImageTypePost)
 		require.NoError(t, err)
 		assert.NotEmpty(t, photo.URL)
@@ -119,7 +159,7 @@ func TestStorageService_UploadImage(t *testing.T) {
 	t.Run("valid PNG post image succeeds (mock storage)", func(t *testing.T) {
 		svc := newTestStorageService()
 		data := makePNG(t, 400, 400)
-		photo, err := svc.UploadImage(ctx, makeTestFile(data),
+		photo, err := svc.UploadImage(ctx, "", makeTestFile(data),
 			makeHeader("image.png", "image/png", int64(len(data))), ImageTypePost)
 		require.NoError(t, err)
 		assert.NotEmpty(t, photo.URL)
@@ -129,7 +169,7 @@ func TestStorageService_UploadImage(t *testing.T) {
 	t.Run("avatar image processed to square", func(t *testing.T) {
 		svc := newTestStorageService()
 		data := makeJPEG(t, 600, 400) // non-square input
-		photo, err := svc.UploadImage(ctx, makeTestFile(data),
+		photo, err := svc.UploadImage(ctx, "", makeTestFile(data),
 			makeHeader("avatar.jpg", "image/jpeg", int64(len(data))), ImageTypeAvatar)
 		require.NoError(t, err)
 		assert.Equal(t, photo.Width, photo.Height, "avatar must be square")
@@ -139,7 +179,7 @@ func TestStorageService_UploadImage(t *testing.T) {
 	t.Run("cover image processed within 1600x900", func(t *testing.T) {
 		svc := newTestStorageService()
 		data := makeJPEG(t, 3000, 2000)
-		photo, err := svc.UploadImage(ctx, makeTestFile(data),
+		photo, err := svc.UploadImage(ctx, "", makeTestFile(data),
 			makeHeader("cover.jpg", "image/jpeg", int64(len(data))), ImageTypeCover)
 		require.NoError(t, err)
 		assert.LessOrEqual(t, photo.Width, 1600)
@@ -149,7 +189,7 @@ func TestStorageService_UploadImage(t *testing.T) {
 	t.Run("post image processed within 2048x2048", func(t *testing.T) {
 		svc := newTestStorageService()
 		data := makeJPEG(t, 4000, 3000)
-		photo, err := svc.UploadImage(ctx, makeTestFile(data),
+		photo, err := svc.UploadImage(ctx, "", makeTestFile(data),
 			makeHeader("post.jpg", "image/jpeg", int64(len(data))), ImageTypePost)
 		require.NoError(t, err)
 		assert.LessOrEqual(t, photo.Width, 2048)
@@ -157,6 +197,77 @@ func TestStorageService_UploadImage(t *testing.T) {
 	})
 }
 
+func TestStorageService_ImageSafety(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("EXIF is stripped and its orientation applied", func(t *testing.T) {
+		svc := newTestStorageService()
+		data := withEXIF(makeJPEG(t, 800, 600), 6) // rotate 90° clockwise
+		require.Contains(t, string(data), "GPS")
+
+		prepared, err := svc.prepareImage(data, "jpeg", ImageTypePost)
+		require.NoError(t, err)
+		assert.NotContains(t, string(prepared.data), "Exif")
+		assert.NotContains(t, string(prepared.data), "GPS")
+		assert.Equal(t, 600, prepared.img.Bounds().Dx())
+		assert.Equal(t, 800, prepared.img.Bounds().Dy())
+	})
+
+	t.Run("oversized dimensions rejected before decoding", func(t *testing.T) {
+		svc := newTestStorageService()
+		data := makePNG(t, maxImageSide+1, 10)
+		_, err := svc.UploadImage(ctx, "", makeTestFile(data),
+			makeHeader("wide.png", "image/png", int64(len(data))), ImageTypePost)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "exceed")
+	})
+
+	t.Run("animated GIF stays animated on posts", func(t *testing.T) {
+		svc := newTestStorageService()
+		data := makeGIF(t, 64, 64, 3)
+		photo, err := svc.UploadImage(ctx, "", makeTestFile(data),
+			makeHeader("wave.gif", "image/gif", int64(len(data))), ImageTypePost)
+		require.NoError(t, err)
+		assert.Equal(t, "image/gif", photo.MimeType)
+		assert.Equal(t, 64, photo.Width)
+	})
+
+	t.Run("GIF avatar stored as a still PNG", func(t *testing.T) {
+		svc := newTestStorageService()
+		data := makeGIF(t, 300, 300, 3)
+		photo, err := svc.UploadImage(ctx, "", makeTestFile(data),
+			makeHeader("me.gif", "image/gif", int64(len(data))), ImageTypeAvatar)
+		require.NoError(t, err)
+		assert.Equal(t, "image/png", photo.MimeType)
+	})
+
+	t.Run("too many GIF frames rejected", func(t *testing.T) {
+		svc := newTestStorageService()
+		data := makeGIF(t, 8, 8, maxGIFFrames+1)
+		_, err := svc.UploadImage(ctx, "", makeTestFile(data),
+			makeHeader("long.gif", "image/gif", int64(len(data))), ImageTypePost)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "frames")
+	})
+
+	t.Run("large animated GIF rejected", func(t *testing.T) {
+		svc := newTestStorageService()
+		data := makeGIF(t, maxAnimatedGIFSide+1, 10, 2)
+		_, err := svc.UploadImage(ctx, "", makeTestFile(data),
+			makeHeader("big.gif", "image/gif", int64(len(data))), ImageTypePost)
+		assert.Error(t, err)
+	})
+}
+
+func TestGIFFrameCount(t *testing.T) {
+	n, err := gifFrameCount(makeGIF(t, 16, 16, 5))
+	require.NoError(t, err)
+	assert.Equal(t, 5, n)
+
+	_, err = gifFrameCount([]byte("not a gif at all"))
+	assert.ErrorIs(t, err, errMalformedGIF)
+}
+
 // --- UploadPostAttachment ---
 
 func TestStorageService_UploadPostAttachment(t *testing.T) {
@@ -175,7 +286,7 @@ func TestStorageService_UploadPostAttachment(t *testing.T) {
 			'm', 'p', '4', '2',
 			'i', 's', 'o', 'm', // compatible brands
 		}
-		photo, err := svc.UploadPostAttachment(ctx, makeTestFile(data),
+		photo, err := svc.UploadPostAttachment(ctx, "", makeTestFile(data),
 			makeHeader("video.mp4", "video/mp4", int64(len(data))))
 		require.NoError(t, err)
 		assert.NotEmpty(t, photo.URL)
@@ -189,7 +300,7 @@ func TestStorageService_UploadPostAttachment(t *testing.T) {
 		// don't match — could be a polyglot. Must be rejected.
 		svc := newTestStorageService()
 		data := []byte("definitely not a real video file")
-		_, err := svc.UploadPostAttachment(ctx, makeTestFile(data),
+		_, err := svc.UploadPostAttachment(ctx, "", makeTestFile(data),
 			makeHeader("fake.mp4", "video/mp4", int64(len(data))))
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "Unsupported video format")
@@ -198,7 +309,7 @@ func TestStorageService_UploadPostAttachment(t *testing.T) {
 	t.Run("video too large rejected", func(t *testing.T) {
 		svc := newTestStorageService()
 		data := make([]byte, 50*1024*1024+1)
-		_, err := svc.UploadPostAttachment(ctx, makeTestFile(data),
+		_, err := svc.UploadPostAttachment(ctx, "", makeTestFile(data),
 			makeHeader("big.mp4", "video/mp4", int64(len(data))))
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "50MB")
@@ -207,7 +318,7 @@ func TestStorageService_UploadPostAttachment(t *testing.T) {
 	t.Run("image delegates to UploadImage", func(t *testing.T) {
 		svc := newTestStorageService()
 		data := makeJPEG(t, 500, 500)
-		photo, err := svc.UploadPostAttachment(ctx, makeTestFile(data),
+		photo, err := svc.UploadPostAttachment(ctx, "", makeTestFile(data),
 			makeHeader("photo.jpg", "image/jpeg", int64(len(data))))
 		require.NoError(t, err)
 		assert.NotEmpty(t, photo.URL)
@@ -224,7 +335,7 @@ func TestStorageService_UploadPostAttachment(t *testing.T) {
 		}
 		// No Content-Type header — falls through to UploadImage with application/octet-stream
 		// which is invalid image type → error
-		_, err := svc.UploadPostAttachment(ctx, makeTestFile(data), header)
+		_, err := svc.UploadPostAttachment(ctx, "", makeTestFile(data), header)
 		assert.Error(t, err)
 	})
 }
@@ -359,7 +470,7 @@ func TestStorageService_MockURLFormat(t *testing.T) {
 	svc := newTestStorageService()
 	ctx := context.Background()
 	data := makeJPEG(t, 200, 200)
-	photo, err := svc.UploadImage(ctx, makeTestFile(data),
+	photo, err := svc.UploadImage(ctx, "", makeTestFile(data),
 		makeHeader("test.jpg", "image/jpeg", int64(len(data))), ImageTypeAvatar)
 	require.NoError(t, err)
 	assert.True(t, strings.HasPrefix(photo.URL, "https://storage.hamsaya.local/uploads/"))
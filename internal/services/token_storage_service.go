@@ -4,11 +4,13 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/hamsaya/backend/internal/repositories"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
@@ -23,8 +25,9 @@ func hashToken(token string) string {
 
 // TokenStorageService handles storing and retrieving tokens in Redis
 type TokenStorageService struct {
-	redis  *redis.Client
-	logger *zap.Logger
+	redis       *redis.Client
+	logger      *zap.Logger
+	revocations repositories.TokenRevocationRepository
 }
 
 // NewTokenStorageService creates a new token storage service
@@ -35,6 +38,14 @@ func NewTokenStorageService(redisClient *redis.Client, logger *zap.Logger) *Toke
 	}
 }
 
+// WithRevocationStore keeps a durable Postgres copy of access-token
+// revocations. Redis still answers every check; Postgres answers when Redis
+// can't and refills it after a restart (RestoreRevocations).
+func (s *TokenStorageService) WithRevocationStore(repo repositories.TokenRevocationRepository) *TokenStorageService {
+	s.revocations = repo
+	return s
+}
+
 // StoreVerificationToken stores an email verification token
 func (s *TokenStorageService) StoreVerificationToken(ctx context.Context, userID, token string, ttl time.Duration) error {
 	key := fmt.Sprintf("verify:email:%s", hashToken(token))
@@ -139,6 +150,102 @@ func (s *TokenStorageService) DeletePasswordResetToken(ctx context.Context, toke
 	return nil
 }
 
+// PendingEmailChange is an email change awaiting the code sent to the new
+// address. The code is stored hashed.
+type PendingEmailChange struct {
+	OldEmail  string    `json:"old_email"`
+	NewEmail  string    `json:"new_email"`
+	CodeHash  string    `json:"code_hash"`
+	Attempts  int       `json:"attempts"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// EmailChangeRevert is what the revert link sent to the old address undoes.
+type EmailChangeRevert struct {
+	UserID   string `json:"user_id"`
+	OldEmail string `json:"old_email"`
+	NewEmail string `json:"new_email"`
+}
+
+// StoreEmailChange stores the user's pending email change, replacing any
+// earlier one.
+func (s *TokenStorageService) StoreEmailChange(ctx context.Context, userID string, change *PendingEmailChange, ttl time.Duration) error {
+	return s.setJSON(ctx, "change:email:"+userID, change, ttl)
+}
+
+// UpdateEmailChange rewrites the pending change keeping its expiry.
+func (s *TokenStorageService) UpdateEmailChange(ctx context.Context, userID string, change *PendingEmailChange) error {
+	return s.setJSON(ctx, "change:email:"+userID, change, redis.KeepTTL)
+}
+
+// GetEmailChange returns the user's pending email change, or nil.
+func (s *TokenStorageService) GetEmailChange(ctx context.Context, userID string) (*PendingEmailChange, error) {
+	var change PendingEmailChange
+	found, err := s.getJSON(ctx, "change:email:"+userID, &change)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &change, nil
+}
+
+// DeleteEmailChange drops the user's pending email change.
+func (s *TokenStorageService) DeleteEmailChange(ctx context.Context, userID string) error {
+	if err := s.redis.Del(ctx, "change:email:"+userID).Err(); err != nil {
+		return fmt.Errorf("failed to delete email change: %w", err)
+	}
+	return nil
+}
+
+// StoreEmailChangeRevert stores a revert token, by hash.
+func (s *TokenStorageService) StoreEmailChangeRevert(ctx context.Context, token string, revert *EmailChangeRevert, ttl time.Duration) error {
+	return s.setJSON(ctx, "revert:email:"+hashToken(token), revert, ttl)
+}
+
+// GetEmailChangeRevert resolves a revert token, or nil when it is unknown
+// or expired.
+func (s *TokenStorageService) GetEmailChangeRevert(ctx context.Context, token string) (*EmailChangeRevert, error) {
+	var revert EmailChangeRevert
+	found, err := s.getJSON(ctx, "revert:email:"+hashToken(token), &revert)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &revert, nil
+}
+
+// DeleteEmailChangeRevert consumes a revert token.
+func (s *TokenStorageService) DeleteEmailChangeRevert(ctx context.Context, token string) error {
+	if err := s.redis.Del(ctx, "revert:email:"+hashToken(token)).Err(); err != nil {
+		return fmt.Errorf("failed to delete email change revert token: %w", err)
+	}
+	return nil
+}
+
+func (s *TokenStorageService) setJSON(ctx context.Context, key string, v any, ttl time.Duration) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", key, err)
+	}
+	if err := s.redis.Set(ctx, key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store %s: %w", key, err)
+	}
+	return nil
+}
+
+// getJSON decodes key into v, reporting false when it doesn't exist.
+func (s *TokenStorageService) getJSON(ctx context.Context, key string, v any) (bool, error) {
+	data, err := s.redis.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get %s: %w", key, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("failed to decode %s: %w", key, err)
+	}
+	return true, nil
+}
+
 // StoreMFAChallenge stores an MFA challenge
 func (s *TokenStorageService) StoreMFAChallenge(ctx context.Context, challengeID, userID string, ttl time.Duration) error {
 	key := fmt.Sprintf("mfa:challenge:%s", challengeID)
@@ -195,6 +302,12 @@ func (s *TokenStorageService) DeleteMFAChallenge(ctx context.Context, challengeI
 
 // BlacklistToken adds a token to the blacklist (for revoked access tokens)
 func (s *TokenStorageService) BlacklistToken(ctx context.Context, tokenHash string, ttl time.Duration) error {
+	if s.revocations != nil {
+		if err := s.revocations.AddBlacklisted(ctx, tokenHash, time.Now().Add(ttl), "logout"); err != nil {
+			s.logger.Warn("Failed to persist token revocation", zap.String("token_hash", tokenHash), zap.Error(err))
+		}
+	}
+
 	key := fmt.Sprintf("blacklist:token:%s", tokenHash)
 	err := s.redis.Set(ctx, key, "1", ttl).Err()
 	if err != nil {
@@ -227,6 +340,100 @@ func (s *TokenStorageService) IsTokenBlacklisted(ctx context.Context, tokenHash
 	return exists > 0, nil
 }
 
+// tokensInvalidBeforePrefix keys the per-user logout-all cutoff, stored in
+// Unix milliseconds: a whole-second cutoff would also revoke a token issued
+// later in the same second, such as the one from an immediate re-login.
+const tokensInvalidBeforePrefix = "auth:invalid_before_ms:"
+
+// InvalidateUserTokens revokes every access token issued to the user up to
+// now in one write, instead of denylisting them one by one. The cutoff
+// lives for ttl, the access-token lifetime: after that every token it
+// covers has expired anyway.
+func (s *TokenStorageService) InvalidateUserTokens(ctx context.Context, userID string, ttl time.Duration) error {
+	now := time.Now()
+	if s.revocations != nil {
+		if err := s.revocations.SetTokensInvalidBefore(ctx, userID, now); err != nil {
+			s.logger.Warn("Failed to persist logout-all cutoff", zap.String("user_id", userID), zap.Error(err))
+		}
+	}
+	if err := s.redis.Set(ctx, tokensInvalidBeforePrefix+userID, now.UnixMilli(), ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set logout-all cutoff: %w", err)
+	}
+	return nil
+}
+
+// IsAccessTokenRevoked reports whether the token was denylisted by JTI or
+// issued (issuedAtMs, Unix milliseconds) no later than its user's
+// logout-all cutoff. Both are checked in a single Redis round trip; when
+// Redis fails and a revocation store is wired, Postgres answers instead.
+func (s *TokenStorageService) IsAccessTokenRevoked(ctx context.Context, jti, userID string, issuedAtMs int64) (bool, error) {
+	pipe := s.redis.Pipeline()
+	var denied *redis.IntCmd
+	if jti != "" {
+		denied = pipe.Exists(ctx, fmt.Sprintf("blacklist:token:%s", jti))
+	}
+	cutoff := pipe.Get(ctx, tokensInvalidBeforePrefix+userID)
+	_, err := pipe.Exec(ctx)
+	if err != nil && err != redis.Nil {
+		if s.revocations == nil {
+			return false, fmt.Errorf("failed to check token revocation: %w", err)
+		}
+		s.logger.Warn("Token revocation check fell back to Postgres", zap.Error(err))
+		return s.revocations.IsRevoked(ctx, jti, userID, time.UnixMilli(issuedAtMs))
+	}
+
+	if denied != nil && denied.Val() > 0 {
+		return true, nil
+	}
+	if before, err := cutoff.Int64(); err == nil && issuedAtMs <= before {
+		return true, nil
+	}
+	return false, nil
+}
+
+// RestoreRevocations refills Redis from the durable copy: unexpired
+// denylisted tokens, and logout-all cutoffs younger than accessTTL. Run at
+// startup so revocations survive a Redis restart. Returns how many keys
+// were written.
+func (s *TokenStorageService) RestoreRevocations(ctx context.Context, accessTTL time.Duration) (int, error) {
+	if s.revocations == nil {
+		return 0, nil
+	}
+	now := time.Now()
+	tokens, err := s.revocations.ListBlacklisted(ctx)
+	if err != nil {
+		return 0, err
+	}
+	cutoffs, err := s.revocations.ListTokensInvalidBefore(ctx, now.Add(-accessTTL))
+	if err != nil {
+		return 0, err
+	}
+
+	pipe := s.redis.Pipeline()
+	for jti, expiresAt := range tokens {
+		pipe.Set(ctx, fmt.Sprintf("blacklist:token:%s", jti), "1", expiresAt.Sub(now))
+	}
+	for userID, at := range cutoffs {
+		pipe.Set(ctx, tokensInvalidBeforePrefix+userID, at.UnixMilli(), at.Add(accessTTL).Sub(now))
+	}
+	if len(tokens)+len(cutoffs) == 0 {
+		return 0, nil
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to restore revocations: %w", err)
+	}
+	return len(tokens) + len(cutoffs), nil
+}
+
+// PurgeExpiredRevocations deletes durable revocations of tokens that have
+// expired; Redis expires its copies on its own.
+func (s *TokenStorageService) PurgeExpiredRevocations(ctx context.Context) (int64, error) {
+	if s.revocations == nil {
+		return 0, nil
+	}
+	return s.revocations.DeleteExpiredBlacklisted(ctx)
+}
+
 // Session cache constants
 const (
 	sessionCachePrefix = "session:cache:"
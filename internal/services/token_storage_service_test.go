@@ -2,12 +2,15 @@ package services
 
 import (
 	"context"
+	"strconv"
 	"testing"
 	"time"
 
 	"github.com/alicebob/miniredis/v2"
+	"github.com/hamsaya/backend/internal/mocks"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
@@ -246,3 +249,87 @@ func TestTokenStorageService_Redis_Failure(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestTokenStorageService_AccessTokenRevocation(t *testing.T) {
+	ctx := context.Background()
+	svc, mr := newTestRedis(t)
+	repo := new(mocks.MockTokenRevocationRepository)
+	repo.On("AddBlacklisted", ctx, "jti-1", mock.AnythingOfType("time.Time"), "logout").Return(nil)
+	repo.On("SetTokensInvalidBefore", ctx, "user-1", mock.AnythingOfType("time.Time")).Return(nil)
+	svc.WithRevocationStore(repo)
+
+	issuedBefore := time.Now().Add(-time.Minute).UnixMilli()
+	revoked, err := svc.IsAccessTokenRevoked(ctx, "jti-1", "user-1", issuedBefore)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	require.NoError(t, svc.BlacklistToken(ctx, "jti-1", time.Hour))
+	revoked, err = svc.IsAccessTokenRevoked(ctx, "jti-1", "user-2", issuedBefore)
+	require.NoError(t, err)
+	assert.True(t, revoked, "denylisted by JTI")
+
+	require.NoError(t, svc.InvalidateUserTokens(ctx, "user-1", 15*time.Minute))
+	revoked, err = svc.IsAccessTokenRevoked(ctx, "jti-2", "user-1", issuedBefore)
+	require.NoError(t, err)
+	assert.True(t, revoked, "issued before logout-all")
+	revoked, err = svc.IsAccessTokenRevoked(ctx, "jti-3", "user-1", time.Now().Add(time.Minute).UnixMilli())
+	require.NoError(t, err)
+	assert.False(t, revoked, "issued after logout-all")
+
+	// A token from a re-login in the same second as the cutoff survives it.
+	cutoff, err := mr.Get("auth:invalid_before_ms:user-1")
+	require.NoError(t, err)
+	cutoffMs, err := strconv.ParseInt(cutoff, 10, 64)
+	require.NoError(t, err)
+	revoked, err = svc.IsAccessTokenRevoked(ctx, "jti-4", "user-1", cutoffMs+1)
+	require.NoError(t, err)
+	assert.False(t, revoked, "issued just after logout-all")
+	revoked, err = svc.IsAccessTokenRevoked(ctx, "jti-5", "user-1", cutoffMs)
+	require.NoError(t, err)
+	assert.True(t, revoked, "issued at the cutoff")
+	repo.AssertExpectations(t)
+
+	// The cutoff only lives as long as the tokens it covers.
+	mr.FastForward(16 * time.Minute)
+	revoked, err = svc.IsAccessTokenRevoked(ctx, "jti-2", "user-1", issuedBefore)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestTokenStorageService_RevocationDurableBackup(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("restore refills redis", func(t *testing.T) {
+		svc, mr := newTestRedis(t)
+		now := time.Now()
+		repo := new(mocks.MockTokenRevocationRepository)
+		repo.On("ListBlacklisted", ctx).Return(map[string]time.Time{"jti-1": now.Add(10 * time.Minute)}, nil)
+		repo.On("ListTokensInvalidBefore", ctx, mock.AnythingOfType("time.Time")).
+			Return(map[string]time.Time{"user-1": now.Add(-5 * time.Minute)}, nil)
+		svc.WithRevocationStore(repo)
+
+		restored, err := svc.RestoreRevocations(ctx, 15*time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, 2, restored)
+		assert.True(t, mr.Exists("blacklist:token:jti-1"))
+		assert.InDelta(t, 10*time.Minute, mr.TTL("auth:invalid_before_ms:user-1"), float64(time.Second))
+
+		revoked, err := svc.IsAccessTokenRevoked(ctx, "", "user-1", now.Add(-10*time.Minute).UnixMilli())
+		require.NoError(t, err)
+		assert.True(t, revoked)
+	})
+
+	t.Run("postgres answers when redis is down", func(t *testing.T) {
+		rdb := redis.NewClient(&redis.Options{Addr: "localhost:0", MaxRetries: 0, DialTimeout: time.Millisecond})
+		repo := new(mocks.MockTokenRevocationRepository)
+		repo.On("IsRevoked", ctx, "jti-1", "user-1", time.UnixMilli(1700000000123)).Return(true, nil)
+		svc := NewTokenStorageService(rdb, zap.NewNop()).WithRevocationStore(repo)
+
+		revoked, err := svc.IsAccessTokenRevoked(ctx, "jti-1", "user-1", 1700000000123)
+		require.NoError(t, err)
+		assert.True(t, revoked)
+
+		_, err = NewTokenStorageService(rdb, zap.NewNop()).IsAccessTokenRevoked(ctx, "jti-1", "user-1", 1700000000123)
+		assert.Error(t, err)
+	})
+}
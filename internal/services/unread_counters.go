@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/pkg/events"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	unreadCountersPrefix = "chat_unread:"
+	// unreadSeededField marks a hash as loaded from the database, so a
+	// user with nothing unread still has a key and isn't re-seeded on
+	// every read.
+	unreadSeededField = "_seeded"
+	// unreadCountersTTL drops the hashes of users who stopped using chat;
+	// every write pushes it forward.
+	unreadCountersTTL = 30 * 24 * time.Hour
+)
+
+// incrementIfSeeded bumps a counter only when the user's hash exists. An
+// unseeded hash is loaded from the database on the next read, which already
+// includes the new message.
+var incrementIfSeeded = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	redis.call('HINCRBY', KEYS[1], ARGV[1], 1)
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return 1
+end
+return 0
+`)
+
+// UnreadCounters keeps each user's unread message count per conversation in
+// a Redis hash, "chat_unread:{userID}" → {conversationID: count}, so the
+// conversation list and the app badge don't count messages on every poll.
+//
+// Counts are incremented when a message is sent and cleared when the
+// conversation is marked read. Anything else that changes unread state
+// (deletes, races with seeding) is corrected by ReconcileAll, which the
+// server runs periodically.
+type UnreadCounters struct {
+	redis       *redis.Client
+	messageRepo repositories.MessageRepository
+	logger      *zap.Logger
+}
+
+// NewUnreadCounters creates the counters.
+func NewUnreadCounters(redisClient *redis.Client, messageRepo repositories.MessageRepository, logger *zap.Logger) *UnreadCounters {
+	return &UnreadCounters{
+		redis:       redisClient,
+		messageRepo: messageRepo,
+		logger:      logger,
+	}
+}
+
+func unreadCountersKey(userID string) string {
+	return unreadCountersPrefix + userID
+}
+
+// SubscribeMessages counts every sent message as unread for its recipient.
+func (u *UnreadCounters) SubscribeMessages(bus *events.Bus) {
+	events.Subscribe(bus, func(ctx context.Context, e MessageSent) {
+		u.Increment(ctx, e.RecipientID, e.Message.ConversationID)
+	})
+}
+
+// Increment counts one more unread message. Best-effort.
+func (u *UnreadCounters) Increment(ctx context.Context, userID, conversationID string) {
+	err := incrementIfSeeded.Run(ctx, u.redis, []string{unreadCountersKey(userID)},
+		conversationID, unreadCountersTTL.Milliseconds()).Err()
+	if err != nil {
+		u.logger.Warn("Failed to increment unread counter",
+			zap.String("user_id", userID),
+			zap.String("conversation_id", conversationID),
+			zap.Error(err),
+		)
+	}
+}
+
+// Reset clears a conversation's count after it was read. Best-effort.
+func (u *UnreadCounters) Reset(ctx context.Context, userID, conversationID string) {
+	if err := u.redis.HDel(ctx, unreadCountersKey(userID), conversationID).Err(); err != nil {
+		u.logger.Warn("Failed to reset unread counter",
+			zap.String("user_id", userID),
+			zap.String("conversation_id", conversationID),
+			zap.Error(err),
+		)
+	}
+}
+
+// Counts returns the user's unread counts keyed by conversation id.
+// Conversations with nothing unread are absent.
+func (u *UnreadCounters) Counts(ctx context.Context, userID string) (map[string]int, error) {
+	raw, err := u.redis.HGetAll(ctx, unreadCountersKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		counts, _, err := u.Reconcile(ctx, userID)
+		return counts, err
+	}
+	return parseUnreadCounts(raw), nil
+}
+
+// Total returns the user's unread messages across all conversations.
+func (u *UnreadCounters) Total(ctx context.Context, userID string) (int, error) {
+	counts, err := u.Counts(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, n := range counts {
+		total += n
+	}
+	return total, nil
+}
+
+// Reconcile reloads the user's counts from the database and replaces the
+// hash. drifted reports whether the stored counts were wrong.
+func (u *UnreadCounters) Reconcile(ctx context.Context, userID string) (counts map[string]int, drifted bool, err error) {
+	counts, err = u.messageRepo.GetUnreadCountsByUser(ctx, userID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	key := unreadCountersKey(userID)
+	stored, err := u.redis.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	drifted = !sameUnreadCounts(parseUnreadCounts(stored), counts)
+
+	fields := []interface{}{unreadSeededField, 1}
+	for conversationID, n := range counts {
+		fields = append(fields, conversationID, n)
+	}
+	pipe := u.redis.TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.HSet(ctx, key, fields...)
+	pipe.Expire(ctx, key, unreadCountersTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, false, err
+	}
+	return counts, drifted, nil
+}
+
+// ReconcileAll reconciles every user with a counters hash and logs how many
+// had drifted. A failing user is logged and skipped.
+func (u *UnreadCounters) ReconcileAll(ctx context.Context) error {
+	var checked, drifted int
+	iter := u.redis.Scan(ctx, 0, unreadCountersPrefix+"*", 200).Iterator()
+	for iter.Next(ctx) {
+		userID := strings.TrimPrefix(iter.Val(), unreadCountersPrefix)
+		_, d, err := u.Reconcile(ctx, userID)
+		if err != nil {
+			u.logger.Warn("Failed to reconcile unread counters", zap.String("user_id", userID), zap.Error(err))
+			continue
+		}
+		checked++
+		if d {
+			drifted++
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if drifted > 0 {
+		u.logger.Info("Unread counters reconciled", zap.Int("checked", checked), zap.Int("drifted", drifted))
+	}
+	return nil
+}
+
+func parseUnreadCounts(raw map[string]string) map[string]int {
+	counts := make(map[string]int, len(raw))
+	for field, value := range raw {
+		if field == unreadSeededField {
+			continue
+		}
+		if n, err := strconv.Atoi(value); err == nil && n > 0 {
+			counts[field] = n
+		}
+	}
+	return counts
+}
+
+func sameUnreadCounts(a, b map[string]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
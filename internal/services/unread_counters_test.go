@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/pkg/events"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestUnreadCounters(t *testing.T, msgRepo *mocks.MockMessageRepository) (*UnreadCounters, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	return NewUnreadCounters(rdb, msgRepo, zap.NewNop()), mr
+}
+
+func TestUnreadCounters(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("seeds from the database once, then counts in redis", func(t *testing.T) {
+		msgRepo := new(mocks.MockMessageRepository)
+		msgRepo.On("GetUnreadCountsByUser", mock.Anything, "user-1").
+			Return(map[string]int{"conv-1": 2}, nil).Once()
+		counters, _ := newTestUnreadCounters(t, msgRepo)
+
+		// Not seeded yet: the increment is left to the seed.
+		counters.Increment(ctx, "user-1", "conv-1")
+
+		counts, err := counters.Counts(ctx, "user-1")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"conv-1": 2}, counts)
+
+		counters.Increment(ctx, "user-1", "conv-1")
+		counters.Increment(ctx, "user-1", "conv-2")
+		total, err := counters.Total(ctx, "user-1")
+		require.NoError(t, err)
+		assert.Equal(t, 4, total)
+
+		counters.Reset(ctx, "user-1", "conv-1")
+		counts, err = counters.Counts(ctx, "user-1")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"conv-2": 1}, counts)
+		msgRepo.AssertExpectations(t)
+	})
+
+	t.Run("a user with nothing unread stays seeded", func(t *testing.T) {
+		msgRepo := new(mocks.MockMessageRepository)
+		msgRepo.On("GetUnreadCountsByUser", mock.Anything, "user-1").Return(map[string]int{}, nil).Once()
+		counters, _ := newTestUnreadCounters(t, msgRepo)
+
+		for i := 0; i < 2; i++ {
+			total, err := counters.Total(ctx, "user-1")
+			require.NoError(t, err)
+			assert.Zero(t, total)
+		}
+		msgRepo.AssertExpectations(t)
+	})
+
+	t.Run("sent messages count for the recipient", func(t *testing.T) {
+		msgRepo := new(mocks.MockMessageRepository)
+		msgRepo.On("GetUnreadCountsByUser", mock.Anything, "user-2").Return(map[string]int{}, nil).Once()
+		counters, _ := newTestUnreadCounters(t, msgRepo)
+		_, err := counters.Counts(ctx, "user-2")
+		require.NoError(t, err)
+
+		bus := events.NewSync(nil)
+		counters.SubscribeMessages(bus)
+		bus.Publish(ctx, MessageSent{Message: newTestMessage("msg-1", "conv-1", "user-1"), RecipientID: "user-2"})
+
+		counts, err := counters.Counts(ctx, "user-2")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"conv-1": 1}, counts)
+	})
+
+	t.Run("reconcile fixes drift", func(t *testing.T) {
+		msgRepo := new(mocks.MockMessageRepository)
+		msgRepo.On("GetUnreadCountsByUser", mock.Anything, "user-1").Return(map[string]int{"conv-1": 1}, nil).Once()
+		msgRepo.On("GetUnreadCountsByUser", mock.Anything, "user-1").Return(map[string]int{}, nil).Once()
+		counters, _ := newTestUnreadCounters(t, msgRepo)
+
+		_, drifted, err := counters.Reconcile(ctx, "user-1")
+		require.NoError(t, err)
+		assert.True(t, drifted)
+
+		require.NoError(t, counters.ReconcileAll(ctx))
+		counts, err := counters.Counts(ctx, "user-1")
+		require.NoError(t, err)
+		assert.Empty(t, counts)
+		msgRepo.AssertExpectations(t)
+	})
+}
+
+func TestChatService_GetUnreadBadge(t *testing.T) {
+	msgRepo := new(mocks.MockMessageRepository)
+	msgRepo.On("GetUnreadCountsByUser", mock.Anything, "user-1").
+		Return(map[string]int{"conv-1": 2, "conv-2": 3}, nil)
+
+	badge, err := newTestChatService(new(mocks.MockConversationRepository), msgRepo, new(mocks.MockUserRepository)).
+		GetUnreadBadge(context.Background(), "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, 5, badge.Messages)
+	assert.Equal(t, 5, badge.Total)
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/hamsaya/backend/internal/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	// uploadSessionTTL is how long uploads wait for a post to claim them
+	// before the cleanup job deletes them.
+	uploadSessionTTL = 24 * time.Hour
+	// uploadCleanupBatch is how many stale uploads one cleanup pass loads.
+	uploadCleanupBatch = 200
+)
+
+// requireUploadSession makes upload_session_id mandatory on posts with
+// attachments. Default false so apps that predate upload sessions keep
+// working; set env REQUIRE_UPLOAD_SESSION=true once they're gone.
+var requireUploadSession = strings.EqualFold(
+	strings.TrimSpace(os.Getenv("REQUIRE_UPLOAD_SESSION")), "true")
+
+// uploadDeleter is the part of the storage service the cleanup uses.
+type uploadDeleter interface {
+	DeleteImage(ctx context.Context, url string) error
+}
+
+// UploadSessionService links pre-uploaded post images to the post that
+// uses them. The app opens a session, uploads images into it, then names it
+// on CreatePost, which claims the images it attached. Uploads that no post
+// claims within uploadSessionTTL are deleted from storage by CleanupStale
+// (run on a ticker by the leader).
+//
+// A nil *UploadSessionService does nothing, so services work unwired.
+type UploadSessionService struct {
+	repo    repositories.UploadSessionRepository
+	storage uploadDeleter
+	logger  *zap.Logger
+}
+
+// NewUploadSessionService creates the upload session service.
+func NewUploadSessionService(repo repositories.UploadSessionRepository, storage uploadDeleter, logger *zap.Logger) *UploadSessionService {
+	return &UploadSessionService{
+		repo:    repo,
+		storage: storage,
+		logger:  logger,
+	}
+}
+
+// Create opens a new upload session for the user.
+func (s *UploadSessionService) Create(ctx context.Context, userID string) (*models.UploadSessionResponse, error) {
+	session := &models.UploadSession{ID: uuid.NewString(), UserID: userID}
+	if err := s.repo.Create(ctx, session); err != nil {
+		s.logger.Error("Failed to create upload session", zap.Error(err), zap.String("user_id", userID))
+		return nil, utils.NewInternalError("Failed to create upload session", err)
+	}
+	return &models.UploadSessionResponse{
+		ID:        session.ID,
+		ExpiresAt: session.CreatedAt.Add(uploadSessionTTL),
+	}, nil
+}
+
+// CheckOpen verifies the user can still upload into the session: it is
+// theirs, unclaimed and not expired.
+func (s *UploadSessionService) CheckOpen(ctx context.Context, sessionID, userID string) error {
+	if s == nil {
+		return nil
+	}
+	_, err := s.open(ctx, sessionID, userID)
+	return err
+}
+
+func (s *UploadSessionService) open(ctx context.Context, sessionID, userID string) (*models.UploadSession, error) {
+	session, err := s.repo.GetByID(ctx, sessionID)
+	if errors.Is(err, repositories.ErrUploadSessionNotFound) || (err == nil && session.UserID != userID) {
+		return nil, utils.NewNotFoundError("Upload session not found", err)
+	}
+	if err != nil {
+		return nil, utils.NewInternalError("Failed to load upload session", err)
+	}
+	if session.ClaimedAt != nil {
+		return nil, utils.NewBadRequestError("This upload session was already used for a post", nil)
+	}
+	if time.Since(session.CreatedAt) > uploadSessionTTL {
+		return nil, utils.NewBadRequestError("This upload session has expired; upload the images again", nil)
+	}
+	return session, nil
+}
+
+// RecordUpload adds an uploaded image to the session.
+func (s *UploadSessionService) RecordUpload(ctx context.Context, sessionID string, photo *models.Photo) error {
+	if s == nil || photo == nil {
+		return nil
+	}
+	file := &models.UploadSessionFile{ID: uuid.NewString(), SessionID: sessionID, Photo: *photo}
+	if err := s.repo.AddFile(ctx, file); err != nil {
+		s.logger.Error("Failed to record upload", zap.Error(err), zap.String("session_id", sessionID))
+		return utils.NewInternalError("Failed to record upload", err)
+	}
+	return nil
+}
+
+// ValidateAttachments checks that every attachment URL was uploaded into
+// the user's open session.
+func (s *UploadSessionService) ValidateAttachments(ctx context.Context, sessionID, userID string, urls []string) error {
+	if s == nil {
+		return nil
+	}
+	if _, err := s.open(ctx, sessionID, userID); err != nil {
+		return err
+	}
+	files, err := s.repo.ListFiles(ctx, sessionID)
+	if err != nil {
+		return utils.NewInternalError("Failed to load upload session", err)
+	}
+	uploaded := make(map[string]bool, len(files))
+	for _, f := range files {
+		uploaded[f.Photo.URL] = true
+	}
+	for _, url := range urls {
+		if !uploaded[url] {
+			return utils.NewBadRequestError("Attachments must be uploaded in the post's upload session", nil)
+		}
+	}
+	return nil
+}
+
+// Claim marks the session as used by the post and keeps the uploads it
+// attached. The post already exists by then, so failures are only logged;
+// the worst case is the cleanup job treating the images as abandoned,
+// which it won't do while an attachment points at them.
+func (s *UploadSessionService) Claim(ctx context.Context, sessionID, postID string, urls []string) {
+	if s == nil {
+		return
+	}
+	claimed, err := s.repo.Claim(ctx, sessionID, postID, urls)
+	if err != nil {
+		s.logger.Warn("Failed to claim upload session",
+			zap.String("session_id", sessionID),
+			zap.String("post_id", postID),
+			zap.Error(err),
+		)
+		return
+	}
+	if !claimed {
+		s.logger.Warn("Upload session already claimed",
+			zap.String("session_id", sessionID),
+			zap.String("post_id", postID),
+		)
+	}
+}
+
+// CleanupStale deletes uploads left unclaimed for longer than
+// uploadSessionTTL, from storage and then from the session, and drops the
+// sessions left empty. An upload whose storage delete fails stays recorded
+// and is retried on the next run.
+func (s *UploadSessionService) CleanupStale(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	cutoff := time.Now().Add(-uploadSessionTTL)
+	deleted := 0
+	for {
+		files, err := s.repo.ListStaleFiles(ctx, cutoff, uploadCleanupBatch)
+		if err != nil {
+			return err
+		}
+
+		var done []string
+		for _, f := range files {
+			if err := s.deleteFromStorage(ctx, f.Photo); err != nil {
+				s.logger.Warn("Failed to delete stale upload",
+					zap.String("url", f.Photo.URL),
+					zap.Error(err),
+				)
+				continue
+			}
+			done = append(done, f.ID)
+		}
+		if err := s.repo.DeleteFiles(ctx, done); err != nil {
+			return err
+		}
+		deleted += len(done)
+
+		// A short batch is the last one; a batch where every delete failed
+		// would come back unchanged, so stop rather than spin.
+		if len(files) < uploadCleanupBatch || len(done) == 0 {
+			break
+		}
+	}
+
+	sessions, err := s.repo.DeleteEmptySessions(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+	if deleted > 0 || sessions > 0 {
+		s.logger.Info("Stale uploads cleaned up",
+			zap.Int("files", deleted),
+			zap.Int64("sessions", sessions),
+		)
+	}
+	return nil
+}
+
+// deleteFromStorage removes the image and its resized variants.
+func (s *UploadSessionService) deleteFromStorage(ctx context.Context, photo models.Photo) error {
+	seen := map[string]bool{}
+	for _, url := range []string{photo.URL, photo.ThumbURL, photo.MediumURL} {
+		if url == "" || seen[url] {
+			continue
+		}
+		seen[url] = true
+		if err := s.storage.DeleteImage(ctx, url); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attachmentURLs returns the URLs of a create request's attachments,
+// skipping ones that don't parse (CreatePost skips those too).
+func attachmentURLs(raws []json.RawMessage) []string {
+	var urls []string
+	for _, raw := range raws {
+		photo, err := models.ParseAttachmentPhoto(raw)
+		if err != nil || photo.URL == "" {
+			continue
+		}
+		urls = append(urls, photo.URL)
+	}
+	return urls
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hamsaya/backend/internal/mocks"
+	"github.com/hamsaya/backend/internal/models"
+	"github.com/hamsaya/backend/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeUploadDeleter struct {
+	deleted []string
+	fail    map[string]bool
+}
+
+func (f *fakeUploadDeleter) DeleteImage(ctx context.Context, url string) error {
+	if f.fail[url] {
+		return errors.New("storage down")
+	}
+	f.deleted = append(f.deleted, url)
+	return nil
+}
+
+func TestUploadSessionService_ValidateAttachments(t *testing.T) {
+	ctx := context.Background()
+	openSession := &models.UploadSession{ID: "sess-1", UserID: "user-1", CreatedAt: time.Now().Add(-time.Hour)}
+	files := []*models.UploadSessionFile{{ID: "f-1", SessionID: "sess-1", Photo: models.Photo{URL: "https://cdn.example.com/posts/a.webp"}}}
+
+	t.Run("uploads from the session pass", func(t *testing.T) {
+		repo := new(mocks.MockUploadSessionRepository)
+		repo.On("GetByID", ctx, "sess-1").Return(openSession, nil)
+		repo.On("ListFiles", ctx, "sess-1").Return(files, nil)
+		svc := NewUploadSessionService(repo, &fakeUploadDeleter{}, zap.NewNop())
+
+		assert.NoError(t, svc.ValidateAttachments(ctx, "sess-1", "user-1", []string{"https://cdn.example.com/posts/a.webp"}))
+	})
+
+	t.Run("foreign attachments are rejected", func(t *testing.T) {
+		repo := new(mocks.MockUploadSessionRepository)
+		repo.On("GetByID", ctx, "sess-1").Return(openSession, nil)
+		repo.On("ListFiles", ctx, "sess-1").Return(files, nil)
+		svc := NewUploadSessionService(repo, &fakeUploadDeleter{}, zap.NewNop())
+
+		err := svc.ValidateAttachments(ctx, "sess-1", "user-1", []string{"https://elsewhere.example.com/b.jpg"})
+		requireAppErrorCode(t, err, http.StatusBadRequest)
+	})
+
+	t.Run("someone else's session is not found", func(t *testing.T) {
+		repo := new(mocks.MockUploadSessionRepository)
+		repo.On("GetByID", ctx, "sess-1").Return(openSession, nil)
+		svc := NewUploadSessionService(repo, &fakeUploadDeleter{}, zap.NewNop())
+
+		requireAppErrorCode(t, svc.ValidateAttachments(ctx, "sess-1", "user-2", nil), http.StatusNotFound)
+	})
+
+	t.Run("claimed and expired sessions are closed", func(t *testing.T) {
+		claimedAt := time.Now()
+		repo := new(mocks.MockUploadSessionRepository)
+		repo.On("GetByID", ctx, "claimed").
+			Return(&models.UploadSession{ID: "claimed", UserID: "user-1", ClaimedAt: &claimedAt, CreatedAt: time.Now()}, nil)
+		repo.On("GetByID", ctx, "old").
+			Return(&models.UploadSession{ID: "old", UserID: "user-1", CreatedAt: time.Now().Add(-25 * time.Hour)}, nil)
+		repo.On("GetByID", ctx, "missing").Return(nil, repositories.ErrUploadSessionNotFound)
+		svc := NewUploadSessionService(repo, &fakeUploadDeleter{}, zap.NewNop())
+
+		requireAppErrorCode(t, svc.CheckOpen(ctx, "claimed", "user-1"), http.StatusBadRequest)
+		requireAppErrorCode(t, svc.CheckOpen(ctx, "old", "user-1"), http.StatusBadRequest)
+		requireAppErrorCode(t, svc.CheckOpen(ctx, "missing", "user-1"), http.StatusNotFound)
+	})
+}
+
+func TestUploadSessionService_CleanupStale(t *testing.T) {
+	ctx := context.Background()
+	repo := new(mocks.MockUploadSessionRepository)
+	repo.On("ListStaleFiles", ctx, mock.AnythingOfType("time.Time"), uploadCleanupBatch).Return([]*models.UploadSessionFile{
+		{ID: "f-1", Photo: models.Photo{
+			URL:       "https://cdn.example.com/posts/a.webp",
+			ThumbURL:  "https://cdn.example.com/posts/a_thumb.webp",
+			MediumURL: "https://cdn.example.com/posts/a_medium.webp",
+		}},
+		{ID: "f-2", Photo: models.Photo{URL: "https://cdn.example.com/posts/b.webp"}},
+	}, nil).Once()
+	repo.On("DeleteFiles", ctx, []string{"f-1"}).Return(nil).Once()
+	repo.On("DeleteEmptySessions", ctx, mock.AnythingOfType("time.Time")).Return(int64(1), nil).Once()
+
+	store := &fakeUploadDeleter{fail: map[string]bool{"https://cdn.example.com/posts/b.webp": true}}
+	svc := NewUploadSessionService(repo, store, zap.NewNop())
+	require.NoError(t, svc.CleanupStale(ctx))
+
+	// The failed delete stays recorded for the next run.
+	assert.ElementsMatch(t, []string{
+		"https://cdn.example.com/posts/a.webp",
+		"https://cdn.example.com/posts/a_thumb.webp",
+		"https://cdn.example.com/posts/a_medium.webp",
+	}, store.deleted)
+	repo.AssertExpectations(t)
+
+	var nilSvc *UploadSessionService
+	assert.NoError(t, nilSvc.CleanupStale(ctx))
+}
+
+func TestPostService_CreatePostChecksUploadSession(t *testing.T) {
+	ctx := context.Background()
+	repo := new(mocks.MockUploadSessionRepository)
+	repo.On("GetByID", mock.Anything, "sess-1").
+		Return(&models.UploadSession{ID: "sess-1", UserID: "user-1", CreatedAt: time.Now()}, nil)
+	repo.On("ListFiles", mock.Anything, "sess-1").Return([]*models.UploadSessionFile{}, nil)
+
+	postRepo := new(mocks.MockPostRepository)
+	svc := newTestPostService(postRepo, new(mocks.MockUserRepository)).
+		WithUploadSessions(NewUploadSessionService(repo, &fakeUploadDeleter{}, zap.NewNop()))
+
+	sessionID := "sess-1"
+	description := "Selling my bike"
+	_, err := svc.CreatePost(ctx, "user-1", &models.CreatePostRequest{
+		Type:            models.PostTypeFeed,
+		Description:     &description,
+		Attachments:     []json.RawMessage{json.RawMessage(`"https://cdn.example.com/posts/other.webp"`)},
+		UploadSessionID: &sessionID,
+	})
+
+	requireAppErrorCode(t, err, http.StatusBadRequest)
+	repo.AssertExpectations(t)
+	postRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
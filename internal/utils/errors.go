@@ -2,7 +2,9 @@ package utils
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"time"
 )
 
 // Common errors
@@ -65,6 +67,141 @@ func NewTooManyRequestsError(message string, err error) *AppError {
 	return NewAppError(http.StatusTooManyRequests, message, err)
 }
 
+// SlowDownError is the cause attached to a per-user creation throttle 429.
+// SendError recognises it and adds code "slow_down", a Retry-After header and
+// this struct as data so the app can show a countdown instead of a generic
+// failure.
+type SlowDownError struct {
+	Action            string `json:"action"`
+	RetryAfterSeconds int    `json:"retry_after_seconds"`
+}
+
+func (e *SlowDownError) Error() string {
+	return fmt.Sprintf("%s creation throttled, retry in %ds", e.Action, e.RetryAfterSeconds)
+}
+
+// NewSlowDownError builds the 429 returned when a user creates content of
+// one kind (post, comment, message) faster than its configured limit.
+func NewSlowDownError(action string, retryAfter time.Duration) *AppError {
+	seconds := int((retryAfter + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return NewAppError(http.StatusTooManyRequests,
+		"You're doing that too often. Please slow down and try again shortly.",
+		&SlowDownError{Action: action, RetryAfterSeconds: seconds})
+}
+
+// LoginChallengeError is the cause attached to a failed or throttled login
+// once the login guard sees repeated failures for the account or IP.
+// SendError recognises it and adds code "login_challenge", this struct as
+// data and, when a delay applies, a Retry-After header, so the app can show
+// a countdown or a CAPTCHA before the next attempt.
+type LoginChallengeError struct {
+	RetryAfterSeconds int  `json:"retry_after_seconds"`
+	CaptchaRequired   bool `json:"captcha_required"`
+}
+
+func (e *LoginChallengeError) Error() string {
+	return fmt.Sprintf("login challenged (retry in %ds, captcha=%t)", e.RetryAfterSeconds, e.CaptchaRequired)
+}
+
+// NewLoginThrottledError builds the 429 returned when a login arrives
+// before the progressive delay since the last failure has passed.
+func NewLoginThrottledError(retryAfter time.Duration, captchaRequired bool) *AppError {
+	seconds := int((retryAfter + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return NewAppError(http.StatusTooManyRequests,
+		"Too many failed login attempts. Please wait before trying again.",
+		&LoginChallengeError{RetryAfterSeconds: seconds, CaptchaRequired: captchaRequired})
+}
+
+// VersionConflictError is the cause attached to a 409 when an update was
+// based on a stale version of the resource (another device saved first).
+// SendError recognises it and adds code "version_conflict" with the current
+// server state as data, so the client can merge and retry.
+type VersionConflictError struct {
+	Current interface{}
+}
+
+func (e *VersionConflictError) Error() string {
+	return "version conflict"
+}
+
+// NewVersionConflictError builds the 409 returned for a stale update;
+// current is the resource as the server has it now.
+func NewVersionConflictError(message string, current interface{}) *AppError {
+	return NewAppError(http.StatusConflict, message, &VersionConflictError{Current: current})
+}
+
+// ServiceModeError is the cause attached to the 503 returned while the API
+// is in maintenance or read-only mode. SendError recognises it, uses Mode as
+// the response code ("maintenance" or "read_only") and adds a Retry-After
+// header so clients back off instead of hammering a paused API.
+type ServiceModeError struct {
+	Mode              string `json:"mode"`
+	RetryAfterSeconds int    `json:"retry_after_seconds"`
+}
+
+func (e *ServiceModeError) Error() string {
+	return fmt.Sprintf("service in %s mode, retry in %ds", e.Mode, e.RetryAfterSeconds)
+}
+
+// Service mode codes carried by ServiceModeError.
+const (
+	ServiceModeMaintenance = "maintenance"
+	ServiceModeReadOnly    = "read_only"
+)
+
+// NewMaintenanceError builds the 503 returned to non-admin traffic while
+// maintenance mode is on.
+func NewMaintenanceError(retryAfter time.Duration) *AppError {
+	return NewAppError(http.StatusServiceUnavailable,
+		"Hamsaya is down for maintenance. Please try again shortly.",
+		&ServiceModeError{Mode: ServiceModeMaintenance, RetryAfterSeconds: retryAfterSeconds(retryAfter)})
+}
+
+// NewReadOnlyError builds the 503 returned for writes while read-only mode
+// is on. Reads keep working.
+func NewReadOnlyError(retryAfter time.Duration) *AppError {
+	return NewAppError(http.StatusServiceUnavailable,
+		"Hamsaya is temporarily read-only. Your changes can't be saved right now; please try again shortly.",
+		&ServiceModeError{Mode: ServiceModeReadOnly, RetryAfterSeconds: retryAfterSeconds(retryAfter)})
+}
+
+// StorageQuotaError is the cause attached to the 413 returned when an
+// upload would take the user past their storage quota. SendError recognises
+// it and adds code "storage_quota_exceeded" with this struct as data, so the
+// app can show the usage and link to the storage screen.
+type StorageQuotaError struct {
+	UsedBytes   int64 `json:"used_bytes"`
+	LimitBytes  int64 `json:"limit_bytes"`
+	UploadBytes int64 `json:"upload_bytes"`
+}
+
+func (e *StorageQuotaError) Error() string {
+	return fmt.Sprintf("storage quota exceeded: %d + %d > %d bytes", e.UsedBytes, e.UploadBytes, e.LimitBytes)
+}
+
+// NewStorageQuotaError builds the 413 returned when an upload of uploadBytes
+// doesn't fit in the user's remaining storage.
+func NewStorageQuotaError(used, limit, uploadBytes int64) *AppError {
+	return NewAppError(http.StatusRequestEntityTooLarge,
+		"You've run out of storage space. Delete old photos or videos to free some up.",
+		&StorageQuotaError{UsedBytes: used, LimitBytes: limit, UploadBytes: uploadBytes})
+}
+
+// retryAfterSeconds rounds d up to whole seconds, at least one.
+func retryAfterSeconds(d time.Duration) int {
+	seconds := int((d + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	return seconds
+}
+
 func NewForbiddenError(message string, err error) *AppError {
 	return NewAppError(http.StatusForbidden, message, err)
 }
@@ -47,6 +47,12 @@ func InitLogger(level string) error {
 		encoding = "json"
 		development = false
 	}
+	// LOG_FORMAT=json forces the aggregator-friendly encoding outside
+	// production (staging, local stacks shipping to Loki).
+	if os.Getenv("LOG_FORMAT") == "json" {
+		levelEncoder = zapcore.CapitalLevelEncoder
+		encoding = "json"
+	}
 
 	encoderConfig := zapcore.EncoderConfig{
 		TimeKey:        "timestamp",
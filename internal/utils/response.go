@@ -1,8 +1,11 @@
 package utils
 
 import (
+	"errors"
 	"net/http"
 	"os"
+	"strconv"
+	"sync/atomic"
 
 	"github.com/gin-gonic/gin"
 )
@@ -11,6 +14,7 @@ import (
 type Response struct {
 	Success bool        `json:"success"`
 	Message string      `json:"message,omitempty"`
+	Code    string      `json:"code,omitempty"`
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty"`
 }
@@ -30,9 +34,33 @@ type PaginatedResponse struct {
 	Meta    Pagination  `json:"meta"`
 }
 
+// ListMeta is the pagination block of every list response. Page-numbered
+// lists set Page and Total; cursor lists set NextCursor and leave Total
+// unset when counting would cost a query. Review queues add per-status
+// Counts.
+type ListMeta struct {
+	Page       int              `json:"page,omitempty"`
+	Limit      int              `json:"limit"`
+	Total      *int64           `json:"total,omitempty"`
+	NextCursor *string          `json:"next_cursor,omitempty"`
+	Counts     map[string]int64 `json:"counts,omitempty"`
+}
+
+// ListResponse is the envelope list endpoints return: the items in data,
+// pagination in meta.
+type ListResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message,omitempty"`
+	Data    interface{} `json:"data"`
+	Meta    ListMeta    `json:"meta"`
+	Error   string      `json:"error,omitempty"`
+}
+
 // Pagination holds pagination metadata
-// Matches frontend PaginationMeta structure
+// Matches frontend PaginationMeta structure. The embedded ListMeta adds the
+// list-envelope keys so clients can switch before the legacy keys go.
 type Pagination struct {
+	ListMeta
 	CurrentPage  int                    `json:"currentPage"`
 	ItemsPerPage int                    `json:"itemsPerPage"`
 	TotalItems   int64                  `json:"totalItems"`
@@ -41,6 +69,46 @@ type Pagination struct {
 	Sorts        map[string]interface{} `json:"sorts,omitempty"`
 }
 
+// legacyListEnvelope keeps list endpoints on their pre-envelope data shape
+// until every client reads ListResponse. See SetLegacyListEnvelope.
+var legacyListEnvelope atomic.Bool
+
+func init() {
+	legacyListEnvelope.Store(true)
+}
+
+// SetLegacyListEnvelope switches list endpoints between their old data
+// shapes (true) and the ListResponse envelope (false).
+func SetLegacyListEnvelope(legacy bool) {
+	legacyListEnvelope.Store(legacy)
+}
+
+// EnvelopeHeader lets a client opt in to ListResponse while the legacy
+// shape is still the default.
+const EnvelopeHeader = "X-Envelope"
+
+func wantsLegacyList(c *gin.Context) bool {
+	if !legacyListEnvelope.Load() {
+		return false
+	}
+	return c.Request == nil || c.GetHeader(EnvelopeHeader) != "2"
+}
+
+// OffsetMeta describes one limit/offset page of total items.
+func OffsetMeta(limit, offset, total int) ListMeta {
+	count := int64(total)
+	meta := ListMeta{Limit: limit, Total: &count}
+	if limit > 0 {
+		meta.Page = offset/limit + 1
+	}
+	return meta
+}
+
+// PageMeta describes one page-numbered page of total items.
+func PageMeta(page, limit int, total int64) ListMeta {
+	return ListMeta{Page: page, Limit: limit, Total: &total}
+}
+
 // SendSuccess sends a successful response
 func SendSuccess(c *gin.Context, statusCode int, message string, data interface{}) {
 	c.JSON(statusCode, Response{
@@ -60,6 +128,41 @@ func SendError(c *gin.Context, statusCode int, message string, err error) {
 		Message: message,
 	}
 
+	var slowDown *SlowDownError
+	if errors.As(err, &slowDown) {
+		response.Code = "slow_down"
+		response.Data = slowDown
+		c.Header("Retry-After", strconv.Itoa(slowDown.RetryAfterSeconds))
+	}
+
+	var challenge *LoginChallengeError
+	if errors.As(err, &challenge) {
+		response.Code = "login_challenge"
+		response.Data = challenge
+		if challenge.RetryAfterSeconds > 0 {
+			c.Header("Retry-After", strconv.Itoa(challenge.RetryAfterSeconds))
+		}
+	}
+
+	var serviceMode *ServiceModeError
+	if errors.As(err, &serviceMode) {
+		response.Code = serviceMode.Mode
+		response.Data = serviceMode
+		c.Header("Retry-After", strconv.Itoa(serviceMode.RetryAfterSeconds))
+	}
+
+	var conflict *VersionConflictError
+	if errors.As(err, &conflict) {
+		response.Code = "version_conflict"
+		response.Data = conflict.Current
+	}
+
+	var quota *StorageQuotaError
+	if errors.As(err, &quota) {
+		response.Code = "storage_quota_exceeded"
+		response.Data = quota
+	}
+
 	if err != nil {
 		// 4xx = client mistake (warn-worthy, not alert-worthy);
 		// 5xx = server fault (real error, page-on-call).
@@ -95,25 +198,80 @@ func SendAppError(c *gin.Context, appErr *AppError) {
 	SendError(c, appErr.Code, appErr.Message, appErr.Err)
 }
 
+// SendList sends a list in the ListResponse envelope. legacy is the data
+// the endpoint returned before the envelope (usually the items wrapped with
+// their counts); while the legacy envelope is on it is sent as data instead
+// of items, with meta added alongside. Pass nil when there is no old shape.
+func SendList(c *gin.Context, message string, items interface{}, meta ListMeta, legacy interface{}) {
+	data := items
+	if legacy != nil && wantsLegacyList(c) {
+		data = legacy
+	}
+	c.JSON(http.StatusOK, ListResponse{
+		Success: true,
+		Message: message,
+		Data:    data,
+		Meta:    meta,
+	})
+}
+
 // SendPaginated sends a paginated response
 // Optional: pass filters and sorts maps if you want to include them in response
 func SendPaginated(c *gin.Context, data interface{}, page, limit int, totalCount int64) {
 	SendPaginatedWithFilters(c, data, page, limit, totalCount, nil, nil)
 }
 
-// SendPaginatedWithFilters sends a paginated response with filters and sorts
+// SendPaginatedWithFilters sends a paginated response with filters and sorts.
+// A "next_cursor" string in sorts becomes meta.next_cursor.
 func SendPaginatedWithFilters(c *gin.Context, data interface{}, page, limit int, totalCount int64, filters map[string]interface{}, sorts map[string]interface{}) {
-	totalPages := int(totalCount) / limit
-	if int(totalCount)%limit != 0 {
-		totalPages++
+	meta := PageMeta(page, limit, totalCount)
+	if cursor, ok := sorts["next_cursor"].(string); ok {
+		meta.NextCursor = &cursor
+	}
+	sendPage(c, data, meta, filters, sorts)
+}
+
+// SendCursorPage sends one page of a cursor-paginated list whose total is
+// not counted. nextCursor is empty on the last page.
+func SendCursorPage(c *gin.Context, data interface{}, limit int, nextCursor string, filters map[string]interface{}, sorts map[string]interface{}) {
+	meta := ListMeta{Limit: limit}
+	if nextCursor != "" {
+		meta.NextCursor = &nextCursor
+	}
+	sendPage(c, data, meta, filters, sorts)
+}
+
+// sendPage writes a page as ListResponse, or in the legacy PaginatedResponse
+// shape (with the ListMeta keys added) while that is still the default.
+func sendPage(c *gin.Context, data interface{}, meta ListMeta, filters, sorts map[string]interface{}) {
+	if !wantsLegacyList(c) {
+		c.JSON(http.StatusOK, ListResponse{Success: true, Data: data, Meta: meta})
+		return
+	}
+
+	var totalCount int64
+	if meta.Total != nil {
+		totalCount = *meta.Total
+	}
+	page := meta.Page
+	if page == 0 {
+		page = 1
+	}
+	totalPages := 0
+	if meta.Limit > 0 {
+		totalPages = int(totalCount) / meta.Limit
+		if int(totalCount)%meta.Limit != 0 {
+			totalPages++
+		}
 	}
 
 	c.JSON(http.StatusOK, PaginatedResponse{
 		Success: true,
 		Data:    data,
 		Meta: Pagination{
+			ListMeta:     meta,
 			CurrentPage:  page,
-			ItemsPerPage: limit,
+			ItemsPerPage: meta.Limit,
 			TotalItems:   totalCount,
 			TotalPages:   totalPages,
 			Filters:      filters,
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -74,3 +75,187 @@ func TestSendPaginated(t *testing.T) {
 	assert.Equal(t, 3, response.Meta.TotalPages)
 	assert.Equal(t, int64(25), response.Meta.TotalItems)
 }
+
+func TestSendPaginated_ListMeta(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/test", nil)
+
+	SendPaginatedWithFilters(c, []string{"a"}, 2, 10, 25, nil, map[string]interface{}{"next_cursor": "abc"})
+
+	var body struct {
+		Meta map[string]interface{} `json:"meta"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	meta := body.Meta
+	// Legacy keys stay while the envelope keys are added alongside.
+	assert.Equal(t, float64(2), meta["currentPage"])
+	assert.Equal(t, float64(2), meta["page"])
+	assert.Equal(t, float64(10), meta["limit"])
+	assert.Equal(t, float64(25), meta["total"])
+	assert.Equal(t, "abc", meta["next_cursor"])
+}
+
+func TestSendList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	items := []string{"a", "b"}
+	legacy := gin.H{"items": items, "total": 12, "limit": 2, "offset": 4}
+
+	send := func(header string) map[string]interface{} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/test", nil)
+		if header != "" {
+			c.Request.Header.Set(EnvelopeHeader, header)
+		}
+		SendList(c, "Items", items, OffsetMeta(2, 4, 12), legacy)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var body map[string]interface{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, true, body["success"])
+		assert.Equal(t, "Items", body["message"])
+		assert.Equal(t, map[string]interface{}{"page": float64(3), "limit": float64(2), "total": float64(12)}, body["meta"])
+		return body
+	}
+
+	t.Run("legacy by default", func(t *testing.T) {
+		body := send("")
+		assert.Equal(t, float64(12), body["data"].(map[string]interface{})["total"])
+	})
+
+	t.Run("header opts in", func(t *testing.T) {
+		body := send("2")
+		assert.Equal(t, []interface{}{"a", "b"}, body["data"])
+	})
+
+	t.Run("flag off", func(t *testing.T) {
+		SetLegacyListEnvelope(false)
+		defer SetLegacyListEnvelope(true)
+		body := send("")
+		assert.Equal(t, []interface{}{"a", "b"}, body["data"])
+	})
+}
+
+func TestSendCursorPage_NoTotal(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	SetLegacyListEnvelope(false)
+	defer SetLegacyListEnvelope(true)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/test", nil)
+
+	SendCursorPage(c, []string{"a"}, 20, "", nil, nil)
+
+	var response ListResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, ListMeta{Limit: 20}, response.Meta)
+}
+
+func TestSendError_SlowDown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	if Logger == nil {
+		if err := InitLogger("error"); err != nil {
+			t.Fatalf("Failed to initialize logger: %v", err)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/posts/1/comments", nil)
+
+	appErr := NewSlowDownError("comment", 1500*time.Millisecond)
+	SendAppError(c, appErr)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+
+	var response struct {
+		Code string        `json:"code"`
+		Data SlowDownError `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "slow_down", response.Code)
+	assert.Equal(t, "comment", response.Data.Action)
+	assert.Equal(t, 2, response.Data.RetryAfterSeconds)
+}
+
+func TestSendError_VersionConflict(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	if Logger == nil {
+		if err := InitLogger("error"); err != nil {
+			t.Fatalf("Failed to initialize logger: %v", err)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("PUT", "/posts/1", nil)
+
+	SendAppError(c, NewVersionConflictError("This post was changed on another device", map[string]int{"version": 5}))
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var response struct {
+		Code string         `json:"code"`
+		Data map[string]int `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "version_conflict", response.Code)
+	assert.Equal(t, 5, response.Data["version"])
+}
+
+func TestSendError_StorageQuota(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	if Logger == nil {
+		if err := InitLogger("error"); err != nil {
+			t.Fatalf("Failed to initialize logger: %v", err)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/posts/upload-image", nil)
+
+	SendAppError(c, NewStorageQuotaError(900, 1000, 200))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	var response struct {
+		Code string            `json:"code"`
+		Data StorageQuotaError `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "storage_quota_exceeded", response.Code)
+	assert.Equal(t, StorageQuotaError{UsedBytes: 900, LimitBytes: 1000, UploadBytes: 200}, response.Data)
+}
+
+func TestSendError_LoginChallenge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	if Logger == nil {
+		if err := InitLogger("error"); err != nil {
+			t.Fatalf("Failed to initialize logger: %v", err)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/auth/login", nil)
+
+	SendAppError(c, NewLoginThrottledError(4*time.Second, true))
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "4", w.Header().Get("Retry-After"))
+
+	var response struct {
+		Code string              `json:"code"`
+		Data LoginChallengeError `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "login_challenge", response.Code)
+	assert.Equal(t, 4, response.Data.RetryAfterSeconds)
+	assert.True(t, response.Data.CaptchaRequired)
+}
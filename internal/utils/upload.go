@@ -15,6 +15,9 @@ const MaxImageUploadBytes = 25 * 1024 * 1024 // 25 MB
 // MaxVideoUploadBytes is the per-file cap for video uploads.
 const MaxVideoUploadBytes = 100 * 1024 * 1024 // 100 MB
 
+// MaxCSVUploadBytes is the cap for spreadsheet imports.
+const MaxCSVUploadBytes = 5 * 1024 * 1024 // 5 MB
+
 // EnforceUploadSize aborts the request with 413 when the multipart file
 // exceeds [maxBytes]. Returns true when the upload is acceptable, false
 // when the response has already been written and the caller should bail.
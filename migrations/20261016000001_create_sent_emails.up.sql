@@ -0,0 +1,38 @@
+-- Outbound email delivery log + suppression list.
+--
+-- sent_emails records every transactional/engagement email the backend
+-- hands to a provider so support can answer "did the verification code
+-- actually go out?". Provider webhooks (Resend → /webhooks/email/resend)
+-- advance the status by provider_message_id.
+--
+-- email_suppressions is the undeliverable-address list. A hard bounce or a
+-- spam complaint inserts a row; EmailService refuses to send to suppressed
+-- addresses so we stop burning sender reputation on dead mailboxes.
+CREATE TABLE IF NOT EXISTS sent_emails (
+    id                  UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    to_email            TEXT NOT NULL,
+    subject             TEXT NOT NULL,
+    provider            TEXT NOT NULL,
+    provider_message_id TEXT,
+    status              TEXT NOT NULL DEFAULT 'sent'
+        CHECK (status IN ('sent', 'failed', 'suppressed', 'delivered', 'delayed', 'bounced', 'complained')),
+    error               TEXT,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_sent_emails_to_email_created
+    ON sent_emails (lower(to_email), created_at DESC);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sent_emails_provider_message
+    ON sent_emails (provider, provider_message_id)
+    WHERE provider_message_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS email_suppressions (
+    email      TEXT PRIMARY KEY, -- always stored lower-cased
+    reason     TEXT NOT NULL CHECK (reason IN ('bounce', 'complaint', 'manual')),
+    detail     TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE email_suppressions IS 'Addresses the provider reported as undeliverable (hard bounce) or that filed a spam complaint. Sends are skipped while a row exists.';
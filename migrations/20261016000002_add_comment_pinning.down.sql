@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_post_comments_one_pinned;
+ALTER TABLE post_comments DROP COLUMN IF EXISTS pinned_at;
@@ -0,0 +1,8 @@
+-- Post owners can pin one top-level comment to the top of their thread.
+-- pinned_at doubles as the pin flag; the partial unique index enforces
+-- "at most one live pinned comment per post".
+ALTER TABLE post_comments ADD COLUMN IF NOT EXISTS pinned_at TIMESTAMP WITH TIME ZONE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_post_comments_one_pinned
+    ON post_comments(post_id)
+    WHERE pinned_at IS NOT NULL AND deleted_at IS NULL;
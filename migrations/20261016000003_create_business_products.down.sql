@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_posts_business_product;
+ALTER TABLE messages DROP COLUMN IF EXISTS business_product_id;
+ALTER TABLE posts DROP COLUMN IF EXISTS business_product_id;
+DROP TABLE IF EXISTS business_products;
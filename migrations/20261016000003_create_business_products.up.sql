@@ -0,0 +1,32 @@
+-- Business catalog: products a business sells, managed separately from SELL
+-- posts so a listing can outlive any one post and be referenced from posts
+-- and chat messages.
+CREATE TABLE IF NOT EXISTS business_products (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    business_id UUID NOT NULL REFERENCES business_profiles(id) ON DELETE CASCADE,
+    name VARCHAR(200) NOT NULL,
+    description TEXT,
+    price NUMERIC(12,2),
+    currency VARCHAR(3),
+    photos JSONB NOT NULL DEFAULT '[]'::jsonb,
+    availability VARCHAR(20) NOT NULL DEFAULT 'IN_STOCK'
+        CHECK (availability IN ('IN_STOCK', 'OUT_OF_STOCK', 'PREORDER', 'DISCONTINUED')),
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_business_products_business
+    ON business_products(business_id, position, created_at DESC)
+    WHERE deleted_at IS NULL;
+
+-- A post or message can reference one catalog product. SET NULL keeps the
+-- post/message when the product row is purged.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS business_product_id UUID
+    REFERENCES business_products(id) ON DELETE SET NULL;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS business_product_id UUID
+    REFERENCES business_products(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_posts_business_product
+    ON posts(business_product_id) WHERE business_product_id IS NOT NULL;
@@ -0,0 +1,24 @@
+-- Booking / inquiry requests from customers to service businesses. The owner
+-- accepts or declines a PENDING request; the customer may cancel while it is
+-- PENDING or ACCEPTED.
+CREATE TABLE IF NOT EXISTS business_bookings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    business_id UUID NOT NULL REFERENCES business_profiles(id) ON DELETE CASCADE,
+    customer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    requested_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    note TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING'
+        CHECK (status IN ('PENDING', 'ACCEPTED', 'DECLINED', 'CANCELLED')),
+    response_note TEXT,
+    responded_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Owner calendar: bookings of a business within a time range.
+CREATE INDEX IF NOT EXISTS idx_business_bookings_business_time
+    ON business_bookings(business_id, requested_at);
+
+-- Customer's "my bookings" list.
+CREATE INDEX IF NOT EXISTS idx_business_bookings_customer
+    ON business_bookings(customer_id, created_at DESC);
@@ -0,0 +1,15 @@
+-- Group posts have nowhere to live without their group.
+DELETE FROM posts WHERE group_id IS NOT NULL;
+
+ALTER TABLE posts
+DROP CONSTRAINT IF EXISTS posts_visibility_check;
+
+ALTER TABLE posts
+ADD CONSTRAINT posts_visibility_check
+CHECK (visibility IN ('PUBLIC', 'FRIENDS', 'PRIVATE', 'VIEW_ONLY'));
+
+DROP INDEX IF EXISTS idx_posts_group_created;
+ALTER TABLE posts DROP COLUMN IF EXISTS group_id;
+
+DROP TABLE IF EXISTS group_members;
+DROP TABLE IF EXISTS groups;
@@ -0,0 +1,45 @@
+-- Neighborhood / interest groups. Posts can target a group (posts.group_id
+-- with visibility GROUP); those posts only appear in the group feed.
+CREATE TABLE IF NOT EXISTS groups (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(120) NOT NULL,
+    description TEXT,
+    kind VARCHAR(20) NOT NULL DEFAULT 'NEIGHBORHOOD' CHECK (kind IN ('NEIGHBORHOOD', 'INTEREST')),
+    privacy VARCHAR(20) NOT NULL DEFAULT 'PUBLIC' CHECK (privacy IN ('PUBLIC', 'PRIVATE')),
+    cover JSONB,
+    province VARCHAR(100),
+    district VARCHAR(100),
+    neighborhood VARCHAR(100),
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    member_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_groups_location ON groups(province, district) WHERE deleted_at IS NULL;
+
+-- status PENDING = join request awaiting approval (private groups).
+CREATE TABLE IF NOT EXISTS group_members (
+    group_id UUID NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL DEFAULT 'MEMBER' CHECK (role IN ('ADMIN', 'MODERATOR', 'MEMBER')),
+    status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'PENDING')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (group_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_members_user ON group_members(user_id) WHERE status = 'ACTIVE';
+CREATE INDEX IF NOT EXISTS idx_group_members_pending ON group_members(group_id, created_at) WHERE status = 'PENDING';
+
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS group_id UUID REFERENCES groups(id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_posts_group_created
+    ON posts(group_id, created_at DESC) WHERE group_id IS NOT NULL AND deleted_at IS NULL;
+
+ALTER TABLE posts
+DROP CONSTRAINT IF EXISTS posts_visibility_check;
+
+ALTER TABLE posts
+ADD CONSTRAINT posts_visibility_check
+CHECK (visibility IN ('PUBLIC', 'FRIENDS', 'PRIVATE', 'VIEW_ONLY', 'GROUP'));
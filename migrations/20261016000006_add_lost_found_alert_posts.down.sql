@@ -0,0 +1,17 @@
+DELETE FROM daily_post_limits WHERE post_type IN ('LOST_FOUND', 'ALERT');
+
+DROP INDEX IF EXISTS idx_posts_alert_urgency;
+
+DELETE FROM posts WHERE type IN ('LOST_FOUND', 'ALERT');
+
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS urgency,
+    DROP COLUMN IF EXISTS last_seen_at,
+    DROP COLUMN IF EXISTS last_seen_place,
+    DROP COLUMN IF EXISTS item_description,
+    DROP COLUMN IF EXISTS lost_found_kind;
+
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS posts_type_check;
+ALTER TABLE posts
+    ADD CONSTRAINT posts_type_check
+    CHECK (type IN ('FEED', 'EVENT', 'SELL', 'PULL'));
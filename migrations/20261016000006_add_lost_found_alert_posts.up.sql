@@ -0,0 +1,26 @@
+-- Lost & found and safety alert post types. Last-seen / alert coordinates
+-- reuse posts.address_location; the columns below carry the type-specific
+-- details.
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS posts_type_check;
+ALTER TABLE posts
+    ADD CONSTRAINT posts_type_check
+    CHECK (type IN ('FEED', 'EVENT', 'SELL', 'PULL', 'LOST_FOUND', 'ALERT'));
+
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS lost_found_kind VARCHAR(10)
+        CHECK (lost_found_kind IN ('LOST', 'FOUND')),
+    ADD COLUMN IF NOT EXISTS item_description TEXT,
+    ADD COLUMN IF NOT EXISTS last_seen_place VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS urgency VARCHAR(10)
+        CHECK (urgency IN ('LOW', 'MEDIUM', 'HIGH', 'CRITICAL'));
+
+CREATE INDEX IF NOT EXISTS idx_posts_alert_urgency
+    ON posts (urgency, created_at DESC)
+    WHERE type = 'ALERT' AND deleted_at IS NULL;
+
+INSERT INTO daily_post_limits (post_type, user_limit, business_multiplier, description)
+VALUES
+    ('LOST_FOUND', 3, 2.0, 'Lost & found notices'),
+    ('ALERT',      2, 1.0, 'Safety alerts — pushed to nearby users, keep low')
+ON CONFLICT (post_type) DO NOTHING;
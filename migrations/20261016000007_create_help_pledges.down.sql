@@ -0,0 +1,11 @@
+DELETE FROM daily_post_limits WHERE post_type = 'HELP';
+
+DROP TABLE IF EXISTS help_fulfillments;
+DROP TABLE IF EXISTS help_pledges;
+
+DELETE FROM posts WHERE type = 'HELP';
+
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS posts_type_check;
+ALTER TABLE posts
+    ADD CONSTRAINT posts_type_check
+    CHECK (type IN ('FEED', 'EVENT', 'SELL', 'PULL', 'LOST_FOUND', 'ALERT'));
@@ -0,0 +1,38 @@
+-- HELP posts: a neighbor asks for items or time, others pledge to help. The
+-- owner confirms each pledge once it has been delivered and can mark the
+-- whole request fulfilled.
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS posts_type_check;
+ALTER TABLE posts
+    ADD CONSTRAINT posts_type_check
+    CHECK (type IN ('FEED', 'EVENT', 'SELL', 'PULL', 'LOST_FOUND', 'ALERT', 'HELP'));
+
+CREATE TABLE IF NOT EXISTS help_pledges (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('ITEM', 'TIME')),
+    note TEXT,
+    quantity INTEGER CHECK (quantity > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'PLEDGED'
+        CHECK (status IN ('PLEDGED', 'FULFILLED', 'WITHDRAWN')),
+    fulfilled_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- One live pledge per user per request; a withdrawn pledge can be renewed.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_help_pledges_post_user_active
+    ON help_pledges(post_id, user_id) WHERE status <> 'WITHDRAWN';
+
+CREATE INDEX IF NOT EXISTS idx_help_pledges_post
+    ON help_pledges(post_id, created_at);
+
+-- Request-level fulfillment, set by the post owner.
+CREATE TABLE IF NOT EXISTS help_fulfillments (
+    post_id UUID PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE,
+    fulfilled_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO daily_post_limits (post_type, user_limit, business_multiplier, description)
+VALUES ('HELP', 3, 2.0, 'Help requests (items or time)')
+ON CONFLICT (post_type) DO NOTHING;
@@ -0,0 +1,13 @@
+DROP TRIGGER IF EXISTS trg_groups_normalize_location ON groups;
+DROP TRIGGER IF EXISTS trg_posts_normalize_location ON posts;
+DROP TRIGGER IF EXISTS trg_business_profiles_normalize_location ON business_profiles;
+DROP TRIGGER IF EXISTS trg_profiles_normalize_location ON profiles;
+DROP FUNCTION IF EXISTS normalize_location_fields();
+
+ALTER TABLE groups DROP COLUMN IF EXISTS district_id, DROP COLUMN IF EXISTS province_id;
+ALTER TABLE posts DROP COLUMN IF EXISTS district_id, DROP COLUMN IF EXISTS province_id;
+ALTER TABLE business_profiles DROP COLUMN IF EXISTS district_id, DROP COLUMN IF EXISTS province_id;
+ALTER TABLE profiles DROP COLUMN IF EXISTS district_id, DROP COLUMN IF EXISTS province_id;
+
+DROP FUNCTION IF EXISTS resolve_location_id(TEXT, UUID, TEXT);
+DROP TABLE IF EXISTS locations;
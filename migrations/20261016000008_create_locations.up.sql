@@ -0,0 +1,153 @@
+-- Reference data for provinces, districts and neighborhoods, edited by admins.
+-- Profiles, businesses, posts and groups keep their free-text province /
+-- district columns for display, but a trigger now resolves them against this
+-- table (English, Dari or Pashto spelling, case-insensitive), rewrites them to
+-- the canonical English name and stores the matching ids. Filters compare ids,
+-- so "kabul", "Kabul " and "کابل" all land in the same bucket.
+CREATE TABLE IF NOT EXISTS locations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    parent_id UUID REFERENCES locations(id) ON DELETE CASCADE,
+    level VARCHAR(20) NOT NULL CHECK (level IN ('PROVINCE', 'DISTRICT', 'NEIGHBORHOOD')),
+    name VARCHAR(100) NOT NULL,
+    name_dari VARCHAR(100),
+    name_pashto VARCHAR(100),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK ((level = 'PROVINCE') = (parent_id IS NULL))
+);
+
+-- English names are unique among siblings.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_locations_sibling_name
+    ON locations (COALESCE(parent_id, '00000000-0000-0000-0000-000000000000'::uuid), level, LOWER(name));
+CREATE INDEX IF NOT EXISTS idx_locations_parent
+    ON locations (parent_id, position, name);
+
+INSERT INTO locations (level, name, name_dari, name_pashto, position) VALUES
+    ('PROVINCE', 'Badakhshan', 'بدخشان', 'بدخشان', 0),
+    ('PROVINCE', 'Badghis', 'بادغیس', 'بادغيس', 0),
+    ('PROVINCE', 'Baghlan', 'بغلان', 'بغلان', 0),
+    ('PROVINCE', 'Balkh', 'بلخ', 'بلخ', 0),
+    ('PROVINCE', 'Bamyan', 'بامیان', 'باميان', 0),
+    ('PROVINCE', 'Daykundi', 'دایکندی', 'دايکندي', 0),
+    ('PROVINCE', 'Farah', 'فراه', 'فراه', 0),
+    ('PROVINCE', 'Faryab', 'فاریاب', 'فارياب', 0),
+    ('PROVINCE', 'Ghazni', 'غزنی', 'غزني', 0),
+    ('PROVINCE', 'Ghor', 'غور', 'غور', 0),
+    ('PROVINCE', 'Helmand', 'هلمند', 'هلمند', 0),
+    ('PROVINCE', 'Herat', 'هرات', 'هرات', 0),
+    ('PROVINCE', 'Jawzjan', 'جوزجان', 'جوزجان', 0),
+    ('PROVINCE', 'Kabul', 'کابل', 'کابل', 0),
+    ('PROVINCE', 'Kandahar', 'قندهار', 'کندهار', 0),
+    ('PROVINCE', 'Kapisa', 'کاپیسا', 'کاپيسا', 0),
+    ('PROVINCE', 'Khost', 'خوست', 'خوست', 0),
+    ('PROVINCE', 'Kunar', 'کنر', 'کونړ', 0),
+    ('PROVINCE', 'Kunduz', 'کندز', 'کندز', 0),
+    ('PROVINCE', 'Laghman', 'لغمان', 'لغمان', 0),
+    ('PROVINCE', 'Logar', 'لوگر', 'لوګر', 0),
+    ('PROVINCE', 'Nangarhar', 'ننگرهار', 'ننګرهار', 0),
+    ('PROVINCE', 'Nimruz', 'نیمروز', 'نيمروز', 0),
+    ('PROVINCE', 'Nuristan', 'نورستان', 'نورستان', 0),
+    ('PROVINCE', 'Paktia', 'پکتیا', 'پکتيا', 0),
+    ('PROVINCE', 'Paktika', 'پکتیکا', 'پکتيکا', 0),
+    ('PROVINCE', 'Panjshir', 'پنجشیر', 'پنجشېر', 0),
+    ('PROVINCE', 'Parwan', 'پروان', 'پروان', 0),
+    ('PROVINCE', 'Samangan', 'سمنگان', 'سمنګان', 0),
+    ('PROVINCE', 'Sar-e Pol', 'سرپل', 'سرپل', 0),
+    ('PROVINCE', 'Takhar', 'تخار', 'تخار', 0),
+    ('PROVINCE', 'Uruzgan', 'ارزگان', 'ارزګان', 0),
+    ('PROVINCE', 'Wardak', 'وردک', 'وردګ', 0),
+    ('PROVINCE', 'Zabul', 'زابل', 'زابل', 0)
+ON CONFLICT DO NOTHING;
+
+-- resolve_location_id matches free text against a location's names under
+-- the given parent (NULL for provinces). Returns NULL when nothing matches.
+CREATE OR REPLACE FUNCTION resolve_location_id(p_level TEXT, p_parent UUID, p_raw TEXT)
+RETURNS UUID AS $$
+    SELECT id FROM locations
+    WHERE level = p_level
+      AND parent_id IS NOT DISTINCT FROM p_parent
+      AND LOWER(BTRIM(p_raw)) IN (LOWER(name), LOWER(COALESCE(name_dari, '')), LOWER(COALESCE(name_pashto, '')))
+    ORDER BY is_active DESC
+    LIMIT 1
+$$ LANGUAGE sql STABLE;
+
+ALTER TABLE profiles
+    ADD COLUMN IF NOT EXISTS province_id UUID REFERENCES locations(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS district_id UUID REFERENCES locations(id) ON DELETE SET NULL;
+ALTER TABLE business_profiles
+    ADD COLUMN IF NOT EXISTS province_id UUID REFERENCES locations(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS district_id UUID REFERENCES locations(id) ON DELETE SET NULL;
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS province_id UUID REFERENCES locations(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS district_id UUID REFERENCES locations(id) ON DELETE SET NULL;
+ALTER TABLE groups
+    ADD COLUMN IF NOT EXISTS province_id UUID REFERENCES locations(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS district_id UUID REFERENCES locations(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_profiles_province_id ON profiles(province_id);
+CREATE INDEX IF NOT EXISTS idx_business_profiles_province_id ON business_profiles(province_id);
+CREATE INDEX IF NOT EXISTS idx_posts_province_id ON posts(province_id);
+CREATE INDEX IF NOT EXISTS idx_groups_province_id ON groups(province_id);
+
+-- Trigger function: resolve province/district text to ids and canonical
+-- names. Unknown values are kept as typed (ids stay NULL) so older clients
+-- keep working; blank strings become NULL.
+CREATE OR REPLACE FUNCTION normalize_location_fields()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.province := NULLIF(BTRIM(NEW.province), '');
+    NEW.district := NULLIF(BTRIM(NEW.district), '');
+
+    NEW.province_id := resolve_location_id('PROVINCE', NULL, NEW.province);
+    IF NEW.province_id IS NOT NULL THEN
+        SELECT name INTO NEW.province FROM locations WHERE id = NEW.province_id;
+    END IF;
+
+    NEW.district_id := NULL;
+    IF NEW.province_id IS NOT NULL AND NEW.district IS NOT NULL THEN
+        NEW.district_id := resolve_location_id('DISTRICT', NEW.province_id, NEW.district);
+        IF NEW.district_id IS NOT NULL THEN
+            SELECT name INTO NEW.district FROM locations WHERE id = NEW.district_id;
+        END IF;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_profiles_normalize_location ON profiles;
+CREATE TRIGGER trg_profiles_normalize_location
+BEFORE INSERT OR UPDATE OF province, district ON profiles
+FOR EACH ROW EXECUTE FUNCTION normalize_location_fields();
+
+DROP TRIGGER IF EXISTS trg_business_profiles_normalize_location ON business_profiles;
+CREATE TRIGGER trg_business_profiles_normalize_location
+BEFORE INSERT OR UPDATE OF province, district ON business_profiles
+FOR EACH ROW EXECUTE FUNCTION normalize_location_fields();
+
+DROP TRIGGER IF EXISTS trg_posts_normalize_location ON posts;
+CREATE TRIGGER trg_posts_normalize_location
+BEFORE INSERT OR UPDATE OF province, district ON posts
+FOR EACH ROW EXECUTE FUNCTION normalize_location_fields();
+
+DROP TRIGGER IF EXISTS trg_groups_normalize_location ON groups;
+CREATE TRIGGER trg_groups_normalize_location
+BEFORE INSERT OR UPDATE OF province, district ON groups
+FOR EACH ROW EXECUTE FUNCTION normalize_location_fields();
+
+-- Data cleanup: run the normalization over existing rows without bumping
+-- their updated_at.
+ALTER TABLE profiles DISABLE TRIGGER trg_profiles_updated_at;
+ALTER TABLE business_profiles DISABLE TRIGGER trg_business_profiles_updated_at;
+ALTER TABLE posts DISABLE TRIGGER trg_posts_updated_at;
+
+UPDATE profiles SET province = province WHERE province IS NOT NULL;
+UPDATE business_profiles SET province = province WHERE province IS NOT NULL;
+UPDATE posts SET province = province WHERE province IS NOT NULL;
+UPDATE groups SET province = province WHERE province IS NOT NULL;
+
+ALTER TABLE profiles ENABLE TRIGGER trg_profiles_updated_at;
+ALTER TABLE business_profiles ENABLE TRIGGER trg_business_profiles_updated_at;
+ALTER TABLE posts ENABLE TRIGGER trg_posts_updated_at;
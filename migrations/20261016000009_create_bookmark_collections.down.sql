@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_post_bookmarks_collection;
+ALTER TABLE post_bookmarks DROP COLUMN IF EXISTS collection_id;
+
+DROP TABLE IF EXISTS bookmark_collections;
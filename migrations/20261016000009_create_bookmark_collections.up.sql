@@ -0,0 +1,19 @@
+-- Named bookmark folders ("Furniture ideas", "Events to attend"). A bookmark
+-- sits in at most one collection; deleting a collection keeps its bookmarks
+-- and just unfiles them.
+CREATE TABLE IF NOT EXISTS bookmark_collections (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(60) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bookmark_collections_user_name
+    ON bookmark_collections(user_id, LOWER(name));
+
+ALTER TABLE post_bookmarks
+    ADD COLUMN IF NOT EXISTS collection_id UUID REFERENCES bookmark_collections(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_post_bookmarks_collection
+    ON post_bookmarks(collection_id, created_at DESC) WHERE collection_id IS NOT NULL;
@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS post_share_links;
+
+ALTER TABLE posts DROP COLUMN IF EXISTS external_shares;
+
+DELETE FROM messages WHERE message_type = 'POST';
+ALTER TABLE messages DROP CONSTRAINT IF EXISTS messages_message_type_check;
+ALTER TABLE messages
+    ADD CONSTRAINT messages_message_type_check
+    CHECK (message_type IN ('TEXT', 'IMAGE', 'FILE', 'LOCATION', 'VOICE'));
+
+ALTER TABLE messages DROP COLUMN IF EXISTS shared_post_id;
@@ -0,0 +1,29 @@
+-- Share targets beyond reposting: send a post into chats as a rich POST
+-- message, or share it outside the app through a tracked short link.
+ALTER TABLE messages
+    ADD COLUMN IF NOT EXISTS shared_post_id UUID NULL REFERENCES posts(id) ON DELETE SET NULL;
+
+ALTER TABLE messages DROP CONSTRAINT IF EXISTS messages_message_type_check;
+ALTER TABLE messages
+    ADD CONSTRAINT messages_message_type_check
+    CHECK (message_type IN ('TEXT', 'IMAGE', 'FILE', 'LOCATION', 'VOICE', 'POST'));
+
+-- External shares are counted apart from total_shares (reposts) so
+-- analytics can tell in-app reach from off-app reach.
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS external_shares INTEGER NOT NULL DEFAULT 0;
+
+-- One short link per (post, sharer, channel); repeat shares reuse it.
+CREATE TABLE IF NOT EXISTS post_share_links (
+    code VARCHAR(16) PRIMARY KEY,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    channel VARCHAR(20) NOT NULL,
+    share_count INTEGER NOT NULL DEFAULT 1,
+    click_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_shared_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (post_id, user_id, channel)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_share_links_post ON post_share_links(post_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_business_followers_active_created;
+
+ALTER TABLE business_profiles
+    DROP COLUMN IF EXISTS notify_followers_low_priority;
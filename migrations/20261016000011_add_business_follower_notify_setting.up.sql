@@ -0,0 +1,9 @@
+-- Owner switch for the new-post fan-out to business followers. When FALSE,
+-- routine updates and polls no longer notify followers; events still do.
+ALTER TABLE business_profiles
+    ADD COLUMN IF NOT EXISTS notify_followers_low_priority BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Owner follower list is paged newest first.
+CREATE INDEX IF NOT EXISTS idx_business_followers_active_created
+    ON business_profile_followers (business_id, created_at DESC)
+    WHERE is_active = TRUE;
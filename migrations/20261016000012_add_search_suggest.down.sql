@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS user_recent_searches;
+DROP TABLE IF EXISTS hashtags;
+
+DROP INDEX IF EXISTS idx_business_profiles_name_trgm;
+DROP INDEX IF EXISTS idx_profiles_name_trgm;
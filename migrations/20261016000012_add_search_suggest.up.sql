@@ -0,0 +1,32 @@
+-- Search autocomplete: trigram indexes for prefix/substring matching on
+-- names, a hashtag popularity table and per-user recent searches.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_profiles_name_trgm
+    ON profiles USING GIN (LOWER(CONCAT(first_name, ' ', last_name)) gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS idx_business_profiles_name_trgm
+    ON business_profiles USING GIN (LOWER(name) gin_trgm_ops);
+
+-- Hashtags are counted when a public post is created; post_count ranks
+-- suggestions.
+CREATE TABLE IF NOT EXISTS hashtags (
+    tag          TEXT PRIMARY KEY,
+    post_count   INTEGER NOT NULL DEFAULT 0,
+    last_used_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_hashtags_tag_prefix
+    ON hashtags (tag text_pattern_ops);
+
+-- Queries are stored normalized (trimmed, lower-case); the newest 20 per
+-- user are kept.
+CREATE TABLE IF NOT EXISTS user_recent_searches (
+    user_id     UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    query       TEXT NOT NULL,
+    searched_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, query)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_recent_searches_user_time
+    ON user_recent_searches (user_id, searched_at DESC);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_users_email_trgm;
+DROP INDEX IF EXISTS idx_post_comments_text_trgm;
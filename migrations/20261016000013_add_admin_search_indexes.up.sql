@@ -0,0 +1,9 @@
+-- Admin search matches comment text and account emails by substring;
+-- trigram indexes keep those ILIKE scans off the full tables.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_post_comments_text_trgm
+    ON post_comments USING GIN (LOWER(text) gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS idx_users_email_trgm
+    ON users USING GIN (LOWER(email) gin_trgm_ops);
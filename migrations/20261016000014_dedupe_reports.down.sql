@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS uq_business_reports_reporter_business;
+DROP INDEX IF EXISTS uq_user_reports_reporter_user;
+DROP INDEX IF EXISTS uq_comment_reports_reporter_comment;
+DROP INDEX IF EXISTS uq_post_reports_reporter_post;
@@ -0,0 +1,23 @@
+-- One report per reporter per item. Duplicates filed before the constraint
+-- are folded into the reporter's first report.
+DELETE FROM post_reports r USING post_reports k
+WHERE r.user_id = k.user_id AND r.post_id = k.post_id
+  AND (r.created_at, r.id) > (k.created_at, k.id);
+
+DELETE FROM comment_reports r USING comment_reports k
+WHERE r.user_id = k.user_id AND r.comment_id = k.comment_id
+  AND (r.created_at, r.id) > (k.created_at, k.id);
+
+DELETE FROM user_reports r USING user_reports k
+WHERE r.reported_by_id = k.reported_by_id AND r.reported_user = k.reported_user
+  AND (r.created_at, r.id) > (k.created_at, k.id);
+
+DELETE FROM business_reports r USING business_reports k
+WHERE r.user_id = k.user_id AND r.business_id = k.business_id
+  AND (r.created_at, r.id) > (k.created_at, k.id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_post_reports_reporter_post ON post_reports(user_id, post_id);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_comment_reports_reporter_comment ON comment_reports(user_id, comment_id);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_user_reports_reporter_user ON user_reports(reported_by_id, reported_user);
+CREATE UNIQUE INDEX IF NOT EXISTS uq_business_reports_reporter_business ON business_reports(user_id, business_id);
+
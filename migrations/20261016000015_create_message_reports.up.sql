@@ -0,0 +1,27 @@
+-- Chat message reports. The message text, type and sender are copied onto
+-- the report so deleting or editing the message doesn't destroy the
+-- evidence; message_id and conversation_id are deliberately not foreign
+-- keys for the same reason.
+CREATE TABLE IF NOT EXISTS message_reports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    message_id UUID NOT NULL,
+    conversation_id UUID NOT NULL,
+    sender_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    message_content TEXT,
+    message_type VARCHAR(20) NOT NULL,
+    message_sent_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    reason VARCHAR(100) NOT NULL,
+    additional_comments TEXT,
+    report_status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_message_reports_reporter_message ON message_reports(user_id, message_id);
+CREATE INDEX IF NOT EXISTS idx_message_reports_message ON message_reports(message_id);
+CREATE INDEX IF NOT EXISTS idx_message_reports_status_created ON message_reports(report_status, created_at DESC);
+
+-- Admin search matches the snapshot text.
+CREATE INDEX IF NOT EXISTS idx_message_reports_content_trgm
+    ON message_reports USING GIN (LOWER(message_content) gin_trgm_ops);
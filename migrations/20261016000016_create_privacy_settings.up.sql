@@ -0,0 +1,12 @@
+-- Per-user privacy settings for profile fields. Users without a row get
+-- the defaults: location visible to everyone, email and phone to nobody.
+CREATE TABLE IF NOT EXISTS privacy_settings (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    location_visibility VARCHAR(16) NOT NULL DEFAULT 'EVERYONE'
+        CHECK (location_visibility IN ('EVERYONE', 'FOLLOWERS', 'NOBODY')),
+    email_visibility VARCHAR(16) NOT NULL DEFAULT 'NOBODY'
+        CHECK (email_visibility IN ('EVERYONE', 'FOLLOWERS', 'NOBODY')),
+    phone_visibility VARCHAR(16) NOT NULL DEFAULT 'NOBODY'
+        CHECK (phone_visibility IN ('EVERYONE', 'FOLLOWERS', 'NOBODY')),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS endorsement_reports;
+DROP TABLE IF EXISTS endorsements;
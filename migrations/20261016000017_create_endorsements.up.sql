@@ -0,0 +1,31 @@
+-- Endorsements: short public recommendations one user leaves on another's
+-- profile. They only show once the profile owner approves them. One per
+-- (subject, author); endorsing again replaces the text and asks for
+-- approval again.
+CREATE TABLE IF NOT EXISTS endorsements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    subject_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    author_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    text VARCHAR(500) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'PENDING'
+        CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (subject_id <> author_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_endorsements_subject_author ON endorsements(subject_id, author_id);
+CREATE INDEX IF NOT EXISTS idx_endorsements_subject_status ON endorsements(subject_id, status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_endorsements_author ON endorsements(author_id);
+
+CREATE TABLE IF NOT EXISTS endorsement_reports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    endorsement_id UUID NOT NULL REFERENCES endorsements(id) ON DELETE CASCADE,
+    reason VARCHAR(100) NOT NULL,
+    additional_comments TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_endorsement_reports_reporter ON endorsement_reports(user_id, endorsement_id);
+CREATE INDEX IF NOT EXISTS idx_endorsement_reports_endorsement ON endorsement_reports(endorsement_id);
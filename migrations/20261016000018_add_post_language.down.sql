@@ -0,0 +1,2 @@
+ALTER TABLE profiles DROP COLUMN IF EXISTS content_languages;
+ALTER TABLE posts DROP COLUMN IF EXISTS lang;
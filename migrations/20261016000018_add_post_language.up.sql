@@ -0,0 +1,8 @@
+-- Language of a post's title and description, detected when the post is
+-- created or edited (fa, ps, ar, en). NULL when there was too little text
+-- to tell; language-filtered feeds keep those posts.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS lang VARCHAR(8);
+
+-- Languages the user reads. Applied to their feed and search when the
+-- request doesn't pass ?lang. Empty means every language.
+ALTER TABLE profiles ADD COLUMN IF NOT EXISTS content_languages TEXT[] NOT NULL DEFAULT '{}';
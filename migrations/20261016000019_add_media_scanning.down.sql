@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS business_media_moderation_queue;
+
+DROP INDEX IF EXISTS idx_media_moderation_unscanned;
+ALTER TABLE media_moderation_queue
+    DROP COLUMN IF EXISTS blurred_url,
+    DROP COLUMN IF EXISTS scan_attempts,
+    DROP COLUMN IF EXISTS scanned_at,
+    DROP COLUMN IF EXISTS flagged;
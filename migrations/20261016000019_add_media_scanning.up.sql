@@ -0,0 +1,39 @@
+-- Automatic image scanning for the media moderation queue. A background
+-- worker sends every pending image to the NSFW classifier and writes its
+-- output to auto_labels. Unsafe images are flagged so admins review them
+-- first; blurred_url is a blurred copy served until they do.
+ALTER TABLE media_moderation_queue
+    ADD COLUMN IF NOT EXISTS flagged BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS scanned_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS scan_attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS blurred_url TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_media_moderation_unscanned
+    ON media_moderation_queue(enqueued_at) WHERE scanned_at IS NULL;
+
+-- The same queue for business avatars, covers and gallery images, which
+-- aren't post attachments. Rejecting a row takes the image off the
+-- business.
+CREATE TABLE IF NOT EXISTS business_media_moderation_queue (
+    id             UUID         PRIMARY KEY DEFAULT uuid_generate_v4(),
+    business_id    UUID         NOT NULL REFERENCES business_profiles(id) ON DELETE CASCADE,
+    url            TEXT         NOT NULL,
+    enqueued_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    status         VARCHAR(15)  NOT NULL DEFAULT 'pending',
+    reviewed_at    TIMESTAMPTZ,
+    reviewed_by    UUID         REFERENCES users(id) ON DELETE SET NULL,
+    review_notes   TEXT,
+    auto_labels    JSONB,
+    flagged        BOOLEAN      NOT NULL DEFAULT FALSE,
+    scanned_at     TIMESTAMPTZ,
+    scan_attempts  INTEGER      NOT NULL DEFAULT 0,
+    blurred_url    TEXT,
+    CONSTRAINT business_media_moderation_status_chk CHECK (status IN ('pending','approved','rejected'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_business_media_moderation_url
+    ON business_media_moderation_queue(url);
+CREATE INDEX IF NOT EXISTS idx_business_media_moderation_status_enqueued
+    ON business_media_moderation_queue(status, enqueued_at DESC);
+CREATE INDEX IF NOT EXISTS idx_business_media_moderation_unscanned
+    ON business_media_moderation_queue(enqueued_at) WHERE scanned_at IS NULL;
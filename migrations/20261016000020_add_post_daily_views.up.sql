@@ -0,0 +1,17 @@
+-- Views per post per day. A user counts once per post per day (deduplicated
+-- in Redis before the increment); post_views keeps the unique viewers.
+CREATE TABLE IF NOT EXISTS post_daily_views (
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    views INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (post_id, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_daily_views_day ON post_daily_views(day);
+
+-- Seed with the unique views recorded so far, on the day they happened.
+INSERT INTO post_daily_views (post_id, day, views)
+SELECT post_id, created_at::date, COUNT(*)
+FROM post_views
+GROUP BY post_id, created_at::date
+ON CONFLICT (post_id, day) DO NOTHING;
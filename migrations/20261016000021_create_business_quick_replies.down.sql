@@ -0,0 +1,3 @@
+ALTER TABLE conversations DROP COLUMN IF EXISTS auto_replied_at;
+DROP TABLE IF EXISTS business_auto_replies;
+DROP TABLE IF EXISTS business_quick_replies;
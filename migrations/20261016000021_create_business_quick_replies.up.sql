@@ -0,0 +1,26 @@
+-- Saved replies a business owner picks from in the chat composer.
+CREATE TABLE IF NOT EXISTS business_quick_replies (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    business_id UUID NOT NULL REFERENCES business_profiles(id) ON DELETE CASCADE,
+    title VARCHAR(60) NOT NULL,
+    body TEXT NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_business_quick_replies_business
+    ON business_quick_replies(business_id, position, created_at);
+
+-- Away message sent to customers who write while the business is closed
+-- (per its business hours).
+CREATE TABLE IF NOT EXISTS business_auto_replies (
+    business_id UUID PRIMARY KEY REFERENCES business_profiles(id) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    message TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- When the away message was last sent in a conversation, so a customer
+-- writing several messages gets it once, not after each one.
+ALTER TABLE conversations ADD COLUMN IF NOT EXISTS auto_replied_at TIMESTAMP WITH TIME ZONE;
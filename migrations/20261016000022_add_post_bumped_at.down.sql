@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_posts_bumped_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS bumped_at;
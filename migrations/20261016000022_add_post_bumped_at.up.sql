@@ -0,0 +1,9 @@
+-- bumped_at orders the recent feed. It starts equal to created_at and moves
+-- forward when a seller renews a SELL listing (POST /posts/:id/renew), which
+-- is allowed once per cooldown per post.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS bumped_at TIMESTAMPTZ;
+UPDATE posts SET bumped_at = created_at WHERE bumped_at IS NULL;
+ALTER TABLE posts ALTER COLUMN bumped_at SET DEFAULT NOW();
+ALTER TABLE posts ALTER COLUMN bumped_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_posts_bumped_at ON posts(bumped_at DESC) WHERE deleted_at IS NULL;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS business_featured_posts;
@@ -0,0 +1,11 @@
+-- Posts a business pins to the top of its profile, in the owner's order
+-- (position ascending). Capped per business in the service.
+CREATE TABLE IF NOT EXISTS business_featured_posts (
+    business_id UUID NOT NULL REFERENCES business_profiles(id) ON DELETE CASCADE,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (business_id, post_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_business_featured_posts_order ON business_featured_posts(business_id, position);
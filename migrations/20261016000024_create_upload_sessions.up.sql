@@ -0,0 +1,24 @@
+-- Upload sessions group the images a client uploads ahead of creating a
+-- post. CreatePost claims the session; uploads never claimed are deleted
+-- from storage by the cleanup job after 24 hours.
+CREATE TABLE IF NOT EXISTS upload_sessions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id UUID REFERENCES posts(id) ON DELETE SET NULL,
+    claimed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_upload_sessions_user ON upload_sessions(user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS upload_session_files (
+    id UUID PRIMARY KEY,
+    session_id UUID NOT NULL REFERENCES upload_sessions(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    photo JSONB NOT NULL,
+    claimed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_upload_session_files_session ON upload_session_files(session_id);
+CREATE INDEX IF NOT EXISTS idx_upload_session_files_unclaimed ON upload_session_files(created_at) WHERE claimed_at IS NULL;
@@ -0,0 +1,4 @@
+-- Row versions for optimistic locking: an edit names the version it was
+-- based on and is refused (409) when another device saved first.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE profiles ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,4 @@
+-- Logout-all cutoff: access tokens issued at or before this instant are
+-- rejected. Durable copy of the Redis key the auth middleware checks, so a
+-- Redis restart doesn't bring logged-out tokens back.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tokens_invalid_before TIMESTAMP WITH TIME ZONE;
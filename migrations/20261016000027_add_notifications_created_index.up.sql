@@ -0,0 +1,4 @@
+-- Notification retention job: deletes rows older than the retention window
+-- across all users, so it needs created_at on its own.
+CREATE INDEX IF NOT EXISTS idx_notifications_created
+    ON notifications(created_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_posts_business_location;
+ALTER TABLE posts DROP COLUMN IF EXISTS business_location_id;
+DROP TABLE IF EXISTS business_locations;
@@ -0,0 +1,37 @@
+-- Business branches: extra physical locations of a business beyond the
+-- address on business_profiles, each with its own map point, phone and
+-- opening hours. Search and discover match a business by whichever of its
+-- addresses is nearest.
+CREATE TABLE IF NOT EXISTS business_locations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    business_id UUID NOT NULL REFERENCES business_profiles(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    address VARCHAR(500),
+    location GEOGRAPHY(POINT, 4326),
+    country VARCHAR(100),
+    province VARCHAR(100),
+    district VARCHAR(100),
+    neighborhood VARCHAR(100),
+    phone_number VARCHAR(20),
+    -- [{day, open_time "HH:MM", close_time, is_closed}], same shape as the
+    -- business hours response.
+    hours JSONB NOT NULL DEFAULT '[]'::jsonb,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_business_locations_business
+    ON business_locations(business_id, position, created_at);
+
+CREATE INDEX IF NOT EXISTS idx_business_locations_location
+    ON business_locations USING GIST(location)
+    WHERE location IS NOT NULL;
+
+-- A business post can name the branch it is about. SET NULL keeps the post
+-- when the branch is removed.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS business_location_id UUID
+    REFERENCES business_locations(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_posts_business_location
+    ON posts(business_location_id) WHERE business_location_id IS NOT NULL;
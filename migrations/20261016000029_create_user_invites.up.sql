@@ -0,0 +1,19 @@
+-- Invites: every user gets one shareable invite code, and a signup made
+-- with a code records who referred the new user. Referral counts feed the
+-- profile and the admin top-referrers report.
+CREATE TABLE IF NOT EXISTS user_invite_codes (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    code VARCHAR(16) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- One row per referred user: a user is attributed to at most one referrer.
+CREATE TABLE IF NOT EXISTS referrals (
+    referred_user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    referrer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_referrals_referrer
+    ON referrals(referrer_id, created_at DESC);
@@ -0,0 +1,10 @@
+DELETE FROM messages WHERE message_type = 'STICKER';
+ALTER TABLE messages DROP CONSTRAINT IF EXISTS messages_message_type_check;
+ALTER TABLE messages
+    ADD CONSTRAINT messages_message_type_check
+    CHECK (message_type IN ('TEXT', 'IMAGE', 'FILE', 'LOCATION', 'VOICE', 'POST'));
+
+ALTER TABLE messages DROP COLUMN IF EXISTS sticker_id;
+
+DROP TABLE IF EXISTS stickers;
+DROP TABLE IF EXISTS sticker_packs;
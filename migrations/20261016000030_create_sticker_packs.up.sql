@@ -0,0 +1,31 @@
+-- Stickers: admin-curated packs the app downloads as a catalog, sent in
+-- chat as STICKER messages that reference a sticker by id.
+CREATE TABLE IF NOT EXISTS sticker_packs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(100) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS stickers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    pack_id UUID NOT NULL REFERENCES sticker_packs(id) ON DELETE CASCADE,
+    image_url TEXT NOT NULL,
+    -- Emoji the sticker stands for; shown as the push/preview text and used
+    -- by clients to suggest stickers while typing.
+    emoji VARCHAR(16),
+    position INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_stickers_pack ON stickers(pack_id, position);
+
+-- SET NULL keeps sent messages when a pack is removed; clients show a
+-- placeholder for a sticker that no longer exists.
+ALTER TABLE messages
+    ADD COLUMN IF NOT EXISTS sticker_id UUID NULL REFERENCES stickers(id) ON DELETE SET NULL;
+
+ALTER TABLE messages DROP CONSTRAINT IF EXISTS messages_message_type_check;
+ALTER TABLE messages
+    ADD CONSTRAINT messages_message_type_check
+    CHECK (message_type IN ('TEXT', 'IMAGE', 'FILE', 'LOCATION', 'VOICE', 'POST', 'STICKER'));
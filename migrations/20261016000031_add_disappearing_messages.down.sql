@@ -0,0 +1,9 @@
+DELETE FROM messages WHERE message_type = 'SYSTEM';
+ALTER TABLE messages DROP CONSTRAINT IF EXISTS messages_message_type_check;
+ALTER TABLE messages
+    ADD CONSTRAINT messages_message_type_check
+    CHECK (message_type IN ('TEXT', 'IMAGE', 'FILE', 'LOCATION', 'VOICE', 'POST', 'STICKER'));
+
+DROP INDEX IF EXISTS idx_messages_expires_at;
+ALTER TABLE messages DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE conversations DROP COLUMN IF EXISTS disappearing_ttl_seconds;
@@ -0,0 +1,19 @@
+-- Disappearing messages: a conversation-level TTL stamps expires_at on new
+-- messages, and a background job hard-deletes them once past it.
+ALTER TABLE conversations
+    ADD COLUMN IF NOT EXISTS disappearing_ttl_seconds INTEGER NULL
+        CHECK (disappearing_ttl_seconds IN (86400, 604800));
+
+ALTER TABLE messages
+    ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE NULL;
+
+CREATE INDEX IF NOT EXISTS idx_messages_expires_at
+    ON messages(expires_at)
+    WHERE expires_at IS NOT NULL;
+
+-- SYSTEM messages record conversation events such as a change to the
+-- disappearing-messages setting.
+ALTER TABLE messages DROP CONSTRAINT IF EXISTS messages_message_type_check;
+ALTER TABLE messages
+    ADD CONSTRAINT messages_message_type_check
+    CHECK (message_type IN ('TEXT', 'IMAGE', 'FILE', 'LOCATION', 'VOICE', 'POST', 'STICKER', 'SYSTEM'));
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_attachments_post_position;
+ALTER TABLE attachments
+    DROP COLUMN IF EXISTS caption,
+    DROP COLUMN IF EXISTS position;
@@ -0,0 +1,19 @@
+-- Post attachments get an explicit order and an optional caption. Until
+-- now order was implied by created_at (staggered per index on create);
+-- backfill position from it so existing posts keep their cover.
+ALTER TABLE attachments
+    ADD COLUMN IF NOT EXISTS position INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS caption VARCHAR(500) NULL;
+
+UPDATE attachments a
+SET position = ordered.rn - 1
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY post_id ORDER BY created_at ASC, id ASC) AS rn
+    FROM attachments
+    WHERE deleted_at IS NULL
+) ordered
+WHERE a.id = ordered.id;
+
+CREATE INDEX IF NOT EXISTS idx_attachments_post_position
+    ON attachments(post_id, position)
+    WHERE deleted_at IS NULL;
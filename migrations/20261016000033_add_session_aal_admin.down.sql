@@ -0,0 +1,3 @@
+ALTER TABLE user_sessions
+    DROP COLUMN IF EXISTS is_admin,
+    DROP COLUMN IF EXISTS aal;
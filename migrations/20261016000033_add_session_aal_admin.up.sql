@@ -0,0 +1,7 @@
+-- Sessions remember the assurance level they were opened at, so a refresh
+-- keeps an MFA-verified session at AAL2, and whether they belong to the
+-- admin dashboard, whose sessions refresh under their own rules (short
+-- access tokens, no extension past the original expiry).
+ALTER TABLE user_sessions
+    ADD COLUMN IF NOT EXISTS aal SMALLINT NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS is_admin BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,16 @@
+DROP TRIGGER IF EXISTS trg_conversations_tombstone ON conversations;
+DROP TRIGGER IF EXISTS trg_notifications_tombstone ON notifications;
+DROP FUNCTION IF EXISTS record_conversation_tombstone();
+DROP FUNCTION IF EXISTS record_notification_tombstone();
+DROP TABLE IF EXISTS sync_tombstones;
+
+DROP INDEX IF EXISTS idx_posts_updated_at;
+
+DROP INDEX IF EXISTS idx_conversations_participant2_updated_at;
+DROP INDEX IF EXISTS idx_conversations_participant1_updated_at;
+DROP TRIGGER IF EXISTS trg_conversations_updated_at ON conversations;
+ALTER TABLE conversations DROP COLUMN IF EXISTS updated_at;
+
+DROP INDEX IF EXISTS idx_notifications_user_updated_at;
+DROP TRIGGER IF EXISTS trg_notifications_updated_at ON notifications;
+ALTER TABLE notifications DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,70 @@
+-- Delta sync: list endpoints accept ?since and return rows changed after it
+-- plus the IDs removed since. Rows need an updated_at that moves on every
+-- change, and hard deletes leave a tombstone behind.
+ALTER TABLE notifications
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+UPDATE notifications SET updated_at = created_at;
+
+CREATE TRIGGER trg_notifications_updated_at BEFORE UPDATE ON notifications
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_updated_at
+    ON notifications(user_id, updated_at);
+
+ALTER TABLE conversations
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+UPDATE conversations SET updated_at = COALESCE(last_message_at, created_at);
+
+-- A new message bumps last_message_at, which moves updated_at too.
+CREATE TRIGGER trg_conversations_updated_at BEFORE UPDATE ON conversations
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE INDEX IF NOT EXISTS idx_conversations_participant1_updated_at
+    ON conversations(participant1_id, updated_at);
+CREATE INDEX IF NOT EXISTS idx_conversations_participant2_updated_at
+    ON conversations(participant2_id, updated_at);
+
+-- Posts are soft-deleted, so their removals are read from posts itself.
+CREATE INDEX IF NOT EXISTS idx_posts_updated_at ON posts(updated_at);
+
+-- One row per (owner, removed entity). Kept for the sync window, then
+-- purged by a background job; a client further behind starts over.
+CREATE TABLE IF NOT EXISTS sync_tombstones (
+    id BIGSERIAL PRIMARY KEY,
+    entity_type VARCHAR(20) NOT NULL CHECK (entity_type IN ('notification', 'conversation')),
+    entity_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    deleted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_sync_tombstones_user_type_deleted_at
+    ON sync_tombstones(user_id, entity_type, deleted_at);
+CREATE INDEX IF NOT EXISTS idx_sync_tombstones_deleted_at
+    ON sync_tombstones(deleted_at);
+
+CREATE OR REPLACE FUNCTION record_notification_tombstone()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO sync_tombstones (entity_type, entity_id, user_id)
+    VALUES ('notification', OLD.id, OLD.user_id);
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_notifications_tombstone AFTER DELETE ON notifications
+    FOR EACH ROW EXECUTE FUNCTION record_notification_tombstone();
+
+CREATE OR REPLACE FUNCTION record_conversation_tombstone()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO sync_tombstones (entity_type, entity_id, user_id)
+    VALUES ('conversation', OLD.id, OLD.participant1_id),
+           ('conversation', OLD.id, OLD.participant2_id);
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_conversations_tombstone AFTER DELETE ON conversations
+    FOR EACH ROW EXECUTE FUNCTION record_conversation_tombstone();
+
+COMMENT ON TABLE sync_tombstones IS 'IDs of hard-deleted rows, per owner, for delta sync';
@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS trg_messages_assign_seq ON messages;
+DROP FUNCTION IF EXISTS assign_message_seq();
+
+DROP INDEX IF EXISTS idx_messages_conversation_seq;
+ALTER TABLE messages DROP COLUMN IF EXISTS seq;
+ALTER TABLE conversations DROP COLUMN IF EXISTS last_seq;
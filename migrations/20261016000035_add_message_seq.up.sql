@@ -0,0 +1,44 @@
+-- Per-conversation message sequence numbers. A client that remembers the
+-- highest seq it has seen asks for ?after_seq=N after a reconnect and gets
+-- every message it missed exactly once, which created_at pagination can't
+-- promise when two messages share a timestamp or arrive mid-scroll.
+ALTER TABLE conversations ADD COLUMN IF NOT EXISTS last_seq BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS seq BIGINT;
+
+-- Number existing messages in the order they were sent.
+UPDATE messages m
+SET seq = numbered.seq
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY conversation_id ORDER BY created_at, id) AS seq
+    FROM messages
+) numbered
+WHERE m.id = numbered.id;
+
+UPDATE conversations c
+SET last_seq = latest.seq
+FROM (SELECT conversation_id, MAX(seq) AS seq FROM messages GROUP BY conversation_id) latest
+WHERE c.id = latest.conversation_id;
+
+ALTER TABLE messages ALTER COLUMN seq SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_messages_conversation_seq ON messages(conversation_id, seq);
+
+-- Bumping the counter locks the conversation row until the inserting
+-- transaction ends, so seqs in a conversation become visible in order and a
+-- reader never sees N+1 before N.
+CREATE OR REPLACE FUNCTION assign_message_seq()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE conversations
+    SET last_seq = last_seq + 1
+    WHERE id = NEW.conversation_id
+    RETURNING last_seq INTO NEW.seq;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_messages_assign_seq BEFORE INSERT ON messages
+    FOR EACH ROW EXECUTE FUNCTION assign_message_seq();
+
+COMMENT ON COLUMN messages.seq IS 'Position in the conversation, gapless at insert time';
+COMMENT ON COLUMN conversations.last_seq IS 'seq of the newest message ever sent in the conversation';
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS trg_post_broadcasts_updated_at ON post_broadcasts;
+DROP TABLE IF EXISTS post_broadcasts;
@@ -0,0 +1,40 @@
+-- Geofenced broadcasts: the author of an EVENT or ALERT post (or an admin)
+-- pushes it to everyone whose saved location is within a chosen radius. A
+-- background worker resolves recipients in id order and records how far it
+-- got, so a large broadcast is sent over several runs and survives restarts.
+CREATE TABLE IF NOT EXISTS post_broadcasts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    requested_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    radius_km DOUBLE PRECISION NOT NULL CHECK (radius_km > 0),
+    -- Users within this distance already got the post's automatic nearby
+    -- push (ALERT urgency radius) and are skipped.
+    exclude_radius_km DOUBLE PRECISION NOT NULL DEFAULT 0,
+    latitude DOUBLE PRECISION NOT NULL,
+    longitude DOUBLE PRECISION NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'QUEUED'
+        CHECK (status IN ('PENDING_APPROVAL', 'QUEUED', 'SENDING', 'SENT', 'REJECTED')),
+    estimated_recipients INTEGER NOT NULL DEFAULT 0,
+    max_recipients INTEGER NOT NULL,
+    sent_count INTEGER NOT NULL DEFAULT 0,
+    -- Last profile id handed to the notifier; the worker resumes after it.
+    cursor_user_id UUID,
+    rejection_reason TEXT,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- One live broadcast per post; a rejected one may be requested again.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_post_broadcasts_post_active
+    ON post_broadcasts(post_id) WHERE status <> 'REJECTED';
+
+CREATE INDEX IF NOT EXISTS idx_post_broadcasts_status
+    ON post_broadcasts(status, created_at);
+
+CREATE TRIGGER trg_post_broadcasts_updated_at BEFORE UPDATE ON post_broadcasts
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE post_broadcasts IS 'Radius pushes of EVENT/ALERT posts, sent in batches by a background worker';
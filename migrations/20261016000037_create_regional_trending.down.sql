@@ -0,0 +1,7 @@
+DELETE FROM notification_settings WHERE category = 'DIGEST';
+ALTER TABLE notification_settings DROP CONSTRAINT IF EXISTS notification_settings_category_check;
+ALTER TABLE notification_settings ADD CONSTRAINT notification_settings_category_check
+    CHECK (category IN ('POSTS', 'MESSAGES', 'EVENTS', 'SALES', 'BUSINESS', 'ACCOUNT'));
+
+DROP TABLE IF EXISTS regional_activity;
+DROP TABLE IF EXISTS regional_trending;
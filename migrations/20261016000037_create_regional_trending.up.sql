@@ -0,0 +1,38 @@
+-- Regional trending: a scheduled rollup ranks the last week's posts, events
+-- and new businesses per province and per district, so GET
+-- /discover/trending is a cheap indexed read. Region names are stored
+-- lower-cased and trimmed; district '' is the province-wide ranking.
+CREATE TABLE IF NOT EXISTS regional_trending (
+    province TEXT NOT NULL,
+    district TEXT NOT NULL DEFAULT '',
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('POST', 'EVENT', 'BUSINESS')),
+    item_id UUID NOT NULL,
+    rank INTEGER NOT NULL,
+    score DOUBLE PRECISION NOT NULL,
+    computed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (province, district, kind, item_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_regional_trending_rank
+    ON regional_trending(province, district, kind, rank);
+
+-- Activity totals per region over the same window, for the trending
+-- response header and the weekly digest copy.
+CREATE TABLE IF NOT EXISTS regional_activity (
+    province TEXT NOT NULL,
+    district TEXT NOT NULL DEFAULT '',
+    new_posts INTEGER NOT NULL DEFAULT 0,
+    upcoming_events INTEGER NOT NULL DEFAULT 0,
+    new_businesses INTEGER NOT NULL DEFAULT 0,
+    computed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (province, district)
+);
+
+-- The weekly digest is opt-in through its own notification category. The
+-- constraint is rebuilt to also admit ACCOUNT, which the API already offers.
+ALTER TABLE notification_settings DROP CONSTRAINT IF EXISTS notification_settings_category_check;
+ALTER TABLE notification_settings ADD CONSTRAINT notification_settings_category_check
+    CHECK (category IN ('POSTS', 'MESSAGES', 'EVENTS', 'SALES', 'BUSINESS', 'ACCOUNT', 'DIGEST'));
+
+COMMENT ON TABLE regional_trending IS 'Weekly top posts, events and new businesses per province/district (rollup)';
+COMMENT ON TABLE regional_activity IS 'Weekly activity totals per province/district (rollup)';
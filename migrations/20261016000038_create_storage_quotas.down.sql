@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trg_storage_usage_updated_at ON storage_usage;
+DROP TABLE IF EXISTS storage_usage;
+DROP TABLE IF EXISTS storage_objects;
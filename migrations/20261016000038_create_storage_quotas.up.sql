@@ -0,0 +1,59 @@
+-- Per-user storage accounting. Every upload the API stores on a user's
+-- behalf gets a storage_objects row; storage_usage keeps the running total
+-- that uploads are checked against, so the quota check is a single-row read.
+CREATE TABLE IF NOT EXISTS storage_objects (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    url TEXT NOT NULL UNIQUE,
+    -- Resized variants stored alongside images; deleted with the object.
+    thumb_url TEXT,
+    medium_url TEXT,
+    mime_type VARCHAR(100),
+    size_bytes BIGINT NOT NULL DEFAULT 0 CHECK (size_bytes >= 0),
+    -- What the upload was for: avatar, cover, post, verification, ad.
+    purpose VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_storage_objects_user
+    ON storage_objects(user_id, created_at);
+
+CREATE TABLE IF NOT EXISTS storage_usage (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    used_bytes BIGINT NOT NULL DEFAULT 0,
+    object_count INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER trg_storage_usage_updated_at BEFORE UPDATE ON storage_usage
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Backfill from the media already referenced by posts, comments and
+-- profiles, using the size recorded in each photo.
+INSERT INTO storage_objects (user_id, url, thumb_url, medium_url, mime_type, size_bytes, purpose, created_at)
+SELECT src.user_id, src.photo->>'url', NULLIF(src.photo->>'thumb_url', ''), NULLIF(src.photo->>'medium_url', ''),
+    LEFT(src.photo->>'mime_type', 100), CASE WHEN jsonb_typeof(src.photo->'size') = 'number'
+        THEN GREATEST((src.photo->>'size')::numeric::bigint, 0) ELSE 0 END, src.purpose, COALESCE(src.created_at, NOW())
+FROM (
+    SELECT p.user_id, a.photo, 'post' AS purpose, a.created_at
+    FROM attachments a JOIN posts p ON p.id = a.post_id
+    WHERE p.user_id IS NOT NULL
+    UNION ALL
+    SELECT c.user_id, ca.photo, 'post', ca.created_at
+    FROM comment_attachments ca JOIN post_comments c ON c.id = ca.comment_id
+    UNION ALL
+    SELECT pr.id, pr.avatar, 'avatar', pr.created_at FROM profiles pr WHERE pr.avatar IS NOT NULL
+    UNION ALL
+    SELECT pr.id, pr.cover, 'cover', pr.created_at FROM profiles pr WHERE pr.cover IS NOT NULL
+) src
+WHERE COALESCE(src.photo->>'url', '') <> ''
+ON CONFLICT (url) DO NOTHING;
+
+INSERT INTO storage_usage (user_id, used_bytes, object_count)
+SELECT user_id, SUM(size_bytes), COUNT(*)
+FROM storage_objects
+GROUP BY user_id
+ON CONFLICT (user_id) DO NOTHING;
+
+COMMENT ON TABLE storage_objects IS 'Uploaded media per user, for storage quotas and the storage management screen';
+COMMENT ON TABLE storage_usage IS 'Running per-user storage totals checked by uploads';
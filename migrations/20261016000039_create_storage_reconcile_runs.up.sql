@@ -0,0 +1,20 @@
+-- One row per run of the orphaned-storage reconciliation job, which lists the
+-- media bucket, deletes objects nothing in the database points at any more,
+-- and records what it found and reclaimed here for the admin dashboard.
+CREATE TABLE IF NOT EXISTS storage_reconcile_runs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    finished_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    -- Dry runs count orphans without deleting them.
+    dry_run BOOLEAN NOT NULL DEFAULT FALSE,
+    scanned_objects BIGINT NOT NULL DEFAULT 0,
+    scanned_bytes BIGINT NOT NULL DEFAULT 0,
+    orphaned_objects BIGINT NOT NULL DEFAULT 0,
+    orphaned_bytes BIGINT NOT NULL DEFAULT 0,
+    deleted_objects BIGINT NOT NULL DEFAULT 0,
+    reclaimed_bytes BIGINT NOT NULL DEFAULT 0,
+    error TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_storage_reconcile_runs_started
+    ON storage_reconcile_runs(started_at DESC);
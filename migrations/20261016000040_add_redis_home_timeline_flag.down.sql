@@ -0,0 +1 @@
+DELETE FROM feature_flags WHERE key = 'redis_home_timeline';
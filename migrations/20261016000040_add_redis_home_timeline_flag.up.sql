@@ -0,0 +1,6 @@
+-- Serve home timelines from Redis sorted sets instead of user_feeds. Meant
+-- for high-traffic deployments; user_feeds is written either way, so the
+-- flag can be flipped at any time.
+INSERT INTO feature_flags (key, enabled, description) VALUES
+    ('redis_home_timeline', FALSE, 'Serve the home timeline from Redis instead of Postgres (user_feeds stays the fallback).')
+ON CONFLICT (key) DO NOTHING;
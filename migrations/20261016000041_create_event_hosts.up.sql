@@ -0,0 +1,28 @@
+-- Event co-hosts: the author of an EVENT post (a user, or the business it was
+-- posted as) can invite other users or businesses to host it with them. An
+-- invite is pending until the invitee accepts or declines; accepted co-hosts
+-- can edit the event and message its attendees.
+CREATE TABLE IF NOT EXISTS event_hosts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    business_id UUID REFERENCES business_profiles(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING'
+        CHECK (status IN ('PENDING', 'ACCEPTED', 'DECLINED')),
+    invited_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    responded_at TIMESTAMP WITH TIME ZONE,
+    -- A co-host is either a user or a business, never both.
+    CONSTRAINT event_hosts_one_host CHECK ((user_id IS NULL) <> (business_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_event_hosts_post_user
+    ON event_hosts(post_id, user_id) WHERE user_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_event_hosts_post_business
+    ON event_hosts(post_id, business_id) WHERE business_id IS NOT NULL;
+
+-- Pending invitations of a user, directly or through a business they own.
+CREATE INDEX IF NOT EXISTS idx_event_hosts_user_pending
+    ON event_hosts(user_id) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS idx_event_hosts_business_pending
+    ON event_hosts(business_id) WHERE status = 'PENDING';
@@ -0,0 +1,10 @@
+-- Secret tokens for the personal events.ics feed. Calendar apps subscribe
+-- to a URL and can't send an Authorization header, so the token in the URL
+-- is the credential; only its SHA-256 is stored. One per user — rotating
+-- replaces it and breaks the old subscription URL.
+CREATE TABLE IF NOT EXISTS calendar_feed_tokens (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMP WITH TIME ZONE
+);
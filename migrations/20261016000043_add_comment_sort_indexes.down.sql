@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_post_comments_top_level_likes;
+DROP INDEX IF EXISTS idx_post_comments_top_level_created;
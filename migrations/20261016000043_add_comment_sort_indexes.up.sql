@@ -0,0 +1,10 @@
+-- Indexes for the top-level comment sort modes of GET /posts/:id/comments.
+-- newest/oldest scan the first one in either direction; most_liked uses the
+-- second. Replies keep using idx_post_comments_parent.
+CREATE INDEX IF NOT EXISTS idx_post_comments_top_level_created
+    ON post_comments(post_id, created_at DESC, id DESC)
+    WHERE parent_comment_id IS NULL AND deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_post_comments_top_level_likes
+    ON post_comments(post_id, total_likes DESC, created_at DESC, id DESC)
+    WHERE parent_comment_id IS NULL AND deleted_at IS NULL;
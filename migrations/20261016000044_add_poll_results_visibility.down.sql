@@ -0,0 +1,3 @@
+ALTER TABLE polls
+    DROP COLUMN IF EXISTS closes_at,
+    DROP COLUMN IF EXISTS results_visibility;
@@ -0,0 +1,6 @@
+-- Poll creators choose when voters see the counts: always, once they have
+-- voted, or once the poll closes at closes_at.
+ALTER TABLE polls
+    ADD COLUMN IF NOT EXISTS results_visibility VARCHAR(20) NOT NULL DEFAULT 'always'
+        CHECK (results_visibility IN ('always', 'after_vote', 'after_close')),
+    ADD COLUMN IF NOT EXISTS closes_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,14 @@
+CREATE OR REPLACE FUNCTION update_poll_vote_count()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE poll_options SET vote_count = vote_count + 1 WHERE id = NEW.poll_option_id;
+    ELSIF TG_OP = 'UPDATE' AND NEW.poll_option_id != OLD.poll_option_id THEN
+        UPDATE poll_options SET vote_count = GREATEST(vote_count - 1, 0) WHERE id = OLD.poll_option_id;
+        UPDATE poll_options SET vote_count = vote_count + 1 WHERE id = NEW.poll_option_id;
+    ELSIF TG_OP = 'DELETE' THEN
+        UPDATE poll_options SET vote_count = GREATEST(vote_count - 1, 0) WHERE id = OLD.poll_option_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,35 @@
+-- Withdrawn votes are soft-deleted (deleted_at) and re-votes revive the row,
+-- but the original trigger only counted hard inserts/deletes and option
+-- changes, so withdrawing never decremented and a re-vote could count twice.
+-- Count a row only while it is live, then rebuild the drifted counters.
+CREATE OR REPLACE FUNCTION update_poll_vote_count()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        IF NEW.deleted_at IS NULL THEN
+            UPDATE poll_options SET vote_count = vote_count + 1 WHERE id = NEW.poll_option_id;
+        END IF;
+    ELSIF TG_OP = 'DELETE' THEN
+        IF OLD.deleted_at IS NULL THEN
+            UPDATE poll_options SET vote_count = GREATEST(vote_count - 1, 0) WHERE id = OLD.poll_option_id;
+        END IF;
+    ELSIF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+        -- Vote withdrawn.
+        UPDATE poll_options SET vote_count = GREATEST(vote_count - 1, 0) WHERE id = OLD.poll_option_id;
+    ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+        -- Withdrawn vote cast again, possibly for another option.
+        UPDATE poll_options SET vote_count = vote_count + 1 WHERE id = NEW.poll_option_id;
+    ELSIF NEW.deleted_at IS NULL AND NEW.poll_option_id != OLD.poll_option_id THEN
+        -- Vote changed.
+        UPDATE poll_options SET vote_count = GREATEST(vote_count - 1, 0) WHERE id = OLD.poll_option_id;
+        UPDATE poll_options SET vote_count = vote_count + 1 WHERE id = NEW.poll_option_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+UPDATE poll_options po
+SET vote_count = (
+    SELECT COUNT(*) FROM user_polls up
+    WHERE up.poll_option_id = po.id AND up.deleted_at IS NULL
+);
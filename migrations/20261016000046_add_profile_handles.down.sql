@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_profiles_handle_prefix;
+DROP INDEX IF EXISTS idx_profiles_handle;
+ALTER TABLE profiles
+    DROP COLUMN IF EXISTS handle_changed_at,
+    DROP COLUMN IF EXISTS handle;
@@ -0,0 +1,14 @@
+-- Unique @handles. Stored lowercase; the index keeps them unique across
+-- deactivated profiles too so a dormant account's handle can't be taken to
+-- impersonate it. handle_changed_at drives the rename cooldown.
+ALTER TABLE profiles
+    ADD COLUMN IF NOT EXISTS handle VARCHAR(30)
+        CHECK (handle ~ '^[a-z][a-z0-9_]{2,29}$'),
+    ADD COLUMN IF NOT EXISTS handle_changed_at TIMESTAMP WITH TIME ZONE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_profiles_handle
+    ON profiles (handle) WHERE handle IS NOT NULL;
+
+-- Handle prefix search for user search and mention autocomplete.
+CREATE INDEX IF NOT EXISTS idx_profiles_handle_prefix
+    ON profiles (handle text_pattern_ops) WHERE handle IS NOT NULL;
@@ -0,0 +1,13 @@
+-- Keywords and hashtags a user muted. Posts whose title or description
+-- contain a muted term (as a whole word) are left out of that user's feeds
+-- and don't notify them.
+CREATE TABLE IF NOT EXISTS muted_terms (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    -- Stored lowercase; a leading # mutes only the hashtag.
+    term VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, term)
+);
+
+COMMENT ON TABLE muted_terms IS 'Per-user muted keywords and hashtags filtered from feeds and notifications';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_business_attachments_business_position;
+ALTER TABLE business_attachments
+    DROP COLUMN IF EXISTS caption,
+    DROP COLUMN IF EXISTS position;
@@ -0,0 +1,19 @@
+-- Business gallery images get an explicit order and an optional caption.
+-- Until now the gallery was listed newest first; backfill position from
+-- that so existing galleries keep the order their owners saw.
+ALTER TABLE business_attachments
+    ADD COLUMN IF NOT EXISTS position INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS caption VARCHAR(300) NULL;
+
+UPDATE business_attachments a
+SET position = ordered.rn - 1
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY business_profile_id ORDER BY created_at DESC, id ASC) AS rn
+    FROM business_attachments
+    WHERE deleted_at IS NULL
+) ordered
+WHERE a.id = ordered.id;
+
+CREATE INDEX IF NOT EXISTS idx_business_attachments_business_position
+    ON business_attachments(business_profile_id, position)
+    WHERE deleted_at IS NULL;
@@ -0,0 +1,32 @@
+-- Held profile media. With MODERATION_HOLD_PROFILE_MEDIA on, a new user
+-- or business avatar/cover is stored here instead of on the profile; the
+-- old image stays live until the new one is approved, by an admin or
+-- automatically when the NSFW scanner scores it safe. Each owner has at
+-- most one pending image per kind: a newer upload supersedes it.
+CREATE TABLE IF NOT EXISTS profile_media_reviews (
+    id             UUID         PRIMARY KEY DEFAULT uuid_generate_v4(),
+    owner_type     VARCHAR(10)  NOT NULL,
+    owner_id       UUID         NOT NULL,
+    kind           VARCHAR(10)  NOT NULL,
+    photo          JSONB        NOT NULL,
+    enqueued_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    status         VARCHAR(15)  NOT NULL DEFAULT 'pending',
+    reviewed_at    TIMESTAMPTZ,
+    reviewed_by    UUID         REFERENCES users(id) ON DELETE SET NULL,
+    review_notes   TEXT,
+    auto_labels    JSONB,
+    flagged        BOOLEAN      NOT NULL DEFAULT FALSE,
+    scanned_at     TIMESTAMPTZ,
+    scan_attempts  INTEGER      NOT NULL DEFAULT 0,
+    blurred_url    TEXT,
+    CONSTRAINT profile_media_reviews_owner_type_chk CHECK (owner_type IN ('user','business')),
+    CONSTRAINT profile_media_reviews_kind_chk CHECK (kind IN ('avatar','cover')),
+    CONSTRAINT profile_media_reviews_status_chk CHECK (status IN ('pending','approved','rejected','superseded'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_profile_media_reviews_pending
+    ON profile_media_reviews(owner_type, owner_id, kind) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_profile_media_reviews_status_enqueued
+    ON profile_media_reviews(status, enqueued_at DESC);
+CREATE INDEX IF NOT EXISTS idx_profile_media_reviews_unscanned
+    ON profile_media_reviews(enqueued_at) WHERE scanned_at IS NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS account_recoveries;
+DROP TABLE IF EXISTS trusted_contacts;
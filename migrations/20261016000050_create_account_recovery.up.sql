@@ -0,0 +1,36 @@
+-- Trusted contact account recovery. A user names another Hamsaya user as
+-- their trusted contact. If they lose access to their email, starting a
+-- recovery sends a one-time code to that contact in the app; the contact
+-- passes it on out-of-band, and support completes the recovery with the
+-- code by moving the account to a new address.
+CREATE TABLE IF NOT EXISTS trusted_contacts (
+    user_id     UUID         PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    contact_id  UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT trusted_contacts_not_self_chk CHECK (user_id <> contact_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_trusted_contacts_contact ON trusted_contacts(contact_id);
+
+CREATE TABLE IF NOT EXISTS account_recoveries (
+    id              UUID         PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id         UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    contact_id      UUID         REFERENCES users(id) ON DELETE SET NULL,
+    code_hash       VARCHAR(64)  NOT NULL,
+    failed_attempts INTEGER      NOT NULL DEFAULT 0,
+    requested_ip    VARCHAR(45),
+    requested_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    expires_at      TIMESTAMPTZ  NOT NULL,
+    status          VARCHAR(15)  NOT NULL DEFAULT 'pending',
+    reviewed_at     TIMESTAMPTZ,
+    reviewed_by     UUID         REFERENCES users(id) ON DELETE SET NULL,
+    review_notes    TEXT,
+    old_email       VARCHAR(255),
+    new_email       VARCHAR(255),
+    CONSTRAINT account_recoveries_status_chk CHECK (status IN ('pending','completed','rejected','cancelled'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_account_recoveries_pending
+    ON account_recoveries(user_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_account_recoveries_status_requested
+    ON account_recoveries(status, requested_at DESC);
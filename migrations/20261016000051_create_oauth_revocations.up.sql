@@ -0,0 +1,10 @@
+-- Provider-side revocations of a user's Sign in with Apple / Google link.
+-- A row marks an OAuth-only account whose sessions were ended because the
+-- provider revoked our access; the user must sign in with the provider
+-- again, which clears the row.
+CREATE TABLE IF NOT EXISTS oauth_revocations (
+    user_id     UUID         PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    provider    VARCHAR(20)  NOT NULL,
+    reason      VARCHAR(100) NOT NULL,
+    revoked_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,5 @@
+-- Live posts with an expiry, for the sell-expiry and post-archive jobs.
+-- Expiry now covers EVENT and ALERT posts as well as SELL listings.
+CREATE INDEX IF NOT EXISTS idx_posts_live_expiry
+    ON posts(expired_at)
+    WHERE status = true AND deleted_at IS NULL AND expired_at IS NOT NULL;
@@ -0,0 +1,11 @@
+-- How quickly a business answers chat, recomputed nightly from message
+-- timestamps so the business page and chat header can tell customers what
+-- to expect. A business without replies in the rollup window has no row.
+CREATE TABLE IF NOT EXISTS business_response_stats (
+    business_id UUID PRIMARY KEY REFERENCES business_profiles(id) ON DELETE CASCADE,
+    median_response_seconds INTEGER NOT NULL,
+    sample_size INTEGER NOT NULL,
+    computed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE business_response_stats IS 'Median chat response time per business over the last 30 days (nightly rollup)';
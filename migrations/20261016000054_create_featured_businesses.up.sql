@@ -0,0 +1,26 @@
+-- Featured businesses: admin-managed placements that promote a business in
+-- one province between starts_at and ends_at, on GET /businesses/featured
+-- and at the top of /discover. Province is stored lower-cased and trimmed.
+-- Impressions and clicks are reported back to the business owner.
+CREATE TABLE IF NOT EXISTS featured_businesses (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    business_id UUID NOT NULL REFERENCES business_profiles(id) ON DELETE CASCADE,
+    province TEXT NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    impressions BIGINT NOT NULL DEFAULT 0,
+    clicks BIGINT NOT NULL DEFAULT 0,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_featured_businesses_live
+    ON featured_businesses(province, ends_at, starts_at);
+
+CREATE INDEX IF NOT EXISTS idx_featured_businesses_business
+    ON featured_businesses(business_id, starts_at DESC);
+
+COMMENT ON TABLE featured_businesses IS 'Date-bounded featured business placements per province, with impression/click counters';
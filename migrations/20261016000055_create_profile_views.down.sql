@@ -0,0 +1,3 @@
+ALTER TABLE privacy_settings DROP COLUMN IF EXISTS profile_view_visibility;
+DROP TABLE IF EXISTS business_profile_viewers;
+DROP TABLE IF EXISTS profile_daily_views;
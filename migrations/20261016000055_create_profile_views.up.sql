@@ -0,0 +1,30 @@
+-- Profile views. A signed-in viewer counts once per profile per day
+-- (deduplicated in Redis before the write).
+
+-- Personal profiles only get an anonymous daily count, shown to the owner.
+CREATE TABLE IF NOT EXISTS profile_daily_views (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    views INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, day)
+);
+
+-- Who viewed a business, one row per viewer per day, for the owner's
+-- viewer list and the unique-viewers series in the insights. Rows older
+-- than the retention window are pruned nightly; the all-time counts live
+-- in business_profile_daily_views.
+CREATE TABLE IF NOT EXISTS business_profile_viewers (
+    business_id UUID NOT NULL REFERENCES business_profiles(id) ON DELETE CASCADE,
+    viewer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    viewed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (business_id, day, viewer_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_business_profile_viewers_day ON business_profile_viewers(day);
+
+-- Whether businesses see the user in their viewer list: EVERYONE, only
+-- businesses the user FOLLOWS, or NOBODY. Views are counted either way.
+ALTER TABLE privacy_settings
+    ADD COLUMN IF NOT EXISTS profile_view_visibility VARCHAR(16) NOT NULL DEFAULT 'FOLLOWERS'
+        CHECK (profile_view_visibility IN ('EVERYONE', 'FOLLOWERS', 'NOBODY'));
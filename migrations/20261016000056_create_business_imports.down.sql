@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS business_owner_invites;
+DROP TABLE IF EXISTS business_import_jobs;
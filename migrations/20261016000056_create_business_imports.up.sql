@@ -0,0 +1,52 @@
+-- Bulk business imports: an admin uploads a directory spreadsheet (CSV) and a
+-- background worker creates its businesses a row at a time, recording how far
+-- it got so a large file is imported over several runs and survives restarts.
+CREATE TABLE IF NOT EXISTS business_import_jobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'QUEUED'
+        CHECK (status IN ('QUEUED', 'RUNNING', 'COMPLETED', 'FAILED')),
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    -- Data rows handled so far; the worker resumes after them.
+    processed_rows INTEGER NOT NULL DEFAULT 0,
+    created_count INTEGER NOT NULL DEFAULT 0,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    invited_count INTEGER NOT NULL DEFAULT 0,
+    -- [{row, field, message}] for the rows that were skipped.
+    row_errors JSONB NOT NULL DEFAULT '[]',
+    -- The uploaded file; cleared once the job finishes.
+    csv_data TEXT NOT NULL DEFAULT '',
+    error TEXT,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_business_import_jobs_status
+    ON business_import_jobs(status, created_at);
+
+CREATE TRIGGER trg_business_import_jobs_updated_at BEFORE UPDATE ON business_import_jobs
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Imported businesses whose owner has no account yet are held by the admin
+-- who imported them; the owner gets an emailed link to claim the business
+-- once they sign up with that address.
+CREATE TABLE IF NOT EXISTS business_owner_invites (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    business_id UUID NOT NULL REFERENCES business_profiles(id) ON DELETE CASCADE,
+    import_job_id UUID REFERENCES business_import_jobs(id) ON DELETE SET NULL,
+    email VARCHAR(255) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    claimed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    claimed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_business_owner_invites_business
+    ON business_owner_invites(business_id);
+
+COMMENT ON TABLE business_import_jobs IS 'Admin CSV imports of businesses, processed in the background';
+COMMENT ON TABLE business_owner_invites IS 'Emailed claim links for imported businesses whose owner had no account';
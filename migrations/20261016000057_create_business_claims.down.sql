@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS business_ownership_history;
+DROP TABLE IF EXISTS business_claim_requests;
@@ -0,0 +1,42 @@
+-- Public business claims: a user asks to take over a business that is held
+-- by staff (imported or admin-created), attaching proof such as a photo of
+-- the license. Admins approve or reject; approval transfers ownership.
+CREATE TABLE IF NOT EXISTS business_claim_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    business_id UUID NOT NULL REFERENCES business_profiles(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    note TEXT,
+    -- Array of Photo objects (same shape as verification documents).
+    proof JSONB NOT NULL DEFAULT '[]'::jsonb,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING'
+        CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED')),
+    rejection_reason TEXT,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Several people may claim the same business, but each only once at a time.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_business_claim_pending
+    ON business_claim_requests (business_id, user_id) WHERE status = 'PENDING';
+
+CREATE INDEX IF NOT EXISTS idx_business_claim_status
+    ON business_claim_requests (status, created_at);
+
+-- Every change of business_profiles.user_id made through a claim, so admins
+-- can see who held a business before.
+CREATE TABLE IF NOT EXISTS business_ownership_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    business_id UUID NOT NULL REFERENCES business_profiles(id) ON DELETE CASCADE,
+    previous_owner_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    new_owner_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    source VARCHAR(20) NOT NULL CHECK (source IN ('CLAIM_REQUEST', 'INVITE')),
+    claim_request_id UUID REFERENCES business_claim_requests(id) ON DELETE SET NULL,
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_business_ownership_history_business
+    ON business_ownership_history (business_id, created_at DESC);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS post_link_previews;
+DROP TABLE IF EXISTS link_previews;
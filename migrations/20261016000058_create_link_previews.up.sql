@@ -0,0 +1,23 @@
+-- Link previews (title, description, image) fetched server-side for URLs in
+-- posts. Keyed by the SHA-256 of the URL so every post linking the same page
+-- shares one fetch. Failed fetches are kept too, so a dead link isn't
+-- retried on every edit until the row goes stale.
+CREATE TABLE IF NOT EXISTS link_previews (
+    url_hash CHAR(64) PRIMARY KEY,
+    url TEXT NOT NULL,
+    title TEXT,
+    description TEXT,
+    image_url TEXT,
+    site_name TEXT,
+    failed BOOLEAN NOT NULL DEFAULT FALSE,
+    fetched_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- The preview shown on a post: the first URL in its title or description.
+CREATE TABLE IF NOT EXISTS post_link_previews (
+    post_id UUID PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE,
+    url_hash CHAR(64) NOT NULL REFERENCES link_previews(url_hash) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_link_previews_url_hash ON post_link_previews (url_hash);
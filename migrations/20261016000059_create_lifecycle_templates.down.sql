@@ -0,0 +1,6 @@
+DELETE FROM notification_settings WHERE category = 'TIPS';
+ALTER TABLE notification_settings DROP CONSTRAINT IF EXISTS notification_settings_category_check;
+ALTER TABLE notification_settings ADD CONSTRAINT notification_settings_category_check
+    CHECK (category IN ('POSTS', 'MESSAGES', 'EVENTS', 'SALES', 'BUSINESS', 'ACCOUNT', 'DIGEST'));
+
+DROP TABLE IF EXISTS lifecycle_templates;
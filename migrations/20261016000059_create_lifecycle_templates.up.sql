@@ -0,0 +1,18 @@
+-- Admin-edited copy for lifecycle messages: the welcome notification, the
+-- first-post nudge and the re-engagement push. A missing row means the
+-- built-in copy is used; disabling a row stops that message for everyone.
+CREATE TABLE IF NOT EXISTS lifecycle_templates (
+    kind VARCHAR(30) PRIMARY KEY CHECK (kind IN ('WELCOME', 'FIRST_POST_NUDGE', 'WINBACK')),
+    title VARCHAR(255) NOT NULL,
+    message TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Lifecycle messages are opt-out through their own notification category.
+ALTER TABLE notification_settings DROP CONSTRAINT IF EXISTS notification_settings_category_check;
+ALTER TABLE notification_settings ADD CONSTRAINT notification_settings_category_check
+    CHECK (category IN ('POSTS', 'MESSAGES', 'EVENTS', 'SALES', 'BUSINESS', 'ACCOUNT', 'DIGEST', 'TIPS'));
+
+COMMENT ON TABLE lifecycle_templates IS 'Admin overrides of welcome / first-post nudge / re-engagement notification copy';
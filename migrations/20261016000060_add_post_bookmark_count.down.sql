@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS trg_post_bookmarks_count ON post_bookmarks;
+DROP FUNCTION IF EXISTS update_post_bookmarks_count();
+
+ALTER TABLE posts DROP COLUMN IF EXISTS bookmark_milestone;
+ALTER TABLE posts DROP COLUMN IF EXISTS total_bookmarks;
@@ -0,0 +1,36 @@
+-- Bookmark counter on posts, kept by trigger like total_likes. Only the
+-- post's owner sees it. bookmark_milestone is the highest threshold (5, 10,
+-- 25) the seller was already told about, so a listing that dips below a
+-- threshold and climbs back doesn't notify twice.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS total_bookmarks INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS bookmark_milestone INTEGER NOT NULL DEFAULT 0;
+
+UPDATE posts p SET total_bookmarks = b.n
+FROM (SELECT post_id, COUNT(*) AS n FROM post_bookmarks GROUP BY post_id) b
+WHERE p.id = b.post_id;
+
+-- Milestones already passed before this migration aren't announced.
+UPDATE posts SET bookmark_milestone = CASE
+    WHEN total_bookmarks >= 25 THEN 25
+    WHEN total_bookmarks >= 10 THEN 10
+    WHEN total_bookmarks >= 5 THEN 5
+    ELSE 0
+END
+WHERE total_bookmarks >= 5;
+
+CREATE OR REPLACE FUNCTION update_post_bookmarks_count()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE posts SET total_bookmarks = total_bookmarks + 1 WHERE id = NEW.post_id;
+    ELSIF TG_OP = 'DELETE' THEN
+        UPDATE posts SET total_bookmarks = GREATEST(total_bookmarks - 1, 0) WHERE id = OLD.post_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_post_bookmarks_count ON post_bookmarks;
+CREATE TRIGGER trg_post_bookmarks_count
+AFTER INSERT OR DELETE ON post_bookmarks
+FOR EACH ROW EXECUTE FUNCTION update_post_bookmarks_count();
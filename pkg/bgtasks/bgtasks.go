@@ -23,9 +23,11 @@ import (
 	"go.uber.org/zap"
 )
 
-// Pool schedules background tasks against a single shared context that is
-// cancelled on Shutdown. All in-flight tasks are awaited up to the shutdown
-// timeout before Shutdown returns.
+// Pool schedules background tasks against a single shared context. Shutdown
+// stops new submissions and lets in-flight tasks finish with that context
+// still live — a notification half-way through its push gets delivered — and
+// cancels it only when the drain timeout runs out. Tasks may submit follow-up
+// tasks through [Pool.SubmitFrom]; those are still accepted while draining.
 type Pool struct {
 	ctx     context.Context
 	cancel  context.CancelFunc
@@ -44,16 +46,35 @@ func New(logger *zap.Logger) *Pool {
 	return &Pool{ctx: ctx, cancel: cancel, logger: logger}
 }
 
+// taskKey marks the context handed to a pool's tasks, so SubmitFrom can tell
+// a nested submission from an outside one.
+type taskKey struct{}
+
 // Submit schedules `task` on a fresh goroutine. After Shutdown is called
 // further submissions are dropped (logged at warn level) so the caller does
 // not need a separate gate.
 func (p *Pool) Submit(task func(ctx context.Context)) {
+	p.submit(false, task)
+}
+
+// SubmitFrom is Submit for callers that may themselves be running as a task
+// of this pool, passing the context they were given. Such nested tasks are
+// accepted until the drain completes: the parent is still holding Shutdown
+// open, and dropping them would lose the work it hands off (a notification's
+// push, say). Any other ctx behaves like Submit.
+func (p *Pool) SubmitFrom(ctx context.Context, task func(ctx context.Context)) {
+	p.submit(ctx != nil && ctx.Value(taskKey{}) == p, task)
+}
+
+func (p *Pool) submit(nested bool, task func(ctx context.Context)) {
 	p.closeMu.Lock()
-	if p.closed {
+	if p.closed && (!nested || p.ctx.Err() != nil) {
 		p.closeMu.Unlock()
 		p.logger.Warn("bgtasks: submit after shutdown — dropped")
 		return
 	}
+	// A nested submitter is an in-flight task, so the counter is above zero
+	// and adding to it can't race Shutdown's Wait.
 	p.wg.Add(1)
 	p.closeMu.Unlock()
 
@@ -64,12 +85,14 @@ func (p *Pool) Submit(task func(ctx context.Context)) {
 				p.logger.Error("bgtasks: task panicked", zap.Any("panic", r))
 			}
 		}()
-		task(p.ctx)
+		task(context.WithValue(p.ctx, taskKey{}, p))
 	}()
 }
 
-// Shutdown cancels the shared context and waits up to `timeout` for all
-// in-flight tasks to return. Returns true on clean drain, false on timeout.
+// Shutdown stops accepting tasks and waits up to `timeout` for in-flight ones
+// to return. The shared context is cancelled afterwards, or as soon as the
+// timeout expires so stragglers abort their I/O. Returns true on clean drain,
+// false on timeout.
 func (p *Pool) Shutdown(timeout time.Duration) bool {
 	p.closeMu.Lock()
 	if p.closed {
@@ -78,8 +101,7 @@ func (p *Pool) Shutdown(timeout time.Duration) bool {
 	}
 	p.closed = true
 	p.closeMu.Unlock()
-
-	p.cancel()
+	defer p.cancel()
 
 	done := make(chan struct{})
 	go func() {
@@ -123,6 +145,16 @@ func Submit(task func(ctx context.Context)) {
 	defaultPool.Submit(task)
 }
 
+// SubmitFrom dispatches `task` against the package-level default pool,
+// accepting it during shutdown when ctx belongs to one of the pool's own
+// tasks. See [Pool.SubmitFrom].
+func SubmitFrom(ctx context.Context, task func(ctx context.Context)) {
+	if defaultPool == nil {
+		return
+	}
+	defaultPool.SubmitFrom(ctx, task)
+}
+
 // Shutdown drains the package-level default pool. No-op when uninitialized.
 func Shutdown(timeout time.Duration) bool {
 	if defaultPool == nil {
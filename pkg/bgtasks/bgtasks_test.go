@@ -0,0 +1,77 @@
+package bgtasks
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShutdown_FlushesInFlightTasks(t *testing.T) {
+	p := New(nil)
+
+	var delivered atomic.Bool
+	started := make(chan struct{})
+	p.Submit(func(ctx context.Context) {
+		close(started)
+		time.Sleep(20 * time.Millisecond)
+		if ctx.Err() == nil {
+			delivered.Store(true)
+		}
+	})
+	<-started
+
+	assert.True(t, p.Shutdown(time.Second))
+	assert.True(t, delivered.Load(), "task finished with a live context")
+	assert.Error(t, p.ctx.Err(), "context cancelled once drained")
+
+	var ran atomic.Bool
+	p.Submit(func(context.Context) { ran.Store(true) })
+	time.Sleep(10 * time.Millisecond)
+	assert.False(t, ran.Load(), "submissions after shutdown are dropped")
+}
+
+func TestShutdown_CancelsStragglersOnTimeout(t *testing.T) {
+	p := New(nil)
+
+	cancelled := make(chan struct{})
+	p.Submit(func(ctx context.Context) {
+		<-ctx.Done()
+		close(cancelled)
+	})
+
+	assert.False(t, p.Shutdown(10*time.Millisecond))
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("straggler context not cancelled after the drain timeout")
+	}
+}
+
+func TestShutdown_AcceptsNestedSubmitsWhileDraining(t *testing.T) {
+	p := New(nil)
+
+	var nested, outside atomic.Bool
+	started, release := make(chan struct{}), make(chan struct{})
+	p.Submit(func(ctx context.Context) {
+		close(started)
+		<-release
+		p.SubmitFrom(ctx, func(ctx context.Context) {
+			time.Sleep(10 * time.Millisecond)
+			nested.Store(ctx.Err() == nil)
+		})
+	})
+	<-started
+
+	drained := make(chan bool)
+	go func() { drained <- p.Shutdown(time.Second) }()
+	time.Sleep(10 * time.Millisecond)
+	p.SubmitFrom(context.Background(), func(context.Context) { outside.Store(true) })
+	close(release)
+
+	assert.True(t, <-drained)
+	assert.True(t, nested.Load(), "nested task ran before the drain finished")
+	assert.False(t, outside.Load(), "outside submissions are still dropped")
+}
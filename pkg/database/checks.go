@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// RequiredExtensions are the Postgres extensions the schema is built on:
+// uuid-ossp for uuid_generate_v4() defaults, PostGIS for every location
+// column and radius query.
+var RequiredExtensions = []string{"uuid-ossp", "postgis"}
+
+// RequiredEnumTypes are the enum types queries cast to. A missing one means
+// the migrations were never applied to this database.
+var RequiredEnumTypes = []string{"user_role"}
+
+// VerifySchema checks that the database has everything the code relies on
+// before the server takes traffic, so a misconfigured database fails the
+// boot with a fix-it message instead of failing at the first query. All
+// problems are reported at once.
+func VerifySchema(ctx context.Context, db *DB) error {
+	extensions := make(map[string]bool, len(RequiredExtensions))
+	rows, err := db.Pool.Query(ctx,
+		`SELECT name, installed_version IS NOT NULL FROM pg_available_extensions WHERE name = ANY($1)`,
+		RequiredExtensions,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to list Postgres extensions: %w", err)
+	}
+	for rows.Next() {
+		var name string
+		var installed bool
+		if err := rows.Scan(&name, &installed); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to list Postgres extensions: %w", err)
+		}
+		extensions[name] = installed
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to list Postgres extensions: %w", err)
+	}
+
+	enums := make(map[string]bool, len(RequiredEnumTypes))
+	rows, err = db.Pool.Query(ctx,
+		`SELECT typname FROM pg_type WHERE typtype = 'e' AND typname = ANY($1)`,
+		RequiredEnumTypes,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to list enum types: %w", err)
+	}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to list enum types: %w", err)
+		}
+		enums[name] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to list enum types: %w", err)
+	}
+
+	return schemaProblems(extensions, enums)
+}
+
+// schemaProblems turns what the database reported into one error per
+// missing requirement. extensions maps each available extension to whether
+// it is installed; enums holds the enum types that exist.
+func schemaProblems(extensions map[string]bool, enums map[string]bool) error {
+	var problems []error
+	for _, name := range RequiredExtensions {
+		installed, available := extensions[name]
+		switch {
+		case !available:
+			problems = append(problems, fmt.Errorf(
+				"postgres extension %q is not available on the database server: install it there (e.g. use the postgis/postgis image)", name))
+		case !installed:
+			problems = append(problems, fmt.Errorf(
+				`postgres extension %q is not installed: run CREATE EXTENSION IF NOT EXISTS "%s"; as a superuser, or apply the migrations`, name, name))
+		}
+	}
+	for _, name := range RequiredEnumTypes {
+		if !enums[name] {
+			problems = append(problems, fmt.Errorf(
+				"enum type %q is missing, so the schema is not migrated: run ./migrate up or start with DB_AUTO_MIGRATE=true", name))
+		}
+	}
+	return errors.Join(problems...)
+}
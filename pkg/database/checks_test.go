@@ -0,0 +1,31 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaProblems(t *testing.T) {
+	t.Run("ready database", func(t *testing.T) {
+		err := schemaProblems(
+			map[string]bool{"uuid-ossp": true, "postgis": true},
+			map[string]bool{"user_role": true},
+		)
+		assert.NoError(t, err)
+	})
+
+	t.Run("reports every problem with a fix", func(t *testing.T) {
+		err := schemaProblems(
+			map[string]bool{"uuid-ossp": false},
+			map[string]bool{},
+		)
+		require.Error(t, err)
+		msg := err.Error()
+		assert.Contains(t, msg, `extension "uuid-ossp" is not installed: run CREATE EXTENSION IF NOT EXISTS "uuid-ossp"`)
+		assert.Contains(t, msg, `extension "postgis" is not available on the database server`)
+		assert.Contains(t, msg, `enum type "user_role" is missing`)
+		assert.Contains(t, msg, "DB_AUTO_MIGRATE=true")
+	})
+}
@@ -14,6 +14,11 @@ import (
 	"github.com/jackc/pgx/v5"
 )
 
+// migrationLockKey is the pg_advisory_lock key that serialises migration
+// runs. Instances deployed together with DB_AUTO_MIGRATE all call Up; the
+// first applies the pending migrations, the rest wait and find none left.
+const migrationLockKey int64 = 7_246_173_001
+
 // Migration represents a database migration
 type Migration struct {
 	Version int
@@ -151,8 +156,29 @@ func (m *Migrator) loadMigrations() ([]Migration, error) {
 	return migrations, nil
 }
 
-// Up applies all pending migrations
+// withLock runs fn while holding the migration advisory lock. The lock is
+// transaction-scoped and held by a transaction that does nothing else, so
+// it is released however fn ends — even if the process dies mid-run.
+func (m *Migrator) withLock(ctx context.Context, fn func() error) error {
+	lockTx, err := m.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin migration lock transaction: %w", err)
+	}
+	defer func() { _ = lockTx.Rollback(context.Background()) }()
+
+	if _, err := lockTx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", migrationLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	return fn()
+}
+
+// Up applies all pending migrations. Concurrent callers — several instances
+// starting at once — are serialised on an advisory lock.
 func (m *Migrator) Up(ctx context.Context) error {
+	return m.withLock(ctx, func() error { return m.up(ctx) })
+}
+
+func (m *Migrator) up(ctx context.Context) error {
 	if err := m.ensureMigrationsTable(ctx); err != nil {
 		return fmt.Errorf("failed to create migrations table: %w", err)
 	}
@@ -217,6 +243,10 @@ func (m *Migrator) executeMigration(ctx context.Context, tx pgx.Tx, migration Mi
 
 // Down rolls back the last applied migration
 func (m *Migrator) Down(ctx context.Context) error {
+	return m.withLock(ctx, func() error { return m.down(ctx) })
+}
+
+func (m *Migrator) down(ctx context.Context) error {
 	if err := m.ensureMigrationsTable(ctx); err != nil {
 		return fmt.Errorf("failed to create migrations table: %w", err)
 	}
@@ -284,6 +314,29 @@ func (m *Migrator) Down(ctx context.Context) error {
 	return nil
 }
 
+// Pending returns how many migration files have not been applied yet. Used
+// by the readiness probe; it reads schema_migrations directly rather than
+// calling ensureMigrationsTable so a probe never issues DDL.
+func (m *Migrator) Pending(ctx context.Context) (int, error) {
+	migrations, err := m.loadMigrations()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	applied, err := m.getAppliedMigrations(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+
+	pending := 0
+	for _, migration := range migrations {
+		if !applied[migration.Version] {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
 // Status shows the current migration status
 func (m *Migrator) Status(ctx context.Context) error {
 	if err := m.ensureMigrationsTable(ctx); err != nil {
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type queryCounterCtxKey struct{}
+
+// WithQueryCounter returns a context that counts every query issued with it
+// (or any context derived from it) once [QueryCounterTracer] is installed on
+// the pool. The access-log middleware attaches one per request.
+func WithQueryCounter(ctx context.Context) context.Context {
+	return context.WithValue(ctx, queryCounterCtxKey{}, new(atomic.Int64))
+}
+
+// QueryCount returns the number of queries recorded against ctx, or 0 when
+// no counter is attached.
+func QueryCount(ctx context.Context) int64 {
+	if n, ok := ctx.Value(queryCounterCtxKey{}).(*atomic.Int64); ok {
+		return n.Load()
+	}
+	return 0
+}
+
+// QueryCounterTracer increments the per-request counter installed by
+// [WithQueryCounter]. Queries on contexts without a counter (background
+// jobs, startup) are ignored. The counter is atomic because handlers may
+// fan queries out across goroutines that share the request context.
+type QueryCounterTracer struct{}
+
+// TraceQueryStart is called when a query starts.
+func (QueryCounterTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	if n, ok := ctx.Value(queryCounterCtxKey{}).(*atomic.Int64); ok {
+		n.Add(1)
+	}
+	return ctx
+}
+
+// TraceQueryEnd is a no-op; only starts are counted.
+func (QueryCounterTracer) TraceQueryEnd(context.Context, *pgx.Conn, pgx.TraceQueryEndData) {}
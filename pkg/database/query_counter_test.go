@@ -0,0 +1,30 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryCounterTracer_CountsPerContext(t *testing.T) {
+	tracer := QueryCounterTracer{}
+	ctx := WithQueryCounter(context.Background())
+
+	for i := 0; i < 3; i++ {
+		qctx := tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+		tracer.TraceQueryEnd(qctx, nil, pgx.TraceQueryEndData{})
+	}
+	// Derived contexts share the counter.
+	child, cancel := context.WithCancel(ctx)
+	defer cancel()
+	tracer.TraceQueryStart(child, nil, pgx.TraceQueryStartData{SQL: "SELECT 2"})
+
+	assert.Equal(t, int64(4), QueryCount(ctx))
+}
+
+func TestQueryCounterTracer_NoCounterIsNoop(t *testing.T) {
+	ctx := QueryCounterTracer{}.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{})
+	assert.Equal(t, int64(0), QueryCount(ctx))
+}
@@ -0,0 +1,103 @@
+// Package events is a small in-process, typed event bus. Services publish
+// domain events ("a post was created") instead of calling the code that
+// reacts to them; notification, analytics and moderation subscribers
+// register independently at startup.
+//
+// Usage:
+//
+//	bus := events.New(logger)
+//	events.Subscribe(bus, func(ctx context.Context, e PostCreated) { … })
+//
+//	bus.Publish(ctx, PostCreated{…})
+//
+// Handlers run on the bgtasks pool by default, so a slow subscriber never
+// holds up the request that published the event and in-flight handlers are
+// drained on graceful shutdown.
+package events
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hamsaya/backend/pkg/bgtasks"
+	"go.uber.org/zap"
+)
+
+// Event is anything published on the bus. EventName identifies the event
+// type and must be constant per type; subscribers are keyed on it.
+type Event interface {
+	EventName() string
+}
+
+type handler func(ctx context.Context, e Event)
+
+// Bus dispatches published events to their subscribers. A nil *Bus is
+// valid: Publish is a no-op, so services work without one wired.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]handler
+	// dispatch runs one handler invocation. nil = run inline.
+	dispatch func(task func(ctx context.Context))
+	logger   *zap.Logger
+}
+
+// New creates a bus whose handlers run in the background on the bgtasks
+// pool. Pass nil logger to use the no-op logger.
+func New(logger *zap.Logger) *Bus {
+	b := NewSync(logger)
+	b.dispatch = bgtasks.Submit
+	return b
+}
+
+// NewSync creates a bus that runs handlers inline, before Publish returns.
+// Intended for tests.
+func NewSync(logger *zap.Logger) *Bus {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &Bus{handlers: map[string][]handler{}, logger: logger}
+}
+
+// Subscribe registers fn for every published event of type E. Handlers run
+// in registration order on a sync bus and concurrently on an async one.
+func Subscribe[E Event](b *Bus, fn func(ctx context.Context, e E)) {
+	var zero E
+	name := zero.EventName()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[name] = append(b.handlers[name], func(ctx context.Context, e Event) {
+		fn(ctx, e.(E))
+	})
+}
+
+// Publish delivers e to its subscribers. Async handlers get the pool's
+// context, not ctx, since the request usually finishes first. A panicking
+// handler is logged and doesn't affect the others.
+func (b *Bus) Publish(ctx context.Context, e Event) {
+	if b == nil {
+		return
+	}
+	b.mu.RLock()
+	handlers := b.handlers[e.EventName()]
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		h := h
+		if b.dispatch != nil {
+			b.dispatch(func(taskCtx context.Context) { h(taskCtx, e) })
+			continue
+		}
+		b.runInline(context.WithoutCancel(ctx), h, e)
+	}
+}
+
+func (b *Bus) runInline(ctx context.Context, h handler, e Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.logger.Error("events: handler panicked",
+				zap.String("event", e.EventName()), zap.Any("panic", r))
+		}
+	}()
+	h(ctx, e)
+}
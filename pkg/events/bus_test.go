@@ -0,0 +1,59 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type thingCreated struct{ ID string }
+
+func (thingCreated) EventName() string { return "thing.created" }
+
+type thingDeleted struct{ ID string }
+
+func (thingDeleted) EventName() string { return "thing.deleted" }
+
+func TestBus_DeliversByType(t *testing.T) {
+	bus := NewSync(nil)
+
+	var created, deleted []string
+	Subscribe(bus, func(_ context.Context, e thingCreated) { created = append(created, "a:"+e.ID) })
+	Subscribe(bus, func(_ context.Context, e thingCreated) { created = append(created, "b:"+e.ID) })
+	Subscribe(bus, func(_ context.Context, e thingDeleted) { deleted = append(deleted, e.ID) })
+
+	bus.Publish(context.Background(), thingCreated{ID: "1"})
+
+	assert.Equal(t, []string{"a:1", "b:1"}, created)
+	assert.Empty(t, deleted)
+}
+
+func TestBus_PanickingHandlerDoesNotStopOthers(t *testing.T) {
+	bus := NewSync(nil)
+
+	called := false
+	Subscribe(bus, func(context.Context, thingCreated) { panic("boom") })
+	Subscribe(bus, func(context.Context, thingCreated) { called = true })
+
+	assert.NotPanics(t, func() { bus.Publish(context.Background(), thingCreated{ID: "1"}) })
+	assert.True(t, called)
+}
+
+func TestBus_HandlersOutliveRequestContext(t *testing.T) {
+	bus := NewSync(nil)
+
+	var handlerErr error
+	Subscribe(bus, func(ctx context.Context, _ thingCreated) { handlerErr = ctx.Err() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	bus.Publish(ctx, thingCreated{ID: "1"})
+
+	assert.NoError(t, handlerErr)
+}
+
+func TestBus_NilIsNoop(t *testing.T) {
+	var bus *Bus
+	assert.NotPanics(t, func() { bus.Publish(context.Background(), thingCreated{ID: "1"}) })
+}
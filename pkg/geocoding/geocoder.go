@@ -0,0 +1,90 @@
+package geocoding
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hamsaya/backend/pkg/cache"
+)
+
+// ReverseGeocoder turns coordinates into address components. Implementations
+// return (nil, nil) when the point has no usable address.
+type ReverseGeocoder interface {
+	Reverse(ctx context.Context, lat, lng float64) (*ReverseResult, error)
+}
+
+// New returns the geocoder for provider: "nominatim" (default; baseURL
+// selects a self-hosted instance) or "none" to disable lookups. The
+// DISABLE_REVERSE_GEOCODE=1 env switch also disables it.
+func New(provider, baseURL string) (ReverseGeocoder, error) {
+	if os.Getenv("DISABLE_REVERSE_GEOCODE") == "1" {
+		return Disabled{}, nil
+	}
+	switch strings.ToLower(strings.TrimSpace(provider)) {
+	case "", "nominatim":
+		return NewNominatim(baseURL), nil
+	case "none", "disabled":
+		return Disabled{}, nil
+	default:
+		return nil, fmt.Errorf("unknown geocoding provider %q", provider)
+	}
+}
+
+// Disabled never resolves anything.
+type Disabled struct{}
+
+// Reverse always returns (nil, nil).
+func (Disabled) Reverse(context.Context, float64, float64) (*ReverseResult, error) {
+	return nil, nil
+}
+
+// cachedResult is the Redis value; Found=false caches "no address here" so
+// empty points don't hit the provider again.
+type cachedResult struct {
+	Found  bool           `json:"found"`
+	Result *ReverseResult `json:"result,omitempty"`
+}
+
+// Cached wraps a geocoder with a Redis read-through cache keyed on
+// coordinates rounded to 3 decimals (~100 m), which is finer than any
+// district boundary we care about.
+type Cached struct {
+	next  ReverseGeocoder
+	cache *cache.Cache
+	ttl   time.Duration
+}
+
+// NewCached returns next behind c. A nil cache passes straight through.
+func NewCached(next ReverseGeocoder, c *cache.Cache, ttl time.Duration) *Cached {
+	return &Cached{next: next, cache: c, ttl: ttl}
+}
+
+func cacheKey(lat, lng float64) string {
+	return fmt.Sprintf("rev:%.3f:%.3f", lat, lng)
+}
+
+// Reverse serves from cache when possible. Provider errors are not cached.
+func (g *Cached) Reverse(ctx context.Context, lat, lng float64) (*ReverseResult, error) {
+	key := cacheKey(lat, lng)
+	if g.cache != nil {
+		var hit cachedResult
+		if ok, _ := g.cache.Get(ctx, key, &hit); ok {
+			if !hit.Found {
+				return nil, nil
+			}
+			return hit.Result, nil
+		}
+	}
+
+	res, err := g.next.Reverse(ctx, lat, lng)
+	if err != nil {
+		return nil, err
+	}
+	if g.cache != nil {
+		_ = g.cache.Set(ctx, key, cachedResult{Found: res != nil, Result: res}, g.ttl)
+	}
+	return res, nil
+}
@@ -0,0 +1,131 @@
+package geocoding
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/hamsaya/backend/pkg/cache"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNominatim_Reverse_SelfHosted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "34.500000", r.URL.Query().Get("lat"))
+		assert.Equal(t, "69.200000", r.URL.Query().Get("lon"))
+		assert.NotEmpty(t, r.Header.Get("User-Agent"))
+		_, _ = w.Write([]byte(`{"address":{"country":"Afghanistan","state":"Kabul","county":"Paghman","suburb":"Qargha"}}`))
+	}))
+	defer srv.Close()
+
+	n := NewNominatim(srv.URL + "/reverse")
+	assert.Zero(t, n.MinInterval, "self-hosted instances are not throttled")
+
+	got, err := n.Reverse(context.Background(), 34.5, 69.2)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, &ReverseResult{Country: "Afghanistan", Province: "Kabul", District: "Paghman", Neighborhood: "Qargha"}, got)
+}
+
+func TestNominatim_Reverse_NoAddress(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"error":"Unable to geocode"}`))
+	}))
+	defer srv.Close()
+
+	got, err := NewNominatim(srv.URL).Reverse(context.Background(), 0, 0)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestNominatim_Reverse_ServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	_, err := NewNominatim(srv.URL).Reverse(context.Background(), 34.5, 69.2)
+	assert.Error(t, err)
+}
+
+func TestNew_Providers(t *testing.T) {
+	g, err := New("", "")
+	require.NoError(t, err)
+	assert.IsType(t, &Nominatim{}, g)
+
+	g, err = New("none", "")
+	require.NoError(t, err)
+	assert.IsType(t, Disabled{}, g)
+
+	_, err = New("google", "")
+	assert.Error(t, err)
+
+	t.Setenv("DISABLE_REVERSE_GEOCODE", "1")
+	g, err = New("nominatim", "")
+	require.NoError(t, err)
+	assert.IsType(t, Disabled{}, g)
+}
+
+type countingGeocoder struct {
+	calls  int32
+	result *ReverseResult
+}
+
+func (c *countingGeocoder) Reverse(context.Context, float64, float64) (*ReverseResult, error) {
+	atomic.AddInt32(&c.calls, 1)
+	return c.result, nil
+}
+
+func newTestCache(t *testing.T) *cache.Cache {
+	t.Helper()
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = rdb.Close() })
+	return cache.New(rdb, "geocode", zap.NewNop())
+}
+
+func TestCached_Reverse(t *testing.T) {
+	t.Run("nearby points share a cache entry", func(t *testing.T) {
+		next := &countingGeocoder{result: &ReverseResult{Province: "Kabul"}}
+		g := NewCached(next, newTestCache(t), time.Hour)
+
+		first, err := g.Reverse(context.Background(), 34.52811, 69.17231)
+		require.NoError(t, err)
+		second, err := g.Reverse(context.Background(), 34.52849, 69.17209)
+		require.NoError(t, err)
+
+		assert.Equal(t, "Kabul", first.Province)
+		assert.Equal(t, first, second)
+		assert.EqualValues(t, 1, next.calls)
+	})
+
+	t.Run("misses are cached too", func(t *testing.T) {
+		next := &countingGeocoder{}
+		g := NewCached(next, newTestCache(t), time.Hour)
+
+		for i := 0; i < 2; i++ {
+			got, err := g.Reverse(context.Background(), 0, 0)
+			require.NoError(t, err)
+			assert.Nil(t, got)
+		}
+		assert.EqualValues(t, 1, next.calls)
+	})
+
+	t.Run("nil cache passes through", func(t *testing.T) {
+		next := &countingGeocoder{result: &ReverseResult{Province: "Herat"}}
+		g := NewCached(next, nil, time.Hour)
+
+		_, _ = g.Reverse(context.Background(), 34.34, 62.2)
+		_, _ = g.Reverse(context.Background(), 34.34, 62.2)
+		assert.EqualValues(t, 2, next.calls)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -37,15 +38,60 @@ func strFromMap(m map[string]interface{}, keys ...string) string {
 	return ""
 }
 
-// ReverseGeocode calls Nominatim to get address components for lat, lng.
-// Requires outbound HTTPS from the server to nominatim.openstreetmap.org.
-// Set env DISABLE_REVERSE_GEOCODE=1 to skip the external call (e.g. when server has no internet).
-// Returns nil if the request fails or address details are missing (no error).
-func ReverseGeocode(ctx context.Context, lat, lng float64) (*ReverseResult, error) {
-	if os.Getenv("DISABLE_REVERSE_GEOCODE") == "1" {
-		return nil, nil
+// Nominatim reverse-geocodes against a Nominatim server: the public
+// openstreetmap.org instance by default, or a self-hosted one. Calls are
+// spaced by MinInterval to respect the public instance's 1 req/s policy.
+type Nominatim struct {
+	BaseURL     string
+	UserAgent   string
+	MinInterval time.Duration
+	client      *http.Client
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// NewNominatim returns a client for baseURL (the /reverse endpoint). Empty
+// baseURL means the public instance, which also gets the 1 req/s spacing.
+func NewNominatim(baseURL string) *Nominatim {
+	n := &Nominatim{
+		BaseURL:   baseURL,
+		UserAgent: "Hamsaya/1.0 (https://github.com/hamsaya; support@hamsaya.com)",
+		client:    &http.Client{Timeout: 5 * time.Second},
+	}
+	if n.BaseURL == "" {
+		n.BaseURL = nominatimBaseURL
+		n.MinInterval = time.Second
+	}
+	return n
+}
+
+// wait blocks until MinInterval has passed since the previous call.
+func (n *Nominatim) wait(ctx context.Context) error {
+	if n.MinInterval <= 0 {
+		return nil
+	}
+	n.mu.Lock()
+	next := n.last.Add(n.MinInterval)
+	now := time.Now()
+	if next.Before(now) {
+		next = now
+	}
+	n.last = next
+	n.mu.Unlock()
+
+	select {
+	case <-time.After(time.Until(next)):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	u, err := url.Parse(nominatimBaseURL)
+}
+
+// Reverse returns address components for lat, lng, or nil when the server
+// has no address details for the point.
+func (n *Nominatim) Reverse(ctx context.Context, lat, lng float64) (*ReverseResult, error) {
+	u, err := url.Parse(n.BaseURL)
 	if err != nil {
 		return nil, err
 	}
@@ -56,15 +102,17 @@ func ReverseGeocode(ctx context.Context, lat, lng float64) (*ReverseResult, erro
 	q.Set("addressdetails", "1")
 	u.RawQuery = q.Encode()
 
+	if err := n.wait(ctx); err != nil {
+		return nil, err
+	}
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
 	// Nominatim usage policy requires a valid User-Agent identifying the application
-	req.Header.Set("User-Agent", "Hamsaya/1.0 (https://github.com/hamsaya; support@hamsaya.com)")
+	req.Header.Set("User-Agent", n.UserAgent)
 
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := n.client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -94,3 +142,17 @@ func ReverseGeocode(ctx context.Context, lat, lng float64) (*ReverseResult, erro
 	}
 	return r, nil
 }
+
+var defaultNominatim = NewNominatim("")
+
+// ReverseGeocode calls the public Nominatim instance to get address
+// components for lat, lng.
+// Requires outbound HTTPS from the server to nominatim.openstreetmap.org.
+// Set env DISABLE_REVERSE_GEOCODE=1 to skip the external call (e.g. when server has no internet).
+// Returns nil if address details are missing (no error).
+func ReverseGeocode(ctx context.Context, lat, lng float64) (*ReverseResult, error) {
+	if os.Getenv("DISABLE_REVERSE_GEOCODE") == "1" {
+		return nil, nil
+	}
+	return defaultNominatim.Reverse(ctx, lat, lng)
+}
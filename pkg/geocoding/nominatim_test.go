@@ -12,13 +12,8 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-// overrideNominatimURL patches the package-level constant for testing.
-// The real function uses a hardcoded const, so we need an httptest server
-// and must monkey-patch or use the env approach. Since the code uses
-// os.Getenv("DISABLE_REVERSE_GEOCODE"), we test that path directly, and
-// for the HTTP path we use a roundabout approach via a mock server patched
-// via the nominatimBaseURL variable (must be var in the implementation).
-// Since it's a const, we test the DISABLE path and the unmarshal helpers.
+// ReverseGeocode always targets the public instance; the HTTP path is
+// covered against a local server through NewNominatim in geocoder_test.go.
 
 func TestReverseGeocode_Disabled(t *testing.T) {
 	t.Setenv("DISABLE_REVERSE_GEOCODE", "1")
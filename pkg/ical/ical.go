@@ -0,0 +1,194 @@
+// Package ical writes iCalendar (RFC 5545) files: single events to import and
+// feeds calendar apps subscribe to.
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the MIME type to serve calendars with.
+const ContentType = "text/calendar; charset=utf-8"
+
+const (
+	dateFormat    = "20060102"
+	localFormat   = "20060102T150405"
+	utcFormat     = "20060102T150405Z"
+	maxLineOctets = 75
+	defaultProdID = "-//Hamsaya//Events//EN"
+)
+
+// Calendar is a VCALENDAR. Local times of its events are written in Location,
+// which must be a zone without daylight saving time (VTIMEZONE only carries
+// its standard offset); nil writes them in UTC.
+type Calendar struct {
+	ProdID   string
+	Name     string
+	Location *time.Location
+	// RefreshInterval hints subscribing apps how often to poll; zero omits it.
+	RefreshInterval time.Duration
+	Events          []Event
+}
+
+// Event is a VEVENT. AllDay events only use the dates of Start and End, End
+// being exclusive.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	Latitude    *float64
+	Longitude   *float64
+	Stamp       time.Time
+}
+
+// Write renders the calendar with CRLF line endings and folded lines.
+func (c *Calendar) Write(w io.Writer) error {
+	lw := &lineWriter{w: w}
+	prodID := c.ProdID
+	if prodID == "" {
+		prodID = defaultProdID
+	}
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:" + prodID)
+	lw.line("CALSCALE:GREGORIAN")
+	lw.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		lw.line("X-WR-CALNAME:" + escapeText(c.Name))
+	}
+	if c.RefreshInterval > 0 {
+		lw.line("REFRESH-INTERVAL;VALUE=DURATION:" + duration(c.RefreshInterval))
+		lw.line("X-PUBLISHED-TTL:" + duration(c.RefreshInterval))
+	}
+	tzid := ""
+	if c.Location != nil && c.Location != time.UTC {
+		tzid = c.Location.String()
+		lw.line("X-WR-TIMEZONE:" + tzid)
+		c.writeTimezone(lw, tzid)
+	}
+	for i := range c.Events {
+		c.Events[i].write(lw, c.Location, tzid)
+	}
+	lw.line("END:VCALENDAR")
+	return lw.err
+}
+
+// writeTimezone describes the calendar zone by its standard offset.
+func (c *Calendar) writeTimezone(lw *lineWriter, tzid string) {
+	_, offset := time.Date(1970, 1, 1, 0, 0, 0, 0, c.Location).Zone()
+	lw.line("BEGIN:VTIMEZONE")
+	lw.line("TZID:" + tzid)
+	lw.line("BEGIN:STANDARD")
+	lw.line("DTSTART:19700101T000000")
+	lw.line("TZOFFSETFROM:" + utcOffset(offset))
+	lw.line("TZOFFSETTO:" + utcOffset(offset))
+	lw.line("END:STANDARD")
+	lw.line("END:VTIMEZONE")
+}
+
+func (e *Event) write(lw *lineWriter, loc *time.Location, tzid string) {
+	lw.line("BEGIN:VEVENT")
+	lw.line("UID:" + e.UID)
+	stamp := e.Stamp
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+	lw.line("DTSTAMP:" + stamp.UTC().Format(utcFormat))
+	switch {
+	case e.AllDay:
+		lw.line("DTSTART;VALUE=DATE:" + e.Start.Format(dateFormat))
+		lw.line("DTEND;VALUE=DATE:" + e.End.Format(dateFormat))
+	case tzid != "":
+		lw.line("DTSTART;TZID=" + tzid + ":" + e.Start.In(loc).Format(localFormat))
+		lw.line("DTEND;TZID=" + tzid + ":" + e.End.In(loc).Format(localFormat))
+	default:
+		lw.line("DTSTART:" + e.Start.UTC().Format(utcFormat))
+		lw.line("DTEND:" + e.End.UTC().Format(utcFormat))
+	}
+	lw.line("SUMMARY:" + escapeText(e.Summary))
+	if e.Description != "" {
+		lw.line("DESCRIPTION:" + escapeText(e.Description))
+	}
+	if e.Location != "" {
+		lw.line("LOCATION:" + escapeText(e.Location))
+	}
+	if e.Latitude != nil && e.Longitude != nil {
+		lw.line(fmt.Sprintf("GEO:%.6f;%.6f", *e.Latitude, *e.Longitude))
+	}
+	if e.URL != "" {
+		lw.line("URL:" + e.URL)
+	}
+	lw.line("STATUS:CONFIRMED")
+	lw.line("END:VEVENT")
+}
+
+// escapeText escapes a TEXT value (RFC 5545 §3.3.11).
+func escapeText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
+
+// utcOffset formats seconds east of UTC as ±HHMM.
+func utcOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds%3600/60)
+}
+
+// duration formats d as an RFC 5545 DURATION, to the minute.
+func duration(d time.Duration) string {
+	minutes := int(d / time.Minute)
+	if minutes%(60*24) == 0 {
+		return fmt.Sprintf("P%dD", minutes/(60*24))
+	}
+	if minutes%60 == 0 {
+		return fmt.Sprintf("PT%dH", minutes/60)
+	}
+	return fmt.Sprintf("PT%dM", minutes)
+}
+
+// lineWriter writes content lines folded at 75 octets without splitting a
+// UTF-8 sequence, keeping the first error.
+type lineWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (lw *lineWriter) line(s string) {
+	if lw.err != nil {
+		return
+	}
+	var b strings.Builder
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space, which counts.
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+	_, lw.err = io.WriteString(lw.w, b.String())
+}
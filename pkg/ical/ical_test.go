@@ -0,0 +1,75 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendar_Write(t *testing.T) {
+	kabul := time.FixedZone("Asia/Kabul", 4*3600+1800)
+	lat, lon := 34.5553, 69.2075
+	cal := &Calendar{
+		Name:            "My events",
+		Location:        kabul,
+		RefreshInterval: 6 * time.Hour,
+		Events: []Event{{
+			UID:         "post-1@hamsaya",
+			Summary:     "Book fair; day 1",
+			Description: "Bring books,\nbring friends",
+			Location:    "Shahr-e Naw, Kabul",
+			Start:       time.Date(2026, 10, 20, 17, 0, 0, 0, kabul),
+			End:         time.Date(2026, 10, 20, 19, 30, 0, 0, kabul),
+			Latitude:    &lat,
+			Longitude:   &lon,
+			Stamp:       time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC),
+		}, {
+			UID:     "post-2@hamsaya",
+			Summary: "Cleanup day",
+			Start:   time.Date(2026, 10, 22, 0, 0, 0, 0, time.UTC),
+			End:     time.Date(2026, 10, 23, 0, 0, 0, 0, time.UTC),
+			AllDay:  true,
+		}},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, cal.Write(&buf))
+	out := buf.String()
+
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	assert.Contains(t, out, "REFRESH-INTERVAL;VALUE=DURATION:PT6H\r\n")
+	assert.Contains(t, out, "TZID:Asia/Kabul\r\n")
+	assert.Contains(t, out, "TZOFFSETTO:+0430\r\n")
+	assert.Contains(t, out, "DTSTAMP:20261016T080000Z\r\n")
+	assert.Contains(t, out, "DTSTART;TZID=Asia/Kabul:20261020T170000\r\n")
+	assert.Contains(t, out, "DTEND;TZID=Asia/Kabul:20261020T193000\r\n")
+	assert.Contains(t, out, `SUMMARY:Book fair\; day 1`+"\r\n")
+	assert.Contains(t, out, `DESCRIPTION:Bring books\,\nbring friends`+"\r\n")
+	assert.Contains(t, out, "GEO:34.555300;69.207500\r\n")
+	assert.Contains(t, out, "DTSTART;VALUE=DATE:20261022\r\nDTEND;VALUE=DATE:20261023\r\n")
+	assert.Equal(t, 2, strings.Count(out, "BEGIN:VEVENT"))
+}
+
+func TestLineWriter_Folds(t *testing.T) {
+	var buf bytes.Buffer
+	lw := &lineWriter{w: &buf}
+	// Multi-byte runes must not be split across a fold.
+	lw.line("SUMMARY:" + strings.Repeat("د", 100))
+	require.NoError(t, lw.err)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	require.Greater(t, len(lines), 1)
+	for i, l := range lines {
+		assert.LessOrEqual(t, len(l), maxLineOctets)
+		if i > 0 {
+			assert.True(t, strings.HasPrefix(l, " "))
+		}
+	}
+	unfolded := strings.ReplaceAll(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n ", "")
+	assert.Equal(t, "SUMMARY:"+strings.Repeat("د", 100), unfolded)
+}
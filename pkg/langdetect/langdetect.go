@@ -0,0 +1,107 @@
+// Package langdetect guesses the language of short user-written text.
+//
+// It only distinguishes the languages our users actually post in: Dari and
+// Pashto (both Arabic script), Arabic and English. The guess is
+// script-based: Pashto has letters no other language here uses, Persian and
+// Arabic differ in a handful of letter forms, and Latin text is taken to be
+// English. That is enough to filter a feed; it is not a general-purpose
+// detector.
+package langdetect
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Language codes stored on posts. Dari is stored as "fa": Dari and Farsi
+// are written the same way and clients already use the Persian locale.
+const (
+	Persian = "fa"
+	Pashto  = "ps"
+	Arabic  = "ar"
+	English = "en"
+)
+
+// minLetters is how many letters a text needs before a guess is made.
+// Shorter texts ("ok", "👍", a price) come back undetermined.
+const minLetters = 3
+
+var supported = map[string]bool{Persian: true, Pashto: true, Arabic: true, English: true}
+
+// pashtoLetters only appear in Pashto.
+var pashtoLetters = map[rune]bool{
+	'ټ': true, 'ډ': true, 'ړ': true, 'ږ': true, 'ښ': true, 'ګ': true,
+	'ڼ': true, 'ې': true, 'ۍ': true, 'ځ': true, 'څ': true,
+}
+
+// persianLetters are Persian forms (shared with Pashto) that Arabic lacks
+// or writes differently.
+var persianLetters = map[rune]bool{
+	'پ': true, 'چ': true, 'ژ': true, 'گ': true, 'ی': true, 'ک': true,
+}
+
+// arabicLetters are Arabic forms Persian and Pashto don't use.
+var arabicLetters = map[rune]bool{
+	'ة': true, 'ي': true, 'ك': true, 'ى': true,
+}
+
+// Detect returns the language code of the combined texts, or "" when there
+// is too little text to tell.
+func Detect(texts ...string) string {
+	var arabicScript, latin, pashto, persian, arabic int
+	for _, text := range texts {
+		for _, r := range text {
+			switch {
+			case unicode.Is(unicode.Arabic, r) && unicode.IsLetter(r):
+				arabicScript++
+				switch {
+				case pashtoLetters[r]:
+					pashto++
+				case persianLetters[r]:
+					persian++
+				case arabicLetters[r]:
+					arabic++
+				}
+			case unicode.Is(unicode.Latin, r):
+				latin++
+			}
+		}
+	}
+
+	if arabicScript+latin < minLetters {
+		return ""
+	}
+	if latin > arabicScript {
+		return English
+	}
+	switch {
+	case pashto > 0:
+		return Pashto
+	case arabic > persian:
+		return Arabic
+	}
+	// Arabic-script text without telling letters is most likely Dari for
+	// our users.
+	return Persian
+}
+
+// Supported reports whether code is a language Detect can return.
+func Supported(code string) bool {
+	return supported[code]
+}
+
+// ParseList parses a comma-separated list such as "fa,ps" into supported
+// codes, lower-cased and without duplicates. Unknown codes are dropped.
+func ParseList(s string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ",") {
+		code := strings.ToLower(strings.TrimSpace(part))
+		if !supported[code] || seen[code] {
+			continue
+		}
+		seen[code] = true
+		out = append(out, code)
+	}
+	return out
+}
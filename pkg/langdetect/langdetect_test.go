@@ -0,0 +1,36 @@
+package langdetect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	cases := []struct {
+		name  string
+		texts []string
+		want  string
+	}{
+		{"dari", []string{"نان تازه برای نوروز در کابل"}, Persian},
+		{"pashto", []string{"زه غواړم چې دا کور وپلورم"}, Pashto},
+		{"arabic", []string{"مرحبا بكم في المدينة الجميلة"}, Arabic},
+		{"english", []string{"Fresh bread for sale near the park"}, English},
+		{"title and description combined", []string{"Sale", "ګل او ښکلي بوټي"}, Pashto},
+		{"mostly latin with a persian word", []string{"Selling my bike, قیمت ok, pickup today"}, English},
+		{"too short", []string{"ok"}, ""},
+		{"no letters", []string{"👍 100 !!!"}, ""},
+		{"empty", nil, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, Detect(tc.texts...))
+		})
+	}
+}
+
+func TestParseList(t *testing.T) {
+	assert.Equal(t, []string{"fa", "ps"}, ParseList(" FA, ps,xx,fa"))
+	assert.Empty(t, ParseList(""))
+	assert.Empty(t, ParseList("all"))
+}
@@ -0,0 +1,186 @@
+// Package lifecycle runs the server's long-lived components — the HTTP
+// listener, the WebSocket hub, periodic jobs, queue workers — under one
+// context and stops them in order on shutdown.
+//
+// Usage:
+//
+//	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM)
+//	defer stop()
+//	lc := lifecycle.New(ctx, logger)
+//
+//	lc.Go("hub", func(ctx context.Context) error { hub.Run(); return nil })
+//	lc.OnStop("hub", 5*time.Second, func(context.Context) error { hub.Shutdown(); return nil })
+//	lc.Every("cleanup", time.Hour, false, cleanup)
+//
+//	<-lc.Done() // signal received, or a component failed
+//	err := lc.Shutdown(15 * time.Second)
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// Manager owns the root context of the process. Components started with Go
+// run until it is cancelled; a component that fails cancels it too, so one
+// broken worker takes the server down cleanly instead of leaving it half
+// alive.
+type Manager struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	group  *errgroup.Group
+	logger *zap.Logger
+
+	mu    sync.Mutex
+	hooks []stopHook
+	once  sync.Once
+}
+
+// stopHook is a shutdown step bounded by its own timeout.
+type stopHook struct {
+	name    string
+	timeout time.Duration
+	fn      func(ctx context.Context) error
+}
+
+// New returns a manager whose context ends when parent does — typically a
+// signal.NotifyContext — or when Shutdown is called. Pass nil logger to use
+// the no-op logger.
+func New(parent context.Context, logger *zap.Logger) *Manager {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	ctx, cancel := context.WithCancel(parent)
+	group, ctx := errgroup.WithContext(ctx)
+	return &Manager{ctx: ctx, cancel: cancel, group: group, logger: logger}
+}
+
+// Context is cancelled when shutdown starts.
+func (m *Manager) Context() context.Context { return m.ctx }
+
+// Done is closed when shutdown should start: the parent context ended or a
+// component returned an error.
+func (m *Manager) Done() <-chan struct{} { return m.ctx.Done() }
+
+// Go runs a component until its context is cancelled. Returning an error —
+// or panicking — starts shutdown; returning nil just ends the component.
+// A context.Canceled error after shutdown started counts as a clean stop.
+func (m *Manager) Go(name string, fn func(ctx context.Context) error) {
+	m.group.Go(func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("%s: panic: %v", name, r)
+				m.logger.Error("lifecycle: component panicked", zap.String("component", name), zap.Any("panic", r))
+			}
+		}()
+		err = fn(m.ctx)
+		if err == nil || (m.ctx.Err() != nil && errors.Is(err, context.Canceled)) {
+			return nil
+		}
+		m.logger.Error("lifecycle: component failed", zap.String("component", name), zap.Error(err))
+		return fmt.Errorf("%s: %w", name, err)
+	})
+}
+
+// Every runs fn every interval until shutdown, and once right away when
+// runNow is set. A run in progress sees its context cancelled at shutdown;
+// no new run starts after that.
+func (m *Manager) Every(name string, interval time.Duration, runNow bool, fn func(ctx context.Context)) {
+	m.Go(name, func(ctx context.Context) error {
+		if runNow {
+			fn(ctx)
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				fn(ctx)
+			}
+		}
+	})
+}
+
+// OnStop registers a shutdown step. Steps run one at a time in reverse
+// registration order, like defers: register a component right after
+// starting it and it is stopped before whatever it was built on. Each step
+// gets at most timeout; one that overruns is abandoned and reported.
+func (m *Manager) OnStop(name string, timeout time.Duration, fn func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, stopHook{name: name, timeout: timeout, fn: fn})
+}
+
+// Shutdown cancels the root context, runs the stop steps, then waits up to
+// drain for the components started with Go to return. It returns the error
+// that triggered shutdown, if any, joined with every step or drain failure.
+// Only the first call does anything.
+func (m *Manager) Shutdown(drain time.Duration) error {
+	err := errors.New("lifecycle: shutdown already called")
+	m.once.Do(func() {
+		err = m.shutdown(drain)
+	})
+	return err
+}
+
+func (m *Manager) shutdown(drain time.Duration) error {
+	m.cancel()
+
+	m.mu.Lock()
+	hooks := append([]stopHook(nil), m.hooks...)
+	m.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		m.logger.Info("lifecycle: stopping", zap.String("component", h.name))
+		if err := runHook(h); err != nil {
+			m.logger.Warn("lifecycle: stop step failed", zap.String("component", h.name), zap.Error(err))
+			errs = append(errs, fmt.Errorf("stop %s: %w", h.name, err))
+		}
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- m.group.Wait() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			errs = append([]error{err}, errs...)
+		}
+	case <-time.After(drain):
+		m.logger.Warn("lifecycle: components did not stop in time", zap.Duration("drain", drain))
+		errs = append(errs, fmt.Errorf("lifecycle: components still running after %s", drain))
+	}
+	return errors.Join(errs...)
+}
+
+// runHook runs one stop step, giving up when its timeout expires even if the
+// step ignores its context.
+func runHook(h stopHook) error {
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- h.fn(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("timed out after %s", h.timeout)
+	}
+}
@@ -0,0 +1,116 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdown_StopsComponentsAndRunsStepsInReverse(t *testing.T) {
+	lc := New(context.Background(), nil)
+
+	var stopped atomic.Bool
+	lc.Go("worker", func(ctx context.Context) error {
+		<-ctx.Done()
+		stopped.Store(true)
+		return ctx.Err()
+	})
+
+	var mu sync.Mutex
+	var order []string
+	for _, name := range []string{"hub", "bgtasks", "http"} {
+		name := name
+		lc.OnStop(name, time.Second, func(context.Context) error {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	require.NoError(t, lc.Shutdown(time.Second))
+	assert.True(t, stopped.Load())
+	assert.Equal(t, []string{"http", "bgtasks", "hub"}, order)
+	assert.Error(t, lc.Shutdown(time.Second), "second call is rejected")
+}
+
+func TestParentCancelStartsShutdown(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	lc := New(parent, nil)
+
+	cancel()
+	select {
+	case <-lc.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Done not closed after parent was cancelled")
+	}
+	require.NoError(t, lc.Shutdown(time.Second))
+}
+
+func TestFailingComponentStartsShutdown(t *testing.T) {
+	lc := New(context.Background(), nil)
+	boom := errors.New("listen: address in use")
+	lc.Go("http", func(context.Context) error { return boom })
+	lc.Go("panicky", func(ctx context.Context) error {
+		<-ctx.Done()
+		panic("late panic")
+	})
+
+	select {
+	case <-lc.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Done not closed after a component failed")
+	}
+	err := lc.Shutdown(time.Second)
+	assert.ErrorIs(t, err, boom)
+	assert.Contains(t, err.Error(), "http")
+}
+
+func TestShutdown_BoundsStepsAndDrain(t *testing.T) {
+	lc := New(context.Background(), nil)
+
+	release := make(chan struct{})
+	defer close(release)
+	lc.Go("stuck", func(context.Context) error {
+		<-release
+		return nil
+	})
+	lc.OnStop("hung", 20*time.Millisecond, func(context.Context) error {
+		<-release
+		return nil
+	})
+
+	var ran atomic.Bool
+	lc.OnStop("after-hung", time.Second, func(context.Context) error {
+		ran.Store(true)
+		return nil
+	})
+
+	start := time.Now()
+	err := lc.Shutdown(20 * time.Millisecond)
+	assert.Less(t, time.Since(start), time.Second)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "stop hung: timed out")
+	assert.Contains(t, err.Error(), "still running")
+	assert.True(t, ran.Load(), "steps keep running after one times out")
+}
+
+func TestEvery(t *testing.T) {
+	lc := New(context.Background(), nil)
+
+	var runs atomic.Int32
+	lc.Every("job", 5*time.Millisecond, true, func(context.Context) { runs.Add(1) })
+
+	assert.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, time.Millisecond)
+	require.NoError(t, lc.Shutdown(time.Second))
+
+	after := runs.Load()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, after, runs.Load(), "no runs after shutdown")
+}
@@ -182,11 +182,13 @@ func (f *FCMClient) SendNotification(ctx context.Context, token string, payload
 			zap.String("token", token),
 		)
 		// Surface stale-token errors so callers can prune the FCM token from
-		// storage. messaging.IsRegistrationTokenNotRegistered covers both
-		// classical "NotRegistered" (token revoked / app uninstalled) and
-		// "InvalidArgument" / "InvalidRegistration" responses.
-		if messaging.IsUnregistered(err) ||
-			messaging.IsInvalidArgument(err) {
+		// storage. "Unregistered" (token revoked / app uninstalled) is told
+		// apart from a malformed token because FCM occasionally reports it
+		// for live tokens; callers prune only after it repeats.
+		if messaging.IsUnregistered(err) {
+			return ErrTokenUnregistered
+		}
+		if messaging.IsInvalidArgument(err) {
 			return ErrTokenInvalid
 		}
 		return fmt.Errorf("failed to send notification: %w", err)
@@ -205,6 +207,10 @@ func (f *FCMClient) SendNotification(ctx context.Context, token string, payload
 // Callers should delete the stored token from their tokens table.
 var ErrTokenInvalid = fmt.Errorf("fcm token is no longer valid")
 
+// ErrTokenUnregistered is returned when FCM reports the token as no longer
+// registered. It wraps ErrTokenInvalid.
+var ErrTokenUnregistered = fmt.Errorf("%w: unregistered", ErrTokenInvalid)
+
 // SendMulticast sends a push notification to multiple devices
 func (f *FCMClient) SendMulticast(ctx context.Context, tokens []string, payload *PushPayload) (*messaging.BatchResponse, error) {
 	if len(tokens) == 0 {
@@ -59,6 +59,15 @@ var explicitClasses = map[string]struct{}{
 	"ANUS_EXPOSED":             {},
 }
 
+// Classifier scans image bytes. Client is the NudeNet implementation;
+// anything else (a hosted moderation API, another local model) can be
+// plugged into the moderation worker by implementing it.
+type Classifier interface {
+	Scan(ctx context.Context, data []byte, filename, contentType string) Result
+}
+
+var _ Classifier = (*Client)(nil)
+
 // Client wraps the HTTP API of a NudeNet sidecar.
 type Client struct {
 	baseURL        string
@@ -50,6 +50,14 @@ func RecordMessageCreated(ctx context.Context) {
 	}
 }
 
+// RecordReportFiled bumps the reports_filed_total counter. kind is what
+// was reported — "post", "comment", "user", "business".
+func RecordReportFiled(ctx context.Context, kind string) {
+	if m := loadGlobal(); m != nil {
+		m.RecordReportFiled(ctx, kind)
+	}
+}
+
 // WebSocketConnected increments the active-connections gauge.
 func WebSocketConnected(ctx context.Context) {
 	if m := loadGlobal(); m != nil {
@@ -63,3 +71,11 @@ func WebSocketDisconnected(ctx context.Context) {
 		m.WebSocketDisconnected(ctx)
 	}
 }
+
+// RecordStorageReclaimed bumps the storage_orphans_deleted_total and
+// storage_reclaimed_bytes_total counters after a reconciliation run.
+func RecordStorageReclaimed(ctx context.Context, objects, bytes int64) {
+	if m := loadGlobal(); m != nil {
+		m.RecordStorageReclaimed(ctx, objects, bytes)
+	}
+}
@@ -26,7 +26,12 @@ type Metrics struct {
 	UsersCreated     metric.Int64Counter
 	PostsCreated     metric.Int64Counter
 	MessagesCreated  metric.Int64Counter
+	ReportsFiled     metric.Int64Counter
 	ActiveWebSockets metric.Int64UpDownCounter
+
+	// Storage metrics
+	StorageOrphansDeleted metric.Int64Counter
+	StorageBytesReclaimed metric.Int64Counter
 }
 
 // NewMetrics creates and registers application metrics
@@ -131,6 +136,15 @@ func NewMetrics(serviceName string) (*Metrics, error) {
 		return nil, err
 	}
 
+	m.ReportsFiled, err = meter.Int64Counter(
+		"reports_filed_total",
+		metric.WithDescription("Total number of content reports filed"),
+		metric.WithUnit("{report}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	m.ActiveWebSockets, err = meter.Int64UpDownCounter(
 		"websocket_connections_active",
 		metric.WithDescription("Number of active WebSocket connections"),
@@ -140,6 +154,25 @@ func NewMetrics(serviceName string) (*Metrics, error) {
 		return nil, err
 	}
 
+	// Storage metrics
+	m.StorageOrphansDeleted, err = meter.Int64Counter(
+		"storage_orphans_deleted_total",
+		metric.WithDescription("Total number of orphaned storage objects deleted"),
+		metric.WithUnit("{object}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	m.StorageBytesReclaimed, err = meter.Int64Counter(
+		"storage_reclaimed_bytes_total",
+		metric.WithDescription("Total bytes freed by deleting orphaned storage objects"),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	return m, nil
 }
 
@@ -203,6 +236,13 @@ func (m *Metrics) RecordMessageCreated(ctx context.Context) {
 	m.MessagesCreated.Add(ctx, 1)
 }
 
+// RecordReportFiled increments the report counter
+func (m *Metrics) RecordReportFiled(ctx context.Context, kind string) {
+	m.ReportsFiled.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("kind", kind),
+	))
+}
+
 // WebSocketConnected increments the active WebSocket connections counter
 func (m *Metrics) WebSocketConnected(ctx context.Context) {
 	m.ActiveWebSockets.Add(ctx, 1)
@@ -212,3 +252,10 @@ func (m *Metrics) WebSocketConnected(ctx context.Context) {
 func (m *Metrics) WebSocketDisconnected(ctx context.Context) {
 	m.ActiveWebSockets.Add(ctx, -1)
 }
+
+// RecordStorageReclaimed adds deleted orphaned objects and their bytes to the
+// storage reclaim counters
+func (m *Metrics) RecordStorageReclaimed(ctx context.Context, objects, bytes int64) {
+	m.StorageOrphansDeleted.Add(ctx, objects)
+	m.StorageBytesReclaimed.Add(ctx, bytes)
+}
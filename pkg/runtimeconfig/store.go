@@ -0,0 +1,263 @@
+// Package runtimeconfig holds operator-tunable settings that can change
+// without a redeploy. Overrides live in a single Redis hash so every API
+// instance converges on the same values; each instance keeps an in-process
+// copy refreshed on an interval so hot paths (rate limiting) never pay a
+// Redis round-trip per read.
+package runtimeconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// DefaultKey is the Redis hash that stores overrides.
+const DefaultKey = "runtime:settings"
+
+// Kind is the value type of a setting. Values are stored as strings and
+// validated against the kind on write.
+type Kind string
+
+const (
+	KindInt      Kind = "int"
+	KindBool     Kind = "bool"
+	KindDuration Kind = "duration"
+)
+
+// ErrUnknownSetting is returned when writing a key that was never registered.
+// The catalog lives in source so typos can't create dead settings.
+var ErrUnknownSetting = errors.New("runtimeconfig: unknown setting")
+
+// ErrInvalidValue is returned when a value doesn't parse for the setting's
+// kind or is out of range.
+var ErrInvalidValue = errors.New("runtimeconfig: invalid value")
+
+// Setting describes one tunable.
+type Setting struct {
+	Key         string `json:"key"`
+	Kind        Kind   `json:"kind"`
+	Default     string `json:"default"`
+	Description string `json:"description"`
+}
+
+// Value is a setting together with its effective value.
+type Value struct {
+	Setting
+	Value      string `json:"value"`
+	Overridden bool   `json:"overridden"`
+}
+
+// Store is the registry of settings plus the cached overrides.
+type Store struct {
+	client *redis.Client
+	key    string
+	logger *zap.Logger
+
+	mu        sync.RWMutex
+	settings  map[string]Setting
+	overrides map[string]string
+}
+
+// New creates a store backed by the DefaultKey hash.
+func New(client *redis.Client, logger *zap.Logger) *Store {
+	return &Store{
+		client:    client,
+		key:       DefaultKey,
+		logger:    logger,
+		settings:  make(map[string]Setting),
+		overrides: make(map[string]string),
+	}
+}
+
+// Register adds a setting to the catalog. Re-registering a key replaces its
+// description and default.
+func (s *Store) Register(setting Setting) {
+	s.mu.Lock()
+	s.settings[setting.Key] = setting
+	s.mu.Unlock()
+}
+
+// Refresh reloads every override from Redis. Values that no longer parse
+// for their registered kind are dropped so a bad write can't wedge a reader.
+func (s *Store) Refresh(ctx context.Context) error {
+	raw, err := s.client.HGetAll(ctx, s.key).Result()
+	if err != nil {
+		return fmt.Errorf("runtimeconfig refresh: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	next := make(map[string]string, len(raw))
+	for k, v := range raw {
+		setting, ok := s.settings[k]
+		if !ok {
+			continue
+		}
+		if err := validate(setting.Kind, v); err != nil {
+			s.logger.Warn("Ignoring invalid runtime setting", zap.String("key", k), zap.String("value", v), zap.Error(err))
+			continue
+		}
+		next[k] = v
+	}
+	s.overrides = next
+	return nil
+}
+
+// Run refreshes on every tick until ctx is cancelled. Errors are logged and
+// the last good snapshot stays in effect.
+func (s *Store) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil {
+				s.logger.Warn("Runtime settings refresh failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Set validates and persists an override. The local copy is updated
+// immediately; other instances pick it up on their next refresh.
+func (s *Store) Set(ctx context.Context, key, value string) error {
+	s.mu.RLock()
+	setting, ok := s.settings[key]
+	s.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownSetting, key)
+	}
+	if err := validate(setting.Kind, value); err != nil {
+		return fmt.Errorf("%w for %s: %v", ErrInvalidValue, key, err)
+	}
+	if err := s.client.HSet(ctx, s.key, key, value).Err(); err != nil {
+		return fmt.Errorf("runtimeconfig set: %w", err)
+	}
+	s.mu.Lock()
+	s.overrides[key] = value
+	s.mu.Unlock()
+	return nil
+}
+
+// Reset removes an override so the setting falls back to its default.
+func (s *Store) Reset(ctx context.Context, key string) error {
+	s.mu.RLock()
+	_, ok := s.settings[key]
+	s.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownSetting, key)
+	}
+	if err := s.client.HDel(ctx, s.key, key).Err(); err != nil {
+		return fmt.Errorf("runtimeconfig reset: %w", err)
+	}
+	s.mu.Lock()
+	delete(s.overrides, key)
+	s.mu.Unlock()
+	return nil
+}
+
+// List returns every registered setting with its effective value, sorted
+// by key.
+func (s *Store) List() []Value {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]Value, 0, len(s.settings))
+	for k, setting := range s.settings {
+		v := Value{Setting: setting, Value: setting.Default}
+		if o, ok := s.overrides[k]; ok {
+			v.Value = o
+			v.Overridden = true
+		}
+		out = append(out, v)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
+
+// lookup returns the override when present, else the registered default.
+// ok is false for unregistered keys.
+func (s *Store) lookup(key string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if v, ok := s.overrides[key]; ok {
+		return v, true
+	}
+	setting, ok := s.settings[key]
+	return setting.Default, ok
+}
+
+// Int returns the effective int value of key, or def when unregistered or
+// unparsable.
+func (s *Store) Int(key string, def int) int {
+	v, ok := s.lookup(key)
+	if !ok {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+// Bool returns the effective bool value of key, or def.
+func (s *Store) Bool(key string, def bool) bool {
+	v, ok := s.lookup(key)
+	if !ok {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return def
+	}
+	return b
+}
+
+// Duration returns the effective duration value of key, or def.
+func (s *Store) Duration(key string, def time.Duration) time.Duration {
+	v, ok := s.lookup(key)
+	if !ok {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return def
+	}
+	return d
+}
+
+func validate(kind Kind, value string) error {
+	switch kind {
+	case KindInt:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		if n <= 0 {
+			return errors.New("must be positive")
+		}
+	case KindBool:
+		_, err := strconv.ParseBool(value)
+		return err
+	case KindDuration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		if d <= 0 {
+			return errors.New("must be positive")
+		}
+	default:
+		return fmt.Errorf("unsupported kind %q", kind)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package runtimeconfig
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+func newTestStore(t *testing.T) (*Store, *redis.Client) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	s := New(client, zap.NewNop())
+	s.Register(Setting{Key: "ratelimit.search.max_requests", Kind: KindInt, Default: "60"})
+	s.Register(Setting{Key: "ratelimit.search.window", Kind: KindDuration, Default: "1m0s"})
+	s.Register(Setting{Key: "flag.posting_enabled", Kind: KindBool, Default: "true"})
+	return s, client
+}
+
+func TestStore_DefaultsWhenNoOverride(t *testing.T) {
+	s, _ := newTestStore(t)
+
+	if got := s.Int("ratelimit.search.max_requests", 1); got != 60 {
+		t.Fatalf("Int: want 60, got %d", got)
+	}
+	if got := s.Duration("ratelimit.search.window", time.Second); got != time.Minute {
+		t.Fatalf("Duration: want 1m, got %s", got)
+	}
+	if got := s.Int("unregistered", 7); got != 7 {
+		t.Fatalf("Int unregistered: want fallback 7, got %d", got)
+	}
+}
+
+func TestStore_SetAndReset(t *testing.T) {
+	s, _ := newTestStore(t)
+	ctx := context.Background()
+
+	if err := s.Set(ctx, "ratelimit.search.max_requests", "120"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if got := s.Int("ratelimit.search.max_requests", 1); got != 120 {
+		t.Fatalf("after Set: want 120, got %d", got)
+	}
+
+	if err := s.Reset(ctx, "ratelimit.search.max_requests"); err != nil {
+		t.Fatalf("Reset: %v", err)
+	}
+	if got := s.Int("ratelimit.search.max_requests", 1); got != 60 {
+		t.Fatalf("after Reset: want 60, got %d", got)
+	}
+}
+
+func TestStore_SetRejectsUnknownAndInvalid(t *testing.T) {
+	s, _ := newTestStore(t)
+	ctx := context.Background()
+
+	if err := s.Set(ctx, "nope", "1"); !errors.Is(err, ErrUnknownSetting) {
+		t.Fatalf("unknown key: want ErrUnknownSetting, got %v", err)
+	}
+	if err := s.Set(ctx, "ratelimit.search.max_requests", "0"); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("zero int: want ErrInvalidValue, got %v", err)
+	}
+	if err := s.Set(ctx, "ratelimit.search.window", "soon"); !errors.Is(err, ErrInvalidValue) {
+		t.Fatalf("bad duration: want ErrInvalidValue, got %v", err)
+	}
+}
+
+func TestStore_RefreshPicksUpOtherInstanceWrites(t *testing.T) {
+	s, client := newTestStore(t)
+	ctx := context.Background()
+
+	// Another instance writes directly to the shared hash.
+	client.HSet(ctx, DefaultKey, "flag.posting_enabled", "false")
+	client.HSet(ctx, DefaultKey, "ratelimit.search.window", "garbage")
+
+	if err := s.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if s.Bool("flag.posting_enabled", true) {
+		t.Fatal("flag override not picked up")
+	}
+	if got := s.Duration("ratelimit.search.window", 0); got != time.Minute {
+		t.Fatalf("invalid override should be ignored; got %s", got)
+	}
+}
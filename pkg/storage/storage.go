@@ -7,7 +7,7 @@ import (
 	"image"
 	"image/jpeg"
 	"image/png"
-	_ "image/gif"
+	"image/gif"
 	"io"
 	"path/filepath"
 	"strings"
@@ -384,6 +384,33 @@ func (c *Client) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+// ObjectInfo describes one stored object in a bucket listing.
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// ListObjects calls fn for every object under prefix, recursively. A non-nil
+// error from fn stops the listing and is returned.
+func (c *Client) ListObjects(ctx context.Context, prefix string, fn func(ObjectInfo) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for obj := range c.client.ListObjects(ctx, c.bucketName, minio.ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+	}) {
+		if obj.Err != nil {
+			return fmt.Errorf("failed to list objects: %w", obj.Err)
+		}
+		if err := fn(ObjectInfo{Key: obj.Key, Size: obj.Size, LastModified: obj.LastModified}); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
 // DeleteByURL extracts the key from URL and deletes the file
 func (c *Client) DeleteByURL(ctx context.Context, url string) error {
 	key := c.extractKeyFromURL(url)
@@ -393,6 +420,24 @@ func (c *Client) DeleteByURL(ctx context.Context, url string) error {
 	return c.Delete(ctx, key)
 }
 
+// ReadByURL reads the object behind a public URL. Objects larger than
+// maxSize are refused.
+func (c *Client) ReadByURL(ctx context.Context, url string, maxSize int64) ([]byte, error) {
+	key := c.extractKeyFromURL(url)
+	if key == "" {
+		return nil, fmt.Errorf("invalid URL: cannot extract key")
+	}
+	obj, info, err := c.StreamObject(ctx, key, "")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = obj.Close() }()
+	if info.Size > maxSize {
+		return nil, fmt.Errorf("object too large: %d bytes", info.Size)
+	}
+	return io.ReadAll(io.LimitReader(obj, maxSize))
+}
+
 // GetPresignedURL generates a presigned URL for secure uploads
 func (c *Client) GetPresignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
 	url, err := c.client.PresignedGetObject(ctx, c.bucketName, key, expiry, nil)
@@ -563,6 +608,10 @@ func EncodeImage(img image.Image, format string) (io.Reader, error) {
 		if err := png.Encode(&buf, img); err != nil {
 			return nil, fmt.Errorf("failed to encode PNG: %w", err)
 		}
+	case "gif":
+		if err := gif.Encode(&buf, img, nil); err != nil {
+			return nil, fmt.Errorf("failed to encode GIF: %w", err)
+		}
 	case "webp":
 		// Configure WebP encoder options for high quality
 		options, err := encoder.NewLossyEncoderOptions(encoder.PresetDefault, 90)
@@ -1,7 +1,10 @@
 package websocket
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"net"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -20,6 +23,15 @@ const (
 
 	// Maximum message size allowed from peer
 	maxMessageSize = 8192 // 8 KB
+
+	// How often the write pump checks the access token's expiry
+	authCheckPeriod = 15 * time.Second
+
+	// How long a connection may stay open after its token expired
+	authGracePeriod = 60 * time.Second
+
+	// Time allowed to validate a token from an auth frame
+	authTimeout = 5 * time.Second
 )
 
 var (
@@ -42,7 +54,15 @@ func (c *Client) ReadPump() {
 	for {
 		_, message, err := c.Conn.ReadMessage()
 		if err != nil {
-			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				// No frame or pong within pongWait: tell the client why
+				// before dropping it so it reconnects instead of retrying.
+				c.Hub.logger.Debug("WebSocket idle timeout", zap.String("user_id", c.ID))
+				_ = c.Conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(CloseIdleTimeout, ErrCodeIdleTimeout),
+					time.Now().Add(writeWait))
+			} else if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				c.Hub.logger.Error("WebSocket read error",
 					zap.Error(err),
 					zap.String("user_id", c.ID),
@@ -51,49 +71,170 @@ func (c *Client) ReadPump() {
 			break
 		}
 
-		// Reject non-JSON payloads before any further processing.
-		// This prevents malformed/binary blobs from reaching business logic.
-		if !json.Valid(message) {
-			c.Hub.logger.Warn("Received invalid JSON WebSocket message, ignoring",
-				zap.String("user_id", c.ID),
-			)
-			continue
-		}
+		// Any frame counts as activity, not just pongs.
+		_ = c.Conn.SetReadDeadline(time.Now().Add(pongWait))
+		c.handleFrame(message)
+	}
+}
+
+// inboundFrame is a client frame. ConversationID is the v1 top-level field
+// of presence frames; v2 clients put it in the payload.
+type inboundFrame struct {
+	Envelope
+	ConversationID string `json:"conversation_id"`
+}
+
+// handleFrame dispatches one client frame. See protocol.go for the frame
+// types.
+func (c *Client) handleFrame(message []byte) {
+	// Reject non-JSON payloads before any further processing.
+	// This prevents malformed/binary blobs from reaching business logic.
+	if !json.Valid(message) {
+		c.Hub.logger.Warn("Received invalid JSON WebSocket message, ignoring",
+			zap.String("user_id", c.ID),
+		)
+		c.sendError("", ErrCodeInvalidFrame, "Frame is not valid JSON")
+		return
+	}
 
-		// Handle incoming WS frames from the client. Currently handled:
-		//   * `presence` — `{type:"presence", conversation_id:"<id>"}` —
-		//     sets/clears the conversation the user is actively viewing so
-		//     the chat service can suppress redundant push notifications
-		//     while the recipient is on the screen. Empty conversation_id
-		//     clears the active marker.
-		var frame struct {
-			Type           string `json:"type"`
-			ConversationID string `json:"conversation_id"`
+	var frame inboundFrame
+	if err := json.Unmarshal(message, &frame); err != nil {
+		c.Hub.logger.Debug("Unparseable WS frame, ignoring",
+			zap.String("user_id", c.ID),
+			zap.Error(err),
+		)
+		c.sendError("", ErrCodeInvalidFrame, "Frame is not an envelope")
+		return
+	}
+
+	switch frame.Type {
+	case FrameHello:
+		var hello HelloPayload
+		if len(frame.Payload) > 0 {
+			if err := json.Unmarshal(frame.Payload, &hello); err != nil {
+				c.replyError(frame.RequestID, ErrCodeInvalidFrame, "Invalid hello payload")
+				return
+			}
 		}
-		if err := json.Unmarshal(message, &frame); err != nil {
-			c.Hub.logger.Debug("Unparseable WS frame, ignoring",
-				zap.String("user_id", c.ID),
-				zap.Error(err),
-			)
-			continue
+		if hello.Version == 0 {
+			hello.Version = frame.Version
 		}
-		switch frame.Type {
-		case "presence":
-			c.Hub.SetActiveConversation(c.ID, frame.ConversationID)
-		default:
-			c.Hub.logger.Debug("Received WebSocket message",
-				zap.String("user_id", c.ID),
-				zap.String("type", frame.Type),
-			)
+		if hello.Version < LegacyProtocolVersion {
+			// The client is speaking the handshake, so it understands
+			// error frames even though nothing is negotiated yet.
+			c.replyError(frame.RequestID, ErrCodeUnsupportedVersion, "Unsupported protocol version")
+			return
 		}
+		c.enqueue(encodeFrame(FrameWelcome, frame.RequestID, c.negotiate(hello)))
+	case FramePing:
+		c.enqueue(encodeFrame(FramePong, frame.RequestID, nil))
+	case FrameAuth:
+		c.reauthenticate(frame)
+	case FramePresence:
+		conversationID := frame.ConversationID
+		if len(frame.Payload) > 0 {
+			var p struct {
+				ConversationID string `json:"conversation_id"`
+			}
+			if err := json.Unmarshal(frame.Payload, &p); err != nil {
+				c.sendError(frame.RequestID, ErrCodeInvalidFrame, "Invalid presence payload")
+				return
+			}
+			conversationID = p.ConversationID
+		}
+		c.Hub.SetActiveConversation(c.ID, conversationID)
+	default:
+		c.Hub.logger.Debug("Received WebSocket message",
+			zap.String("user_id", c.ID),
+			zap.String("type", frame.Type),
+		)
+		c.sendError(frame.RequestID, ErrCodeUnknownType, "Unknown frame type: "+frame.Type)
 	}
 }
 
+// reauthenticate handles the auth frame: a fresh token for the same user
+// moves the connection's expiry forward.
+func (c *Client) reauthenticate(frame inboundFrame) {
+	var p AuthPayload
+	if len(frame.Payload) > 0 {
+		if err := json.Unmarshal(frame.Payload, &p); err != nil {
+			c.replyError(frame.RequestID, ErrCodeInvalidFrame, "Invalid auth payload")
+			return
+		}
+	}
+	if p.Token == "" {
+		c.replyError(frame.RequestID, ErrCodeInvalidFrame, "Token is required")
+		return
+	}
+	if c.Hub.authenticate == nil {
+		c.replyError(frame.RequestID, ErrCodeAuthFailed, "Re-authentication is not available")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), authTimeout)
+	defer cancel()
+	userID, expiresAt, err := c.Hub.authenticate(ctx, p.Token)
+	if err != nil || userID != c.ID {
+		c.Hub.logger.Info("WebSocket re-authentication failed",
+			zap.String("user_id", c.ID),
+			zap.Error(err),
+		)
+		c.replyError(frame.RequestID, ErrCodeAuthFailed, "Invalid token")
+		return
+	}
+
+	c.mu.Lock()
+	c.TokenExpiry = expiresAt
+	c.expiryWarned = false
+	c.mu.Unlock()
+	c.enqueue(encodeFrame(FrameAuthOK, frame.RequestID, AuthOKPayload{ExpiresAt: expiresAt}))
+}
+
+// checkToken enforces the token expiry. Once the token has expired v2
+// clients are told so once; after authGracePeriod it returns false and the
+// connection should be closed.
+func (c *Client) checkToken(now time.Time) bool {
+	c.mu.Lock()
+	expiry := c.TokenExpiry
+	warn := !expiry.IsZero() && now.After(expiry) && !c.expiryWarned
+	if warn {
+		c.expiryWarned = true
+	}
+	c.mu.Unlock()
+
+	if expiry.IsZero() || !now.After(expiry) {
+		return true
+	}
+	if now.After(expiry.Add(authGracePeriod)) {
+		return false
+	}
+	if warn {
+		c.sendError("", ErrCodeTokenExpired, "Access token expired; send an auth frame to stay connected")
+	}
+	return true
+}
+
+// sendError sends an error frame to clients that negotiated v2. Legacy
+// clients don't know the frame and never got feedback, so they still don't.
+func (c *Client) sendError(requestID, code, message string) {
+	if c.protocolVersion() < ProtocolVersion {
+		return
+	}
+	c.replyError(requestID, code, message)
+}
+
+// replyError sends an error frame regardless of the negotiated version.
+func (c *Client) replyError(requestID, code, message string) {
+	c.enqueue(encodeFrame(FrameError, requestID, ErrorPayload{Code: code, Message: message}))
+}
+
 // WritePump pumps messages from the hub to the WebSocket connection
 func (c *Client) WritePump() {
 	ticker := time.NewTicker(pingPeriod)
+	authTicker := time.NewTicker(authCheckPeriod)
 	defer func() {
 		ticker.Stop()
+		authTicker.Stop()
 		_ = c.Conn.Close()
 	}()
 
@@ -129,6 +270,15 @@ func (c *Client) WritePump() {
 			if err := c.Conn.WriteMessage(websocket.PingMessage, nil); err != nil {
 				return
 			}
+
+		case <-authTicker.C:
+			if !c.checkToken(time.Now()) {
+				c.Hub.logger.Info("Closing WebSocket with expired token", zap.String("user_id", c.ID))
+				_ = c.Conn.SetWriteDeadline(time.Now().Add(writeWait))
+				_ = c.Conn.WriteMessage(websocket.CloseMessage,
+					websocket.FormatCloseMessage(CloseTokenExpired, ErrCodeTokenExpired))
+				return
+			}
 		}
 	}
 }
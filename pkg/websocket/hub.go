@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"hash/fnv"
 	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/hamsaya/backend/pkg/observability"
@@ -30,6 +31,16 @@ type Client struct {
 	// mobile client. Used by the chat service to suppress push
 	// notifications for messages the user is already actively viewing.
 	activeConversationID string
+	// version and capabilities are negotiated by the hello frame; zero
+	// until then, which means the legacy protocol.
+	version      int
+	capabilities map[string]bool
+	// TokenExpiry is when the access token the connection authenticated
+	// with expires. Set by the handler before the pumps start; the auth
+	// frame moves it forward. Zero disables expiry enforcement.
+	TokenExpiry time.Time
+	// expiryWarned is set once the token_expired error frame was sent.
+	expiryWarned bool
 }
 
 // hubShard is one slice of the connection map. Each shard runs an
@@ -55,18 +66,32 @@ type Hub struct {
 
 	// Optional cross-instance fanout. nil = single-pod mode.
 	fanout *Fanout
+	// Optional token check for the auth frame. nil = re-authentication
+	// is refused and connections close once their token expires.
+	authenticate Authenticator
 }
 
+// Authenticator validates an access token presented on an open connection
+// and returns the user it belongs to and when it expires.
+type Authenticator func(ctx context.Context, token string) (userID string, expiresAt time.Time, err error)
+
 // AttachFanout wires a Redis pub/sub fanout to this hub. Called once at
 // boot when DB_REDIS multi-instance mode is enabled. Safe to leave nil.
 func (h *Hub) AttachFanout(f *Fanout) {
 	h.fanout = f
 }
 
-// BroadcastMessage represents a message to be sent to a specific user
+// AttachAuthenticator enables the auth frame. Called once at boot.
+func (h *Hub) AttachAuthenticator(a Authenticator) {
+	h.authenticate = a
+}
+
+// BroadcastMessage represents a message to be sent to a specific user.
+// Type is the frame type, used to skip clients that didn't opt in to it.
 type BroadcastMessage struct {
 	UserID  string
 	Message []byte
+	Type    string
 }
 
 // NewHub creates a new WebSocket hub
@@ -153,7 +178,12 @@ func (s *hubShard) run() {
 			client, exists := s.clients[broadcast.UserID]
 			s.mu.RUnlock()
 
-			if exists {
+			if exists && !client.accepts(broadcast.Type) {
+				s.logger.Debug("Client did not opt in to frame type, not sent",
+					zap.String("user_id", broadcast.UserID),
+					zap.String("type", broadcast.Type),
+				)
+			} else if exists {
 				select {
 				case client.Send <- broadcast.Message:
 					s.logger.Debug("Message sent to client",
@@ -213,7 +243,7 @@ func (h *Hub) Unregister(client *Client) {
 }
 
 // SendToUser sends a message to a specific user via the user's shard.
-// When a Fanout is attached and the user isn't connected to *this* pod,
+// The frame is stamped with the protocol version. When a Fanout is attached and the user isn't connected to *this* pod,
 // the message is also published on Redis pub/sub so a peer pod with the
 // connection can deliver it.
 func (h *Hub) SendToUser(userID string, message interface{}) error {
@@ -225,13 +255,14 @@ func (h *Hub) SendToUser(userID string, message interface{}) error {
 		)
 		return err
 	}
+	messageBytes, frameType := stampFrame(messageBytes)
 
 	// Shutdown-safe: if this shard's run() has already returned, there is no
 	// reader on broadcast — selecting on done prevents the caller goroutine
 	// (notification/chat fanout) from blocking forever during drain.
 	s := h.shardFor(userID)
 	select {
-	case s.broadcast <- &BroadcastMessage{UserID: userID, Message: messageBytes}:
+	case s.broadcast <- &BroadcastMessage{UserID: userID, Message: messageBytes, Type: frameType}:
 	case <-s.done:
 	}
 
@@ -338,6 +369,75 @@ func (c *Client) close() {
 	}
 }
 
+// enqueue queues a frame for the write pump without blocking. Returns false
+// when the client is closed or its buffer is full. Safe to call from the
+// read pump: close holds the same lock.
+func (c *Client) enqueue(frame []byte) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return false
+	}
+	select {
+	case c.Send <- frame:
+		return true
+	default:
+		return false
+	}
+}
+
+// accepts reports whether the client should receive frames of frameType.
+func (c *Client) accepts(frameType string) bool {
+	if !isGated(frameType) {
+		return true
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.capabilities[frameType]
+}
+
+// protocolVersion is the negotiated version, LegacyProtocolVersion before
+// hello.
+func (c *Client) protocolVersion() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.version == 0 {
+		return LegacyProtocolVersion
+	}
+	return c.version
+}
+
+// negotiate records the client's hello and returns the welcome payload:
+// the lower of the two versions and the capabilities both sides share.
+func (c *Client) negotiate(hello HelloPayload) WelcomePayload {
+	version := hello.Version
+	if version > ProtocolVersion {
+		version = ProtocolVersion
+	}
+	caps := make(map[string]bool, len(hello.Capabilities))
+	for _, capability := range hello.Capabilities {
+		caps[capability] = true
+	}
+	shared := []string{}
+	for _, capability := range serverCapabilities() {
+		if caps[capability] {
+			shared = append(shared, capability)
+		}
+	}
+
+	c.mu.Lock()
+	c.version = version
+	c.capabilities = caps
+	c.mu.Unlock()
+
+	return WelcomePayload{
+		Version:                  version,
+		Capabilities:             shared,
+		HeartbeatIntervalSeconds: int(pingPeriod / time.Second),
+		IdleTimeoutSeconds:       int(pongWait / time.Second),
+	}
+}
+
 // IsClosed returns whether the client connection is closed
 func (c *Client) IsClosed() bool {
 	c.mu.Lock()
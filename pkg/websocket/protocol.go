@@ -0,0 +1,181 @@
+// Wire protocol.
+//
+// Every frame is a JSON envelope:
+//
+//	{"type": "...", "version": 2, "request_id": "...", "payload": {...}}
+//
+// Version 1 is the original ad-hoc protocol: bare {type, payload} frames out
+// and {type, conversation_id} frames in. Clients that never send `hello`
+// stay on it and keep receiving exactly the frame types they always did.
+//
+// A version 2 client opens with
+//
+//	{"type": "hello", "version": 2, "request_id": "r1",
+//	 "payload": {"version": 2, "capabilities": ["message", "typing"]}}
+//
+// and the server answers with `welcome` carrying the negotiated version, the
+// capabilities both sides support and the heartbeat timings. Frame types
+// registered with [RequireCapability] are only delivered to clients that
+// listed them, so new types can ship without confusing old builds.
+//
+// Access tokens expire while the socket stays open. Clients send a fresh
+// token in an `auth` frame ({"payload": {"token": "..."}}) and get `auth_ok`
+// with the new expiry. Once the token expires v2 clients get a
+// `token_expired` error frame; connections still unrefreshed after
+// authGracePeriod are closed with CloseTokenExpired.
+//
+// Problems with a client frame are answered with an `error` frame carrying a
+// stable code and the offending request_id. Legacy clients never get error
+// frames; they were silently ignored before and still are.
+
+package websocket
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// ProtocolVersion is the newest protocol version this server speaks.
+	ProtocolVersion = 2
+	// LegacyProtocolVersion is assumed until the client says hello.
+	LegacyProtocolVersion = 1
+)
+
+// Control frame types.
+const (
+	FrameHello   = "hello"
+	FrameWelcome = "welcome"
+	FrameError   = "error"
+	FramePing    = "ping"
+	FramePong    = "pong"
+	FrameAuth    = "auth"
+	FrameAuthOK  = "auth_ok"
+	// FramePresence sets the conversation the user has open; an empty
+	// conversation_id clears it.
+	FramePresence = "presence"
+)
+
+// Error codes carried by error frames.
+const (
+	ErrCodeInvalidFrame       = "invalid_frame"
+	ErrCodeUnsupportedVersion = "unsupported_version"
+	ErrCodeUnknownType        = "unknown_type"
+	ErrCodeIdleTimeout        = "idle_timeout"
+	ErrCodeAuthFailed         = "auth_failed"
+	ErrCodeTokenExpired       = "token_expired"
+)
+
+// Application close codes (RFC 6455 reserves 4000-4999 for applications).
+const (
+	CloseIdleTimeout  = 4000
+	CloseTokenExpired = 4001
+)
+
+// Envelope is the v2 frame shape. Outbound frames built elsewhere as
+// {type, payload} are stamped with the version by the hub.
+type Envelope struct {
+	Type      string          `json:"type"`
+	Version   int             `json:"version,omitempty"`
+	RequestID string          `json:"request_id,omitempty"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+}
+
+// ErrorPayload is the payload of an error frame.
+type ErrorPayload struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// HelloPayload is what the client sends to negotiate the protocol.
+type HelloPayload struct {
+	Version      int      `json:"version"`
+	Capabilities []string `json:"capabilities"`
+}
+
+// WelcomePayload answers hello.
+type WelcomePayload struct {
+	Version      int      `json:"version"`
+	Capabilities []string `json:"capabilities"`
+	// HeartbeatIntervalSeconds is how often the server pings.
+	HeartbeatIntervalSeconds int `json:"heartbeat_interval_s"`
+	// IdleTimeoutSeconds is how long the server waits for any frame or pong
+	// before closing the connection.
+	IdleTimeoutSeconds int `json:"idle_timeout_s"`
+}
+
+// AuthPayload is the payload of the auth frame.
+type AuthPayload struct {
+	Token string `json:"token"`
+}
+
+// AuthOKPayload answers a successful auth frame.
+type AuthOKPayload struct {
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+var (
+	gatedMu    sync.RWMutex
+	gatedTypes = map[string]bool{}
+)
+
+// RequireCapability marks an outbound frame type as opt-in: only clients
+// that list it in their hello capabilities receive it. Call from init or
+// at startup when introducing a new frame type.
+func RequireCapability(frameType string) {
+	gatedMu.Lock()
+	gatedTypes[frameType] = true
+	gatedMu.Unlock()
+}
+
+func isGated(frameType string) bool {
+	gatedMu.RLock()
+	defer gatedMu.RUnlock()
+	return gatedTypes[frameType]
+}
+
+// serverCapabilities lists the opt-in frame types plus the client frames
+// the server understands.
+func serverCapabilities() []string {
+	gatedMu.RLock()
+	defer gatedMu.RUnlock()
+	caps := []string{FramePresence, FramePing, FrameAuth}
+	for t := range gatedTypes {
+		caps = append(caps, t)
+	}
+	return caps
+}
+
+// stampFrame adds the protocol version to an outbound frame that lacks one
+// and returns its type. Frames that aren't JSON objects pass through.
+func stampFrame(b []byte) ([]byte, string) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return b, ""
+	}
+	var frameType string
+	_ = json.Unmarshal(fields["type"], &frameType)
+	if _, ok := fields["version"]; ok {
+		return b, frameType
+	}
+	fields["version"] = json.RawMessage(strconv.Itoa(ProtocolVersion))
+	out, err := json.Marshal(fields)
+	if err != nil {
+		return b, frameType
+	}
+	return out, frameType
+}
+
+// encodeFrame builds a v2 envelope.
+func encodeFrame(frameType, requestID string, payload interface{}) []byte {
+	raw, _ := json.Marshal(payload)
+	b, _ := json.Marshal(Envelope{
+		Type:      frameType,
+		Version:   ProtocolVersion,
+		RequestID: requestID,
+		Payload:   raw,
+	})
+	return b
+}
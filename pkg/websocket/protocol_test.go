@@ -0,0 +1,172 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receiveFrame(t *testing.T, c *Client) Envelope {
+	t.Helper()
+	select {
+	case data := <-c.Send:
+		var env Envelope
+		require.NoError(t, json.Unmarshal(data, &env))
+		return env
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("frame not received")
+	}
+	return Envelope{}
+}
+
+func TestStampFrame(t *testing.T) {
+	out, frameType := stampFrame([]byte(`{"type":"message","payload":{"id":"m-1"}}`))
+	assert.Equal(t, "message", frameType)
+	assert.JSONEq(t, `{"type":"message","version":2,"payload":{"id":"m-1"}}`, string(out))
+
+	out, _ = stampFrame([]byte(`{"type":"message","version":1}`))
+	assert.JSONEq(t, `{"type":"message","version":1}`, string(out))
+
+	out, frameType = stampFrame([]byte(`"plain"`))
+	assert.Equal(t, "", frameType)
+	assert.Equal(t, `"plain"`, string(out))
+}
+
+func TestClient_Hello(t *testing.T) {
+	RequireCapability("test.hello_gated")
+	c := newTestClient(newTestHub(t), "user-1")
+
+	c.handleFrame([]byte(`{"type":"hello","request_id":"r1","payload":{"version":5,"capabilities":["test.hello_gated","bogus"]}}`))
+
+	env := receiveFrame(t, c)
+	assert.Equal(t, FrameWelcome, env.Type)
+	assert.Equal(t, "r1", env.RequestID)
+	var welcome WelcomePayload
+	require.NoError(t, json.Unmarshal(env.Payload, &welcome))
+	assert.Equal(t, ProtocolVersion, welcome.Version)
+	assert.Equal(t, []string{"test.hello_gated"}, welcome.Capabilities)
+	assert.Equal(t, 60, welcome.IdleTimeoutSeconds)
+	assert.Equal(t, ProtocolVersion, c.protocolVersion())
+	assert.True(t, c.accepts("test.hello_gated"))
+}
+
+func TestClient_ErrorFrames(t *testing.T) {
+	t.Run("legacy clients get none", func(t *testing.T) {
+		c := newTestClient(newTestHub(t), "user-1")
+		c.handleFrame([]byte(`{"type":"dance"}`))
+		c.handleFrame([]byte(`not json`))
+		assert.Empty(t, c.Send)
+	})
+
+	t.Run("v2 clients get a coded error", func(t *testing.T) {
+		c := newTestClient(newTestHub(t), "user-1")
+		c.negotiate(HelloPayload{Version: ProtocolVersion})
+
+		c.handleFrame([]byte(`{"type":"dance","request_id":"r2"}`))
+		env := receiveFrame(t, c)
+		assert.Equal(t, FrameError, env.Type)
+		assert.Equal(t, "r2", env.RequestID)
+		var payload ErrorPayload
+		require.NoError(t, json.Unmarshal(env.Payload, &payload))
+		assert.Equal(t, ErrCodeUnknownType, payload.Code)
+	})
+
+	t.Run("hello with a bad version", func(t *testing.T) {
+		c := newTestClient(newTestHub(t), "user-1")
+		c.handleFrame([]byte(`{"type":"hello","request_id":"r3","payload":{"version":0}}`))
+		env := receiveFrame(t, c)
+		assert.Equal(t, FrameError, env.Type)
+		assert.Equal(t, LegacyProtocolVersion, c.protocolVersion())
+	})
+}
+
+func TestClient_PingPong(t *testing.T) {
+	c := newTestClient(newTestHub(t), "user-1")
+	c.handleFrame([]byte(`{"type":"ping","request_id":"r4"}`))
+	env := receiveFrame(t, c)
+	assert.Equal(t, FramePong, env.Type)
+	assert.Equal(t, "r4", env.RequestID)
+}
+
+func TestHub_SendToUser_CapabilityGating(t *testing.T) {
+	RequireCapability("test.gated")
+	hub := newTestHub(t)
+	legacy := newTestClient(hub, "user-legacy")
+	modern := newTestClient(hub, "user-modern")
+	modern.negotiate(HelloPayload{Version: ProtocolVersion, Capabilities: []string{"test.gated"}})
+	hub.Register(legacy)
+	hub.Register(modern)
+	time.Sleep(20 * time.Millisecond)
+
+	require.NoError(t, hub.SendToUser("user-legacy", map[string]string{"type": "test.gated"}))
+	require.NoError(t, hub.SendToUser("user-modern", map[string]string{"type": "test.gated"}))
+
+	env := receiveFrame(t, modern)
+	assert.Equal(t, "test.gated", env.Type)
+	assert.Equal(t, ProtocolVersion, env.Version)
+	time.Sleep(20 * time.Millisecond)
+	assert.Empty(t, legacy.Send)
+}
+
+func TestClient_Reauthenticate(t *testing.T) {
+	expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+	hub := newTestHub(t)
+	hub.AttachAuthenticator(func(_ context.Context, token string) (string, time.Time, error) {
+		switch token {
+		case "good":
+			return "user-1", expiry, nil
+		case "other-user":
+			return "user-2", expiry, nil
+		}
+		return "", time.Time{}, errors.New("invalid token")
+	})
+
+	t.Run("fresh token moves the expiry", func(t *testing.T) {
+		c := newTestClient(hub, "user-1")
+		c.TokenExpiry = time.Now().Add(-time.Minute)
+		c.handleFrame([]byte(`{"type":"auth","request_id":"r1","payload":{"token":"good"}}`))
+
+		env := receiveFrame(t, c)
+		assert.Equal(t, FrameAuthOK, env.Type)
+		assert.Equal(t, "r1", env.RequestID)
+		assert.True(t, expiry.Equal(c.TokenExpiry))
+	})
+
+	for _, token := range []string{"bad", "other-user"} {
+		t.Run("rejects "+token, func(t *testing.T) {
+			c := newTestClient(hub, "user-1")
+			old := c.TokenExpiry
+			c.handleFrame([]byte(`{"type":"auth","payload":{"token":"` + token + `"}}`))
+
+			env := receiveFrame(t, c)
+			assert.Equal(t, FrameError, env.Type)
+			var payload ErrorPayload
+			require.NoError(t, json.Unmarshal(env.Payload, &payload))
+			assert.Equal(t, ErrCodeAuthFailed, payload.Code)
+			assert.Equal(t, old, c.TokenExpiry)
+		})
+	}
+}
+
+func TestClient_CheckToken(t *testing.T) {
+	now := time.Now()
+
+	c := newTestClient(newTestHub(t), "user-1")
+	assert.True(t, c.checkToken(now), "no expiry is never enforced")
+
+	c.negotiate(HelloPayload{Version: ProtocolVersion})
+	c.TokenExpiry = now.Add(-time.Second)
+	assert.True(t, c.checkToken(now), "inside the grace period")
+	env := receiveFrame(t, c)
+	assert.Equal(t, FrameError, env.Type)
+
+	assert.True(t, c.checkToken(now))
+	assert.Empty(t, c.Send, "warned only once")
+
+	assert.False(t, c.checkToken(now.Add(authGracePeriod)))
+}
@@ -113,8 +113,9 @@ func (f *Fanout) handle(msg *redis.Message) {
 	// is non-blocking against the client.Send buffer; if the user isn't
 	// here, the shard's "exists" check returns false and the message
 	// is dropped (expected behavior for fanout).
+	payload, frameType := stampFrame(m.Payload)
 	s := f.hub.shardFor(m.UserID)
-	s.broadcast <- &BroadcastMessage{UserID: m.UserID, Message: m.Payload}
+	s.broadcast <- &BroadcastMessage{UserID: m.UserID, Message: payload, Type: frameType}
 }
 
 // Publish writes a message to the cross-instance fanout channel.
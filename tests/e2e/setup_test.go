@@ -170,6 +170,12 @@ func testConfig() *config.Config {
 			AccessTokenDuration:  15 * time.Minute,
 			RefreshTokenDuration: 7 * 24 * time.Hour,
 		},
+		// The e2e admin flows sign in with password only.
+		Admin: config.AdminConfig{
+			RequireMFA:          false,
+			AccessTokenDuration: 10 * time.Minute,
+			SessionDuration:     12 * time.Hour,
+		},
 		Email: config.EmailConfig{},
 	}
 }
@@ -279,7 +285,7 @@ func buildRouter(
 	authHandler := handlers.NewAuthHandler(authSvc, validator, logger)
 	adminCookieCfg := utils.NewCookieConfig(cfg.Server.Env, cfg.Server.AdminCookieDomain)
 	customRoleRepo := repositories.NewCustomRoleRepository(db)
-	adminAuthHandler := handlers.NewAdminAuthHandler(authSvc, customRoleRepo, validator, logger, adminCookieCfg, cfg.JWT)
+	adminAuthHandler := handlers.NewAdminAuthHandler(authSvc, customRoleRepo, validator, logger, adminCookieCfg, cfg.Admin)
 	featureFlagRepo := repositories.NewFeatureFlagRepository(db)
 	systemHandler := handlers.NewSystemHandler(db, redisClient, featureFlagRepo, wsHub, nil, logger)
 	postHandler := handlers.NewPostHandler(postSvc, nil, validator, logger)
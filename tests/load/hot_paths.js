@@ -0,0 +1,90 @@
+// k6 load profile mixing the three hot paths — feed, search and chat send —
+// across many seeded users instead of one token.
+//
+// Prepare fixtures against a seeded environment (see README):
+//   go run ./cmd/loadgen seed
+//   go run ./cmd/loadgen targets -format k6 -out tests/load/fixtures.json
+//
+// Run:
+//   API_URL=http://localhost:8080 k6 run tests/load/hot_paths.js
+//
+// Each iteration picks a random fixture user and one request: 60% feed,
+// 30% search, 10% chat send. The thresholds are the release gate; a run that
+// breaks one exits non-zero.
+import http from 'k6/http';
+import { check, sleep } from 'k6';
+import { SharedArray } from 'k6/data';
+import { Trend, Rate } from 'k6/metrics';
+
+const apiUrl = __ENV.API_URL || 'http://localhost:8080';
+const fixturesPath = __ENV.FIXTURES || './fixtures.json';
+
+const fixtures = new SharedArray('fixtures', () => [JSON.parse(open(fixturesPath))]);
+
+const latency = {
+  feed: new Trend('feed_latency_ms', true),
+  search: new Trend('search_latency_ms', true),
+  chat: new Trend('chat_send_latency_ms', true),
+};
+const errorRate = new Rate('hot_path_error_rate');
+
+export const options = {
+  stages: [
+    { duration: '1m', target: 100 },  // ramp up
+    { duration: '5m', target: 100 },  // sustained load
+    { duration: '30s', target: 0 },   // ramp down
+  ],
+  thresholds: {
+    'feed_latency_ms': ['p(95)<400'],
+    'search_latency_ms': ['p(95)<600'],
+    'chat_send_latency_ms': ['p(95)<300'],
+    // 429s from the chat send limiter count as errors; keep the chat share
+    // low or raise the limit in the load environment.
+    'hot_path_error_rate': ['rate<0.01'],
+  },
+};
+
+function pick(list) {
+  return list[Math.floor(Math.random() * list.length)];
+}
+
+export default function () {
+  const { users, search_terms: terms } = fixtures[0];
+  const user = pick(users);
+  const headers = { Authorization: `Bearer ${user.token}` };
+
+  const r = Math.random();
+  let endpoint;
+  let res;
+  if (r < 0.6) {
+    endpoint = 'feed';
+    res = http.get(`${apiUrl}/api/v1/posts/feed?limit=20`, { headers, tags: { endpoint } });
+  } else if (r < 0.9) {
+    endpoint = 'search';
+    res = http.get(`${apiUrl}/api/v1/search?type=posts&limit=20&query=${encodeURIComponent(pick(terms))}`,
+      { headers, tags: { endpoint } });
+  } else {
+    endpoint = 'chat';
+    let recipient = pick(users);
+    while (recipient.id === user.id) {
+      recipient = pick(users);
+    }
+    const body = JSON.stringify({
+      recipient_id: recipient.id,
+      message_type: 'TEXT',
+      content: 'Salaam, is this still available?',
+    });
+    res = http.post(`${apiUrl}/api/v1/chat/messages`, body, {
+      headers: { ...headers, 'Content-Type': 'application/json' },
+      tags: { endpoint },
+    });
+  }
+
+  latency[endpoint].add(res.timings.duration);
+  const ok = check(res, {
+    'status 2xx': (r) => r.status >= 200 && r.status < 300,
+  });
+  errorRate.add(!ok);
+
+  sleep(1);
+}